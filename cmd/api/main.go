@@ -22,25 +22,51 @@ package main
 import (
 	_ "github.com/mcicare/itsm-backend/docs" // Import pour Swagger docs
 
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/config"
 	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/antivirus"
+	"github.com/mcicare/itsm-backend/internal/cache"
+	"github.com/mcicare/itsm-backend/internal/events"
 	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/kbsearch"
+	"github.com/mcicare/itsm-backend/internal/logger"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
 	"github.com/mcicare/itsm-backend/internal/routes"
+	"github.com/mcicare/itsm-backend/internal/scheduler"
 	"github.com/mcicare/itsm-backend/internal/scope"
 	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/storage"
 	"github.com/mcicare/itsm-backend/internal/websocket"
+	"gorm.io/gorm"
 )
 
 func main() {
 	// Charger la configuration
 	config.LoadConfig()
 
+	// Initialiser le logger structuré (les appels log.Printf existants y sont redirigés)
+	logger.Init(config.AppConfig.App.LogLevel, config.AppConfig.App.LogFormat)
+
+	// Rechargement à chaud des paramètres non critiques (SIGHUP ou POST /admin/config/reload)
+	config.OnReload(func(cfg *config.Config) {
+		if err := logger.SetLevel(cfg.App.LogLevel); err != nil {
+			log.Printf("⚠️  Niveau de log rechargé invalide: %v", err)
+		}
+	})
+	config.WatchSIGHUP()
+
+	// Configurer le cache des données de référence (permissions, utilisateurs, catégories, paramètres)
+	cache.Init(config.AppConfig.Cache.Enabled, config.AppConfig.Cache.TTL)
+
 	// Se connecter à la base de données
 	if err := database.Connect(); err != nil {
 		log.Fatalf("❌ Erreur de connexion à la base de données: %v", err)
@@ -62,23 +88,19 @@ func main() {
 	roleRepo := repositories.NewRoleRepository()
 	permissionRepo := repositories.NewPermissionRepository()
 
-	// Initialiser le getter de permissions pour le package scope
-	// Cela évite les cycles d'importation
+	// Initialiser le chargeur de permissions du package scope (évite les cycles d'importation)
 	// IMPORTANT: Doit être fait après la création de roleRepo
-	scope.SetPermissionsGetter(func(roleName string) []string {
+	// Un rôle introuvable n'a aucune permission ; une erreur de base est remontée (réponse 503)
+	scope.SetPermissionsLoader(func(roleName string) ([]string, error) {
 		role, err := roleRepo.FindByName(roleName)
-		if err != nil {
-			return []string{"tickets.view_own"}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
-		permissions, err := roleRepo.GetPermissionsByRoleID(role.ID)
 		if err != nil {
-			return []string{"tickets.view_own"}
-		}
-		if len(permissions) == 0 {
-			return []string{"tickets.view_own"}
+			return nil, err
 		}
-		return permissions
-	})
+		return roleRepo.GetPermissionsByRoleID(role.ID)
+	}, config.AppConfig.Cache.PermissionsTTL)
 
 	userRepo := repositories.NewUserRepository()
 	userSessionRepo := repositories.NewUserSessionRepository()
@@ -111,6 +133,15 @@ func main() {
 	projectMemberRepo := repositories.NewProjectMemberRepository()
 	projectPhaseMemberRepo := repositories.NewProjectPhaseMemberRepository()
 	projectTaskRepo := repositories.NewProjectTaskRepository()
+	projectMilestoneRepo := repositories.NewProjectMilestoneRepository()
+	capacityRepo := repositories.NewCapacityRepository()
+	portfolioRepo := repositories.NewPortfolioRepository()
+	projectTemplateRepo := repositories.NewProjectTemplateRepository()
+	projectTaskRecurrenceRepo := repositories.NewProjectTaskRecurrenceRepository()
+	projectTaskTimerRepo := repositories.NewProjectTaskTimerRepository()
+	ticketProjectTaskRepo := repositories.NewTicketProjectTaskRepository()
+	payrollExportRepo := repositories.NewPayrollExportRepository()
+	absenceRepo := repositories.NewAbsenceRepository()
 	dailyDeclarationRepo := repositories.NewDailyDeclarationRepository()
 	weeklyDeclarationRepo := repositories.NewWeeklyDeclarationRepository()
 	auditLogRepo := repositories.NewAuditLogRepository()
@@ -120,10 +151,104 @@ func main() {
 	departmentRepo := repositories.NewDepartmentRepository()
 	filialeRepo := repositories.NewFilialeRepository()
 	ticketInternalRepo := repositories.NewTicketInternalRepository()
+	webhookRepo := repositories.NewWebhookRepository()
+	recordShareRepo := repositories.NewRecordShareRepository()
+	accessDelegationRepo := repositories.NewAccessDelegationRepository()
+	userInvitationRepo := repositories.NewUserInvitationRepository()
+	userPreferenceRepo := repositories.NewUserPreferenceRepository()
+	skillRepo := repositories.NewSkillRepository()
+	userSkillRepo := repositories.NewUserSkillRepository()
+	userActivityRepo := repositories.NewUserActivityRepository()
+	softwareReleaseRepo := repositories.NewSoftwareReleaseRepository()
+	softwareEnvironmentRepo := repositories.NewSoftwareEnvironmentRepository()
+	supportContractRepo := repositories.NewSupportContractRepository()
+	jiraLinkRepo := repositories.NewJiraLinkRepository()
+	importRepo := repositories.NewImportRepository()
+	calendarFeedRepo := repositories.NewCalendarFeedRepository()
+	calendarConnectionRepo := repositories.NewCalendarConnectionRepository()
+	telegramLinkRepo := repositories.NewTelegramLinkRepository()
+	whatsAppRepo := repositories.NewWhatsAppRepository()
+	monitoringAlertRepo := repositories.NewMonitoringAlertRepository()
+	gitLinkRepo := repositories.NewGitLinkRepository()
+	reportingRepo := repositories.NewReportingRepository()
+	syncRepo := repositories.NewSyncRepository()
+	pushRepo := repositories.NewPushRepository()
+	notificationPreferenceRepo := repositories.NewNotificationPreferenceRepository()
+	businessCalendarRepo := repositories.NewBusinessCalendarRepository()
+	escalationRuleRepo := repositories.NewEscalationRuleRepository()
+	ticketViewRepo := repositories.NewTicketViewRepository()
+	ticketWatcherRepo := repositories.NewTicketWatcherRepository()
+	ticketChecklistRepo := repositories.NewTicketChecklistRepository()
+	ticketSatisfactionRepo := repositories.NewTicketSatisfactionRepository()
+	ticketRelationRepo := repositories.NewTicketRelationRepository()
+	ticketTimelineRepo := repositories.NewTicketTimelineRepository()
+	recurringTicketRepo := repositories.NewRecurringTicketRepository()
+	problemRepo := repositories.NewProblemRepository()
+	approvalRepo := repositories.NewApprovalRepository()
+	majorIncidentRepo := repositories.NewMajorIncidentRepository()
+	assetLifecycleRepo := repositories.NewAssetLifecycleRepository()
+	softwareRepo := repositories.NewSoftwareRepository()
+	softwareLicenseRepo := repositories.NewSoftwareLicenseRepository()
+	assetDiscoveryRepo := repositories.NewAssetDiscoveryRepository()
+	assetRelationRepo := repositories.NewAssetRelationRepository()
+	assetReservationRepo := repositories.NewAssetReservationRepository()
+	maintenancePlanRepo := repositories.NewMaintenancePlanRepository()
+	knowledgeDeflectionRepo := repositories.NewKnowledgeDeflectionRepository()
+	knowledgeFeedbackRepo := repositories.NewKnowledgeFeedbackRepository()
+	knowledgeAttachmentRepo := repositories.NewKnowledgeAttachmentRepository()
+	knowledgePortalRepo := repositories.NewKnowledgePortalRepository()
+
+	// Partages de tickets et de projets ajoutés au périmètre du scope (cache d'une minute, invalidé à chaque modification)
+	scope.SetSharesLoader(func(userID uint, departmentID *uint) ([]scope.SharedRecord, error) {
+		shares, err := recordShareRepo.FindActiveForUserCached(userID, departmentID)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]scope.SharedRecord, len(shares))
+		for i, share := range shares {
+			records[i] = scope.SharedRecord{
+				ResourceType: share.ResourceType,
+				ResourceID:   share.ResourceID,
+				AccessLevel:  share.AccessLevel,
+				ExpiresAt:    share.ExpiresAt,
+			}
+		}
+		return records, nil
+	})
+	// Équipes hiérarchiques (manager_id) ajoutées au scope des responsables et de leurs suppléants pendant une absence
+	scope.SetTeamLoader(userRepo.FindApprovalTeamIDsCached)
+	scheduledJobRunRepo := repositories.NewScheduledJobRunRepository()
+
+	// Initialiser le stockage des fichiers (disque local ou S3 selon STORAGE_DRIVER)
+	attachmentStorage, err := storage.New(config.AppConfig, storage.NamespaceTickets, config.AppConfig.App.TicketAttachmentsDir)
+	if err != nil {
+		log.Fatalf("Erreur lors de l'initialisation du stockage des pièces jointes: %v", err)
+	}
+	avatarStorage, err := storage.New(config.AppConfig, storage.NamespaceUsers, config.AppConfig.App.AvatarDir)
+	if err != nil {
+		log.Fatalf("Erreur lors de l'initialisation du stockage des avatars: %v", err)
+	}
+	auditArchiveStorage, err := storage.New(config.AppConfig, storage.NamespaceAudit, config.AppConfig.App.AuditArchiveDir)
+	if err != nil {
+		log.Fatalf("Erreur lors de l'initialisation du stockage des archives d'audit: %v", err)
+	}
+	knowledgeStorage, err := storage.New(config.AppConfig, storage.NamespaceKnowledge, config.AppConfig.App.KnowledgeAttachmentsDir)
+	if err != nil {
+		log.Fatalf("Erreur lors de l'initialisation du stockage de la base de connaissances: %v", err)
+	}
+	// Analyse antivirus des pièces jointes avant stockage (ANTIVIRUS_DRIVER)
+	attachmentScanner, err := antivirus.New(config.AppConfig.Antivirus)
+	if err != nil {
+		log.Fatalf("Erreur lors de l'initialisation de l'antivirus: %v", err)
+	}
+	// Recherche plein texte des articles de la base de connaissances (KB_SEARCH_DRIVER)
+	knowledgeSearchEngine, err := kbsearch.New(config.AppConfig.KBSearch)
+	if err != nil {
+		log.Fatalf("Erreur lors de l'initialisation de la recherche de la base de connaissances: %v", err)
+	}
 
 	// Initialiser tous les services
-	authService := services.NewAuthService(userRepo, userSessionRepo, roleRepo)
-	userService := services.NewUserService(userRepo, roleRepo, departmentRepo, ticketRepo)
+	userService := services.NewUserService(userRepo, roleRepo, departmentRepo, ticketRepo, avatarStorage)
 	roleService := services.NewRoleService(roleRepo, userRepo, permissionRepo, filialeRepo)
 	permissionService := services.NewPermissionService(permissionRepo)
 
@@ -133,33 +258,69 @@ func main() {
 	log.Println("✅ Hub WebSocket démarré pour les notifications en temps réel")
 
 	// Créer le service de notifications AVANT le ticketService (car ticketService en a besoin)
-	notificationService := services.NewNotificationService(notificationRepo, userRepo, wsHub)
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, userPreferenceRepo, wsHub)
+
+	// File de tâches en arrière-plan (Redis via asynq si REDIS_ADDR est défini, sinon exécution en mémoire)
+	jobQueue := jobs.New(config.AppConfig.Redis, config.AppConfig.Jobs)
+
+	// Bus d'événements métier : les services publient, les consommateurs s'abonnent (voir services.RegisterEventSubscribers)
+	eventBus := events.NewBus()
+	authService := services.NewAuthService(userRepo, userSessionRepo, roleRepo, eventBus)
 
-	ticketService := services.NewTicketService(ticketRepo, userRepo, ticketCommentRepo, ticketHistoryRepo, slaRepo, ticketSLARepo, ticketCategoryRepo, notificationRepo, notificationService, departmentRepo, filialeRepo, timeEntryRepo)
-	ticketAttachmentService := services.NewTicketAttachmentService(ticketAttachmentRepo, ticketRepo, userRepo)
+	// Webhooks sortants : workers d'envoi et boucle de réessai
+	webhookService := services.NewWebhookService(webhookRepo)
+	recordShareService := services.NewRecordShareService(recordShareRepo, userRepo, departmentRepo)
+	accessCheckService := services.NewAccessCheckService(userRepo, ticketRepo, projectRepo, recordShareRepo)
+	accessDelegationService := services.NewAccessDelegationService(accessDelegationRepo, userRepo)
+	userImportService := services.NewUserImportService(userRepo, roleRepo, departmentRepo, filialeRepo, userInvitationRepo)
+	userPreferenceService := services.NewUserPreferenceService(userPreferenceRepo)
+	skillService := services.NewSkillService(skillRepo, userSkillRepo, userRepo)
+	userActivityService := services.NewUserActivityService(userActivityRepo, userRepo)
+	webhookService.Start(context.Background())
+	log.Println("✅ Dispatcher de webhooks démarré")
+
+	businessCalendarService := services.NewBusinessCalendarService(businessCalendarRepo, filialeRepo)
+	ticketAttachmentService := services.NewTicketAttachmentService(ticketAttachmentRepo, ticketRepo, userRepo, attachmentStorage, attachmentScanner)
+	ticketService := services.NewTicketService(ticketRepo, userRepo, ticketCommentRepo, ticketHistoryRepo, slaRepo, ticketSLARepo, ticketCategoryRepo, notificationRepo, notificationService, departmentRepo, filialeRepo, timeEntryRepo, softwareEnvironmentRepo, supportContractRepo, eventBus, jobQueue, businessCalendarService, ticketAttachmentService)
 	ticketCategoryService := services.NewTicketCategoryService(ticketCategoryRepo)
-	ticketSolutionService := services.NewTicketSolutionService(ticketSolutionRepo, ticketRepo, userRepo, roleRepo, knowledgeArticleRepo, knowledgeCategoryRepo)
+	knowledgeArticleService := services.NewKnowledgeArticleService(knowledgeArticleRepo, knowledgeCategoryRepo, userRepo, notificationService, knowledgeAttachmentRepo, knowledgeStorage, attachmentScanner, knowledgeSearchEngine)
+	ticketSolutionService := services.NewTicketSolutionService(ticketSolutionRepo, ticketRepo, userRepo, roleRepo, knowledgeArticleRepo, knowledgeCategoryRepo, ticketCategoryRepo, ticketHistoryRepo, knowledgeArticleService, ticketAttachmentService)
 	ticketInternalService := services.NewTicketInternalService(ticketInternalRepo, userRepo, departmentRepo, notificationService)
-	incidentService := services.NewIncidentService(incidentRepo, ticketRepo, ticketAssetRepo, assetRepo)
+	incidentService := services.NewIncidentService(incidentRepo, ticketRepo, ticketAssetRepo, assetRepo, eventBus)
 	serviceRequestService := services.NewServiceRequestService(serviceRequestRepo, serviceRequestTypeRepo, ticketRepo, userRepo)
 	serviceRequestTypeService := services.NewServiceRequestTypeService(serviceRequestTypeRepo, userRepo)
 	changeService := services.NewChangeService(changeRepo, ticketRepo, userRepo)
-	timeEntryService := services.NewTimeEntryService(timeEntryRepo, ticketRepo, userRepo, delayRepo)
-	delayService := services.NewDelayService(delayRepo, delayJustificationRepo, userRepo, ticketRepo)
-	assetService := services.NewAssetService(assetRepo, assetCategoryRepo, userRepo, ticketAssetRepo, ticketRepo)
+	timeEntryService := services.NewTimeEntryService(timeEntryRepo, ticketRepo, userRepo, delayRepo, ticketProjectTaskRepo, weeklyDeclarationRepo)
+	approvalService := services.NewApprovalService(approvalRepo, userRepo, eventBus)
+	delayService := services.NewDelayService(delayRepo, delayJustificationRepo, userRepo, ticketRepo, absenceRepo, departmentRepo, accessDelegationRepo, approvalService, jobQueue)
+	assetService := services.NewAssetService(assetRepo, assetCategoryRepo, userRepo, ticketAssetRepo, ticketRepo, assetLifecycleRepo)
 	assetCategoryService := services.NewAssetCategoryService(assetCategoryRepo, assetRepo, userRepo)
 	assetSoftwareService := services.NewAssetSoftwareService(assetSoftwareRepo, assetRepo)
-	slaService := services.NewSLAService(slaRepo, ticketSLARepo, ticketRepo, ticketCategoryRepo)
-	knowledgeArticleService := services.NewKnowledgeArticleService(knowledgeArticleRepo, knowledgeCategoryRepo, userRepo)
+	slaService := services.NewSLAService(slaRepo, ticketSLARepo, ticketRepo, ticketCategoryRepo, ticketService, eventBus, businessCalendarService)
+	knowledgeDeflectionService := services.NewKnowledgeDeflectionService(knowledgeDeflectionRepo, knowledgeArticleRepo, knowledgeSearchEngine)
+	knowledgeFeedbackService := services.NewKnowledgeFeedbackService(knowledgeFeedbackRepo, knowledgeArticleRepo)
+	knowledgeAttachmentService := services.NewKnowledgeAttachmentService(knowledgeAttachmentRepo, knowledgeArticleRepo, filialeRepo, knowledgeStorage, attachmentScanner)
+	knowledgePortalService := services.NewKnowledgePortalService(knowledgePortalRepo, knowledgeArticleRepo, knowledgeSearchEngine, knowledgeStorage)
 	knowledgeCategoryService := services.NewKnowledgeCategoryService(knowledgeCategoryRepo, userRepo)
-	projectService := services.NewProjectService(projectRepo, userRepo, projectBudgetExtRepo, projectPhaseRepo, projectFunctionRepo, projectMemberRepo, projectPhaseMemberRepo, projectTaskRepo, notificationService)
+	projectService := services.NewProjectService(projectRepo, userRepo, projectBudgetExtRepo, projectPhaseRepo, projectFunctionRepo, projectMemberRepo, projectPhaseMemberRepo, projectTaskRepo, projectMilestoneRepo, notificationService, eventBus)
+	capacityService := services.NewCapacityService(capacityRepo)
+	portfolioService := services.NewPortfolioService(portfolioRepo)
+	projectTemplateService := services.NewProjectTemplateService(projectTemplateRepo, projectPhaseRepo, userRepo, projectService)
+	projectTaskRecurrenceService := services.NewProjectTaskRecurrenceService(projectTaskRecurrenceRepo, projectTaskRepo, projectPhaseRepo, userRepo, projectService)
+	projectTaskTimerService := services.NewProjectTaskTimerService(projectTaskTimerRepo, projectTaskRepo, projectService, weeklyDeclarationRepo)
+	ticketProjectTaskService := services.NewTicketProjectTaskService(ticketProjectTaskRepo, projectTaskRepo, ticketRepo, recordShareRepo, projectService)
+	payrollExportService := services.NewPayrollExportService(payrollExportRepo)
+	absenceService := services.NewAbsenceService(absenceRepo, notificationService)
 	dailyDeclarationService := services.NewDailyDeclarationService(dailyDeclarationRepo, timeEntryRepo, userRepo)
-	weeklyDeclarationService := services.NewWeeklyDeclarationService(weeklyDeclarationRepo, userRepo)
+	weeklyDeclarationService := services.NewWeeklyDeclarationService(weeklyDeclarationRepo, userRepo, absenceRepo, notificationService)
 	performanceService := services.NewPerformanceService(
 		ticketRepo,
 		timeEntryRepo,
 		delayRepo,
 		userRepo,
+		capacityRepo,
+		absenceRepo,
+		roleRepo,
 	)
 	reportService := services.NewReportService(
 		ticketRepo,
@@ -167,19 +328,277 @@ func main() {
 		slaRepo,
 		userRepo,
 	)
-	searchService := services.NewSearchService(ticketRepo, assetRepo, knowledgeArticleRepo, userRepo, timeEntryRepo)
+	searchService := services.NewSearchService(ticketRepo, assetRepo, knowledgeArticleRepo, userRepo, timeEntryRepo, knowledgeSearchEngine)
+	jiraSyncService := services.NewJiraSyncService(config.AppConfig.Jira, jiraLinkRepo, ticketRepo, ticketCommentRepo, ticketHistoryRepo, userRepo, ticketService, jobQueue)
+	calendarFeedService := services.NewCalendarFeedService(calendarFeedRepo, userRepo, userPreferenceRepo)
+	calendarSyncService := services.NewCalendarSyncService(config.AppConfig.Calendar, calendarConnectionRepo, calendarFeedRepo, jobQueue)
+
+	// Bot Telegram : copie des notifications et actions rapides sur les tickets
+	telegramService := services.NewTelegramService(config.AppConfig.Telegram, telegramLinkRepo, userRepo, recordShareRepo, ticketService, jobQueue)
+	if telegramService.Enabled() {
+		notificationService.AddChannel(telegramService)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := telegramService.RegisterWebhook(ctx); err != nil {
+				log.Printf("⚠️  Déclaration du webhook Telegram impossible: %v", err)
+			}
+		}()
+	}
+
+	// WhatsApp Business : notifications critiques (SLA violés, incidents majeurs) des utilisateurs ayant consenti
+	whatsAppService := services.NewWhatsAppService(config.AppConfig.WhatsApp, whatsAppRepo, jobQueue)
+	if whatsAppService.Enabled() {
+		notificationService.AddChannel(whatsAppService)
+	}
+
+	// Notifications push mobiles (FCM / APNs) sur les appareils enregistrés, en plus du WebSocket
+	pushService := services.NewPushService(config.AppConfig.Push, pushRepo, userSessionRepo, jobQueue)
+	if pushService.Enabled() {
+		notificationService.AddChannel(pushService)
+	}
+
+	// Emails (SMTP) : tickets créés, assignés, changements de statut et SLA à risque, selon les préférences de chaque utilisateur
+	emailService := services.NewEmailService(config.AppConfig.SMTP, config.AppConfig.App.Name, userRepo, userPreferenceRepo, notificationPreferenceRepo, jobQueue)
+	if emailService.Enabled() {
+		notificationService.AddChannel(emailService)
+	}
+
+	// Supervision : incidents créés et résolus à partir des alertes Alertmanager / Zabbix
+	monitoringAlertService := services.NewMonitoringAlertService(monitoringAlertRepo, assetRepo, ticketService, incidentService)
+	assetDiscoveryService := services.NewAssetDiscoveryService(assetDiscoveryRepo, assetCategoryRepo, filialeRepo)
+	assetRelationService := services.NewAssetRelationService(assetRelationRepo, assetRepo, softwareRepo)
+	assetReservationService := services.NewAssetReservationService(assetReservationRepo, assetRepo, userRepo, notificationService)
+	assetImportService := services.NewAssetImportService(assetRepo, assetCategoryRepo, userRepo, filialeRepo)
+	maintenancePlanService := services.NewMaintenancePlanService(maintenancePlanRepo, assetRepo, assetCategoryRepo, userRepo, ticketCategoryRepo, ticketAssetRepo, ticketChecklistRepo, ticketService)
+	gitIntegrationService := services.NewGitIntegrationService(config.AppConfig.Git, gitLinkRepo, ticketRepo, ticketHistoryRepo, userRepo, ticketService)
+	reportingFeedService := services.NewReportingFeedService(reportingRepo)
+	syncService := services.NewSyncService(syncRepo)
+
+	escalationService := services.NewEscalationService(escalationRuleRepo, userRepo, filialeRepo, ticketCategoryRepo, ticketService, notificationService)
+	ticketViewService := services.NewTicketViewService(ticketViewRepo, ticketService)
+	ticketMergeService := services.NewTicketMergeService(ticketRepo, recordShareRepo, ticketService, eventBus)
+	ticketWatcherService := services.NewTicketWatcherService(ticketWatcherRepo, userRepo, recordShareRepo)
+	ticketChecklistService := services.NewTicketChecklistService(ticketChecklistRepo, ticketRepo, userRepo, recordShareRepo)
+	ticketSatisfactionService := services.NewTicketSatisfactionService(ticketSatisfactionRepo, notificationService)
+	ticketRelationService := services.NewTicketRelationService(ticketRelationRepo, ticketRepo, recordShareRepo)
+	ticketExportService := services.NewTicketExportService(ticketRepo)
+	ticketTimelineService := services.NewTicketTimelineService(ticketTimelineRepo, ticketSLARepo, businessCalendarService, recordShareRepo)
+	recurringTicketService := services.NewRecurringTicketService(recurringTicketRepo, userRepo, filialeRepo, projectRepo, ticketCategoryRepo, ticketService)
+	problemService := services.NewProblemService(problemRepo, ticketRepo, knowledgeArticleService)
+	approvalService.RegisterSubject(models.ApprovalEntityServiceRequest, services.NewServiceRequestApprovalSubject(serviceRequestRepo, serviceRequestService))
+	approvalService.RegisterSubject(models.ApprovalEntityChange, services.NewChangeApprovalSubject(changeRepo))
+	approvalService.RegisterSubject(models.ApprovalEntityBudgetExtension, services.NewBudgetExtensionApprovalSubject(projectBudgetExtRepo, projectRepo, projectService))
+	approvalService.RegisterSubject(models.ApprovalEntityWeeklyDeclaration, services.NewWeeklyDeclarationApprovalSubject(weeklyDeclarationRepo, weeklyDeclarationService))
+	approvalService.RegisterSubject(models.ApprovalEntityAssetReservation, services.NewAssetReservationApprovalSubject(assetReservationRepo, assetReservationService))
+	approvalService.RegisterSubject(models.ApprovalEntityDelayJustification, services.NewDelayJustificationApprovalSubject(delayJustificationRepo, delayRepo))
+	majorIncidentService := services.NewMajorIncidentService(incidentRepo, majorIncidentRepo, incidentService, eventBus)
+	softwareLicenseService := services.NewSoftwareLicenseService(softwareLicenseRepo, softwareRepo, filialeRepo, userRepo, notificationService)
+
+	// Abonner les consommateurs (notifications, webhooks, audit, index de recherche, Jira, escalades) aux événements métier
+	services.RegisterEventSubscribers(eventBus, notificationService, webhookService, searchService, auditLogRepo, jiraSyncService, userRepo, escalationService, ticketWatcherRepo, ticketSatisfactionService)
+
+	// Enregistrer les handlers des tâches puis démarrer les workers
+	importService := services.NewImportService(importRepo, userRepo, roleRepo, departmentRepo, filialeRepo, ticketCategoryRepo, assetCategoryRepo, knowledgeCategoryRepo, attachmentStorage, jobQueue)
+	services.RegisterJobHandlers(jobQueue, notificationService, ticketService, ticketHistoryRepo, jiraSyncService, importService, calendarSyncService, telegramService, whatsAppService, pushService, emailService)
+	if err := jobQueue.Start(); err != nil {
+		log.Fatalf("❌ Erreur lors du démarrage de la file de tâches: %v", err)
+	}
+	defer jobQueue.Shutdown()
+	log.Printf("✅ File de tâches démarrée (backend: %s)", jobQueue.Backend())
 	statisticsService := services.NewStatisticsService(ticketRepo, slaRepo, userRepo, timeEntryRepo)
-	auditService := services.NewAuditService(auditLogRepo)
+	auditService := services.NewAuditService(auditLogRepo, auditArchiveStorage)
 	settingsService := services.NewSettingsService(settingsRepo)
 	requestSourceService := services.NewRequestSourceService(requestSourceRepo)
 	backupService := services.NewBackupService(settingsRepo)
+
+	// Planificateur des tâches périodiques (planifications stockées dans les paramètres, catégorie "scheduler")
+	jobScheduler := scheduler.New(settingsRepo, scheduledJobRunRepo)
+	jobScheduler.Register(scheduler.Job{
+		Name:            "sessions_purge",
+		Description:     "Suppression des sessions expirées",
+		DefaultSchedule: "0 * * * *",
+		Run: func(ctx context.Context) error {
+			return userSessionRepo.DeleteExpired()
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "webhook_deliveries_purge",
+		Description:     "Purge du journal de livraison des webhooks (plus de 30 jours)",
+		DefaultSchedule: "30 3 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := webhookRepo.PurgeDeliveries(time.Now().AddDate(0, 0, -30))
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "record_shares_purge",
+		Description:     "Suppression des partages de tickets et de projets expirés depuis plus de 30 jours",
+		DefaultSchedule: "15 4 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := recordShareRepo.PurgeExpired(time.Now().AddDate(0, 0, -30))
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "audit_logs_archive",
+		Description:     "Archivage puis purge des logs d'audit plus anciens que AUDIT_RETENTION_DAYS",
+		DefaultSchedule: "0 4 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := auditService.Archive(ctx)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "sync_tombstones_purge",
+		Description:     "Purge des traces de suppression de la synchronisation mobile (plus de 90 jours)",
+		DefaultSchedule: "20 4 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := syncService.PurgeTombstones()
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "sla_statuses",
+		Description:     "Surveillance des échéances SLA des tickets ouverts (passage à risque puis violé, notification des assignés et de leurs responsables)",
+		DefaultSchedule: "*/5 * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := slaService.RecalculateSLAStatuses()
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "ticket_escalations",
+		Description:     "Escalade des tickets restés trop longtemps dans un statut selon les règles d'escalade (réassignation, priorité, notification)",
+		DefaultSchedule: "*/15 * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := escalationService.EvaluateStatusRules()
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "recurring_tickets",
+		Description:     "Création des tickets récurrents arrivés à échéance (planifications cron ou RRULE)",
+		DefaultSchedule: "* * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := recurringTicketService.RunDue(ctx)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "major_incident_updates",
+		Description:     "Rappel des points de situation en retard sur les incidents majeurs en cours",
+		DefaultSchedule: "*/5 * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := majorIncidentService.RemindOverdueUpdates()
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "software_license_expiry",
+		Description:     "Alerte des gestionnaires sur les licences logicielles arrivant à échéance (30 jours)",
+		DefaultSchedule: "0 7 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := softwareLicenseService.NotifyExpiring(30)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "asset_reservation_reminders",
+		Description:     "Rappel des retours d'équipements prêtés dus sous 24 h et alerte des prêts en retard",
+		DefaultSchedule: "0 * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := assetReservationService.SendReminders(24 * time.Hour)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "knowledge_review_alerts",
+		Description:     "Alerte des relectures d'articles en retard et des articles dont la révision périodique est échue",
+		DefaultSchedule: "0 8 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := knowledgeArticleService.SendReviewAlerts()
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "project_milestone_alerts",
+		Description:     "Alerte du chef de projet, du lead et du créateur des jalons de projet passés en retard",
+		DefaultSchedule: "0 8 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := projectService.SendMilestoneLateAlerts()
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "project_task_recurrences",
+		Description:     "Création des occurrences à venir des tâches de projet récurrentes et clôture des occurrences expirées",
+		DefaultSchedule: "*/15 * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := projectTaskRecurrenceService.RunDue(ctx)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "knowledge_search_index",
+		Description:     "Réindexation des articles de la base de connaissances dans le moteur de recherche (articles importés ou créés depuis un ticket ; sans effet avec KB_SEARCH_DRIVER=mysql)",
+		DefaultSchedule: "15 * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := knowledgeArticleService.ReindexSearch()
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "maintenance_plans",
+		Description:     "Création des tickets de maintenance préventive des actifs arrivés à échéance",
+		DefaultSchedule: "*/5 * * * *",
+		Run: func(ctx context.Context) error {
+			_, err := maintenancePlanService.RunDue(ctx)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "backup",
+		Description:     "Sauvegarde complète",
+		DefaultSchedule: "0 2 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := backupService.ExecuteBackup("full", 0)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "scheduler_runs_purge",
+		Description:     "Purge de l'historique des tâches planifiées (plus de 30 jours)",
+		DefaultSchedule: "45 3 * * *",
+		Run: func(ctx context.Context) error {
+			_, err := scheduledJobRunRepo.PurgeBefore(time.Now().AddDate(0, 0, -30))
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:            "calendar_sync",
+		Description:     "Synchronisation des agendas Google et Microsoft connectés",
+		DefaultSchedule: "*/15 * * * *",
+		Run: func(ctx context.Context) error {
+			return calendarSyncService.EnqueueAll(ctx)
+		},
+	})
+	if config.AppConfig.Scheduler.Enabled {
+		jobScheduler.Start()
+		defer jobScheduler.Stop()
+		log.Println("✅ Planificateur de tâches démarré")
+	} else {
+		log.Println("⏸️  Planificateur de tâches désactivé sur cette instance (SCHEDULER_ENABLED=false)")
+	}
 	officeService := services.NewOfficeService(officeRepo, filialeRepo)
-	departmentService := services.NewDepartmentService(departmentRepo, officeRepo, filialeRepo)
-	softwareRepo := repositories.NewSoftwareRepository()
+	departmentService := services.NewDepartmentService(departmentRepo, officeRepo, filialeRepo, userRepo)
 	filialeSoftwareRepo := repositories.NewFilialeSoftwareRepository()
 	filialeService := services.NewFilialeService(filialeRepo)
 	softwareService := services.NewSoftwareService(softwareRepo)
 	filialeSoftwareService := services.NewFilialeSoftwareService(filialeSoftwareRepo, filialeRepo, softwareRepo)
+	softwareReleaseService := services.NewSoftwareReleaseService(softwareReleaseRepo, softwareRepo, filialeSoftwareRepo, jobQueue)
+	softwareEnvironmentService := services.NewSoftwareEnvironmentService(softwareEnvironmentRepo, filialeRepo, softwareRepo, filialeSoftwareRepo)
+	supportContractService := services.NewSupportContractService(supportContractRepo, filialeRepo, softwareRepo)
 	timesheetService := services.NewTimesheetService(
 		timeEntryService,
 		dailyDeclarationService,
@@ -189,6 +608,8 @@ func main() {
 		delayRepo,
 		delayJustificationRepo,
 		userRepo,
+		absenceRepo,
+		weeklyDeclarationRepo,
 	)
 
 	// Initialiser tous les handlers
@@ -211,6 +632,20 @@ func main() {
 	assetCategoryHandler := handlers.NewAssetCategoryHandler(assetCategoryService)
 	assetSoftwareHandler := handlers.NewAssetSoftwareHandler(assetSoftwareService)
 	slaHandler := handlers.NewSLAHandler(slaService)
+	businessCalendarHandler := handlers.NewBusinessCalendarHandler(businessCalendarService)
+	escalationHandler := handlers.NewEscalationHandler(escalationService)
+	ticketViewHandler := handlers.NewTicketViewHandler(ticketViewService)
+	ticketMergeHandler := handlers.NewTicketMergeHandler(ticketMergeService)
+	ticketWatcherHandler := handlers.NewTicketWatcherHandler(ticketWatcherService)
+	ticketChecklistHandler := handlers.NewTicketChecklistHandler(ticketChecklistService)
+	ticketSatisfactionHandler := handlers.NewTicketSatisfactionHandler(ticketSatisfactionService)
+	ticketRelationHandler := handlers.NewTicketRelationHandler(ticketRelationService)
+	ticketExportHandler := handlers.NewTicketExportHandler(ticketExportService)
+	ticketTimelineHandler := handlers.NewTicketTimelineHandler(ticketTimelineService)
+	recurringTicketHandler := handlers.NewRecurringTicketHandler(recurringTicketService)
+	problemHandler := handlers.NewProblemHandler(problemService)
+	approvalHandler := handlers.NewApprovalHandler(approvalService)
+	majorIncidentHandler := handlers.NewMajorIncidentHandler(majorIncidentService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
 	knowledgeArticleHandler := handlers.NewKnowledgeArticleHandler(knowledgeArticleService)
 	knowledgeCategoryHandler := handlers.NewKnowledgeCategoryHandler(knowledgeCategoryService)
@@ -233,50 +668,177 @@ func main() {
 	filialeSoftwareHandler := handlers.NewFilialeSoftwareHandler(filialeSoftwareService)
 	wsHandler := handlers.NewWebSocketHandler(wsHub)
 	diagnosticHandler := handlers.NewDiagnosticHandler(filialeRepo)
+	healthHandler := handlers.NewHealthHandler()
+	switch attachmentStorage.Backend() {
+	case "local":
+		healthHandler.RegisterCheck("storage", true, handlers.CheckUploadStorage)
+	case "s3":
+		healthHandler.RegisterCheck("object_storage", true, func(ctx context.Context) (any, error) {
+			_, err := attachmentStorage.Exists(ctx, ".readyz")
+			return map[string]string{"backend": "s3", "bucket": config.AppConfig.Storage.S3Bucket}, err
+		})
+	}
+	if jobQueue.Backend() == "redis" {
+		healthHandler.RegisterCheck("job_queue", false, func(ctx context.Context) (any, error) {
+			return nil, jobQueue.Ping()
+		})
+	}
+	loggingHandler := handlers.NewLoggingHandler()
+	configHandler := handlers.NewConfigHandler()
+	fileHandler := handlers.NewFileHandler(map[string]storage.Storage{
+		storage.NamespaceTickets: attachmentStorage,
+		storage.NamespaceUsers:   avatarStorage,
+	}, config.AppConfig.Storage.SigningSecret)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	recordShareHandler := handlers.NewRecordShareHandler(recordShareService)
+	accessCheckHandler := handlers.NewAccessCheckHandler(accessCheckService)
+	accessDelegationHandler := handlers.NewAccessDelegationHandler(accessDelegationService)
+	userImportHandler := handlers.NewUserImportHandler(userImportService)
+	userPreferenceHandler := handlers.NewUserPreferenceHandler(userPreferenceService)
+	i18nHandler := handlers.NewI18nHandler()
+	skillHandler := handlers.NewSkillHandler(skillService)
+	userActivityHandler := handlers.NewUserActivityHandler(userActivityService)
+	softwareReleaseHandler := handlers.NewSoftwareReleaseHandler(softwareReleaseService)
+	softwareEnvironmentHandler := handlers.NewSoftwareEnvironmentHandler(softwareEnvironmentService)
+	supportContractHandler := handlers.NewSupportContractHandler(supportContractService)
+	softwareLicenseHandler := handlers.NewSoftwareLicenseHandler(softwareLicenseService)
+	jiraHandler := handlers.NewJiraHandler(jiraSyncService)
+	importHandler := handlers.NewImportHandler(importService)
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(calendarFeedService)
+	calendarSyncHandler := handlers.NewCalendarSyncHandler(calendarSyncService)
+	telegramHandler := handlers.NewTelegramHandler(telegramService)
+	whatsAppHandler := handlers.NewWhatsAppHandler(whatsAppService)
+	monitoringAlertHandler := handlers.NewMonitoringAlertHandler(monitoringAlertService)
+	assetDiscoveryHandler := handlers.NewAssetDiscoveryHandler(assetDiscoveryService)
+	assetRelationHandler := handlers.NewAssetRelationHandler(assetRelationService)
+	assetReservationHandler := handlers.NewAssetReservationHandler(assetReservationService)
+	assetImportHandler := handlers.NewAssetImportHandler(assetImportService)
+	maintenancePlanHandler := handlers.NewMaintenancePlanHandler(maintenancePlanService)
+	knowledgeDeflectionHandler := handlers.NewKnowledgeDeflectionHandler(knowledgeDeflectionService)
+	knowledgeFeedbackHandler := handlers.NewKnowledgeFeedbackHandler(knowledgeFeedbackService)
+	knowledgeAttachmentHandler := handlers.NewKnowledgeAttachmentHandler(knowledgeAttachmentService)
+	knowledgePortalHandler := handlers.NewKnowledgePortalHandler(knowledgePortalService)
+	capacityHandler := handlers.NewCapacityHandler(capacityService)
+	portfolioHandler := handlers.NewPortfolioHandler(portfolioService)
+	projectTemplateHandler := handlers.NewProjectTemplateHandler(projectTemplateService)
+	projectTaskRecurrenceHandler := handlers.NewProjectTaskRecurrenceHandler(projectTaskRecurrenceService)
+	projectTaskTimerHandler := handlers.NewProjectTaskTimerHandler(projectTaskTimerService)
+	ticketProjectTaskHandler := handlers.NewTicketProjectTaskHandler(ticketProjectTaskService)
+	payrollExportHandler := handlers.NewPayrollExportHandler(payrollExportService)
+	absenceHandler := handlers.NewAbsenceHandler(absenceService)
+	gitHandler := handlers.NewGitHandler(gitIntegrationService)
+	reportingFeedHandler := handlers.NewReportingFeedHandler(reportingFeedService)
+	syncHandler := handlers.NewSyncHandler(syncService)
+	pushHandler := handlers.NewPushHandler(pushService)
+	notificationPreferenceHandler := handlers.NewNotificationPreferenceHandler(emailService)
+	jobHandler := handlers.NewJobHandler(jobQueue)
+	schedulerHandler := handlers.NewSchedulerHandler(jobScheduler)
 
 	// Créer la structure Handlers
 	appHandlers := &routes.Handlers{
-		AuthHandler:               authHandler,
-		UserHandler:               userHandler,
-		RoleHandler:               roleHandler,
-		PermissionHandler:         permissionHandler,
-		TicketHandler:             ticketHandler,
-		TicketAttachmentHandler:   ticketAttachmentHandler,
-		TicketCategoryHandler:     ticketCategoryHandler,
-		TicketSolutionHandler:     ticketSolutionHandler,
-		TicketInternalHandler:     ticketInternalHandler,
-		IncidentHandler:           incidentHandler,
-		ChangeHandler:             changeHandler,
-		ServiceRequestHandler:     serviceRequestHandler,
-		ServiceRequestTypeHandler: serviceRequestTypeHandler,
-		TimeEntryHandler:          timeEntryHandler,
-		DelayHandler:              delayHandler,
-		AssetHandler:              assetHandler,
-		AssetCategoryHandler:      assetCategoryHandler,
-		AssetSoftwareHandler:      assetSoftwareHandler,
-		SLAHandler:                slaHandler,
-		NotificationHandler:       notificationHandler,
-		KnowledgeArticleHandler:   knowledgeArticleHandler,
-		KnowledgeCategoryHandler:  knowledgeCategoryHandler,
-		ProjectHandler:            projectHandler,
-		DailyDeclarationHandler:   dailyDeclarationHandler,
-		WeeklyDeclarationHandler:  weeklyDeclarationHandler,
-		PerformanceHandler:        performanceHandler,
-		ReportHandler:             reportHandler,
-		SearchHandler:             searchHandler,
-		StatisticsHandler:         statisticsHandler,
-		AuditHandler:              auditHandler,
-		SettingsHandler:           settingsHandler,
-		RequestSourceHandler:      requestSourceHandler,
-		BackupHandler:             backupHandler,
-		TimesheetHandler:          timesheetHandler,
-		OfficeHandler:             officeHandler,
-		DepartmentHandler:         departmentHandler,
-		FilialeHandler:            filialeHandler,
-		SoftwareHandler:           softwareHandler,
-		FilialeSoftwareHandler:    filialeSoftwareHandler,
-		WebSocketHandler:          wsHandler,
-		DiagnosticHandler:         diagnosticHandler,
+		AuthHandler:                   authHandler,
+		UserHandler:                   userHandler,
+		RoleHandler:                   roleHandler,
+		PermissionHandler:             permissionHandler,
+		TicketHandler:                 ticketHandler,
+		TicketAttachmentHandler:       ticketAttachmentHandler,
+		TicketCategoryHandler:         ticketCategoryHandler,
+		TicketSolutionHandler:         ticketSolutionHandler,
+		TicketInternalHandler:         ticketInternalHandler,
+		IncidentHandler:               incidentHandler,
+		ChangeHandler:                 changeHandler,
+		ServiceRequestHandler:         serviceRequestHandler,
+		ServiceRequestTypeHandler:     serviceRequestTypeHandler,
+		TimeEntryHandler:              timeEntryHandler,
+		DelayHandler:                  delayHandler,
+		AssetHandler:                  assetHandler,
+		AssetCategoryHandler:          assetCategoryHandler,
+		AssetSoftwareHandler:          assetSoftwareHandler,
+		SLAHandler:                    slaHandler,
+		NotificationHandler:           notificationHandler,
+		KnowledgeArticleHandler:       knowledgeArticleHandler,
+		KnowledgeCategoryHandler:      knowledgeCategoryHandler,
+		ProjectHandler:                projectHandler,
+		DailyDeclarationHandler:       dailyDeclarationHandler,
+		WeeklyDeclarationHandler:      weeklyDeclarationHandler,
+		PerformanceHandler:            performanceHandler,
+		ReportHandler:                 reportHandler,
+		SearchHandler:                 searchHandler,
+		StatisticsHandler:             statisticsHandler,
+		AuditHandler:                  auditHandler,
+		SettingsHandler:               settingsHandler,
+		RequestSourceHandler:          requestSourceHandler,
+		BackupHandler:                 backupHandler,
+		TimesheetHandler:              timesheetHandler,
+		OfficeHandler:                 officeHandler,
+		DepartmentHandler:             departmentHandler,
+		FilialeHandler:                filialeHandler,
+		SoftwareHandler:               softwareHandler,
+		FilialeSoftwareHandler:        filialeSoftwareHandler,
+		WebSocketHandler:              wsHandler,
+		DiagnosticHandler:             diagnosticHandler,
+		HealthHandler:                 healthHandler,
+		LoggingHandler:                loggingHandler,
+		ConfigHandler:                 configHandler,
+		FileHandler:                   fileHandler,
+		WebhookHandler:                webhookHandler,
+		RecordShareHandler:            recordShareHandler,
+		JobHandler:                    jobHandler,
+		SchedulerHandler:              schedulerHandler,
+		AccessCheckHandler:            accessCheckHandler,
+		AccessDelegationHandler:       accessDelegationHandler,
+		UserImportHandler:             userImportHandler,
+		UserPreferenceHandler:         userPreferenceHandler,
+		I18nHandler:                   i18nHandler,
+		SkillHandler:                  skillHandler,
+		UserActivityHandler:           userActivityHandler,
+		SoftwareReleaseHandler:        softwareReleaseHandler,
+		SoftwareEnvironmentHandler:    softwareEnvironmentHandler,
+		SupportContractHandler:        supportContractHandler,
+		JiraHandler:                   jiraHandler,
+		ImportHandler:                 importHandler,
+		CalendarFeedHandler:           calendarFeedHandler,
+		CalendarSyncHandler:           calendarSyncHandler,
+		TelegramHandler:               telegramHandler,
+		WhatsAppHandler:               whatsAppHandler,
+		MonitoringAlertHandler:        monitoringAlertHandler,
+		GitHandler:                    gitHandler,
+		ReportingFeedHandler:          reportingFeedHandler,
+		SyncHandler:                   syncHandler,
+		PushHandler:                   pushHandler,
+		NotificationPreferenceHandler: notificationPreferenceHandler,
+		BusinessCalendarHandler:       businessCalendarHandler,
+		EscalationHandler:             escalationHandler,
+		TicketViewHandler:             ticketViewHandler,
+		TicketMergeHandler:            ticketMergeHandler,
+		TicketWatcherHandler:          ticketWatcherHandler,
+		TicketChecklistHandler:        ticketChecklistHandler,
+		TicketSatisfactionHandler:     ticketSatisfactionHandler,
+		TicketRelationHandler:         ticketRelationHandler,
+		TicketExportHandler:           ticketExportHandler,
+		TicketTimelineHandler:         ticketTimelineHandler,
+		RecurringTicketHandler:        recurringTicketHandler,
+		ProblemHandler:                problemHandler,
+		ApprovalHandler:               approvalHandler,
+		MajorIncidentHandler:          majorIncidentHandler,
+		SoftwareLicenseHandler:        softwareLicenseHandler,
+		AssetDiscoveryHandler:         assetDiscoveryHandler,
+		AssetRelationHandler:          assetRelationHandler,
+		AssetReservationHandler:       assetReservationHandler,
+		AssetImportHandler:            assetImportHandler,
+		MaintenancePlanHandler:        maintenancePlanHandler,
+		KnowledgeDeflectionHandler:    knowledgeDeflectionHandler,
+		KnowledgeFeedbackHandler:      knowledgeFeedbackHandler,
+		KnowledgeAttachmentHandler:    knowledgeAttachmentHandler,
+		KnowledgePortalHandler:        knowledgePortalHandler,
+		CapacityHandler:               capacityHandler,
+		PortfolioHandler:              portfolioHandler,
+		ProjectTemplateHandler:        projectTemplateHandler,
+		ProjectTaskRecurrenceHandler:  projectTaskRecurrenceHandler,
+		ProjectTaskTimerHandler:       projectTaskTimerHandler,
+		TicketProjectTaskHandler:      ticketProjectTaskHandler,
+		PayrollExportHandler:          payrollExportHandler,
+		AbsenceHandler:                absenceHandler,
 	}
 
 	// Configurer Gin
@@ -284,8 +846,13 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Créer le routeur
-	router := gin.Default()
+	// Créer le routeur (logger Gin remplacé par RequestLoggerMiddleware, voir routes.SetupRoutes)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	// IP cliente : X-Forwarded-For n'est lu que derrière les proxies déclarés (TRUSTED_PROXIES), sinon l'IP de connexion
+	if err := router.SetTrustedProxies(config.AppConfig.Server.TrustedProxies); err != nil {
+		log.Fatalf("❌ TRUSTED_PROXIES invalide: %v", err)
+	}
 
 	// Configurer les routes
 	routes.SetupRoutes(router, appHandlers, auditLogRepo)
@@ -294,7 +861,8 @@ func main() {
 	port := ":" + config.AppConfig.AppPort
 	log.Printf("🚀 Serveur démarré sur le port %s", config.AppConfig.AppPort)
 	log.Printf("📡 API disponible sur http://localhost%s/api/v1", port)
-	log.Printf("💚 Health check: http://localhost%s/health", port)
+	log.Printf("📡 API v2 (enveloppe unifiée, erreurs RFC 7807) sur http://localhost%s/api/v2", port)
+	log.Printf("💚 Health check: http://localhost%s/healthz (readiness: /readyz)", port)
 	log.Printf("📚 Swagger UI: http://localhost%s/swagger/index.html", port)
 
 	if err := http.ListenAndServe(port, router); err != nil {
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/storage"
+)
+
+// Importe les données d'une instance GLPI (GLPI_URL, GLPI_APP_TOKEN, GLPI_USER_TOKEN) sans passer par la file de tâches
+// Exemple : go run ./cmd/glpi-import -user 1 -filiale 2 -role 5
+// Un import interrompu se reprend avec -resume <id> ; les éléments déjà importés sont ignorés
+func main() {
+	userID := flag.Uint("user", 0, "ID de l'utilisateur Kronos auteur de l'import")
+	filialeID := flag.Uint("filiale", 0, "ID de la filiale des utilisateurs, actifs et tickets importés")
+	roleID := flag.Uint("role", 0, "ID du rôle des utilisateurs créés")
+	departmentID := flag.Uint("department", 0, "ID du département des utilisateurs créés (optionnel)")
+	stages := flag.String("stages", "", "Étapes à importer, séparées par des virgules (users,categories,assets,tickets ; toutes par défaut)")
+	skipAttachments := flag.Bool("skip-attachments", false, "Ne pas télécharger les documents des tickets")
+	batchSize := flag.Int("batch", 50, "Nombre d'éléments lus par appel à l'API GLPI (1 à 200)")
+	resume := flag.Uint("resume", 0, "ID d'un import en échec ou arrêté à reprendre")
+	flag.Parse()
+
+	// Charger la configuration
+	config.LoadConfig()
+
+	// Se connecter à la base de données
+	if err := database.Connect(); err != nil {
+		log.Fatalf("❌ Erreur de connexion à la base de données: %v", err)
+	}
+	defer database.Close()
+
+	attachmentStorage, err := storage.New(config.AppConfig, storage.NamespaceTickets, config.AppConfig.App.TicketAttachmentsDir)
+	if err != nil {
+		log.Fatalf("❌ Stockage des pièces jointes: %v", err)
+	}
+
+	// Sans file de tâches, les lots sont traités ci-dessous l'un après l'autre
+	importService := services.NewImportService(
+		repositories.NewImportRepository(),
+		repositories.NewUserRepository(),
+		repositories.NewRoleRepository(),
+		repositories.NewDepartmentRepository(),
+		repositories.NewFilialeRepository(),
+		repositories.NewTicketCategoryRepository(),
+		repositories.NewAssetCategoryRepository(),
+		repositories.NewKnowledgeCategoryRepository(),
+		attachmentStorage,
+		nil,
+	)
+
+	var run *dto.ImportRunDTO
+	if *resume != 0 {
+		run, err = importService.Resume(*resume)
+	} else {
+		if *userID == 0 || *filialeID == 0 || *roleID == 0 {
+			log.Fatalf("❌ Les options -user, -filiale et -role sont obligatoires")
+		}
+		req := dto.StartGLPIImportRequest{
+			FilialeID:       *filialeID,
+			RoleID:          *roleID,
+			SkipAttachments: *skipAttachments,
+			BatchSize:       *batchSize,
+		}
+		if *departmentID != 0 {
+			req.DepartmentID = departmentID
+		}
+		if *stages != "" {
+			req.Stages = strings.Split(*stages, ",")
+		}
+		run, err = importService.StartGLPI(req, *userID)
+	}
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	log.Printf("🔄 Import GLPI n°%d (étapes: %s)", run.ID, strings.Join(run.Stages, ", "))
+
+	ctx := context.Background()
+	batch := &jobs.ImportBatchPayload{RunID: run.ID, Stage: run.Stage, Offset: run.Offset}
+	for batch != nil {
+		batch, err = importService.ProcessBatch(ctx, *batch)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if run, err = importService.GetByID(run.ID); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if batch != nil {
+			log.Printf("   %s: %d/%d", run.Stage, run.Offset, run.Total)
+		}
+	}
+
+	for stage, stats := range run.Stats {
+		log.Printf("   %s: %d créé(s), %d existant(s), %d ignoré(s), %d en erreur", stage, stats.Created, stats.Matched, stats.Skipped, stats.Failed)
+	}
+	for _, message := range run.Errors {
+		log.Printf("⚠️  %s", message)
+	}
+	if run.Status != "completed" {
+		log.Fatalf("❌ Import %s: %s (reprise: -resume %d)", run.Status, run.LastError, run.ID)
+	}
+	log.Println("✅ Import GLPI terminé")
+}
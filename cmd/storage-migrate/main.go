@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/storage"
+)
+
+// Copie les fichiers (pièces jointes, avatars) d'un backend de stockage vers un autre
+// Exemple : go run ./cmd/storage-migrate -from local -to s3
+func main() {
+	from := flag.String("from", "local", "Backend source (local, s3)")
+	to := flag.String("to", "s3", "Backend destination (local, s3)")
+	namespace := flag.String("namespace", "all", "Espace à migrer (tickets, users, all)")
+	overwrite := flag.Bool("overwrite", false, "Remplacer les fichiers déjà présents dans la destination")
+	deleteSource := flag.Bool("delete-source", false, "Supprimer les fichiers de la source après copie")
+	dryRun := flag.Bool("dry-run", false, "Lister les fichiers à copier sans rien modifier")
+	flag.Parse()
+
+	if *from == *to {
+		log.Fatalf("❌ Les backends source et destination doivent être différents")
+	}
+
+	// Charger la configuration
+	config.LoadConfig()
+
+	namespaces := map[string]string{
+		storage.NamespaceTickets:   config.AppConfig.App.TicketAttachmentsDir,
+		storage.NamespaceUsers:     config.AppConfig.App.AvatarDir,
+		storage.NamespaceKnowledge: config.AppConfig.App.KnowledgeAttachmentsDir,
+	}
+	if *namespace != "all" {
+		dir, ok := namespaces[*namespace]
+		if !ok {
+			log.Fatalf("❌ Espace inconnu: %s", *namespace)
+		}
+		namespaces = map[string]string{*namespace: dir}
+	}
+
+	ctx := context.Background()
+	failed := false
+	for name, dir := range namespaces {
+		source, err := newStorage(*from, name, dir)
+		if err != nil {
+			log.Fatalf("❌ Stockage source %s (%s): %v", *from, name, err)
+		}
+		destination, err := newStorage(*to, name, dir)
+		if err != nil {
+			log.Fatalf("❌ Stockage destination %s (%s): %v", *to, name, err)
+		}
+
+		log.Printf("🔄 Migration de l'espace %s (%s → %s)...", name, *from, *to)
+		copied, skipped, errorsCount := 0, 0, 0
+		walkErr := source.Walk(ctx, func(key string) error {
+			if !*overwrite {
+				exists, err := destination.Exists(ctx, key)
+				if err != nil {
+					log.Printf("⚠️  %s/%s: %v", name, key, err)
+					errorsCount++
+					return nil
+				}
+				if exists {
+					skipped++
+					return nil
+				}
+			}
+			if *dryRun {
+				log.Printf("   %s/%s", name, key)
+				copied++
+				return nil
+			}
+			if err := copyObject(ctx, source, destination, key); err != nil {
+				log.Printf("⚠️  %s/%s: %v", name, key, err)
+				errorsCount++
+				return nil
+			}
+			if *deleteSource {
+				if err := source.Delete(ctx, key); err != nil {
+					log.Printf("⚠️  Suppression de %s/%s dans la source: %v", name, key, err)
+				}
+			}
+			copied++
+			return nil
+		})
+		if walkErr != nil {
+			log.Printf("❌ Parcours de l'espace %s interrompu: %v", name, walkErr)
+			failed = true
+		}
+		if errorsCount > 0 {
+			failed = true
+		}
+		log.Printf("✅ Espace %s: %d copié(s), %d ignoré(s) (déjà présents), %d erreur(s)", name, copied, skipped, errorsCount)
+	}
+
+	if failed {
+		log.Fatalf("❌ Migration terminée avec des erreurs")
+	}
+	log.Println("✨ Migration du stockage terminée avec succès!")
+}
+
+// newStorage crée le stockage d'un espace avec le backend demandé (les autres paramètres viennent de la configuration)
+func newStorage(driver, namespace, localDir string) (storage.Storage, error) {
+	cfg := *config.AppConfig
+	cfg.Storage.Driver = driver
+	return storage.New(&cfg, namespace, localDir)
+}
+
+// copyObject copie un fichier de la source vers la destination
+func copyObject(ctx context.Context, source, destination storage.Storage, key string) error {
+	object, err := source.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer object.Body.Close()
+	return destination.Put(ctx, key, object.Body, object.Size, object.ContentType)
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,9 +17,29 @@ import (
 
 // Config contient toute la configuration de l'application
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	App      ApplicationConfig
+	Database  DatabaseConfig
+	Server    ServerConfig
+	App       ApplicationConfig
+	RateLimit RateLimitConfig
+	CORS      CORSConfig
+	Cache     CacheConfig
+	Redis     RedisConfig
+	Jobs      JobsConfig
+	Scheduler SchedulerConfig
+	Storage   StorageConfig
+	Antivirus AntivirusConfig
+	KBSearch  KBSearchConfig
+	KBPublic  KBPublicConfig
+	Jira      JiraConfig
+	GLPI      GLPIConfig
+	Calendar  CalendarSyncConfig
+	Telegram  TelegramConfig
+	WhatsApp  WhatsAppConfig
+	Push      PushConfig
+	SMTP      SMTPConfig
+	Git       GitConfig
+	Payroll   PayrollExportConfig
+	Delays    DelayApprovalConfig
 
 	// Champs de compatibilité pour l'accès direct (deprecated, utiliser Database/Server/App)
 	DBHost                   string
@@ -65,6 +87,226 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// TrustedProxies proxies inverses (IP ou CIDR) dont l'en-tête X-Forwarded-For est pris en compte pour l'IP cliente
+	// (limitation de débit, audit) ; vide : aucun, l'IP de connexion est utilisée
+	TrustedProxies []string
+}
+
+// RateLimitConfig contient la configuration de la limitation de débit (token bucket)
+// Les limites sont exprimées en requêtes par minute, Burst étant la capacité maximale du seau
+type RateLimitConfig struct {
+	Enabled       bool
+	AuthPerMinute int // Limite par IP sur les routes d'authentification publiques
+	AuthBurst     int
+	UserPerMinute int // Limite par utilisateur authentifié sur le reste de l'API
+	UserBurst     int
+	ExemptUsers   []string // Comptes de service exemptés (usernames)
+}
+
+// CORSConfig contient la configuration CORS et des en-têtes de sécurité
+type CORSConfig struct {
+	AllowedOrigins   []string // Origines autorisées ("*" pour toutes, "https://*.domaine.ci" pour les sous-domaines)
+	AllowCredentials bool
+	MaxAge           time.Duration // Durée de mise en cache des réponses préflight
+	EnableHSTS       bool          // Strict-Transport-Security (à activer derrière HTTPS)
+}
+
+// CacheConfig contient la configuration du cache en mémoire des données de référence
+type CacheConfig struct {
+	Enabled bool
+	TTL     time.Duration // Durée de vie par défaut des entrées
+	// PermissionsTTL durée de vie des permissions par rôle en cache (invalidées aussi à chaque modification de rôle)
+	PermissionsTTL time.Duration
+}
+
+// RedisConfig contient les paramètres de connexion à Redis (file de tâches en arrière-plan)
+type RedisConfig struct {
+	Addr     string // Adresse host:port ; vide = Redis non utilisé
+	Password string
+	DB       int
+}
+
+// JobsConfig contient la configuration de la file de tâches en arrière-plan
+type JobsConfig struct {
+	Concurrency int // Nombre de tâches traitées en parallèle
+	MaxRetry    int // Nombre de réessais avant passage en file des échecs (dead-letter)
+}
+
+// SchedulerConfig contient la configuration du planificateur de tâches périodiques
+type SchedulerConfig struct {
+	Enabled bool // Exécution des tâches planifiées sur cette instance (une seule instance doit l'activer)
+}
+
+// StorageConfig contient la configuration du stockage des fichiers (pièces jointes, avatars)
+type StorageConfig struct {
+	Driver        string // local (disque, dossiers *_DIR) ou s3 (AWS S3, MinIO, ...)
+	S3Endpoint    string // Hôte de l'API S3 (ex: s3.eu-west-3.amazonaws.com, minio.local:9000)
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3UseSSL      bool
+	S3Prefix      string        // Préfixe des clés dans le bucket (ex: itsm)
+	SignedURLTTL  time.Duration // Durée de validité par défaut des URL signées
+	SigningSecret string        // Clé HMAC des URL signées du stockage local (JWT_SECRET par défaut)
+	// AttachmentExtensions extensions autorisées des pièces jointes (le contenu doit correspondre à l'extension)
+	AttachmentExtensions []string
+	// KnowledgeQuota quota par défaut des pièces jointes des articles d'une filiale, en octets (0 = illimité)
+	KnowledgeQuota int64
+}
+
+// AntivirusConfig contient la configuration de l'analyse antivirus des pièces jointes avant stockage
+type AntivirusConfig struct {
+	Driver        string        // none (pas d'analyse) ou clamav (démon clamd)
+	ClamAVAddress string        // Adresse de clamd : host:port (TCP) ou unix:/chemin/clamd.sock
+	Timeout       time.Duration // Durée maximale d'une analyse
+	FailOpen      bool          // Accepter le fichier si l'antivirus est indisponible (refusé par défaut)
+}
+
+// KBSearchConfig contient la configuration du moteur de recherche plein texte de la base de connaissances
+type KBSearchConfig struct {
+	Driver           string        // mysql (index FULLTEXT de la base) ou meilisearch
+	MeilisearchURL   string        // URL de Meilisearch (ex: http://localhost:7700)
+	MeilisearchKey   string        // Clé d'API Meilisearch (optionnelle en développement)
+	MeilisearchIndex string        // Index contenant les articles
+	Timeout          time.Duration // Durée maximale d'une requête au moteur
+}
+
+// KBPublicConfig contient la configuration du portail public en lecture seule de la base de connaissances (/public/v1/knowledge)
+type KBPublicConfig struct {
+	Mode      string // disabled, open (sans authentification, clé d'API facultative) ou api_key (clé d'API obligatoire)
+	PerMinute int    // Limite par adresse IP
+	Burst     int
+}
+
+// JiraConfig contient la configuration de la synchronisation des tickets avec Jira
+type JiraConfig struct {
+	BaseURL       string // URL du site Jira (ex: https://societe.atlassian.net) ; vide = intégration désactivée
+	Email         string // Compte de service (authentification basique avec jeton d'API)
+	APIToken      string
+	ProjectKey    string // Projet dans lequel les tickets sont créés (ex: DEV)
+	IssueType     string // Type des tickets Jira créés
+	AutoCreate    bool   // Création automatique pour les tickets de catégorie développement
+	WebhookSecret string // Secret des webhooks entrants (paramètre ?secret= ou signature X-Hub-Signature)
+}
+
+// Enabled indique si l'intégration Jira est configurée
+func (c JiraConfig) Enabled() bool {
+	return c.BaseURL != ""
+}
+
+// GLPIConfig contient l'accès à l'API REST de GLPI pour l'import des données
+type GLPIConfig struct {
+	URL       string // URL de l'API (ex: https://glpi.societe.ci/apirest.php) ; vide = import désactivé
+	AppToken  string // Jeton d'application (App-Token), optionnel selon la configuration du client API
+	UserToken string // Jeton d'API d'un compte ayant accès en lecture à toutes les entités
+}
+
+// CalendarSyncConfig contient les applications OAuth de synchronisation des agendas Google et Microsoft
+type CalendarSyncConfig struct {
+	GoogleClientID        string // Vide = synchronisation Google désactivée
+	GoogleClientSecret    string
+	MicrosoftClientID     string // Vide = synchronisation Microsoft désactivée
+	MicrosoftClientSecret string
+	MicrosoftTenant       string // Locataire Entra ID ("common" pour les comptes de toute organisation)
+	ReturnURL             string // Page du frontend affichée après la connexion d'un agenda (vide = réponse JSON)
+}
+
+// TelegramConfig contient la configuration du bot Telegram (notifications et actions rapides)
+type TelegramConfig struct {
+	BotToken      string // Jeton fourni par @BotFather ; vide = bot désactivé
+	BotUsername   string // Nom du bot sans @, pour les liens de liaison des comptes (t.me/<bot>?start=...)
+	WebhookSecret string // Secret attendu dans l'en-tête X-Telegram-Bot-Api-Secret-Token des mises à jour
+}
+
+// Enabled indique si le bot Telegram est configuré
+func (c TelegramConfig) Enabled() bool {
+	return c.BotToken != ""
+}
+
+// WhatsAppConfig contient la configuration de l'API WhatsApp Business (notifications critiques)
+type WhatsAppConfig struct {
+	PhoneNumberID string // Identifiant du numéro expéditeur (WhatsApp Business Platform)
+	AccessToken   string // Jeton d'accès permanent d'un utilisateur système ; vide = canal désactivé
+	APIVersion    string // Version de l'API Graph (ex: v21.0)
+}
+
+// Enabled indique si le canal WhatsApp est configuré
+func (c WhatsAppConfig) Enabled() bool {
+	return c.AccessToken != ""
+}
+
+// PushConfig contient la configuration des notifications push mobiles (Firebase Cloud Messaging et Apple Push Notification service)
+type PushConfig struct {
+	FCMCredentialsFile string // Fichier JSON du compte de service Firebase ; vide = FCM désactivé
+	FCMProjectID       string // Projet Firebase (par défaut celui du compte de service)
+	APNsKeyFile        string // Clé d'authentification APNs (.p8) ; vide = APNs désactivé
+	APNsKeyID          string // Identifiant de la clé APNs
+	APNsTeamID         string // Identifiant de l'équipe Apple Developer
+	APNsBundleID       string // Bundle ID de l'application iOS (sujet des notifications)
+	APNsSandbox        bool   // Environnement de développement APNs (applications signées en développement)
+}
+
+// FCMEnabled indique si l'envoi par Firebase Cloud Messaging est configuré
+func (c PushConfig) FCMEnabled() bool {
+	return c.FCMCredentialsFile != ""
+}
+
+// APNsEnabled indique si l'envoi par Apple Push Notification service est configuré
+func (c PushConfig) APNsEnabled() bool {
+	return c.APNsKeyFile != ""
+}
+
+// SMTPConfig contient la configuration du serveur SMTP des notifications par email
+type SMTPConfig struct {
+	Host     string // Serveur SMTP ; vide = canal email désactivé
+	Port     int
+	Username string // Identifiant d'authentification (vide = sans authentification)
+	Password string
+	From     string // Adresse expéditrice
+	FromName string // Nom affiché de l'expéditeur
+	TLS      string // starttls (défaut), tls (connexion chiffrée, port 465) ou none
+
+	FrontendURL string // URL du frontend, préfixée aux liens des emails (vide = liens omis)
+}
+
+// Enabled indique si le canal email est configuré
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// GitConfig contient la configuration des webhooks GitHub / GitLab liant commits et merge requests aux tickets
+type GitConfig struct {
+	WebhookSecret  string // Secret des webhooks (jeton X-Gitlab-Token ou clé de signature GitHub) ; vide = intégration désactivée
+	PendingOnMerge bool   // Passer le ticket en attente de validation quand une merge request qui le cite est fusionnée
+}
+
+// PayrollExportConfig contient la configuration de l'export des heures vers la paie
+type PayrollExportConfig struct {
+	Layout   string // Format à largeur fixe : champs "nom:largeur" séparés par des virgules, dans l'ordre de l'enregistrement
+	WageType string // Code de rubrique de paie (type de salaire SAP) écrit dans le champ wage_type
+	CRLF     bool   // Terminer les lignes à largeur fixe par CRLF (fichiers destinés à des systèmes Windows / SAP)
+}
+
+// Niveaux du circuit d'approbation des justifications de retard
+const (
+	DelayApprovalLevelManager        = "manager"         // Responsable hiérarchique (N+1) du technicien
+	DelayApprovalLevelDepartmentHead = "department_head" // Chef du département du technicien
+)
+
+// DelayApprovalConfig contient la configuration du circuit d'approbation des justifications de retard
+type DelayApprovalConfig struct {
+	ApprovalChain []string // Niveaux, dans l'ordre des étapes ; vide = validation unique (responsable ou validateur habilité)
+}
+
+// Enabled indique si l'intégration Git est configurée
+func (c GitConfig) Enabled() bool {
+	return c.WebhookSecret != ""
+}
+
+// Enabled indique si l'import GLPI est configuré
+func (c GLPIConfig) Enabled() bool {
+	return c.URL != ""
 }
 
 // ApplicationConfig contient la configuration générale de l'application
@@ -73,6 +315,7 @@ type ApplicationConfig struct {
 	Environment              string
 	URL                      string
 	LogLevel                 string
+	LogFormat                string // json (production) ou text (développement)
 	JWTSecret                string
 	JWTExpirationHours       int
 	JWTRefreshExpirationDays int
@@ -82,6 +325,14 @@ type ApplicationConfig struct {
 	AvatarMaxSize            int64
 	AvatarDir                string
 	TicketAttachmentsDir     string
+	InvitationURL            string        // Page du frontend d'activation de compte (le token est ajouté en paramètre)
+	InvitationTTL            time.Duration // Durée de validité des liens d'invitation
+	AssetLabelURL            string        // Page du frontend ouverte au scan d'une étiquette d'actif (l'étiquette est ajoutée au chemin) ; vide = le QR code encode l'étiquette seule
+	SatisfactionSurveyTTL    time.Duration // Durée de validité des liens d'enquête de satisfaction envoyés à la clôture des tickets
+	Timezone                 string        // Fuseau IANA par défaut des utilisateurs sans préférence ni filiale (vide = fuseau du serveur)
+	AuditArchiveDir          string        // Dossier des archives du journal d'audit (stockage local)
+	KnowledgeAttachmentsDir  string        // Dossier des images intégrées aux articles de la base de connaissances (stockage local)
+	AuditRetentionDays       int           // Durée de conservation des logs d'audit en base avant archivage (0 = illimitée)
 }
 
 // AppConfig est l'instance globale de configuration
@@ -90,6 +341,7 @@ var AppConfig *Config
 // loadEnvFile charge le fichier .env en gérant le BOM UTF-8
 // Le fichier .env doit être à la racine du projet (même niveau que .gitignore)
 func loadEnvFile() {
+	captureProcessEnv()
 	wd, _ := os.Getwd()
 
 	// Chercher le fichier .env en remontant depuis le répertoire courant
@@ -117,6 +369,7 @@ func loadEnvFile() {
 						if _, err := tmpFile.Write(content); err == nil {
 							tmpFile.Close()
 							if err := godotenv.Load(tmpFile.Name()); err == nil {
+								loadedEnvFile = envPath
 								log.Printf("✅ Fichier .env chargé depuis: %s", envPath)
 								return
 							}
@@ -142,6 +395,30 @@ func Load() (*Config, error) {
 	// Charger le fichier .env si présent
 	loadEnvFile()
 
+	config, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	// Créer les dossiers d'upload si nécessaire
+	createDirs(config)
+
+	// Log de la configuration de la base de données (sans le mot de passe)
+	log.Printf("📊 Configuration DB: Host=%s, Port=%s, User=%s, Database=%s",
+		config.Database.Host, config.Database.Port, config.Database.User, config.Database.Name)
+
+	current.Store(config)
+	return config, nil
+}
+
+// build construit et valide la configuration à partir des sources (environnement, fichiers, Vault)
+// Tous les problèmes détectés sont rapportés ensemble pour corriger la configuration en une fois
+func build() (*Config, error) {
+	resetProblems()
+	if err := loadVaultSecrets(); err != nil {
+		return nil, fmt.Errorf("chargement des secrets Vault: %w", err)
+	}
+
 	env := getEnv("APP_ENV", "development")
 
 	config := &Config{
@@ -160,16 +437,18 @@ func Load() (*Config, error) {
 			ConnMaxIdleTime: getEnvAsDuration("DB_CONN_MAX_IDLE_TIME", 10*time.Minute),
 		},
 		Server: ServerConfig{
-			Port:         getEnv("APP_PORT", "3001"),
-			ReadTimeout:  getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
-			WriteTimeout: getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
-			IdleTimeout:  getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			Port:           getEnv("APP_PORT", "3001"),
+			ReadTimeout:    getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:   getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			IdleTimeout:    getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			TrustedProxies: getEnvSlice("TRUSTED_PROXIES", nil),
 		},
 		App: ApplicationConfig{
 			Name:                     getEnv("APP_NAME", "ITSM Backend"),
 			Environment:              env,
 			URL:                      getEnv("APP_URL", "http://localhost:3001"),
 			LogLevel:                 getEnv("LOG_LEVEL", getDefaultLogLevel(env)),
+			LogFormat:                getEnv("LOG_FORMAT", getDefaultLogFormat(env)),
 			JWTSecret:                getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-in-production"),
 			JWTExpirationHours:       getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
 			JWTRefreshExpirationDays: getEnvAsInt("JWT_REFRESH_EXPIRATION_DAYS", 7),
@@ -179,6 +458,142 @@ func Load() (*Config, error) {
 			AvatarMaxSize:            getEnvAsInt64("AVATAR_MAX_SIZE", 2097152), // 2 MB
 			AvatarDir:                getEnv("AVATAR_DIR", "./uploads/users"),
 			TicketAttachmentsDir:     getEnv("TICKET_ATTACHMENTS_DIR", "./uploads/tickets"),
+			InvitationURL:            getEnv("INVITATION_URL", "http://localhost:3000/invitation"),
+			InvitationTTL:            getEnvAsDuration("INVITATION_TTL", 7*24*time.Hour),
+			AssetLabelURL:            getEnv("ASSET_LABEL_URL", ""),
+			SatisfactionSurveyTTL:    getEnvAsDuration("SATISFACTION_SURVEY_TTL", 30*24*time.Hour),
+			Timezone:                 getEnv("APP_TIMEZONE", ""),
+			AuditArchiveDir:          getEnv("AUDIT_ARCHIVE_DIR", "./archives/audit"),
+			KnowledgeAttachmentsDir:  getEnv("KNOWLEDGE_ATTACHMENTS_DIR", "./uploads/knowledge"),
+			AuditRetentionDays:       getEnvAsInt("AUDIT_RETENTION_DAYS", 365),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:       getEnvBool("RATE_LIMIT_ENABLED", true),
+			AuthPerMinute: getEnvAsInt("RATE_LIMIT_AUTH_PER_MINUTE", 10),
+			AuthBurst:     getEnvAsInt("RATE_LIMIT_AUTH_BURST", 5),
+			UserPerMinute: getEnvAsInt("RATE_LIMIT_USER_PER_MINUTE", 300),
+			UserBurst:     getEnvAsInt("RATE_LIMIT_USER_BURST", 60),
+			ExemptUsers:   getEnvSlice("RATE_LIMIT_EXEMPT_USERS", []string{}),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnvSlice("CORS_ALLOWED_ORIGINS", getDefaultCORSOrigins(env)),
+			AllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", true),
+			MaxAge:           getEnvAsDuration("CORS_MAX_AGE", 12*time.Hour),
+			EnableHSTS:       getEnvBool("SECURITY_HSTS_ENABLED", env == "production" || env == "prod"),
+		},
+		Cache: CacheConfig{
+			Enabled:        getEnvBool("CACHE_ENABLED", true),
+			TTL:            getEnvAsDuration("CACHE_TTL", 5*time.Minute),
+			PermissionsTTL: getEnvAsDuration("PERMISSIONS_CACHE_TTL", time.Minute),
+		},
+		Redis: RedisConfig{
+			Addr:     getEnv("REDIS_ADDR", ""),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       getEnvAsInt("REDIS_DB", 0),
+		},
+		Jobs: JobsConfig{
+			Concurrency: getEnvAsInt("JOBS_CONCURRENCY", 10),
+			MaxRetry:    getEnvAsInt("JOBS_MAX_RETRY", 5),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled: getEnvBool("SCHEDULER_ENABLED", true),
+		},
+		Storage: StorageConfig{
+			Driver:       getEnv("STORAGE_DRIVER", "local"),
+			S3Endpoint:   getEnv("S3_ENDPOINT", "s3.amazonaws.com"),
+			S3Region:     getEnv("S3_REGION", ""),
+			S3Bucket:     getEnv("S3_BUCKET", ""),
+			S3AccessKey:  getEnv("S3_ACCESS_KEY_ID", ""),
+			S3SecretKey:  getEnv("S3_SECRET_ACCESS_KEY", ""),
+			S3UseSSL:     getEnvBool("S3_USE_SSL", true),
+			S3Prefix:     getEnv("S3_PREFIX", ""),
+			SignedURLTTL: getEnvAsDuration("STORAGE_SIGNED_URL_TTL", 15*time.Minute),
+			AttachmentExtensions: getEnvSlice("ATTACHMENT_ALLOWED_EXTENSIONS", []string{
+				".jpg", ".jpeg", ".png", ".gif", ".webp", ".pdf", ".doc", ".docx", ".xls", ".xlsx", ".txt", ".zip",
+			}),
+			KnowledgeQuota: getEnvAsInt64("KB_STORAGE_QUOTA", 1073741824), // 1 GB
+		},
+		Antivirus: AntivirusConfig{
+			Driver:        getEnv("ANTIVIRUS_DRIVER", "none"),
+			ClamAVAddress: getEnv("CLAMAV_ADDRESS", "localhost:3310"),
+			Timeout:       getEnvAsDuration("ANTIVIRUS_TIMEOUT", 30*time.Second),
+			FailOpen:      getEnvBool("ANTIVIRUS_FAIL_OPEN", false),
+		},
+		KBSearch: KBSearchConfig{
+			Driver:           getEnv("KB_SEARCH_DRIVER", "mysql"),
+			MeilisearchURL:   strings.TrimRight(getEnv("MEILISEARCH_URL", ""), "/"),
+			MeilisearchKey:   getEnv("MEILISEARCH_API_KEY", ""),
+			MeilisearchIndex: getEnv("MEILISEARCH_INDEX", "knowledge_articles"),
+			Timeout:          getEnvAsDuration("KB_SEARCH_TIMEOUT", 5*time.Second),
+		},
+		KBPublic: KBPublicConfig{
+			Mode:      getEnv("KB_PUBLIC_MODE", "disabled"),
+			PerMinute: getEnvAsInt("KB_PUBLIC_RATE_LIMIT_PER_MINUTE", 60),
+			Burst:     getEnvAsInt("KB_PUBLIC_RATE_LIMIT_BURST", 20),
+		},
+		Jira: JiraConfig{
+			BaseURL:       strings.TrimRight(getEnv("JIRA_BASE_URL", ""), "/"),
+			Email:         getEnv("JIRA_EMAIL", ""),
+			APIToken:      getEnv("JIRA_API_TOKEN", ""),
+			ProjectKey:    getEnv("JIRA_PROJECT_KEY", ""),
+			IssueType:     getEnv("JIRA_ISSUE_TYPE", "Task"),
+			AutoCreate:    getEnvBool("JIRA_AUTO_CREATE", true),
+			WebhookSecret: getEnv("JIRA_WEBHOOK_SECRET", ""),
+		},
+		GLPI: GLPIConfig{
+			URL:       strings.TrimRight(getEnv("GLPI_URL", ""), "/"),
+			AppToken:  getEnv("GLPI_APP_TOKEN", ""),
+			UserToken: getEnv("GLPI_USER_TOKEN", ""),
+		},
+		Calendar: CalendarSyncConfig{
+			GoogleClientID:        getEnv("GOOGLE_CALENDAR_CLIENT_ID", ""),
+			GoogleClientSecret:    getEnv("GOOGLE_CALENDAR_CLIENT_SECRET", ""),
+			MicrosoftClientID:     getEnv("MICROSOFT_CALENDAR_CLIENT_ID", ""),
+			MicrosoftClientSecret: getEnv("MICROSOFT_CALENDAR_CLIENT_SECRET", ""),
+			MicrosoftTenant:       getEnv("MICROSOFT_CALENDAR_TENANT", "common"),
+			ReturnURL:             getEnv("CALENDAR_SYNC_RETURN_URL", ""),
+		},
+		Telegram: TelegramConfig{
+			BotToken:      getEnv("TELEGRAM_BOT_TOKEN", ""),
+			BotUsername:   strings.TrimPrefix(getEnv("TELEGRAM_BOT_USERNAME", ""), "@"),
+			WebhookSecret: getEnv("TELEGRAM_WEBHOOK_SECRET", ""),
+		},
+		WhatsApp: WhatsAppConfig{
+			PhoneNumberID: getEnv("WHATSAPP_PHONE_NUMBER_ID", ""),
+			AccessToken:   getEnv("WHATSAPP_ACCESS_TOKEN", ""),
+			APIVersion:    getEnv("WHATSAPP_API_VERSION", "v21.0"),
+		},
+		Push: PushConfig{
+			FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+			FCMProjectID:       getEnv("FCM_PROJECT_ID", ""),
+			APNsKeyFile:        getEnv("APNS_KEY_FILE", ""),
+			APNsKeyID:          getEnv("APNS_KEY_ID", ""),
+			APNsTeamID:         getEnv("APNS_TEAM_ID", ""),
+			APNsBundleID:       getEnv("APNS_BUNDLE_ID", ""),
+			APNsSandbox:        getEnvBool("APNS_SANDBOX", false),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvAsInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", ""),
+			FromName: getEnv("SMTP_FROM_NAME", "ITSM"),
+			TLS:      strings.ToLower(getEnv("SMTP_TLS", "starttls")),
+
+			FrontendURL: strings.TrimRight(getEnv("FRONTEND_URL", ""), "/"),
+		},
+		Git: GitConfig{
+			WebhookSecret:  getEnv("GIT_WEBHOOK_SECRET", ""),
+			PendingOnMerge: getEnvBool("GIT_PENDING_ON_MERGE", false),
+		},
+		Payroll: PayrollExportConfig{
+			Layout:   getEnv("PAYROLL_EXPORT_LAYOUT", "personnel_number:8,period_end:8,wage_type:4,cost_center_type:1,cost_center:10,hours:7"),
+			WageType: getEnv("PAYROLL_EXPORT_WAGE_TYPE", "1000"),
+			CRLF:     getEnvBool("PAYROLL_EXPORT_CRLF", true),
+		},
+		Delays: DelayApprovalConfig{
+			ApprovalChain: delayApprovalChain(getEnvSlice("DELAY_JUSTIFICATION_APPROVAL_CHAIN", []string{DelayApprovalLevelManager, DelayApprovalLevelDepartmentHead})),
 		},
 	}
 
@@ -204,18 +619,12 @@ func Load() (*Config, error) {
 	config.AvatarMaxSize = config.App.AvatarMaxSize
 	config.AvatarDir = config.App.AvatarDir
 	config.TicketAttachmentsDir = config.App.TicketAttachmentsDir
+	config.Storage.SigningSecret = getEnv("STORAGE_SIGNING_SECRET", config.App.JWTSecret)
 
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("configuration invalide: %w", err)
+	problems := append(drainProblems(), config.validationProblems()...)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("configuration invalide: %w", &ValidationError{Problems: problems})
 	}
-
-	// Créer les dossiers d'upload si nécessaire
-	createDirs(config)
-
-	// Log de la configuration de la base de données (sans le mot de passe)
-	log.Printf("📊 Configuration DB: Host=%s, Port=%s, User=%s, Database=%s",
-		config.Database.Host, config.Database.Port, config.Database.User, config.Database.Name)
-
 	return config, nil
 }
 
@@ -228,29 +637,186 @@ func LoadConfig() {
 	AppConfig = cfg
 }
 
+// ValidationError regroupe les problèmes de configuration détectés au démarrage
+type ValidationError struct {
+	Problems []string
+}
+
+// Error retourne le rapport complet, un problème par ligne
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d problème(s) détecté(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// requiredInProduction variables devant être explicitement définies en production (pas de valeur par défaut)
+var requiredInProduction = []string{"DB_HOST", "DB_USER", "DB_PASSWORD", "DB_NAME", "JWT_SECRET", "APP_URL", "CORS_ALLOWED_ORIGINS"}
+
 // Validate vérifie que la configuration est valide
 func (c *Config) Validate() error {
+	if problems := c.validationProblems(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validationProblems retourne la liste des problèmes de configuration
+func (c *Config) validationProblems() []string {
+	var problems []string
 	if c.Database.Host == "" {
-		return fmt.Errorf("DB_HOST ne peut pas être vide")
+		problems = append(problems, "DB_HOST ne peut pas être vide")
 	}
-	if c.Database.Port == "" {
-		return fmt.Errorf("DB_PORT ne peut pas être vide")
+	if !isValidPort(c.Database.Port) {
+		problems = append(problems, fmt.Sprintf("DB_PORT invalide: %q (port attendu entre 1 et 65535)", c.Database.Port))
 	}
 	if c.Database.User == "" {
-		return fmt.Errorf("DB_USER ne peut pas être vide")
+		problems = append(problems, "DB_USER ne peut pas être vide")
 	}
 	if c.Database.Name == "" {
-		return fmt.Errorf("DB_NAME ne peut pas être vide")
+		problems = append(problems, "DB_NAME ne peut pas être vide")
+	}
+	if c.Database.MaxOpenConns <= 0 {
+		problems = append(problems, "DB_MAX_OPEN_CONNS doit être strictement positif")
+	} else if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "DB_MAX_IDLE_CONNS ne peut pas dépasser DB_MAX_OPEN_CONNS")
+	}
+	if !isValidPort(c.Server.Port) {
+		problems = append(problems, fmt.Sprintf("APP_PORT invalide: %q (port attendu entre 1 et 65535)", c.Server.Port))
 	}
-	if c.Server.Port == "" {
-		return fmt.Errorf("APP_PORT ne peut pas être vide")
+	// "*" renvoie l'origine de toute requête : avec les credentials, n'importe quel site pourrait appeler l'API
+	// au nom de l'utilisateur connecté (toléré en développement uniquement)
+	if !c.IsDevelopment() && c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*") {
+		problems = append(problems, "CORS_ALLOWED_ORIGINS ne peut pas contenir \"*\" avec CORS_ALLOW_CREDENTIALS=true hors développement")
 	}
-	if c.App.JWTSecret == "" || c.App.JWTSecret == "your-super-secret-jwt-key-change-in-production" {
-		if c.IsProduction() {
-			return fmt.Errorf("JWT_SECRET doit être défini en production")
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES invalide: %q (IP ou CIDR attendu)", proxy))
+			}
 		}
 	}
-	return nil
+	switch strings.ToLower(c.App.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL invalide: %q (debug, info, warn, error)", c.App.LogLevel))
+	}
+	if c.App.LogFormat != "json" && c.App.LogFormat != "text" {
+		problems = append(problems, fmt.Sprintf("LOG_FORMAT invalide: %q (json, text)", c.App.LogFormat))
+	}
+	if c.App.Timezone != "" {
+		if _, err := time.LoadLocation(c.App.Timezone); err != nil {
+			problems = append(problems, fmt.Sprintf("APP_TIMEZONE invalide: %q (fuseau IANA attendu, ex: Africa/Abidjan)", c.App.Timezone))
+		}
+	}
+	if c.App.AuditRetentionDays < 0 {
+		problems = append(problems, "AUDIT_RETENTION_DAYS doit être positif (0 = conservation illimitée)")
+	}
+	if c.RateLimit.Enabled && (c.RateLimit.AuthPerMinute <= 0 || c.RateLimit.UserPerMinute <= 0) {
+		problems = append(problems, "RATE_LIMIT_AUTH_PER_MINUTE et RATE_LIMIT_USER_PER_MINUTE doivent être strictement positifs")
+	}
+	if c.Jobs.Concurrency <= 0 {
+		problems = append(problems, "JOBS_CONCURRENCY doit être strictement positif")
+	}
+	switch c.Storage.Driver {
+	case "local":
+	case "s3":
+		if c.Storage.S3Bucket == "" || c.Storage.S3AccessKey == "" || c.Storage.S3SecretKey == "" {
+			problems = append(problems, "S3_BUCKET, S3_ACCESS_KEY_ID et S3_SECRET_ACCESS_KEY sont requis avec STORAGE_DRIVER=s3")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("STORAGE_DRIVER invalide: %q (local, s3)", c.Storage.Driver))
+	}
+	switch c.Antivirus.Driver {
+	case "none":
+	case "clamav":
+		if c.Antivirus.ClamAVAddress == "" {
+			problems = append(problems, "CLAMAV_ADDRESS est requis avec ANTIVIRUS_DRIVER=clamav")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("ANTIVIRUS_DRIVER invalide: %q (none, clamav)", c.Antivirus.Driver))
+	}
+	switch c.KBSearch.Driver {
+	case "mysql":
+	case "meilisearch":
+		if c.KBSearch.MeilisearchURL == "" || c.KBSearch.MeilisearchIndex == "" {
+			problems = append(problems, "MEILISEARCH_URL et MEILISEARCH_INDEX sont requis avec KB_SEARCH_DRIVER=meilisearch")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("KB_SEARCH_DRIVER invalide: %q (mysql, meilisearch)", c.KBSearch.Driver))
+	}
+	switch c.KBPublic.Mode {
+	case "disabled", "open", "api_key":
+	default:
+		problems = append(problems, fmt.Sprintf("KB_PUBLIC_MODE invalide: %q (disabled, open, api_key)", c.KBPublic.Mode))
+	}
+	if c.KBPublic.Mode != "disabled" && c.KBPublic.PerMinute <= 0 {
+		problems = append(problems, "KB_PUBLIC_RATE_LIMIT_PER_MINUTE doit être strictement positif")
+	}
+	if c.Jobs.MaxRetry < 0 {
+		problems = append(problems, "JOBS_MAX_RETRY ne peut pas être négatif")
+	}
+	if c.Jira.Enabled() && (c.Jira.Email == "" || c.Jira.APIToken == "" || c.Jira.ProjectKey == "" || c.Jira.WebhookSecret == "") {
+		problems = append(problems, "JIRA_EMAIL, JIRA_API_TOKEN, JIRA_PROJECT_KEY et JIRA_WEBHOOK_SECRET sont requis avec JIRA_BASE_URL")
+	}
+	if c.GLPI.Enabled() && c.GLPI.UserToken == "" {
+		problems = append(problems, "GLPI_USER_TOKEN est requis avec GLPI_URL")
+	}
+	if c.Calendar.GoogleClientID != "" && c.Calendar.GoogleClientSecret == "" {
+		problems = append(problems, "GOOGLE_CALENDAR_CLIENT_SECRET est requis avec GOOGLE_CALENDAR_CLIENT_ID")
+	}
+	if c.Calendar.MicrosoftClientID != "" && c.Calendar.MicrosoftClientSecret == "" {
+		problems = append(problems, "MICROSOFT_CALENDAR_CLIENT_SECRET est requis avec MICROSOFT_CALENDAR_CLIENT_ID")
+	}
+	if c.Telegram.Enabled() && (c.Telegram.BotUsername == "" || c.Telegram.WebhookSecret == "") {
+		problems = append(problems, "TELEGRAM_BOT_USERNAME et TELEGRAM_WEBHOOK_SECRET sont requis avec TELEGRAM_BOT_TOKEN")
+	}
+	if c.WhatsApp.Enabled() && c.WhatsApp.PhoneNumberID == "" {
+		problems = append(problems, "WHATSAPP_PHONE_NUMBER_ID est requis avec WHATSAPP_ACCESS_TOKEN")
+	}
+	if c.Push.APNsEnabled() && (c.Push.APNsKeyID == "" || c.Push.APNsTeamID == "" || c.Push.APNsBundleID == "") {
+		problems = append(problems, "APNS_KEY_ID, APNS_TEAM_ID et APNS_BUNDLE_ID sont requis avec APNS_KEY_FILE")
+	}
+	if c.SMTP.Enabled() && c.SMTP.From == "" {
+		problems = append(problems, "SMTP_FROM est requis avec SMTP_HOST")
+	}
+	for _, field := range strings.Split(c.Payroll.Layout, ",") {
+		name, width, found := strings.Cut(strings.TrimSpace(field), ":")
+		if n, err := strconv.Atoi(width); !found || name == "" || err != nil || n <= 0 {
+			problems = append(problems, fmt.Sprintf("PAYROLL_EXPORT_LAYOUT invalide: %q (champs nom:largeur séparés par des virgules)", field))
+			break
+		}
+	}
+	for _, level := range c.Delays.ApprovalChain {
+		if level != DelayApprovalLevelManager && level != DelayApprovalLevelDepartmentHead {
+			problems = append(problems, fmt.Sprintf("DELAY_JUSTIFICATION_APPROVAL_CHAIN invalide: niveau %q (manager, department_head ou none)", level))
+			break
+		}
+	}
+	switch c.SMTP.TLS {
+	case "starttls", "tls", "none":
+	default:
+		problems = append(problems, "SMTP_TLS doit valoir starttls, tls ou none")
+	}
+
+	if c.IsProduction() {
+		for _, key := range requiredInProduction {
+			if lookupEnv(key) == "" {
+				problems = append(problems, key+" doit être défini en production")
+			}
+		}
+		// JWT_SECRET absent est déjà signalé ci-dessus
+		switch secret := lookupEnv("JWT_SECRET"); {
+		case secret == "your-super-secret-jwt-key-change-in-production":
+			problems = append(problems, "JWT_SECRET doit être modifié en production")
+		case secret != "" && len(secret) < 32:
+			problems = append(problems, "JWT_SECRET doit contenir au moins 32 caractères en production")
+		}
+	}
+	return problems
+}
+
+// isValidPort indique si la valeur est un numéro de port TCP valide
+func isValidPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port > 0 && port <= 65535
 }
 
 // IsDevelopment retourne true si l'application est en mode développement
@@ -265,7 +831,7 @@ func (c *Config) IsProduction() bool {
 
 // getEnv récupère une variable d'environnement ou retourne la valeur par défaut
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		return value
 	}
 	return defaultValue
@@ -273,12 +839,13 @@ func getEnv(key, defaultValue string) string {
 
 // getEnvAsInt récupère une variable d'environnement comme entier
 func getEnvAsInt(key string, defaultValue int) int {
-	value := os.Getenv(key)
+	value := lookupEnv(key)
 	if value == "" {
 		return defaultValue
 	}
 	intValue, err := strconv.Atoi(value)
 	if err != nil {
+		recordProblem(fmt.Sprintf("%s: entier attendu, reçu %q", key, value))
 		return defaultValue
 	}
 	return intValue
@@ -286,12 +853,13 @@ func getEnvAsInt(key string, defaultValue int) int {
 
 // getEnvAsInt64 récupère une variable d'environnement comme int64
 func getEnvAsInt64(key string, defaultValue int64) int64 {
-	value := os.Getenv(key)
+	value := lookupEnv(key)
 	if value == "" {
 		return defaultValue
 	}
 	intValue, err := strconv.ParseInt(value, 10, 64)
 	if err != nil {
+		recordProblem(fmt.Sprintf("%s: entier attendu, reçu %q", key, value))
 		return defaultValue
 	}
 	return intValue
@@ -299,12 +867,13 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 
 // getEnvAsDuration récupère une variable d'environnement comme durée
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	value := os.Getenv(key)
+	value := lookupEnv(key)
 	if value == "" {
 		return defaultValue
 	}
 	duration, err := time.ParseDuration(value)
 	if err != nil {
+		recordProblem(fmt.Sprintf("%s: durée attendue (ex: 30s, 5m), reçu %q", key, value))
 		return defaultValue
 	}
 	return duration
@@ -312,17 +881,19 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 
 // getEnvBool récupère une variable d'environnement comme booléen
 func getEnvBool(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
+	if value := lookupEnv(key); value != "" {
+		boolValue, err := strconv.ParseBool(value)
+		if err == nil {
 			return boolValue
 		}
+		recordProblem(fmt.Sprintf("%s: booléen attendu (true/false), reçu %q", key, value))
 	}
 	return defaultValue
 }
 
 // getEnvSlice récupère une variable d'environnement comme slice de strings (séparée par des virgules)
 func getEnvSlice(key string, defaultValue []string) []string {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		// Split par virgule et nettoyer les espaces
 		parts := strings.Split(value, ",")
 		values := []string{}
@@ -339,6 +910,14 @@ func getEnvSlice(key string, defaultValue []string) []string {
 	return defaultValue
 }
 
+// delayApprovalChain retourne les niveaux du circuit d'approbation des justifications de retard ("none" = aucun circuit)
+func delayApprovalChain(levels []string) []string {
+	if len(levels) == 1 && levels[0] == "none" {
+		return nil
+	}
+	return levels
+}
+
 // getDefaultLogLevel retourne le niveau de log par défaut selon l'environnement
 func getDefaultLogLevel(env string) string {
 	switch env {
@@ -353,6 +932,27 @@ func getDefaultLogLevel(env string) string {
 	}
 }
 
+// getDefaultLogFormat retourne le format de log par défaut selon l'environnement
+func getDefaultLogFormat(env string) string {
+	switch env {
+	case "production", "prod", "staging":
+		return "json"
+	default:
+		return "text"
+	}
+}
+
+// getDefaultCORSOrigins retourne les origines autorisées par défaut selon l'environnement
+// En production et staging, les origines doivent être définies explicitement via CORS_ALLOWED_ORIGINS
+func getDefaultCORSOrigins(env string) []string {
+	switch env {
+	case "production", "prod", "staging":
+		return []string{}
+	default:
+		return []string{"*"}
+	}
+}
+
 // createDirs crée les dossiers nécessaires pour les uploads
 func createDirs(cfg *Config) {
 	dirs := []string{
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/joho/godotenv"
+)
+
+// Rechargement à chaud : seuls les paramètres non critiques sont pris en compte sans redémarrage
+// (LOG_LEVEL, RATE_LIMIT_*, CORS_ALLOWED_ORIGINS / CORS_ALLOW_CREDENTIALS / CORS_MAX_AGE).
+// Base de données, serveur, JWT, Redis, etc. nécessitent un redémarrage.
+
+var (
+	// current configuration active (mise à jour par Reload) ; AppConfig reste la configuration de démarrage
+	current atomic.Pointer[Config]
+
+	reloadMu    sync.Mutex
+	reloadHooks []func(cfg *Config)
+
+	// processEnvKeys variables définies par l'environnement du processus (prioritaires sur le .env)
+	processEnvOnce sync.Once
+	processEnvKeys map[string]bool
+	loadedEnvFile  string
+
+	problemsMu sync.Mutex
+	problems   []string
+)
+
+// Current retourne la configuration active, à utiliser pour lire les paramètres rechargeables
+func Current() *Config {
+	if cfg := current.Load(); cfg != nil {
+		return cfg
+	}
+	return AppConfig
+}
+
+// OnReload enregistre une fonction appelée après chaque rechargement réussi
+func OnReload(hook func(cfg *Config)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloadHooks = append(reloadHooks, hook)
+}
+
+// Reload relit le .env, les fichiers de secrets et Vault, valide la configuration
+// et applique les paramètres non critiques ; retourne les paramètres modifiés
+func Reload() ([]string, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	prev := Current()
+	if prev == nil {
+		return nil, fmt.Errorf("configuration non chargée")
+	}
+	if err := reloadEnvFile(); err != nil {
+		return nil, err
+	}
+	next, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *prev
+	var changed []string
+	if updated.App.LogLevel != next.App.LogLevel {
+		updated.App.LogLevel = next.App.LogLevel
+		changed = append(changed, "LOG_LEVEL")
+	}
+	if !reflect.DeepEqual(updated.RateLimit, next.RateLimit) {
+		updated.RateLimit = next.RateLimit
+		changed = append(changed, "RATE_LIMIT")
+	}
+	nextCORS := next.CORS
+	nextCORS.EnableHSTS = updated.CORS.EnableHSTS // en-tête positionné au démarrage
+	if !reflect.DeepEqual(updated.CORS, nextCORS) {
+		updated.CORS = nextCORS
+		changed = append(changed, "CORS")
+	}
+
+	current.Store(&updated)
+	for _, hook := range reloadHooks {
+		hook(&updated)
+	}
+	return changed, nil
+}
+
+// WatchSIGHUP recharge la configuration à la réception du signal SIGHUP (kill -HUP <pid>)
+func WatchSIGHUP() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			changed, err := Reload()
+			if err != nil {
+				log.Printf("❌ Rechargement de la configuration refusé: %v", err)
+				continue
+			}
+			if len(changed) == 0 {
+				log.Println("🔄 Configuration rechargée (aucun changement)")
+				continue
+			}
+			log.Printf("🔄 Configuration rechargée (modifié: %s)", strings.Join(changed, ", "))
+		}
+	}()
+}
+
+// captureProcessEnv mémorise les variables définies avant le chargement du .env
+func captureProcessEnv() {
+	processEnvOnce.Do(func() {
+		processEnvKeys = make(map[string]bool)
+		for _, entry := range os.Environ() {
+			if key, _, ok := strings.Cut(entry, "="); ok {
+				processEnvKeys[key] = true
+			}
+		}
+	})
+}
+
+// reloadEnvFile relit le .env chargé au démarrage ; les variables de l'environnement du processus restent prioritaires
+func reloadEnvFile() error {
+	if loadedEnvFile == "" {
+		return nil
+	}
+	content, err := os.ReadFile(loadedEnvFile)
+	if err != nil {
+		return fmt.Errorf("lecture de %s: %w", loadedEnvFile, err)
+	}
+	values, err := godotenv.Parse(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	if err != nil {
+		return fmt.Errorf("analyse de %s: %w", loadedEnvFile, err)
+	}
+	for key, value := range values {
+		if !processEnvKeys[key] {
+			_ = os.Setenv(key, value)
+		}
+	}
+	return nil
+}
+
+// recordProblem enregistre un problème détecté lors de la lecture des variables
+func recordProblem(problem string) {
+	problemsMu.Lock()
+	defer problemsMu.Unlock()
+	problems = append(problems, problem)
+}
+
+// resetProblems vide la liste des problèmes avant une nouvelle lecture
+func resetProblems() {
+	problemsMu.Lock()
+	defer problemsMu.Unlock()
+	problems = nil
+}
+
+// drainProblems retourne et vide la liste des problèmes
+func drainProblems() []string {
+	problemsMu.Lock()
+	defer problemsMu.Unlock()
+	result := problems
+	problems = nil
+	return result
+}
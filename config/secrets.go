@@ -0,0 +1,107 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sources des valeurs de configuration, par ordre de priorité :
+//  1. variable d'environnement (ou .env)
+//  2. fichier désigné par <VARIABLE>_FILE (secrets Docker / Kubernetes)
+//  3. secret Vault (KV v1 ou v2) si VAULT_ADDR est défini
+//  4. valeur par défaut
+
+var (
+	vaultMu      sync.RWMutex
+	vaultSecrets map[string]string
+)
+
+// lookupEnv retourne la valeur d'une variable en consultant successivement l'environnement,
+// le fichier <VARIABLE>_FILE et les secrets Vault ; chaîne vide si la variable n'est définie nulle part
+func lookupEnv(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			recordProblem(fmt.Sprintf("%s_FILE: lecture de %s impossible: %v", key, path, err))
+			return ""
+		}
+		return strings.TrimSpace(string(content))
+	}
+	vaultMu.RLock()
+	defer vaultMu.RUnlock()
+	return vaultSecrets[key]
+}
+
+// loadVaultSecrets charge les secrets depuis Vault lorsque VAULT_ADDR est défini
+// Variables : VAULT_ADDR, VAULT_TOKEN (ou VAULT_TOKEN_FILE), VAULT_SECRET_PATH (ex: secret/data/itsm), VAULT_NAMESPACE (optionnel)
+// Les clés du secret doivent porter le nom des variables d'environnement (DB_PASSWORD, JWT_SECRET, ...)
+func loadVaultSecrets() error {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil
+	}
+	token := lookupEnv("VAULT_TOKEN")
+	secretPath := os.Getenv("VAULT_SECRET_PATH")
+	if token == "" || secretPath == "" {
+		return fmt.Errorf("VAULT_TOKEN et VAULT_SECRET_PATH sont requis lorsque VAULT_ADDR est défini")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(secretPath, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("requête Vault invalide: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Vault injoignable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lecture du secret Vault %s: statut HTTP %d", secretPath, resp.StatusCode)
+	}
+
+	// KV v2 : {"data": {"data": {...}}} ; KV v1 : {"data": {...}}
+	var payload struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("réponse Vault invalide: %w", err)
+	}
+	data := payload.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		data = nested
+	}
+
+	secrets := make(map[string]string, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			secrets[key] = v
+		case nil:
+		default:
+			secrets[key] = fmt.Sprint(v)
+		}
+	}
+
+	vaultMu.Lock()
+	vaultSecrets = secrets
+	vaultMu.Unlock()
+	return nil
+}
@@ -86,7 +86,140 @@ func AutoMigrate() error {
 	log.Println("📋 Étape 1: Création de toutes les tables (sans contraintes FK)...")
 
 	// Toutes les tables dans l'ordre logique
-	err = DB.AutoMigrate(
+	err = DB.AutoMigrate(migrationModels()...)
+
+	if err != nil {
+		return fmt.Errorf("échec de la création des tables: %w", err)
+	}
+	log.Println("✅ Toutes les tables créées")
+
+	// Étape 2: Supprimer toutes les contraintes incorrectes créées par GORM
+	log.Println("🔧 Étape 2: Nettoyage des contraintes incorrectes...")
+	if err := removeAllIncorrectForeignKeys(); err != nil {
+		log.Printf("⚠️  Erreur lors du nettoyage: %v", err)
+	}
+
+	// Étape 3: Ajouter toutes les contraintes correctes manuellement
+	log.Println("🔧 Étape 3: Ajout des contraintes de clés étrangères...")
+	if err := addAllForeignKeys(); err != nil {
+		log.Printf("⚠️  Erreur lors de l'ajout des contraintes: %v", err)
+		// Ne pas bloquer, continuer
+	}
+
+	// Étape 4: Seeding des données par défaut
+	log.Println("🌱 Étape 4: Seeding des données par défaut...")
+	if err := seedDefaultPermissions(); err != nil {
+		log.Printf("⚠️  Erreur lors du seeding des permissions: %v", err)
+	}
+	if err := seedDefaultUserRole(); err != nil {
+		log.Printf("⚠️  Erreur lors du seeding du rôle USER: %v", err)
+	}
+	if err := seedFieldMaskPermissions(); err != nil {
+		log.Printf("⚠️  Erreur lors du seeding des permissions de champs sensibles: %v", err)
+	}
+	if err := seedUserRoleProjectPermissions(); err != nil {
+		log.Printf("⚠️  Erreur lors de l'attribution des permissions projets au rôle USER: %v", err)
+	}
+	if err := seedDefaultAdmin(); err != nil {
+		log.Printf("⚠️  Erreur lors du seeding de l'admin: %v", err)
+	}
+	if err := seedDefaultTicketCategories(); err != nil {
+		log.Printf("⚠️  Erreur lors du seeding des catégories: %v", err)
+	}
+
+	// Générer les codes pour les tickets existants
+	if err := generateTicketCodes(); err != nil {
+		log.Printf("⚠️  Erreur lors de la génération des codes: %v", err)
+	}
+
+	// Migrer les requester_id
+	if err := migrateRequesterIDs(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration des requester_id: %v", err)
+	}
+
+	// Modifier asset_software.asset_id pour le rendre nullable
+	if err := makeAssetSoftwareAssetIDNullable(); err != nil {
+		log.Printf("⚠️  Erreur lors de la modification de asset_software.asset_id: %v", err)
+	}
+
+	// project_functions.type et project_member_functions (rétrocompat)
+	if err := migrateProjectFunctionTypesAndMemberFunctions(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration project_functions / project_member_functions: %v", err)
+	}
+
+	// Préremplir Chef de projet et Lead pour les projets existants
+	if err := migrateEnsureDefaultDirectionFunctions(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration des fonctions direction par défaut: %v", err)
+	}
+
+	// project_tasks: contrainte unique (code) -> (project_id, code) pour permettre TAP-YYYY-NNNN par projet
+	if err := migrateProjectTasksCodeUniquePerProject(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration project_tasks code unique: %v", err)
+	}
+
+	// projects: colonnes start_date et end_date si absentes (période prévue)
+	if err := migrateProjectsStartEndDates(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration projects start_date/end_date: %v", err)
+	}
+
+	// project_budget_extensions: colonnes start_date et end_date (période de chaque extension)
+	if err := migrateProjectBudgetExtensionsStartEndDates(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration project_budget_extensions start_date/end_date: %v", err)
+	}
+
+	// Migrations multi-filiales : ajouter les colonnes filiale_id, software_id, etc.
+	if err := migrateMultiFiliales(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration multi-filiales: %v", err)
+	}
+
+	// software: contrainte unique (code) -> (code, version) pour permettre plusieurs versions du même logiciel
+	if err := migrateSoftwareCodeVersionUnique(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration software code+version unique: %v", err)
+	}
+
+	// assets: anciens statuts (available, in_use, maintenance) -> statuts du cycle de vie
+	if err := migrateAssetLifecycleStatuses(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration des statuts d'actifs: %v", err)
+	}
+
+	// assets: étiquette d'inventaire par défaut (AST-NNNNNN) pour les actifs existants
+	if err := migrateAssetCodes(); err != nil {
+		log.Printf("⚠️  Erreur lors de l'attribution des étiquettes d'actifs: %v", err)
+	}
+
+	// knowledge_articles: statut de relecture des articles publiés avant le cycle de relecture
+	if err := migrateKnowledgeArticleStatuses(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration des statuts des articles: %v", err)
+	}
+
+	// knowledge_articles: index FULLTEXT de la recherche plein texte (KB_SEARCH_DRIVER=mysql)
+	if err := createKnowledgeFulltextIndexes(); err != nil {
+		log.Printf("⚠️  Erreur lors de la création des index de recherche des articles: %v", err)
+	}
+
+	// knowledge_article_attachments: pièces jointes antérieures aux téléversements (toutes des images intégrées)
+	if err := migrateKnowledgeInlineAttachments(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration des pièces jointes des articles: %v", err)
+	}
+
+	// weekly_declarations: statut du circuit de validation des déclarations validées avant le circuit
+	if err := migrateWeeklyDeclarationStatuses(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration des statuts des déclarations hebdomadaires: %v", err)
+	}
+
+	// time_entries: catégorie de travail des entrées antérieures (la colonne est créée à support, facturable)
+	if err := migrateTimeEntryWorkCategories(); err != nil {
+		log.Printf("⚠️  Erreur lors de la migration des catégories des entrées de temps: %v", err)
+	}
+
+	log.Println("✅ Migrations terminées avec succès")
+	return nil
+}
+
+// migrationModels retourne la liste de tous les modèles migrés, dans l'ordre logique de création
+// Utilisée par AutoMigrate et par PendingMigrations (sonde de disponibilité)
+func migrationModels() []interface{} {
+	return []interface{}{
 		// Tables de base (sans dépendances)
 		&models.Role{},
 		&models.Permission{},
@@ -132,6 +265,8 @@ func AutoMigrate() error {
 		&models.DailyDeclarationTask{},
 		&models.WeeklyDeclaration{},
 		&models.WeeklyDeclarationTask{},
+		&models.WeeklyDeclarationEvent{},
+		&models.Absence{},
 
 		// Tables de retards
 		&models.Delay{},
@@ -153,11 +288,17 @@ func AutoMigrate() error {
 		// Tables de base de connaissances
 		&models.KnowledgeCategory{},
 		&models.KnowledgeArticle{},
+		&models.KnowledgeArticleRevision{},
+		&models.KnowledgeDeflectionEvent{},
+		&models.KnowledgeArticleFeedback{},
 		&models.KnowledgeArticleAttachment{},
+		&models.KnowledgeStorageQuota{},
+		&models.KnowledgePortalKey{},
 
 		// Tables de projets
 		&models.Project{},
 		&models.TicketProject{},
+		&models.TicketProjectTask{},
 		&models.ProjectPhase{},
 		&models.ProjectFunction{},
 		&models.ProjectMember{},
@@ -168,6 +309,15 @@ func AutoMigrate() error {
 		&models.ProjectTaskComment{},
 		&models.ProjectTaskAttachment{},
 		&models.ProjectTaskHistory{},
+		&models.ProjectTaskDependency{},
+		&models.ProjectMilestone{},
+		&models.ProjectTaskRecurrence{},
+		&models.ProjectTaskTimer{},
+		&models.ProjectTemplate{},
+		&models.ProjectTemplatePhase{},
+		&models.ProjectTemplateFunction{},
+		&models.ProjectTemplateMember{},
+		&models.ProjectTemplateTask{},
 		&models.ProjectBudgetExtension{},
 
 		// Tables de paramétrage
@@ -178,96 +328,114 @@ func AutoMigrate() error {
 		&models.AuditLog{},
 		&models.BackupConfiguration{},
 		&models.Backup{},
-	)
-
-	if err != nil {
-		return fmt.Errorf("échec de la création des tables: %w", err)
-	}
-	log.Println("✅ Toutes les tables créées")
-
-	// Étape 2: Supprimer toutes les contraintes incorrectes créées par GORM
-	log.Println("🔧 Étape 2: Nettoyage des contraintes incorrectes...")
-	if err := removeAllIncorrectForeignKeys(); err != nil {
-		log.Printf("⚠️  Erreur lors du nettoyage: %v", err)
-	}
-
-	// Étape 3: Ajouter toutes les contraintes correctes manuellement
-	log.Println("🔧 Étape 3: Ajout des contraintes de clés étrangères...")
-	if err := addAllForeignKeys(); err != nil {
-		log.Printf("⚠️  Erreur lors de l'ajout des contraintes: %v", err)
-		// Ne pas bloquer, continuer
-	}
-
-	// Étape 4: Seeding des données par défaut
-	log.Println("🌱 Étape 4: Seeding des données par défaut...")
-	if err := seedDefaultPermissions(); err != nil {
-		log.Printf("⚠️  Erreur lors du seeding des permissions: %v", err)
-	}
-	if err := seedDefaultUserRole(); err != nil {
-		log.Printf("⚠️  Erreur lors du seeding du rôle USER: %v", err)
-	}
-	if err := seedUserRoleProjectPermissions(); err != nil {
-		log.Printf("⚠️  Erreur lors de l'attribution des permissions projets au rôle USER: %v", err)
-	}
-	if err := seedDefaultAdmin(); err != nil {
-		log.Printf("⚠️  Erreur lors du seeding de l'admin: %v", err)
-	}
-	if err := seedDefaultTicketCategories(); err != nil {
-		log.Printf("⚠️  Erreur lors du seeding des catégories: %v", err)
-	}
-
-	// Générer les codes pour les tickets existants
-	if err := generateTicketCodes(); err != nil {
-		log.Printf("⚠️  Erreur lors de la génération des codes: %v", err)
-	}
-
-	// Migrer les requester_id
-	if err := migrateRequesterIDs(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration des requester_id: %v", err)
-	}
-
-	// Modifier asset_software.asset_id pour le rendre nullable
-	if err := makeAssetSoftwareAssetIDNullable(); err != nil {
-		log.Printf("⚠️  Erreur lors de la modification de asset_software.asset_id: %v", err)
-	}
-
-	// project_functions.type et project_member_functions (rétrocompat)
-	if err := migrateProjectFunctionTypesAndMemberFunctions(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration project_functions / project_member_functions: %v", err)
-	}
-
-	// Préremplir Chef de projet et Lead pour les projets existants
-	if err := migrateEnsureDefaultDirectionFunctions(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration des fonctions direction par défaut: %v", err)
-	}
-
-	// project_tasks: contrainte unique (code) -> (project_id, code) pour permettre TAP-YYYY-NNNN par projet
-	if err := migrateProjectTasksCodeUniquePerProject(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration project_tasks code unique: %v", err)
-	}
 
-	// projects: colonnes start_date et end_date si absentes (période prévue)
-	if err := migrateProjectsStartEndDates(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration projects start_date/end_date: %v", err)
-	}
-
-	// project_budget_extensions: colonnes start_date et end_date (période de chaque extension)
-	if err := migrateProjectBudgetExtensionsStartEndDates(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration project_budget_extensions start_date/end_date: %v", err)
+		// Tables de webhooks
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+
+		// Historique des tâches planifiées
+		&models.ScheduledJobRun{},
+
+		// Partages de tickets et de projets
+		&models.RecordShare{},
+
+		// Délégations d'absence
+		&models.AccessDelegation{},
+
+		// Invitations de comptes (import en masse)
+		&models.UserInvitation{},
+
+		// Préférences utilisateur
+		&models.UserPreference{},
+
+		// Compétences et certifications
+		&models.Skill{},
+		&models.UserSkill{},
+
+		// Historique des déploiements de logiciels
+		&models.FilialeSoftwareHistory{},
+
+		// Versions publiées des logiciels
+		&models.SoftwareRelease{},
+
+		// Environnements des logiciels par filiale
+		&models.SoftwareEnvironment{},
+
+		// Contrats de support logiciel
+		&models.SupportContract{},
+
+		// Synchronisation Jira
+		&models.TicketJiraLink{},
+		&models.JiraCommentLink{},
+
+		// Imports depuis d'autres outils (GLPI, Zendesk, Freshdesk, fichiers)
+		&models.ImportRun{},
+		&models.ImportMapping{},
+		&models.ImportFile{},
+
+		// Flux de calendrier ICS
+		&models.CalendarFeed{},
+
+		// Synchronisation des agendas Google et Microsoft
+		&models.CalendarConnection{},
+		&models.CalendarEventLink{},
+
+		// Bot Telegram
+		&models.TelegramLink{},
+		&models.WhatsAppTemplate{},
+		&models.WhatsAppOptIn{},
+		&models.AlertSource{},
+		&models.MonitoringAlert{},
+		&models.TicketGitLink{},
+		&models.ReportingKey{},
+		&models.SyncTombstone{},
+		&models.PushDevice{},
+		&models.NotificationPreference{},
+		&models.BusinessCalendar{},
+		&models.BusinessHoliday{},
+		&models.EscalationRule{},
+		&models.TicketEscalation{},
+		&models.TicketView{},
+		&models.TicketWatcher{},
+		&models.TicketChecklistItem{},
+		&models.TicketSatisfaction{},
+		&models.TicketRelation{},
+		&models.RecurringTicket{},
+		&models.Problem{},
+		&models.ProblemIncident{},
+		&models.ApprovalRequest{},
+		&models.ApprovalApprover{},
+		&models.ApprovalComment{},
+		&models.MajorIncidentUpdate{},
+		&models.PostIncidentReview{},
+		&models.AssetLifecycleEvent{},
+		&models.SoftwareLicense{},
+		&models.DiscoveryAgent{},
+		&models.AssetDiscoveryDrift{},
+		&models.AssetRelation{},
+		&models.AssetReservation{},
+		&models.MaintenancePlan{},
+		&models.MaintenanceRecord{},
 	}
+}
 
-	// Migrations multi-filiales : ajouter les colonnes filiale_id, software_id, etc.
-	if err := migrateMultiFiliales(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration multi-filiales: %v", err)
+// PendingMigrations retourne les tables attendues qui n'existent pas encore en base
+func PendingMigrations() ([]string, error) {
+	if DB == nil {
+		return nil, fmt.Errorf("la base de données n'est pas initialisée")
 	}
 
-	// software: contrainte unique (code) -> (code, version) pour permettre plusieurs versions du même logiciel
-	if err := migrateSoftwareCodeVersionUnique(); err != nil {
-		log.Printf("⚠️  Erreur lors de la migration software code+version unique: %v", err)
+	pending := []string{}
+	for _, model := range migrationModels() {
+		if !DB.Migrator().HasTable(model) {
+			stmt := &gorm.Statement{DB: DB}
+			if err := stmt.Parse(model); err != nil {
+				return nil, fmt.Errorf("erreur lors de l'analyse du modèle: %w", err)
+			}
+			pending = append(pending, stmt.Schema.Table)
+		}
 	}
-
-	log.Println("✅ Migrations terminées avec succès")
-	return nil
+	return pending, nil
 }
 
 // removeAllIncorrectForeignKeys supprime toutes les contraintes incorrectes créées par GORM
@@ -561,6 +729,9 @@ func seedDefaultPermissions() error {
 		{"tickets.resolve_own_filiale", "Résoudre tickets de sa filiale", "Résoudre les tickets de sa filiale uniquement", "tickets"},
 		{"tickets.validate", "Valider les tickets résolus", "Valider les tickets résolus", "tickets"},
 		{"tickets.validate_own", "Valider ses propres tickets", "Valider uniquement ses propres tickets créés", "tickets"},
+		{"tickets.share", "Partager un ticket", "Partager un ticket visible avec un utilisateur ou un département (lecture ou lecture-écriture)", "tickets"},
+		{"tickets.merge", "Fusionner des tickets", "Fusionner des tickets en double dans un ticket principal", "tickets"},
+		{"tickets.recurring.manage", "Gérer les tickets récurrents", "Créer, modifier et supprimer les planifications de création automatique de tickets", "tickets"},
 
 		// Permissions Tickets internes (départements non-IT, scope département / filiale / global)
 		{"tickets_internes.view_own", "Voir ses tickets internes", "Voir ses tickets internes (créés ou assignés)", "tickets_internes"},
@@ -581,6 +752,10 @@ func seedDefaultPermissions() error {
 		{"software.delete", "Supprimer un logiciel", "Supprimer un logiciel (IT MCI CARE CI)", "software"},
 		{"software.deploy", "Déployer un logiciel", "Déployer un logiciel chez une filiale (IT MCI CARE CI)", "software"},
 		{"software.manage_deployments", "Gérer les déploiements", "Gérer les déploiements de logiciels (IT MCI CARE CI)", "software"},
+		{"support_contracts.view", "Voir les contrats de support", "Voir les contrats de support logiciel des filiales", "software"},
+		{"support_contracts.manage", "Gérer les contrats de support", "Créer, modifier et résilier les contrats de support logiciel (IT MCI CARE CI)", "software"},
+		{"software_licenses.view", "Voir les licences logicielles", "Voir les licences logicielles et le rapport de conformité (sa filiale uniquement sans software_licenses.manage)", "software"},
+		{"software_licenses.manage", "Gérer les licences logicielles", "Créer, modifier et supprimer les licences logicielles des filiales (IT MCI CARE CI)", "software"},
 
 		// Permissions Filiales
 		{"filiales.view", "Voir les filiales", "Voir les filiales (sa filiale uniquement sans view_all)", "filiales"},
@@ -603,6 +778,8 @@ func seedDefaultPermissions() error {
 		{"timesheet.view_budget", "Voir le budget temps", "Accéder à l'onglet Budget temps (temps estimés par ticket, alertes budget)", "timesheet"},
 		{"timesheet.create_daily", "Créer une déclaration journalière", "Créer ou modifier une déclaration journalière de temps", "timesheet"},
 		{"timesheet.create_weekly", "Créer une déclaration hebdomadaire", "Créer ou modifier une déclaration hebdomadaire de temps", "timesheet"},
+		{"timesheet.view_utilization", "Voir le taux d'utilisation", "Voir le taux d'utilisation et la répartition facturable / non facturable des utilisateurs visibles", "timesheet"},
+		{"timesheet.manage_utilization_targets", "Gérer les objectifs d'utilisation", "Définir le taux d'utilisation facturable visé par rôle", "timesheet"},
 
 		// Permissions Users
 		{"users.view_all", "Voir tous les utilisateurs", "Voir tous les utilisateurs", "users"},
@@ -614,6 +791,8 @@ func seedDefaultPermissions() error {
 		{"users.update", "Modifier un utilisateur", "Modifier un utilisateur de sa propre filiale", "users"},
 		{"users.update_any_filiale", "Modifier un utilisateur dans n'importe quelle filiale", "Modifier un utilisateur dans n'importe quelle filiale (admin principal)", "users"},
 		{"users.delete", "Supprimer un utilisateur", "Supprimer un utilisateur", "users"},
+		{"users.offboard", "Gérer le départ d'un utilisateur", "Désactiver un utilisateur et réaffecter ses tickets, tâches et validations en attente à un successeur", "users"},
+		{"skills.manage", "Gérer le catalogue de compétences", "Créer, modifier et désactiver les compétences et certifications du catalogue", "users"},
 
 		// Permissions Roles
 		{"roles.view", "Voir les rôles", "Voir les rôles", "roles"},
@@ -634,6 +813,7 @@ func seedDefaultPermissions() error {
 		{"reports.view_departments", "Rapports par départements", "Voir les rapports par départements", "reports"},
 		{"reports.view_employees", "Rapports par employé", "Voir les rapports par employé", "reports"},
 		{"reports.compare_filiales", "Comparer entre filiales", "Comparer les rapports entre filiales (IT MCI CARE CI)", "reports"},
+		{"reports.odata", "Gérer le flux OData", "Créer les clés d'API du flux OData de reporting (Power BI) limitées à une filiale, ou à toutes avec reports.view_global", "reports"},
 
 		// Permissions Assets
 		{"assets.view_all", "Voir tous les actifs", "Voir tous les actifs IT", "assets"},
@@ -642,6 +822,9 @@ func seedDefaultPermissions() error {
 		{"assets.create", "Créer un actif", "Créer un actif IT", "assets"},
 		{"assets.update", "Modifier un actif", "Modifier un actif IT", "assets"},
 		{"assets.delete", "Supprimer un actif", "Supprimer un actif IT", "assets"},
+		{"asset_discovery.manage", "Gérer la découverte d'actifs", "Déclarer les agents d'inventaire autorisés à remonter les actifs découverts et traiter les écarts avec la CMDB", "assets"},
+		{"asset_reservations.manage", "Gérer le parc de prêt", "Approuver les réservations d'équipements partagés, réserver pour un autre utilisateur, enregistrer la remise et la restitution", "assets"},
+		{"asset_maintenance.manage", "Gérer les plans de maintenance", "Créer et modifier les plans de maintenance préventive des actifs et générer leurs tickets", "assets"},
 
 		// Permissions Knowledge Base
 		{"knowledge.view_all", "Voir tous les articles", "Voir tous les articles", "knowledge"},
@@ -651,11 +834,16 @@ func seedDefaultPermissions() error {
 		{"knowledge.update", "Modifier un article", "Modifier un article", "knowledge"},
 		{"knowledge.delete", "Supprimer un article", "Supprimer un article", "knowledge"},
 		{"knowledge.publish", "Publier un article", "Publier un article", "knowledge"},
+		{"knowledge.manage_public", "Gérer le portail public de la base de connaissances", "Créer et révoquer les clés d'API du portail public en lecture seule de la base de connaissances", "knowledge"},
+		{"knowledge.manage_storage", "Gérer le stockage de la base de connaissances", "Consulter l'espace occupé par les pièces jointes des articles et fixer le quota de chaque filiale", "knowledge"},
 
 		// Permissions Settings
 		{"settings.view", "Voir les paramètres", "Voir les paramètres système", "settings"},
 		{"settings.update", "Modifier les paramètres", "Modifier les paramètres système", "settings"},
 		{"settings.manage", "Configuration système", "Gérer la configuration système (permission globale)", "settings"},
+		{"whatsapp.manage", "Gérer les modèles WhatsApp", "Gérer les modèles de messages WhatsApp Business des notifications critiques", "settings"},
+		{"alerts.manage", "Gérer la supervision", "Déclarer les outils de supervision (Alertmanager, Zabbix) autorisés à créer des incidents et consulter leurs alertes", "settings"},
+		{"imports.manage", "Importer des données", "Importer les données d'un autre outil (GLPI, Zendesk, Freshdesk, fichiers) : utilisateurs, catégories, actifs, tickets et articles", "settings"},
 
 		// Permissions SLA
 		{"sla.view", "Voir les SLA", "Voir les SLA", "sla"},
@@ -666,11 +854,13 @@ func seedDefaultPermissions() error {
 		{"sla.update", "Modifier un SLA", "Modifier un SLA", "sla"},
 		{"sla.delete", "Supprimer un SLA", "Supprimer un SLA", "sla"},
 		{"sla.manage", "Gestion SLA", "Gérer les SLA (permission globale)", "sla"},
+		{"escalations.manage", "Gérer les règles d'escalade", "Créer, modifier et supprimer les règles d'escalade automatique des tickets (réassignation, priorité, notification)", "sla"},
 
 		// Permissions Audit
 		{"audit.view_all", "Voir tous les logs", "Voir tous les logs d'audit", "audit"},
 		{"audit.view_team", "Voir logs de son équipe", "Voir les logs de son équipe", "audit"},
 		{"audit.view_own", "Voir ses propres logs", "Voir ses propres actions enregistrées", "audit"},
+		{"audit.verify", "Vérifier l'intégrité des logs", "Vérifier le chaînage des logs d'audit (audits de conformité)", "audit"},
 
 		// Permissions Offices (Sièges)
 		{"offices.view", "Voir les sièges", "Voir les sièges (équivalent à view_filiale pour rétrocompat)", "offices"},
@@ -700,6 +890,8 @@ func seedDefaultPermissions() error {
 		{"incidents.create", "Créer un incident", "Créer un nouvel incident", "incidents"},
 		{"incidents.update", "Modifier un incident", "Modifier un incident existant", "incidents"},
 		{"incidents.delete", "Supprimer un incident", "Supprimer un incident", "incidents"},
+		{"incidents.major_alerts", "Alertes incident majeur", "Être notifié de tout incident majeur (impact critique) de sa filiale", "incidents"},
+		{"incidents.major_manage", "Piloter les incidents majeurs", "Déclarer un incident majeur, publier les points de situation, le clôturer et rédiger la revue post-incident", "incidents"},
 
 		// Permissions Service Requests (Demandes de service)
 		{"service_requests.view", "Voir les demandes de service", "Voir les demandes de service", "service_requests"},
@@ -719,6 +911,19 @@ func seedDefaultPermissions() error {
 		{"changes.update", "Modifier un changement", "Modifier un changement existant", "changes"},
 		{"changes.delete", "Supprimer un changement", "Supprimer un changement", "changes"},
 
+		// Permissions Problems (Problèmes et erreurs connues)
+		{"problems.view", "Voir les problèmes", "Voir les problèmes et erreurs connues", "problems"},
+		{"problems.view_all", "Voir tous les problèmes", "Voir tous les problèmes du système", "problems"},
+		{"problems.view_team", "Voir problèmes de son équipe", "Voir les problèmes de son équipe/département", "problems"},
+		{"problems.view_own", "Voir ses problèmes", "Voir les problèmes liés à ses tickets", "problems"},
+		{"problems.create", "Créer un problème", "Créer un nouveau problème", "problems"},
+		{"problems.update", "Modifier un problème", "Documenter la cause racine, déclarer une erreur connue, lier des incidents et publier le contournement", "problems"},
+		{"problems.delete", "Supprimer un problème", "Supprimer un problème", "problems"},
+
+		// Permissions Approvals (Circuit d'approbation)
+		{"approvals.request", "Soumettre à approbation", "Soumettre une demande de service, un changement, une extension de budget ou une déclaration à approbation", "approvals"},
+		{"approvals.manage", "Gérer les approbations", "Voir, commenter et annuler toutes les demandes d'approbation", "approvals"},
+
 		// Permissions Delays (Retards)
 		{"delays.view", "Voir les retards", "Voir les retards", "delays"},
 		{"delays.view_all", "Voir tous les retards", "Voir tous les retards du système", "delays"},
@@ -736,6 +941,7 @@ func seedDefaultPermissions() error {
 		{"projects.delete", "Supprimer un projet", "Supprimer un projet", "projects"},
 		{"projects.set_project_manager", "Désigner le chef de projet", "Désigner ou changer le chef de projet", "projects"},
 		{"projects.set_lead", "Désigner le lead", "Désigner ou changer le lead technique ou fonctionnel", "projects"},
+		{"projects.share", "Partager un projet", "Partager un projet visible avec un utilisateur ou un département (lecture ou lecture-écriture)", "projects"},
 
 		// Permissions Projects — étapes (phases)
 		{"projects.phases.view", "Voir les étapes", "Voir les étapes d'un projet", "projects"},
@@ -798,6 +1004,11 @@ func seedDefaultPermissions() error {
 		{"projects.budget.extensions.update", "Modifier une extension de budget", "Modifier une extension de budget", "projects"},
 		{"projects.budget.extensions.delete", "Supprimer une extension de budget", "Supprimer une extension de budget", "projects"},
 		{"projects.dashboard.view", "Voir le tableau de bord projet", "Voir le tableau de bord (avancement, statistiques)", "projects"},
+		{"projects.capacity.view", "Voir le plan de charge", "Voir la charge hebdomadaire des ressources (tâches, tickets, temps déclaré) et les surcharges", "projects"},
+		{"projects.templates.manage", "Gérer les modèles de projet", "Créer, modifier et supprimer les modèles de projet (étapes, fonctions, membres et tâches par défaut)", "projects"},
+		{"projects.archive", "Archiver les projets", "Archiver un projet (lecture seule, masqué des listes par défaut) et le désarchiver", "projects"},
+		{"projects.archived.edit", "Modifier les projets archivés", "Modifier un projet archivé et ses éléments (étapes, membres, tâches, jalons) sans le désarchiver", "projects"},
+		{"projects.portfolio.view", "Voir le portefeuille de projets", "Voir les indicateurs consolidés des projets (statuts, budget, retards, filiales, tendance)", "projects"},
 
 		// Permissions Asset Categories (Catégories d'actifs)
 		{"asset_categories.view", "Voir les catégories d'actifs", "Voir les catégories d'actifs", "asset_categories"},
@@ -816,6 +1027,10 @@ func seedDefaultPermissions() error {
 		{"ticket_categories.create", "Créer une catégorie de ticket", "Créer une nouvelle catégorie de ticket", "ticket_categories"},
 		{"ticket_categories.update", "Modifier une catégorie de ticket", "Modifier une catégorie de ticket existante", "ticket_categories"},
 		{"ticket_categories.delete", "Supprimer une catégorie de ticket", "Supprimer une catégorie de ticket", "ticket_categories"},
+
+		// Permissions Webhooks (intégrations sortantes)
+		{"webhooks.view", "Voir les webhooks", "Voir les abonnements webhook et leur journal de livraison", "webhooks"},
+		{"webhooks.manage", "Gérer les webhooks", "Créer, modifier, tester et supprimer les abonnements webhook", "webhooks"},
 	}
 
 	for _, perm := range permissions {
@@ -839,6 +1054,61 @@ func seedDefaultPermissions() error {
 	return nil
 }
 
+// seedFieldMaskPermissions crée les permissions de visibilité des champs sensibles (tag mask des DTO).
+// À la création, chaque permission est attribuée aux rôles qui voyaient déjà le champ, pour ne rien masquer
+// sans décision d'un administrateur ; ensuite, l'attribution se gère rôle par rôle.
+func seedFieldMaskPermissions() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+
+	fieldPermissions := []struct {
+		Code        string
+		Name        string
+		Description string
+		Module      string
+		GrantedWith []string // Permissions des rôles qui reçoivent la nouvelle permission à sa création
+	}{
+		{"users.view_phone", "Voir le téléphone des utilisateurs", "Voir le numéro de téléphone des autres utilisateurs (demandeurs, membres)", "users", []string{"users.view_all", "users.view_filiale", "users.view_team", "users.view_own"}},
+		{"tickets.comments.view_internal", "Voir les commentaires internes", "Voir les commentaires internes des tickets hors département IT", "tickets", nil},
+		{"software_licenses.view_keys", "Voir les clés de licence", "Voir les clés des licences logicielles", "software", []string{"software_licenses.manage"}},
+	}
+
+	for _, fp := range fieldPermissions {
+		var existing models.Permission
+		err := DB.Where("code = ?", fp.Code).First(&existing).Error
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		perm := models.Permission{Code: fp.Code, Name: fp.Name, Description: fp.Description, Module: fp.Module}
+		if err := DB.Create(&perm).Error; err != nil {
+			log.Printf("   ⚠️  Erreur lors de la création de la permission %s: %v", fp.Code, err)
+			continue
+		}
+		if len(fp.GrantedWith) == 0 {
+			continue
+		}
+
+		var roleIDs []uint
+		DB.Model(&models.RolePermission{}).
+			Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+			Where("permissions.code IN ?", fp.GrantedWith).
+			Distinct().Pluck("role_permissions.role_id", &roleIDs)
+		for _, roleID := range roleIDs {
+			if err := DB.Create(&models.RolePermission{RoleID: roleID, PermissionID: perm.ID, CreatedAt: time.Now()}).Error; err != nil {
+				log.Printf("   ⚠️  Attribution %s au rôle %d: %v", fp.Code, roleID, err)
+			}
+		}
+	}
+
+	log.Println("   ✅ Permissions de champs sensibles vérifiées")
+	return nil
+}
+
 // seedDefaultUserRole crée le rôle USER par défaut
 func seedDefaultUserRole() error {
 	if DB == nil {
@@ -1033,6 +1303,8 @@ func seedDefaultTicketCategories() error {
 		{"Incident", "incident", "Problème technique nécessitant une résolution", "alert-circle", "red"},
 		{"Demande", "demande", "Demande de service ou d'assistance", "help-circle", "blue"},
 		{"Changement", "changement", "Demande de modification ou d'évolution", "refresh-cw", "orange"},
+		{"Problème", "probleme", "Cause sous-jacente d'un ou plusieurs incidents", "search", "purple"},
+		{"Maintenance", "maintenance", "Maintenance préventive planifiée des équipements", "tool", "teal"},
 	}
 
 	for _, cat := range categories {
@@ -1293,6 +1565,150 @@ func migrateProjectBudgetExtensionsStartEndDates() error {
 	return nil
 }
 
+// migrateAssetLifecycleStatuses convertit les anciens statuts d'actifs vers les statuts du cycle de vie
+func migrateAssetLifecycleStatuses() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+	for legacy, status := range models.LegacyAssetStatuses() {
+		result := DB.Model(&models.Asset{}).Unscoped().Where("status = ?", legacy).Update("status", status)
+		if result.Error != nil {
+			return fmt.Errorf("assets.status %s -> %s: %w", legacy, status, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("   🔧 assets: %d actif(s) %s -> %s", result.RowsAffected, legacy, status)
+		}
+	}
+	return nil
+}
+
+// migrateAssetCodes attribue l'étiquette par défaut aux actifs qui n'en ont pas
+func migrateAssetCodes() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+	result := DB.Exec("UPDATE assets SET code = CONCAT('AST-', LPAD(id, 6, '0')) WHERE code IS NULL OR code = ''")
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("   🔧 assets: %d étiquette(s) attribuée(s)", result.RowsAffected)
+	}
+	return nil
+}
+
+// migrateKnowledgeArticleStatuses aligne le statut des articles publiés existants (la colonne est créée à draft)
+func migrateKnowledgeArticleStatuses() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+	result := DB.Model(&models.KnowledgeArticle{}).Unscoped().
+		Where("is_published = ? AND status = ?", true, models.KnowledgeStatusDraft).
+		Update("status", models.KnowledgeStatusPublished)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("   🔧 knowledge_articles: %d article(s) publié(s)", result.RowsAffected)
+	}
+	return nil
+}
+
+// migrateWeeklyDeclarationStatuses aligne le statut des déclarations déjà validées (la colonne est créée à draft) :
+// elles sont approuvées et verrouillent leur semaine
+func migrateWeeklyDeclarationStatuses() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+	result := DB.Model(&models.WeeklyDeclaration{}).
+		Where("validated = ? AND status = ?", true, models.WeeklyDeclarationStatusDraft).
+		Update("status", models.WeeklyDeclarationStatusApproved)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("   🔧 weekly_declarations: %d déclaration(s) approuvée(s)", result.RowsAffected)
+	}
+	return nil
+}
+
+// migrateTimeEntryWorkCategories catégorise les entrées de temps selon leur rattachement (la colonne est créée à support) :
+// tâches de projet en project, tickets internes en internal non facturable. La catégorie de ces entrées n'étant pas
+// modifiable, la migration ne touche que les entrées antérieures
+func migrateTimeEntryWorkCategories() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+	result := DB.Model(&models.TimeEntry{}).Unscoped().
+		Where("project_task_id IS NOT NULL AND work_category <> ?", models.WorkCategoryProject).
+		Update("work_category", models.WorkCategoryProject)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("   🔧 time_entries: %d entrée(s) de projet", result.RowsAffected)
+	}
+	result = DB.Model(&models.TimeEntry{}).Unscoped().
+		Where("ticket_internal_id IS NOT NULL AND work_category <> ?", models.WorkCategoryInternal).
+		Updates(map[string]interface{}{"work_category": models.WorkCategoryInternal, "billable": false})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("   🔧 time_entries: %d entrée(s) interne(s)", result.RowsAffected)
+	}
+	return nil
+}
+
+// migrateKnowledgeInlineAttachments marque comme intégrées les pièces jointes créées avant les téléversements :
+// elles proviennent toutes des images du contenu et n'ont pas d'empreinte (toujours renseignée depuis)
+func migrateKnowledgeInlineAttachments() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+	result := DB.Model(&models.KnowledgeArticleAttachment{}).Unscoped().
+		Where("(content_hash IS NULL OR content_hash = '') AND is_inline = ?", false).
+		Update("is_inline", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("   🔧 knowledge_article_attachments: %d image(s) intégrée(s)", result.RowsAffected)
+	}
+	return nil
+}
+
+// createKnowledgeFulltextIndexes crée les index FULLTEXT des articles : titre seul (pondération du titre) et titre + contenu
+func createKnowledgeFulltextIndexes() error {
+	if DB == nil {
+		return fmt.Errorf("la base de données n'est pas initialisée")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	for _, idx := range []struct{ name, columns string }{
+		{"ft_knowledge_articles_title", "title"},
+		{"ft_knowledge_articles_search", "title, content"},
+	} {
+		var exists int
+		if err := sqlDB.QueryRow(`
+			SELECT COUNT(*) FROM information_schema.STATISTICS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'knowledge_articles' AND INDEX_NAME = ?
+		`, idx.name).Scan(&exists); err != nil {
+			return err
+		}
+		if exists > 0 {
+			continue
+		}
+		log.Printf("   🔧 Création de l'index FULLTEXT %s sur knowledge_articles (%s)", idx.name, idx.columns)
+		if _, err := sqlDB.Exec(fmt.Sprintf("CREATE FULLTEXT INDEX %s ON knowledge_articles (%s)", idx.name, idx.columns)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // makeAssetSoftwareAssetIDNullable rend la colonne asset_id de asset_software nullable
 // Cela permet de créer des logiciels indépendamment des actifs
 func makeAssetSoftwareAssetIDNullable() error {
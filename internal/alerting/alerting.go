@@ -0,0 +1,274 @@
+// Package alerting lit les notifications des outils de supervision (Prometheus Alertmanager, Zabbix)
+// et les ramène à une alerte commune, identifiée par une empreinte stable entre déclenchement et résolution
+package alerting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Formats de notification acceptés
+const (
+	KindAlertmanager = "alertmanager"
+	KindZabbix       = "zabbix"
+)
+
+// États d'une alerte
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Niveaux de sévérité normalisés
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Alert alerte normalisée
+type Alert struct {
+	Fingerprint string            // Identique pour toutes les notifications d'une même alerte
+	Name        string            // Nom de la règle ou du déclencheur
+	Status      string            // firing, resolved
+	Severity    string            // critical, high, warning, info
+	Summary     string            // Résumé lisible
+	Description string            // Détail (optionnel)
+	Labels      map[string]string // Étiquettes (hôte, service, ...) utilisées pour rattacher l'alerte à un actif ou un logiciel
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	URL         string // Lien vers l'alerte dans l'outil de supervision (optionnel)
+}
+
+// Host retourne l'hôte concerné (étiquettes host, hostname, nodename ou instance sans le port)
+func (a Alert) Host() string {
+	for _, key := range []string{"host", "hostname", "nodename", "instance"} {
+		if value := strings.TrimSpace(a.Labels[key]); value != "" {
+			if host, _, err := net.SplitHostPort(value); err == nil && key == "instance" {
+				value = host
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// Label retourne la valeur de la première étiquette non vide parmi keys
+func (a Alert) Label(keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(a.Labels[key]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// LabelsText retourne les étiquettes triées, une par ligne (clé=valeur)
+func (a Alert) LabelsText() string {
+	keys := make([]string, 0, len(a.Labels))
+	for key := range a.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = key + "=" + a.Labels[key]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse lit une notification au format kind et retourne ses alertes
+func Parse(kind string, body []byte) ([]Alert, error) {
+	switch kind {
+	case KindAlertmanager:
+		return parseAlertmanager(body)
+	case KindZabbix:
+		return parseZabbix(body)
+	}
+	return nil, fmt.Errorf("format de notification inconnu: %s", kind)
+}
+
+// alertmanagerPayload notification webhook d'Alertmanager (version 4)
+type alertmanagerPayload struct {
+	Alerts []struct {
+		Status       string            `json:"status"`
+		Labels       map[string]string `json:"labels"`
+		Annotations  map[string]string `json:"annotations"`
+		StartsAt     time.Time         `json:"startsAt"`
+		EndsAt       time.Time         `json:"endsAt"`
+		GeneratorURL string            `json:"generatorURL"`
+		Fingerprint  string            `json:"fingerprint"`
+	} `json:"alerts"`
+}
+
+// parseAlertmanager lit une notification d'Alertmanager (une ou plusieurs alertes groupées)
+func parseAlertmanager(body []byte) ([]Alert, error) {
+	var payload alertmanagerPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("notification Alertmanager illisible")
+	}
+	if len(payload.Alerts) == 0 {
+		return nil, errors.New("notification Alertmanager sans alerte")
+	}
+
+	alerts := make([]Alert, 0, len(payload.Alerts))
+	for _, a := range payload.Alerts {
+		labels := a.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		fingerprint := a.Fingerprint
+		if fingerprint == "" {
+			// Empreinte absente (anciennes versions) : dérivée des étiquettes, comme le fait Alertmanager
+			fingerprint = "labels:" + Alert{Labels: labels}.LabelsText()
+		}
+		alert := Alert{
+			Fingerprint: fingerprint,
+			Name:        labels["alertname"],
+			Status:      StatusFiring,
+			Severity:    normalizeSeverity(labels["severity"]),
+			Summary:     firstNonEmpty(a.Annotations["summary"], a.Annotations["title"], a.Annotations["message"]),
+			Description: firstNonEmpty(a.Annotations["description"], a.Annotations["message"]),
+			Labels:      labels,
+			URL:         a.GeneratorURL,
+		}
+		if strings.EqualFold(a.Status, StatusResolved) {
+			alert.Status = StatusResolved
+		}
+		if !a.StartsAt.IsZero() {
+			startsAt := a.StartsAt
+			alert.StartsAt = &startsAt
+		}
+		// Alertmanager renseigne endsAt à l'an 1 pour les alertes en cours
+		if alert.Status == StatusResolved && a.EndsAt.Year() > 1 {
+			endsAt := a.EndsAt
+			alert.EndsAt = &endsAt
+		}
+		if alert.Name == "" {
+			alert.Name = "Alerte Alertmanager"
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// zabbixPayload paramètres du type de média webhook Kronos à déclarer dans Zabbix
+// (event_id={EVENT.ID}, status={EVENT.STATUS}, value={EVENT.VALUE}, name={EVENT.NAME}, severity={EVENT.SEVERITY},
+// host={HOST.NAME}, message={ALERT.MESSAGE}, tags={EVENT.TAGSJSON}, trigger_id={TRIGGER.ID}, url={TRIGGER.URL})
+type zabbixPayload struct {
+	EventID   string          `json:"event_id"`
+	Status    string          `json:"status"`
+	Value     string          `json:"value"`
+	Name      string          `json:"name"`
+	Severity  string          `json:"severity"`
+	Host      string          `json:"host"`
+	Message   string          `json:"message"`
+	Tags      json.RawMessage `json:"tags"`
+	TriggerID string          `json:"trigger_id"`
+	URL       string          `json:"url"`
+	EventDate string          `json:"event_date"` // {EVENT.DATE} (2006.01.02)
+	EventTime string          `json:"event_time"` // {EVENT.TIME} (15:04:05)
+}
+
+// parseZabbix lit une notification du webhook Zabbix (un événement de problème ou de rétablissement)
+func parseZabbix(body []byte) ([]Alert, error) {
+	var payload zabbixPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("notification Zabbix illisible")
+	}
+	if payload.EventID == "" && payload.TriggerID == "" {
+		return nil, errors.New("notification Zabbix sans event_id")
+	}
+
+	labels := zabbixTags(payload.Tags)
+	if payload.Host != "" {
+		labels["host"] = payload.Host
+	}
+	// L'événement de rétablissement reprend l'ID du problème dans {EVENT.ID}
+	fingerprint := "zabbix:" + payload.EventID
+	if payload.EventID == "" {
+		fingerprint = "zabbix:trigger:" + payload.TriggerID + ":" + payload.Host
+	}
+	alert := Alert{
+		Fingerprint: fingerprint,
+		Name:        firstNonEmpty(payload.Name, "Alerte Zabbix"),
+		Status:      StatusFiring,
+		Severity:    normalizeSeverity(payload.Severity),
+		Summary:     payload.Name,
+		Description: payload.Message,
+		Labels:      labels,
+		URL:         payload.URL,
+	}
+	if payload.Value == "0" || strings.EqualFold(payload.Status, "RESOLVED") || strings.EqualFold(payload.Status, "OK") {
+		alert.Status = StatusResolved
+	}
+	if payload.EventDate != "" {
+		if at, err := time.ParseInLocation("2006.01.02 15:04:05", strings.TrimSpace(payload.EventDate+" "+payload.EventTime), time.Local); err == nil {
+			if alert.Status == StatusResolved {
+				alert.EndsAt = &at
+			} else {
+				alert.StartsAt = &at
+			}
+		}
+	}
+	return []Alert{alert}, nil
+}
+
+// zabbixTags convertit les tags d'un événement ({EVENT.TAGSJSON} ou {EVENT.TAGS} « clé:valeur, ... ») en étiquettes
+func zabbixTags(raw json.RawMessage) map[string]string {
+	labels := map[string]string{}
+	if len(raw) == 0 {
+		return labels
+	}
+	var tags []struct {
+		Tag   string `json:"tag"`
+		Value string `json:"value"`
+	}
+	if json.Unmarshal(raw, &tags) == nil {
+		for _, tag := range tags {
+			if tag.Tag != "" {
+				labels[strings.ToLower(tag.Tag)] = tag.Value
+			}
+		}
+		return labels
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		for _, part := range strings.Split(text, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(part), ":")
+			if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+				labels[key] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return labels
+}
+
+// normalizeSeverity ramène les sévérités Alertmanager et Zabbix à critical, high, warning ou info
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical", "disaster", "page", "emergency", "fatal":
+		return SeverityCritical
+	case "high", "error", "major":
+		return SeverityHigh
+	case "warning", "average", "minor", "warn":
+		return SeverityWarning
+	}
+	return SeverityInfo
+}
+
+// firstNonEmpty retourne la première valeur non vide
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+// Package antivirus analyse les fichiers reçus avant leur stockage (démon ClamAV via le protocole clamd INSTREAM)
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// chunkSize taille des blocs envoyés à clamd (la taille totale est bornée par StreamMaxLength côté clamd)
+const chunkSize = 64 * 1024
+
+// InfectedError fichier signalé par l'antivirus
+type InfectedError struct {
+	Signature string // Nom de la menace détectée (ex: Eicar-Signature)
+}
+
+func (e *InfectedError) Error() string {
+	return "menace détectée: " + e.Signature
+}
+
+// Scanner analyse un contenu : nil s'il est sain, *InfectedError s'il contient une menace,
+// une autre erreur si l'analyse n'a pas pu être effectuée
+type Scanner interface {
+	Scan(ctx context.Context, content io.Reader) error
+}
+
+// New crée l'analyseur configuré par ANTIVIRUS_DRIVER (none : aucune analyse)
+func New(cfg config.AntivirusConfig) (Scanner, error) {
+	switch cfg.Driver {
+	case "", "none":
+		return noopScanner{}, nil
+	case "clamav":
+		return &clamdScanner{address: cfg.ClamAVAddress, timeout: cfg.Timeout}, nil
+	}
+	return nil, fmt.Errorf("antivirus inconnu: %s", cfg.Driver)
+}
+
+// noopScanner accepte tous les fichiers
+type noopScanner struct{}
+
+func (noopScanner) Scan(ctx context.Context, content io.Reader) error {
+	return nil
+}
+
+// clamdScanner envoie le contenu au démon clamd (commande INSTREAM)
+type clamdScanner struct {
+	address string // host:port ou unix:/chemin
+	timeout time.Duration
+}
+
+// Scan transmet le contenu par blocs préfixés de leur longueur, terminés par un bloc vide, puis lit le verdict
+func (s *clamdScanner) Scan(ctx context.Context, content io.Reader) error {
+	network, address := "tcp", s.address
+	if path, ok := strings.CutPrefix(s.address, "unix:"); ok {
+		network, address = "unix", path
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return fmt.Errorf("connexion à clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("envoi à clamd: %w", err)
+	}
+	buf := make([]byte, chunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := content.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return fmt.Errorf("envoi à clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return fmt.Errorf("envoi à clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("envoi à clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("réponse de clamd: %w", err)
+	}
+	return parseReply(string(bytes.TrimRight(reply, "\x00\n")))
+}
+
+// parseReply interprète la réponse de clamd (ex: "stream: OK", "stream: Eicar-Signature FOUND")
+func parseReply(reply string) error {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &InfectedError{Signature: strings.TrimSuffix(result, " FOUND")}
+	default:
+		return fmt.Errorf("réponse de clamd inattendue: %s", reply)
+	}
+}
@@ -0,0 +1,165 @@
+// Package audit prépare le contenu du journal d'audit : instantanés avant/après avec masquage
+// des champs sensibles, et empreintes chaînées rendant toute altération détectable
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// MaskedValue valeur enregistrée à la place d'un champ sensible
+const MaskedValue = "********"
+
+// sensitiveKeys fragments de noms de champs dont la valeur n'est jamais journalisée
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "key_hash", "private_key", "credential", "otp", "totp"}
+
+// ignoredDiffKeys champs exclus des différences (mis à jour à chaque écriture)
+var ignoredDiffKeys = map[string]bool{"updated_at": true}
+
+// IsSensitive indique si un champ doit être masqué
+func IsSensitive(key string) bool {
+	lower := strings.ToLower(key)
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask remplace récursivement les valeurs des champs sensibles (la map est modifiée)
+func Mask(values map[string]interface{}) map[string]interface{} {
+	for key, value := range values {
+		if IsSensitive(key) {
+			if value != nil && value != "" {
+				values[key] = MaskedValue
+			}
+			continue
+		}
+		switch nested := value.(type) {
+		case map[string]interface{}:
+			Mask(nested)
+		case []interface{}:
+			for _, item := range nested {
+				if object, ok := item.(map[string]interface{}); ok {
+					Mask(object)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// MaskJSON masque les champs sensibles d'un objet JSON ; un contenu qui n'est pas un objet est écarté
+func MaskJSON(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil || values == nil {
+		return nil
+	}
+	return Encode(Mask(values))
+}
+
+// Encode sérialise des valeurs pour les colonnes old_values / new_values (nil si vide)
+func Encode(values map[string]interface{}) []byte {
+	if len(values) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// Diff retourne les valeurs avant/après des seuls champs modifiés, champs sensibles masqués
+func Diff(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	oldValues := map[string]interface{}{}
+	newValues := map[string]interface{}{}
+	for key, value := range after {
+		if ignoredDiffKeys[key] {
+			continue
+		}
+		previous, existed := before[key]
+		if existed && sameValue(previous, value) {
+			continue
+		}
+		if existed {
+			oldValues[key] = previous
+		}
+		newValues[key] = value
+	}
+	for key, value := range before {
+		if _, kept := after[key]; !kept && !ignoredDiffKeys[key] {
+			oldValues[key] = value
+		}
+	}
+	return Mask(oldValues), Mask(newValues)
+}
+
+// sameValue compare deux valeurs par leur représentation JSON
+func sameValue(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// hashedEntry contenu d'une entrée couvert par l'empreinte (ordre des champs figé)
+type hashedEntry struct {
+	PrevHash     string `json:"prev_hash"`
+	CreatedAt    string `json:"created_at"`
+	UserID       *uint  `json:"user_id"`
+	OnBehalfOfID *uint  `json:"on_behalf_of_id"`
+	Action       string `json:"action"`
+	EntityType   string `json:"entity_type"`
+	EntityID     *uint  `json:"entity_id"`
+	OldValues    string `json:"old_values"`
+	NewValues    string `json:"new_values"`
+	IPAddress    string `json:"ip_address"`
+	UserAgent    string `json:"user_agent"`
+	Description  string `json:"description"`
+}
+
+// Hash calcule l'empreinte SHA-256 d'une entrée, chaînée à l'empreinte de l'entrée précédente (PrevHash)
+// Les valeurs JSON sont normalisées : la base peut réordonner les clés d'une colonne JSON
+func Hash(log *models.AuditLog) string {
+	content, _ := json.Marshal(hashedEntry{
+		PrevHash:     log.PrevHash,
+		CreatedAt:    log.CreatedAt.UTC().Format(time.RFC3339),
+		UserID:       log.UserID,
+		OnBehalfOfID: log.OnBehalfOfID,
+		Action:       log.Action,
+		EntityType:   log.EntityType,
+		EntityID:     log.EntityID,
+		OldValues:    canonicalJSON(log.OldValues),
+		NewValues:    canonicalJSON(log.NewValues),
+		IPAddress:    log.IPAddress,
+		UserAgent:    log.UserAgent,
+		Description:  log.Description,
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalJSON retourne une forme stable d'un document JSON (clés triées, sans espaces)
+func canonicalJSON(data []byte) string {
+	if len(data) == 0 || string(data) == "null" {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return string(data)
+	}
+	return string(canonical)
+}
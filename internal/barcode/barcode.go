@@ -0,0 +1,86 @@
+// Package barcode génère les QR codes imprimés sur les étiquettes (actifs) aux formats PNG et SVG
+package barcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Formats de sortie pris en charge
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Bornes de la taille (côté, en pixels) d'un QR code
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+// ErrUnsupportedFormat est retournée pour un format autre que PNG ou SVG
+var ErrUnsupportedFormat = errors.New("format de QR code non supporté (png ou svg)")
+
+// ContentType retourne le type MIME du format
+func ContentType(format string) string {
+	if format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// ClampSize ramène la taille demandée dans les bornes (défaut si 0)
+func ClampSize(size int) int {
+	switch {
+	case size <= 0:
+		return DefaultSize
+	case size < MinSize:
+		return MinSize
+	case size > MaxSize:
+		return MaxSize
+	}
+	return size
+}
+
+// QRCode encode le contenu au format demandé (correction d'erreur moyenne, lisible sur une étiquette abîmée)
+func QRCode(content, format string, size int) ([]byte, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	size = ClampSize(size)
+	switch format {
+	case FormatPNG:
+		return code.PNG(size)
+	case FormatSVG:
+		return svg(code.Bitmap(), size), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// svg dessine la matrice (marge incluse) en SVG vectoriel : un chemin par ligne de modules noirs consécutifs
+func svg(bitmap [][]bool, size int) []byte {
+	modules := len(bitmap)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, modules, modules)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
@@ -0,0 +1,115 @@
+// Package boardrank calcule des clés d'ordre par indexation fractionnaire pour les tableaux (Kanban)
+// Les clés sont des chaînes en base 36 (0-9a-z) comparées dans l'ordre lexicographique : insérer un élément entre
+// deux autres ne demande qu'une nouvelle clé, sans réécrire les clés voisines. Une clé ne se termine jamais par 0.
+package boardrank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits alphabet des clés (ordre ASCII identique à l'ordre des collations MySQL insensibles à la casse)
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// MaxLength longueur au-delà de laquelle il est conseillé de redistribuer les clés d'une colonne (Spread)
+const MaxLength = 200
+
+// ErrInvalidRange les bornes ne sont pas ordonnées ou ne sont pas des clés valides
+var ErrInvalidRange = errors.New("bornes de classement invalides")
+
+// Between retourne une clé strictement comprise entre before et after ; before vide = début de colonne,
+// after vide = fin de colonne
+func Between(before, after string) (string, error) {
+	if !valid(before) || !valid(after) || (after != "" && before >= after) {
+		return "", ErrInvalidRange
+	}
+	return midpoint(before, after), nil
+}
+
+// Spread retourne n clés croissantes régulièrement espacées (redistribution d'une colonne)
+func Spread(n int) []string {
+	keys := make([]string, n)
+	if n == 0 {
+		return keys
+	}
+	// Largeur suffisante pour laisser au moins 36 positions libres entre deux clés consécutives
+	width, space := 1, len(digits)
+	for space/(n+1) < len(digits) {
+		width++
+		space *= len(digits)
+	}
+	step := space / (n + 1)
+	for i := range keys {
+		keys[i] = strings.TrimRight(encode((i+1)*step, width), "0")
+	}
+	return keys
+}
+
+// midpoint clé entre a et b (b vide = +infini), a < b, sans zéro final
+func midpoint(a, b string) string {
+	if b != "" {
+		// Préfixe commun (a complété par des 0)
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+	// Chiffres consécutifs : le premier chiffre de b suffit s'il est suivi d'autres chiffres
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[digitA]) + midpoint(rest, "")
+}
+
+// digitAt chiffre de rang i de la clé, 0 au-delà de sa longueur
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return digits[0]
+}
+
+// valid indique si la clé ne contient que des chiffres de l'alphabet et ne se termine pas par 0
+func valid(key string) bool {
+	if strings.HasSuffix(key, "0") {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// encode écrit value en base 36 sur width chiffres
+func encode(value, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[value%len(digits)]
+		value /= len(digits)
+	}
+	return string(buf)
+}
@@ -0,0 +1,186 @@
+// Package businesstime calcule des échéances et des durées en temps ouvré (jours et heures de travail, jours fériés)
+// Un calendrier nil représente un fonctionnement 24h/24, 7j/7 : les calculs se font alors en temps réel
+package businesstime
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxScanDays borne le parcours jour par jour (un calendrier sans aucun créneau ouvré ne boucle pas indéfiniment)
+const maxScanDays = 3 * 366
+
+// Interval créneau ouvré d'une journée, en minutes depuis minuit (fin exclue)
+type Interval struct {
+	Start int
+	End   int
+}
+
+// Holiday jour férié ; Recurring le reconduit chaque année à la même date
+type Holiday struct {
+	Date      time.Time
+	Recurring bool
+}
+
+// Calendar calendrier ouvré dans un fuseau horaire
+type Calendar struct {
+	location  *time.Location
+	hours     [7][]Interval // Créneaux par jour de la semaine (time.Sunday = 0)
+	holidays  map[string]bool
+	recurring map[string]bool
+}
+
+// New crée un calendrier ; les créneaux de chaque jour sont triés
+func New(location *time.Location, hours map[time.Weekday][]Interval, holidays []Holiday) *Calendar {
+	if location == nil {
+		location = time.Local
+	}
+	calendar := &Calendar{
+		location:  location,
+		holidays:  map[string]bool{},
+		recurring: map[string]bool{},
+	}
+	for weekday, intervals := range hours {
+		day := append([]Interval(nil), intervals...)
+		sort.Slice(day, func(i, j int) bool { return day[i].Start < day[j].Start })
+		calendar.hours[weekday] = day
+	}
+	for _, holiday := range holidays {
+		if holiday.Recurring {
+			calendar.recurring[holiday.Date.Format("01-02")] = true
+		} else {
+			calendar.holidays[holiday.Date.Format("2006-01-02")] = true
+		}
+	}
+	return calendar
+}
+
+// ParseClock lit une heure HH:MM (24:00 accepté comme fin de journée) et retourne les minutes depuis minuit
+func ParseClock(value string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("heure invalide: %q (format HH:MM attendu)", value)
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("heure invalide: %q (format HH:MM attendu)", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// FormatClock formate des minutes depuis minuit en HH:MM
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// IsWorkingDay indique si la date (dans le fuseau du calendrier) comporte des heures ouvrées
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	return len(c.intervals(t.In(c.location))) > 0
+}
+
+// Add retourne l'instant atteint après d de temps ouvré à partir de start
+func (c *Calendar) Add(start time.Time, d time.Duration) time.Time {
+	if c == nil || !c.hasWorkingTime() {
+		return start.Add(d)
+	}
+	remaining := d
+	day := start.In(c.location)
+	for i := 0; i < maxScanDays; i++ {
+		dayStart := midnight(day)
+		for _, interval := range c.intervals(dayStart) {
+			from := maxTime(dayStart.Add(time.Duration(interval.Start)*time.Minute), start)
+			to := dayStart.Add(time.Duration(interval.End) * time.Minute)
+			if !from.Before(to) {
+				continue
+			}
+			available := to.Sub(from)
+			if remaining <= available {
+				return from.Add(max(remaining, 0))
+			}
+			remaining -= available
+		}
+		day = dayStart.AddDate(0, 0, 1)
+	}
+	return start.Add(d)
+}
+
+// AddDays retourne l'instant situé days jours ouvrés après start, à la même heure
+// Un départ hors des heures ouvrées part du prochain instant ouvré
+func (c *Calendar) AddDays(start time.Time, days int) time.Time {
+	if c == nil || !c.hasWorkingTime() {
+		return start.AddDate(0, 0, days)
+	}
+	cursor := c.Add(start, 0).In(c.location)
+	clock := cursor.Sub(midnight(cursor))
+	day := midnight(cursor)
+	for counted, i := 0, 0; counted < days && i < maxScanDays; i++ {
+		day = day.AddDate(0, 0, 1)
+		if len(c.intervals(day)) > 0 {
+			counted++
+		}
+	}
+	return day.Add(clock)
+}
+
+// Duration retourne le temps ouvré entre from et to (négatif si to précède from)
+func (c *Calendar) Duration(from, to time.Time) time.Duration {
+	if c == nil || !c.hasWorkingTime() {
+		return to.Sub(from)
+	}
+	if to.Before(from) {
+		return -c.Duration(to, from)
+	}
+	var total time.Duration
+	day := midnight(from.In(c.location))
+	for i := 0; i < maxScanDays && day.Before(to); i++ {
+		for _, interval := range c.intervals(day) {
+			start := maxTime(day.Add(time.Duration(interval.Start)*time.Minute), from)
+			end := minTime(day.Add(time.Duration(interval.End)*time.Minute), to)
+			if start.Before(end) {
+				total += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
+
+// intervals retourne les créneaux ouvrés d'une journée (aucun un jour férié)
+func (c *Calendar) intervals(day time.Time) []Interval {
+	if c.holidays[day.Format("2006-01-02")] || c.recurring[day.Format("01-02")] {
+		return nil
+	}
+	return c.hours[day.Weekday()]
+}
+
+// hasWorkingTime indique si au moins un jour de la semaine comporte des heures ouvrées
+func (c *Calendar) hasWorkingTime() bool {
+	for _, intervals := range c.hours {
+		if len(intervals) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// midnight retourne le début de la journée de t, dans son fuseau
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
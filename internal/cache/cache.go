@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// entry est une valeur mise en cache avec sa date d'expiration
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Cache est un cache clé/valeur en mémoire avec expiration (TTL)
+// Il est utilisé pour les données de référence lues à chaque requête (permissions des rôles,
+// utilisateurs authentifiés, catégories, paramètres) et invalidé explicitement lors des écritures
+type Cache struct {
+	mu         sync.RWMutex
+	items      map[string]entry
+	defaultTTL time.Duration
+	enabled    bool
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+}
+
+// Stats contient les statistiques d'utilisation du cache
+type Stats struct {
+	Enabled bool   `json:"enabled"`
+	Items   int    `json:"items"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+}
+
+// Shared est l'instance de cache partagée par les repositories
+var Shared = New(5 * time.Minute)
+
+// New crée un cache avec la durée de vie par défaut donnée
+func New(defaultTTL time.Duration) *Cache {
+	c := &Cache{
+		items:      make(map[string]entry),
+		defaultTTL: defaultTTL,
+		enabled:    true,
+	}
+	go c.cleanupLoop()
+	return c
+}
+
+// Init configure le cache partagé (activation et durée de vie par défaut)
+func Init(enabled bool, defaultTTL time.Duration) {
+	Shared.mu.Lock()
+	defer Shared.mu.Unlock()
+	Shared.enabled = enabled
+	if defaultTTL > 0 {
+		Shared.defaultTTL = defaultTTL
+	}
+	if !enabled {
+		Shared.items = make(map[string]entry)
+	}
+}
+
+// Get retourne la valeur associée à la clé si elle existe et n'a pas expiré
+func (c *Cache) Get(key string) (any, bool) {
+	c.mu.RLock()
+	e, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(e.expiresAt) {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return e.value, true
+}
+
+// Set enregistre une valeur avec la durée de vie par défaut
+func (c *Cache) Set(key string, value any) {
+	c.SetWithTTL(key, value, 0)
+}
+
+// SetWithTTL enregistre une valeur avec une durée de vie spécifique (0 = durée par défaut)
+func (c *Cache) SetWithTTL(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return
+	}
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+	c.items[key] = entry{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
+// Delete supprime une clé du cache
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// DeletePrefix supprime toutes les clés commençant par le préfixe donné (invalidation d'un groupe)
+func (c *Cache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.items, key)
+		}
+	}
+}
+
+// Flush vide entièrement le cache
+func (c *Cache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]entry)
+}
+
+// Stats retourne les statistiques d'utilisation du cache
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Stats{Enabled: c.enabled, Items: len(c.items), Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// cleanupLoop supprime périodiquement les entrées expirées
+func (c *Cache) cleanupLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		c.mu.Lock()
+		for key, e := range c.items {
+			if now.After(e.expiresAt) {
+				delete(c.items, key)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// GetOrLoad retourne la valeur en cache ou la charge via load puis la met en cache
+// Les erreurs de chargement ne sont pas mises en cache
+func GetOrLoad[T any](c *Cache, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if cached, ok := c.Get(key); ok {
+		if value, ok := cached.(T); ok {
+			return value, nil
+		}
+	}
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.SetWithTTL(key, value, ttl)
+	return value, nil
+}
@@ -0,0 +1,185 @@
+// Package calendarsync publie des événements dans les agendas Google et Microsoft 365 des utilisateurs
+// (autorisation OAuth 2.0 par utilisateur, création, mise à jour et suppression d'événements)
+package calendarsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Fournisseurs d'agenda
+const (
+	ProviderGoogle    = "google"
+	ProviderMicrosoft = "microsoft"
+)
+
+// requestTimeout borne la durée d'un appel à l'API d'un fournisseur
+const requestTimeout = 30 * time.Second
+
+// ErrNotFound l'événement n'existe plus dans l'agenda (supprimé par l'utilisateur)
+var ErrNotFound = errors.New("événement introuvable dans l'agenda")
+
+// ErrUnauthorized l'autorisation a été révoquée ou a expiré : l'utilisateur doit reconnecter son agenda
+var ErrUnauthorized = errors.New("autorisation de l'agenda révoquée ou expirée")
+
+// Token jetons OAuth d'un utilisateur
+type Token struct {
+	AccessToken  string
+	RefreshToken string // Peut être vide lors d'un renouvellement : conserver le précédent
+	Expiry       time.Time
+}
+
+// Event événement publié ; un événement « journée entière » n'utilise que la date de Start (et de End, incluse)
+type Event struct {
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+}
+
+// Provider fournisseur d'agenda ; eventID est l'identifiant de l'événement chez le fournisseur
+type Provider interface {
+	Name() string
+	// AuthURL retourne l'URL de consentement vers laquelle rediriger l'utilisateur
+	AuthURL(state, redirectURI string) string
+	Exchange(ctx context.Context, code, redirectURI string) (*Token, error)
+	Refresh(ctx context.Context, refreshToken string) (*Token, error)
+	// AccountEmail retourne l'adresse du compte autorisé
+	AccountEmail(ctx context.Context, accessToken string) (string, error)
+	// CreateEvent crée un événement dans l'agenda principal et retourne son identifiant
+	CreateEvent(ctx context.Context, accessToken string, event Event) (string, error)
+	// UpdateEvent remplace un événement ; ErrNotFound s'il a été supprimé
+	UpdateEvent(ctx context.Context, accessToken, eventID string, event Event) error
+	// DeleteEvent supprime un événement ; un événement déjà supprimé n'est pas une erreur
+	DeleteEvent(ctx context.Context, accessToken, eventID string) error
+}
+
+// oauthClient échange les codes et jetons auprès du serveur d'autorisation d'un fournisseur
+type oauthClient struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	http         *http.Client
+}
+
+// token appelle le point de terminaison des jetons (grant authorization_code ou refresh_token)
+func (c *oauthClient) token(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.clientID)
+	form.Set("client_secret", c.clientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("réponse du serveur d'autorisation illisible (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if body.Error == "invalid_grant" {
+		return nil, ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("serveur d'autorisation: réponse HTTP %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	return &Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// exchange échange un code d'autorisation
+func (c *oauthClient) exchange(ctx context.Context, code, redirectURI string) (*Token, error) {
+	return c.token(ctx, url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {redirectURI}})
+}
+
+// refresh renouvelle le jeton d'accès
+func (c *oauthClient) refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return c.token(ctx, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refreshToken}})
+}
+
+// apiClient appelle une API REST JSON authentifiée par jeton Bearer
+type apiClient struct {
+	name string
+	http *http.Client
+}
+
+// do envoie une requête JSON ; 404/410 retournent ErrNotFound et 401 ErrUnauthorized
+func (c *apiClient) do(ctx context.Context, method, endpoint, accessToken string, in, out any) error {
+	var reader io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		if len(body) > 512 {
+			body = body[:512]
+		}
+		return fmt.Errorf("API %s %s: réponse HTTP %d: %s", c.name, method, resp.StatusCode, bytes.TrimSpace(body))
+	}
+	if out != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("réponse %s illisible: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// allDayEnd retourne le lendemain du dernier jour d'un événement « journée entière » (fin exclusive)
+func allDayEnd(e Event) time.Time {
+	end := e.End
+	if end.IsZero() || end.Before(e.Start) {
+		end = e.Start
+	}
+	return end.AddDate(0, 0, 1)
+}
@@ -0,0 +1,126 @@
+package calendarsync
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Points de terminaison Google
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+	googleEventsURL   = "https://www.googleapis.com/calendar/v3/calendars/primary/events"
+	googleScopes      = "openid email https://www.googleapis.com/auth/calendar.events"
+)
+
+// Google agenda principal d'un compte Google (API Calendar v3)
+type Google struct {
+	oauth *oauthClient
+	api   *apiClient
+}
+
+// NewGoogle crée un client pour l'application OAuth Google indiquée
+func NewGoogle(clientID, clientSecret string) *Google {
+	client := &http.Client{Timeout: requestTimeout}
+	return &Google{
+		oauth: &oauthClient{tokenURL: googleTokenURL, clientID: clientID, clientSecret: clientSecret, http: client},
+		api:   &apiClient{name: "Google Calendar", http: client},
+	}
+}
+
+// Name retourne le nom du fournisseur
+func (g *Google) Name() string {
+	return ProviderGoogle
+}
+
+// AuthURL retourne l'URL de consentement (accès hors ligne pour obtenir un jeton de renouvellement)
+func (g *Google) AuthURL(state, redirectURI string) string {
+	params := url.Values{
+		"client_id":     {g.oauth.clientID},
+		"redirect_uri":  {redirectURI},
+		"response_type": {"code"},
+		"scope":         {googleScopes},
+		"access_type":   {"offline"},
+		"prompt":        {"consent"},
+		"state":         {state},
+	}
+	return googleAuthURL + "?" + params.Encode()
+}
+
+// Exchange échange un code d'autorisation
+func (g *Google) Exchange(ctx context.Context, code, redirectURI string) (*Token, error) {
+	return g.oauth.exchange(ctx, code, redirectURI)
+}
+
+// Refresh renouvelle le jeton d'accès
+func (g *Google) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return g.oauth.refresh(ctx, refreshToken)
+}
+
+// AccountEmail retourne l'adresse du compte autorisé
+func (g *Google) AccountEmail(ctx context.Context, accessToken string) (string, error) {
+	var info struct {
+		Email string `json:"email"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, googleUserInfoURL, accessToken, nil, &info); err != nil {
+		return "", err
+	}
+	return info.Email, nil
+}
+
+// googleEventTime début ou fin d'un événement Google
+type googleEventTime struct {
+	Date     string `json:"date,omitempty"`
+	DateTime string `json:"dateTime,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// googleEvent corps d'un événement Google
+type googleEvent struct {
+	Summary      string          `json:"summary"`
+	Description  string          `json:"description,omitempty"`
+	Start        googleEventTime `json:"start"`
+	End          googleEventTime `json:"end"`
+	Transparency string          `json:"transparency"`
+}
+
+// googleEventBody convertit un événement
+func googleEventBody(e Event) googleEvent {
+	body := googleEvent{Summary: e.Summary, Description: e.Description, Transparency: "transparent"}
+	if e.AllDay {
+		body.Start = googleEventTime{Date: e.Start.Format(time.DateOnly)}
+		body.End = googleEventTime{Date: allDayEnd(e).Format(time.DateOnly)}
+		return body
+	}
+	body.Start = googleEventTime{DateTime: e.Start.UTC().Format(time.RFC3339), TimeZone: "UTC"}
+	body.End = googleEventTime{DateTime: e.End.UTC().Format(time.RFC3339), TimeZone: "UTC"}
+	return body
+}
+
+// CreateEvent crée un événement dans l'agenda principal
+func (g *Google) CreateEvent(ctx context.Context, accessToken string, event Event) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := g.api.do(ctx, http.MethodPost, googleEventsURL, accessToken, googleEventBody(event), &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// UpdateEvent remplace un événement
+func (g *Google) UpdateEvent(ctx context.Context, accessToken, eventID string, event Event) error {
+	return g.api.do(ctx, http.MethodPut, googleEventsURL+"/"+url.PathEscape(eventID), accessToken, googleEventBody(event), nil)
+}
+
+// DeleteEvent supprime un événement
+func (g *Google) DeleteEvent(ctx context.Context, accessToken, eventID string) error {
+	err := g.api.do(ctx, http.MethodDelete, googleEventsURL+"/"+url.PathEscape(eventID), accessToken, nil, nil)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,156 @@
+package calendarsync
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Points de terminaison Microsoft (plateforme d'identité v2 et Graph)
+const (
+	microsoftLoginURL  = "https://login.microsoftonline.com/"
+	microsoftMeURL     = "https://graph.microsoft.com/v1.0/me"
+	microsoftEventsURL = "https://graph.microsoft.com/v1.0/me/events"
+	microsoftScopes    = "offline_access User.Read Calendars.ReadWrite"
+)
+
+// Microsoft agenda par défaut d'un compte Microsoft 365 ou Outlook.com (API Graph)
+type Microsoft struct {
+	tenant string
+	oauth  *oauthClient
+	api    *apiClient
+}
+
+// NewMicrosoft crée un client pour l'application Entra ID indiquée ; tenant vaut "common" pour une application multi-locataire
+func NewMicrosoft(tenant, clientID, clientSecret string) *Microsoft {
+	if tenant == "" {
+		tenant = "common"
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	return &Microsoft{
+		tenant: tenant,
+		oauth: &oauthClient{
+			tokenURL:     microsoftLoginURL + url.PathEscape(tenant) + "/oauth2/v2.0/token",
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			http:         client,
+		},
+		api: &apiClient{name: "Microsoft Graph", http: client},
+	}
+}
+
+// Name retourne le nom du fournisseur
+func (m *Microsoft) Name() string {
+	return ProviderMicrosoft
+}
+
+// AuthURL retourne l'URL de consentement
+func (m *Microsoft) AuthURL(state, redirectURI string) string {
+	params := url.Values{
+		"client_id":     {m.oauth.clientID},
+		"redirect_uri":  {redirectURI},
+		"response_type": {"code"},
+		"response_mode": {"query"},
+		"scope":         {microsoftScopes},
+		"state":         {state},
+	}
+	return microsoftLoginURL + url.PathEscape(m.tenant) + "/oauth2/v2.0/authorize?" + params.Encode()
+}
+
+// Exchange échange un code d'autorisation
+func (m *Microsoft) Exchange(ctx context.Context, code, redirectURI string) (*Token, error) {
+	return m.oauth.exchange(ctx, code, redirectURI)
+}
+
+// Refresh renouvelle le jeton d'accès
+func (m *Microsoft) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return m.oauth.refresh(ctx, refreshToken)
+}
+
+// AccountEmail retourne l'adresse du compte autorisé (ou son nom principal à défaut)
+func (m *Microsoft) AccountEmail(ctx context.Context, accessToken string) (string, error) {
+	var me struct {
+		Mail              string `json:"mail"`
+		UserPrincipalName string `json:"userPrincipalName"`
+	}
+	if err := m.api.do(ctx, http.MethodGet, microsoftMeURL, accessToken, nil, &me); err != nil {
+		return "", err
+	}
+	if me.Mail != "" {
+		return me.Mail, nil
+	}
+	return me.UserPrincipalName, nil
+}
+
+// microsoftEventTime début ou fin d'un événement Graph
+type microsoftEventTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// microsoftEventBody corps d'un événement Graph
+type microsoftEventBody struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+// microsoftEvent événement Graph
+type microsoftEvent struct {
+	Subject         string             `json:"subject"`
+	Body            microsoftEventBody `json:"body"`
+	Start           microsoftEventTime `json:"start"`
+	End             microsoftEventTime `json:"end"`
+	IsAllDay        bool               `json:"isAllDay"`
+	ShowAs          string             `json:"showAs"`
+	IsReminderOn    bool               `json:"isReminderOn"`
+	ReminderMinutes int                `json:"reminderMinutesBeforeStart,omitempty"`
+}
+
+// microsoftEventFrom convertit un événement (dates exprimées en UTC, minuit pour une journée entière)
+func microsoftEventFrom(e Event) microsoftEvent {
+	const layout = "2006-01-02T15:04:05"
+	body := microsoftEvent{
+		Subject: e.Summary,
+		Body:    microsoftEventBody{ContentType: "text", Content: e.Description},
+		ShowAs:  "free",
+	}
+	if e.AllDay {
+		start := time.Date(e.Start.Year(), e.Start.Month(), e.Start.Day(), 0, 0, 0, 0, time.UTC)
+		end := allDayEnd(e)
+		body.IsAllDay = true
+		body.Start = microsoftEventTime{DateTime: start.Format(layout), TimeZone: "UTC"}
+		body.End = microsoftEventTime{DateTime: time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC).Format(layout), TimeZone: "UTC"}
+		return body
+	}
+	body.IsReminderOn = true
+	body.ReminderMinutes = 15
+	body.Start = microsoftEventTime{DateTime: e.Start.UTC().Format(layout), TimeZone: "UTC"}
+	body.End = microsoftEventTime{DateTime: e.End.UTC().Format(layout), TimeZone: "UTC"}
+	return body
+}
+
+// CreateEvent crée un événement dans l'agenda par défaut
+func (m *Microsoft) CreateEvent(ctx context.Context, accessToken string, event Event) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := m.api.do(ctx, http.MethodPost, microsoftEventsURL, accessToken, microsoftEventFrom(event), &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// UpdateEvent met à jour un événement
+func (m *Microsoft) UpdateEvent(ctx context.Context, accessToken, eventID string, event Event) error {
+	return m.api.do(ctx, http.MethodPatch, microsoftEventsURL+"/"+url.PathEscape(eventID), accessToken, microsoftEventFrom(event), nil)
+}
+
+// DeleteEvent supprime un événement
+func (m *Microsoft) DeleteEvent(ctx context.Context, accessToken, eventID string) error {
+	err := m.api.do(ctx, http.MethodDelete, microsoftEventsURL+"/"+url.PathEscape(eventID), accessToken, nil, nil)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
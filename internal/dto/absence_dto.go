@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// AbsenceDTO représente une absence (congés, maladie, formation)
+type AbsenceDTO struct {
+	ID              uint       `json:"id"`
+	UserID          uint       `json:"user_id"`
+	User            *UserDTO   `json:"user,omitempty"`
+	Type            string     `json:"type"` // vacation, sick, training
+	StartDate       time.Time  `json:"start_date"`
+	EndDate         time.Time  `json:"end_date"`     // Dernier jour d'absence (inclus)
+	WorkingDays     int        `json:"working_days"` // Jours ouvrés couverts (lundi à vendredi)
+	Reason          string     `json:"reason,omitempty"`
+	Status          string     `json:"status"` // pending, approved, rejected, cancelled
+	DecidedBy       *UserDTO   `json:"decided_by,omitempty"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+	DecisionComment string     `json:"decision_comment,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateAbsenceRequest représente une demande d'absence sur des journées entières
+type CreateAbsenceRequest struct {
+	Type      string `json:"type" binding:"required,oneof=vacation sick training"` // Type d'absence (obligatoire)
+	StartDate string `json:"start_date" binding:"required"`                        // Premier jour, format YYYY-MM-DD (obligatoire)
+	EndDate   string `json:"end_date" binding:"required"`                          // Dernier jour inclus, format YYYY-MM-DD (obligatoire)
+	Reason    string `json:"reason,omitempty" binding:"max=500"`                   // Motif (optionnel)
+}
+
+// AbsenceDecisionRequest représente l'approbation ou le refus d'une absence (motif obligatoire pour un refus)
+type AbsenceDecisionRequest struct {
+	Comment string `json:"comment,omitempty" binding:"max=500"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+// AccessCheckDTO représente le diagnostic des droits d'un utilisateur sur un type de ressource
+type AccessCheckDTO struct {
+	User        AccessCheckUserDTO      `json:"user"`
+	Permissions []string                `json:"permissions"` // Permissions effectives du rôle
+	Scope       AccessCheckScopeDTO     `json:"scope"`
+	SQL         string                  `json:"sql"`                // Requête de liste générée avec le périmètre
+	Predicate   string                  `json:"predicate"`          // Clause WHERE appliquée
+	Resource    *AccessCheckResourceDTO `json:"resource,omitempty"` // Résultat pour une ressource précise (si id fourni)
+}
+
+// AccessCheckUserDTO représente l'utilisateur diagnostiqué et les attributs utilisés par le périmètre
+type AccessCheckUserDTO struct {
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	Role           string `json:"role"`
+	IsActive       bool   `json:"is_active"`
+	DepartmentID   *uint  `json:"department_id,omitempty"`
+	FilialeID      *uint  `json:"filiale_id,omitempty"` // Filiale effective (utilisateur, rôle ou département)
+	IsResolver     bool   `json:"is_resolver"`
+	DepartmentIsIT bool   `json:"department_is_it"`
+}
+
+// AccessCheckScopeDTO décrit la branche du périmètre appliquée
+type AccessCheckScopeDTO struct {
+	ResourceType    string   `json:"resource_type"`
+	FilialeRule     string   `json:"filiale_rule"` // filter, global, own_filiale, no_filiale, not_applied
+	Branch          string   `json:"branch"`       // Permission ayant déterminé le périmètre (vide = aucun résultat)
+	Description     string   `json:"description"`
+	ViewPermissions []string `json:"view_permissions"`
+	SharedIDs       []uint   `json:"shared_ids"`
+	Warnings        []string `json:"warnings"`
+}
+
+// AccessCheckResourceDTO représente l'accès à une ressource précise
+type AccessCheckResourceDTO struct {
+	ID           uint   `json:"id"`
+	Exists       bool   `json:"exists"`
+	Visible      bool   `json:"visible"`
+	VisibleVia   string `json:"visible_via,omitempty"`   // permissions ou share
+	SharedAccess string `json:"shared_access,omitempty"` // read ou write si un partage actif existe
+}
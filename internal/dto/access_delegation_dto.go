@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// AccessDelegationDTO représente une délégation d'absence
+type AccessDelegationDTO struct {
+	ID        uint       `json:"id"`
+	Delegator *UserDTO   `json:"delegator,omitempty"` // Utilisateur absent
+	Delegate  *UserDTO   `json:"delegate,omitempty"`  // Collègue qui agit pour son compte
+	Areas     []string   `json:"areas"`               // timesheet_validation, ticket_validation, project_approval
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    time.Time  `json:"ends_at"`
+	Reason    string     `json:"reason,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	IsActive  bool       `json:"is_active"` // En vigueur maintenant
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// AccessDelegationsDTO regroupe les délégations accordées et reçues par l'utilisateur connecté
+type AccessDelegationsDTO struct {
+	Given    []AccessDelegationDTO `json:"given"`
+	Received []AccessDelegationDTO `json:"received"`
+}
+
+// CreateAccessDelegationRequest représente la requête de création d'une délégation d'absence
+type CreateAccessDelegationRequest struct {
+	DelegateID uint      `json:"delegate_id" binding:"required"`                                                                    // Collègue délégataire (obligatoire)
+	Areas      []string  `json:"areas" binding:"required,min=1,dive,oneof=timesheet_validation ticket_validation project_approval"` // Domaines délégués (obligatoire)
+	StartsAt   time.Time `json:"starts_at" binding:"required"`                                                                      // Début (obligatoire)
+	EndsAt     time.Time `json:"ends_at" binding:"required"`                                                                        // Fin (obligatoire, après le début)
+	Reason     string    `json:"reason,omitempty" binding:"max=255"`                                                                // Motif (optionnel)
+}
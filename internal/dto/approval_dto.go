@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// ApprovalRequestDTO représente une demande d'approbation
+type ApprovalRequestDTO struct {
+	ID          uint                  `json:"id"`
+	EntityType  string                `json:"entity_type"` // service_request, change, budget_extension, weekly_declaration, asset_reservation, delay_justification
+	EntityID    uint                  `json:"entity_id"`
+	Title       string                `json:"title"`
+	Description string                `json:"description,omitempty"`
+	Status      string                `json:"status"`       // pending, approved, rejected, cancelled
+	CurrentStep int                   `json:"current_step"` // Étape en attente de décision
+	RequestedBy *UserDTO              `json:"requested_by,omitempty"`
+	FilialeID   *uint                 `json:"filiale_id,omitempty"`
+	DecidedAt   *time.Time            `json:"decided_at,omitempty"`
+	ApplyError  string                `json:"apply_error,omitempty"` // Échec de l'application de la décision au module (optionnel)
+	Approvers   []ApprovalApproverDTO `json:"approvers"`
+	Comments    []ApprovalCommentDTO  `json:"comments"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// ApprovalApproverDTO représente un approbateur et sa décision
+type ApprovalApproverDTO struct {
+	ID        uint       `json:"id"`
+	User      *UserDTO   `json:"user,omitempty"`
+	UserID    uint       `json:"user_id"`
+	StepOrder int        `json:"step_order"`
+	Status    string     `json:"status"` // waiting, pending, approved, rejected, skipped
+	Comment   string     `json:"comment,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+}
+
+// ApprovalCommentDTO représente un commentaire sur une demande d'approbation
+type ApprovalCommentDTO struct {
+	ID        uint      `json:"id"`
+	User      *UserDTO  `json:"user,omitempty"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ApproverRequest représente un approbateur à désigner
+type ApproverRequest struct {
+	UserID    uint `json:"user_id" binding:"required"`                     // ID de l'approbateur (obligatoire)
+	StepOrder int  `json:"step_order,omitempty" binding:"omitempty,min=1"` // Étape (optionnel, défaut: 1) ; les approbateurs d'une même étape décident en parallèle
+}
+
+// CreateApprovalRequest représente la requête de soumission d'un enregistrement à approbation
+type CreateApprovalRequest struct {
+	EntityType  string            `json:"entity_type" binding:"required,oneof=service_request change budget_extension weekly_declaration asset_reservation"` // Module (obligatoire)
+	EntityID    uint              `json:"entity_id" binding:"required"`                                                                                      // ID de l'enregistrement (obligatoire)
+	Description string            `json:"description,omitempty"`                                                                                             // Motif (optionnel)
+	Approvers   []ApproverRequest `json:"approvers" binding:"required,min=1,max=20,dive"`                                                                    // Approbateurs (obligatoire)
+}
+
+// ApprovalDecisionRequest représente la décision d'un approbateur
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment,omitempty"` // Commentaire (obligatoire pour un rejet)
+}
+
+// ApprovalCommentRequest représente l'ajout d'un commentaire à une demande d'approbation
+type ApprovalCommentRequest struct {
+	Comment string `json:"comment" binding:"required"` // Commentaire (obligatoire)
+}
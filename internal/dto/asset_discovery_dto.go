@@ -0,0 +1,104 @@
+package dto
+
+import "time"
+
+// DiscoveryAgentDTO représente un agent d'inventaire autorisé à remonter les actifs découverts
+type DiscoveryAgentDTO struct {
+	ID                  uint       `json:"id"`
+	Name                string     `json:"name"`
+	KeyPrefix           string     `json:"key_prefix"`
+	Key                 string     `json:"key,omitempty"` // Clé d'API, retournée uniquement à la création et au renouvellement
+	FilialeID           *uint      `json:"filiale_id,omitempty"`
+	FilialeName         string     `json:"filiale_name,omitempty"`
+	DefaultCategoryID   *uint      `json:"default_category_id,omitempty"`
+	DefaultCategoryName string     `json:"default_category_name,omitempty"`
+	AutoCreate          bool       `json:"auto_create"`
+	IsActive            bool       `json:"is_active"`
+	LastReportAt        *time.Time `json:"last_report_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// CreateDiscoveryAgentRequest représente la déclaration d'un agent d'inventaire
+type CreateDiscoveryAgentRequest struct {
+	Name              string `json:"name" binding:"required,max=100"`
+	FilialeID         *uint  `json:"filiale_id,omitempty"`          // Filiale des actifs créés (optionnel)
+	DefaultCategoryID *uint  `json:"default_category_id,omitempty"` // Catégorie des actifs créés sans catégorie reconnue (optionnel)
+	AutoCreate        *bool  `json:"auto_create,omitempty"`         // Créer les actifs inconnus (true par défaut)
+}
+
+// UpdateDiscoveryAgentRequest représente la mise à jour d'un agent d'inventaire
+type UpdateDiscoveryAgentRequest struct {
+	Name              *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	FilialeID         *uint   `json:"filiale_id,omitempty"`          // 0 pour retirer la filiale
+	DefaultCategoryID *uint   `json:"default_category_id,omitempty"` // 0 pour retirer la catégorie par défaut
+	AutoCreate        *bool   `json:"auto_create,omitempty"`
+	IsActive          *bool   `json:"is_active,omitempty"`
+}
+
+// DiscoveryReportRequest représente un inventaire remonté par un agent
+type DiscoveryReportRequest struct {
+	Assets []DiscoveredAssetRequest `json:"assets" binding:"required,min=1,max=500,dive"`
+}
+
+// DiscoveredAssetRequest représente un équipement découvert
+type DiscoveredAssetRequest struct {
+	Hostname     string                      `json:"hostname" binding:"required_without=SerialNumber,max=255"` // Nom de l'hôte (obligatoire sans numéro de série)
+	SerialNumber string                      `json:"serial_number,omitempty" binding:"max=100"`
+	Manufacturer string                      `json:"manufacturer,omitempty" binding:"max=255"`
+	Model        string                      `json:"model,omitempty" binding:"max=255"`
+	Category     string                      `json:"category,omitempty" binding:"max=100"`                 // Nom de la catégorie d'actif (à la création uniquement)
+	Software     []DiscoveredSoftwareRequest `json:"software,omitempty" binding:"omitempty,max=2000,dive"` // Inventaire logiciel complet ; omis : logiciels non rapprochés
+}
+
+// DiscoveredSoftwareRequest représente un logiciel découvert sur un équipement
+type DiscoveredSoftwareRequest struct {
+	Name    string `json:"name" binding:"required,max=255"`
+	Version string `json:"version,omitempty" binding:"max=100"`
+}
+
+// DiscoveryIngestResultDTO résultat du traitement d'un inventaire
+type DiscoveryIngestResultDTO struct {
+	Received  int                        `json:"received"`  // Équipements contenus dans l'inventaire
+	Created   int                        `json:"created"`   // Actifs créés
+	Updated   int                        `json:"updated"`   // Actifs complétés (champs vides, logiciels)
+	Unchanged int                        `json:"unchanged"` // Actifs déjà à jour
+	Skipped   int                        `json:"skipped"`   // Équipements inconnus non créés ou en erreur
+	Drifts    int                        `json:"drifts"`    // Écarts avec la CMDB constatés
+	Assets    []DiscoveredAssetResultDTO `json:"assets"`
+	Errors    []string                   `json:"errors,omitempty"`
+}
+
+// DiscoveredAssetResultDTO résultat du rapprochement d'un équipement découvert
+type DiscoveredAssetResultDTO struct {
+	Hostname     string `json:"hostname,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	AssetID      *uint  `json:"asset_id,omitempty"`
+	MatchedBy    string `json:"matched_by,omitempty"` // serial_number, name
+	Action       string `json:"action"`               // created, updated, unchanged, skipped
+	Drifts       int    `json:"drifts"`
+}
+
+// AssetDiscoveryDriftDTO représente un écart entre la CMDB et l'inventaire d'un agent
+type AssetDiscoveryDriftDTO struct {
+	ID              uint       `json:"id"`
+	AssetID         uint       `json:"asset_id"`
+	AssetName       string     `json:"asset_name,omitempty"`
+	AgentID         *uint      `json:"agent_id,omitempty"`
+	AgentName       string     `json:"agent_name,omitempty"`
+	Field           string     `json:"field"`             // name, serial_number, manufacturer, model, software
+	Subject         string     `json:"subject,omitempty"` // Logiciel concerné (champ software)
+	CMDBValue       string     `json:"cmdb_value"`
+	DiscoveredValue string     `json:"discovered_value"`
+	Status          string     `json:"status"` // open, accepted, dismissed, cleared
+	Occurrences     int        `json:"occurrences"`
+	DetectedAt      time.Time  `json:"detected_at"`
+	LastSeenAt      time.Time  `json:"last_seen_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy      *UserDTO   `json:"resolved_by,omitempty"`
+}
+
+// AssetDiscoveryDriftListResponse représente la liste paginée des écarts de découverte
+type AssetDiscoveryDriftListResponse struct {
+	Drifts     []AssetDiscoveryDriftDTO `json:"drifts"`
+	Pagination PaginationDTO            `json:"pagination"`
+}
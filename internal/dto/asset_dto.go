@@ -6,6 +6,7 @@ import "time"
 type AssetDTO struct {
 	ID             uint              `json:"id"`
 	Name           string            `json:"name"`
+	Code           string            `json:"code,omitempty"` // Étiquette d'inventaire (encodée dans le QR code)
 	SerialNumber   string            `json:"serial_number,omitempty"`
 	Model          string            `json:"model,omitempty"`
 	Manufacturer   string            `json:"manufacturer,omitempty"`
@@ -13,11 +14,24 @@ type AssetDTO struct {
 	Category       *AssetCategoryDTO `json:"category,omitempty"`      // Catégorie (optionnel)
 	AssignedTo     *uint             `json:"assigned_to,omitempty"`   // ID utilisateur assigné (optionnel)
 	AssignedUser   *UserDTO          `json:"assigned_user,omitempty"` // Utilisateur assigné (optionnel)
-	Status         string            `json:"status"`                  // available, in_use, maintenance, retired
+	Status         string            `json:"status"`                  // ordered, in_stock, assigned, in_repair, retired, disposed
 	PurchaseDate   *time.Time        `json:"purchase_date,omitempty"`
 	WarrantyExpiry *time.Time        `json:"warranty_expiry,omitempty"`
 	Location       string            `json:"location,omitempty"`
 	Notes          string            `json:"notes,omitempty"`
+	Supplier           string     `json:"supplier,omitempty"`
+	ContractReference  string     `json:"contract_reference,omitempty"`
+	ContractEndDate    *time.Time `json:"contract_end_date,omitempty"`
+	PurchaseCost       *float64   `json:"purchase_cost,omitempty"`
+	SalvageValue       *float64   `json:"salvage_value,omitempty"`
+	DepreciationMonths *int       `json:"depreciation_months,omitempty"`
+	DepreciationMethod string     `json:"depreciation_method,omitempty"` // straight_line, declining_balance
+	InServiceDate      *time.Time `json:"in_service_date,omitempty"`
+	BookValue          *float64   `json:"book_value,omitempty"` // Valeur nette comptable à date (si les données d'amortissement sont renseignées)
+	RetiredAt          *time.Time `json:"retired_at,omitempty"`
+	DisposedAt         *time.Time `json:"disposed_at,omitempty"`
+	LastDiscoveredAt   *time.Time `json:"last_discovered_at,omitempty"` // Dernier inventaire remonté par un agent de découverte
+	Loanable           bool       `json:"loanable"`                     // Équipement du parc de prêt (réservable)
 	CreatedAt      time.Time         `json:"created_at"`
 	UpdatedAt      time.Time         `json:"updated_at"`
 }
@@ -33,31 +47,100 @@ type AssetCategoryDTO struct {
 // CreateAssetRequest représente la requête de création d'un actif
 type CreateAssetRequest struct {
 	Name           string  `json:"name" binding:"required"`                                                         // Nom (obligatoire)
+	Code           string  `json:"code,omitempty" binding:"omitempty,max=50"`                                       // Étiquette d'inventaire (optionnel, défaut: AST-NNNNNN)
 	SerialNumber   string  `json:"serial_number,omitempty"`                                                         // Numéro de série (optionnel)
 	Model          string  `json:"model,omitempty"`                                                                 // Modèle (optionnel)
 	Manufacturer   string  `json:"manufacturer,omitempty"`                                                          // Fabricant (optionnel)
 	CategoryID     uint    `json:"category_id" binding:"required"`                                                  // ID catégorie (obligatoire)
 	AssignedTo     *uint   `json:"assigned_to,omitempty"`                                                           // ID utilisateur (optionnel)
-	Status         string  `json:"status,omitempty" binding:"omitempty,oneof=available in_use maintenance retired"` // Statut (optionnel)
+	Status         string  `json:"status,omitempty" binding:"omitempty,oneof=ordered in_stock assigned in_repair retired disposed available in_use maintenance"` // Statut initial (optionnel, défaut: in_stock, ou assigned si un utilisateur est fourni ; anciens statuts acceptés)
 	PurchaseDate   *string `json:"purchase_date,omitempty"`                                                         // Date d'achat format "2006-01-02" (optionnel)
 	WarrantyExpiry *string `json:"warranty_expiry,omitempty"`                                                       // Date expiration garantie format "2006-01-02" (optionnel)
 	Location       string  `json:"location,omitempty"`                                                              // Localisation (optionnel)
 	Notes          string  `json:"notes,omitempty"`                                                                 // Notes (optionnel)
+	Loanable       bool    `json:"loanable,omitempty"`                                                              // Équipement du parc de prêt (optionnel)
+	AssetFinancialFields
+}
+
+// AssetFinancialFields regroupe les champs contrat et amortissement communs à la création et à la mise à jour d'un actif
+type AssetFinancialFields struct {
+	Supplier           string   `json:"supplier,omitempty"`                                                                // Fournisseur (optionnel)
+	ContractReference  string   `json:"contract_reference,omitempty"`                                                      // Référence du contrat de maintenance / location (optionnel)
+	ContractEndDate    *string  `json:"contract_end_date,omitempty"`                                                       // Fin du contrat format "2006-01-02" (optionnel)
+	PurchaseCost       *float64 `json:"purchase_cost,omitempty" binding:"omitempty,gte=0"`                                 // Coût d'achat (optionnel)
+	SalvageValue       *float64 `json:"salvage_value,omitempty" binding:"omitempty,gte=0"`                                 // Valeur résiduelle (optionnel, défaut: 0)
+	DepreciationMonths *int     `json:"depreciation_months,omitempty" binding:"omitempty,min=1,max=600"`                   // Durée d'amortissement en mois (optionnel)
+	DepreciationMethod string   `json:"depreciation_method,omitempty" binding:"omitempty,oneof=straight_line declining_balance"` // Méthode (optionnel, défaut: straight_line)
+	InServiceDate      *string  `json:"in_service_date,omitempty"`                                                         // Mise en service format "2006-01-02" (optionnel, défaut: date d'achat)
 }
 
 // UpdateAssetRequest représente la requête de mise à jour d'un actif
 type UpdateAssetRequest struct {
 	Name           string  `json:"name,omitempty"`
+	Code           string  `json:"code,omitempty" binding:"omitempty,max=50"` // Nouvelle étiquette d'inventaire (réétiquetage)
 	SerialNumber   string  `json:"serial_number,omitempty"`
 	Model          string  `json:"model,omitempty"`
 	Manufacturer   string  `json:"manufacturer,omitempty"`
 	CategoryID     *uint   `json:"category_id,omitempty"`
 	AssignedTo     *uint   `json:"assigned_to,omitempty"` // nil pour retirer l'assignation
-	Status         string  `json:"status,omitempty" binding:"omitempty,oneof=available in_use maintenance retired"`
+	Status         string  `json:"status,omitempty" binding:"omitempty,oneof=ordered in_stock assigned in_repair retired disposed available in_use maintenance"` // Changement de statut soumis aux transitions du cycle de vie
 	PurchaseDate   *string `json:"purchase_date,omitempty"`
 	WarrantyExpiry *string `json:"warranty_expiry,omitempty"`
 	Location       string  `json:"location,omitempty"`
 	Notes          string  `json:"notes,omitempty"`
+	Loanable       *bool   `json:"loanable,omitempty"` // Ajout ou retrait du parc de prêt
+	AssetFinancialFields
+}
+
+// AssetTransitionRequest représente une transition du cycle de vie d'un actif
+type AssetTransitionRequest struct {
+	Status  string `json:"status" binding:"required,oneof=ordered in_stock assigned in_repair retired disposed"` // Statut cible (obligatoire)
+	UserID  *uint  `json:"user_id,omitempty"`                                                                   // Utilisateur affecté (obligatoire vers assigned, sauf retour de réparation)
+	Comment string `json:"comment,omitempty"`                                                                   // Motif (optionnel)
+}
+
+// AssetLifecycleEventDTO représente une transition de l'historique du cycle de vie d'un actif
+type AssetLifecycleEventDTO struct {
+	ID          uint      `json:"id"`
+	FromStatus  string    `json:"from_status,omitempty"` // Vide pour la création de l'actif
+	ToStatus    string    `json:"to_status"`
+	AssignedTo  *UserDTO  `json:"assigned_to,omitempty"`
+	Comment     string    `json:"comment,omitempty"`
+	PerformedBy *UserDTO  `json:"performed_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AssetLifecycleDTO représente le cycle de vie d'un actif : statut courant, transitions possibles et historique
+type AssetLifecycleDTO struct {
+	AssetID            uint                     `json:"asset_id"`
+	Status             string                   `json:"status"`
+	AllowedTransitions []string                 `json:"allowed_transitions"`
+	History            []AssetLifecycleEventDTO `json:"history"`
+}
+
+// AssetDepreciationDTO représente le calcul d'amortissement d'un actif à une date
+type AssetDepreciationDTO struct {
+	AssetID                 uint                         `json:"asset_id"`
+	Method                  string                       `json:"method"` // straight_line, declining_balance
+	PurchaseCost            float64                      `json:"purchase_cost"`
+	SalvageValue            float64                      `json:"salvage_value"`
+	DepreciationMonths      int                          `json:"depreciation_months"`
+	StartDate               time.Time                    `json:"start_date"` // Mise en service ou date d'achat
+	AsOf                    time.Time                    `json:"as_of"`
+	MonthsElapsed           int                          `json:"months_elapsed"`
+	AccumulatedDepreciation float64                      `json:"accumulated_depreciation"`
+	BookValue               float64                      `json:"book_value"` // Valeur nette comptable
+	FullyDepreciated        bool                         `json:"fully_depreciated"`
+	Schedule                []AssetDepreciationPeriodDTO `json:"schedule"` // Plan d'amortissement par année civile
+}
+
+// AssetDepreciationPeriodDTO représente une année du plan d'amortissement
+type AssetDepreciationPeriodDTO struct {
+	Year         int     `json:"year"`
+	Months       int     `json:"months"` // Mois amortis dans l'année
+	OpeningValue float64 `json:"opening_value"`
+	Depreciation float64 `json:"depreciation"`
+	ClosingValue float64 `json:"closing_value"`
 }
 
 // AssignAssetRequest représente la requête d'assignation d'un actif à un utilisateur
@@ -71,7 +154,7 @@ type AssetInventoryDTO struct {
 	ByStatus   map[string]int `json:"by_status"`   // Répartition par statut
 	ByCategory map[string]int `json:"by_category"` // Répartition par catégorie
 	Assigned   int            `json:"assigned"`    // Nombre d'actifs assignés
-	Available  int            `json:"available"`   // Nombre d'actifs disponibles
+	Available  int            `json:"available"`   // Nombre d'actifs en stock
 }
 
 // CreateAssetCategoryRequest représente la requête de création d'une catégorie d'actif
@@ -0,0 +1,54 @@
+package dto
+
+// Actions d'une ligne du rapport d'import d'actifs
+const (
+	AssetImportActionCreate    = "create"    // Nouvel actif
+	AssetImportActionUpdate    = "update"    // Actif existant (même étiquette ou même numéro de série) modifié
+	AssetImportActionUnchanged = "unchanged" // Actif existant identique au fichier
+)
+
+// Statuts d'une ligne du rapport d'import d'actifs
+const (
+	AssetImportRowValid   = "valid"   // Ligne valide (simulation, ou import annulé à cause d'autres lignes)
+	AssetImportRowApplied = "applied" // Création ou mise à jour enregistrée
+	AssetImportRowError   = "error"   // Ligne rejetée
+)
+
+// AssetImportOptions représente les options d'un import d'actifs (champs du formulaire multipart)
+type AssetImportOptions struct {
+	Mapping map[string]string `json:"mapping,omitempty"` // Champ actif -> en-tête de colonne (optionnel, en-têtes usuels reconnus par défaut)
+	DryRun  bool              `json:"dry_run"`           // Simulation : différences calculées, aucun actif enregistré
+}
+
+// AssetImportChangeDTO représente la modification d'un champ par l'import
+type AssetImportChangeDTO struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"` // Valeur actuelle (vide à la création)
+	To    string `json:"to,omitempty"`   // Valeur importée
+}
+
+// AssetImportRowDTO représente le résultat de l'import d'une ligne du fichier
+type AssetImportRowDTO struct {
+	Row     int                    `json:"row"` // Numéro de ligne dans le fichier (l'en-tête est la ligne 1)
+	Name    string                 `json:"name,omitempty"`
+	Code    string                 `json:"code,omitempty"`
+	Action  string                 `json:"action,omitempty"` // create, update, unchanged
+	Status  string                 `json:"status"`           // valid, applied, error
+	Errors  []string               `json:"errors,omitempty"` // Motifs de rejet
+	AssetID *uint                  `json:"asset_id,omitempty"`
+	Changes []AssetImportChangeDTO `json:"changes,omitempty"` // Différences avec l'actif existant (ou valeurs de l'actif créé)
+}
+
+// AssetImportReportDTO représente le rapport d'un import d'actifs
+// L'import est transactionnel : une seule ligne en erreur annule l'enregistrement de toutes les lignes
+type AssetImportReportDTO struct {
+	DryRun    bool                `json:"dry_run"`
+	Committed bool                `json:"committed"` // Modifications enregistrées
+	Columns   map[string]string   `json:"columns"`   // Correspondance retenue : champ -> en-tête
+	Total     int                 `json:"total"`
+	Created   int                 `json:"created"`   // Actifs créés (ou à créer)
+	Updated   int                 `json:"updated"`   // Actifs modifiés (ou à modifier)
+	Unchanged int                 `json:"unchanged"` // Actifs existants sans différence
+	Failed    int                 `json:"failed"`
+	Rows      []AssetImportRowDTO `json:"rows"`
+}
@@ -0,0 +1,89 @@
+package dto
+
+import "time"
+
+// CreateAssetRelationRequest représente la déclaration d'une dépendance sur un actif
+// Avec target_asset_id, l'actif dépend de la cible ; avec software_id, le logiciel (service) dépend de l'actif
+type CreateAssetRelationRequest struct {
+	Type          string `json:"type" binding:"required,oneof=runs_on connects_to depends_on"`
+	TargetAssetID *uint  `json:"target_asset_id,omitempty" binding:"required_without=SoftwareID"` // Actif dont dépend cet actif
+	SoftwareID    *uint  `json:"software_id,omitempty" binding:"required_without=TargetAssetID"`  // Logiciel qui s'exécute sur / dépend de cet actif (runs_on, depends_on)
+	Notes         string `json:"notes,omitempty" binding:"max=500"`
+}
+
+// AssetNodeDTO représente un actif dans les relations et l'analyse d'impact
+type AssetNodeDTO struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Code         string `json:"code,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	Status       string `json:"status"`
+	CategoryName string `json:"category_name,omitempty"`
+	FilialeID    *uint  `json:"filiale_id,omitempty"`
+}
+
+// ServiceNodeDTO représente un logiciel du catalogue (service) dans les relations et l'analyse d'impact
+type ServiceNodeDTO struct {
+	ID      uint   `json:"id"`
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// AssetRelationDTO représente une dépendance de la CMDB
+type AssetRelationDTO struct {
+	ID             uint            `json:"id"`
+	Type           string          `json:"type"`      // runs_on, connects_to, depends_on
+	Direction      string          `json:"direction"` // outgoing : l'actif consulté dépend de la cible ; incoming : la source dépend de l'actif consulté
+	SourceAsset    *AssetNodeDTO   `json:"source_asset,omitempty"`
+	SourceSoftware *ServiceNodeDTO `json:"source_software,omitempty"`
+	TargetAsset    *AssetNodeDTO   `json:"target_asset,omitempty"`
+	Notes          string          `json:"notes,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AssetImpactDTO représente l'analyse d'impact d'une indisponibilité de l'actif
+type AssetImpactDTO struct {
+	Asset     AssetNodeDTO          `json:"asset"`
+	Depth     int                   `json:"depth"`     // Profondeur maximale parcourue
+	Truncated bool                  `json:"truncated"` // Des dépendances existent au-delà de la profondeur parcourue
+	Assets    []ImpactedAssetDTO    `json:"assets"`    // Actifs impactés (hors actif analysé)
+	Services  []ImpactedServiceDTO  `json:"services"`  // Logiciels impactés
+	Tickets   []ImpactedTicketDTO   `json:"tickets"`   // Tickets ouverts sur l'actif, les actifs et les logiciels impactés
+	Summary   AssetImpactSummaryDTO `json:"summary"`
+}
+
+// ImpactedAssetDTO représente un actif impacté et le chemin qui y mène
+type ImpactedAssetDTO struct {
+	AssetNodeDTO
+	Depth        int    `json:"depth"`         // 1 : dépend directement de l'actif analysé
+	RelationType string `json:"relation_type"` // Relation par laquelle l'impact se propage
+	ViaAssetID   uint   `json:"via_asset_id"`  // Actif impacté dont il dépend
+}
+
+// ImpactedServiceDTO représente un logiciel impacté
+type ImpactedServiceDTO struct {
+	ServiceNodeDTO
+	Depth        int    `json:"depth"`
+	RelationType string `json:"relation_type"`
+	ViaAssetID   uint   `json:"via_asset_id"`
+}
+
+// ImpactedTicketDTO représente un ticket ouvert concerné par l'indisponibilité
+type ImpactedTicketDTO struct {
+	ID         uint      `json:"id"`
+	Code       string    `json:"code"`
+	Title      string    `json:"title"`
+	Category   string    `json:"category"`
+	Status     string    `json:"status"`
+	Priority   string    `json:"priority"`
+	SoftwareID *uint     `json:"software_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AssetImpactSummaryDTO résume l'analyse d'impact
+type AssetImpactSummaryDTO struct {
+	Assets   int `json:"assets"`
+	Services int `json:"services"`
+	Tickets  int `json:"tickets"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// AssetReservationDTO représente une réservation d'équipement du parc de prêt
+type AssetReservationDTO struct {
+	ID              uint          `json:"id"`
+	AssetID         uint          `json:"asset_id"`
+	Asset           *AssetNodeDTO `json:"asset,omitempty"`
+	RequesterID     uint          `json:"requester_id"`
+	Requester       *UserDTO      `json:"requester,omitempty"`
+	StartAt         time.Time     `json:"start_at"`
+	EndAt           time.Time     `json:"end_at"` // Date de retour prévue
+	Purpose         string        `json:"purpose,omitempty"`
+	Status          string        `json:"status"`  // pending, approved, rejected, cancelled, checked_out, returned
+	Overdue         bool          `json:"overdue"` // Prêt en cours dont la date de retour est dépassée
+	DecidedBy       *UserDTO      `json:"decided_by,omitempty"`
+	DecidedAt       *time.Time    `json:"decided_at,omitempty"`
+	DecisionComment string        `json:"decision_comment,omitempty"`
+	CheckedOutAt    *time.Time    `json:"checked_out_at,omitempty"`
+	ReturnedAt      *time.Time    `json:"returned_at,omitempty"`
+	ReturnNotes     string        `json:"return_notes,omitempty"`
+	CreatedByID     uint          `json:"created_by_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// CreateAssetReservationRequest représente une demande de réservation d'équipement
+type CreateAssetReservationRequest struct {
+	AssetID     uint      `json:"asset_id" binding:"required"`               // Équipement du parc de prêt (obligatoire)
+	StartAt     time.Time `json:"start_at" binding:"required"`               // Début du prêt (obligatoire)
+	EndAt       time.Time `json:"end_at" binding:"required,gtfield=StartAt"` // Retour prévu (obligatoire)
+	Purpose     string    `json:"purpose,omitempty" binding:"max=500"`       // Motif (optionnel)
+	RequesterID *uint     `json:"requester_id,omitempty"`                    // Emprunteur (gestionnaires du parc uniquement, défaut: soi-même)
+}
+
+// AssetReservationDecisionRequest représente l'approbation ou le refus d'une réservation
+type AssetReservationDecisionRequest struct {
+	Comment string `json:"comment,omitempty" binding:"max=500"`
+}
+
+// AssetReservationReturnRequest représente la restitution d'un équipement prêté
+type AssetReservationReturnRequest struct {
+	Notes string `json:"notes,omitempty" binding:"max=500"` // État constaté au retour (optionnel)
+}
+
+// AssetReservationSlotDTO représente un créneau occupé dans le calendrier de disponibilité
+type AssetReservationSlotDTO struct {
+	ReservationID uint      `json:"reservation_id"`
+	StartAt       time.Time `json:"start_at"`
+	EndAt         time.Time `json:"end_at"`
+	Status        string    `json:"status"` // pending, approved, checked_out
+	RequesterID   uint      `json:"requester_id"`
+}
+
+// LoanableAssetAvailabilityDTO représente la disponibilité d'un équipement du parc de prêt sur la période
+type LoanableAssetAvailabilityDTO struct {
+	Asset     AssetNodeDTO              `json:"asset"`
+	Available bool                      `json:"available"` // Aucun créneau occupé sur la période et équipement en stock
+	Slots     []AssetReservationSlotDTO `json:"slots"`
+}
+
+// AssetReservationCalendarDTO représente le calendrier de disponibilité du parc de prêt
+type AssetReservationCalendarDTO struct {
+	From   time.Time                      `json:"from"`
+	To     time.Time                      `json:"to"`
+	Assets []LoanableAssetAvailabilityDTO `json:"assets"`
+}
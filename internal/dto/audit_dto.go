@@ -9,6 +9,8 @@ type AuditLogDTO struct {
 	ID          uint                   `json:"id"`
 	UserID      *uint                  `json:"user_id,omitempty"`
 	User        *UserDTO               `json:"user,omitempty"`
+	OnBehalfOfID *uint                 `json:"on_behalf_of_id,omitempty"` // Délégant si l'action a été faite par délégation
+	OnBehalfOf   *UserDTO              `json:"on_behalf_of,omitempty"`
 	Action      string                 `json:"action"`
 	EntityType  string                 `json:"entity_type"`
 	EntityID    *uint                  `json:"entity_id,omitempty"`
@@ -18,6 +20,7 @@ type AuditLogDTO struct {
 	UserAgent   string                 `json:"user_agent,omitempty"`
 	Description string                 `json:"description,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
+	Hash        string                 `json:"hash,omitempty"` // Empreinte chaînée de l'entrée
 }
 
 // AuditLogListResponse représente la réponse de liste de logs d'audit avec pagination
@@ -26,3 +29,26 @@ type AuditLogListResponse struct {
 	Pagination PaginationDTO  `json:"pagination"`
 }
 
+
+// AuditChainVerificationDTO représente le résultat de la vérification d'intégrité du journal d'audit
+type AuditChainVerificationDTO struct {
+	Valid          bool      `json:"valid"`                      // Aucune altération détectée
+	Checked        int       `json:"checked"`                    // Nombre d'entrées chaînées vérifiées
+	Unchained      int       `json:"unchained"`                  // Entrées antérieures au chaînage (non vérifiables)
+	FirstID        uint      `json:"first_id,omitempty"`         // Première entrée chaînée
+	LastID         uint      `json:"last_id,omitempty"`          // Dernière entrée vérifiée
+	LastHash       string    `json:"last_hash,omitempty"`        // Empreinte de la dernière entrée (à conserver hors de la base comme point d'ancrage)
+	FirstInvalidID *uint     `json:"first_invalid_id,omitempty"` // Première entrée altérée, supprimée ou insérée
+	Reason         string    `json:"reason,omitempty"`           // Nature de l'anomalie
+	VerifiedAt     time.Time `json:"verified_at"`
+}
+
+// AuditLogExportFilter représente les critères d'export des logs d'audit
+type AuditLogExportFilter struct {
+	From       *time.Time // Début de période (inclus)
+	To         *time.Time // Fin de période (exclue)
+	UserID     *uint
+	Action     string
+	EntityType string
+	EntityID   *uint
+}
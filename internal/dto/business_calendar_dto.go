@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// BusinessCalendarDTO représente un calendrier ouvré dans les réponses API
+type BusinessCalendarDTO struct {
+	ID           uint                 `json:"id"`
+	Name         string               `json:"name"`
+	FilialeID    *uint                `json:"filiale_id,omitempty"` // Absent = calendrier par défaut
+	Filiale      *FilialeDTO          `json:"filiale,omitempty"`
+	Timezone     string               `json:"timezone,omitempty"` // Fuseau IANA (vide = fuseau de la filiale)
+	WorkingHours []WorkingHoursDTO    `json:"working_hours"`
+	Holidays     []BusinessHolidayDTO `json:"holidays"`
+	IsActive     bool                 `json:"is_active"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// WorkingHoursDTO représente un créneau ouvré d'un jour de la semaine
+type WorkingHoursDTO struct {
+	Weekday int    `json:"weekday" binding:"min=0,max=6"` // 0 = dimanche, 1 = lundi, ..., 6 = samedi
+	Start   string `json:"start" binding:"required"`      // HH:MM
+	End     string `json:"end" binding:"required"`        // HH:MM (24:00 accepté)
+}
+
+// BusinessHolidayDTO représente un jour férié
+type BusinessHolidayDTO struct {
+	ID        uint   `json:"id"`
+	Date      string `json:"date"` // YYYY-MM-DD
+	Name      string `json:"name"`
+	Recurring bool   `json:"recurring"` // Reconduit chaque année à la même date
+}
+
+// CreateBusinessCalendarRequest représente la requête de création d'un calendrier ouvré
+type CreateBusinessCalendarRequest struct {
+	Name         string                         `json:"name" binding:"required"`               // Nom (obligatoire)
+	FilialeID    *uint                          `json:"filiale_id,omitempty"`                  // Filiale (optionnel, absent = calendrier par défaut)
+	Timezone     string                         `json:"timezone,omitempty"`                    // Fuseau IANA (optionnel)
+	WorkingHours []WorkingHoursDTO              `json:"working_hours" binding:"required,dive"` // Créneaux ouvrés (obligatoire)
+	Holidays     []CreateBusinessHolidayRequest `json:"holidays,omitempty" binding:"dive"`     // Jours fériés (optionnel)
+	IsActive     *bool                          `json:"is_active,omitempty"`                   // Statut actif (optionnel, défaut: true)
+}
+
+// UpdateBusinessCalendarRequest représente la requête de mise à jour d'un calendrier ouvré
+type UpdateBusinessCalendarRequest struct {
+	Name         *string            `json:"name,omitempty"`
+	Timezone     *string            `json:"timezone,omitempty"`
+	WorkingHours *[]WorkingHoursDTO `json:"working_hours,omitempty"` // Remplace tous les créneaux
+	IsActive     *bool              `json:"is_active,omitempty"`
+}
+
+// CreateBusinessHolidayRequest représente la requête d'ajout d'un jour férié
+type CreateBusinessHolidayRequest struct {
+	Date      string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name      string `json:"name" binding:"required"`
+	Recurring bool   `json:"recurring,omitempty"` // Reconduit chaque année (optionnel)
+}
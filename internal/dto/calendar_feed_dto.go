@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// CreateCalendarFeedRequest représente la création d'un flux de calendrier ICS
+type CreateCalendarFeedRequest struct {
+	Name  string `json:"name,omitempty" binding:"omitempty,max=100"`                // Nom affiché dans le client de calendrier (optionnel)
+	Scope string `json:"scope,omitempty" binding:"omitempty,oneof=user department"` // user (défaut) ou department (équipe)
+}
+
+// CalendarFeedDTO représente un flux de calendrier ICS
+type CalendarFeedDTO struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Scope         string     `json:"scope"`
+	URL           string     `json:"url,omitempty"` // URL d'abonnement, retournée uniquement à la création
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// ConnectCalendarRequest représente le début de la connexion d'un agenda
+type ConnectCalendarRequest struct {
+	Provider string `json:"provider" binding:"required,oneof=google microsoft"`
+}
+
+// CalendarAuthorizationDTO URL de consentement vers laquelle rediriger l'utilisateur
+type CalendarAuthorizationDTO struct {
+	Provider         string `json:"provider"`
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// CalendarProviderDTO fournisseur d'agenda et disponibilité sur cette instance
+type CalendarProviderDTO struct {
+	Provider string `json:"provider"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// CalendarConnectionDTO représente un agenda connecté
+type CalendarConnectionDTO struct {
+	ID           uint       `json:"id"`
+	Provider     string     `json:"provider"`
+	AccountEmail string     `json:"account_email"`
+	Status       string     `json:"status"` // active, error, revoked (reconnexion requise)
+	LastError    string     `json:"last_error,omitempty"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// CapacityWeekDTO représente la charge d'un utilisateur sur une semaine
+type CapacityWeekDTO struct {
+	Week              string    `json:"week"`               // Semaine ISO (ex: 2026-W42)
+	StartDate         time.Time `json:"start_date"`         // Lundi de la semaine
+	CapacityMinutes   int       `json:"capacity_minutes"`   // Capacité (jours ouvrés x 8 h)
+	TaskMinutes       int       `json:"task_minutes"`       // Reste à faire des tâches de projet planifié sur la semaine
+	TicketMinutes     int       `json:"ticket_minutes"`     // Reste à faire des tickets ouverts (semaine en cours)
+	DeclaredMinutes   int       `json:"declared_minutes"`   // Temps déclaré (feuilles de temps)
+	AllocatedMinutes  int       `json:"allocated_minutes"`  // Temps déclaré + reste à faire planifié
+	AllocationPercent float64   `json:"allocation_percent"` // Charge allouée / capacité
+	OverAllocated     bool      `json:"over_allocated"`
+}
+
+// CapacityUserDTO représente la charge hebdomadaire d'un utilisateur
+type CapacityUserDTO struct {
+	UserID             uint              `json:"user_id"`
+	Username           string            `json:"username"`
+	FullName           string            `json:"full_name"`
+	DepartmentID       *uint             `json:"department_id,omitempty"`
+	FilialeID          *uint             `json:"filiale_id,omitempty"`
+	UnscheduledMinutes int               `json:"unscheduled_minutes"` // Reste à faire des tâches sans échéance (non réparti sur les semaines)
+	Weeks              []CapacityWeekDTO `json:"weeks"`
+}
+
+// CapacityWarningDTO signale une surcharge d'un utilisateur sur une semaine
+type CapacityWarningDTO struct {
+	UserID            uint    `json:"user_id"`
+	FullName          string  `json:"full_name"`
+	Week              string  `json:"week"`
+	AllocationPercent float64 `json:"allocation_percent"`
+	OverMinutes       int     `json:"over_minutes"` // Dépassement de la capacité
+}
+
+// CapacityReportDTO représente le plan de charge des ressources par semaine
+type CapacityReportDTO struct {
+	From      time.Time            `json:"from"` // Lundi de la première semaine
+	To        time.Time            `json:"to"`   // Dimanche de la dernière semaine
+	Weeks     []string             `json:"weeks"`
+	Threshold float64              `json:"threshold"` // Seuil de surcharge (% de la capacité)
+	Users     []CapacityUserDTO    `json:"users"`
+	Warnings  []CapacityWarningDTO `json:"warnings"`
+}
@@ -14,21 +14,27 @@ type ChangeDTO struct {
 	Result            string     `json:"result,omitempty"`             // success, partial, failed, rolled_back
 	ResultDescription string     `json:"result_description,omitempty"` // Description du résultat (optionnel)
 	ResultDate        *time.Time `json:"result_date,omitempty"`        // Date du résultat (optionnel)
+	PlannedStart      *time.Time `json:"planned_start,omitempty"`      // Début de la fenêtre de changement (optionnel)
+	PlannedEnd        *time.Time `json:"planned_end,omitempty"`        // Fin de la fenêtre de changement (optionnel)
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 // CreateChangeRequest représente la requête de création d'un changement
 type CreateChangeRequest struct {
-	TicketID        uint   `json:"ticket_id" binding:"required"`                           // ID du ticket (obligatoire)
-	Risk            string `json:"risk" binding:"required,oneof=low medium high critical"` // Risque (obligatoire)
-	RiskDescription string `json:"risk_description,omitempty"`                             // Description du risque (optionnel)
+	TicketID        uint       `json:"ticket_id" binding:"required"`                           // ID du ticket (obligatoire)
+	Risk            string     `json:"risk" binding:"required,oneof=low medium high critical"` // Risque (obligatoire)
+	RiskDescription string     `json:"risk_description,omitempty"`                             // Description du risque (optionnel)
+	PlannedStart    *time.Time `json:"planned_start,omitempty"`                                // Début de la fenêtre de changement (optionnel)
+	PlannedEnd      *time.Time `json:"planned_end,omitempty"`                                  // Fin de la fenêtre de changement (optionnel)
 }
 
 // UpdateChangeRequest représente la requête de mise à jour d'un changement
 type UpdateChangeRequest struct {
-	Risk            string `json:"risk,omitempty" binding:"omitempty,oneof=low medium high critical"` // Risque (optionnel)
-	RiskDescription string `json:"risk_description,omitempty"`                                        // Description du risque (optionnel)
+	Risk            string     `json:"risk,omitempty" binding:"omitempty,oneof=low medium high critical"` // Risque (optionnel)
+	RiskDescription string     `json:"risk_description,omitempty"`                                        // Description du risque (optionnel)
+	PlannedStart    *time.Time `json:"planned_start,omitempty"`                                           // Début de la fenêtre de changement (optionnel)
+	PlannedEnd      *time.Time `json:"planned_end,omitempty"`                                             // Fin de la fenêtre de changement (optionnel)
 }
 
 // AssignResponsibleRequest représente la requête d'assignation d'un responsable
@@ -0,0 +1,6 @@
+package dto
+
+// ConfigReloadResponse représente le résultat d'un rechargement de la configuration
+type ConfigReloadResponse struct {
+	Changed []string `json:"changed"` // Paramètres modifiés (LOG_LEVEL, RATE_LIMIT, CORS)
+}
@@ -34,6 +34,8 @@ type DelayJustificationDTO struct {
 	ValidatedBy       *uint      `json:"validated_by,omitempty"` // ID du validateur
 	ValidatedAt       *time.Time `json:"validated_at,omitempty"`
 	ValidationComment string     `json:"validation_comment,omitempty"` // Commentaire du validateur
+	ApprovalRequestID *uint      `json:"approval_request_id,omitempty"` // Demande du circuit d'approbation (voir /approvals/{id}), absente en validation unique
+	OnBehalfOfID      *uint      `json:"on_behalf_of_id,omitempty"`     // Approbateur absent pour le compte duquel la décision est attendue (délégation)
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 }
@@ -12,6 +12,7 @@ type DepartmentDTO struct {
 	Office         *OfficeDTO  `json:"office,omitempty"`      // Siège associé (optionnel)
 	IsActive       bool        `json:"is_active"`             // Si le département est actif
 	IsITDepartment bool        `json:"is_it_department"`      // Si c'est un département IT (uniquement pour la filiale fournisseur de logiciels)
+	HeadID         *uint       `json:"head_id,omitempty"`     // Chef de département (optionnel)
 	CreatedAt      string      `json:"created_at"`
 	UpdatedAt      string      `json:"updated_at"`
 }
@@ -25,6 +26,7 @@ type CreateDepartmentRequest struct {
 	OfficeID       *uint   `json:"office_id,omitempty"`           // ID du siège (optionnel)
 	IsActive       *bool   `json:"is_active,omitempty"`           // Si le département est actif (optionnel, défaut: true)
 	IsITDepartment *bool   `json:"is_it_department,omitempty"`    // Si c'est un département IT (optionnel, défaut: false, uniquement pour MCI CARE CI)
+	HeadID         *uint   `json:"head_id,omitempty"`             // Chef de département (optionnel)
 }
 
 // UpdateDepartmentRequest représente la requête de mise à jour d'un département
@@ -36,4 +38,5 @@ type UpdateDepartmentRequest struct {
 	OfficeID       *uint   `json:"office_id,omitempty"`        // ID du siège (optionnel)
 	IsActive       *bool   `json:"is_active,omitempty"`        // Si le département est actif (optionnel)
 	IsITDepartment *bool   `json:"is_it_department,omitempty"` // Si c'est un département IT (optionnel, uniquement pour la filiale fournisseur de logiciels)
+	HeadID         *uint   `json:"head_id,omitempty"`          // Chef de département (optionnel, 0 pour le retirer)
 }
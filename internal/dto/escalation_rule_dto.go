@@ -0,0 +1,59 @@
+package dto
+
+import "time"
+
+// EscalationRuleDTO représente une règle d'escalade automatique des tickets
+type EscalationRuleDTO struct {
+	ID             uint        `json:"id"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description,omitempty"`
+	FilialeID      *uint       `json:"filiale_id,omitempty"` // Absent = toutes les filiales
+	Filiale        *FilialeDTO `json:"filiale,omitempty"`
+	Category       string      `json:"category,omitempty"`        // Slug de la catégorie (vide = toutes)
+	Trigger        string      `json:"trigger"`                   // status_duration, sla_at_risk
+	Status         string      `json:"status,omitempty"`          // Statut surveillé (status_duration)
+	ThresholdHours int         `json:"threshold_hours,omitempty"` // Durée maximale dans le statut en heures (status_duration)
+	ReassignToIDs  []uint      `json:"reassign_to_ids"`           // Groupe de repli (le premier est responsable)
+	BumpPriority   bool        `json:"bump_priority"`
+	NotifyUserIDs  []uint      `json:"notify_user_ids"` // Chaîne d'escalade
+	NotifyManagers bool        `json:"notify_managers"`
+	IsActive       bool        `json:"is_active"`
+	CreatedByID    uint        `json:"created_by_id"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// CreateEscalationRuleRequest représente la requête de création d'une règle d'escalade
+type CreateEscalationRuleRequest struct {
+	Name           string `json:"name" binding:"required"`                                                      // Nom (obligatoire)
+	Description    string `json:"description,omitempty"`                                                        // Description (optionnel)
+	FilialeID      *uint  `json:"filiale_id,omitempty"`                                                         // Filiale (optionnel, absent = toutes)
+	Category       string `json:"category,omitempty"`                                                           // Slug de la catégorie (optionnel, vide = toutes)
+	Trigger        string `json:"trigger" binding:"required,oneof=status_duration sla_at_risk"`                 // Déclencheur (obligatoire)
+	Status         string `json:"status,omitempty" binding:"omitempty,oneof=ouvert en_cours en_attente resolu"` // Statut surveillé (obligatoire pour status_duration)
+	ThresholdHours int    `json:"threshold_hours,omitempty" binding:"omitempty,min=1"`                          // Durée maximale en heures (obligatoire pour status_duration)
+	ReassignToIDs  []uint `json:"reassign_to_ids,omitempty"`                                                    // Groupe de repli (optionnel)
+	BumpPriority   bool   `json:"bump_priority,omitempty"`                                                      // Relever la priorité (optionnel)
+	NotifyUserIDs  []uint `json:"notify_user_ids,omitempty"`                                                    // Chaîne d'escalade (optionnel)
+	NotifyManagers bool   `json:"notify_managers,omitempty"`                                                    // Notifier les responsables des assignés (optionnel)
+	IsActive       *bool  `json:"is_active,omitempty"`                                                          // Statut actif (optionnel, défaut: true)
+}
+
+// UpdateEscalationRuleRequest représente la requête de mise à jour d'une règle d'escalade (la filiale et le déclencheur sont fixes)
+type UpdateEscalationRuleRequest struct {
+	Name           *string `json:"name,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	Category       *string `json:"category,omitempty"`
+	Status         *string `json:"status,omitempty" binding:"omitempty,oneof=ouvert en_cours en_attente resolu"`
+	ThresholdHours *int    `json:"threshold_hours,omitempty" binding:"omitempty,min=1"`
+	ReassignToIDs  *[]uint `json:"reassign_to_ids,omitempty"` // Remplace le groupe de repli
+	BumpPriority   *bool   `json:"bump_priority,omitempty"`
+	NotifyUserIDs  *[]uint `json:"notify_user_ids,omitempty"` // Remplace la chaîne d'escalade
+	NotifyManagers *bool   `json:"notify_managers,omitempty"`
+	IsActive       *bool   `json:"is_active,omitempty"`
+}
+
+// EscalationRunDTO représente le résultat d'une évaluation des règles d'escalade
+type EscalationRunDTO struct {
+	Escalated int `json:"escalated"` // Nombre d'escalades effectuées
+}
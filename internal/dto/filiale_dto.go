@@ -12,6 +12,7 @@ type FilialeDTO struct {
 	Address     *string   `json:"address,omitempty"`     // Adresse complète
 	Phone       string    `json:"phone,omitempty"`       // Téléphone
 	Email       string    `json:"email,omitempty"`       // Email de contact
+	Timezone    string    `json:"timezone,omitempty"`    // Fuseau horaire IANA
 	IsActive    bool      `json:"is_active"`             // Si la filiale est active
 	IsSoftwareProvider bool    `json:"is_software_provider"`         // Filiale fournisseur de logiciels / IT
 	CreatedAt   time.Time `json:"created_at"`
@@ -27,6 +28,7 @@ type CreateFilialeRequest struct {
 	Address     *string `json:"address,omitempty"`              // Adresse (optionnel)
 	Phone       string  `json:"phone,omitempty"`                 // Téléphone (optionnel)
 	Email       string  `json:"email,omitempty"`                 // Email (optionnel)
+	Timezone    string  `json:"timezone,omitempty"`              // Fuseau horaire IANA, ex: Africa/Abidjan (optionnel)
 	IsSoftwareProvider bool   `json:"is_software_provider,omitempty"`       // Filiale fournisseur de logiciels (optionnel)
 }
 
@@ -38,6 +40,7 @@ type UpdateFilialeRequest struct {
 	Address     *string `json:"address,omitempty"`               // Adresse (optionnel)
 	Phone       string  `json:"phone,omitempty"`                 // Téléphone (optionnel)
 	Email       string  `json:"email,omitempty"`                 // Email (optionnel)
+	Timezone    *string `json:"timezone,omitempty"`              // Fuseau horaire IANA, vide = fuseau de l'application (optionnel)
 	IsActive    *bool   `json:"is_active,omitempty"`            // Si la filiale est active (optionnel)
 	IsSoftwareProvider *bool  `json:"is_software_provider,omitempty"`       // Filiale fournisseur de logiciels (optionnel)
 }
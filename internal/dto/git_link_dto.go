@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// TicketGitLinkDTO représente un commit ou une merge request lié à un ticket
+type TicketGitLinkDTO struct {
+	ID         uint      `json:"id"`
+	Provider   string    `json:"provider"` // github, gitlab
+	Repository string    `json:"repository"`
+	Kind       string    `json:"kind"` // commit, merge_request
+	Ref        string    `json:"ref"`  // SHA du commit ou numéro de la merge request
+	Title      string    `json:"title"`
+	URL        string    `json:"url,omitempty"`
+	AuthorName string    `json:"author_name,omitempty"`
+	State      string    `json:"state,omitempty"` // opened, merged, closed (merge requests uniquement)
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GitWebhookResultDTO résultat du traitement d'un webhook GitHub / GitLab
+type GitWebhookResultDTO struct {
+	Received int      `json:"received"`          // Commits et merge requests contenus dans l'événement
+	Linked   int      `json:"linked"`            // Nouveaux liens vers des tickets
+	Merged   int      `json:"merged"`            // Merge requests fusionnées sur des tickets liés
+	Unknown  []string `json:"unknown,omitempty"` // Codes cités ne correspondant à aucun ticket
+}
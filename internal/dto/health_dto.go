@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// HealthComponentDTO représente l'état d'un composant vérifié par une sonde
+type HealthComponentDTO struct {
+	Name     string `json:"name"`     // Nom du composant (database, migrations, storage, ...)
+	Status   string `json:"status"`   // ok, failed (cause journalisée côté serveur, jamais exposée)
+	Critical bool   `json:"critical"` // Si le composant est indispensable à la disponibilité
+}
+
+// HealthStatusDTO représente la réponse des sondes /healthz et /readyz
+type HealthStatusDTO struct {
+	Status     string               `json:"status"`               // ok, degraded, unavailable
+	Uptime     string               `json:"uptime"`               // Durée depuis le démarrage du processus
+	CheckedAt  time.Time            `json:"checked_at"`           // Date de la vérification
+	Components []HealthComponentDTO `json:"components,omitempty"` // Détail par composant (readiness uniquement)
+}
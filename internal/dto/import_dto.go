@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Étapes d'un import (les tickets sont importés en dernier : ils référencent utilisateurs, contacts et catégories)
+const (
+	ImportStageUsers      = "users"
+	ImportStageCategories = "categories"
+	ImportStageAssets     = "assets"
+	ImportStageContacts   = "contacts"
+	ImportStageArticles   = "articles"
+	ImportStageTickets    = "tickets"
+)
+
+// StartGLPIImportRequest représente le lancement d'un import depuis GLPI
+type StartGLPIImportRequest struct {
+	FilialeID       uint     `json:"filiale_id" binding:"required"`                                                   // Filiale des utilisateurs, actifs et tickets importés
+	RoleID          uint     `json:"role_id" binding:"required"`                                                      // Rôle attribué aux utilisateurs créés
+	DepartmentID    *uint    `json:"department_id,omitempty"`                                                         // Département des utilisateurs créés (optionnel)
+	Stages          []string `json:"stages,omitempty" binding:"omitempty,dive,oneof=users categories assets tickets"` // Étapes à importer (défaut: toutes)
+	SkipAttachments bool     `json:"skip_attachments,omitempty"`                                                      // Ne pas télécharger les documents des tickets
+	BatchSize       int      `json:"batch_size,omitempty" binding:"omitempty,min=1,max=200"`                          // Éléments par lot (défaut: 50)
+}
+
+// GLPIImportOptions options d'un import GLPI enregistrées avec l'import
+type GLPIImportOptions struct {
+	FilialeID       uint  `json:"filiale_id"`
+	RoleID          uint  `json:"role_id"`
+	DepartmentID    *uint `json:"department_id,omitempty"`
+	SkipAttachments bool  `json:"skip_attachments"`
+	BatchSize       int   `json:"batch_size"`
+}
+
+// HelpdeskImportMapping correspondances entre les valeurs de l'outil source et celles de Kronos
+// Les clés sont comparées sans tenir compte de la casse ; les correspondances par défaut de chaque outil sont complétées
+type HelpdeskImportMapping struct {
+	Categories          map[string]string `json:"categories,omitempty"`            // Type ou étiquette du ticket -> slug de catégorie Kronos
+	DefaultCategory     string            `json:"default_category,omitempty"`      // Slug des tickets sans correspondance (défaut: incident)
+	Priorities          map[string]string `json:"priorities,omitempty"`            // Priorité source -> low, medium, high, critical
+	Statuses            map[string]string `json:"statuses,omitempty"`              // Statut source -> ouvert, en_cours, en_attente, resolu, cloture
+	KnowledgeCategoryID *uint             `json:"knowledge_category_id,omitempty"` // Catégorie de tous les articles (défaut: une catégorie par rubrique source)
+}
+
+// HelpdeskImportOptions options communes aux imports depuis un outil de support (API ou fichiers)
+type HelpdeskImportOptions struct {
+	FilialeID    uint                  `json:"filiale_id" binding:"required"`                                             // Filiale des contacts, tickets et articles importés
+	RoleID       uint                  `json:"role_id" binding:"required"`                                                // Rôle attribué aux contacts créés
+	DepartmentID *uint                 `json:"department_id,omitempty"`                                                   // Département des contacts créés (optionnel)
+	Stages       []string              `json:"stages,omitempty" binding:"omitempty,dive,oneof=contacts articles tickets"` // Étapes à importer (défaut: toutes)
+	Mapping      HelpdeskImportMapping `json:"mapping"`
+	BatchSize    int                   `json:"batch_size,omitempty" binding:"omitempty,min=1,max=100"` // Éléments par lot (défaut: 50)
+}
+
+// StartHelpdeskImportRequest représente le lancement d'un import depuis l'API Zendesk ou Freshdesk
+type StartHelpdeskImportRequest struct {
+	HelpdeskImportOptions
+	Source   string `json:"source" binding:"required,oneof=zendesk freshdesk"`
+	URL      string `json:"url" binding:"required,url"` // Ex: https://acme.zendesk.com, https://acme.freshdesk.com
+	Email    string `json:"email,omitempty"`            // Zendesk : adresse de l'agent propriétaire du jeton
+	APIToken string `json:"api_token" binding:"required"`
+}
+
+// HelpdeskRunOptions options d'un import depuis un outil de support enregistrées avec l'import (sans le jeton d'API)
+type HelpdeskRunOptions struct {
+	HelpdeskImportOptions
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// HelpdeskImportFile fichier fourni pour une étape d'un import depuis des fichiers (lignes lues, en-tête compris)
+type HelpdeskImportFile struct {
+	Stage    string
+	FileName string
+	Rows     [][]string
+}
+
+// ImportStageStatsDTO représente les compteurs d'une étape d'import
+type ImportStageStatsDTO struct {
+	Created int `json:"created"` // Enregistrements créés
+	Matched int `json:"matched"` // Éléments associés à un enregistrement existant (ex: même e-mail)
+	Skipped int `json:"skipped"` // Éléments ignorés (déjà importés, supprimés, modèles)
+	Failed  int `json:"failed"`  // Éléments en erreur (voir errors)
+}
+
+// ImportRunDTO représente un import et sa progression
+type ImportRunDTO struct {
+	ID          uint                           `json:"id"`
+	Source      string                         `json:"source"`
+	Status      string                         `json:"status"` // pending, running, failed, cancelled, completed
+	Options     json.RawMessage                `json:"options,omitempty"`
+	Stages      []string                       `json:"stages"`
+	Stage       string                         `json:"stage,omitempty"` // Étape en cours
+	Offset      int                            `json:"offset"`          // Position dans l'étape en cours
+	Total       int                            `json:"total"`           // Éléments de l'étape en cours
+	Stats       map[string]ImportStageStatsDTO `json:"stats"`
+	Errors      []string                       `json:"errors,omitempty"` // Dernières erreurs par élément
+	LastError   string                         `json:"last_error,omitempty"`
+	CreatedByID uint                           `json:"created_by_id"`
+	StartedAt   *time.Time                     `json:"started_at,omitempty"`
+	FinishedAt  *time.Time                     `json:"finished_at,omitempty"`
+	CreatedAt   time.Time                      `json:"created_at"`
+	UpdatedAt   time.Time                      `json:"updated_at"`
+}
+
+// ImportRunListResponse représente la liste paginée des imports
+type ImportRunListResponse struct {
+	Runs       []ImportRunDTO `json:"runs"`
+	Pagination PaginationDTO  `json:"pagination"`
+}
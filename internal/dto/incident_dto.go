@@ -12,8 +12,19 @@ type IncidentDTO struct {
 	ResolutionTime *int       `json:"resolution_time,omitempty"` // Temps de résolution en minutes (optionnel)
 	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`     // Date de résolution (optionnel)
 	LinkedAssets   []AssetDTO `json:"linked_assets,omitempty"`   // Actifs liés (optionnel)
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Incident majeur
+	IsMajor               bool       `json:"is_major"`
+	MajorDeclaredAt       *time.Time `json:"major_declared_at,omitempty"`
+	MajorDeclaredBy       *UserDTO   `json:"major_declared_by,omitempty"`
+	MajorEndedAt          *time.Time `json:"major_ended_at,omitempty"`
+	PublicTitle           string     `json:"public_title,omitempty"`            // Libellé de la page de statut
+	UpdateIntervalMinutes int        `json:"update_interval_minutes,omitempty"` // Fréquence attendue des points de situation
+	LastStatusUpdateAt    *time.Time `json:"last_status_update_at,omitempty"`
+	NextStatusUpdateDue   *time.Time `json:"next_status_update_due,omitempty"` // Échéance du prochain point de situation (incident majeur en cours)
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // CreateIncidentRequest représente la requête de création d'un incident
@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// JiraLinkDTO représente le ticket Jira lié à un ticket
+type JiraLinkDTO struct {
+	IssueKey     string     `json:"issue_key"` // Clé Jira (ex: DEV-123)
+	IssueURL     string     `json:"issue_url"`
+	JiraStatus   string     `json:"jira_status,omitempty"` // Dernier statut Jira connu
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	SyncError    string     `json:"sync_error,omitempty"` // Dernière erreur de synchronisation (vide si OK)
+}
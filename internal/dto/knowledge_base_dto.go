@@ -7,12 +7,26 @@ type KnowledgeArticleDTO struct {
 	ID          uint                    `json:"id"`
 	Title       string                  `json:"title"`
 	Content     string                  `json:"content"`
+	ContentFormat string                `json:"content_format"` // text, markdown, html
+	ContentHTML string                  `json:"content_html"`   // Contenu rendu en HTML assaini (à afficher tel quel)
 	CategoryID  uint                    `json:"category_id"`
 	Category    *KnowledgeCategoryDTO   `json:"category,omitempty"` // Catégorie (optionnel)
 	AuthorID    uint                    `json:"author_id"`
 	Author      *UserDTO                `json:"author,omitempty"` // Auteur (optionnel)
+	SourceTicketID *uint                `json:"source_ticket_id,omitempty"` // Ticket dont la solution a servi à rédiger l'article
+	IsPublic    bool                    `json:"is_public"`       // Exposé sur le portail public une fois publié
 	IsPublished bool                    `json:"is_published"`    // Si l'article est publié
+	Status      string                  `json:"status"`          // draft, in_review, published, archived
+	Version     int                     `json:"version"`         // Numéro de la révision courante
 	ViewCount   int                     `json:"view_count"`      // Nombre de vues
+	HelpfulCount    int      `json:"helpful_count"`          // Évaluations « utile »
+	NotHelpfulCount int      `json:"not_helpful_count"`      // Évaluations « pas utile »
+	HelpfulRate     *float64 `json:"helpful_rate,omitempty"` // Part des évaluations « utile », en pourcentage
+	ReviewerID     *uint      `json:"reviewer_id,omitempty"`      // Relecteur désigné
+	ReviewDueAt    *time.Time `json:"review_due_at,omitempty"`    // Échéance de la relecture ou de la prochaine révision
+	ReviewOverdue  bool       `json:"review_overdue"`             // Échéance de relecture ou de révision dépassée
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	ReviewComment  string     `json:"review_comment,omitempty"`   // Commentaire de la dernière décision de relecture
 	CreatedAt   time.Time               `json:"created_at"`
 	UpdatedAt   time.Time               `json:"updated_at"`
 }
@@ -29,16 +43,23 @@ type KnowledgeCategoryDTO struct {
 type CreateKnowledgeArticleRequest struct {
 	Title       string `json:"title" binding:"required"`        // Titre (obligatoire)
 	Content     string `json:"content" binding:"required"`      // Contenu (obligatoire)
+	ContentFormat string `json:"content_format,omitempty" binding:"omitempty,oneof=text markdown html"` // Format du contenu (optionnel, défaut: text) ; images intégrées (data URI) enregistrées en pièces jointes
 	CategoryID  uint   `json:"category_id" binding:"required"` // ID catégorie (obligatoire)
 	IsPublished bool   `json:"is_published,omitempty"`          // Si l'article est publié (optionnel, défaut: false)
+	IsPublic    bool   `json:"is_public,omitempty"`             // Exposé sur le portail public une fois publié (optionnel, défaut: false)
 }
 
 // UpdateKnowledgeArticleRequest représente la requête de mise à jour d'un article
 type UpdateKnowledgeArticleRequest struct {
 	Title       string `json:"title,omitempty"`
 	Content     string `json:"content,omitempty"`
+	ContentFormat string `json:"content_format,omitempty" binding:"omitempty,oneof=text markdown html"` // Format du contenu (optionnel, défaut: format actuel)
 	CategoryID  *uint  `json:"category_id,omitempty"`
 	IsPublished *bool  `json:"is_published,omitempty"` // Statut de publication (optionnel)
+	IsPublic    *bool  `json:"is_public,omitempty"`    // Exposition sur le portail public (optionnel)
+	ChangeSummary string     `json:"change_summary,omitempty" binding:"omitempty,max=255"` // Résumé de la modification, enregistré avec la nouvelle version (optionnel)
+	ReviewerID    *uint      `json:"reviewer_id,omitempty"`                                // Relecteur désigné (optionnel, 0 = aucun)
+	ReviewDueAt   *time.Time `json:"review_due_at,omitempty"`                              // Échéance de relecture ou de prochaine révision (optionnel)
 }
 
 // CreateKnowledgeCategoryRequest représente la requête de création d'une catégorie
@@ -60,6 +81,9 @@ type KnowledgeArticleSearchResultDTO struct {
 	ID          uint   `json:"id"`
 	Title       string `json:"title"`
 	Snippet     string `json:"snippet"`     // Extrait du contenu correspondant
+	TitleHighlight string `json:"title_highlight,omitempty"` // Titre en HTML, termes trouvés entre <mark></mark>
+	Highlight   string `json:"highlight,omitempty"` // Extrait en HTML autour des termes trouvés, entre <mark></mark>
+	Score       float64 `json:"score"`              // Pertinence (plus élevée = plus pertinent ; 0 en recherche simple)
 	CategoryID  uint   `json:"category_id"`
 	Category    *KnowledgeCategoryDTO `json:"category,omitempty"`
 	AuthorID    uint   `json:"author_id"`
@@ -67,3 +91,185 @@ type KnowledgeArticleSearchResultDTO struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// KnowledgeSearchSuggestionsDTO représente les suggestions de recherche (saisie semi-automatique)
+type KnowledgeSearchSuggestionsDTO struct {
+	Query      string                          `json:"query"`
+	DidYouMean string                          `json:"did_you_mean,omitempty"` // Requête corrigée des fautes de frappe
+	Articles   []KnowledgeArticleSuggestionDTO `json:"articles"`
+}
+
+// KnowledgeArticleSuggestionDTO représente un article suggéré
+type KnowledgeArticleSuggestionDTO struct {
+	ID             uint   `json:"id"`
+	Title          string `json:"title"`
+	TitleHighlight string `json:"title_highlight"`
+}
+
+// KnowledgeArticleRevisionDTO représente une version d'un article
+type KnowledgeArticleRevisionDTO struct {
+	Version       int       `json:"version"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content,omitempty"` // Contenu (détail d'une version uniquement)
+	ContentFormat string    `json:"content_format"`
+	CategoryID    uint      `json:"category_id"`
+	EditorID      uint      `json:"editor_id"`
+	Editor        *UserDTO  `json:"editor,omitempty"`
+	Summary       string    `json:"summary,omitempty"` // Résumé de la modification
+	Current       bool      `json:"current"`           // Version courante de l'article
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// KnowledgeDiffLineDTO représente une ligne de la comparaison de deux versions
+type KnowledgeDiffLineDTO struct {
+	Op      string `json:"op"`                 // equal, added, removed
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"` // Numéro de ligne dans la version de départ
+	NewLine int    `json:"new_line,omitempty"` // Numéro de ligne dans la version d'arrivée
+}
+
+// KnowledgeArticleDiffDTO représente la comparaison de deux versions d'un article
+type KnowledgeArticleDiffDTO struct {
+	ArticleID     uint                   `json:"article_id"`
+	FromVersion   int                    `json:"from_version"`
+	ToVersion     int                    `json:"to_version"`
+	ChangedFields []string               `json:"changed_fields"` // title, content, content_format, category_id
+	TitleFrom     string                 `json:"title_from"`
+	TitleTo       string                 `json:"title_to"`
+	Added         int                    `json:"added"`   // Lignes ajoutées
+	Removed       int                    `json:"removed"` // Lignes supprimées
+	Lines         []KnowledgeDiffLineDTO `json:"lines"`   // Comparaison ligne à ligne du contenu
+}
+
+// SubmitKnowledgeReviewRequest représente la soumission d'un article en relecture
+type SubmitKnowledgeReviewRequest struct {
+	ReviewerID  *uint      `json:"reviewer_id,omitempty"`                         // Relecteur (optionnel si déjà désigné)
+	ReviewDueAt *time.Time `json:"review_due_at,omitempty"`                       // Échéance de la relecture (optionnel, défaut: 7 jours)
+	Comment     string     `json:"comment,omitempty" binding:"omitempty,max=1000"` // Message au relecteur (optionnel)
+}
+
+// KnowledgeReviewDecisionRequest représente la décision du relecteur
+type KnowledgeReviewDecisionRequest struct {
+	Decision     string     `json:"decision" binding:"required,oneof=approve reject"` // approve (publication) ou reject (retour en brouillon)
+	Comment      string     `json:"comment,omitempty" binding:"omitempty,max=1000"`   // Commentaire (optionnel)
+	NextReviewAt *time.Time `json:"next_review_at,omitempty"`                         // Prochaine révision périodique (optionnel, défaut: dans un an)
+}
+
+// KnowledgeReviewQueueDTO représente les articles à relire et à réviser
+type KnowledgeReviewQueueDTO struct {
+	InReview  []KnowledgeArticleDTO `json:"in_review"`  // Articles soumis en relecture
+	ReviewDue []KnowledgeArticleDTO `json:"review_due"` // Articles publiés dont la révision périodique est échue
+}
+
+// KnowledgeTicketSuggestionsDTO représente les articles suggérés pendant la saisie d'un ticket
+type KnowledgeTicketSuggestionsDTO struct {
+	SessionID string                            `json:"session_id"` // À renvoyer aux appels suivants et avec les événements de la saisie
+	Articles  []KnowledgeArticleSearchResultDTO `json:"articles"`   // Articles publiés, par pertinence décroissante
+}
+
+// RecordKnowledgeDeflectionRequest représente un événement de la saisie d'un ticket face aux suggestions
+type RecordKnowledgeDeflectionRequest struct {
+	SessionID string `json:"session_id" binding:"required,max=64"`
+	Event     string `json:"event" binding:"required,oneof=clicked abandoned ticket_created"`
+	ArticleID *uint  `json:"article_id,omitempty"` // Article consulté (requis pour clicked)
+	TicketID  *uint  `json:"ticket_id,omitempty"`  // Ticket créé (ticket_created)
+}
+
+// KnowledgeDeflectionStatsDTO représente les statistiques de déviation de tickets par la base de connaissances
+type KnowledgeDeflectionStatsDTO struct {
+	From            time.Time                           `json:"from"`
+	To              time.Time                           `json:"to"`
+	Sessions        int64                               `json:"sessions"`         // Saisies de ticket ayant reçu des suggestions
+	ClickedSessions int64                               `json:"clicked_sessions"` // Saisies où un article suggéré a été consulté
+	Deflected       int64                               `json:"deflected"`        // Saisies abandonnées sans création de ticket
+	TicketsCreated  int64                               `json:"tickets_created"`  // Saisies terminées par la création d'un ticket
+	DeflectionRate  float64                             `json:"deflection_rate"`  // Deflected / Sessions, en pourcentage
+	TopArticles     []KnowledgeDeflectionArticleStatDTO `json:"top_articles"`
+}
+
+// KnowledgeDeflectionArticleStatDTO représente les statistiques de déviation d'un article
+type KnowledgeDeflectionArticleStatDTO struct {
+	ArticleID uint   `json:"article_id"`
+	Title     string `json:"title"`
+	Shown     int64  `json:"shown"`     // Nombre de suggestions
+	Clicked   int64  `json:"clicked"`   // Nombre de consultations depuis une suggestion
+	Deflected int64  `json:"deflected"` // Saisies déviées après consultation de l'article
+}
+
+// RateKnowledgeArticleRequest représente l'évaluation d'un article par un lecteur
+type RateKnowledgeArticleRequest struct {
+	Helpful *bool  `json:"helpful" binding:"required"`                     // true = utile, false = pas utile
+	Comment string `json:"comment,omitempty" binding:"omitempty,max=1000"` // Commentaire (optionnel)
+}
+
+// KnowledgeArticleFeedbackDTO représente l'évaluation d'un article
+type KnowledgeArticleFeedbackDTO struct {
+	ID             uint      `json:"id"`
+	ArticleID      uint      `json:"article_id"`
+	UserID         uint      `json:"user_id"`
+	User           *UserDTO  `json:"user,omitempty"`
+	Helpful        bool      `json:"helpful"`
+	Comment        string    `json:"comment,omitempty"`
+	ArticleVersion int       `json:"article_version"` // Version de l'article évaluée
+	Outdated       bool      `json:"outdated"`        // L'article a été modifié depuis l'évaluation
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// KnowledgeArticleRatingDTO représente les évaluations agrégées d'un article
+type KnowledgeArticleRatingDTO struct {
+	ArticleID       uint                          `json:"article_id"`
+	HelpfulCount    int                           `json:"helpful_count"`
+	NotHelpfulCount int                           `json:"not_helpful_count"`
+	HelpfulRate     *float64                      `json:"helpful_rate,omitempty"` // Part des évaluations « utile », en pourcentage
+	MyFeedback      *KnowledgeArticleFeedbackDTO  `json:"my_feedback,omitempty"`  // Évaluation de l'utilisateur courant
+	Feedbacks       []KnowledgeArticleFeedbackDTO `json:"feedbacks,omitempty"`    // Détail des évaluations (gestionnaires de la base de connaissances)
+}
+
+// LowRatedKnowledgeArticleDTO représente un article mal noté du rapport des gestionnaires
+type LowRatedKnowledgeArticleDTO struct {
+	ID              uint                          `json:"id"`
+	Title           string                        `json:"title"`
+	Status          string                        `json:"status"`
+	Version         int                           `json:"version"`
+	CategoryID      uint                          `json:"category_id"`
+	Category        *KnowledgeCategoryDTO         `json:"category,omitempty"`
+	AuthorID        uint                          `json:"author_id"`
+	Author          *UserDTO                      `json:"author,omitempty"`
+	ViewCount       int                           `json:"view_count"`
+	HelpfulCount    int                           `json:"helpful_count"`
+	NotHelpfulCount int                           `json:"not_helpful_count"`
+	HelpfulRate     float64                       `json:"helpful_rate"`    // Part des évaluations « utile », en pourcentage
+	RecentComments  []KnowledgeArticleFeedbackDTO `json:"recent_comments"` // Derniers commentaires « pas utile »
+	UpdatedAt       time.Time                     `json:"updated_at"`
+}
+
+// KnowledgeArticleAttachmentDTO représente une pièce jointe d'un article
+type KnowledgeArticleAttachmentDTO struct {
+	ID           uint      `json:"id"`
+	ArticleID    uint      `json:"article_id"`
+	FileName     string    `json:"file_name"`
+	FileSize     int       `json:"file_size"`
+	MimeType     string    `json:"mime_type"`
+	IsInline     bool      `json:"is_inline"`               // Image intégrée au contenu (sinon fichier à télécharger)
+	URL          string    `json:"url"`                     // Lien de téléchargement
+	Snippet      string    `json:"snippet"`                 // Code à insérer dans le contenu de l'article (selon son format)
+	Deduplicated bool      `json:"deduplicated,omitempty"`  // Contenu déjà stocké : le fichier existant est réutilisé
+	UploadedByID *uint     `json:"uploaded_by_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// KnowledgeStorageUsageDTO représente l'espace occupé par les pièces jointes des articles d'une filiale
+type KnowledgeStorageUsageDTO struct {
+	FilialeID    *uint    `json:"filiale_id,omitempty"` // Absent pour les articles globaux
+	FilialeName  string   `json:"filiale_name,omitempty"`
+	Files        int64    `json:"files"`                  // Fichiers distincts stockés
+	UsedBytes    int64    `json:"used_bytes"`
+	QuotaBytes   int64    `json:"quota_bytes"`            // 0 = illimité
+	DefaultQuota bool     `json:"default_quota"`          // Quota par défaut (KB_STORAGE_QUOTA), aucun quota propre à la filiale
+	UsedPercent  *float64 `json:"used_percent,omitempty"` // Part du quota utilisée (absent si illimité)
+}
+
+// SetKnowledgeStorageQuotaRequest représente la requête de définition du quota de stockage d'une filiale
+type SetKnowledgeStorageQuotaRequest struct {
+	MaxBytes *int64 `json:"max_bytes" binding:"required,min=0"` // Volume maximal en octets (0 = illimité)
+}
@@ -0,0 +1,63 @@
+package dto
+
+import "time"
+
+// PublicKnowledgeArticleSummaryDTO représente un article dans la liste du portail public
+type PublicKnowledgeArticleSummaryDTO struct {
+	ID             uint                        `json:"id"`
+	Title          string                      `json:"title"`
+	TitleHighlight string                      `json:"title_highlight,omitempty"` // Titre avec les termes recherchés entourés de <mark>
+	Snippet        string                      `json:"snippet"`                   // Extrait du contenu (termes recherchés entourés de <mark>)
+	Category       *PublicKnowledgeCategoryDTO `json:"category,omitempty"`
+	UpdatedAt      time.Time                   `json:"updated_at"`
+}
+
+// PublicKnowledgeArticleDTO représente un article consulté depuis le portail public
+type PublicKnowledgeArticleDTO struct {
+	ID          uint                        `json:"id"`
+	Title       string                      `json:"title"`
+	ContentHTML string                      `json:"content_html"` // Contenu rendu en HTML assaini, liens des pièces jointes réécrits vers le portail
+	Category    *PublicKnowledgeCategoryDTO `json:"category,omitempty"`
+	ViewCount   int                         `json:"view_count"`
+	HelpfulRate *float64                    `json:"helpful_rate,omitempty"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+// PublicKnowledgeCategoryDTO représente une catégorie du portail public
+type PublicKnowledgeCategoryDTO struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ParentID    *uint  `json:"parent_id,omitempty"`
+}
+
+// PublicKnowledgeArticleListDTO représente une page d'articles du portail public
+type PublicKnowledgeArticleListDTO struct {
+	Articles   []PublicKnowledgeArticleSummaryDTO `json:"articles"`
+	Pagination PaginationDTO                      `json:"pagination"`
+}
+
+// KnowledgePortalKeyDTO représente une clé d'accès au portail public de la base de connaissances
+type KnowledgePortalKeyDTO struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	KeyPrefix   string     `json:"key_prefix"`
+	Key         string     `json:"key,omitempty"` // Clé d'API, retournée uniquement à la création et au renouvellement
+	FilialeID   *uint      `json:"filiale_id,omitempty"`
+	FilialeName string     `json:"filiale_name,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateKnowledgePortalKeyRequest représente la création d'une clé d'accès au portail public
+type CreateKnowledgePortalKeyRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	FilialeID *uint  `json:"filiale_id,omitempty"` // Filiale dont les articles sont exposés en plus des articles globaux (absente = toutes)
+}
+
+// UpdateKnowledgePortalKeyRequest représente la mise à jour d'une clé d'accès au portail public
+type UpdateKnowledgePortalKeyRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// LogLevelDTO représente le niveau de log de l'application
+type LogLevelDTO struct {
+	Level string `json:"level" binding:"required"` // debug, info, warn, error
+}
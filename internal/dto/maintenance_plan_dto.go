@@ -0,0 +1,97 @@
+package dto
+
+import "time"
+
+// MaintenancePlanDTO représente un plan de maintenance préventive
+type MaintenancePlanDTO struct {
+	ID                  uint                   `json:"id"`
+	Name                string                 `json:"name"`
+	Description         string                 `json:"description,omitempty"`
+	AssetID             *uint                  `json:"asset_id,omitempty"` // Périmètre : un actif
+	Asset               *AssetNodeDTO          `json:"asset,omitempty"`
+	CategoryID          *uint                  `json:"category_id,omitempty"` // Périmètre : tous les actifs en service d'une catégorie
+	Category            *AssetCategoryDTO      `json:"category,omitempty"`
+	Schedule            string                 `json:"schedule"`           // Expression cron ou RRULE
+	Timezone            string                 `json:"timezone,omitempty"` // Vide = fuseau par défaut
+	StartsAt            time.Time              `json:"starts_at"`
+	Checklist           []string               `json:"checklist"`
+	TicketCategory      string                 `json:"ticket_category"`
+	Priority            string                 `json:"priority"`
+	EstimatedTime       *int                   `json:"estimated_time,omitempty"`
+	RequesterDepartment string                 `json:"requester_department"`
+	AssigneeIDs         []uint                 `json:"assignee_ids"`
+	LeadID              *uint                  `json:"lead_id,omitempty"`
+	IsActive            bool                   `json:"is_active"`
+	NextRunAt           *time.Time             `json:"next_run_at,omitempty"` // Absent = récurrence terminée ou plan inactif
+	LastRunAt           *time.Time             `json:"last_run_at,omitempty"`
+	RunCount            int                    `json:"run_count"`
+	LastError           string                 `json:"last_error,omitempty"`
+	CreatedByID         uint                   `json:"created_by_id"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+	RecentRecords       []MaintenanceRecordDTO `json:"recent_records,omitempty"` // Dernières opérations générées (détail d'un plan)
+}
+
+// CreateMaintenancePlanRequest représente la requête de création d'un plan de maintenance
+type CreateMaintenancePlanRequest struct {
+	Name                string     `json:"name" binding:"required,max=150"`                                       // Nom (obligatoire)
+	Description         string     `json:"description,omitempty"`                                                 // Consignes reprises dans les tickets (optionnel)
+	AssetID             *uint      `json:"asset_id,omitempty"`                                                    // Actif concerné (asset_id ou category_id obligatoire)
+	CategoryID          *uint      `json:"category_id,omitempty"`                                                 // Catégorie d'actifs concernée (asset_id ou category_id obligatoire)
+	Schedule            string     `json:"schedule" binding:"required"`                                           // Expression cron (ex: "0 8 1 */3 *") ou RRULE (ex: "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1;BYHOUR=8") (obligatoire)
+	Timezone            string     `json:"timezone,omitempty"`                                                    // Fuseau IANA (optionnel, défaut: fuseau de l'application)
+	StartsAt            *time.Time `json:"starts_at,omitempty"`                                                   // Début de la récurrence (optionnel, défaut: maintenant)
+	Checklist           []string   `json:"checklist,omitempty" binding:"omitempty,max=50,dive,required,max=255"`  // Étapes de la checklist des tickets (optionnel)
+	TicketCategory      string     `json:"ticket_category,omitempty"`                                             // Slug de la catégorie des tickets (optionnel, défaut: maintenance)
+	Priority            string     `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"` // Priorité (optionnel, défaut: medium)
+	EstimatedTime       *int       `json:"estimated_time,omitempty" binding:"omitempty,min=0"`                    // Temps estimé en minutes (optionnel)
+	RequesterDepartment string     `json:"requester_department,omitempty" binding:"omitempty,max=100"`            // Département demandeur (optionnel, défaut: Maintenance)
+	AssigneeIDs         []uint     `json:"assignee_ids,omitempty"`                                                // Équipe assignée (optionnel)
+	LeadID              *uint      `json:"lead_id,omitempty"`                                                     // Responsable, parmi les assignés (optionnel)
+	IsActive            *bool      `json:"is_active,omitempty"`                                                   // Statut actif (optionnel, défaut: true)
+}
+
+// UpdateMaintenancePlanRequest représente la requête de mise à jour d'un plan (la prochaine échéance est recalculée)
+type UpdateMaintenancePlanRequest struct {
+	Name                *string    `json:"name,omitempty" binding:"omitempty,max=150"`
+	Description         *string    `json:"description,omitempty"`
+	AssetID             *uint      `json:"asset_id,omitempty"`    // Change le périmètre pour cet actif
+	CategoryID          *uint      `json:"category_id,omitempty"` // Change le périmètre pour cette catégorie
+	Schedule            *string    `json:"schedule,omitempty"`
+	Timezone            *string    `json:"timezone,omitempty"`
+	StartsAt            *time.Time `json:"starts_at,omitempty"`
+	Checklist           *[]string  `json:"checklist,omitempty" binding:"omitempty,max=50,dive,required,max=255"` // Remplace la checklist
+	TicketCategory      *string    `json:"ticket_category,omitempty"`
+	Priority            *string    `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	EstimatedTime       *int       `json:"estimated_time,omitempty" binding:"omitempty,min=0"`
+	RequesterDepartment *string    `json:"requester_department,omitempty" binding:"omitempty,max=100"`
+	AssigneeIDs         *[]uint    `json:"assignee_ids,omitempty"` // Remplace l'équipe assignée
+	LeadID              *uint      `json:"lead_id,omitempty"`      // 0 = aucun responsable
+	IsActive            *bool      `json:"is_active,omitempty"`
+}
+
+// MaintenanceRecordDTO représente une opération de maintenance générée par un plan
+type MaintenanceRecordDTO struct {
+	ID           uint       `json:"id"`
+	PlanID       *uint      `json:"plan_id,omitempty"` // Absent si le plan a été supprimé
+	PlanName     string     `json:"plan_name,omitempty"`
+	AssetID      uint       `json:"asset_id"`
+	TicketID     *uint      `json:"ticket_id,omitempty"`
+	TicketCode   string     `json:"ticket_code,omitempty"`
+	TicketTitle  string     `json:"ticket_title,omitempty"`
+	TicketStatus string     `json:"ticket_status,omitempty"` // Statut du ticket de maintenance
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`     // Clôture du ticket (maintenance réalisée)
+	DueAt        time.Time  `json:"due_at"`
+	Error        string     `json:"error,omitempty"` // Motif si le ticket n'a pas pu être créé
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// AssetMaintenanceDTO représente la vue maintenance d'un actif : plans applicables et historique
+type AssetMaintenanceDTO struct {
+	AssetID     uint                   `json:"asset_id"`
+	Plans       []MaintenancePlanDTO   `json:"plans"`                  // Plans de l'actif et de sa catégorie
+	NextDueAt   *time.Time             `json:"next_due_at,omitempty"`  // Prochaine maintenance planifiée (plans actifs)
+	LastDoneAt  *time.Time             `json:"last_done_at,omitempty"` // Dernière maintenance clôturée
+	OpenTickets int                    `json:"open_tickets"`           // Tickets de maintenance non clôturés
+	History     []MaintenanceRecordDTO `json:"history"`                // Opérations, de la plus récente à la plus ancienne
+}
@@ -0,0 +1,88 @@
+package dto
+
+import "time"
+
+// DeclareMajorIncidentRequest représente la déclaration d'un incident majeur
+type DeclareMajorIncidentRequest struct {
+	PublicTitle           string `json:"public_title,omitempty" binding:"omitempty,max=255"`                   // Libellé de la page de statut (optionnel, défaut: titre du ticket)
+	Message               string `json:"message" binding:"required"`                                           // Premier message diffusé aux utilisateurs (obligatoire)
+	UpdateIntervalMinutes int    `json:"update_interval_minutes,omitempty" binding:"omitempty,min=5,max=1440"` // Fréquence des points de situation (optionnel, défaut: 30)
+}
+
+// PostMajorIncidentUpdateRequest représente la publication d'un point de situation
+type PostMajorIncidentUpdateRequest struct {
+	Status   string `json:"status" binding:"required,oneof=investigating identified monitoring"` // Statut (obligatoire)
+	Message  string `json:"message" binding:"required"`                                          // Message (obligatoire)
+	Internal bool   `json:"internal,omitempty"`                                                  // Note interne : ni diffusée ni affichée sur la page de statut
+}
+
+// EndMajorIncidentRequest représente la fin d'un incident majeur (service rétabli)
+type EndMajorIncidentRequest struct {
+	Message string `json:"message" binding:"required"` // Message de rétablissement diffusé aux utilisateurs (obligatoire)
+}
+
+// MajorIncidentUpdateDTO représente un point de situation
+type MajorIncidentUpdateDTO struct {
+	ID        uint      `json:"id"`
+	Status    string    `json:"status"` // investigating, identified, monitoring, resolved
+	Message   string    `json:"message"`
+	IsPublic  bool      `json:"is_public"`
+	PostedBy  *UserDTO  `json:"posted_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicStatusDTO représente la page de statut publique des incidents majeurs
+type PublicStatusDTO struct {
+	Operational bool                     `json:"operational"` // Aucun incident majeur en cours
+	Incidents   []PublicMajorIncidentDTO `json:"incidents"`   // Incidents en cours puis terminés depuis moins de 24 h
+	GeneratedAt time.Time                `json:"generated_at"`
+}
+
+// PublicMajorIncidentDTO représente un incident majeur sur la page de statut (sans donnée interne)
+type PublicMajorIncidentDTO struct {
+	ID        uint                    `json:"id"`
+	Title     string                  `json:"title"`
+	Status    string                  `json:"status"` // Statut du dernier point de situation public
+	Filiale   string                  `json:"filiale,omitempty"`
+	StartedAt time.Time               `json:"started_at"`
+	EndedAt   *time.Time              `json:"ended_at,omitempty"`
+	Updates   []PublicStatusUpdateDTO `json:"updates"`
+}
+
+// PublicStatusUpdateDTO représente un point de situation sur la page de statut
+type PublicStatusUpdateDTO struct {
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostIncidentReviewDTO représente la revue post-incident d'un incident majeur
+type PostIncidentReviewDTO struct {
+	ID             uint       `json:"id"`
+	IncidentID     uint       `json:"incident_id"`
+	Status         string     `json:"status"` // draft, completed
+	Summary        string     `json:"summary"`
+	Timeline       string     `json:"timeline"` // Markdown
+	Impact         string     `json:"impact"`
+	RootCause      string     `json:"root_cause"`
+	Resolution     string     `json:"resolution"`
+	LessonsLearned string     `json:"lessons_learned"`
+	ActionItems    string     `json:"action_items"` // Markdown
+	CreatedBy      *UserDTO   `json:"created_by,omitempty"`
+	CompletedBy    *UserDTO   `json:"completed_by,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// UpdatePostIncidentReviewRequest représente la mise à jour d'une revue post-incident (champs fournis uniquement)
+type UpdatePostIncidentReviewRequest struct {
+	Summary        *string `json:"summary,omitempty"`
+	Timeline       *string `json:"timeline,omitempty"`
+	Impact         *string `json:"impact,omitempty"`
+	RootCause      *string `json:"root_cause,omitempty"`
+	Resolution     *string `json:"resolution,omitempty"`
+	LessonsLearned *string `json:"lessons_learned,omitempty"`
+	ActionItems    *string `json:"action_items,omitempty"`
+	Complete       bool    `json:"complete,omitempty"` // Clôt la revue (cause racine et actions correctives requises)
+}
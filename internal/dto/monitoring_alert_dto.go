@@ -0,0 +1,71 @@
+package dto
+
+import "time"
+
+// AlertSourceDTO représente un outil de supervision autorisé à envoyer ses alertes
+type AlertSourceDTO struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Kind           string     `json:"kind"` // alertmanager, zabbix
+	KeyPrefix      string     `json:"key_prefix"`
+	Key            string     `json:"key,omitempty"` // Clé d'API, retournée uniquement à la création et au renouvellement
+	FilialeID      *uint      `json:"filiale_id,omitempty"`
+	FilialeName    string     `json:"filiale_name,omitempty"`
+	AutoResolve    bool       `json:"auto_resolve"`
+	IsActive       bool       `json:"is_active"`
+	LastReceivedAt *time.Time `json:"last_received_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// CreateAlertSourceRequest représente la déclaration d'un outil de supervision
+type CreateAlertSourceRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Kind        string `json:"kind" binding:"required,oneof=alertmanager zabbix"`
+	FilialeID   *uint  `json:"filiale_id,omitempty"`   // Filiale des tickets créés (optionnel, sinon celle du déclarant)
+	AutoResolve *bool  `json:"auto_resolve,omitempty"` // true par défaut
+}
+
+// UpdateAlertSourceRequest représente la mise à jour d'un outil de supervision
+type UpdateAlertSourceRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	FilialeID   *uint   `json:"filiale_id,omitempty"` // 0 pour retirer la filiale
+	AutoResolve *bool   `json:"auto_resolve,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// MonitoringAlertDTO représente une alerte reçue et le ticket d'incident associé
+type MonitoringAlertDTO struct {
+	ID             uint              `json:"id"`
+	SourceID       uint              `json:"source_id"`
+	SourceName     string            `json:"source_name,omitempty"`
+	Fingerprint    string            `json:"fingerprint"`
+	Name           string            `json:"name"`
+	Status         string            `json:"status"`   // firing, resolved
+	Severity       string            `json:"severity"` // critical, high, warning, info
+	Summary        string            `json:"summary,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	TicketID       *uint             `json:"ticket_id,omitempty"`
+	TicketCode     string            `json:"ticket_code,omitempty"`
+	AssetID        *uint             `json:"asset_id,omitempty"`
+	SoftwareID     *uint             `json:"software_id,omitempty"`
+	Occurrences    int               `json:"occurrences"`
+	StartsAt       *time.Time        `json:"starts_at,omitempty"`
+	ResolvedAt     *time.Time        `json:"resolved_at,omitempty"`
+	LastReceivedAt time.Time         `json:"last_received_at"`
+}
+
+// MonitoringAlertListResponse représente la liste paginée des alertes
+type MonitoringAlertListResponse struct {
+	Alerts     []MonitoringAlertDTO `json:"alerts"`
+	Pagination PaginationDTO        `json:"pagination"`
+}
+
+// AlertIngestResultDTO résultat du traitement d'une notification de supervision
+type AlertIngestResultDTO struct {
+	Received int      `json:"received"` // Alertes contenues dans la notification
+	Created  int      `json:"created"`  // Tickets d'incident créés
+	Updated  int      `json:"updated"`  // Alertes déjà connues (nouveau déclenchement ou répétition)
+	Resolved int      `json:"resolved"` // Alertes levées
+	Errors   []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// NotificationPreferencesDTO représente les réglages des notifications par email de l'utilisateur
+type NotificationPreferencesDTO struct {
+	EmailAvailable     bool                            `json:"email_available"`     // Canal email configuré sur le serveur
+	EmailNotifications bool                            `json:"email_notifications"` // Interrupteur général (préférences utilisateur)
+	Types              []NotificationTypePreferenceDTO `json:"types"`               // Réglage de chaque type pouvant être envoyé par email
+}
+
+// NotificationTypePreferenceDTO représente le réglage d'un type de notification
+type NotificationTypePreferenceDTO struct {
+	NotificationType string `json:"notification_type"` // ticket_created, ticket_assigned, ticket_status_changed, sla_at_risk, ...
+	Email            bool   `json:"email"`
+}
+
+// UpdateNotificationPreferencesRequest représente la mise à jour des réglages (types omis inchangés)
+type UpdateNotificationPreferencesRequest struct {
+	Types []UpdateNotificationTypePreferenceRequest `json:"types" binding:"required,min=1,max=50,dive"`
+}
+
+// UpdateNotificationTypePreferenceRequest représente le réglage d'un type de notification
+type UpdateNotificationTypePreferenceRequest struct {
+	NotificationType string `json:"notification_type" binding:"required,max=50"`
+	Email            *bool  `json:"email" binding:"required"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // PerformanceDTO représente les métriques de performance d'un technicien
 type PerformanceDTO struct {
 	UserID               uint     `json:"user_id"`
@@ -78,3 +80,72 @@ type TeamComparisonDTO struct {
 	Worst   *PerformanceDTO  `json:"worst,omitempty"` // Technicien le plus en retard
 	Metric  string           `json:"metric"`          // Métrique comparée
 }
+
+// UtilizationFiguresDTO répartit le temps déclaré (minutes) et le rapporte à la capacité
+type UtilizationFiguresDTO struct {
+	TotalMinutes       int     `json:"total_minutes"`        // Temps déclaré
+	BillableMinutes    int     `json:"billable_minutes"`     // Temps facturable
+	NonBillableMinutes int     `json:"non_billable_minutes"` // Temps non facturable
+	SupportMinutes     int     `json:"support_minutes"`      // Catégorie support
+	ProjectMinutes     int     `json:"project_minutes"`      // Catégorie project
+	InternalMinutes    int     `json:"internal_minutes"`     // Catégorie internal
+	CapacityMinutes    int     `json:"capacity_minutes"`     // Jours ouvrés x 8 h, hors absences approuvées
+	BillablePercent    float64 `json:"billable_percent"`     // Part facturable du temps déclaré
+	UtilizationPercent float64 `json:"utilization_percent"`  // Temps facturable / capacité
+}
+
+// UtilizationPeriodDTO représente l'utilisation sur une période (semaine ISO ou mois)
+type UtilizationPeriodDTO struct {
+	Period    string    `json:"period"` // 2026-W42 ou 2026-10
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	UtilizationFiguresDTO
+}
+
+// UserUtilizationDTO représente l'utilisation d'un utilisateur sur la période du rapport
+type UserUtilizationDTO struct {
+	UserID        uint                   `json:"user_id"`
+	Username      string                 `json:"username"`
+	FullName      string                 `json:"full_name"`
+	Role          string                 `json:"role,omitempty"`
+	DepartmentID  *uint                  `json:"department_id,omitempty"`
+	FilialeID     *uint                  `json:"filiale_id,omitempty"`
+	TargetPercent *float64               `json:"target_percent,omitempty"` // Objectif du rôle
+	TargetMet     *bool                  `json:"target_met,omitempty"`     // Utilisation >= objectif (si objectif défini)
+	Periods       []UtilizationPeriodDTO `json:"periods"`
+	UtilizationFiguresDTO
+}
+
+// TeamUtilizationDTO représente l'utilisation cumulée d'une équipe (département)
+type TeamUtilizationDTO struct {
+	DepartmentID   *uint                  `json:"department_id,omitempty"` // nil : utilisateurs sans département
+	DepartmentName string                 `json:"department_name"`
+	UserCount      int                    `json:"user_count"`
+	TargetPercent  *float64               `json:"target_percent,omitempty"` // Objectif moyen pondéré par la capacité des membres ayant un objectif
+	Periods        []UtilizationPeriodDTO `json:"periods"`
+	UtilizationFiguresDTO
+}
+
+// UtilizationReportDTO représente le rapport d'utilisation par utilisateur, équipe et période
+type UtilizationReportDTO struct {
+	From        time.Time              `json:"from"`
+	To          time.Time              `json:"to"`
+	Granularity string                 `json:"granularity"` // week ou month
+	Users       []UserUtilizationDTO   `json:"users"`
+	Teams       []TeamUtilizationDTO   `json:"teams"`
+	Periods     []UtilizationPeriodDTO `json:"periods"` // Total de tous les utilisateurs par période
+	Total       UtilizationFiguresDTO  `json:"total"`
+}
+
+// UtilizationTargetDTO représente l'objectif d'utilisation d'un rôle
+type UtilizationTargetDTO struct {
+	RoleID        uint     `json:"role_id"`
+	RoleName      string   `json:"role_name"`
+	FilialeID     *uint    `json:"filiale_id,omitempty"`
+	TargetPercent *float64 `json:"target_percent,omitempty"` // nil : pas d'objectif
+}
+
+// SetUtilizationTargetRequest représente la requête de définition de l'objectif d'utilisation d'un rôle
+type SetUtilizationTargetRequest struct {
+	TargetPercent *float64 `json:"target_percent" binding:"omitempty,gte=0,lte=100"` // null pour retirer l'objectif
+}
@@ -0,0 +1,81 @@
+package dto
+
+import "time"
+
+// PortfolioStatusCountDTO représente le nombre de projets d'un statut
+type PortfolioStatusCountDTO struct {
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+// PortfolioProjectBudgetDTO représente la consommation du budget temps d'un projet
+type PortfolioProjectBudgetDTO struct {
+	ProjectID          uint    `json:"project_id"`
+	Name               string  `json:"name"`
+	Status             string  `json:"status"`
+	BudgetMinutes      int     `json:"budget_minutes"`
+	ConsumedMinutes    int     `json:"consumed_minutes"`
+	ConsumptionPercent float64 `json:"consumption_percent"`
+}
+
+// PortfolioBudgetDTO représente la consommation du budget temps des projets budgétés
+type PortfolioBudgetDTO struct {
+	BudgetedProjects   int                         `json:"budgeted_projects"` // Projets avec un budget temps
+	BudgetMinutes      int                         `json:"budget_minutes"`
+	ConsumedMinutes    int                         `json:"consumed_minutes"`
+	ConsumptionPercent float64                     `json:"consumption_percent"`
+	OverBudgetProjects int                         `json:"over_budget_projects"` // Temps consommé supérieur au budget
+	TopConsumers       []PortfolioProjectBudgetDTO `json:"top_consumers"`        // Projets les plus consommés (% du budget)
+}
+
+// PortfolioProjectOverdueDTO représente les tâches en retard d'un projet
+type PortfolioProjectOverdueDTO struct {
+	ProjectID    uint   `json:"project_id"`
+	Name         string `json:"name"`
+	OpenTasks    int    `json:"open_tasks"`
+	OverdueTasks int    `json:"overdue_tasks"`
+}
+
+// PortfolioOverdueDTO représente les tâches non clôturées dont l'échéance est dépassée
+type PortfolioOverdueDTO struct {
+	OpenTasks       int                          `json:"open_tasks"`
+	OverdueTasks    int                          `json:"overdue_tasks"`
+	ProjectsOverdue int                          `json:"projects_overdue"` // Projets ayant au moins une tâche en retard
+	TopProjects     []PortfolioProjectOverdueDTO `json:"top_projects"`     // Projets ayant le plus de tâches en retard
+}
+
+// PortfolioFilialeDTO représente les indicateurs des projets d'une filiale
+type PortfolioFilialeDTO struct {
+	FilialeID          *uint   `json:"filiale_id,omitempty"` // Absent : projets sans filiale
+	FilialeName        string  `json:"filiale_name,omitempty"`
+	Projects           int     `json:"projects"`
+	ActiveProjects     int     `json:"active_projects"`
+	BudgetMinutes      int     `json:"budget_minutes" mask:"projects.budget.view"`
+	ConsumedMinutes    int     `json:"consumed_minutes" mask:"projects.budget.view"`
+	ConsumptionPercent float64 `json:"consumption_percent" mask:"projects.budget.view"`
+	OpenTasks          int     `json:"open_tasks"`
+	OverdueTasks       int     `json:"overdue_tasks"`
+}
+
+// PortfolioMonthDTO représente l'activité du portefeuille sur un mois
+type PortfolioMonthDTO struct {
+	Month            string `json:"month"` // AAAA-MM
+	ProjectsCreated  int    `json:"projects_created"`
+	TasksCreated     int    `json:"tasks_created"`
+	TasksClosed      int    `json:"tasks_closed"`
+	TimeSpentMinutes int    `json:"time_spent_minutes" mask:"projects.budget.view"` // Temps saisi sur les tâches
+}
+
+// PortfolioReportDTO représente le tableau de bord du portefeuille de projets
+type PortfolioReportDTO struct {
+	GeneratedAt     time.Time                 `json:"generated_at"`
+	Months          int                       `json:"months"`
+	IncludeArchived bool                      `json:"include_archived"`
+	TotalProjects   int                       `json:"total_projects"`
+	Truncated       bool                      `json:"truncated"` // Nombre maximal de projets atteint : indicateurs partiels
+	ByStatus        []PortfolioStatusCountDTO `json:"by_status"`
+	Budget          *PortfolioBudgetDTO       `json:"budget,omitempty" mask:"projects.budget.view"` // Masqué sans projects.budget.view
+	Overdue         PortfolioOverdueDTO       `json:"overdue"`
+	ByFiliale       []PortfolioFilialeDTO     `json:"by_filiale"`
+	Trend           []PortfolioMonthDTO       `json:"trend"`
+}
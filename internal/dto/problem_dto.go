@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// ProblemDTO représente un problème dans les réponses API
+type ProblemDTO struct {
+	ID                    uint                 `json:"id"`
+	TicketID              uint                 `json:"ticket_id"`
+	Ticket                *TicketDTO           `json:"ticket,omitempty"`                   // Ticket associé (optionnel)
+	Impact                string               `json:"impact"`                             // low, medium, high, critical
+	RootCause             string               `json:"root_cause,omitempty"`               // Cause racine (optionnel)
+	RootCauseCategory     string               `json:"root_cause_category,omitempty"`      // hardware, software, network, configuration, human, process, supplier, unknown
+	RootCauseIdentifiedAt *time.Time           `json:"root_cause_identified_at,omitempty"` // Date d'identification de la cause racine
+	Workaround            string               `json:"workaround,omitempty"`               // Contournement (Markdown)
+	WorkaroundHTML        string               `json:"workaround_html,omitempty"`          // Contournement rendu en HTML assaini
+	IsKnownError          bool                 `json:"is_known_error"`                     // Erreur connue
+	KnownErrorAt          *time.Time           `json:"known_error_at,omitempty"`           // Date de passage en erreur connue
+	KnownErrorBy          *UserDTO             `json:"known_error_by,omitempty"`           // Déclarant de l'erreur connue
+	WorkaroundArticleID   *uint                `json:"workaround_article_id,omitempty"`    // Article KB publiant le contournement
+	WorkaroundArticle     string               `json:"workaround_article,omitempty"`       // Titre de l'article KB
+	ResolvedAt            *time.Time           `json:"resolved_at,omitempty"`              // Date de résolution (optionnel)
+	IncidentCount         int                  `json:"incident_count"`                     // Nombre de tickets incidents liés
+	Incidents             []ProblemIncidentDTO `json:"incidents,omitempty"`                // Tickets incidents liés (détail uniquement)
+	CreatedAt             time.Time            `json:"created_at"`
+	UpdatedAt             time.Time            `json:"updated_at"`
+}
+
+// ProblemIncidentDTO représente un ticket incident lié à un problème
+type ProblemIncidentDTO struct {
+	Ticket   RelatedTicketDTO `json:"ticket"`
+	LinkedBy *UserDTO         `json:"linked_by,omitempty"`
+	LinkedAt time.Time        `json:"linked_at"`
+}
+
+// CreateProblemRequest représente la requête de création d'un problème
+type CreateProblemRequest struct {
+	TicketID          uint   `json:"ticket_id" binding:"required"`                                                                                                   // ID du ticket de catégorie "probleme" (obligatoire)
+	Impact            string `json:"impact" binding:"required,oneof=low medium high critical"`                                                                       // Impact (obligatoire)
+	RootCause         string `json:"root_cause,omitempty"`                                                                                                           // Cause racine (optionnel)
+	RootCauseCategory string `json:"root_cause_category,omitempty" binding:"omitempty,oneof=hardware software network configuration human process supplier unknown"` // Catégorie de la cause racine (optionnel)
+	Workaround        string `json:"workaround,omitempty"`                                                                                                           // Contournement en Markdown (optionnel)
+	IncidentTicketIDs []uint `json:"incident_ticket_ids,omitempty"`                                                                                                  // Tickets incidents à lier (optionnel)
+}
+
+// UpdateProblemRequest représente la requête de mise à jour d'un problème
+type UpdateProblemRequest struct {
+	Impact            string  `json:"impact,omitempty" binding:"omitempty,oneof=low medium high critical"`                                                            // Impact (optionnel)
+	RootCause         *string `json:"root_cause,omitempty"`                                                                                                           // Cause racine (optionnel, "" pour effacer)
+	RootCauseCategory *string `json:"root_cause_category,omitempty" binding:"omitempty,oneof=hardware software network configuration human process supplier unknown"` // Catégorie de la cause racine (optionnel)
+	Workaround        *string `json:"workaround,omitempty"`                                                                                                           // Contournement en Markdown (optionnel, "" pour effacer)
+}
+
+// MarkKnownErrorRequest représente la requête de déclaration d'une erreur connue
+type MarkKnownErrorRequest struct {
+	RootCause  string `json:"root_cause,omitempty"` // Cause racine (optionnel si déjà documentée)
+	Workaround string `json:"workaround,omitempty"` // Contournement en Markdown (optionnel si déjà documenté)
+}
+
+// LinkProblemIncidentRequest représente la requête de liaison d'un ticket incident à un problème
+type LinkProblemIncidentRequest struct {
+	TicketID uint `json:"ticket_id" binding:"required"` // ID du ticket incident (obligatoire)
+}
+
+// PublishProblemWorkaroundRequest représente la requête de publication du contournement dans la base de connaissances
+type PublishProblemWorkaroundRequest struct {
+	CategoryID uint   `json:"category_id" binding:"required"` // ID de la catégorie KB (obligatoire)
+	Title      string `json:"title,omitempty"`                // Titre de l'article (optionnel, défaut: « Erreur connue : » + titre du ticket)
+}
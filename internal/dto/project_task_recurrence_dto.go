@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// CreateProjectTaskRecurrenceRequest représente la requête de création d'une règle de récurrence à partir d'une tâche
+// (titre, description, étape, priorité, estimation et assignés de la tâche servent de modèle aux occurrences)
+type CreateProjectTaskRecurrenceRequest struct {
+	Schedule     string     `json:"schedule" binding:"required,max=255"`                  // Expression cron (ex: "0 9 * * 1") ou RRULE (ex: "FREQ=WEEKLY;BYDAY=FR;BYHOUR=9") (obligatoire)
+	Timezone     string     `json:"timezone,omitempty"`                                   // Fuseau IANA (optionnel, défaut: fuseau de l'application)
+	StartsAt     *time.Time `json:"starts_at,omitempty"`                                  // Début de la récurrence (optionnel, défaut: maintenant)
+	LeadDays     *int       `json:"lead_days,omitempty" binding:"omitempty,min=0,max=60"` // Création des occurrences N jours avant leur date (optionnel, défaut: 7)
+	CloseExpired *bool      `json:"close_expired,omitempty"`                              // Clôture des occurrences non terminées à l'occurrence suivante (optionnel, défaut: true)
+}
+
+// UpdateProjectTaskRecurrenceRequest représente la requête de mise à jour d'une règle (la prochaine occurrence est recalculée)
+type UpdateProjectTaskRecurrenceRequest struct {
+	Schedule       *string    `json:"schedule,omitempty" binding:"omitempty,max=255"`
+	Timezone       *string    `json:"timezone,omitempty"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	LeadDays       *int       `json:"lead_days,omitempty" binding:"omitempty,min=0,max=60"`
+	CloseExpired   *bool      `json:"close_expired,omitempty"`
+	IsActive       *bool      `json:"is_active,omitempty"`
+	ProjectPhaseID *uint      `json:"project_phase_id,omitempty"` // Étape des prochaines occurrences
+	Title          *string    `json:"title,omitempty" binding:"omitempty,max=255"`
+	Description    *string    `json:"description,omitempty"`
+	Priority       *string    `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	EstimatedTime  *int       `json:"estimated_time,omitempty" binding:"omitempty,min=0"`
+	AssigneeIDs    *[]uint    `json:"assignee_ids,omitempty"` // Remplace les assignés des prochaines occurrences
+}
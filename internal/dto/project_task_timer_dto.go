@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// StartProjectTaskTimerRequest représente la requête de démarrage d'un chronomètre sur une tâche de projet
+type StartProjectTaskTimerRequest struct {
+	Description string `json:"description,omitempty"` // Description de l'entrée de temps (optionnel)
+}
+
+// StopProjectTaskTimerRequest représente la requête d'arrêt du chronomètre
+type StopProjectTaskTimerRequest struct {
+	Description *string `json:"description,omitempty"` // Remplace la description saisie au démarrage (optionnel)
+}
+
+// ProjectTaskTimerDTO représente le chronomètre en cours de l'utilisateur
+type ProjectTaskTimerDTO struct {
+	ID             uint      `json:"id"`
+	ProjectID      uint      `json:"project_id"`
+	ProjectName    string    `json:"project_name,omitempty"`
+	ProjectTaskID  uint      `json:"project_task_id"`
+	TaskCode       string    `json:"task_code,omitempty"`
+	TaskTitle      string    `json:"task_title,omitempty"`
+	StartedAt      time.Time `json:"started_at"`
+	ElapsedSeconds int64     `json:"elapsed_seconds"` // Temps écoulé au moment de la requête
+	Description    string    `json:"description,omitempty"`
+}
+
+// StoppedProjectTaskTimerDTO représente l'entrée de temps créée à l'arrêt du chronomètre
+type StoppedProjectTaskTimerDTO struct {
+	TimeEntryID    uint      `json:"time_entry_id"`
+	ProjectID      uint      `json:"project_id"`
+	ProjectTaskID  uint      `json:"project_task_id"`
+	StartedAt      time.Time `json:"started_at"`
+	StoppedAt      time.Time `json:"stopped_at"`
+	TimeSpent      int       `json:"time_spent"` // minutes (arrondi à la minute, au moins 1)
+	Date           time.Time `json:"date"`       // Jour de démarrage du chronomètre
+	Description    string    `json:"description,omitempty"`
+	TaskActualTime int       `json:"task_actual_time"` // Temps passé cumulé de la tâche, en minutes
+}
@@ -0,0 +1,55 @@
+package dto
+
+// ProjectTemplatePhaseRequest représente une étape d'un modèle de projet
+type ProjectTemplatePhaseRequest struct {
+	Name            string `json:"name" binding:"required,max=255"`                   // Nom de l'étape (obligatoire)
+	Description     string `json:"description,omitempty"`                             // Description (optionnel)
+	StartOffsetDays int    `json:"start_offset_days" binding:"min=0"`                 // Début : jours après le début du projet
+	DurationDays    *int   `json:"duration_days,omitempty" binding:"omitempty,min=1"` // Durée en jours (optionnel, sans date de fin sinon)
+}
+
+// ProjectTemplateFunctionRequest représente une fonction d'un modèle de projet
+type ProjectTemplateFunctionRequest struct {
+	Name string `json:"name" binding:"required,max=100"`                              // Nom (reprise de la fonction du projet ou du catalogue de même nom)
+	Type string `json:"type,omitempty" binding:"omitempty,oneof=direction execution"` // Type (optionnel, défaut: execution)
+}
+
+// ProjectTemplateMemberRequest représente un membre ajouté par défaut aux projets créés depuis le modèle
+type ProjectTemplateMemberRequest struct {
+	UserID           *uint `json:"user_id,omitempty"`                                  // Utilisateur (optionnel, absent = créateur du projet)
+	FunctionIndex    *int  `json:"function_index,omitempty" binding:"omitempty,min=0"` // Rang de sa fonction dans functions (optionnel)
+	IsProjectManager bool  `json:"is_project_manager,omitempty"`                       // Désigné chef de projet
+	IsLead           bool  `json:"is_lead,omitempty"`                                  // Désigné lead
+}
+
+// ProjectTemplateTaskRequest représente un squelette de tâche d'un modèle de projet
+type ProjectTemplateTaskRequest struct {
+	PhaseIndex    int    `json:"phase_index" binding:"min=0"`                                           // Rang de l'étape dans phases
+	FunctionIndex *int   `json:"function_index,omitempty" binding:"omitempty,min=0"`                    // Fonction assignée : membres du modèle ayant cette fonction (optionnel)
+	Title         string `json:"title" binding:"required,max=255"`                                      // Titre (obligatoire)
+	Description   string `json:"description,omitempty"`                                                 // Description (optionnel)
+	Priority      string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"` // Priorité (optionnel, défaut: medium)
+	EstimatedTime *int   `json:"estimated_time,omitempty" binding:"omitempty,min=0"`                    // Temps estimé en minutes (optionnel)
+	DueOffsetDays *int   `json:"due_offset_days,omitempty" binding:"omitempty,min=0"`                   // Échéance : jours après le début du projet (optionnel)
+}
+
+// SaveProjectTemplateRequest représente la requête de création ou de remplacement d'un modèle de projet
+// (étapes, fonctions, membres et tâches fournis remplacent le contenu existant)
+type SaveProjectTemplateRequest struct {
+	Name         string                           `json:"name" binding:"required,max=255"`                     // Nom unique (obligatoire)
+	Description  string                           `json:"description,omitempty"`                               // Description (optionnel)
+	DurationDays *int                             `json:"duration_days,omitempty" binding:"omitempty,min=1"`   // Durée du projet : fixe la date de fin prévue (optionnel)
+	IsActive     *bool                            `json:"is_active,omitempty"`                                 // Proposé à la création de projet (optionnel, défaut: true)
+	Phases       []ProjectTemplatePhaseRequest    `json:"phases" binding:"required,min=1,max=50,dive"`         // Étapes (au moins une)
+	Functions    []ProjectTemplateFunctionRequest `json:"functions,omitempty" binding:"omitempty,max=50,dive"` // Fonctions du projet (optionnel)
+	Members      []ProjectTemplateMemberRequest   `json:"members,omitempty" binding:"omitempty,max=50,dive"`   // Membres par défaut (optionnel)
+	Tasks        []ProjectTemplateTaskRequest     `json:"tasks,omitempty" binding:"omitempty,max=500,dive"`    // Squelette de tâches (optionnel)
+}
+
+// CreateProjectFromTemplateRequest représente la requête de création d'un projet depuis un modèle
+type CreateProjectFromTemplateRequest struct {
+	Name            string  `json:"name" binding:"required,max=255"`                       // Nom du projet (obligatoire)
+	Description     string  `json:"description,omitempty"`                                 // Description (optionnel, défaut: celle du modèle)
+	StartDate       *string `json:"start_date,omitempty"`                                  // Date de début YYYY-MM-DD, référence des dates relatives (optionnel, défaut: aujourd'hui)
+	TotalBudgetTime *int    `json:"total_budget_time,omitempty" binding:"omitempty,min=0"` // Budget temps en minutes (optionnel)
+}
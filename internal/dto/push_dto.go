@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// PushDeviceDTO représente un appareil enregistré aux notifications push
+type PushDeviceDTO struct {
+	ID         uint      `json:"id"`
+	Provider   string    `json:"provider"` // fcm, apns
+	Platform   string    `json:"platform,omitempty"`
+	DeviceName string    `json:"device_name,omitempty"`
+	AppVersion string    `json:"app_version,omitempty"`
+	Current    bool      `json:"current"` // Enregistré depuis la session de la requête
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RegisterPushDeviceRequest représente l'enregistrement du jeton push d'un appareil (à renvoyer à chaque renouvellement du jeton)
+type RegisterPushDeviceRequest struct {
+	Provider   string `json:"provider" binding:"required,oneof=fcm apns"`
+	Token      string `json:"token" binding:"required,max=512"`                             // Jeton délivré par FCM ou APNs
+	Platform   string `json:"platform,omitempty" binding:"omitempty,oneof=android ios web"` // Déduite du fournisseur si absente
+	DeviceName string `json:"device_name,omitempty" binding:"omitempty,max=100"`
+	AppVersion string `json:"app_version,omitempty" binding:"omitempty,max=30"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// RecordShareDTO représente le partage d'un ticket ou d'un projet
+type RecordShareDTO struct {
+	ID                     uint           `json:"id"`
+	ResourceType           string         `json:"resource_type"` // ticket ou project
+	ResourceID             uint           `json:"resource_id"`
+	SharedWithUserID       *uint          `json:"shared_with_user_id,omitempty"`
+	SharedWithUser         *UserDTO       `json:"shared_with_user,omitempty"`
+	SharedWithDepartmentID *uint          `json:"shared_with_department_id,omitempty"`
+	SharedWithDepartment   *DepartmentDTO `json:"shared_with_department,omitempty"`
+	AccessLevel            string         `json:"access_level"` // read ou write
+	ExpiresAt              *time.Time     `json:"expires_at,omitempty"`
+	IsExpired              bool           `json:"is_expired"`
+	SharedByID             uint           `json:"shared_by_id"`
+	SharedBy               *UserDTO       `json:"shared_by,omitempty"`
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
+}
+
+// CreateRecordShareRequest représente la requête de partage (un utilisateur OU un département)
+type CreateRecordShareRequest struct {
+	UserID       *uint      `json:"user_id,omitempty"`                                // Utilisateur bénéficiaire
+	DepartmentID *uint      `json:"department_id,omitempty"`                          // Département bénéficiaire
+	AccessLevel  string     `json:"access_level" binding:"required,oneof=read write"` // read (lecture) ou write (lecture et modification)
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`                             // Fin du partage (optionnel)
+}
+
+// UpdateRecordShareRequest représente la requête de modification d'un partage
+type UpdateRecordShareRequest struct {
+	AccessLevel  string     `json:"access_level,omitempty" binding:"omitempty,oneof=read write"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`    // Nouvelle date d'expiration
+	RemoveExpiry bool       `json:"remove_expiry,omitempty"` // Rendre le partage permanent
+}
@@ -0,0 +1,79 @@
+package dto
+
+import "time"
+
+// RecurringTicketDTO représente une planification de création automatique de tickets
+type RecurringTicketDTO struct {
+	ID                  uint        `json:"id"`
+	Name                string      `json:"name"`
+	Schedule            string      `json:"schedule"`           // Expression cron ou RRULE
+	Timezone            string      `json:"timezone,omitempty"` // Vide = fuseau par défaut
+	Title               string      `json:"title"`
+	Description         string      `json:"description,omitempty"`
+	Category            string      `json:"category"`
+	Priority            string      `json:"priority"`
+	EstimatedTime       *int        `json:"estimated_time,omitempty"`
+	RequesterID         *uint       `json:"requester_id,omitempty"`
+	RequesterName       string      `json:"requester_name,omitempty"`
+	RequesterDepartment string      `json:"requester_department"`
+	FilialeID           *uint       `json:"filiale_id,omitempty"`
+	Filiale             *FilialeDTO `json:"filiale,omitempty"`
+	SoftwareID          *uint       `json:"software_id,omitempty"`
+	AssigneeIDs         []uint      `json:"assignee_ids"`
+	LeadID              *uint       `json:"lead_id,omitempty"`
+	ProjectID           *uint       `json:"project_id,omitempty"`
+	ProjectName         string      `json:"project_name,omitempty"`
+	IsActive            bool        `json:"is_active"`
+	StartsAt            time.Time   `json:"starts_at"`
+	NextRunAt           *time.Time  `json:"next_run_at,omitempty"` // Absent = récurrence terminée ou inactive
+	LastRunAt           *time.Time  `json:"last_run_at,omitempty"`
+	LastTicketID        *uint       `json:"last_ticket_id,omitempty"`
+	RunCount            int         `json:"run_count"`
+	LastError           string      `json:"last_error,omitempty"`
+	CreatedByID         uint        `json:"created_by_id"`
+	CreatedAt           time.Time   `json:"created_at"`
+	UpdatedAt           time.Time   `json:"updated_at"`
+}
+
+// CreateRecurringTicketRequest représente la requête de création d'une planification de tickets récurrents
+type CreateRecurringTicketRequest struct {
+	Name                string     `json:"name" binding:"required"`                                               // Nom (obligatoire)
+	Schedule            string     `json:"schedule" binding:"required"`                                           // Expression cron (ex: "0 8 1 * *") ou RRULE (ex: "FREQ=MONTHLY;BYMONTHDAY=1;BYHOUR=8") (obligatoire)
+	Timezone            string     `json:"timezone,omitempty"`                                                    // Fuseau IANA (optionnel, défaut: fuseau de l'application)
+	StartsAt            *time.Time `json:"starts_at,omitempty"`                                                   // Début de la récurrence (optionnel, défaut: maintenant)
+	Title               string     `json:"title" binding:"required"`                                              // Titre des tickets créés (obligatoire)
+	Description         string     `json:"description,omitempty"`                                                 // Description des tickets créés (optionnel)
+	Category            string     `json:"category" binding:"required"`                                           // Slug de la catégorie (obligatoire)
+	Priority            string     `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"` // Priorité (optionnel, défaut: medium)
+	EstimatedTime       *int       `json:"estimated_time,omitempty" binding:"omitempty,min=0"`                    // Temps estimé en minutes (optionnel)
+	RequesterID         *uint      `json:"requester_id,omitempty"`                                                // Demandeur (optionnel)
+	RequesterName       string     `json:"requester_name,omitempty"`                                              // Nom du demandeur (optionnel)
+	RequesterDepartment string     `json:"requester_department" binding:"required"`                               // Département du demandeur (obligatoire)
+	FilialeID           *uint      `json:"filiale_id,omitempty"`                                                  // Filiale des tickets (optionnel, défaut: celle du créateur)
+	SoftwareID          *uint      `json:"software_id,omitempty"`                                                 // Logiciel concerné (optionnel)
+	AssigneeIDs         []uint     `json:"assignee_ids,omitempty"`                                                // Assignés (optionnel)
+	LeadID              *uint      `json:"lead_id,omitempty"`                                                     // Responsable, parmi les assignés (optionnel)
+	ProjectID           *uint      `json:"project_id,omitempty"`                                                  // Projet de rattachement (optionnel)
+	IsActive            *bool      `json:"is_active,omitempty"`                                                   // Statut actif (optionnel, défaut: true)
+}
+
+// UpdateRecurringTicketRequest représente la requête de mise à jour d'une planification (un changement de planification recalcule la prochaine échéance)
+type UpdateRecurringTicketRequest struct {
+	Name                *string    `json:"name,omitempty"`
+	Schedule            *string    `json:"schedule,omitempty"`
+	Timezone            *string    `json:"timezone,omitempty"`
+	StartsAt            *time.Time `json:"starts_at,omitempty"`
+	Title               *string    `json:"title,omitempty"`
+	Description         *string    `json:"description,omitempty"`
+	Category            *string    `json:"category,omitempty"`
+	Priority            *string    `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	EstimatedTime       *int       `json:"estimated_time,omitempty" binding:"omitempty,min=0"`
+	RequesterID         *uint      `json:"requester_id,omitempty"` // 0 = retirer le demandeur
+	RequesterName       *string    `json:"requester_name,omitempty"`
+	RequesterDepartment *string    `json:"requester_department,omitempty"`
+	SoftwareID          *uint      `json:"software_id,omitempty"`  // 0 = retirer le logiciel
+	AssigneeIDs         *[]uint    `json:"assignee_ids,omitempty"` // Remplace les assignés
+	LeadID              *uint      `json:"lead_id,omitempty"`      // 0 = aucun responsable
+	ProjectID           *uint      `json:"project_id,omitempty"`   // 0 = aucun projet
+	IsActive            *bool      `json:"is_active,omitempty"`
+}
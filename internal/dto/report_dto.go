@@ -175,4 +175,35 @@ type KnowledgeReportDTO struct {
 	Published  int            `json:"published"`
 	Draft      int            `json:"draft"`
 	ByCategory map[string]int `json:"by_category"`
-}
\ No newline at end of file
+}
+
+// SatisfactionReportDTO représente le rapport de satisfaction (CSAT) des tickets clôturés
+type SatisfactionReportDTO struct {
+	Period        string                     `json:"period"`
+	SurveysSent   int                        `json:"surveys_sent"`   // Enquêtes envoyées sur la période
+	Responses     int                        `json:"responses"`      // Enquêtes ayant reçu une réponse
+	ResponseRate  float64                    `json:"response_rate"`  // Taux de réponse en %
+	AverageRating float64                    `json:"average_rating"` // Note moyenne (1 à 5)
+	CSAT          float64                    `json:"csat"`           // Part des réponses satisfaites (note 4 ou 5) en %
+	ByAgent       []SatisfactionByAgentDTO   `json:"by_agent"`
+	ByFiliale     []SatisfactionByFilialeDTO `json:"by_filiale"`
+	GeneratedAt   time.Time                  `json:"generated_at"`
+}
+
+// SatisfactionByAgentDTO représente la satisfaction des demandeurs pour un agent
+type SatisfactionByAgentDTO struct {
+	UserID        uint     `json:"user_id"`
+	User          *UserDTO `json:"user,omitempty"`
+	Responses     int      `json:"responses"`
+	AverageRating float64  `json:"average_rating"`
+	CSAT          float64  `json:"csat"`
+}
+
+// SatisfactionByFilialeDTO représente la satisfaction des demandeurs pour une filiale
+type SatisfactionByFilialeDTO struct {
+	FilialeID     uint    `json:"filiale_id"`
+	FilialeName   string  `json:"filiale_name"`
+	Responses     int     `json:"responses"`
+	AverageRating float64 `json:"average_rating"`
+	CSAT          float64 `json:"csat"`
+}
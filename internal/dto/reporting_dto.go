@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// ReportingKeyDTO représente une clé d'accès au flux OData de reporting
+type ReportingKeyDTO struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	KeyPrefix     string     `json:"key_prefix"`
+	Key           string     `json:"key,omitempty"` // Clé d'API, retournée uniquement à la création et au renouvellement
+	FeedURL       string     `json:"feed_url"`      // URL du flux OData à saisir dans Power BI
+	FilialeID     *uint      `json:"filiale_id,omitempty"`
+	FilialeName   string     `json:"filiale_name,omitempty"`
+	IncludeBudget bool       `json:"include_budget"`
+	IsActive      bool       `json:"is_active"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// CreateReportingKeyRequest représente la création d'une clé d'accès au flux OData
+type CreateReportingKeyRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	FilialeID *uint  `json:"filiale_id,omitempty"` // Filiale des données exposées (absente = toutes les filiales, réservé à reports.view_global)
+}
+
+// UpdateReportingKeyRequest représente la mise à jour d'une clé d'accès au flux OData
+type UpdateReportingKeyRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+// ODataPage page d'un ensemble d'entités du flux OData
+type ODataPage struct {
+	Rows     []map[string]interface{}
+	Count    *int64 // Nombre total d'entités si $count=true
+	HasNext  bool   // Une page suivante existe
+	NextSkip int
+	NextTop  int // -1 si $top est absent
+}
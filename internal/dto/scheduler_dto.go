@@ -0,0 +1,7 @@
+package dto
+
+// UpdateScheduledJobRequest représente la requête de modification d'une tâche planifiée
+type UpdateScheduledJobRequest struct {
+	Schedule string `json:"schedule,omitempty"` // Expression cron (5 champs ou @daily, @every 1h, ...) ; vide = inchangée
+	Enabled  *bool  `json:"enabled,omitempty"`  // Activation de la tâche ; absent = inchangée
+}
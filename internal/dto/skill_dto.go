@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+// SkillDTO représente une compétence ou une certification du catalogue
+type SkillDTO struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"` // skill, certification
+	Category    string    `json:"category,omitempty"`
+	Description string    `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateSkillRequest représente la requête d'ajout d'une compétence au catalogue
+type CreateSkillRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`                              // Nom unique (obligatoire)
+	Kind        string `json:"kind,omitempty" binding:"omitempty,oneof=skill certification"` // skill (défaut) ou certification
+	Category    string `json:"category,omitempty" binding:"max=100"`                         // Catégorie (optionnel)
+	Description string `json:"description,omitempty"`                                        // Description (optionnel)
+}
+
+// UpdateSkillRequest représente la requête de mise à jour d'une compétence du catalogue
+type UpdateSkillRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,max=100"`
+	Kind        *string `json:"kind,omitempty" binding:"omitempty,oneof=skill certification"`
+	Category    *string `json:"category,omitempty" binding:"omitempty,max=100"`
+	Description *string `json:"description,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// UserSkillDTO représente une compétence détenue par un utilisateur
+type UserSkillDTO struct {
+	Skill      SkillDTO   `json:"skill"`
+	Level      int        `json:"level"`       // 1 débutant, 2 intermédiaire, 3 avancé, 4 expert
+	LevelLabel string     `json:"level_label"` // Libellé du niveau
+	ObtainedAt *time.Time `json:"obtained_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Expired    bool       `json:"expired"` // Certification expirée
+	Reference  string     `json:"reference,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// SetUserSkillRequest représente l'ajout ou la mise à jour d'une compétence dans un profil
+type SetUserSkillRequest struct {
+	Level      int        `json:"level" binding:"required,min=1,max=4"`  // Niveau de maîtrise (obligatoire, 1 à 4)
+	ObtainedAt *time.Time `json:"obtained_at,omitempty"`                 // Date d'obtention (optionnel)
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`                  // Date d'expiration (optionnel)
+	Reference  string     `json:"reference,omitempty" binding:"max=255"` // N° de certificat (optionnel)
+}
+
+// SkillSearchResultDTO représente un utilisateur correspondant à une recherche par compétences
+type SkillSearchResultDTO struct {
+	User         UserDTO        `json:"user"`
+	MatchedCount int            `json:"matched_count"` // Compétences demandées détenues au niveau requis
+	LevelTotal   int            `json:"level_total"`   // Niveau cumulé sur ces compétences
+	Available    bool           `json:"available"`     // Pas d'absence en cours
+	Skills       []UserSkillDTO `json:"skills"`        // Compétences demandées détenues par l'utilisateur
+}
@@ -9,6 +9,7 @@ type SLADTO struct {
 	Description    string    `json:"description,omitempty"`
 	TicketCategory string    `json:"ticket_category"`    // incident, demande, changement, developpement
 	Priority       *string   `json:"priority,omitempty"` // low, medium, high, critical (nil = tous)
+	SupportLevel   *string   `json:"support_level,omitempty"` // basic, standard, premium (nil = tickets hors contrat de support)
 	TargetTime     int       `json:"target_time"`        // Temps cible en minutes
 	Unit           string    `json:"unit"`               // minutes, hours, days
 	IsActive       bool      `json:"is_active"`          // Si le SLA est actif
@@ -22,6 +23,7 @@ type CreateSLARequest struct {
 	Description    string  `json:"description,omitempty"`                                      // Description (optionnel)
 	TicketCategory string  `json:"ticket_category" binding:"required"`                        // Catégorie (obligatoire) - slug de la catégorie de ticket
 	Priority       *string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"` // Priorité (optionnel)
+	SupportLevel   *string `json:"support_level,omitempty" binding:"omitempty,oneof=basic standard premium"` // Niveau de support du contrat (optionnel, SLA des tickets couverts par un contrat de ce niveau)
 	TargetTime     int     `json:"target_time" binding:"required,min=1"`                       // Temps cible en minutes (obligatoire, min 1)
 	Unit           string  `json:"unit,omitempty" binding:"omitempty,oneof=minutes hours days"` // Unité (optionnel, défaut: minutes)
 	IsActive       bool    `json:"is_active,omitempty"`                                        // Statut actif (optionnel, défaut: true)
@@ -34,6 +36,7 @@ type UpdateSLARequest struct {
 	TargetTime  *int   `json:"target_time,omitempty" binding:"omitempty,min=1"`
 	Unit        string `json:"unit,omitempty" binding:"omitempty,oneof=minutes hours days"`
 	IsActive    *bool  `json:"is_active,omitempty"`
+	SupportLevel *string `json:"support_level,omitempty" binding:"omitempty,oneof=none basic standard premium"` // Niveau de support ("none" : tickets hors contrat)
 }
 
 // TicketSLAStatusDTO représente le statut SLA d'un ticket
@@ -41,8 +44,8 @@ type TicketSLAStatusDTO struct {
 	SLAID       uint       `json:"sla_id"`                // ID du SLA appliqué
 	SLA         *SLADTO    `json:"sla,omitempty"`         // SLA (optionnel)
 	TargetTime  time.Time  `json:"target_time"`           // Date/heure cible
-	ElapsedTime int        `json:"elapsed_time"`          // Temps écoulé en minutes
-	Remaining   int        `json:"remaining"`             // Temps restant en minutes (peut être négatif)
+	ElapsedTime int        `json:"elapsed_time"`          // Temps écoulé en minutes (ouvrées si un calendrier ouvré s'applique)
+	Remaining   int        `json:"remaining"`             // Temps restant en minutes ouvrées (peut être négatif)
 	Status      string     `json:"status"`                // on_time, at_risk, violated
 	ViolatedAt  *time.Time `json:"violated_at,omitempty"` // Date de violation (optionnel)
 }
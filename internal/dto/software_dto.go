@@ -32,32 +32,67 @@ type UpdateSoftwareRequest struct {
 
 // FilialeSoftwareDTO représente un déploiement de logiciel chez une filiale
 type FilialeSoftwareDTO struct {
-	ID         uint       `json:"id"`
-	FilialeID  uint       `json:"filiale_id"`
-	Filiale    FilialeDTO `json:"filiale"`
-	SoftwareID uint       `json:"software_id"`
-	Software   SoftwareDTO `json:"software"`
-	Version    string     `json:"version,omitempty"`            // Version déployée
-	DeployedAt *time.Time `json:"deployed_at,omitempty"`       // Date de déploiement
-	IsActive   bool       `json:"is_active"`                    // Si le déploiement est actif
-	Notes      *string    `json:"notes,omitempty"`              // Notes
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
-}
-
-// CreateFilialeSoftwareRequest représente la requête de création d'un déploiement
+	ID              uint        `json:"id"`
+	FilialeID       uint        `json:"filiale_id"`
+	Filiale         FilialeDTO  `json:"filiale"`
+	SoftwareID      uint        `json:"software_id"`
+	Software        SoftwareDTO `json:"software"`
+	Version         string      `json:"version,omitempty"`          // Version déployée
+	PreviousVersion string      `json:"previous_version,omitempty"` // Version remplacée
+	Environment     string      `json:"environment"`                // production, preproduction, test
+	Status          string      `json:"status"`                     // planned, in_progress, done, rolled_back
+	PlannedAt       *time.Time  `json:"planned_at,omitempty"`       // Date prévue
+	DeployedAt      *time.Time  `json:"deployed_at,omitempty"`      // Date de déploiement
+	DeployedByID    *uint       `json:"deployed_by_id,omitempty"`   // Utilisateur ayant confirmé le déploiement
+	IsActive        bool        `json:"is_active"`                  // Version en service
+	Notes           *string     `json:"notes,omitempty"`            // Notes
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// CreateFilialeSoftwareRequest représente la requête d'enregistrement d'un déploiement effectué
 type CreateFilialeSoftwareRequest struct {
-	FilialeID  uint      `json:"filiale_id"`                      // ID de la filiale (peut venir de l'URL)
-	SoftwareID uint      `json:"software_id" binding:"required"` // ID du logiciel (obligatoire)
-	Version    string    `json:"version,omitempty"`               // Version déployée (optionnel)
-	DeployedAt *time.Time `json:"deployed_at,omitempty"`         // Date de déploiement (optionnel)
-	Notes      *string   `json:"notes,omitempty"`                // Notes (optionnel)
+	FilialeID   uint       `json:"filiale_id"`                                                                    // ID de la filiale (peut venir de l'URL)
+	SoftwareID  uint       `json:"software_id" binding:"required"`                                                // ID du logiciel (obligatoire)
+	Version     string     `json:"version,omitempty" binding:"max=50"`                                            // Version déployée (défaut : version courante du logiciel)
+	Environment string     `json:"environment,omitempty" binding:"omitempty,oneof=production preproduction test"` // Environnement (défaut production)
+	DeployedAt  *time.Time `json:"deployed_at,omitempty"`                                                         // Date de déploiement (optionnel)
+	Notes       *string    `json:"notes,omitempty"`                                                               // Notes (optionnel)
 }
 
 // UpdateFilialeSoftwareRequest représente la requête de mise à jour d'un déploiement
 type UpdateFilialeSoftwareRequest struct {
-	Version    string    `json:"version,omitempty"`              // Version déployée (optionnel)
-	DeployedAt *time.Time `json:"deployed_at,omitempty"`        // Date de déploiement (optionnel)
-	IsActive   *bool     `json:"is_active,omitempty"`            // Si le déploiement est actif (optionnel)
-	Notes      *string   `json:"notes,omitempty"`                // Notes (optionnel)
+	Version    string     `json:"version,omitempty" binding:"max=50"` // Version déployée (déploiement planifié ou en cours uniquement)
+	PlannedAt  *time.Time `json:"planned_at,omitempty"`               // Date prévue (optionnel)
+	DeployedAt *time.Time `json:"deployed_at,omitempty"`              // Date de déploiement (optionnel)
+	IsActive   *bool      `json:"is_active,omitempty"`                // Si le déploiement est actif (optionnel)
+	Notes      *string    `json:"notes,omitempty"`                    // Notes (optionnel)
+}
+
+// PlanFilialeSoftwareRequest représente la planification d'un déploiement
+type PlanFilialeSoftwareRequest struct {
+	SoftwareID  uint       `json:"software_id" binding:"required"`                                                // ID du logiciel (obligatoire)
+	Version     string     `json:"version,omitempty" binding:"max=50"`                                            // Version à déployer (défaut : version courante du logiciel)
+	Environment string     `json:"environment,omitempty" binding:"omitempty,oneof=production preproduction test"` // Environnement (défaut production)
+	PlannedAt   *time.Time `json:"planned_at,omitempty"`                                                          // Date prévue (optionnel)
+	Notes       *string    `json:"notes,omitempty"`                                                               // Notes (optionnel)
+}
+
+// DeploymentTransitionRequest représente le passage d'un déploiement à l'étape suivante
+type DeploymentTransitionRequest struct {
+	Version    string     `json:"version,omitempty" binding:"max=50"` // Version effectivement déployée (confirmation, optionnel)
+	DeployedAt *time.Time `json:"deployed_at,omitempty"`              // Date effective (confirmation, défaut maintenant)
+	Notes      string     `json:"notes,omitempty"`                    // Commentaire consigné dans l'historique (motif du retour arrière, ...)
+}
+
+// FilialeSoftwareHistoryDTO représente une étape de l'historique d'un déploiement
+type FilialeSoftwareHistoryDTO struct {
+	ID         uint      `json:"id"`
+	Action     string    `json:"action"` // created, planned, started, confirmed, rolled_back, updated, superseded, restored
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status,omitempty"`
+	Version    string    `json:"version,omitempty"`
+	Notes      string    `json:"notes,omitempty"`
+	User       *UserDTO  `json:"user,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
 }
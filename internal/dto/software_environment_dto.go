@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// SoftwareEnvironmentDTO représente un environnement d'un logiciel chez une filiale
+type SoftwareEnvironmentDTO struct {
+	ID         uint         `json:"id"`
+	FilialeID  uint         `json:"filiale_id"`
+	SoftwareID uint         `json:"software_id"`
+	Software   *SoftwareDTO `json:"software,omitempty"`
+	Name       string       `json:"name"` // production, preproduction, test
+	Label      string       `json:"label,omitempty"`
+	URL        string       `json:"url,omitempty"`
+	Version    string       `json:"version,omitempty"` // Version en service
+	Notes      *string      `json:"notes,omitempty"`
+	IsActive   bool         `json:"is_active"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// EnvironmentSummaryDTO représente l'environnement concerné dans le détail d'un ticket
+type EnvironmentSummaryDTO struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Label   string `json:"label,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// CreateSoftwareEnvironmentRequest représente la déclaration d'un environnement
+type CreateSoftwareEnvironmentRequest struct {
+	SoftwareID uint    `json:"software_id" binding:"required"`                              // ID du logiciel (obligatoire)
+	Name       string  `json:"name" binding:"required,oneof=production preproduction test"` // Environnement (obligatoire)
+	Label      string  `json:"label,omitempty" binding:"max=100"`                           // Libellé (optionnel)
+	URL        string  `json:"url,omitempty" binding:"omitempty,url,max=500"`               // Adresse d'accès (optionnel)
+	Version    string  `json:"version,omitempty" binding:"max=50"`                          // Version en service (défaut : déploiement actif de l'environnement)
+	Notes      *string `json:"notes,omitempty"`                                             // Notes (optionnel)
+}
+
+// UpdateSoftwareEnvironmentRequest représente la mise à jour d'un environnement (champs omis inchangés)
+type UpdateSoftwareEnvironmentRequest struct {
+	Label    *string `json:"label,omitempty" binding:"omitempty,max=100"`
+	URL      *string `json:"url,omitempty" binding:"omitempty,max=500"` // Chaîne vide pour retirer l'adresse
+	Version  *string `json:"version,omitempty" binding:"omitempty,max=50"`
+	Notes    *string `json:"notes,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
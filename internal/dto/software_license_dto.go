@@ -0,0 +1,82 @@
+package dto
+
+import "time"
+
+// SoftwareLicenseDTO représente une licence logicielle détenue par une filiale
+type SoftwareLicenseDTO struct {
+	ID           uint         `json:"id"`
+	SoftwareID   uint         `json:"software_id"`
+	Software     *SoftwareDTO `json:"software,omitempty"`
+	FilialeID    uint         `json:"filiale_id"`
+	Filiale      *FilialeDTO  `json:"filiale,omitempty"`
+	Reference    string       `json:"reference,omitempty"`
+	LicenseKey   string       `json:"license_key,omitempty" mask:"software_licenses.view_keys"` // Masquée sans software_licenses.view_keys
+	Seats        *int         `json:"seats,omitempty"`                                          // Absent : illimité
+	StartDate    *time.Time   `json:"start_date,omitempty"`
+	ExpiryDate   *time.Time   `json:"expiry_date,omitempty"` // Absent : perpétuelle
+	DaysToExpiry *int         `json:"days_to_expiry,omitempty"`
+	Notes        *string      `json:"notes,omitempty"`
+	IsActive     bool         `json:"is_active"`
+	InEffect     bool         `json:"in_effect"` // Active et dans sa période de validité
+	CreatedByID  uint         `json:"created_by_id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// CreateSoftwareLicenseRequest représente la création d'une licence logicielle
+type CreateSoftwareLicenseRequest struct {
+	SoftwareID uint    `json:"software_id" binding:"required"`            // Logiciel (obligatoire)
+	FilialeID  uint    `json:"filiale_id" binding:"required"`             // Filiale titulaire (obligatoire)
+	Reference  string  `json:"reference,omitempty" binding:"max=100"`     // Référence commande / contrat éditeur (optionnel)
+	LicenseKey string  `json:"license_key,omitempty" binding:"max=255"`   // Clé de licence (optionnel)
+	Seats      *int    `json:"seats,omitempty" binding:"omitempty,min=1"` // Postes couverts (optionnel, illimité si absent)
+	StartDate  string  `json:"start_date,omitempty"`                      // Début (YYYY-MM-DD, optionnel)
+	ExpiryDate string  `json:"expiry_date,omitempty"`                     // Expiration (YYYY-MM-DD, optionnel : perpétuelle)
+	Notes      *string `json:"notes,omitempty"`
+}
+
+// UpdateSoftwareLicenseRequest représente la mise à jour d'une licence logicielle (champs omis inchangés)
+type UpdateSoftwareLicenseRequest struct {
+	Reference      *string `json:"reference,omitempty" binding:"omitempty,max=100"`
+	LicenseKey     *string `json:"license_key,omitempty" binding:"omitempty,max=255"`
+	Seats          *int    `json:"seats,omitempty" binding:"omitempty,min=1"`
+	UnlimitedSeats bool    `json:"unlimited_seats,omitempty"` // Retire la limite de postes
+	StartDate      *string `json:"start_date,omitempty"`      // YYYY-MM-DD, chaîne vide pour retirer la date
+	ExpiryDate     *string `json:"expiry_date,omitempty"`     // YYYY-MM-DD, chaîne vide pour une licence perpétuelle
+	Notes          *string `json:"notes,omitempty"`
+	IsActive       *bool   `json:"is_active,omitempty"`
+}
+
+// LicenseComplianceReportDTO représente le rapport de conformité des licences : droits comparés aux installations
+// inventoriées par filiale, et licences expirées ou arrivant à échéance
+type LicenseComplianceReportDTO struct {
+	GeneratedAt      time.Time                   `json:"generated_at"`
+	ExpiryWithinDays int                         `json:"expiry_within_days"`
+	Summary          LicenseComplianceSummaryDTO `json:"summary"`
+	Entries          []LicenseComplianceEntryDTO `json:"entries"`
+	Expiring         []SoftwareLicenseDTO        `json:"expiring"` // Licences actives expirées ou expirant dans l'horizon
+}
+
+// LicenseComplianceSummaryDTO représente les totaux du rapport de conformité
+type LicenseComplianceSummaryDTO struct {
+	Compliant    int `json:"compliant"`
+	OverDeployed int `json:"over_deployed"` // Installations au-delà des postes couverts
+	Unlicensed   int `json:"unlicensed"`    // Installations sans licence en vigueur
+	Expiring     int `json:"expiring"`
+}
+
+// LicenseComplianceEntryDTO représente la conformité d'un logiciel dans une filiale
+type LicenseComplianceEntryDTO struct {
+	FilialeID       *uint  `json:"filiale_id,omitempty"` // Absent : actifs sans filiale
+	FilialeName     string `json:"filiale_name,omitempty"`
+	SoftwareID      uint   `json:"software_id"`
+	SoftwareCode    string `json:"software_code"`
+	SoftwareName    string `json:"software_name"`
+	SoftwareVersion string `json:"software_version,omitempty"`
+	Licenses        int    `json:"licenses"`                 // Licences en vigueur
+	EntitledSeats   *int   `json:"entitled_seats,omitempty"` // Postes couverts (absent : illimité)
+	Unlimited       bool   `json:"unlimited"`
+	Installations   int    `json:"installations"`             // Actifs en service sur lesquels le logiciel est inventorié
+	AvailableSeats  *int   `json:"available_seats,omitempty"` // Postes restants (négatif : dépassement)
+	Status          string `json:"status"`                    // compliant, over_deployed, unlicensed
+}
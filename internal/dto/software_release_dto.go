@@ -0,0 +1,59 @@
+package dto
+
+import "time"
+
+// SoftwareReleaseDTO représente une version publiée d'un logiciel
+type SoftwareReleaseDTO struct {
+	ID            uint       `json:"id"`
+	SoftwareID    uint       `json:"software_id"`
+	Version       string     `json:"version"`
+	Title         string     `json:"title,omitempty"`
+	Changelog     string     `json:"changelog,omitempty"`    // Notes de version
+	KnownIssues   string     `json:"known_issues,omitempty"` // Anomalies connues
+	ReleaseDate   *time.Time `json:"release_date,omitempty"`
+	Status        string     `json:"status"` // draft, released
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	PublishedByID *uint      `json:"published_by_id,omitempty"`
+	CreatedByID   uint       `json:"created_by_id"`
+	FixedTickets  int        `json:"fixed_tickets"` // Nombre de tickets corrigés par la version
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ReleaseSummaryDTO représente une version corrective dans le détail d'un ticket
+type ReleaseSummaryDTO struct {
+	ID          uint       `json:"id"`
+	Version     string     `json:"version"`
+	Status      string     `json:"status"`
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+}
+
+// CreateSoftwareReleaseRequest représente la création d'une version
+type CreateSoftwareReleaseRequest struct {
+	Version     string     `json:"version" binding:"required,max=50"`
+	Title       string     `json:"title,omitempty" binding:"max=255"`
+	Changelog   string     `json:"changelog,omitempty"`
+	KnownIssues string     `json:"known_issues,omitempty"`
+	ReleaseDate *time.Time `json:"release_date,omitempty"` // Date prévue (optionnel)
+}
+
+// UpdateSoftwareReleaseRequest représente la mise à jour d'une version (champs omis inchangés)
+type UpdateSoftwareReleaseRequest struct {
+	Version     *string    `json:"version,omitempty" binding:"omitempty,min=1,max=50"` // Version en préparation uniquement
+	Title       *string    `json:"title,omitempty" binding:"omitempty,max=255"`
+	Changelog   *string    `json:"changelog,omitempty"`
+	KnownIssues *string    `json:"known_issues,omitempty"`
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+}
+
+// LinkReleaseTicketsRequest représente le rattachement de tickets corrigés par une version
+type LinkReleaseTicketsRequest struct {
+	TicketIDs []uint `json:"ticket_ids" binding:"required,min=1,max=200"`
+}
+
+// ReleaseTicketsResultDTO représente le résultat d'un rattachement de tickets à une version
+type ReleaseTicketsResultDTO struct {
+	Linked   []uint `json:"linked"`             // Tickets rattachés
+	Rejected []uint `json:"rejected,omitempty"` // Tickets introuvables ou d'un autre logiciel
+	Notified int    `json:"notified"`           // Utilisateurs notifiés (version publiée)
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "time"
+
+// SignedURLDTO représente une URL temporaire de téléchargement direct
+type SignedURLDTO struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
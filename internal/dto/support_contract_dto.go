@@ -0,0 +1,74 @@
+package dto
+
+import "time"
+
+// SupportContractContactDTO représente un interlocuteur d'un contrat de support
+type SupportContractContactDTO struct {
+	Name  string `json:"name" binding:"required,max=255"`
+	Role  string `json:"role,omitempty" binding:"max=100"` // Fonction (ex: référent applicatif, DSI)
+	Email string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone string `json:"phone,omitempty" binding:"max=50"`
+}
+
+// SupportContractDTO représente un contrat de support logiciel
+type SupportContractDTO struct {
+	ID                uint                        `json:"id"`
+	Reference         string                      `json:"reference"`
+	ProviderFilialeID uint                        `json:"provider_filiale_id"`
+	ProviderFiliale   *FilialeDTO                 `json:"provider_filiale,omitempty"`
+	FilialeID         uint                        `json:"filiale_id"`
+	Filiale           *FilialeDTO                 `json:"filiale,omitempty"`
+	SoftwareID        *uint                       `json:"software_id,omitempty"` // Absent : tous les logiciels
+	Software          *SoftwareDTO                `json:"software,omitempty"`
+	SupportLevel      string                      `json:"support_level"` // basic, standard, premium
+	StartDate         time.Time                   `json:"start_date"`
+	EndDate           *time.Time                  `json:"end_date,omitempty"`
+	RenewalDate       *time.Time                  `json:"renewal_date,omitempty"`
+	Contacts          []SupportContractContactDTO `json:"contacts"`
+	CoveredModules    []string                    `json:"covered_modules"`
+	Notes             *string                     `json:"notes,omitempty"`
+	IsActive          bool                        `json:"is_active"`
+	InEffect          bool                        `json:"in_effect"` // Actif et dans sa période de validité
+	CreatedByID       uint                        `json:"created_by_id"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	UpdatedAt         time.Time                   `json:"updated_at"`
+}
+
+// SupportContractSummaryDTO représente le contrat couvrant un ticket dans son détail
+type SupportContractSummaryDTO struct {
+	ID             uint                        `json:"id"`
+	Reference      string                      `json:"reference"`
+	SupportLevel   string                      `json:"support_level"`
+	EndDate        *time.Time                  `json:"end_date,omitempty"`
+	RenewalDate    *time.Time                  `json:"renewal_date,omitempty"`
+	Contacts       []SupportContractContactDTO `json:"contacts,omitempty"`
+	CoveredModules []string                    `json:"covered_modules,omitempty"`
+}
+
+// CreateSupportContractRequest représente la création d'un contrat de support
+type CreateSupportContractRequest struct {
+	Reference         string                      `json:"reference" binding:"required,max=50"`                               // Référence unique (obligatoire)
+	ProviderFilialeID *uint                       `json:"provider_filiale_id,omitempty"`                                     // Filiale fournisseur (défaut : filiale fournisseur de logiciels)
+	FilialeID         uint                        `json:"filiale_id" binding:"required"`                                     // Filiale cliente (obligatoire)
+	SoftwareID        *uint                       `json:"software_id,omitempty"`                                             // Logiciel couvert (optionnel, tous si absent)
+	SupportLevel      string                      `json:"support_level" binding:"required,oneof=basic standard premium"`     // Niveau de support (obligatoire)
+	StartDate         string                      `json:"start_date" binding:"required"`                                     // Début (YYYY-MM-DD)
+	EndDate           string                      `json:"end_date,omitempty"`                                                // Fin (YYYY-MM-DD, optionnel)
+	RenewalDate       string                      `json:"renewal_date,omitempty"`                                            // Renouvellement (YYYY-MM-DD, optionnel)
+	Contacts          []SupportContractContactDTO `json:"contacts,omitempty" binding:"omitempty,max=20,dive"`                // Interlocuteurs (optionnel)
+	CoveredModules    []string                    `json:"covered_modules,omitempty" binding:"omitempty,max=50,dive,max=100"` // Modules couverts (optionnel)
+	Notes             *string                     `json:"notes,omitempty"`
+}
+
+// UpdateSupportContractRequest représente la mise à jour d'un contrat de support (champs omis inchangés)
+type UpdateSupportContractRequest struct {
+	Reference      *string                      `json:"reference,omitempty" binding:"omitempty,min=1,max=50"`
+	SupportLevel   *string                      `json:"support_level,omitempty" binding:"omitempty,oneof=basic standard premium"`
+	StartDate      *string                      `json:"start_date,omitempty"`   // YYYY-MM-DD
+	EndDate        *string                      `json:"end_date,omitempty"`     // YYYY-MM-DD, chaîne vide pour retirer l'échéance
+	RenewalDate    *string                      `json:"renewal_date,omitempty"` // YYYY-MM-DD, chaîne vide pour retirer la date
+	Contacts       *[]SupportContractContactDTO `json:"contacts,omitempty" binding:"omitempty,max=20,dive"`
+	CoveredModules *[]string                    `json:"covered_modules,omitempty" binding:"omitempty,max=50,dive,max=100"`
+	Notes          *string                      `json:"notes,omitempty"`
+	IsActive       *bool                        `json:"is_active,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// SyncEntityChangesDTO représente les IDs modifiés d'une entité depuis la dernière synchronisation
+type SyncEntityChangesDTO struct {
+	Created []uint `json:"created"` // Créés depuis since
+	Updated []uint `json:"updated"` // Existants avant since et modifiés depuis
+	Deleted []uint `json:"deleted"` // Supprimés depuis since
+}
+
+// SyncChangesDTO représente la réponse de synchronisation incrémentale
+type SyncChangesDTO struct {
+	Cursor     string                          `json:"cursor"`      // À renvoyer dans since à la prochaine synchronisation
+	HasMore    bool                            `json:"has_more"`    // Modifications restantes : rappeler immédiatement avec le nouveau curseur
+	Reset      bool                            `json:"reset"`       // since trop ancien ou absent : liste complète, le client doit écarter ses données locales absentes
+	ServerTime time.Time                       `json:"server_time"` // Heure du serveur
+	Changes    map[string]SyncEntityChangesDTO `json:"changes"`     // Par entité : tickets, comments, notifications, tasks
+}
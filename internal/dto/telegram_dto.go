@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// TelegramLinkDTO état de la liaison du compte au bot Telegram
+type TelegramLinkDTO struct {
+	Enabled          bool       `json:"enabled"` // Bot configuré sur cette instance
+	Linked           bool       `json:"linked"`
+	TelegramUsername string     `json:"telegram_username,omitempty"`
+	LinkedAt         *time.Time `json:"linked_at,omitempty"`
+	LinkURL          string     `json:"link_url,omitempty"`        // Lien à ouvrir dans Telegram, retourné à la demande de liaison
+	LinkExpiresAt    *time.Time `json:"link_expires_at,omitempty"` // Expiration du lien de liaison
+}
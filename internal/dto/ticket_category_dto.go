@@ -10,6 +10,7 @@ type TicketCategoryDTO struct {
 	Color        string `json:"color,omitempty"`
 	IsActive     bool   `json:"is_active"`
 	DisplayOrder int    `json:"display_order"`
+	KnowledgeCategoryID *uint `json:"knowledge_category_id,omitempty"` // Catégorie KB des articles tirés des solutions
 }
 
 // CreateTicketCategoryRequest représente la requête de création d'une catégorie
@@ -21,6 +22,7 @@ type CreateTicketCategoryRequest struct {
 	Color        string `json:"color,omitempty"`                 // Couleur (optionnel)
 	IsActive     bool   `json:"is_active,omitempty"`             // Actif (optionnel, défaut: true)
 	DisplayOrder int    `json:"display_order,omitempty"`         // Ordre d'affichage (optionnel, défaut: 0)
+	KnowledgeCategoryID *uint `json:"knowledge_category_id,omitempty"` // Catégorie KB des articles tirés des solutions (optionnel)
 }
 
 // UpdateTicketCategoryRequest représente la requête de mise à jour d'une catégorie
@@ -32,4 +34,5 @@ type UpdateTicketCategoryRequest struct {
 	Color        string `json:"color,omitempty"`       // Couleur (optionnel)
 	IsActive     *bool  `json:"is_active,omitempty"`    // Actif (optionnel)
 	DisplayOrder *int   `json:"display_order,omitempty"` // Ordre d'affichage (optionnel)
+	KnowledgeCategoryID *uint `json:"knowledge_category_id,omitempty"` // Catégorie KB des articles tirés des solutions (optionnel, 0 pour la retirer)
 }
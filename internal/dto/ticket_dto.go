@@ -4,37 +4,50 @@ import "time"
 
 // TicketDTO représente un ticket dans les réponses API
 type TicketDTO struct {
-	ID                  uint                `json:"id"`
-	Code                string              `json:"code"` // Code unique: TKT-YYYY-NNNN
-	Title               string              `json:"title"`
-	Description         string              `json:"description"`
-	Category            string              `json:"category"`                       // incident, demande, changement, developpement
-	Source              string              `json:"source"`                         // mail, appel, direct
-	Status              string              `json:"status"`                         // ouvert, en_cours, en_attente, cloture
-	Priority            string              `json:"priority"`                       // low, medium, high, critical
-	AssignedTo          *UserDTO            `json:"assigned_to,omitempty"`          // Utilisateur assigné (optionnel)
-	Assignees           []TicketAssigneeDTO `json:"assignees,omitempty"`            // Utilisateurs assignés
-	Lead                *UserDTO            `json:"lead,omitempty"`                 // Responsable (lead)
-	CreatedBy           UserDTO             `json:"created_by"`                     // Créateur du ticket (informaticien)
-	RequesterID         *uint               `json:"requester_id,omitempty"`         // ID du demandeur (relation vers users)
-	Requester           *UserDTO            `json:"requester,omitempty"`            // Demandeur (relation vers users)
-	RequesterName       string              `json:"requester_name,omitempty"`       // Nom de la personne qui a fait la demande (fallback pour demandeurs externes)
-	RequesterDepartment string              `json:"requester_department,omitempty"` // Département du demandeur
-	FilialeID           *uint               `json:"filiale_id,omitempty"`           // ID de la filiale
-	Filiale             *FilialeDTO         `json:"filiale,omitempty"`              // Filiale (optionnel)
-	SoftwareID          *uint               `json:"software_id,omitempty"`          // ID du logiciel concerné
-	Software            *SoftwareDTO        `json:"software,omitempty"`             // Logiciel (optionnel)
-	ValidatedByUserID   *uint               `json:"validated_by_user_id,omitempty"` // ID de l'utilisateur qui a validé
-	ValidatedBy         *UserDTO            `json:"validated_by,omitempty"`         // Utilisateur qui a validé (optionnel)
-	ValidatedAt         *time.Time          `json:"validated_at,omitempty"`         // Date de validation
-	EstimatedTime       *int                `json:"estimated_time,omitempty"`       // Temps estimé en minutes (optionnel)
-	ActualTime          *int                `json:"actual_time,omitempty"`          // Temps réel en minutes (optionnel)
-	PrimaryImage        *string             `json:"primary_image,omitempty"`        // Image principale (optionnel)
-	ParentID            *uint               `json:"parent_id,omitempty"`            // Ticket parent (optionnel)
-	SubTickets          []TicketDTO         `json:"sub_tickets,omitempty"`          // Sous-tickets (optionnel)
-	CreatedAt           time.Time           `json:"created_at"`
-	UpdatedAt           time.Time           `json:"updated_at"`
-	ClosedAt            *time.Time          `json:"closed_at,omitempty"`
+	ID                  uint                       `json:"id"`
+	Code                string                     `json:"code"` // Code unique: TKT-YYYY-NNNN
+	Title               string                     `json:"title"`
+	Description         string                     `json:"description"`
+	DescriptionFormat   string                     `json:"description_format"`             // text, markdown, html
+	DescriptionHTML     string                     `json:"description_html"`               // Description rendue en HTML assaini (à afficher telle quelle)
+	Category            string                     `json:"category"`                       // incident, demande, changement, developpement
+	Source              string                     `json:"source"`                         // mail, appel, direct
+	Status              string                     `json:"status"`                         // ouvert, en_cours, en_attente, cloture, fusionne
+	Priority            string                     `json:"priority"`                       // low, medium, high, critical
+	AssignedTo          *UserDTO                   `json:"assigned_to,omitempty"`          // Utilisateur assigné (optionnel)
+	Assignees           []TicketAssigneeDTO        `json:"assignees,omitempty"`            // Utilisateurs assignés
+	Watchers            []UserDTO                  `json:"watchers,omitempty"`             // Utilisateurs qui suivent le ticket (détail uniquement)
+	Lead                *UserDTO                   `json:"lead,omitempty"`                 // Responsable (lead)
+	CreatedBy           UserDTO                    `json:"created_by"`                     // Créateur du ticket (informaticien)
+	RequesterID         *uint                      `json:"requester_id,omitempty"`         // ID du demandeur (relation vers users)
+	Requester           *UserDTO                   `json:"requester,omitempty"`            // Demandeur (relation vers users)
+	RequesterName       string                     `json:"requester_name,omitempty"`       // Nom de la personne qui a fait la demande (fallback pour demandeurs externes)
+	RequesterDepartment string                     `json:"requester_department,omitempty"` // Département du demandeur
+	FilialeID           *uint                      `json:"filiale_id,omitempty"`           // ID de la filiale
+	Filiale             *FilialeDTO                `json:"filiale,omitempty"`              // Filiale (optionnel)
+	SoftwareID          *uint                      `json:"software_id,omitempty"`          // ID du logiciel concerné
+	Software            *SoftwareDTO               `json:"software,omitempty"`             // Logiciel (optionnel)
+	FixedInReleaseID    *uint                      `json:"fixed_in_release_id,omitempty"`  // Version corrective du logiciel
+	FixedInRelease      *ReleaseSummaryDTO         `json:"fixed_in_release,omitempty"`     // Version corrective (optionnel)
+	EnvironmentID       *uint                      `json:"environment_id,omitempty"`       // Environnement concerné
+	Environment         *EnvironmentSummaryDTO     `json:"environment,omitempty"`          // Environnement concerné (optionnel)
+	SupportContract     *SupportContractSummaryDTO `json:"support_contract,omitempty"`     // Contrat de support couvrant le ticket (détail uniquement)
+	JiraIssue           *JiraLinkDTO               `json:"jira_issue,omitempty"`           // Ticket Jira synchronisé (optionnel)
+	ValidatedByUserID   *uint                      `json:"validated_by_user_id,omitempty"` // ID de l'utilisateur qui a validé
+	ValidatedBy         *UserDTO                   `json:"validated_by,omitempty"`         // Utilisateur qui a validé (optionnel)
+	ValidatedAt         *time.Time                 `json:"validated_at,omitempty"`         // Date de validation
+	EstimatedTime       *int                       `json:"estimated_time,omitempty"`       // Temps estimé en minutes (optionnel)
+	ActualTime          *int                       `json:"actual_time,omitempty"`          // Temps réel en minutes (optionnel)
+	PrimaryImage        *string                    `json:"primary_image,omitempty"`        // Image principale (optionnel)
+	ParentID            *uint                      `json:"parent_id,omitempty"`            // Ticket parent (optionnel)
+	MergedIntoID        *uint                      `json:"merged_into_id,omitempty"`       // Ticket principal (ticket fusionné)
+	ChecklistProgress   *int                       `json:"checklist_progress,omitempty"`   // Avancement de la checklist en pourcentage (absent sans checklist)
+	SubTickets          []TicketDTO                `json:"sub_tickets,omitempty"`          // Sous-tickets (optionnel)
+	Relations           []TicketRelationDTO        `json:"relations,omitempty"`            // Tickets liés (détail uniquement)
+	CreatedAt           time.Time                  `json:"created_at"`
+	UpdatedAt           time.Time                  `json:"updated_at"`
+	ClosedAt            *time.Time                 `json:"closed_at,omitempty"`
+	Warnings            []string                   `json:"warnings,omitempty"` // Avertissements de l'opération (ex: assigné absent)
 }
 
 // TicketAssigneeDTO représente une assignation d'un utilisateur à un ticket
@@ -45,26 +58,29 @@ type TicketAssigneeDTO struct {
 
 // CreateTicketRequest représente la requête de création d'un ticket
 type CreateTicketRequest struct {
-	Title               string `json:"title" binding:"required"`                                              // Titre (obligatoire)
-	Description         string `json:"description" binding:"required"`                                        // Description (obligatoire)
-	Category            string `json:"category" binding:"required"`                                           // Slug de la catégorie (doit exister dans ticket_categories et être active)
-	Source              string `json:"source" binding:"required,oneof=mail appel direct whatsapp kronos"`     // Source (obligatoire)
-	Priority            string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"` // Priorité (optionnel)
-	EstimatedTime       *int   `json:"estimated_time,omitempty"`                                              // Temps estimé en minutes (optionnel)
-	RequesterID         *uint  `json:"requester_id,omitempty"`                                                // ID du demandeur (optionnel, prioritaire sur requester_name)
-	RequesterName       string `json:"requester_name,omitempty"`                                              // Nom de la personne qui a fait la demande (obligatoire si requester_id non fourni)
-	RequesterDepartment string `json:"requester_department" binding:"required"`                               // Département du demandeur (obligatoire)
-	FilialeID           *uint  `json:"filiale_id,omitempty"`                                                  // ID de la filiale (optionnel, défini automatiquement depuis l'utilisateur créateur)
-	SoftwareID          *uint  `json:"software_id,omitempty"`                                                 // ID du logiciel concerné (optionnel)
-	ParentID            *uint  `json:"parent_id,omitempty"`                                                   // Ticket parent (optionnel)
-	AssigneeIDs         []uint `json:"assignee_ids,omitempty"`                                                // Assignés (optionnel)
-	LeadID              *uint  `json:"lead_id,omitempty"`                                                     // Responsable (optionnel)
+	Title               string `json:"title" binding:"required"`                                                  // Titre (obligatoire)
+	Description         string `json:"description" binding:"required"`                                            // Description (obligatoire)
+	DescriptionFormat   string `json:"description_format,omitempty" binding:"omitempty,oneof=text markdown html"` // Format de la description (optionnel, défaut: text) ; images intégrées (data URI) enregistrées en pièces jointes
+	Category            string `json:"category" binding:"required"`                                               // Slug de la catégorie (doit exister dans ticket_categories et être active)
+	Source              string `json:"source" binding:"required,oneof=mail appel direct whatsapp kronos"`         // Source (obligatoire)
+	Priority            string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`     // Priorité (optionnel)
+	EstimatedTime       *int   `json:"estimated_time,omitempty"`                                                  // Temps estimé en minutes (optionnel)
+	RequesterID         *uint  `json:"requester_id,omitempty"`                                                    // ID du demandeur (optionnel, prioritaire sur requester_name)
+	RequesterName       string `json:"requester_name,omitempty"`                                                  // Nom de la personne qui a fait la demande (obligatoire si requester_id non fourni)
+	RequesterDepartment string `json:"requester_department" binding:"required"`                                   // Département du demandeur (obligatoire)
+	FilialeID           *uint  `json:"filiale_id,omitempty"`                                                      // ID de la filiale (optionnel, défini automatiquement depuis l'utilisateur créateur)
+	SoftwareID          *uint  `json:"software_id,omitempty"`                                                     // ID du logiciel concerné (optionnel)
+	EnvironmentID       *uint  `json:"environment_id,omitempty"`                                                  // Environnement concerné (optionnel, du logiciel chez la filiale du ticket)
+	ParentID            *uint  `json:"parent_id,omitempty"`                                                       // Ticket parent (optionnel)
+	AssigneeIDs         []uint `json:"assignee_ids,omitempty"`                                                    // Assignés (optionnel)
+	LeadID              *uint  `json:"lead_id,omitempty"`                                                         // Responsable (optionnel)
 }
 
 // UpdateTicketRequest représente la requête de mise à jour d'un ticket
 type UpdateTicketRequest struct {
 	Title               string `json:"title,omitempty"`                                                                      // Titre (optionnel)
 	Description         string `json:"description,omitempty"`                                                                // Description (optionnel)
+	DescriptionFormat   string `json:"description_format,omitempty" binding:"omitempty,oneof=text markdown html"`            // Format de la description (optionnel, défaut: format actuel)
 	Category            string `json:"category,omitempty" binding:"omitempty"`                                               // Slug de la catégorie (optionnel ; si fourni, doit exister et être active)
 	Status              string `json:"status,omitempty" binding:"omitempty,oneof=ouvert en_cours en_attente resolu cloture"` // Statut (optionnel, ajout de "resolu")
 	Priority            string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`                // Priorité (optionnel)
@@ -72,6 +88,7 @@ type UpdateTicketRequest struct {
 	RequesterName       string `json:"requester_name,omitempty"`                                                             // Nom du demandeur (optionnel, fallback)
 	RequesterDepartment string `json:"requester_department,omitempty"`                                                       // Département du demandeur (optionnel)
 	SoftwareID          *uint  `json:"software_id,omitempty"`                                                                // ID du logiciel concerné (optionnel)
+	EnvironmentID       *uint  `json:"environment_id,omitempty"`                                                             // Environnement concerné (optionnel, 0 pour le retirer)
 	ParentID            *uint  `json:"parent_id,omitempty"`                                                                  // Ticket parent (optionnel)
 	AssigneeIDs         []uint `json:"assignee_ids,omitempty"`                                                               // Assignés (optionnel)
 	LeadID              *uint  `json:"lead_id,omitempty"`                                                                    // Responsable (optionnel)
@@ -94,24 +111,28 @@ type TicketListResponse struct {
 
 // TicketCommentDTO représente un commentaire sur un ticket
 type TicketCommentDTO struct {
-	ID         uint      `json:"id"`
-	TicketID   uint      `json:"ticket_id"`
-	User       UserDTO   `json:"user"`
-	Comment    string    `json:"comment"`
-	IsInternal bool      `json:"is_internal"` // Commentaire interne (visible uniquement par l'IT)
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID          uint      `json:"id"`
+	TicketID    uint      `json:"ticket_id"`
+	User        UserDTO   `json:"user"`
+	Comment     string    `json:"comment"`
+	Format      string    `json:"format"`       // text, markdown, html
+	CommentHTML string    `json:"comment_html"` // Commentaire rendu en HTML assaini (à afficher tel quel)
+	IsInternal  bool      `json:"is_internal"`  // Commentaire interne (visible uniquement par l'IT)
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 // CreateTicketCommentRequest représente la requête de création d'un commentaire
 type CreateTicketCommentRequest struct {
-	Comment    string `json:"comment" binding:"required"` // Commentaire (obligatoire)
-	IsInternal bool   `json:"is_internal,omitempty"`      // Commentaire interne (optionnel, défaut: false)
+	Comment    string `json:"comment" binding:"required"`                                    // Commentaire (obligatoire)
+	Format     string `json:"format,omitempty" binding:"omitempty,oneof=text markdown html"` // Format du commentaire (optionnel, défaut: text)
+	IsInternal bool   `json:"is_internal,omitempty"`                                         // Commentaire interne (optionnel, défaut: false)
 }
 
 // UpdateTicketCommentRequest représente la requête de mise à jour d'un commentaire (texte uniquement)
 type UpdateTicketCommentRequest struct {
-	Comment string `json:"comment" binding:"required"` // Nouveau texte du commentaire
+	Comment string `json:"comment" binding:"required"`                                    // Nouveau texte du commentaire
+	Format  string `json:"format,omitempty" binding:"omitempty,oneof=text markdown html"` // Format (optionnel, défaut: format actuel)
 }
 
 // TicketHistoryDTO représente une entrée d'historique d'un ticket
@@ -162,12 +183,14 @@ type ReorderTicketAttachmentsRequest struct {
 
 // TicketSolutionDTO représente une solution documentée pour un ticket
 type TicketSolutionDTO struct {
-	ID        uint      `json:"id"`
-	TicketID  uint      `json:"ticket_id"`
-	Solution  string    `json:"solution"` // Solution documentée (Markdown)
-	CreatedBy UserDTO   `json:"created_by"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `json:"id"`
+	TicketID     uint      `json:"ticket_id"`
+	Solution     string    `json:"solution"`             // Solution documentée (Markdown)
+	SolutionHTML string    `json:"solution_html"`        // Solution rendue en HTML assaini
+	ArticleID    *uint     `json:"article_id,omitempty"` // Article de la base de connaissances rédigé à partir de la solution
+	CreatedBy    UserDTO   `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // CreateTicketSolutionRequest représente la requête de création d'une solution
@@ -185,3 +208,53 @@ type PublishSolutionToKBRequest struct {
 	Title      string `json:"title" binding:"required"`       // Titre de l'article (obligatoire)
 	CategoryID uint   `json:"category_id" binding:"required"` // ID de la catégorie KB (obligatoire)
 }
+
+// PublishSolutionAsArticleRequest représente la rédaction d'un article de la base de connaissances à partir d'une solution
+type PublishSolutionAsArticleRequest struct {
+	Title         string `json:"title,omitempty" binding:"omitempty,max=255"` // Titre (optionnel, défaut: titre du ticket)
+	CategoryID    *uint  `json:"category_id,omitempty"`                       // Catégorie KB (optionnel, défaut: catégorie associée à la catégorie du ticket)
+	Symptoms      string `json:"symptoms,omitempty"`                          // Symptômes (optionnel, défaut: description du ticket)
+	AttachmentIDs []uint `json:"attachment_ids,omitempty"`                    // Pièces jointes du ticket à reprendre (optionnel, défaut: toutes)
+}
+
+// TicketChecklistItemDTO représente une étape de la checklist d'un ticket
+type TicketChecklistItemDTO struct {
+	ID           uint       `json:"id"`
+	TicketID     uint       `json:"ticket_id"`
+	Label        string     `json:"label"`
+	Done         bool       `json:"done"`
+	AssigneeID   *uint      `json:"assignee_id,omitempty"`
+	Assignee     *UserDTO   `json:"assignee,omitempty"`
+	DisplayOrder int        `json:"display_order"`
+	DoneAt       *time.Time `json:"done_at,omitempty"`
+	DoneBy       *UserDTO   `json:"done_by,omitempty"`
+	CreatedByID  uint       `json:"created_by_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TicketChecklistDTO représente la checklist d'un ticket et son avancement
+type TicketChecklistDTO struct {
+	Items    []TicketChecklistItemDTO `json:"items"`
+	Total    int                      `json:"total"`
+	Done     int                      `json:"done"`
+	Progress int                      `json:"progress"` // Pourcentage d'éléments cochés
+}
+
+// CreateTicketChecklistItemRequest représente la requête d'ajout d'un élément de checklist
+type CreateTicketChecklistItemRequest struct {
+	Label      string `json:"label" binding:"required,max=255"` // Libellé de l'étape (obligatoire)
+	AssigneeID *uint  `json:"assignee_id,omitempty"`            // Utilisateur chargé de l'étape (optionnel)
+}
+
+// UpdateTicketChecklistItemRequest représente la requête de mise à jour d'un élément de checklist
+type UpdateTicketChecklistItemRequest struct {
+	Label         *string `json:"label,omitempty" binding:"omitempty,max=255"` // Libellé (optionnel)
+	AssigneeID    *uint   `json:"assignee_id,omitempty"`                       // Utilisateur chargé de l'étape (optionnel)
+	ClearAssignee bool    `json:"clear_assignee,omitempty"`                    // Retirer l'utilisateur chargé de l'étape
+}
+
+// ReorderTicketChecklistRequest représente la requête de réorganisation de la checklist
+type ReorderTicketChecklistRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required"` // Liste des IDs dans le nouvel ordre
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// MergeTicketsRequest représente la requête de fusion de tickets en double dans un ticket principal
+type MergeTicketsRequest struct {
+	DuplicateIDs []uint `json:"duplicate_ids" binding:"required,min=1,max=50,dive,min=1"` // Tickets en double à fusionner (obligatoire)
+}
+
+// TicketMergeResultDTO représente le résultat d'une fusion de tickets
+type TicketMergeResultDTO struct {
+	Ticket           TicketDTO `json:"ticket"`             // Ticket principal après fusion
+	MergedTicketIDs  []uint    `json:"merged_ticket_ids"`  // Tickets passés au statut fusionne
+	MovedComments    int64     `json:"moved_comments"`     // Commentaires rattachés au ticket principal
+	MovedAttachments int64     `json:"moved_attachments"`  // Pièces jointes rattachées au ticket principal
+	MovedTimeEntries int64     `json:"moved_time_entries"` // Entrées de temps rattachées au ticket principal
+	SLAMoved         bool      `json:"sla_moved"`          // SLA d'un doublon repris par le ticket principal
+}
+
+// TicketDuplicateDTO représente un ticket suggéré comme doublon
+type TicketDuplicateDTO struct {
+	ID         uint      `json:"id"`
+	Code       string    `json:"code"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status"`
+	Priority   string    `json:"priority"`
+	Similarity float64   `json:"similarity"` // Similarité des titres, entre 0 et 1
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// LinkTicketToProjectTaskRequest représente la requête de liaison d'un ticket à une tâche (depuis la tâche)
+type LinkTicketToProjectTaskRequest struct {
+	TicketID uint `json:"ticket_id" binding:"required"` // Ticket à lier
+}
+
+// LinkProjectTaskToTicketRequest représente la requête de liaison d'une tâche de projet à un ticket (depuis le ticket)
+type LinkProjectTaskToTicketRequest struct {
+	ProjectTaskID uint `json:"project_task_id" binding:"required"` // Tâche à lier
+}
+
+// ProjectTaskTicketDTO représente un ticket lié à une tâche de projet
+type ProjectTaskTicketDTO struct {
+	ID        uint             `json:"id"` // ID du lien
+	Ticket    RelatedTicketDTO `json:"ticket"`
+	TimeSpent int              `json:"time_spent"` // Temps saisi sur le ticket, en minutes (reporté dans le ticket_time de la tâche)
+	LinkedAt  time.Time        `json:"linked_at"`
+}
+
+// TicketProjectTaskDTO représente une tâche de projet liée à un ticket
+type TicketProjectTaskDTO struct {
+	ID          uint      `json:"id"` // ID du lien
+	ProjectID   uint      `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	TaskID      uint      `json:"task_id"`
+	TaskCode    string    `json:"task_code"`
+	TaskTitle   string    `json:"task_title"`
+	TaskStatus  string    `json:"task_status"`
+	LinkedAt    time.Time `json:"linked_at"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// TicketRelationDTO représente un lien vers un autre ticket, du point de vue du ticket consulté
+type TicketRelationDTO struct {
+	ID        uint             `json:"id"`
+	Type      string           `json:"type"` // relates_to, blocks, blocked_by, caused_by, causes, duplicates, duplicated_by
+	Ticket    RelatedTicketDTO `json:"ticket"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// RelatedTicketDTO représente un ticket lié (résumé)
+type RelatedTicketDTO struct {
+	ID       uint   `json:"id"`
+	Code     string `json:"code"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+}
+
+// CreateTicketRelationRequest représente la requête de liaison de deux tickets
+type CreateTicketRelationRequest struct {
+	TicketID uint   `json:"ticket_id" binding:"required"`                                                    // Ticket à lier
+	Type     string `json:"type" binding:"required,oneof=relates_to blocks blocked_by caused_by duplicates"` // Type de relation, du point de vue du ticket de l'URL
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// TicketSatisfactionSurveyDTO représente l'enquête de satisfaction d'un ticket, vue depuis le lien envoyé au demandeur
+type TicketSatisfactionSurveyDTO struct {
+	TicketID    uint       `json:"ticket_id"`
+	TicketCode  string     `json:"ticket_code"`
+	TicketTitle string     `json:"ticket_title"`
+	Answered    bool       `json:"answered"`
+	Rating      *int       `json:"rating,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	AnsweredAt  *time.Time `json:"answered_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+}
+
+// SubmitTicketSatisfactionRequest représente la réponse du demandeur à l'enquête de satisfaction
+type SubmitTicketSatisfactionRequest struct {
+	Token   string `json:"token" binding:"required"`              // Token du lien de l'enquête
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"` // Note de 1 (très insatisfait) à 5 (très satisfait)
+	Comment string `json:"comment,omitempty" binding:"max=2000"`  // Commentaire libre (optionnel)
+}
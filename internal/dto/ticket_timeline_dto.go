@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// TicketTimelineDTO représente une page du fil d'activité d'un ticket
+type TicketTimelineDTO struct {
+	Items      []TicketTimelineEntryDTO `json:"items"`
+	NextCursor string                   `json:"next_cursor,omitempty"` // À renvoyer dans cursor pour la page suivante (absent en fin de fil)
+	HasMore    bool                     `json:"has_more"`
+}
+
+// TicketTimelineEntryDTO représente un élément du fil d'activité ; seuls les champs du type de l'élément sont renseignés
+type TicketTimelineEntryDTO struct {
+	Type        string     `json:"type"` // history, assignment, comment, attachment, time_entry, sla
+	ID          uint       `json:"id"`   // ID de l'élément d'origine (rang de l'événement pour le SLA)
+	At          time.Time  `json:"at"`
+	User        *UserDTO   `json:"user,omitempty"`
+	Action      string     `json:"action,omitempty"` // Action de l'historique, ou événement SLA (sla_started, sla_at_risk, sla_violated, sla_met)
+	FieldName   string     `json:"field_name,omitempty"`
+	OldValue    string     `json:"old_value,omitempty"`
+	NewValue    string     `json:"new_value,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	CommentHTML string     `json:"comment_html,omitempty"` // Commentaire rendu en HTML assaini
+	IsInternal  bool       `json:"is_internal,omitempty"`
+	FileName    string     `json:"file_name,omitempty"`
+	MimeType    string     `json:"mime_type,omitempty"`
+	FileSize    *int       `json:"file_size,omitempty"`
+	IsImage     bool       `json:"is_image,omitempty"`
+	TimeSpent   *int       `json:"time_spent,omitempty"`  // Temps passé en minutes
+	Date        *time.Time `json:"date,omitempty"`        // Jour de travail déclaré (temps passé)
+	TargetTime  *time.Time `json:"target_time,omitempty"` // Échéance SLA
+	Description string     `json:"description,omitempty"`
+}
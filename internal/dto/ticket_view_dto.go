@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+// TicketViewFiltersDTO représente la définition du filtre d'une vue de tickets (critères combinés par ET)
+type TicketViewFiltersDTO struct {
+	Statuses     []string `json:"statuses,omitempty" binding:"omitempty,dive,oneof=ouvert en_cours en_attente resolu cloture"` // Statuts (un parmi)
+	Priorities   []string `json:"priorities,omitempty" binding:"omitempty,dive,oneof=low medium high critical"`                // Priorités (une parmi)
+	FilialeID    *uint    `json:"filiale_id,omitempty"`                                                                        // Filiale du ticket
+	AssigneeID   *uint    `json:"assignee_id,omitempty"`                                                                       // Assigné (principal ou co-assigné)
+	AssignedToMe bool     `json:"assigned_to_me,omitempty"`                                                                    // Tickets assignés à l'utilisateur qui consulte la vue (prioritaire sur assignee_id)
+	CreatedFrom  string   `json:"created_from,omitempty"`                                                                      // Date de création minimale incluse (YYYY-MM-DD)
+	CreatedTo    string   `json:"created_to,omitempty"`                                                                        // Date de création maximale incluse (YYYY-MM-DD)
+	Text         string   `json:"text,omitempty" binding:"omitempty,max=200"`                                                  // Texte recherché dans le code, le titre et la description
+}
+
+// TicketViewDTO représente une vue de tickets enregistrée
+type TicketViewDTO struct {
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	UserID      uint                 `json:"user_id"` // Propriétaire
+	User        *UserDTO             `json:"user,omitempty"`
+	IsShared    bool                 `json:"is_shared"`
+	IsOwner     bool                 `json:"is_owner"` // L'utilisateur courant peut modifier la vue
+	Filters     TicketViewFiltersDTO `json:"filters"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// CreateTicketViewRequest représente la requête de création d'une vue de tickets
+type CreateTicketViewRequest struct {
+	Name        string               `json:"name" binding:"required,max=150"` // Nom (obligatoire)
+	Description string               `json:"description,omitempty"`           // Description (optionnel)
+	IsShared    bool                 `json:"is_shared,omitempty"`             // Partagée avec tous les utilisateurs (optionnel, défaut: false)
+	Filters     TicketViewFiltersDTO `json:"filters"`                         // Définition du filtre
+}
+
+// UpdateTicketViewRequest représente la requête de mise à jour d'une vue de tickets
+type UpdateTicketViewRequest struct {
+	Name        *string               `json:"name,omitempty" binding:"omitempty,max=150"`
+	Description *string               `json:"description,omitempty"`
+	IsShared    *bool                 `json:"is_shared,omitempty"`
+	Filters     *TicketViewFiltersDTO `json:"filters,omitempty"` // Remplace la définition du filtre
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// TicketWatcherDTO représente un utilisateur qui suit un ticket
+type TicketWatcherDTO struct {
+	User      UserDTO   `json:"user"`
+	AddedByID uint      `json:"added_by_id"` // Utilisateur à l'origine de l'abonnement
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WatchTicketRequest représente la requête d'abonnement à un ticket
+type WatchTicketRequest struct {
+	UserID *uint `json:"user_id,omitempty"` // Utilisateur abonné (optionnel, défaut: l'utilisateur connecté ; un tiers nécessite tickets.update)
+}
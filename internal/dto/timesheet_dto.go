@@ -13,6 +13,8 @@ type TimeEntryDTO struct {
 	TimeSpent   int        `json:"time_spent"`
 	Date        time.Time  `json:"date"`
 	Description string     `json:"description,omitempty"`
+	WorkCategory string    `json:"work_category"` // support, project, internal
+	Billable    bool       `json:"billable"`
 	Validated   bool       `json:"validated"`
 	ValidatedBy *uint      `json:"validated_by,omitempty"`
 	ValidatedAt *time.Time `json:"validated_at,omitempty"`
@@ -26,6 +28,8 @@ type CreateTimeEntryRequest struct {
 	TimeSpent   int    `json:"time_spent" binding:"required"`
 	Date        string `json:"date" binding:"required"` // Format: YYYY-MM-DD
 	Description string `json:"description,omitempty"`
+	WorkCategory string `json:"work_category,omitempty" binding:"omitempty,oneof=support project internal"` // Défaut : project si le ticket est lié à une tâche de projet, sinon support
+	Billable    *bool  `json:"billable,omitempty"`                                                          // Défaut : facturable sauf travail interne
 }
 
 // UpdateTimeEntryRequest représente la requête de mise à jour d'une entrée de temps
@@ -33,6 +37,8 @@ type UpdateTimeEntryRequest struct {
 	TimeSpent   int    `json:"time_spent,omitempty"`
 	Date        string `json:"date,omitempty"` // Format: YYYY-MM-DD
 	Description string `json:"description,omitempty"`
+	WorkCategory string `json:"work_category,omitempty" binding:"omitempty,oneof=support project internal"` // Entrées sur ticket uniquement
+	Billable    *bool  `json:"billable,omitempty"`
 }
 
 // ValidateTimeEntryRequest représente la requête de validation d'une entrée de temps
@@ -67,15 +73,36 @@ type WeeklyDeclarationDTO struct {
 	EndDate       time.Time          `json:"end_date"`
 	TaskCount     int                `json:"task_count"`
 	TotalTime     int                `json:"total_time"`
+	Status        string             `json:"status"` // draft, submitted, approved (semaine verrouillée), rejected
+	SubmittedAt   *time.Time         `json:"submitted_at,omitempty"`
 	Validated     bool               `json:"validated"`
 	ValidatedBy   *uint              `json:"validated_by,omitempty"`
 	ValidatedAt   *time.Time         `json:"validated_at,omitempty"`
 	ValidationComment string          `json:"validation_comment,omitempty"`
 	DailyBreakdown []DailyBreakdownDTO `json:"daily_breakdown,omitempty"`
+	ExpectedTime  int                `json:"expected_time"`  // Temps attendu (minutes) : jours ouvrés hors absences approuvées
+	AbsenceDays   int                `json:"absence_days"`   // Jours ouvrés couverts par une absence approuvée
+	UnderDeclared bool               `json:"under_declared"` // Temps déclaré inférieur au temps attendu
 	CreatedAt     time.Time          `json:"created_at"`
 	UpdatedAt     time.Time          `json:"updated_at"`
 }
 
+// WeeklyDeclarationActionRequest représente le commentaire d'une étape du circuit de validation
+// (obligatoire pour un rejet ou un déverrouillage)
+type WeeklyDeclarationActionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// WeeklyDeclarationEventDTO représente un changement de statut d'une déclaration hebdomadaire
+type WeeklyDeclarationEventDTO struct {
+	ID          uint      `json:"id"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	Comment     string    `json:"comment,omitempty"`
+	PerformedBy *UserDTO  `json:"performed_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 // DailyTaskRequest représente une requête pour créer/mettre à jour une tâche journalière
 type DailyTaskRequest struct {
 	TicketID  uint `json:"ticket_id" binding:"required"`
@@ -93,18 +120,24 @@ type DailyTaskDTO struct {
 
 // DailySummaryDTO représente le résumé d'une déclaration journalière
 type DailySummaryDTO struct {
-	Date      time.Time `json:"date"`
-	TaskCount int       `json:"task_count"`
-	TotalTime int       `json:"total_time"`
-	Validated bool      `json:"validated"`
+	Date          time.Time `json:"date"`
+	TaskCount     int       `json:"task_count"`
+	TotalTime     int       `json:"total_time"`
+	Validated     bool      `json:"validated"`
+	ExpectedTime  int       `json:"expected_time"`     // Temps attendu (minutes) : 0 le week-end et les jours d'absence approuvée
+	Absence       string    `json:"absence,omitempty"` // Type d'absence approuvée couvrant le jour (vacation, sick, training)
+	UnderDeclared bool      `json:"under_declared"`    // Temps déclaré inférieur au temps attendu
 }
 
 // DailyCalendarDTO représente une entrée du calendrier journalier
 type DailyCalendarDTO struct {
-	Date      time.Time `json:"date"`
-	HasEntry  bool      `json:"has_entry"`
-	TotalTime int       `json:"total_time"`
-	Validated bool      `json:"validated"`
+	Date          time.Time `json:"date"`
+	HasEntry      bool      `json:"has_entry"`
+	TotalTime     int       `json:"total_time"`
+	Validated     bool      `json:"validated"`
+	ExpectedTime  int       `json:"expected_time"`     // Temps attendu (minutes) : 0 le week-end et les jours d'absence approuvée
+	Absence       string    `json:"absence,omitempty"` // Type d'absence approuvée couvrant le jour (vacation, sick, training)
+	UnderDeclared bool      `json:"under_declared"`    // Temps déclaré inférieur au temps attendu
 }
 
 // WeeklyTaskRequest représente une requête pour créer/mettre à jour une tâche hebdomadaire
@@ -143,9 +176,14 @@ type DailyBreakdownDTO struct {
 
 // ValidationStatusDTO représente le statut de validation
 type ValidationStatusDTO struct {
-	Validated   bool       `json:"validated"`
-	ValidatedBy *uint      `json:"validated_by,omitempty"`
-	ValidatedAt *time.Time `json:"validated_at,omitempty"`
+	Status        string     `json:"status,omitempty"` // Statut du circuit de validation (déclarations hebdomadaires)
+	Validated     bool       `json:"validated"`
+	ValidatedBy   *uint      `json:"validated_by,omitempty"`
+	ValidatedAt   *time.Time `json:"validated_at,omitempty"`
+	DeclaredTime  int        `json:"declared_time"`  // Temps déclaré sur la semaine (minutes)
+	ExpectedTime  int        `json:"expected_time"`  // Temps attendu : jours ouvrés hors absences approuvées
+	AbsenceDays   int        `json:"absence_days"`   // Jours ouvrés couverts par une absence approuvée
+	UnderDeclared bool       `json:"under_declared"` // Temps déclaré inférieur au temps attendu
 }
 
 // EstimatedTimeDTO représente le temps estimé d'un ticket
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// UserActivityDTO représente le résumé d'activité d'un utilisateur sur une période
+type UserActivityDTO struct {
+	User           *UserDTO                `json:"user"`
+	From           string                  `json:"from"` // Premier jour de la période (YYYY-MM-DD)
+	To             string                  `json:"to"`   // Dernier jour de la période (inclus)
+	Tickets        UserActivityTicketsDTO  `json:"tickets"`
+	Comments       UserActivityCommentsDTO `json:"comments"`
+	Time           UserActivityTimeDTO     `json:"time"`
+	TasksCompleted int                     `json:"tasks_completed"` // Tâches de projet clôturées (responsable ou assigné)
+	Logins         int                     `json:"logins"`          // Connexions sur la période
+	LastLogin      *time.Time              `json:"last_login,omitempty"`
+}
+
+// UserActivityTicketsDTO activité de l'utilisateur sur les tickets
+type UserActivityTicketsDTO struct {
+	Touched  int                     `json:"touched"`  // Tickets sur lesquels l'utilisateur est intervenu
+	Created  int                     `json:"created"`  // Tickets créés
+	Resolved int                     `json:"resolved"` // Tickets passés à résolu ou clôturé par l'utilisateur
+	Recent   []UserActivityTicketDTO `json:"recent"`   // Derniers tickets touchés (20 au plus)
+}
+
+// UserActivityTicketDTO ticket touché par l'utilisateur
+type UserActivityTicketDTO struct {
+	ID           uint      `json:"id"`
+	Code         string    `json:"code"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	Actions      int       `json:"actions"` // Interventions (historique, commentaires, saisies de temps)
+	LastActionAt time.Time `json:"last_action_at"`
+}
+
+// UserActivityCommentsDTO commentaires rédigés par l'utilisateur
+type UserActivityCommentsDTO struct {
+	Tickets      int `json:"tickets"`
+	ProjectTasks int `json:"project_tasks"`
+	Total        int `json:"total"`
+}
+
+// UserActivityTimeDTO temps saisi par l'utilisateur (minutes)
+type UserActivityTimeDTO struct {
+	Entries   int `json:"entries"`
+	Total     int `json:"total"`
+	Validated int `json:"validated"`
+}
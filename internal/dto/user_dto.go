@@ -5,23 +5,27 @@ import "time"
 // UserDTO représente un utilisateur dans les réponses API
 // C'est la version "publique" du modèle User, sans les informations sensibles
 type UserDTO struct {
-	ID           uint           `json:"id"`
-	Username     string         `json:"username"`
-	Email        string         `json:"email"`
-	Phone        string         `json:"phone,omitempty"`
-	FirstName    string         `json:"first_name,omitempty"`
-	LastName     string         `json:"last_name,omitempty"`
-	DepartmentID *uint          `json:"department_id,omitempty"` // ID du département (optionnel)
-	Department   *DepartmentDTO `json:"department,omitempty"`    // Département complet (optionnel)
-	FilialeID    *uint          `json:"filiale_id,omitempty"`    // ID de la filiale (optionnel)
-	Filiale      *FilialeDTO    `json:"filiale,omitempty"`       // Filiale complète (optionnel)
-	Avatar       string         `json:"avatar,omitempty"`        // Chemin vers l'avatar
-	Role         string         `json:"role"`                    // Nom du rôle (ex: "DSI", "TECHNICIEN_IT")
-	Permissions  []string       `json:"permissions,omitempty"`   // Liste des permissions (optionnelle)
-	IsActive     bool           `json:"is_active"`
-	LastLogin    *time.Time     `json:"last_login,omitempty"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+	ID                 uint            `json:"id"`
+	Username           string          `json:"username"`
+	Email              string          `json:"email"`
+	Phone              string          `json:"phone,omitempty" mask:"users.view_phone,owner=ID"` // Masqué sans users.view_phone (sauf pour soi-même)
+	FirstName          string          `json:"first_name,omitempty"`
+	LastName           string          `json:"last_name,omitempty"`
+	DepartmentID       *uint           `json:"department_id,omitempty"`        // ID du département (optionnel)
+	Department         *DepartmentDTO  `json:"department,omitempty"`           // Département complet (optionnel)
+	FilialeID          *uint           `json:"filiale_id,omitempty"`           // ID de la filiale (optionnel)
+	Filiale            *FilialeDTO     `json:"filiale,omitempty"`              // Filiale complète (optionnel)
+	ManagerID          *uint           `json:"manager_id,omitempty"`           // Responsable hiérarchique (N+1, optionnel)
+	OutOfOffice        *OutOfOfficeDTO `json:"out_of_office,omitempty"`        // Absence déclarée (en cours ou à venir)
+	Avatar             string          `json:"avatar,omitempty"`               // Chemin vers l'avatar
+	AvatarURL          string          `json:"avatar_url,omitempty"`           // URL publique et cacheable de l'avatar
+	AvatarThumbnailURL string          `json:"avatar_thumbnail_url,omitempty"` // URL publique et cacheable de la miniature
+	Role               string          `json:"role"`                           // Nom du rôle (ex: "DSI", "TECHNICIEN_IT")
+	Permissions        []string        `json:"permissions,omitempty"`          // Liste des permissions (optionnelle)
+	IsActive           bool            `json:"is_active"`
+	LastLogin          *time.Time      `json:"last_login,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
 }
 
 // CreateUserRequest représente la requête de création d'un utilisateur
@@ -34,6 +38,7 @@ type CreateUserRequest struct {
 	Phone        string `json:"phone,omitempty"`                   // Téléphone (optionnel)
 	DepartmentID *uint  `json:"department_id,omitempty"`           // ID du département (optionnel)
 	FilialeID    *uint  `json:"filiale_id,omitempty"`              // ID de la filiale (optionnel)
+	ManagerID    *uint  `json:"manager_id,omitempty"`              // ID du responsable hiérarchique (optionnel)
 	RoleID       uint   `json:"role_id"`                           // ID du rôle (optionnel, USER par défaut)
 }
 
@@ -46,20 +51,22 @@ type UpdateUserRequest struct {
 	Phone        string `json:"phone,omitempty"`                           // Téléphone (optionnel)
 	DepartmentID *uint  `json:"department_id,omitempty"`                   // ID du département (optionnel, nil pour supprimer)
 	FilialeID    *uint  `json:"filiale_id,omitempty"`                      // ID de la filiale (optionnel, nil pour supprimer)
+	ManagerID    *uint  `json:"manager_id,omitempty"`                      // ID du responsable (optionnel, 0 pour supprimer)
 	RoleID       uint   `json:"role_id,omitempty"`                         // ID du rôle (optionnel)
 	IsActive     *bool  `json:"is_active,omitempty"`                       // Statut actif (optionnel, pointeur pour distinguer false de non fourni)
 }
 
 // RoleDTO représente un rôle dans les réponses API
 type RoleDTO struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"` // Nom du rôle (ex: "DSI", "TECHNICIEN_IT")
-	Description string    `json:"description,omitempty"`
-	IsSystem    bool      `json:"is_system"`               // Si c'est un rôle système (ne peut pas être modifié/supprimé)
-	CreatedByID *uint     `json:"created_by_id,omitempty"` // ID de l'utilisateur créateur (nil pour les rôles système)
-	FilialeID   *uint     `json:"filiale_id,omitempty"`    // ID de la filiale (nil pour les rôles globaux)
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                uint      `json:"id"`
+	Name              string    `json:"name"` // Nom du rôle (ex: "DSI", "TECHNICIEN_IT")
+	Description       string    `json:"description,omitempty"`
+	IsSystem          bool      `json:"is_system"`                    // Si c'est un rôle système (ne peut pas être modifié/supprimé)
+	CreatedByID       *uint     `json:"created_by_id,omitempty"`      // ID de l'utilisateur créateur (nil pour les rôles système)
+	FilialeID         *uint     `json:"filiale_id,omitempty"`         // ID de la filiale (nil pour les rôles globaux)
+	UtilizationTarget *float64  `json:"utilization_target,omitempty"` // Taux d'utilisation facturable visé en % (optionnel)
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // CreateRoleRequest représente la requête de création d'un rôle
@@ -76,6 +83,29 @@ type UpdateRoleRequest struct {
 	Description string `json:"description,omitempty"` // Description (optionnel)
 }
 
+// RoleTemplateDTO représente un modèle de rôle prédéfini
+type RoleTemplateDTO struct {
+	Key         string   `json:"key"`  // Identifiant du modèle (ex: "dsi_filiale")
+	Name        string   `json:"name"` // Nom par défaut du rôle créé
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// CreateRoleFromTemplateRequest représente la requête de création d'un rôle à partir d'un modèle
+type CreateRoleFromTemplateRequest struct {
+	Template    string `json:"template" binding:"required"` // Clé du modèle (obligatoire)
+	Name        string `json:"name,omitempty"`              // Nom (optionnel, défaut : nom du modèle)
+	Description string `json:"description,omitempty"`       // Description (optionnel, défaut : description du modèle)
+	FilialeID   *uint  `json:"filiale_id,omitempty"`        // ID de la filiale (optionnel, préfixe le nom par le code filiale)
+}
+
+// CloneRoleRequest représente la requête de duplication d'un rôle
+type CloneRoleRequest struct {
+	Name        string `json:"name" binding:"required"` // Nom du nouveau rôle (obligatoire)
+	Description string `json:"description,omitempty"`   // Description (optionnel, défaut : celle du rôle source)
+	FilialeID   *uint  `json:"filiale_id,omitempty"`    // ID de la filiale (optionnel)
+}
+
 // UserPermissionsDTO représente les permissions d'un utilisateur
 type UserPermissionsDTO struct {
 	UserID      uint     `json:"user_id"`
@@ -87,3 +117,52 @@ type UserPermissionsDTO struct {
 type UpdateUserPermissionsRequest struct {
 	Permissions []string `json:"permissions" binding:"required"` // Liste des permissions (obligatoire)
 }
+
+// OrgChartNodeDTO représente un utilisateur et ses collaborateurs dans l'organigramme
+type OrgChartNodeDTO struct {
+	User         UserDTO           `json:"user"`
+	ReportsCount int               `json:"reports_count"` // Nombre de collaborateurs directs (y compris au-delà de la profondeur demandée)
+	Reports      []OrgChartNodeDTO `json:"reports"`       // Collaborateurs directs (limités à la profondeur demandée)
+}
+
+// OrgChartDTO représente l'organigramme autour d'un utilisateur
+type OrgChartDTO struct {
+	Managers []UserDTO       `json:"managers"` // Chaîne hiérarchique, du N+1 au sommet
+	Root     OrgChartNodeDTO `json:"root"`     // L'utilisateur et ses collaborateurs
+}
+
+// OffboardUserRequest représente le départ d'un utilisateur : désactivation et réaffectation de son travail en cours
+type OffboardUserRequest struct {
+	SuccessorID *uint  `json:"successor_id,omitempty"` // Successeur (optionnel, sans successeur le travail est désassigné)
+	Reason      string `json:"reason,omitempty"`       // Motif du départ (optionnel, repris dans l'historique des tickets)
+}
+
+// UserOffboardingReportDTO représente le bilan du départ d'un utilisateur
+type UserOffboardingReportDTO struct {
+	User                  UserDTO  `json:"user"`
+	Successor             *UserDTO `json:"successor,omitempty"`
+	TicketIDs             []uint   `json:"ticket_ids"`             // Tickets ouverts réaffectés (ou désassignés)
+	InternalTicketIDs     []uint   `json:"internal_ticket_ids"`    // Tickets internes ouverts réaffectés (ou désassignés)
+	ProjectTaskIDs        []uint   `json:"project_task_ids"`       // Tâches de projet ouvertes réaffectées (ou désassignées)
+	ReportIDs             []uint   `json:"report_ids"`             // Collaborateurs rattachés au successeur (ou sans responsable)
+	PendingTimeEntries    int64    `json:"pending_time_entries"`   // Entrées de temps des collaborateurs en attente de validation
+	PendingJustifications int64    `json:"pending_justifications"` // Justifications de retard des collaborateurs en attente de validation
+	SessionsRevoked       int64    `json:"sessions_revoked"`
+}
+
+// OutOfOfficeDTO représente l'absence déclarée d'un utilisateur
+type OutOfOfficeDTO struct {
+	From         time.Time `json:"from"`
+	Until        time.Time `json:"until"`
+	BackupUserID *uint     `json:"backup_user_id,omitempty"` // Suppléant qui reçoit les validations en attente
+	Message      string    `json:"message,omitempty"`
+	Active       bool      `json:"active"` // Absence en cours
+}
+
+// SetOutOfOfficeRequest représente la déclaration d'une absence
+type SetOutOfOfficeRequest struct {
+	From         time.Time `json:"from" binding:"required"`             // Début de l'absence (obligatoire)
+	Until        time.Time `json:"until" binding:"required"`            // Fin de l'absence (obligatoire, après le début)
+	BackupUserID *uint     `json:"backup_user_id,omitempty"`            // Suppléant (optionnel, utilisateur actif)
+	Message      string    `json:"message,omitempty" binding:"max=255"` // Message d'absence (optionnel)
+}
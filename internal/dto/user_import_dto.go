@@ -0,0 +1,51 @@
+package dto
+
+// Modes d'initialisation du mot de passe des comptes importés
+const (
+	UserImportPasswordGenerate = "generate" // Mot de passe initial généré et retourné dans le rapport
+	UserImportPasswordInvite   = "invite"   // Lien d'invitation à usage unique pour choisir son mot de passe
+)
+
+// Statuts d'une ligne du rapport d'import
+const (
+	UserImportRowValid   = "valid"   // Ligne valide (simulation)
+	UserImportRowCreated = "created" // Utilisateur créé
+	UserImportRowError   = "error"   // Ligne rejetée
+)
+
+// UserImportOptions représente les options d'un import d'utilisateurs (champs du formulaire multipart)
+type UserImportOptions struct {
+	Mapping      map[string]string `json:"mapping,omitempty"`       // Champ utilisateur -> en-tête de colonne (optionnel, en-têtes usuels reconnus par défaut)
+	PasswordMode string            `json:"password_mode,omitempty"` // generate (défaut) ou invite
+	DryRun       bool              `json:"dry_run"`                 // Validation seule, aucun compte créé
+}
+
+// UserImportRowDTO représente le résultat de l'import d'une ligne du fichier
+type UserImportRowDTO struct {
+	Row             int      `json:"row"` // Numéro de ligne dans le fichier (l'en-tête est la ligne 1)
+	Username        string   `json:"username,omitempty"`
+	Email           string   `json:"email,omitempty"`
+	Status          string   `json:"status"`           // valid, created, error
+	Errors          []string `json:"errors,omitempty"` // Motifs de rejet
+	UserID          *uint    `json:"user_id,omitempty"`
+	InitialPassword string   `json:"initial_password,omitempty"` // Mode generate : à transmettre à l'utilisateur
+	InvitationURL   string   `json:"invitation_url,omitempty"`   // Mode invite : lien d'activation du compte
+}
+
+// UserImportReportDTO représente le rapport d'un import d'utilisateurs
+type UserImportReportDTO struct {
+	DryRun       bool               `json:"dry_run"`
+	PasswordMode string             `json:"password_mode"`
+	Columns      map[string]string  `json:"columns"` // Correspondance retenue : champ -> en-tête
+	Total        int                `json:"total"`
+	Valid        int                `json:"valid"`   // Lignes valides (créées ou à créer)
+	Created      int                `json:"created"` // Comptes effectivement créés
+	Failed       int                `json:"failed"`
+	Rows         []UserImportRowDTO `json:"rows"`
+}
+
+// AcceptInvitationRequest représente l'activation d'un compte invité
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`          // Token du lien d'invitation (obligatoire)
+	Password string `json:"password" binding:"required,min=6"` // Nouveau mot de passe (obligatoire, min 6 caractères)
+}
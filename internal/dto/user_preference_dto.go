@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// UserPreferencesDTO représente les préférences d'un utilisateur (valeurs par défaut si jamais enregistrées)
+type UserPreferencesDTO struct {
+	Language               string     `json:"language"`                 // fr, en (vide = langue du navigateur)
+	Timezone               string     `json:"timezone"`                 // Fuseau IANA (vide = fuseau par défaut)
+	DateFormat             string     `json:"date_format"`              // DD/MM/YYYY, MM/DD/YYYY, YYYY-MM-DD
+	DefaultDashboard       string     `json:"default_dashboard"`        // own, department, filiale, global (vide = selon permissions)
+	EmailNotifications     bool       `json:"email_notifications"`      // Envoi des notifications par email
+	DigestFrequency        string     `json:"digest_frequency"`         // none, daily, weekly
+	MutedNotificationTypes []string   `json:"muted_notification_types"` // Types de notifications désactivés
+	UpdatedAt              *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateUserPreferencesRequest représente la mise à jour des préférences (champs omis inchangés)
+type UpdateUserPreferencesRequest struct {
+	Language               *string  `json:"language,omitempty"` // "" pour revenir à la langue du navigateur
+	Timezone               *string  `json:"timezone,omitempty"` // "" pour revenir au fuseau par défaut
+	DateFormat             *string  `json:"date_format,omitempty" binding:"omitempty,oneof=DD/MM/YYYY MM/DD/YYYY YYYY-MM-DD"`
+	DefaultDashboard       *string  `json:"default_dashboard,omitempty"` // "" pour revenir au périmètre des permissions
+	EmailNotifications     *bool    `json:"email_notifications,omitempty"`
+	DigestFrequency        *string  `json:"digest_frequency,omitempty" binding:"omitempty,oneof=none daily weekly"`
+	MutedNotificationTypes []string `json:"muted_notification_types,omitempty" binding:"omitempty,max=100,dive,max=50"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// WebhookSubscriptionDTO représente un abonnement webhook
+type WebhookSubscriptionDTO struct {
+	ID          uint        `json:"id"`
+	Name        string      `json:"name"`
+	URL         string      `json:"url"`
+	EventTypes  []string    `json:"event_types"`
+	FilialeID   *uint       `json:"filiale_id,omitempty"`
+	Filiale     *FilialeDTO `json:"filiale,omitempty"`
+	IsActive    bool        `json:"is_active"`
+	Secret      string      `json:"secret,omitempty"` // Retourné uniquement à la création (ou à la régénération)
+	CreatedByID *uint       `json:"created_by_id,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// CreateWebhookSubscriptionRequest représente la requête de création d'un abonnement webhook
+type CreateWebhookSubscriptionRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	URL        string   `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"` // Événements souscrits ("*" pour tous)
+	FilialeID  *uint    `json:"filiale_id,omitempty"`                 // Filtre optionnel par filiale
+	Secret     string   `json:"secret,omitempty"`                     // Généré automatiquement si absent
+	IsActive   *bool    `json:"is_active,omitempty"`                  // Actif par défaut
+}
+
+// UpdateWebhookSubscriptionRequest représente la requête de mise à jour d'un abonnement webhook
+type UpdateWebhookSubscriptionRequest struct {
+	Name             string   `json:"name,omitempty"`
+	URL              string   `json:"url,omitempty" binding:"omitempty,url"`
+	EventTypes       []string `json:"event_types,omitempty"`
+	FilialeID        *uint    `json:"filiale_id,omitempty"`
+	ClearFiliale     bool     `json:"clear_filiale,omitempty"` // Retirer le filtre par filiale
+	IsActive         *bool    `json:"is_active,omitempty"`
+	RegenerateSecret bool     `json:"regenerate_secret,omitempty"` // Générer un nouveau secret (retourné dans la réponse)
+}
+
+// WebhookDeliveryDTO représente une entrée du journal de livraison
+type WebhookDeliveryDTO struct {
+	ID             uint       `json:"id"`
+	SubscriptionID uint       `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload,omitempty"`
+	Status         string     `json:"status"` // pending, sending, retrying, success, failed
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	ResponseBody   string     `json:"response_body,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookDeliveryListResponse représente une liste paginée de livraisons
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDeliveryDTO `json:"deliveries"`
+	Pagination PaginationDTO        `json:"pagination"`
+}
+
+// WebhookEventTypeDTO décrit un type d'événement disponible pour les abonnements
+type WebhookEventTypeDTO struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// WhatsAppTemplateDTO représente le modèle de message WhatsApp d'un type de notification critique
+type WhatsAppTemplateDTO struct {
+	ID               uint      `json:"id"`
+	NotificationType string    `json:"notification_type"` // sla_violated, major_incident
+	Name             string    `json:"name"`              // Nom du modèle approuvé dans WhatsApp Manager
+	Language         string    `json:"language"`
+	Parameters       []string  `json:"parameters"` // Champs transmis comme variables {{1}}, {{2}}, ... (title, message, ticket_code)
+	IsActive         bool      `json:"is_active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CreateWhatsAppTemplateRequest représente la création d'un modèle de message WhatsApp
+type CreateWhatsAppTemplateRequest struct {
+	NotificationType string   `json:"notification_type" binding:"required,oneof=sla_violated major_incident"`
+	Name             string   `json:"name" binding:"required,max=512"`
+	Language         string   `json:"language,omitempty" binding:"omitempty,max=15"`                                 // fr par défaut
+	Parameters       []string `json:"parameters,omitempty" binding:"omitempty,dive,oneof=title message ticket_code"` // Dans l'ordre des variables du modèle
+	IsActive         *bool    `json:"is_active,omitempty"`                                                           // true par défaut
+}
+
+// UpdateWhatsAppTemplateRequest représente la mise à jour d'un modèle de message WhatsApp
+type UpdateWhatsAppTemplateRequest struct {
+	Name       *string  `json:"name,omitempty" binding:"omitempty,min=1,max=512"`
+	Language   *string  `json:"language,omitempty" binding:"omitempty,min=1,max=15"`
+	Parameters []string `json:"parameters,omitempty" binding:"omitempty,dive,oneof=title message ticket_code"` // Remplace la liste si fournie
+	IsActive   *bool    `json:"is_active,omitempty"`
+}
+
+// WhatsAppOptInDTO état du consentement de l'utilisateur aux notifications WhatsApp
+type WhatsAppOptInDTO struct {
+	Enabled           bool       `json:"enabled"` // Canal configuré sur cette instance
+	OptedIn           bool       `json:"opted_in"`
+	PhoneNumber       string     `json:"phone_number,omitempty"`
+	OptedInAt         *time.Time `json:"opted_in_at,omitempty"`
+	NotificationTypes []string   `json:"notification_types"` // Types de notification envoyés sur WhatsApp (modèle actif)
+}
+
+// UpdateWhatsAppOptInRequest représente l'inscription aux notifications WhatsApp
+type UpdateWhatsAppOptInRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"` // Format international (+2250700000000)
+	Consent     bool   `json:"consent"`                         // Doit valoir true : consentement explicite à recevoir des messages WhatsApp
+}
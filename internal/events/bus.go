@@ -0,0 +1,153 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/logger"
+)
+
+// Types d'événements métier publiés sur le bus
+const (
+	TicketCreated          = "ticket.created"
+	TicketUpdated          = "ticket.updated"
+	TicketAssigned         = "ticket.assigned"
+	TicketStatusChanged    = "ticket.status_changed"
+	TicketClosed           = "ticket.closed"
+	TicketCommented        = "ticket.commented"
+	TicketMerged           = "ticket.merged"
+	SLAAtRisk              = "sla.at_risk"
+	SLAViolated            = "sla.violated"
+	IncidentMajor          = "incident.major"
+	MajorIncidentDeclared  = "incident.major_declared"   // Incident majeur déclaré : diffusion aux utilisateurs de la filiale
+	MajorIncidentUpdated   = "incident.major_update"     // Point de situation public
+	MajorIncidentEnded     = "incident.major_ended"      // Service rétabli
+	MajorIncidentUpdateDue = "incident.major_update_due" // Point de situation en retard (rappel au pilote)
+	ProjectCreated         = "project.created"
+	ProjectUpdated         = "project.updated"
+	ProjectDeleted         = "project.deleted"
+	ProjectArchived        = "project.archived" // Projet archivé : lecture seule
+	ProjectUnarchived      = "project.unarchived"
+	TaskCompleted          = "task.completed"
+	UserLoggedIn           = "user.logged_in"
+	ApprovalRequested      = "approval.requested" // Nouvelles tâches d'approbation (Metadata["approver_ids"])
+	ApprovalDecided        = "approval.decided"
+)
+
+// Event représente un événement métier survenu dans l'application
+type Event struct {
+	ID         string         // Identifiant unique (généré à la publication si vide)
+	Type       string         // Type d'événement (ticket.created, ...)
+	OccurredAt time.Time      // Date de l'événement (renseignée à la publication si vide)
+	ActorID    *uint          // Utilisateur à l'origine de l'événement (nil pour les traitements système)
+	FilialeID  *uint          // Filiale concernée (optionnel)
+	EntityType string         // Type d'entité (tickets, projects, project_tasks, ...)
+	EntityID   uint           // ID de l'entité concernée
+	Data       any            // Représentation de l'entité (DTO ou modèle)
+	Metadata   map[string]any // Informations complémentaires (ancien statut, assignés, ...)
+}
+
+// Handler traite un événement ; une erreur est journalisée mais n'interrompt pas les autres abonnés
+type Handler func(ctx context.Context, event Event) error
+
+// subscriber représente un abonnement au bus
+type subscriber struct {
+	name    string
+	pattern string
+	handler Handler
+}
+
+// Bus distribue les événements aux abonnés en mémoire, de manière asynchrone
+// L'émetteur ne connaît pas ses consommateurs (notifications, webhooks, audit, index de recherche, ...)
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []subscriber
+	wg          sync.WaitGroup
+}
+
+// NewBus crée un nouveau bus d'événements
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe abonne un handler aux événements correspondant au motif
+// Motifs acceptés : type exact ("ticket.created"), domaine ("ticket.*") ou tous ("*")
+func (b *Bus) Subscribe(pattern, name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber{name: name, pattern: pattern, handler: handler})
+}
+
+// Publish diffuse l'événement à chaque abonné concerné, chacun dans sa propre goroutine
+// Le contexte est détaché de l'annulation de la requête mais conserve ses valeurs (logger, request_id)
+// Sans effet sur un bus nil, pour les services construits sans bus
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subscribers {
+		if !Matches(sub.pattern, event.Type) {
+			continue
+		}
+		b.wg.Add(1)
+		go b.dispatch(ctx, sub, event)
+	}
+}
+
+// Wait attend la fin des handlers en cours (arrêt propre du serveur)
+func (b *Bus) Wait() {
+	if b == nil {
+		return
+	}
+	b.wg.Wait()
+}
+
+// dispatch exécute un handler en l'isolant des paniques
+func (b *Bus) dispatch(ctx context.Context, sub subscriber, event Event) {
+	defer b.wg.Done()
+	log := logger.FromContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("event_handler_panic", "subscriber", sub.name, "event", event.Type, "event_id", event.ID, "panic", r)
+		}
+	}()
+
+	if err := sub.handler(ctx, event); err != nil {
+		log.Error("event_handler_failed", "subscriber", sub.name, "event", event.Type, "event_id", event.ID, "error", err)
+	}
+}
+
+// Matches indique si un type d'événement correspond au motif d'abonnement
+func Matches(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	if strings.HasSuffix(pattern, ".*") {
+		return strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*"))
+	}
+	return false
+}
+
+// newEventID génère un identifiant d'événement aléatoire
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
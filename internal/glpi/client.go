@@ -0,0 +1,240 @@
+// Package glpi lit les données d'une instance GLPI via son API REST (apirest.php) pour la migration vers Kronos
+// Seules les lectures utilisées par l'import sont couvertes : listes paginées, sous-éléments et documents
+package glpi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// requestTimeout borne la durée d'un appel à l'API (téléchargement de document compris)
+const requestTimeout = 60 * time.Second
+
+// Client appelle l'API REST de GLPI avec une session ouverte par jeton utilisateur
+type Client struct {
+	baseURL   string
+	appToken  string
+	userToken string
+	http      *http.Client
+
+	mu           sync.Mutex
+	sessionToken string
+}
+
+// NewClient crée un client à partir de la configuration
+func NewClient(cfg config.GLPIConfig) *Client {
+	return &Client{
+		baseURL:   cfg.URL,
+		appToken:  cfg.AppToken,
+		userToken: cfg.UserToken,
+		http:      &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// List lit une page d'éléments d'un type (Ticket, User, Computer, ...) et retourne le nombre total d'éléments
+// Avec expand, les identifiants des listes déroulantes sont remplacés par leur libellé
+func (c *Client) List(ctx context.Context, itemType string, start, limit int, expand bool, out any) (int, error) {
+	query := url.Values{}
+	query.Set("range", fmt.Sprintf("%d-%d", start, start+limit-1))
+	query.Set("expand_dropdowns", strconv.FormatBool(expand))
+	query.Set("order", "ASC")
+	query.Set("sort", "id")
+
+	resp, err := c.get(ctx, "/"+url.PathEscape(itemType)+"?"+query.Encode(), "application/json")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return 0, err
+	}
+	// Début de page au-delà du total : la liste est épuisée
+	if resp.StatusCode == http.StatusBadRequest && bytes.Contains(body, []byte("ERROR_RANGE_EXCEEDED_TOTAL")) {
+		return start, json.Unmarshal([]byte("[]"), out)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return 0, apiError("GET", itemType, resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return 0, fmt.Errorf("réponse GLPI %s illisible: %w", itemType, err)
+	}
+	return contentRangeTotal(resp.Header.Get("Content-Range")), nil
+}
+
+// SubItems lit tous les sous-éléments d'un élément (ex: Ticket/12/ITILFollowup)
+func (c *Client) SubItems(ctx context.Context, itemType string, id int, subType string, out any) error {
+	path := fmt.Sprintf("/%s/%d/%s?range=0-9999&expand_dropdowns=false", url.PathEscape(itemType), id, url.PathEscape(subType))
+	resp, err := c.get(ctx, path, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return apiError("GET", itemType+"/"+subType, resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// Get lit un élément par son identifiant
+func (c *Client) Get(ctx context.Context, itemType string, id int, out any) error {
+	resp, err := c.get(ctx, fmt.Sprintf("/%s/%d", url.PathEscape(itemType), id), "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return apiError("GET", itemType, resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// DownloadDocument ouvre le contenu d'un document ; l'appelant ferme le flux
+func (c *Client) DownloadDocument(ctx context.Context, documentID int) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, fmt.Sprintf("/Document/%d", documentID), "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		resp.Body.Close()
+		return nil, apiError("GET", "Document", resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
+
+// Close ferme la session GLPI ouverte (sans effet si aucune session)
+func (c *Client) Close(ctx context.Context) {
+	c.mu.Lock()
+	token := c.sessionToken
+	c.sessionToken = ""
+	c.mu.Unlock()
+	if token == "" {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/killSession", nil)
+	if err != nil {
+		return
+	}
+	c.setHeaders(req, token)
+	if resp, err := c.http.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// get exécute un appel authentifié ; la session est rouverte une fois si elle a expiré
+func (c *Client) get(ctx context.Context, path, accept string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := c.session(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(req, token)
+		req.Header.Set("Accept", accept)
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		c.mu.Lock()
+		if c.sessionToken == token {
+			c.sessionToken = ""
+		}
+		c.mu.Unlock()
+	}
+}
+
+// session retourne le jeton de session, en ouvrant une session si nécessaire
+func (c *Client) session(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sessionToken != "" {
+		return c.sessionToken, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/initSession", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "user_token "+c.userToken)
+	if c.appToken != "" {
+		req.Header.Set("App-Token", c.appToken)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode != http.StatusOK {
+		return "", apiError("GET", "initSession", resp.StatusCode, body)
+	}
+	var session struct {
+		SessionToken string `json:"session_token"`
+	}
+	if err := json.Unmarshal(body, &session); err != nil || session.SessionToken == "" {
+		return "", fmt.Errorf("ouverture de session GLPI: réponse inattendue")
+	}
+	c.sessionToken = session.SessionToken
+	return c.sessionToken, nil
+}
+
+// setHeaders ajoute les en-têtes d'authentification d'une session
+func (c *Client) setHeaders(req *http.Request, sessionToken string) {
+	req.Header.Set("Session-Token", sessionToken)
+	if c.appToken != "" {
+		req.Header.Set("App-Token", c.appToken)
+	}
+}
+
+// apiError construit l'erreur d'une réponse HTTP inattendue
+func apiError(method, resource string, status int, body []byte) error {
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return fmt.Errorf("API GLPI %s %s: réponse HTTP %d: %s", method, resource, status, bytes.TrimSpace(body))
+}
+
+// contentRangeTotal extrait le total de l'en-tête Content-Range ("0-49/1234")
+func contentRangeTotal(header string) int {
+	_, total, ok := strings.Cut(header, "/")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(total))
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,207 @@
+package glpi
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types de matériel GLPI importés comme actifs
+var AssetItemTypes = []string{"Computer", "Monitor", "Printer", "NetworkEquipment", "Peripheral", "Phone"}
+
+// Rôles d'un utilisateur sur un ticket (Ticket_User.type)
+const (
+	TicketUserRequester = 1
+	TicketUserAssigned  = 2
+	TicketUserObserver  = 3
+)
+
+// Dropdown valeur d'une liste déroulante : identifiant ou libellé selon expand_dropdowns
+// Les valeurs vides ("", 0, "&nbsp;") sont normalisées en chaîne vide
+type Dropdown string
+
+// UnmarshalJSON accepte un nombre ou une chaîne
+func (d *Dropdown) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case string:
+		v = strings.TrimSpace(html.UnescapeString(v))
+		if v == "0" || v == " " {
+			v = ""
+		}
+		*d = Dropdown(v)
+	case float64:
+		if v == 0 {
+			*d = ""
+		} else {
+			*d = Dropdown(strconv.FormatInt(int64(v), 10))
+		}
+	default:
+		*d = ""
+	}
+	return nil
+}
+
+// ID retourne l'identifiant d'une liste déroulante non développée (0 si vide)
+func (d Dropdown) ID() int {
+	id, _ := strconv.Atoi(string(d))
+	return id
+}
+
+// User utilisateur GLPI
+type User struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"` // Identifiant de connexion
+	FirstName string `json:"firstname"`
+	RealName  string `json:"realname"` // Nom de famille
+	Phone     string `json:"phone"`
+	Mobile    string `json:"mobile"`
+	IsActive  int    `json:"is_active"`
+	IsDeleted int    `json:"is_deleted"`
+}
+
+// UserEmail adresse e-mail d'un utilisateur (sous-élément User/UserEmail)
+type UserEmail struct {
+	Email     string `json:"email"`
+	IsDefault int    `json:"is_default"`
+}
+
+// ITILCategory catégorie de ticket GLPI
+type ITILCategory struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	CompleteName string   `json:"completename"` // Chemin complet (ex: "Réseau > Wifi")
+	Comment      string   `json:"comment"`
+	ParentID     Dropdown `json:"itilcategories_id"`
+}
+
+// Ticket ticket GLPI (liste non développée : les champs *_id sont des identifiants)
+type Ticket struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Content       string   `json:"content"` // HTML échappé
+	Status        int      `json:"status"`  // 1 nouveau, 2 en cours (attribué), 3 en cours (planifié), 4 en attente, 5 résolu, 6 clos
+	Priority      int      `json:"priority"`
+	Type          int      `json:"type"` // 1 incident, 2 demande
+	CategoryID    Dropdown `json:"itilcategories_id"`
+	RequestTypeID Dropdown `json:"requesttypes_id"` // 1 helpdesk, 2 e-mail, 3 téléphone, 4 direct, ...
+	RecipientID   Dropdown `json:"users_id_recipient"`
+	Date          string   `json:"date"`
+	DateMod       string   `json:"date_mod"`
+	SolveDate     string   `json:"solvedate"`
+	CloseDate     string   `json:"closedate"`
+	ActionTime    int      `json:"actiontime"` // Durée totale en secondes
+	IsDeleted     int      `json:"is_deleted"`
+}
+
+// TicketUser acteur d'un ticket (sous-élément Ticket/Ticket_User)
+type TicketUser struct {
+	UserID           int    `json:"users_id"`
+	Type             int    `json:"type"`
+	AlternativeEmail string `json:"alternative_email"`
+}
+
+// Followup suivi d'un ticket (sous-élément Ticket/ITILFollowup)
+type Followup struct {
+	ID        int    `json:"id"`
+	UserID    int    `json:"users_id"`
+	Content   string `json:"content"`
+	IsPrivate int    `json:"is_private"`
+	Date      string `json:"date"`
+}
+
+// DocumentItem association entre un document et un élément (sous-élément Ticket/Document_Item)
+type DocumentItem struct {
+	DocumentID int `json:"documents_id"`
+}
+
+// Document métadonnées d'un document GLPI
+type Document struct {
+	ID       int    `json:"id"`
+	Filename string `json:"filename"`
+	Mime     string `json:"mime"`
+	UserID   int    `json:"users_id"`
+}
+
+// Asset matériel GLPI (champs communs à Computer, Monitor, Printer, ... ; liste développée)
+type Asset struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Serial       string   `json:"serial"`
+	OtherSerial  string   `json:"otherserial"` // Numéro d'inventaire
+	Comment      string   `json:"comment"`
+	Manufacturer Dropdown `json:"manufacturers_id"`
+	Location     Dropdown `json:"locations_id"`
+	State        Dropdown `json:"states_id"`
+	User         Dropdown `json:"users_id"` // Identifiant de connexion de l'utilisateur (liste développée)
+	IsDeleted    int      `json:"is_deleted"`
+	IsTemplate   int      `json:"is_template"`
+	// Le champ modèle dépend du type (computermodels_id, monitormodels_id, ...)
+	Models map[string]Dropdown `json:"-"`
+}
+
+// UnmarshalJSON conserve en plus les champs *models_id propres à chaque type de matériel
+func (a *Asset) UnmarshalJSON(data []byte) error {
+	type plain Asset
+	if err := json.Unmarshal(data, (*plain)(a)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	a.Models = map[string]Dropdown{}
+	for key, value := range raw {
+		if !strings.HasSuffix(key, "models_id") {
+			continue
+		}
+		var model Dropdown
+		if json.Unmarshal(value, &model) == nil && model != "" {
+			a.Models[key] = model
+		}
+	}
+	return nil
+}
+
+// Model retourne le modèle du matériel (premier champ *models_id renseigné)
+func (a *Asset) Model() string {
+	for _, model := range a.Models {
+		return string(model)
+	}
+	return ""
+}
+
+var (
+	lineBreakTags = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</tr>`)
+	htmlTags      = regexp.MustCompile(`<[^>]*>`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+)
+
+// PlainText convertit un contenu riche GLPI (HTML échappé) en texte brut
+func PlainText(content string) string {
+	text := html.UnescapeString(content)
+	text = lineBreakTags.ReplaceAllString(text, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// ParseDate lit une date GLPI ("2006-01-02 15:04:05", heure locale du serveur) ; nil si vide ou invalide
+func ParseDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
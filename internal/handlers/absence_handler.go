@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// AbsenceHandler gère les absences (congés, maladie, formation) et leur approbation
+type AbsenceHandler struct {
+	absenceService services.AbsenceService
+}
+
+// NewAbsenceHandler crée une nouvelle instance de AbsenceHandler
+func NewAbsenceHandler(absenceService services.AbsenceService) *AbsenceHandler {
+	return &AbsenceHandler{
+		absenceService: absenceService,
+	}
+}
+
+// canValidateAbsence indique si l'utilisateur peut se prononcer sur les absences de userID :
+// permission timesheet.validate ou responsable hiérarchique
+func canValidateAbsence(c *gin.Context, userID uint) bool {
+	if utils.RequirePermission(c, "timesheet.validate") {
+		return true
+	}
+	queryScope := utils.GetScopeFromContext(c)
+	return queryScope != nil && queryScope.IsManagerOf(userID)
+}
+
+// canViewAbsences indique si l'utilisateur peut consulter les absences de userID
+func canViewAbsences(c *gin.Context, currentUserID, userID uint) bool {
+	return userID == currentUserID || utils.RequirePermission(c, "timesheet.view_all") || canValidateAbsence(c, userID)
+}
+
+// parseAbsenceDateQuery lit un paramètre de date facultatif (YYYY-MM-DD)
+func parseAbsenceDateQuery(c *gin.Context, name string) (*time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	parsed, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		utils.BadRequestResponse(c, "Paramètre "+name+" invalide (format attendu: YYYY-MM-DD)")
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// GetAll récupère les absences d'un utilisateur
+// @Summary Lister les absences
+// @Description Liste les absences de l'utilisateur connecté, ou d'un autre utilisateur (nécessite timesheet.view_all, timesheet.validate ou d'être son responsable)
+// @Tags absences
+// @Security BearerAuth
+// @Produce json
+// @Param user_id query int false "Utilisateur (défaut: soi-même)"
+// @Param status query string false "Statut (pending, approved, rejected, cancelled)"
+// @Param from query string false "Absences se terminant à partir de ce jour (YYYY-MM-DD)"
+// @Param to query string false "Absences commençant au plus tard ce jour (YYYY-MM-DD)"
+// @Success 200 {array} dto.AbsenceDTO
+// @Failure 403 {object} utils.Response
+// @Router /absences [get]
+func (h *AbsenceHandler) GetAll(c *gin.Context) {
+	currentUserID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+	userID := currentUserID
+	if raw := c.Query("user_id"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre user_id invalide")
+			return
+		}
+		userID = uint(parsed)
+	}
+	if !canViewAbsences(c, currentUserID, userID) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.view_all")
+		return
+	}
+
+	filter := repositories.AbsenceFilter{UserIDs: []uint{userID}}
+	if status := c.Query("status"); status != "" {
+		filter.Statuses = []string{status}
+	}
+	var ok bool
+	if filter.From, ok = parseAbsenceDateQuery(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = parseAbsenceDateQuery(c, "to"); !ok {
+		return
+	}
+
+	absences, err := h.absenceService.GetAll(filter)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, absences, "Absences récupérées avec succès")
+}
+
+// GetPending récupère les absences en attente de la décision de l'utilisateur
+// @Summary Absences en attente d'approbation
+// @Description Liste les demandes d'absence en attente : toutes avec timesheet.validate, sinon celles de son équipe hiérarchique
+// @Tags absences
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.AbsenceDTO
+// @Failure 403 {object} utils.Response
+// @Router /absences/pending [get]
+func (h *AbsenceHandler) GetPending(c *gin.Context) {
+	filter := repositories.AbsenceFilter{Statuses: []string{models.AbsenceStatusPending}}
+	if !utils.RequirePermission(c, "timesheet.validate") {
+		queryScope, ok := utils.RequireScope(c)
+		if !ok {
+			return
+		}
+		if len(queryScope.TeamUserIDs) == 0 {
+			utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.validate")
+			return
+		}
+		filter.UserIDs = queryScope.TeamUserIDs
+	}
+
+	absences, err := h.absenceService.GetAll(filter)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, absences, "Absences en attente récupérées avec succès")
+}
+
+// GetByID récupère une absence
+// @Summary Récupérer une absence
+// @Description Récupère une absence (demandeur, responsable, timesheet.validate ou timesheet.view_all)
+// @Tags absences
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'absence"
+// @Success 200 {object} dto.AbsenceDTO
+// @Failure 404 {object} utils.Response
+// @Router /absences/{id} [get]
+func (h *AbsenceHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	currentUserID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	absence, err := h.absenceService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	if !canViewAbsences(c, currentUserID, absence.UserID) {
+		utils.ServiceErrorResponse(c, utils.ErrAbsenceNotFound)
+		return
+	}
+
+	utils.SuccessResponse(c, absence, "Absence récupérée avec succès")
+}
+
+// Create demande une absence
+// @Summary Demander une absence
+// @Description Demande une absence sur des journées entières (vacation, sick, training), soumise au responsable hiérarchique ou à un validateur des temps (timesheet.validate) ; 409 si elle chevauche une absence en attente ou approuvée. Une fois approuvée, ses jours ouvrés ne sont plus attendus en saisie de temps
+// @Tags absences
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAbsenceRequest true "Absence"
+// @Success 201 {object} dto.AbsenceDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /absences [post]
+func (h *AbsenceHandler) Create(c *gin.Context) {
+	var req dto.CreateAbsenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	absence, err := h.absenceService.Request(req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, absence, "Demande d'absence enregistrée")
+}
+
+// Approve approuve une absence
+// @Summary Approuver une absence
+// @Description Approuve une demande d'absence en attente (nécessite timesheet.validate ou d'être le responsable du demandeur)
+// @Tags absences
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'absence"
+// @Param request body dto.AbsenceDecisionRequest false "Commentaire"
+// @Success 200 {object} dto.AbsenceDTO
+// @Failure 403 {object} utils.Response
+// @Router /absences/{id}/approve [post]
+func (h *AbsenceHandler) Approve(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// Reject refuse une absence
+// @Summary Refuser une absence
+// @Description Refuse une demande d'absence en attente, avec un motif obligatoire (nécessite timesheet.validate ou d'être le responsable du demandeur)
+// @Tags absences
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'absence"
+// @Param request body dto.AbsenceDecisionRequest true "Motif"
+// @Success 200 {object} dto.AbsenceDTO
+// @Failure 403 {object} utils.Response
+// @Router /absences/{id}/reject [post]
+func (h *AbsenceHandler) Reject(c *gin.Context) {
+	h.decide(c, false)
+}
+
+// decide applique la décision d'un validateur
+func (h *AbsenceHandler) decide(c *gin.Context, approve bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	var req dto.AbsenceDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	absence, err := h.absenceService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	if !canValidateAbsence(c, absence.UserID) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.validate")
+		return
+	}
+
+	message := "Absence refusée"
+	if approve {
+		absence, err = h.absenceService.Approve(uint(id), userID, req.Comment)
+		message = "Absence approuvée"
+	} else {
+		absence, err = h.absenceService.Reject(uint(id), userID, req.Comment)
+	}
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, absence, message)
+}
+
+// Cancel annule une absence
+// @Summary Annuler une absence
+// @Description Annule une absence en attente ou approuvée : le demandeur tant qu'elle n'a pas commencé, un validateur (timesheet.validate ou responsable) à tout moment
+// @Tags absences
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'absence"
+// @Success 200 {object} dto.AbsenceDTO
+// @Failure 404 {object} utils.Response
+// @Router /absences/{id}/cancel [post]
+func (h *AbsenceHandler) Cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	absence, err := h.absenceService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	absence, err = h.absenceService.Cancel(uint(id), userID, canValidateAbsence(c, absence.UserID))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, absence, "Absence annulée")
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// AccessCheckHandler gère le diagnostic des droits effectifs des utilisateurs
+type AccessCheckHandler struct {
+	accessCheckService services.AccessCheckService
+}
+
+// NewAccessCheckHandler crée une nouvelle instance de AccessCheckHandler
+func NewAccessCheckHandler(accessCheckService services.AccessCheckService) *AccessCheckHandler {
+	return &AccessCheckHandler{
+		accessCheckService: accessCheckService,
+	}
+}
+
+// Check explique l'accès effectif d'un utilisateur aux tickets ou aux projets
+// @Summary Diagnostic des droits effectifs
+// @Description Rejoue le calcul du périmètre pour un utilisateur : branche de permission retenue, prédicat SQL appliqué, permissions effectives et, si id est fourni, visibilité de la ressource (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param user query int true "ID de l'utilisateur"
+// @Param resource query string false "Type de ressource (ticket ou project)" default(ticket)
+// @Param id query int false "ID de la ressource à vérifier"
+// @Success 200 {object} dto.AccessCheckDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /admin/access-check [get]
+func (h *AccessCheckHandler) Check(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Query("user"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Paramètre user invalide")
+		return
+	}
+	resourceType := c.DefaultQuery("resource", "ticket")
+
+	var resourceID *uint
+	if idParam := c.Query("id"); idParam != "" {
+		id, err := strconv.ParseUint(idParam, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre id invalide")
+			return
+		}
+		idUint := uint(id)
+		resourceID = &idUint
+	}
+
+	result, err := h.accessCheckService.Check(uint(userID), resourceType, resourceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, scope.ErrPermissionsUnavailable):
+			c.Header("Retry-After", "5")
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, "Permissions temporairement indisponibles, veuillez réessayer", nil)
+		case err.Error() == "utilisateur introuvable":
+			utils.NotFoundResponse(c, err.Error())
+		case err.Error() == "type de ressource non pris en charge (ticket ou project)":
+			utils.BadRequestResponse(c, err.Error())
+		default:
+			utils.InternalServerErrorResponse(c, "Erreur lors du diagnostic des droits: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Diagnostic des droits effectué avec succès")
+}
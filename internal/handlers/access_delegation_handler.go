@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// AccessDelegationHandler gère les délégations d'absence
+type AccessDelegationHandler struct {
+	delegationService services.AccessDelegationService
+}
+
+// NewAccessDelegationHandler crée une nouvelle instance de AccessDelegationHandler
+func NewAccessDelegationHandler(delegationService services.AccessDelegationService) *AccessDelegationHandler {
+	return &AccessDelegationHandler{
+		delegationService: delegationService,
+	}
+}
+
+// GetMine récupère les délégations accordées et reçues par l'utilisateur connecté
+// @Summary Mes délégations d'absence
+// @Description Liste les délégations accordées (given) et reçues (received). Le délégataire agit pour le compte du délégant en envoyant l'en-tête X-On-Behalf-Of
+// @Tags delegations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.AccessDelegationsDTO
+// @Failure 401 {object} utils.Response
+// @Router /delegations [get]
+func (h *AccessDelegationHandler) GetMine(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	delegations, err := h.delegationService.GetMine(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, delegations, "Délégations récupérées avec succès")
+}
+
+// Create délègue les validations de l'utilisateur connecté à un collègue
+// @Summary Déléguer ses validations
+// @Description Délègue les validations et la visibilité associée (temps, tickets, projets) à un collègue sur une période. Les actions déléguées sont tracées dans l'audit avec les deux identités
+// @Tags delegations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAccessDelegationRequest true "Délégataire, domaines et période"
+// @Success 201 {object} dto.AccessDelegationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /delegations [post]
+func (h *AccessDelegationHandler) Create(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+	if _, delegated := c.Get("on_behalf_of_id"); delegated {
+		utils.ForbiddenResponse(c, "Une délégation ne peut pas être créée pour le compte d'un autre utilisateur")
+		return
+	}
+
+	var req dto.CreateAccessDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	delegation, err := h.delegationService.Create(req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "délégataire introuvable", "utilisateur introuvable":
+			utils.NotFoundResponse(c, err.Error())
+		case "erreur lors de la création de la délégation", "erreur lors de la récupération de la délégation créée", "erreur lors de la récupération des permissions":
+			utils.InternalServerErrorResponse(c, err.Error())
+		default:
+			utils.ServiceErrorResponse(c, err)
+		}
+		return
+	}
+
+	utils.CreatedResponse(c, delegation, "Délégation créée avec succès")
+}
+
+// Revoke met fin à une délégation
+// @Summary Révoquer une délégation
+// @Description Met fin immédiatement à une délégation (délégant ou délégataire)
+// @Tags delegations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la délégation"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /delegations/{id} [delete]
+func (h *AccessDelegationHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.delegationService.Revoke(uint(id), userID); err != nil {
+		switch err.Error() {
+		case "délégation introuvable":
+			utils.NotFoundResponse(c, err.Error())
+		case "délégation déjà révoquée":
+			utils.BadRequestResponse(c, err.Error())
+		default:
+			utils.InternalServerErrorResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Délégation révoquée avec succès")
+}
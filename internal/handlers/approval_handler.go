@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ApprovalHandler gère les handlers du circuit d'approbation commun aux modules
+type ApprovalHandler struct {
+	approvalService services.ApprovalService
+}
+
+// NewApprovalHandler crée une nouvelle instance de ApprovalHandler
+func NewApprovalHandler(approvalService services.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+	}
+}
+
+// approvalIDParam lit l'ID de la demande d'approbation dans le chemin
+func approvalIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// Create soumet un enregistrement à approbation
+// @Summary Soumettre à approbation
+// @Description Soumet une demande de service, un changement, une extension de budget ou une déclaration hebdomadaire à un circuit d'approbation. Les approbateurs d'une même étape décident en parallèle, les étapes se succèdent par ordre croissant (nécessite approvals.request)
+// @Tags approvals
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateApprovalRequest true "Enregistrement et approbateurs"
+// @Success 201 {object} dto.ApprovalRequestDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /approvals [post]
+func (h *ApprovalHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "approvals.request") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: approvals.request")
+		return
+	}
+
+	var req dto.CreateApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	requestedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	approval, err := h.approvalService.Create(req, requestedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, approval, "Demande d'approbation créée avec succès")
+}
+
+// GetAll récupère les demandes d'approbation
+// @Summary Lister les demandes d'approbation
+// @Description Récupère les demandes émises par l'utilisateur ou dont il est approbateur ; toutes les demandes avec approvals.manage
+// @Tags approvals
+// @Security BearerAuth
+// @Produce json
+// @Param entity_type query string false "Module (service_request, change, budget_extension, weekly_declaration)"
+// @Param entity_id query int false "ID de l'enregistrement"
+// @Param status query string false "Statut (pending, approved, rejected, cancelled)"
+// @Success 200 {array} dto.ApprovalRequestDTO
+// @Failure 400 {object} utils.Response
+// @Router /approvals [get]
+func (h *ApprovalHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	filter := repositories.ApprovalFilter{EntityType: c.Query("entity_type"), Status: c.Query("status")}
+	if v := c.Query("entity_id"); v != "" {
+		entityID, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre entity_id invalide")
+			return
+		}
+		id := uint(entityID)
+		filter.EntityID = &id
+	}
+	if !utils.RequirePermission(c, "approvals.manage") {
+		id := userID.(uint)
+		filter.InvolvedUserID = &id
+	}
+
+	approvals, err := h.approvalService.GetAll(filter)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, approvals, "Demandes d'approbation récupérées avec succès")
+}
+
+// GetMyPending récupère les demandes attendant la décision de l'utilisateur
+// @Summary Mes approbations en attente
+// @Description Récupère les demandes dont l'étape courante attend la décision de l'utilisateur connecté, les plus anciennes d'abord
+// @Tags approvals
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.ApprovalRequestDTO
+// @Failure 500 {object} utils.Response
+// @Router /approvals/my-pending [get]
+func (h *ApprovalHandler) GetMyPending(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	approvals, err := h.approvalService.GetMyPending(userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, approvals, "Approbations en attente récupérées avec succès")
+}
+
+// GetByID récupère une demande d'approbation par son ID
+// @Summary Récupérer une demande d'approbation
+// @Description Récupère une demande avec ses approbateurs et commentaires (demandeur, approbateurs ou approvals.manage)
+// @Tags approvals
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la demande d'approbation"
+// @Success 200 {object} dto.ApprovalRequestDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /approvals/{id} [get]
+func (h *ApprovalHandler) GetByID(c *gin.Context) {
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	approval, err := h.approvalService.GetByID(id, userID.(uint), utils.RequirePermission(c, "approvals.manage"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, approval, "Demande d'approbation récupérée avec succès")
+}
+
+// Approve enregistre l'approbation de l'utilisateur connecté
+// @Summary Approuver
+// @Description Approuve la demande pour l'étape courante ; la décision finale est répercutée sur l'enregistrement
+// @Tags approvals
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la demande d'approbation"
+// @Param request body dto.ApprovalDecisionRequest false "Commentaire"
+// @Success 200 {object} dto.ApprovalRequestDTO
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /approvals/{id}/approve [post]
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// Reject enregistre le rejet de l'utilisateur connecté
+// @Summary Rejeter
+// @Description Rejette la demande (commentaire obligatoire) ; les approbateurs restants sont ignorés
+// @Tags approvals
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la demande d'approbation"
+// @Param request body dto.ApprovalDecisionRequest true "Motif du rejet"
+// @Success 200 {object} dto.ApprovalRequestDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /approvals/{id}/reject [post]
+func (h *ApprovalHandler) Reject(c *gin.Context) {
+	h.decide(c, false)
+}
+
+// decide enregistre la décision de l'utilisateur connecté
+func (h *ApprovalHandler) decide(c *gin.Context, approve bool) {
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	decide, message := h.approvalService.Approve, "Demande approuvée"
+	if !approve {
+		decide, message = h.approvalService.Reject, "Demande rejetée"
+	}
+	approval, err := decide(id, userID.(uint), req.Comment)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, approval, message)
+}
+
+// Cancel annule une demande d'approbation en attente
+// @Summary Annuler une demande d'approbation
+// @Description Annule une demande en attente (demandeur ou approvals.manage)
+// @Tags approvals
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la demande d'approbation"
+// @Success 200 {object} dto.ApprovalRequestDTO
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /approvals/{id}/cancel [post]
+func (h *ApprovalHandler) Cancel(c *gin.Context) {
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	approval, err := h.approvalService.Cancel(id, userID.(uint), utils.RequirePermission(c, "approvals.manage"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, approval, "Demande d'approbation annulée")
+}
+
+// AddComment ajoute un commentaire à une demande d'approbation
+// @Summary Commenter une demande d'approbation
+// @Description Ajoute un commentaire (demandeur, approbateurs ou approvals.manage)
+// @Tags approvals
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la demande d'approbation"
+// @Param request body dto.ApprovalCommentRequest true "Commentaire"
+// @Success 201 {object} dto.ApprovalCommentDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /approvals/{id}/comments [post]
+func (h *ApprovalHandler) AddComment(c *gin.Context) {
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ApprovalCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	comment, err := h.approvalService.AddComment(id, userID.(uint), req.Comment, utils.RequirePermission(c, "approvals.manage"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, comment, "Commentaire ajouté avec succès")
+}
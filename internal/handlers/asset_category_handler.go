@@ -94,7 +94,7 @@ func (h *AssetCategoryHandler) GetByID(c *gin.Context) {
 func (h *AssetCategoryHandler) Create(c *gin.Context) {
 	var req dto.CreateAssetCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -106,7 +106,7 @@ func (h *AssetCategoryHandler) Create(c *gin.Context) {
 
 	category, err := h.assetCategoryService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -135,7 +135,7 @@ func (h *AssetCategoryHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateAssetCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -147,7 +147,7 @@ func (h *AssetCategoryHandler) Update(c *gin.Context) {
 
 	category, err := h.assetCategoryService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
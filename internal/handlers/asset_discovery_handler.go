@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// discoveryReportMaxSize taille maximale d'un inventaire remonté par un agent
+const discoveryReportMaxSize = 8 << 20
+
+// AssetDiscoveryHandler gère l'ingestion des inventaires des agents et le suivi des écarts avec la CMDB
+type AssetDiscoveryHandler struct {
+	discoveryService services.AssetDiscoveryService
+}
+
+// NewAssetDiscoveryHandler crée une nouvelle instance de AssetDiscoveryHandler
+func NewAssetDiscoveryHandler(discoveryService services.AssetDiscoveryService) *AssetDiscoveryHandler {
+	return &AssetDiscoveryHandler{
+		discoveryService: discoveryService,
+	}
+}
+
+// Ingest reçoit l'inventaire d'un agent de découverte
+// @Summary Remonter un inventaire d'actifs
+// @Description Point d'entrée des agents d'inventaire et scripts, authentifié par la clé d'API de l'agent (en-tête Authorization: Bearer <clé> ou X-API-Key). Chaque équipement est rapproché de la CMDB par numéro de série puis par nom d'hôte ; un équipement inconnu est créé en stock si l'agent le permet. Les champs vides de l'actif sont complétés, les logiciels découverts ajoutés ou mis à jour, et les valeurs divergentes (nom, numéro de série, fabricant, modèle, logiciels absents) enregistrées comme écarts à examiner
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param request body dto.DiscoveryReportRequest true "Inventaire"
+// @Success 200 {object} dto.DiscoveryIngestResultDTO
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /integrations/discovery [post]
+func (h *AssetDiscoveryHandler) Ingest(c *gin.Context) {
+	key := c.GetHeader("X-API-Key")
+	if bearer, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found {
+		key = strings.TrimSpace(bearer)
+	}
+	agentID, err := h.discoveryService.AuthenticateAgent(key)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, discoveryReportMaxSize))
+	if err != nil {
+		utils.BadRequestResponse(c, "Contenu de l'inventaire illisible")
+		return
+	}
+	var req dto.DiscoveryReportRequest
+	if err := binding.JSON.BindBody(body, &req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.discoveryService.Ingest(agentID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Inventaire traité")
+}
+
+// GetAgents récupère les agents d'inventaire déclarés
+// @Summary Lister les agents d'inventaire
+// @Description Liste les agents autorisés à remonter des inventaires (sans leur clé d'API) (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.DiscoveryAgentDTO
+// @Router /integrations/discovery/agents [get]
+func (h *AssetDiscoveryHandler) GetAgents(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_discovery.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_discovery.manage")
+		return
+	}
+
+	agents, err := h.discoveryService.GetAgents()
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, agents, "Agents d'inventaire récupérés avec succès")
+}
+
+// CreateAgent déclare un agent d'inventaire
+// @Summary Déclarer un agent d'inventaire
+// @Description Crée la clé d'API d'un agent d'inventaire, retournée uniquement dans cette réponse. Les actifs créés le sont dans la filiale de l'agent, au nom de l'utilisateur connecté (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateDiscoveryAgentRequest true "Agent à déclarer"
+// @Success 201 {object} dto.DiscoveryAgentDTO
+// @Failure 400 {object} utils.Response
+// @Router /integrations/discovery/agents [post]
+func (h *AssetDiscoveryHandler) CreateAgent(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_discovery.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_discovery.manage")
+		return
+	}
+
+	var req dto.CreateDiscoveryAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	agent, err := h.discoveryService.CreateAgent(req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, agent, "Agent d'inventaire créé avec succès")
+}
+
+// UpdateAgent met à jour un agent d'inventaire
+// @Summary Modifier un agent d'inventaire
+// @Description Met à jour le nom, la filiale, la catégorie par défaut, la création automatique ou l'activation ; un agent inactif voit ses inventaires refusés (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'agent"
+// @Param request body dto.UpdateDiscoveryAgentRequest true "Champs à modifier"
+// @Success 200 {object} dto.DiscoveryAgentDTO
+// @Failure 404 {object} utils.Response
+// @Router /integrations/discovery/agents/{id} [put]
+func (h *AssetDiscoveryHandler) UpdateAgent(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_discovery.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_discovery.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateDiscoveryAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	agent, err := h.discoveryService.UpdateAgent(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, agent, "Agent d'inventaire mis à jour avec succès")
+}
+
+// RotateKey renouvelle la clé d'API d'un agent d'inventaire
+// @Summary Renouveler la clé d'un agent d'inventaire
+// @Description Génère une nouvelle clé d'API, retournée uniquement dans cette réponse ; l'ancienne clé est immédiatement refusée (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'agent"
+// @Success 200 {object} dto.DiscoveryAgentDTO
+// @Failure 404 {object} utils.Response
+// @Router /integrations/discovery/agents/{id}/key [post]
+func (h *AssetDiscoveryHandler) RotateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_discovery.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_discovery.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	agent, err := h.discoveryService.RotateKey(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, agent, "Clé d'API renouvelée")
+}
+
+// DeleteAgent supprime un agent d'inventaire
+// @Summary Supprimer un agent d'inventaire
+// @Description Supprime l'agent ; les actifs découverts et les écarts constatés sont conservés (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'agent"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /integrations/discovery/agents/{id} [delete]
+func (h *AssetDiscoveryHandler) DeleteAgent(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_discovery.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_discovery.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.discoveryService.DeleteAgent(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Agent d'inventaire supprimé avec succès")
+}
+
+// GetDrifts récupère les écarts entre la CMDB et les inventaires
+// @Summary Liste des écarts de découverte
+// @Description Liste paginée des écarts constatés entre la CMDB et les inventaires des agents, derniers constatés d'abord (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filtrer par état (open, accepted, dismissed, cleared)"
+// @Param asset_id query int false "Filtrer par actif"
+// @Param agent_id query int false "Filtrer par agent"
+// @Param page query int false "Numéro de page" default(1)
+// @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Success 200 {object} dto.AssetDiscoveryDriftListResponse
+// @Router /integrations/discovery/drifts [get]
+func (h *AssetDiscoveryHandler) GetDrifts(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_discovery.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_discovery.manage")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	var ids [2]*uint
+	for i, param := range []string{"asset_id", "agent_id"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, param+" invalide")
+			return
+		}
+		parsed := uint(id)
+		ids[i] = &parsed
+	}
+
+	drifts, err := h.discoveryService.GetDrifts(c.Query("status"), ids[0], ids[1], page, limit)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, drifts, "Écarts de découverte récupérés avec succès")
+}
+
+// AcceptDrift applique la valeur découverte à la CMDB
+// @Summary Accepter un écart de découverte
+// @Description Applique à l'actif la valeur remontée par l'agent ; pour un logiciel absent de l'inventaire, le retire de l'actif (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'écart"
+// @Success 200 {object} dto.AssetDiscoveryDriftDTO
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /integrations/discovery/drifts/{id}/accept [post]
+func (h *AssetDiscoveryHandler) AcceptDrift(c *gin.Context) {
+	h.resolveDrift(c, h.discoveryService.AcceptDrift, "Écart appliqué à la CMDB")
+}
+
+// DismissDrift ignore un écart de découverte
+// @Summary Ignorer un écart de découverte
+// @Description Conserve la valeur de la CMDB ; l'écart n'est pas signalé à nouveau tant que l'agent remonte la même valeur (nécessite asset_discovery.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'écart"
+// @Success 200 {object} dto.AssetDiscoveryDriftDTO
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /integrations/discovery/drifts/{id}/dismiss [post]
+func (h *AssetDiscoveryHandler) DismissDrift(c *gin.Context) {
+	h.resolveDrift(c, h.discoveryService.DismissDrift, "Écart ignoré")
+}
+
+// resolveDrift traite un écart de découverte (acceptation ou rejet)
+func (h *AssetDiscoveryHandler) resolveDrift(c *gin.Context, resolve func(id, userID uint) (*dto.AssetDiscoveryDriftDTO, error), message string) {
+	if !utils.RequirePermission(c, "asset_discovery.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_discovery.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	drift, err := resolve(uint(id), userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, drift, message)
+}
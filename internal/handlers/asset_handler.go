@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
@@ -37,7 +38,7 @@ func NewAssetHandler(assetService services.AssetService) *AssetHandler {
 func (h *AssetHandler) Create(c *gin.Context) {
 	var req dto.CreateAssetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -49,7 +50,7 @@ func (h *AssetHandler) Create(c *gin.Context) {
 
 	asset, err := h.assetService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -85,6 +86,57 @@ func (h *AssetHandler) GetByID(c *gin.Context) {
 	utils.SuccessResponse(c, asset, "Actif récupéré avec succès")
 }
 
+// GetByCode récupère un actif par son étiquette d'inventaire
+// @Summary Récupérer un actif par étiquette
+// @Description Récupère un actif IT par son étiquette d'inventaire (code lu sur le QR code de l'étiquette)
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param code path string true "Étiquette de l'actif (ex: AST-000042)"
+// @Success 200 {object} dto.AssetDTO
+// @Failure 404 {object} utils.Response
+// @Router /assets/by-code/{code} [get]
+func (h *AssetHandler) GetByCode(c *gin.Context) {
+	asset, err := h.assetService.GetByCode(c.Param("code"))
+	if err != nil {
+		utils.NotFoundResponse(c, "Actif introuvable")
+		return
+	}
+
+	utils.SuccessResponse(c, asset, "Actif récupéré avec succès")
+}
+
+// GetQRCode génère le QR code de l'étiquette d'un actif
+// @Summary QR code d'un actif
+// @Description Génère le QR code à imprimer sur l'étiquette de l'actif : lien vers la fiche de l'actif si ASSET_LABEL_URL est configurée, sinon l'étiquette seule
+// @Tags assets
+// @Security BearerAuth
+// @Produce image/png,image/svg+xml
+// @Param id path int true "ID de l'actif"
+// @Param format query string false "Format: png (défaut) ou svg"
+// @Param size query int false "Côté en pixels (défaut: 256, min: 64, max: 2048)"
+// @Success 200 {file} file "QR code"
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/qrcode [get]
+func (h *AssetHandler) GetQRCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	size, _ := strconv.Atoi(c.Query("size"))
+
+	content, contentType, err := h.assetService.GetQRCode(uint(id), c.DefaultQuery("format", "png"), size)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, contentType, content)
+}
+
 // GetAll récupère tous les actifs
 // @Summary Récupérer tous les actifs
 // @Description Récupère la liste de tous les actifs IT
@@ -131,7 +183,7 @@ func (h *AssetHandler) Assign(c *gin.Context) {
 
 	var req dto.AssignAssetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -143,7 +195,7 @@ func (h *AssetHandler) Assign(c *gin.Context) {
 
 	asset, err := h.assetService.Assign(uint(id), req, assignedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -201,7 +253,7 @@ func (h *AssetHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateAssetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -213,7 +265,7 @@ func (h *AssetHandler) Update(c *gin.Context) {
 
 	asset, err := h.assetService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -248,7 +300,7 @@ func (h *AssetHandler) Unassign(c *gin.Context) {
 	req := dto.AssignAssetRequest{UserID: 0}
 	asset, err := h.assetService.Unassign(uint(id), req, unassignedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -310,7 +362,7 @@ func (h *AssetHandler) GetByCategory(c *gin.Context) {
 	
 	assets, err := h.assetService.GetByCategory(queryScope, uint(categoryID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -337,7 +389,7 @@ func (h *AssetHandler) GetByUser(c *gin.Context) {
 
 	assets, err := h.assetService.GetByAssignedTo(uint(userID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -427,7 +479,7 @@ func (h *AssetHandler) LinkTicket(c *gin.Context) {
 
 	err = h.assetService.LinkTicket(uint(assetID), uint(ticketID), linkedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -462,9 +514,145 @@ func (h *AssetHandler) UnlinkTicket(c *gin.Context) {
 
 	err = h.assetService.UnlinkTicket(uint(assetID), uint(ticketID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, nil, "Liaison supprimée avec succès")
 }
+
+// Transition fait passer un actif dans un nouveau statut de son cycle de vie
+// @Summary Transition du cycle de vie d'un actif
+// @Description Fait passer un actif dans un nouveau statut (ordered, in_stock, assigned, in_repair, retired, disposed) selon les transitions autorisées et l'historise
+// @Tags assets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Param request body dto.AssetTransitionRequest true "Statut cible"
+// @Success 200 {object} dto.AssetDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /assets/{id}/transition [post]
+func (h *AssetHandler) Transition(c *gin.Context) {
+	if !utils.RequirePermission(c, "assets.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: assets.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.AssetTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	performedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	asset, err := h.assetService.Transition(uint(id), req, performedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, asset, "Statut de l'actif mis à jour avec succès")
+}
+
+// GetLifecycle récupère le cycle de vie d'un actif
+// @Summary Cycle de vie d'un actif
+// @Description Récupère le statut courant, les transitions possibles et l'historique du cycle de vie d'un actif
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Success 200 {object} dto.AssetLifecycleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/lifecycle [get]
+func (h *AssetHandler) GetLifecycle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	lifecycle, err := h.assetService.GetLifecycle(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, lifecycle, "Cycle de vie récupéré avec succès")
+}
+
+// GetDepreciation calcule l'amortissement d'un actif
+// @Summary Amortissement d'un actif
+// @Description Calcule le plan d'amortissement (linéaire ou dégressif) et la valeur nette comptable d'un actif à une date
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Param date query string false "Date de calcul (format: 2006-01-02, défaut: aujourd'hui)"
+// @Success 200 {object} dto.AssetDepreciationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/depreciation [get]
+func (h *AssetHandler) GetDepreciation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	asOf := time.Now()
+	if dateParam := c.Query("date"); dateParam != "" {
+		asOf, err = time.Parse("2006-01-02", dateParam)
+		if err != nil {
+			utils.BadRequestResponse(c, "Date invalide (format attendu: 2006-01-02)")
+			return
+		}
+	}
+
+	depreciation, err := h.assetService.GetDepreciation(uint(id), asOf)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, depreciation, "Amortissement calculé avec succès")
+}
+
+// GetExpiring récupère les actifs dont la garantie ou le contrat arrive à échéance
+// @Summary Garanties et contrats arrivant à échéance
+// @Description Récupère les actifs (hors mis au rebut) dont la garantie ou le contrat expire dans les prochains jours
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Horizon en jours (défaut: 30, max: 365)"
+// @Success 200 {array} dto.AssetDTO
+// @Failure 500 {object} utils.Response
+// @Router /assets/expiring [get]
+func (h *AssetHandler) GetExpiring(c *gin.Context) {
+	// Extraire le QueryScope du contexte (injecté par AuthMiddleware)
+	queryScope := utils.GetScopeFromContext(c)
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	assets, err := h.assetService.GetExpiring(queryScope, days)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la récupération des actifs")
+		return
+	}
+
+	utils.SuccessResponse(c, assets, "Actifs récupérés avec succès")
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/spreadsheet"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// AssetImportHandler gère l'import en masse d'actifs
+type AssetImportHandler struct {
+	importService services.AssetImportService
+}
+
+// NewAssetImportHandler crée une nouvelle instance de AssetImportHandler
+func NewAssetImportHandler(importService services.AssetImportService) *AssetImportHandler {
+	return &AssetImportHandler{
+		importService: importService,
+	}
+}
+
+// Import importe des actifs depuis un fichier CSV ou XLSX
+// @Summary Importer des actifs
+// @Description Importe des actifs depuis un fichier CSV ou XLSX (en-tête en première ligne). Une ligne dont l'étiquette, ou à défaut le numéro de série, correspond à un actif existant le met à jour (cellules vides ignorées) ; sinon l'actif est créé. La catégorie est désignée par son nom, la filiale par son code, l'utilisateur affecté par son nom d'utilisateur ou son email. Avec dry_run=true, le rapport liste les différences sans rien enregistrer. Sinon l'import est transactionnel : il n'est enregistré que si aucune ligne n'est en erreur
+// @Tags assets
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Fichier CSV ou XLSX"
+// @Param mapping formData string false "Correspondance JSON champ -> en-tête (ex: {\"serial_number\":\"S/N\"}) ; champs : code, name, serial_number, model, manufacturer, category, status, assigned_to, filiale, location, purchase_date, warranty_expiry, supplier, contract_reference, contract_end_date, purchase_cost, loanable, notes"
+// @Param dry_run formData bool false "Simulation : différences calculées, aucun actif enregistré"
+// @Success 200 {object} dto.AssetImportReportDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /assets/import [post]
+func (h *AssetImportHandler) Import(c *gin.Context) {
+	if !utils.RequirePermission(c, "assets.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: assets.create")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Fichier manquant", err.Error())
+		return
+	}
+	if file.Size > config.AppConfig.MaxUploadSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Fichier trop volumineux. Taille maximale: %d bytes", config.AppConfig.MaxUploadSize), nil)
+		return
+	}
+
+	var opts dto.AssetImportOptions
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Mapping); err != nil {
+			utils.BadRequestResponse(c, "Correspondance des colonnes invalide (objet JSON attendu)")
+			return
+		}
+	}
+	if raw := c.PostForm("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre dry_run invalide")
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+	defer content.Close()
+	data, err := io.ReadAll(io.LimitReader(content, config.AppConfig.MaxUploadSize))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+
+	rows, err := spreadsheet.ReadRows(data, file.Filename)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrUnsupportedFormat) || errors.Is(err, spreadsheet.ErrInvalidFile) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+
+	report, err := h.importService.Import(rows, opts, userID, utils.RequirePermission(c, "assets.update"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	message := "Import effectué"
+	switch {
+	case report.DryRun:
+		message = "Simulation d'import effectuée"
+	case report.Failed > 0:
+		message = "Import annulé : des lignes sont en erreur"
+	}
+	utils.SuccessResponse(c, report, message)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// AssetRelationHandler gère les relations de dépendance de la CMDB et l'analyse d'impact
+type AssetRelationHandler struct {
+	relationService services.AssetRelationService
+}
+
+// NewAssetRelationHandler crée une nouvelle instance de AssetRelationHandler
+func NewAssetRelationHandler(relationService services.AssetRelationService) *AssetRelationHandler {
+	return &AssetRelationHandler{
+		relationService: relationService,
+	}
+}
+
+// GetRelations récupère les relations de dépendance d'un actif
+// @Summary Relations d'un actif
+// @Description Récupère les dépendances de l'actif (direction outgoing : l'actif dépend de la cible) et les actifs ou logiciels qui s'appuient sur lui (direction incoming)
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Success 200 {array} dto.AssetRelationDTO
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/relations [get]
+func (h *AssetRelationHandler) GetRelations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	relations, err := h.relationService.GetRelations(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, relations, "Relations de l'actif récupérées avec succès")
+}
+
+// Create déclare une relation de dépendance
+// @Summary Déclarer une relation de dépendance
+// @Description Avec target_asset_id, l'actif dépend de l'actif cible (runs_on, connects_to, depends_on) ; avec software_id, le logiciel s'exécute sur l'actif ou en dépend (runs_on, depends_on) (nécessite assets.update)
+// @Tags assets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Param request body dto.CreateAssetRelationRequest true "Relation"
+// @Success 201 {object} dto.AssetRelationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/relations [post]
+func (h *AssetRelationHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "assets.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: assets.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.CreateAssetRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	relation, err := h.relationService.Create(uint(id), req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, relation, "Relation créée avec succès")
+}
+
+// Delete supprime une relation de dépendance
+// @Summary Supprimer une relation de dépendance
+// @Description Supprime une relation dont l'actif est la source ou la cible (nécessite assets.update)
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Param relationId path int true "ID de la relation"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/relations/{relationId} [delete]
+func (h *AssetRelationHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "assets.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: assets.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	relationID, err := strconv.ParseUint(c.Param("relationId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de relation invalide")
+		return
+	}
+
+	if err := h.relationService.Delete(uint(id), uint(relationID)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Relation supprimée avec succès")
+}
+
+// GetImpact analyse l'impact d'une indisponibilité de l'actif
+// @Summary Analyse d'impact d'un actif
+// @Description Parcourt le graphe de dépendances : actifs et logiciels (services) qui s'appuient directement ou indirectement sur l'actif, et tickets ouverts sur l'ensemble. Utilisé pour l'analyse d'impact des changements
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Param depth query int false "Profondeur maximale (défaut: 5, max: 10)"
+// @Success 200 {object} dto.AssetImpactDTO
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/impact [get]
+func (h *AssetRelationHandler) GetImpact(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	depth, _ := strconv.Atoi(c.DefaultQuery("depth", "5"))
+
+	impact, err := h.relationService.GetImpact(uint(id), depth)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, impact, "Analyse d'impact réalisée avec succès")
+}
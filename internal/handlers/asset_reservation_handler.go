@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// AssetReservationHandler gère le parc de prêt : calendrier de disponibilité, réservations, remise et restitution
+type AssetReservationHandler struct {
+	reservationService services.AssetReservationService
+}
+
+// NewAssetReservationHandler crée une nouvelle instance de AssetReservationHandler
+func NewAssetReservationHandler(reservationService services.AssetReservationService) *AssetReservationHandler {
+	return &AssetReservationHandler{
+		reservationService: reservationService,
+	}
+}
+
+// canManageReservations indique si l'utilisateur gère le parc de prêt
+func canManageReservations(c *gin.Context) bool {
+	return utils.RequirePermission(c, "asset_reservations.manage")
+}
+
+// parseReservationTime lit une date (2006-01-02) ou un horodatage RFC3339
+func parseReservationTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// optionalUintQuery lit un paramètre d'identifiant facultatif
+func optionalUintQuery(c *gin.Context, name string) (*uint, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	parsed, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Paramètre "+name+" invalide")
+		return nil, false
+	}
+	id := uint(parsed)
+	return &id, true
+}
+
+// GetCalendar retourne le calendrier de disponibilité du parc de prêt
+// @Summary Calendrier de disponibilité du parc de prêt
+// @Description Équipements prêtables et créneaux occupés (réservations en attente, approuvées ou en cours) sur la période (92 jours maximum)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Début (YYYY-MM-DD ou RFC3339, défaut: aujourd'hui)"
+// @Param to query string false "Fin (YYYY-MM-DD ou RFC3339, défaut: début + 14 jours)"
+// @Param category_id query int false "Catégorie d'actif"
+// @Param filiale_id query int false "Filiale"
+// @Success 200 {object} dto.AssetReservationCalendarDTO
+// @Failure 400 {object} utils.Response
+// @Router /asset-reservations/calendar [get]
+func (h *AssetReservationHandler) GetCalendar(c *gin.Context) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := parseReservationTime(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre from invalide (YYYY-MM-DD ou RFC3339)")
+			return
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, 14)
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := parseReservationTime(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre to invalide (YYYY-MM-DD ou RFC3339)")
+			return
+		}
+		to = parsed
+	}
+	categoryID, ok := optionalUintQuery(c, "category_id")
+	if !ok {
+		return
+	}
+	filialeID, ok := optionalUintQuery(c, "filiale_id")
+	if !ok {
+		return
+	}
+
+	calendar, err := h.reservationService.GetCalendar(from, to, categoryID, filialeID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, calendar, "Calendrier du parc de prêt récupéré avec succès")
+}
+
+// GetAll récupère les réservations
+// @Summary Lister les réservations
+// @Description Liste les réservations du parc de prêt ; hors gestionnaires (asset_reservations.manage), seules ses propres réservations
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Produce json
+// @Param asset_id query int false "Équipement"
+// @Param requester_id query int false "Emprunteur (gestionnaires)"
+// @Param status query string false "Statut (pending, approved, rejected, cancelled, checked_out, returned)"
+// @Param overdue query bool false "Prêts en retard uniquement"
+// @Success 200 {array} dto.AssetReservationDTO
+// @Router /asset-reservations [get]
+func (h *AssetReservationHandler) GetAll(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	var filter repositories.AssetReservationFilter
+	var ok bool
+	if filter.AssetID, ok = optionalUintQuery(c, "asset_id"); !ok {
+		return
+	}
+	if filter.RequesterID, ok = optionalUintQuery(c, "requester_id"); !ok {
+		return
+	}
+	if status := c.Query("status"); status != "" {
+		filter.Statuses = []string{status}
+	}
+	overdue, _ := strconv.ParseBool(c.Query("overdue"))
+	if overdue {
+		filter.Statuses = []string{models.AssetReservationCheckedOut}
+	}
+
+	reservations, err := h.reservationService.GetAll(filter, userID, canManageReservations(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	if overdue {
+		late := make([]dto.AssetReservationDTO, 0, len(reservations))
+		for _, reservation := range reservations {
+			if reservation.Overdue {
+				late = append(late, reservation)
+			}
+		}
+		reservations = late
+	}
+
+	utils.SuccessResponse(c, reservations, "Réservations récupérées avec succès")
+}
+
+// GetByID récupère une réservation
+// @Summary Récupérer une réservation
+// @Description Récupère une réservation (emprunteur, demandeur ou gestionnaire du parc de prêt)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la réservation"
+// @Success 200 {object} dto.AssetReservationDTO
+// @Failure 404 {object} utils.Response
+// @Router /asset-reservations/{id} [get]
+func (h *AssetReservationHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	reservation, err := h.reservationService.GetByID(uint(id), userID, canManageReservations(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, reservation, "Réservation récupérée avec succès")
+}
+
+// Create demande la réservation d'un équipement
+// @Summary Réserver un équipement
+// @Description Demande la réservation d'un équipement du parc de prêt sur un créneau ; refusée (409) si le créneau chevauche une réservation en attente, approuvée ou en cours. Une réservation créée par un gestionnaire (asset_reservations.manage) est approuvée d'office ; les autres attendent une approbation (directe ou via le circuit d'approbation, entity_type asset_reservation)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAssetReservationRequest true "Réservation"
+// @Success 201 {object} dto.AssetReservationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /asset-reservations [post]
+func (h *AssetReservationHandler) Create(c *gin.Context) {
+	var req dto.CreateAssetReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	reservation, err := h.reservationService.Create(req, userID, canManageReservations(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, reservation, "Réservation enregistrée avec succès")
+}
+
+// Approve approuve une réservation
+// @Summary Approuver une réservation
+// @Description Approuve une réservation en attente (nécessite asset_reservations.manage)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la réservation"
+// @Param request body dto.AssetReservationDecisionRequest false "Commentaire"
+// @Success 200 {object} dto.AssetReservationDTO
+// @Failure 409 {object} utils.Response
+// @Router /asset-reservations/{id}/approve [post]
+func (h *AssetReservationHandler) Approve(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// Reject refuse une réservation
+// @Summary Refuser une réservation
+// @Description Refuse une réservation en attente (nécessite asset_reservations.manage)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la réservation"
+// @Param request body dto.AssetReservationDecisionRequest false "Motif"
+// @Success 200 {object} dto.AssetReservationDTO
+// @Router /asset-reservations/{id}/reject [post]
+func (h *AssetReservationHandler) Reject(c *gin.Context) {
+	h.decide(c, false)
+}
+
+// decide applique la décision d'un gestionnaire du parc
+func (h *AssetReservationHandler) decide(c *gin.Context, approve bool) {
+	if !canManageReservations(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_reservations.manage")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	var req dto.AssetReservationDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	var reservation *dto.AssetReservationDTO
+	message := "Réservation refusée"
+	if approve {
+		reservation, err = h.reservationService.Approve(uint(id), userID, req.Comment)
+		message = "Réservation approuvée"
+	} else {
+		reservation, err = h.reservationService.Reject(uint(id), userID, req.Comment)
+	}
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, reservation, message)
+}
+
+// Cancel annule une réservation
+// @Summary Annuler une réservation
+// @Description Annule une réservation en attente ou approuvée (emprunteur, demandeur ou gestionnaire du parc)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la réservation"
+// @Success 200 {object} dto.AssetReservationDTO
+// @Router /asset-reservations/{id}/cancel [post]
+func (h *AssetReservationHandler) Cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	reservation, err := h.reservationService.Cancel(uint(id), userID, canManageReservations(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, reservation, "Réservation annulée")
+}
+
+// CheckOut enregistre la remise de l'équipement
+// @Summary Remettre l'équipement
+// @Description Enregistre la remise de l'équipement à l'emprunteur d'une réservation approuvée (nécessite asset_reservations.manage)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la réservation"
+// @Success 200 {object} dto.AssetReservationDTO
+// @Failure 409 {object} utils.Response
+// @Router /asset-reservations/{id}/checkout [post]
+func (h *AssetReservationHandler) CheckOut(c *gin.Context) {
+	if !canManageReservations(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_reservations.manage")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	reservation, err := h.reservationService.CheckOut(uint(id), userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, reservation, "Équipement remis")
+}
+
+// Return enregistre la restitution de l'équipement
+// @Summary Restituer l'équipement
+// @Description Enregistre la restitution de l'équipement prêté et son état (nécessite asset_reservations.manage)
+// @Tags asset-reservations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la réservation"
+// @Param request body dto.AssetReservationReturnRequest false "État constaté"
+// @Success 200 {object} dto.AssetReservationDTO
+// @Router /asset-reservations/{id}/return [post]
+func (h *AssetReservationHandler) Return(c *gin.Context) {
+	if !canManageReservations(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_reservations.manage")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	var req dto.AssetReservationReturnRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	reservation, err := h.reservationService.Return(uint(id), req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, reservation, "Équipement restitué")
+}
@@ -1,12 +1,11 @@
 package handlers
 
 import (
-	"fmt"
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/logger"
 	"github.com/mcicare/itsm-backend/internal/services"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
@@ -38,15 +37,14 @@ func NewAssetSoftwareHandler(assetSoftwareService services.AssetSoftwareService)
 func (h *AssetSoftwareHandler) Create(c *gin.Context) {
 	var req dto.CreateAssetSoftwareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	software, err := h.assetSoftwareService.Create(req)
 	if err != nil {
-		// Log l'erreur pour le débogage
-		fmt.Printf("Erreur lors de la création du logiciel: %v\n", err)
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		logger.FromContext(c.Request.Context()).Warn("asset_software_create_failed", "error", err)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -119,7 +117,7 @@ func (h *AssetSoftwareHandler) GetByAssetID(c *gin.Context) {
 
 	softwareList, err := h.assetSoftwareService.GetByAssetID(uint(assetID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -145,7 +143,7 @@ func (h *AssetSoftwareHandler) GetBySoftwareName(c *gin.Context) {
 
 	softwareList, err := h.assetSoftwareService.GetBySoftwareName(softwareName)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -173,7 +171,7 @@ func (h *AssetSoftwareHandler) GetBySoftwareNameAndVersion(c *gin.Context) {
 
 	softwareList, err := h.assetSoftwareService.GetBySoftwareNameAndVersion(softwareName, version)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -203,13 +201,13 @@ func (h *AssetSoftwareHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateAssetSoftwareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	software, err := h.assetSoftwareService.Update(uint(id), req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
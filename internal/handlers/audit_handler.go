@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -8,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/timezone"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
@@ -27,6 +30,7 @@ func NewAuditHandler(auditService services.AuditService) *AuditHandler {
 type (
 	auditLogListResponse = dto.AuditLogListResponse
 	auditLogDTO          = dto.AuditLogDTO
+	auditChainDTO        = dto.AuditChainVerificationDTO
 )
 
 // GetAll récupère tous les logs d'audit
@@ -150,7 +154,7 @@ func (h *AuditHandler) GetByUserID(c *gin.Context) {
 	
 	logs, err := h.auditService.GetByUserID(queryScope, uint(userID), startDate, endDate)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -212,7 +216,7 @@ func (h *AuditHandler) GetByEntity(c *gin.Context) {
 	
 	logs, err := h.auditService.GetByEntity(queryScope, entityType, uint(entityID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -242,10 +246,127 @@ func (h *AuditHandler) GetTicketAuditTrail(c *gin.Context) {
 	
 	logs, err := h.auditService.GetTicketAuditTrail(queryScope, uint(ticketID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, logs, "Piste d'audit récupérée avec succès")
 }
 
+
+// VerifyChain vérifie l'intégrité du journal d'audit
+// @Summary Vérifier l'intégrité du journal d'audit
+// @Description Recalcule l'empreinte chaînée de chaque entrée et signale la première entrée modifiée, supprimée ou insérée (nécessite audit.verify)
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} auditChainDTO
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /audit-logs/verify [get]
+func (h *AuditHandler) VerifyChain(c *gin.Context) {
+	if !utils.RequirePermission(c, "audit.verify") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: audit.verify")
+		return
+	}
+
+	result, err := h.auditService.VerifyChain()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	if !result.Valid {
+		utils.SuccessResponse(c, result, "Altération détectée dans le journal d'audit")
+		return
+	}
+	utils.SuccessResponse(c, result, "Journal d'audit intègre")
+}
+
+// Export exporte les logs d'audit filtrés
+// @Summary Exporter les logs d'audit
+// @Description Exporte en flux (CSV ou JSONL) les logs d'audit visibles selon les permissions audit.view_*, filtrés par période, utilisateur, action ou entité
+// @Tags audit
+// @Security BearerAuth
+// @Produce text/csv,application/x-ndjson
+// @Param format query string false "Format d'export : csv (défaut) ou jsonl"
+// @Param startDate query string false "Date de début incluse (YYYY-MM-DD)"
+// @Param endDate query string false "Date de fin incluse (YYYY-MM-DD)"
+// @Param userId query int false "Filtrer par ID utilisateur"
+// @Param action query string false "Filtrer par action"
+// @Param entityType query string false "Filtrer par type d'entité"
+// @Param entityId query int false "Filtrer par ID d'entité"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /audit-logs/export [get]
+func (h *AuditHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", services.AuditExportCSV)
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case services.AuditExportCSV:
+	case services.AuditExportJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		utils.BadRequestResponse(c, "Format d'export non supporté (csv ou jsonl)")
+		return
+	}
+
+	filter := dto.AuditLogExportFilter{
+		Action:     c.Query("action"),
+		EntityType: c.Query("entityType"),
+	}
+	location := utils.RequestLocation(c)
+	if raw := c.Query("startDate"); raw != "" {
+		date, err := timezone.ParseDate(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre startDate invalide (YYYY-MM-DD)")
+			return
+		}
+		from, _ := timezone.DayBounds(date, location)
+		filter.From = &from
+	}
+	if raw := c.Query("endDate"); raw != "" {
+		date, err := timezone.ParseDate(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre endDate invalide (YYYY-MM-DD)")
+			return
+		}
+		_, to := timezone.DayBounds(date, location)
+		filter.To = &to
+	}
+	if raw := c.Query("userId"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID utilisateur invalide")
+			return
+		}
+		userID := uint(id)
+		filter.UserID = &userID
+	}
+	if raw := c.Query("entityId"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID d'entité invalide")
+			return
+		}
+		entityID := uint(id)
+		filter.EntityID = &entityID
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit_logs_%s.%s"`, time.Now().Format("20060102_150405"), format))
+	c.Status(http.StatusOK)
+
+	queryScope := utils.GetScopeFromContext(c)
+	if err := h.auditService.Export(queryScope, filter, format, c.Writer); err != nil {
+		// L'export a pu commencer : la réponse ne peut plus être remplacée par une erreur JSON
+		if c.Writer.Size() <= 0 {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		log.Printf("Audit: export interrompu: %v", err)
+	}
+}
@@ -38,7 +38,7 @@ func NewAuthHandler(authService services.AuthService, userService services.UserS
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données de connexion invalides", err.Error())
+		utils.ValidationErrorResponseWithMessage(c, "Données de connexion invalides", err)
 		return
 	}
 
@@ -65,7 +65,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req dto.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -163,13 +163,13 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données d'inscription invalides", err.Error())
+		utils.ValidationErrorResponseWithMessage(c, "Données d'inscription invalides", err)
 		return
 	}
 
 	response, err := h.authService.Register(req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/services"
@@ -54,7 +52,7 @@ func (h *BackupHandler) GetConfiguration(c *gin.Context) {
 func (h *BackupHandler) UpdateConfiguration(c *gin.Context) {
 	var req dto.BackupConfigurationDTO
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -66,7 +64,7 @@ func (h *BackupHandler) UpdateConfiguration(c *gin.Context) {
 
 	config, err := h.backupService.UpdateConfiguration(req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -101,7 +99,7 @@ func (h *BackupHandler) ExecuteBackup(c *gin.Context) {
 
 	response, err := h.backupService.ExecuteBackup(backupType, executedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
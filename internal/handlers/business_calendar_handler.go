@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// BusinessCalendarHandler gère les handlers des calendriers ouvrés (heures de travail et jours fériés des SLA)
+type BusinessCalendarHandler struct {
+	calendarService services.BusinessCalendarService
+}
+
+// NewBusinessCalendarHandler crée une nouvelle instance de BusinessCalendarHandler
+func NewBusinessCalendarHandler(calendarService services.BusinessCalendarService) *BusinessCalendarHandler {
+	return &BusinessCalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetAll récupère les calendriers ouvrés
+// @Summary Lister les calendriers ouvrés
+// @Description Récupère les calendriers ouvrés (heures de travail et jours fériés) utilisés pour calculer les échéances SLA. Le calendrier sans filiale s'applique par défaut
+// @Tags sla
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.BusinessCalendarDTO
+// @Failure 403 {object} utils.Response
+// @Router /sla/calendars [get]
+func (h *BusinessCalendarHandler) GetAll(c *gin.Context) {
+	if !utils.RequirePermission(c, "sla.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: sla.view")
+		return
+	}
+
+	calendars, err := h.calendarService.GetAll()
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, calendars, "Calendriers ouvrés récupérés avec succès")
+}
+
+// GetByID récupère un calendrier ouvré par son ID
+// @Summary Récupérer un calendrier ouvré
+// @Description Récupère un calendrier ouvré avec ses créneaux et ses jours fériés
+// @Tags sla
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du calendrier"
+// @Success 200 {object} dto.BusinessCalendarDTO
+// @Failure 404 {object} utils.Response
+// @Router /sla/calendars/{id} [get]
+func (h *BusinessCalendarHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "sla.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: sla.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	calendar, err := h.calendarService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, calendar, "Calendrier ouvré récupéré avec succès")
+}
+
+// Create crée un calendrier ouvré
+// @Summary Créer un calendrier ouvré
+// @Description Crée le calendrier ouvré d'une filiale (ou le calendrier par défaut sans filiale). Les nouvelles échéances SLA sont calculées en temps ouvré (nécessite sla.manage)
+// @Tags sla
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateBusinessCalendarRequest true "Données du calendrier"
+// @Success 201 {object} dto.BusinessCalendarDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sla/calendars [post]
+func (h *BusinessCalendarHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "sla.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: sla.manage")
+		return
+	}
+
+	var req dto.CreateBusinessCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	calendar, err := h.calendarService.Create(req, createdByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, calendar, "Calendrier ouvré créé avec succès")
+}
+
+// Update met à jour un calendrier ouvré
+// @Summary Mettre à jour un calendrier ouvré
+// @Description Met à jour un calendrier ouvré ; les créneaux fournis remplacent les existants. Les échéances déjà calculées ne sont pas modifiées (nécessite sla.manage)
+// @Tags sla
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du calendrier"
+// @Param request body dto.UpdateBusinessCalendarRequest true "Données à mettre à jour"
+// @Success 200 {object} dto.BusinessCalendarDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sla/calendars/{id} [put]
+func (h *BusinessCalendarHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "sla.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: sla.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateBusinessCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	calendar, err := h.calendarService.Update(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, calendar, "Calendrier ouvré mis à jour avec succès")
+}
+
+// Delete supprime un calendrier ouvré
+// @Summary Supprimer un calendrier ouvré
+// @Description Supprime un calendrier ouvré et ses jours fériés (nécessite sla.manage)
+// @Tags sla
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du calendrier"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sla/calendars/{id} [delete]
+func (h *BusinessCalendarHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "sla.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: sla.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.calendarService.Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Calendrier ouvré supprimé avec succès")
+}
+
+// AddHoliday ajoute un jour férié à un calendrier
+// @Summary Ajouter un jour férié
+// @Description Ajoute un jour férié (ponctuel ou reconduit chaque année) à un calendrier ouvré (nécessite sla.manage)
+// @Tags sla
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du calendrier"
+// @Param request body dto.CreateBusinessHolidayRequest true "Jour férié"
+// @Success 201 {object} dto.BusinessCalendarDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sla/calendars/{id}/holidays [post]
+func (h *BusinessCalendarHandler) AddHoliday(c *gin.Context) {
+	if !utils.RequirePermission(c, "sla.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: sla.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.CreateBusinessHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	calendar, err := h.calendarService.AddHoliday(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, calendar, "Jour férié ajouté avec succès")
+}
+
+// DeleteHoliday supprime un jour férié d'un calendrier
+// @Summary Supprimer un jour férié
+// @Description Supprime un jour férié d'un calendrier ouvré (nécessite sla.manage)
+// @Tags sla
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du calendrier"
+// @Param holiday_id path int true "ID du jour férié"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sla/calendars/{id}/holidays/{holiday_id} [delete]
+func (h *BusinessCalendarHandler) DeleteHoliday(c *gin.Context) {
+	if !utils.RequirePermission(c, "sla.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: sla.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	holidayID, err := strconv.ParseUint(c.Param("holiday_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID du jour férié invalide")
+		return
+	}
+
+	if err := h.calendarService.DeleteHoliday(uint(id), uint(holidayID)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Jour férié supprimé avec succès")
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// CalendarFeedHandler gère les flux de calendrier ICS
+type CalendarFeedHandler struct {
+	calendarFeedService services.CalendarFeedService
+}
+
+// NewCalendarFeedHandler crée une nouvelle instance de CalendarFeedHandler
+func NewCalendarFeedHandler(calendarFeedService services.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		calendarFeedService: calendarFeedService,
+	}
+}
+
+// calendarFeedErrorResponse traduit une erreur du service en réponse HTTP
+func calendarFeedErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// Create crée un flux de calendrier pour l'utilisateur connecté
+// @Summary Créer un flux de calendrier ICS
+// @Description Crée une URL d'abonnement ICS contenant les échéances SLA des tickets assignés, les changements planifiés et les jalons des projets de l'utilisateur (scope user) ou de son département (scope department). L'URL contient un token secret et n'est retournée qu'à la création
+// @Tags calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCalendarFeedRequest true "Flux à créer"
+// @Success 201 {object} dto.CalendarFeedDTO
+// @Failure 400 {object} utils.Response
+// @Router /calendar/feeds [post]
+func (h *CalendarFeedHandler) Create(c *gin.Context) {
+	var req dto.CreateCalendarFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	feed, err := h.calendarFeedService.Create(userID, req)
+	if err != nil {
+		calendarFeedErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, feed, "Flux de calendrier créé avec succès")
+}
+
+// GetAll récupère les flux de calendrier de l'utilisateur connecté
+// @Summary Lister mes flux de calendrier ICS
+// @Description Liste les flux de calendrier de l'utilisateur (sans leur URL)
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.CalendarFeedDTO
+// @Router /calendar/feeds [get]
+func (h *CalendarFeedHandler) GetAll(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	feeds, err := h.calendarFeedService.List(userID)
+	if err != nil {
+		calendarFeedErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, feeds, "Flux de calendrier récupérés avec succès")
+}
+
+// Delete révoque un flux de calendrier de l'utilisateur connecté
+// @Summary Révoquer un flux de calendrier ICS
+// @Description Supprime le flux ; son URL cesse immédiatement de fonctionner
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du flux"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /calendar/feeds/{id} [delete]
+func (h *CalendarFeedHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.calendarFeedService.Delete(uint(id), userID); err != nil {
+		calendarFeedErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Flux de calendrier supprimé avec succès")
+}
+
+// Feed retourne le contenu d'un flux au format iCalendar (accès par token, sans authentification)
+// @Summary Lire un flux de calendrier ICS
+// @Description Retourne le calendrier iCalendar du flux, recalculé à chaque lecture. Destiné aux clients de calendrier (Outlook, Google Agenda, ...)
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Token du flux (extension .ics facultative)"
+// @Success 200 {string} string
+// @Failure 404 {object} utils.Response
+// @Router /calendar/feeds/{token} [get]
+func (h *CalendarFeedHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	content, err := h.calendarFeedService.Render(token)
+	if err != nil {
+		calendarFeedErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", `inline; filename="kronos.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", content)
+}
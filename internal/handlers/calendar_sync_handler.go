@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// CalendarSyncHandler gère la connexion des agendas Google et Microsoft des utilisateurs
+type CalendarSyncHandler struct {
+	calendarSyncService services.CalendarSyncService
+}
+
+// NewCalendarSyncHandler crée une nouvelle instance de CalendarSyncHandler
+func NewCalendarSyncHandler(calendarSyncService services.CalendarSyncService) *CalendarSyncHandler {
+	return &CalendarSyncHandler{
+		calendarSyncService: calendarSyncService,
+	}
+}
+
+// calendarSyncErrorResponse traduit une erreur du service en réponse HTTP
+func calendarSyncErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "synchronisation non configurée"):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// GetProviders liste les fournisseurs d'agenda disponibles
+// @Summary Lister les fournisseurs d'agenda
+// @Description Indique pour Google et Microsoft si la synchronisation est configurée sur cette instance
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.CalendarProviderDTO
+// @Router /calendar/providers [get]
+func (h *CalendarSyncHandler) GetProviders(c *gin.Context) {
+	utils.SuccessResponse(c, h.calendarSyncService.Providers(), "Fournisseurs d'agenda récupérés avec succès")
+}
+
+// GetConnections récupère les agendas connectés de l'utilisateur connecté
+// @Summary Lister mes agendas connectés
+// @Description Liste les agendas Google et Microsoft connectés, avec l'état de leur dernière synchronisation
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.CalendarConnectionDTO
+// @Router /calendar/connections [get]
+func (h *CalendarSyncHandler) GetConnections(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	connections, err := h.calendarSyncService.List(userID)
+	if err != nil {
+		calendarSyncErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, connections, "Agendas connectés récupérés avec succès")
+}
+
+// Connect démarre la connexion d'un agenda
+// @Summary Connecter un agenda
+// @Description Retourne l'URL de consentement OAuth du fournisseur vers laquelle rediriger l'utilisateur. Une fois l'accès accordé, les tâches de projet assignées et les maintenances planifiées (changements) sont publiées dans l'agenda puis tenues à jour. Reconnecter un agenda révoqué utilise le même point d'accès
+// @Tags calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.ConnectCalendarRequest true "Fournisseur"
+// @Success 200 {object} dto.CalendarAuthorizationDTO
+// @Failure 503 {object} utils.Response
+// @Router /calendar/connections [post]
+func (h *CalendarSyncHandler) Connect(c *gin.Context) {
+	var req dto.ConnectCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	authorization, err := h.calendarSyncService.Authorize(userID, req.Provider)
+	if err != nil {
+		calendarSyncErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, authorization, "Autorisation de l'agenda demandée")
+}
+
+// Callback termine la connexion d'un agenda (retour du fournisseur OAuth, sans authentification)
+// @Summary Retour de l'autorisation d'un agenda
+// @Description Point de retour OAuth déclaré auprès de Google ou Microsoft (/api/v1/calendar/oauth/{provider}/callback). L'utilisateur est identifié par le paramètre state signé ; il est redirigé vers CALENDAR_SYNC_RETURN_URL si elle est configurée
+// @Tags calendar
+// @Produce json
+// @Param provider path string true "Fournisseur (google, microsoft)"
+// @Param code query string false "Code d'autorisation"
+// @Param state query string true "Paramètre state de la demande"
+// @Success 200 {object} dto.CalendarConnectionDTO
+// @Failure 400 {object} utils.Response
+// @Router /calendar/oauth/{provider}/callback [get]
+func (h *CalendarSyncHandler) Callback(c *gin.Context) {
+	provider := c.Param("provider")
+	connection, err := h.calendarSyncService.Callback(c.Request.Context(), provider, c.Query("code"), c.Query("state"))
+
+	if returnURL := h.calendarSyncService.ReturnURL(); returnURL != "" {
+		params := url.Values{"provider": {provider}}
+		if err != nil {
+			params.Set("calendar", "error")
+			params.Set("message", err.Error())
+		} else {
+			params.Set("calendar", "connected")
+		}
+		separator := "?"
+		if strings.Contains(returnURL, "?") {
+			separator = "&"
+		}
+		c.Redirect(http.StatusFound, returnURL+separator+params.Encode())
+		return
+	}
+
+	if err != nil {
+		calendarSyncErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, connection, "Agenda connecté avec succès")
+}
+
+// Sync planifie la synchronisation immédiate d'un agenda connecté
+// @Summary Synchroniser un agenda
+// @Description Planifie la synchronisation de l'agenda (effectuée aussi périodiquement)
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'agenda connecté"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /calendar/connections/{id}/sync [post]
+func (h *CalendarSyncHandler) Sync(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.calendarSyncService.RequestSync(c.Request.Context(), uint(id), userID); err != nil {
+		calendarSyncErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Synchronisation planifiée")
+}
+
+// Disconnect déconnecte un agenda
+// @Summary Déconnecter un agenda
+// @Description Supprime les événements publiés par Kronos dans l'agenda (si l'autorisation est encore valide) puis la connexion
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'agenda connecté"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /calendar/connections/{id} [delete]
+func (h *CalendarSyncHandler) Disconnect(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.calendarSyncService.Disconnect(c.Request.Context(), uint(id), userID); err != nil {
+		calendarSyncErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Agenda déconnecté avec succès")
+}
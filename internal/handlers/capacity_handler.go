@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// CapacityHandler gère le plan de charge des ressources
+type CapacityHandler struct {
+	capacityService services.CapacityService
+}
+
+// NewCapacityHandler crée une nouvelle instance de CapacityHandler
+func NewCapacityHandler(capacityService services.CapacityService) *CapacityHandler {
+	return &CapacityHandler{
+		capacityService: capacityService,
+	}
+}
+
+// GetCapacity calcule le plan de charge des ressources par semaine
+// @Summary Plan de charge des ressources
+// @Description Combine le reste à faire des tâches de projet, le reste à faire des tickets ouverts et le temps déclaré, par utilisateur et par semaine ISO : pourcentage d'allocation et alertes de surcharge (nécessite projects.capacity.view ; utilisateurs visibles selon le périmètre users.*)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Date de début (YYYY-MM-DD, ramenée au lundi ; défaut : aujourd'hui)"
+// @Param weeks query int false "Nombre de semaines (défaut 4, maximum 26)"
+// @Param department_id query int false "Département"
+// @Param filiale_id query int false "Filiale"
+// @Success 200 {object} dto.CapacityReportDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /projects/capacity [get]
+func (h *CapacityHandler) GetCapacity(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.capacity.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.capacity.view")
+		return
+	}
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	var filter repositories.CapacityFilter
+	if filter.DepartmentID, ok = parseOptionalUintQuery(c, "department_id"); !ok {
+		utils.BadRequestResponse(c, "ID du département invalide")
+		return
+	}
+	if filter.FilialeID, ok = parseOptionalUintQuery(c, "filiale_id"); !ok {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+
+	from := timezone.Today(timezone.Default())
+	if v := c.Query("from"); v != "" {
+		parsed, err := timezone.ParseDate(v)
+		if err != nil {
+			utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+	weeks := 0
+	if v := c.Query("weeks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			utils.BadRequestResponse(c, "Nombre de semaines invalide")
+			return
+		}
+		weeks = n
+	}
+
+	report, err := h.capacityService.GetCapacity(queryScope, filter, from, weeks)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Plan de charge calculé avec succès")
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -37,7 +36,7 @@ func NewChangeHandler(changeService services.ChangeService) *ChangeHandler {
 func (h *ChangeHandler) Create(c *gin.Context) {
 	var req dto.CreateChangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -49,7 +48,7 @@ func (h *ChangeHandler) Create(c *gin.Context) {
 
 	change, err := h.changeService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -131,7 +130,7 @@ func (h *ChangeHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateChangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -143,7 +142,7 @@ func (h *ChangeHandler) Update(c *gin.Context) {
 
 	change, err := h.changeService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -173,7 +172,7 @@ func (h *ChangeHandler) RecordResult(c *gin.Context) {
 
 	var req dto.RecordChangeResultRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -185,7 +184,7 @@ func (h *ChangeHandler) RecordResult(c *gin.Context) {
 
 	change, err := h.changeService.RecordResult(uint(id), req, recordedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -243,7 +242,7 @@ func (h *ChangeHandler) UpdateRisk(c *gin.Context) {
 
 	var req dto.UpdateRiskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -255,7 +254,7 @@ func (h *ChangeHandler) UpdateRisk(c *gin.Context) {
 
 	change, err := h.changeService.UpdateRisk(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -284,7 +283,7 @@ func (h *ChangeHandler) AssignResponsible(c *gin.Context) {
 
 	var req dto.AssignResponsibleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -296,7 +295,7 @@ func (h *ChangeHandler) AssignResponsible(c *gin.Context) {
 
 	change, err := h.changeService.AssignResponsible(uint(id), req, assignedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -355,7 +354,7 @@ func (h *ChangeHandler) GetByRisk(c *gin.Context) {
 
 	changes, err := h.changeService.GetByRisk(queryScope, riskLevel)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -385,7 +384,7 @@ func (h *ChangeHandler) GetByResponsible(c *gin.Context) {
 	
 	changes, err := h.changeService.GetByResponsible(queryScope, uint(userID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/logger"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ConfigHandler gère le rechargement de la configuration à chaud
+type ConfigHandler struct{}
+
+// NewConfigHandler crée une nouvelle instance de ConfigHandler
+func NewConfigHandler() *ConfigHandler {
+	return &ConfigHandler{}
+}
+
+// Reload recharge les paramètres non critiques de la configuration
+// @Summary Recharger la configuration
+// @Description Relit le .env, les fichiers de secrets et Vault puis applique les paramètres non critiques (LOG_LEVEL, RATE_LIMIT_*, CORS_*) sans redémarrage (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ConfigReloadResponse
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/config/reload [post]
+func (h *ConfigHandler) Reload(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	changed, err := config.Reload()
+	if err != nil {
+		utils.BadRequestResponse(c, "Rechargement de la configuration refusé: "+err.Error())
+		return
+	}
+	if changed == nil {
+		changed = []string{}
+	}
+
+	logger.FromContext(c.Request.Context()).Warn("config_reloaded", slog.Any("changed", changed))
+	utils.SuccessResponse(c, dto.ConfigReloadResponse{Changed: changed}, "Configuration rechargée avec succès")
+}
@@ -1,13 +1,12 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/timezone"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
@@ -82,7 +81,7 @@ func (h *DailyDeclarationHandler) GetByUserIDAndDate(c *gin.Context) {
 		return
 	}
 
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide (attendu: YYYY-MM-DD)")
 		return
@@ -153,7 +152,7 @@ func (h *DailyDeclarationHandler) Validate(c *gin.Context) {
 
 	declaration, err := h.dailyDeclarationService.Validate(uint(id), validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/scope"
 	"github.com/mcicare/itsm-backend/internal/services"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
@@ -63,7 +64,10 @@ func (h *DelayHandler) GetByID(c *gin.Context) {
 // @Failure 500 {object} utils.Response
 // @Router /delays [get]
 func (h *DelayHandler) GetAll(c *gin.Context) {
-	queryScope := utils.GetScopeFromContext(c)
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
 	if u := c.Query("user_id"); u != "" {
 		if id, err := strconv.ParseUint(u, 10, 32); err == nil {
 			uid := uint(id)
@@ -138,7 +142,7 @@ func (h *DelayHandler) CreateJustification(c *gin.Context) {
 
 	var req dto.CreateDelayJustificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -150,7 +154,7 @@ func (h *DelayHandler) CreateJustification(c *gin.Context) {
 
 	justification, err := h.delayService.CreateJustification(uint(delayID), req, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -159,7 +163,7 @@ func (h *DelayHandler) CreateJustification(c *gin.Context) {
 
 // ValidateJustification valide une justification
 // @Summary Valider une justification de retard
-// @Description Valide une justification de retard
+// @Description Enregistre la décision (validated) de l'approbateur attendu : étape en cours du circuit d'approbation (responsable puis chef de département, selon DELAY_JUSTIFICATION_APPROVAL_CHAIN), ou responsable du technicien sans circuit. Un validateur habilité (delays.validate, timesheet.validate_justification) peut décider hors circuit, qui est alors annulé. Par délégation (X-On-Behalf-Of), la décision est prise au nom du délégant
 // @Tags delays
 // @Security BearerAuth
 // @Accept json
@@ -169,18 +173,13 @@ func (h *DelayHandler) CreateJustification(c *gin.Context) {
 // @Success 200 {object} dto.DelayJustificationDTO
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Router /delays/justifications/{id}/validate [post]
 func (h *DelayHandler) ValidateJustification(c *gin.Context) {
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
-		return
-	}
-	if !scope.HasPermission("delays.validate") && !scope.HasPermission("timesheet.validate_justification") {
-		utils.ErrorResponse(c, http.StatusForbidden, "Vous n'avez pas la permission de valider les justifications de retards", nil)
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
-
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
@@ -190,7 +189,7 @@ func (h *DelayHandler) ValidateJustification(c *gin.Context) {
 
 	var req dto.ValidateDelayJustificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	if req.Validated == nil {
@@ -198,15 +197,14 @@ func (h *DelayHandler) ValidateJustification(c *gin.Context) {
 		return
 	}
 
-	validatedByID, exists := c.Get("user_id")
-	if !exists {
-		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+	validatedByID, ok := delayApproverID(c)
+	if !ok {
 		return
 	}
 
-	justification, err := h.delayService.ValidateJustification(uint(id), req, validatedByID.(uint))
+	justification, err := h.delayService.ValidateJustification(uint(id), req, validatedByID, canOverrideDelayApproval(scope))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -269,7 +267,7 @@ func (h *DelayHandler) UpdateJustification(c *gin.Context) {
 
 	var req dto.UpdateDelayJustificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -281,7 +279,7 @@ func (h *DelayHandler) UpdateJustification(c *gin.Context) {
 
 	updatedJustification, err := h.delayService.UpdateJustification(justification.ID, req, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -314,7 +312,7 @@ func (h *DelayHandler) DeleteJustification(c *gin.Context) {
 
 	err = h.delayService.DeleteJustification(uint(delayID), userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -377,7 +375,7 @@ func (h *DelayHandler) GetJustificationsByUserID(c *gin.Context) {
 
 // RejectJustification rejette une justification
 // @Summary Rejeter une justification de retard
-// @Description Rejette une justification de retard
+// @Description Rejette une justification de retard (mêmes règles que la validation ; commentaire requis dans un circuit d'approbation)
 // @Tags delays
 // @Security BearerAuth
 // @Accept json
@@ -386,18 +384,13 @@ func (h *DelayHandler) GetJustificationsByUserID(c *gin.Context) {
 // @Param request body dto.ValidateDelayJustificationRequest true "Commentaire de rejet"
 // @Success 200 {object} dto.DelayJustificationDTO
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Router /delays/{delayId}/justification/reject [post]
 func (h *DelayHandler) RejectJustification(c *gin.Context) {
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
-		return
-	}
-	if !scope.HasPermission("delays.validate") && !scope.HasPermission("timesheet.validate_justification") {
-		utils.ErrorResponse(c, http.StatusForbidden, "Vous n'avez pas la permission de rejeter les justifications de retards", nil)
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
-
 	delayIDParam := c.Param("id")
 	delayID, err := strconv.ParseUint(delayIDParam, 10, 32)
 	if err != nil {
@@ -407,23 +400,66 @@ func (h *DelayHandler) RejectJustification(c *gin.Context) {
 
 	var req dto.ValidateDelayJustificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	rejectedByID, ok := delayApproverID(c)
+	if !ok {
+		return
+	}
+
+	justification, err := h.delayService.RejectJustification(uint(delayID), req, rejectedByID, canOverrideDelayApproval(scope))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	rejectedByID, exists := c.Get("user_id")
+	utils.SuccessResponse(c, justification, "Justification rejetée avec succès")
+}
+
+// GetPendingMyApproval récupère les justifications attendant la décision de l'utilisateur
+// @Summary Justifications en attente de ma décision
+// @Description Liste les justifications dont l'utilisateur est l'approbateur attendu (étape en cours du circuit, ou responsable du technicien sans circuit), y compris pour le compte d'un collègue absent qui lui a délégué la validation des temps (on_behalf_of_id, à transmettre dans l'en-tête X-On-Behalf-Of pour décider)
+// @Tags delays
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.DelayJustificationDTO
+// @Failure 500 {object} utils.Response
+// @Router /delays/justifications/pending-my-approval [get]
+func (h *DelayHandler) GetPendingMyApproval(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
 	if !exists {
 		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
 		return
 	}
 
-	justification, err := h.delayService.RejectJustification(uint(delayID), req, rejectedByID.(uint))
+	justifications, err := h.delayService.GetPendingMyApproval(userID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.InternalServerErrorResponse(c, "Erreur lors de la récupération des justifications en attente")
 		return
 	}
 
-	utils.SuccessResponse(c, justification, "Justification rejetée avec succès")
+	utils.SuccessResponse(c, justifications, "Justifications en attente récupérées avec succès")
+}
+
+// delayApproverID retourne l'approbateur au nom duquel la décision est prise : le délégant en cas de délégation
+// (X-On-Behalf-Of), sinon l'utilisateur connecté
+func delayApproverID(c *gin.Context) (uint, bool) {
+	if delegatorID, delegated := c.Get("on_behalf_of_id"); delegated {
+		return delegatorID.(uint), true
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return 0, false
+	}
+	return userID, true
+}
+
+// canOverrideDelayApproval indique si l'utilisateur peut trancher une justification hors circuit d'approbation
+func canOverrideDelayApproval(queryScope *scope.QueryScope) bool {
+	return queryScope.HasPermission("delays.validate") || queryScope.HasPermission("timesheet.validate_justification")
 }
 
 // GetValidatedJustifications récupère les justifications validées
@@ -36,7 +36,7 @@ func NewDepartmentHandler(departmentService services.DepartmentService) *Departm
 func (h *DepartmentHandler) Create(c *gin.Context) {
 	var req dto.CreateDepartmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -68,7 +68,7 @@ func (h *DepartmentHandler) Create(c *gin.Context) {
 
 	department, err := h.departmentService.Create(req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -88,9 +88,8 @@ func (h *DepartmentHandler) Create(c *gin.Context) {
 // @Router /departments [get]
 func (h *DepartmentHandler) GetAll(c *gin.Context) {
 	activeOnly := c.DefaultQuery("active", "false") == "true"
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
 
@@ -208,7 +207,7 @@ func (h *DepartmentHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateDepartmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -235,7 +234,7 @@ func (h *DepartmentHandler) Update(c *gin.Context) {
 
 	department, err := h.departmentService.Update(uint(id), req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -262,7 +261,7 @@ func (h *DepartmentHandler) Delete(c *gin.Context) {
 
 	err = h.departmentService.Delete(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// EscalationHandler gère les handlers des règles d'escalade automatique des tickets
+type EscalationHandler struct {
+	escalationService services.EscalationService
+}
+
+// NewEscalationHandler crée une nouvelle instance de EscalationHandler
+func NewEscalationHandler(escalationService services.EscalationService) *EscalationHandler {
+	return &EscalationHandler{
+		escalationService: escalationService,
+	}
+}
+
+// canViewEscalationRules indique si l'utilisateur peut consulter les règles d'escalade
+func canViewEscalationRules(c *gin.Context) bool {
+	return utils.RequirePermission(c, "escalations.manage") || utils.RequirePermission(c, "sla.view")
+}
+
+// GetAll récupère les règles d'escalade
+// @Summary Lister les règles d'escalade
+// @Description Récupère les règles d'escalade automatique des tickets. Avec filiale_id ou category, retourne aussi les règles globales (sans filiale ou sans catégorie) qui s'y appliquent
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Produce json
+// @Param filiale_id query int false "Filiale"
+// @Param category query string false "Slug de la catégorie de ticket"
+// @Success 200 {array} dto.EscalationRuleDTO
+// @Failure 403 {object} utils.Response
+// @Router /escalation-rules [get]
+func (h *EscalationHandler) GetAll(c *gin.Context) {
+	if !canViewEscalationRules(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: escalations.manage")
+		return
+	}
+
+	var filialeID *uint
+	if v := c.Query("filiale_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID de la filiale invalide")
+			return
+		}
+		f := uint(id)
+		filialeID = &f
+	}
+
+	rules, err := h.escalationService.GetAll(filialeID, c.Query("category"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, rules, "Règles d'escalade récupérées avec succès")
+}
+
+// GetByID récupère une règle d'escalade par son ID
+// @Summary Récupérer une règle d'escalade
+// @Description Récupère une règle d'escalade par son identifiant
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la règle"
+// @Success 200 {object} dto.EscalationRuleDTO
+// @Failure 404 {object} utils.Response
+// @Router /escalation-rules/{id} [get]
+func (h *EscalationHandler) GetByID(c *gin.Context) {
+	if !canViewEscalationRules(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: escalations.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	rule, err := h.escalationService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, rule, "Règle d'escalade récupérée avec succès")
+}
+
+// Create crée une règle d'escalade
+// @Summary Créer une règle d'escalade
+// @Description Crée une règle d'escalade : lorsqu'un ticket reste plus de threshold_hours heures dans un statut (status_duration) ou que son SLA passe à risque (sla_at_risk), il est réassigné au groupe de repli, sa priorité est relevée et la chaîne d'escalade est notifiée. Les actions sont effectuées au nom du créateur de la règle (nécessite escalations.manage)
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateEscalationRuleRequest true "Données de la règle"
+// @Success 201 {object} dto.EscalationRuleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /escalation-rules [post]
+func (h *EscalationHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "escalations.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: escalations.manage")
+		return
+	}
+
+	var req dto.CreateEscalationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	rule, err := h.escalationService.Create(req, createdByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, rule, "Règle d'escalade créée avec succès")
+}
+
+// Update met à jour une règle d'escalade
+// @Summary Mettre à jour une règle d'escalade
+// @Description Met à jour une règle d'escalade ; les listes d'utilisateurs fournies remplacent les existantes (nécessite escalations.manage)
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la règle"
+// @Param request body dto.UpdateEscalationRuleRequest true "Données à mettre à jour"
+// @Success 200 {object} dto.EscalationRuleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /escalation-rules/{id} [put]
+func (h *EscalationHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "escalations.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: escalations.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateEscalationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	rule, err := h.escalationService.Update(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, rule, "Règle d'escalade mise à jour avec succès")
+}
+
+// Delete supprime une règle d'escalade
+// @Summary Supprimer une règle d'escalade
+// @Description Supprime une règle d'escalade et l'historique de ses applications (nécessite escalations.manage)
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la règle"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /escalation-rules/{id} [delete]
+func (h *EscalationHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "escalations.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: escalations.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.escalationService.Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Règle d'escalade supprimée avec succès")
+}
+
+// Evaluate applique immédiatement les règles status_duration
+// @Summary Évaluer les règles d'escalade
+// @Description Escalade les tickets restés trop longtemps dans un statut (également exécuté périodiquement par la tâche planifiée ticket_escalations). Les règles sla_at_risk s'appliquent au passage à risque du SLA (nécessite escalations.manage)
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.EscalationRunDTO
+// @Failure 500 {object} utils.Response
+// @Router /escalation-rules/evaluate [post]
+func (h *EscalationHandler) Evaluate(c *gin.Context) {
+	if !utils.RequirePermission(c, "escalations.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: escalations.manage")
+		return
+	}
+
+	escalated, err := h.escalationService.EvaluateStatusRules()
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, dto.EscalationRunDTO{Escalated: escalated}, "Règles d'escalade évaluées avec succès")
+}
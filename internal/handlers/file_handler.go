@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/storage"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// FileHandler sert les fichiers du stockage local via des URL signées
+type FileHandler struct {
+	storages map[string]storage.Storage
+	secret   string
+}
+
+// NewFileHandler crée une nouvelle instance de FileHandler
+func NewFileHandler(storages map[string]storage.Storage, secret string) *FileHandler {
+	return &FileHandler{
+		storages: storages,
+		secret:   secret,
+	}
+}
+
+// ServeSigned sert un fichier à partir d'une URL signée (sans jeton d'authentification)
+// @Summary Télécharger un fichier par URL signée
+// @Description Sert un fichier du stockage local ; l'URL est obtenue via les endpoints .../url et expire après STORAGE_SIGNED_URL_TTL
+// @Tags files
+// @Produce application/octet-stream
+// @Param namespace path string true "Espace de stockage (tickets, users)"
+// @Param key path string true "Clé du fichier"
+// @Param expires query int true "Expiration (timestamp Unix)"
+// @Param signature query string true "Signature HMAC"
+// @Success 200 {file} file "Fichier"
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /files/{namespace}/{key} [get]
+func (h *FileHandler) ServeSigned(c *gin.Context) {
+	namespace := c.Param("namespace")
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	if !storage.VerifySignature(h.secret, namespace, key, c.Query("expires"), c.Query("signature")) {
+		utils.ForbiddenResponse(c, "URL de téléchargement invalide ou expirée")
+		return
+	}
+
+	fileStorage, ok := h.storages[namespace]
+	if !ok {
+		utils.NotFoundResponse(c, "Fichier introuvable")
+		return
+	}
+	object, err := fileStorage.Get(c.Request.Context(), key)
+	if err != nil {
+		utils.NotFoundResponse(c, "Fichier introuvable")
+		return
+	}
+
+	serveStorageObject(c, object, "")
+}
+
+// serveStorageObject envoie le contenu d'un fichier du stockage et le ferme
+// fileName, si renseigné, est proposé au navigateur (affichage en ligne)
+func serveStorageObject(c *gin.Context, object *storage.Object, fileName string) {
+	defer object.Body.Close()
+
+	contentType := object.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	headers := map[string]string{}
+	if fileName != "" {
+		headers["Content-Disposition"] = mime.FormatMediaType("inline", map[string]string{"filename": fileName})
+	}
+	if !object.ModTime.IsZero() {
+		headers["Last-Modified"] = object.ModTime.UTC().Format(http.TimeFormat)
+	}
+	c.DataFromReader(http.StatusOK, object.Size, contentType, object.Body, headers)
+}
@@ -1,8 +1,8 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
@@ -24,7 +24,7 @@ func NewFilialeSoftwareHandler(deploymentService services.FilialeSoftwareService
 
 // Create crée un nouveau déploiement
 // @Summary Créer un déploiement
-// @Description Crée un nouveau déploiement de logiciel chez une filiale (nécessite software.deploy)
+// @Description Enregistre un déploiement effectué chez une filiale : il remplace la version en service du même environnement (nécessite software.deploy)
 // @Tags filiale-software
 // @Security BearerAuth
 // @Accept json
@@ -51,16 +51,17 @@ func (h *FilialeSoftwareHandler) Create(c *gin.Context) {
 
 	var req dto.CreateFilialeSoftwareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	// Utiliser le filiale_id de l'URL (prioritaire sur celui du body)
 	req.FilialeID = uint(filialeID)
 
-	deployment, err := h.deploymentService.Create(req)
+	userID, _ := utils.GetUserIDFromContext(c)
+	deployment, err := h.deploymentService.Create(req, userID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -261,13 +262,14 @@ func (h *FilialeSoftwareHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateFilialeSoftwareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
-	deployment, err := h.deploymentService.Update(uint(id), req)
+	userID, _ := utils.GetUserIDFromContext(c)
+	deployment, err := h.deploymentService.Update(uint(id), req, userID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -300,9 +302,170 @@ func (h *FilialeSoftwareHandler) Delete(c *gin.Context) {
 
 	err = h.deploymentService.Delete(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, nil, "Déploiement supprimé avec succès")
 }
+
+// Plan planifie un déploiement
+// @Summary Planifier un déploiement
+// @Description Planifie le déploiement d'une version d'un logiciel chez une filiale ; il reste inactif jusqu'à sa confirmation (nécessite software.deploy)
+// @Tags filiale-software
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param filiale_id path int true "ID de la filiale"
+// @Param request body dto.PlanFilialeSoftwareRequest true "Déploiement à planifier"
+// @Success 201 {object} dto.FilialeSoftwareDTO
+// @Failure 400 {object} utils.Response
+// @Router /filiales/{filiale_id}/software/plan [post]
+func (h *FilialeSoftwareHandler) Plan(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.deploy") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.deploy")
+		return
+	}
+
+	filialeID, err := strconv.ParseUint(c.Param("filiale_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+
+	var req dto.PlanFilialeSoftwareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+	deployment, err := h.deploymentService.Plan(uint(filialeID), req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, deployment, "Déploiement planifié avec succès")
+}
+
+// Start démarre un déploiement planifié
+// @Summary Démarrer un déploiement
+// @Description Passe un déploiement planifié au statut en cours (nécessite software.deploy)
+// @Tags filiale-software
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du déploiement"
+// @Param request body dto.DeploymentTransitionRequest false "Version et commentaire"
+// @Success 200 {object} dto.FilialeSoftwareDTO
+// @Failure 400 {object} utils.Response
+// @Router /filiales-software/{id}/start [post]
+func (h *FilialeSoftwareHandler) Start(c *gin.Context) {
+	h.transition(c, "software.deploy", h.deploymentService.Start, "Déploiement démarré")
+}
+
+// Confirm confirme un déploiement
+// @Summary Confirmer un déploiement
+// @Description Confirme un déploiement planifié ou en cours : il devient la version en service et remplace la précédente (nécessite software.deploy)
+// @Tags filiale-software
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du déploiement"
+// @Param request body dto.DeploymentTransitionRequest false "Version, date effective et commentaire"
+// @Success 200 {object} dto.FilialeSoftwareDTO
+// @Failure 400 {object} utils.Response
+// @Router /filiales-software/{id}/confirm [post]
+func (h *FilialeSoftwareHandler) Confirm(c *gin.Context) {
+	h.transition(c, "software.deploy", h.deploymentService.Confirm, "Déploiement confirmé")
+}
+
+// Rollback annule un déploiement
+// @Summary Annuler un déploiement (retour arrière)
+// @Description Annule un déploiement ; s'il était en service, la version remplacée est remise en service (nécessite software.manage_deployments)
+// @Tags filiale-software
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du déploiement"
+// @Param request body dto.DeploymentTransitionRequest false "Motif du retour arrière"
+// @Success 200 {object} dto.FilialeSoftwareDTO
+// @Failure 400 {object} utils.Response
+// @Router /filiales-software/{id}/rollback [post]
+func (h *FilialeSoftwareHandler) Rollback(c *gin.Context) {
+	h.transition(c, "software.manage_deployments", h.deploymentService.Rollback, "Déploiement annulé")
+}
+
+// transition applique une étape du cycle de vie d'un déploiement
+func (h *FilialeSoftwareHandler) transition(c *gin.Context, permission string, apply func(uint, dto.DeploymentTransitionRequest, uint) (*dto.FilialeSoftwareDTO, error), message string) {
+	if !utils.RequirePermission(c, permission) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: "+permission)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.DeploymentTransitionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+	deployment, err := apply(uint(id), req, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "déploiement introuvable":
+			utils.NotFoundResponse(c, "Déploiement introuvable")
+		case strings.HasPrefix(err.Error(), "erreur lors"):
+			utils.InternalServerErrorResponse(c, err.Error())
+		default:
+			utils.ServiceErrorResponse(c, err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, deployment, message)
+}
+
+// GetHistory récupère l'historique d'un déploiement
+// @Summary Historique d'un déploiement
+// @Description Étapes d'un déploiement : planification, démarrage, confirmation, remplacement, retour arrière (nécessite software.view)
+// @Tags filiale-software
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du déploiement"
+// @Success 200 {array} dto.FilialeSoftwareHistoryDTO
+// @Failure 404 {object} utils.Response
+// @Router /filiales-software/{id}/history [get]
+func (h *FilialeSoftwareHandler) GetHistory(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	history, err := h.deploymentService.GetHistory(uint(id))
+	if err != nil {
+		if err.Error() == "déploiement introuvable" {
+			utils.NotFoundResponse(c, "Déploiement introuvable")
+		} else {
+			utils.InternalServerErrorResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, history, "Historique récupéré avec succès")
+}
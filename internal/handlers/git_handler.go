@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"github.com/mcicare/itsm-backend/internal/vcs"
+)
+
+// gitWebhookMaxSize taille maximale d'un webhook GitHub / GitLab (un push peut contenir de nombreux commits)
+const gitWebhookMaxSize = 5 << 20
+
+// GitHandler gère le rattachement des commits et merge requests GitHub / GitLab aux tickets
+type GitHandler struct {
+	gitService services.GitIntegrationService
+}
+
+// NewGitHandler crée une nouvelle instance de GitHandler
+func NewGitHandler(gitService services.GitIntegrationService) *GitHandler {
+	return &GitHandler{
+		gitService: gitService,
+	}
+}
+
+// gitErrorResponse traduit une erreur du service en réponse HTTP
+func gitErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case err.Error() == "intégration Git non configurée":
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// Webhook reçoit les événements push et merge request de GitHub ou GitLab
+// @Summary Webhook Git entrant
+// @Description Point d'entrée des webhooks GitHub (push, pull_request ; signature X-Hub-Signature-256) et GitLab (Push Hook, Merge Request Hook ; jeton X-Gitlab-Token). Les commits et merge requests dont le message ou le titre cite un code de ticket (TKT-YYYY-NNNN) sont ajoutés à l'historique du ticket ; si GIT_PENDING_ON_MERGE est activé, la fusion d'une merge request passe le ticket en attente de validation
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.GitWebhookResultDTO
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /integrations/git/webhook [post]
+func (h *GitHandler) Webhook(c *gin.Context) {
+	if !h.gitService.Enabled() {
+		utils.NotFoundResponse(c, "Intégration Git non configurée")
+		return
+	}
+
+	provider, event := vcs.ProviderGitHub, c.GetHeader("X-GitHub-Event")
+	if gitlabEvent := c.GetHeader("X-Gitlab-Event"); gitlabEvent != "" {
+		provider, event = vcs.ProviderGitLab, gitlabEvent
+	}
+	if event == "" {
+		utils.BadRequestResponse(c, "En-tête X-GitHub-Event ou X-Gitlab-Event manquant")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, gitWebhookMaxSize))
+	if err != nil {
+		utils.BadRequestResponse(c, "Contenu du webhook illisible")
+		return
+	}
+	if !h.gitService.VerifyWebhook(provider, c.GetHeader("X-Gitlab-Token"), c.GetHeader("X-Hub-Signature-256"), body) {
+		utils.UnauthorizedResponse(c, "Signature du webhook invalide")
+		return
+	}
+
+	result, err := h.gitService.HandleWebhook(provider, event, body)
+	if err != nil {
+		gitErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Webhook traité")
+}
+
+// GetTicketLinks récupère les commits et merge requests liés à un ticket
+// @Summary Commits et merge requests d'un ticket
+// @Description Liste les commits et merge requests GitHub / GitLab citant le code du ticket, plus récents d'abord
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 200 {array} dto.TicketGitLinkDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/git-links [get]
+func (h *GitHandler) GetTicketLinks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	links, err := h.gitService.GetTicketLinks(uint(id))
+	if err != nil {
+		gitErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, links, "Liens Git récupérés avec succès")
+}
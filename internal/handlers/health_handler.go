@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/logger"
+)
+
+// HealthCheckFunc vérifie un composant et retourne une erreur s'il est indisponible
+// Le résultat optionnel (details) et l'erreur sont journalisés en cas d'échec, la réponse (publique) n'indique que ok/failed
+type HealthCheckFunc func(ctx context.Context) (details any, err error)
+
+// healthCheck décrit un composant vérifié par la sonde de disponibilité
+type healthCheck struct {
+	name     string
+	critical bool
+	check    HealthCheckFunc
+}
+
+// HealthHandler gère les sondes de vivacité (/healthz) et de disponibilité (/readyz)
+type HealthHandler struct {
+	startedAt time.Time
+	timeout   time.Duration
+	mu        sync.RWMutex
+	checks    []healthCheck
+}
+
+// NewHealthHandler crée une nouvelle instance de HealthHandler avec les vérifications par défaut
+// (base de données, migrations en attente) ; le stockage des fichiers est ajouté selon son backend (RegisterCheck)
+func NewHealthHandler() *HealthHandler {
+	h := &HealthHandler{
+		startedAt: time.Now(),
+		timeout:   3 * time.Second,
+	}
+	h.RegisterCheck("database", true, checkDatabase)
+	h.RegisterCheck("migrations", true, checkMigrations)
+	return h
+}
+
+// RegisterCheck ajoute un composant à la sonde de disponibilité
+// Un composant non critique en échec rend le statut "degraded" sans retirer l'instance du trafic
+func (h *HealthHandler) RegisterCheck(name string, critical bool, check HealthCheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, healthCheck{name: name, critical: critical, check: check})
+}
+
+// Liveness indique si le processus répond (sans vérifier les dépendances)
+// @Summary Sonde de vivacité
+// @Description Indique que le processus est démarré et répond (liveness probe Kubernetes)
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthStatusDTO
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.HealthStatusDTO{
+		Status:    "ok",
+		Uptime:    time.Since(h.startedAt).Round(time.Second).String(),
+		CheckedAt: time.Now(),
+	})
+}
+
+// Readiness vérifie toutes les dépendances et retourne l'état de chacune
+// @Summary Sonde de disponibilité
+// @Description Vérifie la base de données, les migrations, le stockage et la file de tâches (readiness probe Kubernetes). Route publique : chaque composant est ok ou failed, la cause d'un échec n'est que journalisée
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthStatusDTO
+// @Failure 503 {object} dto.HealthStatusDTO
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	h.mu.RLock()
+	checks := make([]healthCheck, len(h.checks))
+	copy(checks, h.checks)
+	h.mu.RUnlock()
+
+	// Exécuter les vérifications en parallèle pour borner la durée totale au timeout
+	components := make([]dto.HealthComponentDTO, len(checks))
+	var wg sync.WaitGroup
+	for i, hc := range checks {
+		wg.Add(1)
+		go func(i int, hc healthCheck) {
+			defer wg.Done()
+			components[i] = runHealthCheck(ctx, hc)
+		}(i, hc)
+	}
+	wg.Wait()
+
+	status := "ok"
+	httpStatus := http.StatusOK
+	for _, component := range components {
+		if component.Status == "ok" {
+			continue
+		}
+		if component.Critical {
+			status = "unavailable"
+			httpStatus = http.StatusServiceUnavailable
+			break
+		}
+		status = "degraded"
+	}
+
+	c.JSON(httpStatus, dto.HealthStatusDTO{
+		Status:     status,
+		Uptime:     time.Since(h.startedAt).Round(time.Second).String(),
+		CheckedAt:  time.Now(),
+		Components: components,
+	})
+}
+
+// runHealthCheck exécute une vérification en respectant le délai du contexte ; la cause d'un échec est journalisée
+func runHealthCheck(ctx context.Context, hc healthCheck) dto.HealthComponentDTO {
+	start := time.Now()
+	type result struct {
+		details any
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		details, err := hc.check(ctx)
+		done <- result{details: details, err: err}
+	}()
+
+	component := dto.HealthComponentDTO{Name: hc.name, Critical: hc.critical, Status: "ok"}
+	var details any
+	var err error
+	select {
+	case r := <-done:
+		details, err = r.details, r.err
+	case <-ctx.Done():
+		err = errors.New("délai de vérification dépassé")
+	}
+	if err != nil {
+		component.Status = "failed"
+		logger.FromContext(ctx).Error("readiness_check_failed",
+			slog.String("check", hc.name),
+			slog.Bool("critical", hc.critical),
+			slog.String("error", err.Error()),
+			slog.Any("details", details),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()))
+	}
+	return component
+}
+
+// checkDatabase vérifie la connectivité à la base de données
+func checkDatabase(ctx context.Context) (any, error) {
+	return nil, database.HealthCheck(ctx)
+}
+
+// checkMigrations vérifie qu'aucune table attendue ne manque en base
+func checkMigrations(ctx context.Context) (any, error) {
+	pending, err := database.PendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		return gin.H{"pending_tables": pending}, fmt.Errorf("%d table(s) non migrée(s): %s", len(pending), strings.Join(pending, ", "))
+	}
+	return nil, nil
+}
+
+// CheckUploadStorage vérifie que le dossier d'upload est accessible en écriture (stockage local des fichiers)
+func CheckUploadStorage(ctx context.Context) (any, error) {
+	if config.AppConfig == nil {
+		return nil, errors.New("configuration non chargée")
+	}
+	dir := config.AppConfig.UploadDir
+	f, err := os.CreateTemp(dir, ".readyz-*")
+	if err != nil {
+		return nil, fmt.Errorf("dossier d'upload %s non accessible en écriture: %w", dir, err)
+	}
+	name := f.Name()
+	f.Close()
+	_ = os.Remove(name)
+	return gin.H{"backend": "local", "path": dir}, nil
+}
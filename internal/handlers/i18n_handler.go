@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/i18n"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// I18nHandler expose les libellés localisés des énumérations de l'API
+type I18nHandler struct{}
+
+// NewI18nHandler crée une nouvelle instance de I18nHandler
+func NewI18nHandler() *I18nHandler {
+	return &I18nHandler{}
+}
+
+// GetEnums récupère les libellés des énumérations dans la langue de l'utilisateur
+// @Summary Libellés des énumérations
+// @Description Retourne, par énumération (ticket_status, priority, ticket_source, skill_level, sla_status...), le libellé de chaque valeur dans la langue de l'utilisateur (préférence, sinon en-tête Accept-Language, français par défaut)
+// @Tags i18n
+// @Security BearerAuth
+// @Produce json
+// @Param Accept-Language header string false "Langue souhaitée (fr, en)"
+// @Success 200 {object} map[string]map[string]string
+// @Failure 401 {object} utils.Response
+// @Router /i18n/enums [get]
+func (h *I18nHandler) GetEnums(c *gin.Context) {
+	utils.SuccessResponse(c, i18n.Enums(utils.RequestLanguage(c)), "Libellés récupérés avec succès")
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/spreadsheet"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ImportHandler gère les imports de données depuis d'autres outils (GLPI, Zendesk, Freshdesk, fichiers)
+type ImportHandler struct {
+	importService services.ImportService
+}
+
+// NewImportHandler crée une nouvelle instance de ImportHandler
+func NewImportHandler(importService services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// importErrorResponse traduit une erreur du service en réponse HTTP
+func importErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasSuffix(err.Error(), "déjà en cours"):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "import GLPI non configuré"):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// StartGLPI lance un import depuis GLPI
+// @Summary Lancer un import GLPI
+// @Description Importe utilisateurs, catégories, matériels et tickets (avec suivis et documents) depuis l'API REST de GLPI, par lots traités en arrière-plan. Les éléments déjà importés sont ignorés (table de correspondance), un import interrompu peut être repris (nécessite imports.manage)
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.StartGLPIImportRequest true "Options de l'import"
+// @Success 201 {object} dto.ImportRunDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /imports/glpi [post]
+func (h *ImportHandler) StartGLPI(c *gin.Context) {
+	if !utils.RequirePermission(c, "imports.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: imports.manage")
+		return
+	}
+
+	var req dto.StartGLPIImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	run, err := h.importService.StartGLPI(req, userID)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, run, "Import GLPI lancé avec succès")
+}
+
+// StartHelpdesk lance un import depuis l'API Zendesk ou Freshdesk
+// @Summary Lancer un import Zendesk ou Freshdesk
+// @Description Importe contacts, articles de base de connaissances et tickets (avec commentaires) depuis l'API de l'outil, par lots traités en arrière-plan. Les correspondances (mapping) convertissent types ou étiquettes en catégories, ainsi que priorités et statuts ; le jeton d'API n'est jamais retourné (nécessite imports.manage)
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.StartHelpdeskImportRequest true "Connexion et options de l'import"
+// @Success 201 {object} dto.ImportRunDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /imports/helpdesk [post]
+func (h *ImportHandler) StartHelpdesk(c *gin.Context) {
+	if !utils.RequirePermission(c, "imports.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: imports.manage")
+		return
+	}
+
+	var req dto.StartHelpdeskImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	run, err := h.importService.StartHelpdesk(req, userID)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, run, "Import lancé avec succès")
+}
+
+// StartHelpdeskFiles lance un import depuis des fichiers CSV ou XLSX
+// @Summary Lancer un import depuis des fichiers
+// @Description Importe contacts (colonne email obligatoire), articles (title, body) et tickets (subject) depuis des fichiers CSV ou XLSX, un par étape ; les exports de Zendesk et Freshdesk sont reconnus. options reprend les champs de HelpdeskImportOptions au format JSON (nécessite imports.manage)
+// @Tags imports
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param options formData string true "Options de l'import (JSON : filiale_id, role_id, mapping, ...)"
+// @Param contacts formData file false "Fichier des contacts"
+// @Param articles formData file false "Fichier des articles"
+// @Param tickets formData file false "Fichier des tickets"
+// @Success 201 {object} dto.ImportRunDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /imports/helpdesk/files [post]
+func (h *ImportHandler) StartHelpdeskFiles(c *gin.Context) {
+	if !utils.RequirePermission(c, "imports.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: imports.manage")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	var opts dto.HelpdeskImportOptions
+	if err := json.Unmarshal([]byte(c.PostForm("options")), &opts); err != nil {
+		utils.BadRequestResponse(c, "Options de l'import invalides (objet JSON attendu)")
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&opts); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var files []dto.HelpdeskImportFile
+	for _, stage := range []string{dto.ImportStageContacts, dto.ImportStageArticles, dto.ImportStageTickets} {
+		file, err := c.FormFile(stage)
+		if err != nil {
+			continue
+		}
+		if file.Size > config.AppConfig.MaxUploadSize {
+			utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Fichier %s trop volumineux. Taille maximale: %d bytes", file.Filename, config.AppConfig.MaxUploadSize), nil)
+			return
+		}
+		content, err := file.Open()
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(content, config.AppConfig.MaxUploadSize))
+		content.Close()
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+			return
+		}
+		rows, err := spreadsheet.ReadRows(data, file.Filename)
+		if err != nil {
+			if errors.Is(err, spreadsheet.ErrUnsupportedFormat) || errors.Is(err, spreadsheet.ErrInvalidFile) {
+				utils.BadRequestResponse(c, fmt.Sprintf("%s: %s", file.Filename, err.Error()))
+				return
+			}
+			utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+			return
+		}
+		files = append(files, dto.HelpdeskImportFile{Stage: stage, FileName: file.Filename, Rows: rows})
+	}
+
+	run, err := h.importService.StartHelpdeskFiles(opts, files, userID)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, run, "Import lancé avec succès")
+}
+
+// GetAll récupère les imports
+// @Summary Liste des imports
+// @Description Liste paginée des imports, plus récents d'abord (nécessite imports.manage)
+// @Tags imports
+// @Security BearerAuth
+// @Produce json
+// @Param source query string false "Filtrer par source (glpi, zendesk, freshdesk, csv)"
+// @Param page query int false "Numéro de page" default(1)
+// @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Success 200 {object} dto.ImportRunListResponse
+// @Router /imports [get]
+func (h *ImportHandler) GetAll(c *gin.Context) {
+	if !utils.RequirePermission(c, "imports.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: imports.manage")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	runs, err := h.importService.GetAll(c.Query("source"), page, limit)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, runs, "Imports récupérés avec succès")
+}
+
+// GetByID récupère un import (progression, compteurs par étape et dernières erreurs)
+// @Summary Détail d'un import
+// @Description Retourne l'état d'un import : étape et position courantes, compteurs par étape et erreurs par élément (nécessite imports.manage)
+// @Tags imports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'import"
+// @Success 200 {object} dto.ImportRunDTO
+// @Failure 404 {object} utils.Response
+// @Router /imports/{id} [get]
+func (h *ImportHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "imports.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: imports.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	run, err := h.importService.GetByID(uint(id))
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, run, "Import récupéré avec succès")
+}
+
+// Resume reprend un import en échec ou arrêté
+// @Summary Reprendre un import
+// @Description Reprend un import en échec ou arrêté à partir du dernier lot enregistré (nécessite imports.manage)
+// @Tags imports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'import"
+// @Success 200 {object} dto.ImportRunDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /imports/{id}/resume [post]
+func (h *ImportHandler) Resume(c *gin.Context) {
+	if !utils.RequirePermission(c, "imports.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: imports.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	run, err := h.importService.Resume(uint(id))
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, run, "Import repris avec succès")
+}
+
+// Cancel arrête un import en cours
+// @Summary Arrêter un import
+// @Description Arrête un import en attente ou en cours après le lot en cours de traitement (nécessite imports.manage)
+// @Tags imports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'import"
+// @Success 200 {object} dto.ImportRunDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /imports/{id}/cancel [post]
+func (h *ImportHandler) Cancel(c *gin.Context) {
+	if !utils.RequirePermission(c, "imports.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: imports.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	run, err := h.importService.Cancel(uint(id))
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, run, "Import arrêté avec succès")
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -37,7 +36,7 @@ func NewIncidentHandler(incidentService services.IncidentService) *IncidentHandl
 func (h *IncidentHandler) Create(c *gin.Context) {
 	var req dto.CreateIncidentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -49,7 +48,7 @@ func (h *IncidentHandler) Create(c *gin.Context) {
 
 	incident, err := h.incidentService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -131,7 +130,7 @@ func (h *IncidentHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateIncidentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -143,7 +142,7 @@ func (h *IncidentHandler) Update(c *gin.Context) {
 
 	incident, err := h.incidentService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -173,7 +172,7 @@ func (h *IncidentHandler) Qualify(c *gin.Context) {
 
 	var req dto.QualifyIncidentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -185,7 +184,7 @@ func (h *IncidentHandler) Qualify(c *gin.Context) {
 
 	incident, err := h.incidentService.Qualify(uint(id), req, qualifiedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -220,7 +219,7 @@ func (h *IncidentHandler) Resolve(c *gin.Context) {
 
 	incident, err := h.incidentService.Resolve(uint(id), resolvedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -317,7 +316,7 @@ func (h *IncidentHandler) LinkAsset(c *gin.Context) {
 		AssetID uint `json:"asset_id" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -329,7 +328,7 @@ func (h *IncidentHandler) LinkAsset(c *gin.Context) {
 
 	err = h.incidentService.LinkAsset(uint(incidentID), req.AssetID, linkedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -364,7 +363,7 @@ func (h *IncidentHandler) UnlinkAsset(c *gin.Context) {
 
 	err = h.incidentService.UnlinkAsset(uint(incidentID), uint(assetID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -416,7 +415,7 @@ func (h *IncidentHandler) GetByImpact(c *gin.Context) {
 
 	incidents, err := h.incidentService.GetByImpact(queryScope, impact)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -441,7 +440,7 @@ func (h *IncidentHandler) GetByUrgency(c *gin.Context) {
 
 	incidents, err := h.incidentService.GetByUrgency(queryScope, urgency)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// jiraWebhookMaxSize taille maximale d'un webhook Jira entrant
+const jiraWebhookMaxSize = 1 << 20
+
+// JiraHandler gère la synchronisation des tickets avec Jira
+type JiraHandler struct {
+	jiraService services.JiraSyncService
+}
+
+// NewJiraHandler crée une nouvelle instance de JiraHandler
+func NewJiraHandler(jiraService services.JiraSyncService) *JiraHandler {
+	return &JiraHandler{
+		jiraService: jiraService,
+	}
+}
+
+// jiraErrorResponse traduit une erreur du service en réponse HTTP
+func jiraErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case err.Error() == "ce ticket est déjà lié à un ticket Jira":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case err.Error() == "intégration Jira non configurée":
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// Link crée le ticket Jira lié à un ticket
+// @Summary Lier un ticket à Jira
+// @Description Crée immédiatement un ticket Jira pour le ticket et enregistre le lien. Le statut et les commentaires publics sont ensuite synchronisés dans les deux sens
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 201 {object} dto.JiraLinkDTO
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /tickets/{id}/jira [post]
+func (h *JiraHandler) Link(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	link, err := h.jiraService.LinkTicket(uint(id), userID)
+	if err != nil {
+		jiraErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, link, "Ticket Jira créé avec succès")
+}
+
+// Unlink supprime le lien entre un ticket et son ticket Jira
+// @Summary Délier un ticket de Jira
+// @Description Arrête la synchronisation du ticket (le ticket Jira n'est pas supprimé)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/jira [delete]
+func (h *JiraHandler) Unlink(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.jiraService.UnlinkTicket(uint(id)); err != nil {
+		jiraErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Lien Jira supprimé avec succès")
+}
+
+// Webhook reçoit les événements Jira (changement de statut, commentaire)
+// @Summary Webhook Jira entrant
+// @Description Point d'entrée des webhooks Jira (jira:issue_updated, comment_created). Authentifié par le secret partagé (paramètre secret) ou une signature X-Hub-Signature (sha256=HMAC du corps)
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param secret query string false "Secret partagé"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /integrations/jira/webhook [post]
+func (h *JiraHandler) Webhook(c *gin.Context) {
+	if !h.jiraService.Enabled() {
+		utils.NotFoundResponse(c, "Intégration Jira non configurée")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, jiraWebhookMaxSize))
+	if err != nil {
+		utils.BadRequestResponse(c, "Contenu du webhook illisible")
+		return
+	}
+	if !h.jiraService.VerifyWebhook(c.Query("secret"), c.GetHeader("X-Hub-Signature"), body) {
+		utils.UnauthorizedResponse(c, "Signature du webhook invalide")
+		return
+	}
+
+	if err := h.jiraService.HandleWebhook(body); err != nil {
+		jiraErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Webhook traité")
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// JobHandler gère l'administration de la file de tâches en arrière-plan
+type JobHandler struct {
+	queue *jobs.Queue
+}
+
+// NewJobHandler crée une nouvelle instance de JobHandler
+func NewJobHandler(queue *jobs.Queue) *JobHandler {
+	return &JobHandler{
+		queue: queue,
+	}
+}
+
+// GetStats retourne les compteurs de la file de tâches
+// @Summary Statistiques de la file de tâches
+// @Description Compteurs de la file (en attente, actives, réessais, dead-letter) (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} jobs.Stats
+// @Failure 403 {object} utils.Response
+// @Router /admin/jobs/stats [get]
+func (h *JobHandler) GetStats(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	stats, err := h.queue.Stats()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la récupération des statistiques de la file: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, stats, "Statistiques de la file récupérées avec succès")
+}
+
+// GetFailed liste les tâches en échec
+// @Summary Tâches en échec
+// @Description Liste les tâches en réessai et en dead-letter, plus récentes d'abord (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Nombre maximum de tâches" default(100)
+// @Success 200 {array} jobs.FailedJob
+// @Failure 403 {object} utils.Response
+// @Router /admin/jobs/failed [get]
+func (h *JobHandler) GetFailed(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	failed, err := h.queue.ListFailed(limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la récupération des tâches en échec: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, failed, "Tâches en échec récupérées avec succès")
+}
+
+// Retry relance une tâche en échec
+// @Summary Relancer une tâche
+// @Description Relance immédiatement une tâche en réessai ou en dead-letter (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID de la tâche"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/jobs/failed/{id}/retry [post]
+func (h *JobHandler) Retry(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	if err := h.queue.Retry(c.Param("id")); err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			utils.NotFoundResponse(c, "Tâche introuvable")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Erreur lors de la relance de la tâche: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Tâche relancée")
+}
+
+// Delete supprime une tâche en échec
+// @Summary Supprimer une tâche en échec
+// @Description Supprime définitivement une tâche en réessai ou en dead-letter (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID de la tâche"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/jobs/failed/{id} [delete]
+func (h *JobHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	if err := h.queue.Delete(c.Param("id")); err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			utils.NotFoundResponse(c, "Tâche introuvable")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Erreur lors de la suppression de la tâche: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Tâche supprimée")
+}
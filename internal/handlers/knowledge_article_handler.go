@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
@@ -39,7 +40,7 @@ func NewKnowledgeArticleHandler(knowledgeArticleService services.KnowledgeArticl
 func (h *KnowledgeArticleHandler) Create(c *gin.Context) {
 	var req dto.CreateKnowledgeArticleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -51,7 +52,7 @@ func (h *KnowledgeArticleHandler) Create(c *gin.Context) {
 
 	article, err := h.knowledgeArticleService.Create(req, authorID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -111,7 +112,7 @@ func (h *KnowledgeArticleHandler) GetPublished(c *gin.Context) {
 
 // Search recherche des articles
 // @Summary Rechercher des articles
-// @Description Recherche des articles dans la base de connaissances (route publique)
+// @Description Recherche plein texte dans la base de connaissances (route publique) : résultats classés par pertinence (titre prioritaire, préfixes acceptés), titre et extrait surlignés entre <mark></mark>. Sans résultat, la requête corrigée des fautes de frappe est utilisée ; les requêtes sans mot d'au moins trois lettres sont traitées par une recherche simple. Sans authentification, seuls les articles publiés communs à toutes les filiales sont retournés
 // @Tags knowledge-base
 // @Accept json
 // @Produce json
@@ -139,6 +140,41 @@ func (h *KnowledgeArticleHandler) Search(c *gin.Context) {
 	utils.SuccessResponse(c, results, "Résultats de recherche récupérés avec succès")
 }
 
+// Suggest propose des articles pendant la saisie d'une recherche
+// @Summary Suggestions de recherche
+// @Description Retourne les titres d'articles correspondant à la saisie en cours (surlignés) et, si un mot est inconnu de la base de connaissances, la requête corrigée des fautes de frappe (did_you_mean) (route publique)
+// @Tags knowledge-base
+// @Produce json
+// @Param q query string true "Saisie en cours"
+// @Param limit query int false "Nombre de suggestions (5 par défaut, 20 au maximum)"
+// @Success 200 {object} dto.KnowledgeSearchSuggestionsDTO
+// @Failure 400 {object} utils.Response
+// @Router /knowledge-base/articles/suggest [get]
+func (h *KnowledgeArticleHandler) Suggest(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		utils.BadRequestResponse(c, "Paramètre de recherche manquant")
+		return
+	}
+	limit := 5
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			utils.BadRequestResponse(c, "Paramètre limit invalide")
+			return
+		}
+		limit = min(parsed, 20)
+	}
+
+	suggestions, err := h.knowledgeArticleService.Suggest(utils.GetScopeFromContext(c), query, limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la recherche")
+		return
+	}
+
+	utils.SuccessResponse(c, suggestions, "Suggestions récupérées avec succès")
+}
+
 // Delete supprime un article
 // @Summary Supprimer un article
 // @Description Supprime un article de la base de connaissances
@@ -212,7 +248,7 @@ func (h *KnowledgeArticleHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateKnowledgeArticleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -224,7 +260,7 @@ func (h *KnowledgeArticleHandler) Update(c *gin.Context) {
 
 	article, err := h.knowledgeArticleService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -265,7 +301,7 @@ func (h *KnowledgeArticleHandler) Publish(c *gin.Context) {
 	// Parser manuellement le JSON pour s'assurer que "published" (minuscule) est correctement mappé
 	var rawReq map[string]interface{}
 	if err := json.Unmarshal(bodyBytes, &rawReq); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Format JSON invalide", err.Error())
+		utils.ValidationErrorResponseWithMessage(c, "Format JSON invalide", err)
 		return
 	}
 	
@@ -292,7 +328,7 @@ func (h *KnowledgeArticleHandler) Publish(c *gin.Context) {
 
 	article, err := h.knowledgeArticleService.Publish(uint(id), req.Published, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -322,7 +358,7 @@ func (h *KnowledgeArticleHandler) GetByCategory(c *gin.Context) {
 	
 	articles, err := h.knowledgeArticleService.GetByCategory(queryScope, uint(categoryID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -352,7 +388,7 @@ func (h *KnowledgeArticleHandler) GetByAuthor(c *gin.Context) {
 	
 	articles, err := h.knowledgeArticleService.GetByAuthor(queryScope, uint(authorID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -385,3 +421,315 @@ func (h *KnowledgeArticleHandler) IncrementViewCount(c *gin.Context) {
 
 	utils.SuccessResponse(c, nil, "Compteur de vues incrémenté avec succès")
 }
+
+// parseArticleVersion lit l'identifiant de l'article et le numéro de version de l'URL
+func parseArticleVersion(c *gin.Context) (uint, int, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, 0, false
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		utils.BadRequestResponse(c, "Numéro de version invalide")
+		return 0, 0, false
+	}
+	return uint(id), version, true
+}
+
+// GetRevisions récupère l'historique des versions d'un article
+// @Summary Versions d'un article
+// @Description Récupère les versions d'un article, de la plus récente à la plus ancienne (sans leur contenu). Chaque modification du titre, du contenu, du format ou de la catégorie crée une version
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Success 200 {array} dto.KnowledgeArticleRevisionDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/revisions [get]
+func (h *KnowledgeArticleHandler) GetRevisions(c *gin.Context) {
+	if !utils.RequireAnyPermission(c, "knowledge.view_all", "knowledge.create", "knowledge.update", "knowledge.publish") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	revisions, err := h.knowledgeArticleService.GetRevisions(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, revisions, "Versions de l'article récupérées avec succès")
+}
+
+// GetRevision récupère une version d'un article
+// @Summary Version d'un article
+// @Description Récupère une version d'un article avec son contenu
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param version path int true "Numéro de version"
+// @Success 200 {object} dto.KnowledgeArticleRevisionDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/revisions/{version} [get]
+func (h *KnowledgeArticleHandler) GetRevision(c *gin.Context) {
+	if !utils.RequireAnyPermission(c, "knowledge.view_all", "knowledge.create", "knowledge.update", "knowledge.publish") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.update")
+		return
+	}
+
+	id, version, ok := parseArticleVersion(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.knowledgeArticleService.GetRevision(id, version)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, revision, "Version de l'article récupérée avec succès")
+}
+
+// Diff compare deux versions d'un article
+// @Summary Comparer deux versions d'un article
+// @Description Compare ligne à ligne le contenu de deux versions et liste les champs modifiés. Par défaut, la version courante est comparée à la précédente
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param from query int false "Version de départ (défaut: version précédant to)"
+// @Param to query int false "Version d'arrivée (défaut: version courante)"
+// @Success 200 {object} dto.KnowledgeArticleDiffDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/diff [get]
+func (h *KnowledgeArticleHandler) Diff(c *gin.Context) {
+	if !utils.RequireAnyPermission(c, "knowledge.view_all", "knowledge.create", "knowledge.update", "knowledge.publish") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	versions := map[string]int{}
+	for _, name := range []string{"from", "to"} {
+		if v := c.Query(name); v != "" {
+			version, err := strconv.Atoi(v)
+			if err != nil || version < 1 {
+				utils.BadRequestResponse(c, "Numéro de version invalide")
+				return
+			}
+			versions[name] = version
+		}
+	}
+
+	diff, err := h.knowledgeArticleService.Diff(uint(id), versions["from"], versions["to"])
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, diff, "Comparaison des versions effectuée")
+}
+
+// Restore rétablit une version antérieure d'un article
+// @Summary Restaurer une version d'un article
+// @Description Rétablit le titre, le contenu, le format et la catégorie d'une version antérieure ; la restauration crée une nouvelle version (nécessite knowledge.update)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param version path int true "Numéro de la version à restaurer"
+// @Success 200 {object} dto.KnowledgeArticleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/revisions/{version}/restore [post]
+func (h *KnowledgeArticleHandler) Restore(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.update")
+		return
+	}
+
+	id, version, ok := parseArticleVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	article, err := h.knowledgeArticleService.Restore(id, version, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, article, "Version restaurée avec succès")
+}
+
+// SubmitForReview soumet un article en relecture
+// @Summary Soumettre un article en relecture
+// @Description Passe l'article au statut in_review et notifie le relecteur désigné (échéance par défaut : 7 jours) (nécessite knowledge.create ou knowledge.update)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param request body dto.SubmitKnowledgeReviewRequest true "Relecteur et échéance"
+// @Success 200 {object} dto.KnowledgeArticleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/submit-review [post]
+func (h *KnowledgeArticleHandler) SubmitForReview(c *gin.Context) {
+	if !utils.RequireAnyPermission(c, "knowledge.create", "knowledge.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.SubmitKnowledgeReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	article, err := h.knowledgeArticleService.SubmitForReview(uint(id), req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, article, "Article soumis en relecture")
+}
+
+// Review enregistre la décision de relecture d'un article
+// @Summary Relire un article
+// @Description Approuve (publication, prochaine révision périodique dans un an par défaut) ou renvoie en brouillon (commentaire obligatoire) un article en relecture. Réservé au relecteur désigné ou aux titulaires de knowledge.publish ; l'auteur est notifié
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param request body dto.KnowledgeReviewDecisionRequest true "Décision"
+// @Success 200 {object} dto.KnowledgeArticleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/review [post]
+func (h *KnowledgeArticleHandler) Review(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.KnowledgeReviewDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	article, err := h.knowledgeArticleService.Review(uint(id), req, userID, utils.RequirePermission(c, "knowledge.publish"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, article, "Décision de relecture enregistrée")
+}
+
+// Archive archive un article
+// @Summary Archiver un article
+// @Description Retire un article de la publication sans le supprimer ; un article archivé peut être repassé en brouillon (nécessite knowledge.publish)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Success 200 {object} dto.KnowledgeArticleDTO
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/archive [post]
+func (h *KnowledgeArticleHandler) Archive(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.publish") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.publish")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	article, err := h.knowledgeArticleService.Archive(uint(id), userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, article, "Article archivé avec succès")
+}
+
+// GetReviewQueue récupère les articles à relire et à réviser
+// @Summary Articles à relire
+// @Description Récupère les articles en relecture et les articles publiés dont la révision périodique est échue : tous pour les titulaires de knowledge.publish, sinon ceux dont l'utilisateur est relecteur (ou auteur, à défaut de relecteur)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.KnowledgeReviewQueueDTO
+// @Failure 403 {object} utils.Response
+// @Router /knowledge-base/articles/reviews [get]
+func (h *KnowledgeArticleHandler) GetReviewQueue(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	queue, err := h.knowledgeArticleService.GetReviewQueue(userID, utils.RequirePermission(c, "knowledge.publish"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, queue, "Articles à relire récupérés avec succès")
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// KnowledgeAttachmentHandler gère les pièces jointes des articles de la base de connaissances et les quotas de stockage
+type KnowledgeAttachmentHandler struct {
+	knowledgeAttachmentService services.KnowledgeAttachmentService
+}
+
+// NewKnowledgeAttachmentHandler crée une nouvelle instance de KnowledgeAttachmentHandler
+func NewKnowledgeAttachmentHandler(knowledgeAttachmentService services.KnowledgeAttachmentService) *KnowledgeAttachmentHandler {
+	return &KnowledgeAttachmentHandler{
+		knowledgeAttachmentService: knowledgeAttachmentService,
+	}
+}
+
+// parseArticleAttachment lit l'identifiant de l'article et celui de la pièce jointe de l'URL
+func parseArticleAttachment(c *gin.Context) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de pièce jointe invalide")
+		return 0, 0, false
+	}
+	return uint(id), uint(attachmentID), true
+}
+
+// Upload joint un fichier à un article
+// @Summary Joindre un fichier à un article
+// @Description Vérifie le fichier (extension autorisée, contenu, taille, antivirus) puis le joint à l'article. inline=true le destine au contenu (images PNG, JPEG, GIF, WebP) : le snippet retourné s'insère dans le contenu selon son format. Un contenu déjà stocké n'est pas dupliqué (deduplicated) ; un nouveau fichier doit tenir dans le quota de stockage de la filiale de l'article (nécessite knowledge.create ou knowledge.update)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param file formData file true "Fichier"
+// @Param inline formData bool false "Image intégrée au contenu (défaut: false)"
+// @Success 201 {object} dto.KnowledgeArticleAttachmentDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/attachments [post]
+func (h *KnowledgeAttachmentHandler) Upload(c *gin.Context) {
+	if !utils.RequireAnyPermission(c, "knowledge.create", "knowledge.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.create ou knowledge.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Fichier manquant", err.Error())
+		return
+	}
+	if file.Size > config.AppConfig.MaxUploadSize {
+		utils.ServiceErrorResponse(c, utils.ErrAttachmentTooLarge.WithDetails(map[string]int64{"max_size": config.AppConfig.MaxUploadSize}))
+		return
+	}
+	inline := false
+	if v := c.PostForm("inline"); v != "" {
+		if inline, err = strconv.ParseBool(v); err != nil {
+			utils.BadRequestResponse(c, "Paramètre inline invalide")
+			return
+		}
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+	defer content.Close()
+
+	attachment, err := h.knowledgeAttachmentService.Upload(utils.GetScopeFromContext(c), uint(id), file.Filename, content, inline, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, attachment, "Pièce jointe ajoutée avec succès")
+}
+
+// GetByArticle liste les pièces jointes d'un article
+// @Summary Pièces jointes d'un article
+// @Description Images intégrées au contenu et fichiers joints à un article visible, avec leur lien de téléchargement
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Success 200 {array} dto.KnowledgeArticleAttachmentDTO
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/attachments [get]
+func (h *KnowledgeAttachmentHandler) GetByArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	attachments, err := h.knowledgeAttachmentService.GetByArticle(utils.GetScopeFromContext(c), uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, attachments, "Pièces jointes récupérées avec succès")
+}
+
+// Download sert une pièce jointe d'un article
+// @Summary Télécharger une pièce jointe d'article
+// @Description Sert une image intégrée au contenu (lien présent dans content et content_html) ou un fichier joint à un article visible
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param id path int true "ID de l'article"
+// @Param attachmentId path int true "ID de la pièce jointe"
+// @Success 200 {file} file "Fichier"
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/attachments/{attachmentId} [get]
+func (h *KnowledgeAttachmentHandler) Download(c *gin.Context) {
+	id, attachmentID, ok := parseArticleAttachment(c)
+	if !ok {
+		return
+	}
+
+	object, attachment, err := h.knowledgeAttachmentService.Open(utils.GetScopeFromContext(c), id, attachmentID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	serveStorageObject(c, object, attachment.FileName)
+}
+
+// Delete supprime une pièce jointe d'un article
+// @Summary Supprimer une pièce jointe d'article
+// @Description Supprime une pièce jointe qui n'est plus citée dans le contenu de l'article. Le fichier stocké n'est supprimé que s'il n'est partagé avec aucune autre pièce jointe (nécessite knowledge.update)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param attachmentId path int true "ID de la pièce jointe"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/attachments/{attachmentId} [delete]
+func (h *KnowledgeAttachmentHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.update")
+		return
+	}
+
+	id, attachmentID, ok := parseArticleAttachment(c)
+	if !ok {
+		return
+	}
+
+	if err := h.knowledgeAttachmentService.Delete(utils.GetScopeFromContext(c), id, attachmentID); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Pièce jointe supprimée avec succès")
+}
+
+// GetStorageUsage récupère l'espace occupé par les pièces jointes des articles
+// @Summary Stockage de la base de connaissances
+// @Description Espace occupé par les pièces jointes des articles de chaque filiale (un fichier partagé n'est compté qu'une fois par filiale), quota applicable et part utilisée. Les articles globaux et les filiales sans quota propre relèvent du quota par défaut KB_STORAGE_QUOTA (nécessite knowledge.manage_storage)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.KnowledgeStorageUsageDTO
+// @Failure 403 {object} utils.Response
+// @Router /knowledge-base/storage [get]
+func (h *KnowledgeAttachmentHandler) GetStorageUsage(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_storage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_storage")
+		return
+	}
+
+	usage, err := h.knowledgeAttachmentService.GetStorageUsage()
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, usage, "Stockage récupéré avec succès")
+}
+
+// SetQuota définit le quota de stockage d'une filiale
+// @Summary Définir le quota de stockage d'une filiale
+// @Description Fixe le volume maximal des pièces jointes des articles de la filiale, en octets (0 = illimité). Les fichiers déjà stockés sont conservés si le quota est dépassé ; seuls les nouveaux fichiers sont refusés (nécessite knowledge.manage_storage)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param filialeId path int true "ID de la filiale"
+// @Param request body dto.SetKnowledgeStorageQuotaRequest true "Quota"
+// @Success 200 {object} dto.KnowledgeStorageUsageDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/storage/quotas/{filialeId} [put]
+func (h *KnowledgeAttachmentHandler) SetQuota(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_storage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_storage")
+		return
+	}
+
+	filialeID, err := strconv.ParseUint(c.Param("filialeId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+
+	var req dto.SetKnowledgeStorageQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	usage, err := h.knowledgeAttachmentService.SetQuota(uint(filialeID), *req.MaxBytes, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, usage, "Quota de stockage enregistré")
+}
+
+// ResetQuota rétablit le quota de stockage par défaut d'une filiale
+// @Summary Rétablir le quota de stockage par défaut
+// @Description Supprime le quota propre à la filiale : le quota par défaut KB_STORAGE_QUOTA s'applique de nouveau (nécessite knowledge.manage_storage)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param filialeId path int true "ID de la filiale"
+// @Success 200 {object} dto.KnowledgeStorageUsageDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/storage/quotas/{filialeId} [delete]
+func (h *KnowledgeAttachmentHandler) ResetQuota(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_storage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_storage")
+		return
+	}
+
+	filialeID, err := strconv.ParseUint(c.Param("filialeId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+
+	usage, err := h.knowledgeAttachmentService.ResetQuota(uint(filialeID))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, usage, "Quota de stockage par défaut rétabli")
+}
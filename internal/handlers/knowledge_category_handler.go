@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -82,7 +81,7 @@ func (h *KnowledgeCategoryHandler) GetByID(c *gin.Context) {
 func (h *KnowledgeCategoryHandler) Create(c *gin.Context) {
 	var req dto.CreateKnowledgeCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -94,7 +93,7 @@ func (h *KnowledgeCategoryHandler) Create(c *gin.Context) {
 
 	category, err := h.knowledgeCategoryService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -123,7 +122,7 @@ func (h *KnowledgeCategoryHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateKnowledgeCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -135,7 +134,7 @@ func (h *KnowledgeCategoryHandler) Update(c *gin.Context) {
 
 	category, err := h.knowledgeCategoryService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// knowledgeDeflectionDefaultPeriod période par défaut des statistiques de déviation
+const knowledgeDeflectionDefaultPeriod = 30 * 24 * time.Hour
+
+// KnowledgeDeflectionHandler gère les suggestions d'articles pendant la saisie d'un ticket et le suivi de la déviation
+type KnowledgeDeflectionHandler struct {
+	knowledgeDeflectionService services.KnowledgeDeflectionService
+}
+
+// NewKnowledgeDeflectionHandler crée une nouvelle instance de KnowledgeDeflectionHandler
+func NewKnowledgeDeflectionHandler(knowledgeDeflectionService services.KnowledgeDeflectionService) *KnowledgeDeflectionHandler {
+	return &KnowledgeDeflectionHandler{
+		knowledgeDeflectionService: knowledgeDeflectionService,
+	}
+}
+
+// Suggest suggère des articles pendant la saisie d'un ticket
+// @Summary Suggérer des articles pour un ticket en cours de saisie
+// @Description Retourne les articles publiés visibles les plus proches du titre et de la description (score de pertinence, titre et extrait surlignés), pour proposer une solution avant la création du ticket. Le premier appel attribue un session_id à renvoyer aux appels suivants de la même saisie et avec les événements (POST /knowledge/deflection-events) ; l'affichage de chaque article est enregistré une fois par session
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param title query string false "Titre du ticket en cours de saisie"
+// @Param description query string false "Description du ticket en cours de saisie"
+// @Param session_id query string false "Session de saisie (retournée par le premier appel)"
+// @Param limit query int false "Nombre d'articles (5 par défaut, 10 au maximum)"
+// @Success 200 {object} dto.KnowledgeTicketSuggestionsDTO
+// @Failure 400 {object} utils.Response
+// @Router /knowledge/suggest [get]
+func (h *KnowledgeDeflectionHandler) Suggest(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	sessionID := c.Query("session_id")
+	if len(sessionID) > 64 {
+		utils.BadRequestResponse(c, "Paramètre session_id invalide")
+		return
+	}
+	limit := 5
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			utils.BadRequestResponse(c, "Paramètre limit invalide")
+			return
+		}
+		limit = min(parsed, 10)
+	}
+
+	suggestions, err := h.knowledgeDeflectionService.Suggest(utils.GetScopeFromContext(c), userID, c.Query("title"), c.Query("description"), sessionID, limit)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, suggestions, "Suggestions récupérées avec succès")
+}
+
+// RecordEvent enregistre un événement de la saisie d'un ticket
+// @Summary Enregistrer un événement de déviation
+// @Description Enregistre la consultation d'un article suggéré (clicked, article_id requis), l'abandon de la saisie du ticket (abandoned) ou sa création (ticket_created, ticket_id conseillé). Une saisie abandonnée sans création de ticket compte comme une déviation réussie
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.RecordKnowledgeDeflectionRequest true "Événement"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge/deflection-events [post]
+func (h *KnowledgeDeflectionHandler) RecordEvent(c *gin.Context) {
+	var req dto.RecordKnowledgeDeflectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.knowledgeDeflectionService.Record(utils.GetScopeFromContext(c), userID, req); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, nil, "Événement enregistré")
+}
+
+// GetStats récupère les statistiques de déviation
+// @Summary Statistiques de déviation des tickets
+// @Description Nombre de saisies de ticket ayant reçu des suggestions, consultations, saisies déviées (abandonnées sans création de ticket), taux de déviation et articles les plus efficaces. reports.view_global : toutes les filiales (filtrables par filiale_id) ; reports.view_filiale : sa filiale
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Début (YYYY-MM-DD, défaut: il y a 30 jours)"
+// @Param to query string false "Fin incluse (YYYY-MM-DD, défaut: aujourd'hui)"
+// @Param filiale_id query int false "Filiale (reports.view_global)"
+// @Success 200 {object} dto.KnowledgeDeflectionStatsDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /knowledge/deflection-stats [get]
+func (h *KnowledgeDeflectionHandler) GetStats(c *gin.Context) {
+	if !utils.RequireAnyPermission(c, "reports.view_global", "reports.view_filiale") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: reports.view_global ou reports.view_filiale")
+		return
+	}
+
+	var filter repositories.KnowledgeDeflectionFilter
+	location := utils.RequestLocation(c)
+	_, filter.To = timezone.DayBounds(time.Now().In(location), location)
+	if raw := c.Query("to"); raw != "" {
+		date, err := timezone.ParseDate(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre to invalide (YYYY-MM-DD)")
+			return
+		}
+		_, filter.To = timezone.DayBounds(date, location)
+	}
+	filter.From = filter.To.Add(-knowledgeDeflectionDefaultPeriod)
+	if raw := c.Query("from"); raw != "" {
+		date, err := timezone.ParseDate(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre from invalide (YYYY-MM-DD)")
+			return
+		}
+		filter.From, _ = timezone.DayBounds(date, location)
+	}
+
+	if utils.RequirePermission(c, "reports.view_global") {
+		var ok bool
+		if filter.FilialeID, ok = parseOptionalUintQuery(c, "filiale_id"); !ok {
+			utils.BadRequestResponse(c, "ID de la filiale invalide")
+			return
+		}
+	} else {
+		queryScope := utils.GetScopeFromContext(c)
+		if queryScope == nil || queryScope.FilialeID == nil {
+			utils.ForbiddenResponse(c, "Aucune filiale associée à l'utilisateur")
+			return
+		}
+		filter.FilialeID = queryScope.FilialeID
+	}
+
+	stats, err := h.knowledgeDeflectionService.GetStats(filter)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, stats, "Statistiques de déviation récupérées avec succès")
+}
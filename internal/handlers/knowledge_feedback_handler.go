@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// KnowledgeFeedbackHandler gère les évaluations des articles de la base de connaissances
+type KnowledgeFeedbackHandler struct {
+	knowledgeFeedbackService services.KnowledgeFeedbackService
+}
+
+// NewKnowledgeFeedbackHandler crée une nouvelle instance de KnowledgeFeedbackHandler
+func NewKnowledgeFeedbackHandler(knowledgeFeedbackService services.KnowledgeFeedbackService) *KnowledgeFeedbackHandler {
+	return &KnowledgeFeedbackHandler{
+		knowledgeFeedbackService: knowledgeFeedbackService,
+	}
+}
+
+// canManageKnowledgeFeedback indique si l'utilisateur gère la base de connaissances (détail des évaluations, rapport)
+func canManageKnowledgeFeedback(c *gin.Context) bool {
+	return utils.RequireAnyPermission(c, "knowledge.update", "knowledge.publish")
+}
+
+// Rate évalue un article
+// @Summary Évaluer un article
+// @Description Enregistre l'avis de l'utilisateur sur un article publié (utile / pas utile, commentaire optionnel). Un utilisateur n'a qu'une évaluation par article : une nouvelle évaluation remplace la précédente
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Param request body dto.RateKnowledgeArticleRequest true "Évaluation"
+// @Success 200 {object} dto.KnowledgeArticleRatingDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/feedback [post]
+func (h *KnowledgeFeedbackHandler) Rate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.RateKnowledgeArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	rating, err := h.knowledgeFeedbackService.Rate(utils.GetScopeFromContext(c), uint(id), userID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, rating, "Évaluation enregistrée")
+}
+
+// RemoveRating retire l'évaluation de l'utilisateur
+// @Summary Retirer son évaluation d'un article
+// @Description Supprime l'évaluation de l'utilisateur courant et retourne les évaluations agrégées mises à jour
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Success 200 {object} dto.KnowledgeArticleRatingDTO
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/feedback [delete]
+func (h *KnowledgeFeedbackHandler) RemoveRating(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	rating, err := h.knowledgeFeedbackService.RemoveRating(utils.GetScopeFromContext(c), uint(id), userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, rating, "Évaluation retirée")
+}
+
+// GetRating récupère les évaluations d'un article
+// @Summary Évaluations d'un article
+// @Description Nombre d'avis utiles et non utiles, part d'avis utiles et évaluation de l'utilisateur courant. Le détail des évaluations (commentaires, version évaluée) est ajouté pour knowledge.update ou knowledge.publish
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Success 200 {object} dto.KnowledgeArticleRatingDTO
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/articles/{id}/feedback [get]
+func (h *KnowledgeFeedbackHandler) GetRating(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	rating, err := h.knowledgeFeedbackService.GetRating(utils.GetScopeFromContext(c), uint(id), userID, canManageKnowledgeFeedback(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, rating, "Évaluations récupérées avec succès")
+}
+
+// GetLowRated récupère le rapport des articles mal notés
+// @Summary Articles mal notés
+// @Description Articles visibles ayant reçu au moins min_ratings évaluations et dont la part d'avis utiles ne dépasse pas max_rate, du moins utile au plus utile, avec leurs derniers commentaires négatifs (nécessite knowledge.update ou knowledge.publish)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param min_ratings query int false "Nombre minimal d'évaluations (défaut: 5)"
+// @Param max_rate query number false "Part maximale d'avis utiles, en pourcentage (défaut: 50)"
+// @Param limit query int false "Nombre d'articles (défaut: 50, maximum: 200)"
+// @Success 200 {array} dto.LowRatedKnowledgeArticleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /knowledge-base/articles/low-rated [get]
+func (h *KnowledgeFeedbackHandler) GetLowRated(c *gin.Context) {
+	if !canManageKnowledgeFeedback(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.update ou knowledge.publish")
+		return
+	}
+
+	filter := repositories.KnowledgeLowRatedFilter{MinRatings: 5, MaxRate: 50, Limit: 50}
+	if v := c.Query("min_ratings"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			utils.BadRequestResponse(c, "Paramètre min_ratings invalide")
+			return
+		}
+		filter.MinRatings = parsed
+	}
+	if v := c.Query("max_rate"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 || parsed > 100 {
+			utils.BadRequestResponse(c, "Paramètre max_rate invalide (0 à 100)")
+			return
+		}
+		filter.MaxRate = parsed
+	}
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			utils.BadRequestResponse(c, "Paramètre limit invalide")
+			return
+		}
+		filter.Limit = min(parsed, 200)
+	}
+
+	articles, err := h.knowledgeFeedbackService.GetLowRated(utils.GetScopeFromContext(c), filter)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, articles, "Articles mal notés récupérés avec succès")
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// KnowledgePortalHandler gère le portail public (lecture seule) de la base de connaissances et ses clés d'API
+type KnowledgePortalHandler struct {
+	knowledgePortalService services.KnowledgePortalService
+}
+
+// NewKnowledgePortalHandler crée une nouvelle instance de KnowledgePortalHandler
+func NewKnowledgePortalHandler(knowledgePortalService services.KnowledgePortalService) *KnowledgePortalHandler {
+	return &KnowledgePortalHandler{
+		knowledgePortalService: knowledgePortalService,
+	}
+}
+
+// access vérifie l'accès au portail selon KB_PUBLIC_MODE et retourne la filiale dont les articles sont exposés
+// (en plus des articles globaux ; nil = toutes). Une clé d'API (en-tête X-API-Key, Authorization Bearer ou paramètre
+// api_key) impose sa filiale ; sans clé, en mode open, le paramètre filiale_id restreint les articles
+func (h *KnowledgePortalHandler) access(c *gin.Context) (*uint, bool) {
+	mode := "disabled"
+	if cfg := config.Current(); cfg != nil {
+		mode = cfg.KBPublic.Mode
+	}
+	if mode == "disabled" {
+		utils.NotFoundResponse(c, "Portail de la base de connaissances désactivé")
+		return nil, false
+	}
+
+	key := c.GetHeader("X-API-Key")
+	if bearer, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found {
+		key = strings.TrimSpace(bearer)
+	}
+	if key == "" {
+		key = c.Query("api_key")
+	}
+	if key != "" {
+		portalKey, err := h.knowledgePortalService.Authenticate(key)
+		if err != nil {
+			utils.ServiceErrorResponse(c, err)
+			return nil, false
+		}
+		return portalKey.FilialeID, true
+	}
+	if mode == "api_key" {
+		utils.UnauthorizedResponse(c, "Clé d'API requise")
+		return nil, false
+	}
+
+	filialeID, ok := parseOptionalUintQuery(c, "filiale_id")
+	if !ok {
+		utils.BadRequestResponse(c, "filiale_id invalide")
+		return nil, false
+	}
+	return filialeID, true
+}
+
+// GetArticles récupère les articles du portail public
+// @Summary Lister les articles publics
+// @Description Articles publiés marqués publics (is_public), du plus récemment modifié au plus ancien, ou par pertinence avec q (extraits surlignés). Accès selon KB_PUBLIC_MODE : open (sans authentification) ou api_key (clé du portail en en-tête X-API-Key, Bearer ou paramètre api_key) ; une clé de filiale ne voit que les articles globaux et ceux de sa filiale. Limité par adresse IP (KB_PUBLIC_RATE_LIMIT_*)
+// @Tags knowledge-portal
+// @Produce json
+// @Param q query string false "Recherche"
+// @Param category_id query int false "Catégorie"
+// @Param filiale_id query int false "Filiale (mode open sans clé)"
+// @Param page query int false "Page (défaut: 1)"
+// @Param limit query int false "Articles par page (défaut: 20, max: 100)"
+// @Success 200 {object} dto.PublicKnowledgeArticleListDTO
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /knowledge/articles [get]
+func (h *KnowledgePortalHandler) GetArticles(c *gin.Context) {
+	filialeID, ok := h.access(c)
+	if !ok {
+		return
+	}
+
+	categoryID, ok := parseOptionalUintQuery(c, "category_id")
+	if !ok {
+		utils.BadRequestResponse(c, "category_id invalide")
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	articles, err := h.knowledgePortalService.GetArticles(repositories.KnowledgePortalFilter{
+		FilialeID:  filialeID,
+		CategoryID: categoryID,
+		Query:      c.Query("q"),
+		Page:       page,
+		Limit:      limit,
+	})
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, articles, "Articles récupérés avec succès")
+}
+
+// GetArticle récupère un article du portail public
+// @Summary Consulter un article public
+// @Description Article publié marqué public, contenu rendu en HTML assaini ; les liens des images et fichiers joints pointent vers le portail. Incrémente le compteur de vues
+// @Tags knowledge-portal
+// @Produce json
+// @Param id path int true "ID de l'article"
+// @Success 200 {object} dto.PublicKnowledgeArticleDTO
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /knowledge/articles/{id} [get]
+func (h *KnowledgePortalHandler) GetArticle(c *gin.Context) {
+	filialeID, ok := h.access(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	article, err := h.knowledgePortalService.GetArticle(uint(id), filialeID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, article, "Article récupéré avec succès")
+}
+
+// GetCategories récupère les catégories du portail public
+// @Summary Lister les catégories publiques
+// @Description Catégories actives contenant au moins un article public
+// @Tags knowledge-portal
+// @Produce json
+// @Success 200 {array} dto.PublicKnowledgeCategoryDTO
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /knowledge/categories [get]
+func (h *KnowledgePortalHandler) GetCategories(c *gin.Context) {
+	filialeID, ok := h.access(c)
+	if !ok {
+		return
+	}
+
+	categories, err := h.knowledgePortalService.GetCategories(filialeID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, categories, "Catégories récupérées avec succès")
+}
+
+// DownloadAttachment sert une pièce jointe d'un article du portail public
+// @Summary Télécharger une pièce jointe d'un article public
+// @Description Sert une image intégrée ou un fichier joint à un article public
+// @Tags knowledge-portal
+// @Produce octet-stream
+// @Param id path int true "ID de l'article"
+// @Param attachmentId path int true "ID de la pièce jointe"
+// @Success 200 {file} file "Fichier"
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /knowledge/articles/{id}/attachments/{attachmentId} [get]
+func (h *KnowledgePortalHandler) DownloadAttachment(c *gin.Context) {
+	filialeID, ok := h.access(c)
+	if !ok {
+		return
+	}
+
+	id, attachmentID, ok := parseArticleAttachment(c)
+	if !ok {
+		return
+	}
+
+	object, attachment, err := h.knowledgePortalService.OpenAttachment(id, attachmentID, filialeID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	serveStorageObject(c, object, attachment.FileName)
+}
+
+// GetKeys récupère les clés d'API du portail public
+// @Summary Lister les clés du portail public
+// @Description Liste les clés d'API du portail public de la base de connaissances (sans leur valeur) (nécessite knowledge.manage_public)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.KnowledgePortalKeyDTO
+// @Failure 403 {object} utils.Response
+// @Router /knowledge-base/portal-keys [get]
+func (h *KnowledgePortalHandler) GetKeys(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_public") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_public")
+		return
+	}
+
+	keys, err := h.knowledgePortalService.GetKeys()
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, keys, "Clés d'API récupérées avec succès")
+}
+
+// CreateKey crée une clé d'API du portail public
+// @Summary Créer une clé du portail public
+// @Description Crée une clé d'API, retournée uniquement dans cette réponse. Une clé de filiale ne voit que les articles publics globaux et ceux de sa filiale (nécessite knowledge.manage_public)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateKnowledgePortalKeyRequest true "Clé à créer"
+// @Success 201 {object} dto.KnowledgePortalKeyDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /knowledge-base/portal-keys [post]
+func (h *KnowledgePortalHandler) CreateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_public") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_public")
+		return
+	}
+
+	var req dto.CreateKnowledgePortalKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	key, err := h.knowledgePortalService.CreateKey(req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, key, "Clé d'API créée avec succès")
+}
+
+// UpdateKey met à jour une clé d'API du portail public
+// @Summary Modifier une clé du portail public
+// @Description Met à jour le nom ou l'activation ; une clé inactive est refusée par le portail (nécessite knowledge.manage_public)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la clé"
+// @Param request body dto.UpdateKnowledgePortalKeyRequest true "Champs à modifier"
+// @Success 200 {object} dto.KnowledgePortalKeyDTO
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/portal-keys/{id} [put]
+func (h *KnowledgePortalHandler) UpdateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_public") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_public")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateKnowledgePortalKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	key, err := h.knowledgePortalService.UpdateKey(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, key, "Clé d'API mise à jour avec succès")
+}
+
+// RotateKey renouvelle une clé d'API du portail public
+// @Summary Renouveler une clé du portail public
+// @Description Génère une nouvelle clé, retournée uniquement dans cette réponse ; l'ancienne est immédiatement refusée (nécessite knowledge.manage_public)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la clé"
+// @Success 200 {object} dto.KnowledgePortalKeyDTO
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/portal-keys/{id}/key [post]
+func (h *KnowledgePortalHandler) RotateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_public") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_public")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	key, err := h.knowledgePortalService.RotateKey(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, key, "Clé d'API renouvelée")
+}
+
+// DeleteKey supprime une clé d'API du portail public
+// @Summary Supprimer une clé du portail public
+// @Description Révoque définitivement la clé (nécessite knowledge.manage_public)
+// @Tags knowledge-base
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la clé"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /knowledge-base/portal-keys/{id} [delete]
+func (h *KnowledgePortalHandler) DeleteKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.manage_public") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.manage_public")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.knowledgePortalService.DeleteKey(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Clé d'API supprimée avec succès")
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/logger"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// LoggingHandler gère la configuration du logger à l'exécution
+type LoggingHandler struct{}
+
+// NewLoggingHandler crée une nouvelle instance de LoggingHandler
+func NewLoggingHandler() *LoggingHandler {
+	return &LoggingHandler{}
+}
+
+// GetLevel retourne le niveau de log courant
+// @Summary Niveau de log courant
+// @Description Retourne le niveau de log actif (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.LogLevelDTO
+// @Failure 403 {object} utils.Response
+// @Router /admin/log-level [get]
+func (h *LoggingHandler) GetLevel(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	utils.SuccessResponse(c, dto.LogLevelDTO{Level: logger.GetLevel()}, "Niveau de log récupéré avec succès")
+}
+
+// SetLevel modifie le niveau de log sans redémarrage
+// @Summary Modifier le niveau de log
+// @Description Modifie le niveau de log à chaud: debug, info, warn, error (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.LogLevelDTO true "Nouveau niveau"
+// @Success 200 {object} dto.LogLevelDTO
+// @Failure 400 {object} utils.Response
+// @Router /admin/log-level [put]
+func (h *LoggingHandler) SetLevel(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	var req dto.LogLevelDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	previous := logger.GetLevel()
+	if err := logger.SetLevel(req.Level); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).Warn("log_level_changed", slog.String("from", previous), slog.String("to", logger.GetLevel()))
+	utils.SuccessResponse(c, dto.LogLevelDTO{Level: logger.GetLevel()}, "Niveau de log mis à jour avec succès")
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// MaintenancePlanHandler gère les handlers des plans de maintenance préventive des actifs
+type MaintenancePlanHandler struct {
+	maintenancePlanService services.MaintenancePlanService
+}
+
+// NewMaintenancePlanHandler crée une nouvelle instance de MaintenancePlanHandler
+func NewMaintenancePlanHandler(maintenancePlanService services.MaintenancePlanService) *MaintenancePlanHandler {
+	return &MaintenancePlanHandler{
+		maintenancePlanService: maintenancePlanService,
+	}
+}
+
+// parseOptionalUintQuery lit un identifiant optionnel passé en paramètre de requête
+func parseOptionalUintQuery(c *gin.Context, name string) (*uint, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return nil, false
+	}
+	value := uint(id)
+	return &value, true
+}
+
+// GetAll récupère les plans de maintenance
+// @Summary Lister les plans de maintenance
+// @Description Récupère les plans de maintenance préventive, avec leur prochaine échéance (nécessite asset_maintenance.manage)
+// @Tags maintenance-plans
+// @Security BearerAuth
+// @Produce json
+// @Param asset_id query int false "Actif"
+// @Param category_id query int false "Catégorie d'actifs"
+// @Param active query bool false "Plans actifs uniquement"
+// @Success 200 {array} dto.MaintenancePlanDTO
+// @Failure 403 {object} utils.Response
+// @Router /maintenance-plans [get]
+func (h *MaintenancePlanHandler) GetAll(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_maintenance.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_maintenance.manage")
+		return
+	}
+
+	var filter repositories.MaintenancePlanFilter
+	var ok bool
+	if filter.AssetID, ok = parseOptionalUintQuery(c, "asset_id"); !ok {
+		utils.BadRequestResponse(c, "ID de l'actif invalide")
+		return
+	}
+	if filter.CategoryID, ok = parseOptionalUintQuery(c, "category_id"); !ok {
+		utils.BadRequestResponse(c, "ID de la catégorie invalide")
+		return
+	}
+	if v := c.Query("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre active invalide")
+			return
+		}
+		filter.ActiveOnly = active
+	}
+
+	plans, err := h.maintenancePlanService.GetAll(filter)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, plans, "Plans de maintenance récupérés avec succès")
+}
+
+// GetByID récupère un plan de maintenance par son ID
+// @Summary Récupérer un plan de maintenance
+// @Description Récupère un plan de maintenance et ses dernières opérations générées (nécessite asset_maintenance.manage)
+// @Tags maintenance-plans
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du plan de maintenance"
+// @Success 200 {object} dto.MaintenancePlanDTO
+// @Failure 404 {object} utils.Response
+// @Router /maintenance-plans/{id} [get]
+func (h *MaintenancePlanHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_maintenance.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_maintenance.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	plan, err := h.maintenancePlanService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, plan, "Plan de maintenance récupéré avec succès")
+}
+
+// Create crée un plan de maintenance
+// @Summary Créer un plan de maintenance
+// @Description Planifie la maintenance préventive d'un actif ou de tous les actifs en service d'une catégorie. schedule accepte une expression cron ou une règle RRULE, comme les tickets récurrents. À chaque échéance, un ticket est créé par actif au nom du créateur du plan, rattaché à l'actif, assigné à l'équipe configurée et doté de la checklist du plan ; un actif dont le ticket précédent n'est pas clôturé n'en reçoit pas de nouveau (nécessite asset_maintenance.manage)
+// @Tags maintenance-plans
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateMaintenancePlanRequest true "Plan de maintenance"
+// @Success 201 {object} dto.MaintenancePlanDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /maintenance-plans [post]
+func (h *MaintenancePlanHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_maintenance.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_maintenance.manage")
+		return
+	}
+
+	var req dto.CreateMaintenancePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	plan, err := h.maintenancePlanService.Create(req, createdByID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, plan, "Plan de maintenance créé avec succès")
+}
+
+// Update met à jour un plan de maintenance
+// @Summary Mettre à jour un plan de maintenance
+// @Description Met à jour un plan ; la prochaine échéance est recalculée à partir de maintenant, la checklist et l'équipe fournies remplacent les existantes (nécessite asset_maintenance.manage)
+// @Tags maintenance-plans
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du plan de maintenance"
+// @Param request body dto.UpdateMaintenancePlanRequest true "Données à mettre à jour"
+// @Success 200 {object} dto.MaintenancePlanDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /maintenance-plans/{id} [put]
+func (h *MaintenancePlanHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_maintenance.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_maintenance.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateMaintenancePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	plan, err := h.maintenancePlanService.Update(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, plan, "Plan de maintenance mis à jour avec succès")
+}
+
+// Delete supprime un plan de maintenance
+// @Summary Supprimer un plan de maintenance
+// @Description Supprime un plan ; les tickets déjà créés et l'historique de maintenance des actifs sont conservés (nécessite asset_maintenance.manage)
+// @Tags maintenance-plans
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du plan de maintenance"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /maintenance-plans/{id} [delete]
+func (h *MaintenancePlanHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_maintenance.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_maintenance.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.maintenancePlanService.Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Plan de maintenance supprimé avec succès")
+}
+
+// Run génère immédiatement les tickets d'un plan de maintenance
+// @Summary Exécuter un plan de maintenance
+// @Description Crée immédiatement un ticket par actif du périmètre, sans décaler la prochaine échéance (les échéances sont traitées par la tâche planifiée maintenance_plans) (nécessite asset_maintenance.manage)
+// @Tags maintenance-plans
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du plan de maintenance"
+// @Success 201 {array} dto.MaintenanceRecordDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /maintenance-plans/{id}/run [post]
+func (h *MaintenancePlanHandler) Run(c *gin.Context) {
+	if !utils.RequirePermission(c, "asset_maintenance.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: asset_maintenance.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	records, err := h.maintenancePlanService.RunNow(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, records, "Tickets de maintenance générés")
+}
+
+// GetAssetMaintenance récupère la vue maintenance d'un actif
+// @Summary Maintenance d'un actif
+// @Description Récupère les plans de maintenance qui s'appliquent à l'actif (directement ou via sa catégorie), la prochaine échéance, la dernière maintenance clôturée et l'historique des tickets de maintenance
+// @Tags assets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'actif"
+// @Success 200 {object} dto.AssetMaintenanceDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /assets/{id}/maintenance [get]
+func (h *MaintenancePlanHandler) GetAssetMaintenance(c *gin.Context) {
+	if !utils.RequireAnyPermission(c, "assets.view_all", "assets.view_team", "assets.view_own", "asset_maintenance.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: assets.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	view, err := h.maintenancePlanService.GetAssetMaintenance(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, view, "Maintenance de l'actif récupérée avec succès")
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// MajorIncidentHandler gère les handlers du mode incident majeur
+type MajorIncidentHandler struct {
+	majorIncidentService services.MajorIncidentService
+}
+
+// NewMajorIncidentHandler crée une nouvelle instance de MajorIncidentHandler
+func NewMajorIncidentHandler(majorIncidentService services.MajorIncidentService) *MajorIncidentHandler {
+	return &MajorIncidentHandler{
+		majorIncidentService: majorIncidentService,
+	}
+}
+
+// canViewIncidents indique si l'utilisateur peut consulter des incidents
+func canViewIncidents(c *gin.Context) bool {
+	return utils.RequireAnyPermission(c, "incidents.view", "incidents.view_all", "incidents.view_team", "incidents.view_own")
+}
+
+// incidentIDParam lit l'ID de l'incident dans le chemin
+func incidentIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// Declare déclare un incident majeur
+// @Summary Déclarer un incident majeur
+// @Description Passe l'incident en mode incident majeur : le message est diffusé à tous les utilisateurs de la filiale du ticket, l'incident apparaît sur la page de statut et un point de situation est attendu à chaque intervalle (nécessite incidents.major_manage)
+// @Tags incidents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'incident"
+// @Param request body dto.DeclareMajorIncidentRequest true "Premier message et fréquence des points de situation"
+// @Success 200 {object} dto.IncidentDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /incidents/{id}/major [post]
+func (h *MajorIncidentHandler) Declare(c *gin.Context) {
+	if !utils.RequirePermission(c, "incidents.major_manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.major_manage")
+		return
+	}
+
+	id, ok := incidentIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.DeclareMajorIncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	declaredByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	incident, err := h.majorIncidentService.Declare(id, req, declaredByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, incident, "Incident majeur déclaré")
+}
+
+// PostUpdate publie un point de situation
+// @Summary Publier un point de situation
+// @Description Publie un point de situation sur un incident majeur en cours ; un point public est diffusé aux utilisateurs de la filiale et affiché sur la page de statut (nécessite incidents.major_manage)
+// @Tags incidents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'incident"
+// @Param request body dto.PostMajorIncidentUpdateRequest true "Point de situation"
+// @Success 201 {object} dto.MajorIncidentUpdateDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /incidents/{id}/major/updates [post]
+func (h *MajorIncidentHandler) PostUpdate(c *gin.Context) {
+	if !utils.RequirePermission(c, "incidents.major_manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.major_manage")
+		return
+	}
+
+	id, ok := incidentIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostMajorIncidentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	postedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	update, err := h.majorIncidentService.PostUpdate(id, req, postedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, update, "Point de situation publié")
+}
+
+// GetUpdates récupère les points de situation d'un incident
+// @Summary Lister les points de situation
+// @Description Récupère les points de situation d'un incident majeur, notes internes comprises, les plus récents d'abord
+// @Tags incidents
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'incident"
+// @Success 200 {array} dto.MajorIncidentUpdateDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /incidents/{id}/major/updates [get]
+func (h *MajorIncidentHandler) GetUpdates(c *gin.Context) {
+	if !canViewIncidents(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.view")
+		return
+	}
+
+	id, ok := incidentIDParam(c)
+	if !ok {
+		return
+	}
+
+	updates, err := h.majorIncidentService.GetUpdates(id)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, updates, "Points de situation récupérés avec succès")
+}
+
+// End termine un incident majeur
+// @Summary Terminer un incident majeur
+// @Description Publie le message de rétablissement, diffusé aux utilisateurs de la filiale, et prépare la revue post-incident à partir du modèle (nécessite incidents.major_manage)
+// @Tags incidents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'incident"
+// @Param request body dto.EndMajorIncidentRequest true "Message de rétablissement"
+// @Success 200 {object} dto.IncidentDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /incidents/{id}/major/end [post]
+func (h *MajorIncidentHandler) End(c *gin.Context) {
+	if !utils.RequirePermission(c, "incidents.major_manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.major_manage")
+		return
+	}
+
+	id, ok := incidentIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.EndMajorIncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	endedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	incident, err := h.majorIncidentService.End(id, req, endedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, incident, "Incident majeur terminé")
+}
+
+// GetActive récupère les incidents majeurs en cours
+// @Summary Incidents majeurs en cours
+// @Description Récupère les incidents majeurs en cours avec l'échéance de leur prochain point de situation
+// @Tags incidents
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.IncidentDTO
+// @Failure 403 {object} utils.Response
+// @Router /incidents/major/active [get]
+func (h *MajorIncidentHandler) GetActive(c *gin.Context) {
+	if !canViewIncidents(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.view")
+		return
+	}
+
+	incidents, err := h.majorIncidentService.GetActive()
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, incidents, "Incidents majeurs récupérés avec succès")
+}
+
+// GetPublicStatus retourne la page de statut publique
+// @Summary Page de statut
+// @Description Résumé public des incidents majeurs en cours et terminés depuis moins de 24 h, avec leurs points de situation publics (sans authentification)
+// @Tags status
+// @Produce json
+// @Success 200 {object} dto.PublicStatusDTO
+// @Router /status [get]
+func (h *MajorIncidentHandler) GetPublicStatus(c *gin.Context) {
+	status, err := h.majorIncidentService.GetPublicStatus()
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=30")
+	utils.SuccessResponse(c, status, "Statut récupéré avec succès")
+}
+
+// GetReview récupère la revue post-incident
+// @Summary Récupérer la revue post-incident
+// @Description Récupère la revue post-incident d'un incident majeur
+// @Tags incidents
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'incident"
+// @Success 200 {object} dto.PostIncidentReviewDTO
+// @Failure 404 {object} utils.Response
+// @Router /incidents/{id}/review [get]
+func (h *MajorIncidentHandler) GetReview(c *gin.Context) {
+	if !canViewIncidents(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.view")
+		return
+	}
+
+	id, ok := incidentIDParam(c)
+	if !ok {
+		return
+	}
+
+	review, err := h.majorIncidentService.GetReview(id)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, review, "Revue post-incident récupérée avec succès")
+}
+
+// CreateReview crée la revue post-incident à partir du modèle
+// @Summary Créer la revue post-incident
+// @Description Crée la revue post-incident préremplie (résumé, impact, chronologie des points de situation, trames des enseignements et actions correctives) ; elle est créée automatiquement à la fin de l'incident majeur (nécessite incidents.major_manage)
+// @Tags incidents
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'incident"
+// @Success 201 {object} dto.PostIncidentReviewDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /incidents/{id}/review [post]
+func (h *MajorIncidentHandler) CreateReview(c *gin.Context) {
+	if !utils.RequirePermission(c, "incidents.major_manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.major_manage")
+		return
+	}
+
+	id, ok := incidentIDParam(c)
+	if !ok {
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	review, err := h.majorIncidentService.CreateReview(id, createdByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, review, "Revue post-incident créée avec succès")
+}
+
+// UpdateReview met à jour la revue post-incident
+// @Summary Mettre à jour la revue post-incident
+// @Description Met à jour les champs fournis ; complete=true clôt la revue (cause racine et actions correctives requises) (nécessite incidents.major_manage)
+// @Tags incidents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'incident"
+// @Param request body dto.UpdatePostIncidentReviewRequest true "Champs de la revue"
+// @Success 200 {object} dto.PostIncidentReviewDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /incidents/{id}/review [put]
+func (h *MajorIncidentHandler) UpdateReview(c *gin.Context) {
+	if !utils.RequirePermission(c, "incidents.major_manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: incidents.major_manage")
+		return
+	}
+
+	id, ok := incidentIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdatePostIncidentReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	updatedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	review, err := h.majorIncidentService.UpdateReview(id, req, updatedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, review, "Revue post-incident mise à jour avec succès")
+}
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// alertPayloadMaxSize taille maximale d'une notification de supervision
+const alertPayloadMaxSize = 2 << 20
+
+// MonitoringAlertHandler gère l'ingestion des alertes de supervision et la déclaration des outils émetteurs
+type MonitoringAlertHandler struct {
+	alertService services.MonitoringAlertService
+}
+
+// NewMonitoringAlertHandler crée une nouvelle instance de MonitoringAlertHandler
+func NewMonitoringAlertHandler(alertService services.MonitoringAlertService) *MonitoringAlertHandler {
+	return &MonitoringAlertHandler{
+		alertService: alertService,
+	}
+}
+
+// monitoringAlertErrorResponse traduit une erreur du service en réponse HTTP
+func monitoringAlertErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// Ingest reçoit une notification d'un outil de supervision
+// @Summary Recevoir des alertes de supervision
+// @Description Point d'entrée des webhooks Prometheus Alertmanager et Zabbix, authentifié par la clé d'API de l'outil (en-tête Authorization: Bearer <clé> ou X-API-Key). Chaque nouvelle alerte crée un ticket d'incident rattaché à l'actif (étiquettes asset_id, serial ou nom de l'hôte) et au logiciel (étiquettes software, application ou service) reconnus ; les notifications suivantes de la même alerte (même empreinte) mettent à jour le même ticket, qui passe en résolu quand l'alerte est levée
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.AlertIngestResultDTO
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /integrations/alerts [post]
+func (h *MonitoringAlertHandler) Ingest(c *gin.Context) {
+	key := c.GetHeader("X-API-Key")
+	if bearer, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found {
+		key = strings.TrimSpace(bearer)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, alertPayloadMaxSize))
+	if err != nil {
+		utils.BadRequestResponse(c, "Contenu de la notification illisible")
+		return
+	}
+
+	result, err := h.alertService.Ingest(key, body)
+	if err != nil {
+		monitoringAlertErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Alertes traitées")
+}
+
+// GetAlerts récupère les alertes reçues
+// @Summary Liste des alertes de supervision
+// @Description Liste paginée des alertes reçues, dernières notifications d'abord, avec le ticket d'incident associé (nécessite alerts.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param source_id query int false "Filtrer par outil de supervision"
+// @Param status query string false "Filtrer par état (firing, resolved)"
+// @Param page query int false "Numéro de page" default(1)
+// @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Success 200 {object} dto.MonitoringAlertListResponse
+// @Router /integrations/alerts [get]
+func (h *MonitoringAlertHandler) GetAlerts(c *gin.Context) {
+	if !utils.RequirePermission(c, "alerts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: alerts.manage")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	var sourceID *uint
+	if value := c.Query("source_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "source_id invalide")
+			return
+		}
+		sid := uint(id)
+		sourceID = &sid
+	}
+
+	alerts, err := h.alertService.GetAlerts(sourceID, c.Query("status"), page, limit)
+	if err != nil {
+		monitoringAlertErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, alerts, "Alertes récupérées avec succès")
+}
+
+// GetSources récupère les outils de supervision déclarés
+// @Summary Lister les outils de supervision
+// @Description Liste les outils autorisés à envoyer des alertes (sans leur clé d'API) (nécessite alerts.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.AlertSourceDTO
+// @Router /integrations/alerts/sources [get]
+func (h *MonitoringAlertHandler) GetSources(c *gin.Context) {
+	if !utils.RequirePermission(c, "alerts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: alerts.manage")
+		return
+	}
+
+	sources, err := h.alertService.GetSources()
+	if err != nil {
+		monitoringAlertErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, sources, "Sources d'alertes récupérées avec succès")
+}
+
+// CreateSource déclare un outil de supervision
+// @Summary Déclarer un outil de supervision
+// @Description Crée la clé d'API d'un outil Alertmanager ou Zabbix, retournée uniquement dans cette réponse. Les tickets d'incident sont créés au nom de l'utilisateur connecté, dans la filiale indiquée (nécessite alerts.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAlertSourceRequest true "Outil à déclarer"
+// @Success 201 {object} dto.AlertSourceDTO
+// @Failure 400 {object} utils.Response
+// @Router /integrations/alerts/sources [post]
+func (h *MonitoringAlertHandler) CreateSource(c *gin.Context) {
+	if !utils.RequirePermission(c, "alerts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: alerts.manage")
+		return
+	}
+
+	var req dto.CreateAlertSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	source, err := h.alertService.CreateSource(req, userID)
+	if err != nil {
+		monitoringAlertErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, source, "Source d'alertes créée avec succès")
+}
+
+// UpdateSource met à jour un outil de supervision
+// @Summary Modifier un outil de supervision
+// @Description Met à jour le nom, la filiale, la résolution automatique ou l'activation ; une source inactive voit ses notifications refusées (nécessite alerts.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la source"
+// @Param request body dto.UpdateAlertSourceRequest true "Champs à modifier"
+// @Success 200 {object} dto.AlertSourceDTO
+// @Failure 404 {object} utils.Response
+// @Router /integrations/alerts/sources/{id} [put]
+func (h *MonitoringAlertHandler) UpdateSource(c *gin.Context) {
+	if !utils.RequirePermission(c, "alerts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: alerts.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateAlertSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	source, err := h.alertService.UpdateSource(uint(id), req)
+	if err != nil {
+		monitoringAlertErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, source, "Source d'alertes mise à jour avec succès")
+}
+
+// RotateKey renouvelle la clé d'API d'un outil de supervision
+// @Summary Renouveler la clé d'un outil de supervision
+// @Description Génère une nouvelle clé d'API, retournée uniquement dans cette réponse ; l'ancienne clé est immédiatement refusée (nécessite alerts.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la source"
+// @Success 200 {object} dto.AlertSourceDTO
+// @Failure 404 {object} utils.Response
+// @Router /integrations/alerts/sources/{id}/key [post]
+func (h *MonitoringAlertHandler) RotateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "alerts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: alerts.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	source, err := h.alertService.RotateKey(uint(id))
+	if err != nil {
+		monitoringAlertErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, source, "Clé d'API renouvelée")
+}
+
+// DeleteSource supprime un outil de supervision
+// @Summary Supprimer un outil de supervision
+// @Description Supprime la source et l'historique de ses alertes ; les tickets d'incident créés sont conservés (nécessite alerts.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la source"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /integrations/alerts/sources/{id} [delete]
+func (h *MonitoringAlertHandler) DeleteSource(c *gin.Context) {
+	if !utils.RequirePermission(c, "alerts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: alerts.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.alertService.DeleteSource(uint(id)); err != nil {
+		monitoringAlertErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Source d'alertes supprimée avec succès")
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -190,7 +189,7 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 
 	err = h.notificationService.MarkAsRead(uint(id), userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// NotificationPreferenceHandler gère les réglages des notifications par email de l'utilisateur connecté
+type NotificationPreferenceHandler struct {
+	emailService services.EmailService
+}
+
+// NewNotificationPreferenceHandler crée une nouvelle instance de NotificationPreferenceHandler
+func NewNotificationPreferenceHandler(emailService services.EmailService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		emailService: emailService,
+	}
+}
+
+// GetMyNotificationPreferences récupère les réglages des notifications par email de l'utilisateur connecté
+// @Summary Mes réglages de notifications par email
+// @Description Réglage de chaque type de notification pouvant être envoyé par email (activé par défaut). L'interrupteur général email_notifications se modifie dans les préférences utilisateur
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.NotificationPreferencesDTO
+// @Failure 401 {object} utils.Response
+// @Router /users/me/notification-preferences [get]
+func (h *NotificationPreferenceHandler) GetMyNotificationPreferences(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	preferences, err := h.emailService.GetPreferences(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, preferences, "Préférences de notification récupérées avec succès")
+}
+
+// UpdateMyNotificationPreferences met à jour les réglages des notifications par email de l'utilisateur connecté
+// @Summary Modifier mes réglages de notifications par email
+// @Description Active ou désactive l'envoi par email des types fournis (les types omis sont inchangés)
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateNotificationPreferencesRequest true "Réglages à modifier"
+// @Success 200 {object} dto.NotificationPreferencesDTO
+// @Failure 400 {object} utils.Response
+// @Router /users/me/notification-preferences [put]
+func (h *NotificationPreferenceHandler) UpdateMyNotificationPreferences(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	preferences, err := h.emailService.UpdatePreferences(userID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, preferences, "Préférences de notification mises à jour avec succès")
+}
@@ -36,7 +36,7 @@ func NewOfficeHandler(officeService services.OfficeService) *OfficeHandler {
 func (h *OfficeHandler) Create(c *gin.Context) {
 	var req dto.CreateOfficeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -68,7 +68,7 @@ func (h *OfficeHandler) Create(c *gin.Context) {
 
 	office, err := h.officeService.Create(req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -88,9 +88,8 @@ func (h *OfficeHandler) Create(c *gin.Context) {
 // @Router /offices [get]
 func (h *OfficeHandler) GetAll(c *gin.Context) {
 	activeOnly := c.DefaultQuery("active", "false") == "true"
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
 
@@ -190,7 +189,7 @@ func (h *OfficeHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateOfficeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -217,7 +216,7 @@ func (h *OfficeHandler) Update(c *gin.Context) {
 
 	office, err := h.officeService.Update(uint(id), req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -244,7 +243,7 @@ func (h *OfficeHandler) Delete(c *gin.Context) {
 
 	err = h.officeService.Delete(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
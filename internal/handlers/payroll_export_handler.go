@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/spreadsheet"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// PayrollExportHandler gère l'export des heures validées vers la paie
+type PayrollExportHandler struct {
+	payrollExportService services.PayrollExportService
+}
+
+// NewPayrollExportHandler crée une nouvelle instance de PayrollExportHandler
+func NewPayrollExportHandler(payrollExportService services.PayrollExportService) *PayrollExportHandler {
+	return &PayrollExportHandler{
+		payrollExportService: payrollExportService,
+	}
+}
+
+// Export exporte les heures validées d'une période
+// @Summary Exporter les heures vers la paie
+// @Description Exporte les heures validées de la période (entrées validées ou couvertes par une déclaration hebdomadaire approuvée), agrégées par utilisateur et centre de coût : projet (type P, code PRJ-<id>) pour le temps des tâches de projet, département de l'utilisateur (type D) sinon. Format csv, ou fixed : enregistrements à largeur fixe compatibles SAP, décrits par PAYROLL_EXPORT_LAYOUT (champs personnel_number, username, last_name, first_name, period_start, period_end, wage_type, cost_center_type, cost_center, cost_center_name, minutes, hours en centièmes, filler) (nécessite timesheet.view_all)
+// @Tags timesheet
+// @Security BearerAuth
+// @Produce text/csv,text/plain
+// @Param from query string true "Premier jour de la période (YYYY-MM-DD)"
+// @Param to query string true "Dernier jour de la période (YYYY-MM-DD), au plus 366 jours"
+// @Param format query string false "Format d'export : csv (défaut) ou fixed"
+// @Param department_id query int false "Filtrer par département des utilisateurs"
+// @Param filiale_id query int false "Filtrer par filiale des utilisateurs"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /timesheet/export [get]
+func (h *PayrollExportHandler) Export(c *gin.Context) {
+	if !utils.RequirePermission(c, "timesheet.view_all") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.view_all")
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", services.PayrollExportFormatCSV))
+	var filter repositories.PayrollExportFilter
+	var err error
+	if filter.From, err = time.Parse("2006-01-02", c.Query("from")); err != nil {
+		utils.BadRequestResponse(c, "Date de début invalide (format attendu: YYYY-MM-DD)")
+		return
+	}
+	if filter.To, err = time.Parse("2006-01-02", c.Query("to")); err != nil {
+		utils.BadRequestResponse(c, "Date de fin invalide (format attendu: YYYY-MM-DD)")
+		return
+	}
+	if raw := c.Query("department_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID de département invalide")
+			return
+		}
+		departmentID := uint(id)
+		filter.DepartmentID = &departmentID
+	}
+	if raw := c.Query("filiale_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID de filiale invalide")
+			return
+		}
+		filialeID := uint(id)
+		filter.FilialeID = &filialeID
+	}
+
+	if err := h.payrollExportService.Validate(filter, format); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	// Export préparé en mémoire : une erreur reste signalée en JSON, l'en-tête du fichier n'est envoyé qu'ensuite
+	var out bytes.Buffer
+	if err := h.payrollExportService.Export(filter, format, &out); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	contentType, extension := spreadsheet.ContentType(spreadsheet.FormatCSV), "csv"
+	if format == services.PayrollExportFormatFixed {
+		contentType, extension = "text/plain; charset=utf-8", "txt"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="paie_%s_%s.%s"`,
+		filter.From.Format("20060102"), filter.To.Format("20060102"), extension))
+	c.Data(http.StatusOK, contentType, out.Bytes())
+}
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
 	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/timezone"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
@@ -141,3 +144,207 @@ func (h *PerformanceHandler) GetPerformanceRanking(c *gin.Context) {
 
 	utils.SuccessResponse(c, ranking, "Classement récupéré avec succès")
 }
+
+// parseUtilizationRange lit la période du rapport d'utilisation (défaut : du 1er du mois à aujourd'hui)
+func parseUtilizationRange(c *gin.Context) (time.Time, time.Time, bool) {
+	to := timezone.Today(timezone.Default())
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location())
+	if v := c.Query("from"); v != "" {
+		parsed, err := timezone.ParseDate(v)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre from invalide (format attendu: YYYY-MM-DD)")
+			return from, to, false
+		}
+		from = parsed
+	}
+	if v := c.Query("to"); v != "" {
+		parsed, err := timezone.ParseDate(v)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre to invalide (format attendu: YYYY-MM-DD)")
+			return from, to, false
+		}
+		to = parsed
+	}
+	return from, to, true
+}
+
+// GetUtilization calcule le rapport d'utilisation des utilisateurs visibles
+// @Summary Rapport d'utilisation (facturable / non facturable)
+// @Description Répartit le temps déclaré par catégorie de travail (support, project, internal) et facturable / non facturable, par utilisateur, équipe (département) et période ; taux d'utilisation = temps facturable / capacité (8 h par jour ouvré hors absences approuvées), comparé à l'objectif du rôle (nécessite timesheet.view_utilization ; utilisateurs visibles selon le périmètre users.*)
+// @Tags performance
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Date de début (YYYY-MM-DD, défaut : 1er du mois)"
+// @Param to query string false "Date de fin (YYYY-MM-DD, défaut : aujourd'hui ; 366 jours maximum)"
+// @Param granularity query string false "Découpage : week (défaut) ou month"
+// @Param department_id query int false "Département"
+// @Param filiale_id query int false "Filiale"
+// @Success 200 {object} dto.UtilizationReportDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /performance/utilization [get]
+func (h *PerformanceHandler) GetUtilization(c *gin.Context) {
+	if !utils.RequirePermission(c, "timesheet.view_utilization") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.view_utilization")
+		return
+	}
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	var filter repositories.CapacityFilter
+	if filter.DepartmentID, ok = parseOptionalUintQuery(c, "department_id"); !ok {
+		utils.BadRequestResponse(c, "ID du département invalide")
+		return
+	}
+	if filter.FilialeID, ok = parseOptionalUintQuery(c, "filiale_id"); !ok {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+	from, to, ok := parseUtilizationRange(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.performanceService.GetUtilization(queryScope, filter, from, to, c.Query("granularity"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Rapport d'utilisation calculé avec succès")
+}
+
+// GetUserUtilization calcule l'utilisation d'un utilisateur
+// @Summary Utilisation d'un utilisateur
+// @Description Répartition facturable / non facturable et taux d'utilisation d'un utilisateur par période ; chacun voit la sienne, celle des autres nécessite timesheet.view_utilization et un utilisateur visible dans le périmètre
+// @Tags performance
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path int true "ID de l'utilisateur"
+// @Param from query string false "Date de début (YYYY-MM-DD, défaut : 1er du mois)"
+// @Param to query string false "Date de fin (YYYY-MM-DD, défaut : aujourd'hui)"
+// @Param granularity query string false "Découpage : week (défaut) ou month"
+// @Success 200 {object} dto.UserUtilizationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /performance/users/{user_id}/utilization [get]
+func (h *PerformanceHandler) GetUserUtilization(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID utilisateur invalide")
+		return
+	}
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	// Sa propre utilisation : sans restriction de périmètre
+	var scopeParam interface{}
+	if uint(userID) != queryScope.UserID {
+		if !queryScope.HasPermission("timesheet.view_utilization") {
+			utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.view_utilization")
+			return
+		}
+		scopeParam = queryScope
+	}
+	from, to, ok := parseUtilizationRange(c)
+	if !ok {
+		return
+	}
+
+	utilization, err := h.performanceService.GetUserUtilization(scopeParam, uint(userID), from, to, c.Query("granularity"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, utilization, "Utilisation récupérée avec succès")
+}
+
+// GetUtilizationTargets liste les objectifs d'utilisation par rôle
+// @Summary Objectifs d'utilisation par rôle
+// @Description Liste le taux d'utilisation facturable visé de chaque rôle (nécessite timesheet.view_utilization ; sans roles.manage, rôles de sa filiale et rôles globaux)
+// @Tags performance
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.UtilizationTargetDTO
+// @Failure 403 {object} utils.Response
+// @Router /performance/utilization/targets [get]
+func (h *PerformanceHandler) GetUtilizationTargets(c *gin.Context) {
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+	if !queryScope.HasPermission("timesheet.view_utilization") && !queryScope.HasPermission("timesheet.manage_utilization_targets") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.view_utilization")
+		return
+	}
+
+	var filialeID *uint
+	if !queryScope.HasPermission("roles.manage") {
+		filialeID = queryScope.FilialeID
+	}
+	targets, err := h.performanceService.GetUtilizationTargets(filialeID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, targets, "Objectifs d'utilisation récupérés avec succès")
+}
+
+// SetUtilizationTarget définit l'objectif d'utilisation d'un rôle
+// @Summary Définir l'objectif d'utilisation d'un rôle
+// @Description Définit le taux d'utilisation facturable visé (0 à 100 %) des utilisateurs du rôle, ou le retire (null) ; sans roles.manage, seuls les rôles de sa filiale sont modifiables (nécessite timesheet.manage_utilization_targets)
+// @Tags performance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param role_id path int true "ID du rôle"
+// @Param request body dto.SetUtilizationTargetRequest true "Objectif"
+// @Success 200 {object} dto.UtilizationTargetDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /performance/utilization/targets/{role_id} [put]
+func (h *PerformanceHandler) SetUtilizationTarget(c *gin.Context) {
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+	if !queryScope.HasPermission("timesheet.manage_utilization_targets") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.manage_utilization_targets")
+		return
+	}
+	roleID, err := strconv.ParseUint(c.Param("role_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID du rôle invalide")
+		return
+	}
+
+	var req dto.SetUtilizationTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var filialeID *uint
+	if !queryScope.HasPermission("roles.manage") {
+		if queryScope.FilialeID == nil {
+			utils.ForbiddenResponse(c, "Vous ne pouvez définir l'objectif que des rôles de votre filiale")
+			return
+		}
+		filialeID = queryScope.FilialeID
+	}
+	target, err := h.performanceService.SetUtilizationTarget(uint(roleID), req, filialeID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, target, "Objectif d'utilisation mis à jour avec succès")
+}
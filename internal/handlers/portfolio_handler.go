@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// PortfolioHandler gère le tableau de bord du portefeuille de projets
+type PortfolioHandler struct {
+	portfolioService services.PortfolioService
+}
+
+// NewPortfolioHandler crée une nouvelle instance de PortfolioHandler
+func NewPortfolioHandler(portfolioService services.PortfolioService) *PortfolioHandler {
+	return &PortfolioHandler{
+		portfolioService: portfolioService,
+	}
+}
+
+// GetPortfolio calcule les indicateurs consolidés des projets visibles
+// @Summary Portefeuille de projets
+// @Description Agrège les projets du périmètre : répartition par statut, consommation du budget temps (masquée sans projects.budget.view), tâches en retard, comparaison par filiale et tendance mensuelle sur les N derniers mois (nécessite projects.portfolio.view ; projets archivés exclus par défaut)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param months query int false "Nombre de mois de la tendance, mois en cours inclus (défaut 6, maximum 24)"
+// @Param filiale_id query int false "Filiale"
+// @Param include_archived query bool false "Inclure les projets archivés"
+// @Success 200 {object} dto.PortfolioReportDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /projects/portfolio [get]
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.portfolio.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.portfolio.view")
+		return
+	}
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	var filter repositories.PortfolioFilter
+	if filter.FilialeID, ok = parseOptionalUintQuery(c, "filiale_id"); !ok {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+	filter.IncludeArchived, _ = strconv.ParseBool(c.Query("include_archived"))
+	months := 0
+	if v := c.Query("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			utils.BadRequestResponse(c, "Nombre de mois invalide")
+			return
+		}
+		months = n
+	}
+
+	report, err := h.portfolioService.GetPortfolio(queryScope, filter, months)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Portefeuille de projets calculé avec succès")
+}
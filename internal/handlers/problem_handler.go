@@ -0,0 +1,512 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ProblemHandler gère les handlers des problèmes et erreurs connues
+type ProblemHandler struct {
+	problemService services.ProblemService
+}
+
+// NewProblemHandler crée une nouvelle instance de ProblemHandler
+func NewProblemHandler(problemService services.ProblemService) *ProblemHandler {
+	return &ProblemHandler{
+		problemService: problemService,
+	}
+}
+
+// canViewProblems indique si l'utilisateur peut consulter des problèmes
+func canViewProblems(c *gin.Context) bool {
+	return utils.RequireAnyPermission(c, "problems.view", "problems.view_all", "problems.view_team", "problems.view_own")
+}
+
+// Create crée un nouveau problème
+// @Summary Créer un problème
+// @Description Crée un problème à partir d'un ticket de catégorie « probleme » et lie éventuellement des tickets incidents (nécessite problems.create)
+// @Tags problems
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateProblemRequest true "Données du problème"
+// @Success 201 {object} dto.ProblemDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /problems [post]
+func (h *ProblemHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.create")
+		return
+	}
+
+	var req dto.CreateProblemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	problem, err := h.problemService.Create(req, createdByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, problem, "Problème créé avec succès")
+}
+
+// GetAll récupère les problèmes
+// @Summary Lister les problèmes
+// @Description Récupère les problèmes visibles par l'utilisateur ; known_error=true limite la liste aux erreurs connues
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param known_error query bool false "Filtrer sur les erreurs connues"
+// @Success 200 {array} dto.ProblemDTO
+// @Failure 500 {object} utils.Response
+// @Router /problems [get]
+func (h *ProblemHandler) GetAll(c *gin.Context) {
+	var knownError *bool
+	if v := c.Query("known_error"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre known_error invalide")
+			return
+		}
+		knownError = &parsed
+	}
+
+	// Extraire le QueryScope du contexte (injecté par AuthMiddleware)
+	queryScope := utils.GetScopeFromContext(c)
+
+	problems, err := h.problemService.GetAll(queryScope, knownError)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, problems, "Problèmes récupérés avec succès")
+}
+
+// GetByID récupère un problème par son ID
+// @Summary Récupérer un problème par ID
+// @Description Récupère un problème avec ses tickets incidents liés
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Success 200 {object} dto.ProblemDTO
+// @Failure 404 {object} utils.Response
+// @Router /problems/{id} [get]
+func (h *ProblemHandler) GetByID(c *gin.Context) {
+	if !canViewProblems(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	problem, err := h.problemService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, problem, "Problème récupéré avec succès")
+}
+
+// GetByTicketID récupère le problème porté par un ticket
+// @Summary Récupérer le problème d'un ticket
+// @Description Récupère le problème associé à un ticket de catégorie « probleme »
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param ticketId path int true "ID du ticket"
+// @Success 200 {object} dto.ProblemDTO
+// @Failure 404 {object} utils.Response
+// @Router /problems/by-ticket/{ticketId} [get]
+func (h *ProblemHandler) GetByTicketID(c *gin.Context) {
+	if !canViewProblems(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.view")
+		return
+	}
+
+	ticketID, err := strconv.ParseUint(c.Param("ticketId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return
+	}
+
+	problem, err := h.problemService.GetByTicketID(uint(ticketID))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, problem, "Problème récupéré avec succès")
+}
+
+// GetByIncident récupère les problèmes auxquels un ticket incident est lié
+// @Summary Problèmes d'un incident
+// @Description Récupère les problèmes visibles auxquels un ticket incident est lié
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param ticketId path int true "ID du ticket incident"
+// @Success 200 {array} dto.ProblemDTO
+// @Failure 400 {object} utils.Response
+// @Router /problems/by-incident/{ticketId} [get]
+func (h *ProblemHandler) GetByIncident(c *gin.Context) {
+	ticketID, err := strconv.ParseUint(c.Param("ticketId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return
+	}
+
+	queryScope := utils.GetScopeFromContext(c)
+
+	problems, err := h.problemService.GetByIncidentTicketID(queryScope, uint(ticketID))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, problems, "Problèmes récupérés avec succès")
+}
+
+// Update met à jour un problème
+// @Summary Mettre à jour un problème
+// @Description Met à jour l'impact, la cause racine et le contournement d'un problème (nécessite problems.update)
+// @Tags problems
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Param request body dto.UpdateProblemRequest true "Données de mise à jour"
+// @Success 200 {object} dto.ProblemDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /problems/{id} [put]
+func (h *ProblemHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateProblemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	updatedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	problem, err := h.problemService.Update(uint(id), req, updatedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, problem, "Problème mis à jour avec succès")
+}
+
+// MarkKnownError déclare un problème comme erreur connue
+// @Summary Déclarer une erreur connue
+// @Description Déclare le problème comme erreur connue ; la cause racine et le contournement doivent être documentés (dans la requête ou au préalable) (nécessite problems.update)
+// @Tags problems
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Param request body dto.MarkKnownErrorRequest false "Cause racine et contournement"
+// @Success 200 {object} dto.ProblemDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /problems/{id}/known-error [post]
+func (h *ProblemHandler) MarkKnownError(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.MarkKnownErrorRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	declaredByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	problem, err := h.problemService.MarkKnownError(uint(id), req, declaredByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, problem, "Erreur connue déclarée avec succès")
+}
+
+// Resolve marque un problème comme résolu
+// @Summary Résoudre un problème
+// @Description Marque le problème comme résolu (correctif définitif appliqué) (nécessite problems.update)
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Success 200 {object} dto.ProblemDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /problems/{id}/resolve [post]
+func (h *ProblemHandler) Resolve(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	resolvedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	problem, err := h.problemService.Resolve(uint(id), resolvedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, problem, "Problème résolu avec succès")
+}
+
+// Delete supprime un problème
+// @Summary Supprimer un problème
+// @Description Supprime un problème et ses liaisons aux incidents ; les tickets sont conservés (nécessite problems.delete)
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /problems/{id} [delete]
+func (h *ProblemHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.delete") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.delete")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.problemService.Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Problème supprimé avec succès")
+}
+
+// GetIncidents récupère les tickets incidents liés à un problème
+// @Summary Incidents liés à un problème
+// @Description Récupère les tickets incidents liés au problème
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Success 200 {array} dto.ProblemIncidentDTO
+// @Failure 404 {object} utils.Response
+// @Router /problems/{id}/incidents [get]
+func (h *ProblemHandler) GetIncidents(c *gin.Context) {
+	if !canViewProblems(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	incidents, err := h.problemService.GetIncidents(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, incidents, "Incidents liés récupérés avec succès")
+}
+
+// LinkIncident lie un ticket incident à un problème
+// @Summary Lier un incident à un problème
+// @Description Lie un ticket de catégorie « incident » au problème (nécessite problems.update)
+// @Tags problems
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Param request body dto.LinkProblemIncidentRequest true "Ticket incident"
+// @Success 201 {object} dto.ProblemIncidentDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /problems/{id}/incidents [post]
+func (h *ProblemHandler) LinkIncident(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.LinkProblemIncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	linkedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	link, err := h.problemService.LinkIncident(uint(id), req.TicketID, linkedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, link, "Incident lié au problème avec succès")
+}
+
+// UnlinkIncident supprime la liaison entre un problème et un ticket incident
+// @Summary Délier un incident d'un problème
+// @Description Supprime la liaison entre le problème et un ticket incident (nécessite problems.update)
+// @Tags problems
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Param ticketId path int true "ID du ticket incident"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /problems/{id}/incidents/{ticketId} [delete]
+func (h *ProblemHandler) UnlinkIncident(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	ticketID, err := strconv.ParseUint(c.Param("ticketId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return
+	}
+
+	if err := h.problemService.UnlinkIncident(uint(id), uint(ticketID)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Incident délié du problème avec succès")
+}
+
+// PublishWorkaround publie le contournement d'un problème dans la base de connaissances
+// @Summary Publier le contournement dans la base de connaissances
+// @Description Publie un article Markdown (symptômes, cause racine, contournement) ; une nouvelle publication met à jour l'article existant (nécessite problems.update)
+// @Tags problems
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du problème"
+// @Param request body dto.PublishProblemWorkaroundRequest true "Catégorie et titre de l'article"
+// @Success 200 {object} dto.KnowledgeArticleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /problems/{id}/publish-workaround [post]
+func (h *ProblemHandler) PublishWorkaround(c *gin.Context) {
+	if !utils.RequirePermission(c, "problems.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: problems.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.PublishProblemWorkaroundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	publishedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	article, err := h.problemService.PublishWorkaround(uint(id), req, publishedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, article, "Contournement publié dans la base de connaissances")
+}
@@ -27,6 +27,15 @@ func NewProjectHandler(projectService services.ProjectService) *ProjectHandler {
 	}
 }
 
+// service retourne le service des projets pour la requête : avec projects.archived.edit (administrateurs),
+// les projets archivés restent modifiables
+func (h *ProjectHandler) service(c *gin.Context) services.ProjectService {
+	if utils.RequirePermission(c, "projects.archived.edit") {
+		return h.projectService.AllowArchivedWrites()
+	}
+	return h.projectService
+}
+
 // Create crée un nouveau projet
 // @Summary Créer un projet
 // @Description Crée un nouveau projet dans le système
@@ -49,7 +58,7 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -59,9 +68,9 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 		return
 	}
 
-	project, err := h.projectService.Create(req.Name, req.Description, req.TotalBudgetTime, req.StartDate, req.EndDate, createdByID.(uint))
+	project, err := h.service(c).Create(req.Name, req.Description, req.TotalBudgetTime, req.StartDate, req.EndDate, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -88,7 +97,7 @@ func (h *ProjectHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	project, err := h.projectService.GetByID(uint(id))
+	project, err := h.service(c).GetByID(uint(id))
 	if err != nil {
 		utils.NotFoundResponse(c, "Projet introuvable")
 		return
@@ -99,44 +108,33 @@ func (h *ProjectHandler) GetByID(c *gin.Context) {
 
 // GetAll récupère tous les projets
 // @Summary Récupérer tous les projets
-// @Description Récupère la liste de tous les projets. Query ?scope=own pour « Mon tableau de bord » (uniquement les projets où l'utilisateur est impliqué).
+// @Description Récupère la liste de tous les projets. Query ?scope=own pour « Mon tableau de bord » (uniquement les projets où l'utilisateur est impliqué). Les projets archivés sont exclus sauf ?include_archived=true.
 // @Tags projects
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param scope query string false "scope=own pour limiter aux projets de l'utilisateur connecté (Mon tableau de bord)"
+// @Param include_archived query bool false "Inclure les projets archivés"
 // @Success 200 {array} project
 // @Failure 500 {object} utils.Response
 // @Router /projects [get]
 func (h *ProjectHandler) GetAll(c *gin.Context) {
-	queryScope := utils.GetScopeFromContext(c)
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+	// Le périmètre ?scope=filiale|global est appliqué par le middleware ; own et department restreignent aux projets de l'utilisateur / de son département
 	switch c.Query("scope") {
 	case "own":
-		if queryScope != nil {
-			scopeOwn := &scope.QueryScope{
-				UserID:       queryScope.UserID,
-				DepartmentID: queryScope.DepartmentID,
-				FilialeID:    queryScope.FilialeID,
-				Permissions:  []string{"projects.view_own"},
-			}
-			queryScope = scopeOwn
-		}
-	case "department":
-		if queryScope != nil && queryScope.DepartmentID != nil {
-			scopeDept := &scope.QueryScope{
-				UserID:       queryScope.UserID,
-				DepartmentID: queryScope.DepartmentID,
-				FilialeID:    queryScope.FilialeID,
-				Permissions:  []string{"projects.view_team"},
-				DashboardScopeHint: "department",
-			}
-			queryScope = scopeDept
+		queryScope = queryScope.Restrict("", "projects.view_own")
+	case scope.DashboardScopeDepartment:
+		if queryScope.DashboardScopeHint == scope.DashboardScopeDepartment {
+			queryScope = queryScope.Restrict(scope.DashboardScopeDepartment, "projects.view_team")
 		}
-	default:
-		utils.ApplyDashboardScopeHint(c, queryScope)
 	}
 
-	projects, err := h.projectService.GetAll(queryScope)
+	includeArchived, _ := strconv.ParseBool(c.Query("include_archived"))
+	projects, err := h.service(c).GetAll(queryScope, includeArchived)
 	if err != nil {
 		utils.InternalServerErrorResponse(c, "Erreur lors de la récupération des projets")
 		return
@@ -176,7 +174,7 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		EndDate         *string `json:"end_date,omitempty"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -199,9 +197,9 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		statusStr = *req.Status
 	}
 
-	project, err := h.projectService.Update(uint(id), nameStr, descriptionStr, req.TotalBudgetTime, statusStr, req.StartDate, req.EndDate, updatedByID.(uint))
+	project, err := h.service(c).Update(uint(id), nameStr, descriptionStr, req.TotalBudgetTime, statusStr, req.StartDate, req.EndDate, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -234,7 +232,7 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 		Username string `json:"username" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Le nom d'utilisateur est requis pour confirmer la suppression", err.Error())
+		utils.ValidationErrorResponseWithMessage(c, "Le nom d'utilisateur est requis pour confirmer la suppression", err)
 		return
 	}
 
@@ -260,14 +258,92 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.Delete(uint(id)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, nil, "Projet supprimé avec succès")
 }
 
+// Archive archive un projet
+// @Summary Archiver un projet
+// @Description Passe le projet au statut archived : le projet et ses éléments (étapes, fonctions, membres, tâches, jalons, récurrences) deviennent en lecture seule, sauf pour les administrateurs (projects.archived.edit), et il est exclu de la liste par défaut. L'archivage est journalisé dans l'audit (nécessite projects.archive)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Success 200 {object} project
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/archive [post]
+func (h *ProjectHandler) Archive(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.archive") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.archive")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	project, err := h.projectService.Archive(uint(id), userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, project, "Projet archivé avec succès")
+}
+
+// Unarchive désarchive un projet
+// @Summary Désarchiver un projet
+// @Description Rend au projet le statut qu'il avait avant l'archivage ; il redevient modifiable et réapparaît dans la liste par défaut. Le désarchivage est journalisé dans l'audit (nécessite projects.archive)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Success 200 {object} project
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/unarchive [post]
+func (h *ProjectHandler) Unarchive(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.archive") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.archive")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	project, err := h.projectService.Unarchive(uint(id), userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, project, "Projet désarchivé avec succès")
+}
+
 // AddBudgetExtension ajoute une extension au budget temps du projet (temps + justification)
 func (h *ProjectHandler) AddBudgetExtension(c *gin.Context) {
 	idParam := c.Param("id")
@@ -284,7 +360,7 @@ func (h *ProjectHandler) AddBudgetExtension(c *gin.Context) {
 		EndDate           *string `json:"end_date,omitempty"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides : temps strictement positif et justification d'au moins 3 caractères requis", err.Error())
+		utils.ValidationErrorResponseWithMessage(c, "Données invalides : temps strictement positif et justification d'au moins 3 caractères requis", err)
 		return
 	}
 
@@ -294,9 +370,9 @@ func (h *ProjectHandler) AddBudgetExtension(c *gin.Context) {
 		return
 	}
 
-	ext, err := h.projectService.AddBudgetExtension(uint(id), req.AdditionalMinutes, strings.TrimSpace(req.Justification), req.StartDate, req.EndDate, userID.(uint))
+	ext, err := h.service(c).AddBudgetExtension(uint(id), req.AdditionalMinutes, strings.TrimSpace(req.Justification), req.StartDate, req.EndDate, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -312,7 +388,7 @@ func (h *ProjectHandler) GetBudgetExtensions(c *gin.Context) {
 		return
 	}
 
-	list, err := h.projectService.GetBudgetExtensions(uint(id))
+	list, err := h.service(c).GetBudgetExtensions(uint(id))
 	if err != nil {
 		utils.InternalServerErrorResponse(c, "Erreur lors de la récupération des extensions")
 		return
@@ -346,7 +422,7 @@ func (h *ProjectHandler) UpdateBudgetExtension(c *gin.Context) {
 		EndDate           *string `json:"end_date,omitempty"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides : temps strictement positif et justification d'au moins 3 caractères requis", err.Error())
+		utils.ValidationErrorResponseWithMessage(c, "Données invalides : temps strictement positif et justification d'au moins 3 caractères requis", err)
 		return
 	}
 	userID, exists := c.Get("user_id")
@@ -354,9 +430,9 @@ func (h *ProjectHandler) UpdateBudgetExtension(c *gin.Context) {
 		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
 		return
 	}
-	ext, err := h.projectService.UpdateBudgetExtension(uint(id), uint(extID), req.AdditionalMinutes, strings.TrimSpace(req.Justification), req.StartDate, req.EndDate, userID.(uint))
+	ext, err := h.service(c).UpdateBudgetExtension(uint(id), uint(extID), req.AdditionalMinutes, strings.TrimSpace(req.Justification), req.StartDate, req.EndDate, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, ext, "Extension modifiée avec succès")
@@ -380,8 +456,8 @@ func (h *ProjectHandler) DeleteBudgetExtension(c *gin.Context) {
 		utils.BadRequestResponse(c, "ID extension invalide")
 		return
 	}
-	if err := h.projectService.DeleteBudgetExtension(uint(id), uint(extID)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).DeleteBudgetExtension(uint(id), uint(extID)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Extension supprimée")
@@ -390,9 +466,9 @@ func (h *ProjectHandler) DeleteBudgetExtension(c *gin.Context) {
 // --- Phases ---
 func (h *ProjectHandler) GetPhases(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	list, err := h.projectService.GetPhases(uint(id))
+	list, err := h.service(c).GetPhases(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, list, "")
@@ -407,15 +483,15 @@ func (h *ProjectHandler) CreatePhase(c *gin.Context) {
 		Status       string `json:"status"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequestResponse(c, "Données invalides")
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	if req.Status == "" {
 		req.Status = "not_started"
 	}
-	p, err := h.projectService.CreatePhase(uint(id), req.Name, req.Description, req.DisplayOrder, req.Status)
+	p, err := h.service(c).CreatePhase(uint(id), req.Name, req.Description, req.DisplayOrder, req.Status)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.CreatedResponse(c, p, "Étape créée")
@@ -430,9 +506,9 @@ func (h *ProjectHandler) UpdatePhase(c *gin.Context) {
 		Status       string `json:"status"`
 	}
 	_ = c.ShouldBindJSON(&req)
-	p, err := h.projectService.UpdatePhase(uint(pid), req.Name, req.Description, req.DisplayOrder, req.Status)
+	p, err := h.service(c).UpdatePhase(uint(pid), req.Name, req.Description, req.DisplayOrder, req.Status)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, p, "Étape mise à jour")
@@ -440,8 +516,8 @@ func (h *ProjectHandler) UpdatePhase(c *gin.Context) {
 
 func (h *ProjectHandler) DeletePhase(c *gin.Context) {
 	pid, _ := strconv.ParseUint(c.Param("phaseId"), 10, 32)
-	if err := h.projectService.DeletePhase(uint(pid)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).DeletePhase(uint(pid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Étape supprimée")
@@ -453,11 +529,11 @@ func (h *ProjectHandler) ReorderPhases(c *gin.Context) {
 		Order []uint `json:"order" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequestResponse(c, "order (tableau d'IDs) requis")
+		utils.ValidationErrorResponseWithMessage(c, "order (tableau d'IDs) requis", err)
 		return
 	}
-	if err := h.projectService.ReorderPhases(uint(id), req.Order); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).ReorderPhases(uint(id), req.Order); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Ordre enregistré")
@@ -466,9 +542,9 @@ func (h *ProjectHandler) ReorderPhases(c *gin.Context) {
 // --- Functions ---
 func (h *ProjectHandler) GetFunctions(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	list, err := h.projectService.GetFunctions(uint(id))
+	list, err := h.service(c).GetFunctions(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, list, "")
@@ -482,16 +558,16 @@ func (h *ProjectHandler) CreateFunction(c *gin.Context) {
 		DisplayOrder int    `json:"display_order"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequestResponse(c, "Données invalides")
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	typeStr := req.Type
 	if typeStr != "direction" && typeStr != "execution" {
 		typeStr = "execution"
 	}
-	f, err := h.projectService.CreateFunction(uint(id), req.Name, typeStr, req.DisplayOrder)
+	f, err := h.service(c).CreateFunction(uint(id), req.Name, typeStr, req.DisplayOrder)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.CreatedResponse(c, f, "Fonction créée")
@@ -505,9 +581,9 @@ func (h *ProjectHandler) UpdateFunction(c *gin.Context) {
 		DisplayOrder *int    `json:"display_order"`
 	}
 	_ = c.ShouldBindJSON(&req)
-	f, err := h.projectService.UpdateFunction(uint(fid), req.Name, req.Type, req.DisplayOrder)
+	f, err := h.service(c).UpdateFunction(uint(fid), req.Name, req.Type, req.DisplayOrder)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, f, "Fonction mise à jour")
@@ -515,8 +591,8 @@ func (h *ProjectHandler) UpdateFunction(c *gin.Context) {
 
 func (h *ProjectHandler) DeleteFunction(c *gin.Context) {
 	fid, _ := strconv.ParseUint(c.Param("functionId"), 10, 32)
-	if err := h.projectService.DeleteFunction(uint(fid)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).DeleteFunction(uint(fid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Fonction supprimée")
@@ -525,9 +601,9 @@ func (h *ProjectHandler) DeleteFunction(c *gin.Context) {
 // --- Members ---
 func (h *ProjectHandler) GetMembers(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	list, err := h.projectService.GetMembers(uint(id))
+	list, err := h.service(c).GetMembers(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, list, "")
@@ -541,7 +617,7 @@ func (h *ProjectHandler) AddMember(c *gin.Context) {
 		ProjectFunctionID *uint  `json:"project_function_id"` // rétrocompat: si function_ids vide, on utilise celui-ci
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequestResponse(c, "user_id requis")
+		utils.ValidationErrorResponseWithMessage(c, "user_id requis", err)
 		return
 	}
 	if req.FunctionIDs == nil {
@@ -550,9 +626,9 @@ func (h *ProjectHandler) AddMember(c *gin.Context) {
 	if len(req.FunctionIDs) == 0 && req.ProjectFunctionID != nil {
 		req.FunctionIDs = []uint{*req.ProjectFunctionID}
 	}
-	m, err := h.projectService.AddMember(uint(id), req.UserID, req.FunctionIDs)
+	m, err := h.service(c).AddMember(uint(id), req.UserID, req.FunctionIDs)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.CreatedResponse(c, m, "Membre ajouté")
@@ -561,8 +637,8 @@ func (h *ProjectHandler) AddMember(c *gin.Context) {
 func (h *ProjectHandler) RemoveMember(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	uid, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
-	if err := h.projectService.RemoveMember(uint(id), uint(uid)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).RemoveMember(uint(id), uint(uid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Membre retiré")
@@ -578,8 +654,8 @@ func (h *ProjectHandler) SetMemberFunction(c *gin.Context) {
 	if req.FunctionIDs == nil {
 		req.FunctionIDs = []uint{}
 	}
-	if err := h.projectService.SetMemberFunctions(uint(id), uint(uid), req.FunctionIDs); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).SetMemberFunctions(uint(id), uint(uid), req.FunctionIDs); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Fonctions mises à jour")
@@ -588,8 +664,8 @@ func (h *ProjectHandler) SetMemberFunction(c *gin.Context) {
 func (h *ProjectHandler) SetProjectManager(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	uid, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
-	if err := h.projectService.SetProjectManager(uint(id), uint(uid)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).SetProjectManager(uint(id), uint(uid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Chef de projet mis à jour")
@@ -598,8 +674,8 @@ func (h *ProjectHandler) SetProjectManager(c *gin.Context) {
 func (h *ProjectHandler) SetLead(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	uid, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
-	if err := h.projectService.SetLead(uint(id), uint(uid)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).SetLead(uint(id), uint(uid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Lead mis à jour")
@@ -608,9 +684,9 @@ func (h *ProjectHandler) SetLead(c *gin.Context) {
 // --- Phase members ---
 func (h *ProjectHandler) GetPhaseMembers(c *gin.Context) {
 	pid, _ := strconv.ParseUint(c.Param("phaseId"), 10, 32)
-	list, err := h.projectService.GetPhaseMembers(uint(pid))
+	list, err := h.service(c).GetPhaseMembers(uint(pid))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, list, "")
@@ -623,12 +699,12 @@ func (h *ProjectHandler) AddPhaseMember(c *gin.Context) {
 		ProjectFunctionID  *uint `json:"project_function_id"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequestResponse(c, "user_id requis")
+		utils.ValidationErrorResponseWithMessage(c, "user_id requis", err)
 		return
 	}
-	m, err := h.projectService.AddPhaseMember(uint(pid), req.UserID, req.ProjectFunctionID)
+	m, err := h.service(c).AddPhaseMember(uint(pid), req.UserID, req.ProjectFunctionID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.CreatedResponse(c, m, "Membre ajouté à l'étape")
@@ -637,8 +713,8 @@ func (h *ProjectHandler) AddPhaseMember(c *gin.Context) {
 func (h *ProjectHandler) RemovePhaseMember(c *gin.Context) {
 	pid, _ := strconv.ParseUint(c.Param("phaseId"), 10, 32)
 	uid, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
-	if err := h.projectService.RemovePhaseMember(uint(pid), uint(uid)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).RemovePhaseMember(uint(pid), uint(uid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Membre retiré de l'étape")
@@ -651,8 +727,8 @@ func (h *ProjectHandler) SetPhaseMemberFunction(c *gin.Context) {
 		ProjectFunctionID *uint `json:"project_function_id"`
 	}
 	_ = c.ShouldBindJSON(&req)
-	if err := h.projectService.SetPhaseMemberFunction(uint(pid), uint(uid), req.ProjectFunctionID); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).SetPhaseMemberFunction(uint(pid), uint(uid), req.ProjectFunctionID); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Fonction mise à jour")
@@ -661,9 +737,9 @@ func (h *ProjectHandler) SetPhaseMemberFunction(c *gin.Context) {
 // --- Tasks ---
 func (h *ProjectHandler) GetTasks(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	list, err := h.projectService.GetTasks(uint(id))
+	list, err := h.service(c).GetTasks(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, list, "")
@@ -671,9 +747,9 @@ func (h *ProjectHandler) GetTasks(c *gin.Context) {
 
 func (h *ProjectHandler) GetTasksByPhase(c *gin.Context) {
 	pid, _ := strconv.ParseUint(c.Param("phaseId"), 10, 32)
-	list, err := h.projectService.GetTasksByPhase(uint(pid))
+	list, err := h.service(c).GetTasksByPhase(uint(pid))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, list, "")
@@ -693,15 +769,15 @@ func (h *ProjectHandler) CreateTask(c *gin.Context) {
 		DueDate        *string  `json:"due_date"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequestResponse(c, "project_phase_id et title requis")
+		utils.ValidationErrorResponseWithMessage(c, "project_phase_id et title requis", err)
 		return
 	}
 	if req.AssigneeIDs == nil {
 		req.AssigneeIDs = []uint{}
 	}
-	t, err := h.projectService.CreateTask(uint(id), req.ProjectPhaseID, userID.(uint), req.Title, req.Description, req.Status, req.Priority, req.AssigneeIDs, req.EstimatedTime, req.DueDate)
+	t, err := h.service(c).CreateTask(uint(id), req.ProjectPhaseID, userID.(uint), req.Title, req.Description, req.Status, req.Priority, req.AssigneeIDs, req.EstimatedTime, req.DueDate)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.CreatedResponse(c, t, "Tâche créée")
@@ -721,12 +797,12 @@ func (h *ProjectHandler) UpdateTask(c *gin.Context) {
 		ProjectPhaseID *uint    `json:"project_phase_id"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
-	t, err := h.projectService.UpdateTask(uint(tid), req.Title, req.Description, req.Status, req.Priority, req.AssigneeIDs, req.EstimatedTime, req.ActualTime, req.DueDate, req.ProjectPhaseID)
+	t, err := h.service(c).UpdateTask(uint(tid), req.Title, req.Description, req.Status, req.Priority, req.AssigneeIDs, req.EstimatedTime, req.ActualTime, req.DueDate, req.ProjectPhaseID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, t, "Tâche mise à jour")
@@ -734,9 +810,161 @@ func (h *ProjectHandler) UpdateTask(c *gin.Context) {
 
 func (h *ProjectHandler) DeleteTask(c *gin.Context) {
 	tid, _ := strconv.ParseUint(c.Param("taskId"), 10, 32)
-	if err := h.projectService.DeleteTask(uint(tid)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	if err := h.service(c).DeleteTask(uint(tid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Tâche supprimée")
 }
+
+// MoveTask déplace une tâche sur le tableau : colonne (statut, inchangé si absent) et position dans la colonne (0 = haut)
+func (h *ProjectHandler) MoveTask(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	tid, _ := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	var req struct {
+		Status   string `json:"status"`
+		Position *int   `json:"position" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponseWithMessage(c, "position requise", err)
+		return
+	}
+	t, err := h.service(c).MoveTask(uint(id), uint(tid), req.Status, *req.Position)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, t, "Tâche déplacée")
+}
+
+// GetTaskDependencies liste les tâches prérequises d'une tâche
+func (h *ProjectHandler) GetTaskDependencies(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	tid, _ := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	list, err := h.service(c).GetTaskDependencies(uint(id), uint(tid))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, list, "")
+}
+
+// AddTaskDependency ajoute un prérequis à une tâche (la tâche est bloquée tant qu'il n'est pas clôturé)
+func (h *ProjectHandler) AddTaskDependency(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	tid, _ := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	userID, _ := c.Get("user_id")
+	var req struct {
+		DependsOnID uint `json:"depends_on_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponseWithMessage(c, "depends_on_id requis", err)
+		return
+	}
+	t, err := h.service(c).AddTaskDependency(uint(id), uint(tid), req.DependsOnID, userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.CreatedResponse(c, t, "Dépendance ajoutée")
+}
+
+// RemoveTaskDependency retire un prérequis d'une tâche
+func (h *ProjectHandler) RemoveTaskDependency(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	tid, _ := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	did, _ := strconv.ParseUint(c.Param("dependsOnId"), 10, 32)
+	t, err := h.service(c).RemoveTaskDependency(uint(id), uint(tid), uint(did))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, t, "Dépendance supprimée")
+}
+
+// --- Milestones ---
+
+// GetMilestones liste les jalons d'un projet, par échéance
+func (h *ProjectHandler) GetMilestones(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	list, err := h.service(c).GetMilestones(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, list, "")
+}
+
+// CreateMilestone crée un jalon (due_date au format YYYY-MM-DD)
+func (h *ProjectHandler) CreateMilestone(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	userID, _ := c.Get("user_id")
+	var req struct {
+		Name               string `json:"name" binding:"required"`
+		Description        string `json:"description"`
+		CompletionCriteria string `json:"completion_criteria"`
+		DueDate            string `json:"due_date" binding:"required"`
+		ProjectPhaseID     *uint  `json:"project_phase_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponseWithMessage(c, "name et due_date requis", err)
+		return
+	}
+	m, err := h.service(c).CreateMilestone(uint(id), userID.(uint), req.Name, req.Description, req.CompletionCriteria, req.DueDate, req.ProjectPhaseID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.CreatedResponse(c, m, "Jalon créé")
+}
+
+// UpdateMilestone met à jour un jalon (project_phase_id à 0 retire le lien avec l'étape)
+func (h *ProjectHandler) UpdateMilestone(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	mid, _ := strconv.ParseUint(c.Param("milestoneId"), 10, 32)
+	userID, _ := c.Get("user_id")
+	var req struct {
+		Name               *string `json:"name"`
+		Description        *string `json:"description"`
+		CompletionCriteria *string `json:"completion_criteria"`
+		DueDate            *string `json:"due_date"`
+		Status             *string `json:"status"`
+		ProjectPhaseID     *uint   `json:"project_phase_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+	m, err := h.service(c).UpdateMilestone(uint(id), uint(mid), userID.(uint), req.Name, req.Description, req.CompletionCriteria, req.DueDate, req.Status, req.ProjectPhaseID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, m, "Jalon mis à jour")
+}
+
+// DeleteMilestone supprime un jalon
+func (h *ProjectHandler) DeleteMilestone(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	mid, _ := strconv.ParseUint(c.Param("milestoneId"), 10, 32)
+	if err := h.service(c).DeleteMilestone(uint(id), uint(mid)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, nil, "Jalon supprimé")
+}
+
+// GetMilestoneDashboard synthèse des jalons à venir (?days=30) et en retard sur les projets visibles
+func (h *ProjectHandler) GetMilestoneDashboard(c *gin.Context) {
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+	days, _ := strconv.Atoi(c.Query("days"))
+	dashboard, err := h.service(c).GetMilestoneDashboard(queryScope, days)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, dashboard, "")
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ProjectTaskRecurrenceHandler gère les règles de récurrence des tâches de projet
+type ProjectTaskRecurrenceHandler struct {
+	recurrenceService services.ProjectTaskRecurrenceService
+}
+
+// NewProjectTaskRecurrenceHandler crée une nouvelle instance de ProjectTaskRecurrenceHandler
+func NewProjectTaskRecurrenceHandler(recurrenceService services.ProjectTaskRecurrenceService) *ProjectTaskRecurrenceHandler {
+	return &ProjectTaskRecurrenceHandler{
+		recurrenceService: recurrenceService,
+	}
+}
+
+// service retourne le service des récurrences pour la requête : avec projects.archived.edit (administrateurs),
+// les règles des projets archivés restent modifiables
+func (h *ProjectTaskRecurrenceHandler) service(c *gin.Context) services.ProjectTaskRecurrenceService {
+	if utils.RequirePermission(c, "projects.archived.edit") {
+		return h.recurrenceService.AllowArchivedWrites()
+	}
+	return h.recurrenceService
+}
+
+// parseProjectAndChildID lit l'ID du projet et l'ID de l'élément enfant passés dans le chemin
+func parseProjectAndChildID(c *gin.Context, childParam string) (uint, uint, bool) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, 0, false
+	}
+	childID, err := strconv.ParseUint(c.Param(childParam), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, 0, false
+	}
+	return uint(projectID), uint(childID), true
+}
+
+// GetByProject récupère les règles de récurrence d'un projet
+// @Summary Lister les tâches récurrentes d'un projet
+// @Description Récupère les règles de récurrence des tâches du projet, avec leur prochaine occurrence (nécessite projects.tasks.view)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Success 200 {array} models.ProjectTaskRecurrence
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/task-recurrences [get]
+func (h *ProjectTaskRecurrenceHandler) GetByProject(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.view")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	recurrences, err := h.service(c).GetByProject(uint(projectID))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, recurrences, "Récurrences récupérées avec succès")
+}
+
+// Create rend une tâche récurrente
+// @Summary Rendre une tâche récurrente
+// @Description Crée une règle de récurrence (cron ou RRULE) à partir de la tâche : son titre, sa description, son étape, sa priorité, son estimation et ses assignés servent de modèle. Chaque occurrence est créée lead_days jours avant sa date, échue à cette date, et expire à l'occurrence suivante ; une occurrence expirée non clôturée est clôturée automatiquement si close_expired (nécessite projects.tasks.create)
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param taskId path int true "ID de la tâche"
+// @Param request body dto.CreateProjectTaskRecurrenceRequest true "Règle de récurrence"
+// @Success 201 {object} models.ProjectTaskRecurrence
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/tasks/{taskId}/recurrence [post]
+func (h *ProjectTaskRecurrenceHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.create")
+		return
+	}
+
+	projectID, taskID, ok := parseProjectAndChildID(c, "taskId")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateProjectTaskRecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	recurrence, err := h.service(c).Create(projectID, taskID, createdByID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, recurrence, "Récurrence créée avec succès")
+}
+
+// Update met à jour une règle de récurrence
+// @Summary Mettre à jour une tâche récurrente
+// @Description Met à jour la planification ou le modèle des prochaines occurrences ; la prochaine occurrence est recalculée sans recréer les occurrences déjà créées (nécessite projects.tasks.update)
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param recurrenceId path int true "ID de la règle"
+// @Param request body dto.UpdateProjectTaskRecurrenceRequest true "Données à mettre à jour"
+// @Success 200 {object} models.ProjectTaskRecurrence
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/task-recurrences/{recurrenceId} [put]
+func (h *ProjectTaskRecurrenceHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.update")
+		return
+	}
+
+	projectID, recurrenceID, ok := parseProjectAndChildID(c, "recurrenceId")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateProjectTaskRecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	recurrence, err := h.service(c).Update(projectID, recurrenceID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, recurrence, "Récurrence mise à jour avec succès")
+}
+
+// Delete supprime une règle de récurrence
+// @Summary Supprimer une tâche récurrente
+// @Description Supprime la règle ; les occurrences déjà créées sont conservées et perdent leur badge de récurrence (nécessite projects.tasks.delete)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param recurrenceId path int true "ID de la règle"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/task-recurrences/{recurrenceId} [delete]
+func (h *ProjectTaskRecurrenceHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.delete") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.delete")
+		return
+	}
+
+	projectID, recurrenceID, ok := parseProjectAndChildID(c, "recurrenceId")
+	if !ok {
+		return
+	}
+
+	if err := h.service(c).Delete(projectID, recurrenceID); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Récurrence supprimée avec succès")
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ProjectTaskTimerHandler gère la saisie du temps des tâches de projet par chronomètre
+type ProjectTaskTimerHandler struct {
+	timerService services.ProjectTaskTimerService
+}
+
+// NewProjectTaskTimerHandler crée une nouvelle instance de ProjectTaskTimerHandler
+func NewProjectTaskTimerHandler(timerService services.ProjectTaskTimerService) *ProjectTaskTimerHandler {
+	return &ProjectTaskTimerHandler{
+		timerService: timerService,
+	}
+}
+
+// service retourne le service des chronomètres pour la requête : avec projects.archived.edit (administrateurs),
+// le temps reste saisissable sur les projets archivés
+func (h *ProjectTaskTimerHandler) service(c *gin.Context) services.ProjectTaskTimerService {
+	if utils.RequirePermission(c, "projects.archived.edit") {
+		return h.timerService.AllowArchivedWrites()
+	}
+	return h.timerService
+}
+
+// Start démarre un chronomètre sur une tâche
+// @Summary Démarrer le chronomètre d'une tâche
+// @Description Démarre un chronomètre sur la tâche depuis le tableau ; un seul chronomètre en cours par utilisateur (409 si un autre est déjà démarré). L'entrée de temps est créée à l'arrêt (nécessite projects.tasks.time.create)
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param taskId path int true "ID de la tâche"
+// @Param request body dto.StartProjectTaskTimerRequest false "Description de l'entrée de temps"
+// @Success 201 {object} dto.ProjectTaskTimerDTO
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /projects/{id}/tasks/{taskId}/time/start [post]
+func (h *ProjectTaskTimerHandler) Start(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.time.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.time.create")
+		return
+	}
+
+	projectID, taskID, ok := parseProjectAndChildID(c, "taskId")
+	if !ok {
+		return
+	}
+
+	var req dto.StartProjectTaskTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	timer, err := h.service(c).Start(projectID, taskID, userID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, timer, "Chronomètre démarré avec succès")
+}
+
+// Stop arrête le chronomètre d'une tâche
+// @Summary Arrêter le chronomètre d'une tâche
+// @Description Arrête le chronomètre en cours sur la tâche et crée l'entrée de temps : durée arrondie à la minute (au moins 1), datée du jour de démarrage, ajoutée au temps passé de la tâche et au temps consommé du projet (nécessite projects.tasks.time.create)
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param taskId path int true "ID de la tâche"
+// @Param request body dto.StopProjectTaskTimerRequest false "Description de l'entrée de temps (remplace celle du démarrage)"
+// @Success 200 {object} dto.StoppedProjectTaskTimerDTO
+// @Failure 409 {object} utils.Response
+// @Router /projects/{id}/tasks/{taskId}/time/stop [post]
+func (h *ProjectTaskTimerHandler) Stop(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.time.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.time.create")
+		return
+	}
+
+	projectID, taskID, ok := parseProjectAndChildID(c, "taskId")
+	if !ok {
+		return
+	}
+
+	var req dto.StopProjectTaskTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	stopped, err := h.service(c).Stop(projectID, taskID, userID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, stopped, "Chronomètre arrêté, temps enregistré")
+}
+
+// GetCurrent retourne le chronomètre en cours de l'utilisateur connecté
+// @Summary Chronomètre en cours
+// @Description Retourne le chronomètre en cours de l'utilisateur connecté (tâche, projet, temps écoulé) ; data est null s'il n'y en a pas
+// @Tags timesheet
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ProjectTaskTimerDTO
+// @Router /timesheet/timer [get]
+func (h *ProjectTaskTimerHandler) GetCurrent(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	timer, err := h.timerService.GetCurrent(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+	if timer == nil {
+		utils.SuccessResponse(c, nil, "Aucun chronomètre en cours")
+		return
+	}
+
+	utils.SuccessResponse(c, timer, "Chronomètre récupéré avec succès")
+}
+
+// Discard abandonne le chronomètre en cours de l'utilisateur connecté
+// @Summary Abandonner le chronomètre en cours
+// @Description Supprime le chronomètre en cours de l'utilisateur connecté sans saisir de temps (sans effet s'il n'y en a pas)
+// @Tags timesheet
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /timesheet/timer [delete]
+func (h *ProjectTaskTimerHandler) Discard(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.timerService.Discard(userID); err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Chronomètre abandonné")
+}
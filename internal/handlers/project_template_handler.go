@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ProjectTemplateHandler gère les modèles de projet et la création de projets depuis un modèle
+type ProjectTemplateHandler struct {
+	projectTemplateService services.ProjectTemplateService
+}
+
+// NewProjectTemplateHandler crée une nouvelle instance de ProjectTemplateHandler
+func NewProjectTemplateHandler(projectTemplateService services.ProjectTemplateService) *ProjectTemplateHandler {
+	return &ProjectTemplateHandler{
+		projectTemplateService: projectTemplateService,
+	}
+}
+
+// GetAll récupère les modèles de projet
+// @Summary Lister les modèles de projet
+// @Description Récupère les modèles de projet avec leurs étapes, fonctions, membres et tâches par défaut
+// @Tags project-templates
+// @Security BearerAuth
+// @Produce json
+// @Param active query bool false "Modèles actifs uniquement"
+// @Success 200 {array} models.ProjectTemplate
+// @Router /project-templates [get]
+func (h *ProjectTemplateHandler) GetAll(c *gin.Context) {
+	activeOnly := false
+	if v := c.Query("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre active invalide")
+			return
+		}
+		activeOnly = active
+	}
+
+	templates, err := h.projectTemplateService.GetAll(activeOnly)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, templates, "Modèles de projet récupérés avec succès")
+}
+
+// GetByID récupère un modèle de projet par son ID
+// @Summary Récupérer un modèle de projet
+// @Description Récupère un modèle de projet et son contenu
+// @Tags project-templates
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du modèle"
+// @Success 200 {object} models.ProjectTemplate
+// @Failure 404 {object} utils.Response
+// @Router /project-templates/{id} [get]
+func (h *ProjectTemplateHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	template, err := h.projectTemplateService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, template, "Modèle de projet récupéré avec succès")
+}
+
+// Create crée un modèle de projet
+// @Summary Créer un modèle de projet
+// @Description Crée un modèle : étapes (début et durée en jours relatifs au début du projet), fonctions, membres par défaut (user_id absent = créateur du projet, fonction, chef de projet, lead) et squelette de tâches (étape, fonction assignée et échéance relative). Membres et tâches référencent étapes et fonctions par leur rang dans la requête (nécessite projects.templates.manage)
+// @Tags project-templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.SaveProjectTemplateRequest true "Modèle de projet"
+// @Success 201 {object} models.ProjectTemplate
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /project-templates [post]
+func (h *ProjectTemplateHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.templates.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.templates.manage")
+		return
+	}
+
+	var req dto.SaveProjectTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	template, err := h.projectTemplateService.Create(req, createdByID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, template, "Modèle de projet créé avec succès")
+}
+
+// Update remplace un modèle de projet
+// @Summary Mettre à jour un modèle de projet
+// @Description Met à jour un modèle ; les étapes, fonctions, membres et tâches fournis remplacent le contenu existant. Les projets déjà créés depuis le modèle ne sont pas modifiés (nécessite projects.templates.manage)
+// @Tags project-templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du modèle"
+// @Param request body dto.SaveProjectTemplateRequest true "Modèle de projet"
+// @Success 200 {object} models.ProjectTemplate
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /project-templates/{id} [put]
+func (h *ProjectTemplateHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.templates.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.templates.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.SaveProjectTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template, err := h.projectTemplateService.Update(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, template, "Modèle de projet mis à jour avec succès")
+}
+
+// Delete supprime un modèle de projet
+// @Summary Supprimer un modèle de projet
+// @Description Supprime un modèle ; les projets déjà créés depuis le modèle sont conservés (nécessite projects.templates.manage)
+// @Tags project-templates
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du modèle"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /project-templates/{id} [delete]
+func (h *ProjectTemplateHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.templates.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.templates.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.projectTemplateService.Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Modèle de projet supprimé avec succès")
+}
+
+// CreateProject crée un projet depuis un modèle
+// @Summary Créer un projet depuis un modèle
+// @Description Crée le projet puis ses fonctions (reprises par nom si elles existent déjà), ses étapes datées depuis start_date, ses membres par défaut (chef de projet, lead) et ses tâches, assignées aux membres ayant la fonction de la tâche et échues à start_date + décalage. La date de fin prévue est fixée par la durée du modèle. Si une étape échoue, le projet est supprimé (nécessite projects.create)
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du modèle"
+// @Param request body dto.CreateProjectFromTemplateRequest true "Projet à créer"
+// @Success 201 {object} models.Project
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/from-template/{id} [post]
+func (h *ProjectTemplateHandler) CreateProject(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.create")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.CreateProjectFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	project, err := h.projectTemplateService.Instantiate(uint(id), createdByID, req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, project, "Projet créé depuis le modèle avec succès")
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// PushHandler gère l'enregistrement des appareils mobiles aux notifications push
+type PushHandler struct {
+	pushService services.PushService
+}
+
+// NewPushHandler crée une nouvelle instance de PushHandler
+func NewPushHandler(pushService services.PushService) *PushHandler {
+	return &PushHandler{
+		pushService: pushService,
+	}
+}
+
+// pushErrorResponse traduit une erreur du service en réponse HTTP
+func pushErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasSuffix(err.Error(), "non configuré"):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// sessionTokenHash retourne le hash du jeton d'authentification de la requête (identifie la session)
+func sessionTokenHash(c *gin.Context) string {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" || token == c.GetHeader("Authorization") {
+		return ""
+	}
+	return utils.HashString(token)
+}
+
+// GetDevices liste les appareils de l'utilisateur connecté
+// @Summary Lister mes appareils push
+// @Description Liste les appareils enregistrés aux notifications push ; current signale l'appareil de la session courante
+// @Tags push
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.PushDeviceDTO
+// @Router /push/devices [get]
+func (h *PushHandler) GetDevices(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	devices, err := h.pushService.GetDevices(userID, sessionTokenHash(c))
+	if err != nil {
+		pushErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, devices, "Appareils récupérés avec succès")
+}
+
+// RegisterDevice enregistre le jeton push de l'appareil
+// @Summary Enregistrer un appareil push
+// @Description Enregistre le jeton FCM ou APNs de l'appareil pour l'utilisateur connecté (à rappeler à chaque renouvellement du jeton). L'appareil est lié à la session : il ne reçoit plus de notifications après la déconnexion
+// @Tags push
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.RegisterPushDeviceRequest true "Jeton de l'appareil"
+// @Success 200 {object} dto.PushDeviceDTO
+// @Failure 400 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /push/devices [post]
+func (h *PushHandler) RegisterDevice(c *gin.Context) {
+	var req dto.RegisterPushDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	device, err := h.pushService.RegisterDevice(userID, sessionTokenHash(c), req)
+	if err != nil {
+		pushErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, device, "Appareil enregistré")
+}
+
+// DeleteDevice supprime un appareil de l'utilisateur connecté
+// @Summary Supprimer un appareil push
+// @Description L'appareil ne reçoit plus de notifications push
+// @Tags push
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'appareil"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /push/devices/{id} [delete]
+func (h *PushHandler) DeleteDevice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.pushService.DeleteDevice(uint(id), userID); err != nil {
+		pushErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Appareil supprimé")
+}
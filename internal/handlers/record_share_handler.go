@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// RecordShareHandler gère les handlers des partages de tickets et de projets
+type RecordShareHandler struct {
+	shareService services.RecordShareService
+}
+
+// NewRecordShareHandler crée une nouvelle instance de RecordShareHandler
+func NewRecordShareHandler(shareService services.RecordShareService) *RecordShareHandler {
+	return &RecordShareHandler{
+		shareService: shareService,
+	}
+}
+
+// GetTicketShares récupère les partages d'un ticket
+// @Summary Partages d'un ticket
+// @Description Liste les partages d'un ticket (utilisateurs et départements, y compris expirés). Nécessite tickets.share
+// @Tags shares
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 200 {array} dto.RecordShareDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/shares [get]
+func (h *RecordShareHandler) GetTicketShares(c *gin.Context) {
+	h.getShares(c, models.ShareResourceTicket, "tickets.share")
+}
+
+// ShareTicket partage un ticket avec un utilisateur ou un département
+// @Summary Partager un ticket
+// @Description Partage un ticket en lecture (read) ou lecture-écriture (write) avec un utilisateur ou un département, avec expiration optionnelle. Un partage existant pour le même bénéficiaire est mis à jour. Nécessite tickets.share
+// @Tags shares
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param request body dto.CreateRecordShareRequest true "Partage"
+// @Success 201 {object} dto.RecordShareDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/shares [post]
+func (h *RecordShareHandler) ShareTicket(c *gin.Context) {
+	h.share(c, models.ShareResourceTicket, "tickets.share")
+}
+
+// UpdateTicketShare modifie un partage de ticket
+// @Summary Modifier un partage de ticket
+// @Description Modifie le niveau d'accès ou l'expiration d'un partage. Nécessite tickets.share
+// @Tags shares
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param shareId path int true "ID du partage"
+// @Param request body dto.UpdateRecordShareRequest true "Modifications"
+// @Success 200 {object} dto.RecordShareDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/shares/{shareId} [put]
+func (h *RecordShareHandler) UpdateTicketShare(c *gin.Context) {
+	h.updateShare(c, models.ShareResourceTicket, "tickets.share")
+}
+
+// RevokeTicketShare supprime un partage de ticket
+// @Summary Révoquer un partage de ticket
+// @Description Supprime un partage. Nécessite tickets.share
+// @Tags shares
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param shareId path int true "ID du partage"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/shares/{shareId} [delete]
+func (h *RecordShareHandler) RevokeTicketShare(c *gin.Context) {
+	h.revokeShare(c, models.ShareResourceTicket, "tickets.share")
+}
+
+// GetProjectShares récupère les partages d'un projet
+// @Summary Partages d'un projet
+// @Description Liste les partages d'un projet (utilisateurs et départements, y compris expirés). Nécessite projects.share
+// @Tags shares
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Success 200 {array} dto.RecordShareDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/shares [get]
+func (h *RecordShareHandler) GetProjectShares(c *gin.Context) {
+	h.getShares(c, models.ShareResourceProject, "projects.share")
+}
+
+// ShareProject partage un projet avec un utilisateur ou un département
+// @Summary Partager un projet
+// @Description Partage un projet en lecture (read) ou lecture-écriture (write) avec un utilisateur ou un département, avec expiration optionnelle. Nécessite projects.share
+// @Tags shares
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param request body dto.CreateRecordShareRequest true "Partage"
+// @Success 201 {object} dto.RecordShareDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/shares [post]
+func (h *RecordShareHandler) ShareProject(c *gin.Context) {
+	h.share(c, models.ShareResourceProject, "projects.share")
+}
+
+// UpdateProjectShare modifie un partage de projet
+// @Summary Modifier un partage de projet
+// @Description Modifie le niveau d'accès ou l'expiration d'un partage. Nécessite projects.share
+// @Tags shares
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param shareId path int true "ID du partage"
+// @Param request body dto.UpdateRecordShareRequest true "Modifications"
+// @Success 200 {object} dto.RecordShareDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/shares/{shareId} [put]
+func (h *RecordShareHandler) UpdateProjectShare(c *gin.Context) {
+	h.updateShare(c, models.ShareResourceProject, "projects.share")
+}
+
+// RevokeProjectShare supprime un partage de projet
+// @Summary Révoquer un partage de projet
+// @Description Supprime un partage. Nécessite projects.share
+// @Tags shares
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param shareId path int true "ID du partage"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/shares/{shareId} [delete]
+func (h *RecordShareHandler) RevokeProjectShare(c *gin.Context) {
+	h.revokeShare(c, models.ShareResourceProject, "projects.share")
+}
+
+// GetSharedWithMe récupère les tickets et projets partagés avec l'utilisateur connecté
+// @Summary Éléments partagés avec moi
+// @Description Liste les partages actifs dont bénéficie l'utilisateur (directement ou via son département)
+// @Tags shares
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.RecordShareDTO
+// @Failure 401 {object} utils.Response
+// @Router /shares/with-me [get]
+func (h *RecordShareHandler) GetSharedWithMe(c *gin.Context) {
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	shares, err := h.shareService.GetSharedWithMe(queryScope.UserID, queryScope.DepartmentID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, shares, "Partages récupérés avec succès")
+}
+
+// getShares liste les partages d'une ressource
+func (h *RecordShareHandler) getShares(c *gin.Context, resourceType, permission string) {
+	if !utils.RequirePermission(c, permission) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: "+permission)
+		return
+	}
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	shares, err := h.shareService.GetShares(resourceType, uint(resourceID), utils.GetScopeFromContext(c))
+	if err != nil {
+		shareErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, shares, "Partages récupérés avec succès")
+}
+
+// share crée ou met à jour le partage d'une ressource
+func (h *RecordShareHandler) share(c *gin.Context, resourceType, permission string) {
+	if !utils.RequirePermission(c, permission) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: "+permission)
+		return
+	}
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.CreateRecordShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	sharedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	share, err := h.shareService.Share(resourceType, uint(resourceID), req, utils.GetScopeFromContext(c), sharedByID.(uint))
+	if err != nil {
+		shareErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, share, "Partage enregistré avec succès")
+}
+
+// updateShare modifie un partage
+func (h *RecordShareHandler) updateShare(c *gin.Context, resourceType, permission string) {
+	if !utils.RequirePermission(c, permission) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: "+permission)
+		return
+	}
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	shareID, err := strconv.ParseUint(c.Param("shareId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de partage invalide")
+		return
+	}
+
+	var req dto.UpdateRecordShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	share, err := h.shareService.UpdateShare(resourceType, uint(resourceID), uint(shareID), req, utils.GetScopeFromContext(c))
+	if err != nil {
+		shareErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, share, "Partage mis à jour avec succès")
+}
+
+// revokeShare supprime un partage
+func (h *RecordShareHandler) revokeShare(c *gin.Context, resourceType, permission string) {
+	if !utils.RequirePermission(c, permission) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: "+permission)
+		return
+	}
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	shareID, err := strconv.ParseUint(c.Param("shareId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de partage invalide")
+		return
+	}
+
+	if err := h.shareService.RevokeShare(resourceType, uint(resourceID), uint(shareID), utils.GetScopeFromContext(c)); err != nil {
+		shareErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Partage révoqué avec succès")
+}
+
+// shareErrorResponse associe les erreurs du service de partage au statut HTTP
+func shareErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch err.Error() {
+	case "ressource introuvable", "partage introuvable", "utilisateur introuvable", "département introuvable":
+		utils.NotFoundResponse(c, err.Error())
+	case "erreur lors de la vérification des droits d'accès", "erreur lors de la récupération des partages",
+		"erreur lors de la création du partage", "erreur lors de la mise à jour du partage",
+		"erreur lors de la suppression du partage", "erreur lors de la récupération du partage":
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
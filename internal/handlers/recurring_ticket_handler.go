@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// RecurringTicketHandler gère les handlers des tickets récurrents (création planifiée de tickets)
+type RecurringTicketHandler struct {
+	recurringTicketService services.RecurringTicketService
+}
+
+// NewRecurringTicketHandler crée une nouvelle instance de RecurringTicketHandler
+func NewRecurringTicketHandler(recurringTicketService services.RecurringTicketService) *RecurringTicketHandler {
+	return &RecurringTicketHandler{
+		recurringTicketService: recurringTicketService,
+	}
+}
+
+// GetAll récupère les tickets récurrents
+// @Summary Lister les tickets récurrents
+// @Description Récupère les planifications de création automatique de tickets, avec leur prochaine échéance (nécessite tickets.recurring.manage)
+// @Tags recurring-tickets
+// @Security BearerAuth
+// @Produce json
+// @Param filiale_id query int false "Filiale"
+// @Success 200 {array} dto.RecurringTicketDTO
+// @Failure 403 {object} utils.Response
+// @Router /recurring-tickets [get]
+func (h *RecurringTicketHandler) GetAll(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.recurring.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.recurring.manage")
+		return
+	}
+
+	var filialeID *uint
+	if v := c.Query("filiale_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID de la filiale invalide")
+			return
+		}
+		f := uint(id)
+		filialeID = &f
+	}
+
+	recurrings, err := h.recurringTicketService.GetAll(filialeID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, recurrings, "Tickets récurrents récupérés avec succès")
+}
+
+// GetByID récupère un ticket récurrent par son ID
+// @Summary Récupérer un ticket récurrent
+// @Description Récupère une planification de tickets par son identifiant (nécessite tickets.recurring.manage)
+// @Tags recurring-tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket récurrent"
+// @Success 200 {object} dto.RecurringTicketDTO
+// @Failure 404 {object} utils.Response
+// @Router /recurring-tickets/{id} [get]
+func (h *RecurringTicketHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.recurring.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.recurring.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	recurring, err := h.recurringTicketService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, recurring, "Ticket récurrent récupéré avec succès")
+}
+
+// Create crée un ticket récurrent
+// @Summary Créer un ticket récurrent
+// @Description Planifie la création automatique de tickets (ex: contrôle mensuel des sauvegardes). schedule accepte une expression cron (« 0 8 1 * * », « @monthly ») ou une règle RRULE (« FREQ=MONTHLY;BYMONTHDAY=1;BYHOUR=8;BYMINUTE=0 », FREQ DAILY/WEEKLY/MONTHLY/YEARLY avec INTERVAL, BYMONTH, BYMONTHDAY, BYDAY, BYHOUR, BYMINUTE, UNTIL ou COUNT), évaluée dans le fuseau indiqué. Les tickets sont créés au nom du créateur de la planification, assignés aux assignés configurés et rattachés au projet. Les échéances manquées ne sont pas rattrapées (nécessite tickets.recurring.manage)
+// @Tags recurring-tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateRecurringTicketRequest true "Planification et modèle de ticket"
+// @Success 201 {object} dto.RecurringTicketDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /recurring-tickets [post]
+func (h *RecurringTicketHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.recurring.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.recurring.manage")
+		return
+	}
+
+	var req dto.CreateRecurringTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	recurring, err := h.recurringTicketService.Create(req, createdByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, recurring, "Ticket récurrent créé avec succès")
+}
+
+// Update met à jour un ticket récurrent
+// @Summary Mettre à jour un ticket récurrent
+// @Description Met à jour une planification ; la prochaine échéance est recalculée à partir de maintenant et la liste des assignés fournie remplace l'existante (nécessite tickets.recurring.manage)
+// @Tags recurring-tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket récurrent"
+// @Param request body dto.UpdateRecurringTicketRequest true "Données à mettre à jour"
+// @Success 200 {object} dto.RecurringTicketDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /recurring-tickets/{id} [put]
+func (h *RecurringTicketHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.recurring.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.recurring.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateRecurringTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	recurring, err := h.recurringTicketService.Update(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, recurring, "Ticket récurrent mis à jour avec succès")
+}
+
+// Delete supprime un ticket récurrent
+// @Summary Supprimer un ticket récurrent
+// @Description Supprime une planification ; les tickets déjà créés sont conservés (nécessite tickets.recurring.manage)
+// @Tags recurring-tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket récurrent"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /recurring-tickets/{id} [delete]
+func (h *RecurringTicketHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.recurring.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.recurring.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.recurringTicketService.Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Ticket récurrent supprimé avec succès")
+}
+
+// Run crée immédiatement un ticket à partir d'un ticket récurrent
+// @Summary Exécuter un ticket récurrent
+// @Description Crée immédiatement un ticket à partir du modèle, sans décaler la prochaine échéance (les échéances sont traitées chaque minute par la tâche planifiée recurring_tickets) (nécessite tickets.recurring.manage)
+// @Tags recurring-tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket récurrent"
+// @Success 201 {object} dto.TicketDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /recurring-tickets/{id}/run [post]
+func (h *RecurringTicketHandler) Run(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.recurring.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.recurring.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	ticket, err := h.recurringTicketService.RunNow(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, ticket, "Ticket créé avec succès")
+}
@@ -37,7 +37,6 @@ func (h *ReportHandler) GetDashboard(c *gin.Context) {
 	period := c.DefaultQuery("period", "month")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	dashboard, err := h.reportService.GetDashboard(queryScope, period)
 	if err != nil {
@@ -63,7 +62,6 @@ func (h *ReportHandler) GetTicketCountReport(c *gin.Context) {
 	period := c.DefaultQuery("period", "month")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	report, err := h.reportService.GetTicketCountReport(queryScope, period)
 	if err != nil {
@@ -86,7 +84,6 @@ func (h *ReportHandler) GetTicketCountReport(c *gin.Context) {
 // @Router /reports/tickets/distribution [get]
 func (h *ReportHandler) GetTicketTypeDistribution(c *gin.Context) {
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	distribution, err := h.reportService.GetTicketTypeDistribution(queryScope)
 	if err != nil {
@@ -109,7 +106,6 @@ func (h *ReportHandler) GetTicketTypeDistribution(c *gin.Context) {
 // @Router /reports/tickets/average-resolution-time [get]
 func (h *ReportHandler) GetAverageResolutionTime(c *gin.Context) {
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	avgTime, err := h.reportService.GetAverageResolutionTime(queryScope)
 	if err != nil {
@@ -134,7 +130,7 @@ func (h *ReportHandler) GetAverageResolutionTime(c *gin.Context) {
 func (h *ReportHandler) GenerateCustomReport(c *gin.Context) {
 	var req dto.CustomReportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, 400, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -161,7 +157,6 @@ func (h *ReportHandler) GetWorkloadByAgent(c *gin.Context) {
 	period := c.DefaultQuery("period", "month")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	workload, err := h.reportService.GetWorkloadByAgent(queryScope, period)
 	if err != nil {
@@ -187,7 +182,6 @@ func (h *ReportHandler) GetSLAComplianceReport(c *gin.Context) {
 	period := c.DefaultQuery("period", "month")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	report, err := h.reportService.GetSLAComplianceReport(queryScope, period)
 	if err != nil {
@@ -198,6 +192,31 @@ func (h *ReportHandler) GetSLAComplianceReport(c *gin.Context) {
 	utils.SuccessResponse(c, report, "Rapport de conformité SLA récupéré avec succès")
 }
 
+// GetSatisfactionReport récupère le rapport de satisfaction des demandeurs
+// @Summary Récupérer le rapport de satisfaction (CSAT)
+// @Description Récupère le taux de réponse, la note moyenne et le CSAT (part des notes 4 et 5) des enquêtes envoyées à la clôture des tickets, globalement, par agent et par filiale
+// @Tags reports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param period query string false "Période (défaut: month)"
+// @Success 200 {object} dto.SatisfactionReportDTO
+// @Failure 500 {object} utils.Response
+// @Router /reports/satisfaction [get]
+func (h *ReportHandler) GetSatisfactionReport(c *gin.Context) {
+	period := c.DefaultQuery("period", "month")
+
+	queryScope := utils.GetScopeFromContext(c)
+
+	report, err := h.reportService.GetSatisfactionReport(queryScope, period)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la génération du rapport")
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Rapport de satisfaction récupéré avec succès")
+}
+
 // GetDelayedTicketsReport récupère le rapport des tickets en retard
 // @Summary Récupérer le rapport des tickets en retard
 // @Description Récupère le rapport des tickets qui ont dépassé leur délai
@@ -213,7 +232,6 @@ func (h *ReportHandler) GetDelayedTicketsReport(c *gin.Context) {
 	period := c.DefaultQuery("period", "month")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	report, err := h.reportService.GetDelayedTicketsReport(queryScope, period)
 	if err != nil {
@@ -270,7 +288,6 @@ func (h *ReportHandler) GetAssetSummary(c *gin.Context) {
 	period := c.DefaultQuery("period", "month")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	report, err := h.reportService.GetAssetSummary(queryScope, period)
 	if err != nil {
@@ -297,7 +314,6 @@ func (h *ReportHandler) GetKnowledgeSummary(c *gin.Context) {
 	period := c.DefaultQuery("period", "month")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	report, err := h.reportService.GetKnowledgeSummary(queryScope, period)
 	if err != nil {
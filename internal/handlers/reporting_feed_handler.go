@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/i18n"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/odata"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ReportingFeedHandler gère le flux OData de reporting (Power BI) et ses clés d'API
+type ReportingFeedHandler struct {
+	reportingService services.ReportingFeedService
+}
+
+// NewReportingFeedHandler crée une nouvelle instance de ReportingFeedHandler
+func NewReportingFeedHandler(reportingService services.ReportingFeedService) *ReportingFeedHandler {
+	return &ReportingFeedHandler{
+		reportingService: reportingService,
+	}
+}
+
+// reportingErrorResponse traduit une erreur du service en réponse HTTP (gestion des clés)
+func reportingErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// odataErrorResponse répond au format d'erreur OData, affiché tel quel par Power BI
+func odataErrorResponse(c *gin.Context, status int, message string) {
+	c.Header("OData-Version", "4.0")
+	c.AbortWithStatusJSON(status, gin.H{"error": gin.H{"code": strconv.Itoa(status), "message": i18n.T(utils.RequestLanguage(c), message)}})
+}
+
+// authenticate retrouve la clé d'API de la requête : Authorization Basic (mot de passe) ou Bearer,
+// en-tête X-API-Key ou paramètre api_key (clé « API Web » de Power BI)
+func (h *ReportingFeedHandler) authenticate(c *gin.Context) (*models.ReportingKey, bool) {
+	key := c.GetHeader("X-API-Key")
+	authorization := c.GetHeader("Authorization")
+	if bearer, found := strings.CutPrefix(authorization, "Bearer "); found {
+		key = strings.TrimSpace(bearer)
+	} else if basic, found := strings.CutPrefix(authorization, "Basic "); found {
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(basic)); err == nil {
+			if _, password, ok := strings.Cut(string(decoded), ":"); ok {
+				key = password
+			}
+		}
+	}
+	if key == "" {
+		key = c.Query("api_key")
+	}
+
+	reportingKey, err := h.reportingService.Authenticate(key)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Basic realm="OData"`)
+		odataErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return nil, false
+	}
+	return reportingKey, true
+}
+
+// ServiceDocument retourne la liste des ensembles d'entités du flux
+// @Summary Document de service OData
+// @Description Racine du flux OData v4 de reporting (Tickets, TimeEntries, TicketSla, Projects), à saisir dans Power BI (Obtenir des données > Flux OData). Authentification par clé d'API : Basic (mot de passe = clé), Bearer, en-tête X-API-Key ou paramètre api_key. Une clé de filiale ne voit que les données de sa filiale
+// @Tags reports
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /odata [get]
+func (h *ReportingFeedHandler) ServiceDocument(c *gin.Context) {
+	if _, ok := h.authenticate(c); !ok {
+		return
+	}
+	c.Header("OData-Version", "4.0")
+	c.JSON(http.StatusOK, odata.ServiceDocument(services.ReportingFeedURL()+"/$metadata", h.reportingService.EntitySets()))
+}
+
+// EntitySet retourne le document $metadata ou une page d'un ensemble d'entités
+// @Summary Ensemble d'entités OData
+// @Description Retourne le schéma ($metadata, CSDL XML) ou les entités d'un ensemble. Options prises en charge : $select, $filter (comparaisons eq, ne, gt, ge, lt, le combinées par and), $orderby, $top, $skip et $count. Les pages de plus de 1000 entités se poursuivent via @odata.nextLink. Le budget des projets n'est exposé qu'aux clés créées par un utilisateur ayant projects.budget.view
+// @Tags reports
+// @Produce json
+// @Param set path string true "Ensemble d'entités (Tickets, TimeEntries, TicketSla, Projects) ou $metadata"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /odata/{set} [get]
+func (h *ReportingFeedHandler) EntitySet(c *gin.Context) {
+	reportingKey, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	setName := c.Param("set")
+
+	if setName == "$metadata" {
+		content, err := odata.Metadata("Kronos.Reporting", "Reporting", h.reportingService.EntitySets())
+		if err != nil {
+			odataErrorResponse(c, http.StatusInternalServerError, "erreur lors de la génération du schéma")
+			return
+		}
+		c.Header("OData-Version", "4.0")
+		c.Data(http.StatusOK, "application/xml; charset=utf-8", content)
+		return
+	}
+
+	page, err := h.reportingService.Query(reportingKey, setName, c.Request.URL.Query())
+	if err != nil {
+		switch {
+		case strings.HasSuffix(err.Error(), "introuvable"):
+			odataErrorResponse(c, http.StatusNotFound, err.Error())
+		case strings.HasPrefix(err.Error(), "erreur lors"):
+			odataErrorResponse(c, http.StatusInternalServerError, err.Error())
+		default:
+			odataErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	feedURL := services.ReportingFeedURL()
+	body := gin.H{
+		"@odata.context": feedURL + "/$metadata#" + setName,
+		"value":          page.Rows,
+	}
+	if page.Count != nil {
+		body["@odata.count"] = *page.Count
+	}
+	if page.HasNext {
+		current, err := url.Parse(feedURL + "/" + setName)
+		if err == nil {
+			current.RawQuery = c.Request.URL.RawQuery
+			body["@odata.nextLink"] = odata.NextLink(current, page.NextSkip, page.NextTop)
+		}
+	}
+	c.Header("OData-Version", "4.0")
+	c.JSON(http.StatusOK, body)
+}
+
+// reportingKeyScope retourne la filiale à laquelle le gestionnaire est limité (nil avec reports.view_global)
+func reportingKeyScope(c *gin.Context) (*uint, bool) {
+	if utils.RequirePermission(c, "reports.view_global") {
+		return nil, true
+	}
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return nil, false
+	}
+	if queryScope.FilialeID == nil {
+		utils.ForbiddenResponse(c, "Aucune filiale associée à votre compte")
+		return nil, false
+	}
+	return queryScope.FilialeID, true
+}
+
+// GetKeys récupère les clés d'API du flux OData
+// @Summary Lister les clés du flux OData
+// @Description Liste les clés d'API du flux OData de reporting (sans leur valeur) ; sans reports.view_global, seules les clés de sa filiale sont visibles (nécessite reports.odata)
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.ReportingKeyDTO
+// @Router /reports/odata/keys [get]
+func (h *ReportingFeedHandler) GetKeys(c *gin.Context) {
+	if !utils.RequirePermission(c, "reports.odata") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: reports.odata")
+		return
+	}
+	filialeID, ok := reportingKeyScope(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.reportingService.GetKeys(filialeID)
+	if err != nil {
+		reportingErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, keys, "Clés d'API récupérées avec succès")
+}
+
+// CreateKey crée une clé d'API du flux OData
+// @Summary Créer une clé du flux OData
+// @Description Crée une clé d'API, retournée uniquement dans cette réponse avec l'URL du flux. Sans reports.view_global, la clé est limitée à la filiale de l'utilisateur ; le budget des projets n'est exposé que si l'utilisateur a projects.budget.view (nécessite reports.odata)
+// @Tags reports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateReportingKeyRequest true "Clé à créer"
+// @Success 201 {object} dto.ReportingKeyDTO
+// @Failure 400 {object} utils.Response
+// @Router /reports/odata/keys [post]
+func (h *ReportingFeedHandler) CreateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "reports.odata") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: reports.odata")
+		return
+	}
+	scopeFilialeID, ok := reportingKeyScope(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateReportingKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	filialeID := req.FilialeID
+	if scopeFilialeID != nil {
+		if filialeID != nil && *filialeID != *scopeFilialeID {
+			utils.ForbiddenResponse(c, "Permission insuffisante: reports.view_global")
+			return
+		}
+		filialeID = scopeFilialeID
+	}
+
+	key, err := h.reportingService.CreateKey(req, userID, filialeID, utils.RequirePermission(c, "projects.budget.view"))
+	if err != nil {
+		reportingErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, key, "Clé d'API créée avec succès")
+}
+
+// UpdateKey met à jour une clé d'API du flux OData
+// @Summary Modifier une clé du flux OData
+// @Description Met à jour le nom ou l'activation ; une clé inactive est refusée par le flux (nécessite reports.odata)
+// @Tags reports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la clé"
+// @Param request body dto.UpdateReportingKeyRequest true "Champs à modifier"
+// @Success 200 {object} dto.ReportingKeyDTO
+// @Failure 404 {object} utils.Response
+// @Router /reports/odata/keys/{id} [put]
+func (h *ReportingFeedHandler) UpdateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "reports.odata") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: reports.odata")
+		return
+	}
+	filialeID, ok := reportingKeyScope(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateReportingKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	key, err := h.reportingService.UpdateKey(uint(id), req, filialeID)
+	if err != nil {
+		reportingErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, key, "Clé d'API mise à jour avec succès")
+}
+
+// RotateKey renouvelle une clé d'API du flux OData
+// @Summary Renouveler une clé du flux OData
+// @Description Génère une nouvelle clé, retournée uniquement dans cette réponse ; l'ancienne est immédiatement refusée (nécessite reports.odata)
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la clé"
+// @Success 200 {object} dto.ReportingKeyDTO
+// @Failure 404 {object} utils.Response
+// @Router /reports/odata/keys/{id}/key [post]
+func (h *ReportingFeedHandler) RotateKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "reports.odata") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: reports.odata")
+		return
+	}
+	filialeID, ok := reportingKeyScope(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	key, err := h.reportingService.RotateKey(uint(id), filialeID)
+	if err != nil {
+		reportingErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, key, "Clé d'API renouvelée")
+}
+
+// DeleteKey supprime une clé d'API du flux OData
+// @Summary Supprimer une clé du flux OData
+// @Description Révoque définitivement la clé (nécessite reports.odata)
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la clé"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reports/odata/keys/{id} [delete]
+func (h *ReportingFeedHandler) DeleteKey(c *gin.Context) {
+	if !utils.RequirePermission(c, "reports.odata") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: reports.odata")
+		return
+	}
+	filialeID, ok := reportingKeyScope(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.reportingService.DeleteKey(uint(id), filialeID); err != nil {
+		reportingErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Clé d'API supprimée avec succès")
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -82,7 +81,7 @@ func (h *RequestSourceHandler) GetByID(c *gin.Context) {
 func (h *RequestSourceHandler) Create(c *gin.Context) {
 	var req dto.CreateRequestSourceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -94,7 +93,7 @@ func (h *RequestSourceHandler) Create(c *gin.Context) {
 
 	source, err := h.requestSourceService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -123,7 +122,7 @@ func (h *RequestSourceHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateRequestSourceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -135,7 +134,7 @@ func (h *RequestSourceHandler) Update(c *gin.Context) {
 
 	source, err := h.requestSourceService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -162,7 +161,7 @@ func (h *RequestSourceHandler) Delete(c *gin.Context) {
 
 	err = h.requestSourceService.Delete(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
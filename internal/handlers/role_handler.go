@@ -32,9 +32,8 @@ func NewRoleHandler(roleService services.RoleService) *RoleHandler {
 // @Failure 500 {object} utils.Response
 // @Router /roles [get]
 func (h *RoleHandler) GetAll(c *gin.Context) {
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
 	canManageAll := scope.HasPermission("roles.manage")
@@ -90,6 +89,102 @@ func (h *RoleHandler) GetAll(c *gin.Context) {
 	utils.SuccessResponse(c, roles, "Rôles récupérés avec succès")
 }
 
+// GetTemplates récupère les modèles de rôles prédéfinis
+// @Summary Modèles de rôles
+// @Description Liste les modèles de rôles prédéfinis (DSI filiale, Résolveur IT, Chef de projet, RH) et leurs permissions
+// @Tags roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.RoleTemplateDTO
+// @Router /roles/templates [get]
+func (h *RoleHandler) GetTemplates(c *gin.Context) {
+	utils.SuccessResponse(c, h.roleService.GetTemplates(), "Modèles de rôles récupérés avec succès")
+}
+
+// CreateFromTemplate crée un rôle à partir d'un modèle prédéfini
+// @Summary Créer un rôle depuis un modèle
+// @Description Crée un rôle avec le jeu de permissions d'un modèle (le créateur doit posséder toutes ces permissions)
+// @Tags roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateRoleFromTemplateRequest true "Modèle et nom du rôle"
+// @Success 201 {object} dto.RoleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /roles/from-template [post]
+func (h *RoleHandler) CreateFromTemplate(c *gin.Context) {
+	var req dto.CreateRoleFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	role, err := h.roleService.CreateFromTemplate(req, createdByID.(uint))
+	if err != nil {
+		if err.Error() == "modèle de rôle introuvable" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, role, "Rôle créé avec succès")
+}
+
+// Clone duplique un rôle avec ses permissions
+// @Summary Dupliquer un rôle
+// @Description Crée un nouveau rôle avec les permissions du rôle source (le créateur doit posséder toutes ces permissions)
+// @Tags roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du rôle source"
+// @Param request body dto.CloneRoleRequest true "Nom du nouveau rôle"
+// @Success 201 {object} dto.RoleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /roles/{id}/clone [post]
+func (h *RoleHandler) Clone(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.CloneRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	role, err := h.roleService.Clone(uint(id), req, createdByID.(uint))
+	if err != nil {
+		if err.Error() == "rôle introuvable" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, role, "Rôle dupliqué avec succès")
+}
+
 // GetByID récupère un rôle par son ID
 // @Summary Récupérer un rôle par ID
 // @Description Récupère un rôle par son identifiant
@@ -131,7 +226,7 @@ func (h *RoleHandler) GetByID(c *gin.Context) {
 func (h *RoleHandler) Create(c *gin.Context) {
 	var req dto.CreateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -143,7 +238,7 @@ func (h *RoleHandler) Create(c *gin.Context) {
 
 	role, err := h.roleService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -172,7 +267,7 @@ func (h *RoleHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -185,7 +280,7 @@ func (h *RoleHandler) Update(c *gin.Context) {
 	canManageAllRoles := utils.RequirePermission(c, "roles.manage")
 	role, err := h.roleService.Update(uint(id), req, updatedByID.(uint), canManageAllRoles)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -219,7 +314,7 @@ func (h *RoleHandler) Delete(c *gin.Context) {
 	canManageAllRoles := utils.RequirePermission(c, "roles.manage")
 	err = h.roleService.Delete(uint(id), deletedByID.(uint), canManageAllRoles)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -246,7 +341,7 @@ func (h *RoleHandler) GetRolePermissions(c *gin.Context) {
 
 	permissions, err := h.roleService.GetRolePermissions(uint(id))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -278,7 +373,7 @@ func (h *RoleHandler) UpdateRolePermissions(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -291,7 +386,7 @@ func (h *RoleHandler) UpdateRolePermissions(c *gin.Context) {
 	canManageAllRoles := utils.RequirePermission(c, "roles.manage")
 	err = h.roleService.UpdateRolePermissions(uint(id), req.Permissions, updatedByID.(uint), canManageAllRoles)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -316,7 +411,7 @@ func (h *RoleHandler) GetAssignablePermissions(c *gin.Context) {
 
 	permissions, err := h.roleService.GetAssignablePermissions(userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/scheduler"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SchedulerHandler gère l'administration des tâches planifiées
+type SchedulerHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+// NewSchedulerHandler crée une nouvelle instance de SchedulerHandler
+func NewSchedulerHandler(scheduler *scheduler.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{
+		scheduler: scheduler,
+	}
+}
+
+// GetJobs liste les tâches planifiées
+// @Summary Tâches planifiées
+// @Description Liste les tâches planifiées avec leur planification, prochaine et dernière exécution (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} scheduler.JobStatus
+// @Failure 403 {object} utils.Response
+// @Router /admin/scheduler/jobs [get]
+func (h *SchedulerHandler) GetJobs(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	utils.SuccessResponse(c, h.scheduler.List(), "Tâches planifiées récupérées avec succès")
+}
+
+// UpdateJob modifie la planification d'une tâche
+// @Summary Modifier une tâche planifiée
+// @Description Modifie l'expression cron et/ou l'activation d'une tâche ; la modification est persistée dans les paramètres (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param name path string true "Nom de la tâche"
+// @Param request body dto.UpdateScheduledJobRequest true "Planification"
+// @Success 200 {object} scheduler.JobStatus
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/scheduler/jobs/{name} [put]
+func (h *SchedulerHandler) UpdateJob(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	var req dto.UpdateScheduledJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	status, err := h.scheduler.Update(c.Param("name"), req.Schedule, req.Enabled)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrJobNotFound) {
+			utils.NotFoundResponse(c, "Tâche planifiée introuvable")
+			return
+		}
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, status, "Tâche planifiée mise à jour avec succès")
+}
+
+// RunJob lance immédiatement une tâche planifiée
+// @Summary Exécuter une tâche planifiée
+// @Description Lance la tâche en arrière-plan sans attendre sa planification ; le résultat est visible dans l'historique (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Nom de la tâche"
+// @Success 202 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/scheduler/jobs/{name}/run [post]
+func (h *SchedulerHandler) RunJob(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	var triggeredByID *uint
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uint); ok {
+			triggeredByID = &id
+		}
+	}
+
+	if err := h.scheduler.RunNow(c.Param("name"), triggeredByID); err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			utils.NotFoundResponse(c, "Tâche planifiée introuvable")
+		case errors.Is(err, scheduler.ErrJobAlreadyRunning):
+			utils.ErrorResponse(c, http.StatusConflict, "La tâche est déjà en cours d'exécution", nil)
+		default:
+			utils.InternalServerErrorResponse(c, "Erreur lors du lancement de la tâche: "+err.Error())
+		}
+		return
+	}
+
+	utils.AcceptedResponse(c, nil, "Exécution de la tâche lancée")
+}
+
+// GetJobRuns retourne l'historique d'exécution d'une tâche
+// @Summary Historique d'une tâche planifiée
+// @Description Dernières exécutions d'une tâche, plus récentes d'abord (nécessite settings.manage)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Nom de la tâche"
+// @Param limit query int false "Nombre maximum d'exécutions" default(50)
+// @Success 200 {array} models.ScheduledJobRun
+// @Failure 404 {object} utils.Response
+// @Router /admin/scheduler/jobs/{name}/runs [get]
+func (h *SchedulerHandler) GetJobRuns(c *gin.Context) {
+	if !utils.RequirePermission(c, "settings.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: settings.manage")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	runs, err := h.scheduler.Runs(c.Param("name"), limit)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrJobNotFound) {
+			utils.NotFoundResponse(c, "Tâche planifiée introuvable")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Erreur lors de la récupération de l'historique: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, runs, "Historique récupéré avec succès")
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 	"strings"
 
@@ -71,7 +70,7 @@ func (h *SearchHandler) GlobalSearch(c *gin.Context) {
 	
 	result, err := h.searchService.GlobalSearch(queryScope, query, types, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -130,7 +129,7 @@ func (h *SearchHandler) SearchTickets(c *gin.Context) {
 	
 	results, err := h.searchService.SearchTickets(queryScope, query, status, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -172,7 +171,7 @@ func (h *SearchHandler) SearchAssets(c *gin.Context) {
 	
 	results, err := h.searchService.SearchAssets(queryScope, query, category, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -214,7 +213,7 @@ func (h *SearchHandler) SearchKnowledgeBase(c *gin.Context) {
 	
 	results, err := h.searchService.SearchKnowledgeBase(queryScope, query, category, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -254,7 +253,7 @@ func (h *SearchHandler) SearchUsers(c *gin.Context) {
 	
 	results, err := h.searchService.SearchUsers(queryScope, query, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -293,7 +292,7 @@ func (h *SearchHandler) SearchTimeEntries(c *gin.Context) {
 	
 	results, err := h.searchService.SearchTimeEntries(queryScope, query, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
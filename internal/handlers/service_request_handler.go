@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 	"time"
 
@@ -38,7 +37,7 @@ func NewServiceRequestHandler(serviceRequestService services.ServiceRequestServi
 func (h *ServiceRequestHandler) Create(c *gin.Context) {
 	var req dto.CreateServiceRequestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -50,7 +49,7 @@ func (h *ServiceRequestHandler) Create(c *gin.Context) {
 
 	serviceRequest, err := h.serviceRequestService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -132,7 +131,7 @@ func (h *ServiceRequestHandler) Validate(c *gin.Context) {
 
 	var req dto.ValidateServiceRequestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -144,7 +143,7 @@ func (h *ServiceRequestHandler) Validate(c *gin.Context) {
 
 	serviceRequest, err := h.serviceRequestService.Validate(uint(id), req, validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -203,7 +202,7 @@ func (h *ServiceRequestHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateServiceRequestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -215,7 +214,7 @@ func (h *ServiceRequestHandler) Update(c *gin.Context) {
 
 	serviceRequest, err := h.serviceRequestService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/services"
@@ -54,7 +52,7 @@ func (h *SettingsHandler) GetAll(c *gin.Context) {
 func (h *SettingsHandler) Update(c *gin.Context) {
 	var req dto.UpdateSettingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -66,7 +64,7 @@ func (h *SettingsHandler) Update(c *gin.Context) {
 
 	settings, err := h.settingsService.Update(req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/i18n"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SkillHandler gère le catalogue de compétences, les profils des utilisateurs et la recherche par compétences
+type SkillHandler struct {
+	skillService services.SkillService
+}
+
+// NewSkillHandler crée une nouvelle instance de SkillHandler
+func NewSkillHandler(skillService services.SkillService) *SkillHandler {
+	return &SkillHandler{
+		skillService: skillService,
+	}
+}
+
+// GetCatalog récupère le catalogue des compétences et certifications
+// @Summary Catalogue des compétences
+// @Description Liste les compétences et certifications du catalogue (actives uniquement par défaut)
+// @Tags skills
+// @Security BearerAuth
+// @Produce json
+// @Param kind query string false "skill ou certification"
+// @Param category query string false "Catégorie"
+// @Param include_inactive query bool false "Inclure les compétences désactivées"
+// @Success 200 {array} dto.SkillDTO
+// @Router /skills [get]
+func (h *SkillHandler) GetCatalog(c *gin.Context) {
+	skills, err := h.skillService.GetCatalog(c.Query("kind"), c.Query("category"), c.Query("include_inactive") == "true")
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, skills, "Compétences récupérées avec succès")
+}
+
+// Create ajoute une compétence au catalogue
+// @Summary Ajouter une compétence
+// @Description Ajoute une compétence ou une certification au catalogue
+// @Tags skills
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSkillRequest true "Compétence"
+// @Success 201 {object} dto.SkillDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /skills [post]
+func (h *SkillHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "skills.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: skills.manage")
+		return
+	}
+
+	var req dto.CreateSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	skill, err := h.skillService.Create(req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "erreur lors") {
+			utils.InternalServerErrorResponse(c, err.Error())
+		} else {
+			utils.BadRequestResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.CreatedResponse(c, skill, "Compétence créée avec succès")
+}
+
+// Update met à jour une compétence du catalogue
+// @Summary Modifier une compétence
+// @Description Renomme, recatégorise ou désactive une compétence du catalogue
+// @Tags skills
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la compétence"
+// @Param request body dto.UpdateSkillRequest true "Champs à modifier"
+// @Success 200 {object} dto.SkillDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /skills/{id} [put]
+func (h *SkillHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "skills.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: skills.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	skill, err := h.skillService.Update(uint(id), req)
+	if err != nil {
+		switch {
+		case err.Error() == "compétence introuvable":
+			utils.NotFoundResponse(c, "Compétence introuvable")
+		case strings.HasPrefix(err.Error(), "erreur lors"):
+			utils.InternalServerErrorResponse(c, err.Error())
+		default:
+			utils.BadRequestResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, skill, "Compétence mise à jour avec succès")
+}
+
+// Delete supprime une compétence du catalogue
+// @Summary Supprimer une compétence
+// @Description Supprime une compétence détenue par aucun utilisateur (sinon, la désactiver)
+// @Tags skills
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la compétence"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /skills/{id} [delete]
+func (h *SkillHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "skills.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: skills.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.skillService.Delete(uint(id)); err != nil {
+		switch {
+		case err.Error() == "compétence introuvable":
+			utils.NotFoundResponse(c, "Compétence introuvable")
+		case strings.HasPrefix(err.Error(), "erreur lors"):
+			utils.InternalServerErrorResponse(c, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Compétence supprimée avec succès")
+}
+
+// Search recherche des utilisateurs par compétences
+// @Summary Rechercher par compétences
+// @Description Utilisateurs (dans son périmètre de visibilité) détenant les compétences demandées au niveau minimal, triés par pertinence. Sert au routage des tickets et aux suggestions d'affectation des projets
+// @Tags skills
+// @Security BearerAuth
+// @Produce json
+// @Param skill_ids query string true "IDs des compétences, séparés par des virgules"
+// @Param min_level query int false "Niveau minimal (1 à 4, défaut 1)"
+// @Param match query string false "all (toutes les compétences) ou any (au moins une, défaut)"
+// @Param available query bool false "Exclure les utilisateurs absents"
+// @Param limit query int false "Nombre maximal de résultats (défaut et max 100)"
+// @Success 200 {array} dto.SkillSearchResultDTO
+// @Failure 400 {object} utils.Response
+// @Router /skills/search [get]
+func (h *SkillHandler) Search(c *gin.Context) {
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	opts := services.SkillSearchOptions{
+		MatchAll:      c.Query("match") == "all",
+		AvailableOnly: c.Query("available") == "true",
+	}
+	for _, raw := range strings.Split(c.Query("skill_ids"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre skill_ids invalide")
+			return
+		}
+		opts.SkillIDs = append(opts.SkillIDs, uint(id))
+	}
+	if raw := c.Query("min_level"); raw != "" {
+		level, err := strconv.Atoi(raw)
+		if err != nil || level < 1 || level > 4 {
+			utils.BadRequestResponse(c, "Paramètre min_level invalide (1 à 4)")
+			return
+		}
+		opts.MinLevel = level
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			utils.BadRequestResponse(c, "Paramètre limit invalide")
+			return
+		}
+		opts.Limit = limit
+	}
+
+	results, err := h.skillService.Search(queryScope, opts)
+	if err != nil {
+		if err.Error() == "erreur lors de la recherche par compétences" {
+			utils.InternalServerErrorResponse(c, err.Error())
+		} else {
+			utils.BadRequestResponse(c, err.Error())
+		}
+		return
+	}
+
+	for i := range results {
+		localizeSkillLevels(c, results[i].Skills)
+	}
+	utils.SuccessResponse(c, results, "Recherche effectuée avec succès")
+}
+
+// localizeSkillLevels traduit les libellés de niveau dans la langue de la requête
+func localizeSkillLevels(c *gin.Context, skills []dto.UserSkillDTO) {
+	lang := utils.RequestLanguage(c)
+	for i := range skills {
+		skills[i].LevelLabel = i18n.Label(lang, "skill_level", strconv.Itoa(skills[i].Level))
+	}
+}
+
+// skillProfileTarget résout l'utilisateur visé par /users/me/skills ou /users/:id/skills
+// Modifier le profil d'un autre utilisateur requiert users.update
+func skillProfileTarget(c *gin.Context, write bool) (uint, bool) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return 0, false
+	}
+	idParam := c.Param("id")
+	if idParam == "" {
+		return userID, true
+	}
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, false
+	}
+	if write && uint(id) != userID && !utils.RequirePermission(c, "users.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: users.update")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetUserSkills récupère le profil de compétences d'un utilisateur
+// @Summary Compétences d'un utilisateur
+// @Description Compétences et certifications d'un utilisateur (sans ID : utilisateur connecté)
+// @Tags skills
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Success 200 {array} dto.UserSkillDTO
+// @Failure 404 {object} utils.Response
+// @Router /users/{id}/skills [get]
+// @Router /users/me/skills [get]
+func (h *SkillHandler) GetUserSkills(c *gin.Context) {
+	userID, ok := skillProfileTarget(c, false)
+	if !ok {
+		return
+	}
+
+	skills, err := h.skillService.GetUserSkills(userID)
+	if err != nil {
+		if err.Error() == "utilisateur introuvable" {
+			utils.NotFoundResponse(c, "Utilisateur introuvable")
+		} else {
+			utils.InternalServerErrorResponse(c, err.Error())
+		}
+		return
+	}
+
+	localizeSkillLevels(c, skills)
+	utils.SuccessResponse(c, skills, "Compétences récupérées avec succès")
+}
+
+// SetUserSkill ajoute ou met à jour une compétence dans un profil
+// @Summary Ajouter ou modifier une compétence du profil
+// @Description Définit le niveau (1 à 4) et, pour une certification, les dates et la référence. Le profil d'un autre utilisateur requiert users.update
+// @Tags skills
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Param skill_id path int true "ID de la compétence"
+// @Param request body dto.SetUserSkillRequest true "Niveau et certification"
+// @Success 200 {array} dto.UserSkillDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /users/{id}/skills/{skill_id} [put]
+// @Router /users/me/skills/{skill_id} [put]
+func (h *SkillHandler) SetUserSkill(c *gin.Context) {
+	userID, ok := skillProfileTarget(c, true)
+	if !ok {
+		return
+	}
+	skillID, err := strconv.ParseUint(c.Param("skill_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de compétence invalide")
+		return
+	}
+
+	var req dto.SetUserSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	skills, err := h.skillService.SetUserSkill(userID, uint(skillID), req)
+	if err != nil {
+		switch err.Error() {
+		case "utilisateur introuvable", "compétence introuvable":
+			utils.NotFoundResponse(c, err.Error())
+		case "erreur lors de l'enregistrement de la compétence":
+			utils.InternalServerErrorResponse(c, err.Error())
+		default:
+			utils.BadRequestResponse(c, err.Error())
+		}
+		return
+	}
+
+	localizeSkillLevels(c, skills)
+	utils.SuccessResponse(c, skills, "Compétence enregistrée avec succès")
+}
+
+// RemoveUserSkill retire une compétence d'un profil
+// @Summary Retirer une compétence du profil
+// @Description Retire une compétence ou certification du profil. Le profil d'un autre utilisateur requiert users.update
+// @Tags skills
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Param skill_id path int true "ID de la compétence"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/{id}/skills/{skill_id} [delete]
+// @Router /users/me/skills/{skill_id} [delete]
+func (h *SkillHandler) RemoveUserSkill(c *gin.Context) {
+	userID, ok := skillProfileTarget(c, true)
+	if !ok {
+		return
+	}
+	skillID, err := strconv.ParseUint(c.Param("skill_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de compétence invalide")
+		return
+	}
+
+	if err := h.skillService.RemoveUserSkill(userID, uint(skillID)); err != nil {
+		if err.Error() == "compétence absente du profil" {
+			utils.NotFoundResponse(c, err.Error())
+		} else {
+			utils.InternalServerErrorResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Compétence retirée du profil")
+}
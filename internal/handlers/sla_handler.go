@@ -43,7 +43,7 @@ func (h *SLAHandler) Create(c *gin.Context) {
 
 	var req dto.CreateSLARequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -55,7 +55,7 @@ func (h *SLAHandler) Create(c *gin.Context) {
 
 	sla, err := h.slaService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -168,7 +168,7 @@ func (h *SLAHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateSLARequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -180,7 +180,7 @@ func (h *SLAHandler) Update(c *gin.Context) {
 
 	sla, err := h.slaService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -266,7 +266,6 @@ func (h *SLAHandler) GetViolations(c *gin.Context) {
 	}
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	violations, err := h.slaService.GetViolations(queryScope, uint(id))
 	if err != nil {
@@ -293,7 +292,6 @@ func (h *SLAHandler) GetAllViolations(c *gin.Context) {
 	category := c.Query("category")
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	violations, err := h.slaService.GetAllViolations(queryScope, period, category)
 	if err != nil {
@@ -332,7 +330,7 @@ func (h *SLAHandler) GetComplianceReport(c *gin.Context) {
 
 // RecalculateSLAStatuses recalcule les statuts SLA pour tous les tickets ouverts
 // @Summary Recalculer les statuts SLA
-// @Description Recalcule les statuts SLA pour tous les tickets ouverts qui ont un SLA associé
+// @Description Recalcule les statuts SLA pour tous les tickets ouverts qui ont un SLA associé (également exécuté périodiquement par la tâche planifiée sla_statuses). Les franchissements de seuil sont notifiés
 // @Tags sla
 // @Security BearerAuth
 // @Produce json
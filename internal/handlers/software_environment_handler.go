@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SoftwareEnvironmentHandler gère les environnements des logiciels par filiale
+type SoftwareEnvironmentHandler struct {
+	environmentService services.SoftwareEnvironmentService
+}
+
+// NewSoftwareEnvironmentHandler crée une nouvelle instance de SoftwareEnvironmentHandler
+func NewSoftwareEnvironmentHandler(environmentService services.SoftwareEnvironmentService) *SoftwareEnvironmentHandler {
+	return &SoftwareEnvironmentHandler{
+		environmentService: environmentService,
+	}
+}
+
+// environmentErrorResponse traduit une erreur du service en réponse HTTP
+func environmentErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case err.Error() == "environnement introuvable" || err.Error() == "filiale introuvable" || err.Error() == "logiciel introuvable":
+		utils.NotFoundResponse(c, err.Error())
+	case err.Error() == "cet environnement existe déjà pour ce logiciel":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// GetByFiliale récupère les environnements des logiciels d'une filiale
+// @Summary Environnements d'une filiale
+// @Description Liste les environnements (production, préproduction, test) des logiciels d'une filiale avec leur adresse et leur version. Avec seulement tickets.create, seuls les environnements actifs sont retournés (choix de l'environnement d'un ticket)
+// @Tags software-environments
+// @Security BearerAuth
+// @Produce json
+// @Param filiale_id path int true "ID de la filiale"
+// @Param software_id query int false "Filtrer par logiciel"
+// @Success 200 {array} dto.SoftwareEnvironmentDTO
+// @Failure 404 {object} utils.Response
+// @Router /filiales/{filiale_id}/environments [get]
+func (h *SoftwareEnvironmentHandler) GetByFiliale(c *gin.Context) {
+	canViewSoftware := utils.RequirePermission(c, "software.view")
+	canCreateTickets := utils.RequirePermission(c, "tickets.create")
+	if !canViewSoftware && !canCreateTickets {
+		utils.ForbiddenResponse(c, "Permission insuffisante")
+		return
+	}
+
+	filialeID, err := strconv.ParseUint(c.Param("filiale_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+
+	var softwareID *uint
+	if raw := c.Query("software_id"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID du logiciel invalide")
+			return
+		}
+		id := uint(parsed)
+		softwareID = &id
+	}
+
+	environments, err := h.environmentService.GetByFiliale(uint(filialeID), softwareID, !canViewSoftware)
+	if err != nil {
+		environmentErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, environments, "Environnements récupérés avec succès")
+}
+
+// Create déclare un environnement d'un logiciel chez une filiale
+// @Summary Déclarer un environnement
+// @Description Déclare l'environnement d'un logiciel chez une filiale ; sans version, celle du déploiement en service est reprise (nécessite software.manage_deployments)
+// @Tags software-environments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param filiale_id path int true "ID de la filiale"
+// @Param request body dto.CreateSoftwareEnvironmentRequest true "Environnement"
+// @Success 201 {object} dto.SoftwareEnvironmentDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /filiales/{filiale_id}/environments [post]
+func (h *SoftwareEnvironmentHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.manage_deployments") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.manage_deployments")
+		return
+	}
+
+	filialeID, err := strconv.ParseUint(c.Param("filiale_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de la filiale invalide")
+		return
+	}
+
+	var req dto.CreateSoftwareEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	environment, err := h.environmentService.Create(uint(filialeID), req)
+	if err != nil {
+		environmentErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, environment, "Environnement créé avec succès")
+}
+
+// GetByID récupère un environnement
+// @Summary Récupérer un environnement
+// @Description Récupère un environnement d'un logiciel (nécessite software.view ou tickets.create)
+// @Tags software-environments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'environnement"
+// @Success 200 {object} dto.SoftwareEnvironmentDTO
+// @Failure 404 {object} utils.Response
+// @Router /software-environments/{id} [get]
+func (h *SoftwareEnvironmentHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.view") && !utils.RequirePermission(c, "tickets.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	environment, err := h.environmentService.GetByID(uint(id))
+	if err != nil {
+		environmentErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, environment, "Environnement récupéré avec succès")
+}
+
+// Update met à jour un environnement
+// @Summary Mettre à jour un environnement
+// @Description Met à jour le libellé, l'adresse, la version, les notes ou l'activation d'un environnement (nécessite software.manage_deployments)
+// @Tags software-environments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'environnement"
+// @Param request body dto.UpdateSoftwareEnvironmentRequest true "Champs à modifier"
+// @Success 200 {object} dto.SoftwareEnvironmentDTO
+// @Failure 404 {object} utils.Response
+// @Router /software-environments/{id} [put]
+func (h *SoftwareEnvironmentHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.manage_deployments") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.manage_deployments")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateSoftwareEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	environment, err := h.environmentService.Update(uint(id), req)
+	if err != nil {
+		environmentErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, environment, "Environnement mis à jour avec succès")
+}
+
+// Delete supprime un environnement
+// @Summary Supprimer un environnement
+// @Description Supprime un environnement ; les tickets qui le référençaient n'ont plus d'environnement (nécessite software.manage_deployments)
+// @Tags software-environments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'environnement"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /software-environments/{id} [delete]
+func (h *SoftwareEnvironmentHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.manage_deployments") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.manage_deployments")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.environmentService.Delete(uint(id)); err != nil {
+		environmentErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Environnement supprimé avec succès")
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SoftwareLicenseHandler gère les licences logicielles et le rapport de conformité
+type SoftwareLicenseHandler struct {
+	licenseService services.SoftwareLicenseService
+}
+
+// NewSoftwareLicenseHandler crée une nouvelle instance de SoftwareLicenseHandler
+func NewSoftwareLicenseHandler(licenseService services.SoftwareLicenseService) *SoftwareLicenseHandler {
+	return &SoftwareLicenseHandler{
+		licenseService: licenseService,
+	}
+}
+
+// canViewLicenses indique si l'utilisateur peut consulter les licences
+func canViewLicenses(c *gin.Context) bool {
+	return utils.RequireAnyPermission(c, "software_licenses.view", "software_licenses.manage")
+}
+
+// licenseFiliale retourne la filiale à laquelle l'utilisateur est limité pour la consultation des licences
+// (nil : toutes les filiales, pour les gestionnaires de licences)
+func licenseFiliale(c *gin.Context) (*uint, bool) {
+	if utils.RequirePermission(c, "software_licenses.manage") {
+		return nil, true
+	}
+	queryScope := utils.GetScopeFromContext(c)
+	if queryScope == nil || queryScope.FilialeID == nil {
+		return nil, false
+	}
+	return queryScope.FilialeID, true
+}
+
+// GetAll récupère les licences logicielles
+// @Summary Lister les licences logicielles
+// @Description Liste les licences logicielles des filiales (nécessite software_licenses.view). Hors gestionnaires, seules les licences de sa filiale sont visibles. expiry_within limite aux licences actives expirées ou expirant dans ce nombre de jours
+// @Tags software-licenses
+// @Security BearerAuth
+// @Produce json
+// @Param filiale_id query int false "Filiale titulaire"
+// @Param software_id query int false "Logiciel"
+// @Param active query bool false "Licences actives uniquement"
+// @Param expiry_within query int false "Expiration dans les N prochains jours"
+// @Success 200 {array} dto.SoftwareLicenseDTO
+// @Failure 403 {object} utils.Response
+// @Router /software-licenses [get]
+func (h *SoftwareLicenseHandler) GetAll(c *gin.Context) {
+	if !canViewLicenses(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software_licenses.view")
+		return
+	}
+
+	var filialeID, softwareID *uint
+	for _, param := range []struct {
+		name   string
+		target **uint
+	}{{"filiale_id", &filialeID}, {"software_id", &softwareID}} {
+		if raw := c.Query(param.name); raw != "" {
+			parsed, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				utils.BadRequestResponse(c, "Paramètre "+param.name+" invalide")
+				return
+			}
+			id := uint(parsed)
+			*param.target = &id
+		}
+	}
+
+	restricted, allowed := licenseFiliale(c)
+	if !allowed {
+		utils.SuccessResponse(c, []dto.SoftwareLicenseDTO{}, "Licences récupérées avec succès")
+		return
+	}
+	if restricted != nil {
+		if filialeID != nil && *filialeID != *restricted {
+			utils.SuccessResponse(c, []dto.SoftwareLicenseDTO{}, "Licences récupérées avec succès")
+			return
+		}
+		filialeID = restricted
+	}
+
+	activeOnly := false
+	if raw := c.Query("active"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre active invalide")
+			return
+		}
+		activeOnly = parsed
+	}
+
+	var expiryWithin *int
+	if raw := c.Query("expiry_within"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 || days > 3650 {
+			utils.BadRequestResponse(c, "Paramètre expiry_within invalide (nombre de jours)")
+			return
+		}
+		expiryWithin = &days
+	}
+
+	licenses, err := h.licenseService.GetAll(filialeID, softwareID, activeOnly, expiryWithin)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, licenses, "Licences récupérées avec succès")
+}
+
+// GetCompliance récupère le rapport de conformité des licences
+// @Summary Rapport de conformité des licences
+// @Description Compare, par filiale et logiciel, les postes couverts par les licences en vigueur aux installations inventoriées sur les actifs en service (compliant, over_deployed, unlicensed) et liste les licences expirées ou arrivant à échéance (nécessite software_licenses.view ; hors gestionnaires, limité à sa filiale)
+// @Tags software-licenses
+// @Security BearerAuth
+// @Produce json
+// @Param filiale_id query int false "Filiale"
+// @Param expiry_within query int false "Horizon des échéances en jours (défaut: 30)"
+// @Success 200 {object} dto.LicenseComplianceReportDTO
+// @Failure 403 {object} utils.Response
+// @Router /software-licenses/compliance [get]
+func (h *SoftwareLicenseHandler) GetCompliance(c *gin.Context) {
+	if !canViewLicenses(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software_licenses.view")
+		return
+	}
+
+	var filialeID *uint
+	if raw := c.Query("filiale_id"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre filiale_id invalide")
+			return
+		}
+		id := uint(parsed)
+		filialeID = &id
+	}
+	restricted, allowed := licenseFiliale(c)
+	if !allowed || (restricted != nil && filialeID != nil && *filialeID != *restricted) {
+		utils.ForbiddenResponse(c, "Accès limité aux licences de votre filiale")
+		return
+	}
+	if restricted != nil {
+		filialeID = restricted
+	}
+
+	expiryWithin := 30
+	if raw := c.Query("expiry_within"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 || days > 365 {
+			utils.BadRequestResponse(c, "Paramètre expiry_within invalide (nombre de jours)")
+			return
+		}
+		expiryWithin = days
+	}
+
+	report, err := h.licenseService.GetComplianceReport(filialeID, expiryWithin)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Rapport de conformité récupéré avec succès")
+}
+
+// GetByID récupère une licence logicielle
+// @Summary Récupérer une licence logicielle
+// @Description Récupère une licence logicielle (nécessite software_licenses.view ; clé visible avec software_licenses.view_keys)
+// @Tags software-licenses
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la licence"
+// @Success 200 {object} dto.SoftwareLicenseDTO
+// @Failure 404 {object} utils.Response
+// @Router /software-licenses/{id} [get]
+func (h *SoftwareLicenseHandler) GetByID(c *gin.Context) {
+	if !canViewLicenses(c) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software_licenses.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	license, err := h.licenseService.GetByID(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+	if restricted, allowed := licenseFiliale(c); !allowed || (restricted != nil && license.FilialeID != *restricted) {
+		utils.NotFoundResponse(c, "licence introuvable")
+		return
+	}
+
+	utils.SuccessResponse(c, license, "Licence récupérée avec succès")
+}
+
+// Create crée une licence logicielle
+// @Summary Créer une licence logicielle
+// @Description Enregistre une licence détenue par une filiale : postes couverts, clé, dates de validité (nécessite software_licenses.manage)
+// @Tags software-licenses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSoftwareLicenseRequest true "Licence"
+// @Success 201 {object} dto.SoftwareLicenseDTO
+// @Failure 400 {object} utils.Response
+// @Router /software-licenses [post]
+func (h *SoftwareLicenseHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "software_licenses.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software_licenses.manage")
+		return
+	}
+
+	var req dto.CreateSoftwareLicenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+	license, err := h.licenseService.Create(req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, license, "Licence créée avec succès")
+}
+
+// Update met à jour une licence logicielle
+// @Summary Mettre à jour une licence logicielle
+// @Description Met à jour une licence ; une nouvelle date d'expiration (renouvellement) réarme l'alerte d'expiration (nécessite software_licenses.manage)
+// @Tags software-licenses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la licence"
+// @Param request body dto.UpdateSoftwareLicenseRequest true "Champs à modifier"
+// @Success 200 {object} dto.SoftwareLicenseDTO
+// @Failure 404 {object} utils.Response
+// @Router /software-licenses/{id} [put]
+func (h *SoftwareLicenseHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "software_licenses.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software_licenses.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateSoftwareLicenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	license, err := h.licenseService.Update(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, license, "Licence mise à jour avec succès")
+}
+
+// Delete supprime une licence logicielle
+// @Summary Supprimer une licence logicielle
+// @Description Supprime une licence logicielle (nécessite software_licenses.manage)
+// @Tags software-licenses
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la licence"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /software-licenses/{id} [delete]
+func (h *SoftwareLicenseHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "software_licenses.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software_licenses.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.licenseService.Delete(uint(id)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Licence supprimée avec succès")
+}
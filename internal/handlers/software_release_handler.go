@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SoftwareReleaseHandler gère les versions publiées des logiciels
+type SoftwareReleaseHandler struct {
+	releaseService services.SoftwareReleaseService
+}
+
+// NewSoftwareReleaseHandler crée une nouvelle instance de SoftwareReleaseHandler
+func NewSoftwareReleaseHandler(releaseService services.SoftwareReleaseService) *SoftwareReleaseHandler {
+	return &SoftwareReleaseHandler{
+		releaseService: releaseService,
+	}
+}
+
+// releaseErrorResponse traduit une erreur du service en réponse HTTP
+func releaseErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case err.Error() == "version introuvable" || err.Error() == "logiciel introuvable":
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// GetBySoftwareID récupère les versions d'un logiciel
+// @Summary Versions d'un logiciel
+// @Description Liste les versions (en préparation et publiées) d'un logiciel avec le nombre de tickets corrigés (nécessite software.view)
+// @Tags software-releases
+// @Security BearerAuth
+// @Produce json
+// @Param software_id path int true "ID du logiciel"
+// @Success 200 {array} dto.SoftwareReleaseDTO
+// @Failure 404 {object} utils.Response
+// @Router /software/{software_id}/releases [get]
+func (h *SoftwareReleaseHandler) GetBySoftwareID(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.view")
+		return
+	}
+
+	softwareID, err := strconv.ParseUint(c.Param("software_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID du logiciel invalide")
+		return
+	}
+
+	releases, err := h.releaseService.GetBySoftwareID(uint(softwareID))
+	if err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, releases, "Versions récupérées avec succès")
+}
+
+// Create crée une version d'un logiciel
+// @Summary Créer une version
+// @Description Crée une version en préparation : notes de version, anomalies connues, date prévue (nécessite software.update)
+// @Tags software-releases
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param software_id path int true "ID du logiciel"
+// @Param request body dto.CreateSoftwareReleaseRequest true "Version"
+// @Success 201 {object} dto.SoftwareReleaseDTO
+// @Failure 400 {object} utils.Response
+// @Router /software/{software_id}/releases [post]
+func (h *SoftwareReleaseHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.update")
+		return
+	}
+
+	softwareID, err := strconv.ParseUint(c.Param("software_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID du logiciel invalide")
+		return
+	}
+
+	var req dto.CreateSoftwareReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+	release, err := h.releaseService.Create(uint(softwareID), req, userID)
+	if err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, release, "Version créée avec succès")
+}
+
+// GetByID récupère une version
+// @Summary Récupérer une version
+// @Description Récupère une version d'un logiciel (nécessite software.view)
+// @Tags software-releases
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la version"
+// @Success 200 {object} dto.SoftwareReleaseDTO
+// @Failure 404 {object} utils.Response
+// @Router /software-releases/{id} [get]
+func (h *SoftwareReleaseHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	release, err := h.releaseService.GetByID(uint(id))
+	if err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, release, "Version récupérée avec succès")
+}
+
+// Update met à jour une version
+// @Summary Modifier une version
+// @Description Met à jour les notes de version, anomalies connues ou date ; le numéro n'est modifiable qu'avant publication (nécessite software.update)
+// @Tags software-releases
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la version"
+// @Param request body dto.UpdateSoftwareReleaseRequest true "Champs à modifier"
+// @Success 200 {object} dto.SoftwareReleaseDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /software-releases/{id} [put]
+func (h *SoftwareReleaseHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateSoftwareReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	release, err := h.releaseService.Update(uint(id), req)
+	if err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, release, "Version mise à jour avec succès")
+}
+
+// Delete supprime une version en préparation
+// @Summary Supprimer une version
+// @Description Supprime une version non publiée et détache ses tickets (nécessite software.update)
+// @Tags software-releases
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la version"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /software-releases/{id} [delete]
+func (h *SoftwareReleaseHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.releaseService.Delete(uint(id)); err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Version supprimée avec succès")
+}
+
+// Publish publie une version
+// @Summary Publier une version
+// @Description Publie la version et notifie les demandeurs des tickets ouverts qu'elle corrige, lorsque leur filiale utilise une autre version (nécessite software.update)
+// @Tags software-releases
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la version"
+// @Success 200 {object} dto.SoftwareReleaseDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /software-releases/{id}/publish [post]
+func (h *SoftwareReleaseHandler) Publish(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+	release, notified, err := h.releaseService.Publish(uint(id), userID)
+	if err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, release, "Version publiée ("+strconv.Itoa(notified)+" utilisateur(s) notifié(s))")
+}
+
+// GetFixedTickets récupère les tickets corrigés par une version
+// @Summary Tickets corrigés par une version
+// @Description Liste les tickets rattachés à la version (« corrigé dans la version X ») (nécessite software.view)
+// @Tags software-releases
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la version"
+// @Success 200 {array} dto.TicketDTO
+// @Failure 404 {object} utils.Response
+// @Router /software-releases/{id}/tickets [get]
+func (h *SoftwareReleaseHandler) GetFixedTickets(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	tickets, err := h.releaseService.GetFixedTickets(uint(id))
+	if err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, tickets, "Tickets récupérés avec succès")
+}
+
+// LinkTickets rattache des tickets corrigés par une version
+// @Summary Rattacher des tickets à une version
+// @Description Marque des tickets du même logiciel comme corrigés dans la version ; si elle est publiée, les demandeurs concernés sont notifiés (nécessite software.update)
+// @Tags software-releases
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la version"
+// @Param request body dto.LinkReleaseTicketsRequest true "Tickets corrigés"
+// @Success 200 {object} dto.ReleaseTicketsResultDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /software-releases/{id}/tickets [post]
+func (h *SoftwareReleaseHandler) LinkTickets(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.LinkReleaseTicketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.releaseService.LinkTickets(uint(id), req)
+	if err != nil {
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Tickets rattachés à la version")
+}
+
+// UnlinkTicket détache un ticket d'une version
+// @Summary Détacher un ticket d'une version
+// @Description Retire le lien « corrigé dans la version » d'un ticket (nécessite software.update)
+// @Tags software-releases
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la version"
+// @Param ticket_id path int true "ID du ticket"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /software-releases/{id}/tickets/{ticket_id} [delete]
+func (h *SoftwareReleaseHandler) UnlinkTicket(c *gin.Context) {
+	if !utils.RequirePermission(c, "software.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: software.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	ticketID, err := strconv.ParseUint(c.Param("ticket_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID du ticket invalide")
+		return
+	}
+
+	if err := h.releaseService.UnlinkTicket(uint(id), uint(ticketID)); err != nil {
+		if err.Error() == "ticket non rattaché à cette version" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		releaseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Ticket détaché de la version")
+}
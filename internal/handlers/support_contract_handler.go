@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SupportContractHandler gère les contrats de support logiciel
+type SupportContractHandler struct {
+	contractService services.SupportContractService
+}
+
+// NewSupportContractHandler crée une nouvelle instance de SupportContractHandler
+func NewSupportContractHandler(contractService services.SupportContractService) *SupportContractHandler {
+	return &SupportContractHandler{
+		contractService: contractService,
+	}
+}
+
+// contractErrorResponse traduit une erreur du service en réponse HTTP
+func contractErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case err.Error() == "cette référence de contrat existe déjà":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// contractFiliale retourne la filiale à laquelle l'utilisateur est limité pour la consultation des contrats
+// (nil : toutes les filiales, pour les gestionnaires de contrats et les résolveurs de la filiale fournisseur)
+func contractFiliale(c *gin.Context) (*uint, bool) {
+	queryScope := utils.GetScopeFromContext(c)
+	if utils.RequirePermission(c, "support_contracts.manage") || (queryScope != nil && queryScope.IsResolver) {
+		return nil, true
+	}
+	if queryScope == nil || queryScope.FilialeID == nil {
+		return nil, false
+	}
+	return queryScope.FilialeID, true
+}
+
+// GetAll récupère les contrats de support
+// @Summary Lister les contrats de support
+// @Description Liste les contrats de support logiciel (nécessite support_contracts.view). Hors gestionnaires et résolveurs, seuls les contrats de sa filiale sont visibles. renewal_within limite aux contrats actifs à renouveler dans ce nombre de jours
+// @Tags support-contracts
+// @Security BearerAuth
+// @Produce json
+// @Param filiale_id query int false "Filiale cliente"
+// @Param software_id query int false "Logiciel (contrats propres au logiciel ou couvrant tous les logiciels)"
+// @Param active query bool false "Contrats actifs uniquement"
+// @Param renewal_within query int false "Renouvellement dans les N prochains jours"
+// @Success 200 {array} dto.SupportContractDTO
+// @Failure 403 {object} utils.Response
+// @Router /support-contracts [get]
+func (h *SupportContractHandler) GetAll(c *gin.Context) {
+	if !utils.RequirePermission(c, "support_contracts.view") && !utils.RequirePermission(c, "support_contracts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: support_contracts.view")
+		return
+	}
+
+	var filialeID, softwareID *uint
+	for _, param := range []struct {
+		name   string
+		target **uint
+	}{{"filiale_id", &filialeID}, {"software_id", &softwareID}} {
+		if raw := c.Query(param.name); raw != "" {
+			parsed, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				utils.BadRequestResponse(c, "Paramètre "+param.name+" invalide")
+				return
+			}
+			id := uint(parsed)
+			*param.target = &id
+		}
+	}
+
+	restricted, allowed := contractFiliale(c)
+	if !allowed {
+		utils.SuccessResponse(c, []dto.SupportContractDTO{}, "Contrats récupérés avec succès")
+		return
+	}
+	if restricted != nil {
+		if filialeID != nil && *filialeID != *restricted {
+			utils.SuccessResponse(c, []dto.SupportContractDTO{}, "Contrats récupérés avec succès")
+			return
+		}
+		filialeID = restricted
+	}
+
+	activeOnly := false
+	if raw := c.Query("active"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre active invalide")
+			return
+		}
+		activeOnly = parsed
+	}
+
+	var renewalWithin *int
+	if raw := c.Query("renewal_within"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 || days > 3650 {
+			utils.BadRequestResponse(c, "Paramètre renewal_within invalide (nombre de jours)")
+			return
+		}
+		renewalWithin = &days
+	}
+
+	contracts, err := h.contractService.GetAll(filialeID, softwareID, activeOnly, renewalWithin)
+	if err != nil {
+		contractErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, contracts, "Contrats récupérés avec succès")
+}
+
+// GetByID récupère un contrat de support
+// @Summary Récupérer un contrat de support
+// @Description Récupère un contrat de support logiciel (nécessite support_contracts.view)
+// @Tags support-contracts
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du contrat"
+// @Success 200 {object} dto.SupportContractDTO
+// @Failure 404 {object} utils.Response
+// @Router /support-contracts/{id} [get]
+func (h *SupportContractHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "support_contracts.view") && !utils.RequirePermission(c, "support_contracts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: support_contracts.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	contract, err := h.contractService.GetByID(uint(id))
+	if err != nil {
+		contractErrorResponse(c, err)
+		return
+	}
+	if restricted, allowed := contractFiliale(c); !allowed || (restricted != nil && contract.FilialeID != *restricted) {
+		utils.NotFoundResponse(c, "contrat introuvable")
+		return
+	}
+
+	utils.SuccessResponse(c, contract, "Contrat récupéré avec succès")
+}
+
+// Create crée un contrat de support
+// @Summary Créer un contrat de support
+// @Description Crée un contrat de support entre la filiale fournisseur et une filiale cliente : niveau de support (qui détermine les SLA des tickets couverts), dates, interlocuteurs et modules couverts (nécessite support_contracts.manage)
+// @Tags support-contracts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSupportContractRequest true "Contrat"
+// @Success 201 {object} dto.SupportContractDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /support-contracts [post]
+func (h *SupportContractHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "support_contracts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: support_contracts.manage")
+		return
+	}
+
+	var req dto.CreateSupportContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+	contract, err := h.contractService.Create(req, userID)
+	if err != nil {
+		contractErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, contract, "Contrat créé avec succès")
+}
+
+// Update met à jour un contrat de support
+// @Summary Mettre à jour un contrat de support
+// @Description Met à jour un contrat de support ; is_active=false résilie le contrat (nécessite support_contracts.manage)
+// @Tags support-contracts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du contrat"
+// @Param request body dto.UpdateSupportContractRequest true "Champs à modifier"
+// @Success 200 {object} dto.SupportContractDTO
+// @Failure 404 {object} utils.Response
+// @Router /support-contracts/{id} [put]
+func (h *SupportContractHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "support_contracts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: support_contracts.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateSupportContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	contract, err := h.contractService.Update(uint(id), req)
+	if err != nil {
+		contractErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, contract, "Contrat mis à jour avec succès")
+}
+
+// Delete supprime un contrat de support
+// @Summary Supprimer un contrat de support
+// @Description Supprime un contrat de support (nécessite support_contracts.manage)
+// @Tags support-contracts
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du contrat"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /support-contracts/{id} [delete]
+func (h *SupportContractHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "support_contracts.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: support_contracts.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.contractService.Delete(uint(id)); err != nil {
+		contractErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Contrat supprimé avec succès")
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SyncHandler gère la synchronisation incrémentale de l'application mobile
+type SyncHandler struct {
+	syncService services.SyncService
+}
+
+// NewSyncHandler crée une nouvelle instance de SyncHandler
+func NewSyncHandler(syncService services.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// GetChanges récupère les modifications depuis la dernière synchronisation
+// @Summary Modifications depuis la dernière synchronisation
+// @Description Retourne, par entité (tickets, comments, notifications, tasks), les IDs créés, modifiés et supprimés depuis since dans le périmètre de l'utilisateur. Renvoyer le curseur reçu dans since à l'appel suivant ; tant que has_more est vrai, rappeler immédiatement. Sans since (ou curseur de plus de 90 jours), reset est vrai et created contient tous les éléments visibles.
+// @Tags sync
+// @Security BearerAuth
+// @Produce json
+// @Param since query string false "Curseur d'une réponse précédente ou date RFC 3339"
+// @Param entities query string false "Entités séparées par des virgules (défaut: toutes)"
+// @Success 200 {object} dto.SyncChangesDTO
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /sync/changes [get]
+func (h *SyncHandler) GetChanges(c *gin.Context) {
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	var entities []string
+	for _, entity := range strings.Split(c.Query("entities"), ",") {
+		if entity = strings.TrimSpace(entity); entity != "" {
+			entities = append(entities, entity)
+		}
+	}
+
+	changes, err := h.syncService.GetChanges(queryScope, c.Query("since"), entities)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "erreur lors") {
+			utils.InternalServerErrorResponse(c, err.Error())
+		} else {
+			utils.BadRequestResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, changes, "Modifications récupérées avec succès")
+}
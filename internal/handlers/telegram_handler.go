@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// telegramWebhookMaxSize taille maximale d'une mise à jour reçue
+const telegramWebhookMaxSize = 1 << 20
+
+// TelegramHandler gère la liaison des comptes au bot Telegram et son webhook
+type TelegramHandler struct {
+	telegramService services.TelegramService
+}
+
+// NewTelegramHandler crée une nouvelle instance de TelegramHandler
+func NewTelegramHandler(telegramService services.TelegramService) *TelegramHandler {
+	return &TelegramHandler{
+		telegramService: telegramService,
+	}
+}
+
+// telegramErrorResponse traduit une erreur du service en réponse HTTP
+func telegramErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasSuffix(err.Error(), "non configuré"):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// GetStatus retourne l'état de la liaison Telegram de l'utilisateur connecté
+// @Summary État de la liaison Telegram
+// @Description Indique si le bot est configuré et si le compte est lié à une conversation Telegram
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.TelegramLinkDTO
+// @Router /integrations/telegram [get]
+func (h *TelegramHandler) GetStatus(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	status, err := h.telegramService.Status(userID)
+	if err != nil {
+		telegramErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, status, "Liaison Telegram récupérée avec succès")
+}
+
+// CreateLink génère le lien de liaison du compte au bot
+// @Summary Lier son compte Telegram
+// @Description Retourne un lien t.me à usage unique (valable 15 minutes). En l'ouvrant, l'utilisateur démarre la conversation avec le bot qui lie alors son compte : il y reçoit ses notifications, avec des boutons pour prendre en charge, s'assigner ou clôturer les tickets selon ses permissions
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.TelegramLinkDTO
+// @Failure 503 {object} utils.Response
+// @Router /integrations/telegram/link [post]
+func (h *TelegramHandler) CreateLink(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	link, err := h.telegramService.CreateLink(userID)
+	if err != nil {
+		telegramErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, link, "Lien de liaison Telegram généré")
+}
+
+// Unlink supprime la liaison Telegram de l'utilisateur connecté
+// @Summary Délier son compte Telegram
+// @Description Le bot cesse d'envoyer des notifications à la conversation liée
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /integrations/telegram/link [delete]
+func (h *TelegramHandler) Unlink(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.telegramService.Unlink(userID); err != nil {
+		telegramErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Liaison Telegram supprimée avec succès")
+}
+
+// Webhook reçoit les mises à jour du bot (messages et clics sur les boutons)
+// @Summary Webhook Telegram entrant
+// @Description Point d'entrée des mises à jour du bot, déclaré auprès de Telegram au démarrage. Authentifié par l'en-tête X-Telegram-Bot-Api-Secret-Token
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /integrations/telegram/webhook [post]
+func (h *TelegramHandler) Webhook(c *gin.Context) {
+	if !h.telegramService.Enabled() {
+		utils.NotFoundResponse(c, "Bot Telegram non configuré")
+		return
+	}
+	if !h.telegramService.VerifyWebhook(c.GetHeader("X-Telegram-Bot-Api-Secret-Token")) {
+		utils.UnauthorizedResponse(c, "Secret du webhook invalide")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, telegramWebhookMaxSize))
+	if err != nil {
+		utils.BadRequestResponse(c, "Contenu du webhook illisible")
+		return
+	}
+
+	if err := h.telegramService.HandleUpdate(c.Request.Context(), body); err != nil {
+		telegramErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Mise à jour traitée")
+}
@@ -1,17 +1,14 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/config"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/logger"
 	"github.com/mcicare/itsm-backend/internal/services"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
@@ -30,7 +27,7 @@ func NewTicketAttachmentHandler(attachmentService services.TicketAttachmentServi
 
 // UploadAttachment upload une pièce jointe pour un ticket
 // @Summary Uploader une pièce jointe
-// @Description Upload une pièce jointe (image ou document) pour un ticket
+// @Description Upload une pièce jointe (image ou document) pour un ticket. L'extension doit être autorisée (ATTACHMENT_ALLOWED_EXTENSIONS) et correspondre au contenu du fichier, dont le type MIME est déduit ; la taille est limitée à MAX_UPLOAD_SIZE et le fichier est analysé par l'antivirus configuré (ANTIVIRUS_DRIVER) avant d'être stocké
 // @Tags tickets
 // @Security BearerAuth
 // @Accept multipart/form-data
@@ -41,6 +38,10 @@ func NewTicketAttachmentHandler(attachmentService services.TicketAttachmentServi
 // @Param display_order formData int false "Ordre d'affichage"
 // @Success 201 {object} dto.TicketAttachmentDTO
 // @Failure 400 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 503 {object} utils.Response
 // @Router /tickets/{id}/attachments [post]
 func (h *TicketAttachmentHandler) UploadAttachment(c *gin.Context) {
 	ticketIDParam := c.Param("id")
@@ -59,64 +60,10 @@ func (h *TicketAttachmentHandler) UploadAttachment(c *gin.Context) {
 
 	// Vérifier la taille
 	if file.Size > config.AppConfig.MaxUploadSize {
-		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Fichier trop volumineux. Taille maximale: %d bytes", config.AppConfig.MaxUploadSize), nil)
+		utils.ServiceErrorResponse(c, utils.ErrAttachmentTooLarge.WithDetails(map[string]int64{"max_size": config.AppConfig.MaxUploadSize}))
 		return
 	}
 
-	// Vérifier le type de fichier
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	allowedExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".pdf", ".doc", ".docx", ".xls", ".xlsx", ".txt", ".zip"}
-	isAllowed := false
-	for _, allowedExt := range allowedExts {
-		if ext == allowedExt {
-			isAllowed = true
-			break
-		}
-	}
-	if !isAllowed {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Type de fichier non autorisé", nil)
-		return
-	}
-
-	// Déterminer si c'est une image
-	isImage := false
-	imageExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
-	for _, imgExt := range imageExts {
-		if ext == imgExt {
-			isImage = true
-			break
-		}
-	}
-
-	// Créer le dossier de destination s'il n'existe pas
-	attachmentsDir := config.AppConfig.TicketAttachmentsDir
-	ticketDir := filepath.Join(attachmentsDir, fmt.Sprintf("ticket_%d", ticketID))
-	if err := os.MkdirAll(ticketDir, 0755); err != nil {
-		utils.InternalServerErrorResponse(c, "Erreur lors de la création du dossier de destination")
-		return
-	}
-
-	// Générer un nom de fichier unique
-	timestamp := time.Now().Unix()
-	fileName := fmt.Sprintf("%d_%s", timestamp, file.Filename)
-	filePath := filepath.Join(ticketDir, fileName)
-
-	// Sauvegarder le fichier
-	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		utils.InternalServerErrorResponse(c, "Erreur lors de la sauvegarde du fichier")
-		return
-	}
-
-	// Générer le chemin relatif
-	relativePath := filepath.Join(fmt.Sprintf("ticket_%d", ticketID), fileName)
-
-	// Générer une miniature si c'est une image (TODO: implémenter la génération de miniature)
-	thumbnailPath := ""
-	if isImage {
-		// Pour l'instant, on utilise le fichier original comme miniature
-		thumbnailPath = relativePath
-	}
-
 	// Récupérer les paramètres optionnels
 	description := c.PostForm("description")
 	displayOrderStr := c.PostForm("display_order")
@@ -134,29 +81,25 @@ func (h *TicketAttachmentHandler) UploadAttachment(c *gin.Context) {
 		return
 	}
 
-	// Détecter le type MIME
-	mimeType := file.Header.Get("Content-Type")
-	if mimeType == "" {
-		mimeType = "application/octet-stream"
+	// Ouvrir le fichier reçu
+	content, err := file.Open()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
 	}
+	defer content.Close()
 
-	// Créer l'attachment
+	// Vérifier et enregistrer le fichier (extension, contenu, taille, antivirus) puis créer l'attachment
 	attachment, err := h.attachmentService.UploadAttachment(
 		uint(ticketID),
 		file.Filename,
-		relativePath,
-		thumbnailPath,
-		int(file.Size),
-		mimeType,
-		isImage,
+		content,
 		description,
 		displayOrder,
 		userID.(uint),
 	)
 	if err != nil {
-		// Supprimer le fichier en cas d'erreur
-		os.Remove(filePath)
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -187,11 +130,11 @@ func (h *TicketAttachmentHandler) GetAttachments(c *gin.Context) {
 
 	attachments, err := h.attachmentService.GetByTicketID(uint(ticketID), imagesOnly)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	fmt.Printf("PERF GetAttachments ticket=%d imagesOnly=%v count=%d dur=%s\n", ticketID, imagesOnly, len(attachments), time.Since(start))
+	logger.FromContext(c.Request.Context()).Debug("perf_ticket_attachments", "ticket_id", ticketID, "images_only", imagesOnly, "count", len(attachments), "duration_ms", time.Since(start).Milliseconds())
 	utils.SuccessResponse(c, attachments, "Pièces jointes récupérées avec succès")
 }
 
@@ -216,11 +159,11 @@ func (h *TicketAttachmentHandler) GetImages(c *gin.Context) {
 
 	images, err := h.attachmentService.GetImagesByTicketID(uint(ticketID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	fmt.Printf("PERF GetImages ticket=%d count=%d dur=%s\n", ticketID, len(images), time.Since(start))
+	logger.FromContext(c.Request.Context()).Debug("perf_ticket_images", "ticket_id", ticketID, "count", len(images), "duration_ms", time.Since(start).Milliseconds())
 	utils.SuccessResponse(c, images, "Images récupérées avec succès")
 }
 
@@ -278,14 +221,14 @@ func (h *TicketAttachmentHandler) Download(c *gin.Context) {
 		return
 	}
 
-	filePath, err := h.attachmentService.GetFilePathForTicket(uint(ticketID), uint(attachmentID))
+	object, fileName, err := h.attachmentService.OpenFileForTicket(uint(ticketID), uint(attachmentID))
 	if err != nil {
 		utils.NotFoundResponse(c, err.Error())
 		return
 	}
 
-	fmt.Printf("PERF DownloadAttachment id=%d dur=%s\n", attachmentID, time.Since(start))
-	c.File(filePath)
+	logger.FromContext(c.Request.Context()).Debug("perf_attachment_download", "attachment_id", attachmentID, "duration_ms", time.Since(start).Milliseconds())
+	serveStorageObject(c, object, fileName)
 }
 
 // GetThumbnail récupère la miniature d'une image
@@ -314,14 +257,47 @@ func (h *TicketAttachmentHandler) GetThumbnail(c *gin.Context) {
 		return
 	}
 
-	thumbnailPath, err := h.attachmentService.GetThumbnailPathForTicket(uint(ticketID), uint(attachmentID))
+	object, fileName, err := h.attachmentService.OpenThumbnailForTicket(uint(ticketID), uint(attachmentID))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).Debug("perf_attachment_thumbnail", "attachment_id", attachmentID, "duration_ms", time.Since(start).Milliseconds())
+	serveStorageObject(c, object, fileName)
+}
+
+// GetDownloadURL retourne une URL temporaire de téléchargement direct
+// @Summary URL de téléchargement signée
+// @Description Retourne une URL temporaire (présignée S3 ou signée par l'API en stockage local) permettant de télécharger la pièce jointe sans jeton
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param attachmentId path int true "ID de la pièce jointe"
+// @Success 200 {object} dto.SignedURLDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/attachments/{attachmentId}/url [get]
+func (h *TicketAttachmentHandler) GetDownloadURL(c *gin.Context) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de pièce jointe invalide")
+		return
+	}
+
+	ttl := config.AppConfig.Storage.SignedURLTTL
+	url, err := h.attachmentService.SignedURLForTicket(uint(ticketID), uint(attachmentID), ttl)
 	if err != nil {
 		utils.NotFoundResponse(c, err.Error())
 		return
 	}
 
-	fmt.Printf("PERF GetThumbnail id=%d dur=%s\n", attachmentID, time.Since(start))
-	c.File(thumbnailPath)
+	utils.SuccessResponse(c, dto.SignedURLDTO{URL: url, ExpiresAt: time.Now().Add(ttl)}, "URL de téléchargement générée")
 }
 
 // Update met à jour une pièce jointe
@@ -347,7 +323,7 @@ func (h *TicketAttachmentHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateTicketAttachmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -359,7 +335,7 @@ func (h *TicketAttachmentHandler) Update(c *gin.Context) {
 
 	attachment, err := h.attachmentService.Update(uint(attachmentID), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -400,7 +376,7 @@ func (h *TicketAttachmentHandler) SetPrimary(c *gin.Context) {
 
 	attachment, err := h.attachmentService.SetPrimary(uint(ticketID), uint(attachmentID), updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -428,7 +404,7 @@ func (h *TicketAttachmentHandler) Delete(c *gin.Context) {
 
 	err = h.attachmentService.Delete(uint(attachmentID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -457,7 +433,7 @@ func (h *TicketAttachmentHandler) Reorder(c *gin.Context) {
 
 	var req dto.ReorderTicketAttachmentsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -469,7 +445,7 @@ func (h *TicketAttachmentHandler) Reorder(c *gin.Context) {
 
 	err = h.attachmentService.Reorder(uint(ticketID), req.AttachmentIDs, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketChecklistHandler gère les handlers de la checklist des tickets
+type TicketChecklistHandler struct {
+	ticketChecklistService services.TicketChecklistService
+}
+
+// NewTicketChecklistHandler crée une nouvelle instance de TicketChecklistHandler
+func NewTicketChecklistHandler(ticketChecklistService services.TicketChecklistService) *TicketChecklistHandler {
+	return &TicketChecklistHandler{
+		ticketChecklistService: ticketChecklistService,
+	}
+}
+
+// parseChecklistItemIDs lit l'ID du ticket et l'ID de l'étape dans l'URL
+func parseChecklistItemIDs(c *gin.Context) (uint, uint, bool) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return 0, 0, false
+	}
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID d'étape invalide")
+		return 0, 0, false
+	}
+	return uint(ticketID), uint(itemID), true
+}
+
+// GetChecklist récupère la checklist d'un ticket
+// @Summary Checklist d'un ticket
+// @Description Récupère les étapes de résolution du ticket dans l'ordre d'affichage, avec l'avancement en pourcentage
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 200 {object} dto.TicketChecklistDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/checklist [get]
+func (h *TicketChecklistHandler) GetChecklist(c *gin.Context) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return
+	}
+
+	checklist, err := h.ticketChecklistService.GetChecklist(uint(ticketID), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, checklist, "Checklist récupérée avec succès")
+}
+
+// AddItem ajoute une étape à la checklist d'un ticket
+// @Summary Ajouter une étape à la checklist
+// @Description Ajoute une étape en fin de checklist, éventuellement confiée à un utilisateur
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param request body dto.CreateTicketChecklistItemRequest true "Étape à ajouter"
+// @Success 201 {object} dto.TicketChecklistDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/checklist [post]
+func (h *TicketChecklistHandler) AddItem(c *gin.Context) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return
+	}
+
+	var req dto.CreateTicketChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	checklist, err := h.ticketChecklistService.AddItem(uint(ticketID), req, utils.GetScopeFromContext(c), createdByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, checklist, "Étape ajoutée avec succès")
+}
+
+// UpdateItem met à jour une étape de la checklist
+// @Summary Mettre à jour une étape de la checklist
+// @Description Modifie le libellé ou l'utilisateur chargé de l'étape (clear_assignee le retire)
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param itemId path int true "ID de l'étape"
+// @Param request body dto.UpdateTicketChecklistItemRequest true "Données à mettre à jour"
+// @Success 200 {object} dto.TicketChecklistDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/checklist/{itemId} [put]
+func (h *TicketChecklistHandler) UpdateItem(c *gin.Context) {
+	ticketID, itemID, ok := parseChecklistItemIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTicketChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	checklist, err := h.ticketChecklistService.UpdateItem(ticketID, itemID, req, utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, checklist, "Étape mise à jour avec succès")
+}
+
+// ToggleItem coche ou décoche une étape de la checklist
+// @Summary Cocher / décocher une étape
+// @Description Inverse l'état de l'étape ; l'utilisateur et la date sont enregistrés lorsqu'elle est cochée
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param itemId path int true "ID de l'étape"
+// @Success 200 {object} dto.TicketChecklistDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/checklist/{itemId}/toggle [post]
+func (h *TicketChecklistHandler) ToggleItem(c *gin.Context) {
+	ticketID, itemID, ok := parseChecklistItemIDs(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	checklist, err := h.ticketChecklistService.ToggleItem(ticketID, itemID, utils.GetScopeFromContext(c), userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, checklist, "Étape mise à jour avec succès")
+}
+
+// Reorder réorganise la checklist d'un ticket
+// @Summary Réorganiser la checklist
+// @Description Réorganise l'ordre d'affichage des étapes du ticket
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param request body dto.ReorderTicketChecklistRequest true "Liste des IDs dans le nouvel ordre"
+// @Success 200 {object} dto.TicketChecklistDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/checklist/reorder [put]
+func (h *TicketChecklistHandler) Reorder(c *gin.Context) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de ticket invalide")
+		return
+	}
+
+	var req dto.ReorderTicketChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	checklist, err := h.ticketChecklistService.Reorder(uint(ticketID), req.ItemIDs, utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, checklist, "Ordre de la checklist mis à jour avec succès")
+}
+
+// DeleteItem supprime une étape de la checklist
+// @Summary Supprimer une étape de la checklist
+// @Description Supprime une étape de la checklist du ticket
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param itemId path int true "ID de l'étape"
+// @Success 200 {object} dto.TicketChecklistDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/checklist/{itemId} [delete]
+func (h *TicketChecklistHandler) DeleteItem(c *gin.Context) {
+	ticketID, itemID, ok := parseChecklistItemIDs(c)
+	if !ok {
+		return
+	}
+
+	checklist, err := h.ticketChecklistService.DeleteItem(ticketID, itemID, utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, checklist, "Étape supprimée avec succès")
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/spreadsheet"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketExportHandler gère l'export des tickets
+type TicketExportHandler struct {
+	ticketExportService services.TicketExportService
+}
+
+// NewTicketExportHandler crée une nouvelle instance de TicketExportHandler
+func NewTicketExportHandler(ticketExportService services.TicketExportService) *TicketExportHandler {
+	return &TicketExportHandler{
+		ticketExportService: ticketExportService,
+	}
+}
+
+// Export exporte les tickets filtrés
+// @Summary Exporter les tickets
+// @Description Exporte en flux (CSV ou XLSX) tous les tickets visibles par l'utilisateur, avec les mêmes filtres que la liste des tickets. Colonnes disponibles : id, code, title, description, category, source, status, priority, filiale, software, requester, requester_department, assigned_to, created_by, estimated_time, actual_time, parent_id, created_at, updated_at, closed_at (dates dans le fuseau de l'utilisateur)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Format d'export : csv (défaut) ou xlsx"
+// @Param columns query string false "Colonnes à exporter, dans l'ordre (ex: code,title,status,assigned_to) ; par défaut code, title, category, status, priority, filiale, requester, assigned_to, created_at, closed_at"
+// @Param status query string false "Filtrer par statut (ouvert, en_cours, en_attente, resolu, cloture)"
+// @Param filiale_id query int false "Filtrer par ID filiale"
+// @Param user_id query int false "Filtrer par ID utilisateur assigné"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /tickets/export [get]
+func (h *TicketExportHandler) Export(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", spreadsheet.FormatCSV))
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		utils.BadRequestResponse(c, "Format d'export non supporté (csv ou xlsx)")
+		return
+	}
+	columns, err := h.ticketExportService.ParseColumns(c.Query("columns"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var filter repositories.TicketFilter
+	if status := c.Query("status"); status != "" {
+		filter.Statuses = []string{status}
+	}
+	if raw := c.Query("filiale_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID de filiale invalide")
+			return
+		}
+		filialeID := uint(id)
+		filter.FilialeID = &filialeID
+	}
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID utilisateur invalide")
+			return
+		}
+		assigneeID := uint(id)
+		filter.AssigneeID = &assigneeID
+	}
+
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tickets_%s.%s"`, time.Now().Format("20060102_150405"), format))
+	c.Status(http.StatusOK)
+
+	queryScope := utils.GetScopeFromContext(c)
+	if err := h.ticketExportService.Export(queryScope, filter, format, columns, c.Writer); err != nil {
+		// L'export a pu commencer : la réponse ne peut plus être remplacée par une erreur JSON
+		if c.Writer.Size() <= 0 {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		log.Printf("Tickets: export interrompu: %v", err)
+	}
+}
@@ -1,13 +1,12 @@
 package handlers
 
 import (
-	"fmt"
-	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/logger"
 	"github.com/mcicare/itsm-backend/internal/services"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
@@ -44,7 +43,7 @@ func (h *TicketHandler) Create(c *gin.Context) {
 
 	var req dto.CreateTicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -86,7 +85,7 @@ func (h *TicketHandler) Create(c *gin.Context) {
 
 	ticket, err := h.ticketService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -132,6 +131,7 @@ func (h *TicketHandler) GetByID(c *gin.Context) {
 // @Param status query string false "Filtrer par statut (ouvert, en_cours, en_attente, resolu, cloture)"
 // @Param filiale_id query int false "Filtrer par ID filiale"
 // @Param user_id query int false "Filtrer par ID utilisateur assigné"
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 500 {object} utils.Response
 // @Router /tickets [get]
@@ -150,7 +150,6 @@ func (h *TicketHandler) GetAll(c *gin.Context) {
 	}
 
 	queryScope := utils.GetScopeFromContext(c)
-	utils.ApplyDashboardScopeHint(c, queryScope)
 
 	var filialeID *uint
 	if filialeIDStr != "" {
@@ -179,7 +178,7 @@ func (h *TicketHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, response, "Tickets récupérés avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets récupérés avec succès")
 }
 
 // GetByDepartment récupère les tickets par département du demandeur
@@ -191,6 +190,7 @@ func (h *TicketHandler) GetAll(c *gin.Context) {
 // @Param departmentId path int true "ID du département"
 // @Param page query int false "Numéro de page" default(1)
 // @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -219,7 +219,7 @@ func (h *TicketHandler) GetByDepartment(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, response, "Tickets par département récupérés avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets par département récupérés avec succès")
 }
 
 // Update met à jour un ticket
@@ -244,15 +244,11 @@ func (h *TicketHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateTicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		// Log l'erreur de validation pour déboguer
-		fmt.Printf("DEBUG: Erreur de validation lors de la mise à jour du ticket: %v\n", err)
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		logger.FromContext(c.Request.Context()).Debug("ticket_update_invalid_payload", "error", err)
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
-	// Log la requête reçue pour déboguer
-	fmt.Printf("DEBUG: Requête de mise à jour reçue - Category: %s\n", req.Category)
-
 	updatedByID, exists := c.Get("user_id")
 	if !exists {
 		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
@@ -261,7 +257,7 @@ func (h *TicketHandler) Update(c *gin.Context) {
 
 	ticket, err := h.ticketService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -291,10 +287,10 @@ func (h *TicketHandler) Assign(c *gin.Context) {
 
 	var req dto.AssignTicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
-	fmt.Printf("DEBUG: Assign handler - ticket=%s user_ids=%v lead_id=%v\n", idParam, req.UserIDs, req.LeadID)
+	logger.FromContext(c.Request.Context()).Debug("ticket_assign", "ticket", idParam, "user_ids", req.UserIDs, "lead_id", req.LeadID)
 
 	assignedByID, exists := c.Get("user_id")
 	if !exists {
@@ -304,11 +300,11 @@ func (h *TicketHandler) Assign(c *gin.Context) {
 
 	ticket, err := h.ticketService.Assign(uint(id), req, assignedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	fmt.Printf("PERF Assign ticket=%d users=%d dur=%s\n", id, len(req.UserIDs), time.Since(start))
+	logger.FromContext(c.Request.Context()).Debug("perf_ticket_assign_handler", "ticket_id", id, "users", len(req.UserIDs), "duration_ms", time.Since(start).Milliseconds())
 	utils.SuccessResponse(c, ticket, "Ticket assigné avec succès")
 }
 
@@ -337,7 +333,7 @@ func (h *TicketHandler) ChangeStatus(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -349,7 +345,7 @@ func (h *TicketHandler) ChangeStatus(c *gin.Context) {
 
 	ticket, err := h.ticketService.ChangeStatus(uint(id), req.Status, changedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -382,7 +378,7 @@ func (h *TicketHandler) Close(c *gin.Context) {
 
 	ticket, err := h.ticketService.Close(uint(id), closedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -433,7 +429,7 @@ func (h *TicketHandler) ValidateTicket(c *gin.Context) {
 	// Valider le ticket
 	validatedTicket, err := h.ticketService.ValidateTicket(uint(id), validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -490,7 +486,7 @@ func (h *TicketHandler) AddComment(c *gin.Context) {
 
 	var req dto.CreateTicketCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -502,17 +498,17 @@ func (h *TicketHandler) AddComment(c *gin.Context) {
 
 	comment, err := h.ticketService.AddComment(uint(ticketID), req, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	fmt.Printf("PERF AddComment ticket=%d user=%d len=%d dur=%s\n", ticketID, userID.(uint), len(req.Comment), time.Since(start))
+	logger.FromContext(c.Request.Context()).Debug("perf_ticket_add_comment", "ticket_id", ticketID, "length", len(req.Comment), "duration_ms", time.Since(start).Milliseconds())
 	utils.CreatedResponse(c, comment, "Commentaire ajouté avec succès")
 }
 
 // GetComments récupère les commentaires d'un ticket
 // @Summary Récupérer les commentaires
-// @Description Récupère tous les commentaires d'un ticket
+// @Description Récupère tous les commentaires d'un ticket (commentaires internes réservés à l'IT ou à tickets.comments.view_internal)
 // @Tags tickets
 // @Security BearerAuth
 // @Produce json
@@ -529,7 +525,8 @@ func (h *TicketHandler) GetComments(c *gin.Context) {
 	}
 
 	scope := utils.GetScopeFromContext(c)
-	canViewInternal := scope != nil && scope.DepartmentIsIT
+	// Commentaires internes : départements IT, ou rôles disposant de tickets.comments.view_internal
+	canViewInternal := scope != nil && (scope.DepartmentIsIT || scope.HasPermission("tickets.comments.view_internal"))
 
 	comments, err := h.ticketService.GetComments(uint(ticketID), canViewInternal)
 	if err != nil {
@@ -568,7 +565,7 @@ func (h *TicketHandler) UpdateComment(c *gin.Context) {
 	}
 	var req dto.UpdateTicketCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	userID, exists := c.Get("user_id")
@@ -578,7 +575,7 @@ func (h *TicketHandler) UpdateComment(c *gin.Context) {
 	}
 	comment, err := h.ticketService.UpdateComment(uint(ticketID), uint(commentID), req, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, comment, "Commentaire modifié avec succès")
@@ -614,7 +611,7 @@ func (h *TicketHandler) DeleteComment(c *gin.Context) {
 	}
 	err = h.ticketService.DeleteComment(uint(ticketID), uint(commentID), userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Commentaire supprimé avec succès")
@@ -642,7 +639,7 @@ func (h *TicketHandler) Reassign(c *gin.Context) {
 
 	var req dto.AssignTicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -654,7 +651,7 @@ func (h *TicketHandler) Reassign(c *gin.Context) {
 
 	ticket, err := h.ticketService.Assign(uint(id), req, reassignedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -697,6 +694,7 @@ func (h *TicketHandler) GetHistory(c *gin.Context) {
 // @Param source path string true "Source (mail, appel, direct)"
 // @Param page query int false "Numéro de page" default(1)
 // @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 400 {object} utils.Response
 // @Router /tickets/by-source/{source} [get]
@@ -717,11 +715,11 @@ func (h *TicketHandler) GetBySource(c *gin.Context) {
 
 	response, err := h.ticketService.GetBySource(queryScope, source, page, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	utils.SuccessResponse(c, response, "Tickets récupérés avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets récupérés avec succès")
 }
 
 // GetByCategory récupère les tickets par catégorie
@@ -735,6 +733,7 @@ func (h *TicketHandler) GetBySource(c *gin.Context) {
 // @Param limit query int false "Nombre d'éléments par page" default(20)
 // @Param status query string false "Filtrer par statut (ouvert, en_cours, en_attente, cloture)" default(all)
 // @Param priority query string false "Filtrer par priorité (low, medium, high, critical)" default(all)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 400 {object} utils.Response
 // @Router /tickets/by-category/{category} [get]
@@ -757,8 +756,8 @@ func (h *TicketHandler) GetByCategory(c *gin.Context) {
 		priority = "all"
 	}
 
-	fmt.Printf("DEBUG: GetByCategory - category: %s, page: %d, limit: %d, status: '%s' (param: '%s'), priority: '%s' (param: '%s')\n",
-		category, page, limit, status, statusParam, priority, priorityParam)
+	logger.FromContext(c.Request.Context()).Debug("tickets_by_category",
+		"category", category, "page", page, "limit", limit, "status", status, "priority", priority)
 
 	if page < 1 {
 		page = 1
@@ -772,18 +771,11 @@ func (h *TicketHandler) GetByCategory(c *gin.Context) {
 
 	response, err := h.ticketService.GetByCategory(queryScope, category, page, limit, status, priority)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	fmt.Printf("DEBUG: GetByCategory - Nombre de tickets retournés: %d\n", len(response.Tickets))
-	for i, ticket := range response.Tickets {
-		if i < 3 { // Afficher les 3 premiers pour debug
-			fmt.Printf("DEBUG: Ticket %d - ID: %d, Priority: %s\n", i+1, ticket.ID, ticket.Priority)
-		}
-	}
-
-	utils.SuccessResponse(c, response, "Tickets récupérés avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets récupérés avec succès")
 }
 
 // GetByStatus récupère les tickets par statut
@@ -795,6 +787,7 @@ func (h *TicketHandler) GetByCategory(c *gin.Context) {
 // @Param status path string true "Statut (ouvert, en_cours, en_attente, cloture)"
 // @Param page query int false "Numéro de page" default(1)
 // @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 400 {object} utils.Response
 // @Router /tickets/by-status/{status} [get]
@@ -815,11 +808,11 @@ func (h *TicketHandler) GetByStatus(c *gin.Context) {
 
 	response, err := h.ticketService.GetByStatus(queryScope, status, page, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	utils.SuccessResponse(c, response, "Tickets récupérés avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets récupérés avec succès")
 }
 
 // GetByAssignee récupère les tickets assignés à un utilisateur
@@ -831,6 +824,7 @@ func (h *TicketHandler) GetByStatus(c *gin.Context) {
 // @Param userId path int true "ID de l'utilisateur"
 // @Param page query int false "Numéro de page" default(1)
 // @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 400 {object} utils.Response
 // @Router /tickets/by-assignee/{userId} [get]
@@ -854,11 +848,11 @@ func (h *TicketHandler) GetByAssignee(c *gin.Context) {
 
 	response, err := h.ticketService.GetByAssignedTo(uint(userID), page, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	utils.SuccessResponse(c, response, "Tickets récupérés avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets récupérés avec succès")
 }
 
 // GetMyPanier récupère le panier de l'utilisateur: tickets qui lui sont assignés et non clôturés
@@ -869,6 +863,7 @@ func (h *TicketHandler) GetByAssignee(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Numéro de page" default(1)
 // @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 400 {object} utils.Response
 // @Router /tickets/panier [get]
@@ -888,10 +883,10 @@ func (h *TicketHandler) GetMyPanier(c *gin.Context) {
 	}
 	response, err := h.ticketService.GetPanier(userID.(uint), page, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
-	utils.SuccessResponse(c, response, "Panier récupéré avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Panier récupéré avec succès")
 }
 
 // GetMyTickets récupère les tickets de l'utilisateur connecté
@@ -902,6 +897,7 @@ func (h *TicketHandler) GetMyPanier(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Numéro de page" default(1)
 // @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
 // @Success 200 {object} dto.TicketListResponse
 // @Failure 400 {object} utils.Response
 // @Router /tickets/my-tickets [get]
@@ -925,11 +921,11 @@ func (h *TicketHandler) GetMyTickets(c *gin.Context) {
 
 	status := c.Query("status")
 	response, err := h.ticketService.GetByUser(userID.(uint), page, limit, status)
-	fmt.Printf("PERF GetMyTickets user=%d page=%d limit=%d status=%s err=%v dur=%s\n", userID.(uint), page, limit, status, err, time.Since(start))
+	logger.FromContext(c.Request.Context()).Debug("perf_my_tickets", "page", page, "limit", limit, "status", status, "duration_ms", time.Since(start).Milliseconds())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	utils.SuccessResponse(c, response, "Tickets récupérés avec succès")
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets récupérés avec succès")
 }
@@ -96,9 +96,8 @@ func (h *TicketInternalHandler) GetMyPerformance(c *gin.Context) {
 		return
 	}
 	userID := userIDVal.(uint)
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
 	if !scope.HasPermission("tickets_internes.view_own") && !scope.HasPermission("tickets_internes.view_department") &&
@@ -129,9 +128,8 @@ func (h *TicketInternalHandler) GetMyPerformance(c *gin.Context) {
 // @Failure 403 {object} utils.Response
 // @Router /ticket-internes [get]
 func (h *TicketInternalHandler) GetAll(c *gin.Context) {
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
 	if !scope.HasPermission("tickets_internes.view_own") && !scope.HasPermission("tickets_internes.view_department") &&
@@ -139,7 +137,6 @@ func (h *TicketInternalHandler) GetAll(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusForbidden, "Vous n'avez pas la permission de voir les tickets internes", nil)
 		return
 	}
-	utils.ApplyDashboardScopeHint(c, scope)
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
@@ -190,9 +187,8 @@ func (h *TicketInternalHandler) GetByID(c *gin.Context) {
 		utils.BadRequestResponse(c, "ID invalide")
 		return
 	}
-	scope := utils.GetScopeFromContext(c)
-	if scope == nil {
-		utils.InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
+	scope, ok := utils.RequireScope(c)
+	if !ok {
 		return
 	}
 	ticket, err := h.service.GetByID(uint(id))
@@ -230,7 +226,7 @@ func (h *TicketInternalHandler) Create(c *gin.Context) {
 	}
 	var req dto.CreateTicketInternalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	createdByID, exists := c.Get("user_id")
@@ -255,7 +251,7 @@ func (h *TicketInternalHandler) Create(c *gin.Context) {
 	allowAssignAny := scope != nil && scope.HasPermission("tickets_internes.view_all")
 	ticket, err := h.service.Create(req, createdByID.(uint), allowAssignAny)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.CreatedResponse(c, ticket, "Ticket interne créé avec succès")
@@ -298,13 +294,13 @@ func (h *TicketInternalHandler) Update(c *gin.Context) {
 	}
 	var req dto.UpdateTicketInternalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	updatedByID, _ := c.Get("user_id")
 	ticket, err := h.service.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, ticket, "Ticket interne mis à jour avec succès")
@@ -347,14 +343,14 @@ func (h *TicketInternalHandler) Assign(c *gin.Context) {
 	}
 	var req dto.AssignTicketInternalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	assignedByID, _ := c.Get("user_id")
 	allowAssignAny := scope != nil && scope.HasPermission("tickets_internes.view_all")
 	ticket, err := h.service.Assign(uint(id), req, assignedByID.(uint), allowAssignAny)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, ticket, "Ticket interne assigné avec succès")
@@ -376,7 +372,7 @@ func (h *TicketInternalHandler) ChangeStatus(c *gin.Context) {
 		Status string `json:"status" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	status := req.Status
@@ -393,7 +389,7 @@ func (h *TicketInternalHandler) ChangeStatus(c *gin.Context) {
 	changedByID, _ := c.Get("user_id")
 	ticket, err := h.service.ChangeStatus(uint(id), status, changedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, ticket, "Statut mis à jour avec succès")
@@ -435,7 +431,7 @@ func (h *TicketInternalHandler) Validate(c *gin.Context) {
 	validatedByID, _ := c.Get("user_id")
 	ticket, err := h.service.Validate(uint(id), validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, ticket, "Ticket interne validé avec succès")
@@ -466,7 +462,7 @@ func (h *TicketInternalHandler) Close(c *gin.Context) {
 	closedByID, _ := c.Get("user_id")
 	ticket, err := h.service.Close(uint(id), closedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, ticket, "Ticket interne clôturé avec succès")
@@ -505,7 +501,7 @@ func (h *TicketInternalHandler) Delete(c *gin.Context) {
 		return
 	}
 	if err := h.service.Delete(uint(id)); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Ticket interne supprimé avec succès")
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketMergeHandler gère les handlers de fusion des tickets en double
+type TicketMergeHandler struct {
+	ticketMergeService services.TicketMergeService
+}
+
+// NewTicketMergeHandler crée une nouvelle instance de TicketMergeHandler
+func NewTicketMergeHandler(ticketMergeService services.TicketMergeService) *TicketMergeHandler {
+	return &TicketMergeHandler{
+		ticketMergeService: ticketMergeService,
+	}
+}
+
+// Merge fusionne des tickets en double dans un ticket principal
+// @Summary Fusionner des tickets en double
+// @Description Fusionne les tickets en double dans le ticket principal : commentaires, pièces jointes, entrées de temps et SLA sont rattachés au ticket principal, les doublons passent au statut « fusionne » avec un renvoi vers le principal (merged_into_id) et les demandeurs de tous les tickets sont notifiés (nécessite tickets.merge)
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket principal"
+// @Param request body dto.MergeTicketsRequest true "Tickets en double"
+// @Success 200 {object} dto.TicketMergeResultDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/merge [post]
+func (h *TicketMergeHandler) Merge(c *gin.Context) {
+	if !utils.RequirePermission(c, "tickets.merge") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.merge")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.MergeTicketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	mergedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	result, err := h.ticketMergeService.Merge(uint(id), req, utils.GetScopeFromContext(c), mergedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Tickets fusionnés avec succès")
+}
+
+// GetDuplicates suggère des doublons d'un ticket
+// @Summary Suggérer des doublons
+// @Description Retourne les tickets non clôturés de la même filiale, créés au cours des 90 derniers jours, dont le titre est proche de celui du ticket (du plus similaire au moins similaire)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param limit query int false "Nombre maximal de suggestions (10 au plus)" default(10)
+// @Success 200 {array} dto.TicketDuplicateDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/duplicates [get]
+func (h *TicketMergeHandler) GetDuplicates(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	duplicates, err := h.ticketMergeService.SuggestDuplicates(uint(id), utils.GetScopeFromContext(c), limit)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, duplicates, "Doublons suggérés récupérés avec succès")
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketProjectTaskHandler gère les liens entre tickets et tâches de projet
+type TicketProjectTaskHandler struct {
+	linkService services.TicketProjectTaskService
+}
+
+// NewTicketProjectTaskHandler crée une nouvelle instance de TicketProjectTaskHandler
+func NewTicketProjectTaskHandler(linkService services.TicketProjectTaskService) *TicketProjectTaskHandler {
+	return &TicketProjectTaskHandler{
+		linkService: linkService,
+	}
+}
+
+// service retourne le service des liens pour la requête : avec projects.archived.edit (administrateurs),
+// les tâches des projets archivés restent modifiables
+func (h *TicketProjectTaskHandler) service(c *gin.Context) services.TicketProjectTaskService {
+	if utils.RequirePermission(c, "projects.archived.edit") {
+		return h.linkService.AllowArchivedWrites()
+	}
+	return h.linkService
+}
+
+// GetTicketsByTask récupère les tickets liés à une tâche
+// @Summary Lister les tickets d'une tâche
+// @Description Récupère les tickets liés à la tâche (limités aux tickets visibles), avec le temps saisi sur chacun ; ce temps est reporté dans le ticket_time de la tâche (nécessite projects.tasks.view)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param taskId path int true "ID de la tâche"
+// @Success 200 {array} dto.ProjectTaskTicketDTO
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/tasks/{taskId}/tickets [get]
+func (h *TicketProjectTaskHandler) GetTicketsByTask(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.view")
+		return
+	}
+
+	projectID, taskID, ok := parseProjectAndChildID(c, "taskId")
+	if !ok {
+		return
+	}
+
+	tickets, err := h.linkService.GetTicketsByTask(projectID, taskID, utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, tickets, "Tickets liés récupérés avec succès")
+}
+
+// LinkTicket lie un ticket à une tâche
+// @Summary Lier un ticket à une tâche
+// @Description Lie un ticket visible à la tâche et rattache le ticket au projet ; un ticket est lié à au plus une tâche par projet. Le temps saisi sur le ticket s'ajoute au temps consommé du projet (nécessite projects.tasks.update)
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param taskId path int true "ID de la tâche"
+// @Param request body dto.LinkTicketToProjectTaskRequest true "Ticket à lier"
+// @Success 201 {array} dto.ProjectTaskTicketDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/tasks/{taskId}/tickets [post]
+func (h *TicketProjectTaskHandler) LinkTicket(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.update")
+		return
+	}
+
+	projectID, taskID, ok := parseProjectAndChildID(c, "taskId")
+	if !ok {
+		return
+	}
+
+	var req dto.LinkTicketToProjectTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	tickets, err := h.service(c).LinkTicket(projectID, taskID, req.TicketID, utils.GetScopeFromContext(c), createdByID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, tickets, "Ticket lié à la tâche avec succès")
+}
+
+// UnlinkTicket supprime le lien entre un ticket et une tâche
+// @Summary Délier un ticket d'une tâche
+// @Description Supprime le lien ; le temps du ticket est retiré de la tâche, le ticket reste rattaché au projet (nécessite projects.tasks.update)
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du projet"
+// @Param taskId path int true "ID de la tâche"
+// @Param ticketId path int true "ID du ticket"
+// @Success 200 {array} dto.ProjectTaskTicketDTO
+// @Failure 404 {object} utils.Response
+// @Router /projects/{id}/tasks/{taskId}/tickets/{ticketId} [delete]
+func (h *TicketProjectTaskHandler) UnlinkTicket(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.update")
+		return
+	}
+
+	projectID, taskID, ok := parseProjectAndChildID(c, "taskId")
+	if !ok {
+		return
+	}
+	ticketID, err := strconv.ParseUint(c.Param("ticketId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID du ticket invalide")
+		return
+	}
+
+	tickets, err := h.service(c).UnlinkTicket(projectID, taskID, uint(ticketID), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, tickets, "Lien supprimé avec succès")
+}
+
+// GetTasksByTicket récupère les tâches de projet liées à un ticket
+// @Summary Lister les tâches de projet d'un ticket
+// @Description Récupère les tâches de projet liées au ticket, limitées aux projets visibles
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 200 {array} dto.TicketProjectTaskDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/project-tasks [get]
+func (h *TicketProjectTaskHandler) GetTasksByTicket(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	tasks, err := h.linkService.GetTasksByTicket(uint(id), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, tasks, "Tâches liées récupérées avec succès")
+}
+
+// LinkTask lie une tâche de projet à un ticket
+// @Summary Lier une tâche de projet à un ticket
+// @Description Lie le ticket à une tâche de projet et le rattache au projet ; un ticket est lié à au plus une tâche par projet (nécessite projects.tasks.update)
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param request body dto.LinkProjectTaskToTicketRequest true "Tâche à lier"
+// @Success 201 {array} dto.TicketProjectTaskDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/project-tasks [post]
+func (h *TicketProjectTaskHandler) LinkTask(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.LinkProjectTaskToTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	tasks, err := h.service(c).LinkTask(uint(id), req.ProjectTaskID, utils.GetScopeFromContext(c), createdByID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, tasks, "Tâche liée au ticket avec succès")
+}
+
+// UnlinkTask supprime le lien entre un ticket et une tâche de projet
+// @Summary Délier une tâche de projet d'un ticket
+// @Description Supprime le lien ; le temps du ticket est retiré de la tâche (nécessite projects.tasks.update)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param taskId path int true "ID de la tâche"
+// @Success 200 {array} dto.TicketProjectTaskDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/project-tasks/{taskId} [delete]
+func (h *TicketProjectTaskHandler) UnlinkTask(c *gin.Context) {
+	if !utils.RequirePermission(c, "projects.tasks.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: projects.tasks.update")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	taskID, err := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de la tâche invalide")
+		return
+	}
+
+	tasks, err := h.service(c).UnlinkTask(uint(id), uint(taskID), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, tasks, "Lien supprimé avec succès")
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketRelationHandler gère les handlers des relations entre tickets
+type TicketRelationHandler struct {
+	ticketRelationService services.TicketRelationService
+}
+
+// NewTicketRelationHandler crée une nouvelle instance de TicketRelationHandler
+func NewTicketRelationHandler(ticketRelationService services.TicketRelationService) *TicketRelationHandler {
+	return &TicketRelationHandler{
+		ticketRelationService: ticketRelationService,
+	}
+}
+
+// GetRelations récupère les tickets liés à un ticket
+// @Summary Lister les tickets liés
+// @Description Récupère les relations du ticket (relates_to, blocks, blocked_by, caused_by, causes, duplicates, duplicated_by), exprimées du point de vue du ticket, limitées aux tickets liés visibles
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 200 {array} dto.TicketRelationDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/relations [get]
+func (h *TicketRelationHandler) GetRelations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	relations, err := h.ticketRelationService.GetRelations(uint(id), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, relations, "Tickets liés récupérés avec succès")
+}
+
+// Link lie un ticket à un autre ticket
+// @Summary Lier deux tickets
+// @Description Crée une relation entre le ticket et un autre ticket visible (une seule relation par paire de tickets)
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param request body dto.CreateTicketRelationRequest true "Ticket à lier et type de relation"
+// @Success 201 {array} dto.TicketRelationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/relations [post]
+func (h *TicketRelationHandler) Link(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.CreateTicketRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	createdByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	relations, err := h.ticketRelationService.Link(uint(id), req, utils.GetScopeFromContext(c), createdByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, relations, "Tickets liés avec succès")
+}
+
+// Unlink supprime une relation entre deux tickets
+// @Summary Délier deux tickets
+// @Description Supprime une relation du ticket
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param relationId path int true "ID de la relation"
+// @Success 200 {array} dto.TicketRelationDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/relations/{relationId} [delete]
+func (h *TicketRelationHandler) Unlink(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	relationID, err := strconv.ParseUint(c.Param("relationId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de relation invalide")
+		return
+	}
+
+	relations, err := h.ticketRelationService.Unlink(uint(id), uint(relationID), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, relations, "Relation supprimée avec succès")
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketSatisfactionHandler gère les handlers des enquêtes de satisfaction (routes publiques authentifiées par le token du lien)
+type TicketSatisfactionHandler struct {
+	ticketSatisfactionService services.TicketSatisfactionService
+}
+
+// NewTicketSatisfactionHandler crée une nouvelle instance de TicketSatisfactionHandler
+func NewTicketSatisfactionHandler(ticketSatisfactionService services.TicketSatisfactionService) *TicketSatisfactionHandler {
+	return &TicketSatisfactionHandler{
+		ticketSatisfactionService: ticketSatisfactionService,
+	}
+}
+
+// GetSurvey récupère l'enquête de satisfaction d'un ticket
+// @Summary Enquête de satisfaction d'un ticket
+// @Description Récupère l'enquête désignée par le token du lien envoyé au demandeur (route publique) : ticket concerné et réponse éventuelle
+// @Tags tickets
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param token query string true "Token du lien de l'enquête"
+// @Success 200 {object} dto.TicketSatisfactionSurveyDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/satisfaction [get]
+func (h *TicketSatisfactionHandler) GetSurvey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	survey, err := h.ticketSatisfactionService.GetSurvey(uint(id), c.Query("token"))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, survey, "Enquête de satisfaction récupérée avec succès")
+}
+
+// Submit enregistre la réponse à l'enquête de satisfaction d'un ticket
+// @Summary Répondre à l'enquête de satisfaction
+// @Description Enregistre la note (1 à 5) et le commentaire du demandeur ; authentifié par le token du lien envoyé à la clôture (route publique, une seule réponse)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param request body dto.SubmitTicketSatisfactionRequest true "Réponse à l'enquête"
+// @Success 200 {object} dto.TicketSatisfactionSurveyDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Router /tickets/{id}/satisfaction [post]
+func (h *TicketSatisfactionHandler) Submit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.SubmitTicketSatisfactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	survey, err := h.ticketSatisfactionService.Submit(uint(id), req)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, survey, "Merci pour votre réponse")
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -45,7 +44,7 @@ func (h *TicketSolutionHandler) Create(c *gin.Context) {
 
 	var req dto.CreateTicketSolutionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -57,7 +56,7 @@ func (h *TicketSolutionHandler) Create(c *gin.Context) {
 
 	solution, err := h.solutionService.Create(uint(ticketID), req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -111,7 +110,7 @@ func (h *TicketSolutionHandler) GetByTicketID(c *gin.Context) {
 
 	solutions, err := h.solutionService.GetByTicketID(uint(ticketID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -140,7 +139,7 @@ func (h *TicketSolutionHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateTicketSolutionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -152,7 +151,7 @@ func (h *TicketSolutionHandler) Update(c *gin.Context) {
 
 	solution, err := h.solutionService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -208,7 +207,7 @@ func (h *TicketSolutionHandler) PublishToKB(c *gin.Context) {
 
 	var req dto.PublishSolutionToKBRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -220,9 +219,64 @@ func (h *TicketSolutionHandler) PublishToKB(c *gin.Context) {
 
 	article, err := h.solutionService.PublishToKB(uint(id), req, publishedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
 	utils.CreatedResponse(c, article, "Solution publiée dans la base de connaissances avec succès")
 }
+
+// PublishAsArticle rédige un article de la base de connaissances à partir d'une solution
+// @Summary Rédiger un article à partir d'une solution
+// @Description Crée un brouillon d'article reprenant le titre du ticket, sa description (symptômes), la solution (résolution) et ses pièces jointes (images intégrées au contenu, autres fichiers cités), dans la catégorie KB associée à la catégorie du ticket sauf indication contraire. L'article est lié au ticket et à la solution, qui ne peut être convertie qu'une fois (nécessite knowledge.create)
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param sid path int true "ID de la solution"
+// @Param request body dto.PublishSolutionAsArticleRequest false "Titre, catégorie, symptômes et pièces jointes (optionnels)"
+// @Success 201 {object} dto.KnowledgeArticleDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/solutions/{sid}/publish-as-article [post]
+func (h *TicketSolutionHandler) PublishAsArticle(c *gin.Context) {
+	if !utils.RequirePermission(c, "knowledge.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: knowledge.create")
+		return
+	}
+
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID ticket invalide")
+		return
+	}
+	solutionID, err := strconv.ParseUint(c.Param("sid"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID solution invalide")
+		return
+	}
+
+	var req dto.PublishSolutionAsArticleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	article, err := h.solutionService.PublishAsArticle(uint(ticketID), uint(solutionID), req, userID)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, article, "Article rédigé à partir de la solution")
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketTimelineHandler gère le fil d'activité des tickets
+type TicketTimelineHandler struct {
+	ticketTimelineService services.TicketTimelineService
+}
+
+// NewTicketTimelineHandler crée une nouvelle instance de TicketTimelineHandler
+func NewTicketTimelineHandler(ticketTimelineService services.TicketTimelineService) *TicketTimelineHandler {
+	return &TicketTimelineHandler{
+		ticketTimelineService: ticketTimelineService,
+	}
+}
+
+// GetTimeline récupère le fil d'activité d'un ticket
+// @Summary Fil d'activité d'un ticket
+// @Description Retourne en un seul flux chronologique l'historique (dont les assignations), les commentaires (internes réservés à l'IT ou à tickets.comments.view_internal), les pièces jointes, les temps passés et les transitions SLA du ticket. Pagination par curseur : renvoyer next_cursor dans cursor pour obtenir la page suivante
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param cursor query string false "Curseur de la page précédente (vide = début du fil)"
+// @Param limit query int false "Nombre d'éléments par page (max 100)" default(50)
+// @Success 200 {object} dto.TicketTimelineDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/timeline [get]
+func (h *TicketTimelineHandler) GetTimeline(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	timeline, err := h.ticketTimelineService.GetTimeline(uint(id), utils.GetScopeFromContext(c), c.Query("cursor"), limit)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, timeline, "Fil d'activité récupéré avec succès")
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketViewHandler gère les handlers des vues de tickets enregistrées
+type TicketViewHandler struct {
+	ticketViewService services.TicketViewService
+}
+
+// NewTicketViewHandler crée une nouvelle instance de TicketViewHandler
+func NewTicketViewHandler(ticketViewService services.TicketViewService) *TicketViewHandler {
+	return &TicketViewHandler{
+		ticketViewService: ticketViewService,
+	}
+}
+
+// GetAll récupère les vues de tickets de l'utilisateur et les vues partagées
+// @Summary Lister les vues de tickets
+// @Description Récupère les vues enregistrées de l'utilisateur connecté, puis les vues partagées par les autres utilisateurs
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.TicketViewDTO
+// @Failure 401 {object} utils.Response
+// @Router /tickets/views [get]
+func (h *TicketViewHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	views, err := h.ticketViewService.GetAll(userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, views, "Vues de tickets récupérées avec succès")
+}
+
+// GetByID récupère une vue de tickets par son ID
+// @Summary Récupérer une vue de tickets
+// @Description Récupère une vue de l'utilisateur connecté ou une vue partagée
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la vue"
+// @Success 200 {object} dto.TicketViewDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/views/{id} [get]
+func (h *TicketViewHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	view, err := h.ticketViewService.GetByID(uint(id), userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, view, "Vue de tickets récupérée avec succès")
+}
+
+// Create crée une vue de tickets
+// @Summary Créer une vue de tickets
+// @Description Enregistre un filtre nommé (statuts, priorités, filiale, assigné, période de création, texte) ; is_shared le rend visible par tous les utilisateurs
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTicketViewRequest true "Données de la vue"
+// @Success 201 {object} dto.TicketViewDTO
+// @Failure 400 {object} utils.Response
+// @Router /tickets/views [post]
+func (h *TicketViewHandler) Create(c *gin.Context) {
+	var req dto.CreateTicketViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	view, err := h.ticketViewService.Create(req, userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, view, "Vue de tickets créée avec succès")
+}
+
+// Update met à jour une vue de tickets
+// @Summary Mettre à jour une vue de tickets
+// @Description Met à jour une vue ; la définition du filtre fournie remplace l'existante (réservé au propriétaire de la vue)
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la vue"
+// @Param request body dto.UpdateTicketViewRequest true "Données à mettre à jour"
+// @Success 200 {object} dto.TicketViewDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/views/{id} [put]
+func (h *TicketViewHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateTicketViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	view, err := h.ticketViewService.Update(uint(id), req, userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, view, "Vue de tickets mise à jour avec succès")
+}
+
+// Delete supprime une vue de tickets
+// @Summary Supprimer une vue de tickets
+// @Description Supprime une vue (réservé au propriétaire de la vue)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la vue"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/views/{id} [delete]
+func (h *TicketViewHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.ticketViewService.Delete(uint(id), userID.(uint)); err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Vue de tickets supprimée avec succès")
+}
+
+// GetResults exécute le filtre d'une vue de tickets
+// @Summary Résultats d'une vue de tickets
+// @Description Récupère les tickets répondant au filtre de la vue, limités aux tickets visibles par l'utilisateur connecté. Les dates de la vue sont interprétées dans son fuseau horaire
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la vue"
+// @Param page query int false "Numéro de page" default(1)
+// @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Param fields query string false "Champs à retourner, notation pointée pour les objets imbriqués (ex: id,code,title,status,requester.first_name)"
+// @Success 200 {object} dto.TicketListResponse
+// @Failure 404 {object} utils.Response
+// @Router /tickets/views/{id}/results [get]
+func (h *TicketViewHandler) GetResults(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	queryScope := utils.GetScopeFromContext(c)
+	response, err := h.ticketViewService.GetResults(uint(id), queryScope, userID.(uint), page, limit)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, utils.SelectFields(c, response), "Tickets récupérés avec succès")
+}
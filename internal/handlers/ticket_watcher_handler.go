@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketWatcherHandler gère les handlers des abonnés aux tickets
+type TicketWatcherHandler struct {
+	ticketWatcherService services.TicketWatcherService
+}
+
+// NewTicketWatcherHandler crée une nouvelle instance de TicketWatcherHandler
+func NewTicketWatcherHandler(ticketWatcherService services.TicketWatcherService) *TicketWatcherHandler {
+	return &TicketWatcherHandler{
+		ticketWatcherService: ticketWatcherService,
+	}
+}
+
+// GetWatchers récupère les abonnés d'un ticket
+// @Summary Lister les abonnés d'un ticket
+// @Description Récupère les utilisateurs qui suivent le ticket
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Success 200 {array} dto.TicketWatcherDTO
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/watchers [get]
+func (h *TicketWatcherHandler) GetWatchers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	watchers, err := h.ticketWatcherService.GetWatchers(uint(id), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, watchers, "Abonnés du ticket récupérés avec succès")
+}
+
+// Watch abonne un utilisateur à un ticket
+// @Summary S'abonner à un ticket
+// @Description Abonne l'utilisateur connecté au ticket, ou l'utilisateur indiqué (nécessite tickets.update ; il doit avoir accès au ticket). Les abonnés sont notifiés comme le demandeur des changements de statut, de la résolution et des commentaires publics
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param request body dto.WatchTicketRequest false "Utilisateur à abonner (par défaut l'utilisateur connecté)"
+// @Success 200 {array} dto.TicketWatcherDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/watchers [post]
+func (h *TicketWatcherHandler) Watch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.WatchTicketRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+	userID := currentUserID.(uint)
+	if req.UserID != nil && *req.UserID != userID {
+		if !utils.RequirePermission(c, "tickets.update") {
+			utils.ForbiddenResponse(c, "Permission insuffisante: tickets.update")
+			return
+		}
+		userID = *req.UserID
+	}
+
+	watchers, err := h.ticketWatcherService.Watch(uint(id), userID, currentUserID.(uint), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, watchers, "Abonnement au ticket enregistré avec succès")
+}
+
+// Unwatch désabonne un utilisateur d'un ticket
+// @Summary Se désabonner d'un ticket
+// @Description Désabonne l'utilisateur indiqué du ticket (un autre utilisateur que soi nécessite tickets.update)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du ticket"
+// @Param userId path int true "ID de l'utilisateur"
+// @Success 200 {array} dto.TicketWatcherDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id}/watchers/{userId} [delete]
+func (h *TicketWatcherHandler) Unwatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID utilisateur invalide")
+		return
+	}
+
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+	if uint(userID) != currentUserID.(uint) && !utils.RequirePermission(c, "tickets.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: tickets.update")
+		return
+	}
+
+	watchers, err := h.ticketWatcherService.Unwatch(uint(id), uint(userID), utils.GetScopeFromContext(c))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, watchers, "Désabonnement du ticket effectué avec succès")
+}
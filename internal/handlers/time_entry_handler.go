@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -37,7 +36,7 @@ func NewTimeEntryHandler(timeEntryService services.TimeEntryService) *TimeEntryH
 func (h *TimeEntryHandler) Create(c *gin.Context) {
 	var req dto.CreateTimeEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -49,7 +48,7 @@ func (h *TimeEntryHandler) Create(c *gin.Context) {
 
 	timeEntry, err := h.timeEntryService.Create(req, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -120,6 +119,7 @@ func (h *TimeEntryHandler) GetAll(c *gin.Context) {
 // @Success 200 {object} dto.TimeEntryDTO
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /time-entries/{id}/validate [post]
 func (h *TimeEntryHandler) Validate(c *gin.Context) {
 	idParam := c.Param("id")
@@ -131,7 +131,7 @@ func (h *TimeEntryHandler) Validate(c *gin.Context) {
 
 	var req dto.ValidateTimeEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	if req.Validated == nil {
@@ -147,7 +147,7 @@ func (h *TimeEntryHandler) Validate(c *gin.Context) {
 
 	timeEntry, err := h.timeEntryService.Validate(uint(id), req, validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -165,6 +165,7 @@ func (h *TimeEntryHandler) Validate(c *gin.Context) {
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /time-entries/{id} [delete]
 func (h *TimeEntryHandler) Delete(c *gin.Context) {
 	idParam := c.Param("id")
@@ -176,7 +177,7 @@ func (h *TimeEntryHandler) Delete(c *gin.Context) {
 
 	err = h.timeEntryService.Delete(uint(id))
 	if err != nil {
-		utils.NotFoundResponse(c, "Entrée de temps introuvable")
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
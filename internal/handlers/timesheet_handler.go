@@ -1,13 +1,13 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/timezone"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
@@ -39,7 +39,7 @@ func NewTimesheetHandler(timesheetService services.TimesheetService) *TimesheetH
 func (h *TimesheetHandler) CreateTimeEntry(c *gin.Context) {
 	var req dto.CreateTimeEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -51,7 +51,7 @@ func (h *TimesheetHandler) CreateTimeEntry(c *gin.Context) {
 
 	timeEntry, err := h.timesheetService.CreateTimeEntry(req, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -131,7 +131,7 @@ func (h *TimesheetHandler) UpdateTimeEntry(c *gin.Context) {
 
 	var req dto.UpdateTimeEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -143,7 +143,7 @@ func (h *TimesheetHandler) UpdateTimeEntry(c *gin.Context) {
 
 	entry, err := h.timesheetService.UpdateTimeEntry(uint(id), req, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -233,7 +233,7 @@ func (h *TimesheetHandler) GetTimeEntriesByUserID(c *gin.Context) {
 // @Router /timesheet/entries/by-date/{date} [get]
 func (h *TimesheetHandler) GetTimeEntriesByDate(c *gin.Context) {
 	dateParam := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
 		return
@@ -268,7 +268,7 @@ func (h *TimesheetHandler) GetTimeEntriesByDate(c *gin.Context) {
 // @Router /timesheet/daily/{date} [get]
 func (h *TimesheetHandler) GetDailyDeclaration(c *gin.Context) {
 	dateParam := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
 		return
@@ -329,7 +329,7 @@ func (h *TimesheetHandler) GetDailyDeclaration(c *gin.Context) {
 // @Router /timesheet/daily/{date} [post]
 func (h *TimesheetHandler) CreateOrUpdateDailyDeclaration(c *gin.Context) {
 	dateParam := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
 		return
@@ -337,7 +337,7 @@ func (h *TimesheetHandler) CreateOrUpdateDailyDeclaration(c *gin.Context) {
 
 	var tasks []dto.DailyTaskRequest
 	if err := c.ShouldBindJSON(&tasks); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -349,7 +349,7 @@ func (h *TimesheetHandler) CreateOrUpdateDailyDeclaration(c *gin.Context) {
 
 	declaration, err := h.timesheetService.CreateOrUpdateDailyDeclaration(date, userID.(uint), tasks)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -368,7 +368,7 @@ func (h *TimesheetHandler) CreateOrUpdateDailyDeclaration(c *gin.Context) {
 // @Router /timesheet/daily/{date}/tasks [get]
 func (h *TimesheetHandler) GetDailyTasks(c *gin.Context) {
 	dateParam := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
 		return
@@ -403,7 +403,7 @@ func (h *TimesheetHandler) GetDailyTasks(c *gin.Context) {
 // @Router /timesheet/daily/{date}/tasks [post]
 func (h *TimesheetHandler) CreateDailyTask(c *gin.Context) {
 	dateParam := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
 		return
@@ -411,7 +411,7 @@ func (h *TimesheetHandler) CreateDailyTask(c *gin.Context) {
 
 	var task dto.DailyTaskRequest
 	if err := c.ShouldBindJSON(&task); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -423,7 +423,7 @@ func (h *TimesheetHandler) CreateDailyTask(c *gin.Context) {
 
 	createdTask, err := h.timesheetService.CreateDailyTask(date, userID.(uint), task)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -443,7 +443,7 @@ func (h *TimesheetHandler) CreateDailyTask(c *gin.Context) {
 // @Router /timesheet/daily/{date}/tasks/{taskId} [delete]
 func (h *TimesheetHandler) DeleteDailyTask(c *gin.Context) {
 	dateParam := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
 		return
@@ -464,7 +464,7 @@ func (h *TimesheetHandler) DeleteDailyTask(c *gin.Context) {
 
 	err = h.timesheetService.DeleteDailyTask(date, userID.(uint), uint(taskID))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -483,7 +483,7 @@ func (h *TimesheetHandler) DeleteDailyTask(c *gin.Context) {
 // @Router /timesheet/daily/{date}/summary [get]
 func (h *TimesheetHandler) GetDailySummary(c *gin.Context) {
 	dateParam := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateParam)
+	date, err := timezone.ParseDate(dateParam)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date invalide, attendu: YYYY-MM-DD")
 		return
@@ -522,16 +522,16 @@ func (h *TimesheetHandler) GetDailyCalendar(c *gin.Context) {
 		return
 	}
 
-	startDateStr := c.DefaultQuery("startDate", time.Now().AddDate(0, 0, -30).Format("2006-01-02"))
-	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	startDateStr := c.DefaultQuery("startDate", timezone.Today(utils.RequestLocation(c)).AddDate(0, 0, -30).Format(timezone.DateLayout))
+	endDateStr := c.DefaultQuery("endDate", timezone.Today(utils.RequestLocation(c)).Format(timezone.DateLayout))
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, err := timezone.ParseDate(startDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de début invalide")
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := timezone.ParseDate(endDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de fin invalide")
 		return
@@ -564,16 +564,16 @@ func (h *TimesheetHandler) GetDailyRange(c *gin.Context) {
 		return
 	}
 
-	startDateStr := c.DefaultQuery("startDate", time.Now().AddDate(0, 0, -30).Format("2006-01-02"))
-	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	startDateStr := c.DefaultQuery("startDate", timezone.Today(utils.RequestLocation(c)).AddDate(0, 0, -30).Format(timezone.DateLayout))
+	endDateStr := c.DefaultQuery("endDate", timezone.Today(utils.RequestLocation(c)).Format(timezone.DateLayout))
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, err := timezone.ParseDate(startDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de début invalide")
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := timezone.ParseDate(endDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de fin invalide")
 		return
@@ -660,7 +660,7 @@ func (h *TimesheetHandler) CreateOrUpdateWeeklyDeclaration(c *gin.Context) {
 
 	var tasks []dto.WeeklyTaskRequest
 	if err := c.ShouldBindJSON(&tasks); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -672,7 +672,7 @@ func (h *TimesheetHandler) CreateOrUpdateWeeklyDeclaration(c *gin.Context) {
 
 	declaration, err := h.timesheetService.CreateOrUpdateWeeklyDeclaration(week, userID.(uint), tasks)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -763,17 +763,63 @@ func (h *TimesheetHandler) GetWeeklyDailyBreakdown(c *gin.Context) {
 	utils.SuccessResponse(c, breakdown, "Répartition récupérée avec succès")
 }
 
+// SubmitWeeklyDeclaration soumet la déclaration hebdomadaire de l'utilisateur à validation
+// @Summary Soumettre une déclaration hebdomadaire
+// @Description Soumet la déclaration de la semaine (brouillon ou rejetée) à validation ; elle n'est plus modifiable jusqu'à la décision. Une fois approuvée, les entrées de temps de la semaine sont verrouillées
+// @Tags timesheet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param week path string true "Semaine (format: YYYY-MM-Wn)"
+// @Param request body dto.WeeklyDeclarationActionRequest false "Commentaire de soumission"
+// @Success 200 {object} dto.WeeklyDeclarationDTO
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /timesheet/weekly/{week}/submit [post]
+func (h *TimesheetHandler) SubmitWeeklyDeclaration(c *gin.Context) {
+	week := c.Param("week")
+
+	var req dto.WeeklyDeclarationActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	declaration, err := h.timesheetService.SubmitWeeklyDeclaration(week, userID, req.Comment)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, declaration, "Déclaration soumise à validation")
+}
+
 // ValidateWeeklyDeclaration valide une déclaration hebdomadaire
 // @Summary Valider une déclaration hebdomadaire
-// @Description Valide une déclaration hebdomadaire
+// @Description Approuve la déclaration soumise de la semaine de l'utilisateur connecté et verrouille la semaine (nécessite timesheet.validate)
 // @Tags timesheet
 // @Security BearerAuth
 // @Produce json
 // @Param week path string true "Semaine (format: YYYY-Www)"
 // @Success 200 {object} dto.WeeklyDeclarationDTO
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /timesheet/weekly/{week}/validate [post]
 func (h *TimesheetHandler) ValidateWeeklyDeclaration(c *gin.Context) {
+	if !utils.RequirePermission(c, "timesheet.validate") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.validate")
+		return
+	}
+
 	week := c.Param("week")
 
 	userID, exists := c.Get("user_id")
@@ -790,7 +836,7 @@ func (h *TimesheetHandler) ValidateWeeklyDeclaration(c *gin.Context) {
 
 	declaration, err := h.timesheetService.ValidateWeeklyDeclaration(week, userID.(uint), validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -849,7 +895,7 @@ func (h *TimesheetHandler) SetTicketEstimatedTime(c *gin.Context) {
 
 	var req dto.SetEstimatedTimeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -861,7 +907,7 @@ func (h *TimesheetHandler) SetTicketEstimatedTime(c *gin.Context) {
 
 	err = h.timesheetService.SetTicketEstimatedTime(uint(ticketID), req.EstimatedTime, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -917,7 +963,7 @@ func (h *TimesheetHandler) UpdateTicketEstimatedTime(c *gin.Context) {
 
 	var req dto.SetEstimatedTimeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -929,7 +975,7 @@ func (h *TimesheetHandler) UpdateTicketEstimatedTime(c *gin.Context) {
 
 	err = h.timesheetService.UpdateTicketEstimatedTime(uint(ticketID), req.EstimatedTime, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -1020,7 +1066,7 @@ func (h *TimesheetHandler) SetProjectTimeBudget(c *gin.Context) {
 
 	var budget dto.SetProjectTimeBudgetRequest
 	if err := c.ShouldBindJSON(&budget); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -1032,7 +1078,7 @@ func (h *TimesheetHandler) SetProjectTimeBudget(c *gin.Context) {
 
 	err = h.timesheetService.SetProjectTimeBudget(uint(projectID), budget, userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -1108,6 +1154,7 @@ func (h *TimesheetHandler) GetTicketBudgetStatus(c *gin.Context) {
 // @Param request body dto.ValidateTimeEntryRequest true "Données de validation"
 // @Success 200 {object} dto.TimeEntryDTO
 // @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /timesheet/entries/{id}/validate [post]
 func (h *TimesheetHandler) ValidateTimeEntry(c *gin.Context) {
 	idParam := c.Param("id")
@@ -1119,7 +1166,7 @@ func (h *TimesheetHandler) ValidateTimeEntry(c *gin.Context) {
 
 	var req dto.ValidateTimeEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	if req.Validated == nil {
@@ -1133,9 +1180,26 @@ func (h *TimesheetHandler) ValidateTimeEntry(c *gin.Context) {
 		return
 	}
 
+	// Validation réservée aux validateurs et au responsable hiérarchique de l'auteur de l'entrée
+	if !utils.RequirePermission(c, "timesheet.validate") {
+		queryScope, ok := utils.RequireScope(c)
+		if !ok {
+			return
+		}
+		existing, err := h.timesheetService.GetTimeEntryByID(uint(id))
+		if err != nil {
+			utils.NotFoundResponse(c, "Entrée de temps introuvable")
+			return
+		}
+		if !queryScope.IsManagerOf(existing.UserID) {
+			utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.validate")
+			return
+		}
+	}
+
 	entry, err := h.timesheetService.ValidateTimeEntry(uint(id), req, validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -1279,13 +1343,13 @@ func (h *TimesheetHandler) GetUnderloadAlerts(c *gin.Context) {
 func (h *TimesheetHandler) SendReminderAlerts(c *gin.Context) {
 	var userIDs []uint
 	if err := c.ShouldBindJSON(&userIDs); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	err := h.timesheetService.SendReminderAlerts(userIDs)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -1331,16 +1395,16 @@ func (h *TimesheetHandler) GetTimesheetHistory(c *gin.Context) {
 		return
 	}
 
-	startDateStr := c.DefaultQuery("startDate", time.Now().AddDate(0, 0, -30).Format("2006-01-02"))
-	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	startDateStr := c.DefaultQuery("startDate", timezone.Today(utils.RequestLocation(c)).AddDate(0, 0, -30).Format(timezone.DateLayout))
+	endDateStr := c.DefaultQuery("endDate", timezone.Today(utils.RequestLocation(c)).Format(timezone.DateLayout))
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, err := timezone.ParseDate(startDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de début invalide")
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := timezone.ParseDate(endDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de fin invalide")
 		return
@@ -1400,16 +1464,16 @@ func (h *TimesheetHandler) GetTimesheetAuditTrail(c *gin.Context) {
 		return
 	}
 
-	startDateStr := c.DefaultQuery("startDate", time.Now().AddDate(0, 0, -30).Format("2006-01-02"))
-	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	startDateStr := c.DefaultQuery("startDate", timezone.Today(utils.RequestLocation(c)).AddDate(0, 0, -30).Format(timezone.DateLayout))
+	endDateStr := c.DefaultQuery("endDate", timezone.Today(utils.RequestLocation(c)).Format(timezone.DateLayout))
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, err := timezone.ParseDate(startDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de début invalide")
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := timezone.ParseDate(endDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de fin invalide")
 		return
@@ -1442,16 +1506,16 @@ func (h *TimesheetHandler) GetTimesheetModifications(c *gin.Context) {
 		return
 	}
 
-	startDateStr := c.DefaultQuery("startDate", time.Now().AddDate(0, 0, -30).Format("2006-01-02"))
-	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	startDateStr := c.DefaultQuery("startDate", timezone.Today(utils.RequestLocation(c)).AddDate(0, 0, -30).Format(timezone.DateLayout))
+	endDateStr := c.DefaultQuery("endDate", timezone.Today(utils.RequestLocation(c)).Format(timezone.DateLayout))
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, err := timezone.ParseDate(startDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de début invalide")
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := timezone.ParseDate(endDateStr)
 	if err != nil {
 		utils.BadRequestResponse(c, "Format de date de fin invalide")
 		return
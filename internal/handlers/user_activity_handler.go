@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Période du résumé d'activité
+const (
+	defaultActivityPeriodDays = 30
+	maxActivityPeriodDays     = 366
+)
+
+// UserActivityHandler gère le résumé d'activité des utilisateurs
+type UserActivityHandler struct {
+	activityService services.UserActivityService
+}
+
+// NewUserActivityHandler crée une nouvelle instance de UserActivityHandler
+func NewUserActivityHandler(activityService services.UserActivityService) *UserActivityHandler {
+	return &UserActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// GetActivity récupère le résumé d'activité d'un utilisateur
+// @Summary Résumé d'activité d'un utilisateur
+// @Description Tickets touchés, commentaires, temps saisi, tâches terminées et connexions sur une période (30 derniers jours par défaut, 366 jours au plus). Accessible pour soi-même, ses collaborateurs et les utilisateurs de son périmètre
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Param from query string false "Premier jour (YYYY-MM-DD)"
+// @Param to query string false "Dernier jour inclus (YYYY-MM-DD, défaut aujourd'hui)"
+// @Success 200 {object} dto.UserActivityDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/{id}/activity [get]
+// @Router /users/me/activity [get]
+func (h *UserActivityHandler) GetActivity(c *gin.Context) {
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return
+	}
+
+	userID := queryScope.UserID
+	if idParam := c.Param("id"); idParam != "" {
+		id, err := strconv.ParseUint(idParam, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID invalide")
+			return
+		}
+		userID = uint(id)
+	}
+
+	to := timezone.Today(queryScope.Loc())
+	if raw := c.Query("to"); raw != "" {
+		t, err := timezone.ParseDate(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre to invalide (YYYY-MM-DD)")
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, 1-defaultActivityPeriodDays)
+	if raw := c.Query("from"); raw != "" {
+		t, err := timezone.ParseDate(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre from invalide (YYYY-MM-DD)")
+			return
+		}
+		from = t
+	}
+	if from.After(to) {
+		utils.BadRequestResponse(c, "La date de début doit précéder la date de fin")
+		return
+	}
+	if to.Sub(from) >= maxActivityPeriodDays*24*time.Hour {
+		utils.BadRequestResponse(c, "La période ne peut pas dépasser 366 jours")
+		return
+	}
+
+	activity, err := h.activityService.GetActivity(queryScope, userID, from, to)
+	if err != nil {
+		if err.Error() == "utilisateur introuvable" {
+			utils.NotFoundResponse(c, "Utilisateur introuvable")
+		} else {
+			utils.InternalServerErrorResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, activity, "Activité récupérée avec succès")
+}
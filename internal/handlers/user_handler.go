@@ -2,16 +2,16 @@ package handlers
 
 import (
 	"fmt"
+	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/config"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/logger"
 	"github.com/mcicare/itsm-backend/internal/services"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
@@ -43,7 +43,7 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 func (h *UserHandler) Create(c *gin.Context) {
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -91,12 +91,9 @@ func (h *UserHandler) Create(c *gin.Context) {
 		}
 	}
 
-	// Log pour débogage (à retirer en production)
-	// fmt.Printf("DEBUG Create User - req.FilialeID: %v, canSelectAnyFiliale: %v\n", req.FilialeID, canSelectAnyFiliale)
-
 	user, err := h.userService.Create(req, createdByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -131,6 +128,74 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 	utils.SuccessResponse(c, user, "Utilisateur récupéré avec succès")
 }
 
+// GetOrgChart récupère l'organigramme d'un utilisateur
+// @Summary Organigramme d'un utilisateur
+// @Description Retourne la chaîne hiérarchique d'un utilisateur (du N+1 au sommet) et ses collaborateurs sur plusieurs niveaux
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Param depth query int false "Niveaux de collaborateurs détaillés (défaut 2, max 10)"
+// @Success 200 {object} dto.OrgChartDTO
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/{id}/org-chart [get]
+func (h *UserHandler) GetOrgChart(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	depth := 2
+	if raw := c.Query("depth"); raw != "" {
+		depth, err = strconv.Atoi(raw)
+		if err != nil || depth < 1 {
+			utils.BadRequestResponse(c, "Profondeur invalide")
+			return
+		}
+	}
+
+	chart, err := h.userService.GetOrgChart(uint(id), depth)
+	if err != nil {
+		if err.Error() == "utilisateur introuvable" {
+			utils.NotFoundResponse(c, "Utilisateur introuvable")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, chart, "Organigramme récupéré avec succès")
+}
+
+// GetMyTeam récupère les collaborateurs de l'utilisateur connecté
+// @Summary Mon équipe
+// @Description Liste les collaborateurs rattachés à l'utilisateur connecté via manager_id (toute la hiérarchie, ou directs seulement)
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Param direct query bool false "Collaborateurs directs seulement"
+// @Success 200 {array} dto.UserDTO
+// @Failure 401 {object} utils.Response
+// @Router /users/me/team [get]
+func (h *UserHandler) GetMyTeam(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	team, err := h.userService.GetTeam(userID, c.Query("direct") == "true")
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, team, "Équipe récupérée avec succès")
+}
+
 // GetAll récupère tous les utilisateurs
 // @Summary Liste des utilisateurs
 // @Description Récupère la liste de tous les utilisateurs (filtrés selon les permissions)
@@ -214,12 +279,11 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
-	// Log pour déboguer
-	fmt.Printf("Handler Update - User ID: %d, RoleID reçu: %d\n", id, req.RoleID)
+	logger.FromContext(c.Request.Context()).Debug("user_update_request", "target_user_id", id, "role_id", req.RoleID)
 
 	// Récupérer l'ID de l'utilisateur qui effectue la mise à jour
 	updatedByID, exists := c.Get("user_id")
@@ -255,7 +319,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 	user, err := h.userService.Update(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -294,7 +358,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		if err.Error() == "utilisateur introuvable" {
 			utils.NotFoundResponse(c, "Utilisateur introuvable")
 		} else {
-			utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+			utils.ServiceErrorResponse(c, err)
 		}
 		return
 	}
@@ -302,6 +366,154 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	utils.SuccessResponse(c, nil, "Utilisateur supprimé avec succès")
 }
 
+// Offboard organise le départ d'un utilisateur
+// @Summary Départ d'un utilisateur
+// @Description Désactive le compte et réaffecte au successeur, en une seule opération, les tickets et tâches de projet ouverts ainsi que les collaborateurs (et leurs validations en attente). Sans successeur, le travail est désassigné
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Param request body dto.OffboardUserRequest true "Successeur et motif"
+// @Success 200 {object} dto.UserOffboardingReportDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/{id}/offboard [post]
+func (h *UserHandler) Offboard(c *gin.Context) {
+	if !utils.RequirePermission(c, "users.offboard") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: users.offboard")
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.OffboardUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	offboardedByID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	report, err := h.userService.Offboard(uint(id), req, offboardedByID)
+	if err != nil {
+		if err.Error() == "utilisateur introuvable" {
+			utils.NotFoundResponse(c, "Utilisateur introuvable")
+		} else if err.Error() == "erreur lors du départ de l'utilisateur" {
+			utils.InternalServerErrorResponse(c, err.Error())
+		} else {
+			utils.BadRequestResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Départ de l'utilisateur effectué")
+}
+
+// outOfOfficeTarget résout l'utilisateur visé par /users/me/out-of-office ou /users/:id/out-of-office
+// Déclarer l'absence d'un autre utilisateur requiert users.update
+func (h *UserHandler) outOfOfficeTarget(c *gin.Context) (uint, bool) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return 0, false
+	}
+	idParam := c.Param("id")
+	if idParam == "" {
+		return userID, true
+	}
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, false
+	}
+	if uint(id) != userID && !utils.RequirePermission(c, "users.update") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: users.update")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// SetOutOfOffice déclare une absence
+// @Summary Déclarer une absence
+// @Description Déclare une absence avec un suppléant optionnel : pendant la période, les validations en attente (temps, justifications de retard) sont accessibles au suppléant et l'assignation de tickets à l'absent est signalée. Sans ID, s'applique à l'utilisateur connecté
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Param request body dto.SetOutOfOfficeRequest true "Période, suppléant et message"
+// @Success 200 {object} dto.UserDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /users/{id}/out-of-office [put]
+// @Router /users/me/out-of-office [put]
+func (h *UserHandler) SetOutOfOffice(c *gin.Context) {
+	targetID, ok := h.outOfOfficeTarget(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetOutOfOfficeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.userService.SetOutOfOffice(targetID, req)
+	if err != nil {
+		if err.Error() == "utilisateur introuvable" {
+			utils.NotFoundResponse(c, "Utilisateur introuvable")
+		} else {
+			utils.BadRequestResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, user, "Absence enregistrée avec succès")
+}
+
+// ClearOutOfOffice met fin à une absence
+// @Summary Mettre fin à une absence
+// @Description Efface l'absence déclarée (retour anticipé ou annulation). Sans ID, s'applique à l'utilisateur connecté
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Success 200 {object} dto.UserDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/{id}/out-of-office [delete]
+// @Router /users/me/out-of-office [delete]
+func (h *UserHandler) ClearOutOfOffice(c *gin.Context) {
+	targetID, ok := h.outOfOfficeTarget(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.ClearOutOfOffice(targetID)
+	if err != nil {
+		if err.Error() == "utilisateur introuvable" {
+			utils.NotFoundResponse(c, "Utilisateur introuvable")
+		} else {
+			utils.InternalServerErrorResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, user, "Absence terminée")
+}
+
 // ChangePassword change le mot de passe d'un utilisateur
 // @Summary Changer le mot de passe
 // @Description Change le mot de passe d'un utilisateur
@@ -328,13 +540,13 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	err = h.userService.ChangePassword(uint(id), req.OldPassword, req.NewPassword)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -368,12 +580,12 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 		NewPassword string `json:"new_password" binding:"required,min=6"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", "Le mot de passe doit contenir au moins 6 caractères")
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 	err = h.userService.ResetPassword(uint(id), req.NewPassword)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, nil, "Mot de passe réinitialisé avec succès")
@@ -428,7 +640,7 @@ func (h *UserHandler) UpdatePermissions(c *gin.Context) {
 
 	var req dto.UpdateUserPermissionsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Données invalides", err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -440,7 +652,7 @@ func (h *UserHandler) UpdatePermissions(c *gin.Context) {
 
 	permissions, err := h.userService.UpdatePermissions(uint(id), req, updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
@@ -449,13 +661,13 @@ func (h *UserHandler) UpdatePermissions(c *gin.Context) {
 
 // UploadAvatar upload un avatar pour un utilisateur
 // @Summary Uploader un avatar
-// @Description Upload un avatar pour un utilisateur
+// @Description Upload un avatar pour un utilisateur ; l'image est recadrée en carré et redimensionnée (256px, miniature 100px)
 // @Tags users
 // @Security BearerAuth
 // @Accept multipart/form-data
 // @Produce json
 // @Param id path int true "ID de l'utilisateur"
-// @Param file formData file true "Fichier image (JPG, PNG, max 2MB)"
+// @Param file formData file true "Fichier image (JPG, PNG, GIF, max 2MB)"
 // @Success 200 {object} dto.UserDTO
 // @Failure 400 {object} utils.Response
 // @Router /users/{id}/avatar [post]
@@ -467,6 +679,32 @@ func (h *UserHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
+	h.uploadAvatar(c, uint(id))
+}
+
+// UploadMyAvatar upload l'avatar de l'utilisateur connecté
+// @Summary Uploader mon avatar
+// @Description Upload l'avatar de l'utilisateur connecté ; l'image est recadrée en carré et redimensionnée (256px, miniature 100px)
+// @Tags users
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Fichier image (JPG, PNG, GIF, max 2MB)"
+// @Success 200 {object} dto.UserDTO
+// @Failure 400 {object} utils.Response
+// @Router /users/me/avatar [post]
+func (h *UserHandler) UploadMyAvatar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	h.uploadAvatar(c, userID.(uint))
+}
+
+// uploadAvatar vérifie le fichier envoyé puis le confie au service (validation du contenu et redimensionnement)
+func (h *UserHandler) uploadAvatar(c *gin.Context, userID uint) {
 	// Récupérer le fichier
 	file, err := c.FormFile("file")
 	if err != nil {
@@ -480,9 +718,9 @@ func (h *UserHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
-	// Vérifier le type de fichier
+	// Vérifier le type de fichier (le contenu est ensuite validé au décodage)
 	ext := strings.ToLower(filepath.Ext(file.Filename))
-	allowedExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
+	allowedExts := []string{".jpg", ".jpeg", ".png", ".gif"}
 	isAllowed := false
 	for _, allowedExt := range allowedExts {
 		if ext == allowedExt {
@@ -491,47 +729,64 @@ func (h *UserHandler) UploadAvatar(c *gin.Context) {
 		}
 	}
 	if !isAllowed {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Type de fichier non autorisé. Types autorisés: JPG, JPEG, PNG, GIF, WEBP", nil)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Type de fichier non autorisé. Types autorisés: JPG, JPEG, PNG, GIF", nil)
 		return
 	}
 
-	// Créer le dossier de destination s'il n'existe pas
-	avatarDir := config.AppConfig.AvatarDir
-	if err := os.MkdirAll(avatarDir, 0755); err != nil {
-		utils.InternalServerErrorResponse(c, "Erreur lors de la création du dossier de destination")
+	updatedByID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
 		return
 	}
 
-	// Générer un nom de fichier unique
-	timestamp := time.Now().Unix()
-	fileName := fmt.Sprintf("user_%d_%d%s", id, timestamp, ext)
-	filePath := filepath.Join(avatarDir, fileName)
+	content, err := file.Open()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+	defer content.Close()
 
-	// Sauvegarder le fichier
-	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		utils.InternalServerErrorResponse(c, "Erreur lors de la sauvegarde du fichier")
+	// Redimensionner, enregistrer l'avatar et sa miniature puis mettre à jour l'utilisateur
+	user, err := h.userService.UploadAvatar(userID, io.LimitReader(content, config.AppConfig.AvatarMaxSize), updatedByID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
-	// TODO: Générer une miniature (100x100)
-	// Pour l'instant, on utilise le fichier original
+	utils.SuccessResponse(c, user, "Avatar uploadé avec succès")
+}
 
-	updatedByID, exists := c.Get("user_id")
-	if !exists {
-		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+// ServeAvatarFile sert un fichier d'avatar par sa clé, sans authentification
+// La clé change à chaque nouvel avatar : le contenu est immuable et mis en cache longuement
+// @Summary Servir un fichier d'avatar
+// @Description Sert un avatar ou une miniature par la clé retournée dans avatar_url / avatar_thumbnail_url (cacheable)
+// @Tags users
+// @Produce image/*
+// @Param key path string true "Clé du fichier d'avatar"
+// @Success 200 {file} file "Image de l'avatar"
+// @Failure 404 {object} utils.Response
+// @Router /avatars/{key} [get]
+func (h *UserHandler) ServeAvatarFile(c *gin.Context) {
+	key := c.Param("key")
+	if match := c.GetHeader("If-None-Match"); match != "" && match == avatarETag(key) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
-	// Mettre à jour l'avatar dans la base de données
-	user, err := h.userService.UploadAvatar(uint(id), fileName, updatedByID.(uint))
+	object, err := h.userService.OpenAvatarFile(key)
 	if err != nil {
-		// Supprimer le fichier en cas d'erreur
-		os.Remove(filePath)
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.NotFoundResponse(c, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, user, "Avatar uploadé avec succès")
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", avatarETag(key))
+	serveStorageObject(c, object, "")
+}
+
+// avatarETag retourne l'ETag d'un fichier d'avatar (la clé suffit, le contenu ne change jamais)
+func avatarETag(key string) string {
+	return `"` + key + `"`
 }
 
 // GetAvatar récupère l'avatar d'un utilisateur
@@ -552,13 +807,13 @@ func (h *UserHandler) GetAvatar(c *gin.Context) {
 		return
 	}
 
-	avatarPath, err := h.userService.GetAvatarPath(uint(id))
+	object, err := h.userService.OpenAvatar(uint(id))
 	if err != nil {
 		utils.NotFoundResponse(c, err.Error())
 		return
 	}
 
-	c.File(avatarPath)
+	serveStorageObject(c, object, "")
 }
 
 // GetAvatarThumbnail récupère la miniature de l'avatar d'un utilisateur
@@ -579,13 +834,13 @@ func (h *UserHandler) GetAvatarThumbnail(c *gin.Context) {
 		return
 	}
 
-	thumbnailPath, err := h.userService.GetAvatarThumbnailPath(uint(id))
+	object, err := h.userService.OpenAvatarThumbnail(uint(id))
 	if err != nil {
 		utils.NotFoundResponse(c, err.Error())
 		return
 	}
 
-	c.File(thumbnailPath)
+	serveStorageObject(c, object, "")
 }
 
 // DeleteAvatar supprime l'avatar d'un utilisateur
@@ -614,7 +869,32 @@ func (h *UserHandler) DeleteAvatar(c *gin.Context) {
 
 	user, err := h.userService.DeleteAvatar(uint(id), updatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, user, "Avatar supprimé avec succès")
+}
+
+// DeleteMyAvatar supprime l'avatar de l'utilisateur connecté
+// @Summary Supprimer mon avatar
+// @Description Supprime l'avatar de l'utilisateur connecté
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.UserDTO
+// @Failure 400 {object} utils.Response
+// @Router /users/me/avatar [delete]
+func (h *UserHandler) DeleteMyAvatar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	user, err := h.userService.DeleteAvatar(userID.(uint), userID.(uint))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
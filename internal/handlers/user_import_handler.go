@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/spreadsheet"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// UserImportHandler gère l'import en masse d'utilisateurs et l'activation des comptes invités
+type UserImportHandler struct {
+	importService services.UserImportService
+}
+
+// NewUserImportHandler crée une nouvelle instance de UserImportHandler
+func NewUserImportHandler(importService services.UserImportService) *UserImportHandler {
+	return &UserImportHandler{
+		importService: importService,
+	}
+}
+
+// Import importe des utilisateurs depuis un fichier CSV ou XLSX
+// @Summary Importer des utilisateurs
+// @Description Importe des utilisateurs depuis un fichier CSV ou XLSX (en-tête en première ligne). Le rôle est désigné par son nom, le département et la filiale par leur code. Chaque compte reçoit un mot de passe initial (generate) ou un lien d'invitation (invite). Avec dry_run=true, le fichier est seulement validé
+// @Tags users
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Fichier CSV ou XLSX"
+// @Param mapping formData string false "Correspondance JSON champ -> en-tête (ex: {\"email\":\"Courriel\"}) ; champs : username, email, first_name, last_name, phone, role, department, filiale"
+// @Param password_mode formData string false "generate (défaut) ou invite"
+// @Param dry_run formData bool false "Validation seule, sans création"
+// @Success 200 {object} dto.UserImportReportDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /users/import [post]
+func (h *UserImportHandler) Import(c *gin.Context) {
+	if !utils.RequirePermission(c, "users.create") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: users.create")
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Fichier manquant", err.Error())
+		return
+	}
+	if file.Size > config.AppConfig.MaxUploadSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Fichier trop volumineux. Taille maximale: %d bytes", config.AppConfig.MaxUploadSize), nil)
+		return
+	}
+
+	opts := dto.UserImportOptions{PasswordMode: c.PostForm("password_mode")}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Mapping); err != nil {
+			utils.BadRequestResponse(c, "Correspondance des colonnes invalide (objet JSON attendu)")
+			return
+		}
+	}
+	if raw := c.PostForm("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "Paramètre dry_run invalide")
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+	defer content.Close()
+	data, err := io.ReadAll(io.LimitReader(content, config.AppConfig.MaxUploadSize))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+
+	rows, err := spreadsheet.ReadRows(data, file.Filename)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrUnsupportedFormat) || errors.Is(err, spreadsheet.ErrInvalidFile) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Erreur lors de la lecture du fichier")
+		return
+	}
+
+	report, err := h.importService.Import(rows, opts, userID, utils.RequirePermission(c, "users.create_any_filiale"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	message := "Import effectué"
+	if report.DryRun {
+		message = "Simulation d'import effectuée"
+	}
+	utils.SuccessResponse(c, report, message)
+}
+
+// AcceptInvitation active un compte invité
+// @Summary Activer un compte invité
+// @Description Définit le mot de passe d'un compte créé par import à partir du token de son lien d'invitation (usage unique)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.AcceptInvitationRequest true "Token d'invitation et mot de passe"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/invitations/accept [post]
+func (h *UserImportHandler) AcceptInvitation(c *gin.Context) {
+	var req dto.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.importService.AcceptInvitation(req); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Compte activé avec succès, vous pouvez vous connecter")
+}
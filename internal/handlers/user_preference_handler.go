@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// UserPreferenceHandler gère les préférences de l'utilisateur connecté
+type UserPreferenceHandler struct {
+	preferenceService services.UserPreferenceService
+}
+
+// NewUserPreferenceHandler crée une nouvelle instance de UserPreferenceHandler
+func NewUserPreferenceHandler(preferenceService services.UserPreferenceService) *UserPreferenceHandler {
+	return &UserPreferenceHandler{
+		preferenceService: preferenceService,
+	}
+}
+
+// GetMyPreferences récupère les préférences de l'utilisateur connecté
+// @Summary Mes préférences
+// @Description Langue, fuseau horaire, format de date, tableau de bord par défaut et réglages des notifications (valeurs par défaut si jamais enregistrées)
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.UserPreferencesDTO
+// @Failure 401 {object} utils.Response
+// @Router /users/me/preferences [get]
+func (h *UserPreferenceHandler) GetMyPreferences(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	preferences, err := h.preferenceService.Get(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, preferences, "Préférences récupérées avec succès")
+}
+
+// UpdateMyPreferences met à jour les préférences de l'utilisateur connecté
+// @Summary Modifier mes préférences
+// @Description Met à jour les préférences fournies (les champs omis sont inchangés, une chaîne vide rétablit le comportement par défaut)
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateUserPreferencesRequest true "Préférences à modifier"
+// @Success 200 {object} dto.UserPreferencesDTO
+// @Failure 400 {object} utils.Response
+// @Router /users/me/preferences [put]
+func (h *UserPreferenceHandler) UpdateMyPreferences(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	var req dto.UpdateUserPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	preferences, err := h.preferenceService.Update(userID, req)
+	if err != nil {
+		if err.Error() == "erreur lors de l'enregistrement des préférences" || err.Error() == "erreur lors de la récupération des préférences" {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, preferences, "Préférences mises à jour avec succès")
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// WebhookHandler gère les handlers des abonnements webhook
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+// NewWebhookHandler crée une nouvelle instance de WebhookHandler
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// GetAll récupère tous les abonnements webhook
+// @Summary Récupérer les webhooks
+// @Description Récupère la liste des abonnements webhook (nécessite webhooks.view)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.WebhookSubscriptionDTO
+// @Failure 403 {object} utils.Response
+// @Router /webhooks [get]
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.view")
+		return
+	}
+
+	subscriptions, err := h.webhookService.GetAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, subscriptions, "Webhooks récupérés avec succès")
+}
+
+// GetEventTypes récupère les types d'événements disponibles
+// @Summary Types d'événements webhook
+// @Description Liste les événements auxquels un webhook peut s'abonner
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.WebhookEventTypeDTO
+// @Router /webhooks/events [get]
+func (h *WebhookHandler) GetEventTypes(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.view")
+		return
+	}
+
+	utils.SuccessResponse(c, h.webhookService.GetEventTypes(), "Types d'événements récupérés avec succès")
+}
+
+// GetByID récupère un abonnement webhook par son ID
+// @Summary Récupérer un webhook par ID
+// @Description Récupère un abonnement webhook par son identifiant
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du webhook"
+// @Success 200 {object} dto.WebhookSubscriptionDTO
+// @Failure 404 {object} utils.Response
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	subscription, err := h.webhookService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, subscription, "Webhook récupéré avec succès")
+}
+
+// Create crée un abonnement webhook
+// @Summary Créer un webhook
+// @Description Crée un abonnement webhook. Le secret de signature n'est retourné qu'à la création (nécessite webhooks.manage)
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWebhookSubscriptionRequest true "Données du webhook"
+// @Success 201 {object} dto.WebhookSubscriptionDTO
+// @Failure 400 {object} utils.Response
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.manage")
+		return
+	}
+
+	var req dto.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	subscription, err := h.webhookService.Create(req, userID.(uint))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, subscription, "Webhook créé avec succès")
+}
+
+// Update met à jour un abonnement webhook
+// @Summary Mettre à jour un webhook
+// @Description Met à jour un abonnement webhook ; regenerate_secret=true retourne un nouveau secret (nécessite webhooks.manage)
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du webhook"
+// @Param request body dto.UpdateWebhookSubscriptionRequest true "Données à mettre à jour"
+// @Success 200 {object} dto.WebhookSubscriptionDTO
+// @Failure 400 {object} utils.Response
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	subscription, err := h.webhookService.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, subscription, "Webhook mis à jour avec succès")
+}
+
+// Delete supprime un abonnement webhook
+// @Summary Supprimer un webhook
+// @Description Supprime un abonnement webhook et son journal de livraison (nécessite webhooks.manage)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du webhook"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.webhookService.Delete(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Webhook supprimé avec succès")
+}
+
+// Ping envoie un événement de test
+// @Summary Tester un webhook
+// @Description Envoie un événement webhook.ping à l'URL de l'abonnement (nécessite webhooks.manage)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du webhook"
+// @Success 202 {object} dto.WebhookDeliveryDTO
+// @Failure 404 {object} utils.Response
+// @Router /webhooks/{id}/test [post]
+func (h *WebhookHandler) Ping(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	delivery, err := h.webhookService.Ping(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.AcceptedResponse(c, delivery, "Événement de test planifié")
+}
+
+// GetDeliveries récupère le journal de livraison d'un abonnement
+// @Summary Journal de livraison d'un webhook
+// @Description Liste paginée des livraisons d'un webhook, plus récentes d'abord (nécessite webhooks.view)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du webhook"
+// @Param status query string false "Filtrer par statut (pending, sending, retrying, success, failed)"
+// @Param page query int false "Numéro de page" default(1)
+// @Param limit query int false "Nombre d'éléments par page" default(20)
+// @Success 200 {object} dto.WebhookDeliveryListResponse
+// @Failure 404 {object} utils.Response
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.view") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.view")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	deliveries, err := h.webhookService.GetDeliveries(uint(id), c.Query("status"), page, limit)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, deliveries, "Journal de livraison récupéré avec succès")
+}
+
+// Redeliver replanifie une livraison
+// @Summary Renvoyer une livraison
+// @Description Replanifie immédiatement l'envoi d'une livraison avec le même corps (nécessite webhooks.manage)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du webhook"
+// @Param deliveryId path int true "ID de la livraison"
+// @Success 202 {object} dto.WebhookDeliveryDTO
+// @Failure 400 {object} utils.Response
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	if !utils.RequirePermission(c, "webhooks.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: webhooks.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID de livraison invalide")
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(uint(id), uint(deliveryID))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.AcceptedResponse(c, delivery, "Livraison replanifiée")
+}
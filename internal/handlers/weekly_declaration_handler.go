@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -117,9 +116,48 @@ func (h *WeeklyDeclarationHandler) GetByUserID(c *gin.Context) {
 	utils.SuccessResponse(c, declarations, "Déclarations récupérées avec succès")
 }
 
+// requireDeclarationValidator vérifie que l'utilisateur peut se prononcer sur la déclaration : permission
+// timesheet.validate ou responsable hiérarchique de son auteur (réponse d'erreur envoyée sinon)
+func (h *WeeklyDeclarationHandler) requireDeclarationValidator(c *gin.Context, id uint) bool {
+	if utils.RequirePermission(c, "timesheet.validate") {
+		return true
+	}
+	queryScope, ok := utils.RequireScope(c)
+	if !ok {
+		return false
+	}
+	declaration, err := h.weeklyDeclarationService.GetByID(id)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return false
+	}
+	if !queryScope.IsManagerOf(declaration.UserID) {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.validate")
+		return false
+	}
+	return true
+}
+
+// bindDeclarationAction lit l'identifiant de la déclaration et le commentaire facultatif de l'étape
+func bindDeclarationAction(c *gin.Context) (uint, dto.WeeklyDeclarationActionRequest, bool) {
+	var req dto.WeeklyDeclarationActionRequest
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return 0, req, false
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return 0, req, false
+		}
+	}
+	return uint(id), req, true
+}
+
 // Validate valide une déclaration
 // @Summary Valider une déclaration hebdomadaire
-// @Description Valide une déclaration hebdomadaire
+// @Description Approuve une déclaration soumise, sans commentaire (voir /approve) ; les entrées de temps de la semaine sont verrouillées (nécessite timesheet.validate ou d'être le responsable de l'auteur)
 // @Tags weekly-declarations
 // @Security BearerAuth
 // @Accept json
@@ -128,6 +166,7 @@ func (h *WeeklyDeclarationHandler) GetByUserID(c *gin.Context) {
 // @Success 200 {object} weeklyDeclarationDTO
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /weekly-declarations/{id}/validate [post]
 func (h *WeeklyDeclarationHandler) Validate(c *gin.Context) {
 	idParam := c.Param("id")
@@ -143,12 +182,170 @@ func (h *WeeklyDeclarationHandler) Validate(c *gin.Context) {
 		return
 	}
 
+	if !h.requireDeclarationValidator(c, uint(id)) {
+		return
+	}
+
 	declaration, err := h.weeklyDeclarationService.Validate(uint(id), validatedByID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		utils.ServiceErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, declaration, "Déclaration validée avec succès")
 }
 
+// Approve approuve une déclaration soumise
+// @Summary Approuver une déclaration hebdomadaire
+// @Description Approuve une déclaration soumise : la semaine est verrouillée, ses entrées de temps ne peuvent plus être créées, modifiées ni supprimées jusqu'au déverrouillage (nécessite timesheet.validate ou d'être le responsable de l'auteur)
+// @Tags weekly-declarations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la déclaration"
+// @Param request body dto.WeeklyDeclarationActionRequest false "Commentaire de validation"
+// @Success 200 {object} weeklyDeclarationDTO
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /weekly-declarations/{id}/approve [post]
+func (h *WeeklyDeclarationHandler) Approve(c *gin.Context) {
+	id, req, ok := bindDeclarationAction(c)
+	if !ok {
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+	if !h.requireDeclarationValidator(c, id) {
+		return
+	}
+
+	declaration, err := h.weeklyDeclarationService.Approve(id, userID, req.Comment)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, declaration, "Déclaration approuvée, semaine verrouillée")
+}
+
+// Reject rejette une déclaration soumise
+// @Summary Rejeter une déclaration hebdomadaire
+// @Description Rejette une déclaration soumise avec un motif obligatoire ; son auteur peut la corriger puis la soumettre à nouveau (nécessite timesheet.validate ou d'être le responsable de l'auteur)
+// @Tags weekly-declarations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la déclaration"
+// @Param request body dto.WeeklyDeclarationActionRequest true "Motif du rejet"
+// @Success 200 {object} weeklyDeclarationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /weekly-declarations/{id}/reject [post]
+func (h *WeeklyDeclarationHandler) Reject(c *gin.Context) {
+	id, req, ok := bindDeclarationAction(c)
+	if !ok {
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+	if !h.requireDeclarationValidator(c, id) {
+		return
+	}
+
+	declaration, err := h.weeklyDeclarationService.Reject(id, userID, req.Comment)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, declaration, "Déclaration rejetée")
+}
+
+// Unlock déverrouille une déclaration approuvée
+// @Summary Déverrouiller une déclaration hebdomadaire
+// @Description Repasse une déclaration approuvée en brouillon avec un motif obligatoire : la saisie de temps de la semaine est rouverte et la déclaration devra être soumise à nouveau (nécessite timesheet.validate)
+// @Tags weekly-declarations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la déclaration"
+// @Param request body dto.WeeklyDeclarationActionRequest true "Motif du déverrouillage"
+// @Success 200 {object} weeklyDeclarationDTO
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /weekly-declarations/{id}/unlock [post]
+func (h *WeeklyDeclarationHandler) Unlock(c *gin.Context) {
+	if !utils.RequirePermission(c, "timesheet.validate") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.validate")
+		return
+	}
+
+	id, req, ok := bindDeclarationAction(c)
+	if !ok {
+		return
+	}
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	declaration, err := h.weeklyDeclarationService.Unlock(id, userID, req.Comment)
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, declaration, "Déclaration déverrouillée")
+}
+
+// GetHistory récupère l'historique du circuit de validation d'une déclaration
+// @Summary Historique d'une déclaration hebdomadaire
+// @Description Récupère les changements de statut de la déclaration (soumission, approbation, rejet, déverrouillage) avec leur auteur, leur date et leur commentaire ; réservé à l'auteur, aux validateurs (timesheet.validate, timesheet.view_all) et à son responsable
+// @Tags weekly-declarations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID de la déclaration"
+// @Success 200 {array} dto.WeeklyDeclarationEventDTO
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /weekly-declarations/{id}/history [get]
+func (h *WeeklyDeclarationHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if !utils.RequirePermission(c, "timesheet.validate") && !utils.RequirePermission(c, "timesheet.view_all") {
+		queryScope, ok := utils.RequireScope(c)
+		if !ok {
+			return
+		}
+		declaration, err := h.weeklyDeclarationService.GetByID(uint(id))
+		if err != nil {
+			utils.ServiceErrorResponse(c, err)
+			return
+		}
+		if declaration.UserID != queryScope.UserID && !queryScope.IsManagerOf(declaration.UserID) {
+			utils.ForbiddenResponse(c, "Permission insuffisante: timesheet.view_all")
+			return
+		}
+	}
+
+	history, err := h.weeklyDeclarationService.GetHistory(uint(id))
+	if err != nil {
+		utils.ServiceErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, history, "Historique récupéré avec succès")
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/services"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// WhatsAppHandler gère les modèles de messages WhatsApp et le consentement des utilisateurs
+type WhatsAppHandler struct {
+	whatsAppService services.WhatsAppService
+}
+
+// NewWhatsAppHandler crée une nouvelle instance de WhatsAppHandler
+func NewWhatsAppHandler(whatsAppService services.WhatsAppService) *WhatsAppHandler {
+	return &WhatsAppHandler{
+		whatsAppService: whatsAppService,
+	}
+}
+
+// whatsAppErrorResponse traduit une erreur du service en réponse HTTP
+func whatsAppErrorResponse(c *gin.Context, err error) {
+	if utils.AppErrorResponse(c, err) {
+		return
+	}
+	switch {
+	case strings.HasSuffix(err.Error(), "introuvable"):
+		utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "un modèle existe déjà"):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case strings.HasSuffix(err.Error(), "non configuré"):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case strings.HasPrefix(err.Error(), "erreur lors"):
+		utils.InternalServerErrorResponse(c, err.Error())
+	default:
+		utils.ServiceErrorResponse(c, err)
+	}
+}
+
+// GetTemplates récupère les modèles de messages WhatsApp
+// @Summary Lister les modèles WhatsApp
+// @Description Liste les modèles de messages associés aux notifications critiques (nécessite whatsapp.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.WhatsAppTemplateDTO
+// @Failure 403 {object} utils.Response
+// @Router /integrations/whatsapp/templates [get]
+func (h *WhatsAppHandler) GetTemplates(c *gin.Context) {
+	if !utils.RequirePermission(c, "whatsapp.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: whatsapp.manage")
+		return
+	}
+
+	templates, err := h.whatsAppService.GetTemplates()
+	if err != nil {
+		whatsAppErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, templates, "Modèles WhatsApp récupérés avec succès")
+}
+
+// CreateTemplate associe un modèle de message à un type de notification critique
+// @Summary Créer un modèle WhatsApp
+// @Description Associe un modèle approuvé dans WhatsApp Manager à un type de notification critique (sla_violated, major_incident). parameters liste, dans l'ordre des variables {{1}}, {{2}}, ... du corps du modèle, les champs transmis : title, message, ticket_code (nécessite whatsapp.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWhatsAppTemplateRequest true "Modèle à créer"
+// @Success 201 {object} dto.WhatsAppTemplateDTO
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /integrations/whatsapp/templates [post]
+func (h *WhatsAppHandler) CreateTemplate(c *gin.Context) {
+	if !utils.RequirePermission(c, "whatsapp.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: whatsapp.manage")
+		return
+	}
+
+	var req dto.CreateWhatsAppTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	template, err := h.whatsAppService.CreateTemplate(req, userID)
+	if err != nil {
+		whatsAppErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, template, "Modèle WhatsApp créé avec succès")
+}
+
+// UpdateTemplate met à jour un modèle de message WhatsApp
+// @Summary Modifier un modèle WhatsApp
+// @Description Met à jour le nom, la langue, les variables ou l'activation d'un modèle ; un modèle inactif suspend l'envoi de son type de notification (nécessite whatsapp.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du modèle"
+// @Param request body dto.UpdateWhatsAppTemplateRequest true "Champs à modifier"
+// @Success 200 {object} dto.WhatsAppTemplateDTO
+// @Failure 404 {object} utils.Response
+// @Router /integrations/whatsapp/templates/{id} [put]
+func (h *WhatsAppHandler) UpdateTemplate(c *gin.Context) {
+	if !utils.RequirePermission(c, "whatsapp.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: whatsapp.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	var req dto.UpdateWhatsAppTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template, err := h.whatsAppService.UpdateTemplate(uint(id), req)
+	if err != nil {
+		whatsAppErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, template, "Modèle WhatsApp mis à jour avec succès")
+}
+
+// DeleteTemplate supprime un modèle de message WhatsApp
+// @Summary Supprimer un modèle WhatsApp
+// @Description Le type de notification du modèle n'est plus envoyé sur WhatsApp (nécessite whatsapp.manage)
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID du modèle"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /integrations/whatsapp/templates/{id} [delete]
+func (h *WhatsAppHandler) DeleteTemplate(c *gin.Context) {
+	if !utils.RequirePermission(c, "whatsapp.manage") {
+		utils.ForbiddenResponse(c, "Permission insuffisante: whatsapp.manage")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID invalide")
+		return
+	}
+
+	if err := h.whatsAppService.DeleteTemplate(uint(id)); err != nil {
+		whatsAppErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Modèle WhatsApp supprimé avec succès")
+}
+
+// GetOptIn retourne l'état du consentement WhatsApp de l'utilisateur connecté
+// @Summary État de l'inscription WhatsApp
+// @Description Indique si le canal est configuré, si l'utilisateur a consenti (numéro et date) et les types de notification envoyés sur WhatsApp
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.WhatsAppOptInDTO
+// @Router /integrations/whatsapp/opt-in [get]
+func (h *WhatsAppHandler) GetOptIn(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	status, err := h.whatsAppService.GetOptIn(userID)
+	if err != nil {
+		whatsAppErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, status, "Inscription WhatsApp récupérée avec succès")
+}
+
+// UpdateOptIn inscrit l'utilisateur connecté aux notifications critiques sur WhatsApp
+// @Summary S'inscrire aux notifications WhatsApp
+// @Description Enregistre le numéro (format international) et le consentement explicite de l'utilisateur ; il reçoit alors sur WhatsApp ses notifications critiques (SLA violés, incidents majeurs) dont le type a un modèle actif
+// @Tags integrations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateWhatsAppOptInRequest true "Numéro et consentement"
+// @Success 200 {object} dto.WhatsAppOptInDTO
+// @Failure 400 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /integrations/whatsapp/opt-in [put]
+func (h *WhatsAppHandler) UpdateOptIn(c *gin.Context) {
+	var req dto.UpdateWhatsAppOptInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	status, err := h.whatsAppService.OptIn(userID, req)
+	if err != nil {
+		whatsAppErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, status, "Inscription WhatsApp enregistrée")
+}
+
+// DeleteOptIn désinscrit l'utilisateur connecté des notifications WhatsApp
+// @Summary Se désinscrire des notifications WhatsApp
+// @Description Retire le consentement et le numéro enregistrés
+// @Tags integrations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /integrations/whatsapp/opt-in [delete]
+func (h *WhatsAppHandler) DeleteOptIn(c *gin.Context) {
+	userID, exists := utils.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "Utilisateur non authentifié")
+		return
+	}
+
+	if err := h.whatsAppService.OptOut(userID); err != nil {
+		whatsAppErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Désinscription WhatsApp effectuée")
+}
@@ -0,0 +1,204 @@
+package helpdesk
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns en-têtes reconnus pour chaque champ (comparaison insensible à la casse), par étape
+// Les exports CSV de Zendesk et Freshdesk sont reconnus tels quels
+var csvColumns = map[string]map[string][]string{
+	StageContacts: {
+		"id":         {"id", "external_id", "contact id", "user id"},
+		"name":       {"name", "nom complet", "full name", "contact name"},
+		"first_name": {"first_name", "first name", "prénom", "prenom"},
+		"last_name":  {"last_name", "last name", "nom"},
+		"email":      {"email", "e-mail", "mail", "courriel"},
+		"phone":      {"phone", "work phone", "téléphone", "telephone", "mobile phone", "mobile"},
+		"active":     {"active", "actif", "status"},
+	},
+	StageTickets: {
+		"id":              {"id", "ticket id", "external_id"},
+		"subject":         {"subject", "title", "sujet", "titre"},
+		"description":     {"description", "content", "body"},
+		"status":          {"status", "statut"},
+		"priority":        {"priority", "priorité", "priorite"},
+		"type":            {"type", "category", "catégorie", "categorie"},
+		"channel":         {"channel", "source", "via", "canal"},
+		"tags":            {"tags", "étiquettes"},
+		"requester_email": {"requester_email", "requester email", "requester", "demandeur", "contact email"},
+		"assignee_email":  {"assignee_email", "assignee email", "assignee", "agent", "agent email", "assigné"},
+		"created_at":      {"created_at", "created at", "created time", "date de création"},
+		"updated_at":      {"updated_at", "updated at", "last updated time", "date de modification"},
+	},
+	StageArticles: {
+		"id":         {"id", "article id", "external_id"},
+		"title":      {"title", "titre"},
+		"body":       {"body", "content", "description", "contenu"},
+		"section":    {"section", "folder", "category", "rubrique", "catégorie"},
+		"published":  {"published", "status", "publié", "statut"},
+		"created_at": {"created_at", "created at", "date de création"},
+		"updated_at": {"updated_at", "updated at", "date de modification"},
+	},
+}
+
+// csvDateLayouts formats de date acceptés dans les fichiers
+var csvDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", "02/01/2006 15:04:05", "02/01/2006 15:04", "02/01/2006"}
+
+// CSV lit des fichiers CSV ou XLSX déjà découpés en lignes (en-tête en première ligne), un par étape
+// Position : nombre de lignes de données déjà lues ; les tickets n'ont pas de commentaires
+type CSV struct {
+	files map[string][][]string
+	limit int
+}
+
+// NewCSV crée un lecteur de fichiers ; files associe une étape (contacts, tickets, articles) aux lignes de son fichier
+func NewCSV(files map[string][][]string, limit int) *CSV {
+	return &CSV{files: files, limit: limit}
+}
+
+// MissingColumns retourne les champs de required absents de l'en-tête d'un fichier de l'étape
+func MissingColumns(stage string, header []string, required ...string) []string {
+	columns := csvColumnIndexes(stage, header)
+	var missing []string
+	for _, field := range required {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// Contacts lit un lot de lignes du fichier des contacts
+func (c *CSV) Contacts(ctx context.Context, position int) (*Page[Contact], error) {
+	page := &Page[Contact]{}
+	page.Next, page.Total, page.Done = c.read(StageContacts, position, func(row func(string) string, line int) {
+		page.Items = append(page.Items, Contact{
+			ExternalID: csvID(row("id"), line),
+			Name:       row("name"),
+			FirstName:  row("first_name"),
+			LastName:   row("last_name"),
+			Email:      row("email"),
+			Phone:      row("phone"),
+			Active:     csvBool(row("active"), true),
+		})
+	})
+	return page, nil
+}
+
+// Tickets lit un lot de lignes du fichier des tickets
+func (c *CSV) Tickets(ctx context.Context, position int) (*Page[Ticket], error) {
+	page := &Page[Ticket]{}
+	page.Next, page.Total, page.Done = c.read(StageTickets, position, func(row func(string) string, line int) {
+		tags := strings.FieldsFunc(row("tags"), func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+		page.Items = append(page.Items, Ticket{
+			ExternalID:     csvID(row("id"), line),
+			Subject:        row("subject"),
+			Description:    row("description"),
+			Status:         row("status"),
+			Priority:       row("priority"),
+			Type:           row("type"),
+			Channel:        row("channel"),
+			Tags:           tags,
+			RequesterEmail: row("requester_email"),
+			AssigneeEmail:  row("assignee_email"),
+			CreatedAt:      csvDate(row("created_at")),
+			UpdatedAt:      csvDate(row("updated_at")),
+		})
+	})
+	return page, nil
+}
+
+// Comments les fichiers ne contiennent pas de commentaires
+func (c *CSV) Comments(ctx context.Context, ticketID string) ([]Comment, error) {
+	return nil, nil
+}
+
+// Articles lit un lot de lignes du fichier des articles
+func (c *CSV) Articles(ctx context.Context, position int) (*Page[Article], error) {
+	page := &Page[Article]{}
+	page.Next, page.Total, page.Done = c.read(StageArticles, position, func(row func(string) string, line int) {
+		page.Items = append(page.Items, Article{
+			ExternalID: csvID(row("id"), line),
+			Title:      row("title"),
+			Body:       row("body"),
+			Section:    row("section"),
+			Published:  csvBool(row("published"), true),
+			CreatedAt:  csvDate(row("created_at")),
+			UpdatedAt:  csvDate(row("updated_at")),
+		})
+	})
+	return page, nil
+}
+
+// read appelle fn pour chaque ligne de données du lot commençant à position (line : numéro de ligne dans le fichier)
+// Retourne la position suivante, le nombre de lignes de données et si le fichier est entièrement lu
+func (c *CSV) read(stage string, position int, fn func(row func(string) string, line int)) (int, int, bool) {
+	rows := c.files[stage]
+	if len(rows) < 2 || position >= len(rows)-1 {
+		return position, max(len(rows)-1, 0), true
+	}
+	total := len(rows) - 1
+	end := min(position+c.limit, total)
+	columns := csvColumnIndexes(stage, rows[0])
+	for i := position; i < end; i++ {
+		values := rows[i+1]
+		fn(func(field string) string {
+			index, ok := columns[field]
+			if !ok || index >= len(values) {
+				return ""
+			}
+			return strings.TrimSpace(values[index])
+		}, i+2)
+	}
+	return end, total, end >= total
+}
+
+// csvColumnIndexes associe chaque champ reconnu à sa colonne (première colonne correspondante)
+func csvColumnIndexes(stage string, header []string) map[string]int {
+	columns := map[string]int{}
+	for field, names := range csvColumns[stage] {
+		for i, h := range header {
+			if slices.Contains(names, strings.ToLower(strings.TrimSpace(h))) {
+				columns[field] = i
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// csvID retourne l'identifiant d'une ligne, ou son numéro de ligne si la colonne est absente ou vide
+func csvID(id string, line int) string {
+	if id != "" {
+		return id
+	}
+	return "line-" + strconv.Itoa(line)
+}
+
+// csvBool interprète une valeur oui/non (valeur par défaut si vide ou inconnue)
+func csvBool(value string, fallback bool) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "oui", "active", "actif", "published", "publié":
+		return true
+	case "0", "false", "no", "non", "inactive", "inactif", "draft", "brouillon":
+		return false
+	}
+	return fallback
+}
+
+// csvDate interprète une date dans l'un des formats acceptés (nil si vide ou invalide)
+func csvDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	for _, layout := range csvDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package helpdesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valeurs numériques Freshdesk converties en libellés (clés des correspondances de l'import)
+var (
+	freshdeskStatuses   = map[int]string{2: "open", 3: "pending", 4: "resolved", 5: "closed"}
+	freshdeskPriorities = map[int]string{1: "low", 2: "medium", 3: "high", 4: "urgent"}
+	freshdeskSources    = map[int]string{1: "email", 2: "portal", 3: "phone", 7: "chat", 9: "feedback_widget", 10: "outbound_email"}
+)
+
+// Freshdesk lit une instance Freshdesk (authentification par clé d'API)
+// Contacts et tickets sont lus par page (position : nombre de pages lues) ; l'API limite la liste des tickets à 300 pages
+type Freshdesk struct {
+	api   apiClient
+	limit int
+
+	folders []freshdeskFolder // Rubriques de la base de connaissances, chargées au premier lot d'articles
+}
+
+type freshdeskFolder struct {
+	ID   int64
+	Name string
+}
+
+// NewFreshdesk crée un lecteur Freshdesk
+func NewFreshdesk(baseURL, apiKey string, limit int) *Freshdesk {
+	return &Freshdesk{
+		api: apiClient{
+			name:     "Freshdesk",
+			baseURL:  strings.TrimRight(baseURL, "/"),
+			username: apiKey,
+			password: "X",
+			http:     &http.Client{Timeout: requestTimeout},
+		},
+		limit: limit,
+	}
+}
+
+// Contacts lit une page de contacts
+func (f *Freshdesk) Contacts(ctx context.Context, position int) (*Page[Contact], error) {
+	var contacts []struct {
+		ID     int64  `json:"id"`
+		Name   string `json:"name"`
+		Email  string `json:"email"`
+		Phone  string `json:"phone"`
+		Mobile string `json:"mobile"`
+		Active bool   `json:"active"`
+	}
+	path := fmt.Sprintf("/api/v2/contacts?per_page=%d&page=%d", f.limit, position+1)
+	if err := f.api.get(ctx, path, &contacts); err != nil {
+		return nil, err
+	}
+
+	page := &Page[Contact]{Next: position + 1, Done: len(contacts) < f.limit}
+	for _, c := range contacts {
+		phone := c.Phone
+		if phone == "" {
+			phone = c.Mobile
+		}
+		page.Items = append(page.Items, Contact{
+			ExternalID: strconv.FormatInt(c.ID, 10),
+			Name:       c.Name,
+			Email:      c.Email,
+			Phone:      phone,
+			Active:     c.Active,
+		})
+	}
+	return page, nil
+}
+
+// Tickets lit une page de tickets, du plus ancien au plus récent
+func (f *Freshdesk) Tickets(ctx context.Context, position int) (*Page[Ticket], error) {
+	var tickets []struct {
+		ID              int64      `json:"id"`
+		Subject         string     `json:"subject"`
+		DescriptionText string     `json:"description_text"`
+		Status          int        `json:"status"`
+		Priority        int        `json:"priority"`
+		Type            string     `json:"type"`
+		Source          int        `json:"source"`
+		Tags            []string   `json:"tags"`
+		RequesterID     *int64     `json:"requester_id"`
+		ResponderID     *int64     `json:"responder_id"`
+		CreatedAt       *time.Time `json:"created_at"`
+		UpdatedAt       *time.Time `json:"updated_at"`
+	}
+	// Sans updated_since, seuls les tickets des 30 derniers jours sont listés
+	path := fmt.Sprintf("/api/v2/tickets?include=description&updated_since=2000-01-01T00:00:00Z&order_by=created_at&order_type=asc&per_page=%d&page=%d", f.limit, position+1)
+	if err := f.api.get(ctx, path, &tickets); err != nil {
+		return nil, err
+	}
+
+	page := &Page[Ticket]{Next: position + 1, Done: len(tickets) < f.limit}
+	for _, t := range tickets {
+		page.Items = append(page.Items, Ticket{
+			ExternalID:  strconv.FormatInt(t.ID, 10),
+			Subject:     t.Subject,
+			Description: t.DescriptionText,
+			Status:      freshdeskLabel(freshdeskStatuses, t.Status),
+			Priority:    freshdeskLabel(freshdeskPriorities, t.Priority),
+			Type:        t.Type,
+			Channel:     freshdeskLabel(freshdeskSources, t.Source),
+			Tags:        t.Tags,
+			RequesterID: idString(t.RequesterID),
+			AssigneeID:  idString(t.ResponderID),
+			CreatedAt:   t.CreatedAt,
+			UpdatedAt:   t.UpdatedAt,
+		})
+	}
+	return page, nil
+}
+
+// Comments lit les conversations (réponses et notes) d'un ticket
+func (f *Freshdesk) Comments(ctx context.Context, ticketID string) ([]Comment, error) {
+	var comments []Comment
+	for pageNumber := 1; ; pageNumber++ {
+		var conversations []struct {
+			ID        int64      `json:"id"`
+			UserID    *int64     `json:"user_id"`
+			BodyText  string     `json:"body_text"`
+			Private   bool       `json:"private"`
+			CreatedAt *time.Time `json:"created_at"`
+		}
+		path := fmt.Sprintf("/api/v2/tickets/%s/conversations?per_page=100&page=%d", url.PathEscape(ticketID), pageNumber)
+		if err := f.api.get(ctx, path, &conversations); err != nil {
+			return nil, err
+		}
+		for _, c := range conversations {
+			comments = append(comments, Comment{
+				ExternalID: strconv.FormatInt(c.ID, 10),
+				AuthorID:   idString(c.UserID),
+				Body:       c.BodyText,
+				Public:     !c.Private,
+				CreatedAt:  c.CreatedAt,
+			})
+		}
+		if len(conversations) < 100 {
+			return comments, nil
+		}
+	}
+}
+
+// Articles lit tous les articles d'une rubrique (position : nombre de rubriques lues)
+func (f *Freshdesk) Articles(ctx context.Context, position int) (*Page[Article], error) {
+	if f.folders == nil {
+		if err := f.loadFolders(ctx); err != nil {
+			return nil, err
+		}
+	}
+	page := &Page[Article]{Next: position + 1, Total: len(f.folders), Done: position+1 >= len(f.folders)}
+	if position >= len(f.folders) {
+		page.Next, page.Done = position, true
+		return page, nil
+	}
+
+	folder := f.folders[position]
+	for pageNumber := 1; ; pageNumber++ {
+		var articles []struct {
+			ID          int64      `json:"id"`
+			Title       string     `json:"title"`
+			Description string     `json:"description"`
+			Status      int        `json:"status"` // 1 brouillon, 2 publié
+			CreatedAt   *time.Time `json:"created_at"`
+			UpdatedAt   *time.Time `json:"updated_at"`
+		}
+		path := fmt.Sprintf("/api/v2/solutions/folders/%d/articles?per_page=100&page=%d", folder.ID, pageNumber)
+		if err := f.api.get(ctx, path, &articles); err != nil {
+			return nil, err
+		}
+		for _, a := range articles {
+			page.Items = append(page.Items, Article{
+				ExternalID: strconv.FormatInt(a.ID, 10),
+				Title:      a.Title,
+				Body:       a.Description,
+				Section:    folder.Name,
+				Published:  a.Status == 2,
+				CreatedAt:  a.CreatedAt,
+				UpdatedAt:  a.UpdatedAt,
+			})
+		}
+		if len(articles) < 100 {
+			return page, nil
+		}
+	}
+}
+
+// loadFolders charge les rubriques de toutes les catégories de la base de connaissances
+func (f *Freshdesk) loadFolders(ctx context.Context) error {
+	var categories []struct {
+		ID int64 `json:"id"`
+	}
+	if err := f.api.get(ctx, "/api/v2/solutions/categories", &categories); err != nil {
+		return err
+	}
+	f.folders = []freshdeskFolder{}
+	for _, category := range categories {
+		var folders []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := f.api.get(ctx, fmt.Sprintf("/api/v2/solutions/categories/%d/folders", category.ID), &folders); err != nil {
+			return err
+		}
+		for _, folder := range folders {
+			f.folders = append(f.folders, freshdeskFolder{ID: folder.ID, Name: folder.Name})
+		}
+	}
+	return nil
+}
+
+// freshdeskLabel retourne le libellé d'une valeur numérique (la valeur elle-même si elle est inconnue)
+func freshdeskLabel(labels map[int]string, value int) string {
+	if label, ok := labels[value]; ok {
+		return label
+	}
+	return strconv.Itoa(value)
+}
@@ -0,0 +1,155 @@
+// Package helpdesk lit l'historique d'un outil de support (Zendesk, Freshdesk ou fichiers CSV/XLSX)
+// sous une forme commune, pour l'import dans Kronos des contacts, tickets et articles de base de connaissances
+package helpdesk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Étapes lues par un fournisseur
+const (
+	StageContacts = "contacts"
+	StageTickets  = "tickets"
+	StageArticles = "articles"
+)
+
+// requestTimeout borne la durée d'un appel à l'API d'un fournisseur
+const requestTimeout = 60 * time.Second
+
+// maxRetryAfter attente maximale respectée après une limitation de débit (HTTP 429)
+const maxRetryAfter = 60 * time.Second
+
+// Contact personne ayant ouvert des tickets (ou agent, selon l'outil)
+type Contact struct {
+	ExternalID string
+	Name       string
+	FirstName  string
+	LastName   string
+	Email      string
+	Phone      string
+	Active     bool
+}
+
+// Ticket ticket de l'outil source ; statut, priorité, type et canal sont les valeurs brutes de l'outil
+type Ticket struct {
+	ExternalID     string
+	Subject        string
+	Description    string
+	Status         string
+	Priority       string
+	Type           string
+	Channel        string
+	Tags           []string
+	RequesterID    string
+	RequesterEmail string
+	AssigneeID     string
+	AssigneeEmail  string
+	CreatedAt      *time.Time
+	UpdatedAt      *time.Time
+}
+
+// Comment réponse ou note d'un ticket
+type Comment struct {
+	ExternalID string
+	AuthorID   string
+	Body       string
+	Public     bool
+	CreatedAt  *time.Time
+}
+
+// Article article de base de connaissances ; Section est le libellé de sa rubrique dans l'outil
+type Article struct {
+	ExternalID string
+	Title      string
+	Body       string // HTML
+	Section    string
+	Published  bool
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
+}
+
+// Page lot d'éléments lus et position du lot suivant
+type Page[T any] struct {
+	Items []T
+	Next  int  // Position du lot suivant (rang, numéro de page ou horodatage selon l'outil)
+	Total int  // Nombre total d'éléments (0 si inconnu)
+	Done  bool // Dernier lot de l'étape
+}
+
+// Provider lit un outil de support lot par lot à partir d'une position opaque (0 pour le premier lot)
+type Provider interface {
+	Contacts(ctx context.Context, position int) (*Page[Contact], error)
+	Tickets(ctx context.Context, position int) (*Page[Ticket], error)
+	// Comments retourne les réponses d'un ticket, hors description initiale
+	Comments(ctx context.Context, ticketID string) ([]Comment, error)
+	Articles(ctx context.Context, position int) (*Page[Article], error)
+}
+
+// apiClient appelle une API REST JSON authentifiée par HTTP Basic
+type apiClient struct {
+	name     string
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+}
+
+// get lit une ressource JSON ; une limitation de débit est attendue puis l'appel est réessayé une fois
+func (c *apiClient) get(ctx context.Context, path string, out any) error {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(c.username, c.password)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+			wait := maxRetryAfter
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(seconds)*time.Second < wait {
+				wait = time.Duration(seconds) * time.Second
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			if len(body) > 512 {
+				body = body[:512]
+			}
+			return fmt.Errorf("API %s GET %s: réponse HTTP %d: %s", c.name, path, resp.StatusCode, bytes.TrimSpace(body))
+		}
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("réponse %s illisible: %w", c.name, err)
+		}
+		return nil
+	}
+}
+
+// idString convertit un identifiant numérique optionnel (0 ou nil : vide)
+func idString(id *int64) string {
+	if id == nil || *id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
@@ -0,0 +1,227 @@
+package helpdesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Zendesk lit une instance Zendesk Support et son Help Center (authentification par jeton d'API)
+// Contacts et tickets sont lus par l'export incrémental (position : horodatage Unix), sans limite de volume
+type Zendesk struct {
+	api   apiClient
+	limit int
+}
+
+// NewZendesk crée un lecteur Zendesk ; email est l'adresse de l'agent propriétaire du jeton
+func NewZendesk(baseURL, email, apiToken string, limit int) *Zendesk {
+	return &Zendesk{
+		api: apiClient{
+			name:     "Zendesk",
+			baseURL:  strings.TrimRight(baseURL, "/"),
+			username: email + "/token",
+			password: apiToken,
+			http:     &http.Client{Timeout: requestTimeout},
+		},
+		limit: limit,
+	}
+}
+
+type zendeskUser struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Active    bool      `json:"active"`
+	Suspended bool      `json:"suspended"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type zendeskTicket struct {
+	ID                 int64      `json:"id"`
+	Subject            string     `json:"subject"`
+	Description        string     `json:"description"`
+	Status             string     `json:"status"`
+	Priority           string     `json:"priority"`
+	Type               string     `json:"type"`
+	Tags               []string   `json:"tags"`
+	RequesterID        *int64     `json:"requester_id"`
+	AssigneeID         *int64     `json:"assignee_id"`
+	CreatedAt          *time.Time `json:"created_at"`
+	UpdatedAt          *time.Time `json:"updated_at"`
+	GeneratedTimestamp int64      `json:"generated_timestamp"`
+	Via                struct {
+		Channel string `json:"channel"`
+	} `json:"via"`
+}
+
+// Contacts lit les utilisateurs (clients et agents) modifiés depuis la position
+func (z *Zendesk) Contacts(ctx context.Context, position int) (*Page[Contact], error) {
+	var resp struct {
+		Users       []zendeskUser `json:"users"`
+		EndTime     int64         `json:"end_time"`
+		EndOfStream bool          `json:"end_of_stream"`
+	}
+	if err := z.api.get(ctx, "/api/v2/incremental/users.json?start_time="+strconv.Itoa(max(position, 1)), &resp); err != nil {
+		return nil, err
+	}
+	users, next, done := incrementalPage(resp.Users, func(u zendeskUser) int64 { return u.UpdatedAt.Unix() }, position, z.limit, resp.EndTime, resp.EndOfStream)
+
+	page := &Page[Contact]{Next: next, Done: done}
+	for _, u := range users {
+		page.Items = append(page.Items, Contact{
+			ExternalID: strconv.FormatInt(u.ID, 10),
+			Name:       u.Name,
+			Email:      u.Email,
+			Phone:      u.Phone,
+			Active:     u.Active && !u.Suspended,
+		})
+	}
+	return page, nil
+}
+
+// Tickets lit les tickets modifiés depuis la position (les adresses des demandeurs et assignés sont jointes)
+func (z *Zendesk) Tickets(ctx context.Context, position int) (*Page[Ticket], error) {
+	var resp struct {
+		Tickets     []zendeskTicket `json:"tickets"`
+		Users       []zendeskUser   `json:"users"`
+		EndTime     int64           `json:"end_time"`
+		EndOfStream bool            `json:"end_of_stream"`
+	}
+	if err := z.api.get(ctx, "/api/v2/incremental/tickets.json?include=users&start_time="+strconv.Itoa(max(position, 1)), &resp); err != nil {
+		return nil, err
+	}
+	emails := make(map[int64]string, len(resp.Users))
+	for _, u := range resp.Users {
+		emails[u.ID] = u.Email
+	}
+	tickets, next, done := incrementalPage(resp.Tickets, func(t zendeskTicket) int64 { return t.GeneratedTimestamp }, position, z.limit, resp.EndTime, resp.EndOfStream)
+
+	page := &Page[Ticket]{Next: next, Done: done}
+	for _, t := range tickets {
+		// Les tickets supprimés figurent dans l'export avec le statut deleted
+		if t.Status == "deleted" {
+			continue
+		}
+		ticket := Ticket{
+			ExternalID:  strconv.FormatInt(t.ID, 10),
+			Subject:     t.Subject,
+			Description: t.Description,
+			Status:      t.Status,
+			Priority:    t.Priority,
+			Type:        t.Type,
+			Channel:     t.Via.Channel,
+			Tags:        t.Tags,
+			RequesterID: idString(t.RequesterID),
+			AssigneeID:  idString(t.AssigneeID),
+			CreatedAt:   t.CreatedAt,
+			UpdatedAt:   t.UpdatedAt,
+		}
+		if t.RequesterID != nil {
+			ticket.RequesterEmail = emails[*t.RequesterID]
+		}
+		if t.AssigneeID != nil {
+			ticket.AssigneeEmail = emails[*t.AssigneeID]
+		}
+		page.Items = append(page.Items, ticket)
+	}
+	return page, nil
+}
+
+// Comments lit les commentaires d'un ticket ; le premier, identique à la description, est ignoré
+func (z *Zendesk) Comments(ctx context.Context, ticketID string) ([]Comment, error) {
+	var comments []Comment
+	for pageNumber := 1; ; pageNumber++ {
+		var resp struct {
+			Comments []struct {
+				ID        int64      `json:"id"`
+				AuthorID  *int64     `json:"author_id"`
+				PlainBody string     `json:"plain_body"`
+				Public    bool       `json:"public"`
+				CreatedAt *time.Time `json:"created_at"`
+			} `json:"comments"`
+			NextPage *string `json:"next_page"`
+		}
+		path := fmt.Sprintf("/api/v2/tickets/%s/comments.json?per_page=100&page=%d", url.PathEscape(ticketID), pageNumber)
+		if err := z.api.get(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+		for _, c := range resp.Comments {
+			comments = append(comments, Comment{
+				ExternalID: strconv.FormatInt(c.ID, 10),
+				AuthorID:   idString(c.AuthorID),
+				Body:       c.PlainBody,
+				Public:     c.Public,
+				CreatedAt:  c.CreatedAt,
+			})
+		}
+		if resp.NextPage == nil || len(resp.Comments) == 0 {
+			break
+		}
+	}
+	if len(comments) > 0 {
+		comments = comments[1:]
+	}
+	return comments, nil
+}
+
+// Articles lit une page d'articles du Help Center (position : nombre d'articles déjà lus)
+func (z *Zendesk) Articles(ctx context.Context, position int) (*Page[Article], error) {
+	var resp struct {
+		Articles []struct {
+			ID        int64      `json:"id"`
+			Title     string     `json:"title"`
+			Body      string     `json:"body"`
+			SectionID int64      `json:"section_id"`
+			Draft     bool       `json:"draft"`
+			CreatedAt *time.Time `json:"created_at"`
+			UpdatedAt *time.Time `json:"updated_at"`
+		} `json:"articles"`
+		Sections []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"sections"`
+		Count    int     `json:"count"`
+		NextPage *string `json:"next_page"`
+	}
+	path := fmt.Sprintf("/api/v2/help_center/articles.json?include=sections&sort_by=created_at&sort_order=asc&per_page=%d&page=%d", z.limit, position/z.limit+1)
+	if err := z.api.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	sections := make(map[int64]string, len(resp.Sections))
+	for _, s := range resp.Sections {
+		sections[s.ID] = s.Name
+	}
+
+	page := &Page[Article]{Next: position + len(resp.Articles), Total: resp.Count, Done: resp.NextPage == nil}
+	for _, a := range resp.Articles {
+		page.Items = append(page.Items, Article{
+			ExternalID: strconv.FormatInt(a.ID, 10),
+			Title:      a.Title,
+			Body:       a.Body,
+			Section:    sections[a.SectionID],
+			Published:  !a.Draft,
+			CreatedAt:  a.CreatedAt,
+			UpdatedAt:  a.UpdatedAt,
+		})
+	}
+	return page, nil
+}
+
+// incrementalPage limite une page d'export incrémental (jusqu'à 1000 éléments) à limit éléments
+// La position suivante est l'horodatage du dernier élément retenu : les éléments de même horodatage
+// sont relus au lot suivant (et ignorés par l'import) ; une page entière de même horodatage est retenue
+func incrementalPage[T any](items []T, timestamp func(T) int64, position, limit int, endTime int64, endOfStream bool) ([]T, int, bool) {
+	if len(items) <= limit {
+		return items, int(endTime), endOfStream
+	}
+	next := int(timestamp(items[limit-1]))
+	if next <= position {
+		return items, int(endTime), endOfStream
+	}
+	return items[:limit], next, false
+}
@@ -0,0 +1,154 @@
+// Package i18n traduit les messages de l'API et les libellés des énumérations.
+// Le français est la langue source : les messages sont écrits en français dans le code
+// et les catalogues (locales/*.json) associent chaque message source à sa traduction.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Langues supportées
+const (
+	FR = "fr"
+	EN = "en"
+)
+
+// Default langue source des messages et langue par défaut des réponses
+const Default = FR
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalog messages et libellés d'une langue
+type catalog struct {
+	Enums    map[string]map[string]string `json:"enums"`    // Énumération -> valeur -> libellé
+	Messages map[string]string            `json:"messages"` // Message source -> traduction
+
+	patterns []pattern // Messages à paramètres (%s, %d, %v, %w...)
+}
+
+// pattern message à paramètres compilé en expression régulière
+type pattern struct {
+	re          *regexp.Regexp
+	translation string
+}
+
+// verbPattern verbes de formatage reconnus dans les messages source
+var verbPattern = regexp.MustCompile(`%[a-z]`)
+
+// catalogs catalogues chargés au démarrage, par langue
+var catalogs = map[string]*catalog{}
+
+func init() {
+	for _, lang := range []string{FR, EN} {
+		data, err := localeFiles.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: catalogue %s introuvable: %v", lang, err))
+		}
+		cat := &catalog{}
+		if err := json.Unmarshal(data, cat); err != nil {
+			panic(fmt.Sprintf("i18n: catalogue %s invalide: %v", lang, err))
+		}
+		cat.compile()
+		catalogs[lang] = cat
+	}
+}
+
+// compile prépare les messages à paramètres, les plus longs (donc les plus spécifiques) en premier
+func (cat *catalog) compile() {
+	var sources []string
+	for source := range cat.Messages {
+		if verbPattern.MatchString(source) {
+			sources = append(sources, source)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if len(sources[i]) != len(sources[j]) {
+			return len(sources[i]) > len(sources[j])
+		}
+		return sources[i] < sources[j]
+	})
+	for _, source := range sources {
+		parts := verbPattern.Split(source, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		cat.patterns = append(cat.patterns, pattern{
+			re:          regexp.MustCompile("^" + strings.Join(parts, "(.+?)") + "$"),
+			translation: cat.Messages[source],
+		})
+	}
+}
+
+// Supported indique si la langue est prise en charge
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// T traduit un message source dans la langue demandée ; les paramètres d'un message
+// à paramètres (ex: erreur encapsulée par %w) sont eux-mêmes traduits.
+// Un message absent du catalogue est retourné tel quel.
+func T(lang, message string) string {
+	cat, ok := catalogs[lang]
+	if !ok || lang == Default || message == "" {
+		return message
+	}
+	return cat.translate(message)
+}
+
+// translate cherche d'abord le message exact, puis les messages à paramètres
+func (cat *catalog) translate(message string) string {
+	if translation, ok := cat.Messages[message]; ok {
+		return translation
+	}
+	for _, p := range cat.patterns {
+		args := p.re.FindStringSubmatch(message)
+		if args == nil {
+			continue
+		}
+		i := 0
+		return verbPattern.ReplaceAllStringFunc(p.translation, func(string) string {
+			i++
+			if i >= len(args) {
+				return ""
+			}
+			return cat.translate(args[i])
+		})
+	}
+	return message
+}
+
+// Label retourne le libellé d'une valeur d'énumération (ex: ticket_status/en_cours) ;
+// à défaut le libellé français, à défaut la valeur elle-même
+func Label(lang, enum, value string) string {
+	for _, l := range []string{lang, Default} {
+		if cat, ok := catalogs[l]; ok {
+			if label, ok := cat.Enums[enum][value]; ok {
+				return label
+			}
+		}
+	}
+	return value
+}
+
+// Enums retourne tous les libellés d'énumérations dans la langue demandée
+func Enums(lang string) map[string]map[string]string {
+	if !Supported(lang) {
+		lang = Default
+	}
+	result := make(map[string]map[string]string, len(catalogs[Default].Enums))
+	for enum, values := range catalogs[Default].Enums {
+		labels := make(map[string]string, len(values))
+		for value := range values {
+			labels[value] = Label(lang, enum, value)
+		}
+		result[enum] = labels
+	}
+	return result
+}
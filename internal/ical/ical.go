@@ -0,0 +1,155 @@
+// Package ical produit des calendriers au format iCalendar (RFC 5545) pour les abonnements ICS
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event événement d'un calendrier ; un événement « journée entière » n'utilise que la date de Start (et de End)
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Categories  []string
+	Start       time.Time
+	End         time.Time // Optionnel : sans fin, l'événement dure Duration
+	Duration    time.Duration
+	AllDay      bool
+	Updated     time.Time // Dernière modification de l'élément source (DTSTAMP)
+}
+
+// Calendar calendrier publié par un flux
+type Calendar struct {
+	Name        string
+	Description string
+	// Timezone fuseau IANA du propriétaire du flux (X-WR-TIMEZONE), utilisé par les clients pour l'affichage
+	Timezone string
+	// RefreshInterval intervalle de rafraîchissement suggéré aux clients
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Bytes sérialise le calendrier (lignes CRLF repliées à 75 octets)
+func (c *Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	w := &writer{buf: &buf}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//Kronos//ITSM//FR")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	if c.Description != "" {
+		w.line("X-WR-CALDESC:" + escape(c.Description))
+	}
+	if c.Timezone != "" {
+		w.line("X-WR-TIMEZONE:" + c.Timezone)
+	}
+	if c.RefreshInterval > 0 {
+		w.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration(c.RefreshInterval))
+		w.line("X-PUBLISHED-TTL:" + duration(c.RefreshInterval))
+	}
+
+	for _, e := range c.Events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + e.UID)
+		stamp := e.Updated
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		w.line("DTSTAMP:" + utcTime(stamp))
+		if e.AllDay {
+			w.line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			end := e.End
+			if end.IsZero() || !end.After(e.Start) {
+				end = e.Start
+			}
+			// DTEND exclusive : le lendemain du dernier jour
+			w.line("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			w.line("DTSTART:" + utcTime(e.Start))
+			if !e.End.IsZero() && e.End.After(e.Start) {
+				w.line("DTEND:" + utcTime(e.End))
+			} else if e.Duration > 0 {
+				w.line("DURATION:" + duration(e.Duration))
+			}
+		}
+		w.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			w.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Location != "" {
+			w.line("LOCATION:" + escape(e.Location))
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, len(e.Categories))
+			for i, category := range e.Categories {
+				categories[i] = escape(category)
+			}
+			w.line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		w.line("TRANSP:TRANSPARENT")
+		w.line("END:VEVENT")
+	}
+	w.line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writer écrit des lignes de contenu repliées
+type writer struct {
+	buf *bytes.Buffer
+}
+
+// line écrit une ligne en la repliant à 75 octets sans couper de caractère UTF-8
+func (w *writer) line(content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		limit = 74 // L'espace de continuation compte dans la longueur
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+// escape échappe une valeur de type TEXT
+func escape(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// utcTime formate une date-heure UTC
+func utcTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// duration formate une durée positive (PT1H30M, P1D, ...)
+func duration(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return "P" + strconv.Itoa(int(d/(24*time.Hour))) + "D"
+	}
+	s := "PT"
+	if h := int(d / time.Hour); h > 0 {
+		s += strconv.Itoa(h) + "H"
+	}
+	if m := int(d%time.Hour) / int(time.Minute); m > 0 {
+		s += strconv.Itoa(m) + "M"
+	}
+	if s == "PT" {
+		s += "0M"
+	}
+	return s
+}
@@ -0,0 +1,136 @@
+// Package imaging décode, valide et redimensionne les images envoyées par les utilisateurs (avatars)
+// Seuls les formats de la bibliothèque standard sont pris en charge : JPEG, PNG et GIF (première image)
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Décodeur GIF (première image)
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Formats d'image acceptés (noms retournés par image.DecodeConfig)
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+)
+
+// MaxPixels borne la taille d'une image décodée (protection contre les "bombes" de décompression)
+const MaxPixels = 40_000_000
+
+// Erreurs retournées lors du décodage
+var (
+	ErrUnsupportedFormat = errors.New("format d'image non supporté (JPEG, PNG ou GIF)")
+	ErrTooLarge          = errors.New("dimensions de l'image trop grandes")
+)
+
+// Decode lit une image en se fiant à son contenu (et non à l'extension du fichier)
+// Les dimensions sont vérifiées avant le décodage complet
+func Decode(r io.Reader) (image.Image, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if format != FormatJPEG && format != FormatPNG && format != FormatGIF {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	return img, format, nil
+}
+
+// Square recadre l'image au centre en carré puis la redimensionne en size x size
+// La réduction moyenne les pixels source couverts par chaque pixel cible (pas d'aliasing)
+func Square(src image.Image, size int) *image.NRGBA {
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	// Conversion en NRGBA pour un accès direct aux pixels
+	in := image.NewNRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(in, in.Bounds(), src, crop.Min, draw.Src)
+
+	return resize(in, size)
+}
+
+// resize redimensionne une image carrée NRGBA en size x size
+// Chaque pixel cible est la moyenne (pondérée par l'alpha) de la zone source correspondante ;
+// l'agrandissement revient à répéter le pixel source le plus proche
+func resize(in *image.NRGBA, size int) *image.NRGBA {
+	side := in.Bounds().Dx()
+	out := image.NewNRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, size, side)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, size, side)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := in.Pix[sy*in.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					pa := uint64(p[3])
+					r += uint64(p[0]) * pa
+					g += uint64(p[1]) * pa
+					bl += uint64(p[2]) * pa
+					a += pa
+					n++
+				}
+			}
+
+			var c color.NRGBA
+			if a > 0 {
+				c = color.NRGBA{R: uint8(r / a), G: uint8(g / a), B: uint8(bl / a), A: uint8(a / n)}
+			}
+			out.SetNRGBA(x, y, c)
+		}
+	}
+	return out
+}
+
+// span retourne l'intervalle source [start, end) couvert par le pixel cible i (au moins un pixel)
+func span(i, size, side int) (int, int) {
+	start := i * side / size
+	end := (i + 1) * side / size
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+// Encode écrit l'image en JPEG si opaque, en PNG sinon (conservation de la transparence)
+// Retourne le contenu encodé, son type MIME et l'extension associée
+func Encode(img image.Image, opaque bool) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	if opaque {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 88}); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/jpeg", ".jpg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/png", ".png", nil
+}
@@ -0,0 +1,165 @@
+// Package jira appelle l'API REST de Jira (v2) pour la synchronisation des tickets de développement
+// Seules les opérations utilisées par la synchronisation sont couvertes : création, commentaire et transition
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// Catégories de statut Jira (statusCategory.key), communes à tous les workflows
+const (
+	StatusCategoryNew        = "new"
+	StatusCategoryInProgress = "indeterminate"
+	StatusCategoryDone       = "done"
+)
+
+// ErrNoTransition est retournée lorsqu'aucune transition du workflow ne mène à la catégorie demandée
+var ErrNoTransition = errors.New("aucune transition Jira disponible vers ce statut")
+
+// requestTimeout borne la durée d'un appel à l'API Jira
+const requestTimeout = 15 * time.Second
+
+// Client appelle l'API REST de Jira avec un compte de service (e-mail + jeton d'API)
+type Client struct {
+	baseURL    string
+	email      string
+	token      string
+	projectKey string
+	issueType  string
+	http       *http.Client
+}
+
+// Issue représente un ticket Jira créé
+type Issue struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// Status représente le statut courant d'un ticket Jira
+type Status struct {
+	Name        string
+	CategoryKey string
+}
+
+// NewClient crée un client à partir de la configuration
+func NewClient(cfg config.JiraConfig) *Client {
+	return &Client{
+		baseURL:    cfg.BaseURL,
+		email:      cfg.Email,
+		token:      cfg.APIToken,
+		projectKey: cfg.ProjectKey,
+		issueType:  cfg.IssueType,
+		http:       &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// IssueURL retourne l'adresse du ticket dans l'interface Jira
+func (c *Client) IssueURL(key string) string {
+	return c.baseURL + "/browse/" + url.PathEscape(key)
+}
+
+// CreateIssue crée un ticket dans le projet configuré
+func (c *Client) CreateIssue(ctx context.Context, summary, description string, labels []string) (*Issue, error) {
+	body := map[string]any{
+		"fields": map[string]any{
+			"project":     map[string]string{"key": c.projectKey},
+			"issuetype":   map[string]string{"name": c.issueType},
+			"summary":     summary,
+			"description": description,
+			"labels":      labels,
+		},
+	}
+	var issue Issue
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", body, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// AddComment ajoute un commentaire à un ticket et retourne son identifiant Jira
+func (c *Client) AddComment(ctx context.Context, issueKey, text string) (string, error) {
+	var comment struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(issueKey)+"/comment", map[string]string{"body": text}, &comment); err != nil {
+		return "", err
+	}
+	return comment.ID, nil
+}
+
+// TransitionToCategory applique la première transition disponible menant à un statut de la catégorie demandée
+// Retourne le statut atteint
+func (c *Client) TransitionToCategory(ctx context.Context, issueKey, categoryKey string) (*Status, error) {
+	var available struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				Name           string `json:"name"`
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(issueKey) + "/transitions"
+	if err := c.do(ctx, http.MethodGet, path, nil, &available); err != nil {
+		return nil, err
+	}
+	for _, transition := range available.Transitions {
+		if transition.To.StatusCategory.Key != categoryKey {
+			continue
+		}
+		body := map[string]any{"transition": map[string]string{"id": transition.ID}}
+		if err := c.do(ctx, http.MethodPost, path, body, nil); err != nil {
+			return nil, err
+		}
+		return &Status{Name: transition.To.Name, CategoryKey: categoryKey}, nil
+	}
+	return nil, ErrNoTransition
+}
+
+// do exécute un appel à l'API et décode la réponse JSON dans out (si non nil)
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.email, c.token)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("API Jira %s %s: réponse HTTP %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
@@ -0,0 +1,404 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// DefaultQueue est la file Redis utilisée pour toutes les tâches de l'application
+const DefaultQueue = "default"
+
+// Handler traite la charge utile JSON d'une tâche ; une erreur déclenche un réessai
+type Handler func(ctx context.Context, payload []byte) error
+
+// FailedJob représente une tâche en échec (en attente de réessai ou définitivement échouée)
+type FailedJob struct {
+	ID           string          `json:"id"`
+	Type         string          `json:"type"`
+	Queue        string          `json:"queue"`
+	State        string          `json:"state"` // retry (réessai planifié) ou archived (dead-letter)
+	Payload      json.RawMessage `json:"payload"`
+	Retried      int             `json:"retried"`
+	MaxRetry     int             `json:"max_retry"`
+	LastError    string          `json:"last_error"`
+	LastFailedAt time.Time       `json:"last_failed_at"`
+	NextRetryAt  *time.Time      `json:"next_retry_at,omitempty"`
+}
+
+// Stats contient les compteurs de la file
+type Stats struct {
+	Backend   string `json:"backend"` // redis ou memory
+	Pending   int    `json:"pending"`
+	Active    int    `json:"active"`
+	Scheduled int    `json:"scheduled"`
+	Retry     int    `json:"retry"`
+	Archived  int    `json:"archived"` // Tâches en dead-letter
+	Processed int    `json:"processed"`
+	Failed    int    `json:"failed"`
+}
+
+// ErrJobNotFound est retournée lorsqu'une tâche en échec est introuvable
+var ErrJobNotFound = errors.New("tâche introuvable")
+
+// Queue est la file de tâches en arrière-plan
+// Avec Redis (REDIS_ADDR), les tâches sont durables : asynq gère les réessais avec backoff et la dead-letter (archived)
+// Sans Redis, les tâches sont exécutées en mémoire avec réessais ; les échecs définitifs sont conservés jusqu'au redémarrage
+type Queue struct {
+	maxRetry    int
+	concurrency int
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	// Backend Redis
+	client    *asynq.Client
+	server    *asynq.Server
+	inspector *asynq.Inspector
+
+	// Backend mémoire
+	slots     chan struct{}
+	deadMu    sync.Mutex
+	dead      []FailedJob
+	seq       atomic.Uint64
+	processed atomic.Int64
+	failed    atomic.Int64
+}
+
+// maxMemoryDeadJobs borne le nombre de tâches en échec conservées par le backend mémoire
+const maxMemoryDeadJobs = 200
+
+// New crée la file à partir de la configuration (Redis si REDIS_ADDR est renseignée)
+func New(redisCfg config.RedisConfig, jobsCfg config.JobsConfig) *Queue {
+	q := &Queue{
+		maxRetry:    jobsCfg.MaxRetry,
+		concurrency: jobsCfg.Concurrency,
+		handlers:    make(map[string]Handler),
+	}
+	if q.concurrency <= 0 {
+		q.concurrency = 10
+	}
+	if q.maxRetry < 0 {
+		q.maxRetry = 0
+	}
+
+	if redisCfg.Addr == "" {
+		q.slots = make(chan struct{}, q.concurrency)
+		return q
+	}
+
+	redisOpt := asynq.RedisClientOpt{Addr: redisCfg.Addr, Password: redisCfg.Password, DB: redisCfg.DB}
+	q.client = asynq.NewClient(redisOpt)
+	q.inspector = asynq.NewInspector(redisOpt)
+	q.server = asynq.NewServer(redisOpt, asynq.Config{
+		Concurrency: q.concurrency,
+		Queues:      map[string]int{DefaultQueue: 1},
+		Logger:      slogAdapter{},
+		LogLevel:    asynq.WarnLevel,
+		ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
+			retried, _ := asynq.GetRetryCount(ctx)
+			maxRetry, _ := asynq.GetMaxRetry(ctx)
+			slog.Warn("job_failed", "type", task.Type(), "retried", retried, "max_retry", maxRetry, "error", err)
+		}),
+	})
+	return q
+}
+
+// Backend retourne le backend utilisé (redis ou memory)
+func (q *Queue) Backend() string {
+	if q.client != nil {
+		return "redis"
+	}
+	return "memory"
+}
+
+// Register associe un handler à un type de tâche (à appeler avant Start)
+func (q *Queue) Register(taskType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[taskType] = handler
+}
+
+// Start démarre les workers Redis (sans effet pour le backend mémoire)
+func (q *Queue) Start() error {
+	if q.server == nil {
+		return nil
+	}
+	mux := asynq.NewServeMux()
+	q.mu.RLock()
+	for taskType, handler := range q.handlers {
+		h := handler
+		mux.HandleFunc(taskType, func(ctx context.Context, task *asynq.Task) error {
+			return h(ctx, task.Payload())
+		})
+	}
+	q.mu.RUnlock()
+	return q.server.Start(mux)
+}
+
+// Shutdown arrête proprement les workers et ferme les connexions Redis
+func (q *Queue) Shutdown() {
+	if q.server != nil {
+		q.server.Shutdown()
+	}
+	if q.client != nil {
+		_ = q.client.Close()
+	}
+	if q.inspector != nil {
+		_ = q.inspector.Close()
+	}
+}
+
+// Ping vérifie la connexion à Redis (sonde de disponibilité)
+func (q *Queue) Ping() error {
+	if q.client == nil {
+		return nil
+	}
+	return q.client.Ping()
+}
+
+// Enqueue planifie une tâche ; la charge utile est sérialisée en JSON
+// Si Redis est indisponible, la tâche est exécutée en mémoire pour ne pas perdre le traitement
+func (q *Queue) Enqueue(ctx context.Context, taskType string, payload any) error {
+	if q == nil {
+		return errors.New("file de tâches non initialisée")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("sérialisation de la tâche %s: %w", taskType, err)
+	}
+
+	if q.client != nil {
+		_, err := q.client.EnqueueContext(ctx, asynq.NewTask(taskType, data), asynq.Queue(DefaultQueue), asynq.MaxRetry(q.maxRetry))
+		if err == nil {
+			return nil
+		}
+		slog.Error("job_enqueue_failed", "type", taskType, "error", err)
+	}
+
+	q.mu.RLock()
+	handler, ok := q.handlers[taskType]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("aucun handler pour la tâche %s", taskType)
+	}
+	go q.runInMemory(taskType, data, handler)
+	return nil
+}
+
+// runInMemory exécute une tâche avec réessais (backoff exponentiel) et conserve l'échec définitif
+func (q *Queue) runInMemory(taskType string, payload []byte, handler Handler) {
+	if q.slots != nil {
+		q.slots <- struct{}{}
+		defer func() { <-q.slots }()
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= q.maxRetry; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryDelay(attempt))
+		}
+		if lastErr = safeRun(handler, payload); lastErr == nil {
+			q.processed.Add(1)
+			return
+		}
+		slog.Warn("job_failed", "type", taskType, "retried", attempt, "max_retry", q.maxRetry, "error", lastErr)
+	}
+
+	q.failed.Add(1)
+	q.deadMu.Lock()
+	defer q.deadMu.Unlock()
+	q.dead = append(q.dead, FailedJob{
+		ID:           "mem-" + strconv.FormatUint(q.seq.Add(1), 10),
+		Type:         taskType,
+		Queue:        DefaultQueue,
+		State:        "archived",
+		Payload:      payload,
+		Retried:      q.maxRetry,
+		MaxRetry:     q.maxRetry,
+		LastError:    lastErr.Error(),
+		LastFailedAt: time.Now(),
+	})
+	if len(q.dead) > maxMemoryDeadJobs {
+		q.dead = q.dead[len(q.dead)-maxMemoryDeadJobs:]
+	}
+}
+
+// ListFailed liste les tâches en échec : réessais planifiés puis dead-letter, plus récentes d'abord
+func (q *Queue) ListFailed(limit int) ([]FailedJob, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	if q.inspector == nil {
+		q.deadMu.Lock()
+		defer q.deadMu.Unlock()
+		jobs := make([]FailedJob, 0, len(q.dead))
+		for i := len(q.dead) - 1; i >= 0 && len(jobs) < limit; i-- {
+			jobs = append(jobs, q.dead[i])
+		}
+		return jobs, nil
+	}
+
+	retry, err := q.inspector.ListRetryTasks(DefaultQueue, asynq.PageSize(limit))
+	if err != nil && !errors.Is(err, asynq.ErrQueueNotFound) {
+		return nil, err
+	}
+	archived, err := q.inspector.ListArchivedTasks(DefaultQueue, asynq.PageSize(limit))
+	if err != nil && !errors.Is(err, asynq.ErrQueueNotFound) {
+		return nil, err
+	}
+
+	jobs := make([]FailedJob, 0, len(retry)+len(archived))
+	for _, info := range append(retry, archived...) {
+		job := FailedJob{
+			ID:           info.ID,
+			Type:         info.Type,
+			Queue:        info.Queue,
+			State:        info.State.String(),
+			Payload:      json.RawMessage(info.Payload),
+			Retried:      info.Retried,
+			MaxRetry:     info.MaxRetry,
+			LastError:    info.LastErr,
+			LastFailedAt: info.LastFailedAt,
+		}
+		if info.State == asynq.TaskStateRetry && !info.NextProcessAt.IsZero() {
+			next := info.NextProcessAt
+			job.NextRetryAt = &next
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].LastFailedAt.After(jobs[j].LastFailedAt) })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// Retry relance immédiatement une tâche en échec
+func (q *Queue) Retry(id string) error {
+	if q.inspector != nil {
+		if err := q.inspector.RunTask(DefaultQueue, id); err != nil {
+			if errors.Is(err, asynq.ErrTaskNotFound) {
+				return ErrJobNotFound
+			}
+			return err
+		}
+		return nil
+	}
+
+	job, ok := q.removeDead(id)
+	if !ok {
+		return ErrJobNotFound
+	}
+	q.mu.RLock()
+	handler, found := q.handlers[job.Type]
+	q.mu.RUnlock()
+	if !found {
+		return fmt.Errorf("aucun handler pour la tâche %s", job.Type)
+	}
+	go q.runInMemory(job.Type, job.Payload, handler)
+	return nil
+}
+
+// Delete supprime définitivement une tâche en échec
+func (q *Queue) Delete(id string) error {
+	if q.inspector != nil {
+		if err := q.inspector.DeleteTask(DefaultQueue, id); err != nil {
+			if errors.Is(err, asynq.ErrTaskNotFound) {
+				return ErrJobNotFound
+			}
+			return err
+		}
+		return nil
+	}
+	if _, ok := q.removeDead(id); !ok {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+// Stats retourne les compteurs de la file
+func (q *Queue) Stats() (Stats, error) {
+	if q.inspector == nil {
+		q.deadMu.Lock()
+		archived := len(q.dead)
+		q.deadMu.Unlock()
+		return Stats{
+			Backend:   "memory",
+			Active:    len(q.slots),
+			Archived:  archived,
+			Processed: int(q.processed.Load()),
+			Failed:    int(q.failed.Load()),
+		}, nil
+	}
+
+	info, err := q.inspector.GetQueueInfo(DefaultQueue)
+	if err != nil {
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			return Stats{Backend: "redis"}, nil
+		}
+		return Stats{}, err
+	}
+	return Stats{
+		Backend:   "redis",
+		Pending:   info.Pending,
+		Active:    info.Active,
+		Scheduled: info.Scheduled,
+		Retry:     info.Retry,
+		Archived:  info.Archived,
+		Processed: info.ProcessedTotal,
+		Failed:    info.FailedTotal,
+	}, nil
+}
+
+// removeDead retire une tâche de la dead-letter mémoire
+func (q *Queue) removeDead(id string) (FailedJob, bool) {
+	q.deadMu.Lock()
+	defer q.deadMu.Unlock()
+	for i, job := range q.dead {
+		if job.ID == id {
+			q.dead = append(q.dead[:i], q.dead[i+1:]...)
+			return job, true
+		}
+	}
+	return FailedJob{}, false
+}
+
+// safeRun exécute un handler en convertissant une panique en erreur
+func safeRun(handler Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(context.Background(), payload)
+}
+
+// retryDelay retourne le délai avant le réessai n (2s, 4s, 8s, ... plafonné à 5 minutes)
+func retryDelay(attempt int) time.Duration {
+	delay := time.Second << attempt
+	if delay > 5*time.Minute || delay <= 0 {
+		delay = 5 * time.Minute
+	}
+	return delay
+}
+
+// slogAdapter redirige les logs internes d'asynq vers slog
+type slogAdapter struct{}
+
+func (slogAdapter) Debug(args ...interface{}) { slog.Debug(fmt.Sprint(args...), "component", "asynq") }
+func (slogAdapter) Info(args ...interface{})  { slog.Info(fmt.Sprint(args...), "component", "asynq") }
+func (slogAdapter) Warn(args ...interface{})  { slog.Warn(fmt.Sprint(args...), "component", "asynq") }
+func (slogAdapter) Error(args ...interface{}) { slog.Error(fmt.Sprint(args...), "component", "asynq") }
+func (slogAdapter) Fatal(args ...interface{}) { slog.Error(fmt.Sprint(args...), "component", "asynq") }
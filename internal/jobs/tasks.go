@@ -0,0 +1,110 @@
+package jobs
+
+import "time"
+
+// Types de tâches en arrière-plan
+const (
+	TypeNotifyUsers   = "notifications:notify_users" // Notification d'une liste d'utilisateurs
+	TypeTicketHistory = "tickets:history"            // Écriture d'une entrée d'historique de ticket
+	TypeApplySLA      = "tickets:apply_sla"          // Application du SLA correspondant à un ticket
+	TypeJiraCreate    = "jira:create_issue"          // Création du ticket Jira lié à un ticket
+	TypeJiraStatus    = "jira:sync_status"           // Report du statut d'un ticket sur le ticket Jira lié
+	TypeJiraComment   = "jira:push_comment"          // Report d'un commentaire public sur le ticket Jira lié
+	TypeImportBatch   = "imports:batch"              // Traitement du lot suivant d'un import (GLPI, ...)
+	TypeCalendarSync  = "calendar:sync"              // Synchronisation d'un agenda Google ou Microsoft connecté
+	TypeTelegramSend  = "telegram:send"              // Envoi d'une notification par le bot Telegram
+	TypeWhatsAppSend  = "whatsapp:send"              // Envoi d'une notification critique sur WhatsApp
+	TypePushSend      = "push:send"                  // Envoi d'une notification push à un appareil mobile
+	TypeEmailSend     = "email:send"                 // Envoi d'une notification par email
+)
+
+// NotifyUsersPayload charge utile de TypeNotifyUsers
+type NotifyUsersPayload struct {
+	UserIDs  []uint         `json:"user_ids"`
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	LinkURL  string         `json:"link_url"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// TicketHistoryPayload charge utile de TypeTicketHistory
+type TicketHistoryPayload struct {
+	TicketID  uint      `json:"ticket_id"`
+	UserID    uint      `json:"user_id"`
+	Action    string    `json:"action"`
+	FieldName string    `json:"field_name"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"` // Date de l'action (et non du traitement de la tâche)
+}
+
+// ApplySLAPayload charge utile de TypeApplySLA
+type ApplySLAPayload struct {
+	TicketID uint `json:"ticket_id"`
+}
+
+// JiraCreatePayload charge utile de TypeJiraCreate
+type JiraCreatePayload struct {
+	TicketID uint  `json:"ticket_id"`
+	UserID   *uint `json:"user_id,omitempty"` // Nil pour une création automatique
+}
+
+// JiraStatusPayload charge utile de TypeJiraStatus
+type JiraStatusPayload struct {
+	TicketID uint `json:"ticket_id"`
+}
+
+// JiraCommentPayload charge utile de TypeJiraComment
+type JiraCommentPayload struct {
+	CommentID uint `json:"comment_id"`
+}
+
+// ImportBatchPayload charge utile de TypeImportBatch
+type ImportBatchPayload struct {
+	RunID  uint   `json:"run_id"`
+	Stage  string `json:"stage"`  // Étape du lot (un lot dont la position ne correspond plus à l'import est ignoré)
+	Offset int    `json:"offset"` // Position du lot dans l'étape
+}
+
+// CalendarSyncPayload charge utile de TypeCalendarSync
+type CalendarSyncPayload struct {
+	ConnectionID uint `json:"connection_id"`
+}
+
+// TelegramSendPayload charge utile de TypeTelegramSend
+type TelegramSendPayload struct {
+	UserID   uint   `json:"user_id"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	TicketID uint   `json:"ticket_id,omitempty"` // Ticket concerné : des boutons d'action sont proposés
+}
+
+// WhatsAppSendPayload charge utile de TypeWhatsAppSend
+type WhatsAppSendPayload struct {
+	UserID           uint   `json:"user_id"`
+	NotificationType string `json:"notification_type"` // Détermine le modèle de message utilisé
+	Title            string `json:"title"`
+	Message          string `json:"message"`
+	TicketCode       string `json:"ticket_code,omitempty"`
+}
+
+// PushSendPayload charge utile de TypePushSend
+type PushSendPayload struct {
+	DeviceID       uint   `json:"device_id"`
+	NotificationID uint   `json:"notification_id"`
+	Type           string `json:"type"`
+	Title          string `json:"title"`
+	Message        string `json:"message"`
+	LinkURL        string `json:"link_url,omitempty"`
+}
+
+// EmailSendPayload charge utile de TypeEmailSend
+type EmailSendPayload struct {
+	UserID           uint           `json:"user_id"`
+	NotificationType string         `json:"notification_type"` // Détermine le modèle d'email utilisé
+	Title            string         `json:"title"`
+	Message          string         `json:"message"`
+	LinkURL          string         `json:"link_url,omitempty"`
+	Metadata         map[string]any `json:"metadata,omitempty"` // Variables du modèle (ticket_code, new_status, ...)
+}
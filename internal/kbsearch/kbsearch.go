@@ -0,0 +1,171 @@
+// Package kbsearch interroge les articles de la base de connaissances en plein texte (index FULLTEXT MySQL
+// ou Meilisearch) : résultats classés par pertinence, extraits surlignés et correction des fautes de frappe
+package kbsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// Balises de surlignage des termes trouvés dans les extraits (le reste du texte est échappé)
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+)
+
+// ErrQueryTooShort la requête ne contient aucun terme indexable : l'appelant se rabat sur une recherche simple
+var ErrQueryTooShort = errors.New("requête trop courte pour la recherche plein texte")
+
+// Document article tel qu'indexé (contenu en texte brut)
+type Document struct {
+	ID      uint   `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Hit résultat de recherche ; les surlignages sont vides lorsque le moteur n'en produit pas
+type Hit struct {
+	ID             uint
+	Score          float64
+	TitleHighlight string // HTML : titre échappé, termes trouvés entre <mark></mark>
+	Highlight      string // HTML : extrait du contenu autour des termes trouvés
+}
+
+// Engine moteur de recherche des articles ; Search retourne les résultats par pertinence décroissante,
+// sans filtrage de visibilité (appliqué ensuite par l'appelant)
+type Engine interface {
+	Search(ctx context.Context, query string, limit int) ([]Hit, error)
+	// Related classe les articles proches d'un texte libre (ex: ticket en cours de saisie) : chaque terme
+	// trouvé augmente le score, sans qu'aucun ne soit exigé
+	Related(ctx context.Context, text string, limit int) ([]Hit, error)
+	// Index ajoute ou remplace des documents (sans effet pour MySQL, qui interroge directement la table)
+	Index(ctx context.Context, docs []Document) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// New crée le moteur configuré par KB_SEARCH_DRIVER
+func New(cfg config.KBSearchConfig) (Engine, error) {
+	switch cfg.Driver {
+	case "", "mysql":
+		return &mysqlEngine{timeout: cfg.Timeout}, nil
+	case "meilisearch":
+		return newMeilisearchEngine(cfg), nil
+	}
+	return nil, fmt.Errorf("moteur de recherche inconnu: %s", cfg.Driver)
+}
+
+// Terms découpe une requête en termes distincts, en minuscules (lettres et chiffres uniquement)
+func Terms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), isSeparator) {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// Highlight retourne le texte échappé avec les mots commençant par l'un des termes entre <mark></mark>.
+// Si width > 0, seul un extrait d'environ width caractères autour de la première occurrence est conservé
+func Highlight(text string, terms []string, width int) string {
+	runes := []rune(text)
+	folded := make([]string, len(terms))
+	for i, term := range terms {
+		folded[i] = fold(term)
+	}
+
+	// Repérer les mots du texte qui correspondent à un terme (préfixe, sans tenir compte des accents)
+	type span struct{ start, end int }
+	var matches []span
+	for i := 0; i < len(runes); {
+		if isSeparator(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && !isSeparator(runes[j]) {
+			j++
+		}
+		word := fold(string(runes[i:j]))
+		for _, term := range folded {
+			if term != "" && strings.HasPrefix(word, term) {
+				matches = append(matches, span{i, j})
+				break
+			}
+		}
+		i = j
+	}
+
+	start, end := 0, len(runes)
+	if width > 0 && len(runes) > width {
+		if len(matches) > 0 {
+			start = max(matches[0].start-width/3, 0)
+			// Commencer l'extrait en début de mot
+			for start > 0 && !isSeparator(runes[start-1]) && start < matches[0].start {
+				start++
+			}
+		}
+		end = min(start+width, len(runes))
+		for end < len(runes) && end > start && !isSeparator(runes[end-1]) {
+			end--
+		}
+		if end <= start {
+			end = min(start+width, len(runes))
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m.end <= start || m.start >= end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		b.WriteString(markOpen)
+		b.WriteString(html.EscapeString(string(runes[m.start:min(m.end, end)])))
+		b.WriteString(markClose)
+		pos = min(m.end, end)
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// fold met un mot en minuscules sans accents (la collation MySQL ignore les accents)
+func fold(word string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if base, ok := accents[r]; ok {
+			return base
+		}
+		return r
+	}, word)
+}
+
+var accents = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y',
+}
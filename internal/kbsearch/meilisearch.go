@@ -0,0 +1,171 @@
+package kbsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// Délimiteurs de surlignage demandés à Meilisearch, remplacés par <mark></mark> après échappement du texte
+const (
+	meiliPreTag  = "\x02"
+	meiliPostTag = "\x03"
+)
+
+// meilisearchEngine délègue l'indexation et la recherche (tolérante aux fautes de frappe) à Meilisearch
+type meilisearchEngine struct {
+	baseURL string
+	apiKey  string
+	index   string
+	http    *http.Client
+
+	mu         sync.Mutex
+	configured bool // Attributs de recherche de l'index définis
+}
+
+func newMeilisearchEngine(cfg config.KBSearchConfig) *meilisearchEngine {
+	return &meilisearchEngine{
+		baseURL: cfg.MeilisearchURL,
+		apiKey:  cfg.MeilisearchKey,
+		index:   cfg.MeilisearchIndex,
+		http:    &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Search retourne les résultats avec le titre surligné et un extrait recadré autour des termes trouvés
+func (e *meilisearchEngine) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	return e.search(ctx, query, limit, "last")
+}
+
+// Related n'exige aucun terme (stratégie "frequency" : les termes les plus courants sont abandonnés en premier) ;
+// Meilisearch ne retient que les dix premiers mots, d'où le titre du ticket placé en tête par l'appelant
+func (e *meilisearchEngine) Related(ctx context.Context, text string, limit int) ([]Hit, error) {
+	return e.search(ctx, text, limit, "frequency")
+}
+
+// search interroge l'index avec la stratégie de correspondance indiquée
+func (e *meilisearchEngine) search(ctx context.Context, query string, limit int, matchingStrategy string) ([]Hit, error) {
+	if len(Terms(query)) == 0 {
+		return nil, ErrQueryTooShort
+	}
+	body := map[string]any{
+		"q":                     query,
+		"limit":                 limit,
+		"matchingStrategy":      matchingStrategy,
+		"attributesToHighlight": []string{"title", "content"},
+		"attributesToCrop":      []string{"content"},
+		"cropLength":            30,
+		"cropMarker":            "…",
+		"highlightPreTag":       meiliPreTag,
+		"highlightPostTag":      meiliPostTag,
+		"showRankingScore":      true,
+	}
+	var result struct {
+		Hits []struct {
+			ID        uint    `json:"id"`
+			Score     float64 `json:"_rankingScore"`
+			Formatted struct {
+				Title   string `json:"title"`
+				Content string `json:"content"`
+			} `json:"_formatted"`
+		} `json:"hits"`
+	}
+	if err := e.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(e.index)+"/search", body, &result); err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, len(result.Hits))
+	for i, h := range result.Hits {
+		hits[i] = Hit{
+			ID:             h.ID,
+			Score:          h.Score,
+			TitleHighlight: meiliMarkup(h.Formatted.Title),
+			Highlight:      meiliMarkup(h.Formatted.Content),
+		}
+	}
+	return hits, nil
+}
+
+// Index envoie les documents (traitement asynchrone côté Meilisearch) ; l'index est configuré au premier envoi
+func (e *meilisearchEngine) Index(ctx context.Context, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	if err := e.configure(ctx); err != nil {
+		return err
+	}
+	return e.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(e.index)+"/documents?primaryKey=id", docs, nil)
+}
+
+func (e *meilisearchEngine) Delete(ctx context.Context, id uint) error {
+	path := "/indexes/" + url.PathEscape(e.index) + "/documents/" + strconv.FormatUint(uint64(id), 10)
+	return e.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// configure limite la recherche au titre et au contenu, le titre étant prioritaire
+func (e *meilisearchEngine) configure(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.configured {
+		return nil
+	}
+	settings := map[string]any{"searchableAttributes": []string{"title", "content"}}
+	if err := e.do(ctx, http.MethodPatch, "/indexes/"+url.PathEscape(e.index)+"/settings", settings, nil); err != nil {
+		return err
+	}
+	e.configured = true
+	return nil
+}
+
+// do exécute un appel à l'API et décode la réponse JSON dans out (si non nil)
+func (e *meilisearchEngine) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("Meilisearch %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("Meilisearch %s %s: réponse HTTP %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(out)
+}
+
+// meiliMarkup échappe le texte surligné par Meilisearch puis remplace les délimiteurs par <mark></mark>
+func meiliMarkup(s string) string {
+	return strings.NewReplacer(meiliPreTag, markOpen, meiliPostTag, markClose).Replace(html.EscapeString(s))
+}
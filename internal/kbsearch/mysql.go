@@ -0,0 +1,98 @@
+package kbsearch
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mcicare/itsm-backend/database"
+)
+
+const (
+	// minTermLength longueur minimale d'un mot indexé par InnoDB (innodb_ft_min_token_size)
+	minTermLength = 3
+	// titleBoost poids d'une correspondance dans le titre par rapport au contenu
+	titleBoost = 3
+)
+
+// innodbStopwords mots vides par défaut d'InnoDB (de trois lettres ou plus) : exigés par "+", ils annuleraient la recherche
+var innodbStopwords = map[string]bool{
+	"about": true, "are": true, "com": true, "for": true, "from": true, "how": true, "that": true, "the": true, "this": true,
+	"was": true, "what": true, "when": true, "where": true, "who": true, "will": true, "with": true, "und": true, "www": true,
+}
+
+// mysqlEngine interroge les index FULLTEXT de knowledge_articles (créés par la migration)
+type mysqlEngine struct {
+	timeout time.Duration
+}
+
+// Search exige chaque terme (préfixe accepté : "imprim" trouve "imprimante") et classe par pertinence InnoDB,
+// les correspondances dans le titre pesant davantage ; les surlignages sont calculés par l'appelant
+func (e *mysqlEngine) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	var words []string
+	for _, term := range Terms(query) {
+		if utf8.RuneCountInString(term) >= minTermLength && !innodbStopwords[term] {
+			words = append(words, "+"+term+"*")
+		}
+	}
+	if len(words) == 0 {
+		return nil, ErrQueryTooShort
+	}
+	return e.query(ctx, `
+		SELECT id, MATCH(title) AGAINST(? IN BOOLEAN MODE) * ? + MATCH(title, content) AGAINST(? IN BOOLEAN MODE) AS score
+		FROM knowledge_articles
+		WHERE deleted_at IS NULL AND MATCH(title, content) AGAINST(? IN BOOLEAN MODE)
+		ORDER BY score DESC, id DESC
+		LIMIT ?
+	`, strings.Join(words, " "), limit)
+}
+
+// Related utilise le mode langage naturel d'InnoDB (pertinence selon la fréquence et la rareté des termes)
+func (e *mysqlEngine) Related(ctx context.Context, text string, limit int) ([]Hit, error) {
+	var words []string
+	for _, term := range Terms(text) {
+		if utf8.RuneCountInString(term) >= minTermLength {
+			words = append(words, term)
+		}
+	}
+	if len(words) == 0 {
+		return nil, ErrQueryTooShort
+	}
+	return e.query(ctx, `
+		SELECT id, MATCH(title) AGAINST(? IN NATURAL LANGUAGE MODE) * ? + MATCH(title, content) AGAINST(? IN NATURAL LANGUAGE MODE) AS score
+		FROM knowledge_articles
+		WHERE deleted_at IS NULL AND MATCH(title, content) AGAINST(? IN NATURAL LANGUAGE MODE)
+		ORDER BY score DESC, id DESC
+		LIMIT ?
+	`, strings.Join(words, " "), limit)
+}
+
+// query exécute une requête de classement (paramètres : texte, poids du titre, texte, texte, limite)
+func (e *mysqlEngine) query(ctx context.Context, sql, against string, limit int) ([]Hit, error) {
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	var rows []struct {
+		ID    uint
+		Score float64
+	}
+	if err := database.DB.WithContext(ctx).Raw(sql, against, titleBoost, against, against, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	hits := make([]Hit, len(rows))
+	for i, row := range rows {
+		hits[i] = Hit{ID: row.ID, Score: row.Score}
+	}
+	return hits, nil
+}
+
+func (e *mysqlEngine) Index(ctx context.Context, docs []Document) error {
+	return nil
+}
+
+func (e *mysqlEngine) Delete(ctx context.Context, id uint) error {
+	return nil
+}
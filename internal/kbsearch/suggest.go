@@ -0,0 +1,123 @@
+package kbsearch
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Vocabulary mots des articles avec leur fréquence, utilisé pour corriger les fautes de frappe des requêtes
+type Vocabulary struct {
+	counts map[string]int    // Mot sans accents -> nombre d'occurrences
+	forms  map[string]string // Mot sans accents -> graphie la plus fréquente (avec accents)
+	seen   map[string]int    // Graphie -> nombre d'occurrences
+}
+
+// NewVocabulary crée un vocabulaire vide
+func NewVocabulary() *Vocabulary {
+	return &Vocabulary{counts: map[string]int{}, forms: map[string]string{}, seen: map[string]int{}}
+}
+
+// Add ajoute les mots d'un texte (les mots de moins de trois lettres et les nombres sont ignorés)
+func (v *Vocabulary) Add(text string) {
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), isSeparator) {
+		if utf8.RuneCountInString(word) < minTermLength || strings.IndexFunc(word, isNotDigit) < 0 {
+			continue
+		}
+		key := fold(word)
+		v.counts[key]++
+		v.seen[word]++
+		if form, ok := v.forms[key]; !ok || v.seen[word] > v.seen[form] {
+			v.forms[key] = word
+		}
+	}
+}
+
+func isNotDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
+
+// Correct remplace chaque terme inconnu de la requête par le mot le plus proche du vocabulaire
+// (distance d'édition 1 jusqu'à 5 lettres, 2 au-delà ; à égalité, le mot le plus fréquent).
+// Un terme début d'un mot connu est conservé (saisie en cours). ok vaut false si aucun terme n'a été corrigé
+func (v *Vocabulary) Correct(query string) (corrected string, ok bool) {
+	terms := Terms(query)
+	for i, term := range terms {
+		key := fold(term)
+		if utf8.RuneCountInString(key) < minTermLength || v.counts[key] > 0 || v.hasPrefix(key) {
+			continue
+		}
+		if best := v.closest(key); best != "" {
+			terms[i] = v.forms[best]
+			ok = true
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	return strings.Join(terms, " "), true
+}
+
+func (v *Vocabulary) hasPrefix(prefix string) bool {
+	for word := range v.counts {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// closest retourne le mot connu le plus proche dans la distance tolérée, ou "" s'il n'y en a pas
+func (v *Vocabulary) closest(key string) string {
+	maxDistance := 1
+	if utf8.RuneCountInString(key) > 5 {
+		maxDistance = 2
+	}
+	target := []rune(key)
+	best, bestDistance := "", maxDistance+1
+	for word, count := range v.counts {
+		candidate := []rune(word)
+		if abs(len(candidate)-len(target)) > maxDistance {
+			continue
+		}
+		d := editDistance(target, candidate)
+		if d < bestDistance || (d == bestDistance && best != "" && (count > v.counts[best] || (count == v.counts[best] && word < best))) {
+			best, bestDistance = word, d
+		}
+	}
+	if bestDistance > maxDistance {
+		return ""
+	}
+	return best
+}
+
+// editDistance distance de Damerau-Levenshtein restreinte (une inversion de deux lettres compte pour une erreur)
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// level est le niveau de log courant, modifiable à chaud via SetLevel
+var level = new(slog.LevelVar)
+
+// Init configure le logger structuré global (slog) à partir du niveau et du format demandés
+// format: "json" (production) ou "text" (développement)
+// Les appels existants à log.Printf sont redirigés vers ce logger au niveau INFO
+func Init(levelName, format string) {
+	if err := SetLevel(levelName); err != nil {
+		level.Set(slog.LevelInfo)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var out io.Writer = os.Stdout
+	var handler slog.Handler
+	if strings.ToLower(format) == "json" {
+		handler = slog.NewJSONHandler(out, opts)
+	} else {
+		handler = slog.NewTextHandler(out, opts)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	// slog.SetDefault redirige le package log standard ; retirer le préfixe date (déjà fourni par slog)
+	log.SetFlags(0)
+}
+
+// SetLevel modifie le niveau de log à chaud (debug, info, warn, error)
+func SetLevel(levelName string) error {
+	var l slog.Level
+	switch strings.ToLower(strings.TrimSpace(levelName)) {
+	case "debug":
+		l = slog.LevelDebug
+	case "info", "":
+		l = slog.LevelInfo
+	case "warn", "warning":
+		l = slog.LevelWarn
+	case "error":
+		l = slog.LevelError
+	default:
+		return fmt.Errorf("niveau de log invalide: %q (attendu: debug, info, warn, error)", levelName)
+	}
+	level.Set(l)
+	return nil
+}
+
+// GetLevel retourne le niveau de log courant sous forme de chaîne
+func GetLevel() string {
+	return strings.ToLower(level.Level().String())
+}
+
+// ctxKey est la clé utilisée pour stocker le logger de requête dans un context.Context
+type ctxKey struct{}
+
+// WithLogger retourne un contexte contenant le logger fourni
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext retourne le logger associé au contexte (avec request_id, user_id...) ou le logger global
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok && l != nil {
+			return l
+		}
+	}
+	return slog.Default()
+}
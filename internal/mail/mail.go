@@ -0,0 +1,221 @@
+// Package mail envoie des emails par SMTP (corps texte et HTML)
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Modes de chiffrement de la connexion SMTP
+const (
+	TLSStartTLS = "starttls" // Connexion en clair puis STARTTLS (port 587)
+	TLSImplicit = "tls"      // Connexion chiffrée dès l'ouverture (port 465)
+	TLSNone     = "none"     // Aucun chiffrement (relais local)
+)
+
+// sendTimeout borne la durée d'un envoi (connexion comprise)
+const sendTimeout = 30 * time.Second
+
+// ErrInvalidRecipient l'adresse du destinataire est refusée : l'envoi ne doit pas être réessayé
+var ErrInvalidRecipient = errors.New("mail: destinataire invalide")
+
+// Message email à envoyer ; Text est obligatoire, HTML facultatif
+type Message struct {
+	To      string
+	ToName  string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Client client SMTP ; une connexion est ouverte par envoi
+type Client struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     mail.Address
+	tlsMode  string
+}
+
+// New crée un client SMTP ; tlsMode vaut TLSStartTLS, TLSImplicit ou TLSNone
+func New(host string, port int, username, password, from, fromName, tlsMode string) *Client {
+	return &Client{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     mail.Address{Name: fromName, Address: from},
+		tlsMode:  tlsMode,
+	}
+}
+
+// Send envoie le message ; ErrInvalidRecipient si le serveur refuse définitivement le destinataire
+func (c *Client) Send(ctx context.Context, message Message) error {
+	if _, err := mail.ParseAddress(message.To); err != nil {
+		return ErrInvalidRecipient
+	}
+	body, err := c.build(message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	client, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return fmt.Errorf("mail: authentification refusée: %w", err)
+		}
+	}
+	if err := client.Mail(c.from.Address); err != nil {
+		return fmt.Errorf("mail: expéditeur refusé: %w", err)
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) && protoErr.Code >= 550 && protoErr.Code <= 553 {
+			return ErrInvalidRecipient
+		}
+		return fmt.Errorf("mail: destinataire refusé: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("mail: envoi impossible: %w", err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		writer.Close()
+		return fmt.Errorf("mail: envoi impossible: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("mail: message refusé: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial ouvre la connexion au serveur selon le mode de chiffrement configuré
+func (c *Client) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	tlsConfig := &tls.Config{ServerName: c.host}
+
+	var conn net.Conn
+	var err error
+	if c.tlsMode == TLSImplicit {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mail: connexion au serveur SMTP impossible: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("mail: connexion au serveur SMTP impossible: %w", err)
+	}
+	if c.tlsMode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("mail: le serveur SMTP ne propose pas STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("mail: STARTTLS impossible: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// build construit le message MIME : multipart/alternative si un corps HTML est fourni
+func (c *Client) build(message Message) ([]byte, error) {
+	var buf bytes.Buffer
+	to := mail.Address{Name: message.ToName, Address: message.To}
+	headers := []string{
+		"From: " + c.from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + c.messageID(),
+		"MIME-Version: 1.0",
+	}
+
+	if message.HTML == "" {
+		headers = append(headers, "Content-Type: text/plain; charset=utf-8", "Content-Transfer-Encoding: quoted-printable")
+		buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, message.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(partWriter, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	headers = append(headers, "Content-Type: multipart/alternative; boundary="+writer.Boundary())
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
+
+// messageID génère un identifiant de message unique sur le domaine de l'expéditeur
+func (c *Client) messageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	domain := c.host
+	if at := strings.LastIndex(c.from.Address, "@"); at >= 0 {
+		domain = c.from.Address[at+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// writeQuotedPrintable écrit le contenu encodé en quoted-printable
+func writeQuotedPrintable(w io.Writer, content string) error {
+	encoder := quotedprintable.NewWriter(w)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// defaultTemplate modèle utilisé pour les types de notification sans modèle dédié
+const defaultTemplate = "default"
+
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+// TemplateData variables disponibles dans les modèles d'email
+type TemplateData struct {
+	AppName       string
+	RecipientName string
+	Title         string // Titre de la notification
+	Message       string // Message de la notification
+	LinkURL       string // Lien absolu vers le frontend (vide si non configuré)
+	TicketCode    string
+	TicketTitle   string
+	OldStatus     string // Changement de statut
+	NewStatus     string
+	TargetTime    string // Échéance SLA
+}
+
+// Templates modèles d'email par type de notification : {type}.txt définit « subject » et « body », {type}.html définit « content » (inséré dans layout.html)
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// LoadTemplates charge les modèles embarqués
+func LoadTemplates() (*Templates, error) {
+	templates := &Templates{
+		text: map[string]*texttemplate.Template{},
+		html: map[string]*htmltemplate.Template{},
+	}
+	layout, err := templateFS.ReadFile("templates/layout.html")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".txt")
+		if !ok {
+			continue
+		}
+		text, err := texttemplate.ParseFS(templateFS, "templates/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("mail: modèle %s invalide: %w", entry.Name(), err)
+		}
+		templates.text[name] = text
+
+		content, err := templateFS.ReadFile("templates/" + name + ".html")
+		if err != nil {
+			continue // Email texte seul
+		}
+		html, err := htmltemplate.New(name).Parse(string(layout))
+		if err == nil {
+			_, err = html.Parse(string(content))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mail: modèle %s.html invalide: %w", name, err)
+		}
+		templates.html[name] = html
+	}
+	if templates.text[defaultTemplate] == nil {
+		return nil, fmt.Errorf("mail: modèle %s.txt manquant", defaultTemplate)
+	}
+	return templates, nil
+}
+
+// Render produit le sujet et les corps de l'email d'un type de notification (modèle par défaut si le type n'en a pas)
+func (t *Templates) Render(notificationType string, data TemplateData) (subject, text, html string, err error) {
+	name := notificationType
+	if t.text[name] == nil {
+		name = defaultTemplate
+	}
+
+	var buf bytes.Buffer
+	if err := t.text[name].ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", "", fmt.Errorf("mail: rendu du sujet %s: %w", name, err)
+	}
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := t.text[name].ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", "", fmt.Errorf("mail: rendu du modèle %s.txt: %w", name, err)
+	}
+	text = strings.TrimSpace(buf.String()) + "\n"
+
+	if tmpl := t.html[name]; tmpl != nil {
+		buf.Reset()
+		if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+			return "", "", "", fmt.Errorf("mail: rendu du modèle %s.html: %w", name, err)
+		}
+		html = buf.String()
+	}
+	return subject, text, html, nil
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// APIVersionHeader est l'en-tête indiquant la version de l'API ayant servi la réponse
+const APIVersionHeader = "X-API-Version"
+
+// V2Envelope est l'enveloppe des réponses de succès de l'API v2
+type V2Envelope struct {
+	Data any    `json:"data"`
+	Meta V2Meta `json:"meta"`
+}
+
+// V2Meta contient les métadonnées d'une réponse v2
+type V2Meta struct {
+	Message    string            `json:"message,omitempty"`
+	Pagination *utils.Pagination `json:"pagination,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
+}
+
+// ProblemDetails est une erreur au format RFC 7807 (application/problem+json)
+type ProblemDetails struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`                 // Code machine (ex: permission_denied)
+	Errors    any    `json:"errors,omitempty"`     // Détails de l'erreur d'origine (validation, ...)
+	RequestID string `json:"request_id,omitempty"` // Identifiant de requête pour le support
+}
+
+// v1Response reprend les champs des enveloppes v1 (utils.Response et utils.PaginatedResponse)
+type v1Response struct {
+	Success    *bool             `json:"success"`
+	Message    string            `json:"message"`
+	Data       json.RawMessage   `json:"data"`
+	Error      json.RawMessage   `json:"error"`
+	Pagination *utils.Pagination `json:"pagination"`
+}
+
+// v2Writer met en tampon les réponses JSON des handlers v1 pour les convertir au format v2
+type v2Writer struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	decided     bool
+	passthrough bool
+}
+
+// Write met en tampon les réponses JSON et transmet les autres directement (fichiers, exports)
+func (w *v2Writer) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.passthrough = !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+// WriteString redirige vers Write
+func (w *v2Writer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// APIV2Middleware expose les handlers v1 sous /api/v2 avec une enveloppe cohérente :
+//   - succès : {"data": ..., "meta": {"message", "pagination", "request_id"}}
+//   - erreur : problem details RFC 7807 avec un code machine (utils.ErrCode*)
+//
+// Les handlers restent inchangés ; la v1 continue de fonctionner pendant la migration des clients
+func APIV2Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set(APIVersionHeader, "2")
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &v2Writer{ResponseWriter: original}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.passthrough || !writer.decided {
+			return
+		}
+
+		body := writer.buf.Bytes()
+		var v1 v1Response
+		if err := json.Unmarshal(body, &v1); err != nil || v1.Success == nil {
+			// Réponse JSON hors enveloppe v1 : transmise telle quelle
+			_, _ = original.Write(body)
+			return
+		}
+
+		status := original.Status()
+		requestID := c.GetString("request_id")
+		var out any
+		if *v1.Success && status < http.StatusBadRequest {
+			out = V2Envelope{
+				Data: rawOrNull(v1.Data),
+				Meta: V2Meta{Message: v1.Message, Pagination: v1.Pagination, RequestID: requestID},
+			}
+		} else {
+			code := utils.ErrorCodeFromContext(c, status, v1.Message)
+			problem := ProblemDetails{
+				Type:      "urn:itsm:problem:" + code,
+				Title:     http.StatusText(status),
+				Status:    status,
+				Detail:    v1.Message,
+				Instance:  c.Request.URL.Path,
+				Code:      code,
+				RequestID: requestID,
+			}
+			if len(v1.Error) > 0 && string(v1.Error) != "null" {
+				problem.Errors = v1.Error
+			}
+			out = problem
+			original.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+		}
+
+		encoded, err := json.Marshal(out)
+		if err != nil {
+			_, _ = original.Write(body)
+			return
+		}
+		original.Header().Del("Content-Length")
+		_, _ = original.Write(encoded)
+	}
+}
+
+// rawOrNull retourne la valeur JSON brute ou null si absente
+func rawOrNull(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return raw
+}
@@ -1,33 +1,81 @@
 package middleware
 
 import (
+	"bytes"
+	"io"
 	"log"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/audit"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
 )
 
+// maxAuditBodySize taille maximale d'un corps de requête JSON conservé dans le journal d'audit
+const maxAuditBodySize = 64 << 10
+
+// auditSnapshotTables tables dont la ligne est photographiée avant/après modification, par type d'entité
+var auditSnapshotTables = map[string]string{
+	"ticket":                "tickets",
+	"user":                  "users",
+	"asset":                 "assets",
+	"incident":              "incidents",
+	"change":                "changes",
+	"service_request":       "service_requests",
+	"knowledge":             "knowledge_articles",
+	"time_entry":            "time_entries",
+	"projects":              "projects",
+	"filiales":              "filiales",
+	"departments":           "departments",
+	"offices":               "offices",
+	"roles":                 "roles",
+	"sla":                   "sla",
+	"software":              "software",
+	"software-releases":     "software_releases",
+	"software-environments": "software_environments",
+	"support-contracts":     "support_contracts",
+	"ticket-internes":       "ticket_internes",
+	"delays":                "delays",
+	"daily-declarations":    "daily_declarations",
+	"weekly-declarations":   "weekly_declarations",
+	"delegations":           "access_delegations",
+	"shares":                "record_shares",
+	"sources":               "request_sources",
+	"skills":                "skills",
+	"webhooks":              "webhook_subscriptions",
+}
+
 // AuditLogMiddleware enregistre un log d'audit pour les requêtes mutantes
+// Les modifications d'une entité connue sont journalisées avec les valeurs avant/après des champs modifiés,
+// les créations avec le corps JSON de la requête ; les champs sensibles sont masqués
 func AuditLogMiddleware(auditLogRepo repositories.AuditLogRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		method := c.Request.Method
+		mutating := method != "GET" && method != "HEAD" && method != "OPTIONS" && !strings.Contains(c.Request.URL.Path, "/audit-logs")
+
+		var body []byte
+		var before map[string]interface{}
+		table, snapshotID := "", uint(0)
+		if mutating {
+			body = readAuditBody(c)
+			if method != "POST" {
+				table, snapshotID = snapshotTarget(c.Request.URL.Path)
+				if table != "" {
+					before, _ = auditLogRepo.FindSnapshot(table, snapshotID)
+				}
+			}
+		}
+
 		c.Next()
 
 		// Ne logger que les opérations de modification réussies
-		method := c.Request.Method
-		if method == "GET" || method == "HEAD" || method == "OPTIONS" {
-			return
-		}
-		if c.Writer.Status() >= 400 {
+		if !mutating || c.Writer.Status() >= 400 {
 			return
 		}
 
 		path := c.Request.URL.Path
-		if strings.Contains(path, "/audit-logs") {
-			return
-		}
 
 		action := resolveAction(method, path)
 		entityType, entityID := resolveEntity(path)
@@ -39,14 +87,37 @@ func AuditLogMiddleware(auditLogRepo repositories.AuditLogRepository) gin.Handle
 			}
 		}
 
+		// Action faite pour le compte d'un autre utilisateur (délégation d'absence) : tracer les deux identités
+		var onBehalfOfID *uint
+		if id, exists := c.Get("on_behalf_of_id"); exists {
+			if uid, ok := id.(uint); ok {
+				onBehalfOfID = &uid
+			}
+		}
+
 		auditLog := &models.AuditLog{
-			UserID:      userID,
-			Action:      action,
-			EntityType:  entityType,
-			EntityID:    entityID,
-			IPAddress:   c.ClientIP(),
-			UserAgent:   c.GetHeader("User-Agent"),
-			Description: method + " " + path,
+			UserID:       userID,
+			OnBehalfOfID: onBehalfOfID,
+			Action:       action,
+			EntityType:   entityType,
+			EntityID:     entityID,
+			IPAddress:    c.ClientIP(),
+			UserAgent:    c.GetHeader("User-Agent"),
+			Description:  method + " " + path,
+		}
+
+		// Valeurs avant/après : différences de la ligne modifiée, sinon corps de la requête
+		switch {
+		case before != nil && method == "DELETE":
+			auditLog.OldValues = audit.Encode(audit.Mask(before))
+		case before != nil:
+			if after, err := auditLogRepo.FindSnapshot(table, snapshotID); err == nil {
+				oldValues, newValues := audit.Diff(before, after)
+				auditLog.OldValues = audit.Encode(oldValues)
+				auditLog.NewValues = audit.Encode(newValues)
+			}
+		default:
+			auditLog.NewValues = audit.MaskJSON(body)
 		}
 
 		if err := auditLogRepo.Create(auditLog); err != nil {
@@ -55,6 +126,39 @@ func AuditLogMiddleware(auditLogRepo repositories.AuditLogRepository) gin.Handle
 	}
 }
 
+// readAuditBody lit le corps JSON de la requête (remis en place pour le handler) ; vide s'il est trop volumineux
+func readAuditBody(c *gin.Context) []byte {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || len(body) > maxAuditBodySize {
+		return nil
+	}
+	return body
+}
+
+// snapshotTarget retourne la table et l'ID de l'entité modifiée pour /<entité>/<id>[/<action>]
+func snapshotTarget(path string) (string, uint) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 2 && segments[0] == "api" && strings.HasPrefix(segments[1], "v") {
+		segments = segments[2:]
+	}
+	if len(segments) < 2 || len(segments) > 3 {
+		return "", 0
+	}
+	table, ok := auditSnapshotTables[normalizeEntity(segments[0])]
+	if !ok {
+		return "", 0
+	}
+	id, err := strconv.ParseUint(segments[1], 10, 32)
+	if err != nil {
+		return "", 0
+	}
+	return table, uint(id)
+}
+
 func resolveAction(method, path string) string {
 	lowerPath := strings.ToLower(path)
 	switch {
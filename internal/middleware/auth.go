@@ -5,19 +5,28 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/repositories"
-	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/timezone"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // AuthMiddleware vérifie la présence et la validité du token JWT
 // Si le token est valide, les informations de l'utilisateur sont stockées dans le contexte
 // Les handlers peuvent ensuite accéder à ces informations via c.Get("user_id"), etc.
-// Le middleware enrichit également le contexte avec un QueryScope pour le filtrage automatique des données
+// Le middleware enrichit également le contexte avec un QueryScope pour le filtrage automatique des données (voir buildRequestScope)
 func AuthMiddleware() gin.HandlerFunc {
 	// Créer le repository une seule fois (singleton)
 	userRepo := repositories.NewUserRepository()
+	delegationRepo := repositories.NewAccessDelegationRepository()
+	preferenceRepo := repositories.NewUserPreferenceRepository()
 
 	return func(c *gin.Context) {
+		// Déjà authentifié plus haut dans la chaîne (middleware global puis middleware du groupe) :
+		// le scope n'est construit qu'une fois par requête
+		if _, authenticated := c.Get("scope"); authenticated {
+			c.Next()
+			return
+		}
+
 		// Récupérer le header Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -48,7 +57,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		// Récupérer l'utilisateur complet avec ses relations (rôle, département)
 		// pour construire le QueryScope
-		user, err := userRepo.FindByID(claims.UserID)
+		user, err := userRepo.FindByIDCached(claims.UserID)
 		if err != nil {
 			utils.UnauthorizedResponse(c, "Utilisateur introuvable")
 			c.Abort()
@@ -62,8 +71,12 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Créer le QueryScope avec les permissions et attributs de l'utilisateur
-		queryScope := scope.NewQueryScopeFromUser(user)
+		// Créer le QueryScope de la requête (permissions, délégation, périmètre du tableau de bord)
+		queryScope := buildRequestScope(c, user, userRepo, delegationRepo)
+		if queryScope == nil {
+			c.Abort()
+			return
+		}
 
 		// Stocker les informations de l'utilisateur dans le contexte Gin
 		// On utilise user.Username (DB) et non claims.Username (JWT) pour avoir la valeur à jour
@@ -71,7 +84,23 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", user.Username)
 		c.Set("role", claims.Role)
+		// Langue choisie dans les préférences (prioritaire sur Accept-Language)
+		// Fuseau horaire : préférence de l'utilisateur, sinon fuseau de sa filiale, sinon fuseau de l'application
+		preferredTimezone := ""
+		if preference, err := preferenceRepo.FindByUserIDCached(user.ID); err == nil && preference != nil {
+			if preference.Language != "" {
+				c.Set(utils.LanguageContextKey, preference.Language)
+			}
+			preferredTimezone = preference.Timezone
+		}
+		filialeTimezone := ""
+		if user.Filiale != nil {
+			filialeTimezone = user.Filiale.Timezone
+		}
+		queryScope.Location = timezone.Resolve(preferredTimezone, filialeTimezone)
+		c.Set(utils.LocationContextKey, queryScope.Location)
 		c.Set("scope", queryScope) // Ajouter le QueryScope au contexte
+		withUserLogger(c, claims.UserID)
 
 		// Continuer avec la requête
 		c.Next()
@@ -1,30 +1,57 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
 )
 
+// corsAllowedHeaders liste les en-têtes autorisés dans les requêtes cross-origin
+const corsAllowedHeaders = "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, If-None-Match, X-On-Behalf-Of"
+
+// corsExposedHeaders liste les en-têtes de réponse lisibles par le navigateur
+const corsExposedHeaders = "X-Request-ID, Retry-After, Content-Disposition, ETag, X-API-Version"
+
 // CORSMiddleware configure les en-têtes CORS pour permettre les requêtes cross-origin
 // CORS (Cross-Origin Resource Sharing) permet à un navigateur d'autoriser
 // les requêtes HTTP depuis une origine différente (domaine, port, protocole)
+// Les origines autorisées proviennent de la configuration (CORS_ALLOWED_ORIGINS), relue à chaque requête
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Autoriser toutes les origines (en production, spécifier les domaines autorisés)
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-
-		// Autoriser l'envoi de cookies et credentials
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		cfg := corsConfig()
+		origin := c.GetHeader("Origin")
 
-		// En-têtes autorisés dans les requêtes
-		c.Writer.Header().Set("Access-Control-Allow-Headers",
-			"Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		// Les clients sans en-tête Origin (application mobile, appels serveur à serveur) ne sont pas concernés par CORS
+		if origin != "" {
+			if !isOriginAllowed(origin, cfg.AllowedOrigins) {
+				if c.Request.Method == http.MethodOptions {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+				c.Next()
+				return
+			}
 
-		// Méthodes HTTP autorisées
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+			// On renvoie l'origine exacte (et non "*") pour rester compatible avec l'envoi de credentials
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Add("Vary", "Origin")
+			if cfg.AllowCredentials {
+				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			c.Writer.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+			c.Writer.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		}
 
 		// Si c'est une requête OPTIONS (préflight), répondre immédiatement avec 204 No Content
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if c.Request.Method == http.MethodOptions {
+			if cfg.MaxAge > 0 {
+				c.Writer.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
@@ -32,3 +59,29 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// corsConfig retourne la configuration CORS active (rechargeable à chaud)
+func corsConfig() config.CORSConfig {
+	if cfg := config.Current(); cfg != nil {
+		return cfg.CORS
+	}
+	return config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+}
+
+// isOriginAllowed vérifie si l'origine correspond à l'une des origines autorisées
+// Supporte "*" (toutes) et les jokers de sous-domaine ("https://*.mcicare.ci")
+func isOriginAllowed(origin string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+		if idx := strings.Index(pattern, "*."); idx >= 0 {
+			prefix := pattern[:idx]
+			suffix := pattern[idx+1:]
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) && len(origin) > len(prefix)+len(suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
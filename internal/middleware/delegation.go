@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/logger"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// OnBehalfOfHeader est l'en-tête par lequel un délégataire agit pour le compte d'un collègue absent (ID du délégant)
+const OnBehalfOfHeader = "X-On-Behalf-Of"
+
+// delegationRoutes liste, par domaine délégué, les routes accessibles pour le compte du délégant
+// Format "MÉTHODE /chemin" (chemin de route gin sans préfixe de version) ; un suffixe * couvre toutes les routes du préfixe
+var delegationRoutes = map[string][]string{
+	models.DelegationAreaTimesheet: {
+		"GET /timesheet/entries/pending-validation",
+		"GET /timesheet/entries/:id",
+		"POST /timesheet/entries/:id/validate",
+		"GET /timesheet/weekly/*",
+		"POST /timesheet/weekly/:week/validate",
+		"GET /timesheet/validation-history",
+		"GET /time-entries*",
+		"POST /time-entries/:id/validate",
+		"GET /daily-declarations*",
+		"POST /daily-declarations/:id/validate",
+		"GET /weekly-declarations*",
+		"POST /weekly-declarations/:id/validate",
+		"POST /weekly-declarations/:id/approve",
+		"POST /weekly-declarations/:id/reject",
+		"GET /delays*",
+		"POST /delays/justifications/:id/validate",
+		"POST /delays/:id/justification/reject",
+		"GET /absences/pending",
+		"POST /absences/:id/approve",
+		"POST /absences/:id/reject",
+	},
+	models.DelegationAreaTickets: {
+		"GET /tickets*",
+		"POST /tickets/:id/validate",
+		"GET /ticket-internes*",
+		"POST /ticket-internes/:id/validate",
+		"GET /service-requests*",
+		"POST /service-requests/:id/validate",
+	},
+	models.DelegationAreaProjects: {
+		"GET /projects*",
+		"POST /projects/:id/budget-extensions",
+		"PUT /projects/:id/budget-extensions/:extId",
+		"DELETE /projects/:id/budget-extensions/:extId",
+		"POST /projects/:id/time-budget",
+	},
+}
+
+// unversionedPath retire le préfixe /api/vN d'un chemin de route
+func unversionedPath(fullPath string) string {
+	if rest, ok := strings.CutPrefix(fullPath, "/api/"); ok {
+		if idx := strings.IndexByte(rest, '/'); idx >= 0 {
+			return rest[idx:]
+		}
+	}
+	return fullPath
+}
+
+// delegationAllowsRoute indique si la route appelée relève d'un domaine couvert par la délégation
+func delegationAllowsRoute(delegation *models.AccessDelegation, method, fullPath string) bool {
+	route := method + " " + unversionedPath(fullPath)
+	for _, area := range delegation.AreaList() {
+		for _, pattern := range delegationRoutes[area] {
+			if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+				if strings.HasPrefix(route, prefix) {
+					return true
+				}
+			} else if route == pattern {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyDelegation résout l'en-tête X-On-Behalf-Of : vérifie la délégation en vigueur et la route,
+// puis retourne le scope du délégant (visibilité et permissions du collègue absent)
+// L'identité réelle reste dans user_id ; le délégant est placé dans on_behalf_of_id pour l'audit
+// Retourne nil si la requête a été rejetée (réponse déjà envoyée)
+func applyDelegation(c *gin.Context, delegationRepo repositories.AccessDelegationRepository, userRepo repositories.UserRepository, delegateID uint, header string) *scope.QueryScope {
+	delegatorID, err := strconv.ParseUint(header, 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "En-tête "+OnBehalfOfHeader+" invalide")
+		return nil
+	}
+
+	delegation, err := delegationRepo.FindActive(uint(delegatorID), delegateID, time.Now())
+	if err != nil {
+		utils.ForbiddenResponse(c, "Aucune délégation en cours de cet utilisateur")
+		return nil
+	}
+	if !delegationAllowsRoute(delegation, c.Request.Method, c.FullPath()) {
+		utils.ForbiddenResponse(c, "Action non couverte par la délégation")
+		return nil
+	}
+
+	delegator, err := userRepo.FindByIDCached(delegation.DelegatorID)
+	if err != nil || !delegator.IsActive {
+		utils.ForbiddenResponse(c, "Délégant introuvable ou désactivé")
+		return nil
+	}
+	delegatorScope, err := scope.NewQueryScopeFromUser(delegator)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("permissions_unavailable", "user_id", delegator.ID, "role", delegator.Role.Name, "error", err)
+		c.Header("Retry-After", "5")
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Permissions temporairement indisponibles, veuillez réessayer", nil)
+		return nil
+	}
+
+	c.Set("on_behalf_of_id", delegation.DelegatorID)
+	c.Set("delegation_id", delegation.ID)
+	reqLogger := logger.FromContext(c.Request.Context()).With(slog.Uint64("on_behalf_of", uint64(delegation.DelegatorID)))
+	c.Request = c.Request.WithContext(logger.WithLogger(c.Request.Context(), reqLogger))
+	return delegatorScope
+}
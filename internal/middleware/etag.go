@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etagWriter met en mémoire tampon les réponses JSON afin de calculer leur ETag
+// Les autres réponses (fichiers, images) sont transmises directement
+type etagWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	decided     bool
+	passthrough bool
+}
+
+// Write décide au premier appel si la réponse doit être mise en tampon
+func (w *etagWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		contentType := w.Header().Get("Content-Type")
+		w.passthrough = w.Status() != http.StatusOK || !strings.HasPrefix(contentType, "application/json")
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+// WriteString redirige vers Write
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// ETagMiddleware ajoute un en-tête ETag (empreinte du contenu) aux réponses JSON des requêtes GET
+// et répond 304 Not Modified lorsque l'en-tête If-None-Match correspond
+// Permet aux clients qui interrogent régulièrement l'API (polling) d'éviter de retélécharger des données inchangées
+// L'empreinte porte sur le corps complet, qui inclut les dates de mise à jour (updated_at) des ressources
+func ETagMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &etagWriter{ResponseWriter: original}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.passthrough || !writer.decided {
+			return
+		}
+
+		body := writer.buf.Bytes()
+		sum := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		original.Header().Set("ETag", etag)
+		original.Header().Set("Cache-Control", "private, no-cache")
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			original.Header().Del("Content-Length")
+			original.Header().Del("Content-Type")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		_, _ = original.Write(body)
+	}
+}
+
+// etagMatches vérifie si l'ETag figure dans la valeur de If-None-Match (liste, "*" ou ETag faible W/)
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
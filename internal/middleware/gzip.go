@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipMinSize taille minimale (en octets) en dessous de laquelle la compression n'est pas rentable
+const gzipMinSize = 1024
+
+// gzipWriterPool réutilise les compresseurs gzip entre les requêtes
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// compressibleTypes liste les types de contenu compressés (les images et archives le sont déjà)
+var compressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"text/",
+}
+
+// gzipWriter compresse la réponse à la volée si son type et sa taille s'y prêtent
+type gzipWriter struct {
+	gin.ResponseWriter
+	gz      *gzip.Writer
+	buf     []byte
+	decided bool
+}
+
+// Write met en tampon les premiers octets pour décider de la compression
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	if !w.shouldCompress() {
+		w.decided = true
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= gzipMinSize {
+		w.startCompression()
+	}
+	return len(data), nil
+}
+
+// WriteString redirige vers Write
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// shouldCompress vérifie le statut, l'encodage existant et le type de contenu
+func (w *gzipWriter) shouldCompress() bool {
+	h := w.Header()
+	if w.Status() < 200 || w.Status() == http.StatusNoContent || w.Status() == http.StatusNotModified || w.Status() == http.StatusPartialContent {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := h.Get("Content-Type")
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// startCompression envoie les en-têtes de compression et le contenu déjà mis en tampon
+func (w *gzipWriter) startCompression() {
+	w.decided = true
+	h := w.Header()
+	h.Set("Content-Encoding", "gzip")
+	h.Add("Vary", "Accept-Encoding")
+	h.Del("Content-Length")
+
+	gz := gzipWriterPool.Get().(*gzip.Writer)
+	gz.Reset(w.ResponseWriter)
+	w.gz = gz
+	if len(w.buf) > 0 {
+		_, _ = gz.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+// finish termine la réponse : vide le tampon non compressé ou ferme le flux gzip
+func (w *gzipWriter) finish() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+		gzipWriterPool.Put(w.gz)
+		w.gz = nil
+		return
+	}
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+// GzipMiddleware compresse les réponses textuelles (JSON, CSV, ...) lorsque le client accepte gzip
+// Les petites réponses (< 1 Ko), les fichiers binaires et la connexion WebSocket ne sont pas compressés
+func GzipMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") ||
+			strings.EqualFold(c.GetHeader("Upgrade"), "websocket") ||
+			c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &gzipWriter{ResponseWriter: original}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = original
+		}()
+
+		c.Next()
+	}
+}
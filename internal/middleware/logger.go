@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/logger"
+)
+
+// RequestLoggerMiddleware journalise chaque requête HTTP avec des champs structurés
+// (request_id, méthode, route, statut, latence, utilisateur, IP)
+// Remplace le logger texte par défaut de Gin
+func RequestLoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", route,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		}
+		if userID, exists := c.Get("user_id"); exists {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		reqLogger := logger.FromContext(c.Request.Context())
+		status := c.Writer.Status()
+		switch {
+		case status >= 500:
+			reqLogger.Error("http_request", attrs...)
+		case status >= 400:
+			reqLogger.Warn("http_request", attrs...)
+		default:
+			reqLogger.Info("http_request", attrs...)
+		}
+	}
+}
+
+// withUserLogger enrichit le logger de la requête avec l'identifiant de l'utilisateur authentifié
+func withUserLogger(c *gin.Context, userID uint) {
+	reqLogger := logger.FromContext(c.Request.Context()).With(slog.Uint64("user_id", uint64(userID)))
+	c.Request = c.Request.WithContext(logger.WithLogger(c.Request.Context(), reqLogger))
+}
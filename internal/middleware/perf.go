@@ -1,10 +1,10 @@
 package middleware
 
 import (
-	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/logger"
 )
 
 // PerfMiddleware logge les requêtes lentes pour aider au diagnostic
@@ -15,7 +15,12 @@ func PerfMiddleware() gin.HandlerFunc {
 
 		duration := time.Since(start)
 		if duration >= 500*time.Millisecond {
-			log.Printf("SLOW REQ %s %s status=%d dur=%s", c.Request.Method, c.Request.URL.Path, c.Writer.Status(), duration)
+			logger.FromContext(c.Request.Context()).Warn("slow_request",
+				"method", c.Request.Method,
+				"route", c.FullPath(),
+				"status", c.Writer.Status(),
+				"latency_ms", duration.Milliseconds(),
+			)
 		}
 	}
 }
@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/logger"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// tokenBucket représente le seau de jetons d'un client (IP ou utilisateur)
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter limite le débit par clé selon l'algorithme du token bucket (stockage en mémoire)
+// Chaque clé dispose de "burst" jetons, rechargés au rythme de "rate" jetons par seconde
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	rate    float64
+	burst   float64
+	idleTTL time.Duration
+}
+
+// NewRateLimiter crée un limiteur autorisant perMinute requêtes par minute avec une rafale de burst requêtes
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	if perMinute <= 0 {
+		perMinute = 60
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	rl := &RateLimiter{
+		buckets: make(map[string]*tokenBucket),
+		rate:    float64(perMinute) / 60.0,
+		burst:   float64(burst),
+		idleTTL: 10 * time.Minute,
+	}
+	go rl.cleanupLoop()
+	return rl
+}
+
+// Allow consomme un jeton pour la clé donnée
+// Retourne false et le délai avant le prochain jeton disponible si la limite est atteinte
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: rl.burst, lastSeen: now}
+		rl.buckets[key] = b
+	} else {
+		b.tokens = math.Min(rl.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*rl.rate)
+		b.lastSeen = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+	return false, wait
+}
+
+// SetLimits modifie le débit et la rafale (rechargement de la configuration)
+func (rl *RateLimiter) SetLimits(perMinute, burst int) {
+	if perMinute <= 0 || burst <= 0 {
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.rate = float64(perMinute) / 60.0
+	rl.burst = float64(burst)
+}
+
+// cleanupLoop supprime périodiquement les seaux inactifs pour borner la mémoire
+func (rl *RateLimiter) cleanupLoop() {
+	ticker := time.NewTicker(rl.idleTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		cutoff := time.Now().Add(-rl.idleTTL)
+		rl.mu.Lock()
+		for key, b := range rl.buckets {
+			if b.lastSeen.Before(cutoff) {
+				delete(rl.buckets, key)
+			}
+		}
+		rl.mu.Unlock()
+	}
+}
+
+// Limiteurs partagés entre les versions de l'API (/api/v1 et /api/v2 consomment les mêmes seaux)
+var (
+	authLimiterOnce sync.Once
+	authLimiter     *RateLimiter
+	userLimiterOnce sync.Once
+	userLimiter     *RateLimiter
+)
+
+// Limiteur du portail public de la base de connaissances, indépendant de RATE_LIMIT_ENABLED
+var (
+	kbPublicLimiterOnce sync.Once
+	kbPublicLimiter     *RateLimiter
+)
+
+// AuthRateLimitMiddleware limite les requêtes par adresse IP sur les routes d'authentification publiques
+// (connexion, inscription, rafraîchissement du token) pour freiner les attaques par force brute
+// L'IP est celle de la connexion, sauf derrière un proxy déclaré dans TRUSTED_PROXIES (voir ServerConfig)
+func AuthRateLimitMiddleware() gin.HandlerFunc {
+	if config.Current() == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	authLimiterOnce.Do(func() {
+		cfg := rateLimitConfig()
+		authLimiter = NewRateLimiter(cfg.AuthPerMinute, cfg.AuthBurst)
+		config.OnReload(func(cfg *config.Config) {
+			authLimiter.SetLimits(cfg.RateLimit.AuthPerMinute, cfg.RateLimit.AuthBurst)
+		})
+	})
+	limiter := authLimiter
+
+	return func(c *gin.Context) {
+		if !rateLimitConfig().Enabled {
+			c.Next()
+			return
+		}
+		if ok, wait := limiter.Allow("ip:" + c.ClientIP()); !ok {
+			rejectRateLimited(c, wait)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitMiddleware limite les requêtes par utilisateur authentifié
+// Doit être placé après AuthMiddleware ; à défaut d'utilisateur, l'IP est utilisée comme clé
+// Les comptes de service listés dans RATE_LIMIT_EXEMPT_USERS ne sont pas limités
+func RateLimitMiddleware() gin.HandlerFunc {
+	if config.Current() == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	userLimiterOnce.Do(func() {
+		cfg := rateLimitConfig()
+		userLimiter = NewRateLimiter(cfg.UserPerMinute, cfg.UserBurst)
+		config.OnReload(func(cfg *config.Config) {
+			userLimiter.SetLimits(cfg.RateLimit.UserPerMinute, cfg.RateLimit.UserBurst)
+		})
+	})
+	limiter := userLimiter
+
+	return func(c *gin.Context) {
+		cfg := rateLimitConfig()
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+		key := "ip:" + c.ClientIP()
+		if userID, ok := utils.GetUserIDFromContext(c); ok {
+			if slices.Contains(cfg.ExemptUsers, c.GetString("username")) {
+				c.Next()
+				return
+			}
+			key = fmt.Sprintf("user:%d", userID)
+		}
+
+		if ok, wait := limiter.Allow(key); !ok {
+			rejectRateLimited(c, wait)
+			return
+		}
+		c.Next()
+	}
+}
+
+// PublicKnowledgeRateLimitMiddleware limite les requêtes par adresse IP sur le portail public de la base de connaissances
+// (KB_PUBLIC_RATE_LIMIT_PER_MINUTE et KB_PUBLIC_RATE_LIMIT_BURST), avant toute vérification de clé d'API
+func PublicKnowledgeRateLimitMiddleware() gin.HandlerFunc {
+	if config.Current() == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	kbPublicLimiterOnce.Do(func() {
+		cfg := config.Current().KBPublic
+		kbPublicLimiter = NewRateLimiter(cfg.PerMinute, cfg.Burst)
+		config.OnReload(func(cfg *config.Config) {
+			kbPublicLimiter.SetLimits(cfg.KBPublic.PerMinute, cfg.KBPublic.Burst)
+		})
+	})
+	limiter := kbPublicLimiter
+
+	return func(c *gin.Context) {
+		if ok, wait := limiter.Allow("ip:" + c.ClientIP()); !ok {
+			rejectRateLimited(c, wait)
+			return
+		}
+		c.Next()
+	}
+}
+
+// rejectRateLimited répond 429 avec l'en-tête Retry-After (en secondes, arrondi au supérieur)
+func rejectRateLimited(c *gin.Context, wait time.Duration) {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	logger.FromContext(c.Request.Context()).Warn("rate_limited",
+		"client_ip", c.ClientIP(),
+		"path", c.Request.URL.Path,
+		"retry_after_s", retryAfter,
+	)
+	utils.ErrorResponse(c, http.StatusTooManyRequests, "Trop de requêtes, veuillez réessayer plus tard", gin.H{"retry_after": retryAfter})
+	c.Abort()
+}
+
+// rateLimitConfig retourne la configuration de limitation active (désactivée si la configuration n'est pas chargée)
+func rateLimitConfig() config.RateLimitConfig {
+	cfg := config.Current()
+	if cfg == nil {
+		return config.RateLimitConfig{Enabled: false}
+	}
+	return cfg.RateLimit
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/logger"
+)
+
+// RequestIDHeader est l'en-tête HTTP portant l'identifiant de requête
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limite les identifiants fournis par le client (évite l'injection dans les logs)
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware attribue un identifiant unique à chaque requête
+// L'identifiant fourni par le client (X-Request-ID) est réutilisé s'il est valide, sinon un nouveau est généré
+// Il est renvoyé dans la réponse, stocké dans le contexte Gin ("request_id") et attaché au logger de la requête
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set("request_id", requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+
+		reqLogger := slog.Default().With("request_id", requestID)
+		c.Request = c.Request.WithContext(logger.WithLogger(c.Request.Context(), reqLogger))
+
+		c.Next()
+	}
+}
+
+// newRequestID génère un identifiant aléatoire de 16 octets encodé en hexadécimal
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/logger"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// buildRequestScope construit le QueryScope de la requête, une seule fois, à partir de l'utilisateur authentifié :
+//   - utilisateur, filiale, département et permissions du rôle ;
+//   - délégation d'absence (en-tête X-On-Behalf-Of) : scope du délégant, limité aux routes déléguées ;
+//   - périmètre forcé du tableau de bord (?scope=department|filiale|global) pour les lectures,
+//     accepté seulement s'il ne dépasse pas le périmètre des permissions.
+//
+// Les handlers consomment ce scope via utils.GetScopeFromContext / utils.RequireScope sans le reconstruire
+// Retourne nil si la requête a été rejetée (réponse déjà envoyée)
+func buildRequestScope(c *gin.Context, user *models.User, userRepo repositories.UserRepository, delegationRepo repositories.AccessDelegationRepository) *scope.QueryScope {
+	// Une erreur de chargement des permissions ne doit pas aboutir à un périmètre dégradé
+	queryScope, err := scope.NewQueryScopeFromUser(user)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("permissions_unavailable", "user_id", user.ID, "role", user.Role.Name, "error", err)
+		c.Header("Retry-After", "5")
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Permissions temporairement indisponibles, veuillez réessayer", nil)
+		return nil
+	}
+
+	// Action pour le compte d'un collègue absent
+	if onBehalfOf := c.GetHeader(OnBehalfOfHeader); onBehalfOf != "" {
+		queryScope = applyDelegation(c, delegationRepo, userRepo, user.ID, onBehalfOf)
+		if queryScope == nil {
+			return nil
+		}
+	}
+
+	// Périmètre forcé du tableau de bord (lectures uniquement)
+	if hint := c.Query("scope"); hint != "" && c.Request.Method == http.MethodGet {
+		if !queryScope.ApplyDashboardHint(hint) {
+			logger.FromContext(c.Request.Context()).Debug("dashboard_scope_hint_ignored", "hint", hint)
+		}
+	}
+
+	return queryScope
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/logger"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SharedWriteGuard refuse les modifications (méthodes autres que GET/HEAD/OPTIONS) d'un ticket ou d'un projet
+// accessible uniquement via un partage en lecture seule ; l'ID de la ressource est lu dans le paramètre :id
+// Doit être placé après AuthMiddleware
+func SharedWriteGuard(resourceType string) gin.HandlerFunc {
+	shareRepo := repositories.NewRecordShareRepository()
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		queryScope := utils.GetScopeFromContext(c)
+		resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || queryScope == nil || queryScope.SharedAccess(resourceType, uint(resourceID)) != models.ShareAccessRead {
+			c.Next()
+			return
+		}
+
+		// Partage en lecture seule : la modification reste possible si les permissions donnent déjà accès à la ressource
+		visible, err := shareRepo.IsVisible(resourceType, uint(resourceID), queryScope.WithoutShares())
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("shared_write_check_failed", "resource_type", resourceType, "resource_id", resourceID, "error", err)
+			utils.InternalServerErrorResponse(c, "Erreur lors de la vérification des droits d'accès")
+			c.Abort()
+			return
+		}
+		if !visible {
+			utils.ForbiddenResponse(c, "Accès en lecture seule : cette ressource vous est partagée sans droit de modification")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
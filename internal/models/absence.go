@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Types d'absence
+const (
+	AbsenceTypeVacation = "vacation" // Congés
+	AbsenceTypeSick     = "sick"     // Maladie
+	AbsenceTypeTraining = "training" // Formation
+)
+
+// AbsenceTypes types d'absence acceptés
+var AbsenceTypes = []string{AbsenceTypeVacation, AbsenceTypeSick, AbsenceTypeTraining}
+
+// Statuts d'une demande d'absence
+const (
+	AbsenceStatusPending   = "pending"   // Demandée, en attente d'approbation
+	AbsenceStatusApproved  = "approved"  // Approuvée : les jours ouvrés couverts ne sont pas attendus en saisie de temps
+	AbsenceStatusRejected  = "rejected"  // Refusée
+	AbsenceStatusCancelled = "cancelled" // Annulée par le demandeur ou un validateur
+)
+
+// AbsenceBlockingStatuses statuts qui occupent la période (chevauchement refusé pour une nouvelle demande)
+var AbsenceBlockingStatuses = []string{AbsenceStatusPending, AbsenceStatusApproved}
+
+// Absence représente une absence d'un utilisateur (congés, maladie, formation) sur des journées entières
+// Table: absences
+type Absence struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	UserID          uint       `gorm:"not null;index:idx_absences_user_period,priority:1" json:"user_id"`
+	Type            string     `gorm:"type:varchar(20);not null" json:"type"` // vacation, sick, training
+	StartDate       time.Time  `gorm:"type:date;not null;index:idx_absences_user_period,priority:2" json:"start_date"`
+	EndDate         time.Time  `gorm:"type:date;not null;index:idx_absences_user_period,priority:3" json:"end_date"` // Dernier jour d'absence (inclus)
+	Reason          string     `gorm:"type:varchar(500)" json:"reason,omitempty"`
+	Status          string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // pending, approved, rejected, cancelled
+	DecidedByID     *uint      `json:"decided_by_id,omitempty"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+	DecisionComment string     `gorm:"type:varchar(500)" json:"decision_comment,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relations
+	User      *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	DecidedBy *User `gorm:"foreignKey:DecidedByID" json:"decided_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (Absence) TableName() string {
+	return "absences"
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Domaines couverts par une délégation d'absence
+const (
+	DelegationAreaTimesheet = "timesheet_validation" // Validation des temps, déclarations et justifications de retard
+	DelegationAreaTickets   = "ticket_validation"    // Validation des tickets, tickets internes et demandes de service
+	DelegationAreaProjects  = "project_approval"     // Approbations projet (budget temps, extensions)
+)
+
+// AccessDelegation représente la délégation des validations et de la visibilité d'un utilisateur
+// à un collègue pendant une absence (congés, déplacement)
+// Table: access_delegations
+type AccessDelegation struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	DelegatorID uint       `gorm:"not null;index" json:"delegator_id"`        // Utilisateur absent
+	DelegateID  uint       `gorm:"not null;index" json:"delegate_id"`         // Collègue qui agit pour son compte
+	Areas       string     `gorm:"type:varchar(255);not null" json:"areas"`   // Domaines délégués, séparés par des virgules
+	StartsAt    time.Time  `gorm:"not null;index" json:"starts_at"`           // Début de la délégation
+	EndsAt      time.Time  `gorm:"not null;index" json:"ends_at"`             // Fin de la délégation
+	Reason      string     `gorm:"type:varchar(255)" json:"reason,omitempty"` // Motif (optionnel)
+	RevokedAt   *time.Time `gorm:"index" json:"revoked_at,omitempty"`         // Révocation anticipée
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relations
+	Delegator *User `gorm:"foreignKey:DelegatorID" json:"-"`
+	Delegate  *User `gorm:"foreignKey:DelegateID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (AccessDelegation) TableName() string {
+	return "access_delegations"
+}
+
+// AreaList retourne les domaines délégués
+func (d *AccessDelegation) AreaList() []string {
+	areas := []string{}
+	for _, area := range strings.Split(d.Areas, ",") {
+		if area = strings.TrimSpace(area); area != "" {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+// HasArea indique si le domaine est couvert par la délégation
+func (d *AccessDelegation) HasArea(area string) bool {
+	for _, a := range d.AreaList() {
+		if a == area {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActiveAt indique si la délégation est en vigueur à l'instant donné
+func (d *AccessDelegation) IsActiveAt(at time.Time) bool {
+	return d.RevokedAt == nil && !at.Before(d.StartsAt) && at.Before(d.EndsAt)
+}
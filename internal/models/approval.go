@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+)
+
+// Modules pouvant soumettre un enregistrement à approbation (EntityType)
+const (
+	ApprovalEntityServiceRequest     = "service_request"
+	ApprovalEntityChange             = "change"
+	ApprovalEntityBudgetExtension    = "budget_extension"
+	ApprovalEntityWeeklyDeclaration  = "weekly_declaration"
+	ApprovalEntityAssetReservation   = "asset_reservation"
+	ApprovalEntityDelayJustification = "delay_justification"
+)
+
+// Statuts d'une demande d'approbation
+const (
+	ApprovalStatusPending   = "pending"
+	ApprovalStatusApproved  = "approved"
+	ApprovalStatusRejected  = "rejected"
+	ApprovalStatusCancelled = "cancelled"
+)
+
+// Statuts d'un approbateur
+const (
+	ApproverStatusWaiting  = "waiting" // Étape pas encore atteinte
+	ApproverStatusPending  = "pending" // Décision attendue (étape courante)
+	ApproverStatusApproved = "approved"
+	ApproverStatusRejected = "rejected"
+	ApproverStatusSkipped  = "skipped" // Demande close avant sa décision
+)
+
+// ApprovalRequest représente une demande d'approbation portant sur un enregistrement d'un module
+// Les approbateurs décident par étapes (ordre croissant) : tous ceux d'une étape doivent approuver
+// pour passer à la suivante ; un seul rejet rejette la demande
+// Table: approval_requests
+type ApprovalRequest struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	EntityType    string     `gorm:"type:varchar(50);not null;index:idx_approval_requests_entity" json:"entity_type"` // service_request, change, budget_extension, weekly_declaration
+	EntityID      uint       `gorm:"not null;index:idx_approval_requests_entity" json:"entity_id"`
+	Title         string     `gorm:"type:varchar(255);not null" json:"title"`                         // Libellé de l'enregistrement au moment de la demande
+	Description   string     `gorm:"type:text" json:"description,omitempty"`                          // Motif de la demande (optionnel)
+	Status        string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // pending, approved, rejected, cancelled
+	CurrentStep   int        `gorm:"not null;default:0" json:"current_step"`                          // Ordre de l'étape en attente de décision
+	RequestedByID uint       `gorm:"not null;index" json:"requested_by_id"`
+	FilialeID     *uint      `gorm:"index" json:"filiale_id,omitempty"`              // Filiale de l'enregistrement (optionnel)
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`                           // Date de la décision finale
+	ApplyError    string     `gorm:"type:varchar(500)" json:"apply_error,omitempty"` // Échec de l'application de la décision au module (optionnel)
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relations
+	RequestedBy User               `gorm:"foreignKey:RequestedByID" json:"requested_by,omitempty"`
+	Approvers   []ApprovalApprover `gorm:"foreignKey:ApprovalRequestID;constraint:OnDelete:CASCADE" json:"approvers,omitempty"`
+	Comments    []ApprovalComment  `gorm:"foreignKey:ApprovalRequestID;constraint:OnDelete:CASCADE" json:"comments,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (ApprovalRequest) TableName() string {
+	return "approval_requests"
+}
+
+// ApprovalApprover représente un approbateur d'une demande et sa décision
+// Table: approval_approvers
+type ApprovalApprover struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	ApprovalRequestID uint       `gorm:"not null;uniqueIndex:idx_approval_approvers_pair" json:"approval_request_id"`
+	UserID            uint       `gorm:"not null;uniqueIndex:idx_approval_approvers_pair;index:idx_approval_approvers_user_status" json:"user_id"`
+	StepOrder         int        `gorm:"not null;default:1" json:"step_order"`                                             // Étape de l'approbateur (les étapes sont traitées par ordre croissant)
+	Status            string     `gorm:"type:varchar(20);not null;index:idx_approval_approvers_user_status" json:"status"` // waiting, pending, approved, rejected, skipped
+	Comment           string     `gorm:"type:text" json:"comment,omitempty"`                                               // Commentaire de la décision
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (ApprovalApprover) TableName() string {
+	return "approval_approvers"
+}
+
+// ApprovalComment représente un commentaire échangé sur une demande d'approbation
+// Table: approval_comments
+type ApprovalComment struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ApprovalRequestID uint      `gorm:"not null;index" json:"approval_request_id"`
+	UserID            uint      `gorm:"not null;index" json:"user_id"`
+	Comment           string    `gorm:"type:text;not null" json:"comment"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (ApprovalComment) TableName() string {
+	return "approval_comments"
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -26,20 +27,69 @@ func (AssetCategory) TableName() string {
 	return "asset_categories"
 }
 
+// Statuts du cycle de vie d'un actif (de la commande à la mise au rebut)
+const (
+	AssetStatusOrdered  = "ordered"   // Commandé, pas encore réceptionné
+	AssetStatusInStock  = "in_stock"  // En stock, disponible
+	AssetStatusAssigned = "assigned"  // Affecté à un utilisateur
+	AssetStatusInRepair = "in_repair" // En réparation
+	AssetStatusRetired  = "retired"   // Retiré du service
+	AssetStatusDisposed = "disposed"  // Mis au rebut ou cédé (état final)
+)
+
+// Méthodes d'amortissement d'un actif
+const (
+	AssetDepreciationStraightLine     = "straight_line"     // Linéaire
+	AssetDepreciationDecliningBalance = "declining_balance" // Dégressif (double taux linéaire)
+)
+
+// legacyAssetStatuses fait correspondre les anciens statuts aux statuts du cycle de vie
+var legacyAssetStatuses = map[string]string{
+	"available":   AssetStatusInStock,
+	"in_use":      AssetStatusAssigned,
+	"maintenance": AssetStatusInRepair,
+}
+
+// NormalizeAssetStatus convertit un ancien statut (available, in_use, maintenance) en statut du cycle de vie
+func NormalizeAssetStatus(status string) string {
+	if mapped, ok := legacyAssetStatuses[status]; ok {
+		return mapped
+	}
+	return status
+}
+
+// LegacyAssetStatuses retourne la correspondance ancien statut -> statut du cycle de vie (migration)
+func LegacyAssetStatuses() map[string]string {
+	return legacyAssetStatuses
+}
+
 // Asset représente un actif IT (équipement)
 // Table: assets
 type Asset struct {
 	ID             uint           `gorm:"primaryKey" json:"id"`
 	Name           string         `gorm:"type:varchar(255);not null" json:"name"`
+	Code           *string        `gorm:"type:varchar(50);uniqueIndex" json:"code,omitempty"` // Étiquette d'inventaire encodée dans le QR code (défaut: AST-NNNNNN)
 	SerialNumber   string         `gorm:"type:varchar(100);index" json:"serial_number,omitempty"`
 	Model          string         `gorm:"type:varchar(255)" json:"model,omitempty"`
 	Manufacturer   string         `gorm:"type:varchar(255)" json:"manufacturer,omitempty"`
 	CategoryID     uint           `gorm:"not null;index" json:"category_id"`
 	FilialeID      *uint          `gorm:"index" json:"filiale_id,omitempty"`                    // ID de la filiale (optionnel)
 	AssignedToID   *uint          `gorm:"index" json:"assigned_to_id,omitempty"` // ID utilisateur assigné (optionnel)
-	Status         string         `gorm:"type:varchar(50);default:'available';index" json:"status"` // available, in_use, maintenance, retired
+	Status         string         `gorm:"type:varchar(50);default:'in_stock';index" json:"status"` // ordered, in_stock, assigned, in_repair, retired, disposed
 	PurchaseDate   *time.Time     `gorm:"type:date" json:"purchase_date,omitempty"`
 	WarrantyExpiry *time.Time      `gorm:"type:date" json:"warranty_expiry,omitempty"`
+	Supplier           string     `gorm:"type:varchar(255)" json:"supplier,omitempty"`                     // Fournisseur
+	ContractReference  string     `gorm:"type:varchar(100)" json:"contract_reference,omitempty"`           // Contrat de maintenance / location
+	ContractEndDate    *time.Time `gorm:"type:date;index" json:"contract_end_date,omitempty"`              // Fin du contrat
+	PurchaseCost       *float64   `gorm:"type:decimal(12,2)" json:"purchase_cost,omitempty"`               // Coût d'achat (base amortissable)
+	SalvageValue       *float64   `gorm:"type:decimal(12,2)" json:"salvage_value,omitempty"`               // Valeur résiduelle en fin d'amortissement
+	DepreciationMonths *int       `json:"depreciation_months,omitempty"`                                   // Durée d'amortissement en mois
+	DepreciationMethod string     `gorm:"type:varchar(20);default:'straight_line'" json:"depreciation_method"` // straight_line, declining_balance
+	InServiceDate      *time.Time `gorm:"type:date" json:"in_service_date,omitempty"`                      // Mise en service (début d'amortissement, défaut: date d'achat)
+	RetiredAt          *time.Time `json:"retired_at,omitempty"`
+	DisposedAt         *time.Time `json:"disposed_at,omitempty"`
+	LastDiscoveredAt   *time.Time `json:"last_discovered_at,omitempty"`                                  // Dernier inventaire remonté par un agent de découverte
+	Loanable           bool       `gorm:"default:false;index" json:"loanable"`                             // Équipement partagé du parc de prêt (réservable)
 	Location       string         `gorm:"type:varchar(255)" json:"location,omitempty"`
 	Notes          string         `gorm:"type:text" json:"notes,omitempty"`
 	CreatedAt      time.Time      `json:"created_at"`
@@ -60,3 +110,18 @@ func (Asset) TableName() string {
 	return "assets"
 }
 
+// AssetCodeFromID retourne l'étiquette d'inventaire par défaut d'un actif (AST-NNNNNN)
+func AssetCodeFromID(id uint) string {
+	return fmt.Sprintf("AST-%06d", id)
+}
+
+// AfterCreate attribue l'étiquette par défaut aux actifs créés sans code, quel que soit le point de création
+func (a *Asset) AfterCreate(tx *gorm.DB) error {
+	if a.Code != nil && *a.Code != "" {
+		return nil
+	}
+	code := AssetCodeFromID(a.ID)
+	a.Code = &code
+	return tx.Model(&Asset{}).Where("id = ?", a.ID).UpdateColumn("code", code).Error
+}
+
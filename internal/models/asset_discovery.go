@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// DiscoveryAgent représente un agent d'inventaire (ou script) autorisé à remonter le matériel et les logiciels découverts
+// L'agent s'authentifie par une clé d'API dont seul le hash est stocké
+// Table: discovery_agents
+type DiscoveryAgent struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	Name              string     `gorm:"type:varchar(100);not null" json:"name"`
+	KeyHash           string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // Hash SHA256 de la clé d'API
+	KeyPrefix         string     `gorm:"type:varchar(12)" json:"key_prefix"`             // Début de la clé, pour l'identifier
+	FilialeID         *uint      `gorm:"index" json:"filiale_id,omitempty"`              // Filiale des actifs créés et périmètre du rapprochement par nom
+	DefaultCategoryID *uint      `gorm:"index" json:"default_category_id,omitempty"`     // Catégorie des actifs créés quand l'agent n'en indique pas
+	AutoCreate        bool       `gorm:"not null" json:"auto_create"`                    // Créer les actifs inconnus de la CMDB
+	IsActive          bool       `gorm:"default:true" json:"is_active"`
+	LastReportAt      *time.Time `json:"last_report_at,omitempty"`
+	CreatedByID       uint       `gorm:"not null;index" json:"created_by_id"` // Auteur de l'historique des actifs créés
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relations
+	Filiale         *Filiale       `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+	DefaultCategory *AssetCategory `gorm:"foreignKey:DefaultCategoryID" json:"default_category,omitempty"`
+	CreatedBy       User           `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (DiscoveryAgent) TableName() string {
+	return "discovery_agents"
+}
+
+// Champs d'un actif suivis par la découverte
+const (
+	DiscoveryFieldName         = "name"
+	DiscoveryFieldSerialNumber = "serial_number"
+	DiscoveryFieldManufacturer = "manufacturer"
+	DiscoveryFieldModel        = "model"
+	DiscoveryFieldSoftware     = "software" // Logiciel inscrit dans la CMDB mais absent de l'inventaire
+)
+
+// États d'un écart de découverte
+const (
+	DiscoveryDriftOpen      = "open"      // À examiner
+	DiscoveryDriftAccepted  = "accepted"  // Valeur découverte appliquée à la CMDB
+	DiscoveryDriftDismissed = "dismissed" // Écart ignoré : la CMDB fait foi
+	DiscoveryDriftCleared   = "cleared"   // Un inventaire ultérieur concorde à nouveau avec la CMDB
+)
+
+// AssetDiscoveryDrift représente un écart entre la CMDB et l'inventaire remonté par un agent
+// Un écart ouvert est mis à jour par les inventaires suivants tant qu'il n'est pas traité
+// Table: asset_discovery_drifts
+type AssetDiscoveryDrift struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	AssetID         uint       `gorm:"not null;index" json:"asset_id"`
+	AgentID         *uint      `gorm:"index" json:"agent_id,omitempty"`
+	Field           string     `gorm:"type:varchar(30);not null" json:"field"`                       // name, serial_number, manufacturer, model, software
+	Subject         string     `gorm:"type:varchar(255)" json:"subject"`                             // Logiciel concerné (champ software)
+	CMDBValue       string     `gorm:"type:varchar(255)" json:"cmdb_value"`                          // Valeur enregistrée dans la CMDB
+	DiscoveredValue string     `gorm:"type:varchar(255)" json:"discovered_value"`                    // Valeur remontée par l'agent (vide : absent de l'inventaire)
+	Status          string     `gorm:"type:varchar(20);not null;default:'open';index" json:"status"` // open, accepted, dismissed, cleared
+	Occurrences     int        `gorm:"default:1" json:"occurrences"`                                 // Nombre d'inventaires ayant constaté l'écart
+	DetectedAt      time.Time  `json:"detected_at"`
+	LastSeenAt      time.Time  `json:"last_seen_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	ResolvedByID    *uint      `gorm:"index" json:"resolved_by_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relations
+	Asset      Asset           `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE" json:"-"`
+	Agent      *DiscoveryAgent `gorm:"foreignKey:AgentID;constraint:OnDelete:SET NULL" json:"-"`
+	ResolvedBy *User           `gorm:"foreignKey:ResolvedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (AssetDiscoveryDrift) TableName() string {
+	return "asset_discovery_drifts"
+}
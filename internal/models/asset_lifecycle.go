@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// AssetLifecycleEvent représente une transition dans le cycle de vie d'un actif
+// Table: asset_lifecycle_events
+type AssetLifecycleEvent struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	AssetID       uint      `gorm:"not null;index" json:"asset_id"`
+	FromStatus    string    `gorm:"type:varchar(20)" json:"from_status,omitempty"` // Vide à la création de l'actif
+	ToStatus      string    `gorm:"type:varchar(20);not null" json:"to_status"`    // ordered, in_stock, assigned, in_repair, retired, disposed
+	AssignedToID  *uint     `gorm:"index" json:"assigned_to_id,omitempty"`         // Utilisateur affecté après la transition
+	Comment       string    `gorm:"type:text" json:"comment,omitempty"`            // Motif de la transition
+	PerformedByID uint      `gorm:"not null;index" json:"performed_by_id"`         // Auteur de la transition
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+
+	// Relations
+	Asset       Asset `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE" json:"-"`
+	AssignedTo  *User `gorm:"foreignKey:AssignedToID" json:"assigned_to,omitempty"`
+	PerformedBy User  `gorm:"foreignKey:PerformedByID" json:"performed_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (AssetLifecycleEvent) TableName() string {
+	return "asset_lifecycle_events"
+}
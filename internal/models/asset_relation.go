@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Types de relations de dépendance de la CMDB (la source s'appuie sur la cible : une panne de la cible impacte la source)
+const (
+	AssetRelationRunsOn     = "runs_on"     // La source s'exécute sur la cible (VM sur hôte, logiciel sur serveur)
+	AssetRelationConnectsTo = "connects_to" // La source est raccordée à la cible (poste sur switch, serveur sur baie)
+	AssetRelationDependsOn  = "depends_on"  // La source a besoin de la cible pour fonctionner (application sur base de données)
+)
+
+// AssetRelation représente une dépendance entre un actif (ou un logiciel du catalogue, vu comme service) et l'actif dont il dépend
+// La source est soit un actif, soit un logiciel ; la cible est toujours un actif
+// Table: asset_relations
+type AssetRelation struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	SourceAssetID    *uint     `gorm:"index" json:"source_asset_id,omitempty"`
+	SourceSoftwareID *uint     `gorm:"index" json:"source_software_id,omitempty"`
+	TargetAssetID    uint      `gorm:"not null;index" json:"target_asset_id"`
+	Type             string    `gorm:"type:varchar(20);not null" json:"type"` // runs_on, connects_to, depends_on
+	Notes            string    `gorm:"type:varchar(500)" json:"notes,omitempty"`
+	CreatedByID      uint      `gorm:"not null" json:"created_by_id"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	// Relations
+	SourceAsset    *Asset    `gorm:"foreignKey:SourceAssetID;constraint:OnDelete:CASCADE" json:"source_asset,omitempty"`
+	SourceSoftware *Software `gorm:"foreignKey:SourceSoftwareID;constraint:OnDelete:CASCADE" json:"source_software,omitempty"`
+	TargetAsset    *Asset    `gorm:"foreignKey:TargetAssetID;constraint:OnDelete:CASCADE" json:"target_asset,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (AssetRelation) TableName() string {
+	return "asset_relations"
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Statuts d'une réservation d'équipement du parc de prêt
+const (
+	AssetReservationPending    = "pending"     // Demandée, en attente d'approbation
+	AssetReservationApproved   = "approved"    // Approuvée, équipement à remettre
+	AssetReservationRejected   = "rejected"    // Refusée
+	AssetReservationCancelled  = "cancelled"   // Annulée par le demandeur ou un gestionnaire
+	AssetReservationCheckedOut = "checked_out" // Équipement remis à l'emprunteur
+	AssetReservationReturned   = "returned"    // Équipement restitué (état final)
+)
+
+// AssetReservationBlockingStatuses statuts qui occupent le créneau (conflit avec une nouvelle réservation)
+var AssetReservationBlockingStatuses = []string{AssetReservationPending, AssetReservationApproved, AssetReservationCheckedOut}
+
+// AssetReservation représente la réservation d'un équipement partagé (actif prêtable) sur un créneau
+// Table: asset_reservations
+type AssetReservation struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	AssetID           uint       `gorm:"not null;index:idx_asset_reservations_slot,priority:1" json:"asset_id"`
+	RequesterID       uint       `gorm:"not null;index" json:"requester_id"` // Emprunteur
+	StartAt           time.Time  `gorm:"not null;index:idx_asset_reservations_slot,priority:2" json:"start_at"`
+	EndAt             time.Time  `gorm:"not null;index:idx_asset_reservations_slot,priority:3" json:"end_at"` // Date de retour prévue
+	Purpose           string     `gorm:"type:varchar(500)" json:"purpose,omitempty"`
+	Status            string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // pending, approved, rejected, cancelled, checked_out, returned
+	DecidedByID       *uint      `json:"decided_by_id,omitempty"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+	DecisionComment   string     `gorm:"type:varchar(500)" json:"decision_comment,omitempty"`
+	CheckedOutAt      *time.Time `json:"checked_out_at,omitempty"`
+	CheckedOutByID    *uint      `json:"checked_out_by_id,omitempty"`
+	ReturnedAt        *time.Time `json:"returned_at,omitempty"`
+	ReturnedByID      *uint      `json:"returned_by_id,omitempty"`
+	ReturnNotes       string     `gorm:"type:varchar(500)" json:"return_notes,omitempty"` // État constaté au retour
+	DueReminderSentAt *time.Time `json:"-"`                                               // Rappel de retour envoyé
+	OverdueNotifiedAt *time.Time `json:"-"`                                               // Alerte de retard envoyée
+	CreatedByID       uint       `gorm:"not null" json:"created_by_id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relations
+	Asset     *Asset `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE" json:"asset,omitempty"`
+	Requester *User  `gorm:"foreignKey:RequesterID" json:"requester,omitempty"`
+	DecidedBy *User  `gorm:"foreignKey:DecidedByID" json:"decided_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (AssetReservation) TableName() string {
+	return "asset_reservations"
+}
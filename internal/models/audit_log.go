@@ -11,6 +11,7 @@ import (
 type AuditLog struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	UserID      *uint          `gorm:"index" json:"user_id,omitempty"` // Utilisateur qui a effectué l'action (optionnel, peut être NULL pour actions système)
+	OnBehalfOfID *uint         `gorm:"index" json:"on_behalf_of_id,omitempty"` // Utilisateur pour le compte duquel l'action a été faite (délégation d'absence)
 	Action      string         `gorm:"type:varchar(100);not null;index" json:"action"` // create, update, delete, login, logout, etc.
 	EntityType  string         `gorm:"type:varchar(100);not null;index" json:"entity_type"` // Type d'entité (users, tickets, etc.)
 	EntityID    *uint          `gorm:"index" json:"entity_id,omitempty"` // ID de l'entité concernée (optionnel)
@@ -20,9 +21,13 @@ type AuditLog struct {
 	UserAgent   string         `gorm:"type:varchar(500)" json:"user_agent,omitempty"` // User-Agent du navigateur
 	Description string         `gorm:"type:text" json:"description,omitempty"` // Description de l'action (optionnel)
 	CreatedAt   time.Time      `gorm:"index" json:"created_at"`
+	// Chaînage : empreinte SHA-256 de l'entrée incluant l'empreinte de l'entrée précédente (vide pour les entrées antérieures au chaînage)
+	PrevHash string `gorm:"type:varchar(64)" json:"prev_hash,omitempty"`
+	Hash     string `gorm:"type:varchar(64);index" json:"hash,omitempty"`
 
 	// Relations
-	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"` // Utilisateur (optionnel)
+	User       *User `gorm:"foreignKey:UserID" json:"user,omitempty"`             // Utilisateur (optionnel)
+	OnBehalfOf *User `gorm:"foreignKey:OnBehalfOfID" json:"on_behalf_of,omitempty"` // Délégant (optionnel)
 }
 
 // TableName spécifie le nom de la table
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// BusinessCalendar représente le calendrier ouvré (jours et heures de travail, jours fériés) utilisé pour calculer les échéances SLA
+// Un calendrier est rattaché à une filiale ; le calendrier sans filiale s'applique par défaut. Sans calendrier, les SLA courent 24h/24
+// Table: business_calendars
+type BusinessCalendar struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Name         string         `gorm:"type:varchar(100);not null" json:"name"`
+	FilialeID    *uint          `gorm:"index" json:"filiale_id,omitempty"` // Nil = calendrier par défaut
+	Timezone     string         `gorm:"type:varchar(64)" json:"timezone"`  // Fuseau IANA (vide = fuseau de la filiale, sinon de l'application)
+	WorkingHours datatypes.JSON `gorm:"type:json" json:"working_hours"`    // Créneaux ouvrés : [{"weekday":1,"start":"08:00","end":"12:00"}, ...] (0 = dimanche)
+	IsActive     bool           `gorm:"default:true" json:"is_active"`
+	CreatedByID  *uint          `json:"created_by_id,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+
+	// Relations
+	Filiale  *Filiale          `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+	Holidays []BusinessHoliday `gorm:"foreignKey:CalendarID" json:"holidays,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (BusinessCalendar) TableName() string {
+	return "business_calendars"
+}
+
+// BusinessHoliday représente un jour férié d'un calendrier ouvré
+// Table: business_holidays
+type BusinessHoliday struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CalendarID uint      `gorm:"not null;index" json:"calendar_id"`
+	Date       time.Time `gorm:"type:date;not null" json:"date"`
+	Name       string    `gorm:"type:varchar(150);not null" json:"name"`
+	Recurring  bool      `gorm:"default:false" json:"recurring"` // Reconduit chaque année à la même date (1er janvier, 1er mai, ...)
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (BusinessHoliday) TableName() string {
+	return "business_holidays"
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Statuts d'une connexion d'agenda
+const (
+	CalendarConnectionActive  = "active"  // Synchronisation opérationnelle
+	CalendarConnectionError   = "error"   // Dernière synchronisation en échec (voir LastError)
+	CalendarConnectionRevoked = "revoked" // Autorisation révoquée ou expirée : reconnexion requise
+)
+
+// CalendarConnection représente l'agenda Google ou Microsoft connecté par un utilisateur (autorisation OAuth)
+// Les tâches de projet assignées et les fenêtres de maintenance planifiées y sont publiées
+// Table: calendar_connections
+type CalendarConnection struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_calendar_connection_user_provider" json:"user_id"`
+	Provider     string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_calendar_connection_user_provider" json:"provider"` // google, microsoft
+	AccountEmail string     `gorm:"type:varchar(255)" json:"account_email"`
+	AccessToken  string     `gorm:"type:text" json:"-"`
+	RefreshToken string     `gorm:"type:text" json:"-"`
+	TokenExpiry  time.Time  `json:"-"`
+	Status       string     `gorm:"type:varchar(20);not null;default:'active'" json:"status"` // active, error, revoked
+	LastError    string     `gorm:"type:text" json:"last_error,omitempty"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (CalendarConnection) TableName() string {
+	return "calendar_connections"
+}
+
+// CalendarEventLink associe un élément Kronos à l'événement publié dans un agenda connecté
+// Table: calendar_event_links
+type CalendarEventLink struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ConnectionID uint      `gorm:"not null;uniqueIndex:idx_calendar_event_link_source" json:"connection_id"`
+	SourceType   string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_calendar_event_link_source" json:"source_type"` // project_task, change
+	SourceID     uint      `gorm:"not null;uniqueIndex:idx_calendar_event_link_source" json:"source_id"`
+	EventID      string    `gorm:"type:varchar(255);not null" json:"event_id"`    // Identifiant de l'événement chez le fournisseur
+	ContentHash  string    `gorm:"type:varchar(64);not null" json:"content_hash"` // Contenu publié, pour ne pousser que les modifications
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Relations
+	Connection CalendarConnection `gorm:"foreignKey:ConnectionID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (CalendarEventLink) TableName() string {
+	return "calendar_event_links"
+}
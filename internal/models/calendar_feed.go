@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Périmètres d'un flux de calendrier
+const (
+	CalendarFeedScopeUser       = "user"       // Éléments assignés à l'utilisateur
+	CalendarFeedScopeDepartment = "department" // Éléments assignés aux membres de son département (équipe)
+)
+
+// CalendarFeed représente un abonnement ICS d'un utilisateur (échéances SLA, fenêtres de changement, jalons de projet)
+// Le flux est accessible sans authentification par un token dont seul le hash est stocké
+// Table: calendar_feeds
+type CalendarFeed struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	Name          string     `gorm:"type:varchar(100)" json:"name"`
+	Scope         string     `gorm:"type:varchar(20);not null" json:"scope"`         // user, department
+	TokenHash     string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // Hash SHA256 du token
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`                      // Dernière lecture par un client de calendrier
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
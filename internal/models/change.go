@@ -15,6 +15,8 @@ type Change struct {
 	Result            string     `gorm:"type:varchar(50)" json:"result,omitempty"`                    // success, partial, failed, rolled_back
 	ResultDescription string     `gorm:"type:text" json:"result_description,omitempty"`               // Description du résultat (optionnel)
 	ResultDate        *time.Time `json:"result_date,omitempty"`                                       // Date du résultat (optionnel)
+	PlannedStart      *time.Time `gorm:"index" json:"planned_start,omitempty"`                        // Début de la fenêtre de changement planifiée (optionnel)
+	PlannedEnd        *time.Time `json:"planned_end,omitempty"`                                       // Fin de la fenêtre de changement planifiée (optionnel)
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 
@@ -43,6 +43,7 @@ type DelayJustification struct {
 	ValidatedByID     *uint      `gorm:"index" json:"validated_by_id,omitempty"`                 // ID du validateur (optionnel)
 	ValidatedAt       *time.Time `json:"validated_at,omitempty"`                                 // Date de validation (optionnel)
 	ValidationComment string     `gorm:"type:text" json:"validation_comment,omitempty"`          // Commentaire du validateur (optionnel)
+	ApprovalRequestID *uint      `gorm:"index" json:"approval_request_id,omitempty"`             // Demande du circuit d'approbation (optionnel, absente en validation unique)
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 
@@ -16,8 +16,10 @@ type Department struct {
 	FilialeID   *uint          `gorm:"index" json:"filiale_id,omitempty"`                   // ID de la filiale (optionnel)
 	OfficeID    *uint          `gorm:"index" json:"office_id,omitempty"`                      // ID du siège (optionnel)
 	IsITDepartment bool        `gorm:"default:false;index" json:"is_it_department"`           // Si c'est un département IT (uniquement pour la filiale fournisseur de logiciels)
+	HeadID      *uint          `gorm:"index" json:"head_id,omitempty"`                       // Chef de département (optionnel, approbateur des justifications de retard)
 	Office      *Office        `gorm:"foreignKey:OfficeID" json:"office,omitempty"`          // Relation vers le siège
 	Filiale     *Filiale       `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`        // Relation vers la filiale
+	Head        *User          `gorm:"foreignKey:HeadID" json:"head,omitempty"`              // Relation vers le chef de département
 	IsActive    bool           `gorm:"default:true;index" json:"is_active"`                   // Si le département est actif
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// EscalationRule représente une règle d'escalade automatique des tickets d'une filiale et d'une catégorie
+// Déclencheurs : ticket resté trop longtemps dans un statut (status_duration) ou SLA passé à risque (sla_at_risk)
+// Actions : réassignation à un groupe de repli, priorité relevée d'un niveau, notification de la chaîne d'escalade
+// Table: escalation_rules
+type EscalationRule struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Name           string         `gorm:"type:varchar(150);not null" json:"name"`
+	Description    string         `gorm:"type:text" json:"description,omitempty"`
+	FilialeID      *uint          `gorm:"index" json:"filiale_id,omitempty"`                                  // Nil = toutes les filiales
+	Category       string         `gorm:"type:varchar(50);index" json:"category,omitempty"`                   // Slug de la catégorie de ticket (vide = toutes)
+	Trigger        string         `gorm:"column:trigger_type;type:varchar(30);not null;index" json:"trigger"` // status_duration, sla_at_risk
+	Status         string         `gorm:"type:varchar(50)" json:"status,omitempty"`                           // Statut surveillé (status_duration)
+	ThresholdHours int            `gorm:"default:0" json:"threshold_hours,omitempty"`                         // Durée maximale dans le statut, en heures (status_duration)
+	ReassignToIDs  datatypes.JSON `gorm:"type:json" json:"reassign_to_ids,omitempty"`                         // Groupe de repli : IDs des utilisateurs (le premier est responsable)
+	BumpPriority   bool           `gorm:"default:false" json:"bump_priority"`                                 // Relever la priorité d'un niveau
+	NotifyUserIDs  datatypes.JSON `gorm:"type:json" json:"notify_user_ids,omitempty"`                         // Chaîne d'escalade : IDs des utilisateurs notifiés
+	NotifyManagers bool           `gorm:"default:false" json:"notify_managers"`                               // Notifier aussi les responsables hiérarchiques des assignés
+	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	CreatedByID    uint           `gorm:"not null" json:"created_by_id"` // Les actions sont effectuées au nom du créateur de la règle
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+
+	// Relations
+	Filiale *Filiale `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (EscalationRule) TableName() string {
+	return "escalation_rules"
+}
+
+// TicketEscalation représente l'application d'une règle d'escalade à un ticket
+// Une règle ne s'applique qu'une fois par ticket (une fois par passage dans le statut pour status_duration)
+// Table: ticket_escalations
+type TicketEscalation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RuleID    uint      `gorm:"not null;index:idx_ticket_escalation_rule" json:"rule_id"`
+	TicketID  uint      `gorm:"not null;index:idx_ticket_escalation_rule;index" json:"ticket_id"`
+	Trigger   string    `gorm:"column:trigger_type;type:varchar(30);not null" json:"trigger"`
+	Actions   string    `gorm:"type:varchar(255)" json:"actions,omitempty"` // Actions effectuées (ex: reassigned,priority:high,notified:3)
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketEscalation) TableName() string {
+	return "ticket_escalations"
+}
@@ -17,6 +17,7 @@ type Filiale struct {
 	Address  *string `gorm:"type:text" json:"address,omitempty"`                // Adresse complète
 	Phone    string  `gorm:"type:varchar(20)" json:"phone,omitempty"`           // Téléphone
 	Email    string  `gorm:"type:varchar(255)" json:"email,omitempty"`          // Email de contact
+	Timezone string  `gorm:"type:varchar(64)" json:"timezone,omitempty"`        // Fuseau horaire IANA (ex: Africa/Abidjan), vide = fuseau de l'application
 	IsActive bool    `gorm:"default:true;index" json:"is_active"`               // Si la filiale est active
 	// IsSoftwareProvider : filiale fournisseur de logiciels/IT. Lu depuis la colonne is_mci_care_ci en BDD (rétrocompatibilité).
 	IsSoftwareProvider bool           `gorm:"column:is_mci_care_ci;default:false;index" json:"is_software_provider"`
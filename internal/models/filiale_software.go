@@ -6,19 +6,40 @@ import (
 	"gorm.io/gorm"
 )
 
+// Statuts d'un déploiement de logiciel
+const (
+	DeploymentStatusPlanned    = "planned"     // Planifié
+	DeploymentStatusInProgress = "in_progress" // En cours
+	DeploymentStatusDone       = "done"        // Effectué (version en service si actif)
+	DeploymentStatusRolledBack = "rolled_back" // Annulé / version retirée
+)
+
+// Environnements de déploiement
+const (
+	DeploymentEnvironmentProduction    = "production"
+	DeploymentEnvironmentPreproduction = "preproduction"
+	DeploymentEnvironmentTest          = "test"
+)
+
 // FilialeSoftware représente un déploiement d'un logiciel chez une filiale
+// Un seul déploiement effectué est actif par filiale, logiciel et environnement : la version en service
 // Table: filiale_software
 type FilialeSoftware struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	FilialeID   uint           `gorm:"not null;index" json:"filiale_id"`                       // ID de la filiale
-	SoftwareID  uint           `gorm:"not null;index" json:"software_id"`                     // ID du logiciel
-	Version     string         `gorm:"type:varchar(50)" json:"version,omitempty"`               // Version déployée chez cette filiale
-	DeployedAt  *time.Time     `json:"deployed_at,omitempty"`                                  // Date de déploiement
-	IsActive    bool           `gorm:"default:true;index" json:"is_active"`                     // Si le déploiement est actif
-	Notes       *string        `gorm:"type:text" json:"notes,omitempty"`                        // Notes sur le déploiement
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	FilialeID       uint           `gorm:"not null;index" json:"filiale_id"`                                        // ID de la filiale
+	SoftwareID      uint           `gorm:"not null;index" json:"software_id"`                                       // ID du logiciel
+	Version         string         `gorm:"type:varchar(50)" json:"version,omitempty"`                               // Version déployée chez cette filiale
+	PreviousVersion string         `gorm:"type:varchar(50)" json:"previous_version,omitempty"`                      // Version remplacée lors de la confirmation
+	Environment     string         `gorm:"type:varchar(20);not null;default:'production';index" json:"environment"` // production, preproduction, test
+	Status          string         `gorm:"type:varchar(20);not null;default:'done';index" json:"status"`            // planned, in_progress, done, rolled_back
+	PlannedAt       *time.Time     `json:"planned_at,omitempty"`                                                    // Date prévue
+	DeployedAt      *time.Time     `json:"deployed_at,omitempty"`                                                   // Date de déploiement effectif
+	DeployedByID    *uint          `gorm:"index" json:"deployed_by_id,omitempty"`                                   // Utilisateur ayant confirmé le déploiement
+	IsActive        bool           `gorm:"default:true;index" json:"is_active"`                                     // Si le déploiement est actif
+	Notes           *string        `gorm:"type:text" json:"notes,omitempty"`                                        // Notes sur le déploiement
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
 
 	// Relations
 	Filiale  Filiale  `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
@@ -29,3 +50,25 @@ type FilialeSoftware struct {
 func (FilialeSoftware) TableName() string {
 	return "filiale_software"
 }
+
+// FilialeSoftwareHistory enregistre les étapes d'un déploiement (planification, démarrage, confirmation, retour arrière)
+// Table: filiale_software_history
+type FilialeSoftwareHistory struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	FilialeSoftwareID uint      `gorm:"not null;index" json:"filiale_software_id"`
+	UserID            *uint     `gorm:"index" json:"user_id,omitempty"`
+	Action            string    `gorm:"type:varchar(50);not null" json:"action"` // created, planned, started, confirmed, rolled_back, updated, superseded, restored
+	FromStatus        string    `gorm:"type:varchar(20)" json:"from_status,omitempty"`
+	ToStatus          string    `gorm:"type:varchar(20)" json:"to_status,omitempty"`
+	Version           string    `gorm:"type:varchar(50)" json:"version,omitempty"`
+	Notes             string    `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt         time.Time `gorm:"index" json:"created_at"`
+
+	FilialeSoftware *FilialeSoftware `gorm:"foreignKey:FilialeSoftwareID;constraint:OnDelete:CASCADE" json:"-"`
+	User            *User            `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (FilialeSoftwareHistory) TableName() string {
+	return "filiale_software_history"
+}
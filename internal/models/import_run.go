@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Statuts d'un import
+const (
+	ImportStatusPending   = "pending"   // Créé, premier lot planifié
+	ImportStatusRunning   = "running"   // Lots en cours de traitement
+	ImportStatusFailed    = "failed"    // Interrompu par une erreur de la source : reprise possible
+	ImportStatusCancelled = "cancelled" // Arrêté à la demande : reprise possible
+	ImportStatusCompleted = "completed"
+)
+
+// ImportRun représente un import de données depuis un outil externe (GLPI, ...), traité par lots
+// L'étape et la position courantes permettent de reprendre l'import là où il s'est arrêté
+// Table: import_runs
+type ImportRun struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Source      string         `gorm:"type:varchar(30);not null;index" json:"source"` // glpi, zendesk, freshdesk, csv
+	Status      string         `gorm:"type:varchar(20);not null;index" json:"status"`
+	Options     datatypes.JSON `gorm:"type:json" json:"options"`              // Options de l'import (propres à la source)
+	Stages      datatypes.JSON `gorm:"type:json" json:"stages"`               // Étapes à traiter, dans l'ordre (users, categories, ...)
+	Stage       string         `gorm:"type:varchar(30)" json:"stage"`         // Étape en cours
+	Offset      int            `gorm:"not null;default:0" json:"offset"`      // Position dans l'étape en cours
+	Total       int            `gorm:"not null;default:0" json:"total"`       // Nombre d'éléments de l'étape en cours (selon la source)
+	Stats       datatypes.JSON `gorm:"type:json" json:"stats"`                // Compteurs par étape
+	Errors      datatypes.JSON `gorm:"type:json" json:"errors"`               // Dernières erreurs par élément
+	LastError   string         `gorm:"type:text" json:"last_error,omitempty"` // Erreur ayant interrompu l'import
+	Secret      string         `gorm:"type:text" json:"-"`                    // Jeton d'API de la source (jamais exposé)
+	CreatedByID uint           `gorm:"not null;index" json:"created_by_id"`
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+
+	// Relations
+	CreatedBy User `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (ImportRun) TableName() string {
+	return "import_runs"
+}
+
+// ImportFile représente un fichier (CSV, XLSX) fourni pour une étape d'un import, conservé découpé en lignes
+// Table: import_files
+type ImportFile struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	ImportRunID uint           `gorm:"not null;uniqueIndex:idx_import_file_stage,priority:1" json:"import_run_id"`
+	Stage       string         `gorm:"type:varchar(30);not null;uniqueIndex:idx_import_file_stage,priority:2" json:"stage"` // contacts, tickets, articles
+	FileName    string         `gorm:"type:varchar(255)" json:"file_name"`
+	Rows        datatypes.JSON `gorm:"type:json" json:"-"` // Lignes du fichier, en-tête compris
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (ImportFile) TableName() string {
+	return "import_files"
+}
+
+// ImportMapping associe un élément de la source à l'enregistrement créé (ou retrouvé) dans Kronos
+// Un élément déjà associé n'est jamais réimporté, ce qui rend les lots rejouables
+// Table: import_mappings
+type ImportMapping struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Source      string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_import_mapping,priority:1" json:"source"`
+	EntityType  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_import_mapping,priority:2" json:"entity_type"` // user, category, asset, ticket, followup, document, contact, comment, article
+	ExternalID  string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_import_mapping,priority:3" json:"external_id"`
+	LocalID     uint      `gorm:"not null;index" json:"local_id"`
+	ImportRunID *uint     `gorm:"index" json:"import_run_id,omitempty"` // Import ayant créé l'association
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (ImportMapping) TableName() string {
+	return "import_mappings"
+}
@@ -13,11 +13,23 @@ type Incident struct {
 	Urgency        string     `gorm:"type:varchar(50);not null;index" json:"urgency"`                // low, medium, high, critical
 	ResolutionTime *int       `gorm:"type:int" json:"resolution_time,omitempty"`                     // Temps de résolution en minutes (calculé)
 	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`                                         // Date de résolution (optionnel)
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Incident majeur : communication aux utilisateurs de la filiale et points de situation périodiques
+	IsMajor               bool       `gorm:"default:false;index" json:"is_major"`                // Incident majeur déclaré
+	MajorDeclaredAt       *time.Time `json:"major_declared_at,omitempty"`                        // Date de déclaration
+	MajorDeclaredByID     *uint      `gorm:"index" json:"major_declared_by_id,omitempty"`        // Utilisateur ayant déclaré l'incident majeur
+	MajorEndedAt          *time.Time `json:"major_ended_at,omitempty"`                           // Fin de l'incident majeur (service rétabli)
+	PublicTitle           string     `gorm:"type:varchar(255)" json:"public_title,omitempty"`    // Libellé affiché sur la page de statut publique
+	UpdateIntervalMinutes int        `gorm:"default:0" json:"update_interval_minutes,omitempty"` // Fréquence attendue des points de situation
+	LastStatusUpdateAt    *time.Time `json:"last_status_update_at,omitempty"`                    // Dernier point de situation publié
+	UpdateReminderAt      *time.Time `json:"-"`                                                  // Dernier rappel envoyé pour un point de situation en retard
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	// Relations - GORM utilisera automatiquement le champ TicketID existant
-	Ticket Ticket `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"ticket,omitempty"` // Ticket associé (1:1)
+	Ticket          Ticket `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"ticket,omitempty"` // Ticket associé (1:1)
+	MajorDeclaredBy *User  `gorm:"foreignKey:MajorDeclaredByID" json:"major_declared_by,omitempty"`         // Déclarant de l'incident majeur
 }
 
 // TableName spécifie le nom de la table
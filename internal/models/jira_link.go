@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// TicketJiraLink représente le lien entre un ticket et un ticket Jira synchronisé
+// Table: ticket_jira_links
+type TicketJiraLink struct {
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	TicketID           uint       `gorm:"not null;uniqueIndex" json:"ticket_id"`
+	IssueKey           string     `gorm:"type:varchar(50);not null;uniqueIndex" json:"issue_key"` // Clé Jira (ex: DEV-123)
+	IssueID            string     `gorm:"type:varchar(50)" json:"issue_id"`
+	IssueURL           string     `gorm:"type:varchar(500)" json:"issue_url"`
+	JiraStatus         string     `gorm:"type:varchar(100)" json:"jira_status,omitempty"`         // Dernier statut Jira connu
+	JiraStatusCategory string     `gorm:"type:varchar(20)" json:"jira_status_category,omitempty"` // new, indeterminate, done
+	SyncedStatus       string     `gorm:"type:varchar(50)" json:"synced_status,omitempty"`        // Statut du ticket lors de la dernière synchronisation
+	LastSyncedAt       *time.Time `json:"last_synced_at,omitempty"`
+	SyncError          string     `gorm:"type:text" json:"sync_error,omitempty"` // Dernière erreur de synchronisation (vide si OK)
+	CreatedByID        *uint      `gorm:"index" json:"created_by_id,omitempty"`  // Nil si créé automatiquement
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketJiraLink) TableName() string {
+	return "ticket_jira_links"
+}
+
+// JiraCommentLink associe un commentaire de ticket au commentaire Jira correspondant (évite les doublons dans les deux sens)
+// Table: jira_comment_links
+type JiraCommentLink struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	TicketCommentID uint      `gorm:"not null;uniqueIndex" json:"ticket_comment_id"`
+	IssueKey        string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_jira_comment,priority:1" json:"issue_key"`
+	JiraCommentID   string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_jira_comment,priority:2" json:"jira_comment_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (JiraCommentLink) TableName() string {
+	return "jira_comment_links"
+}
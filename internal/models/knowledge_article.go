@@ -36,11 +36,28 @@ type KnowledgeArticle struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Title       string         `gorm:"type:varchar(255);not null" json:"title"`
 	Content     string         `gorm:"type:text;not null" json:"content"`
+	ContentFormat string       `gorm:"type:varchar(20);not null;default:'text'" json:"content_format"` // text, markdown, html (HTML assaini)
 	CategoryID  uint           `gorm:"not null;index" json:"category_id"`
 	FilialeID   *uint          `gorm:"index" json:"filiale_id,omitempty"`              // ID de la filiale (optionnel pour articles globaux)
 	AuthorID    uint           `gorm:"not null;index" json:"author_id"`
-	IsPublished bool           `gorm:"default:false;index" json:"is_published"` // Si l'article est publié
+	SourceTicketID *uint       `gorm:"index" json:"source_ticket_id,omitempty"` // Ticket dont la solution a servi à rédiger l'article (optionnel)
+	IsPublic    bool           `gorm:"default:false;index" json:"is_public"`    // Exposé sur le portail public (/public/v1/knowledge) une fois publié
+	IsPublished bool           `gorm:"default:false;index" json:"is_published"` // Si l'article est publié (tenu à jour avec Status)
+	Status      string         `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"` // draft, in_review, published, archived
+	Version     int            `gorm:"not null;default:1" json:"version"`                             // Numéro de la révision courante
 	ViewCount   int            `gorm:"default:0" json:"view_count"`             // Nombre de vues
+
+	// Évaluations des lecteurs (agrégats tenus à jour à chaque évaluation)
+	HelpfulCount    int      `gorm:"not null;default:0" json:"helpful_count"`        // Évaluations « utile »
+	NotHelpfulCount int      `gorm:"not null;default:0" json:"not_helpful_count"`    // Évaluations « pas utile »
+	HelpfulRate     *float64 `gorm:"index" json:"helpful_rate,omitempty"`            // Part des évaluations « utile », en pourcentage (nil sans évaluation)
+
+	// Relecture
+	ReviewerID      *uint      `gorm:"index" json:"reviewer_id,omitempty"`   // Relecteur désigné
+	ReviewDueAt     *time.Time `gorm:"index" json:"review_due_at,omitempty"` // Échéance de la relecture en cours ou de la prochaine révision périodique
+	ReviewAlertedAt *time.Time `json:"-"`                                    // Alerte d'échéance envoyée (remise à zéro à chaque nouvelle échéance)
+	LastReviewedAt  *time.Time `json:"last_reviewed_at,omitempty"`
+	ReviewComment   string     `gorm:"type:text" json:"review_comment,omitempty"` // Commentaire de la dernière décision de relecture
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
@@ -57,6 +74,38 @@ func (KnowledgeArticle) TableName() string {
 	return "knowledge_articles"
 }
 
+// Statuts du cycle de relecture d'un article
+const (
+	KnowledgeStatusDraft     = "draft"
+	KnowledgeStatusInReview  = "in_review"
+	KnowledgeStatusPublished = "published"
+	KnowledgeStatusArchived  = "archived"
+)
+
+// KnowledgeArticleRevision représente une version d'un article (chaque modification du contenu crée une version)
+// Table: knowledge_article_revisions
+type KnowledgeArticleRevision struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ArticleID     uint      `gorm:"not null;uniqueIndex:idx_knowledge_revision_version" json:"article_id"`
+	Version       int       `gorm:"not null;uniqueIndex:idx_knowledge_revision_version" json:"version"`
+	Title         string    `gorm:"type:varchar(255);not null" json:"title"`
+	Content       string    `gorm:"type:text;not null" json:"content"`
+	ContentFormat string    `gorm:"type:varchar(20);not null;default:'text'" json:"content_format"`
+	CategoryID    uint      `json:"category_id"`
+	EditorID      uint      `gorm:"not null;index" json:"editor_id"`
+	Summary       string    `gorm:"type:varchar(255)" json:"summary,omitempty"` // Résumé de la modification
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relations
+	Article KnowledgeArticle `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	Editor  User             `gorm:"foreignKey:EditorID" json:"editor,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (KnowledgeArticleRevision) TableName() string {
+	return "knowledge_article_revisions"
+}
+
 // KnowledgeArticleAttachment représente une pièce jointe d'un article
 // Table: knowledge_article_attachments
 type KnowledgeArticleAttachment struct {
@@ -66,6 +115,9 @@ type KnowledgeArticleAttachment struct {
 	FilePath  string         `gorm:"type:varchar(500);not null" json:"file_path"`
 	FileSize  int            `gorm:"type:int" json:"file_size,omitempty"` // Taille en bytes
 	MimeType  string         `gorm:"type:varchar(100)" json:"mime_type,omitempty"`
+	ContentHash  string      `gorm:"type:varchar(64);index" json:"content_hash,omitempty"` // SHA-256 du contenu : un même fichier n'est stocké qu'une fois
+	IsInline     bool        `gorm:"default:false" json:"is_inline"`                      // Image intégrée au contenu (sinon fichier à télécharger)
+	UploadedByID *uint       `gorm:"index" json:"uploaded_by_id,omitempty"`
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
 
@@ -77,3 +129,22 @@ type KnowledgeArticleAttachment struct {
 func (KnowledgeArticleAttachment) TableName() string {
 	return "knowledge_article_attachments"
 }
+
+// KnowledgeStorageQuota représente le quota de stockage des pièces jointes des articles d'une filiale
+// Table: knowledge_storage_quotas
+type KnowledgeStorageQuota struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	FilialeID   uint      `gorm:"not null;uniqueIndex" json:"filiale_id"`
+	MaxBytes    int64     `gorm:"not null" json:"max_bytes"` // Volume maximal en octets (0 = illimité)
+	UpdatedByID *uint     `json:"updated_by_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relations
+	Filiale Filiale `gorm:"foreignKey:FilialeID;constraint:OnDelete:CASCADE" json:"filiale,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (KnowledgeStorageQuota) TableName() string {
+	return "knowledge_storage_quotas"
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Événements de déviation de tickets par la base de connaissances
+const (
+	KnowledgeDeflectionShown         = "shown"          // Article suggéré pendant la saisie d'un ticket
+	KnowledgeDeflectionClicked       = "clicked"        // Article suggéré consulté
+	KnowledgeDeflectionAbandoned     = "abandoned"      // Saisie du ticket abandonnée (déviation réussie si aucun ticket n'est créé ensuite)
+	KnowledgeDeflectionTicketCreated = "ticket_created" // Ticket créé malgré les suggestions
+)
+
+// KnowledgeDeflectionEvent représente un événement du parcours de création d'un ticket face aux articles suggérés
+// Les événements d'une même saisie partagent un identifiant de session, attribué à la première suggestion
+// Table: knowledge_deflection_events
+type KnowledgeDeflectionEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SessionID string    `gorm:"type:varchar(64);not null;index" json:"session_id"`
+	Event     string    `gorm:"type:varchar(20);not null;index" json:"event"` // shown, clicked, abandoned, ticket_created
+	ArticleID *uint     `gorm:"index" json:"article_id,omitempty"`            // Article suggéré (shown, clicked)
+	TicketID  *uint     `gorm:"index" json:"ticket_id,omitempty"`             // Ticket créé (ticket_created)
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	FilialeID *uint     `gorm:"index" json:"filiale_id,omitempty"`        // Filiale de l'utilisateur (rapports par filiale)
+	Score     float64   `json:"score,omitempty"`                          // Pertinence de l'article suggéré (shown)
+	Query     string    `gorm:"type:varchar(255)" json:"query,omitempty"` // Titre du ticket en cours de saisie (shown)
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (KnowledgeDeflectionEvent) TableName() string {
+	return "knowledge_deflection_events"
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// KnowledgeArticleFeedback représente l'évaluation d'un article par un utilisateur (utile / pas utile)
+// Un utilisateur n'a qu'une évaluation par article, qu'il peut modifier ou retirer
+// Table: knowledge_article_feedbacks
+type KnowledgeArticleFeedback struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ArticleID      uint      `gorm:"not null;uniqueIndex:idx_knowledge_feedback_article_user" json:"article_id"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_knowledge_feedback_article_user;index" json:"user_id"`
+	Helpful        bool      `gorm:"not null;index" json:"helpful"`
+	Comment        string    `gorm:"type:text" json:"comment,omitempty"`
+	ArticleVersion int       `gorm:"not null;default:1" json:"article_version"` // Version de l'article évaluée
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Relations
+	Article *KnowledgeArticle `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	User    *User             `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (KnowledgeArticleFeedback) TableName() string {
+	return "knowledge_article_feedbacks"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// KnowledgePortalKey représente une clé d'API du portail public de la base de connaissances (intranet, site web)
+// Seul le hash de la clé est stocké ; une clé de filiale ne voit que les articles globaux et ceux de cette filiale
+// Table: knowledge_portal_keys
+type KnowledgePortalKey struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	KeyHash     string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // Hash SHA256 de la clé d'API
+	KeyPrefix   string     `gorm:"type:varchar(12)" json:"key_prefix"`             // Début de la clé, pour l'identifier
+	FilialeID   *uint      `gorm:"index" json:"filiale_id,omitempty"`              // Nil = articles publics de toutes les filiales
+	IsActive    bool       `gorm:"default:true" json:"is_active"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedByID uint       `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relations
+	Filiale   *Filiale `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+	CreatedBy User     `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (KnowledgePortalKey) TableName() string {
+	return "knowledge_portal_keys"
+}
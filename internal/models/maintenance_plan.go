@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// MaintenancePlan représente un plan de maintenance préventive d'actifs (ex: nettoyage trimestriel des onduleurs)
+// Le plan porte sur un actif ou sur une catégorie d'actifs (un ticket par actif en service de la catégorie)
+// La planification est une expression cron ou une règle RRULE, comme pour les tickets récurrents
+// Table: maintenance_plans
+type MaintenancePlan struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"type:varchar(150);not null" json:"name"`
+	Description string `gorm:"type:text" json:"description,omitempty"` // Consignes reprises dans la description des tickets
+
+	// Périmètre : un actif ou une catégorie d'actifs (exclusifs)
+	AssetID    *uint `gorm:"index" json:"asset_id,omitempty"`
+	CategoryID *uint `gorm:"index" json:"category_id,omitempty"`
+
+	Schedule string    `gorm:"type:varchar(255);not null" json:"schedule"` // Expression cron ou RRULE
+	Timezone string    `gorm:"type:varchar(64)" json:"timezone,omitempty"` // Fuseau IANA de la planification (vide = fuseau par défaut)
+	StartsAt time.Time `json:"starts_at"`                                  // Début de la récurrence (DTSTART des règles RRULE)
+
+	// Modèle des tickets de maintenance
+	Checklist           datatypes.JSON `gorm:"type:json" json:"checklist,omitempty"`                   // Étapes copiées dans la checklist de chaque ticket
+	TicketCategory      string         `gorm:"type:varchar(50);not null" json:"ticket_category"`       // Slug de la catégorie des tickets
+	Priority            string         `gorm:"type:varchar(50);default:'medium'" json:"priority"`      // low, medium, high, critical
+	EstimatedTime       *int           `json:"estimated_time,omitempty"`                               // En minutes
+	RequesterDepartment string         `gorm:"type:varchar(100);not null" json:"requester_department"` // Département demandeur des tickets
+	AssigneeIDs         datatypes.JSON `gorm:"type:json" json:"assignee_ids,omitempty"`                // Équipe assignée (IDs des utilisateurs)
+	LeadID              *uint          `json:"lead_id,omitempty"`                                      // Responsable (parmi les assignés)
+
+	IsActive    bool       `gorm:"default:true;index" json:"is_active"`
+	NextRunAt   *time.Time `gorm:"index" json:"next_run_at,omitempty"` // Nil = récurrence terminée ou plan inactif
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	RunCount    int        `gorm:"default:0" json:"run_count"` // Nombre d'exécutions (borné par COUNT d'une RRULE)
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedByID uint       `gorm:"not null" json:"created_by_id"` // Les tickets sont créés au nom du créateur du plan
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relations
+	Asset    *Asset         `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE" json:"asset,omitempty"`
+	Category *AssetCategory `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE" json:"category,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (MaintenancePlan) TableName() string {
+	return "maintenance_plans"
+}
+
+// MaintenanceRecord représente une opération de maintenance générée par un plan pour un actif (historique de maintenance)
+// Table: maintenance_records
+type MaintenanceRecord struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PlanID    *uint     `gorm:"index" json:"plan_id,omitempty"` // Nil si le plan a été supprimé
+	AssetID   uint      `gorm:"not null;index" json:"asset_id"`
+	TicketID  *uint     `gorm:"index" json:"ticket_id,omitempty"` // Nil si la création du ticket a échoué
+	DueAt     time.Time `gorm:"index" json:"due_at"`              // Échéance planifiée
+	Error     string    `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	// Relations
+	Plan   *MaintenancePlan `gorm:"foreignKey:PlanID;constraint:OnDelete:SET NULL" json:"plan,omitempty"`
+	Asset  Asset            `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE" json:"-"`
+	Ticket *Ticket          `gorm:"foreignKey:TicketID;constraint:OnDelete:SET NULL" json:"ticket,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (MaintenanceRecord) TableName() string {
+	return "maintenance_records"
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// Statuts des points de situation d'un incident majeur (page de statut)
+const (
+	MajorIncidentStatusInvestigating = "investigating" // Analyse en cours
+	MajorIncidentStatusIdentified    = "identified"    // Cause identifiée, correction en cours
+	MajorIncidentStatusMonitoring    = "monitoring"    // Correctif appliqué, surveillance
+	MajorIncidentStatusResolved      = "resolved"      // Service rétabli
+)
+
+// MajorIncidentUpdate représente un point de situation publié pendant un incident majeur
+// Table: major_incident_updates
+type MajorIncidentUpdate struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	IncidentID uint      `gorm:"not null;index" json:"incident_id"`
+	Status     string    `gorm:"type:varchar(20);not null" json:"status"` // investigating, identified, monitoring, resolved
+	Message    string    `gorm:"type:text;not null" json:"message"`       // Message destiné aux utilisateurs
+	IsPublic   bool      `gorm:"not null" json:"is_public"`               // Diffusé aux utilisateurs et affiché sur la page de statut
+	PostedByID uint      `gorm:"not null;index" json:"posted_by_id"`      // Auteur du point de situation
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+
+	// Relations
+	Incident Incident `gorm:"foreignKey:IncidentID;constraint:OnDelete:CASCADE" json:"-"`
+	PostedBy User     `gorm:"foreignKey:PostedByID" json:"posted_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (MajorIncidentUpdate) TableName() string {
+	return "major_incident_updates"
+}
+
+// Statuts d'une revue post-incident
+const (
+	PostIncidentReviewDraft     = "draft"
+	PostIncidentReviewCompleted = "completed"
+)
+
+// PostIncidentReview représente la revue post-incident d'un incident majeur, préremplie à partir du modèle
+// (résumé, chronologie des points de situation) puis complétée par l'équipe
+// Table: post_incident_reviews
+type PostIncidentReview struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	IncidentID     uint       `gorm:"uniqueIndex;not null" json:"incident_id"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'draft'" json:"status"` // draft, completed
+	Summary        string     `gorm:"type:text" json:"summary"`                                // Résumé de l'incident
+	Timeline       string     `gorm:"type:text" json:"timeline"`                               // Chronologie (Markdown)
+	Impact         string     `gorm:"type:text" json:"impact"`                                 // Utilisateurs et services affectés, durée
+	RootCause      string     `gorm:"type:text" json:"root_cause"`                             // Cause racine
+	Resolution     string     `gorm:"type:text" json:"resolution"`                             // Actions ayant rétabli le service
+	LessonsLearned string     `gorm:"type:text" json:"lessons_learned"`                        // Ce qui a bien ou mal fonctionné
+	ActionItems    string     `gorm:"type:text" json:"action_items"`                           // Actions correctives (Markdown)
+	CreatedByID    uint       `gorm:"not null;index" json:"created_by_id"`
+	CompletedByID  *uint      `gorm:"index" json:"completed_by_id,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relations
+	Incident    Incident `gorm:"foreignKey:IncidentID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedBy   User     `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+	CompletedBy *User    `gorm:"foreignKey:CompletedByID" json:"completed_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (PostIncidentReview) TableName() string {
+	return "post_incident_reviews"
+}
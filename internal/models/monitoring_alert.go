@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// AlertSource représente un outil de supervision autorisé à envoyer ses alertes (Alertmanager, Zabbix)
+// L'outil s'authentifie par une clé d'API dont seul le hash est stocké
+// Table: alert_sources
+type AlertSource struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Name           string     `gorm:"type:varchar(100);not null" json:"name"`
+	Kind           string     `gorm:"type:varchar(20);not null" json:"kind"`          // alertmanager, zabbix
+	KeyHash        string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // Hash SHA256 de la clé d'API
+	KeyPrefix      string     `gorm:"type:varchar(12)" json:"key_prefix"`             // Début de la clé, pour l'identifier
+	FilialeID      *uint      `gorm:"index" json:"filiale_id,omitempty"`              // Filiale des tickets créés (sinon celle du créateur de la source)
+	AutoResolve    bool       `gorm:"default:true" json:"auto_resolve"`               // Passer le ticket en résolu quand l'alerte est levée
+	IsActive       bool       `gorm:"default:true" json:"is_active"`
+	LastReceivedAt *time.Time `json:"last_received_at,omitempty"`
+	CreatedByID    uint       `gorm:"not null;index" json:"created_by_id"` // Auteur des tickets créés automatiquement
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relations
+	Filiale   *Filiale `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+	CreatedBy User     `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (AlertSource) TableName() string {
+	return "alert_sources"
+}
+
+// États d'une alerte de supervision
+const (
+	MonitoringAlertFiring   = "firing"
+	MonitoringAlertResolved = "resolved"
+)
+
+// MonitoringAlert représente une alerte reçue d'un outil de supervision et le ticket d'incident associé
+// Les notifications d'une même alerte (même empreinte) mettent à jour la même ligne
+// Table: monitoring_alerts
+type MonitoringAlert struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	SourceID        uint           `gorm:"not null;uniqueIndex:idx_monitoring_alert_fingerprint" json:"source_id"`
+	FingerprintHash string         `gorm:"type:varchar(64);not null;uniqueIndex:idx_monitoring_alert_fingerprint" json:"-"` // Hash SHA256 de l'empreinte
+	Fingerprint     string         `gorm:"type:varchar(255)" json:"fingerprint"`                                            // Empreinte fournie par l'outil (tronquée)
+	Name            string         `gorm:"type:varchar(255);not null" json:"name"`
+	Status          string         `gorm:"type:varchar(20);not null;index" json:"status"` // firing, resolved
+	Severity        string         `gorm:"type:varchar(20)" json:"severity"`              // critical, high, warning, info
+	Summary         string         `gorm:"type:text" json:"summary,omitempty"`
+	Labels          datatypes.JSON `gorm:"type:json" json:"labels,omitempty"`
+	URL             string         `gorm:"type:varchar(1000)" json:"url,omitempty"`
+	TicketID        *uint          `gorm:"index" json:"ticket_id,omitempty"`
+	AssetID         *uint          `gorm:"index" json:"asset_id,omitempty"`    // Actif reconnu d'après les étiquettes
+	SoftwareID      *uint          `gorm:"index" json:"software_id,omitempty"` // Logiciel reconnu d'après les étiquettes
+	Occurrences     int            `gorm:"default:1" json:"occurrences"`       // Nombre de déclenchements
+	StartsAt        *time.Time     `json:"starts_at,omitempty"`
+	ResolvedAt      *time.Time     `json:"resolved_at,omitempty"`
+	LastReceivedAt  time.Time      `json:"last_received_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+
+	// Relations
+	Source AlertSource `gorm:"foreignKey:SourceID;constraint:OnDelete:CASCADE" json:"-"`
+	Ticket *Ticket     `gorm:"foreignKey:TicketID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (MonitoringAlert) TableName() string {
+	return "monitoring_alerts"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// NotificationPreference représente le réglage d'un utilisateur pour un type de notification envoyé par email
+// Sans ligne pour un type, l'email est envoyé (si les notifications par email de l'utilisateur sont activées)
+// Table: notification_preferences
+type NotificationPreference struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"not null;uniqueIndex:idx_notification_preferences_user_type" json:"user_id"`
+	NotificationType string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_notification_preferences_user_type" json:"notification_type"` // ticket_created, ticket_assigned, ...
+	Email            bool      `gorm:"not null" json:"email"`                                                                                 // Envoi de ce type par email
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TableName spécifie le nom de la table
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// Problem représente un problème (extension d'un ticket) : cause commune à un ou plusieurs incidents
+// Un problème dont la cause racine et le contournement sont documentés devient une erreur connue
+// Table: problems
+type Problem struct {
+	ID                    uint       `gorm:"primaryKey" json:"id"`
+	TicketID              uint       `gorm:"uniqueIndex:idx_problems_ticket_id;not null" json:"ticket_id"` // Relation 1:1 avec Ticket
+	Impact                string     `gorm:"type:varchar(50);not null;index" json:"impact"`                // low, medium, high, critical
+	RootCause             string     `gorm:"type:text" json:"root_cause,omitempty"`                        // Cause racine identifiée (optionnel)
+	RootCauseCategory     string     `gorm:"type:varchar(50);index" json:"root_cause_category,omitempty"`  // hardware, software, network, configuration, human, process, supplier, unknown
+	RootCauseIdentifiedAt *time.Time `json:"root_cause_identified_at,omitempty"`                           // Date d'identification de la cause racine
+	Workaround            string     `gorm:"type:text" json:"workaround,omitempty"`                        // Contournement (Markdown)
+	IsKnownError          bool       `gorm:"default:false;index" json:"is_known_error"`                    // Erreur connue (cause racine et contournement documentés)
+	KnownErrorAt          *time.Time `json:"known_error_at,omitempty"`                                     // Date de passage en erreur connue
+	KnownErrorByID        *uint      `gorm:"index" json:"known_error_by_id,omitempty"`                     // Utilisateur ayant déclaré l'erreur connue
+	WorkaroundArticleID   *uint      `gorm:"index" json:"workaround_article_id,omitempty"`                 // Article de la base de connaissances publiant le contournement
+	ResolvedAt            *time.Time `json:"resolved_at,omitempty"`                                        // Date de résolution (correctif définitif)
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+
+	// Relations
+	Ticket            Ticket            `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"ticket,omitempty"`                         // Ticket associé (1:1)
+	KnownErrorBy      *User             `gorm:"foreignKey:KnownErrorByID" json:"known_error_by,omitempty"`                                       // Déclarant de l'erreur connue
+	WorkaroundArticle *KnowledgeArticle `gorm:"foreignKey:WorkaroundArticleID;constraint:OnDelete:SET NULL" json:"workaround_article,omitempty"` // Article KB du contournement
+}
+
+// TableName spécifie le nom de la table
+func (Problem) TableName() string {
+	return "problems"
+}
+
+// ProblemIncident représente la liaison entre un problème et un ticket incident
+// Table: problem_incidents
+type ProblemIncident struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ProblemID  uint      `gorm:"not null;uniqueIndex:idx_problem_incidents_pair" json:"problem_id"`
+	TicketID   uint      `gorm:"not null;uniqueIndex:idx_problem_incidents_pair;index" json:"ticket_id"` // Ticket de catégorie "incident"
+	LinkedByID *uint     `gorm:"index" json:"linked_by_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relations
+	Problem  Problem `gorm:"foreignKey:ProblemID;constraint:OnDelete:CASCADE" json:"-"`
+	Ticket   Ticket  `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"ticket,omitempty"`
+	LinkedBy *User   `gorm:"foreignKey:LinkedByID" json:"linked_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (ProblemIncident) TableName() string {
+	return "problem_incidents"
+}
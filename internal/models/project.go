@@ -10,14 +10,17 @@ type Project struct {
 	ID              uint       `gorm:"primaryKey" json:"id"`
 	Name            string     `gorm:"type:varchar(255);not null" json:"name"`
 	Description     string     `gorm:"type:text" json:"description,omitempty"`
-	TotalBudgetTime *int       `gorm:"type:int" json:"total_budget_time,omitempty"`           // Budget temps total en minutes (optionnel)
-	ConsumedTime    int        `gorm:"default:0" json:"consumed_time"`                        // Temps consommé en minutes (calculé)
+	TotalBudgetTime *int       `gorm:"type:int" json:"total_budget_time,omitempty" mask:"projects.budget.view"` // Budget temps total en minutes (optionnel, masqué sans projects.budget.view)
+	ConsumedTime    int        `gorm:"default:0" json:"consumed_time" mask:"projects.budget.view"`                        // Temps consommé en minutes (calculé, masqué sans projects.budget.view)
 	FilialeID       *uint      `gorm:"index" json:"filiale_id,omitempty"`                     // ID de la filiale (optionnel)
-	Status            string     `gorm:"type:varchar(50);default:'active';index" json:"status"` // active, completed, cancelled
+	Status            string     `gorm:"type:varchar(50);default:'active';index" json:"status"` // active, completed, cancelled, archived
 	StartDate         *time.Time `gorm:"type:date" json:"start_date,omitempty"`
 	EndDate           *time.Time `gorm:"type:date" json:"end_date,omitempty"`
 	ProjectManagerID  *uint      `gorm:"index" json:"project_manager_id,omitempty"` // Chef de projet
 	LeadID            *uint      `gorm:"index" json:"lead_id,omitempty"`            // Lead technique ou fonctionnel
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`                     // Date d'archivage (statut archived)
+	ArchivedByID      *uint      `json:"archived_by_id,omitempty"`
+	StatusBeforeArchive string   `gorm:"type:varchar(50)" json:"status_before_archive,omitempty"` // Statut restauré au désarchivage
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 	CreatedByID       *uint      `gorm:"index" json:"-"`
@@ -30,6 +33,10 @@ type Project struct {
 	Lead          *User   `gorm:"foreignKey:LeadID" json:"-"`
 }
 
+// ProjectStatusArchived projet archivé : le projet et ses éléments sont en lecture seule (sauf administrateurs)
+// et il n'apparaît plus dans la liste des projets par défaut
+const ProjectStatusArchived = "archived"
+
 // TableName spécifie le nom de la table
 func (Project) TableName() string {
 	return "projects"
@@ -10,7 +10,7 @@ import (
 type ProjectBudgetExtension struct {
 	ID                uint       `gorm:"primaryKey" json:"id"`
 	ProjectID         uint       `gorm:"not null;index" json:"project_id"`
-	AdditionalMinutes int        `gorm:"not null" json:"additional_minutes"` // Minutes ajoutées au budget
+	AdditionalMinutes int        `gorm:"not null" json:"additional_minutes" mask:"projects.budget.view"` // Minutes ajoutées au budget (masquées sans projects.budget.view)
 	Justification     string     `gorm:"type:text;not null" json:"justification"`
 	StartDate         *time.Time `gorm:"type:date" json:"start_date,omitempty"` // Début de la période de l'extension
 	EndDate           *time.Time `gorm:"type:date" json:"end_date,omitempty"`   // Fin de la période de l'extension
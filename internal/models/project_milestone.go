@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// ProjectMilestone représente un jalon de projet (livrable ou point de contrôle daté)
+// Un jalon planifié dont l'échéance est passée est en retard ; son report (nouvelle échéance plus tardive) est notifié
+// Table: project_milestones
+type ProjectMilestone struct {
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	ProjectID          uint       `gorm:"not null;index" json:"project_id"`
+	ProjectPhaseID     *uint      `gorm:"index" json:"project_phase_id,omitempty"` // Étape liée (optionnel)
+	Name               string     `gorm:"type:varchar(255);not null" json:"name"`
+	Description        string     `gorm:"type:text" json:"description,omitempty"`
+	CompletionCriteria string     `gorm:"type:text" json:"completion_criteria,omitempty"` // Critères d'atteinte (livrable attendu, validation, ...)
+	DueDate            time.Time  `gorm:"type:date;not null;index" json:"due_date"`
+	BaselineDueDate    time.Time  `gorm:"type:date;not null" json:"baseline_due_date"`                     // Échéance initiale, référence du glissement
+	Status             string     `gorm:"type:varchar(20);not null;default:'planned';index" json:"status"` // planned, achieved, cancelled
+	AchievedAt         *time.Time `json:"achieved_at,omitempty"`
+	LateAlertedAt      *time.Time `json:"-"` // Alerte de retard envoyée (remise à zéro à chaque report de l'échéance)
+	CreatedByID        uint       `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Champs calculés (non persistés), renseignés à la lecture
+	IsLate   bool `gorm:"-" json:"is_late"`   // Planifié et échéance dépassée
+	SlipDays int  `gorm:"-" json:"slip_days"` // Glissement en jours par rapport à l'échéance initiale
+
+	Project      *Project      `gorm:"foreignKey:ProjectID" json:"project,omitempty"`            // Chargé (id, nom) pour le tableau de bord
+	ProjectPhase *ProjectPhase `gorm:"foreignKey:ProjectPhaseID" json:"project_phase,omitempty"` // Chargée (id, nom)
+	CreatedBy    *User         `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (ProjectMilestone) TableName() string {
+	return "project_milestones"
+}
+
+// Statuts d'un jalon de projet
+const (
+	MilestoneStatusPlanned   = "planned"
+	MilestoneStatusAchieved  = "achieved"
+	MilestoneStatusCancelled = "cancelled"
+)
@@ -20,9 +20,15 @@ type ProjectTask struct {
 	CreatedByID     uint       `gorm:"not null;index" json:"created_by_id"`
 	EstimatedTime   *int       `gorm:"type:int" json:"estimated_time,omitempty"` // minutes
 	ActualTime      int        `gorm:"column:actual_time;default:0" json:"actual_time"` // minutes (calculé ou saisi)
+	TicketTime      int        `gorm:"column:ticket_time;default:0" json:"ticket_time"` // minutes saisies sur les tickets liés (calculé)
 	DueDate         *time.Time `gorm:"type:date" json:"due_date,omitempty"`
 	DisplayOrder    int        `gorm:"default:0" json:"display_order"`
+	BoardRank       string     `gorm:"type:varchar(255);not null;default:''" json:"board_rank"` // Ordre manuel dans la colonne du tableau (statut), indexation fractionnaire (voir boardrank)
+	IsBlocked       bool       `gorm:"default:false;index" json:"is_blocked"` // Au moins une tâche prérequise n'est pas clôturée
 	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+	RecurrenceID    *uint      `gorm:"index" json:"recurrence_id,omitempty"` // Règle de récurrence (tâche d'origine ou occurrence)
+	OccurrenceAt    *time.Time `json:"occurrence_at,omitempty"`              // Date de l'occurrence (tâches générées)
+	ExpiresAt       *time.Time `gorm:"index" json:"expires_at,omitempty"`    // Occurrence suivante : l'occurrence expire à cette date
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 
@@ -31,6 +37,8 @@ type ProjectTask struct {
 	AssignedTo   *User                   `gorm:"foreignKey:AssignedToID" json:"-"`
 	CreatedBy    *User                   `gorm:"foreignKey:CreatedByID" json:"-"`
 	Assignees    []ProjectTaskAssignee   `gorm:"foreignKey:ProjectTaskID" json:"assignees,omitempty"`
+	Dependencies []ProjectTaskDependency `gorm:"foreignKey:TaskID" json:"dependencies,omitempty"` // Tâches prérequises
+	Recurrence   *ProjectTaskRecurrence  `gorm:"foreignKey:RecurrenceID" json:"recurrence,omitempty"` // Badge de récurrence (planification, statut)
 }
 
 // TableName spécifie le nom de la table
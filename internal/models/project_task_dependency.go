@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// ProjectTaskDependency lie une tâche à une tâche prérequise du même projet
+// Tant qu'un prérequis n'est pas clôturé, la tâche dépendante est marquée bloquée (ProjectTask.IsBlocked)
+// Table: project_task_dependencies
+type ProjectTaskDependency struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TaskID      uint      `gorm:"not null;uniqueIndex:idx_task_dependency,priority:1" json:"task_id"`             // Tâche dépendante
+	DependsOnID uint      `gorm:"not null;uniqueIndex:idx_task_dependency,priority:2;index" json:"depends_on_id"` // Tâche prérequise
+	CreatedByID uint      `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	Task      *ProjectTask `gorm:"foreignKey:TaskID" json:"-"`
+	DependsOn *ProjectTask `gorm:"foreignKey:DependsOnID" json:"depends_on,omitempty"`
+	CreatedBy *User        `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTaskDependency) TableName() string {
+	return "project_task_dependencies"
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ProjectTaskRecurrence représente une règle de récurrence de tâche de projet (ex: compte rendu hebdomadaire)
+// La planification est une expression cron ou une règle RRULE, comme les tickets récurrents. Chaque occurrence est
+// créée LeadDays jours avant sa date, à partir du modèle copié de la tâche d'origine, avec la date de l'occurrence
+// pour échéance ; elle expire à la date de l'occurrence suivante (clôturée automatiquement si CloseExpired).
+// Table: project_task_recurrences
+type ProjectTaskRecurrence struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ProjectID uint   `gorm:"not null;index" json:"project_id"`
+	Schedule  string `gorm:"type:varchar(255);not null" json:"schedule"` // Expression cron ou RRULE
+	Timezone  string `gorm:"type:varchar(64)" json:"timezone,omitempty"` // Fuseau IANA (vide = fuseau par défaut)
+
+	// Modèle des tâches créées à chaque occurrence
+	ProjectPhaseID uint           `gorm:"not null;index" json:"project_phase_id"`
+	Title          string         `gorm:"type:varchar(255);not null" json:"title"`
+	Description    string         `gorm:"type:text" json:"description,omitempty"`
+	Priority       string         `gorm:"type:varchar(50);default:'medium'" json:"priority"`
+	EstimatedTime  *int           `gorm:"type:int" json:"estimated_time,omitempty"` // minutes
+	AssigneeIDs    datatypes.JSON `gorm:"type:json" json:"assignee_ids,omitempty"`
+
+	LeadDays         int        `gorm:"not null" json:"lead_days"`                 // Création des occurrences N jours avant leur date
+	CloseExpired     bool       `gorm:"not null" json:"close_expired"`             // Clôture des occurrences non terminées à l'occurrence suivante
+	IsActive         bool       `gorm:"default:true;index" json:"is_active"`       // Inactive : plus d'occurrence créée
+	StartsAt         time.Time  `json:"starts_at"`                                 // Début de la récurrence (DTSTART des règles RRULE)
+	NextOccurrenceAt *time.Time `gorm:"index" json:"next_occurrence_at,omitempty"` // Prochaine occurrence à créer (nil = récurrence terminée)
+	LastOccurrenceAt *time.Time `json:"last_occurrence_at,omitempty"`              // Dernière occurrence traitée
+	OccurrenceCount  int        `gorm:"default:0" json:"occurrence_count"`         // Occurrences passées, créées ou non (borné par COUNT d'une RRULE)
+	LastError        string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedByID      uint       `gorm:"not null" json:"created_by_id"` // Les tâches sont créées au nom du créateur de la règle
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	Project      *Project      `gorm:"foreignKey:ProjectID" json:"-"`
+	ProjectPhase *ProjectPhase `gorm:"foreignKey:ProjectPhaseID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTaskRecurrence) TableName() string {
+	return "project_task_recurrences"
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// ProjectTaskTimer représente un chronomètre en cours sur une tâche de projet
+// Un seul chronomètre par utilisateur ; à l'arrêt, le temps écoulé devient une entrée de temps (time_entries) et la ligne est supprimée.
+// Table: project_task_timers
+type ProjectTaskTimer struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;uniqueIndex" json:"user_id"` // Un seul chronomètre en cours par utilisateur
+	ProjectID     uint      `gorm:"not null;index" json:"project_id"`
+	ProjectTaskID uint      `gorm:"not null;index" json:"project_task_id"`
+	StartedAt     time.Time `gorm:"not null" json:"started_at"`
+	Description   string    `gorm:"type:text" json:"description,omitempty"` // Reprise dans l'entrée de temps (peut être remplacée à l'arrêt)
+	CreatedAt     time.Time `json:"created_at"`
+
+	Project     *Project     `gorm:"foreignKey:ProjectID" json:"-"`
+	ProjectTask *ProjectTask `gorm:"foreignKey:ProjectTaskID" json:"-"`
+	User        *User        `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTaskTimer) TableName() string {
+	return "project_task_timers"
+}
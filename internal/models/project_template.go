@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+)
+
+// ProjectTemplate représente un modèle de projet : étapes, fonctions, membres par défaut et squelette de tâches
+// instanciés à la création d'un projet (dates relatives à la date de début du projet)
+// Table: project_templates
+type ProjectTemplate struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Name         string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"name"`
+	Description  string    `gorm:"type:text" json:"description,omitempty"`
+	DurationDays *int      `gorm:"type:int" json:"duration_days,omitempty"` // Durée prévue : date de fin = début + durée - 1 (optionnel)
+	IsActive     bool      `gorm:"default:true;index" json:"is_active"`
+	CreatedByID  uint      `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	CreatedBy *User                     `gorm:"foreignKey:CreatedByID" json:"-"`
+	Phases    []ProjectTemplatePhase    `gorm:"foreignKey:TemplateID" json:"phases"`
+	Functions []ProjectTemplateFunction `gorm:"foreignKey:TemplateID" json:"functions"`
+	Members   []ProjectTemplateMember   `gorm:"foreignKey:TemplateID" json:"members"`
+	Tasks     []ProjectTemplateTask     `gorm:"foreignKey:TemplateID" json:"tasks"`
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTemplate) TableName() string {
+	return "project_templates"
+}
+
+// ProjectTemplatePhase étape d'un modèle de projet
+// Table: project_template_phases
+type ProjectTemplatePhase struct {
+	ID              uint   `gorm:"primaryKey" json:"id"`
+	TemplateID      uint   `gorm:"not null;index" json:"template_id"`
+	Name            string `gorm:"type:varchar(255);not null" json:"name"`
+	Description     string `gorm:"type:text" json:"description,omitempty"`
+	DisplayOrder    int    `gorm:"default:0" json:"display_order"`
+	StartOffsetDays int    `gorm:"default:0" json:"start_offset_days"`      // Début : jours après le début du projet
+	DurationDays    *int   `gorm:"type:int" json:"duration_days,omitempty"` // Durée de l'étape (optionnel)
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTemplatePhase) TableName() string {
+	return "project_template_phases"
+}
+
+// ProjectTemplateFunction fonction projet créée (ou reprise du catalogue, par nom) à l'instanciation
+// Table: project_template_functions
+type ProjectTemplateFunction struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	TemplateID   uint   `gorm:"not null;index" json:"template_id"`
+	Name         string `gorm:"type:varchar(100);not null" json:"name"`
+	Type         string `gorm:"column:function_type;type:varchar(20);default:execution" json:"type"` // "direction" | "execution"
+	DisplayOrder int    `gorm:"default:0" json:"display_order"`
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTemplateFunction) TableName() string {
+	return "project_template_functions"
+}
+
+// ProjectTemplateMember membre ajouté par défaut au projet, avec sa fonction et son rôle de direction
+// Table: project_template_members
+type ProjectTemplateMember struct {
+	ID               uint  `gorm:"primaryKey" json:"id"`
+	TemplateID       uint  `gorm:"not null;index" json:"template_id"`
+	UserID           *uint `gorm:"index" json:"user_id,omitempty"`     // NULL = créateur du projet
+	FunctionID       *uint `gorm:"index" json:"function_id,omitempty"` // Fonction du modèle (project_template_functions)
+	IsProjectManager bool  `gorm:"default:false" json:"is_project_manager"`
+	IsLead           bool  `gorm:"default:false" json:"is_lead"`
+
+	FunctionIndex *int `gorm:"-" json:"-"` // Rang de la fonction dans Functions (création / remplacement du contenu)
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTemplateMember) TableName() string {
+	return "project_template_members"
+}
+
+// ProjectTemplateTask squelette de tâche d'un modèle, assigné à l'instanciation aux membres de sa fonction
+// Table: project_template_tasks
+type ProjectTemplateTask struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	TemplateID    uint   `gorm:"not null;index" json:"template_id"`
+	PhaseID       uint   `gorm:"not null;index" json:"phase_id"` // Étape du modèle (project_template_phases)
+	FunctionID    *uint  `gorm:"index" json:"function_id,omitempty"`
+	Title         string `gorm:"type:varchar(255);not null" json:"title"`
+	Description   string `gorm:"type:text" json:"description,omitempty"`
+	Priority      string `gorm:"type:varchar(50);default:'medium'" json:"priority"`
+	EstimatedTime *int   `gorm:"type:int" json:"estimated_time,omitempty"`  // minutes
+	DueOffsetDays *int   `gorm:"type:int" json:"due_offset_days,omitempty"` // Échéance : jours après le début du projet (optionnel)
+	DisplayOrder  int    `gorm:"default:0" json:"display_order"`
+
+	PhaseIndex    int  `gorm:"-" json:"-"` // Rang de l'étape dans Phases (création / remplacement du contenu)
+	FunctionIndex *int `gorm:"-" json:"-"` // Rang de la fonction dans Functions
+}
+
+// TableName spécifie le nom de la table
+func (ProjectTemplateTask) TableName() string {
+	return "project_template_tasks"
+}
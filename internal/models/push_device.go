@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Fournisseurs de notifications push
+const (
+	PushProviderFCM  = "fcm"  // Firebase Cloud Messaging (Android, web)
+	PushProviderAPNs = "apns" // Apple Push Notification service (iOS)
+)
+
+// PushDevice représente un appareil mobile enregistré pour recevoir les notifications push d'un utilisateur
+// L'appareil est lié à la session depuis laquelle il a été enregistré : il est supprimé à la déconnexion
+// ou à l'expiration de cette session, et dès que le fournisseur signale le jeton comme invalide
+// Table: push_devices
+type PushDevice struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	SessionID  *uint     `gorm:"index" json:"session_id,omitempty"`               // Session d'enregistrement
+	Provider   string    `gorm:"type:varchar(10);not null" json:"provider"`       // fcm, apns
+	Platform   string    `gorm:"type:varchar(20)" json:"platform,omitempty"`      // android, ios, web
+	Token      string    `gorm:"type:varchar(512);uniqueIndex;not null" json:"-"` // Jeton d'appareil délivré par le fournisseur
+	DeviceName string    `gorm:"type:varchar(100)" json:"device_name,omitempty"`  // Nom affiché (ex: Pixel 8)
+	AppVersion string    `gorm:"type:varchar(30)" json:"app_version,omitempty"`   // Version de l'application mobile
+	LastSeenAt time.Time `json:"last_seen_at"`                                    // Dernier enregistrement du jeton
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (PushDevice) TableName() string {
+	return "push_devices"
+}
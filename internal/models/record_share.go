@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Types de ressources partageables
+const (
+	ShareResourceTicket  = "ticket"
+	ShareResourceProject = "project"
+)
+
+// Niveaux d'accès d'un partage
+const (
+	ShareAccessRead  = "read"  // Lecture seule
+	ShareAccessWrite = "write" // Lecture et modification
+)
+
+// RecordShare représente le partage d'un ticket ou d'un projet avec un utilisateur ou un département
+// Le partage s'ajoute au périmètre défini par les permissions (collaborations inter-départements)
+// Table: record_shares
+type RecordShare struct {
+	ID                     uint       `gorm:"primaryKey" json:"id"`
+	ResourceType           string     `gorm:"type:varchar(20);not null;index:idx_record_shares_resource" json:"resource_type"` // ticket ou project
+	ResourceID             uint       `gorm:"not null;index:idx_record_shares_resource" json:"resource_id"`
+	SharedWithUserID       *uint      `gorm:"index" json:"shared_with_user_id,omitempty"`                   // Utilisateur bénéficiaire
+	SharedWithDepartmentID *uint      `gorm:"index" json:"shared_with_department_id,omitempty"`             // Département bénéficiaire (tous ses membres)
+	AccessLevel            string     `gorm:"type:varchar(10);not null;default:'read'" json:"access_level"` // read ou write
+	ExpiresAt              *time.Time `gorm:"index" json:"expires_at,omitempty"`                            // Fin du partage (nil = sans expiration)
+	SharedByID             uint       `gorm:"not null;index" json:"shared_by_id"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+
+	// Relations
+	SharedWithUser       *User       `gorm:"foreignKey:SharedWithUserID" json:"shared_with_user,omitempty"`
+	SharedWithDepartment *Department `gorm:"foreignKey:SharedWithDepartmentID" json:"shared_with_department,omitempty"`
+	SharedBy             User        `gorm:"foreignKey:SharedByID" json:"shared_by"`
+}
+
+// TableName spécifie le nom de la table
+func (RecordShare) TableName() string {
+	return "record_shares"
+}
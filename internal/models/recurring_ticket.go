@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// RecurringTicket représente une planification de création automatique de tickets (ex: contrôle mensuel des sauvegardes)
+// La planification est une expression cron (5 champs ou @monthly, ...) ou une règle RRULE (FREQ=MONTHLY;BYMONTHDAY=1;BYHOUR=9)
+// Les tickets sont créés à partir du modèle (titre, description, catégorie, ...) puis assignés et rattachés au projet configurés
+// Table: recurring_tickets
+type RecurringTicket struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Name     string `gorm:"type:varchar(150);not null" json:"name"`
+	Schedule string `gorm:"type:varchar(255);not null" json:"schedule"` // Expression cron ou RRULE
+	Timezone string `gorm:"type:varchar(64)" json:"timezone,omitempty"` // Fuseau IANA de la planification (vide = fuseau par défaut)
+
+	// Modèle du ticket créé à chaque occurrence
+	Title               string         `gorm:"type:varchar(255);not null" json:"title"`
+	Description         string         `gorm:"type:text" json:"description,omitempty"`
+	Category            string         `gorm:"type:varchar(50);not null" json:"category"`
+	Priority            string         `gorm:"type:varchar(50);default:'medium'" json:"priority"`
+	EstimatedTime       *int           `json:"estimated_time,omitempty"` // En minutes
+	RequesterID         *uint          `gorm:"index" json:"requester_id,omitempty"`
+	RequesterName       string         `gorm:"type:varchar(255)" json:"requester_name,omitempty"`
+	RequesterDepartment string         `gorm:"type:varchar(100);not null" json:"requester_department"`
+	FilialeID           *uint          `gorm:"index" json:"filiale_id,omitempty"`
+	SoftwareID          *uint          `gorm:"index" json:"software_id,omitempty"`
+	AssigneeIDs         datatypes.JSON `gorm:"type:json" json:"assignee_ids,omitempty"` // IDs des utilisateurs assignés
+	LeadID              *uint          `json:"lead_id,omitempty"`                       // Responsable (parmi les assignés)
+	ProjectID           *uint          `gorm:"index" json:"project_id,omitempty"`       // Projet auquel les tickets sont rattachés
+
+	IsActive     bool       `gorm:"default:true;index" json:"is_active"`
+	StartsAt     time.Time  `json:"starts_at"`                          // Début de la récurrence (DTSTART des règles RRULE)
+	NextRunAt    *time.Time `gorm:"index" json:"next_run_at,omitempty"` // Nil = récurrence terminée
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastTicketID *uint      `json:"last_ticket_id,omitempty"`
+	RunCount     int        `gorm:"default:0" json:"run_count"` // Nombre de tickets créés (borné par COUNT d'une RRULE)
+	LastError    string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedByID  uint       `gorm:"not null" json:"created_by_id"` // Les tickets sont créés au nom du créateur de la planification
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relations
+	Filiale *Filiale `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+	Project *Project `gorm:"foreignKey:ProjectID" json:"project,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (RecurringTicket) TableName() string {
+	return "recurring_tickets"
+}
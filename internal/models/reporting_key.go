@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ReportingKey représente une clé d'API d'accès au flux OData de reporting (Power BI, Excel)
+// Seul le hash de la clé est stocké ; une clé de filiale ne voit que les données de cette filiale
+// Table: reporting_keys
+type ReportingKey struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Name          string     `gorm:"type:varchar(100);not null" json:"name"`
+	KeyHash       string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // Hash SHA256 de la clé d'API
+	KeyPrefix     string     `gorm:"type:varchar(12)" json:"key_prefix"`             // Début de la clé, pour l'identifier
+	FilialeID     *uint      `gorm:"index" json:"filiale_id,omitempty"`              // Nil = toutes les filiales
+	IncludeBudget bool       `gorm:"default:false" json:"include_budget"`            // Expose le budget et le temps consommé des projets
+	IsActive      bool       `gorm:"default:true" json:"is_active"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	CreatedByID   uint       `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relations
+	Filiale   *Filiale `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+	CreatedBy User     `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (ReportingKey) TableName() string {
+	return "reporting_keys"
+}
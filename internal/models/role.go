@@ -9,15 +9,16 @@ import (
 // Role représente un rôle utilisateur dans le système
 // Table: roles
 type Role struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"` // DSI, RESPONSABLE_IT, TECHNICIEN_IT
-	Description string         `gorm:"type:text" json:"description,omitempty"`
-	IsSystem    bool           `gorm:"default:false" json:"is_system"`       // Rôle système (ne peut pas être supprimé)
-	CreatedByID *uint          `gorm:"index" json:"created_by_id,omitempty"` // ID de l'utilisateur qui a créé le rôle (nil pour les rôles système)
-	FilialeID   *uint          `gorm:"index" json:"filiale_id,omitempty"`    // ID de la filiale à laquelle le rôle appartient (nil pour les rôles globaux)
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Name              string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"` // DSI, RESPONSABLE_IT, TECHNICIEN_IT
+	Description       string         `gorm:"type:text" json:"description,omitempty"`
+	IsSystem          bool           `gorm:"default:false" json:"is_system"`                        // Rôle système (ne peut pas être supprimé)
+	CreatedByID       *uint          `gorm:"index" json:"created_by_id,omitempty"`                  // ID de l'utilisateur qui a créé le rôle (nil pour les rôles système)
+	FilialeID         *uint          `gorm:"index" json:"filiale_id,omitempty"`                     // ID de la filiale à laquelle le rôle appartient (nil pour les rôles globaux)
+	UtilizationTarget *float64       `gorm:"type:decimal(5,2)" json:"utilization_target,omitempty"` // Taux d'utilisation facturable visé en % (optionnel)
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
 
 	// Relations
 	Users     []User   `gorm:"foreignKey:RoleID" json:"-"`      // Utilisateurs ayant ce rôle
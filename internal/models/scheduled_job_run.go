@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ScheduledJobRun représente une exécution d'une tâche planifiée (cron ou déclenchement manuel)
+// Table: scheduled_job_runs
+type ScheduledJobRun struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	JobName       string     `gorm:"type:varchar(100);not null;index" json:"job_name"` // Nom de la tâche planifiée
+	Trigger       string     `gorm:"type:varchar(20);not null" json:"trigger"`         // cron ou manual
+	TriggeredByID *uint      `gorm:"index" json:"triggered_by_id,omitempty"`           // Utilisateur ayant lancé l'exécution manuelle
+	Status        string     `gorm:"type:varchar(20);not null;index" json:"status"`    // running, success, failed
+	Error         string     `gorm:"type:text" json:"error,omitempty"`                 // Erreur retournée par la tâche
+	StartedAt     time.Time  `gorm:"index" json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	DurationMs    int64      `json:"duration_ms"`
+}
+
+// TableName spécifie le nom de la table
+func (ScheduledJobRun) TableName() string {
+	return "scheduled_job_runs"
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// Types d'éléments du catalogue de compétences
+const (
+	SkillKindSkill         = "skill"         // Compétence (ex: Active Directory, Réseau Cisco)
+	SkillKindCertification = "certification" // Certification (ex: ITIL 4 Foundation, CCNA)
+)
+
+// Niveaux de maîtrise d'une compétence
+const (
+	SkillLevelBeginner     = 1 // Débutant
+	SkillLevelIntermediate = 2 // Intermédiaire
+	SkillLevelAdvanced     = 3 // Avancé
+	SkillLevelExpert       = 4 // Expert
+)
+
+// Skill représente une compétence ou une certification du catalogue géré par les administrateurs
+// Table: skills
+type Skill struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Kind        string    `gorm:"type:varchar(20);not null;default:'skill';index" json:"kind"` // skill, certification
+	Category    string    `gorm:"type:varchar(100);index" json:"category,omitempty"`           // Regroupement libre (ex: Réseau, Développement)
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	IsActive    bool      `gorm:"default:true;index" json:"is_active"` // Inactif : plus proposé, les profils existants sont conservés
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName spécifie le nom de la table
+func (Skill) TableName() string {
+	return "skills"
+}
+
+// UserSkill représente une compétence ou une certification détenue par un utilisateur
+// Table: user_skills
+type UserSkill struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;uniqueIndex:idx_user_skill" json:"user_id"`
+	SkillID    uint       `gorm:"not null;uniqueIndex:idx_user_skill;index" json:"skill_id"`
+	Level      int        `gorm:"not null;default:1" json:"level"`              // 1 débutant, 2 intermédiaire, 3 avancé, 4 expert
+	ObtainedAt *time.Time `gorm:"type:date" json:"obtained_at,omitempty"`       // Date d'obtention (certifications)
+	ExpiresAt  *time.Time `gorm:"type:date;index" json:"expires_at,omitempty"`  // Date d'expiration (certifications)
+	Reference  string     `gorm:"type:varchar(255)" json:"reference,omitempty"` // N° de certificat ou justificatif
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relations
+	User  *User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Skill *Skill `gorm:"foreignKey:SkillID;constraint:OnDelete:CASCADE" json:"skill,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (UserSkill) TableName() string {
+	return "user_skills"
+}
+
+// IsExpired indique si la certification est expirée à la date donnée
+func (s *UserSkill) IsExpired(at time.Time) bool {
+	return s.ExpiresAt != nil && !at.Before(*s.ExpiresAt)
+}
@@ -13,6 +13,7 @@ type SLA struct {
 	TicketCategory string    `gorm:"type:varchar(50);not null;index" json:"ticket_category"` // incident, demande, changement, developpement
 	Priority       *string   `gorm:"type:varchar(50);index" json:"priority,omitempty"`       // low, medium, high, critical (nil = tous)
 	FilialeID      *uint     `gorm:"index" json:"filiale_id,omitempty"`                       // ID de la filiale (optionnel)
+	SupportLevel   *string   `gorm:"type:varchar(20);index" json:"support_level,omitempty"`  // Niveau de support du contrat couvrant le ticket (nil = tickets hors contrat)
 	TargetTime     int       `gorm:"not null" json:"target_time"`                            // Temps cible en minutes
 	Unit           string    `gorm:"type:varchar(20);default:'minutes'" json:"unit"`         // minutes, hours, days
 	IsActive       bool      `gorm:"default:true;index" json:"is_active"`                    // Si le SLA est actif
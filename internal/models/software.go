@@ -22,6 +22,7 @@ type Software struct {
 	// Relations HasMany
 	Tickets     []Ticket         `gorm:"foreignKey:SoftwareID" json:"tickets,omitempty"`
 	Deployments []FilialeSoftware `gorm:"foreignKey:SoftwareID" json:"deployments,omitempty"`
+	Licenses    []SoftwareLicense `gorm:"foreignKey:SoftwareID" json:"licenses,omitempty"` // Licences détenues par les filiales
 }
 
 // TableName spécifie le nom de la table
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// SoftwareEnvironment représente un environnement d'un logiciel chez une filiale (production, préproduction, test)
+// La version est mise à jour à la confirmation des déploiements de cet environnement
+// Table: software_environments
+type SoftwareEnvironment struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	FilialeID  uint      `gorm:"not null;uniqueIndex:idx_software_environment,priority:1" json:"filiale_id"`
+	SoftwareID uint      `gorm:"not null;uniqueIndex:idx_software_environment,priority:2;index" json:"software_id"`
+	Name       string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_software_environment,priority:3" json:"name"` // production, preproduction, test
+	Label      string    `gorm:"type:varchar(100)" json:"label,omitempty"`                                              // Libellé affiché (ex: « Recette paie »)
+	URL        string    `gorm:"type:varchar(500)" json:"url,omitempty"`                                                // Adresse d'accès
+	Version    string    `gorm:"type:varchar(50)" json:"version,omitempty"`                                             // Version en service
+	Notes      *string   `gorm:"type:text" json:"notes,omitempty"`
+	IsActive   bool      `gorm:"default:true;index" json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Filiale  *Filiale  `gorm:"foreignKey:FilialeID" json:"-"`
+	Software *Software `gorm:"foreignKey:SoftwareID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (SoftwareEnvironment) TableName() string {
+	return "software_environments"
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SoftwareLicense représente un droit d'utilisation (licence) d'un logiciel détenu par une filiale
+// Les postes couverts sont comparés aux installations inventoriées (asset_software) des actifs de la filiale
+// Table: software_licenses
+type SoftwareLicense struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	SoftwareID       uint           `gorm:"not null;index" json:"software_id"`            // Logiciel couvert
+	FilialeID        uint           `gorm:"not null;index" json:"filiale_id"`             // Filiale titulaire
+	Reference        string         `gorm:"type:varchar(100)" json:"reference,omitempty"` // Référence commande / contrat éditeur
+	LicenseKey       string         `gorm:"type:varchar(255)" json:"-"`                   // Clé de licence (exposée selon software_licenses.view_keys)
+	Seats            *int           `json:"seats,omitempty"`                              // Nombre de postes couverts (nil = illimité)
+	StartDate        *time.Time     `gorm:"type:date" json:"start_date,omitempty"`        // Début de validité
+	ExpiryDate       *time.Time     `gorm:"type:date;index" json:"expiry_date,omitempty"` // Expiration (nil = perpétuelle)
+	Notes            *string        `gorm:"type:text" json:"notes,omitempty"`
+	IsActive         bool           `gorm:"default:true;index" json:"is_active"`
+	ExpiryNotifiedAt *time.Time     `json:"-"` // Alerte d'expiration envoyée (remise à zéro si la date d'expiration change)
+	CreatedByID      uint           `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Software *Software `gorm:"foreignKey:SoftwareID" json:"-"`
+	Filiale  *Filiale  `gorm:"foreignKey:FilialeID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (SoftwareLicense) TableName() string {
+	return "software_licenses"
+}
+
+// InEffect indique si la licence est active et valide à la date at
+func (l *SoftwareLicense) InEffect(at time.Time) bool {
+	day := at.Format("2006-01-02")
+	if !l.IsActive {
+		return false
+	}
+	if l.StartDate != nil && l.StartDate.Format("2006-01-02") > day {
+		return false
+	}
+	return l.ExpiryDate == nil || l.ExpiryDate.Format("2006-01-02") >= day
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Statuts d'une version publiée (release) de logiciel
+const (
+	ReleaseStatusDraft    = "draft"    // En préparation
+	ReleaseStatusReleased = "released" // Publiée
+)
+
+// SoftwareRelease représente une version publiée d'un logiciel (notes de version, anomalies connues)
+// Les tickets corrigés par la version y sont rattachés (tickets.fixed_in_release_id)
+// Table: software_releases
+type SoftwareRelease struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	SoftwareID    uint           `gorm:"not null;uniqueIndex:idx_software_release_version,priority:1" json:"software_id"`
+	Version       string         `gorm:"type:varchar(50);not null;uniqueIndex:idx_software_release_version,priority:2" json:"version"`
+	Title         string         `gorm:"type:varchar(255)" json:"title,omitempty"`
+	Changelog     string         `gorm:"type:text" json:"changelog,omitempty"`    // Notes de version
+	KnownIssues   string         `gorm:"type:text" json:"known_issues,omitempty"` // Anomalies connues
+	ReleaseDate   *time.Time     `gorm:"type:date" json:"release_date,omitempty"` // Date de mise à disposition (prévue puis effective)
+	Status        string         `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"`
+	PublishedAt   *time.Time     `json:"published_at,omitempty"`
+	PublishedByID *uint          `gorm:"index" json:"published_by_id,omitempty"`
+	CreatedByID   uint           `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Software *Software `gorm:"foreignKey:SoftwareID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (SoftwareRelease) TableName() string {
+	return "software_releases"
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Niveaux de support d'un contrat (déterminent les SLA appliqués aux tickets couverts)
+const (
+	SupportLevelBasic    = "basic"
+	SupportLevelStandard = "standard"
+	SupportLevelPremium  = "premium"
+)
+
+// SupportContract représente un contrat de support logiciel entre la filiale fournisseur et une filiale cliente
+// Sans logiciel, le contrat couvre tous les logiciels déployés chez la filiale
+// Table: support_contracts
+type SupportContract struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Reference         string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"reference"` // Référence du contrat (ex: CTR-2026-001)
+	ProviderFilialeID uint           `gorm:"not null;index" json:"provider_filiale_id"`              // Filiale fournisseur
+	FilialeID         uint           `gorm:"not null;index" json:"filiale_id"`                       // Filiale cliente
+	SoftwareID        *uint          `gorm:"index" json:"software_id,omitempty"`                     // Logiciel couvert (nil = tous)
+	SupportLevel      string         `gorm:"type:varchar(20);not null;index" json:"support_level"`   // basic, standard, premium
+	StartDate         time.Time      `gorm:"type:date;not null" json:"start_date"`
+	EndDate           *time.Time     `gorm:"type:date" json:"end_date,omitempty"`           // Fin du contrat (nil = sans échéance)
+	RenewalDate       *time.Time     `gorm:"type:date;index" json:"renewal_date,omitempty"` // Date de renouvellement
+	Contacts          datatypes.JSON `gorm:"type:json" json:"contacts,omitempty"`           // Interlocuteurs ([{"name": ..., "role": ..., "email": ..., "phone": ...}])
+	CoveredModules    datatypes.JSON `gorm:"type:json" json:"covered_modules,omitempty"`    // Modules couverts (["Paie", ...])
+	Notes             *string        `gorm:"type:text" json:"notes,omitempty"`
+	IsActive          bool           `gorm:"default:true;index" json:"is_active"`
+	CreatedByID       uint           `gorm:"not null;index" json:"created_by_id"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+
+	ProviderFiliale *Filiale  `gorm:"foreignKey:ProviderFilialeID" json:"-"`
+	Filiale         *Filiale  `gorm:"foreignKey:FilialeID" json:"-"`
+	Software        *Software `gorm:"foreignKey:SoftwareID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (SupportContract) TableName() string {
+	return "support_contracts"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Entités suivies par la synchronisation incrémentale (application mobile)
+const (
+	SyncEntityTickets       = "tickets"
+	SyncEntityComments      = "comments"
+	SyncEntityNotifications = "notifications"
+	SyncEntityTasks         = "tasks"
+)
+
+// SyncTombstone trace la suppression définitive d'un élément sans soft delete (notifications, tâches de projet)
+// pour que les clients synchronisés puissent la répercuter
+// Table: sync_tombstones
+type SyncTombstone struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EntityType string    `gorm:"type:varchar(50);not null;index:idx_sync_tombstones_entity,priority:1" json:"entity_type"` // tickets, comments, notifications, tasks
+	EntityID   uint      `gorm:"not null" json:"entity_id"`
+	UserID     *uint     `gorm:"index" json:"user_id,omitempty"` // Destinataire (notifications) ; nil = visible selon le périmètre
+	DeletedAt  time.Time `gorm:"not null;index:idx_sync_tombstones_entity,priority:2" json:"deleted_at"`
+}
+
+// TableName spécifie le nom de la table
+func (SyncTombstone) TableName() string {
+	return "sync_tombstones"
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// TelegramLink représente la liaison d'un compte utilisateur à une conversation avec le bot Telegram
+// La liaison est en attente tant que ChatID est nul : l'utilisateur doit ouvrir le lien contenant le code
+// Table: telegram_links
+type TelegramLink struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	UserID            uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	ChatID            *int64     `gorm:"uniqueIndex" json:"-"`                                 // Conversation privée avec le bot (nil = liaison en attente)
+	TelegramUsername  string     `gorm:"type:varchar(100)" json:"telegram_username,omitempty"` // @username au moment de la liaison
+	LinkCodeHash      string     `gorm:"type:varchar(64);index" json:"-"`                      // Hash SHA256 du code de liaison
+	LinkCodeExpiresAt *time.Time `json:"-"`
+	LinkedAt          *time.Time `json:"linked_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (TelegramLink) TableName() string {
+	return "telegram_links"
+}
@@ -13,10 +13,11 @@ type Ticket struct {
 	Code           string         `gorm:"type:varchar(50);uniqueIndex" json:"code"` // Code unique: TKT-YYYY-NNNN (nullable pour migration)
 	Title          string         `gorm:"type:varchar(255);not null" json:"title"`
 	Description    string         `gorm:"type:text" json:"description"`
+	DescriptionFormat string      `gorm:"type:varchar(20);not null;default:'text'" json:"description_format"` // text, markdown, html (HTML assaini)
 	Category       string         `gorm:"type:varchar(50);not null;index" json:"category"`                // incident, demande, changement, developpement (slug pour compatibilité)
 	CategoryID     *uint          `gorm:"index" json:"category_id,omitempty"`                              // ID de la catégorie (relation optionnelle)
 	Source         string         `gorm:"type:varchar(50);not null" json:"source"`                        // mail, appel, direct
-	Status         string         `gorm:"type:varchar(50);not null;default:'ouvert';index" json:"status"` // ouvert, en_cours, en_attente, cloture
+	Status         string         `gorm:"type:varchar(50);not null;default:'ouvert';index" json:"status"` // ouvert, en_cours, en_attente, cloture, fusionne
 	Priority       string         `gorm:"type:varchar(50);default:'medium'" json:"priority"`              // low, medium, high, critical
 	AssignedToID       *uint          `gorm:"index" json:"assigned_to_id,omitempty"`                          // ID utilisateur assigné (optionnel)
 	CreatedByID        uint           `gorm:"not null;index" json:"created_by_id"`
@@ -25,12 +26,15 @@ type Ticket struct {
 	RequesterDepartment string        `gorm:"type:varchar(100)" json:"requester_department,omitempty"`         // Département du demandeur (ex: DAF)
 	FilialeID           *uint         `gorm:"index" json:"filiale_id,omitempty"`                              // ID de la filiale (optionnel)
 	SoftwareID          *uint         `gorm:"index" json:"software_id,omitempty"`                             // ID du logiciel concerné (optionnel)
+	FixedInReleaseID    *uint         `gorm:"index" json:"fixed_in_release_id,omitempty"`                     // Version du logiciel qui corrige le ticket (optionnel)
+	EnvironmentID       *uint         `gorm:"index" json:"environment_id,omitempty"`                          // Environnement où l'anomalie se produit (optionnel)
 	ValidatedByUserID   *uint         `gorm:"index" json:"validated_by_user_id,omitempty"`                     // ID de l'utilisateur qui a validé (optionnel)
 	ValidatedAt         *time.Time    `json:"validated_at,omitempty"`                                          // Date de validation (optionnel)
 	PrimaryImageID     *uint          `gorm:"index" json:"primary_image_id,omitempty"`                        // ID de l'image principale (optionnel)
 	EstimatedTime  *int           `gorm:"type:int" json:"estimated_time,omitempty"` // Temps estimé en minutes (optionnel)
 	ActualTime     *int           `gorm:"type:int" json:"actual_time,omitempty"`    // Temps réel en minutes (calculé)
 	ParentID       *uint          `gorm:"index" json:"parent_id,omitempty"`          // Ticket parent (sous-ticket)
+	MergedIntoID   *uint          `gorm:"index" json:"merged_into_id,omitempty"`     // Ticket principal dans lequel ce ticket a été fusionné (statut fusionne)
 	CreatedAt      time.Time      `gorm:"index" json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	ClosedAt       *time.Time     `json:"closed_at,omitempty"`
@@ -43,17 +47,25 @@ type Ticket struct {
 	ValidatedBy  *User             `gorm:"foreignKey:ValidatedByUserID;references:ID" json:"validated_by,omitempty"` // Utilisateur qui a validé
 	Filiale      *Filiale          `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`                      // Filiale (relation optionnelle)
 	Software     *Software         `gorm:"foreignKey:SoftwareID" json:"software,omitempty"`                     // Logiciel concerné (relation optionnelle)
+	FixedInRelease *SoftwareRelease `gorm:"foreignKey:FixedInReleaseID" json:"fixed_in_release,omitempty"`    // Version corrective (relation optionnelle)
+	Environment  *SoftwareEnvironment `gorm:"foreignKey:EnvironmentID" json:"environment,omitempty"`         // Environnement concerné (relation optionnelle)
+	JiraLink     *TicketJiraLink      `gorm:"foreignKey:TicketID" json:"jira_link,omitempty"`                // Ticket Jira synchronisé (optionnel)
 	CategoryObj  *TicketCategory   `gorm:"foreignKey:CategoryID" json:"category_obj,omitempty"`     // Catégorie (relation optionnelle)
 	PrimaryImage *TicketAttachment `gorm:"foreignKey:PrimaryImageID" json:"primary_image,omitempty"` // Image principale (optionnel)
 	Parent       *Ticket           `gorm:"foreignKey:ParentID" json:"parent,omitempty"`              // Ticket parent (optionnel)
 
 	// Relations HasMany
-	Comments    []TicketComment    `gorm:"foreignKey:TicketID" json:"comments,omitempty"`
-	History     []TicketHistory    `gorm:"foreignKey:TicketID" json:"history,omitempty"`
-	Attachments []TicketAttachment `gorm:"foreignKey:TicketID" json:"attachments,omitempty"`
-	Assignees   []TicketAssignee   `gorm:"foreignKey:TicketID" json:"assignees,omitempty"`
-	Solutions   []TicketSolution   `gorm:"foreignKey:TicketID" json:"solutions,omitempty"`
-	SubTickets  []Ticket           `gorm:"foreignKey:ParentID" json:"sub_tickets,omitempty"`
+	Comments    []TicketComment       `gorm:"foreignKey:TicketID" json:"comments,omitempty"`
+	History     []TicketHistory       `gorm:"foreignKey:TicketID" json:"history,omitempty"`
+	Attachments []TicketAttachment    `gorm:"foreignKey:TicketID" json:"attachments,omitempty"`
+	Assignees   []TicketAssignee      `gorm:"foreignKey:TicketID" json:"assignees,omitempty"`
+	Watchers    []TicketWatcher       `gorm:"foreignKey:TicketID" json:"watchers,omitempty"`
+	Checklist   []TicketChecklistItem `gorm:"foreignKey:TicketID" json:"checklist,omitempty"`
+	Solutions   []TicketSolution      `gorm:"foreignKey:TicketID" json:"solutions,omitempty"`
+	SubTickets  []Ticket              `gorm:"foreignKey:ParentID" json:"sub_tickets,omitempty"`
+	// Relations avec d'autres tickets (lues depuis le ticket source ou le ticket cible)
+	OutgoingRelations []TicketRelation `gorm:"foreignKey:SourceTicketID" json:"-"`
+	IncomingRelations []TicketRelation `gorm:"foreignKey:TargetTicketID" json:"-"`
 	// TimeEntries []TimeEntry `gorm:"foreignKey:TicketID" json:"-"`
 }
 
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// TicketChecklistItem représente une étape de résolution d'un ticket (checklist légère, sans sous-ticket)
+// Table: ticket_checklist_items
+type TicketChecklistItem struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TicketID     uint       `gorm:"not null;index" json:"ticket_id"`
+	Label        string     `gorm:"type:varchar(255);not null" json:"label"`
+	Done         bool       `gorm:"default:false" json:"done"`
+	AssigneeID   *uint      `gorm:"index" json:"assignee_id,omitempty"`
+	DisplayOrder int        `gorm:"default:0" json:"display_order"`
+	DoneAt       *time.Time `json:"done_at,omitempty"`
+	DoneByID     *uint      `json:"done_by_id,omitempty"`
+	CreatedByID  uint       `gorm:"not null" json:"created_by_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relations
+	Ticket   Ticket `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"-"`
+	Assignee *User  `gorm:"foreignKey:AssigneeID" json:"assignee,omitempty"`
+	DoneBy   *User  `gorm:"foreignKey:DoneByID" json:"done_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketChecklistItem) TableName() string {
+	return "ticket_checklist_items"
+}
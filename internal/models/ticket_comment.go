@@ -13,7 +13,8 @@ type TicketComment struct {
 	TicketID   uint           `gorm:"not null;index" json:"ticket_id"`
 	UserID     uint           `gorm:"not null;index" json:"user_id"`
 	Comment    string         `gorm:"type:text;not null" json:"comment"`
-	IsInternal bool           `gorm:"default:false" json:"is_internal"` // Commentaire interne (visible uniquement par l'IT)
+	Format     string         `gorm:"type:varchar(20);not null;default:'text'" json:"format"` // text, markdown, html (HTML assaini)
+	IsInternal bool           `gorm:"default:false" json:"is_internal"`                       // Commentaire interne (visible uniquement par l'IT)
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// TicketGitLink représente un commit ou une merge request GitHub / GitLab citant le code d'un ticket
+// Un même commit poussé sur plusieurs branches n'est lié qu'une fois
+// Table: ticket_git_links
+type TicketGitLink struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TicketID   uint      `gorm:"not null;uniqueIndex:idx_ticket_git_link,priority:1" json:"ticket_id"`
+	Provider   string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_ticket_git_link,priority:2" json:"provider"`    // github, gitlab
+	Repository string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_ticket_git_link,priority:3" json:"repository"` // Chemin du dépôt (ex: groupe/projet)
+	Kind       string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_ticket_git_link,priority:4" json:"kind"`        // commit, merge_request
+	Ref        string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_ticket_git_link,priority:5" json:"ref"`         // SHA du commit ou numéro de la merge request
+	Title      string    `gorm:"type:varchar(500)" json:"title"`                                                          // Première ligne du message ou titre
+	URL        string    `gorm:"type:varchar(1000)" json:"url,omitempty"`
+	AuthorName string    `gorm:"type:varchar(255)" json:"author_name,omitempty"`
+	State      string    `gorm:"type:varchar(20)" json:"state,omitempty"` // opened, merged, closed (merge requests uniquement)
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relations
+	Ticket Ticket `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketGitLink) TableName() string {
+	return "ticket_git_links"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// TicketProjectTask représente le lien entre un ticket et une tâche de projet (travail de support rattaché à la tâche)
+// Un ticket est lié à au plus une tâche par projet ; le temps saisi sur le ticket est reporté dans ProjectTask.TicketTime.
+// Table: ticket_project_tasks
+type TicketProjectTask struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TicketID      uint      `gorm:"not null;uniqueIndex:idx_ticket_project_tasks_ticket_project,priority:1" json:"ticket_id"`
+	ProjectID     uint      `gorm:"not null;uniqueIndex:idx_ticket_project_tasks_ticket_project,priority:2;index" json:"project_id"`
+	ProjectTaskID uint      `gorm:"not null;index" json:"project_task_id"`
+	CreatedByID   uint      `gorm:"not null" json:"created_by_id"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	Ticket      *Ticket      `gorm:"foreignKey:TicketID" json:"-"`
+	Project     *Project     `gorm:"foreignKey:ProjectID" json:"-"`
+	ProjectTask *ProjectTask `gorm:"foreignKey:ProjectTaskID" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketProjectTask) TableName() string {
+	return "ticket_project_tasks"
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Types de relations entre tickets (du point de vue du ticket source)
+const (
+	TicketRelationRelatesTo  = "relates_to"
+	TicketRelationBlocks     = "blocks"
+	TicketRelationBlockedBy  = "blocked_by" // Enregistrée comme « blocks » dans l'autre sens
+	TicketRelationCausedBy   = "caused_by"  // Ex : incident causé par un problème ou un changement
+	TicketRelationDuplicates = "duplicates"
+)
+
+// Types des relations vues depuis le ticket cible (sans création directe)
+const (
+	TicketRelationCauses       = "causes"
+	TicketRelationDuplicatedBy = "duplicated_by"
+)
+
+// TicketRelationInverse retourne le type d'une relation vue depuis son ticket cible
+func TicketRelationInverse(relationType string) string {
+	switch relationType {
+	case TicketRelationBlocks:
+		return TicketRelationBlockedBy
+	case TicketRelationBlockedBy:
+		return TicketRelationBlocks
+	case TicketRelationCausedBy:
+		return TicketRelationCauses
+	case TicketRelationDuplicates:
+		return TicketRelationDuplicatedBy
+	default:
+		return relationType
+	}
+}
+
+// TicketRelation représente un lien entre deux tickets, en plus de la hiérarchie parent / sous-tickets
+// Une seule relation par paire de tickets, quel que soit le sens
+// Table: ticket_relations
+type TicketRelation struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	SourceTicketID uint      `gorm:"not null;uniqueIndex:idx_ticket_relation" json:"source_ticket_id"`
+	TargetTicketID uint      `gorm:"not null;uniqueIndex:idx_ticket_relation;index" json:"target_ticket_id"`
+	Type           string    `gorm:"type:varchar(30);not null" json:"type"` // relates_to, blocks, caused_by, duplicates
+	CreatedByID    uint      `gorm:"not null" json:"created_by_id"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relations
+	SourceTicket *Ticket `gorm:"foreignKey:SourceTicketID" json:"source_ticket,omitempty"`
+	TargetTicket *Ticket `gorm:"foreignKey:TargetTicketID" json:"target_ticket,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketRelation) TableName() string {
+	return "ticket_relations"
+}
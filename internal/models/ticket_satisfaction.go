@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// TicketSatisfaction représente l'enquête de satisfaction (CSAT) envoyée au demandeur à la clôture d'un ticket
+// Le lien de l'enquête porte un token dont seul le hash est stocké ; une seule réponse par ticket
+// Table: ticket_satisfactions
+type TicketSatisfaction struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	TicketID    uint       `gorm:"not null;uniqueIndex" json:"ticket_id"`
+	RequesterID uint       `gorm:"not null;index" json:"requester_id"`             // Destinataire de l'enquête (demandeur, à défaut créateur du ticket)
+	AgentID     *uint      `gorm:"index" json:"agent_id,omitempty"`                // Responsable du ticket à la clôture (évalué)
+	FilialeID   *uint      `gorm:"index" json:"filiale_id,omitempty"`              // Filiale du ticket à la clôture
+	TokenHash   string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // Hash SHA256 du token
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`                     // Date d'expiration du lien
+	Rating      *int       `gorm:"type:int" json:"rating,omitempty"`               // Note de 1 à 5 (absente tant que l'enquête n'a pas de réponse)
+	Comment     string     `gorm:"type:text" json:"comment,omitempty"`
+	AnsweredAt  *time.Time `gorm:"index" json:"answered_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Relations
+	Ticket Ticket `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketSatisfaction) TableName() string {
+	return "ticket_satisfactions"
+}
@@ -13,6 +13,7 @@ type TicketSolution struct {
 	TicketID    uint           `gorm:"not null;index" json:"ticket_id"`
 	Solution    string         `gorm:"type:text;not null" json:"solution"` // Solution documentée (Markdown)
 	CreatedByID uint           `gorm:"not null;index" json:"created_by_id"`
+	ArticleID   *uint          `gorm:"index" json:"article_id,omitempty"` // Article de la base de connaissances rédigé à partir de la solution
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
@@ -20,6 +21,7 @@ type TicketSolution struct {
 	// Relations
 	Ticket   Ticket `gorm:"foreignKey:TicketID" json:"ticket,omitempty"`
 	CreatedBy User  `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+	Article   *KnowledgeArticle `gorm:"foreignKey:ArticleID;constraint:OnDelete:SET NULL" json:"-"`
 }
 
 // TableName spécifie le nom de la table
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TicketView représente une vue enregistrée de la liste des tickets (filtre nommé)
+// Une vue appartient à son créateur ; partagée, elle est visible par tous les utilisateurs
+// Table: ticket_views
+type TicketView struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"type:varchar(150);not null" json:"name"`
+	Description string         `gorm:"type:text" json:"description,omitempty"`
+	UserID      uint           `gorm:"not null;index" json:"user_id"` // Propriétaire
+	IsShared    bool           `gorm:"default:false;index" json:"is_shared"`
+	Filters     datatypes.JSON `gorm:"type:json" json:"filters"` // Définition du filtre (statuts, priorités, filiale, assigné, période, texte)
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketView) TableName() string {
+	return "ticket_views"
+}
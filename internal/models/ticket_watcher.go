@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TicketWatcher représente un utilisateur qui suit un ticket (notifié comme le demandeur des changements de statut et des commentaires)
+// Table: ticket_watchers
+type TicketWatcher struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TicketID  uint      `gorm:"not null;uniqueIndex:idx_ticket_watcher" json:"ticket_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_ticket_watcher;index" json:"user_id"`
+	AddedByID uint      `gorm:"not null" json:"added_by_id"` // Utilisateur à l'origine de l'abonnement (lui-même ou un tiers)
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Ticket Ticket `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE" json:"-"`
+	User   User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (TicketWatcher) TableName() string {
+	return "ticket_watchers"
+}
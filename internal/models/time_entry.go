@@ -17,6 +17,8 @@ type TimeEntry struct {
 	TimeSpent      int            `gorm:"not null" json:"time_spent"`
 	Date           time.Time      `gorm:"type:date;not null;index" json:"date"`
 	Description    string         `gorm:"type:text" json:"description,omitempty"`
+	WorkCategory   string         `gorm:"type:varchar(20);not null;default:'support';index" json:"work_category"` // support, project, internal
+	Billable       bool           `gorm:"not null;default:true;index" json:"billable"`                           // Temps facturable (par défaut selon la catégorie)
 	Validated      bool           `gorm:"default:false;index" json:"validated"`
 	ValidatedByID  *uint          `gorm:"index" json:"validated_by_id,omitempty"`
 	ValidatedAt    *time.Time     `json:"validated_at,omitempty"`
@@ -36,3 +38,24 @@ type TimeEntry struct {
 func (TimeEntry) TableName() string {
 	return "time_entries"
 }
+
+// Catégories de travail d'une entrée de temps
+const (
+	WorkCategorySupport  = "support"  // Traitement des tickets
+	WorkCategoryProject  = "project"  // Tâches de projet (ou tickets liés à une tâche)
+	WorkCategoryInternal = "internal" // Travail interne (tickets internes, organisation)
+)
+
+// IsValidWorkCategory indique si la catégorie de travail est connue
+func IsValidWorkCategory(category string) bool {
+	switch category {
+	case WorkCategorySupport, WorkCategoryProject, WorkCategoryInternal:
+		return true
+	}
+	return false
+}
+
+// DefaultBillable indique si le temps d'une catégorie est facturable par défaut (tout sauf le travail interne)
+func DefaultBillable(category string) bool {
+	return category != WorkCategoryInternal
+}
@@ -9,22 +9,27 @@ import (
 // User représente un utilisateur du système
 // Table: users
 type User struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Username     string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"username"`
-	Email        string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Phone        string         `gorm:"type:varchar(20)" json:"phone,omitempty"` // Numéro de téléphone
-	PasswordHash string         `gorm:"type:varchar(255);not null" json:"-"`     // Mot de passe hashé (non exposé dans JSON)
-	FirstName    string         `gorm:"type:varchar(100)" json:"first_name,omitempty"`
-	LastName     string         `gorm:"type:varchar(100)" json:"last_name,omitempty"`
-	DepartmentID *uint          `gorm:"index" json:"department_id,omitempty"`      // ID du département (optionnel)
-	FilialeID    *uint          `gorm:"index" json:"filiale_id,omitempty"`         // ID de la filiale (optionnel)
-	Avatar       string         `gorm:"type:varchar(500)" json:"avatar,omitempty"` // Chemin vers la photo de profil
-	RoleID       uint           `gorm:"not null;index" json:"role_id"`
-	IsActive     bool           `gorm:"default:true;index" json:"is_active"`
-	LastLogin    *time.Time     `json:"last_login,omitempty"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	Username           string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"username"`
+	Email              string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Phone              string         `gorm:"type:varchar(20)" json:"phone,omitempty" mask:"users.view_phone,owner=ID"` // Numéro de téléphone (masqué sans users.view_phone, sauf pour soi-même)
+	PasswordHash       string         `gorm:"type:varchar(255);not null" json:"-"`                                      // Mot de passe hashé (non exposé dans JSON)
+	FirstName          string         `gorm:"type:varchar(100)" json:"first_name,omitempty"`
+	LastName           string         `gorm:"type:varchar(100)" json:"last_name,omitempty"`
+	DepartmentID       *uint          `gorm:"index" json:"department_id,omitempty"`                     // ID du département (optionnel)
+	FilialeID          *uint          `gorm:"index" json:"filiale_id,omitempty"`                        // ID de la filiale (optionnel)
+	ManagerID          *uint          `gorm:"index" json:"manager_id,omitempty"`                        // Responsable hiérarchique (N+1, optionnel)
+	OutOfOfficeFrom    *time.Time     `gorm:"index" json:"out_of_office_from,omitempty"`                // Début de l'absence (optionnel)
+	OutOfOfficeUntil   *time.Time     `gorm:"index" json:"out_of_office_until,omitempty"`               // Fin de l'absence (optionnel)
+	BackupUserID       *uint          `gorm:"index" json:"backup_user_id,omitempty"`                    // Suppléant pendant l'absence (optionnel)
+	OutOfOfficeMessage string         `gorm:"type:varchar(255)" json:"out_of_office_message,omitempty"` // Message d'absence (optionnel)
+	Avatar             string         `gorm:"type:varchar(500)" json:"avatar,omitempty"`                // Chemin vers la photo de profil
+	RoleID             uint           `gorm:"not null;index" json:"role_id"`
+	IsActive           bool           `gorm:"default:true;index" json:"is_active"`
+	LastLogin          *time.Time     `json:"last_login,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
 
 	// Relations
 	Role       Role        `gorm:"foreignKey:RoleID" json:"role,omitempty"`             // Rôle de l'utilisateur
@@ -47,3 +52,9 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// IsOutOfOffice indique si l'utilisateur est absent à la date donnée
+func (u *User) IsOutOfOffice(at time.Time) bool {
+	return u.OutOfOfficeFrom != nil && u.OutOfOfficeUntil != nil &&
+		!at.Before(*u.OutOfOfficeFrom) && at.Before(*u.OutOfOfficeUntil)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserInvitation représente une invitation à activer un compte créé par un administrateur (import en masse)
+// Le lien d'invitation porte un token à usage unique dont seul le hash est stocké
+// Table: user_invitations
+type UserInvitation struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`                  // Compte invité
+	TokenHash   string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // Hash SHA256 du token
+	ExpiresAt   time.Time  `gorm:"not null;index" json:"expires_at"`               // Date d'expiration du lien
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`                          // Date d'activation du compte
+	CreatedByID *uint      `gorm:"index" json:"created_by_id,omitempty"`           // Utilisateur à l'origine de l'invitation
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (UserInvitation) TableName() string {
+	return "user_invitations"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Fréquences du récapitulatif des notifications
+const (
+	DigestFrequencyNone   = "none"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
+// UserPreference représente les préférences personnelles d'un utilisateur (langue, fuseau horaire, notifications)
+// Une valeur vide signifie « comportement par défaut » (langue du navigateur, fuseau de la filiale, ...)
+// Table: user_preferences
+type UserPreference struct {
+	ID                     uint           `gorm:"primaryKey" json:"id"`
+	UserID                 uint           `gorm:"not null;uniqueIndex" json:"user_id"`
+	Language               string         `gorm:"type:varchar(5)" json:"language"`                         // fr, en (vide = Accept-Language)
+	Timezone               string         `gorm:"type:varchar(64)" json:"timezone"`                        // Fuseau IANA, ex: Africa/Abidjan (vide = défaut)
+	DateFormat             string         `gorm:"type:varchar(20)" json:"date_format"`                     // DD/MM/YYYY, MM/DD/YYYY, YYYY-MM-DD
+	DefaultDashboard       string         `gorm:"type:varchar(20)" json:"default_dashboard"`               // own, department, filiale, global (vide = selon permissions)
+	EmailNotifications     bool           `gorm:"not null" json:"email_notifications"`                     // Envoi des notifications par email
+	DigestFrequency        string         `gorm:"type:varchar(20);default:'none'" json:"digest_frequency"` // none, daily, weekly
+	MutedNotificationTypes datatypes.JSON `gorm:"type:json" json:"muted_notification_types,omitempty"`     // Types de notifications désactivés (["ticket_commented", ...])
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (UserPreference) TableName() string {
+	return "user_preferences"
+}
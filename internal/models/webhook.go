@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// WebhookSubscription représente un abonnement d'un système externe aux événements de l'application
+// Table: webhook_subscriptions
+type WebhookSubscription struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"type:varchar(150);not null" json:"name"`
+	URL         string         `gorm:"type:varchar(500);not null" json:"url"` // URL de destination (POST)
+	Secret      string         `gorm:"type:varchar(128);not null" json:"-"`   // Secret partagé pour la signature HMAC
+	EventTypes  datatypes.JSON `gorm:"type:json" json:"event_types"`          // Liste des événements (["ticket.created", ...] ou ["*"])
+	FilialeID   *uint          `gorm:"index" json:"filiale_id,omitempty"`     // Filtre optionnel : événements de cette filiale uniquement
+	IsActive    bool           `gorm:"default:true;index" json:"is_active"`   // Abonnement actif
+	CreatedByID *uint          `gorm:"index" json:"created_by_id,omitempty"`  // Utilisateur ayant créé l'abonnement
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+
+	// Relations
+	Filiale *Filiale `gorm:"foreignKey:FilialeID" json:"filiale,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery représente une tentative d'envoi d'un événement à un abonnement (journal de livraison)
+// Table: webhook_deliveries
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint       `gorm:"not null;index" json:"subscription_id"`
+	EventID        string     `gorm:"type:varchar(64);not null;index" json:"event_id"`    // Identifiant unique de l'événement
+	EventType      string     `gorm:"type:varchar(100);not null;index" json:"event_type"` // Type d'événement (ticket.created, ...)
+	Payload        string     `gorm:"type:longtext;not null" json:"payload"`              // Corps JSON envoyé
+	Status         string     `gorm:"type:varchar(20);not null;index" json:"status"`      // pending, sending, retrying, success, failed
+	Attempts       int        `gorm:"default:0" json:"attempts"`                          // Nombre de tentatives effectuées
+	ResponseStatus int        `json:"response_status,omitempty"`                          // Code HTTP de la dernière réponse
+	ResponseBody   string     `gorm:"type:text" json:"response_body,omitempty"`           // Début du corps de la dernière réponse
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`              // Dernière erreur réseau ou HTTP
+	NextAttemptAt  *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`             // Prochaine tentative planifiée
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`                             // Date de livraison réussie
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relations
+	Subscription WebhookSubscription `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -13,7 +13,9 @@ type WeeklyDeclaration struct {
 	EndDate           time.Time      `gorm:"type:date;not null" json:"end_date"`           // Date de fin de la semaine
 	TaskCount         int            `gorm:"default:0" json:"task_count"`                  // Nombre total de tâches
 	TotalTime         int            `gorm:"default:0" json:"total_time"`                  // Temps total en minutes
-	Validated         bool           `gorm:"default:false;index" json:"validated"`         // Si la déclaration a été validée
+	Status            string         `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"` // draft, submitted, approved (semaine verrouillée), rejected
+	SubmittedAt       *time.Time     `json:"submitted_at,omitempty"`                       // Date de la dernière soumission (optionnel)
+	Validated         bool           `gorm:"default:false;index" json:"validated"`         // Si la déclaration a été validée (statut approved)
 	ValidatedByID     *uint          `gorm:"index" json:"validated_by_id,omitempty"`       // ID du validateur (optionnel)
 	ValidatedAt       *time.Time     `json:"validated_at,omitempty"`                       // Date de validation (optionnel)
 	ValidationComment string         `gorm:"type:text" json:"validation_comment,omitempty"` // Commentaire de validation (optionnel)
@@ -32,6 +34,36 @@ func (WeeklyDeclaration) TableName() string {
 	return "weekly_declarations"
 }
 
+// Statuts du circuit de validation d'une déclaration hebdomadaire : une déclaration approuvée verrouille
+// les entrées de temps de la semaine jusqu'à son déverrouillage (retour en brouillon)
+const (
+	WeeklyDeclarationStatusDraft     = "draft"
+	WeeklyDeclarationStatusSubmitted = "submitted"
+	WeeklyDeclarationStatusApproved  = "approved"
+	WeeklyDeclarationStatusRejected  = "rejected"
+)
+
+// WeeklyDeclarationEvent représente un changement de statut d'une déclaration hebdomadaire (piste d'audit)
+// Table: weekly_declaration_events
+type WeeklyDeclarationEvent struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	DeclarationID uint      `gorm:"not null;index" json:"declaration_id"`
+	FromStatus    string    `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus      string    `gorm:"type:varchar(20);not null" json:"to_status"`
+	Comment       string    `gorm:"type:text" json:"comment,omitempty"` // Motif du rejet ou du déverrouillage, commentaire de validation
+	PerformedByID uint      `gorm:"not null;index" json:"performed_by_id"` // Auteur du changement
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+
+	// Relations
+	Declaration WeeklyDeclaration `gorm:"foreignKey:DeclarationID;constraint:OnDelete:CASCADE" json:"-"`
+	PerformedBy User              `gorm:"foreignKey:PerformedByID" json:"performed_by,omitempty"`
+}
+
+// TableName spécifie le nom de la table
+func (WeeklyDeclarationEvent) TableName() string {
+	return "weekly_declaration_events"
+}
+
 // WeeklyDeclarationTask représente une tâche déclarée dans une déclaration hebdomadaire
 // Table: weekly_declaration_tasks
 type WeeklyDeclarationTask struct {
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Champs de notification utilisables comme variables d'un modèle WhatsApp, dans l'ordre des paramètres {{1}}, {{2}}, ...
+const (
+	WhatsAppFieldTitle      = "title"       // Titre de la notification
+	WhatsAppFieldMessage    = "message"     // Message de la notification
+	WhatsAppFieldTicketCode = "ticket_code" // Code du ticket concerné
+)
+
+// WhatsAppTemplate représente le modèle de message WhatsApp (approuvé par Meta) utilisé pour un type de notification critique
+// Un type sans modèle actif n'est pas envoyé sur WhatsApp
+// Table: whatsapp_templates
+type WhatsAppTemplate struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	NotificationType string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"notification_type"` // sla_violated, major_incident
+	Name             string         `gorm:"type:varchar(512);not null" json:"name"`                         // Nom du modèle dans WhatsApp Manager
+	Language         string         `gorm:"type:varchar(15);not null;default:'fr'" json:"language"`         // Code de langue du modèle (fr, en_US, ...)
+	Parameters       datatypes.JSON `gorm:"type:json" json:"parameters"`                                    // Champs transmis comme variables du corps (["title", "ticket_code"])
+	IsActive         bool           `gorm:"default:true" json:"is_active"`
+	CreatedByID      *uint          `json:"created_by_id,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// TableName spécifie le nom de la table
+func (WhatsAppTemplate) TableName() string {
+	return "whatsapp_templates"
+}
+
+// WhatsAppOptIn représente le consentement d'un utilisateur à recevoir les notifications critiques sur WhatsApp
+// Table: whatsapp_opt_ins
+type WhatsAppOptIn struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex" json:"user_id"`
+	PhoneNumber string    `gorm:"type:varchar(20);not null" json:"phone_number"` // Format E.164 (+2250700000000)
+	OptedInAt   time.Time `json:"opted_in_at"`                                   // Date du consentement
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName spécifie le nom de la table
+func (WhatsAppOptIn) TableName() string {
+	return "whatsapp_opt_ins"
+}
@@ -0,0 +1,383 @@
+// Package odata implémente le sous-ensemble d'OData v4 utilisé par les outils de reporting (Power BI, Excel) :
+// document de service, $metadata (CSDL), options de requête $top, $skip, $select, $filter, $orderby et $count
+package odata
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types EDM des propriétés exposées
+const (
+	TypeInt64          = "Edm.Int64"
+	TypeInt32          = "Edm.Int32"
+	TypeString         = "Edm.String"
+	TypeBoolean        = "Edm.Boolean"
+	TypeDate           = "Edm.Date"
+	TypeDateTimeOffset = "Edm.DateTimeOffset"
+)
+
+// Property propriété d'un type d'entité et expression SQL correspondante
+type Property struct {
+	Name       string // Nom exposé (ex: CreatedAt)
+	Type       string // Type EDM
+	Column     string // Expression SQL (ex: t.created_at)
+	Nullable   bool
+	Restricted bool // Valeur masquée (null) pour les clés sans accès aux données sensibles
+}
+
+// EntitySet ensemble d'entités exposé et requête SQL de base
+type EntitySet struct {
+	Name          string   // Nom de l'ensemble (ex: Tickets)
+	Type          string   // Nom du type d'entité (ex: Ticket)
+	Table         string   // Table principale et alias (ex: tickets t)
+	Joins         []string // Jointures nécessaires aux propriétés
+	Where         string   // Condition de base (ex: t.deleted_at IS NULL), optionnelle
+	FilialeColumn string   // Colonne de filiale utilisée pour limiter une clé à sa filiale
+	Key           string   // Propriété clé
+	Properties    []Property
+}
+
+// Property retourne la propriété portant ce nom (nil si inconnue)
+func (s *EntitySet) Property(name string) *Property {
+	for i := range s.Properties {
+		if s.Properties[i].Name == name {
+			return &s.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Condition comparaison d'une propriété à une valeur ($filter)
+type Condition struct {
+	Property *Property
+	Operator string      // Opérateur SQL (=, <>, >, >=, <, <=)
+	Value    interface{} // Nil pour une comparaison à null
+}
+
+// Order tri sur une propriété ($orderby)
+type Order struct {
+	Property *Property
+	Desc     bool
+}
+
+// Query options de requête d'un ensemble d'entités
+type Query struct {
+	Top     int // -1 si absent
+	Skip    int
+	Select  []*Property // Vide = toutes les propriétés
+	Filter  []Condition // Conditions combinées par and
+	OrderBy []Order
+	Count   bool
+}
+
+// operators opérateurs de comparaison OData et équivalent SQL
+var operators = map[string]string{
+	"eq": "=",
+	"ne": "<>",
+	"gt": ">",
+	"ge": ">=",
+	"lt": "<",
+	"le": "<=",
+}
+
+// filterToken élément d'une expression $filter : identifiant, mot-clé ou littéral
+var filterToken = regexp.MustCompile(`\s*('(?:[^']|'')*'|[^\s']+)`)
+
+// ParseQuery lit les options de requête en les validant contre les propriétés de l'ensemble
+func ParseQuery(set *EntitySet, values url.Values) (Query, error) {
+	query := Query{Top: -1}
+
+	if value := values.Get("$top"); value != "" {
+		top, err := strconv.Atoi(value)
+		if err != nil || top < 0 {
+			return query, errors.New("$top invalide")
+		}
+		query.Top = top
+	}
+	if value := values.Get("$skip"); value != "" {
+		skip, err := strconv.Atoi(value)
+		if err != nil || skip < 0 {
+			return query, errors.New("$skip invalide")
+		}
+		query.Skip = skip
+	}
+	if value := values.Get("$count"); value != "" {
+		count, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, errors.New("$count invalide")
+		}
+		query.Count = count
+	}
+
+	if value := strings.TrimSpace(values.Get("$select")); value != "" && value != "*" {
+		for _, name := range strings.Split(value, ",") {
+			prop := set.Property(strings.TrimSpace(name))
+			if prop == nil {
+				return query, fmt.Errorf("propriété inconnue dans $select: %s", strings.TrimSpace(name))
+			}
+			query.Select = append(query.Select, prop)
+		}
+	}
+
+	if value := strings.TrimSpace(values.Get("$orderby")); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			fields := strings.Fields(item)
+			if len(fields) == 0 || len(fields) > 2 {
+				return query, errors.New("$orderby invalide")
+			}
+			prop := set.Property(fields[0])
+			if prop == nil {
+				return query, fmt.Errorf("propriété inconnue dans $orderby: %s", fields[0])
+			}
+			order := Order{Property: prop}
+			if len(fields) == 2 {
+				switch strings.ToLower(fields[1]) {
+				case "asc":
+				case "desc":
+					order.Desc = true
+				default:
+					return query, errors.New("$orderby invalide")
+				}
+			}
+			query.OrderBy = append(query.OrderBy, order)
+		}
+	}
+
+	if value := strings.TrimSpace(values.Get("$filter")); value != "" {
+		filter, err := parseFilter(set, value)
+		if err != nil {
+			return query, err
+		}
+		query.Filter = filter
+	}
+	return query, nil
+}
+
+// parseFilter lit une expression « Propriété opérateur littéral » éventuellement combinée par and
+func parseFilter(set *EntitySet, expr string) ([]Condition, error) {
+	var tokens []string
+	for _, match := range filterToken.FindAllStringSubmatch(expr, -1) {
+		tokens = append(tokens, match[1])
+	}
+
+	var conditions []Condition
+	for i := 0; i < len(tokens); {
+		if i > 0 {
+			if !strings.EqualFold(tokens[i], "and") {
+				return nil, errors.New("$filter non pris en charge: seules les comparaisons combinées par and sont acceptées")
+			}
+			i++
+		}
+		if i+3 > len(tokens) {
+			return nil, errors.New("$filter incomplet")
+		}
+		prop := set.Property(tokens[i])
+		if prop == nil {
+			return nil, fmt.Errorf("propriété inconnue dans $filter: %s", tokens[i])
+		}
+		op, ok := operators[strings.ToLower(tokens[i+1])]
+		if !ok {
+			return nil, fmt.Errorf("opérateur non pris en charge dans $filter: %s", tokens[i+1])
+		}
+		value, err := parseLiteral(prop, tokens[i+2])
+		if err != nil {
+			return nil, err
+		}
+		if value == nil && op != "=" && op != "<>" {
+			return nil, errors.New("null ne peut être comparé qu'avec eq ou ne")
+		}
+		conditions = append(conditions, Condition{Property: prop, Operator: op, Value: value})
+		i += 3
+	}
+	return conditions, nil
+}
+
+// parseLiteral convertit un littéral OData selon le type de la propriété comparée
+func parseLiteral(prop *Property, literal string) (interface{}, error) {
+	if literal == "null" {
+		return nil, nil
+	}
+	invalid := fmt.Errorf("valeur invalide pour %s dans $filter: %s", prop.Name, literal)
+	switch prop.Type {
+	case TypeString:
+		if len(literal) < 2 || !strings.HasPrefix(literal, "'") || !strings.HasSuffix(literal, "'") {
+			return nil, invalid
+		}
+		return strings.ReplaceAll(literal[1:len(literal)-1], "''", "'"), nil
+	case TypeInt64, TypeInt32:
+		value, err := strconv.ParseInt(literal, 10, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case TypeBoolean:
+		value, err := strconv.ParseBool(literal)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case TypeDate:
+		value, err := time.ParseInLocation("2006-01-02", literal, time.Local)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case TypeDateTimeOffset:
+		if value, err := time.Parse(time.RFC3339Nano, literal); err == nil {
+			return value, nil
+		}
+		if value, err := time.ParseInLocation("2006-01-02", literal, time.Local); err == nil {
+			return value, nil
+		}
+		return nil, invalid
+	}
+	return nil, invalid
+}
+
+// Limit nombre d'entités à retourner dans la page, borné par la taille de page du serveur
+func (q Query) Limit(pageSize int) int {
+	if q.Top >= 0 && q.Top < pageSize {
+		return q.Top
+	}
+	return pageSize
+}
+
+// NextLink construit le lien de la page suivante (pagination côté serveur) ; top < 0 si $top est absent
+func NextLink(current *url.URL, skip, top int) string {
+	next := *current
+	values := next.Query()
+	values.Set("$skip", strconv.Itoa(skip))
+	if top >= 0 {
+		values.Set("$top", strconv.Itoa(top))
+	}
+	next.RawQuery = values.Encode()
+	return next.String()
+}
+
+// FormatValue convertit une valeur lue en base au format JSON OData du type de la propriété
+func FormatValue(prop *Property, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	switch prop.Type {
+	case TypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v
+		case int64:
+			return v != 0
+		case uint64:
+			return v != 0
+		case string:
+			return v == "1" || strings.EqualFold(v, "true")
+		}
+	case TypeDate:
+		if t, ok := value.(time.Time); ok {
+			return t.Format("2006-01-02")
+		}
+	case TypeDateTimeOffset:
+		if t, ok := value.(time.Time); ok {
+			return t.Format(time.RFC3339)
+		}
+	case TypeInt64, TypeInt32:
+		if s, ok := value.(string); ok {
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return value
+}
+
+// ServiceDocument retourne le document de service listant les ensembles d'entités
+func ServiceDocument(metadataURL string, sets []EntitySet) map[string]interface{} {
+	entries := make([]map[string]string, len(sets))
+	for i, set := range sets {
+		entries[i] = map[string]string{"name": set.Name, "kind": "EntitySet", "url": set.Name}
+	}
+	return map[string]interface{}{
+		"@odata.context": metadataURL,
+		"value":          entries,
+	}
+}
+
+// Structures CSDL du document $metadata
+type edmx struct {
+	XMLName      xml.Name `xml:"edmx:Edmx"`
+	Version      string   `xml:"Version,attr"`
+	XmlnsEdmx    string   `xml:"xmlns:edmx,attr"`
+	DataServices struct {
+		Schema edmSchema `xml:"Schema"`
+	} `xml:"edmx:DataServices"`
+}
+
+type edmSchema struct {
+	Namespace   string          `xml:"Namespace,attr"`
+	Xmlns       string          `xml:"xmlns,attr"`
+	EntityTypes []edmEntityType `xml:"EntityType"`
+	Container   struct {
+		Name       string         `xml:"Name,attr"`
+		EntitySets []edmEntitySet `xml:"EntitySet"`
+	} `xml:"EntityContainer"`
+}
+
+type edmEntityType struct {
+	Name string `xml:"Name,attr"`
+	Key  struct {
+		PropertyRef struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"PropertyRef"`
+	} `xml:"Key"`
+	Properties []edmProperty `xml:"Property"`
+}
+
+type edmProperty struct {
+	Name     string `xml:"Name,attr"`
+	Type     string `xml:"Type,attr"`
+	Nullable string `xml:"Nullable,attr"`
+}
+
+type edmEntitySet struct {
+	Name       string `xml:"Name,attr"`
+	EntityType string `xml:"EntityType,attr"`
+}
+
+// Metadata génère le document $metadata (CSDL XML) des ensembles d'entités
+func Metadata(namespace, container string, sets []EntitySet) ([]byte, error) {
+	doc := edmx{Version: "4.0", XmlnsEdmx: "http://docs.oasis-open.org/odata/ns/edmx"}
+	schema := &doc.DataServices.Schema
+	schema.Namespace = namespace
+	schema.Xmlns = "http://docs.oasis-open.org/odata/ns/edm"
+	schema.Container.Name = container
+	for _, set := range sets {
+		entityType := edmEntityType{Name: set.Type}
+		entityType.Key.PropertyRef.Name = set.Key
+		for _, prop := range set.Properties {
+			entityType.Properties = append(entityType.Properties, edmProperty{
+				Name:     prop.Name,
+				Type:     prop.Type,
+				Nullable: strconv.FormatBool(prop.Nullable || prop.Restricted),
+			})
+		}
+		schema.EntityTypes = append(schema.EntityTypes, entityType)
+		schema.Container.EntitySets = append(schema.Container.EntitySets, edmEntitySet{
+			Name:       set.Name,
+			EntityType: namespace + "." + set.Type,
+		})
+	}
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
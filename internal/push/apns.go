@@ -0,0 +1,149 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Points de terminaison APNs (HTTP/2)
+const (
+	apnsProductionURL  = "https://api.push.apple.com/3/device/"
+	apnsDevelopmentURL = "https://api.sandbox.push.apple.com/3/device/"
+)
+
+// apnsTokenLifetime durée d'utilisation du jeton d'authentification (Apple le refuse au-delà d'une heure)
+const apnsTokenLifetime = 50 * time.Minute
+
+// apnsInvalidReasons motifs de refus désignant un jeton d'appareil à supprimer
+var apnsInvalidReasons = map[string]bool{
+	"BadDeviceToken":         true,
+	"DeviceTokenNotForTopic": true,
+	"Unregistered":           true,
+}
+
+// APNs client d'Apple Push Notification service, authentifié par une clé de signature (.p8)
+type APNs struct {
+	baseURL  string
+	keyID    string
+	teamID   string
+	bundleID string
+	key      *ecdsa.PrivateKey
+	http     *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNs crée un client à partir de la clé d'authentification ; sandbox cible l'environnement de développement
+func NewAPNs(keyFile, keyID, teamID, bundleID string, sandbox bool) (*APNs, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("APNs: lecture de la clé impossible: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("APNs: clé invalide: %w", err)
+	}
+	baseURL := apnsProductionURL
+	if sandbox {
+		baseURL = apnsDevelopmentURL
+	}
+	return &APNs{
+		baseURL:  baseURL,
+		keyID:    keyID,
+		teamID:   teamID,
+		bundleID: bundleID,
+		key:      key,
+		http:     &http.Client{Timeout: requestTimeout}, // Le transport par défaut négocie HTTP/2, exigé par APNs
+	}, nil
+}
+
+// Send envoie une alerte à un appareil iOS ; ErrInvalidToken si le jeton n'est plus valide pour l'application
+func (a *APNs) Send(ctx context.Context, token string, message Message) error {
+	bearer, err := a.authToken()
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": message.Title, "body": message.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range message.Data {
+		if key != "aps" {
+			body[key] = value
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+token, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+bearer)
+	req.Header.Set("apns-topic", a.bundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("APNs: requête impossible: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&failure)
+	if resp.StatusCode == http.StatusGone || apnsInvalidReasons[failure.Reason] {
+		return ErrInvalidToken
+	}
+	if failure.Reason == "ExpiredProviderToken" {
+		a.mu.Lock()
+		a.token = ""
+		a.mu.Unlock()
+	}
+	if failure.Reason == "" {
+		return fmt.Errorf("APNs: réponse HTTP %d", resp.StatusCode)
+	}
+	return fmt.Errorf("APNs: réponse HTTP %d: %s", resp.StatusCode, failure.Reason)
+}
+
+// authToken retourne le jeton d'authentification ES256, renouvelé avant son expiration
+func (a *APNs) authToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Since(a.issuedAt) < apnsTokenLifetime {
+		return a.token, nil
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = a.keyID
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("APNs: signature du jeton impossible: %w", err)
+	}
+	a.token = signed
+	a.issuedAt = now
+	return signed, nil
+}
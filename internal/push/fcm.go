@@ -0,0 +1,190 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Points de terminaison Google
+const (
+	fcmSendURL   = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmScope     = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmTokenURL  = "https://oauth2.googleapis.com/token"
+	fcmGrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+// fcmTokenMargin marge avant expiration au-delà de laquelle le jeton d'accès est renouvelé
+const fcmTokenMargin = time.Minute
+
+// FCM client de l'API HTTP v1 de Firebase Cloud Messaging, authentifié par un compte de service
+type FCM struct {
+	projectID   string
+	clientEmail string
+	tokenURL    string
+	privateKey  *rsa.PrivateKey // Clé du compte de service
+	http        *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// serviceAccount champs utiles du fichier JSON d'un compte de service Google
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewFCM crée un client à partir du fichier du compte de service ; projectID remplace le projet du compte s'il est renseigné
+func NewFCM(credentialsFile, projectID string) (*FCM, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("FCM: lecture du compte de service impossible: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("FCM: compte de service invalide: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("FCM: clé privée du compte de service invalide: %w", err)
+	}
+	if projectID == "" {
+		projectID = account.ProjectID
+	}
+	if projectID == "" || account.ClientEmail == "" {
+		return nil, errors.New("FCM: project_id et client_email sont requis")
+	}
+	tokenURL := account.TokenURI
+	if tokenURL == "" {
+		tokenURL = fcmTokenURL
+	}
+	return &FCM{
+		projectID:   projectID,
+		clientEmail: account.ClientEmail,
+		tokenURL:    tokenURL,
+		privateKey:  key,
+		http:        &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Send envoie un message à un appareil Android (ou web) ; ErrInvalidToken si le jeton n'est plus enregistré
+func (f *FCM) Send(ctx context.Context, token string, message Message) error {
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"token":        token,
+			"notification": map[string]string{"title": message.Title, "body": message.Body},
+			"data":         message.Data,
+			"android":      map[string]string{"priority": "high"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, f.projectID), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("FCM: requête impossible: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, detail := range body.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ErrInvalidToken
+		}
+	}
+	// Le message envoyé est toujours bien formé : un argument invalide désigne le jeton
+	if resp.StatusCode == http.StatusNotFound || body.Error.Status == "INVALID_ARGUMENT" {
+		return ErrInvalidToken
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		f.mu.Lock()
+		f.accessToken = ""
+		f.mu.Unlock()
+	}
+	if body.Error.Message == "" {
+		return fmt.Errorf("FCM: réponse HTTP %d", resp.StatusCode)
+	}
+	return fmt.Errorf("FCM: réponse HTTP %d: %s", resp.StatusCode, body.Error.Message)
+}
+
+// token retourne un jeton d'accès OAuth valide, obtenu par une assertion JWT signée avec la clé du compte de service
+func (f *FCM) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.accessToken != "" && time.Now().Add(fcmTokenMargin).Before(f.expiresAt) {
+		return f.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.clientEmail,
+		"scope": fcmScope,
+		"aud":   f.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(f.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("FCM: signature de l'assertion impossible: %w", err)
+	}
+	form := url.Values{"grant_type": {fcmGrantType}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("FCM: obtention du jeton d'accès impossible: %w", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil || resp.StatusCode >= 300 || body.AccessToken == "" {
+		return "", fmt.Errorf("FCM: jeton d'accès refusé (HTTP %d): %s", resp.StatusCode, body.ErrorDescription)
+	}
+	f.accessToken = body.AccessToken
+	f.expiresAt = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+	return f.accessToken, nil
+}
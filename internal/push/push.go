@@ -0,0 +1,22 @@
+// Package push envoie les notifications push mobiles par Firebase Cloud Messaging (Android) et
+// Apple Push Notification service (iOS)
+package push
+
+import (
+	"errors"
+	"time"
+)
+
+// requestTimeout borne la durée d'un appel aux fournisseurs
+const requestTimeout = 15 * time.Second
+
+// ErrInvalidToken le fournisseur signale un jeton d'appareil invalide ou expiré (application désinstallée, jeton renouvelé) :
+// le jeton doit être supprimé
+var ErrInvalidToken = errors.New("jeton d'appareil invalide")
+
+// Message notification à afficher sur l'appareil
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string // Données transmises à l'application (identifiant de notification, lien, ...)
+}
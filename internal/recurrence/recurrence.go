@@ -0,0 +1,387 @@
+// Package recurrence calcule les occurrences d'une planification exprimée en cron (5 champs ou @monthly, ...)
+// ou en règle RRULE (RFC 5545, sous-ensemble : FREQ, INTERVAL, BYMONTH, BYMONTHDAY, BYDAY, BYHOUR, BYMINUTE, UNTIL, COUNT)
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ErrInvalidSchedule planification illisible
+var ErrInvalidSchedule = errors.New("planification invalide (expression cron ou RRULE attendue)")
+
+// maxInterval intervalle maximal d'une règle RRULE (borne la recherche de la prochaine occurrence)
+const maxInterval = 99
+
+// Schedule planification analysée
+type Schedule interface {
+	Next(after time.Time) time.Time // Première occurrence strictement après after (zéro s'il n'y en a plus)
+	Count() int                     // Nombre maximal d'occurrences (COUNT), 0 = illimité
+}
+
+// IsRRule indique si l'expression est une règle RRULE plutôt qu'une expression cron
+func IsRRule(expr string) bool {
+	expr = strings.ToUpper(strings.TrimSpace(expr))
+	return strings.HasPrefix(expr, "RRULE:") || strings.HasPrefix(expr, "FREQ=")
+}
+
+// Parse analyse une expression cron ou RRULE ; les occurrences sont calculées dans le fuseau loc
+// Une règle RRULE est ancrée sur start (DTSTART) : heure, jour de la semaine ou du mois par défaut, base de l'intervalle
+func Parse(expr string, loc *time.Location, start time.Time) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, ErrInvalidSchedule
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	if IsRRule(expr) {
+		return parseRRule(expr, loc, start)
+	}
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, ErrInvalidSchedule
+	}
+	return &cronSchedule{schedule: schedule, loc: loc}, nil
+}
+
+// cronSchedule planification cron évaluée dans un fuseau donné
+type cronSchedule struct {
+	schedule cron.Schedule
+	loc      *time.Location
+}
+
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	return s.schedule.Next(after.In(s.loc))
+}
+
+func (s *cronSchedule) Count() int {
+	return 0
+}
+
+// Fréquences RRULE supportées
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+	freqYearly  = "YEARLY"
+)
+
+// weekdays codes RRULE des jours de la semaine
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// byDay jour de la semaine d'une règle, avec son rang éventuel (1MO : premier lundi, -1FR : dernier vendredi)
+type byDay struct {
+	weekday time.Weekday
+	ordinal int
+}
+
+// rruleSchedule règle RRULE analysée
+type rruleSchedule struct {
+	loc        *time.Location
+	start      time.Time // DTSTART, dans loc
+	freq       string
+	interval   int
+	byMonth    []int
+	byMonthDay []int
+	byDay      []byDay
+	byHour     []int
+	byMinute   []int
+	until      *time.Time
+	count      int
+}
+
+func parseRRule(expr string, loc *time.Location, start time.Time) (*rruleSchedule, error) {
+	rule := &rruleSchedule{loc: loc, start: start.In(loc).Truncate(time.Second), interval: 1}
+	body := expr
+	if len(body) >= 6 && strings.EqualFold(body[:6], "RRULE:") {
+		body = body[6:]
+	}
+	for _, part := range strings.Split(body, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("RRULE invalide: %s", part)
+		}
+		key, value = strings.ToUpper(strings.TrimSpace(key)), strings.ToUpper(strings.TrimSpace(value))
+		var err error
+		switch key {
+		case "FREQ":
+			if value != freqDaily && value != freqWeekly && value != freqMonthly && value != freqYearly {
+				return nil, fmt.Errorf("RRULE: fréquence non supportée: %s (DAILY, WEEKLY, MONTHLY ou YEARLY)", value)
+			}
+			rule.freq = value
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(value)
+			if err != nil || rule.interval < 1 || rule.interval > maxInterval {
+				return nil, fmt.Errorf("RRULE: INTERVAL doit être compris entre 1 et %d", maxInterval)
+			}
+		case "BYMONTH":
+			rule.byMonth, err = parseIntList(value, 1, 12, false)
+		case "BYMONTHDAY":
+			rule.byMonthDay, err = parseIntList(value, 1, 31, true)
+		case "BYHOUR":
+			rule.byHour, err = parseIntList(value, 0, 23, false)
+		case "BYMINUTE":
+			rule.byMinute, err = parseIntList(value, 0, 59, false)
+		case "BYDAY":
+			rule.byDay, err = parseByDay(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value, loc)
+			rule.until = &until
+		case "COUNT":
+			rule.count, err = strconv.Atoi(value)
+			if err == nil && rule.count < 1 {
+				err = errors.New("COUNT doit être positif")
+			}
+		case "WKST":
+			if value != "MO" {
+				return nil, errors.New("RRULE: seul WKST=MO est supporté")
+			}
+		default:
+			return nil, fmt.Errorf("RRULE: paramètre non supporté: %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("RRULE: valeur de %s invalide", key)
+		}
+	}
+	if rule.freq == "" {
+		return nil, errors.New("RRULE: FREQ est obligatoire")
+	}
+	if rule.until != nil && rule.count > 0 {
+		return nil, errors.New("RRULE: UNTIL et COUNT ne peuvent pas être combinés")
+	}
+	for _, day := range rule.byDay {
+		if day.ordinal != 0 && rule.freq != freqMonthly && rule.freq != freqYearly {
+			return nil, errors.New("RRULE: un rang dans BYDAY n'est permis qu'en MONTHLY ou YEARLY")
+		}
+	}
+	if rule.Next(rule.start.Add(-time.Second)).IsZero() {
+		return nil, errors.New("RRULE: la règle ne produit aucune occurrence")
+	}
+	return rule, nil
+}
+
+func (r *rruleSchedule) Count() int {
+	return r.count
+}
+
+// Next parcourt les jours à partir de after jusqu'à trouver un jour retenu par la règle, puis la première heure qui suit after
+func (r *rruleSchedule) Next(after time.Time) time.Time {
+	after = after.In(r.loc)
+	if after.Before(r.start) {
+		after = r.start.Add(-time.Second)
+	}
+	hours := r.byHour
+	if len(hours) == 0 {
+		hours = []int{r.start.Hour()}
+	}
+	minutes := r.byMinute
+	if len(minutes) == 0 {
+		minutes = []int{r.start.Minute()}
+	}
+
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, r.loc)
+	// Horizon de recherche : quelques périodes (une date comme le 29 février peut ne revenir que tous les 4 ans)
+	horizon := 366 * 4 * (r.interval + 1)
+	for i := 0; i <= horizon; i++ {
+		if r.until != nil && day.After(*r.until) {
+			return time.Time{}
+		}
+		if r.matchesDay(day) {
+			for _, hour := range hours {
+				for _, minute := range minutes {
+					occurrence := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, r.start.Second(), 0, r.loc)
+					if !occurrence.After(after) {
+						continue
+					}
+					if r.until != nil && occurrence.After(*r.until) {
+						return time.Time{}
+					}
+					return occurrence
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// matchesDay indique si la règle retient le jour (minuit dans le fuseau de la règle)
+func (r *rruleSchedule) matchesDay(day time.Time) bool {
+	startDay := time.Date(r.start.Year(), r.start.Month(), r.start.Day(), 0, 0, 0, 0, r.loc)
+	if day.Before(startDay) {
+		return false
+	}
+	switch r.freq {
+	case freqDaily:
+		if daysBetween(startDay, day)%r.interval != 0 {
+			return false
+		}
+	case freqWeekly:
+		if daysBetween(weekStart(startDay), weekStart(day))/7%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 && day.Weekday() != r.start.Weekday() {
+			return false
+		}
+	case freqMonthly:
+		months := (day.Year()-startDay.Year())*12 + int(day.Month()-startDay.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 && day.Day() != r.start.Day() {
+			return false
+		}
+	case freqYearly:
+		if (day.Year()-startDay.Year())%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+			if len(r.byMonth) == 0 && day.Month() != r.start.Month() {
+				return false
+			}
+			if day.Day() != r.start.Day() {
+				return false
+			}
+		}
+	}
+	if len(r.byMonth) > 0 && !slices.Contains(r.byMonth, int(day.Month())) {
+		return false
+	}
+	if len(r.byMonthDay) > 0 && !r.matchesMonthDay(day) {
+		return false
+	}
+	if len(r.byDay) > 0 && !r.matchesWeekday(day) {
+		return false
+	}
+	return true
+}
+
+// matchesMonthDay vérifie BYMONTHDAY (valeurs négatives comptées depuis la fin du mois)
+func (r *rruleSchedule) matchesMonthDay(day time.Time) bool {
+	last := daysInMonth(day)
+	for _, monthDay := range r.byMonthDay {
+		if monthDay == day.Day() || (monthDay < 0 && last+monthDay+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday vérifie BYDAY ; le rang porte sur le mois (MONTHLY, ou YEARLY avec BYMONTH), sinon sur l'année
+func (r *rruleSchedule) matchesWeekday(day time.Time) bool {
+	inYear := r.freq == freqYearly && len(r.byMonth) == 0
+	for _, d := range r.byDay {
+		if d.weekday != day.Weekday() {
+			continue
+		}
+		if d.ordinal == 0 {
+			return true
+		}
+		index, length := day.Day(), daysInMonth(day)
+		if inYear {
+			index, length = day.YearDay(), daysInYear(day)
+		}
+		nth := (index-1)/7 + 1
+		nthFromEnd := -((length-index)/7 + 1)
+		if d.ordinal == nth || d.ordinal == nthFromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIntList lit une liste triée d'entiers séparés par des virgules, bornés (négatifs acceptés si signed)
+func parseIntList(value string, min, max int, signed bool) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		abs := n
+		if signed && n < 0 {
+			abs = -n
+		}
+		if abs < min || abs > max {
+			return nil, fmt.Errorf("valeur hors bornes: %d", n)
+		}
+		values = append(values, n)
+	}
+	slices.Sort(values)
+	return values, nil
+}
+
+// parseByDay lit BYDAY (ex: MO,WE,FR ou 1MO,-1FR)
+func parseByDay(value string) ([]byDay, error) {
+	var days []byDay
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("jour invalide: %s", item)
+		}
+		weekday, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("jour invalide: %s", item)
+		}
+		day := byDay{weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			ordinal, err := strconv.Atoi(strings.TrimPrefix(prefix, "+"))
+			if err != nil || ordinal == 0 || ordinal < -53 || ordinal > 53 {
+				return nil, fmt.Errorf("rang invalide: %s", item)
+			}
+			day.ordinal = ordinal
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseUntil lit UNTIL : date-heure UTC (20261231T235959Z), date-heure locale ou date seule (fin de journée incluse)
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("20060102", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// daysBetween nombre de jours calendaires entre deux minuits du même fuseau
+func daysBetween(from, to time.Time) int {
+	fromUTC := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toUTC := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toUTC.Sub(fromUTC).Hours() / 24)
+}
+
+// weekStart lundi de la semaine du jour (WKST=MO)
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func daysInMonth(day time.Time) int {
+	return time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(day time.Time) int {
+	return time.Date(day.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AbsenceFilter critères de recherche des absences
+type AbsenceFilter struct {
+	UserIDs  []uint     // Utilisateurs concernés (tous si vide)
+	Statuses []string   // Statuts (tous si vide)
+	From     *time.Time // Absences qui se terminent à partir de ce jour
+	To       *time.Time // Absences qui commencent au plus tard ce jour
+}
+
+// AbsenceRepository interface pour les absences des utilisateurs
+type AbsenceRepository interface {
+	Create(absence *models.Absence) error
+	Update(absence *models.Absence) error
+	FindByID(id uint) (*models.Absence, error)
+	FindAll(filter AbsenceFilter) ([]models.Absence, error)
+	FindOverlapping(userID uint, start, end time.Time, excludeID uint) ([]models.Absence, error) // Absences en attente ou approuvées qui chevauchent la période
+	FindApproved(userIDs []uint, from, to time.Time) ([]models.Absence, error)                   // Absences approuvées qui chevauchent la période
+}
+
+// absenceRepository implémente AbsenceRepository
+type absenceRepository struct{}
+
+// NewAbsenceRepository crée une nouvelle instance de AbsenceRepository
+func NewAbsenceRepository() AbsenceRepository {
+	return &absenceRepository{}
+}
+
+// withAbsenceRelations précharge le demandeur et le validateur d'une absence
+func withAbsenceRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").Preload("DecidedBy")
+}
+
+// Create crée une absence
+func (r *absenceRepository) Create(absence *models.Absence) error {
+	return database.DB.Omit(clause.Associations).Create(absence).Error
+}
+
+// Update met à jour une absence
+func (r *absenceRepository) Update(absence *models.Absence) error {
+	return database.DB.Omit(clause.Associations).Save(absence).Error
+}
+
+// FindByID trouve une absence par son ID
+func (r *absenceRepository) FindByID(id uint) (*models.Absence, error) {
+	var absence models.Absence
+	err := withAbsenceRelations(database.DB).First(&absence, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &absence, nil
+}
+
+// FindAll récupère les absences selon les filtres, par date de début
+func (r *absenceRepository) FindAll(filter AbsenceFilter) ([]models.Absence, error) {
+	var absences []models.Absence
+	query := withAbsenceRelations(database.DB)
+	if len(filter.UserIDs) > 0 {
+		query = query.Where("user_id IN ?", filter.UserIDs)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.From != nil {
+		query = query.Where("end_date >= ?", filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		query = query.Where("start_date <= ?", filter.To.Format("2006-01-02"))
+	}
+	err := query.Order("start_date, id").Find(&absences).Error
+	return absences, err
+}
+
+// FindOverlapping récupère les absences en attente ou approuvées de l'utilisateur qui chevauchent [start, end]
+func (r *absenceRepository) FindOverlapping(userID uint, start, end time.Time, excludeID uint) ([]models.Absence, error) {
+	var absences []models.Absence
+	err := database.DB.Where("user_id = ? AND id <> ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+		userID, excludeID, models.AbsenceBlockingStatuses, end.Format("2006-01-02"), start.Format("2006-01-02")).
+		Order("start_date").Find(&absences).Error
+	return absences, err
+}
+
+// FindApproved récupère les absences approuvées des utilisateurs qui chevauchent [from, to] (tous les utilisateurs si userIDs est vide)
+func (r *absenceRepository) FindApproved(userIDs []uint, from, to time.Time) ([]models.Absence, error) {
+	var absences []models.Absence
+	query := database.DB.Where("status = ? AND start_date <= ? AND end_date >= ?",
+		models.AbsenceStatusApproved, to.Format("2006-01-02"), from.Format("2006-01-02"))
+	if len(userIDs) > 0 {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+	err := query.Order("user_id, start_date").Find(&absences).Error
+	return absences, err
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// AccessDelegationRepository interface pour les opérations sur les délégations d'absence
+type AccessDelegationRepository interface {
+	Create(delegation *models.AccessDelegation) error
+	FindByID(id uint) (*models.AccessDelegation, error)
+	FindByDelegator(delegatorID uint) ([]models.AccessDelegation, error)
+	FindByDelegate(delegateID uint) ([]models.AccessDelegation, error)
+	FindActive(delegatorID, delegateID uint, at time.Time) (*models.AccessDelegation, error)
+	Update(delegation *models.AccessDelegation) error
+}
+
+// accessDelegationRepository implémente AccessDelegationRepository
+type accessDelegationRepository struct{}
+
+// NewAccessDelegationRepository crée une nouvelle instance de AccessDelegationRepository
+func NewAccessDelegationRepository() AccessDelegationRepository {
+	return &accessDelegationRepository{}
+}
+
+// Create crée une nouvelle délégation
+func (r *accessDelegationRepository) Create(delegation *models.AccessDelegation) error {
+	return database.DB.Create(delegation).Error
+}
+
+// FindByID trouve une délégation par son ID
+func (r *accessDelegationRepository) FindByID(id uint) (*models.AccessDelegation, error) {
+	var delegation models.AccessDelegation
+	err := database.DB.Preload("Delegator").Preload("Delegate").First(&delegation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// FindByDelegator récupère les délégations accordées par un utilisateur (plus récentes d'abord)
+func (r *accessDelegationRepository) FindByDelegator(delegatorID uint) ([]models.AccessDelegation, error) {
+	var delegations []models.AccessDelegation
+	err := database.DB.Preload("Delegator").Preload("Delegate").
+		Where("delegator_id = ?", delegatorID).
+		Order("starts_at DESC").
+		Find(&delegations).Error
+	return delegations, err
+}
+
+// FindByDelegate récupère les délégations reçues par un utilisateur (plus récentes d'abord)
+func (r *accessDelegationRepository) FindByDelegate(delegateID uint) ([]models.AccessDelegation, error) {
+	var delegations []models.AccessDelegation
+	err := database.DB.Preload("Delegator").Preload("Delegate").
+		Where("delegate_id = ?", delegateID).
+		Order("starts_at DESC").
+		Find(&delegations).Error
+	return delegations, err
+}
+
+// FindActive trouve la délégation en vigueur entre deux utilisateurs à l'instant donné
+func (r *accessDelegationRepository) FindActive(delegatorID, delegateID uint, at time.Time) (*models.AccessDelegation, error) {
+	var delegation models.AccessDelegation
+	err := database.DB.
+		Where("delegator_id = ? AND delegate_id = ? AND revoked_at IS NULL AND starts_at <= ? AND ends_at > ?", delegatorID, delegateID, at, at).
+		Order("ends_at DESC").
+		First(&delegation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// Update met à jour une délégation (sans les utilisateurs associés)
+func (r *accessDelegationRepository) Update(delegation *models.AccessDelegation) error {
+	return database.DB.Omit(clause.Associations).Save(delegation).Error
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApprovalFilter filtres de la liste des demandes d'approbation
+type ApprovalFilter struct {
+	EntityType     string
+	EntityID       *uint
+	Status         string
+	InvolvedUserID *uint // Demandes émises par l'utilisateur ou dont il est approbateur
+}
+
+// ApprovalRepository interface pour les opérations sur les demandes d'approbation
+type ApprovalRepository interface {
+	Create(request *models.ApprovalRequest) error // Crée la demande et ses approbateurs
+	FindByID(id uint) (*models.ApprovalRequest, error)
+	FindAll(filter ApprovalFilter) ([]models.ApprovalRequest, error)
+	FindPendingByEntity(entityType string, entityID uint) (*models.ApprovalRequest, error)
+	FindPendingForApprover(userID uint) ([]models.ApprovalRequest, error)          // Demandes dont l'étape courante attend la décision de l'utilisateur
+	UpdateLocked(id uint, apply func(request *models.ApprovalRequest) error) error // Verrouille la demande, applique apply puis enregistre la demande et ses approbateurs
+	SetApplyError(id uint, applyError string) error
+	AddComment(comment *models.ApprovalComment) error
+}
+
+// approvalRepository implémente ApprovalRepository
+type approvalRepository struct{}
+
+// NewApprovalRepository crée une nouvelle instance de ApprovalRepository
+func NewApprovalRepository() ApprovalRepository {
+	return &approvalRepository{}
+}
+
+// preloadApproval charge le demandeur, les approbateurs (par étape) et les commentaires
+func preloadApproval(db *gorm.DB) *gorm.DB {
+	return db.Preload("RequestedBy").
+		Preload("Approvers", func(db *gorm.DB) *gorm.DB { return db.Order("step_order ASC, id ASC") }).
+		Preload("Approvers.User").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC, id ASC") }).
+		Preload("Comments.User")
+}
+
+// Create crée la demande et ses approbateurs
+func (r *approvalRepository) Create(request *models.ApprovalRequest) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(request).Error; err != nil {
+			return err
+		}
+		for i := range request.Approvers {
+			request.Approvers[i].ApprovalRequestID = request.ID
+		}
+		if len(request.Approvers) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(&request.Approvers).Error
+	})
+}
+
+// FindByID récupère une demande d'approbation avec ses approbateurs et commentaires
+func (r *approvalRepository) FindByID(id uint) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	if err := preloadApproval(database.DB).First(&request, id).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindAll récupère les demandes d'approbation filtrées, les plus récentes d'abord
+func (r *approvalRepository) FindAll(filter ApprovalFilter) ([]models.ApprovalRequest, error) {
+	var requests []models.ApprovalRequest
+	query := preloadApproval(database.DB.Model(&models.ApprovalRequest{}))
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.InvolvedUserID != nil {
+		query = query.Where("requested_by_id = ? OR id IN (?)", *filter.InvolvedUserID,
+			database.DB.Model(&models.ApprovalApprover{}).Select("approval_request_id").Where("user_id = ?", *filter.InvolvedUserID))
+	}
+	err := query.Order("created_at DESC").Find(&requests).Error
+	return requests, err
+}
+
+// FindPendingByEntity récupère la demande en attente d'un enregistrement
+func (r *approvalRepository) FindPendingByEntity(entityType string, entityID uint) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	err := database.DB.Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, models.ApprovalStatusPending).
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindPendingForApprover récupère les demandes en attente de la décision de l'utilisateur, les plus anciennes d'abord
+func (r *approvalRepository) FindPendingForApprover(userID uint) ([]models.ApprovalRequest, error) {
+	var requests []models.ApprovalRequest
+	err := preloadApproval(database.DB.Model(&models.ApprovalRequest{})).
+		Where("status = ?", models.ApprovalStatusPending).
+		Where("id IN (?)", database.DB.Model(&models.ApprovalApprover{}).Select("approval_request_id").
+			Where("user_id = ? AND status = ?", userID, models.ApproverStatusPending)).
+		Order("created_at ASC").Find(&requests).Error
+	return requests, err
+}
+
+// UpdateLocked verrouille la demande (SELECT ... FOR UPDATE) le temps d'appliquer une décision :
+// deux approbateurs d'une même étape décidant en même temps voient chacun la décision de l'autre
+func (r *approvalRepository) UpdateLocked(id uint, apply func(request *models.ApprovalRequest) error) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var request models.ApprovalRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&request, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("approval_request_id = ?", id).Order("step_order ASC, id ASC").Find(&request.Approvers).Error; err != nil {
+			return err
+		}
+		if err := apply(&request); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(&request).Error; err != nil {
+			return err
+		}
+		for i := range request.Approvers {
+			if err := tx.Omit(clause.Associations).Save(&request.Approvers[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetApplyError enregistre l'échec de l'application de la décision au module
+func (r *approvalRepository) SetApplyError(id uint, applyError string) error {
+	return database.DB.Model(&models.ApprovalRequest{}).Where("id = ?", id).Update("apply_error", applyError).Error
+}
+
+// AddComment ajoute un commentaire à une demande
+func (r *approvalRepository) AddComment(comment *models.ApprovalComment) error {
+	return database.DB.Omit(clause.Associations).Create(comment).Error
+}
@@ -0,0 +1,240 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssetDiscoveryRepository interface pour les agents d'inventaire, le rapprochement avec la CMDB et les écarts constatés
+type AssetDiscoveryRepository interface {
+	CreateAgent(agent *models.DiscoveryAgent) error
+	FindAgentByID(id uint) (*models.DiscoveryAgent, error)
+	FindAgentByKeyHash(keyHash string) (*models.DiscoveryAgent, error)
+	FindAgents() ([]models.DiscoveryAgent, error)
+	UpdateAgent(agent *models.DiscoveryAgent) error
+	DeleteAgent(id uint) error
+	FindAssetBySerialNumber(serialNumber string, filialeID *uint) (*models.Asset, error)
+	FindAssetByName(name string, filialeID *uint) (*models.Asset, error)
+	FindCategoryByName(name string) (*models.AssetCategory, error)
+	CreateAsset(asset *models.Asset, event *models.AssetLifecycleEvent, software []models.AssetSoftware) error // Crée l'actif découvert, son historique et ses logiciels
+	UpdateAssetFields(assetID uint, fields map[string]any) error                                               // Met à jour les seules colonnes fournies
+	FindAssetSoftware(assetID uint) ([]models.AssetSoftware, error)
+	CreateAssetSoftware(software *models.AssetSoftware) error
+	UpdateAssetSoftwareVersion(id uint, version string) error
+	DeleteAssetSoftware(assetID uint, softwareName, version string) error
+	CreateDrift(drift *models.AssetDiscoveryDrift) error
+	UpdateDrift(drift *models.AssetDiscoveryDrift) error
+	FindDriftByID(id uint) (*models.AssetDiscoveryDrift, error)
+	FindLatestDrift(assetID uint, field, subject, cmdbValue string) (*models.AssetDiscoveryDrift, error)
+	FindOpenDrifts(assetID uint) ([]models.AssetDiscoveryDrift, error)
+	ClearDrifts(ids []uint, at time.Time) error
+	FindDrifts(status string, assetID, agentID *uint, page, limit int) ([]models.AssetDiscoveryDrift, int64, error)
+}
+
+// assetDiscoveryRepository implémente AssetDiscoveryRepository
+type assetDiscoveryRepository struct{}
+
+// NewAssetDiscoveryRepository crée une nouvelle instance de AssetDiscoveryRepository
+func NewAssetDiscoveryRepository() AssetDiscoveryRepository {
+	return &assetDiscoveryRepository{}
+}
+
+// CreateAgent crée un agent d'inventaire
+func (r *assetDiscoveryRepository) CreateAgent(agent *models.DiscoveryAgent) error {
+	return database.DB.Omit(clause.Associations).Create(agent).Error
+}
+
+// FindAgentByID trouve un agent par son ID
+func (r *assetDiscoveryRepository) FindAgentByID(id uint) (*models.DiscoveryAgent, error) {
+	var agent models.DiscoveryAgent
+	if err := database.DB.Preload("Filiale").Preload("DefaultCategory").First(&agent, id).Error; err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// FindAgentByKeyHash trouve l'agent correspondant à une clé d'API
+func (r *assetDiscoveryRepository) FindAgentByKeyHash(keyHash string) (*models.DiscoveryAgent, error) {
+	var agent models.DiscoveryAgent
+	if err := database.DB.Where("key_hash = ?", keyHash).First(&agent).Error; err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// FindAgents récupère tous les agents
+func (r *assetDiscoveryRepository) FindAgents() ([]models.DiscoveryAgent, error) {
+	var agents []models.DiscoveryAgent
+	err := database.DB.Preload("Filiale").Preload("DefaultCategory").Order("name").Find(&agents).Error
+	return agents, err
+}
+
+// UpdateAgent met à jour un agent
+func (r *assetDiscoveryRepository) UpdateAgent(agent *models.DiscoveryAgent) error {
+	return database.DB.Omit(clause.Associations).Save(agent).Error
+}
+
+// DeleteAgent supprime un agent (les actifs découverts et les écarts sont conservés)
+func (r *assetDiscoveryRepository) DeleteAgent(id uint) error {
+	return database.DB.Delete(&models.DiscoveryAgent{}, id).Error
+}
+
+// FindAssetBySerialNumber trouve l'actif portant ce numéro de série (insensible à la casse), limité à la filiale donnée
+// et aux actifs sans filiale
+func (r *assetDiscoveryRepository) FindAssetBySerialNumber(serialNumber string, filialeID *uint) (*models.Asset, error) {
+	var asset models.Asset
+	query := database.DB.Where("LOWER(serial_number) = LOWER(?)", serialNumber)
+	if filialeID != nil {
+		query = query.Where("filiale_id = ? OR filiale_id IS NULL", *filialeID)
+	}
+	if err := query.Order("id").First(&asset).Error; err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// FindAssetByName trouve l'actif portant ce nom (insensible à la casse), limité à la filiale donnée et aux actifs sans filiale
+func (r *assetDiscoveryRepository) FindAssetByName(name string, filialeID *uint) (*models.Asset, error) {
+	var asset models.Asset
+	query := database.DB.Where("LOWER(name) = LOWER(?)", name)
+	if filialeID != nil {
+		query = query.Where("filiale_id = ? OR filiale_id IS NULL", *filialeID)
+	}
+	if err := query.Order("id").First(&asset).Error; err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// FindCategoryByName trouve la catégorie d'actif portant ce nom (insensible à la casse)
+func (r *assetDiscoveryRepository) FindCategoryByName(name string) (*models.AssetCategory, error) {
+	var category models.AssetCategory
+	if err := database.DB.Where("LOWER(name) = LOWER(?)", name).Order("id").First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CreateAsset crée l'actif découvert avec son entrée d'historique et ses logiciels installés
+func (r *assetDiscoveryRepository) CreateAsset(asset *models.Asset, event *models.AssetLifecycleEvent, software []models.AssetSoftware) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(asset).Error; err != nil {
+			return err
+		}
+		event.AssetID = asset.ID
+		if err := tx.Omit(clause.Associations).Create(event).Error; err != nil {
+			return err
+		}
+		for i := range software {
+			software[i].AssetID = &asset.ID
+		}
+		if len(software) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(&software).Error
+	})
+}
+
+// UpdateAssetFields met à jour les colonnes d'un actif sans réécrire ses autres champs
+func (r *assetDiscoveryRepository) UpdateAssetFields(assetID uint, fields map[string]any) error {
+	return database.DB.Model(&models.Asset{}).Where("id = ?", assetID).Updates(fields).Error
+}
+
+// FindAssetSoftware récupère les logiciels inscrits sur un actif
+func (r *assetDiscoveryRepository) FindAssetSoftware(assetID uint) ([]models.AssetSoftware, error) {
+	var software []models.AssetSoftware
+	err := database.DB.Where("asset_id = ?", assetID).Order("id").Find(&software).Error
+	return software, err
+}
+
+// CreateAssetSoftware inscrit un logiciel installé sur un actif
+func (r *assetDiscoveryRepository) CreateAssetSoftware(software *models.AssetSoftware) error {
+	return database.DB.Omit(clause.Associations).Create(software).Error
+}
+
+// UpdateAssetSoftwareVersion met à jour la version d'un logiciel installé
+func (r *assetDiscoveryRepository) UpdateAssetSoftwareVersion(id uint, version string) error {
+	return database.DB.Model(&models.AssetSoftware{}).Where("id = ?", id).Update("version", version).Error
+}
+
+// DeleteAssetSoftware retire d'un actif les logiciels de ce nom et de cette version (soft delete)
+func (r *assetDiscoveryRepository) DeleteAssetSoftware(assetID uint, softwareName, version string) error {
+	return database.DB.Where("asset_id = ? AND software_name = ? AND version = ?", assetID, softwareName, version).
+		Delete(&models.AssetSoftware{}).Error
+}
+
+// CreateDrift crée un écart de découverte
+func (r *assetDiscoveryRepository) CreateDrift(drift *models.AssetDiscoveryDrift) error {
+	return database.DB.Omit(clause.Associations).Create(drift).Error
+}
+
+// UpdateDrift met à jour un écart de découverte
+func (r *assetDiscoveryRepository) UpdateDrift(drift *models.AssetDiscoveryDrift) error {
+	return database.DB.Omit(clause.Associations).Save(drift).Error
+}
+
+// FindDriftByID trouve un écart par son ID
+func (r *assetDiscoveryRepository) FindDriftByID(id uint) (*models.AssetDiscoveryDrift, error) {
+	var drift models.AssetDiscoveryDrift
+	if err := database.DB.Preload("Asset").Preload("Agent").Preload("ResolvedBy").First(&drift, id).Error; err != nil {
+		return nil, err
+	}
+	return &drift, nil
+}
+
+// FindLatestDrift trouve le dernier écart constaté sur un champ (et un logiciel) d'un actif ;
+// cmdbValue limite la recherche à cette valeur de la CMDB (vide : toute valeur)
+func (r *assetDiscoveryRepository) FindLatestDrift(assetID uint, field, subject, cmdbValue string) (*models.AssetDiscoveryDrift, error) {
+	var drift models.AssetDiscoveryDrift
+	query := database.DB.Where("asset_id = ? AND field = ? AND subject = ?", assetID, field, subject)
+	if cmdbValue != "" {
+		query = query.Where("cmdb_value = ?", cmdbValue)
+	}
+	if err := query.Order("id DESC").First(&drift).Error; err != nil {
+		return nil, err
+	}
+	return &drift, nil
+}
+
+// FindOpenDrifts récupère les écarts non traités d'un actif
+func (r *assetDiscoveryRepository) FindOpenDrifts(assetID uint) ([]models.AssetDiscoveryDrift, error) {
+	var drifts []models.AssetDiscoveryDrift
+	err := database.DB.Where("asset_id = ? AND status = ?", assetID, models.DiscoveryDriftOpen).Find(&drifts).Error
+	return drifts, err
+}
+
+// ClearDrifts clôt les écarts résorbés (l'inventaire concorde à nouveau avec la CMDB)
+func (r *assetDiscoveryRepository) ClearDrifts(ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return database.DB.Model(&models.AssetDiscoveryDrift{}).
+		Where("id IN ? AND status = ?", ids, models.DiscoveryDriftOpen).
+		Updates(map[string]any{"status": models.DiscoveryDriftCleared, "resolved_at": at}).Error
+}
+
+// FindDrifts récupère les écarts (derniers constatés d'abord), éventuellement filtrés par état, actif et agent
+func (r *assetDiscoveryRepository) FindDrifts(status string, assetID, agentID *uint, page, limit int) ([]models.AssetDiscoveryDrift, int64, error) {
+	var drifts []models.AssetDiscoveryDrift
+	var total int64
+	query := database.DB.Model(&models.AssetDiscoveryDrift{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if assetID != nil {
+		query = query.Where("asset_id = ?", *assetID)
+	}
+	if agentID != nil {
+		query = query.Where("agent_id = ?", *agentID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Asset").Preload("Agent").Preload("ResolvedBy").
+		Order("last_seen_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&drifts).Error
+	return drifts, total, err
+}
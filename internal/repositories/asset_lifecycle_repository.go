@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssetLifecycleRepository interface pour le cycle de vie des actifs : transitions et historique
+type AssetLifecycleRepository interface {
+	Transition(asset *models.Asset, event *models.AssetLifecycleEvent) error // Enregistre l'actif et la transition dans une même transaction
+	CreateEvent(event *models.AssetLifecycleEvent) error
+	FindEvents(assetID uint) ([]models.AssetLifecycleEvent, error)
+	FindExpiring(scope interface{}, from, to time.Time) ([]models.Asset, error) // Actifs dont la garantie ou le contrat se termine entre from et to
+}
+
+// assetLifecycleRepository implémente AssetLifecycleRepository
+type assetLifecycleRepository struct{}
+
+// NewAssetLifecycleRepository crée une nouvelle instance de AssetLifecycleRepository
+func NewAssetLifecycleRepository() AssetLifecycleRepository {
+	return &assetLifecycleRepository{}
+}
+
+// Transition enregistre le nouvel état de l'actif et l'événement de cycle de vie correspondant
+func (r *assetLifecycleRepository) Transition(asset *models.Asset, event *models.AssetLifecycleEvent) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(asset).Error; err != nil {
+			return err
+		}
+		event.AssetID = asset.ID
+		return tx.Omit(clause.Associations).Create(event).Error
+	})
+}
+
+// CreateEvent crée un événement de cycle de vie
+func (r *assetLifecycleRepository) CreateEvent(event *models.AssetLifecycleEvent) error {
+	return database.DB.Omit(clause.Associations).Create(event).Error
+}
+
+// FindEvents récupère l'historique du cycle de vie d'un actif, du plus ancien au plus récent
+func (r *assetLifecycleRepository) FindEvents(assetID uint) ([]models.AssetLifecycleEvent, error) {
+	var events []models.AssetLifecycleEvent
+	err := database.DB.Preload("AssignedTo").Preload("PerformedBy").
+		Where("asset_id = ?", assetID).
+		Order("created_at ASC, id ASC").Find(&events).Error
+	return events, err
+}
+
+// FindExpiring récupère les actifs en service dont la garantie ou le contrat expire entre from et to
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (r *assetLifecycleRepository) FindExpiring(scopeParam interface{}, from, to time.Time) ([]models.Asset, error) {
+	var assets []models.Asset
+	query := database.DB.Model(&models.Asset{}).Preload("Category").Preload("AssignedTo").
+		Where("status <> ?", models.AssetStatusDisposed).
+		Where("(warranty_expiry BETWEEN ? AND ?) OR (contract_end_date BETWEEN ? AND ?)", from, to, from, to)
+
+	if scopeParam != nil {
+		if queryScope, ok := scopeParam.(*scope.QueryScope); ok {
+			query = scope.ApplyAssetScope(query, queryScope)
+		}
+	}
+
+	err := query.Order("LEAST(COALESCE(warranty_expiry, contract_end_date), COALESCE(contract_end_date, warranty_expiry)) ASC").Find(&assets).Error
+	return assets, err
+}
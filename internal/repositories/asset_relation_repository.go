@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// AssetRelationRepository interface pour les relations de dépendance de la CMDB et l'analyse d'impact
+type AssetRelationRepository interface {
+	Create(relation *models.AssetRelation) error
+	FindByID(id uint) (*models.AssetRelation, error)
+	FindByAsset(assetID uint) ([]models.AssetRelation, error)                                            // Relations dont l'actif est la source ou la cible
+	Exists(sourceAssetID, sourceSoftwareID *uint, targetAssetID uint, relationType string) (bool, error) // Relation identique déjà déclarée
+	Delete(id uint) error
+	FindDependents(targetAssetIDs []uint) ([]models.AssetRelation, error)                      // Relations vers ces actifs, avec leur source
+	FindOpenTicketsByAssets(assetIDs []uint, closedStatuses []string) ([]models.Ticket, error) // Tickets liés aux actifs (directement ou par un incident)
+	FindOpenTicketsBySoftware(softwareIDs, filialeIDs []uint, closedStatuses []string) ([]models.Ticket, error)
+}
+
+// assetRelationRepository implémente AssetRelationRepository
+type assetRelationRepository struct{}
+
+// NewAssetRelationRepository crée une nouvelle instance de AssetRelationRepository
+func NewAssetRelationRepository() AssetRelationRepository {
+	return &assetRelationRepository{}
+}
+
+// Create crée une relation
+func (r *assetRelationRepository) Create(relation *models.AssetRelation) error {
+	return database.DB.Omit(clause.Associations).Create(relation).Error
+}
+
+// FindByID trouve une relation par son ID
+func (r *assetRelationRepository) FindByID(id uint) (*models.AssetRelation, error) {
+	var relation models.AssetRelation
+	err := database.DB.Preload("SourceAsset.Category").Preload("SourceSoftware").Preload("TargetAsset.Category").First(&relation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// FindByAsset récupère les relations dont l'actif est la source ou la cible
+func (r *assetRelationRepository) FindByAsset(assetID uint) ([]models.AssetRelation, error) {
+	var relations []models.AssetRelation
+	err := database.DB.Preload("SourceAsset.Category").Preload("SourceSoftware").Preload("TargetAsset.Category").
+		Where("source_asset_id = ? OR target_asset_id = ?", assetID, assetID).
+		Order("type, id").Find(&relations).Error
+	return relations, err
+}
+
+// Exists indique si une relation identique est déjà déclarée
+func (r *assetRelationRepository) Exists(sourceAssetID, sourceSoftwareID *uint, targetAssetID uint, relationType string) (bool, error) {
+	var count int64
+	query := database.DB.Model(&models.AssetRelation{}).Where("target_asset_id = ? AND type = ?", targetAssetID, relationType)
+	if sourceAssetID != nil {
+		query = query.Where("source_asset_id = ?", *sourceAssetID)
+	} else {
+		query = query.Where("source_software_id = ?", *sourceSoftwareID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Delete supprime une relation
+func (r *assetRelationRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.AssetRelation{}, id).Error
+}
+
+// FindDependents récupère les relations qui pointent vers ces actifs, avec l'actif ou le logiciel source
+func (r *assetRelationRepository) FindDependents(targetAssetIDs []uint) ([]models.AssetRelation, error) {
+	var relations []models.AssetRelation
+	if len(targetAssetIDs) == 0 {
+		return relations, nil
+	}
+	err := database.DB.Preload("SourceAsset.Category").Preload("SourceSoftware").
+		Where("target_asset_id IN ?", targetAssetIDs).Order("id").Find(&relations).Error
+	return relations, err
+}
+
+// FindOpenTicketsByAssets récupère les tickets non clôturés liés aux actifs, directement ou par leur incident
+func (r *assetRelationRepository) FindOpenTicketsByAssets(assetIDs []uint, closedStatuses []string) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	if len(assetIDs) == 0 {
+		return tickets, nil
+	}
+	linked := database.DB.Table("ticket_assets").Select("ticket_id").Where("asset_id IN ?", assetIDs)
+	viaIncident := database.DB.Table("incident_assets").Select("incidents.ticket_id").
+		Joins("JOIN incidents ON incidents.id = incident_assets.incident_id").
+		Where("incident_assets.asset_id IN ?", assetIDs)
+	err := database.DB.Where("(id IN (?) OR id IN (?)) AND status NOT IN ?", linked, viaIncident, closedStatuses).
+		Order("created_at DESC").Find(&tickets).Error
+	return tickets, err
+}
+
+// FindOpenTicketsBySoftware récupère les tickets non clôturés portant sur ces logiciels, limités aux filiales données si fournies
+func (r *assetRelationRepository) FindOpenTicketsBySoftware(softwareIDs, filialeIDs []uint, closedStatuses []string) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	if len(softwareIDs) == 0 {
+		return tickets, nil
+	}
+	query := database.DB.Where("software_id IN ? AND status NOT IN ?", softwareIDs, closedStatuses)
+	if len(filialeIDs) > 0 {
+		query = query.Where("filiale_id IN ?", filialeIDs)
+	}
+	err := query.Order("created_at DESC").Find(&tickets).Error
+	return tickets, err
+}
@@ -16,6 +16,7 @@ type AssetRepository interface {
 	FindByStatus(scope interface{}, status string) ([]models.Asset, error)
 	FindByAssignedTo(userID uint) ([]models.Asset, error)
 	FindBySerialNumber(serialNumber string) (*models.Asset, error)
+	FindByCode(code string) (*models.Asset, error)
 	Search(scope interface{}, query string, category string, limit int) ([]models.Asset, error) // scope peut être *scope.QueryScope ou nil
 	Update(asset *models.Asset) error
 	Delete(id uint) error
@@ -154,6 +155,16 @@ func (r *assetRepository) FindBySerialNumber(serialNumber string) (*models.Asset
 	return &asset, nil
 }
 
+// FindByCode trouve un actif par son étiquette d'inventaire
+func (r *assetRepository) FindByCode(code string) (*models.Asset, error) {
+	var asset models.Asset
+	err := database.DB.Preload("Category").Preload("AssignedTo").Preload("AssignedTo.Role").Where("code = ?", code).First(&asset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
 // Update met à jour un actif
 func (r *assetRepository) Update(asset *models.Asset) error {
 	return database.DB.Save(asset).Error
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssetReservationFilter critères de recherche des réservations
+type AssetReservationFilter struct {
+	AssetID     *uint
+	RequesterID *uint
+	Statuses    []string
+	From        *time.Time // Réservations qui se terminent après cette date
+	To          *time.Time // Réservations qui commencent avant cette date
+}
+
+// AssetReservationRepository interface pour les réservations du parc de prêt
+type AssetReservationRepository interface {
+	Create(reservation *models.AssetReservation) error
+	Update(reservation *models.AssetReservation) error
+	FindByID(id uint) (*models.AssetReservation, error)
+	FindAll(filter AssetReservationFilter) ([]models.AssetReservation, error)
+	FindConflicts(assetID uint, start, end time.Time, excludeID uint) ([]models.AssetReservation, error) // Réservations actives qui chevauchent le créneau
+	FindLoanableAssets(categoryID, filialeID *uint) ([]models.Asset, error)
+	FindDueForReminder(before time.Time) ([]models.AssetReservation, error) // Prêts en cours à rendre avant cette date, sans rappel envoyé
+	FindOverdueUnnotified(now time.Time) ([]models.AssetReservation, error) // Prêts en cours non rendus à échéance, sans alerte envoyée
+	MarkDueReminderSent(id uint, at time.Time) error
+	MarkOverdueNotified(id uint, at time.Time) error
+}
+
+// assetReservationRepository implémente AssetReservationRepository
+type assetReservationRepository struct{}
+
+// NewAssetReservationRepository crée une nouvelle instance de AssetReservationRepository
+func NewAssetReservationRepository() AssetReservationRepository {
+	return &assetReservationRepository{}
+}
+
+// withReservationRelations précharge l'équipement et les utilisateurs d'une réservation
+func withReservationRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Asset.Category").Preload("Requester").Preload("DecidedBy")
+}
+
+// Create crée une réservation
+func (r *assetReservationRepository) Create(reservation *models.AssetReservation) error {
+	return database.DB.Omit(clause.Associations).Create(reservation).Error
+}
+
+// Update met à jour une réservation
+func (r *assetReservationRepository) Update(reservation *models.AssetReservation) error {
+	return database.DB.Omit(clause.Associations).Save(reservation).Error
+}
+
+// FindByID trouve une réservation par son ID
+func (r *assetReservationRepository) FindByID(id uint) (*models.AssetReservation, error) {
+	var reservation models.AssetReservation
+	err := withReservationRelations(database.DB).First(&reservation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// FindAll récupère les réservations selon les filtres, par date de début
+func (r *assetReservationRepository) FindAll(filter AssetReservationFilter) ([]models.AssetReservation, error) {
+	var reservations []models.AssetReservation
+	query := withReservationRelations(database.DB)
+	if filter.AssetID != nil {
+		query = query.Where("asset_id = ?", *filter.AssetID)
+	}
+	if filter.RequesterID != nil {
+		query = query.Where("requester_id = ?", *filter.RequesterID)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.From != nil {
+		query = query.Where("end_at > ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("start_at < ?", *filter.To)
+	}
+	err := query.Order("start_at, id").Find(&reservations).Error
+	return reservations, err
+}
+
+// FindConflicts récupère les réservations actives de l'équipement qui chevauchent le créneau [start, end)
+func (r *assetReservationRepository) FindConflicts(assetID uint, start, end time.Time, excludeID uint) ([]models.AssetReservation, error) {
+	var reservations []models.AssetReservation
+	err := database.DB.Where("asset_id = ? AND id <> ? AND status IN ? AND start_at < ? AND end_at > ?",
+		assetID, excludeID, models.AssetReservationBlockingStatuses, end, start).
+		Order("start_at").Find(&reservations).Error
+	return reservations, err
+}
+
+// FindLoanableAssets récupère les équipements du parc de prêt
+func (r *assetReservationRepository) FindLoanableAssets(categoryID, filialeID *uint) ([]models.Asset, error) {
+	var assets []models.Asset
+	query := database.DB.Preload("Category").Where("loanable = ?", true)
+	if categoryID != nil {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+	if filialeID != nil {
+		query = query.Where("filiale_id = ?", *filialeID)
+	}
+	err := query.Order("name, id").Find(&assets).Error
+	return assets, err
+}
+
+// FindDueForReminder récupère les prêts en cours à rendre avant la date, dont le rappel n'a pas été envoyé
+func (r *assetReservationRepository) FindDueForReminder(before time.Time) ([]models.AssetReservation, error) {
+	var reservations []models.AssetReservation
+	err := database.DB.Preload("Asset").
+		Where("status = ? AND end_at <= ? AND due_reminder_sent_at IS NULL", models.AssetReservationCheckedOut, before).
+		Order("end_at").Find(&reservations).Error
+	return reservations, err
+}
+
+// FindOverdueUnnotified récupère les prêts en cours dont la date de retour est dépassée, sans alerte envoyée
+func (r *assetReservationRepository) FindOverdueUnnotified(now time.Time) ([]models.AssetReservation, error) {
+	var reservations []models.AssetReservation
+	err := database.DB.Preload("Asset").Preload("Requester").
+		Where("status = ? AND end_at < ? AND overdue_notified_at IS NULL", models.AssetReservationCheckedOut, now).
+		Order("end_at").Find(&reservations).Error
+	return reservations, err
+}
+
+// MarkDueReminderSent enregistre l'envoi du rappel de retour
+func (r *assetReservationRepository) MarkDueReminderSent(id uint, at time.Time) error {
+	return database.DB.Model(&models.AssetReservation{}).Where("id = ?", id).UpdateColumn("due_reminder_sent_at", at).Error
+}
+
+// MarkOverdueNotified enregistre l'envoi de l'alerte de retard
+func (r *assetReservationRepository) MarkOverdueNotified(id uint, at time.Time) error {
+	return database.DB.Model(&models.AssetReservation{}).Where("id = ?", id).UpdateColumn("overdue_notified_at", at).Error
+}
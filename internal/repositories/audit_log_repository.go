@@ -1,13 +1,28 @@
 package repositories
 
 import (
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/audit"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// AuditLogFilter critères de sélection des logs d'audit (export)
+type AuditLogFilter struct {
+	From       *time.Time // Borne de début incluse (created_at)
+	To         *time.Time // Borne de fin exclue (created_at)
+	UserID     *uint
+	Action     string
+	EntityType string
+	EntityID   *uint
+}
+
 // AuditLogRepository interface pour les opérations sur les logs d'audit
 type AuditLogRepository interface {
 	Create(log *models.AuditLog) error
@@ -19,6 +34,16 @@ type AuditLogRepository interface {
 	FindRecent(scope interface{}, limit int) ([]models.AuditLog, error)
 	// FindPaginated récupère les logs d'audit avec pagination et filtres, et renvoie aussi le total
 	FindPaginated(scope interface{}, page, limit int, userID *uint, action, entityType string) ([]models.AuditLog, int64, error)
+	// FindForExport récupère une page de logs filtrés d'ID supérieur à afterID, par ordre d'insertion (export en flux)
+	FindForExport(scope interface{}, filter AuditLogFilter, afterID uint, limit int) ([]models.AuditLog, error)
+	// FindOlderThan récupère les logs les plus anciens créés avant une date (archivage)
+	FindOlderThan(olderThan time.Time, limit int) ([]models.AuditLog, error)
+	// DeleteArchived supprime les logs créés avant une date jusqu'à l'ID maxID inclus, une fois archivés
+	DeleteArchived(olderThan time.Time, maxID uint) (int64, error)
+	// FindChain récupère les entrées d'ID supérieur à afterID par ordre d'insertion, sans relations (vérification du chaînage)
+	FindChain(afterID uint, limit int) ([]models.AuditLog, error)
+	// FindSnapshot lit la ligne courante d'une table (instantané avant/après d'une modification)
+	FindSnapshot(table string, id uint) (map[string]interface{}, error)
 	Delete(id uint) error
 	DeleteOld(olderThan time.Time) error
 }
@@ -31,15 +56,40 @@ func NewAuditLogRepository() AuditLogRepository {
 	return &auditLogRepository{}
 }
 
-// Create crée un nouveau log d'audit
+// auditChainMutex sérialise les insertions de l'instance : chaque entrée est chaînée à la précédente
+var auditChainMutex sync.Mutex
+
+// Create crée un nouveau log d'audit chaîné au dernier log (empreinte de l'entrée précédente incluse dans la sienne)
 func (r *auditLogRepository) Create(log *models.AuditLog) error {
-	return database.DB.Create(log).Error
+	auditChainMutex.Lock()
+	defer auditChainMutex.Unlock()
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var last models.AuditLog
+		err := tx.Select("id", "hash").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("hash <> ''").
+			Order("id DESC").
+			Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Précision à la seconde : la date relue en base doit redonner la même empreinte
+		if log.CreatedAt.IsZero() {
+			log.CreatedAt = time.Now()
+		}
+		log.CreatedAt = log.CreatedAt.Truncate(time.Second)
+		log.PrevHash = last.Hash
+		log.Hash = audit.Hash(log)
+		return tx.Create(log).Error
+	})
 }
 
 // FindByID trouve un log d'audit par son ID
 func (r *auditLogRepository) FindByID(id uint) (*models.AuditLog, error) {
 	var auditLog models.AuditLog
-	err := database.DB.Preload("User").Preload("User.Role").First(&auditLog, id).Error
+	err := database.DB.Preload("User").Preload("User.Role").Preload("OnBehalfOf").First(&auditLog, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +103,7 @@ func (r *auditLogRepository) FindByUserID(scopeParam interface{}, userID uint) (
 	
 	// Construire la requête de base
 	query := database.DB.Model(&models.AuditLog{}).
-		Preload("User").Preload("User.Role").
+		Preload("User").Preload("User.Role").Preload("OnBehalfOf").
 		Where("audit_logs.user_id = ?", userID)
 	
 	// Appliquer le scope si fourni
@@ -74,7 +124,7 @@ func (r *auditLogRepository) FindByEntity(scopeParam interface{}, entityType str
 	
 	// Construire la requête de base
 	query := database.DB.Model(&models.AuditLog{}).
-		Preload("User").Preload("User.Role").
+		Preload("User").Preload("User.Role").Preload("OnBehalfOf").
 		Where("audit_logs.entity_type = ? AND audit_logs.entity_id = ?", entityType, entityID)
 	
 	// Appliquer le scope si fourni
@@ -95,7 +145,7 @@ func (r *auditLogRepository) FindByAction(scopeParam interface{}, action string)
 	
 	// Construire la requête de base
 	query := database.DB.Model(&models.AuditLog{}).
-		Preload("User").Preload("User.Role").
+		Preload("User").Preload("User.Role").Preload("OnBehalfOf").
 		Where("audit_logs.action = ?", action)
 	
 	// Appliquer le scope si fourni
@@ -116,7 +166,7 @@ func (r *auditLogRepository) FindByDateRange(scopeParam interface{}, startDate,
 	
 	// Construire la requête de base
 	query := database.DB.Model(&models.AuditLog{}).
-		Preload("User").Preload("User.Role").
+		Preload("User").Preload("User.Role").Preload("OnBehalfOf").
 		Where("audit_logs.created_at >= ? AND audit_logs.created_at <= ?", startDate, endDate)
 	
 	// Appliquer le scope si fourni
@@ -137,7 +187,7 @@ func (r *auditLogRepository) FindRecent(scopeParam interface{}, limit int) ([]mo
 	
 	// Construire la requête de base
 	query := database.DB.Model(&models.AuditLog{}).
-		Preload("User").Preload("User.Role")
+		Preload("User").Preload("User.Role").Preload("OnBehalfOf")
 	
 	// Appliquer le scope si fourni
 	if scopeParam != nil {
@@ -198,6 +248,7 @@ func (r *auditLogRepository) FindPaginated(scopeParam interface{}, page, limit i
 	if err := query.
 		Preload("User").
 		Preload("User.Role").
+		Preload("OnBehalfOf").
 		Order("audit_logs.created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -208,6 +259,73 @@ func (r *auditLogRepository) FindPaginated(scopeParam interface{}, page, limit i
 	return logs, total, nil
 }
 
+// FindForExport récupère une page de logs filtrés selon le scope
+func (r *auditLogRepository) FindForExport(scopeParam interface{}, filter AuditLogFilter, afterID uint, limit int) ([]models.AuditLog, error) {
+	query := database.DB.Model(&models.AuditLog{}).Where("audit_logs.id > ?", afterID)
+	if filter.From != nil {
+		query = query.Where("audit_logs.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("audit_logs.created_at < ?", *filter.To)
+	}
+	if filter.UserID != nil {
+		query = query.Where("audit_logs.user_id = ?", *filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("audit_logs.action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("audit_logs.entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("audit_logs.entity_id = ?", *filter.EntityID)
+	}
+
+	// Appliquer le scope si fourni (permissions audit)
+	if scopeParam != nil {
+		if queryScope, ok := scopeParam.(*scope.QueryScope); ok {
+			query = scope.ApplyAuditScope(query, queryScope)
+		}
+	}
+
+	var logs []models.AuditLog
+	err := query.Select("audit_logs.*").
+		Preload("User").Preload("User.Role").Preload("OnBehalfOf").
+		Order("audit_logs.id ASC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// FindOlderThan récupère les logs les plus anciens créés avant une date
+func (r *auditLogRepository) FindOlderThan(olderThan time.Time, limit int) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	err := database.DB.Where("created_at < ?", olderThan).Order("id ASC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// DeleteArchived supprime les logs archivés
+func (r *auditLogRepository) DeleteArchived(olderThan time.Time, maxID uint) (int64, error) {
+	result := database.DB.Where("created_at < ? AND id <= ?", olderThan, maxID).Delete(&models.AuditLog{})
+	return result.RowsAffected, result.Error
+}
+
+// FindChain récupère une page d'entrées par ordre d'insertion
+func (r *auditLogRepository) FindChain(afterID uint, limit int) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	err := database.DB.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// FindSnapshot lit une ligne par son ID (colonnes brutes)
+func (r *auditLogRepository) FindSnapshot(table string, id uint) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if err := database.DB.Table(table).Where("id = ?", id).Take(&values).Error; err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 // Delete supprime un log d'audit
 func (r *auditLogRepository) Delete(id uint) error {
 	return database.DB.Delete(&models.AuditLog{}, id).Error
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BusinessCalendarRepository interface pour les opérations sur les calendriers ouvrés et leurs jours fériés
+type BusinessCalendarRepository interface {
+	Create(calendar *models.BusinessCalendar) error
+	FindByID(id uint) (*models.BusinessCalendar, error)
+	FindAll() ([]models.BusinessCalendar, error)
+	FindActive() ([]models.BusinessCalendar, error)
+	FindByFilialeID(filialeID *uint) (*models.BusinessCalendar, error) // Nil = calendrier par défaut
+	Update(calendar *models.BusinessCalendar) error
+	Delete(id uint) error
+	CreateHoliday(holiday *models.BusinessHoliday) error
+	FindHolidayByID(id uint) (*models.BusinessHoliday, error)
+	DeleteHoliday(id uint) error
+}
+
+// businessCalendarRepository implémente BusinessCalendarRepository
+type businessCalendarRepository struct{}
+
+// NewBusinessCalendarRepository crée une nouvelle instance de BusinessCalendarRepository
+func NewBusinessCalendarRepository() BusinessCalendarRepository {
+	return &businessCalendarRepository{}
+}
+
+// preloadBusinessCalendar charge la filiale et les jours fériés (par date) d'un calendrier
+func preloadBusinessCalendar(db *gorm.DB) *gorm.DB {
+	return db.Preload("Filiale").Preload("Holidays", func(db *gorm.DB) *gorm.DB {
+		return db.Order("business_holidays.date ASC")
+	})
+}
+
+// Create crée un calendrier avec ses jours fériés
+func (r *businessCalendarRepository) Create(calendar *models.BusinessCalendar) error {
+	return database.DB.Omit("Filiale").Create(calendar).Error
+}
+
+// FindByID récupère un calendrier par son ID
+func (r *businessCalendarRepository) FindByID(id uint) (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	if err := preloadBusinessCalendar(database.DB).First(&calendar, id).Error; err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// FindAll récupère tous les calendriers (calendrier par défaut en premier)
+func (r *businessCalendarRepository) FindAll() ([]models.BusinessCalendar, error) {
+	var calendars []models.BusinessCalendar
+	err := preloadBusinessCalendar(database.DB).Order("filiale_id IS NOT NULL, name ASC").Find(&calendars).Error
+	return calendars, err
+}
+
+// FindActive récupère les calendriers actifs
+func (r *businessCalendarRepository) FindActive() ([]models.BusinessCalendar, error) {
+	var calendars []models.BusinessCalendar
+	err := preloadBusinessCalendar(database.DB).Where("is_active = ?", true).Find(&calendars).Error
+	return calendars, err
+}
+
+// FindByFilialeID récupère le calendrier d'une filiale (ou le calendrier par défaut si filialeID est nil)
+func (r *businessCalendarRepository) FindByFilialeID(filialeID *uint) (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	query := database.DB
+	if filialeID == nil {
+		query = query.Where("filiale_id IS NULL")
+	} else {
+		query = query.Where("filiale_id = ?", *filialeID)
+	}
+	if err := query.First(&calendar).Error; err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// Update met à jour un calendrier (hors jours fériés)
+func (r *businessCalendarRepository) Update(calendar *models.BusinessCalendar) error {
+	return database.DB.Omit(clause.Associations).Save(calendar).Error
+}
+
+// Delete supprime un calendrier et ses jours fériés
+func (r *businessCalendarRepository) Delete(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("calendar_id = ?", id).Delete(&models.BusinessHoliday{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.BusinessCalendar{}, id).Error
+	})
+}
+
+// CreateHoliday ajoute un jour férié à un calendrier
+func (r *businessCalendarRepository) CreateHoliday(holiday *models.BusinessHoliday) error {
+	return database.DB.Create(holiday).Error
+}
+
+// FindHolidayByID récupère un jour férié par son ID
+func (r *businessCalendarRepository) FindHolidayByID(id uint) (*models.BusinessHoliday, error) {
+	var holiday models.BusinessHoliday
+	if err := database.DB.First(&holiday, id).Error; err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// DeleteHoliday supprime un jour férié
+func (r *businessCalendarRepository) DeleteHoliday(id uint) error {
+	return database.DB.Delete(&models.BusinessHoliday{}, id).Error
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// CalendarConnectionRepository interface pour les opérations sur les agendas connectés et les événements publiés
+type CalendarConnectionRepository interface {
+	Create(connection *models.CalendarConnection) error
+	Update(connection *models.CalendarConnection) error
+	FindByID(id uint) (*models.CalendarConnection, error)
+	FindByUser(userID uint) ([]models.CalendarConnection, error)
+	FindByUserAndProvider(userID uint, provider string) (*models.CalendarConnection, error)
+	FindSyncableIDs() ([]uint, error)
+	Delete(id uint) error
+	FindLinks(connectionID uint) ([]models.CalendarEventLink, error)
+	SaveLink(link *models.CalendarEventLink) error
+	DeleteLink(id uint) error
+	FindAssignedProjectTasks(userID uint, since time.Time) ([]models.ProjectTask, error)
+}
+
+// calendarConnectionRepository implémente CalendarConnectionRepository
+type calendarConnectionRepository struct{}
+
+// NewCalendarConnectionRepository crée une nouvelle instance de CalendarConnectionRepository
+func NewCalendarConnectionRepository() CalendarConnectionRepository {
+	return &calendarConnectionRepository{}
+}
+
+// Create crée une connexion
+func (r *calendarConnectionRepository) Create(connection *models.CalendarConnection) error {
+	return database.DB.Create(connection).Error
+}
+
+// Update met à jour une connexion
+func (r *calendarConnectionRepository) Update(connection *models.CalendarConnection) error {
+	return database.DB.Save(connection).Error
+}
+
+// FindByID trouve une connexion par son ID
+func (r *calendarConnectionRepository) FindByID(id uint) (*models.CalendarConnection, error) {
+	var connection models.CalendarConnection
+	if err := database.DB.First(&connection, id).Error; err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+// FindByUser récupère les connexions d'un utilisateur
+func (r *calendarConnectionRepository) FindByUser(userID uint) ([]models.CalendarConnection, error) {
+	var connections []models.CalendarConnection
+	err := database.DB.Where("user_id = ?", userID).Order("provider ASC").Find(&connections).Error
+	return connections, err
+}
+
+// FindByUserAndProvider trouve la connexion d'un utilisateur à un fournisseur
+func (r *calendarConnectionRepository) FindByUserAndProvider(userID uint, provider string) (*models.CalendarConnection, error) {
+	var connection models.CalendarConnection
+	if err := database.DB.Where("user_id = ? AND provider = ?", userID, provider).First(&connection).Error; err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+// FindSyncableIDs récupère les IDs des connexions à synchroniser (autorisation valide, utilisateur actif)
+func (r *calendarConnectionRepository) FindSyncableIDs() ([]uint, error) {
+	var ids []uint
+	err := database.DB.Model(&models.CalendarConnection{}).
+		Joins("JOIN users ON users.id = calendar_connections.user_id AND users.is_active = ? AND users.deleted_at IS NULL", true).
+		Where("calendar_connections.status <> ?", models.CalendarConnectionRevoked).
+		Pluck("calendar_connections.id", &ids).Error
+	return ids, err
+}
+
+// Delete supprime une connexion et ses liens d'événements
+func (r *calendarConnectionRepository) Delete(id uint) error {
+	if err := database.DB.Where("connection_id = ?", id).Delete(&models.CalendarEventLink{}).Error; err != nil {
+		return err
+	}
+	return database.DB.Delete(&models.CalendarConnection{}, id).Error
+}
+
+// FindLinks récupère les événements publiés dans un agenda
+func (r *calendarConnectionRepository) FindLinks(connectionID uint) ([]models.CalendarEventLink, error) {
+	var links []models.CalendarEventLink
+	err := database.DB.Where("connection_id = ?", connectionID).Find(&links).Error
+	return links, err
+}
+
+// SaveLink crée ou met à jour un lien d'événement
+func (r *calendarConnectionRepository) SaveLink(link *models.CalendarEventLink) error {
+	return database.DB.Save(link).Error
+}
+
+// DeleteLink supprime un lien d'événement
+func (r *calendarConnectionRepository) DeleteLink(id uint) error {
+	return database.DB.Delete(&models.CalendarEventLink{}, id).Error
+}
+
+// FindAssignedProjectTasks récupère les tâches non clôturées avec échéance assignées à l'utilisateur (directement ou comme co-assigné)
+func (r *calendarConnectionRepository) FindAssignedProjectTasks(userID uint, since time.Time) ([]models.ProjectTask, error) {
+	var tasks []models.ProjectTask
+	err := database.DB.Preload("Project").
+		Where("project_tasks.due_date IS NOT NULL AND project_tasks.due_date >= ?", since).
+		Where("project_tasks.status <> ?", "cloture").
+		Where("project_tasks.assigned_to_id = ? OR project_tasks.id IN (?)", userID,
+			database.DB.Model(&models.ProjectTaskAssignee{}).Select("project_task_id").Where("user_id = ?", userID)).
+		Order("project_tasks.due_date ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
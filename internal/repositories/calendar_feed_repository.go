@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// CalendarFeedRepository interface pour les opérations sur les flux de calendrier et leur contenu
+type CalendarFeedRepository interface {
+	Create(feed *models.CalendarFeed) error
+	FindByID(id uint) (*models.CalendarFeed, error)
+	FindByUser(userID uint) ([]models.CalendarFeed, error)
+	FindByTokenHash(tokenHash string) (*models.CalendarFeed, error)
+	UpdateLastFetched(id uint, at time.Time) error
+	Delete(id uint) error
+	FindDepartmentUserIDs(departmentID uint) ([]uint, error)
+	FindOpenTicketSLAs(userIDs []uint) ([]models.TicketSLA, error)
+	FindPlannedChanges(userIDs []uint, since time.Time) ([]models.Change, error)
+	FindProjectPhases(userIDs []uint, since time.Time) ([]models.ProjectPhase, error)
+	FindProjects(userIDs []uint, since time.Time) ([]models.Project, error)
+}
+
+// calendarFeedRepository implémente CalendarFeedRepository
+type calendarFeedRepository struct{}
+
+// NewCalendarFeedRepository crée une nouvelle instance de CalendarFeedRepository
+func NewCalendarFeedRepository() CalendarFeedRepository {
+	return &calendarFeedRepository{}
+}
+
+// Create crée un flux
+func (r *calendarFeedRepository) Create(feed *models.CalendarFeed) error {
+	return database.DB.Create(feed).Error
+}
+
+// FindByID trouve un flux par son ID
+func (r *calendarFeedRepository) FindByID(id uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	if err := database.DB.First(&feed, id).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// FindByUser récupère les flux d'un utilisateur
+func (r *calendarFeedRepository) FindByUser(userID uint) ([]models.CalendarFeed, error) {
+	var feeds []models.CalendarFeed
+	err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&feeds).Error
+	return feeds, err
+}
+
+// FindByTokenHash trouve un flux par le hash de son token
+func (r *calendarFeedRepository) FindByTokenHash(tokenHash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	if err := database.DB.Preload("User").Preload("User.Filiale").Where("token_hash = ?", tokenHash).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// UpdateLastFetched enregistre la dernière lecture d'un flux
+func (r *calendarFeedRepository) UpdateLastFetched(id uint, at time.Time) error {
+	return database.DB.Model(&models.CalendarFeed{}).Where("id = ?", id).Update("last_fetched_at", at).Error
+}
+
+// Delete supprime un flux
+func (r *calendarFeedRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.CalendarFeed{}, id).Error
+}
+
+// FindDepartmentUserIDs récupère les IDs des utilisateurs actifs d'un département
+func (r *calendarFeedRepository) FindDepartmentUserIDs(departmentID uint) ([]uint, error) {
+	var ids []uint
+	err := database.DB.Model(&models.User{}).Where("department_id = ? AND is_active = ?", departmentID, true).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindOpenTicketSLAs récupère les SLA des tickets non résolus assignés (directement ou comme co-assigné) aux utilisateurs
+func (r *calendarFeedRepository) FindOpenTicketSLAs(userIDs []uint) ([]models.TicketSLA, error) {
+	var slas []models.TicketSLA
+	err := database.DB.Preload("Ticket").
+		Joins("JOIN tickets ON tickets.id = ticket_sla.ticket_id AND tickets.deleted_at IS NULL").
+		Where("tickets.status NOT IN ?", []string{"resolu", "cloture"}).
+		Where("ticket_sla.actual_time IS NULL").
+		Where("tickets.assigned_to_id IN ? OR tickets.id IN (?)", userIDs,
+			database.DB.Model(&models.TicketAssignee{}).Select("ticket_id").Where("user_id IN ?", userIDs)).
+		Order("ticket_sla.target_time ASC").
+		Find(&slas).Error
+	return slas, err
+}
+
+// FindPlannedChanges récupère les changements planifiés dont les utilisateurs sont responsables ou assignés, terminés après since
+func (r *calendarFeedRepository) FindPlannedChanges(userIDs []uint, since time.Time) ([]models.Change, error) {
+	var changes []models.Change
+	err := database.DB.Preload("Ticket").
+		Joins("JOIN tickets ON tickets.id = changes.ticket_id AND tickets.deleted_at IS NULL").
+		Where("changes.planned_start IS NOT NULL").
+		Where("COALESCE(changes.planned_end, changes.planned_start) >= ?", since).
+		Where("changes.responsible_id IN ? OR tickets.assigned_to_id IN ?", userIDs, userIDs).
+		Order("changes.planned_start ASC").
+		Find(&changes).Error
+	return changes, err
+}
+
+// FindProjectPhases récupère les phases datées des projets actifs dont les utilisateurs sont membres, finissant après since
+func (r *calendarFeedRepository) FindProjectPhases(userIDs []uint, since time.Time) ([]models.ProjectPhase, error) {
+	var phases []models.ProjectPhase
+	err := database.DB.Preload("Project").
+		Joins("JOIN projects ON projects.id = project_phases.project_id").
+		Where("projects.status = ?", "active").
+		Where("project_phases.end_date IS NOT NULL AND project_phases.end_date >= ?", since).
+		Where("project_phases.status <> ?", "cancelled").
+		Where("project_phases.project_id IN (?)", projectIDsForUsers(userIDs)).
+		Order("project_phases.end_date ASC").
+		Find(&phases).Error
+	return phases, err
+}
+
+// FindProjects récupère les projets actifs dont les utilisateurs sont membres et dont l'échéance est après since
+func (r *calendarFeedRepository) FindProjects(userIDs []uint, since time.Time) ([]models.Project, error) {
+	var projects []models.Project
+	err := database.DB.
+		Where("status = ?", "active").
+		Where("end_date IS NOT NULL AND end_date >= ?", since).
+		Where("id IN (?)", projectIDsForUsers(userIDs)).
+		Order("end_date ASC").
+		Find(&projects).Error
+	return projects, err
+}
+
+// projectIDsForUsers sous-requête des projets dont les utilisateurs sont membres, chef de projet ou lead
+func projectIDsForUsers(userIDs []uint) interface{} {
+	return database.DB.Model(&models.Project{}).Select("projects.id").
+		Where("projects.project_manager_id IN ? OR projects.lead_id IN ? OR projects.id IN (?)", userIDs, userIDs,
+			database.DB.Model(&models.ProjectMember{}).Select("project_id").Where("user_id IN ?", userIDs))
+}
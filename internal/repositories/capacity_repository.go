@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+)
+
+// maxCapacityUsers nombre maximal d'utilisateurs couverts par un plan de charge
+const maxCapacityUsers = 500
+
+// CapacityFilter critères de sélection des utilisateurs d'un plan de charge
+type CapacityFilter struct {
+	DepartmentID *uint
+	FilialeID    *uint
+	UserID       *uint
+}
+
+// CapacityTaskLoad reste à faire d'une tâche de projet ouverte pour un de ses assignés
+type CapacityTaskLoad struct {
+	UserID        uint
+	TaskID        uint
+	Remaining     int // Estimation moins temps réel, en minutes (pour toute la tâche)
+	AssigneeCount int
+	DueDate       *time.Time
+}
+
+// CapacityTicketLoad reste à faire d'un ticket ouvert pour un de ses assignés
+type CapacityTicketLoad struct {
+	UserID        uint
+	TicketID      uint
+	Remaining     int // Estimation moins temps réel, en minutes (pour tout le ticket)
+	AssigneeCount int
+}
+
+// CapacityDeclaredTime temps déclaré par un utilisateur sur une journée
+type CapacityDeclaredTime struct {
+	UserID  uint
+	Date    time.Time
+	Minutes int
+}
+
+// CapacityRepository interface pour les données du plan de charge des ressources
+type CapacityRepository interface {
+	FindUsers(scope interface{}, filter CapacityFilter) ([]models.User, error)
+	FindOpenTaskLoads(userIDs []uint) ([]CapacityTaskLoad, error)
+	FindOpenTicketLoads(userIDs []uint) ([]CapacityTicketLoad, error)
+	FindDeclaredTime(userIDs []uint, from, to time.Time) ([]CapacityDeclaredTime, error)
+}
+
+// capacityRepository implémente CapacityRepository
+type capacityRepository struct{}
+
+// NewCapacityRepository crée une nouvelle instance de CapacityRepository
+func NewCapacityRepository() CapacityRepository {
+	return &capacityRepository{}
+}
+
+// FindUsers récupère les utilisateurs actifs visibles selon le périmètre et les filtres, par nom (avec rôle et département)
+func (r *capacityRepository) FindUsers(scopeParam interface{}, filter CapacityFilter) ([]models.User, error) {
+	var users []models.User
+	query := database.DB.Model(&models.User{}).Where("users.is_active = ?", true)
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		query = scope.ApplyUserScope(query, queryScope)
+	}
+	if filter.DepartmentID != nil {
+		query = query.Where("users.department_id = ?", *filter.DepartmentID)
+	}
+	if filter.FilialeID != nil {
+		query = query.Where("users.filiale_id = ?", *filter.FilialeID)
+	}
+	if filter.UserID != nil {
+		query = query.Where("users.id = ?", *filter.UserID)
+	}
+	err := query.Preload("Role").Preload("Department").Order("users.last_name ASC, users.first_name ASC, users.id ASC").
+		Limit(maxCapacityUsers).
+		Find(&users).Error
+	return users, err
+}
+
+// FindOpenTaskLoads récupère le reste à faire des tâches de projet non clôturées assignées aux utilisateurs (hors projets archivés)
+func (r *capacityRepository) FindOpenTaskLoads(userIDs []uint) ([]CapacityTaskLoad, error) {
+	var loads []CapacityTaskLoad
+	if len(userIDs) == 0 {
+		return loads, nil
+	}
+	err := database.DB.Table("project_task_assignees AS pta").
+		Select("pta.user_id, pt.id AS task_id, pt.estimated_time - pt.actual_time AS remaining, pt.due_date, "+
+			"(SELECT COUNT(*) FROM project_task_assignees c WHERE c.project_task_id = pt.id) AS assignee_count").
+		Joins("JOIN project_tasks pt ON pt.id = pta.project_task_id").
+		Joins("JOIN projects p ON p.id = pt.project_id AND p.status <> ?", models.ProjectStatusArchived).
+		Where("pta.user_id IN ? AND pt.status <> ? AND pt.estimated_time > pt.actual_time", userIDs, "cloture").
+		Scan(&loads).Error
+	return loads, err
+}
+
+// FindOpenTicketLoads récupère le reste à faire des tickets ouverts assignés aux utilisateurs
+// (assignés multiples, sinon assigné principal)
+func (r *capacityRepository) FindOpenTicketLoads(userIDs []uint) ([]CapacityTicketLoad, error) {
+	var loads []CapacityTicketLoad
+	if len(userIDs) == 0 {
+		return loads, nil
+	}
+	closedStatuses := []string{"cloture", "fusionne"}
+	remaining := "t.estimated_time - COALESCE(t.actual_time, 0)"
+
+	var multi []CapacityTicketLoad
+	if err := database.DB.Table("ticket_assignees AS ta").
+		Select("ta.user_id, t.id AS ticket_id, "+remaining+" AS remaining, "+
+			"(SELECT COUNT(*) FROM ticket_assignees c WHERE c.ticket_id = t.id) AS assignee_count").
+		Joins("JOIN tickets t ON t.id = ta.ticket_id AND t.deleted_at IS NULL").
+		Where("ta.user_id IN ? AND t.status NOT IN ? AND t.estimated_time > COALESCE(t.actual_time, 0)", userIDs, closedStatuses).
+		Scan(&multi).Error; err != nil {
+		return nil, err
+	}
+	loads = append(loads, multi...)
+
+	var single []CapacityTicketLoad
+	if err := database.DB.Table("tickets AS t").
+		Select("t.assigned_to_id AS user_id, t.id AS ticket_id, "+remaining+" AS remaining, 1 AS assignee_count").
+		Where("t.deleted_at IS NULL AND t.assigned_to_id IN ? AND t.status NOT IN ? AND t.estimated_time > COALESCE(t.actual_time, 0)", userIDs, closedStatuses).
+		Where("NOT EXISTS (SELECT 1 FROM ticket_assignees c WHERE c.ticket_id = t.id)").
+		Scan(&single).Error; err != nil {
+		return nil, err
+	}
+	return append(loads, single...), nil
+}
+
+// FindDeclaredTime récupère le temps déclaré par utilisateur et par jour sur [from, to]
+func (r *capacityRepository) FindDeclaredTime(userIDs []uint, from, to time.Time) ([]CapacityDeclaredTime, error) {
+	var declared []CapacityDeclaredTime
+	if len(userIDs) == 0 {
+		return declared, nil
+	}
+	err := database.DB.Model(&models.TimeEntry{}).
+		Select("user_id, date, SUM(time_spent) AS minutes").
+		Where("user_id IN ? AND date >= ? AND date <= ?", userIDs, from, to).
+		Group("user_id, date").
+		Scan(&declared).Error
+	return declared, err
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ticketStatusSinceSQL date d'entrée d'un ticket dans son statut actuel (dernier changement de statut historisé, sinon création)
+const ticketStatusSinceSQL = "COALESCE((SELECT MAX(ticket_history.created_at) FROM ticket_history WHERE ticket_history.ticket_id = tickets.id AND ticket_history.field_name = 'status'), tickets.created_at)"
+
+// maxEscalationCandidates nombre maximal de tickets escaladés par règle et par passage
+const maxEscalationCandidates = 200
+
+// EscalationRuleRepository interface pour les opérations sur les règles d'escalade et leurs applications
+type EscalationRuleRepository interface {
+	Create(rule *models.EscalationRule) error
+	FindByID(id uint) (*models.EscalationRule, error)
+	FindAll(filialeID *uint, category string) ([]models.EscalationRule, error)
+	FindActiveByTrigger(trigger string) ([]models.EscalationRule, error)
+	Update(rule *models.EscalationRule) error
+	Delete(id uint) error
+	FindStatusDurationCandidates(rule *models.EscalationRule, now time.Time) ([]uint, error) // IDs des tickets restés trop longtemps dans le statut, pas encore escaladés
+	HasEscalation(ruleID, ticketID uint) (bool, error)
+	CreateEscalation(escalation *models.TicketEscalation) error
+}
+
+// escalationRuleRepository implémente EscalationRuleRepository
+type escalationRuleRepository struct{}
+
+// NewEscalationRuleRepository crée une nouvelle instance de EscalationRuleRepository
+func NewEscalationRuleRepository() EscalationRuleRepository {
+	return &escalationRuleRepository{}
+}
+
+// Create crée une règle d'escalade
+func (r *escalationRuleRepository) Create(rule *models.EscalationRule) error {
+	return database.DB.Omit(clause.Associations).Create(rule).Error
+}
+
+// FindByID récupère une règle par son ID
+func (r *escalationRuleRepository) FindByID(id uint) (*models.EscalationRule, error) {
+	var rule models.EscalationRule
+	if err := database.DB.Preload("Filiale").First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindAll récupère les règles, filtrées par filiale (règles de la filiale et règles globales) et par catégorie
+func (r *escalationRuleRepository) FindAll(filialeID *uint, category string) ([]models.EscalationRule, error) {
+	var rules []models.EscalationRule
+	query := database.DB.Preload("Filiale")
+	if filialeID != nil {
+		query = query.Where("filiale_id = ? OR filiale_id IS NULL", *filialeID)
+	}
+	if category != "" {
+		query = query.Where("category = ? OR category = ''", category)
+	}
+	err := query.Order("name ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindActiveByTrigger récupère les règles actives d'un déclencheur
+func (r *escalationRuleRepository) FindActiveByTrigger(trigger string) ([]models.EscalationRule, error) {
+	var rules []models.EscalationRule
+	err := database.DB.Where("trigger_type = ? AND is_active = ?", trigger, true).Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// Update met à jour une règle
+func (r *escalationRuleRepository) Update(rule *models.EscalationRule) error {
+	return database.DB.Omit(clause.Associations).Save(rule).Error
+}
+
+// Delete supprime une règle et l'historique de ses applications
+func (r *escalationRuleRepository) Delete(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", id).Delete(&models.TicketEscalation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.EscalationRule{}, id).Error
+	})
+}
+
+// FindStatusDurationCandidates récupère les tickets du périmètre de la règle présents dans le statut depuis plus de ThresholdHours
+// et que la règle n'a pas déjà escaladés depuis leur entrée dans ce statut
+func (r *escalationRuleRepository) FindStatusDurationCandidates(rule *models.EscalationRule, now time.Time) ([]uint, error) {
+	query := database.DB.Model(&models.Ticket{}).
+		Where("tickets.status = ?", rule.Status).
+		Where(ticketStatusSinceSQL+" <= ?", now.Add(-time.Duration(rule.ThresholdHours)*time.Hour)).
+		Where("NOT EXISTS (SELECT 1 FROM ticket_escalations WHERE ticket_escalations.rule_id = ? AND ticket_escalations.ticket_id = tickets.id AND ticket_escalations.created_at >= "+ticketStatusSinceSQL+")", rule.ID)
+	if rule.FilialeID != nil {
+		query = query.Where("tickets.filiale_id = ?", *rule.FilialeID)
+	}
+	if rule.Category != "" {
+		query = query.Where("tickets.category = ?", rule.Category)
+	}
+	var ticketIDs []uint
+	err := query.Order("tickets.id ASC").Limit(maxEscalationCandidates).Pluck("tickets.id", &ticketIDs).Error
+	return ticketIDs, err
+}
+
+// HasEscalation indique si la règle a déjà été appliquée au ticket
+func (r *escalationRuleRepository) HasEscalation(ruleID, ticketID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.TicketEscalation{}).Where("rule_id = ? AND ticket_id = ?", ruleID, ticketID).Count(&count).Error
+	return count > 0, err
+}
+
+// CreateEscalation enregistre l'application d'une règle à un ticket
+func (r *escalationRuleRepository) CreateEscalation(escalation *models.TicketEscalation) error {
+	return database.DB.Create(escalation).Error
+}
@@ -18,6 +18,8 @@ type FilialeSoftwareRepository interface {
 	FindActiveBySoftware(softwareID uint) ([]models.FilialeSoftware, error)
 	Update(deployment *models.FilialeSoftware) error
 	Delete(id uint) error
+	CreateHistory(entry *models.FilialeSoftwareHistory) error
+	FindHistory(deploymentID uint) ([]models.FilialeSoftwareHistory, error)
 }
 
 // filialeSoftwareRepository implémente FilialeSoftwareRepository
@@ -73,11 +75,13 @@ func (r *filialeSoftwareRepository) FindBySoftwareID(softwareID uint) ([]models.
 	return deployments, err
 }
 
-// FindByFilialeAndSoftware trouve un déploiement spécifique pour une filiale et un logiciel
+// FindByFilialeAndSoftware trouve le déploiement en service d'un logiciel chez une filiale
+// (déploiement actif en production en priorité, sinon le plus récent)
 func (r *filialeSoftwareRepository) FindByFilialeAndSoftware(filialeID, softwareID uint) (*models.FilialeSoftware, error) {
 	var deployment models.FilialeSoftware
 	err := database.DB.Preload("Filiale").Preload("Software").
 		Where("filiale_id = ? AND software_id = ?", filialeID, softwareID).
+		Order("is_active DESC, environment = 'production' DESC, deployed_at DESC, id DESC").
 		First(&deployment).Error
 	if err != nil {
 		return nil, err
@@ -124,3 +128,18 @@ func (r *filialeSoftwareRepository) Update(deployment *models.FilialeSoftware) e
 func (r *filialeSoftwareRepository) Delete(id uint) error {
 	return database.DB.Delete(&models.FilialeSoftware{}, id).Error
 }
+
+// CreateHistory enregistre une étape dans l'historique d'un déploiement
+func (r *filialeSoftwareRepository) CreateHistory(entry *models.FilialeSoftwareHistory) error {
+	return database.DB.Create(entry).Error
+}
+
+// FindHistory récupère l'historique d'un déploiement (plus ancien d'abord)
+func (r *filialeSoftwareRepository) FindHistory(deploymentID uint) ([]models.FilialeSoftwareHistory, error) {
+	var history []models.FilialeSoftwareHistory
+	err := database.DB.Preload("User").
+		Where("filiale_software_id = ?", deploymentID).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
+}
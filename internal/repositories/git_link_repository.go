@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// GitLinkRepository interface pour les opérations sur les commits et merge requests liés aux tickets
+type GitLinkRepository interface {
+	Create(link *models.TicketGitLink) error
+	Find(ticketID uint, provider, repository, kind, ref string) (*models.TicketGitLink, error)
+	FindByTicketID(ticketID uint) ([]models.TicketGitLink, error)
+	Update(link *models.TicketGitLink) error
+	FindTicketByCode(code string) (*models.Ticket, error)
+}
+
+// gitLinkRepository implémente GitLinkRepository
+type gitLinkRepository struct{}
+
+// NewGitLinkRepository crée une nouvelle instance de GitLinkRepository
+func NewGitLinkRepository() GitLinkRepository {
+	return &gitLinkRepository{}
+}
+
+// Create crée un lien
+func (r *gitLinkRepository) Create(link *models.TicketGitLink) error {
+	return database.DB.Omit("Ticket").Create(link).Error
+}
+
+// Find trouve le lien d'un ticket vers un commit ou une merge request
+func (r *gitLinkRepository) Find(ticketID uint, provider, repository, kind, ref string) (*models.TicketGitLink, error) {
+	var link models.TicketGitLink
+	err := database.DB.Where("ticket_id = ? AND provider = ? AND repository = ? AND kind = ? AND ref = ?", ticketID, provider, repository, kind, ref).
+		First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByTicketID récupère les liens d'un ticket (plus récents d'abord)
+func (r *gitLinkRepository) FindByTicketID(ticketID uint) ([]models.TicketGitLink, error) {
+	var links []models.TicketGitLink
+	err := database.DB.Where("ticket_id = ?", ticketID).Order("created_at DESC").Find(&links).Error
+	return links, err
+}
+
+// Update met à jour un lien
+func (r *gitLinkRepository) Update(link *models.TicketGitLink) error {
+	return database.DB.Omit("Ticket").Save(link).Error
+}
+
+// FindTicketByCode trouve un ticket par son code (champs utiles au rattachement uniquement)
+func (r *gitLinkRepository) FindTicketByCode(code string) (*models.Ticket, error) {
+	var ticket models.Ticket
+	err := database.DB.Select("id", "code", "status", "assigned_to_id", "created_by_id").
+		Where("code = ?", code).First(&ticket).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
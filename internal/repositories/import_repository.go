@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ImportRepository interface pour les opérations sur les imports et leurs tables de correspondance
+type ImportRepository interface {
+	CreateRun(run *models.ImportRun) error
+	CreateRunWithFiles(run *models.ImportRun, files []models.ImportFile) error
+	FindFiles(runID uint) ([]models.ImportFile, error)
+	FindRunByID(id uint) (*models.ImportRun, error)
+	FindRuns(source string, page, limit int) ([]models.ImportRun, int64, error)
+	UpdateRun(run *models.ImportRun) error
+	FindLocalID(source, entityType, externalID string) (uint, bool, error)
+	FindLocalIDs(source, entityType string, externalIDs []string) (map[string]uint, error)
+	CreateMapping(mapping *models.ImportMapping) error
+	HasActiveRun(source string) (bool, error)
+}
+
+// importRepository implémente ImportRepository
+type importRepository struct{}
+
+// NewImportRepository crée une nouvelle instance de ImportRepository
+func NewImportRepository() ImportRepository {
+	return &importRepository{}
+}
+
+// CreateRun crée un import
+func (r *importRepository) CreateRun(run *models.ImportRun) error {
+	return database.DB.Create(run).Error
+}
+
+// CreateRunWithFiles crée un import et les fichiers de ses étapes (en une transaction)
+func (r *importRepository) CreateRunWithFiles(run *models.ImportRun, files []models.ImportFile) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		for i := range files {
+			files[i].ImportRunID = run.ID
+			if err := tx.Create(&files[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindFiles récupère les fichiers d'un import
+func (r *importRepository) FindFiles(runID uint) ([]models.ImportFile, error) {
+	var files []models.ImportFile
+	err := database.DB.Where("import_run_id = ?", runID).Find(&files).Error
+	return files, err
+}
+
+// FindRunByID trouve un import par son ID
+func (r *importRepository) FindRunByID(id uint) (*models.ImportRun, error) {
+	var run models.ImportRun
+	if err := database.DB.First(&run, id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FindRuns récupère les imports (du plus récent au plus ancien), éventuellement filtrés par source
+func (r *importRepository) FindRuns(source string, page, limit int) ([]models.ImportRun, int64, error) {
+	var runs []models.ImportRun
+	var total int64
+	query := database.DB.Model(&models.ImportRun{})
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&runs).Error
+	return runs, total, err
+}
+
+// UpdateRun met à jour un import
+func (r *importRepository) UpdateRun(run *models.ImportRun) error {
+	return database.DB.Omit("CreatedBy").Save(run).Error
+}
+
+// FindLocalID trouve l'enregistrement Kronos associé à un élément de la source
+func (r *importRepository) FindLocalID(source, entityType, externalID string) (uint, bool, error) {
+	var mapping models.ImportMapping
+	err := database.DB.Select("local_id").
+		Where("source = ? AND entity_type = ? AND external_id = ?", source, entityType, externalID).
+		First(&mapping).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return mapping.LocalID, true, nil
+}
+
+// FindLocalIDs trouve les enregistrements associés à plusieurs éléments de la source (external_id -> local_id)
+func (r *importRepository) FindLocalIDs(source, entityType string, externalIDs []string) (map[string]uint, error) {
+	result := make(map[string]uint, len(externalIDs))
+	if len(externalIDs) == 0 {
+		return result, nil
+	}
+	var mappings []models.ImportMapping
+	err := database.DB.Select("external_id", "local_id").
+		Where("source = ? AND entity_type = ? AND external_id IN ?", source, entityType, externalIDs).
+		Find(&mappings).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mappings {
+		result[m.ExternalID] = m.LocalID
+	}
+	return result, nil
+}
+
+// CreateMapping enregistre une correspondance
+func (r *importRepository) CreateMapping(mapping *models.ImportMapping) error {
+	return database.DB.Create(mapping).Error
+}
+
+// HasActiveRun indique si un import de la source est en attente ou en cours
+func (r *importRepository) HasActiveRun(source string) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.ImportRun{}).
+		Where("source = ? AND status IN ?", source, []string{models.ImportStatusPending, models.ImportStatusRunning}).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -34,7 +34,7 @@ func (r *incidentRepository) Create(incident *models.Incident) error {
 // FindByID trouve un incident par son ID avec son ticket
 func (r *incidentRepository) FindByID(id uint) (*models.Incident, error) {
 	var incident models.Incident
-	err := database.DB.Preload("Ticket").Preload("Ticket.CreatedBy").Preload("Ticket.AssignedTo").First(&incident, id).Error
+	err := database.DB.Preload("Ticket").Preload("Ticket.CreatedBy").Preload("Ticket.AssignedTo").Preload("MajorDeclaredBy").First(&incident, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func (r *incidentRepository) FindByID(id uint) (*models.Incident, error) {
 // FindByTicketID trouve un incident par l'ID du ticket
 func (r *incidentRepository) FindByTicketID(ticketID uint) (*models.Incident, error) {
 	var incident models.Incident
-	err := database.DB.Preload("Ticket").Preload("Ticket.CreatedBy").Preload("Ticket.AssignedTo").Where("ticket_id = ?", ticketID).First(&incident).Error
+	err := database.DB.Preload("Ticket").Preload("Ticket.CreatedBy").Preload("Ticket.AssignedTo").Preload("MajorDeclaredBy").Where("ticket_id = ?", ticketID).First(&incident).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// JiraLinkRepository interface pour les opérations sur les liens entre tickets et tickets Jira
+type JiraLinkRepository interface {
+	Create(link *models.TicketJiraLink) error
+	FindByTicketID(ticketID uint) (*models.TicketJiraLink, error)
+	FindByIssueKey(issueKey string) (*models.TicketJiraLink, error)
+	Update(link *models.TicketJiraLink) error
+	DeleteByTicketID(ticketID uint) error
+	CreateCommentLink(link *models.JiraCommentLink) error
+	CommentLinkedByTicketComment(ticketCommentID uint) (bool, error)
+	CommentLinkedByJiraComment(issueKey, jiraCommentID string) (bool, error)
+}
+
+// jiraLinkRepository implémente JiraLinkRepository
+type jiraLinkRepository struct{}
+
+// NewJiraLinkRepository crée une nouvelle instance de JiraLinkRepository
+func NewJiraLinkRepository() JiraLinkRepository {
+	return &jiraLinkRepository{}
+}
+
+// Create crée un lien
+func (r *jiraLinkRepository) Create(link *models.TicketJiraLink) error {
+	return database.DB.Create(link).Error
+}
+
+// FindByTicketID trouve le lien d'un ticket
+func (r *jiraLinkRepository) FindByTicketID(ticketID uint) (*models.TicketJiraLink, error) {
+	var link models.TicketJiraLink
+	if err := database.DB.Where("ticket_id = ?", ticketID).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByIssueKey trouve le lien d'un ticket Jira
+func (r *jiraLinkRepository) FindByIssueKey(issueKey string) (*models.TicketJiraLink, error) {
+	var link models.TicketJiraLink
+	if err := database.DB.Where("issue_key = ?", issueKey).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// Update met à jour un lien
+func (r *jiraLinkRepository) Update(link *models.TicketJiraLink) error {
+	return database.DB.Save(link).Error
+}
+
+// DeleteByTicketID supprime le lien d'un ticket et les correspondances de commentaires associées
+func (r *jiraLinkRepository) DeleteByTicketID(ticketID uint) error {
+	link, err := r.FindByTicketID(ticketID)
+	if err != nil {
+		return err
+	}
+	if err := database.DB.Where("issue_key = ?", link.IssueKey).Delete(&models.JiraCommentLink{}).Error; err != nil {
+		return err
+	}
+	return database.DB.Delete(link).Error
+}
+
+// CreateCommentLink enregistre la correspondance entre un commentaire et un commentaire Jira
+func (r *jiraLinkRepository) CreateCommentLink(link *models.JiraCommentLink) error {
+	return database.DB.Create(link).Error
+}
+
+// CommentLinkedByTicketComment indique si un commentaire de ticket a déjà été synchronisé
+func (r *jiraLinkRepository) CommentLinkedByTicketComment(ticketCommentID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.JiraCommentLink{}).Where("ticket_comment_id = ?", ticketCommentID).Count(&count).Error
+	return count > 0, err
+}
+
+// CommentLinkedByJiraComment indique si un commentaire Jira a déjà été synchronisé
+func (r *jiraLinkRepository) CommentLinkedByJiraComment(issueKey, jiraCommentID string) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.JiraCommentLink{}).
+		Where("issue_key = ? AND jira_comment_id = ?", issueKey, jiraCommentID).
+		Count(&count).Error
+	return count > 0, err
+}
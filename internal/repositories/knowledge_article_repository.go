@@ -1,9 +1,13 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // KnowledgeArticleRepository interface pour les opérations sur les articles de la base de connaissances
@@ -15,9 +19,22 @@ type KnowledgeArticleRepository interface {
 	FindByCategory(scope interface{}, categoryID uint) ([]models.KnowledgeArticle, error)
 	FindByAuthor(scope interface{}, authorID uint) ([]models.KnowledgeArticle, error) // scope peut être *scope.QueryScope ou nil
 	Search(scope interface{}, query string) ([]models.KnowledgeArticle, error)
+	FindVisibleByIDs(scope interface{}, ids []uint) ([]models.KnowledgeArticle, error) // Articles visibles parmi ids (ordre quelconque)
+	FindForIndex(afterID uint, limit int) ([]models.KnowledgeArticle, error)           // Articles par ID croissant, pour l'indexation par lots
 	Update(article *models.KnowledgeArticle) error
 	Delete(id uint) error
 	IncrementViewCount(id uint) error
+	SetSourceTicket(id, ticketID uint) error // Lie l'article au ticket dont la solution a servi à le rédiger
+	CreateAttachment(attachment *models.KnowledgeArticleAttachment) error
+	FindAttachment(articleID, attachmentID uint) (*models.KnowledgeArticleAttachment, error)
+	CreateRevision(revision *models.KnowledgeArticleRevision) error
+	FindRevisions(articleID uint) ([]models.KnowledgeArticleRevision, error)
+	FindRevision(articleID uint, version int) (*models.KnowledgeArticleRevision, error)
+	CountRevisions(articleID uint) (int64, error)
+	FindInReview(reviewerID *uint) ([]models.KnowledgeArticle, error)                // Articles en relecture (d'un relecteur si fourni)
+	FindReviewDue(now time.Time, userID *uint) ([]models.KnowledgeArticle, error)    // Articles publiés dont la révision périodique est échue (relecteur ou auteur si fourni)
+	FindReviewAlerts(now time.Time) ([]models.KnowledgeArticle, error)               // Relectures et révisions échues non encore signalées
+	MarkReviewAlerted(id uint, at time.Time) error
 }
 
 // KnowledgeCategoryRepository interface pour les opérations sur les catégories de la base de connaissances
@@ -48,7 +65,17 @@ func NewKnowledgeCategoryRepository() KnowledgeCategoryRepository {
 }
 
 // Create crée un nouvel article
+// Le statut est déduit de IsPublished s'il n'est pas renseigné (articles créés hors du cycle de relecture)
 func (r *knowledgeArticleRepository) Create(article *models.KnowledgeArticle) error {
+	if article.Version == 0 {
+		article.Version = 1
+	}
+	if article.Status == "" {
+		article.Status = models.KnowledgeStatusDraft
+		if article.IsPublished {
+			article.Status = models.KnowledgeStatusPublished
+		}
+	}
 	return database.DB.Create(article).Error
 }
 
@@ -145,7 +172,7 @@ func (r *knowledgeArticleRepository) FindByAuthor(scopeParam interface{}, author
 	return articles, err
 }
 
-// Search recherche des articles par titre ou contenu
+// Search recherche des articles par titre ou contenu (LIKE, utilisé pour les requêtes sans terme indexable)
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (r *knowledgeArticleRepository) Search(scopeParam interface{}, searchQuery string) ([]models.KnowledgeArticle, error) {
 	var articles []models.KnowledgeArticle
@@ -155,20 +182,45 @@ func (r *knowledgeArticleRepository) Search(scopeParam interface{}, searchQuery
 		Preload("Category").Preload("Author").
 		Where("(knowledge_articles.title LIKE ? OR knowledge_articles.content LIKE ?)", "%"+searchQuery+"%", "%"+searchQuery+"%")
 	
-	// Appliquer le scope si fourni
-	if scopeParam != nil {
-		if queryScope, ok := scopeParam.(*scope.QueryScope); ok {
-			query = scope.ApplyKnowledgeScope(query, queryScope)
-		}
-	}
+	query = applyKnowledgeSearchScope(query, scopeParam)
 	
 	err := query.Order("knowledge_articles.created_at DESC").Find(&articles).Error
 	return articles, err
 }
 
-// Update met à jour un article
+// FindVisibleByIDs récupère les articles visibles parmi les identifiants retournés par le moteur de recherche
+func (r *knowledgeArticleRepository) FindVisibleByIDs(scopeParam interface{}, ids []uint) ([]models.KnowledgeArticle, error) {
+	var articles []models.KnowledgeArticle
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	query := database.DB.Model(&models.KnowledgeArticle{}).
+		Preload("Category").Preload("Author").
+		Where("knowledge_articles.id IN ?", ids)
+	err := applyKnowledgeSearchScope(query, scopeParam).Find(&articles).Error
+	return articles, err
+}
+
+// applyKnowledgeSearchScope applique le scope de l'utilisateur ; sans utilisateur authentifié (route publique),
+// seuls les articles publiés communs à toutes les filiales sont visibles
+func applyKnowledgeSearchScope(query *gorm.DB, scopeParam interface{}) *gorm.DB {
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		return scope.ApplyKnowledgeScope(query, queryScope)
+	}
+	return query.Where("knowledge_articles.is_published = ? AND knowledge_articles.filiale_id IS NULL", true)
+}
+
+// FindForIndex récupère un lot d'articles d'ID supérieur à afterID (contenu inclus, sans relations)
+func (r *knowledgeArticleRepository) FindForIndex(afterID uint, limit int) ([]models.KnowledgeArticle, error) {
+	var articles []models.KnowledgeArticle
+	err := database.DB.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+// Update met à jour un article (les relations préchargées ne sont pas réenregistrées)
 func (r *knowledgeArticleRepository) Update(article *models.KnowledgeArticle) error {
-	return database.DB.Save(article).Error
+	// Les agrégats des évaluations sont tenus à jour par KnowledgeFeedbackRepository
+	return database.DB.Omit(clause.Associations, "helpful_count", "not_helpful_count", "helpful_rate").Save(article).Error
 }
 
 // Delete supprime un article (soft delete)
@@ -181,6 +233,95 @@ func (r *knowledgeArticleRepository) IncrementViewCount(id uint) error {
 	return database.DB.Model(&models.KnowledgeArticle{}).Where("id = ?", id).Update("view_count", database.DB.Raw("view_count + 1")).Error
 }
 
+// SetSourceTicket enregistre le ticket d'origine d'un article (sans modifier sa date de mise à jour)
+func (r *knowledgeArticleRepository) SetSourceTicket(id, ticketID uint) error {
+	return database.DB.Model(&models.KnowledgeArticle{}).Where("id = ?", id).UpdateColumn("source_ticket_id", ticketID).Error
+}
+
+// CreateAttachment crée une pièce jointe d'article
+func (r *knowledgeArticleRepository) CreateAttachment(attachment *models.KnowledgeArticleAttachment) error {
+	return database.DB.Omit(clause.Associations).Create(attachment).Error
+}
+
+// FindAttachment récupère une pièce jointe d'un article
+func (r *knowledgeArticleRepository) FindAttachment(articleID, attachmentID uint) (*models.KnowledgeArticleAttachment, error) {
+	var attachment models.KnowledgeArticleAttachment
+	err := database.DB.Where("article_id = ?", articleID).First(&attachment, attachmentID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// CreateRevision enregistre une version d'un article
+func (r *knowledgeArticleRepository) CreateRevision(revision *models.KnowledgeArticleRevision) error {
+	return database.DB.Omit(clause.Associations).Create(revision).Error
+}
+
+// FindRevisions récupère les versions d'un article, de la plus récente à la plus ancienne
+func (r *knowledgeArticleRepository) FindRevisions(articleID uint) ([]models.KnowledgeArticleRevision, error) {
+	var revisions []models.KnowledgeArticleRevision
+	err := database.DB.Preload("Editor").Where("article_id = ?", articleID).
+		Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// FindRevision récupère une version d'un article
+func (r *knowledgeArticleRepository) FindRevision(articleID uint, version int) (*models.KnowledgeArticleRevision, error) {
+	var revision models.KnowledgeArticleRevision
+	err := database.DB.Preload("Editor").Where("article_id = ? AND version = ?", articleID, version).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// CountRevisions compte les versions enregistrées d'un article
+func (r *knowledgeArticleRepository) CountRevisions(articleID uint) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.KnowledgeArticleRevision{}).Where("article_id = ?", articleID).Count(&count).Error
+	return count, err
+}
+
+// FindInReview récupère les articles en relecture, par échéance
+func (r *knowledgeArticleRepository) FindInReview(reviewerID *uint) ([]models.KnowledgeArticle, error) {
+	var articles []models.KnowledgeArticle
+	query := database.DB.Preload("Category").Preload("Author").
+		Where("knowledge_articles.status = ?", models.KnowledgeStatusInReview)
+	if reviewerID != nil {
+		query = query.Where("knowledge_articles.reviewer_id = ?", *reviewerID)
+	}
+	err := query.Order("knowledge_articles.review_due_at IS NULL, knowledge_articles.review_due_at ASC").Find(&articles).Error
+	return articles, err
+}
+
+// FindReviewDue récupère les articles publiés dont la révision périodique est échue, de la plus ancienne échéance à la plus récente
+func (r *knowledgeArticleRepository) FindReviewDue(now time.Time, userID *uint) ([]models.KnowledgeArticle, error) {
+	var articles []models.KnowledgeArticle
+	query := database.DB.Preload("Category").Preload("Author").
+		Where("knowledge_articles.status = ? AND knowledge_articles.review_due_at <= ?", models.KnowledgeStatusPublished, now)
+	if userID != nil {
+		query = query.Where("knowledge_articles.reviewer_id = ? OR (knowledge_articles.reviewer_id IS NULL AND knowledge_articles.author_id = ?)", *userID, *userID)
+	}
+	err := query.Order("knowledge_articles.review_due_at ASC").Find(&articles).Error
+	return articles, err
+}
+
+// FindReviewAlerts récupère les articles en relecture ou publiés dont l'échéance est passée et pas encore signalée
+func (r *knowledgeArticleRepository) FindReviewAlerts(now time.Time) ([]models.KnowledgeArticle, error) {
+	var articles []models.KnowledgeArticle
+	err := database.DB.
+		Where("status IN ? AND review_due_at <= ? AND review_alerted_at IS NULL",
+			[]string{models.KnowledgeStatusInReview, models.KnowledgeStatusPublished}, now).
+		Order("review_due_at ASC").Find(&articles).Error
+	return articles, err
+}
+
+// MarkReviewAlerted enregistre l'envoi de l'alerte d'échéance d'un article
+func (r *knowledgeArticleRepository) MarkReviewAlerted(id uint, at time.Time) error {
+	return database.DB.Model(&models.KnowledgeArticle{}).Where("id = ?", id).Update("review_alerted_at", at).Error
+}
+
 // Create crée une nouvelle catégorie
 func (r *knowledgeCategoryRepository) Create(category *models.KnowledgeCategory) error {
 	return database.DB.Create(category).Error
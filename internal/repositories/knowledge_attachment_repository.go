@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KnowledgeStorageUsage espace occupé par les pièces jointes des articles d'une filiale (fichiers distincts)
+type KnowledgeStorageUsage struct {
+	FilialeID *uint // Nil = articles globaux
+	Files     int64
+	Bytes     int64
+}
+
+// KnowledgeAttachmentRepository interface pour les pièces jointes des articles et les quotas de stockage
+type KnowledgeAttachmentRepository interface {
+	FindByArticle(articleID uint) ([]models.KnowledgeArticleAttachment, error)
+	FindByHash(hash string) ([]models.KnowledgeArticleAttachment, error) // Avec l'article de chaque pièce jointe
+	Delete(id uint) error
+	CountByPath(path string) (int64, error) // Pièces jointes partageant le fichier stocké
+	Usage(filialeID *uint) (*KnowledgeStorageUsage, error)
+	UsageByFiliale() ([]KnowledgeStorageUsage, error)
+	FindQuota(filialeID uint) (*models.KnowledgeStorageQuota, error)
+	FindQuotas() ([]models.KnowledgeStorageQuota, error)
+	SaveQuota(quota *models.KnowledgeStorageQuota) error
+	DeleteQuota(filialeID uint) error
+}
+
+// knowledgeAttachmentRepository implémente KnowledgeAttachmentRepository
+type knowledgeAttachmentRepository struct{}
+
+// NewKnowledgeAttachmentRepository crée une nouvelle instance de KnowledgeAttachmentRepository
+func NewKnowledgeAttachmentRepository() KnowledgeAttachmentRepository {
+	return &knowledgeAttachmentRepository{}
+}
+
+// FindByArticle récupère les pièces jointes d'un article, des plus anciennes aux plus récentes
+func (r *knowledgeAttachmentRepository) FindByArticle(articleID uint) ([]models.KnowledgeArticleAttachment, error) {
+	var attachments []models.KnowledgeArticleAttachment
+	err := database.DB.Where("article_id = ?", articleID).Order("id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// FindByHash récupère les pièces jointes ayant le même contenu
+func (r *knowledgeAttachmentRepository) FindByHash(hash string) ([]models.KnowledgeArticleAttachment, error) {
+	var attachments []models.KnowledgeArticleAttachment
+	err := database.DB.Preload("Article", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id", "filiale_id")
+	}).Where("content_hash = ?", hash).Order("id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// Delete supprime une pièce jointe (soft delete)
+func (r *knowledgeAttachmentRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.KnowledgeArticleAttachment{}, id).Error
+}
+
+// CountByPath compte les pièces jointes qui référencent un fichier stocké
+func (r *knowledgeAttachmentRepository) CountByPath(path string) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.KnowledgeArticleAttachment{}).Where("file_path = ?", path).Count(&count).Error
+	return count, err
+}
+
+// Usage calcule l'espace occupé par les pièces jointes des articles d'une filiale (nil = articles globaux) ;
+// un fichier partagé par plusieurs articles de la filiale n'est compté qu'une fois
+func (r *knowledgeAttachmentRepository) Usage(filialeID *uint) (*KnowledgeStorageUsage, error) {
+	files := storageFiles()
+	if filialeID == nil {
+		files = files.Where("knowledge_articles.filiale_id IS NULL")
+	} else {
+		files = files.Where("knowledge_articles.filiale_id = ?", *filialeID)
+	}
+	usage := &KnowledgeStorageUsage{FilialeID: filialeID}
+	err := database.DB.Table("(?) AS files", files.Group("knowledge_article_attachments.file_path")).
+		Select("COUNT(*) AS files, COALESCE(SUM(files.size), 0) AS bytes").
+		Scan(usage).Error
+	return usage, err
+}
+
+// UsageByFiliale calcule l'espace occupé par filiale (les articles globaux ont une filiale nil)
+func (r *knowledgeAttachmentRepository) UsageByFiliale() ([]KnowledgeStorageUsage, error) {
+	var usages []KnowledgeStorageUsage
+	files := storageFiles().Select("knowledge_articles.filiale_id, MAX(knowledge_article_attachments.file_size) AS size").
+		Group("knowledge_articles.filiale_id, knowledge_article_attachments.file_path")
+	err := database.DB.Table("(?) AS files", files).
+		Select("files.filiale_id, COUNT(*) AS files, COALESCE(SUM(files.size), 0) AS bytes").
+		Group("files.filiale_id").
+		Order("bytes DESC").
+		Scan(&usages).Error
+	return usages, err
+}
+
+// storageFiles sélectionne la taille des pièces jointes avec la filiale de leur article (y compris les articles supprimés,
+// dont les fichiers restent stockés)
+func storageFiles() *gorm.DB {
+	return database.DB.Model(&models.KnowledgeArticleAttachment{}).
+		Select("MAX(knowledge_article_attachments.file_size) AS size").
+		Joins("JOIN knowledge_articles ON knowledge_articles.id = knowledge_article_attachments.article_id")
+}
+
+// FindQuota récupère le quota d'une filiale
+func (r *knowledgeAttachmentRepository) FindQuota(filialeID uint) (*models.KnowledgeStorageQuota, error) {
+	var quota models.KnowledgeStorageQuota
+	if err := database.DB.Where("filiale_id = ?", filialeID).First(&quota).Error; err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// FindQuotas récupère les quotas propres aux filiales
+func (r *knowledgeAttachmentRepository) FindQuotas() ([]models.KnowledgeStorageQuota, error) {
+	var quotas []models.KnowledgeStorageQuota
+	err := database.DB.Order("filiale_id ASC").Find(&quotas).Error
+	return quotas, err
+}
+
+// SaveQuota crée ou met à jour le quota d'une filiale
+func (r *knowledgeAttachmentRepository) SaveQuota(quota *models.KnowledgeStorageQuota) error {
+	return database.DB.Omit(clause.Associations).Save(quota).Error
+}
+
+// DeleteQuota supprime le quota d'une filiale (le quota par défaut s'applique de nouveau)
+func (r *knowledgeAttachmentRepository) DeleteQuota(filialeID uint) error {
+	return database.DB.Where("filiale_id = ?", filialeID).Delete(&models.KnowledgeStorageQuota{}).Error
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// KnowledgeDeflectionFilter période (début inclus, fin exclue) et filiale des statistiques de déviation
+type KnowledgeDeflectionFilter struct {
+	From      time.Time
+	To        time.Time
+	FilialeID *uint // Nil = toutes les filiales
+}
+
+// KnowledgeDeflectionTotals nombre de sessions de saisie de ticket par issue
+type KnowledgeDeflectionTotals struct {
+	Sessions        int64 // Sessions ayant reçu au moins une suggestion
+	ClickedSessions int64 // Sessions où un article suggéré a été consulté
+	Deflected       int64 // Sessions abandonnées sans création de ticket
+	TicketsCreated  int64 // Sessions terminées par la création d'un ticket
+}
+
+// KnowledgeDeflectionArticleStat statistiques de déviation d'un article suggéré
+type KnowledgeDeflectionArticleStat struct {
+	ArticleID uint
+	Title     string
+	Shown     int64
+	Clicked   int64
+	Deflected int64 // Sessions déviées après consultation de l'article
+}
+
+// KnowledgeDeflectionRepository interface pour les événements de déviation de tickets par la base de connaissances
+type KnowledgeDeflectionRepository interface {
+	Create(events []models.KnowledgeDeflectionEvent) error
+	FindSessionEvents(sessionID string) ([]models.KnowledgeDeflectionEvent, error)
+	Totals(filter KnowledgeDeflectionFilter) (*KnowledgeDeflectionTotals, error)
+	TopArticles(filter KnowledgeDeflectionFilter, limit int) ([]KnowledgeDeflectionArticleStat, error) // Par sessions déviées décroissantes
+}
+
+// knowledgeDeflectionRepository implémente KnowledgeDeflectionRepository
+type knowledgeDeflectionRepository struct{}
+
+// NewKnowledgeDeflectionRepository crée une nouvelle instance de KnowledgeDeflectionRepository
+func NewKnowledgeDeflectionRepository() KnowledgeDeflectionRepository {
+	return &knowledgeDeflectionRepository{}
+}
+
+// Create enregistre des événements
+func (r *knowledgeDeflectionRepository) Create(events []models.KnowledgeDeflectionEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return database.DB.Create(&events).Error
+}
+
+// FindSessionEvents récupère les événements d'une session de saisie
+func (r *knowledgeDeflectionRepository) FindSessionEvents(sessionID string) ([]models.KnowledgeDeflectionEvent, error) {
+	var events []models.KnowledgeDeflectionEvent
+	err := database.DB.Where("session_id = ?", sessionID).Order("id ASC").Find(&events).Error
+	return events, err
+}
+
+// Totals compte les sessions de la période ; une session abandonnée puis reprise jusqu'à la création d'un ticket n'est pas déviée
+func (r *knowledgeDeflectionRepository) Totals(filter KnowledgeDeflectionFilter) (*KnowledgeDeflectionTotals, error) {
+	var totals KnowledgeDeflectionTotals
+	err := r.scoped(filter).
+		Select(`COUNT(DISTINCT CASE WHEN event = ? THEN session_id END) AS sessions,
+			COUNT(DISTINCT CASE WHEN event = ? THEN session_id END) AS clicked_sessions,
+			COUNT(DISTINCT CASE WHEN event = ? AND session_id NOT IN (?) THEN session_id END) AS deflected,
+			COUNT(DISTINCT CASE WHEN event = ? THEN session_id END) AS tickets_created`,
+			models.KnowledgeDeflectionShown,
+			models.KnowledgeDeflectionClicked,
+			models.KnowledgeDeflectionAbandoned, r.sessionsWith(models.KnowledgeDeflectionTicketCreated),
+			models.KnowledgeDeflectionTicketCreated).
+		Scan(&totals).Error
+	return &totals, err
+}
+
+// TopArticles classe les articles suggérés de la période par nombre de sessions déviées après consultation
+func (r *knowledgeDeflectionRepository) TopArticles(filter KnowledgeDeflectionFilter, limit int) ([]KnowledgeDeflectionArticleStat, error) {
+	var stats []KnowledgeDeflectionArticleStat
+	deflected := database.DB.Model(&models.KnowledgeDeflectionEvent{}).
+		Select("session_id").
+		Where("event = ? AND session_id NOT IN (?)", models.KnowledgeDeflectionAbandoned, r.sessionsWith(models.KnowledgeDeflectionTicketCreated))
+	err := r.scoped(filter).
+		Select(`knowledge_deflection_events.article_id AS article_id, knowledge_articles.title AS title,
+			SUM(CASE WHEN event = ? THEN 1 ELSE 0 END) AS shown,
+			SUM(CASE WHEN event = ? THEN 1 ELSE 0 END) AS clicked,
+			COUNT(DISTINCT CASE WHEN event = ? AND session_id IN (?) THEN session_id END) AS deflected`,
+			models.KnowledgeDeflectionShown, models.KnowledgeDeflectionClicked, models.KnowledgeDeflectionClicked, deflected).
+		Joins("LEFT JOIN knowledge_articles ON knowledge_articles.id = knowledge_deflection_events.article_id").
+		Where("knowledge_deflection_events.article_id IS NOT NULL").
+		Group("knowledge_deflection_events.article_id, knowledge_articles.title").
+		Order("deflected DESC, clicked DESC, shown DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// scoped restreint les événements à la période et à la filiale
+func (r *knowledgeDeflectionRepository) scoped(filter KnowledgeDeflectionFilter) *gorm.DB {
+	query := database.DB.Model(&models.KnowledgeDeflectionEvent{}).
+		Where("knowledge_deflection_events.created_at >= ? AND knowledge_deflection_events.created_at < ?", filter.From, filter.To)
+	if filter.FilialeID != nil {
+		query = query.Where("knowledge_deflection_events.filiale_id = ?", *filter.FilialeID)
+	}
+	return query
+}
+
+// sessionsWith sous-requête des sessions comportant l'événement donné
+func (r *knowledgeDeflectionRepository) sessionsWith(event string) *gorm.DB {
+	return database.DB.Model(&models.KnowledgeDeflectionEvent{}).Select("session_id").Where("event = ?", event)
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"math"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// KnowledgeLowRatedFilter critères du rapport des articles mal notés
+type KnowledgeLowRatedFilter struct {
+	MinRatings int     // Nombre minimal d'évaluations pour qu'un article soit classé
+	MaxRate    float64 // Part maximale d'évaluations « utile », en pourcentage
+	Limit      int
+}
+
+// KnowledgeFeedbackFilter filtre des évaluations d'un article
+type KnowledgeFeedbackFilter struct {
+	Helpful     *bool // Nil = tous les avis
+	WithComment bool  // Uniquement les évaluations commentées
+	Limit       int   // 0 = toutes
+}
+
+// KnowledgeFeedbackRepository interface pour les évaluations des articles de la base de connaissances
+type KnowledgeFeedbackRepository interface {
+	Save(feedback *models.KnowledgeArticleFeedback) error // Crée ou met à jour l'évaluation
+	FindByArticleAndUser(articleID, userID uint) (*models.KnowledgeArticleFeedback, error)
+	FindByArticle(articleID uint, filter KnowledgeFeedbackFilter) ([]models.KnowledgeArticleFeedback, error) // Plus récentes d'abord
+	Delete(articleID, userID uint) error
+	RefreshArticleScore(articleID uint) (*models.KnowledgeArticle, error)                              // Recalcule les agrégats de l'article
+	FindLowRated(scope interface{}, filter KnowledgeLowRatedFilter) ([]models.KnowledgeArticle, error) // Part « utile » croissante
+}
+
+// knowledgeFeedbackRepository implémente KnowledgeFeedbackRepository
+type knowledgeFeedbackRepository struct{}
+
+// NewKnowledgeFeedbackRepository crée une nouvelle instance de KnowledgeFeedbackRepository
+func NewKnowledgeFeedbackRepository() KnowledgeFeedbackRepository {
+	return &knowledgeFeedbackRepository{}
+}
+
+// Save crée ou met à jour une évaluation
+func (r *knowledgeFeedbackRepository) Save(feedback *models.KnowledgeArticleFeedback) error {
+	if feedback.ID == 0 {
+		return database.DB.Omit(clause.Associations).Create(feedback).Error
+	}
+	return database.DB.Omit(clause.Associations).Save(feedback).Error
+}
+
+// FindByArticleAndUser récupère l'évaluation d'un article par un utilisateur
+func (r *knowledgeFeedbackRepository) FindByArticleAndUser(articleID, userID uint) (*models.KnowledgeArticleFeedback, error) {
+	var feedback models.KnowledgeArticleFeedback
+	err := database.DB.Where("article_id = ? AND user_id = ?", articleID, userID).First(&feedback).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+// FindByArticle récupère les évaluations d'un article
+func (r *knowledgeFeedbackRepository) FindByArticle(articleID uint, filter KnowledgeFeedbackFilter) ([]models.KnowledgeArticleFeedback, error) {
+	var feedbacks []models.KnowledgeArticleFeedback
+	query := database.DB.Preload("User").Where("article_id = ?", articleID)
+	if filter.Helpful != nil {
+		query = query.Where("helpful = ?", *filter.Helpful)
+	}
+	if filter.WithComment {
+		query = query.Where("comment IS NOT NULL AND comment <> ''")
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	err := query.Order("updated_at DESC, id DESC").Find(&feedbacks).Error
+	return feedbacks, err
+}
+
+// Delete supprime l'évaluation d'un article par un utilisateur
+func (r *knowledgeFeedbackRepository) Delete(articleID, userID uint) error {
+	return database.DB.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&models.KnowledgeArticleFeedback{}).Error
+}
+
+// RefreshArticleScore recompte les évaluations de l'article et enregistre les agrégats
+// (sans modifier la date de mise à jour de l'article)
+func (r *knowledgeFeedbackRepository) RefreshArticleScore(articleID uint) (*models.KnowledgeArticle, error) {
+	var counts struct {
+		Helpful    int
+		NotHelpful int
+	}
+	err := database.DB.Model(&models.KnowledgeArticleFeedback{}).
+		Select("COALESCE(SUM(CASE WHEN helpful THEN 1 ELSE 0 END), 0) AS helpful, COALESCE(SUM(CASE WHEN helpful THEN 0 ELSE 1 END), 0) AS not_helpful").
+		Where("article_id = ?", articleID).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	article := models.KnowledgeArticle{ID: articleID, HelpfulCount: counts.Helpful, NotHelpfulCount: counts.NotHelpful}
+	if total := counts.Helpful + counts.NotHelpful; total > 0 {
+		rate := math.Round(float64(counts.Helpful)/float64(total)*1000) / 10
+		article.HelpfulRate = &rate
+	}
+	err = database.DB.Model(&models.KnowledgeArticle{}).Where("id = ?", articleID).UpdateColumns(map[string]interface{}{
+		"helpful_count":     article.HelpfulCount,
+		"not_helpful_count": article.NotHelpfulCount,
+		"helpful_rate":      article.HelpfulRate,
+	}).Error
+	return &article, err
+}
+
+// FindLowRated récupère les articles visibles ayant assez d'évaluations et une part « utile » inférieure au seuil
+func (r *knowledgeFeedbackRepository) FindLowRated(scopeParam interface{}, filter KnowledgeLowRatedFilter) ([]models.KnowledgeArticle, error) {
+	var articles []models.KnowledgeArticle
+	query := database.DB.Model(&models.KnowledgeArticle{}).
+		Preload("Category").Preload("Author").
+		Where("knowledge_articles.helpful_count + knowledge_articles.not_helpful_count >= ?", filter.MinRatings).
+		Where("knowledge_articles.helpful_rate <= ?", filter.MaxRate)
+	query = applyKnowledgeSearchScope(query, scopeParam)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	err := query.Order("knowledge_articles.helpful_rate ASC, knowledge_articles.not_helpful_count DESC, knowledge_articles.id ASC").
+		Find(&articles).Error
+	return articles, err
+}
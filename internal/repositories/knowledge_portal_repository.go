@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KnowledgePortalFilter filtre des articles du portail public
+type KnowledgePortalFilter struct {
+	FilialeID  *uint  // Articles globaux et de cette filiale ; nil = toutes les filiales
+	CategoryID *uint  // Catégorie (optionnel)
+	Query      string // Recherche par sous-chaîne dans le titre et le contenu (optionnel)
+	Page       int
+	Limit      int
+}
+
+// KnowledgePortalRepository interface pour le portail public de la base de connaissances et ses clés d'API
+type KnowledgePortalRepository interface {
+	FindArticles(filter KnowledgePortalFilter) ([]models.KnowledgeArticle, int64, error) // Plus récents d'abord
+	FindArticlesByIDs(ids []uint, filter KnowledgePortalFilter) ([]models.KnowledgeArticle, error)
+	FindArticle(id uint, filialeID *uint) (*models.KnowledgeArticle, error)
+	FindCategories(filialeID *uint) ([]models.KnowledgeCategory, error) // Catégories actives ayant des articles publics
+	CreateKey(key *models.KnowledgePortalKey) error
+	FindKeys() ([]models.KnowledgePortalKey, error)
+	FindKeyByID(id uint) (*models.KnowledgePortalKey, error)
+	FindKeyByHash(hash string) (*models.KnowledgePortalKey, error)
+	UpdateKey(key *models.KnowledgePortalKey) error
+	DeleteKey(id uint) error
+}
+
+// knowledgePortalRepository implémente KnowledgePortalRepository
+type knowledgePortalRepository struct{}
+
+// NewKnowledgePortalRepository crée une nouvelle instance de KnowledgePortalRepository
+func NewKnowledgePortalRepository() KnowledgePortalRepository {
+	return &knowledgePortalRepository{}
+}
+
+// publicArticles sélectionne les articles publiés exposés sur le portail public, filtrés selon filter
+func publicArticles(filter KnowledgePortalFilter) *gorm.DB {
+	query := database.DB.Model(&models.KnowledgeArticle{}).
+		Where("knowledge_articles.status = ? AND knowledge_articles.is_public = ?", models.KnowledgeStatusPublished, true)
+	if filter.FilialeID != nil {
+		query = query.Where("(knowledge_articles.filiale_id IS NULL OR knowledge_articles.filiale_id = ?)", *filter.FilialeID)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("knowledge_articles.category_id = ?", *filter.CategoryID)
+	}
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("(knowledge_articles.title LIKE ? OR knowledge_articles.content LIKE ?)", like, like)
+	}
+	return query
+}
+
+// FindArticles récupère une page d'articles publics, du plus récemment modifié au plus ancien
+func (r *knowledgePortalRepository) FindArticles(filter KnowledgePortalFilter) ([]models.KnowledgeArticle, int64, error) {
+	var total int64
+	if err := publicArticles(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var articles []models.KnowledgeArticle
+	err := publicArticles(filter).Preload("Category").
+		Order("knowledge_articles.updated_at DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&articles).Error
+	return articles, total, err
+}
+
+// FindArticlesByIDs récupère les articles publics parmi ids (ordre quelconque)
+func (r *knowledgePortalRepository) FindArticlesByIDs(ids []uint, filter KnowledgePortalFilter) ([]models.KnowledgeArticle, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var articles []models.KnowledgeArticle
+	err := publicArticles(filter).Preload("Category").Where("knowledge_articles.id IN ?", ids).Find(&articles).Error
+	return articles, err
+}
+
+// FindArticle récupère un article public
+func (r *knowledgePortalRepository) FindArticle(id uint, filialeID *uint) (*models.KnowledgeArticle, error) {
+	var article models.KnowledgeArticle
+	err := publicArticles(KnowledgePortalFilter{FilialeID: filialeID}).Preload("Category").First(&article, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// FindCategories récupère les catégories actives contenant au moins un article public, par nom
+func (r *knowledgePortalRepository) FindCategories(filialeID *uint) ([]models.KnowledgeCategory, error) {
+	var categories []models.KnowledgeCategory
+	articles := publicArticles(KnowledgePortalFilter{FilialeID: filialeID}).Select("knowledge_articles.category_id")
+	err := database.DB.Where("is_active = ? AND id IN (?)", true, articles).Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// CreateKey crée une clé d'API du portail
+func (r *knowledgePortalRepository) CreateKey(key *models.KnowledgePortalKey) error {
+	return database.DB.Omit(clause.Associations).Create(key).Error
+}
+
+// FindKeys récupère les clés d'API du portail, avec leur filiale
+func (r *knowledgePortalRepository) FindKeys() ([]models.KnowledgePortalKey, error) {
+	var keys []models.KnowledgePortalKey
+	err := database.DB.Preload("Filiale").Order("name").Find(&keys).Error
+	return keys, err
+}
+
+// FindKeyByID récupère une clé d'API du portail par son ID
+func (r *knowledgePortalRepository) FindKeyByID(id uint) (*models.KnowledgePortalKey, error) {
+	var key models.KnowledgePortalKey
+	if err := database.DB.Preload("Filiale").First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindKeyByHash récupère une clé d'API du portail par le hash de sa valeur
+func (r *knowledgePortalRepository) FindKeyByHash(hash string) (*models.KnowledgePortalKey, error) {
+	var key models.KnowledgePortalKey
+	if err := database.DB.Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// UpdateKey met à jour une clé d'API du portail
+func (r *knowledgePortalRepository) UpdateKey(key *models.KnowledgePortalKey) error {
+	return database.DB.Omit(clause.Associations).Save(key).Error
+}
+
+// DeleteKey supprime une clé d'API du portail
+func (r *knowledgePortalRepository) DeleteKey(id uint) error {
+	return database.DB.Delete(&models.KnowledgePortalKey{}, id).Error
+}
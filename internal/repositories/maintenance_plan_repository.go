@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxDueMaintenancePlans nombre maximal de plans traités par passage
+const maxDueMaintenancePlans = 50
+
+// MaintenancePlanFilter critères de recherche des plans de maintenance
+type MaintenancePlanFilter struct {
+	AssetID    *uint
+	CategoryID *uint
+	ActiveOnly bool
+}
+
+// MaintenancePlanRepository interface pour les plans de maintenance préventive et leur historique
+type MaintenancePlanRepository interface {
+	Create(plan *models.MaintenancePlan) error
+	FindByID(id uint) (*models.MaintenancePlan, error)
+	FindAll(filter MaintenancePlanFilter) ([]models.MaintenancePlan, error)
+	FindForAsset(assetID, categoryID uint) ([]models.MaintenancePlan, error) // Plans de l'actif et de sa catégorie
+	FindDue(now time.Time) ([]models.MaintenancePlan, error)                 // Plans actifs dont l'échéance est passée
+	Update(plan *models.MaintenancePlan) error
+	Delete(id uint) error
+	Claim(id uint, dueAt time.Time, nextRunAt *time.Time) (bool, error) // Avance l'échéance si elle vaut encore dueAt (une seule instance crée les tickets)
+	RecordRun(id uint, runAt time.Time, runError string) error
+	CreateRecord(record *models.MaintenanceRecord) error
+	HasOpenTicket(planID, assetID uint, closedStatuses []string) (bool, error) // Un ticket du plan pour l'actif n'est pas encore clôturé
+	FindRecordsByAsset(assetID uint, limit int) ([]models.MaintenanceRecord, error)
+	FindRecordsByPlan(planID uint, limit int) ([]models.MaintenanceRecord, error)
+}
+
+// maintenancePlanRepository implémente MaintenancePlanRepository
+type maintenancePlanRepository struct{}
+
+// NewMaintenancePlanRepository crée une nouvelle instance de MaintenancePlanRepository
+func NewMaintenancePlanRepository() MaintenancePlanRepository {
+	return &maintenancePlanRepository{}
+}
+
+// withMaintenancePlanRelations précharge le périmètre d'un plan
+func withMaintenancePlanRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Asset").Preload("Category")
+}
+
+// withMaintenanceRecordRelations précharge le plan et le ticket d'une opération de maintenance
+func withMaintenanceRecordRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Plan").Preload("Ticket", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "code", "title", "status", "created_at", "closed_at")
+	})
+}
+
+// Create crée un plan de maintenance
+func (r *maintenancePlanRepository) Create(plan *models.MaintenancePlan) error {
+	return database.DB.Omit(clause.Associations).Create(plan).Error
+}
+
+// FindByID récupère un plan de maintenance par son ID
+func (r *maintenancePlanRepository) FindByID(id uint) (*models.MaintenancePlan, error) {
+	var plan models.MaintenancePlan
+	if err := withMaintenancePlanRelations(database.DB).First(&plan, id).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// FindAll récupère les plans de maintenance selon les filtres, par nom
+func (r *maintenancePlanRepository) FindAll(filter MaintenancePlanFilter) ([]models.MaintenancePlan, error) {
+	var plans []models.MaintenancePlan
+	query := withMaintenancePlanRelations(database.DB)
+	if filter.AssetID != nil {
+		query = query.Where("asset_id = ?", *filter.AssetID)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("name ASC, id ASC").Find(&plans).Error
+	return plans, err
+}
+
+// FindForAsset récupère les plans qui portent sur l'actif ou sur sa catégorie
+func (r *maintenancePlanRepository) FindForAsset(assetID, categoryID uint) ([]models.MaintenancePlan, error) {
+	var plans []models.MaintenancePlan
+	err := withMaintenancePlanRelations(database.DB).
+		Where("asset_id = ? OR category_id = ?", assetID, categoryID).
+		Order("name ASC, id ASC").Find(&plans).Error
+	return plans, err
+}
+
+// FindDue récupère les plans actifs arrivés à échéance, de la plus ancienne échéance à la plus récente
+func (r *maintenancePlanRepository) FindDue(now time.Time) ([]models.MaintenancePlan, error) {
+	var plans []models.MaintenancePlan
+	err := withMaintenancePlanRelations(database.DB).
+		Where("is_active = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").Limit(maxDueMaintenancePlans).Find(&plans).Error
+	return plans, err
+}
+
+// Update met à jour un plan de maintenance
+func (r *maintenancePlanRepository) Update(plan *models.MaintenancePlan) error {
+	return database.DB.Omit(clause.Associations).Save(plan).Error
+}
+
+// Delete supprime un plan de maintenance (l'historique et les tickets déjà créés sont conservés)
+func (r *maintenancePlanRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.MaintenancePlan{}, id).Error
+}
+
+// Claim avance l'échéance d'un plan à condition qu'il n'ait pas déjà été traité
+func (r *maintenancePlanRepository) Claim(id uint, dueAt time.Time, nextRunAt *time.Time) (bool, error) {
+	result := database.DB.Model(&models.MaintenancePlan{}).
+		Where("id = ? AND next_run_at = ?", id, dueAt).
+		Update("next_run_at", nextRunAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordRun enregistre le résultat d'une exécution du plan
+func (r *maintenancePlanRepository) RecordRun(id uint, runAt time.Time, runError string) error {
+	return database.DB.Model(&models.MaintenancePlan{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_run_at": runAt,
+		"last_error":  runError,
+		"run_count":   gorm.Expr("run_count + 1"),
+	}).Error
+}
+
+// CreateRecord enregistre une opération de maintenance dans l'historique de l'actif
+func (r *maintenancePlanRepository) CreateRecord(record *models.MaintenanceRecord) error {
+	return database.DB.Omit(clause.Associations).Create(record).Error
+}
+
+// HasOpenTicket indique si un ticket généré par le plan pour l'actif n'est pas encore clôturé
+func (r *maintenancePlanRepository) HasOpenTicket(planID, assetID uint, closedStatuses []string) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.MaintenanceRecord{}).
+		Joins("JOIN tickets ON tickets.id = maintenance_records.ticket_id AND tickets.deleted_at IS NULL").
+		Where("maintenance_records.plan_id = ? AND maintenance_records.asset_id = ?", planID, assetID).
+		Where("tickets.status NOT IN ?", closedStatuses).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindRecordsByAsset récupère l'historique de maintenance d'un actif, du plus récent au plus ancien
+func (r *maintenancePlanRepository) FindRecordsByAsset(assetID uint, limit int) ([]models.MaintenanceRecord, error) {
+	var records []models.MaintenanceRecord
+	err := withMaintenanceRecordRelations(database.DB).Where("asset_id = ?", assetID).
+		Order("due_at DESC, id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
+
+// FindRecordsByPlan récupère les dernières opérations générées par un plan
+func (r *maintenancePlanRepository) FindRecordsByPlan(planID uint, limit int) ([]models.MaintenanceRecord, error) {
+	var records []models.MaintenanceRecord
+	err := withMaintenanceRecordRelations(database.DB).Where("plan_id = ?", planID).
+		Order("due_at DESC, id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
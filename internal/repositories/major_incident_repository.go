@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MajorIncidentRepository interface pour les incidents majeurs : points de situation et revues post-incident
+type MajorIncidentRepository interface {
+	UpdateMajorFields(incidentID uint, fields map[string]any) error // Met à jour les seules colonnes d'incident majeur
+	CreateUpdate(update *models.MajorIncidentUpdate) error          // Crée le point de situation et date le dernier point de l'incident
+	FindUpdates(incidentID uint, publicOnly bool) ([]models.MajorIncidentUpdate, error)
+	FindActive() ([]models.Incident, error)                            // Incidents majeurs en cours
+	FindForStatusPage(endedSince time.Time) ([]models.Incident, error) // Incidents majeurs en cours ou terminés depuis endedSince
+	FindReview(incidentID uint) (*models.PostIncidentReview, error)
+	CreateReview(review *models.PostIncidentReview) error
+	UpdateReview(review *models.PostIncidentReview) error
+}
+
+// majorIncidentRepository implémente MajorIncidentRepository
+type majorIncidentRepository struct{}
+
+// NewMajorIncidentRepository crée une nouvelle instance de MajorIncidentRepository
+func NewMajorIncidentRepository() MajorIncidentRepository {
+	return &majorIncidentRepository{}
+}
+
+// UpdateMajorFields met à jour les colonnes d'incident majeur sans réécrire le reste de l'incident
+func (r *majorIncidentRepository) UpdateMajorFields(incidentID uint, fields map[string]any) error {
+	return database.DB.Model(&models.Incident{}).Where("id = ?", incidentID).Updates(fields).Error
+}
+
+// CreateUpdate crée le point de situation ; le rappel de point en retard repart de sa date
+func (r *majorIncidentRepository) CreateUpdate(update *models.MajorIncidentUpdate) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(update).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Incident{}).Where("id = ?", update.IncidentID).
+			Updates(map[string]any{"last_status_update_at": update.CreatedAt, "update_reminder_at": nil}).Error
+	})
+}
+
+// FindUpdates récupère les points de situation d'un incident, les plus récents d'abord
+func (r *majorIncidentRepository) FindUpdates(incidentID uint, publicOnly bool) ([]models.MajorIncidentUpdate, error) {
+	var updates []models.MajorIncidentUpdate
+	query := database.DB.Preload("PostedBy").Where("incident_id = ?", incidentID)
+	if publicOnly {
+		query = query.Where("is_public = ?", true)
+	}
+	err := query.Order("created_at DESC, id DESC").Find(&updates).Error
+	return updates, err
+}
+
+// FindActive récupère les incidents majeurs en cours
+func (r *majorIncidentRepository) FindActive() ([]models.Incident, error) {
+	var incidents []models.Incident
+	err := database.DB.Preload("Ticket").Preload("Ticket.AssignedTo").
+		Where("is_major = ? AND major_ended_at IS NULL", true).
+		Order("major_declared_at ASC").Find(&incidents).Error
+	return incidents, err
+}
+
+// FindForStatusPage récupère les incidents majeurs en cours puis ceux terminés depuis endedSince
+func (r *majorIncidentRepository) FindForStatusPage(endedSince time.Time) ([]models.Incident, error) {
+	var incidents []models.Incident
+	err := database.DB.Preload("Ticket").Preload("Ticket.Filiale").
+		Where("is_major = ? AND (major_ended_at IS NULL OR major_ended_at >= ?)", true, endedSince).
+		Order("major_ended_at IS NOT NULL, major_declared_at DESC").Find(&incidents).Error
+	return incidents, err
+}
+
+// FindReview récupère la revue post-incident d'un incident
+func (r *majorIncidentRepository) FindReview(incidentID uint) (*models.PostIncidentReview, error) {
+	var review models.PostIncidentReview
+	err := database.DB.Preload("CreatedBy").Preload("CompletedBy").Where("incident_id = ?", incidentID).First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// CreateReview crée une revue post-incident
+func (r *majorIncidentRepository) CreateReview(review *models.PostIncidentReview) error {
+	return database.DB.Omit(clause.Associations).Create(review).Error
+}
+
+// UpdateReview enregistre une revue post-incident
+func (r *majorIncidentRepository) UpdateReview(review *models.PostIncidentReview) error {
+	return database.DB.Omit(clause.Associations).Save(review).Error
+}
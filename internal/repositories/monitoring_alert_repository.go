@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// MonitoringAlertRepository interface pour les opérations sur les sources et les alertes de supervision
+type MonitoringAlertRepository interface {
+	CreateSource(source *models.AlertSource) error
+	FindSourceByID(id uint) (*models.AlertSource, error)
+	FindSourceByKeyHash(keyHash string) (*models.AlertSource, error)
+	FindSources() ([]models.AlertSource, error)
+	UpdateSource(source *models.AlertSource) error
+	DeleteSource(id uint) error
+	Create(alert *models.MonitoringAlert) error
+	FindByFingerprint(sourceID uint, fingerprintHash string) (*models.MonitoringAlert, error)
+	FindAll(sourceID *uint, status string, page, limit int) ([]models.MonitoringAlert, int64, error)
+	Update(alert *models.MonitoringAlert) error
+	FindAssetByName(name string, filialeID *uint) (*models.Asset, error)
+	FindSoftwareByCodeOrName(value string) (*models.Software, error)
+}
+
+// monitoringAlertRepository implémente MonitoringAlertRepository
+type monitoringAlertRepository struct{}
+
+// NewMonitoringAlertRepository crée une nouvelle instance de MonitoringAlertRepository
+func NewMonitoringAlertRepository() MonitoringAlertRepository {
+	return &monitoringAlertRepository{}
+}
+
+// CreateSource crée une source d'alertes
+func (r *monitoringAlertRepository) CreateSource(source *models.AlertSource) error {
+	return database.DB.Create(source).Error
+}
+
+// FindSourceByID trouve une source par son ID
+func (r *monitoringAlertRepository) FindSourceByID(id uint) (*models.AlertSource, error) {
+	var source models.AlertSource
+	if err := database.DB.Preload("Filiale").First(&source, id).Error; err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// FindSourceByKeyHash trouve la source correspondant à une clé d'API
+func (r *monitoringAlertRepository) FindSourceByKeyHash(keyHash string) (*models.AlertSource, error) {
+	var source models.AlertSource
+	if err := database.DB.Where("key_hash = ?", keyHash).First(&source).Error; err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// FindSources récupère toutes les sources
+func (r *monitoringAlertRepository) FindSources() ([]models.AlertSource, error) {
+	var sources []models.AlertSource
+	err := database.DB.Preload("Filiale").Order("name").Find(&sources).Error
+	return sources, err
+}
+
+// UpdateSource met à jour une source
+func (r *monitoringAlertRepository) UpdateSource(source *models.AlertSource) error {
+	return database.DB.Omit("Filiale", "CreatedBy").Save(source).Error
+}
+
+// DeleteSource supprime une source et ses alertes (les tickets créés sont conservés)
+func (r *monitoringAlertRepository) DeleteSource(id uint) error {
+	return database.DB.Delete(&models.AlertSource{}, id).Error
+}
+
+// Create crée une alerte
+func (r *monitoringAlertRepository) Create(alert *models.MonitoringAlert) error {
+	return database.DB.Omit("Source", "Ticket").Create(alert).Error
+}
+
+// FindByFingerprint trouve l'alerte d'une source par le hash de son empreinte
+func (r *monitoringAlertRepository) FindByFingerprint(sourceID uint, fingerprintHash string) (*models.MonitoringAlert, error) {
+	var alert models.MonitoringAlert
+	err := database.DB.Where("source_id = ? AND fingerprint_hash = ?", sourceID, fingerprintHash).First(&alert).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// FindAll récupère les alertes (plus récentes d'abord), éventuellement filtrées par source et par état
+func (r *monitoringAlertRepository) FindAll(sourceID *uint, status string, page, limit int) ([]models.MonitoringAlert, int64, error) {
+	var alerts []models.MonitoringAlert
+	var total int64
+	query := database.DB.Model(&models.MonitoringAlert{})
+	if sourceID != nil {
+		query = query.Where("source_id = ?", *sourceID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Source").Preload("Ticket").
+		Order("last_received_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&alerts).Error
+	return alerts, total, err
+}
+
+// Update met à jour une alerte
+func (r *monitoringAlertRepository) Update(alert *models.MonitoringAlert) error {
+	return database.DB.Omit("Source", "Ticket").Save(alert).Error
+}
+
+// FindAssetByName trouve l'actif portant ce nom (insensible à la casse), limité à la filiale donnée et aux actifs sans filiale
+func (r *monitoringAlertRepository) FindAssetByName(name string, filialeID *uint) (*models.Asset, error) {
+	var asset models.Asset
+	query := database.DB.Where("LOWER(name) = LOWER(?)", name)
+	if filialeID != nil {
+		query = query.Where("filiale_id = ? OR filiale_id IS NULL", *filialeID)
+	}
+	if err := query.Order("id").First(&asset).Error; err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// FindSoftwareByCodeOrName trouve le logiciel actif dont le code ou le nom correspond (insensible à la casse)
+func (r *monitoringAlertRepository) FindSoftwareByCodeOrName(value string) (*models.Software, error) {
+	var software models.Software
+	err := database.DB.Where("is_active = ? AND (LOWER(code) = LOWER(?) OR LOWER(name) = LOWER(?))", true, value, value).
+		Order("id DESC").First(&software).Error
+	if err != nil {
+		return nil, err
+	}
+	return &software, nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationPreferenceRepository interface pour les réglages des notifications par email, par type
+type NotificationPreferenceRepository interface {
+	FindByUserID(userID uint) ([]models.NotificationPreference, error)
+	FindByUserIDAndType(userID uint, notificationType string) (*models.NotificationPreference, error)
+	Upsert(preferences []models.NotificationPreference) error
+}
+
+// notificationPreferenceRepository implémente NotificationPreferenceRepository
+type notificationPreferenceRepository struct{}
+
+// NewNotificationPreferenceRepository crée une nouvelle instance de NotificationPreferenceRepository
+func NewNotificationPreferenceRepository() NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{}
+}
+
+// FindByUserID récupère les réglages enregistrés d'un utilisateur
+func (r *notificationPreferenceRepository) FindByUserID(userID uint) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := database.DB.Where("user_id = ?", userID).Order("notification_type ASC").Find(&preferences).Error
+	return preferences, err
+}
+
+// FindByUserIDAndType récupère le réglage d'un utilisateur pour un type de notification
+func (r *notificationPreferenceRepository) FindByUserIDAndType(userID uint, notificationType string) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := database.DB.Where("user_id = ? AND notification_type = ?", userID, notificationType).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// Upsert crée ou met à jour les réglages (clé unique utilisateur + type)
+func (r *notificationPreferenceRepository) Upsert(preferences []models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "notification_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "updated_at"}),
+	}).Create(&preferences).Error
+}
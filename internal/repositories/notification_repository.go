@@ -5,6 +5,7 @@ import (
 
 	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
 )
 
 // NotificationRepository interface pour les opérations sur les notifications
@@ -159,7 +160,17 @@ func (r *notificationRepository) MarkAllAsRead(userID uint) error {
 
 // Delete supprime une notification
 func (r *notificationRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.Notification{}, id).Error
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var notification models.Notification
+		if err := tx.Select("id", "user_id").First(&notification, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Notification{}, id).Error; err != nil {
+			return err
+		}
+		// Trace pour la synchronisation des clients mobiles (pas de soft delete)
+		return RecordTombstones(tx, models.SyncEntityNotifications, []uint{id}, &notification.UserID)
+	})
 }
 
 // CountUnread compte les notifications non lues d'un utilisateur
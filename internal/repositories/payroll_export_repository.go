@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// Types de centre de coût de l'export de paie
+const (
+	PayrollCostCenterDepartment = "D" // Département de l'utilisateur (temps sur tickets)
+	PayrollCostCenterProject    = "P" // Projet (temps sur tâches de projet)
+)
+
+// PayrollExportFilter critères de sélection des heures exportées vers la paie
+type PayrollExportFilter struct {
+	From         time.Time // Premier jour de la période (inclus)
+	To           time.Time // Dernier jour de la période (inclus)
+	DepartmentID *uint     // Département des utilisateurs
+	FilialeID    *uint     // Filiale des utilisateurs
+}
+
+// PayrollExportRow heures validées d'un utilisateur sur un centre de coût
+type PayrollExportRow struct {
+	UserID         uint
+	Username       string
+	FirstName      string
+	LastName       string
+	CostCenterType string // PayrollCostCenterDepartment ou PayrollCostCenterProject
+	CostCenterID   *uint  // Absent pour un utilisateur sans département
+	CostCenterCode string
+	CostCenterName string
+	Minutes        int
+}
+
+// PayrollExportRepository interface pour l'agrégation des heures exportées vers la paie
+type PayrollExportRepository interface {
+	SumValidatedTime(filter PayrollExportFilter) ([]PayrollExportRow, error)
+}
+
+// payrollExportRepository implémente PayrollExportRepository
+type payrollExportRepository struct{}
+
+// NewPayrollExportRepository crée une nouvelle instance de PayrollExportRepository
+func NewPayrollExportRepository() PayrollExportRepository {
+	return &payrollExportRepository{}
+}
+
+// SumValidatedTime agrège par utilisateur et centre de coût les heures validées de la période : entrées validées
+// individuellement ou couvertes par une déclaration hebdomadaire approuvée. Le temps des tâches de projet est imputé
+// au projet, le reste au département de l'utilisateur
+func (r *payrollExportRepository) SumValidatedTime(filter PayrollExportFilter) ([]PayrollExportRow, error) {
+	var rows []PayrollExportRow
+	query := database.DB.Model(&models.TimeEntry{}).
+		Select("users.id AS user_id, MAX(users.username) AS username, MAX(users.first_name) AS first_name, "+
+			"MAX(users.last_name) AS last_name, "+
+			"CASE WHEN projects.id IS NULL THEN ? ELSE ? END AS cost_center_type, "+
+			"COALESCE(projects.id, departments.id) AS cost_center_id, "+
+			"CASE WHEN projects.id IS NULL THEN COALESCE(MAX(departments.code), '') ELSE CONCAT('PRJ-', projects.id) END AS cost_center_code, "+
+			"COALESCE(MAX(projects.name), MAX(departments.name), '') AS cost_center_name, "+
+			"SUM(time_entries.time_spent) AS minutes",
+			PayrollCostCenterDepartment, PayrollCostCenterProject).
+		Joins("JOIN users ON users.id = time_entries.user_id").
+		Joins("LEFT JOIN project_tasks ON project_tasks.id = time_entries.project_task_id").
+		Joins("LEFT JOIN projects ON projects.id = project_tasks.project_id").
+		Joins("LEFT JOIN departments ON departments.id = users.department_id AND projects.id IS NULL").
+		Where("time_entries.date >= ? AND time_entries.date <= ?", filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02")).
+		Where("time_entries.validated = ? OR EXISTS (SELECT 1 FROM weekly_declarations "+
+			"WHERE weekly_declarations.user_id = time_entries.user_id AND weekly_declarations.status = ? "+
+			"AND weekly_declarations.start_date <= time_entries.date AND weekly_declarations.end_date >= time_entries.date)",
+			true, models.WeeklyDeclarationStatusApproved)
+	if filter.DepartmentID != nil {
+		query = query.Where("users.department_id = ?", *filter.DepartmentID)
+	}
+	if filter.FilialeID != nil {
+		query = query.Where("users.filiale_id = ?", *filter.FilialeID)
+	}
+	err := query.Group("users.id, projects.id, departments.id").
+		Order("last_name ASC, first_name ASC, users.id ASC, cost_center_type ASC, cost_center_code ASC").
+		Scan(&rows).Error
+	return rows, err
+}
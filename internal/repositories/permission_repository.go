@@ -65,11 +65,20 @@ func (r *permissionRepository) FindByModule(module string) ([]models.Permission,
 
 // Update met à jour une permission
 func (r *permissionRepository) Update(permission *models.Permission) error {
-	return database.DB.Save(permission).Error
+	if err := database.DB.Save(permission).Error; err != nil {
+		return err
+	}
+	// Le code de la permission peut avoir changé : invalider les permissions des rôles en cache
+	InvalidateRoleCache()
+	return nil
 }
 
 // Delete supprime une permission
 func (r *permissionRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.Permission{}, id).Error
+	if err := database.DB.Delete(&models.Permission{}, id).Error; err != nil {
+		return err
+	}
+	InvalidateRoleCache()
+	return nil
 }
 
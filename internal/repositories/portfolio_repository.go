@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+)
+
+// MaxPortfolioProjects nombre maximal de projets couverts par le portefeuille
+const MaxPortfolioProjects = 5000
+
+// PortfolioFilter critères de sélection des projets du portefeuille
+type PortfolioFilter struct {
+	FilialeID       *uint
+	IncludeArchived bool
+}
+
+// PortfolioProject projet du portefeuille (colonnes utiles aux agrégats)
+type PortfolioProject struct {
+	ID              uint
+	Name            string
+	Status          string
+	FilialeID       *uint
+	FilialeName     string
+	TotalBudgetTime *int
+	ConsumedTime    int
+	CreatedAt       time.Time
+}
+
+// PortfolioTaskCount tâches non clôturées et en retard d'un projet
+type PortfolioTaskCount struct {
+	ProjectID    uint
+	OpenCount    int
+	OverdueCount int
+}
+
+// PortfolioMonthValue valeur d'un mois (période AAAA-MM)
+type PortfolioMonthValue struct {
+	Period string
+	Value  int
+}
+
+// PortfolioRepository interface pour les données du portefeuille de projets
+type PortfolioRepository interface {
+	FindProjects(scope interface{}, filter PortfolioFilter) ([]PortfolioProject, error)
+	CountTasks(projectIDs []uint, today time.Time) ([]PortfolioTaskCount, error)
+	CountTasksCreatedByMonth(projectIDs []uint, from time.Time) ([]PortfolioMonthValue, error)
+	CountTasksClosedByMonth(projectIDs []uint, from time.Time) ([]PortfolioMonthValue, error)
+	SumTimeSpentByMonth(projectIDs []uint, from time.Time) ([]PortfolioMonthValue, error)
+}
+
+// portfolioRepository implémente PortfolioRepository
+type portfolioRepository struct{}
+
+// NewPortfolioRepository crée une nouvelle instance de PortfolioRepository
+func NewPortfolioRepository() PortfolioRepository {
+	return &portfolioRepository{}
+}
+
+// FindProjects récupère les projets visibles selon le périmètre et les filtres (au plus MaxPortfolioProjects + 1)
+func (r *portfolioRepository) FindProjects(scopeParam interface{}, filter PortfolioFilter) ([]PortfolioProject, error) {
+	var projects []PortfolioProject
+	query := database.DB.Model(&models.Project{}).
+		Select("projects.id, projects.name, projects.status, projects.filiale_id, COALESCE(filiales.name, '') AS filiale_name, " +
+			"projects.total_budget_time, projects.consumed_time, projects.created_at").
+		Joins("LEFT JOIN filiales ON filiales.id = projects.filiale_id")
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		query = scope.ApplyProjectScope(query, queryScope)
+	}
+	if !filter.IncludeArchived {
+		query = query.Where("projects.status <> ?", models.ProjectStatusArchived)
+	}
+	if filter.FilialeID != nil {
+		query = query.Where("projects.filiale_id = ?", *filter.FilialeID)
+	}
+	err := query.Order("projects.id ASC").Limit(MaxPortfolioProjects + 1).Scan(&projects).Error
+	return projects, err
+}
+
+// CountTasks compte, par projet, les tâches non clôturées et celles dont l'échéance est antérieure à today
+func (r *portfolioRepository) CountTasks(projectIDs []uint, today time.Time) ([]PortfolioTaskCount, error) {
+	var counts []PortfolioTaskCount
+	if len(projectIDs) == 0 {
+		return counts, nil
+	}
+	err := database.DB.Model(&models.ProjectTask{}).
+		Select("project_id, COUNT(*) AS open_count, SUM(CASE WHEN due_date IS NOT NULL AND due_date < ? THEN 1 ELSE 0 END) AS overdue_count", today).
+		Where("project_id IN ? AND status <> ?", projectIDs, "cloture").
+		Group("project_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountTasksCreatedByMonth compte les tâches créées depuis from, par mois
+func (r *portfolioRepository) CountTasksCreatedByMonth(projectIDs []uint, from time.Time) ([]PortfolioMonthValue, error) {
+	var values []PortfolioMonthValue
+	if len(projectIDs) == 0 {
+		return values, nil
+	}
+	err := database.DB.Model(&models.ProjectTask{}).
+		Select("DATE_FORMAT(created_at, '%Y-%m') AS period, COUNT(*) AS value").
+		Where("project_id IN ? AND created_at >= ?", projectIDs, from).
+		Group("DATE_FORMAT(created_at, '%Y-%m')").
+		Scan(&values).Error
+	return values, err
+}
+
+// CountTasksClosedByMonth compte les tâches clôturées depuis from, par mois de clôture
+func (r *portfolioRepository) CountTasksClosedByMonth(projectIDs []uint, from time.Time) ([]PortfolioMonthValue, error) {
+	var values []PortfolioMonthValue
+	if len(projectIDs) == 0 {
+		return values, nil
+	}
+	err := database.DB.Model(&models.ProjectTask{}).
+		Select("DATE_FORMAT(closed_at, '%Y-%m') AS period, COUNT(*) AS value").
+		Where("project_id IN ? AND status = ? AND closed_at >= ?", projectIDs, "cloture", from).
+		Group("DATE_FORMAT(closed_at, '%Y-%m')").
+		Scan(&values).Error
+	return values, err
+}
+
+// SumTimeSpentByMonth cumule le temps saisi sur les tâches des projets depuis from, par mois
+func (r *portfolioRepository) SumTimeSpentByMonth(projectIDs []uint, from time.Time) ([]PortfolioMonthValue, error) {
+	var values []PortfolioMonthValue
+	if len(projectIDs) == 0 {
+		return values, nil
+	}
+	err := database.DB.Model(&models.TimeEntry{}).
+		Select("DATE_FORMAT(time_entries.date, '%Y-%m') AS period, SUM(time_entries.time_spent) AS value").
+		Joins("JOIN project_tasks ON project_tasks.id = time_entries.project_task_id").
+		Where("project_tasks.project_id IN ? AND time_entries.date >= ?", projectIDs, from).
+		Group("DATE_FORMAT(time_entries.date, '%Y-%m')").
+		Scan(&values).Error
+	return values, err
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProblemRepository interface pour les opérations sur les problèmes
+type ProblemRepository interface {
+	Create(problem *models.Problem) error
+	FindByID(id uint) (*models.Problem, error)
+	FindByTicketID(ticketID uint) (*models.Problem, error)
+	FindAll(scope interface{}, knownError *bool) ([]models.Problem, error)             // scope peut être *scope.QueryScope ou nil
+	FindByIncidentTicketID(scope interface{}, ticketID uint) ([]models.Problem, error) // Problèmes auxquels un ticket incident est lié
+	Update(problem *models.Problem) error
+	Delete(id uint) error
+	AddIncident(link *models.ProblemIncident) error
+	FindIncident(problemID, ticketID uint) (*models.ProblemIncident, error)
+	FindIncidents(problemID uint) ([]models.ProblemIncident, error)
+	RemoveIncident(problemID, ticketID uint) error
+	CountIncidents(problemIDs []uint) (map[uint]int, error)
+}
+
+// problemRepository implémente ProblemRepository
+type problemRepository struct{}
+
+// NewProblemRepository crée une nouvelle instance de ProblemRepository
+func NewProblemRepository() ProblemRepository {
+	return &problemRepository{}
+}
+
+// preloadProblem charge le ticket, le déclarant de l'erreur connue et l'article du contournement
+func preloadProblem(db *gorm.DB) *gorm.DB {
+	return db.Preload("Ticket").Preload("Ticket.CreatedBy").Preload("Ticket.AssignedTo").
+		Preload("KnownErrorBy").Preload("WorkaroundArticle")
+}
+
+// Create crée un nouveau problème
+func (r *problemRepository) Create(problem *models.Problem) error {
+	return database.DB.Omit(clause.Associations).Create(problem).Error
+}
+
+// FindByID trouve un problème par son ID avec son ticket
+func (r *problemRepository) FindByID(id uint) (*models.Problem, error) {
+	var problem models.Problem
+	if err := preloadProblem(database.DB).First(&problem, id).Error; err != nil {
+		return nil, err
+	}
+	return &problem, nil
+}
+
+// FindByTicketID trouve un problème par l'ID de son ticket
+func (r *problemRepository) FindByTicketID(ticketID uint) (*models.Problem, error) {
+	var problem models.Problem
+	if err := preloadProblem(database.DB).Where("ticket_id = ?", ticketID).First(&problem).Error; err != nil {
+		return nil, err
+	}
+	return &problem, nil
+}
+
+// FindAll récupère les problèmes, éventuellement limités aux erreurs connues (ou aux autres)
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (r *problemRepository) FindAll(scopeParam interface{}, knownError *bool) ([]models.Problem, error) {
+	var problems []models.Problem
+	query := preloadProblem(database.DB.Model(&models.Problem{}))
+	if knownError != nil {
+		query = query.Where("problems.is_known_error = ?", *knownError)
+	}
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		query = scope.ApplyProblemScope(query, queryScope)
+	}
+	err := query.Order("problems.created_at DESC").Find(&problems).Error
+	return problems, err
+}
+
+// FindByIncidentTicketID récupère les problèmes auxquels un ticket incident est lié
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (r *problemRepository) FindByIncidentTicketID(scopeParam interface{}, ticketID uint) ([]models.Problem, error) {
+	var problems []models.Problem
+	query := preloadProblem(database.DB.Model(&models.Problem{})).
+		Where("problems.id IN (?)", database.DB.Model(&models.ProblemIncident{}).Select("problem_id").Where("ticket_id = ?", ticketID))
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		query = scope.ApplyProblemScope(query, queryScope)
+	}
+	err := query.Order("problems.created_at DESC").Find(&problems).Error
+	return problems, err
+}
+
+// Update met à jour un problème
+func (r *problemRepository) Update(problem *models.Problem) error {
+	return database.DB.Omit(clause.Associations).Save(problem).Error
+}
+
+// Delete supprime un problème (les liaisons aux incidents sont supprimées en cascade)
+func (r *problemRepository) Delete(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("problem_id = ?", id).Delete(&models.ProblemIncident{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Problem{}, id).Error
+	})
+}
+
+// AddIncident lie un ticket incident à un problème
+func (r *problemRepository) AddIncident(link *models.ProblemIncident) error {
+	return database.DB.Omit(clause.Associations).Create(link).Error
+}
+
+// FindIncident récupère la liaison entre un problème et un ticket incident
+func (r *problemRepository) FindIncident(problemID, ticketID uint) (*models.ProblemIncident, error) {
+	var link models.ProblemIncident
+	err := database.DB.Preload("Ticket").Preload("LinkedBy").
+		Where("problem_id = ? AND ticket_id = ?", problemID, ticketID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindIncidents récupère les tickets incidents liés à un problème
+func (r *problemRepository) FindIncidents(problemID uint) ([]models.ProblemIncident, error) {
+	var links []models.ProblemIncident
+	err := database.DB.Preload("Ticket").Preload("LinkedBy").
+		Where("problem_id = ?", problemID).Order("created_at ASC").Find(&links).Error
+	return links, err
+}
+
+// RemoveIncident supprime la liaison entre un problème et un ticket incident
+func (r *problemRepository) RemoveIncident(problemID, ticketID uint) error {
+	return database.DB.Where("problem_id = ? AND ticket_id = ?", problemID, ticketID).Delete(&models.ProblemIncident{}).Error
+}
+
+// CountIncidents compte les incidents liés à chaque problème
+func (r *problemRepository) CountIncidents(problemIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(problemIDs))
+	if len(problemIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		ProblemID uint
+		Count     int
+	}
+	err := database.DB.Model(&models.ProblemIncident{}).
+		Select("problem_id, COUNT(*) AS count").
+		Where("problem_id IN ?", problemIDs).
+		Group("problem_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ProblemID] = row.Count
+	}
+	return counts, nil
+}
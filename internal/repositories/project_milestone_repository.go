@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxDashboardMilestones nombre maximal de jalons retournés par liste du tableau de bord
+const maxDashboardMilestones = 100
+
+// ProjectMilestoneRepository interface pour les jalons de projet
+type ProjectMilestoneRepository interface {
+	Create(milestone *models.ProjectMilestone) error
+	FindByID(id uint) (*models.ProjectMilestone, error)
+	FindByProjectID(projectID uint) ([]models.ProjectMilestone, error)
+	Update(milestone *models.ProjectMilestone) error
+	Delete(id uint) error
+	FindUpcoming(scope interface{}, from, to time.Time) ([]models.ProjectMilestone, error) // Jalons planifiés dont l'échéance est dans [from, to]
+	FindLate(scope interface{}, today time.Time) ([]models.ProjectMilestone, error)        // Jalons planifiés dont l'échéance est dépassée
+	FindLateToAlert(today time.Time) ([]models.ProjectMilestone, error)                    // Jalons en retard dont l'alerte n'a pas encore été envoyée
+	MarkLateAlerted(id uint, at time.Time) error
+}
+
+// projectMilestoneRepository implémente ProjectMilestoneRepository
+type projectMilestoneRepository struct{}
+
+// NewProjectMilestoneRepository crée une nouvelle instance de ProjectMilestoneRepository
+func NewProjectMilestoneRepository() ProjectMilestoneRepository {
+	return &projectMilestoneRepository{}
+}
+
+// withMilestoneRelations précharge le projet et l'étape liée (identifiants et noms)
+func withMilestoneRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Project", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "status", "project_manager_id", "lead_id")
+	}).Preload("ProjectPhase", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "project_id", "name", "status")
+	})
+}
+
+// scopedMilestones construit la requête des jalons planifiés restreinte au périmètre projets de l'utilisateur (hors projets archivés)
+func scopedMilestones(scopeParam interface{}) *gorm.DB {
+	query := withMilestoneRelations(database.DB.Model(&models.ProjectMilestone{})).
+		Joins("JOIN projects ON projects.id = project_milestones.project_id AND projects.status <> ?", models.ProjectStatusArchived).
+		Where("project_milestones.status = ?", models.MilestoneStatusPlanned)
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		query = scope.ApplyProjectScope(query, queryScope)
+	}
+	return query
+}
+
+// Create crée un jalon
+func (r *projectMilestoneRepository) Create(milestone *models.ProjectMilestone) error {
+	return database.DB.Omit(clause.Associations).Create(milestone).Error
+}
+
+// FindByID récupère un jalon par son ID
+func (r *projectMilestoneRepository) FindByID(id uint) (*models.ProjectMilestone, error) {
+	var milestone models.ProjectMilestone
+	if err := withMilestoneRelations(database.DB).First(&milestone, id).Error; err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// FindByProjectID récupère les jalons d'un projet, par échéance
+func (r *projectMilestoneRepository) FindByProjectID(projectID uint) ([]models.ProjectMilestone, error) {
+	var milestones []models.ProjectMilestone
+	err := withMilestoneRelations(database.DB).
+		Where("project_id = ?", projectID).
+		Order("due_date ASC, id ASC").
+		Find(&milestones).Error
+	return milestones, err
+}
+
+// Update met à jour un jalon
+func (r *projectMilestoneRepository) Update(milestone *models.ProjectMilestone) error {
+	return database.DB.Omit(clause.Associations).Save(milestone).Error
+}
+
+// Delete supprime un jalon
+func (r *projectMilestoneRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.ProjectMilestone{}, id).Error
+}
+
+// FindUpcoming récupère les jalons planifiés à échéance dans [from, to], les plus proches d'abord
+func (r *projectMilestoneRepository) FindUpcoming(scopeParam interface{}, from, to time.Time) ([]models.ProjectMilestone, error) {
+	var milestones []models.ProjectMilestone
+	err := scopedMilestones(scopeParam).
+		Where("project_milestones.due_date >= ? AND project_milestones.due_date <= ?", from, to).
+		Order("project_milestones.due_date ASC, project_milestones.id ASC").
+		Limit(maxDashboardMilestones).
+		Find(&milestones).Error
+	return milestones, err
+}
+
+// FindLate récupère les jalons planifiés dont l'échéance est antérieure à today, les plus anciens d'abord
+func (r *projectMilestoneRepository) FindLate(scopeParam interface{}, today time.Time) ([]models.ProjectMilestone, error) {
+	var milestones []models.ProjectMilestone
+	err := scopedMilestones(scopeParam).
+		Where("project_milestones.due_date < ?", today).
+		Order("project_milestones.due_date ASC, project_milestones.id ASC").
+		Limit(maxDashboardMilestones).
+		Find(&milestones).Error
+	return milestones, err
+}
+
+// FindLateToAlert récupère les jalons planifiés en retard dont l'alerte n'a pas encore été envoyée (hors projets archivés)
+func (r *projectMilestoneRepository) FindLateToAlert(today time.Time) ([]models.ProjectMilestone, error) {
+	var milestones []models.ProjectMilestone
+	err := withMilestoneRelations(database.DB.Model(&models.ProjectMilestone{})).
+		Joins("JOIN projects ON projects.id = project_milestones.project_id AND projects.status <> ?", models.ProjectStatusArchived).
+		Where("project_milestones.status = ? AND project_milestones.due_date < ? AND project_milestones.late_alerted_at IS NULL", models.MilestoneStatusPlanned, today).
+		Order("project_milestones.due_date ASC, project_milestones.id ASC").
+		Find(&milestones).Error
+	return milestones, err
+}
+
+// MarkLateAlerted enregistre l'envoi de l'alerte de retard d'un jalon
+func (r *projectMilestoneRepository) MarkLateAlerted(id uint, at time.Time) error {
+	return database.DB.Model(&models.ProjectMilestone{}).Where("id = ?", id).Update("late_alerted_at", at).Error
+}
@@ -46,6 +46,10 @@ func (r *projectPhaseRepository) Update(p *models.ProjectPhase) error {
 }
 
 func (r *projectPhaseRepository) Delete(id uint) error {
+	// Les jalons liés à l'étape restent rattachés au projet
+	if err := database.DB.Model(&models.ProjectMilestone{}).Where("project_phase_id = ?", id).Update("project_phase_id", nil).Error; err != nil {
+		return err
+	}
 	return database.DB.Delete(&models.ProjectPhase{}, id).Error
 }
 
@@ -11,10 +11,11 @@ import (
 type ProjectRepository interface {
 	Create(project *models.Project) error
 	FindByID(id uint) (*models.Project, error)
-	FindAll(scope interface{}) ([]models.Project, error) // scope peut être *scope.QueryScope ou nil
+	FindAll(scope interface{}, includeArchived bool) ([]models.Project, error) // scope peut être *scope.QueryScope ou nil ; projets archivés exclus sauf includeArchived
 	FindByStatus(scope interface{}, status string) ([]models.Project, error)
 	Update(project *models.Project) error
 	UpdateStatus(projectID uint, status string) error
+	UpdateArchiveState(project *models.Project) error // Statut et champs d'archivage
 	Delete(id uint) error
 	UpdateConsumedTime(projectID uint, consumedTime int) error
 	IncrementTotalBudgetTime(projectID uint, additionalMinutes int) error
@@ -45,12 +46,15 @@ func (r *projectRepository) FindByID(id uint) (*models.Project, error) {
 
 // FindAll récupère tous les projets
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
-func (r *projectRepository) FindAll(scopeParam interface{}) ([]models.Project, error) {
+func (r *projectRepository) FindAll(scopeParam interface{}, includeArchived bool) ([]models.Project, error) {
 	var projects []models.Project
 	
 	// Construire la requête de base
 	query := database.DB.Model(&models.Project{}).
 		Preload("CreatedBy")
+	if !includeArchived {
+		query = query.Where("projects.status <> ?", models.ProjectStatusArchived)
+	}
 	
 	// Appliquer le scope si fourni
 	if scopeParam != nil {
@@ -102,6 +106,16 @@ func (r *projectRepository) UpdateStatus(projectID uint, status string) error {
 	return database.DB.Model(&models.Project{}).Where("id = ?", projectID).Update("status", status).Error
 }
 
+// UpdateArchiveState met à jour le statut et les champs d'archivage d'un projet
+func (r *projectRepository) UpdateArchiveState(project *models.Project) error {
+	return database.DB.Model(&models.Project{}).Where("id = ?", project.ID).Updates(map[string]interface{}{
+		"status":                project.Status,
+		"status_before_archive": project.StatusBeforeArchive,
+		"archived_at":           project.ArchivedAt,
+		"archived_by_id":        project.ArchivedByID,
+	}).Error
+}
+
 // Delete supprime un projet
 func (r *projectRepository) Delete(id uint) error {
 	return database.DB.Delete(&models.Project{}, id).Error
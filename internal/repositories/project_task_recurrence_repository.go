@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxDueTaskRecurrences nombre maximal de règles traitées par passage
+	maxDueTaskRecurrences = 200
+	// maxExpiredOccurrences nombre maximal d'occurrences expirées clôturées par passage
+	maxExpiredOccurrences = 500
+)
+
+// ProjectTaskRecurrenceRepository interface pour les règles de récurrence des tâches de projet
+type ProjectTaskRecurrenceRepository interface {
+	Create(recurrence *models.ProjectTaskRecurrence) error
+	FindByID(id uint) (*models.ProjectTaskRecurrence, error)
+	FindByProjectID(projectID uint) ([]models.ProjectTaskRecurrence, error)
+	Update(recurrence *models.ProjectTaskRecurrence) error
+	Delete(id uint) error                                                            // Les tâches déjà créées sont conservées, sans règle
+	FindDue(before time.Time) ([]models.ProjectTaskRecurrence, error)                // Règles actives dont la prochaine occurrence est au plus tard à before
+	Claim(id uint, occurrenceAt time.Time, next *time.Time, count int) (bool, error) // Avance la prochaine occurrence si elle n'a pas déjà été traitée
+	RecordError(id uint, runError string) error
+	TagTask(taskID, recurrenceID uint, occurrenceAt, expiresAt *time.Time) error // Rattache une tâche à la règle
+	FindExpiredOccurrences(now time.Time) ([]models.ProjectTask, error)          // Occurrences non clôturées expirées, pour les règles qui les clôturent
+}
+
+// projectTaskRecurrenceRepository implémente ProjectTaskRecurrenceRepository
+type projectTaskRecurrenceRepository struct{}
+
+// NewProjectTaskRecurrenceRepository crée une nouvelle instance de ProjectTaskRecurrenceRepository
+func NewProjectTaskRecurrenceRepository() ProjectTaskRecurrenceRepository {
+	return &projectTaskRecurrenceRepository{}
+}
+
+// Create crée une règle de récurrence
+func (r *projectTaskRecurrenceRepository) Create(recurrence *models.ProjectTaskRecurrence) error {
+	return database.DB.Omit(clause.Associations).Create(recurrence).Error
+}
+
+// FindByID récupère une règle par son ID
+func (r *projectTaskRecurrenceRepository) FindByID(id uint) (*models.ProjectTaskRecurrence, error) {
+	var recurrence models.ProjectTaskRecurrence
+	if err := database.DB.First(&recurrence, id).Error; err != nil {
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+// FindByProjectID récupère les règles d'un projet
+func (r *projectTaskRecurrenceRepository) FindByProjectID(projectID uint) ([]models.ProjectTaskRecurrence, error) {
+	var recurrences []models.ProjectTaskRecurrence
+	err := database.DB.Where("project_id = ?", projectID).Order("id ASC").Find(&recurrences).Error
+	return recurrences, err
+}
+
+// Update met à jour une règle
+func (r *projectTaskRecurrenceRepository) Update(recurrence *models.ProjectTaskRecurrence) error {
+	return database.DB.Omit(clause.Associations).Save(recurrence).Error
+}
+
+// Delete supprime une règle et détache ses tâches
+func (r *projectTaskRecurrenceRepository) Delete(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ProjectTask{}).Where("recurrence_id = ?", id).
+			Updates(map[string]interface{}{"recurrence_id": nil, "expires_at": nil}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProjectTaskRecurrence{}, id).Error
+	})
+}
+
+// FindDue récupère les règles actives dont la prochaine occurrence est au plus tard à before, de la plus proche à la plus lointaine
+// Les règles des projets archivés sont ignorées
+func (r *projectTaskRecurrenceRepository) FindDue(before time.Time) ([]models.ProjectTaskRecurrence, error) {
+	var recurrences []models.ProjectTaskRecurrence
+	err := database.DB.Model(&models.ProjectTaskRecurrence{}).
+		Joins("JOIN projects ON projects.id = project_task_recurrences.project_id AND projects.status <> ?", models.ProjectStatusArchived).
+		Where("project_task_recurrences.is_active = ? AND project_task_recurrences.next_occurrence_at IS NOT NULL AND project_task_recurrences.next_occurrence_at <= ?", true, before).
+		Order("project_task_recurrences.next_occurrence_at ASC").Limit(maxDueTaskRecurrences).Find(&recurrences).Error
+	return recurrences, err
+}
+
+// Claim avance la prochaine occurrence d'une règle à condition qu'elle n'ait pas déjà été traitée
+func (r *projectTaskRecurrenceRepository) Claim(id uint, occurrenceAt time.Time, next *time.Time, count int) (bool, error) {
+	result := database.DB.Model(&models.ProjectTaskRecurrence{}).
+		Where("id = ? AND next_occurrence_at = ?", id, occurrenceAt).
+		Updates(map[string]interface{}{"next_occurrence_at": next, "last_occurrence_at": occurrenceAt, "occurrence_count": count})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordError enregistre le résultat du dernier passage (vide = succès)
+func (r *projectTaskRecurrenceRepository) RecordError(id uint, runError string) error {
+	return database.DB.Model(&models.ProjectTaskRecurrence{}).Where("id = ?", id).Update("last_error", runError).Error
+}
+
+// TagTask rattache une tâche à une règle, avec la date de l'occurrence et son expiration
+func (r *projectTaskRecurrenceRepository) TagTask(taskID, recurrenceID uint, occurrenceAt, expiresAt *time.Time) error {
+	return database.DB.Model(&models.ProjectTask{}).Where("id = ?", taskID).Updates(map[string]interface{}{
+		"recurrence_id": recurrenceID,
+		"occurrence_at": occurrenceAt,
+		"expires_at":    expiresAt,
+	}).Error
+}
+
+// FindExpiredOccurrences récupère les occurrences non clôturées dont l'expiration est passée (hors projets archivés)
+func (r *projectTaskRecurrenceRepository) FindExpiredOccurrences(now time.Time) ([]models.ProjectTask, error) {
+	var tasks []models.ProjectTask
+	err := database.DB.Model(&models.ProjectTask{}).
+		Select("project_tasks.id", "project_tasks.project_id", "project_tasks.code", "project_tasks.recurrence_id").
+		Joins("JOIN project_task_recurrences ptr ON ptr.id = project_tasks.recurrence_id").
+		Joins("JOIN projects ON projects.id = project_tasks.project_id AND projects.status <> ?", models.ProjectStatusArchived).
+		Where("ptr.close_expired = ? AND project_tasks.status <> ? AND project_tasks.expires_at IS NOT NULL AND project_tasks.expires_at <= ?", true, "cloture", now).
+		Order("project_tasks.expires_at ASC").Limit(maxExpiredOccurrences).
+		Find(&tasks).Error
+	return tasks, err
+}
@@ -7,8 +7,25 @@ import (
 
 	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
 )
 
+// preloadTaskDependencies charge les prérequis d'une tâche avec l'essentiel de chaque tâche prérequise
+func preloadTaskDependencies(db *gorm.DB) *gorm.DB {
+	return db.Preload("Dependencies", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Dependencies.DependsOn", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "project_id", "code", "title", "status")
+	})
+}
+
+// preloadTaskRecurrence charge la règle de récurrence d'une tâche (badge : planification et statut)
+func preloadTaskRecurrence(db *gorm.DB) *gorm.DB {
+	return db.Preload("Recurrence", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "project_id", "schedule", "timezone", "is_active", "next_occurrence_at")
+	})
+}
+
 // ProjectTaskRepository interface pour les tâches de projet
 type ProjectTaskRepository interface {
 	FindByProjectID(projectID uint) ([]models.ProjectTask, error)
@@ -20,6 +37,17 @@ type ProjectTaskRepository interface {
 	Delete(id uint) error
 	GenerateCode(projectID uint) (string, error)
 	ReplaceAssignees(taskID uint, userIDs []uint) error
+	// Dépendances entre tâches
+	FindDependencies(taskID uint) ([]models.ProjectTaskDependency, error) // Prérequis de la tâche, avec la tâche prérequise
+	FindDependents(taskID uint) ([]models.ProjectTask, error)             // Tâches qui dépendent de la tâche, avec leurs assignés
+	FindDependenciesByProject(projectID uint) ([]models.ProjectTaskDependency, error)
+	CreateDependency(d *models.ProjectTaskDependency) error
+	DeleteDependency(taskID, dependsOnID uint) error
+	CountOpenPrerequisites(taskID uint) (int64, error) // Prérequis non clôturés
+	SetBlocked(taskID uint, blocked bool) error
+	// Ordre manuel des colonnes du tableau (une colonne = un statut)
+	FindBoardColumn(projectID uint, status string, excludeID uint) ([]models.ProjectTask, error) // Tâches de la colonne (id, board_rank), dans l'ordre du tableau
+	SetBoardRanks(ranks map[uint]string) error
 }
 
 type projectTaskRepository struct{}
@@ -31,16 +59,16 @@ func NewProjectTaskRepository() ProjectTaskRepository {
 
 func (r *projectTaskRepository) FindByProjectID(projectID uint) ([]models.ProjectTask, error) {
 	var list []models.ProjectTask
-	err := database.DB.Where("project_id = ?", projectID).
+	err := preloadTaskRecurrence(preloadTaskDependencies(database.DB.Where("project_id = ?", projectID))).
 		Preload("ProjectPhase").Preload("AssignedTo").Preload("CreatedBy").Preload("Assignees").Preload("Assignees.User").
-		Order("project_phase_id ASC, display_order ASC, id ASC").
+		Order("project_phase_id ASC, board_rank ASC, display_order ASC, id ASC").
 		Find(&list).Error
 	return list, err
 }
 
 func (r *projectTaskRepository) FindByPhaseID(phaseID uint) ([]models.ProjectTask, error) {
 	var list []models.ProjectTask
-	err := database.DB.Where("project_phase_id = ?", phaseID).
+	err := preloadTaskRecurrence(database.DB.Where("project_phase_id = ?", phaseID)).
 		Preload("AssignedTo").Preload("CreatedBy").
 		Order("display_order ASC, id ASC").
 		Find(&list).Error
@@ -49,7 +77,7 @@ func (r *projectTaskRepository) FindByPhaseID(phaseID uint) ([]models.ProjectTas
 
 func (r *projectTaskRepository) FindByID(id uint) (*models.ProjectTask, error) {
 	var t models.ProjectTask
-	err := database.DB.Preload("ProjectPhase").Preload("AssignedTo").Preload("CreatedBy").Preload("Assignees").Preload("Assignees.User").
+	err := preloadTaskRecurrence(preloadTaskDependencies(database.DB)).Preload("ProjectPhase").Preload("AssignedTo").Preload("CreatedBy").Preload("Assignees").Preload("Assignees.User").
 		First(&t, id).Error
 	if err != nil {
 		return nil, err
@@ -62,7 +90,8 @@ func (r *projectTaskRepository) Create(t *models.ProjectTask) error {
 }
 
 func (r *projectTaskRepository) Update(t *models.ProjectTask) error {
-	return database.DB.Save(t).Error
+	// Les prérequis se gèrent via CreateDependency / DeleteDependency, la récurrence via les règles
+	return database.DB.Omit("Dependencies", "Recurrence").Save(t).Error
 }
 
 func (r *projectTaskRepository) UpdateActualTime(taskID uint, minutes int) error {
@@ -71,7 +100,14 @@ func (r *projectTaskRepository) UpdateActualTime(taskID uint, minutes int) error
 
 func (r *projectTaskRepository) Delete(id uint) error {
 	_ = database.DB.Where("project_task_id = ?", id).Delete(&models.ProjectTaskAssignee{}).Error
-	return database.DB.Delete(&models.ProjectTask{}, id).Error
+	_ = database.DB.Where("task_id = ? OR depends_on_id = ?", id, id).Delete(&models.ProjectTaskDependency{}).Error
+	_ = database.DB.Where("project_task_id = ?", id).Delete(&models.ProjectTaskTimer{}).Error
+	_ = database.DB.Where("project_task_id = ?", id).Delete(&models.TicketProjectTask{}).Error
+	if err := database.DB.Delete(&models.ProjectTask{}, id).Error; err != nil {
+		return err
+	}
+	// Trace pour la synchronisation des clients mobiles (pas de soft delete)
+	return RecordTombstones(database.DB, models.SyncEntityTasks, []uint{id}, nil)
 }
 
 func (r *projectTaskRepository) ReplaceAssignees(taskID uint, userIDs []uint) error {
@@ -88,6 +124,60 @@ func (r *projectTaskRepository) ReplaceAssignees(taskID uint, userIDs []uint) er
 	return database.DB.Create(&list).Error
 }
 
+// FindDependencies récupère les prérequis d'une tâche
+func (r *projectTaskRepository) FindDependencies(taskID uint) ([]models.ProjectTaskDependency, error) {
+	var list []models.ProjectTaskDependency
+	err := database.DB.Where("task_id = ?", taskID).
+		Preload("DependsOn", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "project_id", "code", "title", "status")
+		}).
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// FindDependents récupère les tâches qui dépendent d'une tâche
+func (r *projectTaskRepository) FindDependents(taskID uint) ([]models.ProjectTask, error) {
+	var list []models.ProjectTask
+	err := database.DB.Where("id IN (?)", database.DB.Model(&models.ProjectTaskDependency{}).Select("task_id").Where("depends_on_id = ?", taskID)).
+		Preload("Assignees").
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// FindDependenciesByProject récupère tous les liens de dépendance entre les tâches d'un projet
+func (r *projectTaskRepository) FindDependenciesByProject(projectID uint) ([]models.ProjectTaskDependency, error) {
+	var list []models.ProjectTaskDependency
+	err := database.DB.Joins("JOIN project_tasks ON project_tasks.id = project_task_dependencies.task_id").
+		Where("project_tasks.project_id = ?", projectID).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *projectTaskRepository) CreateDependency(d *models.ProjectTaskDependency) error {
+	return database.DB.Omit("Task", "DependsOn", "CreatedBy").Create(d).Error
+}
+
+func (r *projectTaskRepository) DeleteDependency(taskID, dependsOnID uint) error {
+	return database.DB.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).Delete(&models.ProjectTaskDependency{}).Error
+}
+
+// CountOpenPrerequisites compte les prérequis d'une tâche qui ne sont pas clôturés
+func (r *projectTaskRepository) CountOpenPrerequisites(taskID uint) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.ProjectTaskDependency{}).
+		Joins("JOIN project_tasks ON project_tasks.id = project_task_dependencies.depends_on_id").
+		Where("project_task_dependencies.task_id = ? AND project_tasks.status <> ?", taskID, "cloture").
+		Count(&count).Error
+	return count, err
+}
+
+// SetBlocked met à jour l'indicateur de blocage d'une tâche (et sa date de modification, pour la synchronisation)
+func (r *projectTaskRepository) SetBlocked(taskID uint, blocked bool) error {
+	return database.DB.Model(&models.ProjectTask{}).Where("id = ?", taskID).Update("is_blocked", blocked).Error
+}
+
 var codeSuffixRE = regexp.MustCompile(`^TAP-\d{4}-(\d+)$`)
 
 // GenerateCode génère un code TAP-YYYY-NNNN pour une nouvelle tâche du projet
@@ -110,3 +200,25 @@ func (r *projectTaskRepository) GenerateCode(projectID uint) (string, error) {
 	}
 	return prefix + fmt.Sprintf("%04d", maxN+1), nil
 }
+
+// FindBoardColumn récupère les tâches d'une colonne du tableau (clés vides en tête, dans l'ordre d'affichage historique)
+func (r *projectTaskRepository) FindBoardColumn(projectID uint, status string, excludeID uint) ([]models.ProjectTask, error) {
+	var list []models.ProjectTask
+	err := database.DB.Select("id", "board_rank").
+		Where("project_id = ? AND status = ? AND id <> ?", projectID, status, excludeID).
+		Order("board_rank ASC, display_order ASC, id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// SetBoardRanks enregistre les clés d'ordre de plusieurs tâches
+func (r *projectTaskRepository) SetBoardRanks(ranks map[uint]string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		for id, rank := range ranks {
+			if err := tx.Model(&models.ProjectTask{}).Where("id = ?", id).Update("board_rank", rank).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProjectTaskTimerRepository interface pour les chronomètres des tâches de projet
+type ProjectTaskTimerRepository interface {
+	Create(timer *models.ProjectTaskTimer) error
+	FindByUserID(userID uint) (*models.ProjectTaskTimer, error)
+	Delete(id uint) (bool, error)
+	Stop(timer *models.ProjectTaskTimer, entry *models.TimeEntry) (bool, error)
+}
+
+// projectTaskTimerRepository implémente ProjectTaskTimerRepository
+type projectTaskTimerRepository struct{}
+
+// NewProjectTaskTimerRepository crée une nouvelle instance de ProjectTaskTimerRepository
+func NewProjectTaskTimerRepository() ProjectTaskTimerRepository {
+	return &projectTaskTimerRepository{}
+}
+
+// Create crée un chronomètre (échoue si l'utilisateur en a déjà un : index unique sur user_id)
+func (r *projectTaskTimerRepository) Create(timer *models.ProjectTaskTimer) error {
+	return database.DB.Omit(clause.Associations).Create(timer).Error
+}
+
+// FindByUserID récupère le chronomètre en cours d'un utilisateur, avec sa tâche et son projet (nil s'il n'y en a pas)
+func (r *projectTaskTimerRepository) FindByUserID(userID uint) (*models.ProjectTaskTimer, error) {
+	var timers []models.ProjectTaskTimer
+	err := database.DB.Preload("ProjectTask").Preload("Project").
+		Where("user_id = ?", userID).Limit(1).Find(&timers).Error
+	if err != nil || len(timers) == 0 {
+		return nil, err
+	}
+	return &timers[0], nil
+}
+
+// Delete supprime un chronomètre sans saisir de temps ; false s'il n'existait plus
+func (r *projectTaskTimerRepository) Delete(id uint) (bool, error) {
+	result := database.DB.Delete(&models.ProjectTaskTimer{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Stop arrête un chronomètre : suppression du chronomètre, création de l'entrée de temps et report des minutes sur la tâche
+// et le projet, dans une même transaction ; false si le chronomètre a déjà été arrêté (requêtes concurrentes)
+func (r *projectTaskTimerRepository) Stop(timer *models.ProjectTaskTimer, entry *models.TimeEntry) (bool, error) {
+	stopped := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.ProjectTaskTimer{}, timer.ID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Omit(clause.Associations).Create(entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ProjectTask{}).Where("id = ?", timer.ProjectTaskID).
+			Update("actual_time", gorm.Expr("actual_time + ?", entry.TimeSpent)).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Project{}).Where("id = ?", timer.ProjectID).
+			Update("consumed_time", gorm.Expr("consumed_time + ?", entry.TimeSpent)).Error; err != nil {
+			return err
+		}
+		stopped = true
+		return nil
+	})
+	return stopped, err
+}
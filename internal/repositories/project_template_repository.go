@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProjectTemplateRepository interface pour les modèles de projet
+type ProjectTemplateRepository interface {
+	Create(template *models.ProjectTemplate) error // Crée le modèle et son contenu (étapes, fonctions, membres, tâches)
+	FindByID(id uint) (*models.ProjectTemplate, error)
+	FindByName(name string) (*models.ProjectTemplate, error)
+	FindAll(activeOnly bool) ([]models.ProjectTemplate, error)
+	Update(template *models.ProjectTemplate) error // Met à jour le modèle et remplace son contenu
+	Delete(id uint) error
+}
+
+// projectTemplateRepository implémente ProjectTemplateRepository
+type projectTemplateRepository struct{}
+
+// NewProjectTemplateRepository crée une nouvelle instance de ProjectTemplateRepository
+func NewProjectTemplateRepository() ProjectTemplateRepository {
+	return &projectTemplateRepository{}
+}
+
+// withTemplateContent précharge le contenu d'un modèle dans l'ordre d'affichage
+func withTemplateContent(db *gorm.DB) *gorm.DB {
+	ordered := func(db *gorm.DB) *gorm.DB {
+		return db.Order("display_order ASC, id ASC")
+	}
+	return db.Preload("Phases", ordered).
+		Preload("Functions", ordered).
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Tasks", ordered)
+}
+
+// Create crée un modèle et son contenu
+func (r *projectTemplateRepository) Create(template *models.ProjectTemplate) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(template).Error; err != nil {
+			return err
+		}
+		return createTemplateContent(tx, template)
+	})
+}
+
+// FindByID récupère un modèle et son contenu
+func (r *projectTemplateRepository) FindByID(id uint) (*models.ProjectTemplate, error) {
+	var template models.ProjectTemplate
+	if err := withTemplateContent(database.DB).First(&template, id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindByName récupère un modèle par son nom
+func (r *projectTemplateRepository) FindByName(name string) (*models.ProjectTemplate, error) {
+	var template models.ProjectTemplate
+	if err := database.DB.Where("name = ?", name).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindAll récupère les modèles et leur contenu, par nom
+func (r *projectTemplateRepository) FindAll(activeOnly bool) ([]models.ProjectTemplate, error) {
+	var templates []models.ProjectTemplate
+	query := withTemplateContent(database.DB)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+// Update met à jour un modèle et remplace son contenu
+func (r *projectTemplateRepository) Update(template *models.ProjectTemplate) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(template).Error; err != nil {
+			return err
+		}
+		if err := deleteTemplateContent(tx, template.ID); err != nil {
+			return err
+		}
+		return createTemplateContent(tx, template)
+	})
+}
+
+// Delete supprime un modèle et son contenu (les projets déjà créés depuis le modèle sont conservés)
+func (r *projectTemplateRepository) Delete(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := deleteTemplateContent(tx, id); err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProjectTemplate{}, id).Error
+	})
+}
+
+// deleteTemplateContent supprime les tâches, membres, fonctions et étapes d'un modèle
+func deleteTemplateContent(tx *gorm.DB, templateID uint) error {
+	for _, model := range []interface{}{
+		&models.ProjectTemplateTask{},
+		&models.ProjectTemplateMember{},
+		&models.ProjectTemplateFunction{},
+		&models.ProjectTemplatePhase{},
+	} {
+		if err := tx.Where("template_id = ?", templateID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTemplateContent crée le contenu d'un modèle ; les membres et tâches référencent leurs étape et fonction
+// par leur rang (PhaseIndex, FunctionIndex), validé par le service
+func createTemplateContent(tx *gorm.DB, template *models.ProjectTemplate) error {
+	for i := range template.Phases {
+		phase := &template.Phases[i]
+		phase.ID, phase.TemplateID, phase.DisplayOrder = 0, template.ID, i
+		if err := tx.Create(phase).Error; err != nil {
+			return err
+		}
+	}
+	for i := range template.Functions {
+		function := &template.Functions[i]
+		function.ID, function.TemplateID, function.DisplayOrder = 0, template.ID, i
+		if err := tx.Create(function).Error; err != nil {
+			return err
+		}
+	}
+	functionID := func(index *int) *uint {
+		if index == nil {
+			return nil
+		}
+		return &template.Functions[*index].ID
+	}
+	for i := range template.Members {
+		member := &template.Members[i]
+		member.ID, member.TemplateID, member.FunctionID = 0, template.ID, functionID(member.FunctionIndex)
+		if err := tx.Create(member).Error; err != nil {
+			return err
+		}
+	}
+	for i := range template.Tasks {
+		task := &template.Tasks[i]
+		task.ID, task.TemplateID, task.DisplayOrder = 0, template.ID, i
+		task.PhaseID = template.Phases[task.PhaseIndex].ID
+		task.FunctionID = functionID(task.FunctionIndex)
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PushRepository interface pour les opérations sur les appareils enregistrés aux notifications push
+type PushRepository interface {
+	Save(device *models.PushDevice) error
+	FindByID(id uint) (*models.PushDevice, error)
+	FindByToken(token string) (*models.PushDevice, error)
+	FindByUserID(userID uint) ([]models.PushDevice, error)
+	Delete(id uint) error
+}
+
+// pushRepository implémente PushRepository
+type pushRepository struct{}
+
+// NewPushRepository crée une nouvelle instance de PushRepository
+func NewPushRepository() PushRepository {
+	return &pushRepository{}
+}
+
+// Save crée ou met à jour un appareil
+func (r *pushRepository) Save(device *models.PushDevice) error {
+	return database.DB.Save(device).Error
+}
+
+// FindByID trouve un appareil par son ID
+func (r *pushRepository) FindByID(id uint) (*models.PushDevice, error) {
+	var device models.PushDevice
+	if err := database.DB.First(&device, id).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// FindByToken trouve un appareil par son jeton
+func (r *pushRepository) FindByToken(token string) (*models.PushDevice, error) {
+	var device models.PushDevice
+	if err := database.DB.Where("token = ?", token).First(&device).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// FindByUserID récupère les appareils d'un utilisateur, du plus récemment vu au plus ancien
+func (r *pushRepository) FindByUserID(userID uint) ([]models.PushDevice, error) {
+	var devices []models.PushDevice
+	err := database.DB.Where("user_id = ?", userID).Order("last_seen_at DESC").Find(&devices).Error
+	return devices, err
+}
+
+// Delete supprime un appareil
+func (r *pushRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.PushDevice{}, id).Error
+}
+
+// deleteSessionPushDevices supprime les appareils enregistrés depuis les sessions correspondant à la condition
+// (appelé dans la transaction de suppression de ces sessions)
+func deleteSessionPushDevices(tx *gorm.DB, query string, args ...interface{}) error {
+	var sessionIDs []uint
+	if err := tx.Model(&models.UserSession{}).Where(query, args...).Pluck("id", &sessionIDs).Error; err != nil {
+		return err
+	}
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	return tx.Where("session_id IN ?", sessionIDs).Delete(&models.PushDevice{}).Error
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/cache"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+)
+
+// RecordShareRepository interface pour les opérations sur les partages de tickets et de projets
+type RecordShareRepository interface {
+	Create(share *models.RecordShare) error
+	FindByID(id uint) (*models.RecordShare, error)
+	FindByResource(resourceType string, resourceID uint) ([]models.RecordShare, error)
+	FindForRecipient(resourceType string, resourceID uint, userID, departmentID *uint) (*models.RecordShare, error)
+	FindActiveForUser(userID uint, departmentID *uint) ([]models.RecordShare, error)
+	FindActiveForUserCached(userID uint, departmentID *uint) ([]models.RecordShare, error)
+	FindActiveForUserWithDetails(userID uint, departmentID *uint) ([]models.RecordShare, error)
+	Update(share *models.RecordShare) error
+	Delete(id uint) error
+	PurgeExpired(before time.Time) (int64, error)
+	IsVisible(resourceType string, resourceID uint, queryScope *scope.QueryScope) (bool, error)
+}
+
+// recordShareCachePrefix préfixe les clés de cache des partages actifs par utilisateur
+const recordShareCachePrefix = "record_share:"
+
+// recordShareCacheTTL durée de vie des partages en cache (l'expiration est revérifiée à l'utilisation)
+const recordShareCacheTTL = time.Minute
+
+// invalidateRecordShareCache invalide les partages en cache (un partage de département concerne plusieurs utilisateurs)
+func invalidateRecordShareCache() {
+	cache.Shared.DeletePrefix(recordShareCachePrefix)
+}
+
+// recordShareRepository implémente RecordShareRepository
+type recordShareRepository struct{}
+
+// NewRecordShareRepository crée une nouvelle instance de RecordShareRepository
+func NewRecordShareRepository() RecordShareRepository {
+	return &recordShareRepository{}
+}
+
+// Create crée un nouveau partage
+func (r *recordShareRepository) Create(share *models.RecordShare) error {
+	defer invalidateRecordShareCache()
+	return database.DB.Create(share).Error
+}
+
+// FindByID trouve un partage par son ID
+func (r *recordShareRepository) FindByID(id uint) (*models.RecordShare, error) {
+	var share models.RecordShare
+	err := database.DB.
+		Preload("SharedWithUser").
+		Preload("SharedWithDepartment").
+		Preload("SharedBy").
+		First(&share, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// FindByResource récupère les partages d'un ticket ou d'un projet (y compris expirés)
+func (r *recordShareRepository) FindByResource(resourceType string, resourceID uint) ([]models.RecordShare, error) {
+	var shares []models.RecordShare
+	err := database.DB.
+		Preload("SharedWithUser").
+		Preload("SharedWithDepartment").
+		Preload("SharedBy").
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Order("created_at DESC").
+		Find(&shares).Error
+	return shares, err
+}
+
+// FindForRecipient trouve le partage d'une ressource avec un utilisateur ou un département donné
+func (r *recordShareRepository) FindForRecipient(resourceType string, resourceID uint, userID, departmentID *uint) (*models.RecordShare, error) {
+	query := database.DB.Where("resource_type = ? AND resource_id = ?", resourceType, resourceID)
+	if userID != nil {
+		query = query.Where("shared_with_user_id = ?", *userID)
+	} else {
+		query = query.Where("shared_with_user_id IS NULL")
+	}
+	if departmentID != nil {
+		query = query.Where("shared_with_department_id = ?", *departmentID)
+	} else {
+		query = query.Where("shared_with_department_id IS NULL")
+	}
+	var share models.RecordShare
+	if err := query.First(&share).Error; err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// FindActiveForUser récupère les partages non expirés dont bénéficie un utilisateur (directement ou via son département)
+func (r *recordShareRepository) FindActiveForUser(userID uint, departmentID *uint) ([]models.RecordShare, error) {
+	var shares []models.RecordShare
+	err := activeSharesQuery(userID, departmentID).Find(&shares).Error
+	return shares, err
+}
+
+// FindActiveForUserWithDetails récupère les partages actifs d'un utilisateur avec bénéficiaires et auteurs
+func (r *recordShareRepository) FindActiveForUserWithDetails(userID uint, departmentID *uint) ([]models.RecordShare, error) {
+	var shares []models.RecordShare
+	err := activeSharesQuery(userID, departmentID).
+		Preload("SharedWithUser").
+		Preload("SharedWithDepartment").
+		Preload("SharedBy").
+		Find(&shares).Error
+	return shares, err
+}
+
+// FindActiveForUserCached récupère les partages actifs d'un utilisateur en passant par le cache
+// Utilisé par le middleware d'authentification, appelé à chaque requête
+func (r *recordShareRepository) FindActiveForUserCached(userID uint, departmentID *uint) ([]models.RecordShare, error) {
+	key := fmt.Sprintf("%suser:%d", recordShareCachePrefix, userID)
+	if departmentID != nil {
+		key = fmt.Sprintf("%s:dept:%d", key, *departmentID)
+	}
+	shares, err := cache.GetOrLoad(cache.Shared, key, recordShareCacheTTL, func() ([]models.RecordShare, error) {
+		return r.FindActiveForUser(userID, departmentID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Retourner une copie pour protéger la valeur en cache
+	return append([]models.RecordShare(nil), shares...), nil
+}
+
+// Update met à jour un partage (niveau d'accès, expiration)
+func (r *recordShareRepository) Update(share *models.RecordShare) error {
+	defer invalidateRecordShareCache()
+	return database.DB.Model(&models.RecordShare{}).Where("id = ?", share.ID).Updates(map[string]interface{}{
+		"access_level": share.AccessLevel,
+		"expires_at":   share.ExpiresAt,
+	}).Error
+}
+
+// Delete supprime un partage
+func (r *recordShareRepository) Delete(id uint) error {
+	defer invalidateRecordShareCache()
+	return database.DB.Delete(&models.RecordShare{}, id).Error
+}
+
+// PurgeExpired supprime les partages expirés avant la date donnée
+func (r *recordShareRepository) PurgeExpired(before time.Time) (int64, error) {
+	defer invalidateRecordShareCache()
+	result := database.DB.Where("expires_at IS NOT NULL AND expires_at < ?", before).Delete(&models.RecordShare{})
+	return result.RowsAffected, result.Error
+}
+
+// activeSharesQuery construit la requête des partages non expirés d'un utilisateur et de son département
+func activeSharesQuery(userID uint, departmentID *uint) *gorm.DB {
+	query := database.DB.Where("expires_at IS NULL OR expires_at > ?", time.Now())
+	if departmentID != nil {
+		query = query.Where("shared_with_user_id = ? OR shared_with_department_id = ?", userID, *departmentID)
+	} else {
+		query = query.Where("shared_with_user_id = ?", userID)
+	}
+	return query.Order("created_at DESC")
+}
+
+// IsVisible indique si une ressource fait partie du périmètre d'un scope (partages inclus s'ils sont présents dans le scope)
+func (r *recordShareRepository) IsVisible(resourceType string, resourceID uint, queryScope *scope.QueryScope) (bool, error) {
+	var count int64
+	var err error
+	switch resourceType {
+	case models.ShareResourceTicket:
+		err = scope.ApplyTicketScope(database.DB.Model(&models.Ticket{}).Where("tickets.id = ?", resourceID), queryScope).Count(&count).Error
+	case models.ShareResourceProject:
+		err = scope.ApplyProjectScope(database.DB.Model(&models.Project{}).Where("projects.id = ?", resourceID), queryScope).Count(&count).Error
+	default:
+		return false, fmt.Errorf("type de ressource inconnu: %s", resourceType)
+	}
+	return count > 0, err
+}
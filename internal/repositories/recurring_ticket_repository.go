@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxDueRecurringTickets nombre maximal de planifications traitées par passage
+const maxDueRecurringTickets = 100
+
+// RecurringTicketRepository interface pour les opérations sur les planifications de tickets récurrents
+type RecurringTicketRepository interface {
+	Create(recurring *models.RecurringTicket) error
+	FindByID(id uint) (*models.RecurringTicket, error)
+	FindAll(filialeID *uint) ([]models.RecurringTicket, error)
+	FindDue(now time.Time) ([]models.RecurringTicket, error) // Planifications actives dont l'échéance est passée
+	Update(recurring *models.RecurringTicket) error
+	Delete(id uint) error
+	Claim(id uint, dueAt time.Time, nextRunAt *time.Time) (bool, error) // Avance l'échéance si elle vaut encore dueAt (une seule instance crée le ticket)
+	RecordRun(id uint, runAt time.Time, ticketID *uint, runError string) error
+	LinkProject(ticketID, projectID uint) error
+}
+
+// recurringTicketRepository implémente RecurringTicketRepository
+type recurringTicketRepository struct{}
+
+// NewRecurringTicketRepository crée une nouvelle instance de RecurringTicketRepository
+func NewRecurringTicketRepository() RecurringTicketRepository {
+	return &recurringTicketRepository{}
+}
+
+// Create crée une planification
+func (r *recurringTicketRepository) Create(recurring *models.RecurringTicket) error {
+	return database.DB.Omit(clause.Associations).Create(recurring).Error
+}
+
+// FindByID récupère une planification par son ID
+func (r *recurringTicketRepository) FindByID(id uint) (*models.RecurringTicket, error) {
+	var recurring models.RecurringTicket
+	if err := database.DB.Preload("Filiale").Preload("Project", selectProjectLight).First(&recurring, id).Error; err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// FindAll récupère les planifications, éventuellement limitées à une filiale
+func (r *recurringTicketRepository) FindAll(filialeID *uint) ([]models.RecurringTicket, error) {
+	var recurrings []models.RecurringTicket
+	query := database.DB.Preload("Filiale").Preload("Project", selectProjectLight)
+	if filialeID != nil {
+		query = query.Where("filiale_id = ?", *filialeID)
+	}
+	err := query.Order("name ASC").Find(&recurrings).Error
+	return recurrings, err
+}
+
+// FindDue récupère les planifications actives arrivées à échéance, de la plus ancienne échéance à la plus récente
+func (r *recurringTicketRepository) FindDue(now time.Time) ([]models.RecurringTicket, error) {
+	var recurrings []models.RecurringTicket
+	err := database.DB.Where("is_active = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").Limit(maxDueRecurringTickets).Find(&recurrings).Error
+	return recurrings, err
+}
+
+// Update met à jour une planification
+func (r *recurringTicketRepository) Update(recurring *models.RecurringTicket) error {
+	return database.DB.Omit(clause.Associations).Save(recurring).Error
+}
+
+// Delete supprime une planification (les tickets déjà créés sont conservés)
+func (r *recurringTicketRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.RecurringTicket{}, id).Error
+}
+
+// Claim avance l'échéance d'une planification à condition qu'elle n'ait pas déjà été traitée
+func (r *recurringTicketRepository) Claim(id uint, dueAt time.Time, nextRunAt *time.Time) (bool, error) {
+	result := database.DB.Model(&models.RecurringTicket{}).
+		Where("id = ? AND next_run_at = ?", id, dueAt).
+		Update("next_run_at", nextRunAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordRun enregistre le résultat d'une exécution (ticket créé ou erreur)
+func (r *recurringTicketRepository) RecordRun(id uint, runAt time.Time, ticketID *uint, runError string) error {
+	updates := map[string]interface{}{
+		"last_run_at": runAt,
+		"last_error":  runError,
+	}
+	if ticketID != nil {
+		updates["last_ticket_id"] = *ticketID
+		updates["run_count"] = gorm.Expr("run_count + 1")
+	}
+	return database.DB.Model(&models.RecurringTicket{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// selectProjectLight limite le préchargement du projet aux colonnes affichées
+func selectProjectLight(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "name", "status")
+}
+
+// LinkProject rattache un ticket à un projet (table ticket_projects)
+func (r *recurringTicketRepository) LinkProject(ticketID, projectID uint) error {
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).
+		Create(&models.TicketProject{TicketID: ticketID, ProjectID: projectID}).Error
+}
@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/odata"
+)
+
+// reportingEntitySets schéma de reporting exposé par le flux OData (vues en lecture seule, sans données personnelles sensibles)
+var reportingEntitySets = []odata.EntitySet{
+	{
+		Name:  "Tickets",
+		Type:  "Ticket",
+		Table: "tickets t",
+		Joins: []string{
+			"LEFT JOIN filiales f ON f.id = t.filiale_id",
+			"LEFT JOIN users a ON a.id = t.assigned_to_id",
+		},
+		Where:         "t.deleted_at IS NULL",
+		FilialeColumn: "t.filiale_id",
+		Key:           "Id",
+		Properties: []odata.Property{
+			{Name: "Id", Type: odata.TypeInt64, Column: "t.id"},
+			{Name: "Code", Type: odata.TypeString, Column: "t.code", Nullable: true},
+			{Name: "Title", Type: odata.TypeString, Column: "t.title"},
+			{Name: "Category", Type: odata.TypeString, Column: "t.category"},
+			{Name: "Source", Type: odata.TypeString, Column: "t.source"},
+			{Name: "Status", Type: odata.TypeString, Column: "t.status"},
+			{Name: "Priority", Type: odata.TypeString, Column: "t.priority", Nullable: true},
+			{Name: "FilialeId", Type: odata.TypeInt64, Column: "t.filiale_id", Nullable: true},
+			{Name: "FilialeName", Type: odata.TypeString, Column: "f.name", Nullable: true},
+			{Name: "AssignedToId", Type: odata.TypeInt64, Column: "t.assigned_to_id", Nullable: true},
+			{Name: "AssignedToName", Type: odata.TypeString, Column: "NULLIF(CONCAT_WS(' ', a.first_name, a.last_name), '')", Nullable: true},
+			{Name: "RequesterId", Type: odata.TypeInt64, Column: "t.requester_id", Nullable: true},
+			{Name: "RequesterDepartment", Type: odata.TypeString, Column: "t.requester_department", Nullable: true},
+			{Name: "SoftwareId", Type: odata.TypeInt64, Column: "t.software_id", Nullable: true},
+			{Name: "ParentId", Type: odata.TypeInt64, Column: "t.parent_id", Nullable: true},
+			{Name: "EstimatedTime", Type: odata.TypeInt32, Column: "t.estimated_time", Nullable: true},
+			{Name: "ActualTime", Type: odata.TypeInt32, Column: "t.actual_time", Nullable: true},
+			{Name: "CreatedAt", Type: odata.TypeDateTimeOffset, Column: "t.created_at"},
+			{Name: "UpdatedAt", Type: odata.TypeDateTimeOffset, Column: "t.updated_at"},
+			{Name: "ClosedAt", Type: odata.TypeDateTimeOffset, Column: "t.closed_at", Nullable: true},
+			{Name: "ValidatedAt", Type: odata.TypeDateTimeOffset, Column: "t.validated_at", Nullable: true},
+		},
+	},
+	{
+		Name:  "TimeEntries",
+		Type:  "TimeEntry",
+		Table: "time_entries te",
+		Joins: []string{
+			"JOIN users u ON u.id = te.user_id",
+			"LEFT JOIN project_tasks pt ON pt.id = te.project_task_id",
+		},
+		Where:         "te.deleted_at IS NULL",
+		FilialeColumn: "u.filiale_id",
+		Key:           "Id",
+		Properties: []odata.Property{
+			{Name: "Id", Type: odata.TypeInt64, Column: "te.id"},
+			{Name: "TicketId", Type: odata.TypeInt64, Column: "te.ticket_id", Nullable: true},
+			{Name: "TicketInternalId", Type: odata.TypeInt64, Column: "te.ticket_internal_id", Nullable: true},
+			{Name: "ProjectTaskId", Type: odata.TypeInt64, Column: "te.project_task_id", Nullable: true},
+			{Name: "ProjectId", Type: odata.TypeInt64, Column: "pt.project_id", Nullable: true},
+			{Name: "UserId", Type: odata.TypeInt64, Column: "te.user_id"},
+			{Name: "UserName", Type: odata.TypeString, Column: "CONCAT_WS(' ', u.first_name, u.last_name)"},
+			{Name: "FilialeId", Type: odata.TypeInt64, Column: "u.filiale_id", Nullable: true},
+			{Name: "Date", Type: odata.TypeDate, Column: "te.date"},
+			{Name: "TimeSpent", Type: odata.TypeInt32, Column: "te.time_spent"}, // Minutes
+			{Name: "Validated", Type: odata.TypeBoolean, Column: "te.validated"},
+			{Name: "ValidatedAt", Type: odata.TypeDateTimeOffset, Column: "te.validated_at", Nullable: true},
+			{Name: "CreatedAt", Type: odata.TypeDateTimeOffset, Column: "te.created_at"},
+		},
+	},
+	{
+		Name:  "TicketSla",
+		Type:  "TicketSlaEntry",
+		Table: "ticket_sla ts",
+		Joins: []string{
+			"JOIN tickets t ON t.id = ts.ticket_id AND t.deleted_at IS NULL",
+			"JOIN sla s ON s.id = ts.sla_id",
+		},
+		FilialeColumn: "t.filiale_id",
+		Key:           "Id",
+		Properties: []odata.Property{
+			{Name: "Id", Type: odata.TypeInt64, Column: "ts.id"},
+			{Name: "TicketId", Type: odata.TypeInt64, Column: "ts.ticket_id"},
+			{Name: "TicketCode", Type: odata.TypeString, Column: "t.code", Nullable: true},
+			{Name: "TicketCategory", Type: odata.TypeString, Column: "t.category"},
+			{Name: "TicketPriority", Type: odata.TypeString, Column: "t.priority", Nullable: true},
+			{Name: "FilialeId", Type: odata.TypeInt64, Column: "t.filiale_id", Nullable: true},
+			{Name: "SlaId", Type: odata.TypeInt64, Column: "ts.sla_id"},
+			{Name: "SlaName", Type: odata.TypeString, Column: "s.name"},
+			{Name: "TargetTime", Type: odata.TypeDateTimeOffset, Column: "ts.target_time"},
+			{Name: "ActualTime", Type: odata.TypeDateTimeOffset, Column: "ts.actual_time", Nullable: true},
+			{Name: "Status", Type: odata.TypeString, Column: "ts.status"},                               // on_time, at_risk, violated
+			{Name: "ViolationTime", Type: odata.TypeInt32, Column: "ts.violation_time", Nullable: true}, // Minutes
+			{Name: "CreatedAt", Type: odata.TypeDateTimeOffset, Column: "ts.created_at"},
+		},
+	},
+	{
+		Name:          "Projects",
+		Type:          "Project",
+		Table:         "projects p",
+		Joins:         []string{"LEFT JOIN filiales f ON f.id = p.filiale_id"},
+		FilialeColumn: "p.filiale_id",
+		Key:           "Id",
+		Properties: []odata.Property{
+			{Name: "Id", Type: odata.TypeInt64, Column: "p.id"},
+			{Name: "Name", Type: odata.TypeString, Column: "p.name"},
+			{Name: "Status", Type: odata.TypeString, Column: "p.status"},
+			{Name: "FilialeId", Type: odata.TypeInt64, Column: "p.filiale_id", Nullable: true},
+			{Name: "FilialeName", Type: odata.TypeString, Column: "f.name", Nullable: true},
+			{Name: "ProjectManagerId", Type: odata.TypeInt64, Column: "p.project_manager_id", Nullable: true},
+			{Name: "LeadId", Type: odata.TypeInt64, Column: "p.lead_id", Nullable: true},
+			{Name: "StartDate", Type: odata.TypeDate, Column: "p.start_date", Nullable: true},
+			{Name: "EndDate", Type: odata.TypeDate, Column: "p.end_date", Nullable: true},
+			{Name: "TotalBudgetTime", Type: odata.TypeInt32, Column: "p.total_budget_time", Nullable: true, Restricted: true}, // Minutes
+			{Name: "ConsumedTime", Type: odata.TypeInt32, Column: "p.consumed_time", Restricted: true},                        // Minutes
+			{Name: "CreatedAt", Type: odata.TypeDateTimeOffset, Column: "p.created_at"},
+		},
+	},
+}
+
+// ReportingRepository interface pour les clés d'API et la lecture du schéma de reporting
+type ReportingRepository interface {
+	EntitySets() []odata.EntitySet
+	CreateKey(key *models.ReportingKey) error
+	FindKeyByID(id uint) (*models.ReportingKey, error)
+	FindKeyByHash(keyHash string) (*models.ReportingKey, error)
+	FindKeys(filialeID *uint) ([]models.ReportingKey, error)
+	UpdateKey(key *models.ReportingKey) error
+	DeleteKey(id uint) error
+	Query(set *odata.EntitySet, query odata.Query, filialeID *uint, includeRestricted bool, limit int) ([]map[string]interface{}, *int64, error)
+}
+
+// reportingRepository implémente ReportingRepository
+type reportingRepository struct{}
+
+// NewReportingRepository crée une nouvelle instance de ReportingRepository
+func NewReportingRepository() ReportingRepository {
+	return &reportingRepository{}
+}
+
+// EntitySets retourne les ensembles d'entités exposés
+func (r *reportingRepository) EntitySets() []odata.EntitySet {
+	return reportingEntitySets
+}
+
+// CreateKey crée une clé d'API
+func (r *reportingRepository) CreateKey(key *models.ReportingKey) error {
+	return database.DB.Create(key).Error
+}
+
+// FindKeyByID trouve une clé par son ID
+func (r *reportingRepository) FindKeyByID(id uint) (*models.ReportingKey, error) {
+	var key models.ReportingKey
+	if err := database.DB.Preload("Filiale").First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindKeyByHash trouve la clé correspondant au hash fourni
+func (r *reportingRepository) FindKeyByHash(keyHash string) (*models.ReportingKey, error) {
+	var key models.ReportingKey
+	if err := database.DB.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindKeys récupère les clés, éventuellement limitées à une filiale
+func (r *reportingRepository) FindKeys(filialeID *uint) ([]models.ReportingKey, error) {
+	var keys []models.ReportingKey
+	query := database.DB.Preload("Filiale")
+	if filialeID != nil {
+		query = query.Where("filiale_id = ?", *filialeID)
+	}
+	err := query.Order("name").Find(&keys).Error
+	return keys, err
+}
+
+// UpdateKey met à jour une clé
+func (r *reportingRepository) UpdateKey(key *models.ReportingKey) error {
+	return database.DB.Omit("Filiale", "CreatedBy").Save(key).Error
+}
+
+// DeleteKey supprime une clé
+func (r *reportingRepository) DeleteKey(id uint) error {
+	return database.DB.Delete(&models.ReportingKey{}, id).Error
+}
+
+// Query lit une page d'un ensemble d'entités ; retourne au plus limit+1 lignes pour détecter la page suivante,
+// et le nombre total de lignes correspondant au filtre si $count est demandé
+func (r *reportingRepository) Query(set *odata.EntitySet, query odata.Query, filialeID *uint, includeRestricted bool, limit int) ([]map[string]interface{}, *int64, error) {
+	db := database.DB.Table(set.Table)
+	for _, join := range set.Joins {
+		db = db.Joins(join)
+	}
+	if set.Where != "" {
+		db = db.Where(set.Where)
+	}
+	if filialeID != nil {
+		db = db.Where(set.FilialeColumn+" = ?", *filialeID)
+	}
+	for _, condition := range query.Filter {
+		switch {
+		case condition.Value == nil && condition.Operator == "=":
+			db = db.Where(condition.Property.Column + " IS NULL")
+		case condition.Value == nil:
+			db = db.Where(condition.Property.Column + " IS NOT NULL")
+		default:
+			db = db.Where(fmt.Sprintf("%s %s ?", condition.Property.Column, condition.Operator), condition.Value)
+		}
+	}
+
+	var total *int64
+	if query.Count {
+		var count int64
+		if err := db.Count(&count).Error; err != nil {
+			return nil, nil, err
+		}
+		total = &count
+	}
+
+	properties := query.Select
+	if len(properties) == 0 {
+		for i := range set.Properties {
+			properties = append(properties, &set.Properties[i])
+		}
+	}
+	columns := make([]string, len(properties))
+	for i, prop := range properties {
+		column := prop.Column
+		if prop.Restricted && !includeRestricted {
+			column = "NULL"
+		}
+		columns[i] = fmt.Sprintf("%s AS `%s`", column, prop.Name)
+	}
+
+	orders := make([]string, 0, len(query.OrderBy)+1)
+	for _, order := range query.OrderBy {
+		direction := "ASC"
+		if order.Desc {
+			direction = "DESC"
+		}
+		orders = append(orders, order.Property.Column+" "+direction)
+	}
+	// Tri final sur la clé pour une pagination stable
+	orders = append(orders, set.Property(set.Key).Column+" ASC")
+
+	var rows []map[string]interface{}
+	err := db.Select(strings.Join(columns, ", ")).Order(strings.Join(orders, ", ")).
+		Offset(query.Skip).Limit(limit + 1).Find(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, total, nil
+}
@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/cache"
 	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
 )
 
 // RoleRepository interface pour les opérations sur les rôles
@@ -21,6 +24,16 @@ type RoleRepository interface {
 	Delete(id uint) error
 	GetPermissionsByRoleID(roleID uint) ([]string, error)              // Récupère les codes des permissions d'un rôle
 	UpdateRolePermissions(roleID uint, permissionCodes []string) error // Met à jour les permissions d'un rôle
+	UpdateUtilizationTarget(roleID uint, target *float64) error        // Définit (ou retire, nil) le taux d'utilisation visé du rôle
+}
+
+// RoleCachePrefix préfixe les clés de cache liées aux rôles (permissions par rôle)
+const RoleCachePrefix = "role:"
+
+// InvalidateRoleCache invalide toutes les permissions de rôles mises en cache (y compris celles du scope)
+func InvalidateRoleCache() {
+	cache.Shared.DeletePrefix(RoleCachePrefix)
+	scope.InvalidatePermissions()
 }
 
 // roleRepository implémente RoleRepository
@@ -126,16 +139,36 @@ func (r *roleRepository) FindByCreatedByID(createdByID uint) ([]models.Role, err
 
 // Update met à jour un rôle
 func (r *roleRepository) Update(role *models.Role) error {
-	return database.DB.Save(role).Error
+	if err := database.DB.Save(role).Error; err != nil {
+		return err
+	}
+	InvalidateRoleCache()
+	return nil
 }
 
 // Delete supprime un rôle (soft delete)
 func (r *roleRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.Role{}, id).Error
+	if err := database.DB.Delete(&models.Role{}, id).Error; err != nil {
+		return err
+	}
+	InvalidateRoleCache()
+	return nil
 }
 
-// GetPermissionsByRoleID récupère les codes des permissions associées à un rôle
+// GetPermissionsByRoleID récupère les codes des permissions associées à un rôle (mis en cache)
 func (r *roleRepository) GetPermissionsByRoleID(roleID uint) ([]string, error) {
+	permissions, err := cache.GetOrLoad(cache.Shared, fmt.Sprintf("%sperms:%d", RoleCachePrefix, roleID), 0, func() ([]string, error) {
+		return r.loadPermissionsByRoleID(roleID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Retourner une copie pour protéger la valeur en cache
+	return append([]string(nil), permissions...), nil
+}
+
+// loadPermissionsByRoleID charge les codes des permissions d'un rôle depuis la base de données
+func (r *roleRepository) loadPermissionsByRoleID(roleID uint) ([]string, error) {
 	var rolePermissions []models.RolePermission
 	err := database.DB.
 		Where("role_id = ?", roleID).
@@ -163,6 +196,9 @@ func (r *roleRepository) UpdateRolePermissions(roleID uint, permissionCodes []st
 		return err
 	}
 
+	// Les permissions changent : invalider le cache même en cas d'échec partiel
+	defer InvalidateRoleCache()
+
 	// Supprimer toutes les permissions existantes pour ce rôle
 	if err := database.DB.Where("role_id = ?", roleID).Delete(&models.RolePermission{}).Error; err != nil {
 		return err
@@ -200,3 +236,8 @@ func (r *roleRepository) UpdateRolePermissions(roleID uint, permissionCodes []st
 
 	return nil
 }
+
+// UpdateUtilizationTarget définit (ou retire, nil) le taux d'utilisation facturable visé du rôle
+func (r *roleRepository) UpdateUtilizationTarget(roleID uint, target *float64) error {
+	return database.DB.Model(&models.Role{}).Where("id = ?", roleID).Update("utilization_target", target).Error
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// ScheduledJobRunRepository interface pour les opérations sur l'historique des tâches planifiées
+type ScheduledJobRunRepository interface {
+	Create(run *models.ScheduledJobRun) error
+	Update(run *models.ScheduledJobRun) error
+	FindLastByJob(jobName string) (*models.ScheduledJobRun, error)
+	FindByJob(jobName string, limit int) ([]models.ScheduledJobRun, error)
+	PurgeBefore(before time.Time) (int64, error)
+}
+
+// scheduledJobRunRepository implémente ScheduledJobRunRepository
+type scheduledJobRunRepository struct{}
+
+// NewScheduledJobRunRepository crée une nouvelle instance de ScheduledJobRunRepository
+func NewScheduledJobRunRepository() ScheduledJobRunRepository {
+	return &scheduledJobRunRepository{}
+}
+
+// Create enregistre le début d'une exécution
+func (r *scheduledJobRunRepository) Create(run *models.ScheduledJobRun) error {
+	return database.DB.Create(run).Error
+}
+
+// Update met à jour une exécution (fin, statut, erreur)
+func (r *scheduledJobRunRepository) Update(run *models.ScheduledJobRun) error {
+	return database.DB.Save(run).Error
+}
+
+// FindLastByJob récupère la dernière exécution d'une tâche
+func (r *scheduledJobRunRepository) FindLastByJob(jobName string) (*models.ScheduledJobRun, error) {
+	var run models.ScheduledJobRun
+	err := database.DB.Where("job_name = ?", jobName).Order("started_at DESC, id DESC").First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FindByJob récupère les dernières exécutions d'une tâche (plus récentes d'abord)
+func (r *scheduledJobRunRepository) FindByJob(jobName string, limit int) ([]models.ScheduledJobRun, error) {
+	var runs []models.ScheduledJobRun
+	err := database.DB.Where("job_name = ?", jobName).Order("started_at DESC, id DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// PurgeBefore supprime les exécutions terminées antérieures à la date donnée
+func (r *scheduledJobRunRepository) PurgeBefore(before time.Time) (int64, error) {
+	result := database.DB.Where("started_at < ? AND status <> ?", before, "running").Delete(&models.ScheduledJobRun{})
+	return result.RowsAffected, result.Error
+}
@@ -53,7 +53,7 @@ func (r *serviceRequestRepository) Create(serviceRequest *models.ServiceRequest)
 // FindByID trouve une demande de service par son ID
 func (r *serviceRequestRepository) FindByID(id uint) (*models.ServiceRequest, error) {
 	var serviceRequest models.ServiceRequest
-	err := database.DB.Preload("Ticket").Preload("Ticket.CreatedBy").Preload("Ticket.AssignedTo").Preload("Type").Preload("Validator").First(&serviceRequest, id).Error
+	err := database.DB.Preload("Ticket").Preload("Ticket.CreatedBy").Preload("Ticket.AssignedTo").Preload("Type").Preload("ValidatedBy").First(&serviceRequest, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +63,7 @@ func (r *serviceRequestRepository) FindByID(id uint) (*models.ServiceRequest, er
 // FindByTicketID trouve une demande de service par l'ID du ticket
 func (r *serviceRequestRepository) FindByTicketID(ticketID uint) (*models.ServiceRequest, error) {
 	var serviceRequest models.ServiceRequest
-	err := database.DB.Preload("Ticket").Preload("Type").Preload("Validator").Where("ticket_id = ?", ticketID).First(&serviceRequest).Error
+	err := database.DB.Preload("Ticket").Preload("Type").Preload("ValidatedBy").Where("ticket_id = ?", ticketID).First(&serviceRequest).Error
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/cache"
 	"github.com/mcicare/itsm-backend/internal/models"
 )
 
@@ -19,6 +20,9 @@ type SettingsRepository interface {
 	SetValue(key, value string) error
 }
 
+// settingsCachePrefix préfixe les clés de cache des paramètres système
+const settingsCachePrefix = "settings:"
+
 // settingsRepository implémente SettingsRepository
 type settingsRepository struct{}
 
@@ -29,7 +33,11 @@ func NewSettingsRepository() SettingsRepository {
 
 // Create crée un nouveau paramètre
 func (r *settingsRepository) Create(setting *models.Setting) error {
-	return database.DB.Create(setting).Error
+	if err := database.DB.Create(setting).Error; err != nil {
+		return err
+	}
+	cache.Shared.DeletePrefix(settingsCachePrefix)
+	return nil
 }
 
 // FindByID trouve un paramètre par son ID
@@ -45,53 +53,66 @@ func (r *settingsRepository) FindByID(id uint) (*models.Setting, error) {
 // FindByKey trouve un paramètre par sa clé
 func (r *settingsRepository) FindByKey(key string) (*models.Setting, error) {
 	var setting models.Setting
-	err := database.DB.Preload("UpdatedBy").Where("key = ?", key).First(&setting).Error
+	err := database.DB.Preload("UpdatedBy").Where("`key` = ?", key).First(&setting).Error
 	if err != nil {
 		return nil, err
 	}
 	return &setting, nil
 }
 
-// FindAll récupère tous les paramètres
+// FindAll récupère tous les paramètres (mis en cache)
 func (r *settingsRepository) FindAll() ([]models.Setting, error) {
-	var settings []models.Setting
-	// Ne pas utiliser Preload pour éviter les erreurs si UpdatedBy n'existe pas
-	// Les paramètres peuvent être récupérés sans la relation UpdatedBy
-	err := database.DB.Order("category ASC, key ASC").Find(&settings).Error
-	return settings, err
+	settings, err := cache.GetOrLoad(cache.Shared, settingsCachePrefix+"all", 0, func() ([]models.Setting, error) {
+		var settings []models.Setting
+		// Ne pas utiliser Preload pour éviter les erreurs si UpdatedBy n'existe pas
+		// Les paramètres peuvent être récupérés sans la relation UpdatedBy
+		err := database.DB.Order("category ASC, `key` ASC").Find(&settings).Error
+		return settings, err
+	})
+	return append([]models.Setting(nil), settings...), err
 }
 
 // FindByCategory récupère les paramètres d'une catégorie
 func (r *settingsRepository) FindByCategory(category string) ([]models.Setting, error) {
 	var settings []models.Setting
-	err := database.DB.Preload("UpdatedBy").Where("category = ?", category).Order("key ASC").Find(&settings).Error
+	err := database.DB.Preload("UpdatedBy").Where("category = ?", category).Order("`key` ASC").Find(&settings).Error
 	return settings, err
 }
 
 // FindPublic récupère les paramètres publics (accessibles sans authentification)
 func (r *settingsRepository) FindPublic() ([]models.Setting, error) {
 	var settings []models.Setting
-	err := database.DB.Preload("UpdatedBy").Where("is_public = ?", true).Order("category ASC, key ASC").Find(&settings).Error
+	err := database.DB.Preload("UpdatedBy").Where("is_public = ?", true).Order("category ASC, `key` ASC").Find(&settings).Error
 	return settings, err
 }
 
 // Update met à jour un paramètre
 func (r *settingsRepository) Update(setting *models.Setting) error {
-	return database.DB.Save(setting).Error
+	if err := database.DB.Save(setting).Error; err != nil {
+		return err
+	}
+	cache.Shared.DeletePrefix(settingsCachePrefix)
+	return nil
 }
 
 // Delete supprime un paramètre
 func (r *settingsRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.Setting{}, id).Error
+	if err := database.DB.Delete(&models.Setting{}, id).Error; err != nil {
+		return err
+	}
+	cache.Shared.DeletePrefix(settingsCachePrefix)
+	return nil
 }
 
-// GetValue récupère la valeur d'un paramètre par sa clé (méthode utilitaire)
+// GetValue récupère la valeur d'un paramètre par sa clé (méthode utilitaire, mise en cache)
 func (r *settingsRepository) GetValue(key string) (string, error) {
-	setting, err := r.FindByKey(key)
-	if err != nil {
-		return "", err
-	}
-	return setting.Value, nil
+	return cache.GetOrLoad(cache.Shared, settingsCachePrefix+"value:"+key, 0, func() (string, error) {
+		setting, err := r.FindByKey(key)
+		if err != nil {
+			return "", err
+		}
+		return setting.Value, nil
+	})
 }
 
 // SetValue met à jour la valeur d'un paramètre par sa clé (méthode utilitaire)
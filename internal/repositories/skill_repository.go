@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// SkillRepository interface pour les opérations sur le catalogue de compétences
+type SkillRepository interface {
+	Create(skill *models.Skill) error
+	FindByID(id uint) (*models.Skill, error)
+	FindByName(name string) (*models.Skill, error)
+	FindByIDs(ids []uint) ([]models.Skill, error)
+	FindAll(kind, category string, activeOnly bool) ([]models.Skill, error)
+	Update(skill *models.Skill) error
+	Delete(id uint) error
+	CountHolders(id uint) (int64, error)
+}
+
+// skillRepository implémente SkillRepository
+type skillRepository struct{}
+
+// NewSkillRepository crée une nouvelle instance de SkillRepository
+func NewSkillRepository() SkillRepository {
+	return &skillRepository{}
+}
+
+// Create crée une nouvelle compétence
+func (r *skillRepository) Create(skill *models.Skill) error {
+	return database.DB.Create(skill).Error
+}
+
+// FindByID trouve une compétence par son ID
+func (r *skillRepository) FindByID(id uint) (*models.Skill, error) {
+	var skill models.Skill
+	if err := database.DB.First(&skill, id).Error; err != nil {
+		return nil, err
+	}
+	return &skill, nil
+}
+
+// FindByName trouve une compétence par son nom
+func (r *skillRepository) FindByName(name string) (*models.Skill, error) {
+	var skill models.Skill
+	if err := database.DB.Where("name = ?", name).First(&skill).Error; err != nil {
+		return nil, err
+	}
+	return &skill, nil
+}
+
+// FindByIDs récupère plusieurs compétences
+func (r *skillRepository) FindByIDs(ids []uint) ([]models.Skill, error) {
+	var skills []models.Skill
+	if len(ids) == 0 {
+		return skills, nil
+	}
+	err := database.DB.Where("id IN ?", ids).Find(&skills).Error
+	return skills, err
+}
+
+// FindAll récupère le catalogue, filtré par type et catégorie (optionnels)
+func (r *skillRepository) FindAll(kind, category string, activeOnly bool) ([]models.Skill, error) {
+	var skills []models.Skill
+	query := database.DB.Model(&models.Skill{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("category ASC, name ASC").Find(&skills).Error
+	return skills, err
+}
+
+// Update met à jour une compétence
+func (r *skillRepository) Update(skill *models.Skill) error {
+	return database.DB.Save(skill).Error
+}
+
+// Delete supprime une compétence
+func (r *skillRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.Skill{}, id).Error
+}
+
+// CountHolders compte les utilisateurs détenant une compétence
+func (r *skillRepository) CountHolders(id uint) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.UserSkill{}).Where("skill_id = ?", id).Count(&count).Error
+	return count, err
+}
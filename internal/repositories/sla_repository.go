@@ -4,6 +4,7 @@ import (
 	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm/clause"
 )
 
 // SLARepository interface pour les opérations sur les SLA
@@ -13,7 +14,7 @@ type SLARepository interface {
 	FindAll() ([]models.SLA, error)
 	FindActive() ([]models.SLA, error)
 	FindByCategory(category string) ([]models.SLA, error)
-	FindByCategoryAndPriority(category, priority string) (*models.SLA, error)
+	FindByCategoryAndPriority(category, priority, supportLevel string) (*models.SLA, error)
 	Update(sla *models.SLA) error
 	Delete(id uint) error
 }
@@ -27,6 +28,7 @@ type TicketSLARepository interface {
 	FindAll(scope interface{}) ([]models.TicketSLA, error) // scope peut être *scope.QueryScope ou nil
 	FindByStatus(scope interface{}, status string) ([]models.TicketSLA, error)
 	FindViolated(scope interface{}) ([]models.TicketSLA, error) // scope peut être *scope.QueryScope ou nil
+	FindPending() ([]models.TicketSLA, error)                   // Associations non violées des tickets non clôturés (surveillance des échéances)
 	Update(ticketSLA *models.TicketSLA) error
 	Delete(id uint) error
 }
@@ -84,7 +86,8 @@ func (r *slaRepository) FindByCategory(category string) ([]models.SLA, error) {
 }
 
 // FindByCategoryAndPriority trouve un SLA par catégorie et priorité
-func (r *slaRepository) FindByCategoryAndPriority(category, priority string) (*models.SLA, error) {
+// supportLevel vide : SLA des tickets hors contrat de support (support_level IS NULL)
+func (r *slaRepository) FindByCategoryAndPriority(category, priority, supportLevel string) (*models.SLA, error) {
 	var sla models.SLA
 	query := database.DB.Where("ticket_category = ? AND is_active = ?", category, true)
 	if priority != "" {
@@ -92,6 +95,11 @@ func (r *slaRepository) FindByCategoryAndPriority(category, priority string) (*m
 	} else {
 		query = query.Where("priority IS NULL")
 	}
+	if supportLevel != "" {
+		query = query.Where("support_level = ?", supportLevel)
+	} else {
+		query = query.Where("support_level IS NULL")
+	}
 	err := query.Preload("CreatedBy").First(&sla).Error
 	if err != nil {
 		return nil, err
@@ -217,9 +225,21 @@ func (r *ticketSLARepository) FindViolated(scopeParam interface{}) ([]models.Tic
 	return ticketSLAs, err
 }
 
+// FindPending récupère les associations ticket-SLA encore susceptibles de changer de statut : non violées, non résolues, ticket non clôturé
+func (r *ticketSLARepository) FindPending() ([]models.TicketSLA, error) {
+	var ticketSLAs []models.TicketSLA
+	err := database.DB.Model(&models.TicketSLA{}).
+		Joins("JOIN tickets ON tickets.id = ticket_sla.ticket_id AND tickets.deleted_at IS NULL").
+		Where("ticket_sla.status <> ? AND ticket_sla.actual_time IS NULL AND tickets.status <> ?", "violated", "cloture").
+		Preload("Ticket").
+		Order("ticket_sla.target_time ASC").
+		Find(&ticketSLAs).Error
+	return ticketSLAs, err
+}
+
 // Update met à jour une association ticket-SLA
 func (r *ticketSLARepository) Update(ticketSLA *models.TicketSLA) error {
-	return database.DB.Save(ticketSLA).Error
+	return database.DB.Omit(clause.Associations).Save(ticketSLA).Error
 }
 
 // Delete supprime une association ticket-SLA
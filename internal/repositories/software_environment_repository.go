@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// SoftwareEnvironmentRepository interface pour les opérations sur les environnements des logiciels par filiale
+type SoftwareEnvironmentRepository interface {
+	Create(environment *models.SoftwareEnvironment) error
+	FindByID(id uint) (*models.SoftwareEnvironment, error)
+	FindByFiliale(filialeID uint, softwareID *uint, activeOnly bool) ([]models.SoftwareEnvironment, error)
+	FindByFilialeSoftwareAndName(filialeID, softwareID uint, name string) (*models.SoftwareEnvironment, error)
+	Update(environment *models.SoftwareEnvironment) error
+	Delete(id uint) error
+}
+
+// softwareEnvironmentRepository implémente SoftwareEnvironmentRepository
+type softwareEnvironmentRepository struct{}
+
+// NewSoftwareEnvironmentRepository crée une nouvelle instance de SoftwareEnvironmentRepository
+func NewSoftwareEnvironmentRepository() SoftwareEnvironmentRepository {
+	return &softwareEnvironmentRepository{}
+}
+
+// Create crée un environnement
+func (r *softwareEnvironmentRepository) Create(environment *models.SoftwareEnvironment) error {
+	return database.DB.Create(environment).Error
+}
+
+// FindByID trouve un environnement par son ID
+func (r *softwareEnvironmentRepository) FindByID(id uint) (*models.SoftwareEnvironment, error) {
+	var environment models.SoftwareEnvironment
+	if err := database.DB.Preload("Software").First(&environment, id).Error; err != nil {
+		return nil, err
+	}
+	return &environment, nil
+}
+
+// FindByFiliale récupère les environnements d'une filiale, éventuellement limités à un logiciel
+func (r *softwareEnvironmentRepository) FindByFiliale(filialeID uint, softwareID *uint, activeOnly bool) ([]models.SoftwareEnvironment, error) {
+	var environments []models.SoftwareEnvironment
+	query := database.DB.Preload("Software").Where("filiale_id = ?", filialeID)
+	if softwareID != nil {
+		query = query.Where("software_id = ?", *softwareID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("software_id ASC, FIELD(name, 'production', 'preproduction', 'test'), id ASC").
+		Find(&environments).Error
+	return environments, err
+}
+
+// FindByFilialeSoftwareAndName trouve un environnement par filiale, logiciel et nom
+func (r *softwareEnvironmentRepository) FindByFilialeSoftwareAndName(filialeID, softwareID uint, name string) (*models.SoftwareEnvironment, error) {
+	var environment models.SoftwareEnvironment
+	err := database.DB.Where("filiale_id = ? AND software_id = ? AND name = ?", filialeID, softwareID, name).
+		First(&environment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &environment, nil
+}
+
+// Update met à jour un environnement
+func (r *softwareEnvironmentRepository) Update(environment *models.SoftwareEnvironment) error {
+	return database.DB.Omit("Filiale", "Software").Save(environment).Error
+}
+
+// Delete supprime un environnement et le détache des tickets qui le référencent
+func (r *softwareEnvironmentRepository) Delete(id uint) error {
+	if err := database.DB.Model(&models.Ticket{}).Where("environment_id = ?", id).
+		Update("environment_id", nil).Error; err != nil {
+		return err
+	}
+	return database.DB.Delete(&models.SoftwareEnvironment{}, id).Error
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SoftwareInstallationCount nombre d'actifs d'une filiale sur lesquels un logiciel (nom, version) est installé
+type SoftwareInstallationCount struct {
+	FilialeID     *uint  // Nil : actifs sans filiale
+	SoftwareName  string // Nom normalisé (minuscules, sans espaces en bordure)
+	Version       string
+	Installations int64
+}
+
+// SoftwareLicenseRepository interface pour les opérations sur les licences logicielles
+type SoftwareLicenseRepository interface {
+	Create(license *models.SoftwareLicense) error
+	FindByID(id uint) (*models.SoftwareLicense, error)
+	FindAll(filialeID, softwareID *uint, activeOnly bool, expiryBefore *time.Time) ([]models.SoftwareLicense, error)
+	FindExpiringUnnotified(expiryBefore time.Time) ([]models.SoftwareLicense, error) // Licences actives expirant avant la date, sans alerte envoyée
+	MarkExpiryNotified(id uint, at time.Time) error
+	CountInstallations(filialeID *uint) ([]SoftwareInstallationCount, error) // Installations inventoriées des actifs en service
+	Update(license *models.SoftwareLicense) error
+	Delete(id uint) error
+}
+
+// softwareLicenseRepository implémente SoftwareLicenseRepository
+type softwareLicenseRepository struct{}
+
+// NewSoftwareLicenseRepository crée une nouvelle instance de SoftwareLicenseRepository
+func NewSoftwareLicenseRepository() SoftwareLicenseRepository {
+	return &softwareLicenseRepository{}
+}
+
+// withRelations précharge le logiciel et la filiale d'une licence
+func (r *softwareLicenseRepository) withRelations() *gorm.DB {
+	return database.DB.Preload("Software").Preload("Filiale")
+}
+
+// Create crée une licence
+func (r *softwareLicenseRepository) Create(license *models.SoftwareLicense) error {
+	return database.DB.Omit(clause.Associations).Create(license).Error
+}
+
+// FindByID trouve une licence par son ID
+func (r *softwareLicenseRepository) FindByID(id uint) (*models.SoftwareLicense, error) {
+	var license models.SoftwareLicense
+	if err := r.withRelations().First(&license, id).Error; err != nil {
+		return nil, err
+	}
+	return &license, nil
+}
+
+// FindAll récupère les licences, éventuellement filtrées par filiale, logiciel, activité
+// et date d'expiration (licences expirant avant expiryBefore)
+func (r *softwareLicenseRepository) FindAll(filialeID, softwareID *uint, activeOnly bool, expiryBefore *time.Time) ([]models.SoftwareLicense, error) {
+	var licenses []models.SoftwareLicense
+	query := r.withRelations()
+	if filialeID != nil {
+		query = query.Where("filiale_id = ?", *filialeID)
+	}
+	if softwareID != nil {
+		query = query.Where("software_id = ?", *softwareID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if expiryBefore != nil {
+		query = query.Where("expiry_date IS NOT NULL AND expiry_date <= ?", expiryBefore.Format("2006-01-02"))
+	}
+	err := query.Order("filiale_id ASC, software_id ASC, expiry_date IS NULL, expiry_date ASC, id ASC").Find(&licenses).Error
+	return licenses, err
+}
+
+// FindExpiringUnnotified récupère les licences actives expirant avant expiryBefore dont l'alerte n'a pas été envoyée
+func (r *softwareLicenseRepository) FindExpiringUnnotified(expiryBefore time.Time) ([]models.SoftwareLicense, error) {
+	var licenses []models.SoftwareLicense
+	err := r.withRelations().
+		Where("is_active = ? AND expiry_date IS NOT NULL AND expiry_date <= ? AND expiry_notified_at IS NULL", true, expiryBefore.Format("2006-01-02")).
+		Order("expiry_date ASC, id ASC").Find(&licenses).Error
+	return licenses, err
+}
+
+// MarkExpiryNotified enregistre l'envoi de l'alerte d'expiration d'une licence
+func (r *softwareLicenseRepository) MarkExpiryNotified(id uint, at time.Time) error {
+	return database.DB.Model(&models.SoftwareLicense{}).Where("id = ?", id).Update("expiry_notified_at", at).Error
+}
+
+// CountInstallations compte, par filiale, nom et version de logiciel, les actifs en service (ni retirés ni mis au rebut)
+// sur lesquels le logiciel est inventorié
+func (r *softwareLicenseRepository) CountInstallations(filialeID *uint) ([]SoftwareInstallationCount, error) {
+	var counts []SoftwareInstallationCount
+	query := database.DB.Table("asset_software").
+		Select("assets.filiale_id AS filiale_id, LOWER(TRIM(asset_software.software_name)) AS software_name, TRIM(COALESCE(asset_software.version, '')) AS version, COUNT(DISTINCT asset_software.asset_id) AS installations").
+		Joins("JOIN assets ON assets.id = asset_software.asset_id AND assets.deleted_at IS NULL").
+		Where("asset_software.deleted_at IS NULL").
+		Where("assets.status NOT IN ?", []string{models.AssetStatusRetired, models.AssetStatusDisposed})
+	if filialeID != nil {
+		query = query.Where("assets.filiale_id = ?", *filialeID)
+	}
+	err := query.Group("assets.filiale_id, LOWER(TRIM(asset_software.software_name)), TRIM(COALESCE(asset_software.version, ''))").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// Update met à jour une licence
+func (r *softwareLicenseRepository) Update(license *models.SoftwareLicense) error {
+	return database.DB.Omit(clause.Associations).Save(license).Error
+}
+
+// Delete supprime une licence (soft delete)
+func (r *softwareLicenseRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.SoftwareLicense{}, id).Error
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// SoftwareReleaseRepository interface pour les opérations sur les versions publiées des logiciels
+type SoftwareReleaseRepository interface {
+	Create(release *models.SoftwareRelease) error
+	FindByID(id uint) (*models.SoftwareRelease, error)
+	FindBySoftwareID(softwareID uint) ([]models.SoftwareRelease, error)
+	FindBySoftwareAndVersion(softwareID uint, version string) (*models.SoftwareRelease, error)
+	Update(release *models.SoftwareRelease) error
+	Delete(id uint) error
+	CountFixedTickets(releaseIDs []uint) (map[uint]int, error)
+	FindFixedTickets(releaseID uint) ([]models.Ticket, error)
+	LinkTickets(releaseID, softwareID uint, ticketIDs []uint) ([]uint, error)
+	UnlinkTicket(releaseID, ticketID uint) (bool, error)
+}
+
+// softwareReleaseRepository implémente SoftwareReleaseRepository
+type softwareReleaseRepository struct{}
+
+// NewSoftwareReleaseRepository crée une nouvelle instance de SoftwareReleaseRepository
+func NewSoftwareReleaseRepository() SoftwareReleaseRepository {
+	return &softwareReleaseRepository{}
+}
+
+// Create crée une version
+func (r *softwareReleaseRepository) Create(release *models.SoftwareRelease) error {
+	return database.DB.Create(release).Error
+}
+
+// FindByID trouve une version par son ID
+func (r *softwareReleaseRepository) FindByID(id uint) (*models.SoftwareRelease, error) {
+	var release models.SoftwareRelease
+	if err := database.DB.First(&release, id).Error; err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// FindBySoftwareID récupère les versions d'un logiciel (plus récentes d'abord)
+func (r *softwareReleaseRepository) FindBySoftwareID(softwareID uint) ([]models.SoftwareRelease, error) {
+	var releases []models.SoftwareRelease
+	err := database.DB.Where("software_id = ?", softwareID).
+		Order("release_date IS NULL DESC, release_date DESC, id DESC").
+		Find(&releases).Error
+	return releases, err
+}
+
+// FindBySoftwareAndVersion trouve une version d'un logiciel par son numéro
+func (r *softwareReleaseRepository) FindBySoftwareAndVersion(softwareID uint, version string) (*models.SoftwareRelease, error) {
+	var release models.SoftwareRelease
+	if err := database.DB.Where("software_id = ? AND version = ?", softwareID, version).First(&release).Error; err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// Update met à jour une version
+func (r *softwareReleaseRepository) Update(release *models.SoftwareRelease) error {
+	return database.DB.Save(release).Error
+}
+
+// Delete supprime une version (soft delete) et détache les tickets qu'elle corrigeait
+func (r *softwareReleaseRepository) Delete(id uint) error {
+	if err := database.DB.Model(&models.Ticket{}).Where("fixed_in_release_id = ?", id).
+		Update("fixed_in_release_id", nil).Error; err != nil {
+		return err
+	}
+	return database.DB.Delete(&models.SoftwareRelease{}, id).Error
+}
+
+// CountFixedTickets compte les tickets corrigés par chaque version
+func (r *softwareReleaseRepository) CountFixedTickets(releaseIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(releaseIDs))
+	if len(releaseIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		FixedInReleaseID uint
+		Count            int
+	}
+	err := database.DB.Model(&models.Ticket{}).
+		Select("fixed_in_release_id, COUNT(*) AS count").
+		Where("fixed_in_release_id IN ?", releaseIDs).
+		Group("fixed_in_release_id").
+		Scan(&rows).Error
+	for _, row := range rows {
+		counts[row.FixedInReleaseID] = row.Count
+	}
+	return counts, err
+}
+
+// FindFixedTickets récupère les tickets corrigés par une version
+func (r *softwareReleaseRepository) FindFixedTickets(releaseID uint) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	err := database.DB.Preload("Filiale").
+		Where("fixed_in_release_id = ?", releaseID).
+		Order("created_at DESC").
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// LinkTickets rattache à la version les tickets du même logiciel et retourne les IDs rattachés
+func (r *softwareReleaseRepository) LinkTickets(releaseID, softwareID uint, ticketIDs []uint) ([]uint, error) {
+	var linked []uint
+	if err := database.DB.Model(&models.Ticket{}).
+		Where("id IN ? AND software_id = ?", ticketIDs, softwareID).
+		Pluck("id", &linked).Error; err != nil {
+		return nil, err
+	}
+	if len(linked) == 0 {
+		return linked, nil
+	}
+	err := database.DB.Model(&models.Ticket{}).Where("id IN ?", linked).
+		Update("fixed_in_release_id", releaseID).Error
+	return linked, err
+}
+
+// UnlinkTicket détache un ticket de la version
+func (r *softwareReleaseRepository) UnlinkTicket(releaseID, ticketID uint) (bool, error) {
+	result := database.DB.Model(&models.Ticket{}).
+		Where("id = ? AND fixed_in_release_id = ?", ticketID, releaseID).
+		Update("fixed_in_release_id", nil)
+	return result.RowsAffected > 0, result.Error
+}
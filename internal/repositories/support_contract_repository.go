@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SupportContractRepository interface pour les opérations sur les contrats de support logiciel
+type SupportContractRepository interface {
+	Create(contract *models.SupportContract) error
+	FindByID(id uint) (*models.SupportContract, error)
+	FindAll(filialeID, softwareID *uint, activeOnly bool, renewalBefore *time.Time) ([]models.SupportContract, error)
+	FindEffective(filialeID uint, softwareID *uint, at time.Time) (*models.SupportContract, error)
+	ReferenceExists(reference string, excludeID uint) (bool, error)
+	Update(contract *models.SupportContract) error
+	Delete(id uint) error
+}
+
+// supportContractRepository implémente SupportContractRepository
+type supportContractRepository struct{}
+
+// NewSupportContractRepository crée une nouvelle instance de SupportContractRepository
+func NewSupportContractRepository() SupportContractRepository {
+	return &supportContractRepository{}
+}
+
+// withRelations précharge les filiales et le logiciel d'un contrat
+func (r *supportContractRepository) withRelations() *gorm.DB {
+	return database.DB.Preload("ProviderFiliale").Preload("Filiale").Preload("Software")
+}
+
+// Create crée un contrat
+func (r *supportContractRepository) Create(contract *models.SupportContract) error {
+	return database.DB.Create(contract).Error
+}
+
+// FindByID trouve un contrat par son ID
+func (r *supportContractRepository) FindByID(id uint) (*models.SupportContract, error) {
+	var contract models.SupportContract
+	if err := r.withRelations().First(&contract, id).Error; err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+// FindAll récupère les contrats, éventuellement filtrés par filiale cliente, logiciel, activité
+// et date de renouvellement (contrats à renouveler avant renewalBefore)
+func (r *supportContractRepository) FindAll(filialeID, softwareID *uint, activeOnly bool, renewalBefore *time.Time) ([]models.SupportContract, error) {
+	var contracts []models.SupportContract
+	query := r.withRelations()
+	if filialeID != nil {
+		query = query.Where("filiale_id = ?", *filialeID)
+	}
+	if softwareID != nil {
+		query = query.Where("software_id = ? OR software_id IS NULL", *softwareID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if renewalBefore != nil {
+		query = query.Where("renewal_date IS NOT NULL AND renewal_date <= ?", renewalBefore.Format("2006-01-02"))
+	}
+	err := query.Order("filiale_id ASC, start_date DESC, id DESC").Find(&contracts).Error
+	return contracts, err
+}
+
+// FindEffective trouve le contrat en vigueur à la date at pour une filiale et un logiciel
+// Un contrat propre au logiciel prime sur un contrat couvrant tous les logiciels
+func (r *supportContractRepository) FindEffective(filialeID uint, softwareID *uint, at time.Time) (*models.SupportContract, error) {
+	var contract models.SupportContract
+	day := at.Format("2006-01-02")
+	query := database.DB.Where("filiale_id = ? AND is_active = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)",
+		filialeID, true, day, day)
+	if softwareID != nil && *softwareID != 0 {
+		query = query.Where("software_id = ? OR software_id IS NULL", *softwareID).Order("software_id IS NULL ASC")
+	} else {
+		query = query.Where("software_id IS NULL")
+	}
+	if err := query.Order("start_date DESC, id DESC").First(&contract).Error; err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+// ReferenceExists vérifie si une référence est déjà utilisée par un autre contrat
+func (r *supportContractRepository) ReferenceExists(reference string, excludeID uint) (bool, error) {
+	var count int64
+	err := database.DB.Unscoped().Model(&models.SupportContract{}).
+		Where("reference = ? AND id <> ?", reference, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update met à jour un contrat
+func (r *supportContractRepository) Update(contract *models.SupportContract) error {
+	return database.DB.Omit("ProviderFiliale", "Filiale", "Software").Save(contract).Error
+}
+
+// Delete supprime un contrat (soft delete)
+func (r *supportContractRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.SupportContract{}, id).Error
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+)
+
+// SyncChange modification d'un élément depuis la dernière synchronisation
+type SyncChange struct {
+	ID        uint
+	CreatedAt time.Time
+	ChangedAt time.Time // Date de la dernière modification (ou de la suppression)
+	Deleted   bool
+}
+
+// SyncRepository interface pour la synchronisation incrémentale des clients mobiles
+type SyncRepository interface {
+	// FindChanges récupère les éléments d'une entité créés, modifiés ou supprimés (soft delete) depuis since
+	// dans le périmètre du scope, par date de modification croissante ; since nil = tous les éléments existants
+	FindChanges(entity string, queryScope *scope.QueryScope, since *time.Time, limit int) ([]SyncChange, error)
+	// FindTombstones récupère les suppressions définitives d'une entité depuis since
+	FindTombstones(entity string, userID uint, since time.Time, limit int) ([]models.SyncTombstone, error)
+	PurgeTombstones(olderThan time.Time) (int64, error)
+}
+
+// syncRepository implémente SyncRepository
+type syncRepository struct{}
+
+// NewSyncRepository crée une nouvelle instance de SyncRepository
+func NewSyncRepository() SyncRepository {
+	return &syncRepository{}
+}
+
+// RecordTombstones enregistre la suppression définitive d'éléments (à appeler dans la transaction de suppression)
+func RecordTombstones(db *gorm.DB, entity string, ids []uint, userID *uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	now := time.Now()
+	tombstones := make([]models.SyncTombstone, len(ids))
+	for i, id := range ids {
+		tombstones[i] = models.SyncTombstone{EntityType: entity, EntityID: id, UserID: userID, DeletedAt: now}
+	}
+	return db.Create(&tombstones).Error
+}
+
+// FindChanges récupère les modifications d'une entité
+func (r *syncRepository) FindChanges(entity string, queryScope *scope.QueryScope, since *time.Time, limit int) ([]SyncChange, error) {
+	var query *gorm.DB
+	var changedAt string
+	switch entity {
+	case models.SyncEntityTickets:
+		changedAt = "COALESCE(tickets.deleted_at, tickets.updated_at)"
+		query = database.DB.Table("tickets").
+			Select("tickets.id, tickets.created_at, "+changedAt+" AS changed_at, tickets.deleted_at IS NOT NULL AS deleted").
+			Where("tickets.id IN (?)", visibleTicketIDs(queryScope))
+		if since == nil {
+			query = query.Where("tickets.deleted_at IS NULL")
+		}
+	case models.SyncEntityComments:
+		changedAt = "COALESCE(ticket_comments.deleted_at, ticket_comments.updated_at)"
+		query = database.DB.Table("ticket_comments").
+			Select("ticket_comments.id, ticket_comments.created_at, "+changedAt+" AS changed_at, ticket_comments.deleted_at IS NOT NULL AS deleted").
+			Where("ticket_comments.ticket_id IN (?)", visibleTicketIDs(queryScope))
+		// Commentaires internes réservés à l'IT (même règle que la liste des commentaires d'un ticket)
+		if !queryScope.DepartmentIsIT && !queryScope.HasPermission("tickets.comments.view_internal") {
+			query = query.Where("ticket_comments.is_internal = ?", false)
+		}
+		if since == nil {
+			query = query.Where("ticket_comments.deleted_at IS NULL")
+		}
+	case models.SyncEntityNotifications:
+		changedAt = "GREATEST(notifications.created_at, COALESCE(notifications.read_at, notifications.created_at))"
+		query = database.DB.Table("notifications").
+			Select("notifications.id, notifications.created_at, "+changedAt+" AS changed_at, FALSE AS deleted").
+			Where("notifications.user_id = ?", queryScope.UserID)
+	case models.SyncEntityTasks:
+		changedAt = "project_tasks.updated_at"
+		projects := scope.ApplyProjectScope(database.DB.Model(&models.Project{}).Select("projects.id"), queryScope)
+		assigned := database.DB.Model(&models.ProjectTaskAssignee{}).Select("project_task_id").Where("user_id = ?", queryScope.UserID)
+		query = database.DB.Table("project_tasks").
+			Select("project_tasks.id, project_tasks.created_at, "+changedAt+" AS changed_at, FALSE AS deleted").
+			Where("project_tasks.project_id IN (?) OR project_tasks.assigned_to_id = ? OR project_tasks.id IN (?)", projects, queryScope.UserID, assigned)
+	default:
+		return nil, nil
+	}
+
+	if since != nil {
+		query = query.Where(changedAt+" >= ?", *since)
+	}
+	var changes []SyncChange
+	err := query.Order(changedAt + " ASC").Order("id ASC").Limit(limit).Scan(&changes).Error
+	return changes, err
+}
+
+// visibleTicketIDs sous-requête des tickets du périmètre, supprimés compris (pour signaler leur suppression)
+func visibleTicketIDs(queryScope *scope.QueryScope) *gorm.DB {
+	return scope.ApplyTicketScope(database.DB.Unscoped().Model(&models.Ticket{}).Select("tickets.id"), queryScope)
+}
+
+// FindTombstones récupère les suppressions définitives
+func (r *syncRepository) FindTombstones(entity string, userID uint, since time.Time, limit int) ([]models.SyncTombstone, error) {
+	var tombstones []models.SyncTombstone
+	err := database.DB.
+		Where("entity_type = ? AND deleted_at >= ?", entity, since).
+		Where("user_id IS NULL OR user_id = ?", userID).
+		Order("deleted_at ASC").Order("id ASC").
+		Limit(limit).
+		Find(&tombstones).Error
+	return tombstones, err
+}
+
+// PurgeTombstones supprime les traces de suppression plus anciennes qu'une date
+func (r *syncRepository) PurgeTombstones(olderThan time.Time) (int64, error) {
+	result := database.DB.Where("deleted_at < ?", olderThan).Delete(&models.SyncTombstone{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// TelegramLinkRepository interface pour les opérations sur les liaisons de comptes au bot Telegram
+type TelegramLinkRepository interface {
+	Save(link *models.TelegramLink) error
+	FindByUserID(userID uint) (*models.TelegramLink, error)
+	FindByChatID(chatID int64) (*models.TelegramLink, error)
+	FindByLinkCodeHash(codeHash string) (*models.TelegramLink, error)
+	DeleteByUserID(userID uint) error
+	DeleteByChatID(chatID int64) error
+}
+
+// telegramLinkRepository implémente TelegramLinkRepository
+type telegramLinkRepository struct{}
+
+// NewTelegramLinkRepository crée une nouvelle instance de TelegramLinkRepository
+func NewTelegramLinkRepository() TelegramLinkRepository {
+	return &telegramLinkRepository{}
+}
+
+// Save crée ou met à jour une liaison
+func (r *telegramLinkRepository) Save(link *models.TelegramLink) error {
+	return database.DB.Save(link).Error
+}
+
+// FindByUserID trouve la liaison d'un utilisateur
+func (r *telegramLinkRepository) FindByUserID(userID uint) (*models.TelegramLink, error) {
+	var link models.TelegramLink
+	if err := database.DB.Where("user_id = ?", userID).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByChatID trouve la liaison d'une conversation
+func (r *telegramLinkRepository) FindByChatID(chatID int64) (*models.TelegramLink, error) {
+	var link models.TelegramLink
+	if err := database.DB.Where("chat_id = ?", chatID).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByLinkCodeHash trouve la liaison en attente correspondant à un code
+func (r *telegramLinkRepository) FindByLinkCodeHash(codeHash string) (*models.TelegramLink, error) {
+	var link models.TelegramLink
+	if err := database.DB.Where("link_code_hash = ?", codeHash).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// DeleteByUserID supprime la liaison d'un utilisateur
+func (r *telegramLinkRepository) DeleteByUserID(userID uint) error {
+	return database.DB.Where("user_id = ?", userID).Delete(&models.TelegramLink{}).Error
+}
+
+// DeleteByChatID supprime la liaison d'une conversation
+func (r *telegramLinkRepository) DeleteByChatID(chatID int64) error {
+	return database.DB.Where("chat_id = ?", chatID).Delete(&models.TelegramLink{}).Error
+}
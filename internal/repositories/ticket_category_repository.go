@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/cache"
 	"github.com/mcicare/itsm-backend/internal/models"
 )
 
@@ -16,6 +17,9 @@ type TicketCategoryRepository interface {
 	Delete(id uint) error
 }
 
+// ticketCategoryCachePrefix préfixe les clés de cache des listes de catégories
+const ticketCategoryCachePrefix = "ticket_categories:"
+
 // ticketCategoryRepository implémente TicketCategoryRepository
 type ticketCategoryRepository struct{}
 
@@ -26,7 +30,11 @@ func NewTicketCategoryRepository() TicketCategoryRepository {
 
 // Create crée une nouvelle catégorie
 func (r *ticketCategoryRepository) Create(category *models.TicketCategory) error {
-	return database.DB.Create(category).Error
+	if err := database.DB.Create(category).Error; err != nil {
+		return err
+	}
+	cache.Shared.DeletePrefix(ticketCategoryCachePrefix)
+	return nil
 }
 
 // FindByID trouve une catégorie par son ID
@@ -49,26 +57,40 @@ func (r *ticketCategoryRepository) FindBySlug(slug string) (*models.TicketCatego
 	return &category, nil
 }
 
-// FindAll récupère toutes les catégories
+// FindAll récupère toutes les catégories (mis en cache)
 func (r *ticketCategoryRepository) FindAll() ([]models.TicketCategory, error) {
-	var categories []models.TicketCategory
-	err := database.DB.Order("display_order ASC, name ASC").Find(&categories).Error
-	return categories, err
+	categories, err := cache.GetOrLoad(cache.Shared, ticketCategoryCachePrefix+"all", 0, func() ([]models.TicketCategory, error) {
+		var categories []models.TicketCategory
+		err := database.DB.Order("display_order ASC, name ASC").Find(&categories).Error
+		return categories, err
+	})
+	return append([]models.TicketCategory(nil), categories...), err
 }
 
-// FindActive récupère uniquement les catégories actives
+// FindActive récupère uniquement les catégories actives (mis en cache)
 func (r *ticketCategoryRepository) FindActive() ([]models.TicketCategory, error) {
-	var categories []models.TicketCategory
-	err := database.DB.Where("is_active = ?", true).Order("display_order ASC, name ASC").Find(&categories).Error
-	return categories, err
+	categories, err := cache.GetOrLoad(cache.Shared, ticketCategoryCachePrefix+"active", 0, func() ([]models.TicketCategory, error) {
+		var categories []models.TicketCategory
+		err := database.DB.Where("is_active = ?", true).Order("display_order ASC, name ASC").Find(&categories).Error
+		return categories, err
+	})
+	return append([]models.TicketCategory(nil), categories...), err
 }
 
 // Update met à jour une catégorie
 func (r *ticketCategoryRepository) Update(category *models.TicketCategory) error {
-	return database.DB.Save(category).Error
+	if err := database.DB.Save(category).Error; err != nil {
+		return err
+	}
+	cache.Shared.DeletePrefix(ticketCategoryCachePrefix)
+	return nil
 }
 
 // Delete supprime une catégorie (soft delete)
 func (r *ticketCategoryRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.TicketCategory{}, id).Error
+	if err := database.DB.Delete(&models.TicketCategory{}, id).Error; err != nil {
+		return err
+	}
+	cache.Shared.DeletePrefix(ticketCategoryCachePrefix)
+	return nil
 }
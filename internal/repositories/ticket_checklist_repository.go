@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TicketChecklistRepository interface pour les opérations sur les éléments de checklist des tickets
+type TicketChecklistRepository interface {
+	Create(item *models.TicketChecklistItem) error
+	FindByID(id uint) (*models.TicketChecklistItem, error)
+	FindByTicketID(ticketID uint) ([]models.TicketChecklistItem, error)
+	NextDisplayOrder(ticketID uint) (int, error)
+	Update(item *models.TicketChecklistItem) error
+	Reorder(ticketID uint, itemIDs []uint) error
+	Delete(id uint) error
+}
+
+// ticketChecklistRepository implémente TicketChecklistRepository
+type ticketChecklistRepository struct{}
+
+// NewTicketChecklistRepository crée une nouvelle instance de TicketChecklistRepository
+func NewTicketChecklistRepository() TicketChecklistRepository {
+	return &ticketChecklistRepository{}
+}
+
+// Create crée un élément de checklist
+func (r *ticketChecklistRepository) Create(item *models.TicketChecklistItem) error {
+	return database.DB.Omit(clause.Associations).Create(item).Error
+}
+
+// FindByID trouve un élément de checklist par son ID
+func (r *ticketChecklistRepository) FindByID(id uint) (*models.TicketChecklistItem, error) {
+	var item models.TicketChecklistItem
+	err := database.DB.Preload("Assignee").Preload("DoneBy").First(&item, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindByTicketID récupère la checklist d'un ticket dans l'ordre d'affichage
+func (r *ticketChecklistRepository) FindByTicketID(ticketID uint) ([]models.TicketChecklistItem, error) {
+	var items []models.TicketChecklistItem
+	err := database.DB.Preload("Assignee").Preload("DoneBy").
+		Where("ticket_id = ?", ticketID).
+		Order("display_order ASC, id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// NextDisplayOrder retourne la position suivant le dernier élément de la checklist du ticket
+func (r *ticketChecklistRepository) NextDisplayOrder(ticketID uint) (int, error) {
+	var maxOrder *int
+	err := database.DB.Model(&models.TicketChecklistItem{}).
+		Where("ticket_id = ?", ticketID).
+		Select("MAX(display_order)").
+		Scan(&maxOrder).Error
+	if err != nil || maxOrder == nil {
+		return 0, err
+	}
+	return *maxOrder + 1, nil
+}
+
+// Update met à jour un élément de checklist
+func (r *ticketChecklistRepository) Update(item *models.TicketChecklistItem) error {
+	return database.DB.Omit(clause.Associations).Save(item).Error
+}
+
+// Reorder applique l'ordre donné aux éléments de la checklist du ticket (les IDs d'un autre ticket sont ignorés)
+func (r *ticketChecklistRepository) Reorder(ticketID uint, itemIDs []uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		for order, id := range itemIDs {
+			if err := tx.Model(&models.TicketChecklistItem{}).
+				Where("id = ? AND ticket_id = ?", id, ticketID).
+				Update("display_order", order).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete supprime un élément de checklist
+func (r *ticketChecklistRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.TicketChecklistItem{}, id).Error
+}
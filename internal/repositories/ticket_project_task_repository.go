@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TicketProjectTaskRepository interface pour les liens entre tickets et tâches de projet
+type TicketProjectTaskRepository interface {
+	Create(link *models.TicketProjectTask) error
+	FindByTicketAndProject(ticketID, projectID uint) (*models.TicketProjectTask, error)
+	FindByTaskID(taskID uint) ([]models.TicketProjectTask, error)
+	FindByTicketID(ticketID uint, scope interface{}) ([]models.TicketProjectTask, error)
+	Delete(ticketID, taskID uint) (bool, error)
+	RefreshTicketTime(taskIDs []uint) error
+	RefreshTicketTimeByTicketID(ticketID uint) error
+}
+
+// ticketProjectTaskRepository implémente TicketProjectTaskRepository
+type ticketProjectTaskRepository struct{}
+
+// NewTicketProjectTaskRepository crée une nouvelle instance de TicketProjectTaskRepository
+func NewTicketProjectTaskRepository() TicketProjectTaskRepository {
+	return &ticketProjectTaskRepository{}
+}
+
+// Create lie un ticket à une tâche et rattache le ticket au projet de la tâche (ticket_projects) s'il ne l'est pas déjà
+func (r *ticketProjectTaskRepository) Create(link *models.TicketProjectTask) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(link).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).
+			Create(&models.TicketProject{TicketID: link.TicketID, ProjectID: link.ProjectID}).Error
+	})
+}
+
+// FindByTicketAndProject récupère le lien d'un ticket vers une tâche du projet (nil s'il n'y en a pas)
+func (r *ticketProjectTaskRepository) FindByTicketAndProject(ticketID, projectID uint) (*models.TicketProjectTask, error) {
+	var links []models.TicketProjectTask
+	err := database.DB.Where("ticket_id = ? AND project_id = ?", ticketID, projectID).Limit(1).Find(&links).Error
+	if err != nil || len(links) == 0 {
+		return nil, err
+	}
+	return &links[0], nil
+}
+
+// FindByTaskID récupère les tickets liés à une tâche, du plus récemment lié au plus ancien
+func (r *ticketProjectTaskRepository) FindByTaskID(taskID uint) ([]models.TicketProjectTask, error) {
+	var links []models.TicketProjectTask
+	err := database.DB.Preload("Ticket").
+		Where("project_task_id = ?", taskID).Order("created_at DESC").Find(&links).Error
+	return links, err
+}
+
+// FindByTicketID récupère les tâches liées à un ticket, limitées aux projets du périmètre
+func (r *ticketProjectTaskRepository) FindByTicketID(ticketID uint, scopeParam interface{}) ([]models.TicketProjectTask, error) {
+	var links []models.TicketProjectTask
+	query := database.DB.Model(&models.TicketProjectTask{}).Preload("ProjectTask").Preload("Project").
+		Joins("JOIN projects ON projects.id = ticket_project_tasks.project_id").
+		Where("ticket_project_tasks.ticket_id = ?", ticketID)
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		query = scope.ApplyProjectScope(query, queryScope)
+	}
+	err := query.Order("ticket_project_tasks.created_at DESC").Find(&links).Error
+	return links, err
+}
+
+// Delete supprime le lien entre un ticket et une tâche ; false s'il n'existait pas
+// (le rattachement du ticket au projet est conservé)
+func (r *ticketProjectTaskRepository) Delete(ticketID, taskID uint) (bool, error) {
+	result := database.DB.Where("ticket_id = ? AND project_task_id = ?", ticketID, taskID).Delete(&models.TicketProjectTask{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RefreshTicketTime recalcule le temps saisi sur les tickets liés aux tâches (ticket_time), puis le temps consommé
+// de leurs projets (somme des actual_time et ticket_time des tâches)
+func (r *ticketProjectTaskRepository) RefreshTicketTime(taskIDs []uint) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ProjectTask{}).Where("id IN ?", taskIDs).
+			Update("ticket_time", gorm.Expr("(SELECT COALESCE(SUM(time_entries.time_spent), 0) FROM ticket_project_tasks "+
+				"JOIN time_entries ON time_entries.ticket_id = ticket_project_tasks.ticket_id AND time_entries.deleted_at IS NULL "+
+				"WHERE ticket_project_tasks.project_task_id = project_tasks.id)")).Error; err != nil {
+			return err
+		}
+		var projectIDs []uint
+		if err := tx.Model(&models.ProjectTask{}).Where("id IN ?", taskIDs).Distinct().Pluck("project_id", &projectIDs).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Project{}).Where("id IN ?", projectIDs).
+			Update("consumed_time", gorm.Expr("(SELECT COALESCE(SUM(project_tasks.actual_time + project_tasks.ticket_time), 0) "+
+				"FROM project_tasks WHERE project_tasks.project_id = projects.id)")).Error
+	})
+}
+
+// RefreshTicketTimeByTicketID recalcule le temps des tâches liées à un ticket (après une saisie de temps sur le ticket)
+func (r *ticketProjectTaskRepository) RefreshTicketTimeByTicketID(ticketID uint) error {
+	var taskIDs []uint
+	if err := database.DB.Model(&models.TicketProjectTask{}).Where("ticket_id = ?", ticketID).
+		Pluck("project_task_id", &taskIDs).Error; err != nil {
+		return err
+	}
+	return r.RefreshTicketTime(taskIDs)
+}
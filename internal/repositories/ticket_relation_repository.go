@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// TicketRelationRepository interface pour les opérations sur les relations entre tickets
+type TicketRelationRepository interface {
+	Create(relation *models.TicketRelation) error
+	FindByID(id uint) (*models.TicketRelation, error)
+	FindByTicketID(ticketID uint) ([]models.TicketRelation, error) // Relations dont le ticket est la source ou la cible
+	ExistsBetween(ticketID, otherTicketID uint) (bool, error)
+	Delete(id uint) error
+}
+
+// ticketRelationRepository implémente TicketRelationRepository
+type ticketRelationRepository struct{}
+
+// NewTicketRelationRepository crée une nouvelle instance de TicketRelationRepository
+func NewTicketRelationRepository() TicketRelationRepository {
+	return &ticketRelationRepository{}
+}
+
+// Create crée une relation entre deux tickets
+func (r *ticketRelationRepository) Create(relation *models.TicketRelation) error {
+	return database.DB.Omit(clause.Associations).Create(relation).Error
+}
+
+// FindByID trouve une relation par son ID
+func (r *ticketRelationRepository) FindByID(id uint) (*models.TicketRelation, error) {
+	var relation models.TicketRelation
+	if err := database.DB.First(&relation, id).Error; err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// FindByTicketID récupère les relations d'un ticket avec le résumé des deux tickets, des plus anciennes aux plus récentes
+func (r *ticketRelationRepository) FindByTicketID(ticketID uint) ([]models.TicketRelation, error) {
+	var relations []models.TicketRelation
+	err := database.DB.
+		Preload("SourceTicket", selectRelatedTicket).
+		Preload("TargetTicket", selectRelatedTicket).
+		Where("source_ticket_id = ? OR target_ticket_id = ?", ticketID, ticketID).
+		Order("created_at ASC").
+		Find(&relations).Error
+	return relations, err
+}
+
+// ExistsBetween indique si deux tickets sont déjà liés, dans un sens ou dans l'autre
+func (r *ticketRelationRepository) ExistsBetween(ticketID, otherTicketID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.TicketRelation{}).
+		Where("(source_ticket_id = ? AND target_ticket_id = ?) OR (source_ticket_id = ? AND target_ticket_id = ?)", ticketID, otherTicketID, otherTicketID, ticketID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Delete supprime une relation
+func (r *ticketRelationRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.TicketRelation{}, id).Error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/scope"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -27,6 +29,25 @@ func assigneesTableExists() bool {
 	return database.DB.Migrator().HasTable(&models.TicketAssignee{})
 }
 
+// TicketFilter critères de sélection des tickets (vues enregistrées, export)
+type TicketFilter struct {
+	Statuses    []string
+	Priorities  []string
+	FilialeID   *uint
+	AssigneeID  *uint      // Assigné principal ou co-assigné
+	CreatedFrom *time.Time // Borne de début incluse (created_at)
+	CreatedTo   *time.Time // Borne de fin exclue (created_at)
+	Text        string     // Recherche dans le code, le titre et la description
+}
+
+// TicketMergeResult éléments rattachés au ticket principal lors d'une fusion
+type TicketMergeResult struct {
+	Comments    int64
+	Attachments int64
+	TimeEntries int64
+	SLAMoved    bool // SLA d'un doublon repris par le ticket principal (qui n'en avait pas)
+}
+
 // TicketRepository interface pour les opérations sur les tickets
 type TicketRepository interface {
 	Create(ticket *models.Ticket) error
@@ -37,6 +58,8 @@ type TicketRepository interface {
 	FindByIDForUpdate(id uint) (*models.Ticket, error)
 	FindAll(scope interface{}, page, limit int, filterFilialeID *uint) ([]models.Ticket, int64, error) // scope peut être *scope.QueryScope ou nil; filterFilialeID = filtre par filiale du ticket (envoyée par)
 	FindWithFilters(scope interface{}, page, limit int, status string, filterFilialeID *uint, assigneeUserID *uint) ([]models.Ticket, int64, error)
+	FindByFilter(scope interface{}, filter TicketFilter, page, limit int) ([]models.Ticket, int64, error)
+	FindForExport(scope interface{}, filter TicketFilter, afterID uint, limit int) ([]models.Ticket, error) // Page d'ID supérieur à afterID (export en flux)
 	FindByStatus(scope interface{}, status string, page, limit int) ([]models.Ticket, int64, error)
 	FindByCategory(scope interface{}, category string, page, limit int, status, priority string) ([]models.Ticket, int64, error)
 	FindByPriority(priority string) ([]models.Ticket, error)
@@ -58,6 +81,10 @@ type TicketRepository interface {
 	Search(scope interface{}, query string, status string, limit int) ([]models.Ticket, error) // scope peut être *scope.QueryScope ou nil
 	GetNextSequenceNumber(year int) (int, error) // Obtient le prochain numéro séquentiel pour une année donnée
 	CodeExists(code string) (bool, error)        // Vérifie si un code existe déjà
+	// Merge rattache au ticket principal les commentaires, pièces jointes, entrées de temps et SLA des doublons, puis passe ces derniers au statut fusionne
+	Merge(primaryID uint, duplicateIDs []uint, histories []models.TicketHistory, mergedAt time.Time) (*TicketMergeResult, error)
+	// FindMergeCandidates récupère les tickets non clôturés de la même filiale créés depuis une date (suggestion de doublons)
+	FindMergeCandidates(scope interface{}, ticketID uint, filialeID *uint, since time.Time, limit int) ([]models.Ticket, error)
 }
 
 // ticketRepository implémente TicketRepository
@@ -76,7 +103,24 @@ func applyTicketPreloads(query *gorm.DB) *gorm.DB {
 		Preload("ValidatedBy").
 		Preload("Filiale").
 		Preload("Software").
-		Preload("Assignees").Preload("Assignees.User")
+		Preload("FixedInRelease").
+		Preload("Environment").
+		Preload("JiraLink").
+		Preload("Assignees").Preload("Assignees.User").
+		Preload("Watchers").Preload("Watchers.User").
+		Preload("Checklist", selectChecklistProgress).
+		Preload("OutgoingRelations.TargetTicket", selectRelatedTicket).
+		Preload("IncomingRelations.SourceTicket", selectRelatedTicket)
+}
+
+// selectRelatedTicket limite le préchargement des tickets liés aux colonnes affichées dans le détail
+func selectRelatedTicket(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "code", "title", "status", "priority")
+}
+
+// selectChecklistProgress limite le préchargement de la checklist aux colonnes utiles au calcul de l'avancement
+func selectChecklistProgress(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "ticket_id", "done")
 }
 
 // applyTicketPreloadsBasic applique les Preloads de base (sans toutes les relations)
@@ -118,7 +162,14 @@ func (r *ticketRepository) FindByIDLean(id uint) (*models.Ticket, error) {
 		Preload("Requester").
 		Preload("Filiale").
 		Preload("Software").
+		Preload("FixedInRelease").
+		Preload("Environment").
+		Preload("JiraLink").
 		Preload("Assignees").Preload("Assignees.User").
+		Preload("Watchers").Preload("Watchers.User").
+		Preload("Checklist", selectChecklistProgress).
+		Preload("OutgoingRelations.TargetTicket", selectRelatedTicket).
+		Preload("IncomingRelations.SourceTicket", selectRelatedTicket).
 		First(&ticket, id).Error
 	if err != nil {
 		return nil, err
@@ -155,6 +206,9 @@ func (r *ticketRepository) FindByIDForUpdate(id uint) (*models.Ticket, error) {
 		"assigned_to_id",
 		"created_by_id",
 		"parent_id",
+		"filiale_id",
+		"software_id",
+		"environment_id",
 	).
 		First(&ticket, id).Error
 	if err != nil {
@@ -260,6 +314,76 @@ func (r *ticketRepository) FindWithFilters(scopeParam interface{}, page, limit i
 	return tickets, total, err
 }
 
+// FindByFilter récupère les tickets répondant aux critères (avec pagination)
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (r *ticketRepository) FindByFilter(scopeParam interface{}, filter TicketFilter, page, limit int) ([]models.Ticket, int64, error) {
+	var tickets []models.Ticket
+	var total int64
+
+	query := applyTicketFilter(database.DB.Model(&models.Ticket{}), filter)
+	if scopeParam != nil {
+		if queryScope, ok := scopeParam.(*scope.QueryScope); ok {
+			query = scope.ApplyTicketScope(query, queryScope)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * limit
+	err := applyTicketPreloadsBasic(query).
+		Order("tickets.created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&tickets).Error
+	return tickets, total, err
+}
+
+// FindForExport récupère une page de tickets filtrés d'ID supérieur à afterID, par ordre de création (export en flux)
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (r *ticketRepository) FindForExport(scopeParam interface{}, filter TicketFilter, afterID uint, limit int) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+
+	query := applyTicketFilter(database.DB.Model(&models.Ticket{}), filter).Where("tickets.id > ?", afterID)
+	if scopeParam != nil {
+		if queryScope, ok := scopeParam.(*scope.QueryScope); ok {
+			query = scope.ApplyTicketScope(query, queryScope)
+		}
+	}
+
+	err := applyTicketPreloadsBasic(query).Preload("Requester").
+		Order("tickets.id ASC").
+		Limit(limit).
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// applyTicketFilter applique les critères d'un TicketFilter à la requête
+func applyTicketFilter(query *gorm.DB, filter TicketFilter) *gorm.DB {
+	if len(filter.Statuses) > 0 {
+		query = query.Where("tickets.status IN ?", filter.Statuses)
+	}
+	if len(filter.Priorities) > 0 {
+		query = query.Where("tickets.priority IN ?", filter.Priorities)
+	}
+	if filter.FilialeID != nil {
+		query = query.Where("tickets.filiale_id = ?", *filter.FilialeID)
+	}
+	if filter.AssigneeID != nil {
+		query = query.Where("tickets.assigned_to_id = ? OR tickets.id IN (SELECT ticket_id FROM ticket_assignees WHERE user_id = ?)", *filter.AssigneeID, *filter.AssigneeID)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("tickets.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("tickets.created_at < ?", *filter.CreatedTo)
+	}
+	if filter.Text != "" {
+		searchPattern := "%" + filter.Text + "%"
+		query = query.Where("tickets.code LIKE ? OR tickets.title LIKE ? OR tickets.description LIKE ?", searchPattern, searchPattern, searchPattern)
+	}
+	return query
+}
+
 // FindByStatus récupère les tickets par statut (avec pagination)
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (r *ticketRepository) FindByStatus(scopeParam interface{}, status string, page, limit int) ([]models.Ticket, int64, error) {
@@ -306,24 +430,18 @@ func (r *ticketRepository) FindByCategory(scopeParam interface{}, category strin
 	var tickets []models.Ticket
 	var total int64
 
-	fmt.Printf("DEBUG: FindByCategory - category: %s, status: %s, priority: %s\n", category, status, priority)
-
 	// Construire la requête de base
 	query := database.DB.Model(&models.Ticket{}).Where("category = ?", category)
 	countQuery := database.DB.Model(&models.Ticket{}).Where("category = ?", category)
 
 	// Ajouter les filtres optionnels
 	if status != "" && status != "all" {
-		fmt.Printf("DEBUG: Ajout du filtre status: %s\n", status)
 		query = query.Where("status = ?", status)
 		countQuery = countQuery.Where("status = ?", status)
 	}
 	if priority != "" && priority != "all" {
-		fmt.Printf("DEBUG: Ajout du filtre priority: %s\n", priority)
 		query = query.Where("priority = ?", priority)
 		countQuery = countQuery.Where("priority = ?", priority)
-	} else {
-		fmt.Printf("DEBUG: Pas de filtre priority (valeur: '%s')\n", priority)
 	}
 
 	// Appliquer le scope si fourni (scope dépendant de la catégorie : incidents.*, service_requests.*, changes.*, ticket_categories.view, tickets.view_*)
@@ -394,7 +512,7 @@ func (r *ticketRepository) FindByAssignedTo(userID uint, page, limit int) ([]mod
 		Offset(offset).Limit(limit).
 		Find(&tickets).Error
 	queryDur := time.Since(queryStart)
-	fmt.Printf("PERF FindByAssignedTo user=%d count=%d countDur=%s queryDur=%s totalDur=%s\n", userID, total, countDur, queryDur, time.Since(start))
+	slog.Debug("perf_tickets_by_assignee", "user_id", userID, "count", total, "count_ms", countDur.Milliseconds(), "query_ms", queryDur.Milliseconds(), "total_ms", time.Since(start).Milliseconds())
 
 	return tickets, total, err
 }
@@ -408,7 +526,7 @@ func (r *ticketRepository) FindPanierByUser(userID uint, page, limit int) ([]mod
 		return tickets, 0, nil
 	}
 
-	baseQuery := database.DB.Model(&models.Ticket{}).Where("tickets.status NOT IN (?, ?)", "cloture", "closed")
+	baseQuery := database.DB.Model(&models.Ticket{}).Where("tickets.status NOT IN (?, ?, ?)", "cloture", "closed", "fusionne")
 	if assigneesTableExists() {
 		baseQuery = baseQuery.Where(
 			"tickets.assigned_to_id = ? OR EXISTS (SELECT 1 FROM ticket_assignees ta WHERE ta.ticket_id = tickets.id AND ta.user_id = ?)",
@@ -497,7 +615,7 @@ func (r *ticketRepository) FindByUser(userID uint, page, limit int, status strin
 		Offset(offset).Limit(limit).
 		Find(&tickets).Error
 	queryDur := time.Since(queryStart)
-	fmt.Printf("PERF FindByUser user=%d status=%s count=%d countDur=%s queryDur=%s totalDur=%s\n", userID, status, total, countDur, queryDur, time.Since(start))
+	slog.Debug("perf_tickets_by_user", "user_id", userID, "status", status, "count", total, "count_ms", countDur.Milliseconds(), "query_ms", queryDur.Milliseconds(), "total_ms", time.Since(start).Milliseconds())
 
 	return tickets, total, err
 }
@@ -714,3 +832,93 @@ func (r *ticketRepository) CodeExists(code string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// Merge rattache au ticket principal les éléments des doublons et passe ces derniers au statut fusionne, en une seule transaction
+// Le ticket principal reprend le SLA le plus urgent des doublons s'il n'en a pas ; les SLA restants des doublons sont arrêtés
+func (r *ticketRepository) Merge(primaryID uint, duplicateIDs []uint, histories []models.TicketHistory, mergedAt time.Time) (*TicketMergeResult, error) {
+	result := &TicketMergeResult{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		moved := tx.Model(&models.TicketComment{}).Where("ticket_id IN ?", duplicateIDs).Update("ticket_id", primaryID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.Comments = moved.RowsAffected
+
+		moved = tx.Model(&models.TicketAttachment{}).Where("ticket_id IN ?", duplicateIDs).Update("ticket_id", primaryID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.Attachments = moved.RowsAffected
+
+		moved = tx.Model(&models.TimeEntry{}).Where("ticket_id IN ?", duplicateIDs).Update("ticket_id", primaryID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.TimeEntries = moved.RowsAffected
+
+		var primarySLAs int64
+		if err := tx.Model(&models.TicketSLA{}).Where("ticket_id = ?", primaryID).Count(&primarySLAs).Error; err != nil {
+			return err
+		}
+		if primarySLAs == 0 {
+			var ticketSLA models.TicketSLA
+			if err := tx.Where("ticket_id IN ?", duplicateIDs).Order("target_time ASC").Limit(1).Find(&ticketSLA).Error; err != nil {
+				return err
+			}
+			if ticketSLA.ID != 0 {
+				if err := tx.Model(&models.TicketSLA{}).Where("id = ?", ticketSLA.ID).Update("ticket_id", primaryID).Error; err != nil {
+					return err
+				}
+				result.SLAMoved = true
+			}
+		}
+		if err := tx.Model(&models.TicketSLA{}).Where("ticket_id IN ? AND actual_time IS NULL", duplicateIDs).Update("actual_time", mergedAt).Error; err != nil {
+			return err
+		}
+
+		var actualTime int
+		if err := tx.Model(&models.TimeEntry{}).Where("ticket_id = ?", primaryID).Select("COALESCE(SUM(time_spent), 0)").Scan(&actualTime).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Ticket{}).Where("id = ?", primaryID).Update("actual_time", actualTime).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Ticket{}).Where("id IN ?", duplicateIDs).Updates(map[string]interface{}{
+			"status":         "fusionne",
+			"merged_into_id": primaryID,
+			"closed_at":      mergedAt,
+			"actual_time":    nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if len(histories) > 0 {
+			return tx.Omit(clause.Associations).Create(&histories).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindMergeCandidates récupère les tickets non clôturés de la même filiale créés depuis une date, sans relations (suggestion de doublons)
+func (r *ticketRepository) FindMergeCandidates(scopeParam interface{}, ticketID uint, filialeID *uint, since time.Time, limit int) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	query := database.DB.Model(&models.Ticket{}).
+		Select("tickets.id, tickets.code, tickets.title, tickets.status, tickets.priority, tickets.filiale_id, tickets.created_at").
+		Where("tickets.id <> ? AND tickets.status NOT IN ? AND tickets.created_at >= ?", ticketID, []string{"cloture", "fusionne"}, since)
+	if filialeID != nil {
+		query = query.Where("tickets.filiale_id = ?", *filialeID)
+	} else {
+		query = query.Where("tickets.filiale_id IS NULL")
+	}
+	if scopeParam != nil {
+		if queryScope, ok := scopeParam.(*scope.QueryScope); ok {
+			query = scope.ApplyTicketScope(query, queryScope)
+		}
+	}
+	err := query.Order("tickets.created_at DESC").Limit(limit).Find(&tickets).Error
+	return tickets, err
+}
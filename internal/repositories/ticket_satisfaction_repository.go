@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TicketSatisfactionRepository interface pour les opérations sur les enquêtes de satisfaction des tickets
+type TicketSatisfactionRepository interface {
+	Create(survey *models.TicketSatisfaction) (bool, error) // false si le ticket a déjà une enquête
+	FindByTokenHash(tokenHash string) (*models.TicketSatisfaction, error)
+	Answer(id uint, rating int, comment string, answeredAt time.Time) (bool, error) // false si l'enquête a déjà une réponse
+}
+
+// ticketSatisfactionRepository implémente TicketSatisfactionRepository
+type ticketSatisfactionRepository struct{}
+
+// NewTicketSatisfactionRepository crée une nouvelle instance de TicketSatisfactionRepository
+func NewTicketSatisfactionRepository() TicketSatisfactionRepository {
+	return &ticketSatisfactionRepository{}
+}
+
+// Create crée l'enquête d'un ticket (une seule enquête par ticket, même s'il est rouvert puis clôturé à nouveau)
+func (r *ticketSatisfactionRepository) Create(survey *models.TicketSatisfaction) (bool, error) {
+	result := database.DB.Omit(clause.Associations).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "ticket_id"}}, DoNothing: true}).
+		Create(survey)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByTokenHash trouve une enquête par le hash de son token, avec le code et le titre du ticket
+func (r *ticketSatisfactionRepository) FindByTokenHash(tokenHash string) (*models.TicketSatisfaction, error) {
+	var survey models.TicketSatisfaction
+	err := database.DB.Preload("Ticket", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "code", "title")
+	}).Where("token_hash = ?", tokenHash).First(&survey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &survey, nil
+}
+
+// Answer enregistre la réponse à une enquête qui n'en a pas encore
+func (r *ticketSatisfactionRepository) Answer(id uint, rating int, comment string, answeredAt time.Time) (bool, error) {
+	result := database.DB.Model(&models.TicketSatisfaction{}).
+		Where("id = ? AND answered_at IS NULL", id).
+		Updates(map[string]any{"rating": rating, "comment": comment, "answered_at": answeredAt})
+	return result.RowsAffected > 0, result.Error
+}
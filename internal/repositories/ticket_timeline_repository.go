@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Sources du fil d'activité d'un ticket (l'ordre alphabétique départage les éléments de même date)
+const (
+	TimelineSourceAttachment = "attachment"
+	TimelineSourceComment    = "comment"
+	TimelineSourceHistory    = "history"
+	TimelineSourceSLA        = "sla"
+	TimelineSourceTimeEntry  = "time_entry"
+)
+
+// TicketTimelineCursor position dans le fil d'activité : les éléments suivants sont strictement après (date, source, ID)
+type TicketTimelineCursor struct {
+	At     time.Time
+	Source string
+	ID     uint
+}
+
+// After indique si l'élément (at, source, id) se situe après le curseur
+func (c *TicketTimelineCursor) After(at time.Time, source string, id uint) bool {
+	if c == nil || at.After(c.At) {
+		return true
+	}
+	if at.Before(c.At) {
+		return false
+	}
+	if source != c.Source {
+		return source > c.Source
+	}
+	return id > c.ID
+}
+
+// TicketTimelineRepository interface pour la lecture des éléments du fil d'activité d'un ticket
+// Chaque méthode retourne au plus limit éléments situés après le curseur (nil = depuis le début), par date puis ID
+type TicketTimelineRepository interface {
+	FindHistory(ticketID uint, after *TicketTimelineCursor, limit int) ([]models.TicketHistory, error)
+	FindComments(ticketID uint, includeInternal bool, after *TicketTimelineCursor, limit int) ([]models.TicketComment, error)
+	FindAttachments(ticketID uint, after *TicketTimelineCursor, limit int) ([]models.TicketAttachment, error)
+	FindTimeEntries(ticketID uint, after *TicketTimelineCursor, limit int) ([]models.TimeEntry, error)
+}
+
+// ticketTimelineRepository implémente TicketTimelineRepository
+type ticketTimelineRepository struct{}
+
+// NewTicketTimelineRepository crée une nouvelle instance de TicketTimelineRepository
+func NewTicketTimelineRepository() TicketTimelineRepository {
+	return &ticketTimelineRepository{}
+}
+
+// FindHistory récupère l'historique du ticket (hors ajouts de commentaires, présents dans le fil en tant que commentaires)
+func (r *ticketTimelineRepository) FindHistory(ticketID uint, after *TicketTimelineCursor, limit int) ([]models.TicketHistory, error) {
+	var histories []models.TicketHistory
+	query := database.DB.Preload("User").Preload("User.Role").
+		Where("ticket_id = ? AND action <> ?", ticketID, "comment_added")
+	err := timelinePage(query, TimelineSourceHistory, after, limit).Find(&histories).Error
+	return histories, err
+}
+
+// FindComments récupère les commentaires du ticket (internes uniquement si includeInternal)
+func (r *ticketTimelineRepository) FindComments(ticketID uint, includeInternal bool, after *TicketTimelineCursor, limit int) ([]models.TicketComment, error) {
+	var comments []models.TicketComment
+	query := database.DB.Preload("User").Preload("User.Role").Where("ticket_id = ?", ticketID)
+	if !includeInternal {
+		query = query.Where("is_internal = ?", false)
+	}
+	err := timelinePage(query, TimelineSourceComment, after, limit).Find(&comments).Error
+	return comments, err
+}
+
+// FindAttachments récupère les pièces jointes du ticket
+func (r *ticketTimelineRepository) FindAttachments(ticketID uint, after *TicketTimelineCursor, limit int) ([]models.TicketAttachment, error) {
+	var attachments []models.TicketAttachment
+	query := database.DB.Preload("User").Preload("User.Role").Where("ticket_id = ?", ticketID)
+	err := timelinePage(query, TimelineSourceAttachment, after, limit).Find(&attachments).Error
+	return attachments, err
+}
+
+// FindTimeEntries récupère les temps passés déclarés sur le ticket
+func (r *ticketTimelineRepository) FindTimeEntries(ticketID uint, after *TicketTimelineCursor, limit int) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	query := database.DB.Preload("User").Preload("User.Role").Where("ticket_id = ?", ticketID)
+	err := timelinePage(query, TimelineSourceTimeEntry, after, limit).Find(&entries).Error
+	return entries, err
+}
+
+// timelinePage restreint la requête aux éléments de la source situés après le curseur, triés par date puis ID
+func timelinePage(query *gorm.DB, source string, after *TicketTimelineCursor, limit int) *gorm.DB {
+	if after != nil {
+		switch {
+		case source < after.Source:
+			query = query.Where("created_at > ?", after.At)
+		case source == after.Source:
+			query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", after.At, after.At, after.ID)
+		default:
+			query = query.Where("created_at >= ?", after.At)
+		}
+	}
+	return query.Order("created_at ASC, id ASC").Limit(limit)
+}
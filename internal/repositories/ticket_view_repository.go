@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// TicketViewRepository interface pour les opérations sur les vues de tickets enregistrées
+type TicketViewRepository interface {
+	Create(view *models.TicketView) error
+	FindByID(id uint) (*models.TicketView, error)
+	FindVisible(userID uint) ([]models.TicketView, error) // Vues de l'utilisateur et vues partagées
+	Update(view *models.TicketView) error
+	Delete(id uint) error
+}
+
+// ticketViewRepository implémente TicketViewRepository
+type ticketViewRepository struct{}
+
+// NewTicketViewRepository crée une nouvelle instance de TicketViewRepository
+func NewTicketViewRepository() TicketViewRepository {
+	return &ticketViewRepository{}
+}
+
+// Create crée une vue
+func (r *ticketViewRepository) Create(view *models.TicketView) error {
+	return database.DB.Omit(clause.Associations).Create(view).Error
+}
+
+// FindByID récupère une vue par son ID
+func (r *ticketViewRepository) FindByID(id uint) (*models.TicketView, error) {
+	var view models.TicketView
+	if err := database.DB.Preload("User").First(&view, id).Error; err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// FindVisible récupère les vues de l'utilisateur puis les vues partagées par les autres
+func (r *ticketViewRepository) FindVisible(userID uint) ([]models.TicketView, error) {
+	var views []models.TicketView
+	err := database.DB.Preload("User").
+		Where("user_id = ? OR is_shared = ?", userID, true).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "user_id = ? DESC, name ASC", Vars: []interface{}{userID}}}).
+		Find(&views).Error
+	return views, err
+}
+
+// Update met à jour une vue
+func (r *ticketViewRepository) Update(view *models.TicketView) error {
+	return database.DB.Omit(clause.Associations).Save(view).Error
+}
+
+// Delete supprime une vue
+func (r *ticketViewRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.TicketView{}, id).Error
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// TicketWatcherRepository interface pour les opérations sur les abonnés aux tickets
+type TicketWatcherRepository interface {
+	Create(watcher *models.TicketWatcher) error // Sans effet si l'utilisateur suit déjà le ticket
+	FindByTicketID(ticketID uint) ([]models.TicketWatcher, error)
+	FindUserIDsByTicketID(ticketID uint) ([]uint, error)
+	Exists(ticketID, userID uint) (bool, error)
+	Delete(ticketID, userID uint) error
+}
+
+// ticketWatcherRepository implémente TicketWatcherRepository
+type ticketWatcherRepository struct{}
+
+// NewTicketWatcherRepository crée une nouvelle instance de TicketWatcherRepository
+func NewTicketWatcherRepository() TicketWatcherRepository {
+	return &ticketWatcherRepository{}
+}
+
+// Create abonne un utilisateur à un ticket
+func (r *ticketWatcherRepository) Create(watcher *models.TicketWatcher) error {
+	return database.DB.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(watcher).Error
+}
+
+// FindByTicketID récupère les abonnés d'un ticket, par ordre d'abonnement
+func (r *ticketWatcherRepository) FindByTicketID(ticketID uint) ([]models.TicketWatcher, error) {
+	var watchers []models.TicketWatcher
+	err := database.DB.Preload("User").Where("ticket_id = ?", ticketID).Order("created_at ASC").Find(&watchers).Error
+	return watchers, err
+}
+
+// FindUserIDsByTicketID récupère les IDs des abonnés actifs d'un ticket (destinataires des notifications)
+func (r *ticketWatcherRepository) FindUserIDsByTicketID(ticketID uint) ([]uint, error) {
+	var userIDs []uint
+	err := database.DB.Model(&models.TicketWatcher{}).
+		Joins("JOIN users ON users.id = ticket_watchers.user_id AND users.is_active = ? AND users.deleted_at IS NULL", true).
+		Where("ticket_watchers.ticket_id = ?", ticketID).
+		Pluck("ticket_watchers.user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// Exists indique si l'utilisateur suit le ticket
+func (r *ticketWatcherRepository) Exists(ticketID, userID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.TicketWatcher{}).Where("ticket_id = ? AND user_id = ?", ticketID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+// Delete désabonne un utilisateur d'un ticket
+func (r *ticketWatcherRepository) Delete(ticketID, userID uint) error {
+	return database.DB.Where("ticket_id = ? AND user_id = ?", ticketID, userID).Delete(&models.TicketWatcher{}).Error
+}
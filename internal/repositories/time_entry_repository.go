@@ -9,6 +9,15 @@ import (
 	"github.com/mcicare/itsm-backend/internal/scope"
 )
 
+// TimeEntryCategoryTotal temps déclaré par un utilisateur sur une journée pour une catégorie de travail
+type TimeEntryCategoryTotal struct {
+	UserID       uint
+	Date         time.Time
+	WorkCategory string
+	Billable     bool
+	Minutes      int
+}
+
 // TimeEntryRepository interface pour les opérations sur les entrées de temps
 type TimeEntryRepository interface {
 	Create(timeEntry *models.TimeEntry) error
@@ -24,6 +33,8 @@ type TimeEntryRepository interface {
 	Delete(id uint) error
 	SumByTicketID(ticketID uint) (int, error)
 	SumByUserID(userID uint) (int, error)
+	// SumByCategory cumule le temps des utilisateurs par jour, catégorie de travail et caractère facturable sur [from, to]
+	SumByCategory(userIDs []uint, from, to time.Time) ([]TimeEntryCategoryTotal, error)
 	// ValidateByTicketID marque comme validées toutes les entrées de temps non encore validées du ticket (ex. après validation du ticket par le demandeur)
 	ValidateByTicketID(ticketID uint, validatedByID uint) error
 }
@@ -204,6 +215,20 @@ func (r *timeEntryRepository) SumByUserID(userID uint) (int, error) {
 	return total, err
 }
 
+// SumByCategory cumule le temps des utilisateurs par jour, catégorie de travail et caractère facturable sur [from, to]
+func (r *timeEntryRepository) SumByCategory(userIDs []uint, from, to time.Time) ([]TimeEntryCategoryTotal, error) {
+	var totals []TimeEntryCategoryTotal
+	if len(userIDs) == 0 {
+		return totals, nil
+	}
+	err := database.DB.Model(&models.TimeEntry{}).
+		Select("user_id, date, work_category, billable, SUM(time_spent) AS minutes").
+		Where("user_id IN ? AND date >= ? AND date <= ?", userIDs, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("user_id, date, work_category, billable").
+		Scan(&totals).Error
+	return totals, err
+}
+
 // ValidateByTicketID marque comme validées toutes les entrées de temps non encore validées du ticket
 func (r *timeEntryRepository) ValidateByTicketID(ticketID uint, validatedByID uint) error {
 	now := time.Now()
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+)
+
+// TouchedTicket ticket sur lequel un utilisateur est intervenu pendant une période
+type TouchedTicket struct {
+	TicketID     uint
+	Code         string
+	Title        string
+	Status       string
+	Actions      int       // Interventions (historique, commentaires, saisies de temps)
+	LastActionAt time.Time // Dernière intervention
+}
+
+// TimeLoggedTotals temps saisi par un utilisateur sur une période (minutes)
+type TimeLoggedTotals struct {
+	Entries   int
+	Total     int
+	Validated int
+}
+
+// UserActivityRepository agrège l'activité d'un utilisateur sur une période (bornes [from, to[)
+type UserActivityRepository interface {
+	IsUserVisible(scope interface{}, userID uint) (bool, error) // scope peut être *scope.QueryScope ou nil
+	FindTouchedTickets(userID uint, from, to time.Time) ([]TouchedTicket, error)
+	CountTicketsCreated(userID uint, from, to time.Time) (int64, error)
+	CountTicketsResolved(userID uint, from, to time.Time) (int64, error)
+	CountComments(userID uint, from, to time.Time) (tickets int64, tasks int64, err error)
+	SumTimeLogged(userID uint, from, to time.Time) (*TimeLoggedTotals, error)
+	CountTasksCompleted(userID uint, from, to time.Time) (int64, error)
+	CountLogins(userID uint, from, to time.Time) (int64, error)
+}
+
+// userActivityRepository implémente UserActivityRepository
+type userActivityRepository struct{}
+
+// NewUserActivityRepository crée une nouvelle instance de UserActivityRepository
+func NewUserActivityRepository() UserActivityRepository {
+	return &userActivityRepository{}
+}
+
+// IsUserVisible indique si l'utilisateur fait partie du périmètre de visibilité des utilisateurs
+func (r *userActivityRepository) IsUserVisible(scopeParam interface{}, userID uint) (bool, error) {
+	var count int64
+	query := database.DB.Model(&models.User{}).Where("users.id = ?", userID)
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		query = scope.ApplyUserScope(query, queryScope)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// FindTouchedTickets récupère les tickets sur lesquels l'utilisateur est intervenu (plus récents d'abord)
+// Une intervention est une entrée d'historique, un commentaire ou une saisie de temps
+func (r *userActivityRepository) FindTouchedTickets(userID uint, from, to time.Time) ([]TouchedTicket, error) {
+	var tickets []TouchedTicket
+	err := database.DB.Raw(`
+		SELECT t.id AS ticket_id, t.code, t.title, t.status, COUNT(*) AS actions, MAX(a.acted_at) AS last_action_at
+		FROM (
+			SELECT ticket_id, created_at AS acted_at FROM ticket_history
+			WHERE user_id = ? AND created_at >= ? AND created_at < ?
+			UNION ALL
+			SELECT ticket_id, created_at FROM ticket_comments
+			WHERE user_id = ? AND created_at >= ? AND created_at < ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT ticket_id, created_at FROM time_entries
+			WHERE user_id = ? AND ticket_id IS NOT NULL AND date >= ? AND date < ? AND deleted_at IS NULL
+		) a
+		JOIN tickets t ON t.id = a.ticket_id AND t.deleted_at IS NULL
+		GROUP BY t.id, t.code, t.title, t.status
+		ORDER BY last_action_at DESC`,
+		userID, from, to, userID, from, to, userID, from, to,
+	).Scan(&tickets).Error
+	return tickets, err
+}
+
+// CountTicketsCreated compte les tickets créés par l'utilisateur
+func (r *userActivityRepository) CountTicketsCreated(userID uint, from, to time.Time) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.Ticket{}).
+		Where("created_by_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Count(&count).Error
+	return count, err
+}
+
+// CountTicketsResolved compte les tickets passés à résolu ou clôturé par l'utilisateur
+func (r *userActivityRepository) CountTicketsResolved(userID uint, from, to time.Time) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.TicketHistory{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Where("action = ? AND field_name = ? AND new_value IN ?", "status_changed", "status", []string{"resolu", "cloture"}).
+		Distinct("ticket_id").
+		Count(&count).Error
+	return count, err
+}
+
+// CountComments compte les commentaires de l'utilisateur sur les tickets et sur les tâches de projet
+func (r *userActivityRepository) CountComments(userID uint, from, to time.Time) (int64, int64, error) {
+	var tickets, tasks int64
+	if err := database.DB.Model(&models.TicketComment{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Count(&tickets).Error; err != nil {
+		return 0, 0, err
+	}
+	err := database.DB.Model(&models.ProjectTaskComment{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Count(&tasks).Error
+	return tickets, tasks, err
+}
+
+// SumTimeLogged totalise le temps saisi par l'utilisateur (date de la saisie)
+func (r *userActivityRepository) SumTimeLogged(userID uint, from, to time.Time) (*TimeLoggedTotals, error) {
+	var totals TimeLoggedTotals
+	err := database.DB.Model(&models.TimeEntry{}).
+		Select("COUNT(*) AS entries, COALESCE(SUM(time_spent), 0) AS total, COALESCE(SUM(CASE WHEN validated THEN time_spent ELSE 0 END), 0) AS validated").
+		Where("user_id = ? AND date >= ? AND date < ?", userID, from, to).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// CountTasksCompleted compte les tâches de projet clôturées dont l'utilisateur est responsable ou assigné
+func (r *userActivityRepository) CountTasksCompleted(userID uint, from, to time.Time) (int64, error) {
+	var count int64
+	assigned := database.DB.Model(&models.ProjectTaskAssignee{}).Select("project_task_id").Where("user_id = ?", userID)
+	err := database.DB.Model(&models.ProjectTask{}).
+		Where("status = ? AND closed_at >= ? AND closed_at < ?", "cloture", from, to).
+		Where("assigned_to_id = ? OR id IN (?)", userID, assigned).
+		Count(&count).Error
+	return count, err
+}
+
+// CountLogins compte les connexions de l'utilisateur (journal d'audit)
+func (r *userActivityRepository) CountLogins(userID uint, from, to time.Time) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.AuditLog{}).
+		Where("user_id = ? AND action = ? AND created_at >= ? AND created_at < ?", userID, events.UserLoggedIn, from, to).
+		Count(&count).Error
+	return count, err
+}
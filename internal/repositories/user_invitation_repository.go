@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// UserInvitationRepository interface pour les opérations sur les invitations de comptes
+type UserInvitationRepository interface {
+	Create(invitation *models.UserInvitation) error
+	FindByTokenHash(tokenHash string) (*models.UserInvitation, error)
+	Update(invitation *models.UserInvitation) error
+}
+
+// userInvitationRepository implémente UserInvitationRepository
+type userInvitationRepository struct{}
+
+// NewUserInvitationRepository crée une nouvelle instance de UserInvitationRepository
+func NewUserInvitationRepository() UserInvitationRepository {
+	return &userInvitationRepository{}
+}
+
+// Create crée une nouvelle invitation
+func (r *userInvitationRepository) Create(invitation *models.UserInvitation) error {
+	return database.DB.Create(invitation).Error
+}
+
+// FindByTokenHash trouve une invitation par le hash de son token
+func (r *userInvitationRepository) FindByTokenHash(tokenHash string) (*models.UserInvitation, error) {
+	var invitation models.UserInvitation
+	err := database.DB.Preload("User").Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// Update met à jour une invitation (sans toucher à l'utilisateur associé)
+func (r *userInvitationRepository) Update(invitation *models.UserInvitation) error {
+	return database.DB.Omit(clause.Associations).Save(invitation).Error
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/cache"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserPreferenceRepository interface pour les opérations sur les préférences utilisateur
+type UserPreferenceRepository interface {
+	FindByUserID(userID uint) (*models.UserPreference, error)       // nil, nil si aucune préférence enregistrée
+	FindByUserIDCached(userID uint) (*models.UserPreference, error) // Version mise en cache (lue à chaque requête et notification)
+	Save(preference *models.UserPreference) error
+}
+
+// userPreferenceRepository implémente UserPreferenceRepository
+type userPreferenceRepository struct{}
+
+// NewUserPreferenceRepository crée une nouvelle instance de UserPreferenceRepository
+func NewUserPreferenceRepository() UserPreferenceRepository {
+	return &userPreferenceRepository{}
+}
+
+// userPreferenceCacheKey retourne la clé de cache des préférences d'un utilisateur
+func userPreferenceCacheKey(userID uint) string {
+	return fmt.Sprintf("user_pref:%d", userID)
+}
+
+// FindByUserID récupère les préférences d'un utilisateur
+func (r *userPreferenceRepository) FindByUserID(userID uint) (*models.UserPreference, error) {
+	var preference models.UserPreference
+	err := database.DB.Where("user_id = ?", userID).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// FindByUserIDCached récupère les préférences d'un utilisateur en passant par le cache
+func (r *userPreferenceRepository) FindByUserIDCached(userID uint) (*models.UserPreference, error) {
+	return cache.GetOrLoad(cache.Shared, userPreferenceCacheKey(userID), userCacheTTL, func() (*models.UserPreference, error) {
+		return r.FindByUserID(userID)
+	})
+}
+
+// Save crée ou met à jour les préférences d'un utilisateur (tous les champs, y compris les valeurs nulles)
+func (r *userPreferenceRepository) Save(preference *models.UserPreference) error {
+	if err := database.DB.Omit(clause.Associations).Save(preference).Error; err != nil {
+		return err
+	}
+	cache.Shared.Delete(userPreferenceCacheKey(preference.UserID))
+	return nil
+}
@@ -2,10 +2,12 @@ package repositories
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/cache"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/scope"
 	"gorm.io/gorm"
@@ -15,6 +17,7 @@ import (
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByID(id uint) (*models.User, error)
+	FindByIDCached(id uint) (*models.User, error) // Version mise en cache (courte durée) pour l'authentification
 	CountByIDs(ids []uint) (int64, error)
 	FindByUsername(username string) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
@@ -24,10 +27,35 @@ type UserRepository interface {
 	Search(scope interface{}, query string, limit int) ([]models.User, error) // scope peut être *scope.QueryScope ou nil
 	CountByRole(roleID uint, count *int64) error
 	Update(user *models.User) error
+	UpdatePassword(userID uint, passwordHash string) error
+	FindByIDs(ids []uint) ([]models.User, error)
+	FindByManagerIDs(managerIDs []uint) ([]models.User, error)
+	FindActiveByPermission(code string, filialeID *uint) ([]models.User, error) // Utilisateurs dont le rôle a la permission (filiale donnée ou sans filiale)
+	FindActiveIDsByFiliale(filialeID *uint) ([]uint, error)                     // Utilisateurs actifs de la filiale ou sans filiale (tous si filialeID est nil)
+	FindTeamMemberIDs(managerID uint) ([]uint, error)
+	FindTeamMemberIDsCached(managerID uint) ([]uint, error) // Version mise en cache pour le scope de chaque requête
+	FindApprovalTeamIDsCached(userID uint) ([]uint, error)  // Équipe + équipes des responsables absents suppléés (scope de chaque requête)
+	UpdateOutOfOffice(userID uint, from, until *time.Time, backupUserID *uint, message string) error
 	Delete(id uint) error
 	UpdateLastLogin(userID uint) error
+	InvalidateCache(ids ...uint) // Après une modification hors repository (transaction de départ d'un utilisateur)
 }
 
+// userCacheTTL durée de vie des utilisateurs en cache (rôle, département, filiale)
+const userCacheTTL = time.Minute
+
+// userCacheKey retourne la clé de cache d'un utilisateur
+func userCacheKey(id uint) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// userTeamCachePrefix préfixe les clés de cache des équipes hiérarchiques
+// Un changement de responsable modifie l'équipe de toute la chaîne : tout le préfixe est invalidé
+const userTeamCachePrefix = "user_team:"
+
+// userTeamMaxDepth borne le parcours de la hiérarchie (protection contre un cycle en base)
+const userTeamMaxDepth = 10
+
 // userRepository implémente UserRepository
 type userRepository struct{}
 
@@ -45,7 +73,13 @@ func applyUserPreloads(query *gorm.DB) *gorm.DB {
 
 // Create crée un nouvel utilisateur
 func (r *userRepository) Create(user *models.User) error {
-	return database.DB.Create(user).Error
+	if err := database.DB.Create(user).Error; err != nil {
+		return err
+	}
+	if user.ManagerID != nil {
+		cache.Shared.DeletePrefix(userTeamCachePrefix)
+	}
+	return nil
 }
 
 // FindByID trouve un utilisateur par son ID avec son rôle et département
@@ -59,6 +93,23 @@ func (r *userRepository) FindByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByIDCached trouve un utilisateur par son ID en passant par le cache
+// Utilisé par le middleware d'authentification, appelé à chaque requête
+// Retourne une copie : la valeur en cache ne doit pas être modifiée par l'appelant
+func (r *userRepository) FindByIDCached(id uint) (*models.User, error) {
+	user, err := cache.GetOrLoad(cache.Shared, userCacheKey(id), userCacheTTL, func() (models.User, error) {
+		found, err := r.FindByID(id)
+		if err != nil {
+			return models.User{}, err
+		}
+		return *found, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // CountByIDs compte les utilisateurs par IDs (requête légère)
 func (r *userRepository) CountByIDs(ids []uint) (int64, error) {
 	if len(ids) == 0 {
@@ -186,9 +237,6 @@ func (r *userRepository) CountByRole(roleID uint, count *int64) error {
 
 // Update met à jour un utilisateur
 func (r *userRepository) Update(user *models.User) error {
-	// Log pour déboguer
-	fmt.Printf("Repository Update - User ID: %d, RoleID à sauvegarder: %d\n", user.ID, user.RoleID)
-	
 	// Utiliser Where + Updates pour forcer la mise à jour de tous les champs, y compris role_id
 	// Cela évite que GORM ignore role_id si Role est préchargé
 	// On utilise Omit pour exclure les champs qu'on ne veut pas mettre à jour
@@ -203,28 +251,186 @@ func (r *userRepository) Update(user *models.User) error {
 			"phone":         user.Phone,
 			"avatar":        user.Avatar,
 			"department_id": user.DepartmentID,
+			"manager_id":    user.ManagerID,
 			"role_id":       user.RoleID, // Forcer la mise à jour du role_id
 			"is_active":     user.IsActive,
 			"updated_by_id": user.UpdatedByID,
 			"updated_at":    time.Now(),
 		}).Error
-	
 	if err != nil {
-		fmt.Printf("Repository Update - Erreur: %v\n", err)
+		slog.Error("user_repository_update_failed", "user_id", user.ID, "error", err)
 		return err
 	}
-	
-	// Vérifier que le role_id a bien été mis à jour
-	var checkUser models.User
-	database.DB.Select("id", "role_id").First(&checkUser, user.ID)
-	fmt.Printf("Repository Update - Après mise à jour, RoleID dans la DB: %d\n", checkUser.RoleID)
-	
+	cache.Shared.Delete(userCacheKey(user.ID))
+	cache.Shared.DeletePrefix(userTeamCachePrefix)
+
+	return nil
+}
+
+// UpdatePassword remplace le hash du mot de passe (exclu de Update)
+func (r *userRepository) UpdatePassword(userID uint, passwordHash string) error {
+	err := database.DB.Model(&models.User{}).Where("id = ?", userID).
+		Updates(map[string]interface{}{"password_hash": passwordHash, "updated_at": time.Now()}).Error
+	if err != nil {
+		return err
+	}
+	cache.Shared.Delete(userCacheKey(userID))
+	return nil
+}
+
+// FindByIDs récupère des utilisateurs par leurs IDs
+func (r *userRepository) FindByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := applyUserPreloads(database.DB.Model(&models.User{})).
+		Where("id IN ?", ids).
+		Order("last_name, first_name").
+		Find(&users).Error
+	return users, err
+}
+
+// FindByManagerIDs récupère les collaborateurs actifs directement rattachés à l'un des responsables
+func (r *userRepository) FindByManagerIDs(managerIDs []uint) ([]models.User, error) {
+	var users []models.User
+	if len(managerIDs) == 0 {
+		return users, nil
+	}
+	err := applyUserPreloads(database.DB.Model(&models.User{})).
+		Where("manager_id IN ? AND is_active = ?", managerIDs, true).
+		Order("last_name, first_name").
+		Find(&users).Error
+	return users, err
+}
+
+// FindActiveByPermission récupère les utilisateurs actifs dont le rôle accorde la permission
+// Avec filialeID, seuls les utilisateurs de cette filiale ou sans filiale sont retenus
+func (r *userRepository) FindActiveByPermission(code string, filialeID *uint) ([]models.User, error) {
+	var users []models.User
+	query := database.DB.Model(&models.User{}).
+		Joins("JOIN role_permissions ON role_permissions.role_id = users.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("permissions.code = ? AND users.is_active = ?", code, true)
+	if filialeID != nil {
+		query = query.Where("users.filiale_id = ? OR users.filiale_id IS NULL", *filialeID)
+	}
+	err := query.Order("users.last_name, users.first_name").Find(&users).Error
+	return users, err
+}
+
+// FindActiveIDsByFiliale récupère les IDs des utilisateurs actifs de la filiale ou sans filiale (tous si filialeID est nil)
+func (r *userRepository) FindActiveIDsByFiliale(filialeID *uint) ([]uint, error) {
+	var ids []uint
+	query := database.DB.Model(&models.User{}).Where("is_active = ?", true)
+	if filialeID != nil {
+		query = query.Where("filiale_id = ? OR filiale_id IS NULL", *filialeID)
+	}
+	err := query.Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindTeamMemberIDs récupère les IDs des collaborateurs actifs d'un responsable (rattachements directs et indirects)
+func (r *userRepository) FindTeamMemberIDs(managerID uint) ([]uint, error) {
+	var team []uint
+	visited := map[uint]bool{managerID: true}
+	level := []uint{managerID}
+	for depth := 0; depth < userTeamMaxDepth && len(level) > 0; depth++ {
+		var ids []uint
+		if err := database.DB.Model(&models.User{}).
+			Where("manager_id IN ? AND is_active = ?", level, true).
+			Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+		level = level[:0:0]
+		for _, id := range ids {
+			if !visited[id] {
+				visited[id] = true
+				team = append(team, id)
+				level = append(level, id)
+			}
+		}
+	}
+	return team, nil
+}
+
+// FindTeamMemberIDsCached récupère l'équipe d'un responsable en passant par le cache
+// Utilisé par le middleware d'authentification, appelé à chaque requête
+func (r *userRepository) FindTeamMemberIDsCached(managerID uint) ([]uint, error) {
+	key := fmt.Sprintf("%s%d", userTeamCachePrefix, managerID)
+	return cache.GetOrLoad(cache.Shared, key, userCacheTTL, func() ([]uint, error) {
+		return r.FindTeamMemberIDs(managerID)
+	})
+}
+
+// FindApprovalTeamIDsCached récupère les collaborateurs dont l'utilisateur valide le travail :
+// sa propre équipe et celles des responsables actuellement absents dont il est le suppléant
+func (r *userRepository) FindApprovalTeamIDsCached(userID uint) ([]uint, error) {
+	key := fmt.Sprintf("%sapproval:%d", userTeamCachePrefix, userID)
+	return cache.GetOrLoad(cache.Shared, key, userCacheTTL, func() ([]uint, error) {
+		team, err := r.FindTeamMemberIDsCached(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		var absentIDs []uint
+		if err := database.DB.Model(&models.User{}).
+			Where("backup_user_id = ? AND out_of_office_from <= ? AND out_of_office_until > ?", userID, now, now).
+			Pluck("id", &absentIDs).Error; err != nil {
+			return nil, err
+		}
+		if len(absentIDs) == 0 {
+			return team, nil
+		}
+
+		seen := make(map[uint]bool, len(team))
+		merged := append([]uint{}, team...)
+		for _, id := range team {
+			seen[id] = true
+		}
+		for _, absentID := range absentIDs {
+			absentTeam, err := r.FindTeamMemberIDsCached(absentID)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range absentTeam {
+				if !seen[id] && id != userID {
+					seen[id] = true
+					merged = append(merged, id)
+				}
+			}
+		}
+		return merged, nil
+	})
+}
+
+// UpdateOutOfOffice déclare (ou efface avec des valeurs nulles) l'absence d'un utilisateur
+func (r *userRepository) UpdateOutOfOffice(userID uint, from, until *time.Time, backupUserID *uint, message string) error {
+	err := database.DB.Model(&models.User{}).Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"out_of_office_from":    from,
+			"out_of_office_until":   until,
+			"backup_user_id":        backupUserID,
+			"out_of_office_message": message,
+			"updated_at":            time.Now(),
+		}).Error
+	if err != nil {
+		return err
+	}
+	cache.Shared.Delete(userCacheKey(userID))
+	cache.Shared.DeletePrefix(userTeamCachePrefix)
 	return nil
 }
 
 // Delete supprime un utilisateur (soft delete)
 func (r *userRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.User{}, id).Error
+	if err := database.DB.Delete(&models.User{}, id).Error; err != nil {
+		return err
+	}
+	cache.Shared.Delete(userCacheKey(id))
+	cache.Shared.DeletePrefix(userTeamCachePrefix)
+	return nil
 }
 
 // UpdateLastLogin met à jour la date de dernière connexion
@@ -233,3 +439,11 @@ func (r *userRepository) UpdateLastLogin(userID uint) error {
 	return database.DB.Model(&models.User{}).Where("id = ?", userID).Update("last_login", now).Error
 }
 
+
+// InvalidateCache retire des utilisateurs du cache ainsi que les équipes hiérarchiques
+func (r *userRepository) InvalidateCache(ids ...uint) {
+	for _, id := range ids {
+		cache.Shared.Delete(userCacheKey(id))
+	}
+	cache.Shared.DeletePrefix(userTeamCachePrefix)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
 )
 
 // UserSessionRepository interface pour les opérations sur les sessions utilisateurs
@@ -83,20 +84,30 @@ func (r *userSessionRepository) Update(session *models.UserSession) error {
 	return database.DB.Save(session).Error
 }
 
-// Delete supprime une session
+// Delete supprime une session et les appareils push enregistrés depuis celle-ci
 func (r *userSessionRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.UserSession{}, id).Error
+	return r.deleteWhere("id = ?", id)
 }
 
 // DeleteExpired supprime toutes les sessions expirées
 func (r *userSessionRepository) DeleteExpired() error {
 	now := time.Now()
-	return database.DB.Where("expires_at <= ?", now).Delete(&models.UserSession{}).Error
+	return r.deleteWhere("expires_at <= ?", now)
 }
 
 // DeleteByUserID supprime toutes les sessions d'un utilisateur
 func (r *userSessionRepository) DeleteByUserID(userID uint) error {
-	return database.DB.Where("user_id = ?", userID).Delete(&models.UserSession{}).Error
+	return r.deleteWhere("user_id = ?", userID)
+}
+
+// deleteWhere supprime les sessions correspondant à la condition, avec leurs appareils push
+func (r *userSessionRepository) deleteWhere(query string, args ...interface{}) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := deleteSessionPushDevices(tx, query, args...); err != nil {
+			return err
+		}
+		return tx.Where(query, args...).Delete(&models.UserSession{}).Error
+	})
 }
 
 // UpdateLastActivity met à jour la dernière activité d'une session
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm/clause"
+)
+
+// UserSkillMatch résultat agrégé d'une recherche d'utilisateurs par compétences
+type UserSkillMatch struct {
+	UserID       uint
+	MatchedCount int // Nombre de compétences demandées détenues au niveau requis
+	LevelTotal   int // Somme des niveaux sur ces compétences (départage)
+}
+
+// UserSkillRepository interface pour les opérations sur les compétences des utilisateurs
+type UserSkillRepository interface {
+	FindByUserID(userID uint) ([]models.UserSkill, error)
+	FindByUserIDs(userIDs []uint, skillIDs []uint) ([]models.UserSkill, error)
+	Save(userSkill *models.UserSkill) error
+	Delete(userID, skillID uint) (bool, error)
+	SearchUsers(scope interface{}, skillIDs []uint, minLevel int, matchAll bool, limit int) ([]UserSkillMatch, error) // scope peut être *scope.QueryScope ou nil
+}
+
+// userSkillRepository implémente UserSkillRepository
+type userSkillRepository struct{}
+
+// NewUserSkillRepository crée une nouvelle instance de UserSkillRepository
+func NewUserSkillRepository() UserSkillRepository {
+	return &userSkillRepository{}
+}
+
+// FindByUserID récupère le profil de compétences d'un utilisateur
+func (r *userSkillRepository) FindByUserID(userID uint) ([]models.UserSkill, error) {
+	var userSkills []models.UserSkill
+	err := database.DB.Preload("Skill").
+		Joins("JOIN skills ON skills.id = user_skills.skill_id").
+		Where("user_skills.user_id = ?", userID).
+		Order("skills.kind ASC, user_skills.level DESC, skills.name ASC").
+		Find(&userSkills).Error
+	return userSkills, err
+}
+
+// FindByUserIDs récupère les compétences de plusieurs utilisateurs (limitées à skillIDs si fourni)
+func (r *userSkillRepository) FindByUserIDs(userIDs []uint, skillIDs []uint) ([]models.UserSkill, error) {
+	var userSkills []models.UserSkill
+	if len(userIDs) == 0 {
+		return userSkills, nil
+	}
+	query := database.DB.Preload("Skill").Where("user_id IN ?", userIDs)
+	if len(skillIDs) > 0 {
+		query = query.Where("skill_id IN ?", skillIDs)
+	}
+	err := query.Find(&userSkills).Error
+	return userSkills, err
+}
+
+// Save crée ou met à jour la compétence d'un utilisateur (une ligne par utilisateur et compétence)
+func (r *userSkillRepository) Save(userSkill *models.UserSkill) error {
+	return database.DB.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "skill_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"level", "obtained_at", "expires_at", "reference", "updated_at"}),
+	}).Create(userSkill).Error
+}
+
+// Delete retire une compétence du profil d'un utilisateur
+func (r *userSkillRepository) Delete(userID, skillID uint) (bool, error) {
+	result := database.DB.Where("user_id = ? AND skill_id = ?", userID, skillID).Delete(&models.UserSkill{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SearchUsers recherche les utilisateurs actifs détenant les compétences demandées (niveau minimal, certifications non expirées)
+// Les résultats sont triés par nombre de compétences détenues puis par niveau cumulé
+func (r *userSkillRepository) SearchUsers(scopeParam interface{}, skillIDs []uint, minLevel int, matchAll bool, limit int) ([]UserSkillMatch, error) {
+	var matches []UserSkillMatch
+	if len(skillIDs) == 0 {
+		return matches, nil
+	}
+
+	users := database.DB.Model(&models.User{}).Select("users.id").Where("users.is_active = ?", true)
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		users = scope.ApplyUserScope(users, queryScope)
+	}
+
+	query := database.DB.Model(&models.UserSkill{}).
+		Select("user_id, COUNT(*) AS matched_count, SUM(level) AS level_total").
+		Where("skill_id IN ? AND level >= ?", skillIDs, minLevel).
+		Where("(expires_at IS NULL OR expires_at > ?)", time.Now()).
+		Where("user_id IN (?)", users).
+		Group("user_id")
+	if matchAll {
+		query = query.Having("COUNT(*) = ?", len(skillIDs))
+	}
+	err := query.Order("matched_count DESC, level_total DESC").Limit(limit).Scan(&matches).Error
+	return matches, err
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// WebhookRepository interface pour les opérations sur les abonnements webhook et leurs livraisons
+type WebhookRepository interface {
+	Create(subscription *models.WebhookSubscription) error
+	FindByID(id uint) (*models.WebhookSubscription, error)
+	FindAll() ([]models.WebhookSubscription, error)
+	FindActive() ([]models.WebhookSubscription, error)
+	Update(subscription *models.WebhookSubscription) error
+	Delete(id uint) error
+
+	CreateDelivery(delivery *models.WebhookDelivery) error
+	FindDeliveryByID(id uint) (*models.WebhookDelivery, error)
+	FindDeliveriesBySubscription(subscriptionID uint, status string, page, limit int) ([]models.WebhookDelivery, int64, error)
+	FindDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	ClaimDelivery(id uint) (bool, error) // Passe la livraison en "sending" si elle est en attente (évite les envois en double)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	PurgeDeliveries(before time.Time) (int64, error)
+}
+
+// webhookRepository implémente WebhookRepository
+type webhookRepository struct{}
+
+// NewWebhookRepository crée une nouvelle instance de WebhookRepository
+func NewWebhookRepository() WebhookRepository {
+	return &webhookRepository{}
+}
+
+// Create crée un nouvel abonnement
+func (r *webhookRepository) Create(subscription *models.WebhookSubscription) error {
+	return database.DB.Create(subscription).Error
+}
+
+// FindByID trouve un abonnement par son ID
+func (r *webhookRepository) FindByID(id uint) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := database.DB.Preload("Filiale").First(&subscription, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// FindAll récupère tous les abonnements
+func (r *webhookRepository) FindAll() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := database.DB.Preload("Filiale").Order("name ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// FindActive récupère les abonnements actifs
+func (r *webhookRepository) FindActive() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := database.DB.Where("is_active = ?", true).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Update met à jour un abonnement
+func (r *webhookRepository) Update(subscription *models.WebhookSubscription) error {
+	return database.DB.Omit("Filiale").Save(subscription).Error
+}
+
+// Delete supprime un abonnement et son journal de livraison
+func (r *webhookRepository) Delete(id uint) error {
+	if err := database.DB.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+		return err
+	}
+	return database.DB.Delete(&models.WebhookSubscription{}, id).Error
+}
+
+// CreateDelivery enregistre une livraison à effectuer
+func (r *webhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return database.DB.Create(delivery).Error
+}
+
+// FindDeliveryByID trouve une livraison par son ID
+func (r *webhookRepository) FindDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := database.DB.First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDeliveriesBySubscription récupère le journal de livraison d'un abonnement (plus récentes d'abord)
+func (r *webhookRepository) FindDeliveriesBySubscription(subscriptionID uint, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	query := database.DB.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// FindDueDeliveries récupère les livraisons à (re)tenter : en attente ou en réessai dont l'échéance est passée,
+// ainsi que les envois restés bloqués en "sending" (arrêt du serveur pendant l'envoi)
+func (r *webhookRepository) FindDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	stuckBefore := now.Add(-5 * time.Minute)
+	err := database.DB.
+		Where("(status IN ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)) OR (status = ? AND updated_at <= ?)",
+			[]string{"pending", "retrying"}, now, "sending", stuckBefore).
+		Order("id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery réserve une livraison pour envoi
+func (r *webhookRepository) ClaimDelivery(id uint) (bool, error) {
+	stuckBefore := time.Now().Add(-5 * time.Minute)
+	result := database.DB.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND (status IN ? OR (status = ? AND updated_at <= ?))", id, []string{"pending", "retrying"}, "sending", stuckBefore).
+		Updates(map[string]interface{}{"status": "sending", "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateDelivery met à jour une livraison
+func (r *webhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return database.DB.Omit("Subscription").Save(delivery).Error
+}
+
+// PurgeDeliveries supprime les livraisons terminées antérieures à la date donnée
+func (r *webhookRepository) PurgeDeliveries(before time.Time) (int64, error) {
+	result := database.DB.Where("created_at < ? AND status IN ?", before, []string{"success", "failed"}).Delete(&models.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -1,8 +1,12 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // WeeklyDeclarationRepository interface pour les opérations sur les déclarations hebdomadaires
@@ -14,6 +18,9 @@ type WeeklyDeclarationRepository interface {
 	FindValidated() ([]models.WeeklyDeclaration, error)
 	FindPendingValidation() ([]models.WeeklyDeclaration, error)
 	Update(declaration *models.WeeklyDeclaration) error
+	Transition(declaration *models.WeeklyDeclaration, fromStatus string, event *models.WeeklyDeclarationEvent) (bool, error)
+	FindEvents(declarationID uint) ([]models.WeeklyDeclarationEvent, error)
+	IsLocked(userID uint, date time.Time) (bool, error)
 	Delete(id uint) error
 }
 
@@ -78,7 +85,54 @@ func (r *weeklyDeclarationRepository) Update(declaration *models.WeeklyDeclarati
 	return database.DB.Save(declaration).Error
 }
 
-// Delete supprime une déclaration (soft delete)
+// Transition applique un changement de statut et enregistre l'événement correspondant, si la déclaration est
+// toujours dans le statut fromStatus ; false si elle a changé de statut entre-temps
+func (r *weeklyDeclarationRepository) Transition(declaration *models.WeeklyDeclaration, fromStatus string, event *models.WeeklyDeclarationEvent) (bool, error) {
+	applied := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WeeklyDeclaration{}).
+			Where("id = ? AND status = ?", declaration.ID, fromStatus).
+			Select("status", "submitted_at", "validated", "validated_by_id", "validated_at", "validation_comment").
+			Updates(declaration)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		applied = true
+		return tx.Omit(clause.Associations).Create(event).Error
+	})
+	return applied, err
+}
+
+// FindEvents récupère l'historique des changements de statut d'une déclaration, du plus ancien au plus récent
+func (r *weeklyDeclarationRepository) FindEvents(declarationID uint) ([]models.WeeklyDeclarationEvent, error) {
+	var events []models.WeeklyDeclarationEvent
+	err := database.DB.Preload("PerformedBy").Where("declaration_id = ?", declarationID).
+		Order("created_at ASC, id ASC").Find(&events).Error
+	return events, err
+}
+
+// IsLocked indique si la date tombe dans une semaine dont la déclaration de l'utilisateur est approuvée
+func (r *weeklyDeclarationRepository) IsLocked(userID uint, date time.Time) (bool, error) {
+	var count int64
+	day := date.Format("2006-01-02")
+	err := database.DB.Model(&models.WeeklyDeclaration{}).
+		Where("user_id = ? AND status = ? AND start_date <= ? AND end_date >= ?", userID, models.WeeklyDeclarationStatusApproved, day, day).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Delete supprime une déclaration, ses tâches et son historique
 func (r *weeklyDeclarationRepository) Delete(id uint) error {
-	return database.DB.Delete(&models.WeeklyDeclaration{}, id).Error
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("declaration_id = ?", id).Delete(&models.WeeklyDeclarationEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("declaration_id = ?", id).Delete(&models.WeeklyDeclarationTask{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WeeklyDeclaration{}, id).Error
+	})
 }
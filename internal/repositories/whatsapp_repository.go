@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// WhatsAppRepository interface pour les opérations sur les modèles de messages WhatsApp et les consentements
+type WhatsAppRepository interface {
+	CreateTemplate(template *models.WhatsAppTemplate) error
+	FindTemplateByID(id uint) (*models.WhatsAppTemplate, error)
+	FindTemplateByType(notificationType string) (*models.WhatsAppTemplate, error)
+	FindTemplates() ([]models.WhatsAppTemplate, error)
+	UpdateTemplate(template *models.WhatsAppTemplate) error
+	DeleteTemplate(id uint) error
+	SaveOptIn(optIn *models.WhatsAppOptIn) error
+	FindOptInByUserID(userID uint) (*models.WhatsAppOptIn, error)
+	DeleteOptInByUserID(userID uint) error
+}
+
+// whatsAppRepository implémente WhatsAppRepository
+type whatsAppRepository struct{}
+
+// NewWhatsAppRepository crée une nouvelle instance de WhatsAppRepository
+func NewWhatsAppRepository() WhatsAppRepository {
+	return &whatsAppRepository{}
+}
+
+// CreateTemplate crée un modèle de message
+func (r *whatsAppRepository) CreateTemplate(template *models.WhatsAppTemplate) error {
+	return database.DB.Create(template).Error
+}
+
+// FindTemplateByID trouve un modèle par son ID
+func (r *whatsAppRepository) FindTemplateByID(id uint) (*models.WhatsAppTemplate, error) {
+	var template models.WhatsAppTemplate
+	if err := database.DB.First(&template, id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindTemplateByType trouve le modèle d'un type de notification
+func (r *whatsAppRepository) FindTemplateByType(notificationType string) (*models.WhatsAppTemplate, error) {
+	var template models.WhatsAppTemplate
+	if err := database.DB.Where("notification_type = ?", notificationType).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindTemplates récupère tous les modèles
+func (r *whatsAppRepository) FindTemplates() ([]models.WhatsAppTemplate, error) {
+	var templates []models.WhatsAppTemplate
+	err := database.DB.Order("notification_type").Find(&templates).Error
+	return templates, err
+}
+
+// UpdateTemplate met à jour un modèle
+func (r *whatsAppRepository) UpdateTemplate(template *models.WhatsAppTemplate) error {
+	return database.DB.Save(template).Error
+}
+
+// DeleteTemplate supprime un modèle
+func (r *whatsAppRepository) DeleteTemplate(id uint) error {
+	return database.DB.Delete(&models.WhatsAppTemplate{}, id).Error
+}
+
+// SaveOptIn crée ou met à jour le consentement d'un utilisateur
+func (r *whatsAppRepository) SaveOptIn(optIn *models.WhatsAppOptIn) error {
+	return database.DB.Save(optIn).Error
+}
+
+// FindOptInByUserID trouve le consentement d'un utilisateur
+func (r *whatsAppRepository) FindOptInByUserID(userID uint) (*models.WhatsAppOptIn, error) {
+	var optIn models.WhatsAppOptIn
+	if err := database.DB.Where("user_id = ?", userID).First(&optIn).Error; err != nil {
+		return nil, err
+	}
+	return &optIn, nil
+}
+
+// DeleteOptInByUserID supprime le consentement d'un utilisateur
+func (r *whatsAppRepository) DeleteOptInByUserID(userID uint) error {
+	return database.DB.Where("user_id = ?", userID).Delete(&models.WhatsAppOptIn{}).Error
+}
@@ -0,0 +1,75 @@
+package richtext
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxEmbeddedImages nombre maximal d'images intégrées extraites d'un contenu
+const MaxEmbeddedImages = 20
+
+// ErrTooManyImages contenu contenant trop d'images intégrées
+var ErrTooManyImages = fmt.Errorf("trop d'images intégrées (maximum %d)", MaxEmbeddedImages)
+
+// embeddedImagePattern image intégrée en data URI (attribut src HTML ou cible d'image Markdown)
+var embeddedImagePattern = regexp.MustCompile(`data:image/(png|jpeg|gif|webp);base64,([A-Za-z0-9+/=]+)`)
+
+// imageExtensions extension de fichier par sous-type d'image
+var imageExtensions = map[string]string{"png": ".png", "jpeg": ".jpg", "gif": ".gif", "webp": ".webp"}
+
+// Image image intégrée décodée
+type Image struct {
+	Name     string // Nom de fichier généré (image-1.png, image-2.jpg, ...)
+	MimeType string
+	Data     []byte
+}
+
+// ImageStore enregistre une image intégrée et retourne l'URL qui la remplace dans le contenu
+type ImageStore func(image Image) (string, error)
+
+// ExtractImages remplace les images intégrées (data URI) d'un contenu HTML ou Markdown par l'URL retournée par store
+// Le texte brut est retourné tel quel ; une même image présente plusieurs fois n'est enregistrée qu'une fois
+func ExtractImages(content, format string, store ImageStore) (string, error) {
+	format = NormalizeFormat(format)
+	if format != FormatHTML && format != FormatMarkdown {
+		return content, nil
+	}
+	matches := embeddedImagePattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, nil
+	}
+	var b strings.Builder
+	stored := map[string]string{}
+	last := 0
+	for _, match := range matches {
+		uri := content[match[0]:match[1]]
+		url, ok := stored[uri]
+		if !ok {
+			if len(stored) == MaxEmbeddedImages {
+				return "", ErrTooManyImages
+			}
+			subtype := content[match[2]:match[3]]
+			data, err := base64.StdEncoding.DecodeString(content[match[4]:match[5]])
+			if err != nil {
+				return "", errors.New("image intégrée invalide")
+			}
+			url, err = store(Image{
+				Name:     fmt.Sprintf("image-%d%s", len(stored)+1, imageExtensions[subtype]),
+				MimeType: "image/" + subtype,
+				Data:     data,
+			})
+			if err != nil {
+				return "", err
+			}
+			stored[uri] = url
+		}
+		b.WriteString(content[last:match[0]])
+		b.WriteString(url)
+		last = match[1]
+	}
+	b.WriteString(content[last:])
+	return b.String(), nil
+}
@@ -0,0 +1,409 @@
+package richtext
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Sous-ensemble Markdown supporté (GFM) : titres, paragraphes, emphase, code, citations, listes imbriquées,
+// tableaux, séparateurs, liens, images et liens automatiques ; le HTML brut est échappé
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	rulePattern        = regexp.MustCompile(`^ {0,3}([-*_])(\s*([-*_]))+\s*$`)
+	fencePattern       = regexp.MustCompile("^ {0,3}(```+|~~~+)\\s*([A-Za-z0-9_+-]*)")
+	bulletPattern      = regexp.MustCompile(`^ {0,3}[-*+]\s+`)
+	orderedPattern     = regexp.MustCompile(`^ {0,3}(\d{1,9})[.)]\s+`)
+	tableDelimiterLine = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	bareURLPattern     = regexp.MustCompile(`^https?://[^\s<>"']*[^\s<>"'.,;:!?)\]]`)
+)
+
+// RenderMarkdown convertit du Markdown en HTML (à assainir par Sanitize avant affichage)
+func RenderMarkdown(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(source, "\n"))
+	return b.String()
+}
+
+// renderBlocks rend une suite de lignes en blocs
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+		case fencePattern.MatchString(line):
+			i = renderFence(b, lines, i)
+		case headingPattern.MatchString(trimmed):
+			match := headingPattern.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(match[1])))
+			b.WriteString("<h" + level + ">" + renderInline(match[2]) + "</h" + level + ">")
+			i++
+		case rulePattern.MatchString(line) && ruleCharsMatch(trimmed):
+			b.WriteString("<hr>")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				content := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(content, " "))
+			}
+			b.WriteString("<blockquote>")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>")
+		case bulletPattern.MatchString(line) || orderedPattern.MatchString(line):
+			i = renderList(b, lines, i)
+		case i+1 < len(lines) && strings.Contains(line, "|") && tableDelimiterLine.MatchString(lines[i+1]):
+			i = renderTable(b, lines, i)
+		default:
+			i = renderParagraph(b, lines, i)
+		}
+	}
+}
+
+// ruleCharsMatch vérifie qu'un séparateur n'utilise qu'un seul caractère (---, ***, ___)
+func ruleCharsMatch(trimmed string) bool {
+	compact := strings.ReplaceAll(trimmed, " ", "")
+	return len(compact) >= 3 && strings.Count(compact, compact[:1]) == len(compact)
+}
+
+// renderFence rend un bloc de code délimité (``` ou ~~~) jusqu'à sa clôture ou la fin du contenu
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	match := fencePattern.FindStringSubmatch(lines[i])
+	fence := match[1]
+	b.WriteString("<pre><code")
+	if match[2] != "" {
+		b.WriteString(` class="language-` + match[2] + `"`)
+	}
+	b.WriteString(">")
+	var code []string
+	for i++; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	b.WriteString("</code></pre>")
+	return i
+}
+
+// renderList rend une liste (à puces ou numérotée) ; les lignes indentées appartiennent à l'élément courant
+func renderList(b *strings.Builder, lines []string, i int) int {
+	ordered := !bulletPattern.MatchString(lines[i])
+	marker := bulletPattern
+	tag := "ul"
+	if ordered {
+		marker, tag = orderedPattern, "ol"
+		if start := orderedPattern.FindStringSubmatch(lines[i])[1]; strings.TrimLeft(start, "0") != "1" {
+			b.WriteString(`<ol start="` + strings.TrimLeft(start, "0") + `">`)
+		} else {
+			b.WriteString("<ol>")
+		}
+	} else {
+		b.WriteString("<ul>")
+	}
+
+	for i < len(lines) && marker.MatchString(lines[i]) {
+		item := []string{lines[i][len(marker.FindString(lines[i])):]}
+		loose := false
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// Ligne vide : l'élément continue seulement si la suite est indentée
+				if i+1 < len(lines) && indentation(lines[i+1]) >= 2 {
+					item = append(item, "")
+					loose = true
+					continue
+				}
+				break
+			}
+			if indentation(line) < 2 && (marker.MatchString(line) || startsBlock(line)) {
+				break
+			}
+			item = append(item, strings.TrimPrefix(line, strings.Repeat(" ", min(indentation(line), 4))))
+		}
+		b.WriteString("<li>")
+		if !loose && !containsBlock(item[1:]) {
+			b.WriteString(renderInline(strings.Join(item, "\n")))
+		} else {
+			renderListItem(b, item)
+		}
+		b.WriteString("</li>")
+		// Une ligne vide entre deux éléments de même type ne coupe pas la liste
+		if i < len(lines) && strings.TrimSpace(lines[i]) == "" && i+1 < len(lines) && marker.MatchString(lines[i+1]) {
+			i++
+		}
+	}
+	b.WriteString("</" + tag + ">")
+	return i
+}
+
+// renderListItem rend un élément contenant des blocs : le premier paragraphe reste en ligne
+func renderListItem(b *strings.Builder, item []string) {
+	first := 1
+	for first < len(item) && strings.TrimSpace(item[first]) != "" && !startsBlock(item[first]) {
+		first++
+	}
+	b.WriteString(renderInline(strings.Join(item[:first], "\n")))
+	renderBlocks(b, item[first:])
+}
+
+// containsBlock indique si des lignes de continuation ouvrent un bloc (sous-liste, code, citation, ...)
+func containsBlock(lines []string) bool {
+	for _, line := range lines {
+		if startsBlock(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// startsBlock indique si la ligne ouvre un bloc autre qu'un paragraphe
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return bulletPattern.MatchString(trimmed) || orderedPattern.MatchString(trimmed) ||
+		fencePattern.MatchString(trimmed) || headingPattern.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, ">") || (rulePattern.MatchString(trimmed) && ruleCharsMatch(trimmed))
+}
+
+// indentation nombre d'espaces en début de ligne
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// renderTable rend un tableau GFM (ligne d'en-tête, ligne de délimitation, lignes de données)
+func renderTable(b *strings.Builder, lines []string, i int) int {
+	header := tableCells(lines[i])
+	b.WriteString("<table><thead><tr>")
+	for _, cell := range header {
+		b.WriteString("<th>" + renderInline(cell) + "</th>")
+	}
+	b.WriteString("</tr></thead><tbody>")
+	for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+		cells := tableCells(lines[i])
+		b.WriteString("<tr>")
+		for j := range header {
+			cell := ""
+			if j < len(cells) {
+				cell = cells[j]
+			}
+			b.WriteString("<td>" + renderInline(cell) + "</td>")
+		}
+		b.WriteString("</tr>")
+	}
+	b.WriteString("</tbody></table>")
+	return i
+}
+
+// tableCells découpe une ligne de tableau en cellules (les "\|" restent dans la cellule)
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// renderParagraph rend un paragraphe jusqu'à une ligne vide ou un autre bloc ; les retours à la ligne sont conservés
+func renderParagraph(b *strings.Builder, lines []string, i int) int {
+	var paragraph []string
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" || (len(paragraph) > 0 && startsBlock(lines[i])) {
+			break
+		}
+		paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+	}
+	b.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>")
+	return i
+}
+
+// renderInline rend les éléments en ligne : échappements, code, images, liens, emphase et liens automatiques
+func renderInline(text string) string {
+	return renderInlineText(text, true)
+}
+
+// renderInlineText rend les éléments en ligne ; autolink désactivé dans le libellé d'un lien (pas de lien imbriqué)
+func renderInlineText(text string, autolink bool) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_{}[]()#+-.!|~<>", text[i+1]) >= 0:
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '\n':
+			b.WriteString("<br>")
+			i++
+			continue
+		case c == '`':
+			if end, code := codeSpan(text, i); end > i {
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i = end
+				continue
+			}
+		case c == '!' && strings.HasPrefix(text[i+1:], "["):
+			if end, alt, target := linkAt(text, i+1); end > 0 {
+				b.WriteString(`<img src="` + html.EscapeString(target) + `" alt="` + html.EscapeString(alt) + `">`)
+				i = end
+				continue
+			}
+		case c == '[':
+			if end, label, target := linkAt(text, i); end > 0 {
+				b.WriteString(`<a href="` + html.EscapeString(target) + `">` + renderInlineText(label, false) + "</a>")
+				i = end
+				continue
+			}
+		case c == '<' && autolink:
+			if end := strings.IndexByte(text[i:], '>'); end > 0 && bareURLPattern.MatchString(text[i+1:i+end]) {
+				target := text[i+1 : i+end]
+				b.WriteString(`<a href="` + html.EscapeString(target) + `">` + html.EscapeString(target) + "</a>")
+				i += end + 1
+				continue
+			}
+		case c == 'h' && autolink && (i == 0 || !isWordByte(text[i-1])):
+			if target := bareURLPattern.FindString(text[i:]); target != "" {
+				b.WriteString(`<a href="` + html.EscapeString(target) + `">` + html.EscapeString(target) + "</a>")
+				i += len(target)
+				continue
+			}
+		case c == '*' || c == '_' || c == '~':
+			if end, tag, inner := emphasis(text, i); end > i {
+				b.WriteString("<" + tag + ">" + renderInlineText(inner, autolink) + "</" + tag + ">")
+				i = end
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// codeSpan retourne la fin et le contenu d'un code en ligne ouvert à la position i (même nombre de `)
+func codeSpan(text string, i int) (int, string) {
+	n := 0
+	for i+n < len(text) && text[i+n] == '`' {
+		n++
+	}
+	delimiter := strings.Repeat("`", n)
+	end := strings.Index(text[i+n:], delimiter)
+	if end < 0 {
+		return i, ""
+	}
+	return i + n + end + n, strings.TrimSpace(text[i+n : i+n+end])
+}
+
+// linkAt lit un lien [libellé](cible "titre") ouvert à la position i ; end vaut 0 si la syntaxe est incomplète
+func linkAt(text string, i int) (end int, label, target string) {
+	depth := 0
+	closeLabel := -1
+	for j := i; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeLabel = j
+			}
+		}
+		if closeLabel >= 0 {
+			break
+		}
+	}
+	if closeLabel < 0 || closeLabel+1 >= len(text) || text[closeLabel+1] != '(' {
+		return 0, "", ""
+	}
+	closeTarget := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeTarget < 0 {
+		return 0, "", ""
+	}
+	target = strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeTarget])
+	// Titre optionnel après la cible : ignoré
+	if space := strings.IndexAny(target, " \t"); space > 0 {
+		target = target[:space]
+	}
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	return closeLabel + 3 + closeTarget, text[i+1 : closeLabel], target
+}
+
+// emphasis lit une emphase ouverte à la position i : ** ou __ (strong), * ou _ (em), ~~ (del)
+// Le "_" n'est pas une emphase à l'intérieur d'un mot (ex: nom_de_variable)
+func emphasis(text string, i int) (end int, tag, inner string) {
+	c := text[i]
+	delimiter := string(c)
+	tag = "em"
+	if strings.HasPrefix(text[i:], string([]byte{c, c})) {
+		delimiter = string([]byte{c, c})
+		tag = "strong"
+	}
+	if c == '~' {
+		if delimiter != "~~" {
+			return i, "", ""
+		}
+		tag = "del"
+	}
+	if c == '_' && i > 0 && isWordByte(text[i-1]) {
+		return i, "", ""
+	}
+	start := i + len(delimiter)
+	if start >= len(text) || text[start] == ' ' || text[start] == '\n' {
+		return i, "", ""
+	}
+	for j := start + 1; j <= len(text)-len(delimiter); j++ {
+		if text[j] == '\\' {
+			j++
+			continue
+		}
+		if text[j] == '`' {
+			if codeEnd, _ := codeSpan(text, j); codeEnd > j {
+				j = codeEnd - 1
+				continue
+			}
+		}
+		if !strings.HasPrefix(text[j:], delimiter) || text[j-1] == ' ' {
+			continue
+		}
+		// Simple délimiteur : ne pas confondre avec un double (ex: *a **b** c*)
+		if len(delimiter) == 1 && j+1 < len(text) && text[j+1] == c {
+			j++
+			continue
+		}
+		after := j + len(delimiter)
+		if c == '_' && after < len(text) && isWordByte(text[after]) {
+			continue
+		}
+		return after, tag, text[start:j]
+	}
+	return i, "", ""
+}
+
+// isWordByte indique si l'octet fait partie d'un mot (lettre ASCII, chiffre ou octet UTF-8 multi-octets)
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
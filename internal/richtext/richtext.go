@@ -0,0 +1,70 @@
+// Package richtext assainit et rend le contenu riche des tickets, commentaires et articles (texte, Markdown, HTML)
+package richtext
+
+import (
+	"html"
+	"strings"
+)
+
+// Formats de contenu acceptés
+const (
+	FormatText     = "text"     // Texte brut (échappé, retours à la ligne conservés)
+	FormatMarkdown = "markdown" // Markdown (sous-ensemble GFM), rendu côté serveur
+	FormatHTML     = "html"     // HTML assaini à l'enregistrement et au rendu
+)
+
+// NormalizeFormat retourne le format fourni ou le texte brut par défaut
+func NormalizeFormat(format string) string {
+	if format == "" {
+		return FormatText
+	}
+	return format
+}
+
+// ValidFormat indique si le format est supporté (vide = texte brut)
+func ValidFormat(format string) bool {
+	switch NormalizeFormat(format) {
+	case FormatText, FormatMarkdown, FormatHTML:
+		return true
+	}
+	return false
+}
+
+// Prepare normalise le contenu avant enregistrement : le HTML est assaini, le Markdown et le texte sont conservés tels quels
+func Prepare(content, format string) string {
+	if NormalizeFormat(format) == FormatHTML {
+		return Sanitize(content)
+	}
+	return content
+}
+
+// Render retourne le HTML sûr à afficher pour un contenu enregistré
+func Render(content, format string) string {
+	switch NormalizeFormat(format) {
+	case FormatHTML:
+		return Sanitize(content)
+	case FormatMarkdown:
+		return Sanitize(RenderMarkdown(content))
+	default:
+		return renderText(content)
+	}
+}
+
+// renderText rend un texte brut : paragraphes séparés par une ligne vide, retours à la ligne en <br>
+func renderText(content string) string {
+	content = strings.ReplaceAll(strings.TrimSpace(content), "\r\n", "\n")
+	if content == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, paragraph := range strings.Split(content, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
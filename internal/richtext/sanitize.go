@@ -0,0 +1,206 @@
+package richtext
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// allowedTags balises conservées et attributs autorisés pour chacune
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+	"b": nil, "strong": nil, "i": nil, "em": nil, "u": nil, "s": nil, "del": nil, "sub": nil, "sup": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"blockquote": nil, "pre": nil, "code": {"class"},
+	"a":     {"href", "title"},
+	"img":   {"src", "alt", "title", "width", "height"},
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil,
+	"th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+}
+
+// voidTags balises sans contenu ni balise fermante
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// inlineTags balises de mise en forme du texte (sans séparation dans le texte brut)
+var inlineTags = map[string]bool{
+	"a": true, "span": true, "b": true, "strong": true, "i": true, "em": true, "u": true, "s": true,
+	"del": true, "sub": true, "sup": true, "code": true,
+}
+
+// droppedTags balises supprimées avec tout leur contenu
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "textarea": true, "select": true, "title": true, "head": true, "svg": true, "math": true,
+}
+
+// linkSchemes schémas autorisés dans les liens ; les images acceptent en plus les data URI d'images matricielles
+var linkSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// dataImagePattern data URI d'image matricielle (le SVG peut contenir du script et n'est pas accepté)
+var dataImagePattern = regexp.MustCompile(`^data:image/(png|jpeg|gif|webp);base64,[A-Za-z0-9+/=\s]+$`)
+
+// codeClassPattern classe de coloration syntaxique d'un bloc de code (ex: language-go)
+var codeClassPattern = regexp.MustCompile(`^language-[A-Za-z0-9_+-]{1,30}$`)
+
+// Sanitize filtre un fragment HTML par liste blanche : balises et attributs inconnus retirés, URL vérifiées,
+// balises non fermées complétées ; le texte est ré-échappé
+func Sanitize(input string) string {
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(input))
+	var b strings.Builder
+	var open []string // balises ouvertes, pour refermer celles qui ne l'ont pas été
+	skipDepth := 0    // profondeur dans une balise supprimée avec son contenu
+	skipTag := ""
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			break // Fin du fragment (io.EOF) ou HTML illisible : le reste est ignoré
+		}
+		token := tokenizer.Token()
+		tag := token.Data
+
+		if skipDepth > 0 {
+			switch {
+			case tokenType == nethtml.StartTagToken && tag == skipTag:
+				skipDepth++
+			case tokenType == nethtml.EndTagToken && tag == skipTag:
+				skipDepth--
+			}
+			continue
+		}
+
+		switch tokenType {
+		case nethtml.TextToken:
+			b.WriteString(html.EscapeString(token.Data))
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedTags[tag] {
+				if tokenType == nethtml.StartTagToken {
+					skipDepth, skipTag = 1, tag
+				}
+				continue
+			}
+			attrs, ok := allowedTags[tag]
+			if !ok {
+				continue
+			}
+			b.WriteString("<" + tag)
+			writeAttributes(&b, tag, token.Attr, attrs)
+			b.WriteString(">")
+			if !voidTags[tag] {
+				open = append(open, tag)
+			}
+		case nethtml.EndTagToken:
+			if _, ok := allowedTags[tag]; !ok || voidTags[tag] {
+				continue
+			}
+			// Fermer les balises ouvertes jusqu'à celle-ci ; une balise fermante orpheline est ignorée
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != tag {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// writeAttributes écrit les attributs autorisés d'une balise, avec leurs valeurs vérifiées
+func writeAttributes(b *strings.Builder, tag string, attrs []nethtml.Attribute, allowed []string) {
+	seen := map[string]bool{}
+	for _, attr := range attrs {
+		name := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || seen[name] || !slices.Contains(allowed, name) {
+			continue
+		}
+		value, ok := attributeValue(tag, name, strings.TrimSpace(attr.Val))
+		if !ok {
+			continue
+		}
+		seen[name] = true
+		b.WriteString(" " + name + `="` + html.EscapeString(value) + `"`)
+	}
+	if tag == "a" && seen["href"] {
+		b.WriteString(` rel="noopener noreferrer nofollow"`)
+	}
+}
+
+// attributeValue vérifie la valeur d'un attribut autorisé
+func attributeValue(tag, name, value string) (string, bool) {
+	switch name {
+	case "href":
+		return value, safeURL(value, false)
+	case "src":
+		return value, safeURL(value, tag == "img")
+	case "start", "colspan", "rowspan", "width", "height":
+		n, err := strconv.Atoi(value)
+		return value, err == nil && n >= 0 && n <= 10000
+	case "class":
+		return value, codeClassPattern.MatchString(value)
+	}
+	return value, true
+}
+
+// safeURL accepte les URL relatives et les schémas http, https et mailto (data URI d'image si allowDataImage)
+func safeURL(value string, allowDataImage bool) bool {
+	if value == "" {
+		return false
+	}
+	// Les navigateurs ignorent les caractères de contrôle et espaces dans le schéma (ex: "java\tscript:")
+	compact := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+	if allowDataImage && strings.HasPrefix(strings.ToLower(compact), "data:") {
+		return dataImagePattern.MatchString(value)
+	}
+	parsed, err := url.Parse(compact)
+	if err != nil {
+		return false
+	}
+	if parsed.Scheme == "" {
+		// Pas de schéma : seul un ":" avant le premier "/", "?" ou "#" pourrait en introduire un
+		prefix := compact
+		if i := strings.IndexAny(prefix, "/?#"); i >= 0 {
+			prefix = prefix[:i]
+		}
+		return !strings.Contains(prefix, ":")
+	}
+	return linkSchemes[strings.ToLower(parsed.Scheme)]
+}
+
+// PlainText retourne le texte d'un contenu sans mise en forme (extraits de recherche, notifications)
+func PlainText(content, format string) string {
+	if NormalizeFormat(format) == FormatText {
+		return strings.TrimSpace(content)
+	}
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(Render(content, format)))
+	var b strings.Builder
+	for {
+		switch tokenizer.Next() {
+		case nethtml.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case nethtml.TextToken:
+			b.WriteString(tokenizer.Token().Data)
+		case nethtml.StartTagToken, nethtml.EndTagToken, nethtml.SelfClosingTagToken:
+			if name, _ := tokenizer.TagName(); !inlineTags[string(name)] {
+				b.WriteString(" ") // Séparer le texte de deux blocs ou cellules
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupAbsenceRoutes configure les routes des absences (congés, maladie, formation)
+func SetupAbsenceRoutes(router *gin.RouterGroup, absenceHandler *handlers.AbsenceHandler) {
+	absences := router.Group("/absences")
+	absences.Use(middleware.AuthMiddleware())
+	{
+		absences.GET("", absenceHandler.GetAll)
+		absences.POST("", absenceHandler.Create)
+		// Route spécifique - doit être avant /:id
+		absences.GET("/pending", absenceHandler.GetPending)
+		absences.GET("/:id", absenceHandler.GetByID)
+		absences.POST("/:id/approve", absenceHandler.Approve)
+		absences.POST("/:id/reject", absenceHandler.Reject)
+		absences.POST("/:id/cancel", absenceHandler.Cancel)
+	}
+}
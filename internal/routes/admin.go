@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupAdminRoutes configure les routes d'administration technique
+func SetupAdminRoutes(router *gin.RouterGroup, loggingHandler *handlers.LoggingHandler, configHandler *handlers.ConfigHandler, jobHandler *handlers.JobHandler, schedulerHandler *handlers.SchedulerHandler, accessCheckHandler *handlers.AccessCheckHandler) {
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware())
+	{
+		// Niveau de log modifiable à chaud
+		admin.GET("/log-level", loggingHandler.GetLevel)
+		admin.PUT("/log-level", loggingHandler.SetLevel)
+
+		// Rechargement à chaud des paramètres non critiques (équivalent à SIGHUP)
+		if configHandler != nil {
+			admin.POST("/config/reload", configHandler.Reload)
+		}
+
+		// File de tâches en arrière-plan (statistiques, échecs / dead-letter)
+		if jobHandler != nil {
+			admin.GET("/jobs/stats", jobHandler.GetStats)
+			admin.GET("/jobs/failed", jobHandler.GetFailed)
+			admin.POST("/jobs/failed/:id/retry", jobHandler.Retry)
+			admin.DELETE("/jobs/failed/:id", jobHandler.Delete)
+		}
+
+		// Tâches planifiées (planification, exécution manuelle, historique)
+		if schedulerHandler != nil {
+			admin.GET("/scheduler/jobs", schedulerHandler.GetJobs)
+			admin.PUT("/scheduler/jobs/:name", schedulerHandler.UpdateJob)
+			admin.POST("/scheduler/jobs/:name/run", schedulerHandler.RunJob)
+			admin.GET("/scheduler/jobs/:name/runs", schedulerHandler.GetJobRuns)
+		}
+
+		// Diagnostic des droits effectifs (branche de périmètre, prédicat SQL)
+		if accessCheckHandler != nil {
+			admin.GET("/access-check", accessCheckHandler.Check)
+		}
+	}
+}
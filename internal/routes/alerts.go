@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+)
+
+// SetupMonitoringAlertRoutes configure les routes de gestion de la supervision (la réception des alertes est publique)
+func SetupMonitoringAlertRoutes(router *gin.RouterGroup, alertHandler *handlers.MonitoringAlertHandler) {
+	alerts := router.Group("/integrations/alerts")
+	{
+		alerts.GET("", alertHandler.GetAlerts)
+		alerts.GET("/sources", alertHandler.GetSources)
+		alerts.POST("/sources", alertHandler.CreateSource)
+		alerts.PUT("/sources/:id", alertHandler.UpdateSource)
+		alerts.POST("/sources/:id/key", alertHandler.RotateKey)
+		alerts.DELETE("/sources/:id", alertHandler.DeleteSource)
+	}
+}
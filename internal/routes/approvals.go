@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupApprovalRoutes configure les routes du circuit d'approbation
+func SetupApprovalRoutes(router *gin.RouterGroup, approvalHandler *handlers.ApprovalHandler) {
+	approvals := router.Group("/approvals")
+	approvals.Use(middleware.AuthMiddleware())
+	{
+		approvals.GET("", approvalHandler.GetAll)
+		approvals.POST("", approvalHandler.Create)
+		approvals.GET("/my-pending", approvalHandler.GetMyPending)
+		approvals.GET("/:id", approvalHandler.GetByID)
+		approvals.POST("/:id/approve", approvalHandler.Approve)
+		approvals.POST("/:id/reject", approvalHandler.Reject)
+		approvals.POST("/:id/cancel", approvalHandler.Cancel)
+		approvals.POST("/:id/comments", approvalHandler.AddComment)
+	}
+}
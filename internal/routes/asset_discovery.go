@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+)
+
+// SetupAssetDiscoveryRoutes configure les routes de gestion de la découverte d'actifs (la réception des inventaires est publique)
+func SetupAssetDiscoveryRoutes(router *gin.RouterGroup, discoveryHandler *handlers.AssetDiscoveryHandler) {
+	discovery := router.Group("/integrations/discovery")
+	{
+		discovery.GET("/agents", discoveryHandler.GetAgents)
+		discovery.POST("/agents", discoveryHandler.CreateAgent)
+		discovery.PUT("/agents/:id", discoveryHandler.UpdateAgent)
+		discovery.POST("/agents/:id/key", discoveryHandler.RotateKey)
+		discovery.DELETE("/agents/:id", discoveryHandler.DeleteAgent)
+		discovery.GET("/drifts", discoveryHandler.GetDrifts)
+		discovery.POST("/drifts/:id/accept", discoveryHandler.AcceptDrift)
+		discovery.POST("/drifts/:id/dismiss", discoveryHandler.DismissDrift)
+	}
+}
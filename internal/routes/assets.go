@@ -15,8 +15,10 @@ func SetupAssetRoutes(router *gin.RouterGroup, assetHandler *handlers.AssetHandl
 		assets.GET("", assetHandler.GetAll)
 		assets.POST("", assetHandler.Create)
 		assets.GET("/inventory", assetHandler.GetInventory)
+		assets.GET("/expiring", assetHandler.GetExpiring)
 		assets.GET("/by-category/:categoryId", assetHandler.GetByCategory)
 		assets.GET("/by-user/:userId", assetHandler.GetByUser)
+		assets.GET("/by-code/:code", assetHandler.GetByCode)
 
 		// Catégories d'actifs (doivent être avant les routes avec :id)
 		assets.GET("/categories", assetCategoryHandler.GetAll)
@@ -46,5 +48,67 @@ func SetupAssetRoutes(router *gin.RouterGroup, assetHandler *handlers.AssetHandl
 		assets.GET("/:id/tickets", assetHandler.GetLinkedTickets)
 		assets.POST("/:id/link-ticket/:ticketId", assetHandler.LinkTicket)
 		assets.DELETE("/:id/unlink-ticket/:ticketId", assetHandler.UnlinkTicket)
+		assets.POST("/:id/transition", assetHandler.Transition)
+		assets.GET("/:id/lifecycle", assetHandler.GetLifecycle)
+		assets.GET("/:id/depreciation", assetHandler.GetDepreciation)
+		assets.GET("/:id/qrcode", assetHandler.GetQRCode)
+	}
+}
+
+// SetupAssetRelationRoutes configure les routes des relations de dépendance de la CMDB et de l'analyse d'impact
+func SetupAssetRelationRoutes(router *gin.RouterGroup, relationHandler *handlers.AssetRelationHandler) {
+	assets := router.Group("/assets")
+	assets.Use(middleware.AuthMiddleware())
+	{
+		assets.GET("/:id/relations", relationHandler.GetRelations)
+		assets.POST("/:id/relations", relationHandler.Create)
+		assets.DELETE("/:id/relations/:relationId", relationHandler.Delete)
+		assets.GET("/:id/impact", relationHandler.GetImpact)
+	}
+}
+
+// SetupAssetReservationRoutes configure les routes du parc de prêt (réservations d'équipements partagés)
+func SetupAssetReservationRoutes(router *gin.RouterGroup, reservationHandler *handlers.AssetReservationHandler) {
+	reservations := router.Group("/asset-reservations")
+	reservations.Use(middleware.AuthMiddleware())
+	{
+		reservations.GET("/calendar", reservationHandler.GetCalendar)
+		reservations.GET("", reservationHandler.GetAll)
+		reservations.POST("", reservationHandler.Create)
+		reservations.GET("/:id", reservationHandler.GetByID)
+		reservations.POST("/:id/approve", reservationHandler.Approve)
+		reservations.POST("/:id/reject", reservationHandler.Reject)
+		reservations.POST("/:id/cancel", reservationHandler.Cancel)
+		reservations.POST("/:id/checkout", reservationHandler.CheckOut)
+		reservations.POST("/:id/return", reservationHandler.Return)
+	}
+}
+
+// SetupAssetImportRoutes configure la route d'import en masse des actifs
+func SetupAssetImportRoutes(router *gin.RouterGroup, importHandler *handlers.AssetImportHandler) {
+	assets := router.Group("/assets")
+	assets.Use(middleware.AuthMiddleware())
+	{
+		assets.POST("/import", importHandler.Import)
+	}
+}
+
+// SetupMaintenancePlanRoutes configure les routes des plans de maintenance préventive et de l'historique de maintenance des actifs
+func SetupMaintenancePlanRoutes(router *gin.RouterGroup, maintenancePlanHandler *handlers.MaintenancePlanHandler) {
+	plans := router.Group("/maintenance-plans")
+	plans.Use(middleware.AuthMiddleware())
+	{
+		plans.GET("", maintenancePlanHandler.GetAll)
+		plans.GET("/:id", maintenancePlanHandler.GetByID)
+		plans.POST("", maintenancePlanHandler.Create)
+		plans.PUT("/:id", maintenancePlanHandler.Update)
+		plans.DELETE("/:id", maintenancePlanHandler.Delete)
+		plans.POST("/:id/run", maintenancePlanHandler.Run)
+	}
+
+	assets := router.Group("/assets")
+	assets.Use(middleware.AuthMiddleware())
+	{
+		assets.GET("/:id/maintenance", maintenancePlanHandler.GetAssetMaintenance)
 	}
 }
@@ -12,6 +12,8 @@ func SetupAuditRoutes(router *gin.RouterGroup, auditHandler *handlers.AuditHandl
 	audit.Use(middleware.AuthMiddleware())
 	{
 		audit.GET("", auditHandler.GetAll)
+		audit.GET("/verify", auditHandler.VerifyChain)
+		audit.GET("/export", auditHandler.Export)
 		audit.GET("/:id", auditHandler.GetByID)
 		audit.GET("/by-user/:userId", auditHandler.GetByUserID)
 		audit.GET("/by-action/:action", auditHandler.GetByAction)
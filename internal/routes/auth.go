@@ -6,14 +6,14 @@ import (
 	"github.com/mcicare/itsm-backend/internal/middleware"
 )
 
-// SetupAuthRoutes configure les routes d'authentification
-func SetupAuthRoutes(router *gin.RouterGroup, authHandler *handlers.AuthHandler) {
+// SetupAuthRoutes configure les routes d'authentification ; authRateLimit limite les routes publiques par adresse IP
+func SetupAuthRoutes(router *gin.RouterGroup, authHandler *handlers.AuthHandler, authRateLimit gin.HandlerFunc) {
 	auth := router.Group("/auth")
 	{
-		// Routes publiques (sans authentification)
-		auth.POST("/register", authHandler.Register)
-		auth.POST("/login", authHandler.Login)
-		auth.POST("/refresh", authHandler.RefreshToken)
+		// Routes publiques (sans authentification), limitées par adresse IP
+		auth.POST("/register", authRateLimit, authHandler.Register)
+		auth.POST("/login", authRateLimit, authHandler.Login)
+		auth.POST("/refresh", authRateLimit, authHandler.RefreshToken)
 
 		// Routes protégées (avec authentification)
 		auth.Use(middleware.AuthMiddleware())
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+)
+
+// SetupCalendarRoutes configure les routes de gestion des flux de calendrier ICS
+func SetupCalendarRoutes(router *gin.RouterGroup, calendarFeedHandler *handlers.CalendarFeedHandler) {
+	feeds := router.Group("/calendar/feeds")
+	{
+		feeds.GET("", calendarFeedHandler.GetAll)
+		feeds.POST("", calendarFeedHandler.Create)
+		feeds.DELETE("/:id", calendarFeedHandler.Delete)
+	}
+}
+
+// SetupCalendarSyncRoutes configure les routes de connexion des agendas Google et Microsoft
+func SetupCalendarSyncRoutes(router *gin.RouterGroup, calendarSyncHandler *handlers.CalendarSyncHandler) {
+	router.GET("/calendar/providers", calendarSyncHandler.GetProviders)
+	connections := router.Group("/calendar/connections")
+	{
+		connections.GET("", calendarSyncHandler.GetConnections)
+		connections.POST("", calendarSyncHandler.Connect)
+		connections.POST("/:id/sync", calendarSyncHandler.Sync)
+		connections.DELETE("/:id", calendarSyncHandler.Disconnect)
+	}
+}
@@ -17,6 +17,7 @@ func SetupDelayRoutes(router *gin.RouterGroup, delayHandler *handlers.DelayHandl
 		delays.GET("/justifications/validated", delayHandler.GetValidatedJustifications)
 		delays.GET("/justifications/rejected", delayHandler.GetRejectedJustifications)
 		delays.GET("/justifications/history", delayHandler.GetJustificationsHistory)
+		delays.GET("/justifications/pending-my-approval", delayHandler.GetPendingMyApproval)
 		delays.POST("/justifications/:id/validate", delayHandler.ValidateJustification)
 		
 		// Routes spécifiques pour les justifications par delay (doivent être avant la route générique :id)
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupAccessDelegationRoutes configure les routes des délégations d'absence
+func SetupAccessDelegationRoutes(router *gin.RouterGroup, delegationHandler *handlers.AccessDelegationHandler) {
+	delegations := router.Group("/delegations")
+	delegations.Use(middleware.AuthMiddleware())
+	{
+		delegations.GET("", delegationHandler.GetMine)
+		delegations.POST("", delegationHandler.Create)
+		delegations.DELETE("/:id", delegationHandler.Revoke)
+	}
+}
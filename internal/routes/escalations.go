@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupEscalationRoutes configure les routes des règles d'escalade automatique des tickets
+func SetupEscalationRoutes(router *gin.RouterGroup, escalationHandler *handlers.EscalationHandler) {
+	rules := router.Group("/escalation-rules")
+	rules.Use(middleware.AuthMiddleware())
+	{
+		rules.GET("", escalationHandler.GetAll)
+		rules.POST("/evaluate", escalationHandler.Evaluate) // Route spécifique avant /:id
+		rules.GET("/:id", escalationHandler.GetByID)
+		rules.POST("", escalationHandler.Create)
+		rules.PUT("/:id", escalationHandler.Update)
+		rules.DELETE("/:id", escalationHandler.Delete)
+	}
+}
@@ -20,6 +20,7 @@ func SetupFilialeRoutes(router *gin.RouterGroup, filialeHandler *handlers.Filial
 		// Routes pour les déploiements de logiciels par filiale (DOIVENT être avant /:filiale_id)
 		filiales.GET("/:filiale_id/software", filialeSoftwareHandler.GetByFilialeID)
 		filiales.POST("/:filiale_id/software", filialeSoftwareHandler.Create)
+		filiales.POST("/:filiale_id/software/plan", filialeSoftwareHandler.Plan)
 
 		// Routes génériques (utilisent :filiale_id pour éviter le conflit avec les routes ci-dessus)
 		filiales.GET("/:filiale_id", filialeHandler.GetByID)
@@ -36,6 +37,10 @@ func SetupFilialeSoftwareRoutes(router *gin.RouterGroup, filialeSoftwareHandler
 		deployments.GET("", filialeSoftwareHandler.GetAll)
 		deployments.GET("/active", filialeSoftwareHandler.GetActive)
 		deployments.GET("/:id", filialeSoftwareHandler.GetByID)
+		deployments.GET("/:id/history", filialeSoftwareHandler.GetHistory)
+		deployments.POST("/:id/start", filialeSoftwareHandler.Start)
+		deployments.POST("/:id/confirm", filialeSoftwareHandler.Confirm)
+		deployments.POST("/:id/rollback", filialeSoftwareHandler.Rollback)
 		deployments.PUT("/:id", filialeSoftwareHandler.Update)
 		deployments.DELETE("/:id", filialeSoftwareHandler.Delete)
 	}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupGitRoutes configure les routes de consultation des commits et merge requests liés aux tickets (le webhook est public)
+func SetupGitRoutes(router *gin.RouterGroup, gitHandler *handlers.GitHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	{
+		tickets.GET("/:id/git-links", gitHandler.GetTicketLinks)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupI18nRoutes configure les routes des libellés localisés
+func SetupI18nRoutes(router *gin.RouterGroup, i18nHandler *handlers.I18nHandler) {
+	i18nGroup := router.Group("/i18n")
+	i18nGroup.Use(middleware.AuthMiddleware())
+	{
+		i18nGroup.GET("/enums", i18nHandler.GetEnums)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupImportRoutes configure les routes des imports depuis d'autres outils
+func SetupImportRoutes(router *gin.RouterGroup, importHandler *handlers.ImportHandler) {
+	imports := router.Group("/imports")
+	imports.Use(middleware.AuthMiddleware())
+	{
+		imports.GET("", importHandler.GetAll)
+		imports.POST("/glpi", importHandler.StartGLPI)
+		imports.POST("/helpdesk", importHandler.StartHelpdesk)
+		imports.POST("/helpdesk/files", importHandler.StartHelpdeskFiles)
+		imports.GET("/:id", importHandler.GetByID)
+		imports.POST("/:id/resume", importHandler.Resume)
+		imports.POST("/:id/cancel", importHandler.Cancel)
+	}
+}
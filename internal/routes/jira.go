@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+	"github.com/mcicare/itsm-backend/internal/models"
+)
+
+// SetupJiraRoutes configure les routes de liaison des tickets à Jira
+func SetupJiraRoutes(router *gin.RouterGroup, jiraHandler *handlers.JiraHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	sharedWriteGuard := middleware.SharedWriteGuard(models.ShareResourceTicket)
+	{
+		tickets.POST("/:id/jira", sharedWriteGuard, jiraHandler.Link)
+		tickets.DELETE("/:id/jira", sharedWriteGuard, jiraHandler.Unlink)
+	}
+}
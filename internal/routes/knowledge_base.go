@@ -13,6 +13,7 @@ func SetupKnowledgeBaseRoutes(router *gin.RouterGroup, knowledgeArticleHandler *
 		// Routes publiques (articles publiés)
 		kb.GET("/articles/published", knowledgeArticleHandler.GetPublished)
 		kb.GET("/articles/search", knowledgeArticleHandler.Search)
+		kb.GET("/articles/suggest", knowledgeArticleHandler.Suggest)
 
 		// Routes protégées (gestion des articles)
 		kb.Use(middleware.AuthMiddleware())
@@ -27,6 +28,16 @@ func SetupKnowledgeBaseRoutes(router *gin.RouterGroup, knowledgeArticleHandler *
 			kb.GET("/articles/by-category/:categoryId", knowledgeArticleHandler.GetByCategory)
 			kb.GET("/articles/by-author/:authorId", knowledgeArticleHandler.GetByAuthor)
 
+			// Versions et relecture
+			kb.GET("/articles/reviews", knowledgeArticleHandler.GetReviewQueue)
+			kb.GET("/articles/:id/revisions", knowledgeArticleHandler.GetRevisions)
+			kb.GET("/articles/:id/revisions/:version", knowledgeArticleHandler.GetRevision)
+			kb.POST("/articles/:id/revisions/:version/restore", knowledgeArticleHandler.Restore)
+			kb.GET("/articles/:id/diff", knowledgeArticleHandler.Diff)
+			kb.POST("/articles/:id/submit-review", knowledgeArticleHandler.SubmitForReview)
+			kb.POST("/articles/:id/review", knowledgeArticleHandler.Review)
+			kb.POST("/articles/:id/archive", knowledgeArticleHandler.Archive)
+
 			// Catégories
 			kb.GET("/categories", knowledgeCategoryHandler.GetAll)
 			kb.GET("/categories/:id", knowledgeCategoryHandler.GetByID)
@@ -36,3 +47,67 @@ func SetupKnowledgeBaseRoutes(router *gin.RouterGroup, knowledgeArticleHandler *
 		}
 	}
 }
+
+// SetupKnowledgeDeflectionRoutes configure les suggestions d'articles pendant la saisie d'un ticket et le suivi de la déviation
+func SetupKnowledgeDeflectionRoutes(router *gin.RouterGroup, knowledgeDeflectionHandler *handlers.KnowledgeDeflectionHandler) {
+	knowledge := router.Group("/knowledge")
+	knowledge.Use(middleware.AuthMiddleware())
+	{
+		knowledge.GET("/suggest", knowledgeDeflectionHandler.Suggest)
+		knowledge.POST("/deflection-events", knowledgeDeflectionHandler.RecordEvent)
+		knowledge.GET("/deflection-stats", knowledgeDeflectionHandler.GetStats)
+	}
+}
+
+// SetupKnowledgeFeedbackRoutes configure les évaluations des articles et le rapport des articles mal notés
+func SetupKnowledgeFeedbackRoutes(router *gin.RouterGroup, knowledgeFeedbackHandler *handlers.KnowledgeFeedbackHandler) {
+	kb := router.Group("/knowledge-base")
+	kb.Use(middleware.AuthMiddleware())
+	{
+		kb.GET("/articles/low-rated", knowledgeFeedbackHandler.GetLowRated)
+		kb.GET("/articles/:id/feedback", knowledgeFeedbackHandler.GetRating)
+		kb.POST("/articles/:id/feedback", knowledgeFeedbackHandler.Rate)
+		kb.DELETE("/articles/:id/feedback", knowledgeFeedbackHandler.RemoveRating)
+	}
+}
+
+// SetupKnowledgeAttachmentRoutes configure les pièces jointes des articles et les quotas de stockage
+func SetupKnowledgeAttachmentRoutes(router *gin.RouterGroup, knowledgeAttachmentHandler *handlers.KnowledgeAttachmentHandler) {
+	kb := router.Group("/knowledge-base")
+	kb.Use(middleware.AuthMiddleware())
+	{
+		kb.GET("/articles/:id/attachments", knowledgeAttachmentHandler.GetByArticle)
+		kb.POST("/articles/:id/attachments", knowledgeAttachmentHandler.Upload)
+		kb.GET("/articles/:id/attachments/:attachmentId", knowledgeAttachmentHandler.Download)
+		kb.DELETE("/articles/:id/attachments/:attachmentId", knowledgeAttachmentHandler.Delete)
+		kb.GET("/storage", knowledgeAttachmentHandler.GetStorageUsage)
+		kb.PUT("/storage/quotas/:filialeId", knowledgeAttachmentHandler.SetQuota)
+		kb.DELETE("/storage/quotas/:filialeId", knowledgeAttachmentHandler.ResetQuota)
+	}
+}
+
+// SetupKnowledgePortalKeyRoutes configure la gestion des clés d'API du portail public de la base de connaissances
+func SetupKnowledgePortalKeyRoutes(router *gin.RouterGroup, knowledgePortalHandler *handlers.KnowledgePortalHandler) {
+	keys := router.Group("/knowledge-base/portal-keys")
+	keys.Use(middleware.AuthMiddleware())
+	{
+		keys.GET("", knowledgePortalHandler.GetKeys)
+		keys.POST("", knowledgePortalHandler.CreateKey)
+		keys.PUT("/:id", knowledgePortalHandler.UpdateKey)
+		keys.POST("/:id/key", knowledgePortalHandler.RotateKey)
+		keys.DELETE("/:id", knowledgePortalHandler.DeleteKey)
+	}
+}
+
+// SetupPublicKnowledgeRoutes configure le portail public en lecture seule de la base de connaissances
+// (accès selon KB_PUBLIC_MODE, vérifié par le handler ; limité par adresse IP)
+func SetupPublicKnowledgeRoutes(router *gin.RouterGroup, knowledgePortalHandler *handlers.KnowledgePortalHandler) {
+	kb := router.Group("/knowledge")
+	kb.Use(middleware.PublicKnowledgeRateLimitMiddleware())
+	{
+		kb.GET("/articles", knowledgePortalHandler.GetArticles)
+		kb.GET("/articles/:id", knowledgePortalHandler.GetArticle)
+		kb.GET("/articles/:id/attachments/:attachmentId", knowledgePortalHandler.DownloadAttachment)
+		kb.GET("/categories", knowledgePortalHandler.GetCategories)
+	}
+}
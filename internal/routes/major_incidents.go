@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupMajorIncidentRoutes configure les routes du mode incident majeur (la page de statut publique est enregistrée avec les routes publiques)
+func SetupMajorIncidentRoutes(router *gin.RouterGroup, majorIncidentHandler *handlers.MajorIncidentHandler) {
+	incidents := router.Group("/incidents")
+	incidents.Use(middleware.AuthMiddleware())
+	{
+		incidents.GET("/major/active", majorIncidentHandler.GetActive)
+		incidents.POST("/:id/major", majorIncidentHandler.Declare)
+		incidents.GET("/:id/major/updates", majorIncidentHandler.GetUpdates)
+		incidents.POST("/:id/major/updates", majorIncidentHandler.PostUpdate)
+		incidents.POST("/:id/major/end", majorIncidentHandler.End)
+		incidents.GET("/:id/review", majorIncidentHandler.GetReview)
+		incidents.POST("/:id/review", majorIncidentHandler.CreateReview)
+		incidents.PUT("/:id/review", majorIncidentHandler.UpdateReview)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupReportingFeedRoutes configure la gestion des clés du flux OData (le flux lui-même est authentifié par clé d'API)
+func SetupReportingFeedRoutes(router *gin.RouterGroup, reportingHandler *handlers.ReportingFeedHandler) {
+	keys := router.Group("/reports/odata/keys")
+	keys.Use(middleware.AuthMiddleware())
+	{
+		keys.GET("", reportingHandler.GetKeys)
+		keys.POST("", reportingHandler.CreateKey)
+		keys.PUT("/:id", reportingHandler.UpdateKey)
+		keys.POST("/:id/key", reportingHandler.RotateKey)
+		keys.DELETE("/:id", reportingHandler.DeleteKey)
+	}
+}
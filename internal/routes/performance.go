@@ -15,6 +15,10 @@ func SetupPerformanceRoutes(router *gin.RouterGroup, performanceHandler *handler
 		performance.GET("/users/:user_id/efficiency", performanceHandler.GetEfficiencyByUserID)
 		performance.GET("/users/:user_id/productivity", performanceHandler.GetProductivityByUserID)
 		performance.GET("/ranking", performanceHandler.GetPerformanceRanking)
+		performance.GET("/utilization", performanceHandler.GetUtilization)
+		performance.GET("/utilization/targets", performanceHandler.GetUtilizationTargets)
+		performance.PUT("/utilization/targets/:role_id", performanceHandler.SetUtilizationTarget)
+		performance.GET("/users/:user_id/utilization", performanceHandler.GetUserUtilization)
 	}
 }
 
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupProblemRoutes configure les routes des problèmes et erreurs connues
+func SetupProblemRoutes(router *gin.RouterGroup, problemHandler *handlers.ProblemHandler) {
+	problems := router.Group("/problems")
+	problems.Use(middleware.AuthMiddleware())
+	{
+		problems.GET("", problemHandler.GetAll)
+		problems.POST("", problemHandler.Create)
+		problems.GET("/by-ticket/:ticketId", problemHandler.GetByTicketID)
+		problems.GET("/by-incident/:ticketId", problemHandler.GetByIncident)
+		problems.GET("/:id", problemHandler.GetByID)
+		problems.PUT("/:id", problemHandler.Update)
+		problems.DELETE("/:id", problemHandler.Delete)
+		problems.POST("/:id/known-error", problemHandler.MarkKnownError)
+		problems.POST("/:id/resolve", problemHandler.Resolve)
+		problems.POST("/:id/publish-workaround", problemHandler.PublishWorkaround)
+		problems.GET("/:id/incidents", problemHandler.GetIncidents)
+		problems.POST("/:id/incidents", problemHandler.LinkIncident)
+		problems.DELETE("/:id/incidents/:ticketId", problemHandler.UnlinkIncident)
+	}
+}
@@ -4,14 +4,109 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/handlers"
 	"github.com/mcicare/itsm-backend/internal/middleware"
+	"github.com/mcicare/itsm-backend/internal/models"
 )
 
+// SetupCapacityRoutes configure le plan de charge des ressources (/projects/capacity, avant /projects/:id)
+func SetupCapacityRoutes(router *gin.RouterGroup, capacityHandler *handlers.CapacityHandler) {
+	capacity := router.Group("/projects/capacity")
+	capacity.Use(middleware.AuthMiddleware())
+	{
+		capacity.GET("", capacityHandler.GetCapacity)
+	}
+}
+
+// SetupPortfolioRoutes configure le tableau de bord du portefeuille de projets (/projects/portfolio, avant /projects/:id)
+func SetupPortfolioRoutes(router *gin.RouterGroup, portfolioHandler *handlers.PortfolioHandler) {
+	portfolio := router.Group("/projects/portfolio")
+	portfolio.Use(middleware.AuthMiddleware())
+	{
+		portfolio.GET("", portfolioHandler.GetPortfolio)
+	}
+}
+
+// SetupProjectTemplateRoutes configure les modèles de projet et la création de projet depuis un modèle
+// (/projects/from-template, avant /projects/:id)
+func SetupProjectTemplateRoutes(router *gin.RouterGroup, projectTemplateHandler *handlers.ProjectTemplateHandler) {
+	templates := router.Group("/project-templates")
+	templates.Use(middleware.AuthMiddleware())
+	{
+		templates.GET("", projectTemplateHandler.GetAll)
+		templates.GET("/:id", projectTemplateHandler.GetByID)
+		templates.POST("", projectTemplateHandler.Create)
+		templates.PUT("/:id", projectTemplateHandler.Update)
+		templates.DELETE("/:id", projectTemplateHandler.Delete)
+	}
+
+	fromTemplate := router.Group("/projects/from-template")
+	fromTemplate.Use(middleware.AuthMiddleware())
+	{
+		fromTemplate.POST("/:id", projectTemplateHandler.CreateProject)
+	}
+}
+
+// SetupProjectTaskRecurrenceRoutes configure les règles de récurrence des tâches de projet
+func SetupProjectTaskRecurrenceRoutes(router *gin.RouterGroup, recurrenceHandler *handlers.ProjectTaskRecurrenceHandler) {
+	projects := router.Group("/projects")
+	projects.Use(middleware.AuthMiddleware())
+	projects.Use(middleware.SharedWriteGuard(models.ShareResourceProject))
+	{
+		projects.GET("/:id/task-recurrences", recurrenceHandler.GetByProject)
+		projects.POST("/:id/tasks/:taskId/recurrence", recurrenceHandler.Create)
+		projects.PUT("/:id/task-recurrences/:recurrenceId", recurrenceHandler.Update)
+		projects.DELETE("/:id/task-recurrences/:recurrenceId", recurrenceHandler.Delete)
+	}
+}
+
+// SetupProjectTaskTimerRoutes configure la saisie du temps des tâches par chronomètre et le chronomètre en cours
+// (/timesheet/timer)
+func SetupProjectTaskTimerRoutes(router *gin.RouterGroup, timerHandler *handlers.ProjectTaskTimerHandler) {
+	projects := router.Group("/projects")
+	projects.Use(middleware.AuthMiddleware())
+	projects.Use(middleware.SharedWriteGuard(models.ShareResourceProject))
+	{
+		projects.POST("/:id/tasks/:taskId/time/start", timerHandler.Start)
+		projects.POST("/:id/tasks/:taskId/time/stop", timerHandler.Stop)
+	}
+
+	timer := router.Group("/timesheet/timer")
+	timer.Use(middleware.AuthMiddleware())
+	{
+		timer.GET("", timerHandler.GetCurrent)
+		timer.DELETE("", timerHandler.Discard)
+	}
+}
+
+// SetupTicketProjectTaskRoutes configure les liens entre tickets et tâches de projet, des deux côtés
+func SetupTicketProjectTaskRoutes(router *gin.RouterGroup, linkHandler *handlers.TicketProjectTaskHandler) {
+	projects := router.Group("/projects")
+	projects.Use(middleware.AuthMiddleware())
+	projects.Use(middleware.SharedWriteGuard(models.ShareResourceProject))
+	{
+		projects.GET("/:id/tasks/:taskId/tickets", linkHandler.GetTicketsByTask)
+		projects.POST("/:id/tasks/:taskId/tickets", linkHandler.LinkTicket)
+		projects.DELETE("/:id/tasks/:taskId/tickets/:ticketId", linkHandler.UnlinkTicket)
+	}
+
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	sharedWriteGuard := middleware.SharedWriteGuard(models.ShareResourceTicket)
+	{
+		tickets.GET("/:id/project-tasks", linkHandler.GetTasksByTicket)
+		tickets.POST("/:id/project-tasks", sharedWriteGuard, linkHandler.LinkTask)
+		tickets.DELETE("/:id/project-tasks/:taskId", sharedWriteGuard, linkHandler.UnlinkTask)
+	}
+}
+
 // SetupProjectRoutes configure les routes des projets
 func SetupProjectRoutes(router *gin.RouterGroup, projectHandler *handlers.ProjectHandler) {
 	projects := router.Group("/projects")
 	projects.Use(middleware.AuthMiddleware())
+	projects.Use(middleware.ETagMiddleware())
+	projects.Use(middleware.SharedWriteGuard(models.ShareResourceProject))
 	{
 		projects.GET("", projectHandler.GetAll)
+		projects.GET("/milestones/dashboard", projectHandler.GetMilestoneDashboard)
 		projects.GET("/:id", projectHandler.GetByID)
 		projects.GET("/:id/budget-extensions", projectHandler.GetBudgetExtensions)
 		projects.POST("", projectHandler.Create)
@@ -20,6 +115,8 @@ func SetupProjectRoutes(router *gin.RouterGroup, projectHandler *handlers.Projec
 		projects.DELETE("/:id/budget-extensions/:extId", projectHandler.DeleteBudgetExtension)
 		projects.PUT("/:id", projectHandler.Update)
 		projects.DELETE("/:id", projectHandler.Delete)
+		projects.POST("/:id/archive", projectHandler.Archive)
+		projects.POST("/:id/unarchive", projectHandler.Unarchive)
 
 		// Phases — /reorder avant /:phaseId
 		projects.GET("/:id/phases", projectHandler.GetPhases)
@@ -54,6 +151,18 @@ func SetupProjectRoutes(router *gin.RouterGroup, projectHandler *handlers.Projec
 		projects.GET("/:id/phases/:phaseId/tasks", projectHandler.GetTasksByPhase)
 		projects.PUT("/:id/tasks/:taskId", projectHandler.UpdateTask)
 		projects.DELETE("/:id/tasks/:taskId", projectHandler.DeleteTask)
+		projects.PATCH("/:id/tasks/:taskId/move", projectHandler.MoveTask)
+
+		// Task dependencies
+		projects.GET("/:id/tasks/:taskId/dependencies", projectHandler.GetTaskDependencies)
+		projects.POST("/:id/tasks/:taskId/dependencies", projectHandler.AddTaskDependency)
+		projects.DELETE("/:id/tasks/:taskId/dependencies/:dependsOnId", projectHandler.RemoveTaskDependency)
+
+		// Milestones
+		projects.GET("/:id/milestones", projectHandler.GetMilestones)
+		projects.POST("/:id/milestones", projectHandler.CreateMilestone)
+		projects.PUT("/:id/milestones/:milestoneId", projectHandler.UpdateMilestone)
+		projects.DELETE("/:id/milestones/:milestoneId", projectHandler.DeleteMilestone)
 	}
 }
 
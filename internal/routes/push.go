@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+)
+
+// SetupPushRoutes configure les routes d'enregistrement des appareils aux notifications push
+func SetupPushRoutes(router *gin.RouterGroup, pushHandler *handlers.PushHandler) {
+	push := router.Group("/push/devices")
+	{
+		push.GET("", pushHandler.GetDevices)
+		push.POST("", pushHandler.RegisterDevice)
+		push.DELETE("/:id", pushHandler.DeleteDevice)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupRecurringTicketRoutes configure les routes des tickets récurrents (création planifiée de tickets)
+func SetupRecurringTicketRoutes(router *gin.RouterGroup, recurringTicketHandler *handlers.RecurringTicketHandler) {
+	recurring := router.Group("/recurring-tickets")
+	recurring.Use(middleware.AuthMiddleware())
+	{
+		recurring.GET("", recurringTicketHandler.GetAll)
+		recurring.GET("/:id", recurringTicketHandler.GetByID)
+		recurring.POST("", recurringTicketHandler.Create)
+		recurring.PUT("/:id", recurringTicketHandler.Update)
+		recurring.DELETE("/:id", recurringTicketHandler.Delete)
+		recurring.POST("/:id/run", recurringTicketHandler.Run)
+	}
+}
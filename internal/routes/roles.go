@@ -16,10 +16,13 @@ func SetupRoleRoutes(router *gin.RouterGroup, roleHandler *handlers.RoleHandler)
 		roles.GET("/assignable-permissions", roleHandler.GetAssignablePermissions)
 		roles.GET("/my-delegations", roleHandler.GetMyDelegations)
 		roles.GET("/for-delegation", roleHandler.GetForDelegationPage)
+		roles.GET("/templates", roleHandler.GetTemplates)
+		roles.POST("/from-template", roleHandler.CreateFromTemplate)
 		roles.GET("/:id", roleHandler.GetByID)
 		roles.POST("", roleHandler.Create)
 		roles.PUT("/:id", roleHandler.Update)
 		roles.DELETE("/:id", roleHandler.Delete)
+		roles.POST("/:id/clone", roleHandler.Clone)
 
 		// Routes pour la gestion des permissions
 		roles.GET("/:id/permissions", roleHandler.GetRolePermissions)
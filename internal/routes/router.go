@@ -12,183 +12,551 @@ import (
 // SetupRoutes configure toutes les routes de l'application
 func SetupRoutes(router *gin.Engine, handlers *Handlers, auditLogRepo repositories.AuditLogRepository) {
 	// Middleware global
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.RequestLoggerMiddleware())
 	router.Use(middleware.CORSMiddleware())
-
-	// Route de santé
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	router.Use(middleware.SecurityHeadersMiddleware())
+	router.Use(middleware.GzipMiddleware())
+
+	// Routes de santé : /healthz (vivacité) et /readyz (disponibilité avec vérification des dépendances)
+	// /health est conservée pour compatibilité et équivaut à /healthz
+	if handlers.HealthHandler != nil {
+		router.GET("/health", handlers.HealthHandler.Liveness)
+		router.GET("/healthz", handlers.HealthHandler.Liveness)
+		router.GET("/readyz", handlers.HealthHandler.Readiness)
+	} else {
+		router.GET("/health", func(c *gin.Context) {
+			c.JSON(200, gin.H{"status": "ok"})
+		})
+	}
 
 	// Route Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Limiteurs de débit créés une seule fois : v1 et v2 (et les routes publiques limitées par IP) partagent les quotas
+	limits := rateLimits{
+		auth: middleware.AuthRateLimitMiddleware(),
+		user: middleware.RateLimitMiddleware(),
+	}
+
 	// Groupe API v1
-	api := router.Group("/api/v1")
+	setupAPIRoutes(router.Group("/api/v1"), handlers, auditLogRepo, limits)
+
+	// Groupe API v2 : mêmes handlers, enveloppe cohérente et erreurs RFC 7807 (voir middleware.APIV2Middleware)
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.APIV2Middleware())
+	setupAPIRoutes(v2, handlers, auditLogRepo, limits)
+
+	// Portail public de la base de connaissances (intranet), hors API versionnée : sans JWT, clé d'API selon KB_PUBLIC_MODE
+	if handlers.KnowledgePortalHandler != nil {
+		SetupPublicKnowledgeRoutes(router.Group("/public/v1"), handlers.KnowledgePortalHandler)
+	}
+}
+
+// rateLimits middlewares de limitation de débit partagés par les groupes versionnés
+type rateLimits struct {
+	auth gin.HandlerFunc // Par adresse IP, routes publiques
+	user gin.HandlerFunc // Par utilisateur authentifié
+}
+
+// setupAPIRoutes enregistre l'ensemble des routes de l'API sur le groupe versionné donné
+func setupAPIRoutes(api *gin.RouterGroup, handlers *Handlers, auditLogRepo repositories.AuditLogRepository, limits rateLimits) {
+	// Routes d'authentification (publiques)
+	SetupAuthRoutes(api, handlers.AuthHandler, limits.auth)
+
+	// Routes publiques pour l'inscription et la création de tickets
+	api.GET("/departments/active", handlers.DepartmentHandler.GetActive)
+	api.GET("/filiales/active", handlers.FilialeHandler.GetActive)
+	api.GET("/software/active", handlers.SoftwareHandler.GetActive)
+
+	// Route WebSocket pour les notifications en temps réel (authentification dans le handler)
+	// Note: Cette route doit être avant le middleware AuthMiddleware car elle utilise un protocole différent
+	if handlers.WebSocketHandler != nil {
+		api.GET("/ws", handlers.WebSocketHandler.HandleWebSocket)
+	}
+
+	// Fichiers du stockage local servis par URL signée (l'autorisation est portée par la signature)
+	if handlers.FileHandler != nil {
+		api.GET("/files/:namespace/*key", handlers.FileHandler.ServeSigned)
+	}
+
+	// Activation des comptes invités (lien d'invitation, public et limité par adresse IP)
+	if handlers.UserImportHandler != nil {
+		api.POST("/auth/invitations/accept", limits.auth, handlers.UserImportHandler.AcceptInvitation)
+	}
+
+	// Enquêtes de satisfaction des tickets clôturés (authentifiées par le token du lien envoyé au demandeur)
+	if handlers.TicketSatisfactionHandler != nil {
+		api.GET("/tickets/:id/satisfaction", limits.auth, handlers.TicketSatisfactionHandler.GetSurvey)
+		api.POST("/tickets/:id/satisfaction", limits.auth, handlers.TicketSatisfactionHandler.Submit)
+	}
+
+	// Fichiers d'avatar publics et cacheables (clé versionnée par le contenu)
+	if handlers.UserHandler != nil {
+		api.GET("/avatars/:key", handlers.UserHandler.ServeAvatarFile)
+	}
+
+	// Webhooks Jira entrants (authentifiés par secret partagé ou signature)
+	if handlers.JiraHandler != nil {
+		api.POST("/integrations/jira/webhook", handlers.JiraHandler.Webhook)
+	}
+
+	// Mises à jour du bot Telegram (authentifiées par secret partagé)
+	if handlers.TelegramHandler != nil {
+		api.POST("/integrations/telegram/webhook", handlers.TelegramHandler.Webhook)
+	}
+
+	// Webhooks GitHub / GitLab entrants (authentifiés par signature ou jeton)
+	if handlers.GitHandler != nil {
+		api.POST("/integrations/git/webhook", handlers.GitHandler.Webhook)
+	}
+
+	// Alertes des outils de supervision (authentifiées par clé d'API)
+	if handlers.MonitoringAlertHandler != nil {
+		api.POST("/integrations/alerts", handlers.MonitoringAlertHandler.Ingest)
+	}
+
+	// Inventaires des agents de découverte d'actifs (authentifiés par clé d'API)
+	if handlers.AssetDiscoveryHandler != nil {
+		api.POST("/integrations/discovery", handlers.AssetDiscoveryHandler.Ingest)
+	}
+
+	// Flux OData de reporting pour Power BI (authentifié par clé d'API)
+	if handlers.ReportingFeedHandler != nil {
+		api.GET("/odata", handlers.ReportingFeedHandler.ServiceDocument)
+		api.GET("/odata/:set", handlers.ReportingFeedHandler.EntitySet)
+	}
+
+	// Flux de calendrier ICS (authentifiés par le token contenu dans l'URL)
+	if handlers.CalendarFeedHandler != nil {
+		api.GET("/calendar/feeds/:token", handlers.CalendarFeedHandler.Feed)
+	}
+
+	// Retour OAuth de la connexion d'un agenda (utilisateur identifié par le paramètre state signé)
+	if handlers.CalendarSyncHandler != nil {
+		api.GET("/calendar/oauth/:provider/callback", handlers.CalendarSyncHandler.Callback)
+	}
+
+	// Page de statut publique des incidents majeurs (limitée par adresse IP)
+	if handlers.MajorIncidentHandler != nil {
+		api.GET("/status", limits.auth, handlers.MajorIncidentHandler.GetPublicStatus)
+	}
+
+	// Routes protégées (nécessitent authentification)
+	api.Use(middleware.AuthMiddleware())
+	api.Use(limits.user)
+	api.Use(middleware.PerfMiddleware())
+	api.Use(middleware.AuditLogMiddleware(auditLogRepo))
 	{
-		// Routes d'authentification (publiques)
-		SetupAuthRoutes(api, handlers.AuthHandler)
+		// Diagnostic
+		if handlers.DiagnosticHandler != nil {
+			api.GET("/diagnostic/it-users", handlers.DiagnosticHandler.GetITUsersInfo)
+		}
+
+		// Administration technique (niveau de log, ...)
+		SetupAdminRoutes(api, handlers.LoggingHandler, handlers.ConfigHandler, handlers.JobHandler, handlers.SchedulerHandler, handlers.AccessCheckHandler)
 
-		// Routes publiques pour l'inscription et la création de tickets
-		api.GET("/departments/active", handlers.DepartmentHandler.GetActive)
-		api.GET("/filiales/active", handlers.FilialeHandler.GetActive)
-		api.GET("/software/active", handlers.SoftwareHandler.GetActive)
+		// Utilisateurs
+		SetupUserRoutes(api, handlers.UserHandler)
 
-		// Route WebSocket pour les notifications en temps réel (authentification dans le handler)
-		// Note: Cette route doit être avant le middleware AuthMiddleware car elle utilise un protocole différent
-		if handlers.WebSocketHandler != nil {
-			api.GET("/ws", handlers.WebSocketHandler.HandleWebSocket)
+		if handlers.UserImportHandler != nil {
+			SetupUserImportRoutes(api, handlers.UserImportHandler)
 		}
 
-		// Routes protégées (nécessitent authentification)
-		api.Use(middleware.AuthMiddleware())
-		api.Use(middleware.PerfMiddleware())
-		api.Use(middleware.AuditLogMiddleware(auditLogRepo))
-		{
-			// Diagnostic
-			if handlers.DiagnosticHandler != nil {
-				api.GET("/diagnostic/it-users", handlers.DiagnosticHandler.GetITUsersInfo)
-			}
+		if handlers.UserPreferenceHandler != nil {
+			SetupUserPreferenceRoutes(api, handlers.UserPreferenceHandler)
+		}
+		if handlers.NotificationPreferenceHandler != nil {
+			SetupNotificationPreferenceRoutes(api, handlers.NotificationPreferenceHandler)
+		}
 
-			// Utilisateurs
-			SetupUserRoutes(api, handlers.UserHandler)
+		// Libellés localisés
+		if handlers.I18nHandler != nil {
+			SetupI18nRoutes(api, handlers.I18nHandler)
+		}
 
-			// Rôles
-			SetupRoleRoutes(api, handlers.RoleHandler)
+		// Résumé d'activité des utilisateurs
+		if handlers.UserActivityHandler != nil {
+			SetupUserActivityRoutes(api, handlers.UserActivityHandler)
+		}
 
-			// Permissions
-			SetupPermissionRoutes(api, handlers.PermissionHandler)
+		// Compétences et certifications
+		if handlers.SkillHandler != nil {
+			SetupSkillRoutes(api, handlers.SkillHandler)
+		}
 
-			// Tickets - Les routes spécifiques doivent être définies avant les routes génériques
-			// Donc on définit d'abord les routes de timesheet et delay-justification
-			SetupTicketTimesheetRoutes(api, handlers.TimesheetHandler)
-			SetupTicketDelayJustificationRoutes(api, handlers.DelayHandler)
-			SetupTicketAuditRoutes(api, handlers.AuditHandler)
-			// Puis les routes principales des tickets
-			SetupTicketRoutes(api, handlers.TicketHandler, handlers.TicketAttachmentHandler, handlers.TicketCategoryHandler, handlers.TicketSolutionHandler)
+		// Rôles
+		SetupRoleRoutes(api, handlers.RoleHandler)
 
-			// Tickets internes (départements non-IT) — route /panier enregistrée avant le groupe pour éviter que /:id capture "panier"
-			if handlers.TicketInternalHandler != nil {
-				api.GET("/ticket-internes/panier", handlers.TicketInternalHandler.GetMyPanier)
-				SetupTicketInternesRoutes(api, handlers.TicketInternalHandler)
-			}
+		// Permissions
+		SetupPermissionRoutes(api, handlers.PermissionHandler)
 
-			// Incidents
-			SetupIncidentRoutes(api, handlers.IncidentHandler)
+		// Tickets - Les routes spécifiques doivent être définies avant les routes génériques
+		// Donc on définit d'abord les routes de timesheet et delay-justification
+		SetupTicketTimesheetRoutes(api, handlers.TimesheetHandler)
+		SetupTicketDelayJustificationRoutes(api, handlers.DelayHandler)
+		SetupTicketAuditRoutes(api, handlers.AuditHandler)
+		if handlers.TicketViewHandler != nil {
+			SetupTicketViewRoutes(api, handlers.TicketViewHandler)
+		}
+		if handlers.TicketMergeHandler != nil {
+			SetupTicketMergeRoutes(api, handlers.TicketMergeHandler)
+		}
+		if handlers.TicketWatcherHandler != nil {
+			SetupTicketWatcherRoutes(api, handlers.TicketWatcherHandler)
+		}
+		if handlers.TicketChecklistHandler != nil {
+			SetupTicketChecklistRoutes(api, handlers.TicketChecklistHandler)
+		}
+		if handlers.TicketRelationHandler != nil {
+			SetupTicketRelationRoutes(api, handlers.TicketRelationHandler)
+		}
+		if handlers.TicketExportHandler != nil {
+			SetupTicketExportRoutes(api, handlers.TicketExportHandler)
+		}
+		if handlers.TicketTimelineHandler != nil {
+			SetupTicketTimelineRoutes(api, handlers.TicketTimelineHandler)
+		}
+		if handlers.RecurringTicketHandler != nil {
+			SetupRecurringTicketRoutes(api, handlers.RecurringTicketHandler)
+		}
+		if handlers.ProblemHandler != nil {
+			SetupProblemRoutes(api, handlers.ProblemHandler)
+		}
+		if handlers.ApprovalHandler != nil {
+			SetupApprovalRoutes(api, handlers.ApprovalHandler)
+		}
+		if handlers.MajorIncidentHandler != nil {
+			SetupMajorIncidentRoutes(api, handlers.MajorIncidentHandler)
+		}
+		// Puis les routes principales des tickets
+		SetupTicketRoutes(api, handlers.TicketHandler, handlers.TicketAttachmentHandler, handlers.TicketCategoryHandler, handlers.TicketSolutionHandler)
+
+		// Tickets internes (départements non-IT) — route /panier enregistrée avant le groupe pour éviter que /:id capture "panier"
+		if handlers.TicketInternalHandler != nil {
+			api.GET("/ticket-internes/panier", handlers.TicketInternalHandler.GetMyPanier)
+			SetupTicketInternesRoutes(api, handlers.TicketInternalHandler)
+		}
+
+		// Incidents
+		SetupIncidentRoutes(api, handlers.IncidentHandler)
+
+		// Changements
+		SetupChangeRoutes(api, handlers.ChangeHandler)
+
+		// Demandes de service
+		SetupServiceRequestRoutes(api, handlers.ServiceRequestHandler, handlers.ServiceRequestTypeHandler)
+
+		// Entrées de temps
+		SetupTimeEntryRoutes(api, handlers.TimeEntryHandler)
+
+		// Retards
+		SetupDelayRoutes(api, handlers.DelayHandler)
+		SetupUserDelayJustificationRoutes(api, handlers.DelayHandler)
+
+		// Actifs IT
+		SetupAssetRoutes(api, handlers.AssetHandler, handlers.AssetCategoryHandler, handlers.AssetSoftwareHandler)
+		if handlers.AssetRelationHandler != nil {
+			SetupAssetRelationRoutes(api, handlers.AssetRelationHandler)
+		}
+		if handlers.AssetReservationHandler != nil {
+			SetupAssetReservationRoutes(api, handlers.AssetReservationHandler)
+		}
+		if handlers.AssetImportHandler != nil {
+			SetupAssetImportRoutes(api, handlers.AssetImportHandler)
+		}
+		if handlers.MaintenancePlanHandler != nil {
+			SetupMaintenancePlanRoutes(api, handlers.MaintenancePlanHandler)
+		}
+
+		// SLA
+		SetupSLARoutes(api, handlers.SLAHandler)
+		if handlers.BusinessCalendarHandler != nil {
+			SetupBusinessCalendarRoutes(api, handlers.BusinessCalendarHandler)
+		}
+		if handlers.EscalationHandler != nil {
+			SetupEscalationRoutes(api, handlers.EscalationHandler)
+		}
+
+		// Notifications
+		SetupNotificationRoutes(api, handlers.NotificationHandler)
+
+		// Base de connaissances
+		SetupKnowledgeBaseRoutes(api, handlers.KnowledgeArticleHandler, handlers.KnowledgeCategoryHandler)
+		if handlers.KnowledgeDeflectionHandler != nil {
+			SetupKnowledgeDeflectionRoutes(api, handlers.KnowledgeDeflectionHandler)
+		}
+		if handlers.KnowledgeFeedbackHandler != nil {
+			SetupKnowledgeFeedbackRoutes(api, handlers.KnowledgeFeedbackHandler)
+		}
+		if handlers.KnowledgeAttachmentHandler != nil {
+			SetupKnowledgeAttachmentRoutes(api, handlers.KnowledgeAttachmentHandler)
+		}
+		if handlers.KnowledgePortalHandler != nil {
+			SetupKnowledgePortalKeyRoutes(api, handlers.KnowledgePortalHandler)
+		}
+
+		// Projets
+		SetupProjectRoutes(api, handlers.ProjectHandler)
+		if handlers.CapacityHandler != nil {
+			SetupCapacityRoutes(api, handlers.CapacityHandler)
+		}
+		if handlers.PortfolioHandler != nil {
+			SetupPortfolioRoutes(api, handlers.PortfolioHandler)
+		}
+		if handlers.ProjectTemplateHandler != nil {
+			SetupProjectTemplateRoutes(api, handlers.ProjectTemplateHandler)
+		}
+		if handlers.ProjectTaskRecurrenceHandler != nil {
+			SetupProjectTaskRecurrenceRoutes(api, handlers.ProjectTaskRecurrenceHandler)
+		}
+		if handlers.ProjectTaskTimerHandler != nil {
+			SetupProjectTaskTimerRoutes(api, handlers.ProjectTaskTimerHandler)
+		}
+		if handlers.TicketProjectTaskHandler != nil {
+			SetupTicketProjectTaskRoutes(api, handlers.TicketProjectTaskHandler)
+		}
+
+		// Déclarations journalières
+		SetupDailyDeclarationRoutes(api, handlers.DailyDeclarationHandler)
+
+		// Déclarations hebdomadaires
+		SetupWeeklyDeclarationRoutes(api, handlers.WeeklyDeclarationHandler)
+
+		// Performances
+		SetupPerformanceRoutes(api, handlers.PerformanceHandler)
+
+		// Rapports
+		SetupReportRoutes(api, handlers.ReportHandler)
+
+		// Recherche globale
+		SetupSearchRoutes(api, handlers.SearchHandler)
 
-			// Changements
-			SetupChangeRoutes(api, handlers.ChangeHandler)
+		// Statistiques
+		SetupStatisticsRoutes(api, handlers.StatisticsHandler)
 
-			// Demandes de service
-			SetupServiceRequestRoutes(api, handlers.ServiceRequestHandler, handlers.ServiceRequestTypeHandler)
+		// Logs d'audit
+		SetupAuditRoutes(api, handlers.AuditHandler)
 
-			// Entrées de temps
-			SetupTimeEntryRoutes(api, handlers.TimeEntryHandler)
+		// Paramétrage
+		SetupSettingsRoutes(api, handlers.SettingsHandler, handlers.RequestSourceHandler, handlers.BackupHandler)
 
-			// Retards
-			SetupDelayRoutes(api, handlers.DelayHandler)
-			SetupUserDelayJustificationRoutes(api, handlers.DelayHandler)
+		// Sièges
+		SetupOfficeRoutes(api, handlers.OfficeHandler)
 
-			// Actifs IT
-			SetupAssetRoutes(api, handlers.AssetHandler, handlers.AssetCategoryHandler, handlers.AssetSoftwareHandler)
+		// Départements
+		SetupDepartmentRoutes(api, handlers.DepartmentHandler)
 
-			// SLA
-			SetupSLARoutes(api, handlers.SLAHandler)
+		// Filiales
+		SetupFilialeRoutes(api, handlers.FilialeHandler, handlers.FilialeSoftwareHandler)
+		SetupFilialeSoftwareRoutes(api, handlers.FilialeSoftwareHandler)
 
-			// Notifications
-			SetupNotificationRoutes(api, handlers.NotificationHandler)
+		// Logiciels
+		SetupSoftwareRoutes(api, handlers.SoftwareHandler, handlers.FilialeSoftwareHandler)
+		if handlers.SoftwareReleaseHandler != nil {
+			SetupSoftwareReleaseRoutes(api, handlers.SoftwareReleaseHandler)
+		}
+		if handlers.SoftwareEnvironmentHandler != nil {
+			SetupSoftwareEnvironmentRoutes(api, handlers.SoftwareEnvironmentHandler)
+		}
+		if handlers.SupportContractHandler != nil {
+			SetupSupportContractRoutes(api, handlers.SupportContractHandler)
+		}
+		if handlers.SoftwareLicenseHandler != nil {
+			SetupSoftwareLicenseRoutes(api, handlers.SoftwareLicenseHandler)
+		}
 
-			// Base de connaissances
-			SetupKnowledgeBaseRoutes(api, handlers.KnowledgeArticleHandler, handlers.KnowledgeCategoryHandler)
+		// Synchronisation Jira
+		if handlers.JiraHandler != nil {
+			SetupJiraRoutes(api, handlers.JiraHandler)
+		}
+
+		// Clés d'API du flux OData de reporting
+		if handlers.ReportingFeedHandler != nil {
+			SetupReportingFeedRoutes(api, handlers.ReportingFeedHandler)
+		}
 
-			// Projets
-			SetupProjectRoutes(api, handlers.ProjectHandler)
+		// Commits et merge requests GitHub / GitLab liés aux tickets
+		if handlers.GitHandler != nil {
+			SetupGitRoutes(api, handlers.GitHandler)
+		}
 
-			// Déclarations journalières
-			SetupDailyDeclarationRoutes(api, handlers.DailyDeclarationHandler)
+		// Synchronisation incrémentale de l'application mobile
+		if handlers.SyncHandler != nil {
+			SetupSyncRoutes(api, handlers.SyncHandler)
+		}
 
-			// Déclarations hebdomadaires
-			SetupWeeklyDeclarationRoutes(api, handlers.WeeklyDeclarationHandler)
+		// Appareils mobiles enregistrés aux notifications push
+		if handlers.PushHandler != nil {
+			SetupPushRoutes(api, handlers.PushHandler)
+		}
 
-			// Performances
-			SetupPerformanceRoutes(api, handlers.PerformanceHandler)
+		// Imports depuis d'autres outils (GLPI, Zendesk, Freshdesk, fichiers)
+		if handlers.ImportHandler != nil {
+			SetupImportRoutes(api, handlers.ImportHandler)
+		}
 
-			// Rapports
-			SetupReportRoutes(api, handlers.ReportHandler)
+		// Flux de calendrier ICS (gestion)
+		if handlers.CalendarFeedHandler != nil {
+			SetupCalendarRoutes(api, handlers.CalendarFeedHandler)
+		}
 
-			// Recherche globale
-			SetupSearchRoutes(api, handlers.SearchHandler)
+		// Synchronisation des agendas Google et Microsoft
+		if handlers.CalendarSyncHandler != nil {
+			SetupCalendarSyncRoutes(api, handlers.CalendarSyncHandler)
+		}
 
-			// Statistiques
-			SetupStatisticsRoutes(api, handlers.StatisticsHandler)
+		// Bot Telegram (liaison des comptes)
+		if handlers.TelegramHandler != nil {
+			SetupTelegramRoutes(api, handlers.TelegramHandler)
+		}
 
-			// Logs d'audit
-			SetupAuditRoutes(api, handlers.AuditHandler)
+		// Canal WhatsApp Business (modèles de messages et consentements)
+		if handlers.WhatsAppHandler != nil {
+			SetupWhatsAppRoutes(api, handlers.WhatsAppHandler)
+		}
 
-			// Paramétrage
-			SetupSettingsRoutes(api, handlers.SettingsHandler, handlers.RequestSourceHandler, handlers.BackupHandler)
+		// Supervision (outils émetteurs et alertes reçues)
+		if handlers.MonitoringAlertHandler != nil {
+			SetupMonitoringAlertRoutes(api, handlers.MonitoringAlertHandler)
+		}
 
-			// Sièges
-			SetupOfficeRoutes(api, handlers.OfficeHandler)
+		// Découverte d'actifs (agents d'inventaire et écarts avec la CMDB)
+		if handlers.AssetDiscoveryHandler != nil {
+			SetupAssetDiscoveryRoutes(api, handlers.AssetDiscoveryHandler)
+		}
 
-			// Départements
-			SetupDepartmentRoutes(api, handlers.DepartmentHandler)
+		// Timesheet
+		SetupTimesheetRoutes(api, handlers.TimesheetHandler)
+		SetupUserTimesheetRoutes(api, handlers.TimesheetHandler)
+		SetupProjectTimesheetRoutes(api, handlers.TimesheetHandler)
+		if handlers.PayrollExportHandler != nil {
+			SetupPayrollExportRoutes(api, handlers.PayrollExportHandler)
+		}
+		if handlers.AbsenceHandler != nil {
+			SetupAbsenceRoutes(api, handlers.AbsenceHandler)
+		}
 
-			// Filiales
-			SetupFilialeRoutes(api, handlers.FilialeHandler, handlers.FilialeSoftwareHandler)
-			SetupFilialeSoftwareRoutes(api, handlers.FilialeSoftwareHandler)
+		// Webhooks sortants
+		if handlers.WebhookHandler != nil {
+			SetupWebhookRoutes(api, handlers.WebhookHandler)
+		}
 
-			// Logiciels
-			SetupSoftwareRoutes(api, handlers.SoftwareHandler, handlers.FilialeSoftwareHandler)
+		// Partages de tickets et de projets
+		if handlers.RecordShareHandler != nil {
+			SetupRecordShareRoutes(api, handlers.RecordShareHandler)
+		}
 
-			// Timesheet
-			SetupTimesheetRoutes(api, handlers.TimesheetHandler)
-			SetupUserTimesheetRoutes(api, handlers.TimesheetHandler)
-			SetupProjectTimesheetRoutes(api, handlers.TimesheetHandler)
+		// Délégations d'absence
+		if handlers.AccessDelegationHandler != nil {
+			SetupAccessDelegationRoutes(api, handlers.AccessDelegationHandler)
 		}
 	}
 }
 
 // Handlers contient toutes les instances de handlers
 type Handlers struct {
-	AuthHandler               *handlers.AuthHandler
-	UserHandler               *handlers.UserHandler
-	RoleHandler               *handlers.RoleHandler
-	PermissionHandler         *handlers.PermissionHandler
-	TicketHandler             *handlers.TicketHandler
-	TicketAttachmentHandler   *handlers.TicketAttachmentHandler
-	TicketCategoryHandler     *handlers.TicketCategoryHandler
-	TicketSolutionHandler     *handlers.TicketSolutionHandler
-	TicketInternalHandler     *handlers.TicketInternalHandler
-	IncidentHandler           *handlers.IncidentHandler
-	ChangeHandler             *handlers.ChangeHandler
-	ServiceRequestHandler     *handlers.ServiceRequestHandler
-	ServiceRequestTypeHandler *handlers.ServiceRequestTypeHandler
-	TimeEntryHandler          *handlers.TimeEntryHandler
-	DelayHandler              *handlers.DelayHandler
-	AssetHandler              *handlers.AssetHandler
-	AssetCategoryHandler      *handlers.AssetCategoryHandler
-	AssetSoftwareHandler      *handlers.AssetSoftwareHandler
-	SLAHandler                *handlers.SLAHandler
-	NotificationHandler       *handlers.NotificationHandler
-	KnowledgeArticleHandler   *handlers.KnowledgeArticleHandler
-	KnowledgeCategoryHandler  *handlers.KnowledgeCategoryHandler
-	ProjectHandler            *handlers.ProjectHandler
-	DailyDeclarationHandler   *handlers.DailyDeclarationHandler
-	WeeklyDeclarationHandler  *handlers.WeeklyDeclarationHandler
-	PerformanceHandler        *handlers.PerformanceHandler
-	ReportHandler             *handlers.ReportHandler
-	SearchHandler             *handlers.SearchHandler
-	StatisticsHandler         *handlers.StatisticsHandler
-	AuditHandler              *handlers.AuditHandler
-	SettingsHandler           *handlers.SettingsHandler
-	RequestSourceHandler      *handlers.RequestSourceHandler
-	BackupHandler             *handlers.BackupHandler
-	TimesheetHandler          *handlers.TimesheetHandler
-	OfficeHandler             *handlers.OfficeHandler
-	DepartmentHandler         *handlers.DepartmentHandler
-	FilialeHandler            *handlers.FilialeHandler
-	SoftwareHandler           *handlers.SoftwareHandler
-	FilialeSoftwareHandler    *handlers.FilialeSoftwareHandler
-	WebSocketHandler          *handlers.WebSocketHandler
-	DiagnosticHandler         *handlers.DiagnosticHandler
+	AuthHandler                   *handlers.AuthHandler
+	UserHandler                   *handlers.UserHandler
+	RoleHandler                   *handlers.RoleHandler
+	PermissionHandler             *handlers.PermissionHandler
+	TicketHandler                 *handlers.TicketHandler
+	TicketAttachmentHandler       *handlers.TicketAttachmentHandler
+	TicketCategoryHandler         *handlers.TicketCategoryHandler
+	TicketSolutionHandler         *handlers.TicketSolutionHandler
+	TicketInternalHandler         *handlers.TicketInternalHandler
+	IncidentHandler               *handlers.IncidentHandler
+	ChangeHandler                 *handlers.ChangeHandler
+	ServiceRequestHandler         *handlers.ServiceRequestHandler
+	ServiceRequestTypeHandler     *handlers.ServiceRequestTypeHandler
+	TimeEntryHandler              *handlers.TimeEntryHandler
+	DelayHandler                  *handlers.DelayHandler
+	AssetHandler                  *handlers.AssetHandler
+	AssetCategoryHandler          *handlers.AssetCategoryHandler
+	AssetSoftwareHandler          *handlers.AssetSoftwareHandler
+	SLAHandler                    *handlers.SLAHandler
+	NotificationHandler           *handlers.NotificationHandler
+	KnowledgeArticleHandler       *handlers.KnowledgeArticleHandler
+	KnowledgeCategoryHandler      *handlers.KnowledgeCategoryHandler
+	ProjectHandler                *handlers.ProjectHandler
+	DailyDeclarationHandler       *handlers.DailyDeclarationHandler
+	WeeklyDeclarationHandler      *handlers.WeeklyDeclarationHandler
+	PerformanceHandler            *handlers.PerformanceHandler
+	ReportHandler                 *handlers.ReportHandler
+	SearchHandler                 *handlers.SearchHandler
+	StatisticsHandler             *handlers.StatisticsHandler
+	AuditHandler                  *handlers.AuditHandler
+	SettingsHandler               *handlers.SettingsHandler
+	RequestSourceHandler          *handlers.RequestSourceHandler
+	BackupHandler                 *handlers.BackupHandler
+	TimesheetHandler              *handlers.TimesheetHandler
+	OfficeHandler                 *handlers.OfficeHandler
+	DepartmentHandler             *handlers.DepartmentHandler
+	FilialeHandler                *handlers.FilialeHandler
+	SoftwareHandler               *handlers.SoftwareHandler
+	FilialeSoftwareHandler        *handlers.FilialeSoftwareHandler
+	WebSocketHandler              *handlers.WebSocketHandler
+	DiagnosticHandler             *handlers.DiagnosticHandler
+	HealthHandler                 *handlers.HealthHandler
+	LoggingHandler                *handlers.LoggingHandler
+	ConfigHandler                 *handlers.ConfigHandler
+	FileHandler                   *handlers.FileHandler
+	WebhookHandler                *handlers.WebhookHandler
+	RecordShareHandler            *handlers.RecordShareHandler
+	JobHandler                    *handlers.JobHandler
+	SchedulerHandler              *handlers.SchedulerHandler
+	AccessCheckHandler            *handlers.AccessCheckHandler
+	AccessDelegationHandler       *handlers.AccessDelegationHandler
+	UserImportHandler             *handlers.UserImportHandler
+	UserPreferenceHandler         *handlers.UserPreferenceHandler
+	I18nHandler                   *handlers.I18nHandler
+	SkillHandler                  *handlers.SkillHandler
+	UserActivityHandler           *handlers.UserActivityHandler
+	SoftwareReleaseHandler        *handlers.SoftwareReleaseHandler
+	SoftwareEnvironmentHandler    *handlers.SoftwareEnvironmentHandler
+	SupportContractHandler        *handlers.SupportContractHandler
+	JiraHandler                   *handlers.JiraHandler
+	ImportHandler                 *handlers.ImportHandler
+	CalendarFeedHandler           *handlers.CalendarFeedHandler
+	CalendarSyncHandler           *handlers.CalendarSyncHandler
+	TelegramHandler               *handlers.TelegramHandler
+	WhatsAppHandler               *handlers.WhatsAppHandler
+	MonitoringAlertHandler        *handlers.MonitoringAlertHandler
+	GitHandler                    *handlers.GitHandler
+	ReportingFeedHandler          *handlers.ReportingFeedHandler
+	SyncHandler                   *handlers.SyncHandler
+	PushHandler                   *handlers.PushHandler
+	NotificationPreferenceHandler *handlers.NotificationPreferenceHandler
+	BusinessCalendarHandler       *handlers.BusinessCalendarHandler
+	EscalationHandler             *handlers.EscalationHandler
+	TicketViewHandler             *handlers.TicketViewHandler
+	TicketMergeHandler            *handlers.TicketMergeHandler
+	TicketWatcherHandler          *handlers.TicketWatcherHandler
+	TicketChecklistHandler        *handlers.TicketChecklistHandler
+	TicketSatisfactionHandler     *handlers.TicketSatisfactionHandler
+	TicketRelationHandler         *handlers.TicketRelationHandler
+	TicketExportHandler           *handlers.TicketExportHandler
+	TicketTimelineHandler         *handlers.TicketTimelineHandler
+	RecurringTicketHandler        *handlers.RecurringTicketHandler
+	ProblemHandler                *handlers.ProblemHandler
+	ApprovalHandler               *handlers.ApprovalHandler
+	MajorIncidentHandler          *handlers.MajorIncidentHandler
+	SoftwareLicenseHandler        *handlers.SoftwareLicenseHandler
+	AssetDiscoveryHandler         *handlers.AssetDiscoveryHandler
+	AssetRelationHandler          *handlers.AssetRelationHandler
+	AssetReservationHandler       *handlers.AssetReservationHandler
+	AssetImportHandler            *handlers.AssetImportHandler
+	MaintenancePlanHandler        *handlers.MaintenancePlanHandler
+	KnowledgeDeflectionHandler    *handlers.KnowledgeDeflectionHandler
+	KnowledgeFeedbackHandler      *handlers.KnowledgeFeedbackHandler
+	KnowledgeAttachmentHandler    *handlers.KnowledgeAttachmentHandler
+	KnowledgePortalHandler        *handlers.KnowledgePortalHandler
+	CapacityHandler               *handlers.CapacityHandler
+	PortfolioHandler              *handlers.PortfolioHandler
+	ProjectTemplateHandler        *handlers.ProjectTemplateHandler
+	ProjectTaskRecurrenceHandler  *handlers.ProjectTaskRecurrenceHandler
+	ProjectTaskTimerHandler       *handlers.ProjectTaskTimerHandler
+	TicketProjectTaskHandler      *handlers.TicketProjectTaskHandler
+	PayrollExportHandler          *handlers.PayrollExportHandler
+	AbsenceHandler                *handlers.AbsenceHandler
 }
@@ -10,6 +10,7 @@ import (
 func SetupSettingsRoutes(router *gin.RouterGroup, settingsHandler *handlers.SettingsHandler, requestSourceHandler *handlers.RequestSourceHandler, backupHandler *handlers.BackupHandler) {
 	settings := router.Group("/settings")
 	settings.Use(middleware.AuthMiddleware())
+	settings.Use(middleware.ETagMiddleware())
 	{
 		// Paramètres généraux
 		settings.GET("", settingsHandler.GetAll)
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupRecordShareRoutes configure les routes de partage des tickets et des projets
+func SetupRecordShareRoutes(router *gin.RouterGroup, shareHandler *handlers.RecordShareHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	{
+		tickets.GET("/:id/shares", shareHandler.GetTicketShares)
+		tickets.POST("/:id/shares", shareHandler.ShareTicket)
+		tickets.PUT("/:id/shares/:shareId", shareHandler.UpdateTicketShare)
+		tickets.DELETE("/:id/shares/:shareId", shareHandler.RevokeTicketShare)
+	}
+
+	projects := router.Group("/projects")
+	projects.Use(middleware.AuthMiddleware())
+	{
+		projects.GET("/:id/shares", shareHandler.GetProjectShares)
+		projects.POST("/:id/shares", shareHandler.ShareProject)
+		projects.PUT("/:id/shares/:shareId", shareHandler.UpdateProjectShare)
+		projects.DELETE("/:id/shares/:shareId", shareHandler.RevokeProjectShare)
+	}
+
+	shares := router.Group("/shares")
+	shares.Use(middleware.AuthMiddleware())
+	{
+		shares.GET("/with-me", shareHandler.GetSharedWithMe)
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupSkillRoutes configure les routes du catalogue de compétences et des profils de compétences
+func SetupSkillRoutes(router *gin.RouterGroup, skillHandler *handlers.SkillHandler) {
+	skills := router.Group("/skills")
+	skills.Use(middleware.AuthMiddleware())
+	{
+		skills.GET("", skillHandler.GetCatalog)
+		skills.GET("/search", skillHandler.Search) // Route spécifique avant /:id
+		skills.POST("", skillHandler.Create)
+		skills.PUT("/:id", skillHandler.Update)
+		skills.DELETE("/:id", skillHandler.Delete)
+	}
+
+	users := router.Group("/users")
+	users.Use(middleware.AuthMiddleware())
+	{
+		users.GET("/me/skills", skillHandler.GetUserSkills)
+		users.PUT("/me/skills/:skill_id", skillHandler.SetUserSkill)
+		users.DELETE("/me/skills/:skill_id", skillHandler.RemoveUserSkill)
+		users.GET("/:id/skills", skillHandler.GetUserSkills)
+		users.PUT("/:id/skills/:skill_id", skillHandler.SetUserSkill)
+		users.DELETE("/:id/skills/:skill_id", skillHandler.RemoveUserSkill)
+	}
+}
@@ -24,3 +24,18 @@ func SetupSLARoutes(router *gin.RouterGroup, slaHandler *handlers.SLAHandler) {
 		sla.POST("/recalculate", slaHandler.RecalculateSLAStatuses)
 	}
 }
+
+// SetupBusinessCalendarRoutes configure les routes des calendriers ouvrés utilisés par les SLA
+func SetupBusinessCalendarRoutes(router *gin.RouterGroup, calendarHandler *handlers.BusinessCalendarHandler) {
+	calendars := router.Group("/sla/calendars")
+	calendars.Use(middleware.AuthMiddleware())
+	{
+		calendars.GET("", calendarHandler.GetAll)
+		calendars.GET("/:id", calendarHandler.GetByID)
+		calendars.POST("", calendarHandler.Create)
+		calendars.PUT("/:id", calendarHandler.Update)
+		calendars.DELETE("/:id", calendarHandler.Delete)
+		calendars.POST("/:id/holidays", calendarHandler.AddHoliday)
+		calendars.DELETE("/:id/holidays/:holiday_id", calendarHandler.DeleteHoliday)
+	}
+}
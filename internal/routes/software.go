@@ -25,3 +25,70 @@ func SetupSoftwareRoutes(router *gin.RouterGroup, softwareHandler *handlers.Soft
 		software.DELETE("/:software_id", softwareHandler.Delete)
 	}
 }
+
+// SetupSoftwareReleaseRoutes configure les routes des versions publiées des logiciels
+func SetupSoftwareReleaseRoutes(router *gin.RouterGroup, releaseHandler *handlers.SoftwareReleaseHandler) {
+	software := router.Group("/software")
+	software.Use(middleware.AuthMiddleware())
+	{
+		software.GET("/:software_id/releases", releaseHandler.GetBySoftwareID)
+		software.POST("/:software_id/releases", releaseHandler.Create)
+	}
+
+	releases := router.Group("/software-releases")
+	releases.Use(middleware.AuthMiddleware())
+	{
+		releases.GET("/:id", releaseHandler.GetByID)
+		releases.PUT("/:id", releaseHandler.Update)
+		releases.DELETE("/:id", releaseHandler.Delete)
+		releases.POST("/:id/publish", releaseHandler.Publish)
+		releases.GET("/:id/tickets", releaseHandler.GetFixedTickets)
+		releases.POST("/:id/tickets", releaseHandler.LinkTickets)
+		releases.DELETE("/:id/tickets/:ticket_id", releaseHandler.UnlinkTicket)
+	}
+}
+
+// SetupSoftwareEnvironmentRoutes configure les routes des environnements des logiciels par filiale
+func SetupSoftwareEnvironmentRoutes(router *gin.RouterGroup, environmentHandler *handlers.SoftwareEnvironmentHandler) {
+	filiales := router.Group("/filiales")
+	filiales.Use(middleware.AuthMiddleware())
+	{
+		filiales.GET("/:filiale_id/environments", environmentHandler.GetByFiliale)
+		filiales.POST("/:filiale_id/environments", environmentHandler.Create)
+	}
+
+	environments := router.Group("/software-environments")
+	environments.Use(middleware.AuthMiddleware())
+	{
+		environments.GET("/:id", environmentHandler.GetByID)
+		environments.PUT("/:id", environmentHandler.Update)
+		environments.DELETE("/:id", environmentHandler.Delete)
+	}
+}
+
+// SetupSupportContractRoutes configure les routes des contrats de support logiciel
+func SetupSupportContractRoutes(router *gin.RouterGroup, contractHandler *handlers.SupportContractHandler) {
+	contracts := router.Group("/support-contracts")
+	contracts.Use(middleware.AuthMiddleware())
+	{
+		contracts.GET("", contractHandler.GetAll)
+		contracts.POST("", contractHandler.Create)
+		contracts.GET("/:id", contractHandler.GetByID)
+		contracts.PUT("/:id", contractHandler.Update)
+		contracts.DELETE("/:id", contractHandler.Delete)
+	}
+}
+
+// SetupSoftwareLicenseRoutes configure les routes des licences logicielles et du rapport de conformité
+func SetupSoftwareLicenseRoutes(router *gin.RouterGroup, licenseHandler *handlers.SoftwareLicenseHandler) {
+	licenses := router.Group("/software-licenses")
+	licenses.Use(middleware.AuthMiddleware())
+	{
+		licenses.GET("", licenseHandler.GetAll)
+		licenses.POST("", licenseHandler.Create)
+		licenses.GET("/compliance", licenseHandler.GetCompliance)
+		licenses.GET("/:id", licenseHandler.GetByID)
+		licenses.PUT("/:id", licenseHandler.Update)
+		licenses.DELETE("/:id", licenseHandler.Delete)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupSyncRoutes configure les routes de synchronisation incrémentale (application mobile)
+func SetupSyncRoutes(router *gin.RouterGroup, syncHandler *handlers.SyncHandler) {
+	sync := router.Group("/sync")
+	sync.Use(middleware.AuthMiddleware())
+	{
+		sync.GET("/changes", syncHandler.GetChanges)
+	}
+}
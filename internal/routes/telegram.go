@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+)
+
+// SetupTelegramRoutes configure les routes de liaison des comptes au bot Telegram
+func SetupTelegramRoutes(router *gin.RouterGroup, telegramHandler *handlers.TelegramHandler) {
+	telegram := router.Group("/integrations/telegram")
+	{
+		telegram.GET("", telegramHandler.GetStatus)
+		telegram.POST("/link", telegramHandler.CreateLink)
+		telegram.DELETE("/link", telegramHandler.Unlink)
+	}
+}
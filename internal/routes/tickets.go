@@ -4,12 +4,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mcicare/itsm-backend/internal/handlers"
 	"github.com/mcicare/itsm-backend/internal/middleware"
+	"github.com/mcicare/itsm-backend/internal/models"
 )
 
 // SetupTicketRoutes configure les routes des tickets
 func SetupTicketRoutes(router *gin.RouterGroup, ticketHandler *handlers.TicketHandler, ticketAttachmentHandler *handlers.TicketAttachmentHandler, ticketCategoryHandler *handlers.TicketCategoryHandler, ticketSolutionHandler *handlers.TicketSolutionHandler) {
 	tickets := router.Group("/tickets")
 	tickets.Use(middleware.AuthMiddleware())
+	tickets.Use(middleware.ETagMiddleware())
+	// Les tickets partagés en lecture seule ne peuvent pas être modifiés (routes dont :id est l'ID du ticket)
+	sharedWriteGuard := middleware.SharedWriteGuard(models.ShareResourceTicket)
 	{
 		tickets.GET("", ticketHandler.GetAll)
 		tickets.POST("", ticketHandler.Create)
@@ -33,41 +37,124 @@ func SetupTicketRoutes(router *gin.RouterGroup, ticketHandler *handlers.TicketHa
 
 		// Routes spécifiques avec plus de segments - doivent être avant la route générique :id
 		// Routes pour les pièces jointes
-		tickets.POST("/:id/attachments", ticketAttachmentHandler.UploadAttachment)
+		tickets.POST("/:id/attachments", sharedWriteGuard, ticketAttachmentHandler.UploadAttachment)
 		tickets.GET("/:id/attachments", ticketAttachmentHandler.GetAttachments)
 		tickets.GET("/:id/attachments/images", ticketAttachmentHandler.GetImages)
 		tickets.GET("/:id/attachments/:attachmentId", ticketAttachmentHandler.GetByID)
 		tickets.GET("/:id/attachments/:attachmentId/download", ticketAttachmentHandler.Download)
 		tickets.GET("/:id/attachments/:attachmentId/thumbnail", ticketAttachmentHandler.GetThumbnail)
-		tickets.PUT("/:id/attachments/:attachmentId", ticketAttachmentHandler.Update)
-		tickets.PUT("/:id/attachments/:attachmentId/set-primary", ticketAttachmentHandler.SetPrimary)
-		tickets.DELETE("/:id/attachments/:attachmentId", ticketAttachmentHandler.Delete)
-		tickets.PUT("/:id/attachments/reorder", ticketAttachmentHandler.Reorder)
+		tickets.GET("/:id/attachments/:attachmentId/url", ticketAttachmentHandler.GetDownloadURL)
+		tickets.PUT("/:id/attachments/:attachmentId", sharedWriteGuard, ticketAttachmentHandler.Update)
+		tickets.PUT("/:id/attachments/:attachmentId/set-primary", sharedWriteGuard, ticketAttachmentHandler.SetPrimary)
+		tickets.DELETE("/:id/attachments/:attachmentId", sharedWriteGuard, ticketAttachmentHandler.Delete)
+		tickets.PUT("/:id/attachments/reorder", sharedWriteGuard, ticketAttachmentHandler.Reorder)
 
 		// Routes pour les solutions (doivent être avant les routes génériques)
 		tickets.GET("/:id/solutions", ticketSolutionHandler.GetByTicketID)
-		tickets.POST("/:id/solutions", ticketSolutionHandler.Create)
+		tickets.POST("/:id/solutions", sharedWriteGuard, ticketSolutionHandler.Create)
+		tickets.POST("/:id/solutions/:sid/publish-as-article", sharedWriteGuard, ticketSolutionHandler.PublishAsArticle)
 		tickets.GET("/solutions/:id", ticketSolutionHandler.GetByID)
 		tickets.PUT("/solutions/:id", ticketSolutionHandler.Update)
 		tickets.DELETE("/solutions/:id", ticketSolutionHandler.Delete)
 		tickets.POST("/solutions/:id/publish-to-kb", ticketSolutionHandler.PublishToKB)
 
 		// Autres routes spécifiques
-		tickets.POST("/:id/assign", ticketHandler.Assign)
-		tickets.PUT("/:id/status", ticketHandler.ChangeStatus)
-		tickets.POST("/:id/validate", ticketHandler.ValidateTicket) // Valider un ticket résolu
-		tickets.POST("/:id/close", ticketHandler.Close)
-		tickets.POST("/:id/comments", ticketHandler.AddComment)
+		tickets.POST("/:id/assign", sharedWriteGuard, ticketHandler.Assign)
+		tickets.PUT("/:id/status", sharedWriteGuard, ticketHandler.ChangeStatus)
+		tickets.POST("/:id/validate", sharedWriteGuard, ticketHandler.ValidateTicket) // Valider un ticket résolu
+		tickets.POST("/:id/close", sharedWriteGuard, ticketHandler.Close)
+		tickets.POST("/:id/comments", sharedWriteGuard, ticketHandler.AddComment)
 		tickets.GET("/:id/comments", ticketHandler.GetComments)
-		tickets.PUT("/:id/comments/:commentId", ticketHandler.UpdateComment)
-		tickets.DELETE("/:id/comments/:commentId", ticketHandler.DeleteComment)
-		tickets.POST("/:id/reassign", ticketHandler.Reassign)
+		tickets.PUT("/:id/comments/:commentId", sharedWriteGuard, ticketHandler.UpdateComment)
+		tickets.DELETE("/:id/comments/:commentId", sharedWriteGuard, ticketHandler.DeleteComment)
+		tickets.POST("/:id/reassign", sharedWriteGuard, ticketHandler.Reassign)
 		tickets.GET("/:id/history", ticketHandler.GetHistory)
 
 		// Routes génériques (doivent être en dernier)
 		tickets.GET("/:id", ticketHandler.GetByID)
-		tickets.PUT("/:id", ticketHandler.Update)
-		tickets.DELETE("/:id", ticketHandler.Delete)
+		tickets.PUT("/:id", sharedWriteGuard, ticketHandler.Update)
+		tickets.DELETE("/:id", sharedWriteGuard, ticketHandler.Delete)
+	}
+}
+
+// SetupTicketExportRoutes configure la route d'export des tickets (hors ETag : le fichier est écrit en flux)
+func SetupTicketExportRoutes(router *gin.RouterGroup, ticketExportHandler *handlers.TicketExportHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	{
+		tickets.GET("/export", ticketExportHandler.Export)
+	}
+}
+
+// SetupTicketTimelineRoutes configure la route du fil d'activité des tickets
+func SetupTicketTimelineRoutes(router *gin.RouterGroup, ticketTimelineHandler *handlers.TicketTimelineHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	{
+		tickets.GET("/:id/timeline", ticketTimelineHandler.GetTimeline)
+	}
+}
+
+// SetupTicketMergeRoutes configure les routes de fusion des tickets en double
+func SetupTicketMergeRoutes(router *gin.RouterGroup, ticketMergeHandler *handlers.TicketMergeHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	{
+		tickets.POST("/:id/merge", middleware.SharedWriteGuard(models.ShareResourceTicket), ticketMergeHandler.Merge)
+		tickets.GET("/:id/duplicates", ticketMergeHandler.GetDuplicates)
+	}
+}
+
+// SetupTicketWatcherRoutes configure les routes des abonnés aux tickets
+func SetupTicketWatcherRoutes(router *gin.RouterGroup, ticketWatcherHandler *handlers.TicketWatcherHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	sharedWriteGuard := middleware.SharedWriteGuard(models.ShareResourceTicket)
+	{
+		tickets.GET("/:id/watchers", ticketWatcherHandler.GetWatchers)
+		tickets.POST("/:id/watchers", sharedWriteGuard, ticketWatcherHandler.Watch)
+		tickets.DELETE("/:id/watchers/:userId", sharedWriteGuard, ticketWatcherHandler.Unwatch)
+	}
+}
+
+// SetupTicketChecklistRoutes configure les routes de la checklist des tickets
+func SetupTicketChecklistRoutes(router *gin.RouterGroup, ticketChecklistHandler *handlers.TicketChecklistHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	sharedWriteGuard := middleware.SharedWriteGuard(models.ShareResourceTicket)
+	{
+		tickets.GET("/:id/checklist", ticketChecklistHandler.GetChecklist)
+		tickets.POST("/:id/checklist", sharedWriteGuard, ticketChecklistHandler.AddItem)
+		tickets.PUT("/:id/checklist/reorder", sharedWriteGuard, ticketChecklistHandler.Reorder)
+		tickets.PUT("/:id/checklist/:itemId", sharedWriteGuard, ticketChecklistHandler.UpdateItem)
+		tickets.POST("/:id/checklist/:itemId/toggle", sharedWriteGuard, ticketChecklistHandler.ToggleItem)
+		tickets.DELETE("/:id/checklist/:itemId", sharedWriteGuard, ticketChecklistHandler.DeleteItem)
+	}
+}
+
+// SetupTicketRelationRoutes configure les routes des relations entre tickets
+func SetupTicketRelationRoutes(router *gin.RouterGroup, ticketRelationHandler *handlers.TicketRelationHandler) {
+	tickets := router.Group("/tickets")
+	tickets.Use(middleware.AuthMiddleware())
+	sharedWriteGuard := middleware.SharedWriteGuard(models.ShareResourceTicket)
+	{
+		tickets.GET("/:id/relations", ticketRelationHandler.GetRelations)
+		tickets.POST("/:id/relations", sharedWriteGuard, ticketRelationHandler.Link)
+		tickets.DELETE("/:id/relations/:relationId", sharedWriteGuard, ticketRelationHandler.Unlink)
+	}
+}
+
+// SetupTicketViewRoutes configure les routes des vues de tickets enregistrées
+func SetupTicketViewRoutes(router *gin.RouterGroup, ticketViewHandler *handlers.TicketViewHandler) {
+	views := router.Group("/tickets/views")
+	views.Use(middleware.AuthMiddleware())
+	{
+		views.GET("", ticketViewHandler.GetAll)
+		views.POST("", ticketViewHandler.Create)
+		views.GET("/:id/results", ticketViewHandler.GetResults)
+		views.GET("/:id", ticketViewHandler.GetByID)
+		views.PUT("/:id", ticketViewHandler.Update)
+		views.DELETE("/:id", ticketViewHandler.Delete)
 	}
 }
 
@@ -36,6 +36,7 @@ func SetupTimesheetRoutes(router *gin.RouterGroup, timesheetHandler *handlers.Ti
 		timesheet.GET("/weekly/:week/tasks", timesheetHandler.GetWeeklyTasks)
 		timesheet.GET("/weekly/:week/summary", timesheetHandler.GetWeeklySummary)
 		timesheet.GET("/weekly/:week/daily-breakdown", timesheetHandler.GetWeeklyDailyBreakdown)
+		timesheet.POST("/weekly/:week/submit", timesheetHandler.SubmitWeeklyDeclaration)
 		timesheet.POST("/weekly/:week/validate", timesheetHandler.ValidateWeeklyDeclaration)
 		timesheet.GET("/weekly/:week/validation-status", timesheetHandler.GetWeeklyValidationStatus)
 
@@ -97,3 +98,12 @@ func SetupProjectTimesheetRoutes(router *gin.RouterGroup, timesheetHandler *hand
 	}
 }
 
+
+// SetupPayrollExportRoutes configure l'export des heures validées vers la paie
+func SetupPayrollExportRoutes(router *gin.RouterGroup, payrollExportHandler *handlers.PayrollExportHandler) {
+	export := router.Group("/timesheet/export")
+	export.Use(middleware.AuthMiddleware())
+	{
+		export.GET("", payrollExportHandler.Export)
+	}
+}
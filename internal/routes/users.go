@@ -13,13 +13,22 @@ func SetupUserRoutes(router *gin.RouterGroup, userHandler *handlers.UserHandler)
 	{
 		users.GET("", userHandler.GetAll)
 		users.GET("/for-ticket-creation", userHandler.GetForTicketCreation) // Route spécifique avant /:id
+		users.GET("/me/team", userHandler.GetMyTeam)
+		users.POST("/me/avatar", userHandler.UploadMyAvatar)
+		users.DELETE("/me/avatar", userHandler.DeleteMyAvatar)
+		users.PUT("/me/out-of-office", userHandler.SetOutOfOffice)
+		users.DELETE("/me/out-of-office", userHandler.ClearOutOfOffice)
 		users.GET("/:id", userHandler.GetByID)
 		users.POST("", userHandler.Create)
 		users.PUT("/:id", userHandler.Update)
 		users.DELETE("/:id", userHandler.Delete)
+		users.POST("/:id/offboard", userHandler.Offboard)
+		users.PUT("/:id/out-of-office", userHandler.SetOutOfOffice)
+		users.DELETE("/:id/out-of-office", userHandler.ClearOutOfOffice)
 		users.PUT("/:id/password", userHandler.ChangePassword)
 		users.PUT("/:id/reset-password", userHandler.ResetPassword)
 		users.GET("/:id/permissions", userHandler.GetPermissions)
+		users.GET("/:id/org-chart", userHandler.GetOrgChart)
 		users.PUT("/:id/permissions", userHandler.UpdatePermissions)
 		users.POST("/:id/avatar", userHandler.UploadAvatar)
 		users.GET("/:id/avatar", userHandler.GetAvatar)
@@ -28,6 +37,35 @@ func SetupUserRoutes(router *gin.RouterGroup, userHandler *handlers.UserHandler)
 	}
 }
 
+// SetupUserImportRoutes configure la route d'import en masse des utilisateurs
+func SetupUserImportRoutes(router *gin.RouterGroup, importHandler *handlers.UserImportHandler) {
+	users := router.Group("/users")
+	users.Use(middleware.AuthMiddleware())
+	{
+		users.POST("/import", importHandler.Import)
+	}
+}
+
+// SetupUserPreferenceRoutes configure les routes des préférences de l'utilisateur connecté
+func SetupUserPreferenceRoutes(router *gin.RouterGroup, preferenceHandler *handlers.UserPreferenceHandler) {
+	users := router.Group("/users")
+	users.Use(middleware.AuthMiddleware())
+	{
+		users.GET("/me/preferences", preferenceHandler.GetMyPreferences)
+		users.PUT("/me/preferences", preferenceHandler.UpdateMyPreferences)
+	}
+}
+
+// SetupNotificationPreferenceRoutes configure les routes des réglages de notifications par email de l'utilisateur connecté
+func SetupNotificationPreferenceRoutes(router *gin.RouterGroup, notificationPreferenceHandler *handlers.NotificationPreferenceHandler) {
+	users := router.Group("/users")
+	users.Use(middleware.AuthMiddleware())
+	{
+		users.GET("/me/notification-preferences", notificationPreferenceHandler.GetMyNotificationPreferences)
+		users.PUT("/me/notification-preferences", notificationPreferenceHandler.UpdateMyNotificationPreferences)
+	}
+}
+
 // SetupUserDelayJustificationRoutes configure les routes de justification de retard pour les utilisateurs
 func SetupUserDelayJustificationRoutes(router *gin.RouterGroup, delayHandler *handlers.DelayHandler) {
 	users := router.Group("/users")
@@ -38,3 +76,13 @@ func SetupUserDelayJustificationRoutes(router *gin.RouterGroup, delayHandler *ha
 		users.GET("/:id/delay-justifications", delayHandler.GetJustificationsByUserID)
 	}
 }
+
+// SetupUserActivityRoutes configure les routes du résumé d'activité des utilisateurs
+func SetupUserActivityRoutes(router *gin.RouterGroup, activityHandler *handlers.UserActivityHandler) {
+	users := router.Group("/users")
+	users.Use(middleware.AuthMiddleware())
+	{
+		users.GET("/me/activity", activityHandler.GetActivity)
+		users.GET("/:id/activity", activityHandler.GetActivity)
+	}
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+	"github.com/mcicare/itsm-backend/internal/middleware"
+)
+
+// SetupWebhookRoutes configure les routes des abonnements webhook
+func SetupWebhookRoutes(router *gin.RouterGroup, webhookHandler *handlers.WebhookHandler) {
+	webhooks := router.Group("/webhooks")
+	webhooks.Use(middleware.AuthMiddleware())
+	{
+		webhooks.GET("", webhookHandler.GetAll)
+		webhooks.GET("/events", webhookHandler.GetEventTypes)
+		webhooks.POST("", webhookHandler.Create)
+		webhooks.GET("/:id", webhookHandler.GetByID)
+		webhooks.PUT("/:id", webhookHandler.Update)
+		webhooks.DELETE("/:id", webhookHandler.Delete)
+		webhooks.POST("/:id/test", webhookHandler.Ping)
+		webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+		webhooks.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+	}
+}
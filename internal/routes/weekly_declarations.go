@@ -14,6 +14,10 @@ func SetupWeeklyDeclarationRoutes(router *gin.RouterGroup, weeklyDeclarationHand
 		weeklyDeclarations.GET("/:id", weeklyDeclarationHandler.GetByID)
 		weeklyDeclarations.GET("/users/:user_id", weeklyDeclarationHandler.GetByUserID)
 		weeklyDeclarations.GET("/users/:user_id/by-week", weeklyDeclarationHandler.GetByUserIDAndWeek)
+		weeklyDeclarations.GET("/:id/history", weeklyDeclarationHandler.GetHistory)
 		weeklyDeclarations.POST("/:id/validate", weeklyDeclarationHandler.Validate)
+		weeklyDeclarations.POST("/:id/approve", weeklyDeclarationHandler.Approve)
+		weeklyDeclarations.POST("/:id/reject", weeklyDeclarationHandler.Reject)
+		weeklyDeclarations.POST("/:id/unlock", weeklyDeclarationHandler.Unlock)
 	}
 }
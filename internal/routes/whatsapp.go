@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/handlers"
+)
+
+// SetupWhatsAppRoutes configure les routes du canal WhatsApp Business
+func SetupWhatsAppRoutes(router *gin.RouterGroup, whatsAppHandler *handlers.WhatsAppHandler) {
+	whatsapp := router.Group("/integrations/whatsapp")
+	{
+		whatsapp.GET("/templates", whatsAppHandler.GetTemplates)
+		whatsapp.POST("/templates", whatsAppHandler.CreateTemplate)
+		whatsapp.PUT("/templates/:id", whatsAppHandler.UpdateTemplate)
+		whatsapp.DELETE("/templates/:id", whatsAppHandler.DeleteTemplate)
+		whatsapp.GET("/opt-in", whatsAppHandler.GetOptIn)
+		whatsapp.PUT("/opt-in", whatsAppHandler.UpdateOptIn)
+		whatsapp.DELETE("/opt-in", whatsAppHandler.DeleteOptIn)
+	}
+}
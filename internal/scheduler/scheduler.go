@@ -0,0 +1,331 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/robfig/cron/v3"
+)
+
+// settingsCategory catégorie des paramètres de planification (clés scheduler.<tâche>.schedule / .enabled)
+const settingsCategory = "scheduler"
+
+// Erreurs retournées par le planificateur
+var (
+	ErrJobNotFound       = errors.New("tâche planifiée introuvable")
+	ErrJobAlreadyRunning = errors.New("la tâche est déjà en cours d'exécution")
+)
+
+// JobFunc exécute une tâche planifiée
+type JobFunc func(ctx context.Context) error
+
+// Job décrit une tâche périodique enregistrée auprès du planificateur
+type Job struct {
+	Name            string  // Identifiant stable (utilisé dans les clés de paramètres)
+	Description     string  // Description affichée dans l'administration
+	DefaultSchedule string  // Expression cron par défaut (5 champs ou @daily, @every 1h, ...)
+	Run             JobFunc // Traitement
+}
+
+// JobStatus représente l'état d'une tâche planifiée
+type JobStatus struct {
+	Name            string                  `json:"name"`
+	Description     string                  `json:"description"`
+	Schedule        string                  `json:"schedule"`
+	DefaultSchedule string                  `json:"default_schedule"`
+	Enabled         bool                    `json:"enabled"`
+	Running         bool                    `json:"running"`
+	NextRunAt       *time.Time              `json:"next_run_at,omitempty"`
+	LastRun         *models.ScheduledJobRun `json:"last_run,omitempty"`
+}
+
+// entry état interne d'une tâche enregistrée
+type entry struct {
+	job      Job
+	schedule string
+	enabled  bool
+	entryID  cron.EntryID
+	running  atomic.Bool
+}
+
+// Scheduler gère l'ensemble des tâches périodiques (SLA, rappels, résumés, sauvegardes, purges, ...)
+// Les planifications sont stockées dans les paramètres (table settings) et modifiables à chaud
+// Une seule instance doit exécuter le planificateur (SCHEDULER_ENABLED=false sur les autres)
+type Scheduler struct {
+	cron         *cron.Cron
+	settingsRepo repositories.SettingsRepository
+	runRepo      repositories.ScheduledJobRunRepository
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+	started bool
+}
+
+// New crée un planificateur
+func New(settingsRepo repositories.SettingsRepository, runRepo repositories.ScheduledJobRunRepository) *Scheduler {
+	return &Scheduler{
+		cron:         cron.New(cron.WithLocation(timezone.Default())),
+		settingsRepo: settingsRepo,
+		runRepo:      runRepo,
+		entries:      make(map[string]*entry),
+	}
+}
+
+// Register enregistre une tâche ; la planification effective est lue dans les paramètres (valeur par défaut sinon)
+func (s *Scheduler) Register(job Job) {
+	schedule, enabled := s.loadSettings(job)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &entry{job: job, schedule: schedule, enabled: enabled}
+	s.entries[job.Name] = e
+	if s.started {
+		s.scheduleLocked(e)
+	}
+}
+
+// Start démarre l'exécution périodique des tâches activées
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	for _, e := range s.entries {
+		s.scheduleLocked(e)
+	}
+	s.started = true
+	s.cron.Start()
+}
+
+// Stop arrête le planificateur et attend la fin des exécutions en cours
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
+
+// List retourne l'état de toutes les tâches, triées par nom
+func (s *Scheduler) List() []JobStatus {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.entries))
+	for name := range s.entries {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+
+	statuses := make([]JobStatus, 0, len(names))
+	for _, name := range names {
+		if status, err := s.Get(name); err == nil {
+			statuses = append(statuses, *status)
+		}
+	}
+	return statuses
+}
+
+// Get retourne l'état d'une tâche
+func (s *Scheduler) Get(name string) (*JobStatus, error) {
+	s.mu.RLock()
+	e, ok := s.entries[name]
+	if !ok {
+		s.mu.RUnlock()
+		return nil, ErrJobNotFound
+	}
+	status := &JobStatus{
+		Name:            e.job.Name,
+		Description:     e.job.Description,
+		Schedule:        e.schedule,
+		DefaultSchedule: e.job.DefaultSchedule,
+		Enabled:         e.enabled,
+		Running:         e.running.Load(),
+	}
+	if e.entryID != 0 {
+		if next := s.cron.Entry(e.entryID).Next; !next.IsZero() {
+			status.NextRunAt = &next
+		}
+	}
+	s.mu.RUnlock()
+
+	if lastRun, err := s.runRepo.FindLastByJob(name); err == nil {
+		status.LastRun = lastRun
+	}
+	return status, nil
+}
+
+// Runs retourne l'historique des exécutions d'une tâche
+func (s *Scheduler) Runs(name string, limit int) ([]models.ScheduledJobRun, error) {
+	s.mu.RLock()
+	_, ok := s.entries[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.runRepo.FindByJob(name, limit)
+}
+
+// Update modifie la planification et/ou l'activation d'une tâche, persiste dans les paramètres et replanifie
+func (s *Scheduler) Update(name, schedule string, enabled *bool) (*JobStatus, error) {
+	if schedule != "" {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			return nil, fmt.Errorf("expression cron invalide: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrJobNotFound
+	}
+	if schedule != "" {
+		e.schedule = schedule
+	}
+	if enabled != nil {
+		e.enabled = *enabled
+	}
+	if s.started {
+		s.scheduleLocked(e)
+	}
+	schedule, isEnabled := e.schedule, e.enabled
+	s.mu.Unlock()
+
+	if err := s.settingsRepo.SetValue(scheduleKey(name), schedule); err != nil {
+		return nil, fmt.Errorf("enregistrement de la planification: %w", err)
+	}
+	if err := s.settingsRepo.SetValue(enabledKey(name), strconv.FormatBool(isEnabled)); err != nil {
+		return nil, fmt.Errorf("enregistrement de l'activation: %w", err)
+	}
+	return s.Get(name)
+}
+
+// RunNow lance immédiatement une tâche en arrière-plan (déclenchement manuel)
+func (s *Scheduler) RunNow(name string, triggeredByID *uint) error {
+	s.mu.RLock()
+	e, ok := s.entries[name]
+	s.mu.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+	if !e.running.CompareAndSwap(false, true) {
+		return ErrJobAlreadyRunning
+	}
+	go s.execute(e, "manual", triggeredByID)
+	return nil
+}
+
+// scheduleLocked (re)crée l'entrée cron d'une tâche ; appelé avec s.mu verrouillé
+func (s *Scheduler) scheduleLocked(e *entry) {
+	if e.entryID != 0 {
+		s.cron.Remove(e.entryID)
+		e.entryID = 0
+	}
+	if !e.enabled {
+		return
+	}
+	id, err := s.cron.AddFunc(e.schedule, func() {
+		if !e.running.CompareAndSwap(false, true) {
+			slog.Warn("scheduled_job_skipped", "job", e.job.Name, "reason", "already_running")
+			return
+		}
+		s.execute(e, "cron", nil)
+	})
+	if err != nil {
+		slog.Error("scheduled_job_invalid_schedule", "job", e.job.Name, "schedule", e.schedule, "error", err)
+		return
+	}
+	e.entryID = id
+}
+
+// execute exécute une tâche et enregistre le résultat ; e.running doit avoir été positionné par l'appelant
+func (s *Scheduler) execute(e *entry, trigger string, triggeredByID *uint) {
+	defer e.running.Store(false)
+
+	run := &models.ScheduledJobRun{
+		JobName:       e.job.Name,
+		Trigger:       trigger,
+		TriggeredByID: triggeredByID,
+		Status:        "running",
+		StartedAt:     time.Now(),
+	}
+	if err := s.runRepo.Create(run); err != nil {
+		slog.Error("scheduled_job_run_create_failed", "job", e.job.Name, "error", err)
+	}
+
+	err := safeRun(e.job.Run)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = "success"
+	if err != nil {
+		run.Status = "failed"
+		run.Error = err.Error()
+		slog.Error("scheduled_job_failed", "job", e.job.Name, "trigger", trigger, "duration_ms", run.DurationMs, "error", err)
+	} else {
+		slog.Info("scheduled_job_completed", "job", e.job.Name, "trigger", trigger, "duration_ms", run.DurationMs)
+	}
+	if run.ID != 0 {
+		if err := s.runRepo.Update(run); err != nil {
+			slog.Error("scheduled_job_run_update_failed", "job", e.job.Name, "error", err)
+		}
+	}
+}
+
+// loadSettings lit la planification d'une tâche dans les paramètres et crée les paramètres manquants
+func (s *Scheduler) loadSettings(job Job) (string, bool) {
+	schedule := job.DefaultSchedule
+	if setting, err := s.settingsRepo.FindByKey(scheduleKey(job.Name)); err == nil {
+		if _, parseErr := cron.ParseStandard(setting.Value); parseErr == nil {
+			schedule = setting.Value
+		} else {
+			slog.Warn("scheduled_job_invalid_schedule", "job", job.Name, "schedule", setting.Value, "error", parseErr)
+		}
+	} else {
+		_ = s.settingsRepo.Create(&models.Setting{
+			Key:         scheduleKey(job.Name),
+			Value:       job.DefaultSchedule,
+			Type:        "string",
+			Category:    settingsCategory,
+			Description: "Planification (cron) : " + job.Description,
+		})
+	}
+
+	enabled := true
+	if setting, err := s.settingsRepo.FindByKey(enabledKey(job.Name)); err == nil {
+		enabled = setting.Value != "false"
+	} else {
+		_ = s.settingsRepo.Create(&models.Setting{
+			Key:         enabledKey(job.Name),
+			Value:       "true",
+			Type:        "boolean",
+			Category:    settingsCategory,
+			Description: "Activation : " + job.Description,
+		})
+	}
+	return schedule, enabled
+}
+
+// safeRun exécute la tâche en convertissant une panique en erreur
+func safeRun(run JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(context.Background())
+}
+
+func scheduleKey(name string) string { return "scheduler." + name + ".schedule" }
+func enabledKey(name string) string  { return "scheduler." + name + ".enabled" }
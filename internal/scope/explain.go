@@ -0,0 +1,94 @@
+package scope
+
+import "fmt"
+
+// FilialeRuleNotApplied indique que le filtrage par filiale n'est pas appliqué (projets d'un utilisateur sans filiale)
+const FilialeRuleNotApplied = "not_applied"
+
+// ScopeExplanation décrit comment le périmètre d'un type de ressource est calculé pour un utilisateur
+// Utilisé pour le diagnostic des droits (GET /admin/access-check)
+type ScopeExplanation struct {
+	ResourceType    string   `json:"resource_type"`
+	FilialeRule     string   `json:"filiale_rule"`     // filter, global, own_filiale, no_filiale, not_applied
+	Branch          string   `json:"branch"`           // Permission ayant déterminé le périmètre (vide = aucun résultat)
+	Description     string   `json:"description"`      // Explication lisible de la branche
+	ViewPermissions []string `json:"view_permissions"` // Permissions de vue détenues pour ce type de ressource
+	SharedIDs       []uint   `json:"shared_ids"`       // Ressources ajoutées par des partages actifs
+	Warnings        []string `json:"warnings"`         // Causes fréquentes de liste vide
+}
+
+// ticketViewPermissions permissions de vue des tickets, par priorité
+var ticketViewPermissions = []string{"tickets.view_all", "tickets.view_filiale", "tickets.view_team", "tickets.view_own", "tickets.create"}
+
+// projectViewPermissions permissions de vue des projets, par priorité
+var projectViewPermissions = []string{"projects.view_all", "projects.view", "projects.view_team", "projects.view_own"}
+
+// branchDescriptions explication de chaque branche de périmètre
+var branchDescriptions = map[string]string{
+	"tickets.view_all":     "Tous les tickets (filtrés par filiale selon la règle de filiale)",
+	"tickets.view_filiale": "Tous les tickets de la filiale",
+	"tickets.view_team":    "Tickets des demandeurs du département, plus ceux créés par l'utilisateur ou qui lui sont assignés",
+	"tickets.view_own":     "Tickets créés par l'utilisateur ou qui lui sont assignés",
+	"tickets.create":       "Tickets créés par l'utilisateur uniquement (aucune permission de vue)",
+	"projects.view_all":    "Tous les projets (filtrés par filiale si l'utilisateur en a une)",
+	"projects.view":        "Tous les projets (permission historique, filtrés par filiale si l'utilisateur en a une)",
+	"projects.view_team":   "Projets impliquant un membre du département (création, membre, tâche, ticket lié)",
+	"projects.view_own":    "Projets impliquant l'utilisateur (création, membre, tâche, ticket lié)",
+}
+
+// ExplainScope décrit le périmètre appliqué à un scope pour un type de ressource (ticket ou project)
+// La branche est calculée par les mêmes fonctions que ApplyTicketScope / ApplyProjectScope
+func ExplainScope(resourceType string, scope *QueryScope) (*ScopeExplanation, error) {
+	explanation := &ScopeExplanation{
+		ResourceType:    resourceType,
+		ViewPermissions: []string{},
+		SharedIDs:       scope.SharedIDs(resourceType),
+		Warnings:        []string{},
+	}
+	if explanation.SharedIDs == nil {
+		explanation.SharedIDs = []uint{}
+	}
+
+	var candidates []string
+	switch resourceType {
+	case ShareResourceTicket:
+		candidates = ticketViewPermissions
+		explanation.FilialeRule = filialeRule(scope)
+		explanation.Branch = ticketViewPermission(scope)
+		if scope.HasPermission("tickets.view_team") && scope.DepartmentID == nil {
+			explanation.Warnings = append(explanation.Warnings, "tickets.view_team ignorée : l'utilisateur n'a pas de département")
+		}
+	case ShareResourceProject:
+		candidates = projectViewPermissions
+		explanation.FilialeRule = FilialeRuleNotApplied
+		if scope.FilialeID != nil {
+			explanation.FilialeRule = filialeRule(scope)
+		}
+		explanation.Branch = projectViewPermission(scope)
+		if scope.HasPermission("projects.view_team") && scope.DepartmentID == nil {
+			explanation.Warnings = append(explanation.Warnings, "projects.view_team ignorée : l'utilisateur n'a pas de département")
+		}
+	default:
+		return nil, fmt.Errorf("type de ressource non pris en charge: %s", resourceType)
+	}
+
+	for _, permission := range candidates {
+		if scope.HasPermission(permission) {
+			explanation.ViewPermissions = append(explanation.ViewPermissions, permission)
+		}
+	}
+
+	if explanation.FilialeRule == FilialeRuleNone {
+		explanation.Warnings = append(explanation.Warnings, "Aucune filiale (utilisateur, rôle ou département) et aucune permission globale : aucun résultat")
+	}
+	if explanation.Branch == "" {
+		explanation.Description = "Aucune permission de vue : aucun résultat"
+		explanation.Warnings = append(explanation.Warnings, "Le rôle ne possède aucune permission de vue pour ce type de ressource")
+	} else {
+		explanation.Description = branchDescriptions[explanation.Branch]
+	}
+	if len(explanation.SharedIDs) > 0 {
+		explanation.Description += fmt.Sprintf(", plus %d élément(s) partagé(s)", len(explanation.SharedIDs))
+	}
+	return explanation, nil
+}
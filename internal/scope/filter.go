@@ -2,6 +2,7 @@ package scope
 
 import (
 	"log"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -13,24 +14,16 @@ import (
 func ApplyFilialeScope(db *gorm.DB, scope *QueryScope, tableName, filialeColumn string) *gorm.DB {
 	query := db
 
-	// Si un filtre de filiale spécifique est demandé (pour les rapports filtrés)
-	if scope.FilterFilialeID != nil {
+	switch filialeRule(scope) {
+	case FilialeRuleFilter:
+		// Un filtre de filiale spécifique est demandé (pour les rapports filtrés)
 		query = query.Where(tableName+"."+filialeColumn+" = ?", *scope.FilterFilialeID)
 		return query
-	}
-
-	// Si l'utilisateur a la permission de voir globalement (IT MCI CARE CI)
-	// Permissions qui donnent accès à toutes les filiales :
-	// - reports.view_global : rapports globaux groupe
-	// - tickets.resolve_all : résoudre tous les tickets
-	// - reports.compare_filiales : comparer entre filiales
-	if scope.HasAnyPermission("reports.view_global", "tickets.resolve_all", "reports.compare_filiales") {
+	case FilialeRuleGlobal:
 		// Pas de filtre, voir toutes les filiales
 		return query
-	}
-
-	// Sinon, filtrer par la filiale de l'utilisateur
-	if scope.FilialeID != nil {
+	case FilialeRuleOwn:
+		// Filtrer par la filiale de l'utilisateur
 		query = query.Where(tableName+"."+filialeColumn+" = ?", *scope.FilialeID)
 		return query
 	}
@@ -42,6 +35,33 @@ func ApplyFilialeScope(db *gorm.DB, scope *QueryScope, tableName, filialeColumn
 	return query
 }
 
+// Règles de filtrage par filiale appliquées par ApplyFilialeScope
+const (
+	FilialeRuleFilter = "filter"      // Filtre explicite (FilterFilialeID)
+	FilialeRuleGlobal = "global"      // Toutes les filiales (permissions globales)
+	FilialeRuleOwn    = "own_filiale" // Filiale de l'utilisateur
+	FilialeRuleNone   = "no_filiale"  // Aucune filiale : aucun résultat
+)
+
+// filialeRule détermine la règle de filtrage par filiale d'un scope
+func filialeRule(scope *QueryScope) string {
+	if scope.FilterFilialeID != nil {
+		return FilialeRuleFilter
+	}
+	// Si l'utilisateur a la permission de voir globalement (IT MCI CARE CI)
+	// Permissions qui donnent accès à toutes les filiales :
+	// - reports.view_global : rapports globaux groupe
+	// - tickets.resolve_all : résoudre tous les tickets
+	// - reports.compare_filiales : comparer entre filiales
+	if scope.HasAnyPermission("reports.view_global", "tickets.resolve_all", "reports.compare_filiales") {
+		return FilialeRuleGlobal
+	}
+	if scope.FilialeID != nil {
+		return FilialeRuleOwn
+	}
+	return FilialeRuleNone
+}
+
 // assigneesTableChecker est une fonction qui vérifie si la table ticket_assignees existe
 // Cette fonction peut être injectée depuis l'extérieur pour éviter les cycles d'importation
 var assigneesTableChecker func() bool
@@ -63,8 +83,13 @@ func assigneesTableExists() bool {
 
 // ApplyTicketScope applique les filtres de scope sur une requête de tickets
 // Cette fonction détermine automatiquement quels tickets l'utilisateur peut voir
-// selon ses permissions
+// selon ses permissions, plus les tickets partagés avec lui
 func ApplyTicketScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
+	return applyWithShares(db, scope, "tickets", ShareResourceTicket, applyTicketScope)
+}
+
+// applyTicketScope applique le périmètre des tickets défini par les permissions
+func applyTicketScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	query := db
 
 	// Périmètre forcé pour le tableau de bord (department / filiale / global)
@@ -98,28 +123,22 @@ func ApplyTicketScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	// Appliquer le filtrage par filiale en premier
 	query = ApplyFilialeScope(query, scope, "tickets", "filiale_id")
 
-	// Diagnostic : quel branche de permission est utilisée (pour debug liste vide)
-	hasViewAll := scope.HasPermission("tickets.view_all")
-	hasViewFiliale := scope.HasPermission("tickets.view_filiale")
-	hasViewTeam := scope.HasPermission("tickets.view_team")
-	hasViewOwn := scope.HasPermission("tickets.view_own")
-	hasCreate := scope.HasPermission("tickets.create")
-	log.Printf("[scope] ApplyTicketScope: user=%d FilialeID=%v view_all=%v view_filiale=%v view_team=%v view_own=%v create=%v",
-		scope.UserID, scope.FilialeID, hasViewAll, hasViewFiliale, hasViewTeam, hasViewOwn, hasCreate)
+	// Diagnostic : quel branche de permission est utilisée (pour debug liste vide ; voir aussi GET /admin/access-check)
+	branch := ticketViewPermission(scope)
+	log.Printf("[scope] ApplyTicketScope: user=%d FilialeID=%v branche=%q", scope.UserID, scope.FilialeID, branch)
 
-	// Si l'utilisateur a la permission de voir tous les tickets, pas de filtre supplémentaire
-	if hasViewAll {
+	switch branch {
+	case "tickets.view_all":
+		// Si l'utilisateur a la permission de voir tous les tickets, pas de filtre supplémentaire
 		return query
-	}
 
-	// Si l'utilisateur peut voir tous les tickets de sa filiale (DSI filiale) : ApplyFilialeScope a déjà filtré par filiale
-	if hasViewFiliale {
+	case "tickets.view_filiale":
+		// Si l'utilisateur peut voir tous les tickets de sa filiale (DSI filiale) : ApplyFilialeScope a déjà filtré par filiale
 		return query
-	}
 
-	// Si l'utilisateur peut voir les tickets de son département (et éventuellement view_own)
-	// Inclure aussi les tickets qu'il a créés ou qui lui sont assignés (ex. rôle délégué filiale)
-	if scope.HasPermission("tickets.view_team") && scope.DepartmentID != nil {
+	case "tickets.view_team":
+		// Si l'utilisateur peut voir les tickets de son département (et éventuellement view_own)
+		// Inclure aussi les tickets qu'il a créés ou qui lui sont assignés (ex. rôle délégué filiale)
 		query = query.Joins("LEFT JOIN users ON users.id = tickets.requester_id")
 		if assigneesTableExists() {
 			query = query.Where(
@@ -133,10 +152,9 @@ func ApplyTicketScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 			)
 		}
 		return query
-	}
 
-	// Si l'utilisateur ne peut voir que ses propres tickets
-	if scope.HasPermission("tickets.view_own") {
+	case "tickets.view_own":
+		// Si l'utilisateur ne peut voir que ses propres tickets
 		// Voir les tickets créés par l'utilisateur, assignés à l'utilisateur,
 		// ou où l'utilisateur est dans la liste des assignés (si la table existe)
 		if assigneesTableExists() {
@@ -151,11 +169,10 @@ func ApplyTicketScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 			)
 		}
 		return query
-	}
 
-	// Si l'utilisateur a tickets.create mais pas de permission de vue explicite,
-	// il peut au moins voir les tickets qu'il a créés (logique : si on peut créer, on peut voir ce qu'on crée)
-	if scope.HasPermission("tickets.create") {
+	case "tickets.create":
+		// Si l'utilisateur a tickets.create mais pas de permission de vue explicite,
+		// il peut au moins voir les tickets qu'il a créés (logique : si on peut créer, on peut voir ce qu'on crée)
 		query = query.Where("tickets.created_by_id = ?", scope.UserID)
 		return query
 	}
@@ -166,6 +183,24 @@ func ApplyTicketScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	return query
 }
 
+// ticketViewPermission retourne la permission qui détermine le périmètre des tickets (vide = aucun ticket visible)
+// L'ordre des tests fixe la priorité des branches de ApplyTicketScope
+func ticketViewPermission(scope *QueryScope) string {
+	switch {
+	case scope.HasPermission("tickets.view_all"):
+		return "tickets.view_all"
+	case scope.HasPermission("tickets.view_filiale"):
+		return "tickets.view_filiale"
+	case scope.HasPermission("tickets.view_team") && scope.DepartmentID != nil:
+		return "tickets.view_team"
+	case scope.HasPermission("tickets.view_own"):
+		return "tickets.view_own"
+	case scope.HasPermission("tickets.create"):
+		return "tickets.create"
+	}
+	return ""
+}
+
 // applyTicketScopeTeamOrOwn applique le filtre view_team ou view_own sur la table tickets.
 // Utilisé par ApplyTicketScopeForCategory pour incidents, service_requests et changes.
 func applyTicketScopeTeamOrOwn(db *gorm.DB, scope *QueryScope, permTeam, permOwn string) *gorm.DB {
@@ -291,16 +326,28 @@ func ApplyReportScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 		return query
 	}
 
-	// Si l'utilisateur peut voir les rapports de son département
-	if scope.HasPermission("reports.view_team") && scope.DepartmentID != nil {
+	// Si l'utilisateur peut voir les rapports de son département ou de son équipe hiérarchique
+	if scope.HasPermission("reports.view_team") && (scope.DepartmentID != nil || len(scope.TeamUserIDs) > 0) {
 		// Pour les rapports basés sur les tickets, on filtre par département du demandeur
 		// ET par filiale de l'utilisateur
-		query = query.Joins("LEFT JOIN users ON users.id = tickets.requester_id").
-			Where("users.department_id = ?", *scope.DepartmentID)
-		if scope.FilialeID != nil {
-			query = query.Where("tickets.filiale_id = ?", *scope.FilialeID)
+		var conds []string
+		var args []interface{}
+		if scope.DepartmentID != nil {
+			cond := "users.department_id = ?"
+			args = append(args, *scope.DepartmentID)
+			if scope.FilialeID != nil {
+				cond += " AND tickets.filiale_id = ?"
+				args = append(args, *scope.FilialeID)
+			}
+			conds = append(conds, "("+cond+")")
 		}
-		return query
+		// Tickets demandés ou traités par les collaborateurs du responsable
+		if len(scope.TeamUserIDs) > 0 {
+			conds = append(conds, "tickets.requester_id IN ? OR tickets.assigned_to_id IN ?")
+			args = append(args, scope.TeamUserIDs, scope.TeamUserIDs)
+		}
+		return query.Joins("LEFT JOIN users ON users.id = tickets.requester_id").
+			Where("("+strings.Join(conds, " OR ")+")", args...)
 	}
 
 	// Par défaut, ne rien retourner
@@ -358,6 +405,11 @@ func ApplyUserScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 // ApplyTicketScopeToTable applique le scope sur une requête qui utilise Table("tickets")
 // Cette fonction est utile pour les requêtes qui utilisent Table() au lieu de Model()
 func ApplyTicketScopeToTable(db *gorm.DB, scope *QueryScope) *gorm.DB {
+	return applyWithShares(db, scope, "tickets", ShareResourceTicket, applyTicketScopeToTable)
+}
+
+// applyTicketScopeToTable applique le périmètre des tickets défini par les permissions (requêtes Table("tickets"))
+func applyTicketScopeToTable(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	query := db
 
 	// Périmètre forcé pour le tableau de bord
@@ -644,6 +696,53 @@ func ApplyChangeScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	return query
 }
 
+// ApplyProblemScope applique les filtres de scope sur une requête de problèmes
+// Cette fonction détermine automatiquement quels problèmes l'utilisateur peut voir
+// selon ses permissions. Les problèmes sont filtrés via leurs tickets associés.
+func ApplyProblemScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
+	query := db
+
+	// Si l'utilisateur a la permission de voir tous les problèmes (ou view legacy)
+	if scope.HasPermission("problems.view_all") || scope.HasPermission("problems.view") {
+		return query
+	}
+
+	// Si l'utilisateur peut voir les problèmes de son équipe
+	if scope.HasPermission("problems.view_team") && scope.DepartmentID != nil {
+		// Filtrer par département du demandeur via le ticket associé
+		query = query.Joins("INNER JOIN tickets ON tickets.id = problems.ticket_id").
+			Joins("LEFT JOIN users ON users.id = tickets.requester_id").
+			Where("users.department_id = ?", *scope.DepartmentID)
+		return query
+	}
+
+	// Si l'utilisateur ne peut voir que ses propres problèmes (liés à ses tickets)
+	if scope.HasPermission("problems.view_own") {
+		// Filtrer via le ticket associé
+		query = query.Joins("INNER JOIN tickets ON tickets.id = problems.ticket_id")
+
+		// Voir les problèmes dont le ticket est créé par l'utilisateur, assigné à l'utilisateur,
+		// ou où l'utilisateur est dans la liste des assignés (si la table existe)
+		if assigneesTableExists() {
+			query = query.Where(
+				"tickets.created_by_id = ? OR tickets.assigned_to_id = ? OR EXISTS (SELECT 1 FROM ticket_assignees ta WHERE ta.ticket_id = tickets.id AND ta.user_id = ?)",
+				scope.UserID, scope.UserID, scope.UserID,
+			)
+		} else {
+			query = query.Where(
+				"tickets.created_by_id = ? OR tickets.assigned_to_id = ?",
+				scope.UserID, scope.UserID,
+			)
+		}
+		return query
+	}
+
+	// Par défaut, si aucune permission de vue n'est trouvée, ne rien retourner
+	// (sécurité par défaut : ne rien montrer)
+	query = query.Where("1 = 0")
+	return query
+}
+
 // ApplyKnowledgeScope applique les filtres de scope sur une requête d'articles de la base de connaissances
 // Cette fonction détermine automatiquement quels articles l'utilisateur peut voir selon ses permissions
 func ApplyKnowledgeScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
@@ -777,7 +876,12 @@ func ApplyTimeEntryScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 // Il élargit le scope par rapport à ApplyTimeEntryScope afin que les utilisateurs avec
 // timesheet.validate voient les entrées qu'ils sont habilités à valider (ex. entrées
 // des membres de leur département, ou toutes si validateur sans département).
+// Un responsable voit en plus les entrées de son équipe hiérarchique.
 func ApplyTimeEntryScopeForPendingValidation(db *gorm.DB, scope *QueryScope) *gorm.DB {
+	return applyWithTeam(db, scope, "time_entries", "user_id", applyTimeEntryScopeForPendingValidation)
+}
+
+func applyTimeEntryScopeForPendingValidation(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	query := db
 
 	if scope.HasPermission("timesheet.view_all") {
@@ -787,7 +891,7 @@ func ApplyTimeEntryScopeForPendingValidation(db *gorm.DB, scope *QueryScope) *go
 	if scope.HasPermission("timesheet.view_team") && scope.DepartmentID != nil {
 		query = query.Joins("INNER JOIN tickets ON tickets.id = time_entries.ticket_id").
 			Joins("LEFT JOIN users ON users.id = tickets.requester_id").
-			Where("(users.department_id = ?) OR (EXISTS (SELECT 1 FROM users u_te ON u_te.id = time_entries.user_id AND u_te.department_id = ?))",
+			Where("(users.department_id = ?) OR (EXISTS (SELECT 1 FROM users u_te WHERE u_te.id = time_entries.user_id AND u_te.department_id = ?))",
 				*scope.DepartmentID, *scope.DepartmentID)
 		return query
 	}
@@ -805,7 +909,7 @@ func ApplyTimeEntryScopeForPendingValidation(db *gorm.DB, scope *QueryScope) *go
 		if assigneesTableExists() {
 			if scope.HasPermission("timesheet.validate") && scope.DepartmentID != nil {
 				query = query.Where(
-					"(time_entries.user_id = ? OR tickets.created_by_id = ? OR tickets.assigned_to_id = ? OR EXISTS (SELECT 1 FROM ticket_assignees ta WHERE ta.ticket_id = tickets.id AND ta.user_id = ?)) OR (EXISTS (SELECT 1 FROM users u_te ON u_te.id = time_entries.user_id AND u_te.department_id = ?))",
+					"(time_entries.user_id = ? OR tickets.created_by_id = ? OR tickets.assigned_to_id = ? OR EXISTS (SELECT 1 FROM ticket_assignees ta WHERE ta.ticket_id = tickets.id AND ta.user_id = ?)) OR (EXISTS (SELECT 1 FROM users u_te WHERE u_te.id = time_entries.user_id AND u_te.department_id = ?))",
 					scope.UserID, scope.UserID, scope.UserID, scope.UserID, *scope.DepartmentID)
 			} else {
 				query = query.Where(
@@ -815,7 +919,7 @@ func ApplyTimeEntryScopeForPendingValidation(db *gorm.DB, scope *QueryScope) *go
 		} else {
 			if scope.HasPermission("timesheet.validate") && scope.DepartmentID != nil {
 				query = query.Where(
-					"(time_entries.user_id = ? OR tickets.created_by_id = ? OR tickets.assigned_to_id = ?) OR (EXISTS (SELECT 1 FROM users u_te ON u_te.id = time_entries.user_id AND u_te.department_id = ?))",
+					"(time_entries.user_id = ? OR tickets.created_by_id = ? OR tickets.assigned_to_id = ?) OR (EXISTS (SELECT 1 FROM users u_te WHERE u_te.id = time_entries.user_id AND u_te.department_id = ?))",
 					scope.UserID, scope.UserID, scope.UserID, *scope.DepartmentID)
 			} else {
 				query = query.Where(
@@ -915,7 +1019,13 @@ func ApplyAuditScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 // ApplyProjectScope applique les filtres de scope sur une requête de projets
 // Cette fonction détermine automatiquement quels projets l'utilisateur peut voir
 // selon ses permissions. Les projets sont filtrés via leurs tickets associés.
+// Les projets partagés avec l'utilisateur sont ajoutés au périmètre.
 func ApplyProjectScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
+	return applyWithShares(db, scope, "projects", ShareResourceProject, applyProjectScope)
+}
+
+// applyProjectScope applique le périmètre des projets défini par les permissions
+func applyProjectScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	query := db
 
 	// Périmètre forcé pour le tableau de bord
@@ -945,13 +1055,13 @@ func ApplyProjectScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 		query = ApplyFilialeScope(query, scope, "projects", "filiale_id")
 	}
 
-	// Si l'utilisateur a la permission de voir tous les projets (ou view legacy)
-	if scope.HasPermission("projects.view_all") || scope.HasPermission("projects.view") {
+	switch projectViewPermission(scope) {
+	case "projects.view_all", "projects.view":
+		// Si l'utilisateur a la permission de voir tous les projets (ou view legacy)
 		return query
-	}
 
-	// Si l'utilisateur peut voir les projets de son équipe (chef de département : projets où lui ou un membre du département est membre, assigné à une tâche, ou a des tickets liés)
-	if scope.HasPermission("projects.view_team") && scope.DepartmentID != nil {
+	case "projects.view_team":
+		// Si l'utilisateur peut voir les projets de son équipe (chef de département : projets où lui ou un membre du département est membre, assigné à une tâche, ou a des tickets liés)
 		deptID := *scope.DepartmentID
 		// Projets créés par un membre du département, ou avec un membre du département comme membre du projet, assigné à une tâche, ou ayant un ticket lié
 		byCreatedBy := "projects.created_by_id IN (SELECT id FROM users WHERE department_id = ? AND is_active = 1)"
@@ -961,10 +1071,9 @@ func ApplyProjectScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 		byTicket := "EXISTS (SELECT 1 FROM ticket_projects tp INNER JOIN tickets t ON t.id = tp.ticket_id LEFT JOIN users u ON u.id = t.requester_id WHERE tp.project_id = projects.id AND u.department_id = ?)"
 		query = query.Where("("+byCreatedBy+" OR "+byMember+" OR "+byTaskAssignee+" OR "+byTicket+")", deptID, deptID, deptID, deptID, deptID)
 		return query
-	}
 
-	// Si l'utilisateur ne peut voir que ses propres projets (créateur, membre, assigné à une tâche, ou tickets liés)
-	if scope.HasPermission("projects.view_own") {
+	case "projects.view_own":
+		// Si l'utilisateur ne peut voir que ses propres projets (créateur, membre, assigné à une tâche, ou tickets liés)
 		// Projets créés par l'utilisateur, ou où il est membre (project_members), assigné à une tâche, ou a des tickets liés
 		byCreatedBy := "projects.created_by_id = ?"
 		byMember := "EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = projects.id AND pm.user_id = ?)"
@@ -995,6 +1104,22 @@ func ApplyProjectScope(db *gorm.DB, scope *QueryScope) *gorm.DB {
 	return query
 }
 
+// projectViewPermission retourne la permission qui détermine le périmètre des projets (vide = aucun projet visible)
+// L'ordre des tests fixe la priorité des branches de ApplyProjectScope
+func projectViewPermission(scope *QueryScope) string {
+	switch {
+	case scope.HasPermission("projects.view_all"):
+		return "projects.view_all"
+	case scope.HasPermission("projects.view"):
+		return "projects.view"
+	case scope.HasPermission("projects.view_team") && scope.DepartmentID != nil:
+		return "projects.view_team"
+	case scope.HasPermission("projects.view_own"):
+		return "projects.view_own"
+	}
+	return ""
+}
+
 // ApplySLAScope applique les filtres de scope sur une requête de violations SLA
 // Cette fonction détermine automatiquement quelles violations SLA l'utilisateur peut voir
 // selon ses permissions du module sla. Les violations sont filtrées via leurs tickets associés.
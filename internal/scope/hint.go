@@ -0,0 +1,71 @@
+package scope
+
+import "strings"
+
+// Périmètres forcés du tableau de bord (paramètre de requête scope=...), du plus restreint au plus large
+const (
+	DashboardScopeDepartment = "department"
+	DashboardScopeFiliale    = "filiale"
+	DashboardScopeGlobal     = "global"
+)
+
+// dashboardScopeRank ordonne les périmètres forcés (un périmètre ne peut pas dépasser celui des permissions)
+var dashboardScopeRank = map[string]int{
+	DashboardScopeDepartment: 1,
+	DashboardScopeFiliale:    2,
+	DashboardScopeGlobal:     3,
+}
+
+// maxDashboardScope retourne le périmètre le plus large accordé par les permissions (rang 0 = aucun)
+// view_all / reports.view_global : global ; view_filiale / projects.view : filiale ; view_team / view_department : département
+func (s *QueryScope) maxDashboardScope() int {
+	max := 0
+	for _, permission := range s.Permissions {
+		rank := 0
+		switch {
+		case strings.HasSuffix(permission, ".view_all"), permission == "reports.view_global":
+			rank = dashboardScopeRank[DashboardScopeGlobal]
+		case strings.HasSuffix(permission, ".view_filiale"), permission == "projects.view":
+			rank = dashboardScopeRank[DashboardScopeFiliale]
+		case strings.HasSuffix(permission, ".view_team"), strings.HasSuffix(permission, ".view_department"):
+			rank = dashboardScopeRank[DashboardScopeDepartment]
+		}
+		if rank > max {
+			max = rank
+		}
+	}
+	return max
+}
+
+// ApplyDashboardHint force le périmètre du tableau de bord (department, filiale ou global)
+// Le périmètre n'est accepté que s'il ne dépasse pas celui accordé par les permissions et que l'utilisateur
+// a le rattachement nécessaire (département, filiale) ; sinon il est ignoré et retourne false
+func (s *QueryScope) ApplyDashboardHint(hint string) bool {
+	rank, ok := dashboardScopeRank[hint]
+	if !ok || rank > s.maxDashboardScope() {
+		return false
+	}
+	if hint == DashboardScopeDepartment && s.DepartmentID == nil {
+		return false
+	}
+	if hint == DashboardScopeFiliale && s.FilialeID == nil {
+		return false
+	}
+	s.DashboardScopeHint = hint
+	return true
+}
+
+// Restrict retourne une copie du scope limitée aux permissions données (ex: « Mon tableau de bord »)
+// Seule l'identité est conservée (utilisateur, département, filiale) ; les partages et filtres ne le sont pas
+func (s *QueryScope) Restrict(hint string, permissions ...string) *QueryScope {
+	return &QueryScope{
+		UserID:             s.UserID,
+		DepartmentID:       s.DepartmentID,
+		FilialeID:          s.FilialeID,
+		Role:               s.Role,
+		Permissions:        permissions,
+		IsResolver:         s.IsResolver,
+		DepartmentIsIT:     s.DepartmentIsIT,
+		DashboardScopeHint: hint,
+	}
+}
@@ -0,0 +1,94 @@
+package scope
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPermissionsUnavailable indique que les permissions n'ont pas pu être chargées (base indisponible, ...)
+// À distinguer d'un rôle sans permission : la requête doit échouer (503) plutôt que recevoir un périmètre erroné
+var ErrPermissionsUnavailable = errors.New("permissions indisponibles")
+
+// PermissionsLoader charge les permissions d'un rôle par son nom
+// Un rôle inexistant ou sans permission retourne une liste vide sans erreur
+type PermissionsLoader func(roleName string) ([]string, error)
+
+// defaultPermissionsTTL durée de vie par défaut des permissions en cache
+const defaultPermissionsTTL = time.Minute
+
+// permissionsEntry permissions d'un rôle en cache
+type permissionsEntry struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+var (
+	permissionsMu     sync.RWMutex
+	permissionsLoader PermissionsLoader
+	permissionsTTL    = defaultPermissionsTTL
+	permissionsCache  = make(map[string]permissionsEntry)
+	// permissionsGeneration est incrémenté à chaque invalidation : un chargement commencé avant n'est pas mis en cache
+	permissionsGeneration uint64
+)
+
+// SetPermissionsLoader définit la fonction de chargement des permissions et la durée du cache (ttl <= 0 = valeur par défaut)
+// Cette fonction doit être appelée au démarrage de l'application (injection pour éviter les cycles d'importation)
+func SetPermissionsLoader(loader PermissionsLoader, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultPermissionsTTL
+	}
+	permissionsMu.Lock()
+	defer permissionsMu.Unlock()
+	permissionsLoader = loader
+	permissionsTTL = ttl
+	permissionsCache = make(map[string]permissionsEntry)
+	permissionsGeneration++
+}
+
+// InvalidatePermissions vide le cache des rôles donnés, ou de tous les rôles si aucun nom n'est fourni
+// Appelé lors des modifications de rôles et de permissions
+func InvalidatePermissions(roleNames ...string) {
+	permissionsMu.Lock()
+	defer permissionsMu.Unlock()
+	if len(roleNames) == 0 {
+		permissionsCache = make(map[string]permissionsEntry)
+	} else {
+		for _, name := range roleNames {
+			delete(permissionsCache, name)
+		}
+	}
+	permissionsGeneration++
+}
+
+// PermissionsForRole retourne les permissions d'un rôle (depuis le cache si possible)
+// Retourne une erreur ErrPermissionsUnavailable si elles n'ont pas pu être chargées
+func PermissionsForRole(roleName string) ([]string, error) {
+	permissionsMu.RLock()
+	entry, ok := permissionsCache[roleName]
+	loader := permissionsLoader
+	generation := permissionsGeneration
+	permissionsMu.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return append([]string(nil), entry.permissions...), nil
+	}
+	if loader == nil {
+		return nil, fmt.Errorf("%w: chargeur de permissions non initialisé", ErrPermissionsUnavailable)
+	}
+
+	permissions, err := loader(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: rôle %s: %v", ErrPermissionsUnavailable, roleName, err)
+	}
+	permissions = append([]string(nil), permissions...)
+
+	permissionsMu.Lock()
+	if generation == permissionsGeneration {
+		permissionsCache[roleName] = permissionsEntry{permissions: permissions, expiresAt: time.Now().Add(permissionsTTL)}
+	}
+	permissionsMu.Unlock()
+
+	return append([]string(nil), permissions...), nil
+}
@@ -2,8 +2,10 @@ package scope
 
 import (
 	"log"
+	"time"
 
 	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/timezone"
 )
 
 // QueryScope représente le contexte de requête avec les permissions et attributs de l'utilisateur
@@ -26,10 +28,23 @@ type QueryScope struct {
 	FilterFilialeID *uint
 	// DashboardScopeHint force le périmètre pour le tableau de bord : "department" | "filiale" | "global" (vide = comportement par permissions)
 	DashboardScopeHint string
+	// Shares partages actifs (tickets, projets) ajoutés au périmètre défini par les permissions
+	Shares []SharedRecord
+	// TeamUserIDs collaborateurs rattachés à l'utilisateur (directement ou non) via manager_id,
+	// ainsi que ceux des responsables absents dont il est le suppléant
+	TeamUserIDs []uint
+	// Location fuseau horaire de l'utilisateur (préférence, sinon filiale), utilisé pour les bornes de journée
+	Location *time.Location
 }
 
 // NewQueryScopeFromUser crée un QueryScope à partir d'un modèle User
-func NewQueryScopeFromUser(user *models.User) *QueryScope {
+// Retourne ErrPermissionsUnavailable si les permissions du rôle n'ont pas pu être chargées
+func NewQueryScopeFromUser(user *models.User) (*QueryScope, error) {
+	permissions, err := PermissionsForRole(user.Role.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	isResolver := false
 	if user.Department != nil && user.Department.IsITDepartment && user.Filiale != nil && user.Filiale.IsSoftwareProvider {
 		isResolver = true
@@ -57,10 +72,20 @@ func NewQueryScopeFromUser(user *models.User) *QueryScope {
 		DepartmentID:   user.DepartmentID,
 		FilialeID:      filialeID,
 		Role:           user.Role.Name,
-		Permissions:    GetPermissionsForRole(user.Role.Name),
+		Permissions:    permissions,
 		IsResolver:     isResolver,
 		DepartmentIsIT: departmentIsIT,
+		Shares:         loadShares(user.ID, user.DepartmentID),
+		TeamUserIDs:    loadTeam(user.ID),
+	}, nil
+}
+
+// Loc retourne le fuseau horaire de l'utilisateur, sinon le fuseau par défaut de l'application
+func (s *QueryScope) Loc() *time.Location {
+	if s == nil || s.Location == nil {
+		return timezone.Default()
 	}
+	return s.Location
 }
 
 // HasPermission vérifie si le scope a une permission donnée
@@ -92,25 +117,3 @@ func (s *QueryScope) HasAllPermissions(permissions ...string) bool {
 	}
 	return true
 }
-
-// permissionsGetter est une fonction qui récupère les permissions d'un rôle par son nom
-// Cette fonction peut être injectée depuis l'extérieur pour éviter les cycles d'importation
-var permissionsGetter func(roleName string) []string
-
-// SetPermissionsGetter définit la fonction de récupération des permissions
-// Cette fonction doit être appelée au démarrage de l'application
-func SetPermissionsGetter(getter func(roleName string) []string) {
-	permissionsGetter = getter
-}
-
-// GetPermissionsForRole retourne la liste des permissions associées à un rôle donné
-// Les permissions sont récupérées depuis la base de données via la table role_permissions
-// Cette fonction doit être identique à celle dans auth_service.go pour la cohérence
-func GetPermissionsForRole(roleName string) []string {
-	if permissionsGetter == nil {
-		log.Printf("⚠️  PermissionsGetter non initialisé pour le rôle '%s', retour de permissions minimales", roleName)
-		// Retourner des permissions minimales par défaut si le getter n'est pas défini
-		return []string{"tickets.view_own"}
-	}
-	return permissionsGetter(roleName)
-}
@@ -0,0 +1,97 @@
+package scope
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Types de ressources partageables (identiques aux constantes models.ShareResource*)
+const (
+	ShareResourceTicket  = "ticket"
+	ShareResourceProject = "project"
+)
+
+// SharedRecord représente un partage actif dont bénéficie l'utilisateur (directement ou via son département)
+type SharedRecord struct {
+	ResourceType string
+	ResourceID   uint
+	AccessLevel  string     // read ou write
+	ExpiresAt    *time.Time // nil = sans expiration
+}
+
+// SharesLoader charge les partages actifs d'un utilisateur (et de son département)
+type SharesLoader func(userID uint, departmentID *uint) ([]SharedRecord, error)
+
+// sharesLoader est injecté depuis l'extérieur pour éviter les cycles d'importation
+var sharesLoader SharesLoader
+
+// SetSharesLoader définit la fonction de chargement des partages
+// Cette fonction doit être appelée au démarrage de l'application
+func SetSharesLoader(loader SharesLoader) {
+	sharesLoader = loader
+}
+
+// loadShares charge les partages d'un utilisateur ; en cas d'erreur, aucun accès supplémentaire n'est accordé
+func loadShares(userID uint, departmentID *uint) []SharedRecord {
+	if sharesLoader == nil {
+		return nil
+	}
+	shares, err := sharesLoader(userID, departmentID)
+	if err != nil {
+		log.Printf("⚠️  [scope] Partages de l'utilisateur %d non chargés: %v", userID, err)
+		return nil
+	}
+	return shares
+}
+
+// SharedIDs retourne les IDs des ressources d'un type partagées avec l'utilisateur (partages non expirés)
+func (s *QueryScope) SharedIDs(resourceType string) []uint {
+	now := time.Now()
+	var ids []uint
+	for _, share := range s.Shares {
+		if share.ResourceType == resourceType && (share.ExpiresAt == nil || share.ExpiresAt.After(now)) {
+			ids = append(ids, share.ResourceID)
+		}
+	}
+	return ids
+}
+
+// SharedAccess retourne le niveau d'accès partagé sur une ressource (write l'emporte sur read), vide si aucun partage
+func (s *QueryScope) SharedAccess(resourceType string, resourceID uint) string {
+	now := time.Now()
+	level := ""
+	for _, share := range s.Shares {
+		if share.ResourceType != resourceType || share.ResourceID != resourceID {
+			continue
+		}
+		if share.ExpiresAt != nil && !share.ExpiresAt.After(now) {
+			continue
+		}
+		if share.AccessLevel == "write" {
+			return share.AccessLevel
+		}
+		level = share.AccessLevel
+	}
+	return level
+}
+
+// WithoutShares retourne une copie du scope sans les partages (périmètre défini par les seules permissions)
+func (s *QueryScope) WithoutShares() *QueryScope {
+	base := *s
+	base.Shares = nil
+	return &base
+}
+
+// applyWithShares ajoute les ressources partagées au périmètre calculé par apply :
+// (id dans le périmètre des permissions) OU (id partagé avec l'utilisateur)
+// Le périmètre forcé du tableau de bord (DashboardScopeHint) n'inclut pas les partages
+func applyWithShares(db *gorm.DB, scope *QueryScope, tableName, resourceType string, apply func(*gorm.DB, *QueryScope) *gorm.DB) *gorm.DB {
+	sharedIDs := scope.SharedIDs(resourceType)
+	if len(sharedIDs) == 0 || scope.DashboardScopeHint != "" {
+		return apply(db, scope)
+	}
+	scoped := apply(db.Session(&gorm.Session{NewDB: true}).Table(tableName).Select(tableName+".id"), scope)
+	return db.Where("("+tableName+".id IN (?) OR "+tableName+".id IN ?)", scoped, sharedIDs)
+}
@@ -0,0 +1,48 @@
+package scope
+
+import (
+	"log"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// TeamLoader charge les IDs des collaborateurs d'un responsable (rattachements directs et indirects)
+type TeamLoader func(managerID uint) ([]uint, error)
+
+// teamLoader est défini au démarrage (main) pour éviter une dépendance du scope vers les repositories
+var teamLoader TeamLoader
+
+// SetTeamLoader définit la fonction de chargement des équipes hiérarchiques
+func SetTeamLoader(loader TeamLoader) {
+	teamLoader = loader
+}
+
+// loadTeam charge l'équipe d'un responsable ; en cas d'erreur, aucun accès supplémentaire n'est accordé
+func loadTeam(managerID uint) []uint {
+	if teamLoader == nil {
+		return nil
+	}
+	ids, err := teamLoader(managerID)
+	if err != nil {
+		log.Printf("⚠️  [scope] Équipe de l'utilisateur %d non chargée: %v", managerID, err)
+		return nil
+	}
+	return ids
+}
+
+// IsManagerOf indique si l'utilisateur est le responsable (direct ou indirect) d'un autre utilisateur, ou son suppléant
+func (s *QueryScope) IsManagerOf(userID uint) bool {
+	return slices.Contains(s.TeamUserIDs, userID)
+}
+
+// applyWithTeam ajoute les lignes de l'équipe hiérarchique au périmètre calculé par apply :
+// (id dans le périmètre des permissions) OU (userColumn parmi les collaborateurs du responsable)
+// Le périmètre forcé du tableau de bord (DashboardScopeHint) n'inclut pas l'équipe
+func applyWithTeam(db *gorm.DB, scope *QueryScope, tableName, userColumn string, apply func(*gorm.DB, *QueryScope) *gorm.DB) *gorm.DB {
+	if len(scope.TeamUserIDs) == 0 || scope.DashboardScopeHint != "" {
+		return apply(db, scope)
+	}
+	scoped := apply(db.Session(&gorm.Session{NewDB: true}).Table(tableName).Select(tableName+".id"), scope)
+	return db.Where("("+tableName+".id IN (?) OR "+tableName+"."+userColumn+" IN ?)", scoped, scope.TeamUserIDs)
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+const (
+	// absenceMaxDays durée maximale d'une demande d'absence
+	absenceMaxDays = 366
+	// expectedDailyMinutes temps attendu en saisie par jour ouvré (lundi à vendredi), hors absence approuvée
+	expectedDailyMinutes = 8 * 60
+)
+
+// AbsenceService interface pour les absences (congés, maladie, formation) : demande, approbation, annulation
+type AbsenceService interface {
+	Request(req dto.CreateAbsenceRequest, userID uint) (*dto.AbsenceDTO, error)
+	GetByID(id uint) (*dto.AbsenceDTO, error)
+	GetAll(filter repositories.AbsenceFilter) ([]dto.AbsenceDTO, error)
+	Approve(id uint, decidedByID uint, comment string) (*dto.AbsenceDTO, error)
+	Reject(id uint, decidedByID uint, comment string) (*dto.AbsenceDTO, error)
+	Cancel(id uint, userID uint, canValidate bool) (*dto.AbsenceDTO, error) // Demandeur (absence pas encore commencée) ou validateur
+}
+
+// absenceService implémente AbsenceService
+type absenceService struct {
+	absenceRepo         repositories.AbsenceRepository
+	notificationService NotificationService
+}
+
+// NewAbsenceService crée une nouvelle instance de AbsenceService
+func NewAbsenceService(
+	absenceRepo repositories.AbsenceRepository,
+	notificationService NotificationService,
+) AbsenceService {
+	return &absenceService{
+		absenceRepo:         absenceRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Request enregistre une demande d'absence de l'utilisateur, soumise à son responsable ou à un validateur des temps
+func (s *absenceService) Request(req dto.CreateAbsenceRequest, userID uint) (*dto.AbsenceDTO, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("date de début invalide (format attendu: YYYY-MM-DD)")
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New("date de fin invalide (format attendu: YYYY-MM-DD)")
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New("la date de fin doit être postérieure à la date de début")
+	}
+	if int(endDate.Sub(startDate).Hours()/24)+1 > absenceMaxDays {
+		return nil, fmt.Errorf("une absence ne peut pas dépasser %d jours", absenceMaxDays)
+	}
+	if workingDaysBetween(startDate, endDate) == 0 {
+		return nil, errors.New("la période ne couvre aucun jour ouvré")
+	}
+
+	overlapping, err := s.absenceRepo.FindOverlapping(userID, startDate, endDate, 0)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la vérification des absences existantes")
+	}
+	if len(overlapping) > 0 {
+		return nil, utils.ErrAbsenceConflict.WithDetails(map[string]any{
+			"absence_id": overlapping[0].ID,
+			"start_date": overlapping[0].StartDate.Format("2006-01-02"),
+			"end_date":   overlapping[0].EndDate.Format("2006-01-02"),
+			"status":     overlapping[0].Status,
+		})
+	}
+
+	absence := &models.Absence{
+		UserID:    userID,
+		Type:      req.Type,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    truncateRunes(strings.TrimSpace(req.Reason), 500),
+		Status:    models.AbsenceStatusPending,
+	}
+	if err := s.absenceRepo.Create(absence); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de l'absence")
+	}
+
+	created, err := s.absenceRepo.FindByID(absence.ID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'absence")
+	}
+	if created.User != nil && created.User.ManagerID != nil {
+		s.notify(*created.User.ManagerID, created, "absence_requested",
+			"Demande d'absence : "+absenceUserName(created), absencePeriodLabel(created))
+	}
+	absenceDTO := absenceToDTO(created)
+	return &absenceDTO, nil
+}
+
+// GetByID récupère une absence
+func (s *absenceService) GetByID(id uint) (*dto.AbsenceDTO, error) {
+	absence, err := s.absenceRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrAbsenceNotFound
+	}
+	absenceDTO := absenceToDTO(absence)
+	return &absenceDTO, nil
+}
+
+// GetAll récupère les absences selon les filtres
+func (s *absenceService) GetAll(filter repositories.AbsenceFilter) ([]dto.AbsenceDTO, error) {
+	absences, err := s.absenceRepo.FindAll(filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des absences")
+	}
+	absenceDTOs := make([]dto.AbsenceDTO, len(absences))
+	for i := range absences {
+		absenceDTOs[i] = absenceToDTO(&absences[i])
+	}
+	return absenceDTOs, nil
+}
+
+// Approve approuve une absence en attente
+func (s *absenceService) Approve(id uint, decidedByID uint, comment string) (*dto.AbsenceDTO, error) {
+	return s.decide(id, decidedByID, true, comment)
+}
+
+// Reject refuse une absence en attente ; un motif est requis
+func (s *absenceService) Reject(id uint, decidedByID uint, comment string) (*dto.AbsenceDTO, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, errors.New("un motif est requis pour refuser l'absence")
+	}
+	return s.decide(id, decidedByID, false, comment)
+}
+
+// decide applique la décision sur une absence en attente et en informe le demandeur
+func (s *absenceService) decide(id uint, decidedByID uint, approve bool, comment string) (*dto.AbsenceDTO, error) {
+	absence, err := s.absenceRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrAbsenceNotFound
+	}
+	if absence.Status != models.AbsenceStatusPending {
+		return nil, errors.New("cette absence n'est plus en attente d'approbation")
+	}
+	if absence.UserID == decidedByID {
+		return nil, errors.New("vous ne pouvez pas vous prononcer sur votre propre absence")
+	}
+
+	now := time.Now()
+	absence.Status = models.AbsenceStatusRejected
+	if approve {
+		absence.Status = models.AbsenceStatusApproved
+	}
+	absence.DecidedByID = &decidedByID
+	absence.DecidedAt = &now
+	absence.DecisionComment = truncateRunes(strings.TrimSpace(comment), 500)
+	if err := s.absenceRepo.Update(absence); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'absence")
+	}
+
+	updated, err := s.absenceRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'absence")
+	}
+	title := "Absence refusée"
+	if approve {
+		title = "Absence approuvée"
+	}
+	message := absencePeriodLabel(updated)
+	if updated.DecisionComment != "" {
+		message += " - " + updated.DecisionComment
+	}
+	s.notify(updated.UserID, updated, "absence_decided", title, message)
+
+	absenceDTO := absenceToDTO(updated)
+	return &absenceDTO, nil
+}
+
+// Cancel annule une absence en attente ou approuvée ; le demandeur ne peut plus annuler une absence approuvée
+// déjà commencée (un validateur le peut)
+func (s *absenceService) Cancel(id uint, userID uint, canValidate bool) (*dto.AbsenceDTO, error) {
+	absence, err := s.absenceRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrAbsenceNotFound
+	}
+	if !canValidate && absence.UserID != userID {
+		return nil, utils.ErrAbsenceNotFound
+	}
+	if absence.Status != models.AbsenceStatusPending && absence.Status != models.AbsenceStatusApproved {
+		return nil, errors.New("seule une absence en attente ou approuvée peut être annulée")
+	}
+	if !canValidate && absence.Status == models.AbsenceStatusApproved && !absence.StartDate.After(time.Now()) {
+		return nil, errors.New("absence déjà commencée : seul un validateur peut l'annuler")
+	}
+
+	absence.Status = models.AbsenceStatusCancelled
+	if err := s.absenceRepo.Update(absence); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'absence")
+	}
+	if absence.UserID != userID {
+		s.notify(absence.UserID, absence, "absence_decided", "Absence annulée", absencePeriodLabel(absence))
+	}
+	absenceDTO := absenceToDTO(absence)
+	return &absenceDTO, nil
+}
+
+// notify envoie une notification liée à l'absence
+func (s *absenceService) notify(userID uint, absence *models.Absence, notificationType, title, message string) {
+	metadata := map[string]any{
+		"absence_id": absence.ID,
+		"type":       absence.Type,
+		"start_date": absence.StartDate.Format("2006-01-02"),
+		"end_date":   absence.EndDate.Format("2006-01-02"),
+	}
+	linkURL := fmt.Sprintf("/absences/%d", absence.ID)
+	if err := s.notificationService.Create(userID, notificationType, title, message, linkURL, metadata); err != nil {
+		log.Printf("Erreur lors de la notification %s de l'absence %d à l'utilisateur %d: %v", notificationType, absence.ID, userID, err)
+	}
+}
+
+// absenceUserName retourne le nom du demandeur
+func absenceUserName(absence *models.Absence) string {
+	if absence.User != nil && absence.User.ID != 0 {
+		if name := strings.TrimSpace(absence.User.FirstName + " " + absence.User.LastName); name != "" {
+			return name
+		}
+		return absence.User.Username
+	}
+	return fmt.Sprintf("utilisateur #%d", absence.UserID)
+}
+
+// absencePeriodLabel retourne le libellé de la période d'absence (ex: "Congés du 03/08/2026 au 14/08/2026")
+func absencePeriodLabel(absence *models.Absence) string {
+	label := map[string]string{
+		models.AbsenceTypeVacation: "Congés",
+		models.AbsenceTypeSick:     "Maladie",
+		models.AbsenceTypeTraining: "Formation",
+	}[absence.Type]
+	return fmt.Sprintf("%s du %s au %s", label, absence.StartDate.Format("02/01/2006"), absence.EndDate.Format("02/01/2006"))
+}
+
+// workingDaysBetween compte les jours ouvrés (lundi à vendredi) de la période, bornes incluses
+func workingDaysBetween(start, end time.Time) int {
+	return workingMinutesBetween(start, end) / expectedDailyMinutes
+}
+
+// absentDays indexe par jour (AAAA-MM-JJ) le type des absences couvrant les jours ouvrés de la période
+func absentDays(absences []models.Absence, from, to time.Time) map[string]string {
+	days := make(map[string]string)
+	from, to = calendarDay(from), calendarDay(to)
+	for _, absence := range absences {
+		start, end := calendarDay(absence.StartDate), calendarDay(absence.EndDate)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			if isWorkingDay(d) {
+				days[d.Format("2006-01-02")] = absence.Type
+			}
+		}
+	}
+	return days
+}
+
+// expectedWorkload calcule le temps attendu en saisie sur la période (jours ouvrés hors absences approuvées)
+// et le nombre de jours ouvrés d'absence
+func expectedWorkload(from, to time.Time, absent map[string]string) (expectedMinutes, absenceDays int) {
+	for d := calendarDay(from); !d.After(calendarDay(to)); d = d.AddDate(0, 0, 1) {
+		if !isWorkingDay(d) {
+			continue
+		}
+		if _, ok := absent[d.Format("2006-01-02")]; ok {
+			absenceDays++
+			continue
+		}
+		expectedMinutes += expectedDailyMinutes
+	}
+	return expectedMinutes, absenceDays
+}
+
+// calendarDay ramène une date à son jour calendaire (minuit UTC), pour comparer des dates de colonnes DATE
+// et des dates saisies quel que soit leur fuseau
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// absenceToDTO convertit une absence en DTO
+func absenceToDTO(absence *models.Absence) dto.AbsenceDTO {
+	absenceDTO := dto.AbsenceDTO{
+		ID:              absence.ID,
+		UserID:          absence.UserID,
+		Type:            absence.Type,
+		StartDate:       absence.StartDate,
+		EndDate:         absence.EndDate,
+		WorkingDays:     workingDaysBetween(absence.StartDate, absence.EndDate),
+		Reason:          absence.Reason,
+		Status:          absence.Status,
+		DecidedAt:       absence.DecidedAt,
+		DecisionComment: absence.DecisionComment,
+		CreatedAt:       absence.CreatedAt,
+		UpdatedAt:       absence.UpdatedAt,
+	}
+	if absence.User != nil && absence.User.ID != 0 {
+		user := userToDTO(absence.User)
+		absenceDTO.User = &user
+	}
+	if absence.DecidedBy != nil && absence.DecidedBy.ID != 0 {
+		decidedBy := userToDTO(absence.DecidedBy)
+		absenceDTO.DecidedBy = &decidedBy
+	}
+	return absenceDTO
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// AccessCheckService interface pour le diagnostic des droits effectifs d'un utilisateur
+type AccessCheckService interface {
+	Check(userID uint, resourceType string, resourceID *uint) (*dto.AccessCheckDTO, error)
+}
+
+// accessCheckService implémente AccessCheckService
+type accessCheckService struct {
+	userRepo        repositories.UserRepository
+	ticketRepo      repositories.TicketRepository
+	projectRepo     repositories.ProjectRepository
+	recordShareRepo repositories.RecordShareRepository
+}
+
+// NewAccessCheckService crée une nouvelle instance de AccessCheckService
+func NewAccessCheckService(userRepo repositories.UserRepository, ticketRepo repositories.TicketRepository, projectRepo repositories.ProjectRepository, recordShareRepo repositories.RecordShareRepository) AccessCheckService {
+	return &accessCheckService{
+		userRepo:        userRepo,
+		ticketRepo:      ticketRepo,
+		projectRepo:     projectRepo,
+		recordShareRepo: recordShareRepo,
+	}
+}
+
+// Check rejoue le calcul du périmètre pour un utilisateur : branche de permission, prédicat SQL
+// et, si resourceID est fourni, visibilité de la ressource
+func (s *accessCheckService) Check(userID uint, resourceType string, resourceID *uint) (*dto.AccessCheckDTO, error) {
+	if resourceType != models.ShareResourceTicket && resourceType != models.ShareResourceProject {
+		return nil, errors.New("type de ressource non pris en charge (ticket ou project)")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	queryScope, err := scope.NewQueryScopeFromUser(user)
+	if err != nil {
+		return nil, err
+	}
+	explanation, err := scope.ExplainScope(resourceType, queryScope)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := database.DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		if resourceType == models.ShareResourceProject {
+			return scope.ApplyProjectScope(tx.Model(&models.Project{}), queryScope).Find(&[]models.Project{})
+		}
+		return scope.ApplyTicketScope(tx.Model(&models.Ticket{}), queryScope).Find(&[]models.Ticket{})
+	})
+
+	result := &dto.AccessCheckDTO{
+		User: dto.AccessCheckUserDTO{
+			ID:             user.ID,
+			Username:       user.Username,
+			Role:           user.Role.Name,
+			IsActive:       user.IsActive,
+			DepartmentID:   queryScope.DepartmentID,
+			FilialeID:      queryScope.FilialeID,
+			IsResolver:     queryScope.IsResolver,
+			DepartmentIsIT: queryScope.DepartmentIsIT,
+		},
+		Permissions: queryScope.Permissions,
+		Scope: dto.AccessCheckScopeDTO{
+			ResourceType:    explanation.ResourceType,
+			FilialeRule:     explanation.FilialeRule,
+			Branch:          explanation.Branch,
+			Description:     explanation.Description,
+			ViewPermissions: explanation.ViewPermissions,
+			SharedIDs:       explanation.SharedIDs,
+			Warnings:        explanation.Warnings,
+		},
+		SQL:       sql,
+		Predicate: wherePredicate(sql),
+	}
+	if result.Permissions == nil {
+		result.Permissions = []string{}
+	}
+	if !user.IsActive {
+		result.Scope.Warnings = append(result.Scope.Warnings, "Compte désactivé : toutes les requêtes sont refusées à l'authentification")
+	}
+
+	if resourceID != nil {
+		resource, err := s.checkResource(resourceType, *resourceID, queryScope)
+		if err != nil {
+			return nil, err
+		}
+		result.Resource = resource
+	}
+	return result, nil
+}
+
+// checkResource indique si une ressource existe et si elle est visible (via les permissions ou un partage)
+func (s *accessCheckService) checkResource(resourceType string, resourceID uint, queryScope *scope.QueryScope) (*dto.AccessCheckResourceDTO, error) {
+	resource := &dto.AccessCheckResourceDTO{
+		ID:           resourceID,
+		SharedAccess: queryScope.SharedAccess(resourceType, resourceID),
+	}
+	var err error
+	if resourceType == models.ShareResourceProject {
+		_, err = s.projectRepo.FindByID(resourceID)
+	} else {
+		_, err = s.ticketRepo.FindByIDLean(resourceID)
+	}
+	if err != nil {
+		return resource, nil
+	}
+	resource.Exists = true
+
+	visible, err := s.recordShareRepo.IsVisible(resourceType, resourceID, queryScope.WithoutShares())
+	if err != nil {
+		return nil, errors.New("erreur lors de la vérification de la visibilité")
+	}
+	switch {
+	case visible:
+		resource.Visible = true
+		resource.VisibleVia = "permissions"
+	case resource.SharedAccess != "":
+		resource.Visible = true
+		resource.VisibleVia = "share"
+	}
+	return resource, nil
+}
+
+// wherePredicate extrait la clause WHERE (premier niveau) d'une requête SELECT
+func wherePredicate(sql string) string {
+	if idx := strings.Index(sql, " WHERE "); idx >= 0 {
+		return sql[idx+len(" WHERE "):]
+	}
+	return ""
+}
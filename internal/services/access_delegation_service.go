@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// delegationAreaPermissions liste, par domaine, les permissions dont le délégant doit disposer (au moins une)
+var delegationAreaPermissions = map[string][]string{
+	models.DelegationAreaTimesheet: {"timesheet.validate", "timesheet.validate_justification", "delays.validate"},
+	models.DelegationAreaTickets:   {"tickets.validate", "tickets.validate_own", "tickets_internes.validate"},
+	models.DelegationAreaProjects:  {"projects.budget.manage", "projects.update"},
+}
+
+// AccessDelegationService interface pour la gestion des délégations d'absence
+type AccessDelegationService interface {
+	GetMine(userID uint) (*dto.AccessDelegationsDTO, error)
+	Create(req dto.CreateAccessDelegationRequest, delegatorID uint) (*dto.AccessDelegationDTO, error)
+	Revoke(id uint, userID uint) error
+}
+
+// accessDelegationService implémente AccessDelegationService
+type accessDelegationService struct {
+	delegationRepo repositories.AccessDelegationRepository
+	userRepo       repositories.UserRepository
+}
+
+// NewAccessDelegationService crée une nouvelle instance de AccessDelegationService
+func NewAccessDelegationService(delegationRepo repositories.AccessDelegationRepository, userRepo repositories.UserRepository) AccessDelegationService {
+	return &accessDelegationService{
+		delegationRepo: delegationRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// GetMine récupère les délégations accordées et reçues par l'utilisateur
+func (s *accessDelegationService) GetMine(userID uint) (*dto.AccessDelegationsDTO, error) {
+	given, err := s.delegationRepo.FindByDelegator(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des délégations")
+	}
+	received, err := s.delegationRepo.FindByDelegate(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des délégations")
+	}
+
+	now := time.Now()
+	result := &dto.AccessDelegationsDTO{
+		Given:    make([]dto.AccessDelegationDTO, 0, len(given)),
+		Received: make([]dto.AccessDelegationDTO, 0, len(received)),
+	}
+	for i := range given {
+		result.Given = append(result.Given, delegationToDTO(&given[i], now))
+	}
+	for i := range received {
+		result.Received = append(result.Received, delegationToDTO(&received[i], now))
+	}
+	return result, nil
+}
+
+// Create délègue les validations et la visibilité de l'utilisateur à un collègue sur une période
+func (s *accessDelegationService) Create(req dto.CreateAccessDelegationRequest, delegatorID uint) (*dto.AccessDelegationDTO, error) {
+	if req.DelegateID == delegatorID {
+		return nil, errors.New("impossible de se déléguer ses propres accès")
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, errors.New("la date de fin doit être postérieure à la date de début")
+	}
+	if !req.EndsAt.After(time.Now()) {
+		return nil, errors.New("la date de fin doit être dans le futur")
+	}
+
+	delegator, err := s.userRepo.FindByID(delegatorID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	delegate, err := s.userRepo.FindByID(req.DelegateID)
+	if err != nil {
+		return nil, errors.New("délégataire introuvable")
+	}
+	if !delegate.IsActive {
+		return nil, errors.New("le délégataire est désactivé")
+	}
+
+	// On ne délègue que ce que l'on peut soi-même valider
+	permissions, err := scope.PermissionsForRole(delegator.Role.Name)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des permissions")
+	}
+	held := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		held[permission] = true
+	}
+	areas := make([]string, 0, len(req.Areas))
+	for _, area := range req.Areas {
+		if contains(areas, area) {
+			continue
+		}
+		allowed := false
+		for _, permission := range delegationAreaPermissions[area] {
+			if held[permission] {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, errors.New("vous ne disposez d'aucune permission de validation pour le domaine " + area)
+		}
+		areas = append(areas, area)
+	}
+
+	delegation := &models.AccessDelegation{
+		DelegatorID: delegatorID,
+		DelegateID:  req.DelegateID,
+		Areas:       strings.Join(areas, ","),
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Reason:      strings.TrimSpace(req.Reason),
+	}
+	if err := s.delegationRepo.Create(delegation); err != nil {
+		return nil, errors.New("erreur lors de la création de la délégation")
+	}
+
+	created, err := s.delegationRepo.FindByID(delegation.ID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de la délégation créée")
+	}
+	result := delegationToDTO(created, time.Now())
+	return &result, nil
+}
+
+// Revoke met fin à une délégation (par le délégant ou le délégataire)
+func (s *accessDelegationService) Revoke(id uint, userID uint) error {
+	delegation, err := s.delegationRepo.FindByID(id)
+	if err != nil {
+		return errors.New("délégation introuvable")
+	}
+	if delegation.DelegatorID != userID && delegation.DelegateID != userID {
+		return errors.New("délégation introuvable")
+	}
+	if delegation.RevokedAt != nil {
+		return errors.New("délégation déjà révoquée")
+	}
+
+	now := time.Now()
+	delegation.RevokedAt = &now
+	if err := s.delegationRepo.Update(delegation); err != nil {
+		return errors.New("erreur lors de la révocation de la délégation")
+	}
+	return nil
+}
+
+// delegationToDTO convertit une délégation en DTO
+func delegationToDTO(delegation *models.AccessDelegation, now time.Time) dto.AccessDelegationDTO {
+	result := dto.AccessDelegationDTO{
+		ID:        delegation.ID,
+		Areas:     delegation.AreaList(),
+		StartsAt:  delegation.StartsAt,
+		EndsAt:    delegation.EndsAt,
+		Reason:    delegation.Reason,
+		RevokedAt: delegation.RevokedAt,
+		IsActive:  delegation.IsActiveAt(now),
+		CreatedAt: delegation.CreatedAt,
+	}
+	if delegation.Delegator != nil {
+		result.Delegator = shareUserToDTO(delegation.Delegator)
+	}
+	if delegation.Delegate != nil {
+		result.Delegate = shareUserToDTO(delegation.Delegate)
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Clés d'API des intégrations (supervision, flux OData, agents d'inventaire, portail de la base de connaissances) :
+// une valeur aléatoire précédée du préfixe de l'intégration, remise une seule fois ; seuls son empreinte SHA256
+// et son début (pour l'identifier dans les listes) sont stockés
+
+// apiKeyPrefixDisplayLength nombre de caractères aléatoires conservés après le préfixe dans le début de clé stocké
+const apiKeyPrefixDisplayLength = 6
+
+// apiKeyLastUsedInterval intervalle minimal entre deux mises à jour de la date de dernière utilisation d'une clé
+const apiKeyLastUsedInterval = time.Minute
+
+// generatedAPIKey clé d'API générée : valeur à remettre à l'appelant, empreinte et début de clé à stocker
+type generatedAPIKey struct {
+	Key    string
+	Hash   string
+	Prefix string
+}
+
+// generateAPIKey génère une clé d'API aléatoire portant le préfixe de l'intégration (ex: kal_)
+func generateAPIKey(prefix string) (*generatedAPIKey, error) {
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	key := prefix + token
+	return &generatedAPIKey{
+		Key:    key,
+		Hash:   utils.HashString(key),
+		Prefix: key[:len(prefix)+apiKeyPrefixDisplayLength],
+	}, nil
+}
+
+// apiKeyLookupHash retourne l'empreinte sous laquelle rechercher une clé présentée ; une clé sans le préfixe
+// de l'intégration est refusée sans accès à la base
+func apiKeyLookupHash(key, prefix string) (string, error) {
+	if len(key) <= len(prefix) || !strings.HasPrefix(key, prefix) {
+		return "", utils.ErrAPIKeyInvalid
+	}
+	return utils.HashString(key), nil
+}
+
+// markAPIKeyUsed renseigne la date de dernière utilisation d'une clé si la précédente remonte à plus de
+// apiKeyLastUsedInterval, et indique alors que la clé doit être enregistrée
+func markAPIKeyUsed(lastUsedAt **time.Time) bool {
+	now := time.Now()
+	if *lastUsedAt != nil && now.Sub(**lastUsedAt) <= apiKeyLastUsedInterval {
+		return false
+	}
+	*lastUsedAt = &now
+	return true
+}
@@ -0,0 +1,450 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ApprovalSubject module dont les enregistrements peuvent être soumis à approbation
+type ApprovalSubject interface {
+	// Describe vérifie que l'enregistrement existe et retourne son libellé et sa filiale
+	Describe(entityID uint) (title string, filialeID *uint, err error)
+	// ApplyDecision répercute la décision finale sur l'enregistrement
+	ApplyDecision(entityID uint, approved bool, decidedByID uint, comment string) error
+}
+
+// ApprovalService interface pour le circuit d'approbation commun aux modules
+type ApprovalService interface {
+	RegisterSubject(entityType string, subject ApprovalSubject) // À appeler au démarrage, pour chaque module
+	Create(req dto.CreateApprovalRequest, requestedByID uint) (*dto.ApprovalRequestDTO, error)
+	GetByID(id uint, userID uint, canManage bool) (*dto.ApprovalRequestDTO, error)
+	GetAll(filter repositories.ApprovalFilter) ([]dto.ApprovalRequestDTO, error)
+	GetMyPending(userID uint) ([]dto.ApprovalRequestDTO, error)
+	Approve(id uint, userID uint, comment string) (*dto.ApprovalRequestDTO, error)
+	Reject(id uint, userID uint, comment string) (*dto.ApprovalRequestDTO, error)
+	Cancel(id uint, userID uint, canManage bool) (*dto.ApprovalRequestDTO, error)
+	AddComment(id uint, userID uint, comment string, canManage bool) (*dto.ApprovalCommentDTO, error)
+}
+
+// approvalService implémente ApprovalService
+type approvalService struct {
+	approvalRepo repositories.ApprovalRepository
+	userRepo     repositories.UserRepository
+	eventBus     *events.Bus // Notification des nouvelles tâches d'approbation et des décisions
+	subjects     map[string]ApprovalSubject
+}
+
+// NewApprovalService crée une nouvelle instance de ApprovalService
+func NewApprovalService(
+	approvalRepo repositories.ApprovalRepository,
+	userRepo repositories.UserRepository,
+	eventBus *events.Bus,
+) ApprovalService {
+	return &approvalService{
+		approvalRepo: approvalRepo,
+		userRepo:     userRepo,
+		eventBus:     eventBus,
+		subjects:     map[string]ApprovalSubject{},
+	}
+}
+
+// RegisterSubject déclare un module dont les enregistrements peuvent être soumis à approbation
+func (s *approvalService) RegisterSubject(entityType string, subject ApprovalSubject) {
+	s.subjects[entityType] = subject
+}
+
+// Create soumet un enregistrement à approbation ; les approbateurs de la première étape sont notifiés
+func (s *approvalService) Create(req dto.CreateApprovalRequest, requestedByID uint) (*dto.ApprovalRequestDTO, error) {
+	subject, ok := s.subjects[req.EntityType]
+	if !ok {
+		return nil, fmt.Errorf("type d'enregistrement non soumis à approbation: %s", req.EntityType)
+	}
+	title, filialeID, err := subject.Describe(req.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if pending, _ := s.approvalRepo.FindPendingByEntity(req.EntityType, req.EntityID); pending != nil {
+		return nil, utils.ErrApprovalPending.WithDetails(map[string]any{"approval_id": pending.ID})
+	}
+
+	// Approbateurs : uniques, actifs, distincts du demandeur ; la première étape est la plus petite
+	approvers := make([]models.ApprovalApprover, 0, len(req.Approvers))
+	firstStep := 0
+	for _, approver := range req.Approvers {
+		if approver.UserID == requestedByID {
+			return nil, errors.New("le demandeur ne peut pas approuver sa propre demande")
+		}
+		if slices.ContainsFunc(approvers, func(a models.ApprovalApprover) bool { return a.UserID == approver.UserID }) {
+			return nil, errors.New("un approbateur ne peut figurer qu'une fois dans la demande")
+		}
+		user, err := s.userRepo.FindByID(approver.UserID)
+		if err != nil {
+			return nil, utils.ErrUserNotFound.WithDetails(map[string]any{"user_id": approver.UserID})
+		}
+		if !user.IsActive {
+			return nil, fmt.Errorf("l'approbateur %s est désactivé", user.Username)
+		}
+		step := approver.StepOrder
+		if step == 0 {
+			step = 1
+		}
+		if firstStep == 0 || step < firstStep {
+			firstStep = step
+		}
+		approvers = append(approvers, models.ApprovalApprover{UserID: approver.UserID, StepOrder: step})
+	}
+	var notified []uint
+	for i := range approvers {
+		if approvers[i].StepOrder == firstStep {
+			approvers[i].Status = models.ApproverStatusPending
+			notified = append(notified, approvers[i].UserID)
+		} else {
+			approvers[i].Status = models.ApproverStatusWaiting
+		}
+	}
+
+	request := &models.ApprovalRequest{
+		EntityType:    req.EntityType,
+		EntityID:      req.EntityID,
+		Title:         truncateRunes(title, 255),
+		Description:   strings.TrimSpace(req.Description),
+		Status:        models.ApprovalStatusPending,
+		CurrentStep:   firstStep,
+		RequestedByID: requestedByID,
+		FilialeID:     filialeID,
+		Approvers:     approvers,
+	}
+	if err := s.approvalRepo.Create(request); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la demande d'approbation")
+	}
+
+	approvalDTO, err := s.reload(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.publishRequested(approvalDTO, notified, requestedByID)
+	return approvalDTO, nil
+}
+
+// GetByID récupère une demande d'approbation (demandeur, approbateurs ou gestionnaires des approbations)
+func (s *approvalService) GetByID(id uint, userID uint, canManage bool) (*dto.ApprovalRequestDTO, error) {
+	request, err := s.approvalRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrApprovalNotFound
+	}
+	if !canManage && !isApprovalParticipant(request, userID) {
+		return nil, utils.ErrApprovalForbidden
+	}
+	approvalDTO := approvalToDTO(request)
+	return &approvalDTO, nil
+}
+
+// GetAll récupère les demandes d'approbation filtrées
+func (s *approvalService) GetAll(filter repositories.ApprovalFilter) ([]dto.ApprovalRequestDTO, error) {
+	requests, err := s.approvalRepo.FindAll(filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des demandes d'approbation")
+	}
+	return approvalsToDTOs(requests), nil
+}
+
+// GetMyPending récupère les demandes attendant la décision de l'utilisateur
+func (s *approvalService) GetMyPending(userID uint) ([]dto.ApprovalRequestDTO, error) {
+	requests, err := s.approvalRepo.FindPendingForApprover(userID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des demandes d'approbation")
+	}
+	return approvalsToDTOs(requests), nil
+}
+
+// Approve enregistre l'approbation de l'utilisateur ; l'étape suivante est ouverte lorsque tous les
+// approbateurs de l'étape courante ont approuvé, la demande est approuvée après la dernière étape
+func (s *approvalService) Approve(id uint, userID uint, comment string) (*dto.ApprovalRequestDTO, error) {
+	return s.decide(id, userID, true, strings.TrimSpace(comment))
+}
+
+// Reject enregistre le rejet de l'utilisateur : la demande est rejetée sans attendre les autres approbateurs
+func (s *approvalService) Reject(id uint, userID uint, comment string) (*dto.ApprovalRequestDTO, error) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, errors.New("un commentaire est requis pour rejeter une demande")
+	}
+	return s.decide(id, userID, false, comment)
+}
+
+// decide applique la décision d'un approbateur sous verrou, puis notifie et répercute la décision finale
+func (s *approvalService) decide(id uint, userID uint, approve bool, comment string) (*dto.ApprovalRequestDTO, error) {
+	var opened []uint
+	var request models.ApprovalRequest
+	err := s.approvalRepo.UpdateLocked(id, func(locked *models.ApprovalRequest) error {
+		if locked.Status != models.ApprovalStatusPending {
+			return utils.ErrApprovalClosed
+		}
+		index := slices.IndexFunc(locked.Approvers, func(a models.ApprovalApprover) bool {
+			return a.UserID == userID && a.Status == models.ApproverStatusPending
+		})
+		if index < 0 {
+			return utils.ErrApprovalNoTask
+		}
+
+		now := time.Now()
+		approver := &locked.Approvers[index]
+		approver.Comment = comment
+		approver.DecidedAt = &now
+		if !approve {
+			approver.Status = models.ApproverStatusRejected
+			closeApproval(locked, models.ApprovalStatusRejected, now)
+		} else {
+			approver.Status = models.ApproverStatusApproved
+			opened = advanceApproval(locked, now)
+		}
+		request = *locked
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrApprovalNotFound
+	}
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return nil, err
+		}
+		return nil, utils.NewInternalError("erreur lors de l'enregistrement de la décision")
+	}
+
+	if request.Status != models.ApprovalStatusPending {
+		s.applyDecision(&request, userID, comment)
+	}
+
+	approvalDTO, err := s.reload(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(opened) > 0 {
+		s.publishRequested(approvalDTO, opened, userID)
+	}
+	if approvalDTO.Status != models.ApprovalStatusPending {
+		s.publishDecided(approvalDTO, userID)
+	}
+	return approvalDTO, nil
+}
+
+// Cancel annule une demande en attente (demandeur ou gestionnaires des approbations)
+func (s *approvalService) Cancel(id uint, userID uint, canManage bool) (*dto.ApprovalRequestDTO, error) {
+	err := s.approvalRepo.UpdateLocked(id, func(locked *models.ApprovalRequest) error {
+		if !canManage && locked.RequestedByID != userID {
+			return utils.ErrApprovalForbidden
+		}
+		if locked.Status != models.ApprovalStatusPending {
+			return utils.ErrApprovalClosed
+		}
+		closeApproval(locked, models.ApprovalStatusCancelled, time.Now())
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrApprovalNotFound
+	}
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return nil, err
+		}
+		return nil, utils.NewInternalError("erreur lors de l'annulation de la demande d'approbation")
+	}
+	return s.reload(id)
+}
+
+// AddComment ajoute un commentaire à une demande (demandeur, approbateurs ou gestionnaires des approbations)
+func (s *approvalService) AddComment(id uint, userID uint, comment string, canManage bool) (*dto.ApprovalCommentDTO, error) {
+	request, err := s.approvalRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrApprovalNotFound
+	}
+	if !canManage && !isApprovalParticipant(request, userID) {
+		return nil, utils.ErrApprovalForbidden
+	}
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, errors.New("le commentaire est vide")
+	}
+
+	approvalComment := &models.ApprovalComment{ApprovalRequestID: id, UserID: userID, Comment: comment}
+	if err := s.approvalRepo.AddComment(approvalComment); err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'ajout du commentaire")
+	}
+	commentDTO := dto.ApprovalCommentDTO{ID: approvalComment.ID, Comment: approvalComment.Comment, CreatedAt: approvalComment.CreatedAt}
+	if user, err := s.userRepo.FindByID(userID); err == nil {
+		userDTO := userToDTO(user)
+		commentDTO.User = &userDTO
+	}
+	return &commentDTO, nil
+}
+
+// applyDecision répercute la décision finale sur l'enregistrement ; un échec est conservé sur la demande
+func (s *approvalService) applyDecision(request *models.ApprovalRequest, decidedByID uint, comment string) {
+	subject, ok := s.subjects[request.EntityType]
+	if !ok {
+		return
+	}
+	err := subject.ApplyDecision(request.EntityID, request.Status == models.ApprovalStatusApproved, decidedByID, comment)
+	if err == nil {
+		return
+	}
+	log.Printf("Approbation %d: décision non appliquée à %s %d: %v", request.ID, request.EntityType, request.EntityID, err)
+	if err := s.approvalRepo.SetApplyError(request.ID, truncateRunes(err.Error(), 500)); err != nil {
+		log.Printf("Erreur lors de l'enregistrement de l'échec de l'approbation %d: %v", request.ID, err)
+	}
+}
+
+// reload relit une demande et la convertit en DTO
+func (s *approvalService) reload(id uint) (*dto.ApprovalRequestDTO, error) {
+	request, err := s.approvalRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de la demande d'approbation")
+	}
+	approvalDTO := approvalToDTO(request)
+	return &approvalDTO, nil
+}
+
+// publishRequested publie les nouvelles tâches d'approbation (approverIDs : approbateurs de l'étape ouverte)
+func (s *approvalService) publishRequested(approval *dto.ApprovalRequestDTO, approverIDs []uint, actorID uint) {
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       events.ApprovalRequested,
+		ActorID:    &actorID,
+		FilialeID:  approval.FilialeID,
+		EntityType: "approvals",
+		EntityID:   approval.ID,
+		Data:       *approval,
+		Metadata:   map[string]any{"approver_ids": approverIDs, "step": approval.CurrentStep},
+	})
+}
+
+// publishDecided publie la décision finale d'une demande
+func (s *approvalService) publishDecided(approval *dto.ApprovalRequestDTO, actorID uint) {
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       events.ApprovalDecided,
+		ActorID:    &actorID,
+		FilialeID:  approval.FilialeID,
+		EntityType: "approvals",
+		EntityID:   approval.ID,
+		Data:       *approval,
+		Metadata:   map[string]any{"status": approval.Status, "entity_type": approval.EntityType, "entity_id": approval.EntityID},
+	})
+}
+
+// advanceApproval ouvre l'étape suivante lorsque l'étape courante est entièrement approuvée, ou approuve la demande
+// après la dernière étape ; retourne les approbateurs de l'étape ouverte
+func advanceApproval(request *models.ApprovalRequest, now time.Time) []uint {
+	for _, approver := range request.Approvers {
+		if approver.Status == models.ApproverStatusPending {
+			return nil // Étape courante incomplète
+		}
+	}
+	nextStep := 0
+	for _, approver := range request.Approvers {
+		if approver.Status == models.ApproverStatusWaiting && (nextStep == 0 || approver.StepOrder < nextStep) {
+			nextStep = approver.StepOrder
+		}
+	}
+	if nextStep == 0 {
+		closeApproval(request, models.ApprovalStatusApproved, now)
+		return nil
+	}
+	var opened []uint
+	for i := range request.Approvers {
+		if request.Approvers[i].Status == models.ApproverStatusWaiting && request.Approvers[i].StepOrder == nextStep {
+			request.Approvers[i].Status = models.ApproverStatusPending
+			opened = append(opened, request.Approvers[i].UserID)
+		}
+	}
+	request.CurrentStep = nextStep
+	return opened
+}
+
+// closeApproval clôt une demande ; les approbateurs n'ayant pas décidé sont ignorés
+func closeApproval(request *models.ApprovalRequest, status string, now time.Time) {
+	request.Status = status
+	request.DecidedAt = &now
+	for i := range request.Approvers {
+		if request.Approvers[i].Status == models.ApproverStatusPending || request.Approvers[i].Status == models.ApproverStatusWaiting {
+			request.Approvers[i].Status = models.ApproverStatusSkipped
+		}
+	}
+}
+
+// isApprovalParticipant indique si l'utilisateur est le demandeur ou un approbateur de la demande
+func isApprovalParticipant(request *models.ApprovalRequest, userID uint) bool {
+	if request.RequestedByID == userID {
+		return true
+	}
+	return slices.ContainsFunc(request.Approvers, func(a models.ApprovalApprover) bool { return a.UserID == userID })
+}
+
+// approvalsToDTOs convertit une liste de demandes d'approbation en DTO
+func approvalsToDTOs(requests []models.ApprovalRequest) []dto.ApprovalRequestDTO {
+	approvalDTOs := make([]dto.ApprovalRequestDTO, len(requests))
+	for i := range requests {
+		approvalDTOs[i] = approvalToDTO(&requests[i])
+	}
+	return approvalDTOs
+}
+
+// approvalToDTO convertit une demande d'approbation en DTO
+func approvalToDTO(request *models.ApprovalRequest) dto.ApprovalRequestDTO {
+	approvalDTO := dto.ApprovalRequestDTO{
+		ID:          request.ID,
+		EntityType:  request.EntityType,
+		EntityID:    request.EntityID,
+		Title:       request.Title,
+		Description: request.Description,
+		Status:      request.Status,
+		CurrentStep: request.CurrentStep,
+		FilialeID:   request.FilialeID,
+		DecidedAt:   request.DecidedAt,
+		ApplyError:  request.ApplyError,
+		Approvers:   make([]dto.ApprovalApproverDTO, len(request.Approvers)),
+		Comments:    make([]dto.ApprovalCommentDTO, len(request.Comments)),
+		CreatedAt:   request.CreatedAt,
+		UpdatedAt:   request.UpdatedAt,
+	}
+	if request.RequestedBy.ID != 0 {
+		requestedBy := userToDTO(&request.RequestedBy)
+		approvalDTO.RequestedBy = &requestedBy
+	}
+	for i, approver := range request.Approvers {
+		approverDTO := dto.ApprovalApproverDTO{
+			ID:        approver.ID,
+			UserID:    approver.UserID,
+			StepOrder: approver.StepOrder,
+			Status:    approver.Status,
+			Comment:   approver.Comment,
+			DecidedAt: approver.DecidedAt,
+		}
+		if approver.User.ID != 0 {
+			user := userToDTO(&approver.User)
+			approverDTO.User = &user
+		}
+		approvalDTO.Approvers[i] = approverDTO
+	}
+	for i, comment := range request.Comments {
+		commentDTO := dto.ApprovalCommentDTO{ID: comment.ID, Comment: comment.Comment, CreatedAt: comment.CreatedAt}
+		if comment.User.ID != 0 {
+			user := userToDTO(&comment.User)
+			commentDTO.User = &user
+		}
+		approvalDTO.Comments[i] = commentDTO
+	}
+	return approvalDTO
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// serviceRequestApprovalSubject soumet les demandes de service à approbation : la décision finale valide ou rejette la demande
+type serviceRequestApprovalSubject struct {
+	serviceRequestRepo    repositories.ServiceRequestRepository
+	serviceRequestService ServiceRequestService
+}
+
+// NewServiceRequestApprovalSubject crée le module d'approbation des demandes de service
+func NewServiceRequestApprovalSubject(serviceRequestRepo repositories.ServiceRequestRepository, serviceRequestService ServiceRequestService) ApprovalSubject {
+	return &serviceRequestApprovalSubject{serviceRequestRepo: serviceRequestRepo, serviceRequestService: serviceRequestService}
+}
+
+// Describe retourne le libellé du ticket de la demande
+func (s *serviceRequestApprovalSubject) Describe(entityID uint) (string, *uint, error) {
+	serviceRequest, err := s.serviceRequestRepo.FindByID(entityID)
+	if err != nil {
+		return "", nil, utils.ErrServiceRequestNotFound
+	}
+	return ticketApprovalTitle(serviceRequest.Ticket.Code, serviceRequest.Ticket.Title), serviceRequest.Ticket.FilialeID, nil
+}
+
+// ApplyDecision valide ou rejette la demande de service
+func (s *serviceRequestApprovalSubject) ApplyDecision(entityID uint, approved bool, decidedByID uint, comment string) error {
+	_, err := s.serviceRequestService.Validate(entityID, dto.ValidateServiceRequestRequest{Validated: approved, Comment: comment}, decidedByID)
+	return err
+}
+
+// changeApprovalSubject soumet les changements à approbation (CAB) : la décision est conservée sur la demande d'approbation
+type changeApprovalSubject struct {
+	changeRepo repositories.ChangeRepository
+}
+
+// NewChangeApprovalSubject crée le module d'approbation des changements
+func NewChangeApprovalSubject(changeRepo repositories.ChangeRepository) ApprovalSubject {
+	return &changeApprovalSubject{changeRepo: changeRepo}
+}
+
+// Describe retourne le libellé du ticket du changement
+func (s *changeApprovalSubject) Describe(entityID uint) (string, *uint, error) {
+	change, err := s.changeRepo.FindByID(entityID)
+	if err != nil {
+		return "", nil, utils.ErrChangeNotFound
+	}
+	return ticketApprovalTitle(change.Ticket.Code, change.Ticket.Title), change.Ticket.FilialeID, nil
+}
+
+// ApplyDecision n'a pas d'effet : le changement ne porte pas d'état d'approbation
+func (s *changeApprovalSubject) ApplyDecision(entityID uint, approved bool, decidedByID uint, comment string) error {
+	return nil
+}
+
+// budgetExtensionApprovalSubject soumet les extensions de budget projet à approbation : une extension rejetée est supprimée
+// et le budget du projet rétabli
+type budgetExtensionApprovalSubject struct {
+	budgetExtRepo  repositories.ProjectBudgetExtensionRepository
+	projectRepo    repositories.ProjectRepository
+	projectService ProjectService
+}
+
+// NewBudgetExtensionApprovalSubject crée le module d'approbation des extensions de budget
+func NewBudgetExtensionApprovalSubject(budgetExtRepo repositories.ProjectBudgetExtensionRepository, projectRepo repositories.ProjectRepository, projectService ProjectService) ApprovalSubject {
+	return &budgetExtensionApprovalSubject{budgetExtRepo: budgetExtRepo, projectRepo: projectRepo, projectService: projectService}
+}
+
+// Describe retourne le projet et la durée de l'extension
+func (s *budgetExtensionApprovalSubject) Describe(entityID uint) (string, *uint, error) {
+	extension, err := s.budgetExtRepo.FindByID(entityID)
+	if err != nil {
+		return "", nil, utils.ErrBudgetExtensionNotFound
+	}
+	project, err := s.projectRepo.FindByID(extension.ProjectID)
+	if err != nil {
+		return "", nil, utils.ErrProjectNotFound
+	}
+	return fmt.Sprintf("Extension de budget %s : +%d min", project.Name, extension.AdditionalMinutes), project.FilialeID, nil
+}
+
+// ApplyDecision supprime l'extension rejetée ; une extension approuvée reste acquise
+func (s *budgetExtensionApprovalSubject) ApplyDecision(entityID uint, approved bool, decidedByID uint, comment string) error {
+	if approved {
+		return nil
+	}
+	extension, err := s.budgetExtRepo.FindByID(entityID)
+	if err != nil {
+		return utils.ErrBudgetExtensionNotFound
+	}
+	return s.projectService.DeleteBudgetExtension(extension.ProjectID, extension.ID)
+}
+
+// weeklyDeclarationApprovalSubject soumet les déclarations hebdomadaires à approbation : la décision finale approuve
+// (et verrouille la semaine) ou rejette la déclaration soumise
+type weeklyDeclarationApprovalSubject struct {
+	declarationRepo    repositories.WeeklyDeclarationRepository
+	declarationService WeeklyDeclarationService
+}
+
+// NewWeeklyDeclarationApprovalSubject crée le module d'approbation des déclarations hebdomadaires
+func NewWeeklyDeclarationApprovalSubject(declarationRepo repositories.WeeklyDeclarationRepository, declarationService WeeklyDeclarationService) ApprovalSubject {
+	return &weeklyDeclarationApprovalSubject{declarationRepo: declarationRepo, declarationService: declarationService}
+}
+
+// Describe retourne la semaine et l'auteur de la déclaration, qui doit avoir été soumise
+func (s *weeklyDeclarationApprovalSubject) Describe(entityID uint) (string, *uint, error) {
+	declaration, err := s.declarationRepo.FindByID(entityID)
+	if err != nil {
+		return "", nil, utils.ErrDeclarationNotFound
+	}
+	if declaration.Status != models.WeeklyDeclarationStatusSubmitted {
+		return "", nil, errors.New("la déclaration doit être soumise avant d'être présentée à approbation")
+	}
+	return fmt.Sprintf("Déclaration %s de %s", declaration.Week, declaration.User.Username), declaration.FilialeID, nil
+}
+
+// ApplyDecision approuve ou rejette la déclaration
+func (s *weeklyDeclarationApprovalSubject) ApplyDecision(entityID uint, approved bool, decidedByID uint, comment string) error {
+	var err error
+	if approved {
+		_, err = s.declarationService.Approve(entityID, decidedByID, comment)
+	} else {
+		if strings.TrimSpace(comment) == "" {
+			comment = "Rejetée par le circuit d'approbation"
+		}
+		_, err = s.declarationService.Reject(entityID, decidedByID, comment)
+	}
+	return err
+}
+
+// assetReservationApprovalSubject soumet les réservations du parc de prêt à approbation : la décision finale approuve
+// ou refuse la réservation
+type assetReservationApprovalSubject struct {
+	reservationRepo    repositories.AssetReservationRepository
+	reservationService AssetReservationService
+}
+
+// NewAssetReservationApprovalSubject crée le module d'approbation des réservations d'équipement
+func NewAssetReservationApprovalSubject(reservationRepo repositories.AssetReservationRepository, reservationService AssetReservationService) ApprovalSubject {
+	return &assetReservationApprovalSubject{reservationRepo: reservationRepo, reservationService: reservationService}
+}
+
+// Describe retourne l'équipement, l'emprunteur et le créneau réservés
+func (s *assetReservationApprovalSubject) Describe(entityID uint) (string, *uint, error) {
+	reservation, err := s.reservationRepo.FindByID(entityID)
+	if err != nil {
+		return "", nil, utils.ErrReservationNotFound
+	}
+	var filialeID *uint
+	if reservation.Asset != nil {
+		filialeID = reservation.Asset.FilialeID
+	}
+	return fmt.Sprintf("Réservation %s par %s du %s au %s", reservationAssetName(reservation), reservationRequesterName(reservation),
+		reservation.StartAt.Format("02/01/2006"), reservation.EndAt.Format("02/01/2006")), filialeID, nil
+}
+
+// ApplyDecision approuve ou refuse la réservation
+func (s *assetReservationApprovalSubject) ApplyDecision(entityID uint, approved bool, decidedByID uint, comment string) error {
+	var err error
+	if approved {
+		_, err = s.reservationService.Approve(entityID, decidedByID, comment)
+	} else {
+		_, err = s.reservationService.Reject(entityID, decidedByID, comment)
+	}
+	return err
+}
+
+// delayJustificationApprovalSubject soumet les justifications de retard au circuit responsable / chef de
+// département : la décision finale valide ou rejette la justification
+type delayJustificationApprovalSubject struct {
+	justificationRepo repositories.DelayJustificationRepository
+	delayRepo         repositories.DelayRepository
+}
+
+// NewDelayJustificationApprovalSubject crée le module d'approbation des justifications de retard
+func NewDelayJustificationApprovalSubject(justificationRepo repositories.DelayJustificationRepository, delayRepo repositories.DelayRepository) ApprovalSubject {
+	return &delayJustificationApprovalSubject{justificationRepo: justificationRepo, delayRepo: delayRepo}
+}
+
+// Describe retourne l'auteur et le ticket de la justification, qui doit être en attente
+func (s *delayJustificationApprovalSubject) Describe(entityID uint) (string, *uint, error) {
+	justification, err := s.justificationRepo.FindByID(entityID)
+	if err != nil {
+		return "", nil, utils.ErrJustificationNotFound
+	}
+	if justification.Status != "pending" {
+		return "", nil, errors.New("seules les justifications en attente peuvent être présentées à approbation")
+	}
+	title := "Justification de retard de " + justification.User.Username
+	if justification.Delay.Ticket != nil && justification.Delay.Ticket.Code != "" {
+		title += " sur " + justification.Delay.Ticket.Code
+	}
+	return title, justification.Delay.FilialeID, nil
+}
+
+// ApplyDecision valide ou rejette la justification
+func (s *delayJustificationApprovalSubject) ApplyDecision(entityID uint, approved bool, decidedByID uint, comment string) error {
+	return applyDelayJustificationDecision(s.justificationRepo, s.delayRepo, entityID, approved, decidedByID, comment)
+}
+
+// ticketApprovalTitle libellé d'un enregistrement rattaché à un ticket
+func ticketApprovalTitle(code, title string) string {
+	if code == "" {
+		return title
+	}
+	return code + " - " + title
+}
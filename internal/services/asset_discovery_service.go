@@ -0,0 +1,682 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// discoveryAgentKeyPrefix préfixe des clés d'API des agents d'inventaire
+const discoveryAgentKeyPrefix = "kdi_"
+
+// discoveryPlaceholderSerials numéros de série génériques remontés par les BIOS non renseignés (ignorés au rapprochement)
+var discoveryPlaceholderSerials = map[string]bool{
+	"to be filled by o.e.m.": true,
+	"default string":         true,
+	"system serial number":   true,
+	"not specified":          true,
+	"not applicable":         true,
+	"none":                   true,
+	"n/a":                    true,
+	"0":                      true,
+	"0123456789":             true,
+}
+
+// discoveryAssetColumns colonnes des actifs rapprochées d'après l'inventaire
+var discoveryAssetColumns = map[string]string{
+	models.DiscoveryFieldName:         "name",
+	models.DiscoveryFieldSerialNumber: "serial_number",
+	models.DiscoveryFieldManufacturer: "manufacturer",
+	models.DiscoveryFieldModel:        "model",
+}
+
+// AssetDiscoveryService interface pour l'ingestion des inventaires des agents et le suivi des écarts avec la CMDB
+type AssetDiscoveryService interface {
+	GetAgents() ([]dto.DiscoveryAgentDTO, error)
+	CreateAgent(req dto.CreateDiscoveryAgentRequest, createdByID uint) (*dto.DiscoveryAgentDTO, error)
+	UpdateAgent(id uint, req dto.UpdateDiscoveryAgentRequest) (*dto.DiscoveryAgentDTO, error)
+	RotateKey(id uint) (*dto.DiscoveryAgentDTO, error)
+	DeleteAgent(id uint) error
+	AuthenticateAgent(key string) (uint, error) // Retourne l'ID de l'agent actif correspondant à la clé d'API
+	Ingest(agentID uint, req dto.DiscoveryReportRequest) (*dto.DiscoveryIngestResultDTO, error)
+	GetDrifts(status string, assetID, agentID *uint, page, limit int) (*dto.AssetDiscoveryDriftListResponse, error)
+	AcceptDrift(id, userID uint) (*dto.AssetDiscoveryDriftDTO, error)
+	DismissDrift(id, userID uint) (*dto.AssetDiscoveryDriftDTO, error)
+}
+
+// assetDiscoveryService implémente AssetDiscoveryService
+type assetDiscoveryService struct {
+	discoveryRepo     repositories.AssetDiscoveryRepository
+	assetCategoryRepo repositories.AssetCategoryRepository
+	filialeRepo       repositories.FilialeRepository
+	mu                sync.Mutex // Sérialise les inventaires : un équipement inconnu n'est créé qu'une fois
+}
+
+// NewAssetDiscoveryService crée une nouvelle instance de AssetDiscoveryService
+func NewAssetDiscoveryService(
+	discoveryRepo repositories.AssetDiscoveryRepository,
+	assetCategoryRepo repositories.AssetCategoryRepository,
+	filialeRepo repositories.FilialeRepository,
+) AssetDiscoveryService {
+	return &assetDiscoveryService{
+		discoveryRepo:     discoveryRepo,
+		assetCategoryRepo: assetCategoryRepo,
+		filialeRepo:       filialeRepo,
+	}
+}
+
+// discoveryObservation écart constaté entre la CMDB et l'inventaire sur un champ d'un actif
+type discoveryObservation struct {
+	field      string
+	subject    string // Logiciel concerné (champ software)
+	cmdb       string
+	discovered string
+}
+
+// key identifie l'écart : le champ, et pour un logiciel son nom et sa version inscrits dans la CMDB
+func (o discoveryObservation) key() string {
+	if o.field == models.DiscoveryFieldSoftware {
+		return o.field + "|" + strings.ToLower(o.subject) + "|" + strings.ToLower(o.cmdb)
+	}
+	return o.field
+}
+
+// GetAgents récupère les agents d'inventaire déclarés
+func (s *assetDiscoveryService) GetAgents() ([]dto.DiscoveryAgentDTO, error) {
+	agents, err := s.discoveryRepo.FindAgents()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des agents d'inventaire")
+	}
+	agentDTOs := make([]dto.DiscoveryAgentDTO, len(agents))
+	for i := range agents {
+		agentDTOs[i] = discoveryAgentToDTO(&agents[i])
+	}
+	return agentDTOs, nil
+}
+
+// CreateAgent déclare un agent d'inventaire et retourne sa clé d'API
+func (s *assetDiscoveryService) CreateAgent(req dto.CreateDiscoveryAgentRequest, createdByID uint) (*dto.DiscoveryAgentDTO, error) {
+	if req.FilialeID != nil {
+		if _, err := s.filialeRepo.FindByID(*req.FilialeID); err != nil {
+			return nil, utils.ErrFilialeNotFound
+		}
+	}
+	if req.DefaultCategoryID != nil {
+		if _, err := s.assetCategoryRepo.FindByID(*req.DefaultCategoryID); err != nil {
+			return nil, utils.ErrAssetCategoryNotFound
+		}
+	}
+	key, err := generateAPIKey(discoveryAgentKeyPrefix)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la génération de la clé d'API")
+	}
+	agent := &models.DiscoveryAgent{
+		Name:              strings.TrimSpace(req.Name),
+		KeyHash:           key.Hash,
+		KeyPrefix:         key.Prefix,
+		FilialeID:         req.FilialeID,
+		DefaultCategoryID: req.DefaultCategoryID,
+		AutoCreate:        true,
+		IsActive:          true,
+		CreatedByID:       createdByID,
+	}
+	if req.AutoCreate != nil {
+		agent.AutoCreate = *req.AutoCreate
+	}
+	if err := s.discoveryRepo.CreateAgent(agent); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de l'agent d'inventaire")
+	}
+
+	created, err := s.discoveryRepo.FindAgentByID(agent.ID)
+	if err != nil {
+		created = agent
+	}
+	agentDTO := discoveryAgentToDTO(created)
+	agentDTO.Key = key.Key
+	return &agentDTO, nil
+}
+
+// UpdateAgent met à jour un agent d'inventaire
+func (s *assetDiscoveryService) UpdateAgent(id uint, req dto.UpdateDiscoveryAgentRequest) (*dto.DiscoveryAgentDTO, error) {
+	agent, err := s.discoveryRepo.FindAgentByID(id)
+	if err != nil {
+		return nil, utils.ErrDiscoveryAgentNotFound
+	}
+	if req.Name != nil {
+		agent.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.FilialeID != nil {
+		agent.FilialeID = nil
+		if *req.FilialeID != 0 {
+			if _, err := s.filialeRepo.FindByID(*req.FilialeID); err != nil {
+				return nil, utils.ErrFilialeNotFound
+			}
+			agent.FilialeID = req.FilialeID
+		}
+	}
+	if req.DefaultCategoryID != nil {
+		agent.DefaultCategoryID = nil
+		if *req.DefaultCategoryID != 0 {
+			if _, err := s.assetCategoryRepo.FindByID(*req.DefaultCategoryID); err != nil {
+				return nil, utils.ErrAssetCategoryNotFound
+			}
+			agent.DefaultCategoryID = req.DefaultCategoryID
+		}
+	}
+	if req.AutoCreate != nil {
+		agent.AutoCreate = *req.AutoCreate
+	}
+	if req.IsActive != nil {
+		agent.IsActive = *req.IsActive
+	}
+	if err := s.discoveryRepo.UpdateAgent(agent); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'agent d'inventaire")
+	}
+
+	updated, err := s.discoveryRepo.FindAgentByID(id)
+	if err != nil {
+		updated = agent
+	}
+	agentDTO := discoveryAgentToDTO(updated)
+	return &agentDTO, nil
+}
+
+// RotateKey remplace la clé d'API d'un agent d'inventaire ; l'ancienne clé est immédiatement refusée
+func (s *assetDiscoveryService) RotateKey(id uint) (*dto.DiscoveryAgentDTO, error) {
+	agent, err := s.discoveryRepo.FindAgentByID(id)
+	if err != nil {
+		return nil, utils.ErrDiscoveryAgentNotFound
+	}
+	key, err := generateAPIKey(discoveryAgentKeyPrefix)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la génération de la clé d'API")
+	}
+	agent.KeyHash = key.Hash
+	agent.KeyPrefix = key.Prefix
+	if err := s.discoveryRepo.UpdateAgent(agent); err != nil {
+		return nil, utils.NewInternalError("erreur lors du renouvellement de la clé d'API")
+	}
+	agentDTO := discoveryAgentToDTO(agent)
+	agentDTO.Key = key.Key
+	return &agentDTO, nil
+}
+
+// DeleteAgent supprime un agent d'inventaire (les actifs découverts et les écarts sont conservés)
+func (s *assetDiscoveryService) DeleteAgent(id uint) error {
+	if _, err := s.discoveryRepo.FindAgentByID(id); err != nil {
+		return utils.ErrDiscoveryAgentNotFound
+	}
+	if err := s.discoveryRepo.DeleteAgent(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de l'agent d'inventaire")
+	}
+	return nil
+}
+
+// AuthenticateAgent retrouve l'agent actif correspondant à la clé d'API
+func (s *assetDiscoveryService) AuthenticateAgent(key string) (uint, error) {
+	hash, err := apiKeyLookupHash(key, discoveryAgentKeyPrefix)
+	if err != nil {
+		return 0, err
+	}
+	agent, err := s.discoveryRepo.FindAgentByKeyHash(hash)
+	if err != nil || !agent.IsActive {
+		return 0, utils.ErrAPIKeyInvalid
+	}
+	return agent.ID, nil
+}
+
+// Ingest rapproche l'inventaire d'un agent de la CMDB : création des actifs inconnus, complément des champs vides,
+// mise à jour des logiciels installés et enregistrement des écarts ; un équipement en erreur n'empêche pas le traitement des autres
+func (s *assetDiscoveryService) Ingest(agentID uint, req dto.DiscoveryReportRequest) (*dto.DiscoveryIngestResultDTO, error) {
+	agent, err := s.discoveryRepo.FindAgentByID(agentID)
+	if err != nil || !agent.IsActive {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	result := &dto.DiscoveryIngestResultDTO{Received: len(req.Assets), Assets: make([]dto.DiscoveredAssetResultDTO, 0, len(req.Assets))}
+	for _, item := range req.Assets {
+		assetResult := dto.DiscoveredAssetResultDTO{
+			Hostname:     strings.TrimSpace(item.Hostname),
+			SerialNumber: strings.TrimSpace(item.SerialNumber),
+			Action:       "skipped",
+		}
+		if err := s.ingestAsset(agent, item, now, &assetResult); err != nil {
+			label := assetResult.Hostname
+			if label == "" {
+				label = assetResult.SerialNumber
+			}
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", label, err))
+		}
+		switch assetResult.Action {
+		case "created":
+			result.Created++
+		case "updated":
+			result.Updated++
+		case "unchanged":
+			result.Unchanged++
+		default:
+			result.Skipped++
+		}
+		result.Drifts += assetResult.Drifts
+		result.Assets = append(result.Assets, assetResult)
+	}
+
+	agent.LastReportAt = &now
+	_ = s.discoveryRepo.UpdateAgent(agent)
+	return result, nil
+}
+
+// ingestAsset rapproche un équipement découvert : numéro de série, puis nom de l'hôte dans la filiale de l'agent
+func (s *assetDiscoveryService) ingestAsset(agent *models.DiscoveryAgent, item dto.DiscoveredAssetRequest, now time.Time, result *dto.DiscoveredAssetResultDTO) error {
+	hostname := strings.TrimSpace(item.Hostname)
+	serial := normalizeDiscoveredSerial(item.SerialNumber)
+
+	var asset *models.Asset
+	if serial != "" {
+		if found, err := s.discoveryRepo.FindAssetBySerialNumber(serial, agent.FilialeID); err == nil {
+			asset = found
+			result.MatchedBy = models.DiscoveryFieldSerialNumber
+		}
+	}
+	if asset == nil && hostname != "" {
+		if found, err := s.discoveryRepo.FindAssetByName(hostname, agent.FilialeID); err == nil {
+			asset = found
+			result.MatchedBy = models.DiscoveryFieldName
+		}
+	}
+	if asset == nil {
+		if !agent.AutoCreate {
+			return nil
+		}
+		return s.createDiscoveredAsset(agent, item, hostname, serial, now, result)
+	}
+	result.AssetID = &asset.ID
+
+	// Champs descriptifs : les champs vides de la CMDB sont complétés, les valeurs divergentes deviennent des écarts
+	fields := map[string]any{"last_discovered_at": now}
+	var observations []discoveryObservation
+	inSync := make(map[string]bool)
+	compare := func(field, cmdb, discovered string) {
+		cmdb, discovered = strings.TrimSpace(cmdb), strings.TrimSpace(discovered)
+		switch {
+		case discovered == "":
+		case cmdb == "":
+			fields[discoveryAssetColumns[field]] = discovered
+			inSync[field] = true
+		case strings.EqualFold(cmdb, discovered):
+			inSync[field] = true
+		default:
+			observations = append(observations, discoveryObservation{field: field, cmdb: cmdb, discovered: discovered})
+		}
+	}
+	compare(models.DiscoveryFieldName, asset.Name, truncateRunes(hostname, 255))
+	compare(models.DiscoveryFieldSerialNumber, asset.SerialNumber, truncateRunes(serial, 100))
+	compare(models.DiscoveryFieldManufacturer, asset.Manufacturer, truncateRunes(item.Manufacturer, 255))
+	compare(models.DiscoveryFieldModel, asset.Model, truncateRunes(item.Model, 255))
+	completed := len(fields) > 1
+
+	if item.Software != nil {
+		softwareChanged, softwareObservations, err := s.reconcileSoftware(asset.ID, item.Software, inSync)
+		if err != nil {
+			return err
+		}
+		completed = completed || softwareChanged
+		observations = append(observations, softwareObservations...)
+	}
+
+	if err := s.discoveryRepo.UpdateAssetFields(asset.ID, fields); err != nil {
+		return fmt.Errorf("mise à jour de l'actif: %w", err)
+	}
+	drifts, err := s.recordObservations(agent, asset.ID, observations, inSync, now)
+	if err != nil {
+		return err
+	}
+	result.Drifts = drifts
+	result.Action = "unchanged"
+	if completed {
+		result.Action = "updated"
+	}
+	return nil
+}
+
+// createDiscoveredAsset crée l'actif d'un équipement inconnu de la CMDB, en stock, avec ses logiciels
+func (s *assetDiscoveryService) createDiscoveredAsset(agent *models.DiscoveryAgent, item dto.DiscoveredAssetRequest, hostname, serial string, now time.Time, result *dto.DiscoveredAssetResultDTO) error {
+	categoryID := agent.DefaultCategoryID
+	if name := strings.TrimSpace(item.Category); name != "" {
+		if category, err := s.discoveryRepo.FindCategoryByName(name); err == nil {
+			categoryID = &category.ID
+		}
+	}
+	if categoryID == nil {
+		return errors.New("catégorie d'actif inconnue et aucune catégorie par défaut pour l'agent")
+	}
+
+	name := hostname
+	if name == "" {
+		name = serial
+	}
+	createdByID := agent.CreatedByID
+	asset := &models.Asset{
+		Name:             truncateRunes(name, 255),
+		SerialNumber:     truncateRunes(serial, 100),
+		Manufacturer:     truncateRunes(strings.TrimSpace(item.Manufacturer), 255),
+		Model:            truncateRunes(strings.TrimSpace(item.Model), 255),
+		CategoryID:       *categoryID,
+		FilialeID:        agent.FilialeID,
+		Status:           models.AssetStatusInStock,
+		LastDiscoveredAt: &now,
+		CreatedByID:      &createdByID,
+	}
+	event := &models.AssetLifecycleEvent{
+		ToStatus:      asset.Status,
+		Comment:       "Découvert par l'agent d'inventaire " + agent.Name,
+		PerformedByID: createdByID,
+	}
+	var software []models.AssetSoftware
+	seen := make(map[string]bool)
+	for _, discovered := range item.Software {
+		name, version := strings.TrimSpace(discovered.Name), strings.TrimSpace(discovered.Version)
+		key := strings.ToLower(name + "|" + version)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		software = append(software, models.AssetSoftware{SoftwareName: truncateRunes(name, 255), Version: truncateRunes(version, 100), Notes: "Découvert par l'agent d'inventaire " + agent.Name})
+	}
+
+	if err := s.discoveryRepo.CreateAsset(asset, event, software); err != nil {
+		return fmt.Errorf("création de l'actif: %w", err)
+	}
+	result.AssetID = &asset.ID
+	result.Action = "created"
+	return nil
+}
+
+// reconcileSoftware met à jour les logiciels inscrits sur l'actif d'après son inventaire complet : les logiciels découverts
+// sont ajoutés, une version différente d'un logiciel inscrit le met à jour ; les logiciels inscrits absents de l'inventaire
+// deviennent des écarts (ils peuvent avoir été saisis volontairement)
+func (s *assetDiscoveryService) reconcileSoftware(assetID uint, discovered []dto.DiscoveredSoftwareRequest, inSync map[string]bool) (bool, []discoveryObservation, error) {
+	installed, err := s.discoveryRepo.FindAssetSoftware(assetID)
+	if err != nil {
+		return false, nil, fmt.Errorf("lecture des logiciels installés: %w", err)
+	}
+
+	softwareKey := func(name, version string) string {
+		return strings.ToLower(strings.TrimSpace(name)) + "|" + strings.ToLower(strings.TrimSpace(version))
+	}
+	discoveredKeys := make(map[string]bool, len(discovered))
+	for _, d := range discovered {
+		discoveredKeys[softwareKey(d.Name, d.Version)] = true
+	}
+
+	changed := false
+	matched := make(map[uint]bool, len(installed))
+	seen := make(map[string]bool, len(discovered))
+	for _, d := range discovered {
+		name, version := strings.TrimSpace(d.Name), strings.TrimSpace(d.Version)
+		key := softwareKey(name, version)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		// Même logiciel, même version
+		var same, upgraded *models.AssetSoftware
+		for i := range installed {
+			row := &installed[i]
+			if matched[row.ID] || !strings.EqualFold(strings.TrimSpace(row.SoftwareName), name) {
+				continue
+			}
+			if softwareKey(row.SoftwareName, row.Version) == key {
+				same = row
+				break
+			}
+			// Version inscrite qui n'apparaît plus dans l'inventaire : mise à jour du logiciel
+			if upgraded == nil && !discoveredKeys[softwareKey(row.SoftwareName, row.Version)] {
+				upgraded = row
+			}
+		}
+		switch {
+		case same != nil:
+			matched[same.ID] = true
+		case upgraded != nil:
+			if err := s.discoveryRepo.UpdateAssetSoftwareVersion(upgraded.ID, truncateRunes(version, 100)); err != nil {
+				return false, nil, fmt.Errorf("mise à jour du logiciel %s: %w", name, err)
+			}
+			matched[upgraded.ID] = true
+			changed = true
+		default:
+			software := &models.AssetSoftware{AssetID: &assetID, SoftwareName: truncateRunes(name, 255), Version: truncateRunes(version, 100), Notes: "Découvert par inventaire"}
+			if err := s.discoveryRepo.CreateAssetSoftware(software); err != nil {
+				return false, nil, fmt.Errorf("ajout du logiciel %s: %w", name, err)
+			}
+			changed = true
+		}
+	}
+
+	var observations []discoveryObservation
+	for _, row := range installed {
+		observation := discoveryObservation{field: models.DiscoveryFieldSoftware, subject: row.SoftwareName, cmdb: row.Version}
+		if matched[row.ID] {
+			inSync[observation.key()] = true
+			continue
+		}
+		observations = append(observations, observation)
+	}
+	return changed, observations, nil
+}
+
+// recordObservations enregistre les écarts constatés et clôt ceux qui sont résorbés ; retourne le nombre d'écarts ouverts ou mis à jour
+// Un écart ignoré n'est pas rouvert tant que l'inventaire remonte la même valeur
+func (s *assetDiscoveryService) recordObservations(agent *models.DiscoveryAgent, assetID uint, observations []discoveryObservation, inSync map[string]bool, now time.Time) (int, error) {
+	openDrifts, err := s.discoveryRepo.FindOpenDrifts(assetID)
+	if err != nil {
+		return 0, fmt.Errorf("lecture des écarts: %w", err)
+	}
+	var cleared []uint
+	for _, drift := range openDrifts {
+		observation := discoveryObservation{field: drift.Field, subject: drift.Subject, cmdb: drift.CMDBValue}
+		if inSync[observation.key()] {
+			cleared = append(cleared, drift.ID)
+		}
+	}
+	if err := s.discoveryRepo.ClearDrifts(cleared, now); err != nil {
+		return 0, fmt.Errorf("clôture des écarts résorbés: %w", err)
+	}
+
+	recorded := 0
+	for _, observation := range observations {
+		cmdbFilter := ""
+		if observation.field == models.DiscoveryFieldSoftware {
+			cmdbFilter = observation.cmdb
+		}
+		latest, err := s.discoveryRepo.FindLatestDrift(assetID, observation.field, observation.subject, cmdbFilter)
+		if err == nil {
+			switch latest.Status {
+			case models.DiscoveryDriftOpen:
+				latest.AgentID = &agent.ID
+				latest.CMDBValue = truncateRunes(observation.cmdb, 255)
+				latest.DiscoveredValue = truncateRunes(observation.discovered, 255)
+				latest.Occurrences++
+				latest.LastSeenAt = now
+				if err := s.discoveryRepo.UpdateDrift(latest); err != nil {
+					return recorded, fmt.Errorf("mise à jour de l'écart %s: %w", observation.field, err)
+				}
+				recorded++
+				continue
+			case models.DiscoveryDriftDismissed:
+				if strings.EqualFold(latest.CMDBValue, observation.cmdb) && strings.EqualFold(latest.DiscoveredValue, observation.discovered) {
+					continue
+				}
+			}
+		}
+
+		drift := &models.AssetDiscoveryDrift{
+			AssetID:         assetID,
+			AgentID:         &agent.ID,
+			Field:           observation.field,
+			Subject:         truncateRunes(observation.subject, 255),
+			CMDBValue:       truncateRunes(observation.cmdb, 255),
+			DiscoveredValue: truncateRunes(observation.discovered, 255),
+			Status:          models.DiscoveryDriftOpen,
+			Occurrences:     1,
+			DetectedAt:      now,
+			LastSeenAt:      now,
+		}
+		if err := s.discoveryRepo.CreateDrift(drift); err != nil {
+			return recorded, fmt.Errorf("enregistrement de l'écart %s: %w", observation.field, err)
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+// GetDrifts récupère les écarts de découverte
+func (s *assetDiscoveryService) GetDrifts(status string, assetID, agentID *uint, page, limit int) (*dto.AssetDiscoveryDriftListResponse, error) {
+	drifts, total, err := s.discoveryRepo.FindDrifts(status, assetID, agentID, page, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des écarts de découverte")
+	}
+	driftDTOs := make([]dto.AssetDiscoveryDriftDTO, len(drifts))
+	for i := range drifts {
+		driftDTOs[i] = assetDiscoveryDriftToDTO(&drifts[i])
+	}
+	return &dto.AssetDiscoveryDriftListResponse{
+		Drifts: driftDTOs,
+		Pagination: dto.PaginationDTO{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	}, nil
+}
+
+// AcceptDrift applique la valeur découverte à la CMDB (un logiciel absent de l'inventaire est retiré de l'actif)
+func (s *assetDiscoveryService) AcceptDrift(id, userID uint) (*dto.AssetDiscoveryDriftDTO, error) {
+	drift, err := s.openDrift(id)
+	if err != nil {
+		return nil, err
+	}
+	if drift.Field == models.DiscoveryFieldSoftware {
+		err = s.discoveryRepo.DeleteAssetSoftware(drift.AssetID, drift.Subject, drift.CMDBValue)
+	} else {
+		column, ok := discoveryAssetColumns[drift.Field]
+		if !ok {
+			return nil, errors.New("champ d'écart non pris en charge")
+		}
+		err = s.discoveryRepo.UpdateAssetFields(drift.AssetID, map[string]any{column: drift.DiscoveredValue})
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'actif")
+	}
+	return s.resolveDrift(drift, models.DiscoveryDriftAccepted, userID)
+}
+
+// DismissDrift ignore un écart : la CMDB fait foi
+func (s *assetDiscoveryService) DismissDrift(id, userID uint) (*dto.AssetDiscoveryDriftDTO, error) {
+	drift, err := s.openDrift(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveDrift(drift, models.DiscoveryDriftDismissed, userID)
+}
+
+// openDrift récupère un écart encore à examiner
+func (s *assetDiscoveryService) openDrift(id uint) (*models.AssetDiscoveryDrift, error) {
+	drift, err := s.discoveryRepo.FindDriftByID(id)
+	if err != nil {
+		return nil, utils.ErrDiscrepancyNotFound
+	}
+	if drift.Status != models.DiscoveryDriftOpen {
+		return nil, utils.ErrDiscrepancyProcessed
+	}
+	return drift, nil
+}
+
+// resolveDrift enregistre le traitement d'un écart
+func (s *assetDiscoveryService) resolveDrift(drift *models.AssetDiscoveryDrift, status string, userID uint) (*dto.AssetDiscoveryDriftDTO, error) {
+	now := time.Now()
+	drift.Status = status
+	drift.ResolvedAt = &now
+	drift.ResolvedByID = &userID
+	if err := s.discoveryRepo.UpdateDrift(drift); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'écart de découverte")
+	}
+	updated, err := s.discoveryRepo.FindDriftByID(drift.ID)
+	if err != nil {
+		updated = drift
+	}
+	driftDTO := assetDiscoveryDriftToDTO(updated)
+	return &driftDTO, nil
+}
+
+// normalizeDiscoveredSerial nettoie un numéro de série remonté ; les valeurs génériques des BIOS sont ignorées
+func normalizeDiscoveredSerial(serial string) string {
+	serial = strings.TrimSpace(serial)
+	if discoveryPlaceholderSerials[strings.ToLower(serial)] {
+		return ""
+	}
+	return serial
+}
+
+// discoveryAgentToDTO convertit un agent d'inventaire en DTO
+func discoveryAgentToDTO(agent *models.DiscoveryAgent) dto.DiscoveryAgentDTO {
+	agentDTO := dto.DiscoveryAgentDTO{
+		ID:                agent.ID,
+		Name:              agent.Name,
+		KeyPrefix:         agent.KeyPrefix,
+		FilialeID:         agent.FilialeID,
+		DefaultCategoryID: agent.DefaultCategoryID,
+		AutoCreate:        agent.AutoCreate,
+		IsActive:          agent.IsActive,
+		LastReportAt:      agent.LastReportAt,
+		CreatedAt:         agent.CreatedAt,
+	}
+	if agent.Filiale != nil {
+		agentDTO.FilialeName = agent.Filiale.Name
+	}
+	if agent.DefaultCategory != nil {
+		agentDTO.DefaultCategoryName = agent.DefaultCategory.Name
+	}
+	return agentDTO
+}
+
+// assetDiscoveryDriftToDTO convertit un écart de découverte en DTO
+func assetDiscoveryDriftToDTO(drift *models.AssetDiscoveryDrift) dto.AssetDiscoveryDriftDTO {
+	driftDTO := dto.AssetDiscoveryDriftDTO{
+		ID:              drift.ID,
+		AssetID:         drift.AssetID,
+		AssetName:       drift.Asset.Name,
+		AgentID:         drift.AgentID,
+		Field:           drift.Field,
+		Subject:         drift.Subject,
+		CMDBValue:       drift.CMDBValue,
+		DiscoveredValue: drift.DiscoveredValue,
+		Status:          drift.Status,
+		Occurrences:     drift.Occurrences,
+		DetectedAt:      drift.DetectedAt,
+		LastSeenAt:      drift.LastSeenAt,
+		ResolvedAt:      drift.ResolvedAt,
+	}
+	if drift.Agent != nil {
+		driftDTO.AgentName = drift.Agent.Name
+	}
+	if drift.ResolvedBy != nil {
+		resolvedBy := userToDTO(drift.ResolvedBy)
+		driftDTO.ResolvedBy = &resolvedBy
+	}
+	return driftDTO
+}
@@ -0,0 +1,636 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssetImportMaxRows nombre maximal de lignes (hors en-tête) par import d'actifs
+const AssetImportMaxRows = 5000
+
+// assetImportComment motif des événements du cycle de vie enregistrés par l'import
+const assetImportComment = "Import en masse"
+
+// assetImportFields liste les champs importables et les en-têtes reconnus par défaut (comparaison insensible à la casse)
+var assetImportFields = []struct {
+	Field   string
+	Headers []string
+}{
+	{"code", []string{"code", "étiquette", "etiquette", "asset_code", "inventaire"}},
+	{"name", []string{"name", "nom", "libellé", "libelle"}},
+	{"serial_number", []string{"serial_number", "serial", "numéro de série", "numero de serie", "n° de série"}},
+	{"model", []string{"model", "modèle", "modele"}},
+	{"manufacturer", []string{"manufacturer", "fabricant", "marque"}},
+	{"category", []string{"category", "catégorie", "categorie"}},
+	{"status", []string{"status", "statut"}},
+	{"assigned_to", []string{"assigned_to", "utilisateur", "affecté à", "affecte a"}},
+	{"filiale", []string{"filiale", "filiale_code"}},
+	{"location", []string{"location", "localisation", "emplacement"}},
+	{"purchase_date", []string{"purchase_date", "date d'achat"}},
+	{"warranty_expiry", []string{"warranty_expiry", "fin de garantie", "garantie"}},
+	{"supplier", []string{"supplier", "fournisseur"}},
+	{"contract_reference", []string{"contract_reference", "contrat"}},
+	{"contract_end_date", []string{"contract_end_date", "fin de contrat"}},
+	{"purchase_cost", []string{"purchase_cost", "coût d'achat", "cout d'achat", "prix"}},
+	{"loanable", []string{"loanable", "prêt", "pret"}},
+	{"notes", []string{"notes", "commentaire"}},
+}
+
+// assetImportDiffFields valeurs comparées pour calculer les différences (ordre du rapport)
+var assetImportDiffFields = []struct {
+	Field string
+	Value func(asset *models.Asset) string
+}{
+	{"code", func(a *models.Asset) string { return importedAssetCode(a) }},
+	{"name", func(a *models.Asset) string { return a.Name }},
+	{"serial_number", func(a *models.Asset) string { return a.SerialNumber }},
+	{"model", func(a *models.Asset) string { return a.Model }},
+	{"manufacturer", func(a *models.Asset) string { return a.Manufacturer }},
+	{"category", func(a *models.Asset) string { return a.Category.Name }},
+	{"status", func(a *models.Asset) string { return models.NormalizeAssetStatus(a.Status) }},
+	{"assigned_to", func(a *models.Asset) string {
+		if a.AssignedToID == nil || a.AssignedTo == nil {
+			return ""
+		}
+		return a.AssignedTo.Username
+	}},
+	{"filiale", func(a *models.Asset) string {
+		if a.FilialeID == nil || a.Filiale == nil {
+			return ""
+		}
+		return a.Filiale.Code
+	}},
+	{"location", func(a *models.Asset) string { return a.Location }},
+	{"purchase_date", func(a *models.Asset) string { return formatImportDate(a.PurchaseDate) }},
+	{"warranty_expiry", func(a *models.Asset) string { return formatImportDate(a.WarrantyExpiry) }},
+	{"supplier", func(a *models.Asset) string { return a.Supplier }},
+	{"contract_reference", func(a *models.Asset) string { return a.ContractReference }},
+	{"contract_end_date", func(a *models.Asset) string { return formatImportDate(a.ContractEndDate) }},
+	{"purchase_cost", func(a *models.Asset) string {
+		if a.PurchaseCost == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*a.PurchaseCost, 'f', 2, 64)
+	}},
+	{"loanable", func(a *models.Asset) string { return strconv.FormatBool(a.Loanable) }},
+	{"notes", func(a *models.Asset) string { return a.Notes }},
+}
+
+// AssetImportService interface pour l'import en masse d'actifs
+type AssetImportService interface {
+	Import(rows [][]string, opts dto.AssetImportOptions, importerID uint, allowUpdate bool) (*dto.AssetImportReportDTO, error)
+}
+
+// assetImportService implémente AssetImportService
+type assetImportService struct {
+	assetRepo         repositories.AssetRepository
+	assetCategoryRepo repositories.AssetCategoryRepository
+	userRepo          repositories.UserRepository
+	filialeRepo       repositories.FilialeRepository
+}
+
+// NewAssetImportService crée une nouvelle instance de AssetImportService
+func NewAssetImportService(assetRepo repositories.AssetRepository, assetCategoryRepo repositories.AssetCategoryRepository, userRepo repositories.UserRepository, filialeRepo repositories.FilialeRepository) AssetImportService {
+	return &assetImportService{
+		assetRepo:         assetRepo,
+		assetCategoryRepo: assetCategoryRepo,
+		userRepo:          userRepo,
+		filialeRepo:       filialeRepo,
+	}
+}
+
+// assetImportLookups met en cache les références résolues pendant un import
+type assetImportLookups struct {
+	categories map[string]*models.AssetCategory // Par nom en minuscules
+	filiales   map[string]*models.Filiale       // Par code en minuscules
+	filialeIDs map[uint]*models.Filiale
+	users      map[string]*models.User // Par nom d'utilisateur ou email en minuscules
+}
+
+// assetImportPlan opération retenue pour une ligne valide
+type assetImportPlan struct {
+	index  int           // Index de la ligne dans le rapport
+	asset  *models.Asset // Actif à créer ou à enregistrer
+	before *models.Asset // État actuel (nil à la création)
+}
+
+// Import valide chaque ligne du fichier (en-tête en première ligne) et calcule les différences avec le parc existant
+// Une ligne dont l'étiquette (ou à défaut le numéro de série) correspond à un actif existant le met à jour ; sinon un actif est créé
+// Les cellules vides laissent la valeur existante inchangée. Hors simulation, toutes les lignes sont enregistrées dans une seule
+// transaction, et seulement si aucune ligne n'est en erreur
+// Sans allowUpdate, les lignes qui modifient un actif existant sont rejetées
+func (s *assetImportService) Import(rows [][]string, opts dto.AssetImportOptions, importerID uint, allowUpdate bool) (*dto.AssetImportReportDTO, error) {
+	if len(rows) < 2 {
+		return nil, errors.New("le fichier ne contient aucune ligne à importer")
+	}
+	if len(rows)-1 > AssetImportMaxRows {
+		return nil, fmt.Errorf("le fichier dépasse %d lignes", AssetImportMaxRows)
+	}
+
+	columns, headers, err := resolveAssetImportColumns(rows[0], opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	lookups, err := s.loadLookups()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.AssetImportReportDTO{
+		DryRun:  opts.DryRun,
+		Columns: headers,
+		Total:   len(rows) - 1,
+		Rows:    make([]dto.AssetImportRowDTO, 0, len(rows)-1),
+	}
+	var plans []assetImportPlan
+	seenCodes := map[string]int{}
+	seenSerials := map[string]int{}
+	seenAssets := map[uint]int{}
+
+	for i, row := range rows[1:] {
+		cell := func(field string) string {
+			idx, ok := columns[field]
+			if !ok || idx >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[idx])
+		}
+
+		line := i + 2
+		before, errs := s.matchImportedAsset(cell, lookups)
+		asset, rowErrs := s.buildImportedAsset(cell, before, lookups)
+		errs = append(errs, rowErrs...)
+		result := dto.AssetImportRowDTO{Row: line, Name: asset.Name, Code: importedAssetCode(asset)}
+
+		// Doublons à l'intérieur du fichier
+		if code := strings.ToLower(cell("code")); code != "" {
+			if first, ok := seenCodes[code]; ok {
+				errs = append(errs, fmt.Sprintf("étiquette en double (ligne %d)", first))
+			} else {
+				seenCodes[code] = line
+			}
+		}
+		if serial := strings.ToLower(cell("serial_number")); serial != "" {
+			if first, ok := seenSerials[serial]; ok {
+				errs = append(errs, fmt.Sprintf("numéro de série en double (ligne %d)", first))
+			} else {
+				seenSerials[serial] = line
+			}
+		}
+		if before != nil {
+			result.AssetID = &before.ID
+			if first, ok := seenAssets[before.ID]; ok {
+				errs = append(errs, fmt.Sprintf("actif déjà modifié par la ligne %d", first))
+			} else {
+				seenAssets[before.ID] = line
+			}
+		}
+
+		result.Changes = diffImportedAsset(before, asset)
+		switch {
+		case before == nil:
+			result.Action = dto.AssetImportActionCreate
+		case len(result.Changes) == 0:
+			result.Action = dto.AssetImportActionUnchanged
+		default:
+			result.Action = dto.AssetImportActionUpdate
+			if !allowUpdate {
+				errs = append(errs, "permission assets.update requise pour modifier un actif existant")
+			}
+		}
+
+		if len(errs) > 0 {
+			result.Status = dto.AssetImportRowError
+			result.Errors = errs
+			report.Failed++
+		} else {
+			result.Status = dto.AssetImportRowValid
+			switch result.Action {
+			case dto.AssetImportActionCreate:
+				report.Created++
+			case dto.AssetImportActionUpdate:
+				report.Updated++
+			default:
+				report.Unchanged++
+			}
+			if result.Action != dto.AssetImportActionUnchanged {
+				plans = append(plans, assetImportPlan{index: len(report.Rows), asset: asset, before: before})
+			}
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if opts.DryRun || report.Failed > 0 || len(plans) == 0 {
+		return report, nil
+	}
+
+	// Enregistrement transactionnel : tout ou rien
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, plan := range plans {
+			if err := saveImportedAsset(tx, plan, importerID); err != nil {
+				return fmt.Errorf("ligne %d: %w", report.Rows[plan.index].Row, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("asset_import_failed", "importer_id", importerID, "error", err)
+		return nil, utils.NewInternalError("erreur lors de l'enregistrement de l'import, aucun actif n'a été modifié")
+	}
+
+	for _, plan := range plans {
+		row := &report.Rows[plan.index]
+		row.Status = dto.AssetImportRowApplied
+		row.AssetID = &plan.asset.ID
+		row.Code = importedAssetCode(plan.asset)
+	}
+	report.Committed = true
+	return report, nil
+}
+
+// loadLookups charge les catégories et les filiales, référencées par nom et par code
+func (s *assetImportService) loadLookups() (*assetImportLookups, error) {
+	categories, err := s.assetCategoryRepo.FindAll()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des catégories")
+	}
+	filiales, err := s.filialeRepo.FindAll()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des filiales")
+	}
+
+	lookups := &assetImportLookups{
+		categories: make(map[string]*models.AssetCategory, len(categories)),
+		filiales:   make(map[string]*models.Filiale, len(filiales)),
+		filialeIDs: make(map[uint]*models.Filiale, len(filiales)),
+		users:      map[string]*models.User{},
+	}
+	for i := range categories {
+		key := strings.ToLower(categories[i].Name)
+		if _, ok := lookups.categories[key]; !ok {
+			lookups.categories[key] = &categories[i]
+		}
+	}
+	for i := range filiales {
+		lookups.filiales[strings.ToLower(filiales[i].Code)] = &filiales[i]
+		lookups.filialeIDs[filiales[i].ID] = &filiales[i]
+	}
+	return lookups, nil
+}
+
+// matchImportedAsset retrouve l'actif existant désigné par la ligne : par étiquette, sinon par numéro de série
+// Une étiquette inconnue associée à un numéro de série existant réétiquette l'actif
+func (s *assetImportService) matchImportedAsset(cell func(string) string, lookups *assetImportLookups) (*models.Asset, []string) {
+	var existing *models.Asset
+	code, serial := cell("code"), cell("serial_number")
+	if code != "" {
+		existing, _ = s.assetRepo.FindByCode(code)
+	}
+	if existing == nil && serial != "" {
+		existing, _ = s.assetRepo.FindBySerialNumber(serial)
+	} else if existing != nil && serial != "" && serial != existing.SerialNumber {
+		if other, _ := s.assetRepo.FindBySerialNumber(serial); other != nil {
+			return existing, []string{fmt.Sprintf("numéro de série déjà attribué à l'actif %s", importedAssetCode(other))}
+		}
+	}
+	if existing != nil && existing.FilialeID != nil {
+		existing.Filiale = lookups.filialeIDs[*existing.FilialeID]
+	}
+	return existing, nil
+}
+
+// buildImportedAsset applique les cellules non vides à une copie de l'actif existant (ou à un nouvel actif) et valide le résultat
+func (s *assetImportService) buildImportedAsset(cell func(string) string, existing *models.Asset, lookups *assetImportLookups) (*models.Asset, []string) {
+	var errs []string
+	asset := &models.Asset{DepreciationMethod: models.AssetDepreciationStraightLine}
+	if existing != nil {
+		copied := *existing
+		asset = &copied
+	}
+
+	if code := cell("code"); code != "" {
+		if len(code) > 50 {
+			errs = append(errs, "étiquette trop longue (50 caractères maximum)")
+		}
+		asset.Code = &code
+	}
+	for _, text := range []struct {
+		field  string
+		target *string
+	}{
+		{"name", &asset.Name},
+		{"serial_number", &asset.SerialNumber},
+		{"model", &asset.Model},
+		{"manufacturer", &asset.Manufacturer},
+		{"location", &asset.Location},
+		{"supplier", &asset.Supplier},
+		{"contract_reference", &asset.ContractReference},
+		{"notes", &asset.Notes},
+	} {
+		if value := cell(text.field); value != "" {
+			*text.target = value
+		}
+	}
+	if asset.Name == "" {
+		errs = append(errs, "nom manquant")
+	}
+
+	// Catégorie (par son nom)
+	if name := cell("category"); name != "" {
+		if category := lookups.categories[strings.ToLower(name)]; category != nil {
+			asset.CategoryID = category.ID
+			asset.Category = *category
+		} else {
+			errs = append(errs, fmt.Sprintf("catégorie introuvable: %s", name))
+		}
+	} else if asset.CategoryID == 0 {
+		errs = append(errs, "catégorie manquante")
+	}
+
+	// Filiale (par code)
+	if code := cell("filiale"); code != "" {
+		if filiale := lookups.filiales[strings.ToLower(code)]; filiale != nil {
+			asset.FilialeID = &filiale.ID
+			asset.Filiale = filiale
+		} else {
+			errs = append(errs, fmt.Sprintf("filiale introuvable: %s", code))
+		}
+	}
+
+	// Utilisateur affecté (nom d'utilisateur ou email)
+	if login := cell("assigned_to"); login != "" {
+		if user := s.lookupUser(login, lookups); user != nil {
+			asset.AssignedToID = &user.ID
+			asset.AssignedTo = user
+		} else {
+			errs = append(errs, fmt.Sprintf("utilisateur introuvable: %s", login))
+		}
+	}
+
+	for _, date := range []struct {
+		field  string
+		target **time.Time
+	}{
+		{"purchase_date", &asset.PurchaseDate},
+		{"warranty_expiry", &asset.WarrantyExpiry},
+		{"contract_end_date", &asset.ContractEndDate},
+	} {
+		if value := cell(date.field); value != "" {
+			if parsed, ok := parseImportDate(value); ok {
+				*date.target = &parsed
+			} else {
+				errs = append(errs, fmt.Sprintf("date invalide pour %s: %s (AAAA-MM-JJ ou JJ/MM/AAAA)", date.field, value))
+			}
+		}
+	}
+
+	if value := cell("purchase_cost"); value != "" {
+		if cost, ok := parseImportAmount(value); ok {
+			asset.PurchaseCost = &cost
+		} else {
+			errs = append(errs, fmt.Sprintf("coût d'achat invalide: %s", value))
+		}
+	}
+	if value := cell("loanable"); value != "" {
+		if loanable, ok := parseImportBool(value); ok {
+			asset.Loanable = loanable
+		} else {
+			errs = append(errs, fmt.Sprintf("valeur invalide pour loanable: %s (oui/non)", value))
+		}
+	}
+
+	errs = append(errs, applyImportedStatus(asset, existing, cell("status"), cell("assigned_to") != "")...)
+	return asset, errs
+}
+
+// applyImportedStatus détermine le statut importé et vérifie la transition depuis le statut actuel
+// Sans statut fourni, un actif affecté par le fichier passe au statut assigned
+func applyImportedStatus(asset, existing *models.Asset, value string, assigning bool) []string {
+	current := ""
+	if existing != nil {
+		current = models.NormalizeAssetStatus(existing.Status)
+	}
+
+	status := models.NormalizeAssetStatus(strings.ToLower(value))
+	if _, known := assetLifecycleTransitions[status]; status != "" && !known {
+		return []string{fmt.Sprintf("statut invalide: %s", value)}
+	}
+	if status == "" {
+		status = current
+		if assigning {
+			status = models.AssetStatusAssigned
+		} else if status == "" {
+			status = models.AssetStatusInStock
+		}
+	}
+
+	if existing != nil && status != current {
+		if !slices.Contains(assetLifecycleTransitions[current], status) {
+			return []string{fmt.Sprintf("transition de statut non autorisée: %s -> %s", current, status)}
+		}
+	}
+
+	now := time.Now()
+	switch status {
+	case models.AssetStatusAssigned:
+		if asset.AssignedToID == nil {
+			return []string{"l'utilisateur à affecter est obligatoire"}
+		}
+	case models.AssetStatusInStock, models.AssetStatusRetired:
+		if assigning {
+			return []string{fmt.Sprintf("un actif au statut %s ne peut pas être affecté", status)}
+		}
+		asset.AssignedToID = nil
+		asset.AssignedTo = nil
+	}
+	if status != current {
+		switch status {
+		case models.AssetStatusInStock:
+			asset.RetiredAt = nil
+		case models.AssetStatusRetired:
+			asset.RetiredAt = &now
+		case models.AssetStatusDisposed:
+			asset.DisposedAt = &now
+		}
+	}
+	asset.Status = status
+	return nil
+}
+
+// lookupUser retrouve un utilisateur par son nom d'utilisateur ou son email (mis en cache pour l'import)
+func (s *assetImportService) lookupUser(login string, lookups *assetImportLookups) *models.User {
+	key := strings.ToLower(login)
+	if user, ok := lookups.users[key]; ok {
+		return user
+	}
+	var user *models.User
+	if strings.Contains(key, "@") {
+		user, _ = s.userRepo.FindByEmail(key)
+	} else {
+		user, _ = s.userRepo.FindByUsername(login)
+	}
+	if user != nil && !user.IsActive {
+		user = nil
+	}
+	lookups.users[key] = user
+	return user
+}
+
+// saveImportedAsset enregistre la création ou la mise à jour d'un actif et historise le changement de statut ou d'affectation
+func saveImportedAsset(tx *gorm.DB, plan assetImportPlan, importerID uint) error {
+	asset := plan.asset
+	event := &models.AssetLifecycleEvent{
+		ToStatus:      asset.Status,
+		AssignedToID:  asset.AssignedToID,
+		Comment:       assetImportComment,
+		PerformedByID: importerID,
+	}
+
+	if plan.before == nil {
+		asset.CreatedByID = &importerID
+		if err := tx.Omit(clause.Associations).Create(asset).Error; err != nil {
+			return err
+		}
+	} else {
+		if err := tx.Omit(clause.Associations).Save(asset).Error; err != nil {
+			return err
+		}
+		event.FromStatus = models.NormalizeAssetStatus(plan.before.Status)
+		if event.FromStatus == asset.Status && sameUintPtr(plan.before.AssignedToID, asset.AssignedToID) {
+			return nil
+		}
+	}
+
+	event.AssetID = asset.ID
+	return tx.Create(event).Error
+}
+
+// diffImportedAsset liste les champs dont la valeur importée diffère de l'actif existant (tous les champs renseignés à la création)
+func diffImportedAsset(before, after *models.Asset) []dto.AssetImportChangeDTO {
+	if before == nil {
+		before = &models.Asset{}
+	}
+	var changes []dto.AssetImportChangeDTO
+	for _, f := range assetImportDiffFields {
+		from, to := f.Value(before), f.Value(after)
+		if from == to {
+			continue
+		}
+		changes = append(changes, dto.AssetImportChangeDTO{Field: f.Field, From: from, To: to})
+	}
+	return changes
+}
+
+// resolveAssetImportColumns associe chaque champ à l'index de sa colonne
+// La correspondance explicite (champ -> en-tête) prime sur les en-têtes reconnus par défaut
+func resolveAssetImportColumns(header []string, mapping map[string]string) (map[string]int, map[string]string, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(h))
+		if _, ok := index[key]; !ok && key != "" {
+			index[key] = i
+		}
+	}
+
+	known := make(map[string]bool, len(assetImportFields))
+	for _, f := range assetImportFields {
+		known[f.Field] = true
+	}
+	for field := range mapping {
+		if !known[field] {
+			return nil, nil, fmt.Errorf("champ inconnu dans la correspondance: %s", field)
+		}
+	}
+
+	columns := map[string]int{}
+	headers := map[string]string{}
+	for _, f := range assetImportFields {
+		if h, ok := mapping[f.Field]; ok {
+			idx, found := index[strings.ToLower(strings.TrimSpace(h))]
+			if !found {
+				return nil, nil, fmt.Errorf("colonne \"%s\" introuvable pour le champ %s", h, f.Field)
+			}
+			columns[f.Field], headers[f.Field] = idx, header[idx]
+			continue
+		}
+		for _, candidate := range f.Headers {
+			if idx, found := index[candidate]; found {
+				columns[f.Field], headers[f.Field] = idx, header[idx]
+				break
+			}
+		}
+	}
+
+	_, hasCode := columns["code"]
+	_, hasSerial := columns["serial_number"]
+	_, hasName := columns["name"]
+	if !hasCode && !hasSerial && !hasName {
+		return nil, nil, errors.New("colonne obligatoire manquante: code, serial_number ou name")
+	}
+	return columns, headers, nil
+}
+
+// parseImportDate lit une date AAAA-MM-JJ, JJ/MM/AAAA ou un numéro de série de date Excel
+func parseImportDate(value string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	// Cellule date XLSX lue comme nombre de jours depuis le 30/12/1899
+	if serial, err := strconv.Atoi(value); err == nil && serial > 0 && serial < 100000 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, serial), true
+	}
+	return time.Time{}, false
+}
+
+// parseImportAmount lit un montant positif (séparateur décimal point ou virgule, espaces de milliers tolérés)
+func parseImportAmount(value string) (float64, bool) {
+	cleaned := strings.NewReplacer(" ", "", " ", "", " ", "", ",", ".").Replace(value)
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || amount < 0 {
+		return 0, false
+	}
+	return roundAmount(amount), true
+}
+
+// parseImportBool lit une valeur booléenne (oui/non, true/false, 1/0, x)
+func parseImportBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "oui", "o", "yes", "y", "true", "vrai", "1", "x":
+		return true, true
+	case "non", "n", "no", "false", "faux", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// formatImportDate formate une date pour le rapport d'import
+func formatImportDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+// importedAssetCode retourne l'étiquette d'un actif (vide si non attribuée)
+func importedAssetCode(asset *models.Asset) string {
+	if asset.Code == nil {
+		return ""
+	}
+	return *asset.Code
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Profondeur de parcours du graphe de dépendances pour l'analyse d'impact
+const (
+	assetImpactDefaultDepth = 5
+	assetImpactMaxDepth     = 10
+)
+
+// AssetRelationService interface pour les relations de dépendance de la CMDB et l'analyse d'impact
+type AssetRelationService interface {
+	GetRelations(assetID uint) ([]dto.AssetRelationDTO, error)
+	Create(assetID uint, req dto.CreateAssetRelationRequest, createdByID uint) (*dto.AssetRelationDTO, error)
+	Delete(assetID, relationID uint) error
+	GetImpact(assetID uint, depth int) (*dto.AssetImpactDTO, error)
+}
+
+// assetRelationService implémente AssetRelationService
+type assetRelationService struct {
+	relationRepo repositories.AssetRelationRepository
+	assetRepo    repositories.AssetRepository
+	softwareRepo repositories.SoftwareRepository
+}
+
+// NewAssetRelationService crée une nouvelle instance de AssetRelationService
+func NewAssetRelationService(
+	relationRepo repositories.AssetRelationRepository,
+	assetRepo repositories.AssetRepository,
+	softwareRepo repositories.SoftwareRepository,
+) AssetRelationService {
+	return &assetRelationService{
+		relationRepo: relationRepo,
+		assetRepo:    assetRepo,
+		softwareRepo: softwareRepo,
+	}
+}
+
+// GetRelations récupère les dépendances de l'actif et celles qui s'appuient sur lui
+func (s *assetRelationService) GetRelations(assetID uint) ([]dto.AssetRelationDTO, error) {
+	if _, err := s.assetRepo.FindByID(assetID); err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+	relations, err := s.relationRepo.FindByAsset(assetID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des relations de l'actif")
+	}
+	relationDTOs := make([]dto.AssetRelationDTO, len(relations))
+	for i := range relations {
+		relationDTOs[i] = assetRelationToDTO(&relations[i], assetID)
+	}
+	return relationDTOs, nil
+}
+
+// Create déclare une dépendance : l'actif dépend de l'actif cible, ou le logiciel dépend de l'actif
+func (s *assetRelationService) Create(assetID uint, req dto.CreateAssetRelationRequest, createdByID uint) (*dto.AssetRelationDTO, error) {
+	if _, err := s.assetRepo.FindByID(assetID); err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+	if req.TargetAssetID != nil && req.SoftwareID != nil {
+		return nil, errors.New("indiquez soit un actif cible, soit un logiciel")
+	}
+
+	relation := &models.AssetRelation{
+		Type:        req.Type,
+		Notes:       req.Notes,
+		CreatedByID: createdByID,
+	}
+	if req.TargetAssetID != nil {
+		if *req.TargetAssetID == assetID {
+			return nil, errors.New("un actif ne peut pas dépendre de lui-même")
+		}
+		if _, err := s.assetRepo.FindByID(*req.TargetAssetID); err != nil {
+			return nil, utils.ErrAssetNotFound.WithDetails(map[string]any{"asset_id": *req.TargetAssetID})
+		}
+		relation.SourceAssetID = &assetID
+		relation.TargetAssetID = *req.TargetAssetID
+	} else {
+		if req.Type == models.AssetRelationConnectsTo {
+			return nil, errors.New("un logiciel ne peut être relié à un actif que par runs_on ou depends_on")
+		}
+		if _, err := s.softwareRepo.FindByID(*req.SoftwareID); err != nil {
+			return nil, utils.ErrSoftwareNotFound
+		}
+		relation.SourceSoftwareID = req.SoftwareID
+		relation.TargetAssetID = assetID
+	}
+
+	exists, err := s.relationRepo.Exists(relation.SourceAssetID, relation.SourceSoftwareID, relation.TargetAssetID, relation.Type)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la vérification des relations de l'actif")
+	}
+	if exists {
+		return nil, errors.New("cette relation existe déjà")
+	}
+	if err := s.relationRepo.Create(relation); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la relation")
+	}
+
+	created, err := s.relationRepo.FindByID(relation.ID)
+	if err != nil {
+		created = relation
+	}
+	relationDTO := assetRelationToDTO(created, assetID)
+	return &relationDTO, nil
+}
+
+// Delete supprime une relation de l'actif (comme source ou comme cible)
+func (s *assetRelationService) Delete(assetID, relationID uint) error {
+	relation, err := s.relationRepo.FindByID(relationID)
+	if err != nil || !assetRelationInvolves(relation, assetID) {
+		return utils.ErrAssetRelationNotFound
+	}
+	if err := s.relationRepo.Delete(relationID); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de la relation")
+	}
+	return nil
+}
+
+// GetImpact parcourt le graphe de dépendances à partir de l'actif, niveau par niveau : les actifs et logiciels
+// qui s'appuient sur un actif impacté sont impactés à leur tour ; les tickets ouverts sur l'ensemble sont listés
+func (s *assetRelationService) GetImpact(assetID uint, depth int) (*dto.AssetImpactDTO, error) {
+	asset, err := s.assetRepo.FindByID(assetID)
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+	if depth < 1 || depth > assetImpactMaxDepth {
+		depth = assetImpactDefaultDepth
+	}
+
+	impact := &dto.AssetImpactDTO{
+		Asset:    assetToNodeDTO(asset),
+		Depth:    depth,
+		Assets:   []dto.ImpactedAssetDTO{},
+		Services: []dto.ImpactedServiceDTO{},
+		Tickets:  []dto.ImpactedTicketDTO{},
+	}
+
+	visitedAssets := map[uint]bool{asset.ID: true}
+	visitedServices := make(map[uint]bool)
+	impactedAssetIDs := []uint{asset.ID}
+	var serviceIDs []uint
+	filialeIDs := make(map[uint]bool)
+	if asset.FilialeID != nil {
+		filialeIDs[*asset.FilialeID] = true
+	}
+
+	frontier := []uint{asset.ID}
+	for level := 1; len(frontier) > 0; level++ {
+		relations, err := s.relationRepo.FindDependents(frontier)
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors du parcours des dépendances")
+		}
+		if level > depth {
+			// Dépendances au-delà de la profondeur demandée : signalées sans être parcourues
+			for _, relation := range relations {
+				if (relation.SourceAssetID != nil && !visitedAssets[*relation.SourceAssetID]) ||
+					(relation.SourceSoftwareID != nil && !visitedServices[*relation.SourceSoftwareID]) {
+					impact.Truncated = true
+					break
+				}
+			}
+			break
+		}
+
+		var next []uint
+		for _, relation := range relations {
+			switch {
+			case relation.SourceAssetID != nil && relation.SourceAsset != nil:
+				if visitedAssets[*relation.SourceAssetID] {
+					continue
+				}
+				visitedAssets[*relation.SourceAssetID] = true
+				impact.Assets = append(impact.Assets, dto.ImpactedAssetDTO{
+					AssetNodeDTO: assetToNodeDTO(relation.SourceAsset),
+					Depth:        level,
+					RelationType: relation.Type,
+					ViaAssetID:   relation.TargetAssetID,
+				})
+				impactedAssetIDs = append(impactedAssetIDs, *relation.SourceAssetID)
+				if relation.SourceAsset.FilialeID != nil {
+					filialeIDs[*relation.SourceAsset.FilialeID] = true
+				}
+				next = append(next, *relation.SourceAssetID)
+			case relation.SourceSoftwareID != nil && relation.SourceSoftware != nil:
+				if visitedServices[*relation.SourceSoftwareID] {
+					continue
+				}
+				visitedServices[*relation.SourceSoftwareID] = true
+				impact.Services = append(impact.Services, dto.ImpactedServiceDTO{
+					ServiceNodeDTO: softwareToNodeDTO(relation.SourceSoftware),
+					Depth:          level,
+					RelationType:   relation.Type,
+					ViaAssetID:     relation.TargetAssetID,
+				})
+				serviceIDs = append(serviceIDs, *relation.SourceSoftwareID)
+			}
+		}
+		frontier = next
+	}
+
+	// Tickets ouverts : liés aux actifs impactés, ou portant sur un logiciel impacté dans les filiales concernées
+	assetTickets, err := s.relationRepo.FindOpenTicketsByAssets(impactedAssetIDs, closedTicketStatuses)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des tickets impactés")
+	}
+	filiales := make([]uint, 0, len(filialeIDs))
+	for id := range filialeIDs {
+		filiales = append(filiales, id)
+	}
+	serviceTickets, err := s.relationRepo.FindOpenTicketsBySoftware(serviceIDs, filiales, closedTicketStatuses)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des tickets impactés")
+	}
+	seenTickets := make(map[uint]bool)
+	for _, tickets := range [][]models.Ticket{assetTickets, serviceTickets} {
+		for i := range tickets {
+			ticket := &tickets[i]
+			if seenTickets[ticket.ID] {
+				continue
+			}
+			seenTickets[ticket.ID] = true
+			impact.Tickets = append(impact.Tickets, dto.ImpactedTicketDTO{
+				ID:         ticket.ID,
+				Code:       ticket.Code,
+				Title:      ticket.Title,
+				Category:   ticket.Category,
+				Status:     ticket.Status,
+				Priority:   ticket.Priority,
+				SoftwareID: ticket.SoftwareID,
+				CreatedAt:  ticket.CreatedAt,
+			})
+		}
+	}
+
+	impact.Summary = dto.AssetImpactSummaryDTO{
+		Assets:   len(impact.Assets),
+		Services: len(impact.Services),
+		Tickets:  len(impact.Tickets),
+	}
+	return impact, nil
+}
+
+// assetRelationInvolves indique si l'actif est la source ou la cible de la relation
+func assetRelationInvolves(relation *models.AssetRelation, assetID uint) bool {
+	return relation.TargetAssetID == assetID || (relation.SourceAssetID != nil && *relation.SourceAssetID == assetID)
+}
+
+// assetRelationToDTO convertit une relation en DTO, orientée du point de vue de l'actif consulté
+func assetRelationToDTO(relation *models.AssetRelation, assetID uint) dto.AssetRelationDTO {
+	relationDTO := dto.AssetRelationDTO{
+		ID:        relation.ID,
+		Type:      relation.Type,
+		Direction: "incoming",
+		Notes:     relation.Notes,
+		CreatedAt: relation.CreatedAt,
+	}
+	if relation.SourceAssetID != nil && *relation.SourceAssetID == assetID {
+		relationDTO.Direction = "outgoing"
+	}
+	if relation.SourceAsset != nil {
+		node := assetToNodeDTO(relation.SourceAsset)
+		relationDTO.SourceAsset = &node
+	}
+	if relation.SourceSoftware != nil {
+		node := softwareToNodeDTO(relation.SourceSoftware)
+		relationDTO.SourceSoftware = &node
+	}
+	if relation.TargetAsset != nil {
+		node := assetToNodeDTO(relation.TargetAsset)
+		relationDTO.TargetAsset = &node
+	}
+	return relationDTO
+}
+
+// assetToNodeDTO convertit un actif en nœud du graphe de dépendances
+func assetToNodeDTO(asset *models.Asset) dto.AssetNodeDTO {
+	node := dto.AssetNodeDTO{
+		ID:           asset.ID,
+		Name:         asset.Name,
+		SerialNumber: asset.SerialNumber,
+		Status:       models.NormalizeAssetStatus(asset.Status),
+		CategoryName: asset.Category.Name,
+		FilialeID:    asset.FilialeID,
+	}
+	if asset.Code != nil {
+		node.Code = *asset.Code
+	}
+	return node
+}
+
+// softwareToNodeDTO convertit un logiciel en nœud du graphe de dépendances
+func softwareToNodeDTO(software *models.Software) dto.ServiceNodeDTO {
+	return dto.ServiceNodeDTO{
+		ID:      software.ID,
+		Code:    software.Code,
+		Name:    software.Name,
+		Version: software.Version,
+	}
+}
@@ -0,0 +1,504 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// assetReservationMaxCalendarDays borne la période du calendrier de disponibilité
+const assetReservationMaxCalendarDays = 92
+
+// AssetReservationService interface pour le parc de prêt : réservations, approbation, remise et restitution des équipements
+type AssetReservationService interface {
+	GetCalendar(from, to time.Time, categoryID, filialeID *uint) (*dto.AssetReservationCalendarDTO, error)
+	GetAll(filter repositories.AssetReservationFilter, userID uint, canManage bool) ([]dto.AssetReservationDTO, error) // Hors gestionnaires : ses propres réservations
+	GetByID(id uint, userID uint, canManage bool) (*dto.AssetReservationDTO, error)
+	Create(req dto.CreateAssetReservationRequest, userID uint, canManage bool) (*dto.AssetReservationDTO, error) // Approuvée d'office si créée par un gestionnaire
+	Approve(id uint, decidedByID uint, comment string) (*dto.AssetReservationDTO, error)
+	Reject(id uint, decidedByID uint, comment string) (*dto.AssetReservationDTO, error)
+	Cancel(id uint, userID uint, canManage bool) (*dto.AssetReservationDTO, error)
+	CheckOut(id uint, userID uint) (*dto.AssetReservationDTO, error)
+	Return(id uint, req dto.AssetReservationReturnRequest, userID uint) (*dto.AssetReservationDTO, error)
+	SendReminders(dueWithin time.Duration) (int, error) // Rappels de retour et alertes de retard (tâche planifiée)
+}
+
+// assetReservationService implémente AssetReservationService
+type assetReservationService struct {
+	reservationRepo     repositories.AssetReservationRepository
+	assetRepo           repositories.AssetRepository
+	userRepo            repositories.UserRepository
+	notificationService NotificationService
+}
+
+// NewAssetReservationService crée une nouvelle instance de AssetReservationService
+func NewAssetReservationService(
+	reservationRepo repositories.AssetReservationRepository,
+	assetRepo repositories.AssetRepository,
+	userRepo repositories.UserRepository,
+	notificationService NotificationService,
+) AssetReservationService {
+	return &assetReservationService{
+		reservationRepo:     reservationRepo,
+		assetRepo:           assetRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// GetCalendar retourne les équipements du parc de prêt et leurs créneaux occupés sur la période
+func (s *assetReservationService) GetCalendar(from, to time.Time, categoryID, filialeID *uint) (*dto.AssetReservationCalendarDTO, error) {
+	if !to.After(from) {
+		return nil, errors.New("la fin de la période doit être postérieure à son début")
+	}
+	if to.Sub(from) > assetReservationMaxCalendarDays*24*time.Hour {
+		return nil, fmt.Errorf("la période du calendrier est limitée à %d jours", assetReservationMaxCalendarDays)
+	}
+
+	assets, err := s.reservationRepo.FindLoanableAssets(categoryID, filialeID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du parc de prêt")
+	}
+	reservations, err := s.reservationRepo.FindAll(repositories.AssetReservationFilter{
+		Statuses: models.AssetReservationBlockingStatuses,
+		From:     &from,
+		To:       &to,
+	})
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des réservations")
+	}
+	slots := make(map[uint][]dto.AssetReservationSlotDTO)
+	for _, reservation := range reservations {
+		slots[reservation.AssetID] = append(slots[reservation.AssetID], dto.AssetReservationSlotDTO{
+			ReservationID: reservation.ID,
+			StartAt:       reservation.StartAt,
+			EndAt:         reservation.EndAt,
+			Status:        reservation.Status,
+			RequesterID:   reservation.RequesterID,
+		})
+	}
+
+	calendar := &dto.AssetReservationCalendarDTO{
+		From:   from,
+		To:     to,
+		Assets: make([]dto.LoanableAssetAvailabilityDTO, 0, len(assets)),
+	}
+	for i := range assets {
+		asset := &assets[i]
+		assetSlots := slots[asset.ID]
+		if assetSlots == nil {
+			assetSlots = []dto.AssetReservationSlotDTO{}
+		}
+		calendar.Assets = append(calendar.Assets, dto.LoanableAssetAvailabilityDTO{
+			Asset:     assetToNodeDTO(asset),
+			Available: len(assetSlots) == 0 && models.NormalizeAssetStatus(asset.Status) == models.AssetStatusInStock,
+			Slots:     assetSlots,
+		})
+	}
+	return calendar, nil
+}
+
+// GetAll récupère les réservations ; hors gestionnaires du parc, seules les siennes
+func (s *assetReservationService) GetAll(filter repositories.AssetReservationFilter, userID uint, canManage bool) ([]dto.AssetReservationDTO, error) {
+	if !canManage {
+		filter.RequesterID = &userID
+	}
+	reservations, err := s.reservationRepo.FindAll(filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des réservations")
+	}
+	now := time.Now()
+	reservationDTOs := make([]dto.AssetReservationDTO, len(reservations))
+	for i := range reservations {
+		reservationDTOs[i] = assetReservationToDTO(&reservations[i], now)
+	}
+	return reservationDTOs, nil
+}
+
+// GetByID récupère une réservation (emprunteur, demandeur ou gestionnaire du parc)
+func (s *assetReservationService) GetByID(id uint, userID uint, canManage bool) (*dto.AssetReservationDTO, error) {
+	reservation, err := s.find(id, userID, canManage)
+	if err != nil {
+		return nil, err
+	}
+	reservationDTO := assetReservationToDTO(reservation, time.Now())
+	return &reservationDTO, nil
+}
+
+// Create enregistre une demande de réservation après contrôle des conflits de créneau
+func (s *assetReservationService) Create(req dto.CreateAssetReservationRequest, userID uint, canManage bool) (*dto.AssetReservationDTO, error) {
+	now := time.Now()
+	if !req.EndAt.After(req.StartAt) {
+		return nil, errors.New("la date de retour doit être postérieure au début du prêt")
+	}
+	if !req.EndAt.After(now) {
+		return nil, errors.New("la date de retour doit être dans le futur")
+	}
+
+	requesterID := userID
+	if req.RequesterID != nil && *req.RequesterID != userID {
+		if !canManage {
+			return nil, errors.New("seul un gestionnaire du parc de prêt peut réserver pour un autre utilisateur")
+		}
+		requester, err := s.userRepo.FindByID(*req.RequesterID)
+		if err != nil {
+			return nil, utils.ErrUserNotFound
+		}
+		if !requester.IsActive {
+			return nil, errors.New("l'emprunteur est désactivé")
+		}
+		requesterID = requester.ID
+	}
+
+	asset, err := s.assetRepo.FindByID(req.AssetID)
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+	if err := checkLoanable(asset); err != nil {
+		return nil, err
+	}
+	if err := s.checkConflicts(asset.ID, req.StartAt, req.EndAt, 0, models.AssetReservationBlockingStatuses); err != nil {
+		return nil, err
+	}
+
+	reservation := &models.AssetReservation{
+		AssetID:     asset.ID,
+		RequesterID: requesterID,
+		StartAt:     req.StartAt,
+		EndAt:       req.EndAt,
+		Purpose:     strings.TrimSpace(req.Purpose),
+		Status:      models.AssetReservationPending,
+		CreatedByID: userID,
+	}
+	// Les gestionnaires du parc réservent sans circuit d'approbation
+	if canManage {
+		reservation.Status = models.AssetReservationApproved
+		reservation.DecidedByID = &userID
+		reservation.DecidedAt = &now
+	}
+	if err := s.reservationRepo.Create(reservation); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la réservation")
+	}
+
+	created, err := s.reservationRepo.FindByID(reservation.ID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de la réservation")
+	}
+	if created.Status == models.AssetReservationPending {
+		s.notifyManagers(created, "asset_reservation_requested",
+			"Demande de réservation : "+asset.Name,
+			fmt.Sprintf("%s du %s au %s", reservationRequesterName(created), created.StartAt.Format("02/01/2006 15:04"), created.EndAt.Format("02/01/2006 15:04")))
+	}
+	reservationDTO := assetReservationToDTO(created, now)
+	return &reservationDTO, nil
+}
+
+// Approve approuve une réservation en attente (le créneau doit toujours être libre)
+func (s *assetReservationService) Approve(id uint, decidedByID uint, comment string) (*dto.AssetReservationDTO, error) {
+	return s.decide(id, decidedByID, true, comment)
+}
+
+// Reject refuse une réservation en attente
+func (s *assetReservationService) Reject(id uint, decidedByID uint, comment string) (*dto.AssetReservationDTO, error) {
+	return s.decide(id, decidedByID, false, comment)
+}
+
+// decide applique la décision sur une réservation en attente et en informe l'emprunteur
+func (s *assetReservationService) decide(id uint, decidedByID uint, approve bool, comment string) (*dto.AssetReservationDTO, error) {
+	reservation, err := s.reservationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrReservationNotFound
+	}
+	if reservation.Status != models.AssetReservationPending {
+		return nil, errors.New("cette réservation n'est plus en attente d'approbation")
+	}
+
+	status := models.AssetReservationRejected
+	if approve {
+		// Créneau déjà attribué entre-temps (demandes concurrentes)
+		if err := s.checkConflicts(reservation.AssetID, reservation.StartAt, reservation.EndAt, reservation.ID,
+			[]string{models.AssetReservationApproved, models.AssetReservationCheckedOut}); err != nil {
+			return nil, err
+		}
+		status = models.AssetReservationApproved
+	}
+	now := time.Now()
+	reservation.Status = status
+	reservation.DecidedByID = &decidedByID
+	reservation.DecidedAt = &now
+	reservation.DecisionComment = truncateRunes(strings.TrimSpace(comment), 500)
+	if err := s.reservationRepo.Update(reservation); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la réservation")
+	}
+
+	updated, err := s.reservationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de la réservation")
+	}
+	title := "Réservation refusée : " + reservationAssetName(updated)
+	if approve {
+		title = "Réservation approuvée : " + reservationAssetName(updated)
+	}
+	message := fmt.Sprintf("Du %s au %s", updated.StartAt.Format("02/01/2006 15:04"), updated.EndAt.Format("02/01/2006 15:04"))
+	if updated.DecisionComment != "" {
+		message += " - " + updated.DecisionComment
+	}
+	s.notify(updated.RequesterID, updated, "asset_reservation_decided", title, message)
+
+	reservationDTO := assetReservationToDTO(updated, now)
+	return &reservationDTO, nil
+}
+
+// Cancel annule une réservation pas encore remise (emprunteur, demandeur ou gestionnaire)
+func (s *assetReservationService) Cancel(id uint, userID uint, canManage bool) (*dto.AssetReservationDTO, error) {
+	reservation, err := s.find(id, userID, canManage)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != models.AssetReservationPending && reservation.Status != models.AssetReservationApproved {
+		return nil, errors.New("seule une réservation en attente ou approuvée peut être annulée")
+	}
+	reservation.Status = models.AssetReservationCancelled
+	if err := s.reservationRepo.Update(reservation); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la réservation")
+	}
+	reservationDTO := assetReservationToDTO(reservation, time.Now())
+	return &reservationDTO, nil
+}
+
+// CheckOut enregistre la remise de l'équipement à l'emprunteur
+func (s *assetReservationService) CheckOut(id uint, userID uint) (*dto.AssetReservationDTO, error) {
+	reservation, err := s.reservationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrReservationNotFound
+	}
+	if reservation.Status != models.AssetReservationApproved {
+		return nil, errors.New("seule une réservation approuvée peut être remise")
+	}
+	asset, err := s.assetRepo.FindByID(reservation.AssetID)
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+	if err := checkLoanable(asset); err != nil {
+		return nil, err
+	}
+	onLoan, err := s.reservationRepo.FindAll(repositories.AssetReservationFilter{
+		AssetID:  &reservation.AssetID,
+		Statuses: []string{models.AssetReservationCheckedOut},
+	})
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des réservations")
+	}
+	if len(onLoan) > 0 {
+		return nil, utils.ErrReservationConflict.WithDetails(map[string]any{"reservation_id": onLoan[0].ID, "reason": "not_returned"})
+	}
+
+	now := time.Now()
+	reservation.Status = models.AssetReservationCheckedOut
+	reservation.CheckedOutAt = &now
+	reservation.CheckedOutByID = &userID
+	if err := s.reservationRepo.Update(reservation); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la réservation")
+	}
+	reservationDTO := assetReservationToDTO(reservation, now)
+	return &reservationDTO, nil
+}
+
+// Return enregistre la restitution de l'équipement
+func (s *assetReservationService) Return(id uint, req dto.AssetReservationReturnRequest, userID uint) (*dto.AssetReservationDTO, error) {
+	reservation, err := s.reservationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrReservationNotFound
+	}
+	if reservation.Status != models.AssetReservationCheckedOut {
+		return nil, errors.New("seul un équipement remis peut être restitué")
+	}
+	now := time.Now()
+	reservation.Status = models.AssetReservationReturned
+	reservation.ReturnedAt = &now
+	reservation.ReturnedByID = &userID
+	reservation.ReturnNotes = truncateRunes(strings.TrimSpace(req.Notes), 500)
+	if err := s.reservationRepo.Update(reservation); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la réservation")
+	}
+	reservationDTO := assetReservationToDTO(reservation, now)
+	return &reservationDTO, nil
+}
+
+// SendReminders rappelle aux emprunteurs les retours dus dans le délai, puis signale les retards aux emprunteurs
+// et aux gestionnaires du parc ; chaque rappel n'est envoyé qu'une fois
+func (s *assetReservationService) SendReminders(dueWithin time.Duration) (int, error) {
+	now := time.Now()
+	sent := 0
+
+	due, err := s.reservationRepo.FindDueForReminder(now.Add(dueWithin))
+	if err != nil {
+		return 0, fmt.Errorf("prêts à rendre: %w", err)
+	}
+	for i := range due {
+		reservation := &due[i]
+		if reservation.EndAt.Before(now) {
+			continue // En retard : traité par l'alerte de retard
+		}
+		s.notify(reservation.RequesterID, reservation, "asset_reservation_due",
+			"Retour à prévoir : "+reservationAssetName(reservation),
+			"Retour attendu le "+reservation.EndAt.Format("02/01/2006 à 15:04"))
+		if err := s.reservationRepo.MarkDueReminderSent(reservation.ID, now); err != nil {
+			log.Printf("Erreur lors de l'enregistrement du rappel de la réservation %d: %v", reservation.ID, err)
+			continue
+		}
+		sent++
+	}
+
+	overdue, err := s.reservationRepo.FindOverdueUnnotified(now)
+	if err != nil {
+		return sent, fmt.Errorf("prêts en retard: %w", err)
+	}
+	for i := range overdue {
+		reservation := &overdue[i]
+		title := "Retour en retard : " + reservationAssetName(reservation)
+		message := "Retour attendu le " + reservation.EndAt.Format("02/01/2006 à 15:04")
+		s.notify(reservation.RequesterID, reservation, "asset_reservation_overdue", title, message)
+		s.notifyManagers(reservation, "asset_reservation_overdue", title, reservationRequesterName(reservation)+" - "+message)
+		if err := s.reservationRepo.MarkOverdueNotified(reservation.ID, now); err != nil {
+			log.Printf("Erreur lors de l'enregistrement de l'alerte de retard de la réservation %d: %v", reservation.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// find récupère une réservation visible par l'utilisateur (emprunteur, demandeur ou gestionnaire)
+func (s *assetReservationService) find(id uint, userID uint, canManage bool) (*models.AssetReservation, error) {
+	reservation, err := s.reservationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrReservationNotFound
+	}
+	if !canManage && reservation.RequesterID != userID && reservation.CreatedByID != userID {
+		return nil, utils.ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// checkConflicts refuse un créneau qui chevauche une réservation de l'équipement dans l'un des statuts donnés
+func (s *assetReservationService) checkConflicts(assetID uint, start, end time.Time, excludeID uint, statuses []string) error {
+	conflicts, err := s.reservationRepo.FindConflicts(assetID, start, end, excludeID)
+	if err != nil {
+		return utils.NewInternalError("erreur lors de la vérification des disponibilités")
+	}
+	for _, conflict := range conflicts {
+		if slices.Contains(statuses, conflict.Status) {
+			return utils.ErrReservationConflict.WithDetails(map[string]any{
+				"reservation_id": conflict.ID,
+				"start_at":       conflict.StartAt,
+				"end_at":         conflict.EndAt,
+				"status":         conflict.Status,
+			})
+		}
+	}
+	return nil
+}
+
+// notifyManagers notifie les gestionnaires du parc de prêt de la filiale de l'équipement
+func (s *assetReservationService) notifyManagers(reservation *models.AssetReservation, notificationType, title, message string) {
+	var filialeID *uint
+	if reservation.Asset != nil {
+		filialeID = reservation.Asset.FilialeID
+	}
+	managers, err := s.userRepo.FindActiveByPermission("asset_reservations.manage", filialeID)
+	if err != nil {
+		log.Printf("Erreur lors de la recherche des gestionnaires du parc de prêt (réservation %d): %v", reservation.ID, err)
+		return
+	}
+	for _, manager := range managers {
+		s.notify(manager.ID, reservation, notificationType, title, message)
+	}
+}
+
+// notify envoie une notification liée à la réservation
+func (s *assetReservationService) notify(userID uint, reservation *models.AssetReservation, notificationType, title, message string) {
+	metadata := map[string]any{
+		"reservation_id": reservation.ID,
+		"asset_id":       reservation.AssetID,
+		"start_at":       reservation.StartAt,
+		"end_at":         reservation.EndAt,
+	}
+	linkURL := fmt.Sprintf("/asset-reservations/%d", reservation.ID)
+	if err := s.notificationService.Create(userID, notificationType, title, message, linkURL, metadata); err != nil {
+		log.Printf("Erreur lors de la notification %s de la réservation %d à l'utilisateur %d: %v", notificationType, reservation.ID, userID, err)
+	}
+}
+
+// checkLoanable vérifie que l'actif fait partie du parc de prêt et peut être prêté
+func checkLoanable(asset *models.Asset) error {
+	if !asset.Loanable {
+		return errors.New("cet actif ne fait pas partie du parc de prêt")
+	}
+	if status := models.NormalizeAssetStatus(asset.Status); status != models.AssetStatusInStock {
+		return fmt.Errorf("cet équipement n'est pas disponible au prêt (statut: %s)", status)
+	}
+	return nil
+}
+
+// reservationAssetName retourne le libellé de l'équipement réservé
+func reservationAssetName(reservation *models.AssetReservation) string {
+	if reservation.Asset != nil && reservation.Asset.Name != "" {
+		return reservation.Asset.Name
+	}
+	return fmt.Sprintf("actif #%d", reservation.AssetID)
+}
+
+// reservationRequesterName retourne le nom de l'emprunteur
+func reservationRequesterName(reservation *models.AssetReservation) string {
+	if reservation.Requester != nil && reservation.Requester.ID != 0 {
+		if name := strings.TrimSpace(reservation.Requester.FirstName + " " + reservation.Requester.LastName); name != "" {
+			return name
+		}
+		return reservation.Requester.Username
+	}
+	return fmt.Sprintf("utilisateur #%d", reservation.RequesterID)
+}
+
+// assetReservationToDTO convertit une réservation en DTO
+func assetReservationToDTO(reservation *models.AssetReservation, now time.Time) dto.AssetReservationDTO {
+	reservationDTO := dto.AssetReservationDTO{
+		ID:              reservation.ID,
+		AssetID:         reservation.AssetID,
+		RequesterID:     reservation.RequesterID,
+		StartAt:         reservation.StartAt,
+		EndAt:           reservation.EndAt,
+		Purpose:         reservation.Purpose,
+		Status:          reservation.Status,
+		Overdue:         reservation.Status == models.AssetReservationCheckedOut && reservation.EndAt.Before(now),
+		DecidedAt:       reservation.DecidedAt,
+		DecisionComment: reservation.DecisionComment,
+		CheckedOutAt:    reservation.CheckedOutAt,
+		ReturnedAt:      reservation.ReturnedAt,
+		ReturnNotes:     reservation.ReturnNotes,
+		CreatedByID:     reservation.CreatedByID,
+		CreatedAt:       reservation.CreatedAt,
+		UpdatedAt:       reservation.UpdatedAt,
+	}
+	if reservation.Asset != nil && reservation.Asset.ID != 0 {
+		node := assetToNodeDTO(reservation.Asset)
+		reservationDTO.Asset = &node
+	}
+	if reservation.Requester != nil && reservation.Requester.ID != 0 {
+		requester := userToDTO(reservation.Requester)
+		reservationDTO.Requester = &requester
+	}
+	if reservation.DecidedBy != nil && reservation.DecidedBy.ID != 0 {
+		decidedBy := userToDTO(reservation.DecidedBy)
+		reservationDTO.DecidedBy = &decidedBy
+	}
+	return reservationDTO
+}
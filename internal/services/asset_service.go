@@ -3,18 +3,29 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
+	"log/slog"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/barcode"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // AssetService interface pour les opérations sur les actifs IT
 type AssetService interface {
 	Create(req dto.CreateAssetRequest, createdByID uint) (*dto.AssetDTO, error)
 	GetByID(id uint) (*dto.AssetDTO, error)
-	GetAll(scope interface{}) ([]dto.AssetDTO, error) // scope peut être *scope.QueryScope ou nil
+	GetByCode(code string) (*dto.AssetDTO, error)                       // Recherche par étiquette d'inventaire (scan du QR code)
+	GetQRCode(id uint, format string, size int) ([]byte, string, error) // QR code de l'étiquette (contenu, type MIME)
+	GetAll(scope interface{}) ([]dto.AssetDTO, error)                   // scope peut être *scope.QueryScope ou nil
 	GetByCategory(scope interface{}, categoryID uint) ([]dto.AssetDTO, error)
 	GetByStatus(scope interface{}, status string) ([]dto.AssetDTO, error)
 	GetByAssignedTo(userID uint) ([]dto.AssetDTO, error)
@@ -26,6 +37,21 @@ type AssetService interface {
 	LinkTicket(assetID uint, ticketID uint, linkedByID uint) error
 	UnlinkTicket(assetID uint, ticketID uint) error
 	Delete(id uint) error
+	Transition(id uint, req dto.AssetTransitionRequest, performedByID uint) (*dto.AssetDTO, error)
+	GetLifecycle(id uint) (*dto.AssetLifecycleDTO, error)
+	GetDepreciation(id uint, asOf time.Time) (*dto.AssetDepreciationDTO, error)
+	GetExpiring(scope interface{}, days int) ([]dto.AssetDTO, error) // Garantie ou contrat expirant dans les prochains jours
+}
+
+// assetLifecycleTransitions transitions autorisées depuis chaque statut du cycle de vie
+// (assigned -> assigned : réaffectation à un autre utilisateur)
+var assetLifecycleTransitions = map[string][]string{
+	models.AssetStatusOrdered:  {models.AssetStatusInStock, models.AssetStatusAssigned},
+	models.AssetStatusInStock:  {models.AssetStatusAssigned, models.AssetStatusInRepair, models.AssetStatusRetired},
+	models.AssetStatusAssigned: {models.AssetStatusAssigned, models.AssetStatusInStock, models.AssetStatusInRepair, models.AssetStatusRetired},
+	models.AssetStatusInRepair: {models.AssetStatusInStock, models.AssetStatusAssigned, models.AssetStatusRetired},
+	models.AssetStatusRetired:  {models.AssetStatusInStock, models.AssetStatusDisposed},
+	models.AssetStatusDisposed: {},
 }
 
 // assetService implémente AssetService
@@ -35,6 +61,7 @@ type assetService struct {
 	userRepo          repositories.UserRepository
 	ticketAssetRepo   repositories.TicketAssetRepository
 	ticketRepo        repositories.TicketRepository
+	lifecycleRepo     repositories.AssetLifecycleRepository
 }
 
 // NewAssetService crée une nouvelle instance de AssetService
@@ -44,6 +71,7 @@ func NewAssetService(
 	userRepo repositories.UserRepository,
 	ticketAssetRepo repositories.TicketAssetRepository,
 	ticketRepo repositories.TicketRepository,
+	lifecycleRepo repositories.AssetLifecycleRepository,
 ) AssetService {
 	return &assetService{
 		assetRepo:         assetRepo,
@@ -51,6 +79,7 @@ func NewAssetService(
 		userRepo:          userRepo,
 		ticketAssetRepo:   ticketAssetRepo,
 		ticketRepo:        ticketRepo,
+		lifecycleRepo:     lifecycleRepo,
 	}
 }
 
@@ -59,7 +88,7 @@ func (s *assetService) Create(req dto.CreateAssetRequest, createdByID uint) (*dt
 	// Vérifier que la catégorie existe
 	_, err := s.assetCategoryRepo.FindByID(req.CategoryID)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	// Vérifier que l'utilisateur assigné existe si fourni
@@ -85,15 +114,31 @@ func (s *assetService) Create(req dto.CreateAssetRequest, createdByID uint) (*dt
 		}
 	}
 
-	// Définir le statut par défaut
-	status := req.Status
+	// Étiquette d'inventaire fournie : unique (sinon attribuée à la création)
+	var code *string
+	if c := strings.TrimSpace(req.Code); c != "" {
+		if err := s.checkAssetCode(c, 0); err != nil {
+			return nil, err
+		}
+		code = &c
+	}
+
+	// Statut initial : en stock, ou affecté si un utilisateur est fourni
+	status := models.NormalizeAssetStatus(req.Status)
 	if status == "" {
-		status = "available"
+		status = models.AssetStatusInStock
+		if req.AssignedTo != nil {
+			status = models.AssetStatusAssigned
+		}
+	}
+	if status == models.AssetStatusAssigned && req.AssignedTo == nil {
+		return nil, errors.New("l'utilisateur à affecter est obligatoire")
 	}
 
 	// Créer l'actif
 	asset := &models.Asset{
 		Name:           req.Name,
+		Code:           code,
 		SerialNumber:   req.SerialNumber,
 		Model:          req.Model,
 		Manufacturer:   req.Manufacturer,
@@ -104,13 +149,36 @@ func (s *assetService) Create(req dto.CreateAssetRequest, createdByID uint) (*dt
 		WarrantyExpiry: warrantyExpiry,
 		Location:       req.Location,
 		Notes:          req.Notes,
+		Loanable:       req.Loanable,
 		CreatedByID:    &createdByID,
 	}
+	applyAssetFinancialFields(asset, req.AssetFinancialFields)
+	if asset.DepreciationMethod == "" {
+		asset.DepreciationMethod = models.AssetDepreciationStraightLine
+	}
+	now := time.Now()
+	switch status {
+	case models.AssetStatusRetired:
+		asset.RetiredAt = &now
+	case models.AssetStatusDisposed:
+		asset.DisposedAt = &now
+	}
 
 	if err := s.assetRepo.Create(asset); err != nil {
 		return nil, errors.New("erreur lors de la création de l'actif")
 	}
 
+	// Premier événement du cycle de vie
+	event := &models.AssetLifecycleEvent{
+		AssetID:       asset.ID,
+		ToStatus:      status,
+		AssignedToID:  asset.AssignedToID,
+		PerformedByID: createdByID,
+	}
+	if err := s.lifecycleRepo.CreateEvent(event); err != nil {
+		log.Printf("Erreur lors de l'historisation de la création de l'actif %d: %v", asset.ID, err)
+	}
+
 	// Récupérer l'actif créé avec ses relations
 	createdAsset, err := s.assetRepo.FindByID(asset.ID)
 	if err != nil {
@@ -125,13 +193,65 @@ func (s *assetService) Create(req dto.CreateAssetRequest, createdByID uint) (*dt
 func (s *assetService) GetByID(id uint) (*dto.AssetDTO, error) {
 	asset, err := s.assetRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("actif introuvable")
+		return nil, utils.ErrAssetNotFound
+	}
+
+	assetDTO := s.assetToDTO(asset)
+	return &assetDTO, nil
+}
+
+// GetByCode récupère un actif par son étiquette d'inventaire
+func (s *assetService) GetByCode(code string) (*dto.AssetDTO, error) {
+	asset, err := s.assetRepo.FindByCode(strings.TrimSpace(code))
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
 	}
 
 	assetDTO := s.assetToDTO(asset)
 	return &assetDTO, nil
 }
 
+// GetQRCode génère le QR code de l'étiquette de l'actif : lien vers la fiche du frontend si ASSET_LABEL_URL
+// est configurée, sinon l'étiquette seule (recherchée ensuite via /assets/by-code/{code})
+func (s *assetService) GetQRCode(id uint, format string, size int) ([]byte, string, error) {
+	format = strings.ToLower(format)
+	if format != barcode.FormatPNG && format != barcode.FormatSVG {
+		return nil, "", barcode.ErrUnsupportedFormat
+	}
+	asset, err := s.assetRepo.FindByID(id)
+	if err != nil {
+		return nil, "", utils.ErrAssetNotFound
+	}
+
+	code := models.AssetCodeFromID(asset.ID)
+	if asset.Code != nil && *asset.Code != "" {
+		code = *asset.Code
+	}
+	content, err := barcode.QRCode(assetLabelContent(code), format, size)
+	if err != nil {
+		return nil, "", errors.New("erreur lors de la génération du QR code")
+	}
+	return content, barcode.ContentType(format), nil
+}
+
+// checkAssetCode vérifie qu'aucun autre actif ne porte déjà l'étiquette
+func (s *assetService) checkAssetCode(code string, excludeID uint) error {
+	existing, err := s.assetRepo.FindByCode(code)
+	if err == nil && existing.ID != excludeID {
+		return errors.New("cette étiquette est déjà attribuée à un autre actif")
+	}
+	return nil
+}
+
+// assetLabelContent construit le contenu encodé dans le QR code d'une étiquette
+func assetLabelContent(code string) string {
+	base := strings.TrimRight(config.AppConfig.App.AssetLabelURL, "/")
+	if base == "" {
+		return code
+	}
+	return base + "/" + url.PathEscape(code)
+}
+
 // GetAll récupère tous les actifs
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *assetService) GetAll(scopeParam interface{}) ([]dto.AssetDTO, error) {
@@ -167,7 +287,7 @@ func (s *assetService) GetByCategory(scopeParam interface{}, categoryID uint) ([
 // GetByStatus récupère les actifs par statut
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *assetService) GetByStatus(scopeParam interface{}, status string) ([]dto.AssetDTO, error) {
-	assets, err := s.assetRepo.FindByStatus(scopeParam, status)
+	assets, err := s.assetRepo.FindByStatus(scopeParam, models.NormalizeAssetStatus(status))
 	if err != nil {
 		return nil, errors.New("erreur lors de la récupération des actifs")
 	}
@@ -199,13 +319,19 @@ func (s *assetService) GetByAssignedTo(userID uint) ([]dto.AssetDTO, error) {
 func (s *assetService) Update(id uint, req dto.UpdateAssetRequest, updatedByID uint) (*dto.AssetDTO, error) {
 	asset, err := s.assetRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("actif introuvable")
+		return nil, utils.ErrAssetNotFound
 	}
 
 	// Mettre à jour les champs fournis
 	if req.Name != "" {
 		asset.Name = req.Name
 	}
+	if c := strings.TrimSpace(req.Code); c != "" && (asset.Code == nil || *asset.Code != c) {
+		if err := s.checkAssetCode(c, asset.ID); err != nil {
+			return nil, err
+		}
+		asset.Code = &c
+	}
 	if req.SerialNumber != "" {
 		asset.SerialNumber = req.SerialNumber
 	}
@@ -219,24 +345,36 @@ func (s *assetService) Update(id uint, req dto.UpdateAssetRequest, updatedByID u
 		// Vérifier que la catégorie existe
 		_, err = s.assetCategoryRepo.FindByID(*req.CategoryID)
 		if err != nil {
-			return nil, errors.New("catégorie introuvable")
+			return nil, utils.ErrCategoryNotFound
 		}
 		asset.CategoryID = *req.CategoryID
 	}
-	if req.AssignedTo != nil {
+
+	// Statut cible : changement explicite, ou déduit de l'(dés)affectation d'un actif en stock ou affecté
+	current := models.NormalizeAssetStatus(asset.Status)
+	target := models.NormalizeAssetStatus(req.Status)
+	var assignTo *uint
+	if req.AssignedTo != nil && *req.AssignedTo != 0 {
 		// Vérifier que l'utilisateur existe si assigné
-		if *req.AssignedTo != 0 {
-			_, err = s.userRepo.FindByID(*req.AssignedTo)
-			if err != nil {
-				return nil, errors.New("utilisateur assigné introuvable")
-			}
-			asset.AssignedToID = req.AssignedTo
-		} else {
-			asset.AssignedToID = nil
+		_, err = s.userRepo.FindByID(*req.AssignedTo)
+		if err != nil {
+			return nil, errors.New("utilisateur assigné introuvable")
+		}
+		assignTo = req.AssignedTo
+	}
+	reassign := assignTo != nil && (asset.AssignedToID == nil || *asset.AssignedToID != *assignTo)
+	if target == "" && req.AssignedTo != nil {
+		switch {
+		case reassign && (current == models.AssetStatusInStock || current == models.AssetStatusAssigned):
+			target = models.AssetStatusAssigned
+		case assignTo == nil && current == models.AssetStatusAssigned:
+			target = models.AssetStatusInStock
 		}
 	}
-	if req.Status != "" {
-		asset.Status = req.Status
+	needsTransition := target != "" && (target != current || (target == models.AssetStatusAssigned && reassign))
+	if req.AssignedTo != nil && !needsTransition {
+		asset.AssignedToID = assignTo
+		asset.AssignedTo = nil
 	}
 	if req.PurchaseDate != nil && *req.PurchaseDate != "" {
 		parsed, err := time.Parse("2006-01-02", *req.PurchaseDate)
@@ -256,6 +394,15 @@ func (s *assetService) Update(id uint, req dto.UpdateAssetRequest, updatedByID u
 	if req.Notes != "" {
 		asset.Notes = req.Notes
 	}
+	if req.Loanable != nil {
+		asset.Loanable = *req.Loanable
+	}
+	applyAssetFinancialFields(asset, req.AssetFinancialFields)
+
+	// Un changement de statut passe par le cycle de vie (contrôle de la transition et historique)
+	if needsTransition {
+		return s.transition(asset, target, assignTo, "", updatedByID)
+	}
 
 	if err := s.assetRepo.Update(asset); err != nil {
 		return nil, errors.New("erreur lors de la mise à jour de l'actif")
@@ -275,41 +422,33 @@ func (s *assetService) Update(id uint, req dto.UpdateAssetRequest, updatedByID u
 func (s *assetService) Assign(id uint, req dto.AssignAssetRequest, assignedByID uint) (*dto.AssetDTO, error) {
 	asset, err := s.assetRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("actif introuvable")
-	}
-
-	// Vérifier que l'utilisateur existe
-	_, err = s.userRepo.FindByID(req.UserID)
-	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
-	}
-
-	asset.AssignedToID = &req.UserID
-	asset.Status = "in_use"
-
-	if err := s.assetRepo.Update(asset); err != nil {
-		return nil, errors.New("erreur lors de l'assignation de l'actif")
+		return nil, utils.ErrAssetNotFound
 	}
 
-	// Récupérer l'actif mis à jour
-	updatedAsset, err := s.assetRepo.FindByID(id)
-	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de l'actif mis à jour")
+	// Déjà affecté à cet utilisateur : rien à faire
+	if models.NormalizeAssetStatus(asset.Status) == models.AssetStatusAssigned &&
+		asset.AssignedToID != nil && *asset.AssignedToID == req.UserID {
+		assetDTO := s.assetToDTO(asset)
+		return &assetDTO, nil
 	}
 
-	assetDTO := s.assetToDTO(updatedAsset)
-	return &assetDTO, nil
+	return s.transition(asset, models.AssetStatusAssigned, &req.UserID, "", assignedByID)
 }
 
 // Unassign retire l'assignation d'un actif
 func (s *assetService) Unassign(id uint, req dto.AssignAssetRequest, unassignedByID uint) (*dto.AssetDTO, error) {
 	asset, err := s.assetRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("actif introuvable")
+		return nil, utils.ErrAssetNotFound
+	}
+
+	// Un actif affecté retourne en stock ; ailleurs (ex: en réparation) seule l'affectation est retirée
+	if models.NormalizeAssetStatus(asset.Status) == models.AssetStatusAssigned {
+		return s.transition(asset, models.AssetStatusInStock, nil, "", unassignedByID)
 	}
 
 	asset.AssignedToID = nil
-	asset.Status = "available"
+	asset.AssignedTo = nil
 
 	if err := s.assetRepo.Update(asset); err != nil {
 		return nil, errors.New("erreur lors de la désassignation de l'actif")
@@ -339,13 +478,15 @@ func (s *assetService) GetInventory(scopeParam interface{}) (*dto.AssetInventory
 	available := 0
 
 	for _, asset := range allAssets {
-		byStatus[asset.Status]++
+		status := models.NormalizeAssetStatus(asset.Status)
+		byStatus[status]++
 		if asset.Category.ID != 0 {
 			byCategory[asset.Category.Name]++
 		}
 		if asset.AssignedToID != nil {
 			assigned++
-		} else {
+		}
+		if status == models.AssetStatusInStock {
 			available++
 		}
 	}
@@ -364,7 +505,7 @@ func (s *assetService) GetLinkedTickets(assetID uint) ([]dto.TicketDTO, error) {
 	// Vérifier que l'actif existe
 	_, err := s.assetRepo.FindByID(assetID)
 	if err != nil {
-		return nil, errors.New("actif introuvable")
+		return nil, utils.ErrAssetNotFound
 	}
 
 	// Récupérer les associations
@@ -389,13 +530,13 @@ func (s *assetService) LinkTicket(assetID uint, ticketID uint, linkedByID uint)
 	// Vérifier que l'actif existe
 	_, err := s.assetRepo.FindByID(assetID)
 	if err != nil {
-		return errors.New("actif introuvable")
+		return utils.ErrAssetNotFound
 	}
 
 	// Vérifier que le ticket existe
 	_, err = s.ticketRepo.FindByID(ticketID)
 	if err != nil {
-		return errors.New("ticket introuvable")
+		return utils.ErrTicketNotFound
 	}
 
 	// Vérifier que l'association n'existe pas déjà
@@ -424,7 +565,7 @@ func (s *assetService) UnlinkTicket(assetID uint, ticketID uint) error {
 	// Vérifier que l'actif existe
 	_, err := s.assetRepo.FindByID(assetID)
 	if err != nil {
-		return errors.New("actif introuvable")
+		return utils.ErrAssetNotFound
 	}
 
 	// Supprimer l'association
@@ -478,7 +619,7 @@ func (s *assetService) ticketToDTO(ticket *models.Ticket) dto.TicketDTO {
 func (s *assetService) Delete(id uint) error {
 	_, err := s.assetRepo.FindByID(id)
 	if err != nil {
-		return errors.New("actif introuvable")
+		return utils.ErrAssetNotFound
 	}
 
 	if err := s.assetRepo.Delete(id); err != nil {
@@ -488,6 +629,277 @@ func (s *assetService) Delete(id uint) error {
 	return nil
 }
 
+// Transition fait passer un actif dans un nouveau statut de son cycle de vie
+func (s *assetService) Transition(id uint, req dto.AssetTransitionRequest, performedByID uint) (*dto.AssetDTO, error) {
+	asset, err := s.assetRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+
+	return s.transition(asset, req.Status, req.UserID, req.Comment, performedByID)
+}
+
+// GetLifecycle récupère le statut courant, les transitions possibles et l'historique du cycle de vie d'un actif
+func (s *assetService) GetLifecycle(id uint) (*dto.AssetLifecycleDTO, error) {
+	asset, err := s.assetRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+
+	events, err := s.lifecycleRepo.FindEvents(id)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'historique de l'actif")
+	}
+
+	status := models.NormalizeAssetStatus(asset.Status)
+	lifecycle := &dto.AssetLifecycleDTO{
+		AssetID:            asset.ID,
+		Status:             status,
+		AllowedTransitions: append([]string{}, assetLifecycleTransitions[status]...),
+		History:            make([]dto.AssetLifecycleEventDTO, 0, len(events)),
+	}
+	for _, event := range events {
+		eventDTO := dto.AssetLifecycleEventDTO{
+			ID:         event.ID,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Comment:    event.Comment,
+			CreatedAt:  event.CreatedAt,
+		}
+		if event.AssignedTo != nil && event.AssignedTo.ID != 0 {
+			assignedTo := s.userToDTO(event.AssignedTo)
+			eventDTO.AssignedTo = &assignedTo
+		}
+		if event.PerformedBy.ID != 0 {
+			performedBy := s.userToDTO(&event.PerformedBy)
+			eventDTO.PerformedBy = &performedBy
+		}
+		lifecycle.History = append(lifecycle.History, eventDTO)
+	}
+
+	return lifecycle, nil
+}
+
+// GetDepreciation calcule le plan d'amortissement d'un actif et sa valeur nette comptable à la date asOf
+func (s *assetService) GetDepreciation(id uint, asOf time.Time) (*dto.AssetDepreciationDTO, error) {
+	asset, err := s.assetRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+
+	return computeAssetDepreciation(asset, asOf)
+}
+
+// GetExpiring récupère les actifs dont la garantie ou le contrat expire dans les prochains jours
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (s *assetService) GetExpiring(scopeParam interface{}, days int) ([]dto.AssetDTO, error) {
+	if days < 1 || days > 365 {
+		days = 30
+	}
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 0, days)
+
+	assets, err := s.lifecycleRepo.FindExpiring(scopeParam, from, to)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des actifs")
+	}
+
+	assetDTOs := make([]dto.AssetDTO, 0, len(assets))
+	for _, asset := range assets {
+		assetDTOs = append(assetDTOs, s.assetToDTO(&asset))
+	}
+
+	return assetDTOs, nil
+}
+
+// transition contrôle et applique une transition du cycle de vie, l'historise et retourne l'actif à jour
+func (s *assetService) transition(asset *models.Asset, to string, userID *uint, comment string, performedByID uint) (*dto.AssetDTO, error) {
+	from := models.NormalizeAssetStatus(asset.Status)
+	if !slices.Contains(assetLifecycleTransitions[from], to) {
+		return nil, utils.ErrAssetTransition.WithDetails(map[string]any{
+			"from":    from,
+			"to":      to,
+			"allowed": assetLifecycleTransitions[from],
+		})
+	}
+
+	now := time.Now()
+	switch to {
+	case models.AssetStatusAssigned:
+		if userID == nil || *userID == 0 {
+			// Retour de réparation : l'actif revient à l'utilisateur auquel il était affecté
+			if from != models.AssetStatusInRepair || asset.AssignedToID == nil {
+				return nil, errors.New("l'utilisateur à affecter est obligatoire")
+			}
+			userID = asset.AssignedToID
+		} else if _, err := s.userRepo.FindByID(*userID); err != nil {
+			return nil, utils.ErrUserNotFound
+		}
+		if from == models.AssetStatusAssigned && asset.AssignedToID != nil && *asset.AssignedToID == *userID {
+			return nil, errors.New("l'actif est déjà affecté à cet utilisateur")
+		}
+		asset.AssignedToID = userID
+		if asset.InServiceDate == nil && asset.PurchaseDate == nil {
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			asset.InServiceDate = &today
+		}
+	case models.AssetStatusInStock:
+		asset.AssignedToID = nil
+		asset.RetiredAt = nil
+	case models.AssetStatusRetired:
+		asset.AssignedToID = nil
+		asset.RetiredAt = &now
+	case models.AssetStatusDisposed:
+		asset.DisposedAt = &now
+	}
+	asset.Status = to
+
+	event := &models.AssetLifecycleEvent{
+		FromStatus:    from,
+		ToStatus:      to,
+		AssignedToID:  asset.AssignedToID,
+		Comment:       comment,
+		PerformedByID: performedByID,
+	}
+	if err := s.lifecycleRepo.Transition(asset, event); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement de la transition de l'actif")
+	}
+
+	updatedAsset, err := s.assetRepo.FindByID(asset.ID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'actif mis à jour")
+	}
+
+	assetDTO := s.assetToDTO(updatedAsset)
+	return &assetDTO, nil
+}
+
+// applyAssetFinancialFields applique les champs contrat et amortissement fournis
+func applyAssetFinancialFields(asset *models.Asset, fields dto.AssetFinancialFields) {
+	if fields.Supplier != "" {
+		asset.Supplier = fields.Supplier
+	}
+	if fields.ContractReference != "" {
+		asset.ContractReference = fields.ContractReference
+	}
+	if date := parseDate(fields.ContractEndDate); date != nil {
+		asset.ContractEndDate = date
+	}
+	if fields.PurchaseCost != nil {
+		asset.PurchaseCost = fields.PurchaseCost
+	}
+	if fields.SalvageValue != nil {
+		asset.SalvageValue = fields.SalvageValue
+	}
+	if fields.DepreciationMonths != nil {
+		asset.DepreciationMonths = fields.DepreciationMonths
+	}
+	if fields.DepreciationMethod != "" {
+		asset.DepreciationMethod = fields.DepreciationMethod
+	}
+	if date := parseDate(fields.InServiceDate); date != nil {
+		asset.InServiceDate = date
+	}
+}
+
+// computeAssetDepreciation calcule le plan d'amortissement d'un actif et sa valeur nette comptable à asOf
+// L'amortissement démarre à la mise en service (défaut: date d'achat) ; le mois de départ est amorti en totalité
+func computeAssetDepreciation(asset *models.Asset, asOf time.Time) (*dto.AssetDepreciationDTO, error) {
+	start := asset.InServiceDate
+	if start == nil {
+		start = asset.PurchaseDate
+	}
+	if asset.PurchaseCost == nil || asset.DepreciationMonths == nil || *asset.DepreciationMonths <= 0 || start == nil {
+		return nil, errors.New("données d'amortissement incomplètes : coût d'achat, durée et date d'achat ou de mise en service requis")
+	}
+
+	cost := *asset.PurchaseCost
+	salvage := 0.0
+	if asset.SalvageValue != nil {
+		salvage = math.Min(*asset.SalvageValue, cost)
+	}
+	months := *asset.DepreciationMonths
+	method := asset.DepreciationMethod
+	if method == "" {
+		method = models.AssetDepreciationStraightLine
+	}
+	amounts := monthlyDepreciation(method, cost, salvage, months)
+
+	// Mois entamés entre la mise en service et asOf
+	elapsed := 0
+	if !asOf.Before(*start) {
+		elapsed = (asOf.Year()-start.Year())*12 + int(asOf.Month()-start.Month()) + 1
+	}
+	elapsed = min(elapsed, months)
+
+	accumulated := 0.0
+	for _, amount := range amounts[:elapsed] {
+		accumulated += amount
+	}
+
+	// Plan par année civile
+	firstMonth := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location())
+	schedule := []dto.AssetDepreciationPeriodDTO{}
+	value, yearDepreciation := cost, 0.0
+	for i, amount := range amounts {
+		year := firstMonth.AddDate(0, i, 0).Year()
+		if len(schedule) == 0 || schedule[len(schedule)-1].Year != year {
+			schedule = append(schedule, dto.AssetDepreciationPeriodDTO{Year: year, OpeningValue: roundAmount(value)})
+			yearDepreciation = 0
+		}
+		period := &schedule[len(schedule)-1]
+		value -= amount
+		yearDepreciation += amount
+		period.Months++
+		period.Depreciation = roundAmount(yearDepreciation)
+		period.ClosingValue = roundAmount(value)
+	}
+
+	return &dto.AssetDepreciationDTO{
+		AssetID:                 asset.ID,
+		Method:                  method,
+		PurchaseCost:            roundAmount(cost),
+		SalvageValue:            roundAmount(salvage),
+		DepreciationMonths:      months,
+		StartDate:               *start,
+		AsOf:                    asOf,
+		MonthsElapsed:           elapsed,
+		AccumulatedDepreciation: roundAmount(accumulated),
+		BookValue:               roundAmount(cost - accumulated),
+		FullyDepreciated:        elapsed >= months,
+		Schedule:                schedule,
+	}, nil
+}
+
+// monthlyDepreciation retourne la dotation de chaque mois de la durée d'amortissement
+// Dégressif : taux double du taux linéaire appliqué à la valeur restante, avec bascule en linéaire
+// sur la durée restante dès que la dotation linéaire devient supérieure
+func monthlyDepreciation(method string, cost, salvage float64, months int) []float64 {
+	amounts := make([]float64, months)
+	if method != models.AssetDepreciationDecliningBalance {
+		for i := range amounts {
+			amounts[i] = (cost - salvage) / float64(months)
+		}
+		return amounts
+	}
+
+	rate := math.Min(2/float64(months), 1)
+	value := cost
+	for i := range amounts {
+		amount := math.Max(value*rate, (value-salvage)/float64(months-i))
+		amount = math.Min(amount, value-salvage)
+		amounts[i] = amount
+		value -= amount
+	}
+	return amounts
+}
+
+// roundAmount arrondit un montant au centime
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
 // assetToDTO convertit un modèle Asset en DTO
 func (s *assetService) assetToDTO(asset *models.Asset) dto.AssetDTO {
 	assetDTO := dto.AssetDTO{
@@ -497,11 +909,32 @@ func (s *assetService) assetToDTO(asset *models.Asset) dto.AssetDTO {
 		Model:        asset.Model,
 		Manufacturer: asset.Manufacturer,
 		CategoryID:   asset.CategoryID,
-		Status:       asset.Status,
+		Status:       models.NormalizeAssetStatus(asset.Status),
 		Location:     asset.Location,
 		Notes:        asset.Notes,
 		CreatedAt:    asset.CreatedAt,
 		UpdatedAt:    asset.UpdatedAt,
+
+		Supplier:           asset.Supplier,
+		ContractReference:  asset.ContractReference,
+		ContractEndDate:    asset.ContractEndDate,
+		PurchaseCost:       asset.PurchaseCost,
+		SalvageValue:       asset.SalvageValue,
+		DepreciationMonths: asset.DepreciationMonths,
+		DepreciationMethod: asset.DepreciationMethod,
+		InServiceDate:      asset.InServiceDate,
+		RetiredAt:          asset.RetiredAt,
+		DisposedAt:         asset.DisposedAt,
+		LastDiscoveredAt:   asset.LastDiscoveredAt,
+		Loanable:           asset.Loanable,
+	}
+	if asset.Code != nil {
+		assetDTO.Code = *asset.Code
+	}
+
+	// Valeur nette comptable à date si les données d'amortissement sont renseignées
+	if depreciation, err := computeAssetDepreciation(asset, time.Now()); err == nil {
+		assetDTO.BookValue = &depreciation.BookValue
 	}
 
 	if asset.PurchaseDate != nil {
@@ -625,7 +1058,7 @@ func (s *assetCategoryService) Create(req dto.CreateAssetCategoryRequest, create
 func (s *assetCategoryService) GetByID(id uint) (*dto.AssetCategoryDTO, error) {
 	category, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	categoryDTO := s.categoryToDTO(category)
@@ -706,7 +1139,7 @@ func (s *assetCategoryService) GetByParentID(parentID uint) ([]dto.AssetCategory
 func (s *assetCategoryService) Update(id uint, req dto.UpdateAssetCategoryRequest, updatedByID uint) (*dto.AssetCategoryDTO, error) {
 	category, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	if req.Name != "" {
@@ -737,19 +1170,16 @@ func (s *assetCategoryService) Delete(id uint, confirmName string) error {
 	// Vérifier que la catégorie existe
 	category, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return errors.New("catégorie introuvable")
+		return utils.ErrCategoryNotFound
 	}
 
-	// Log pour débogage
-	fmt.Printf("DEBUG Delete: Tentative de suppression de la catégorie ID=%d, Nom=%s\n", id, category.Name)
+	slog.Debug("asset_category_delete", "category_id", id, "name", category.Name)
 
 	// Vérifier si la catégorie a des sous-catégories
 	subCategoryCount, err := s.categoryRepo.CountByParentID(id)
 	if err != nil {
-		fmt.Printf("DEBUG Delete: Erreur lors du comptage des sous-catégories: %v\n", err)
 		return fmt.Errorf("erreur lors de la vérification des sous-catégories: %v", err)
 	}
-	fmt.Printf("DEBUG Delete: Nombre de sous-catégories trouvées: %d\n", subCategoryCount)
 
 	// Si la catégorie a des sous-catégories, vérifier la confirmation
 	if subCategoryCount > 0 {
@@ -761,7 +1191,7 @@ func (s *assetCategoryService) Delete(id uint, confirmName string) error {
 			return errors.New("le nom de confirmation ne correspond pas au nom de la catégorie")
 		}
 		// Supprimer toutes les sous-catégories en cascade
-		fmt.Printf("DEBUG Delete: Suppression en cascade de %d sous-catégories\n", subCategoryCount)
+		slog.Debug("asset_category_delete_cascade", "category_id", id, "sub_categories", subCategoryCount)
 		subCategories, err := s.categoryRepo.FindByParentID(id)
 		if err != nil {
 			return fmt.Errorf("erreur lors de la récupération des sous-catégories: %v", err)
@@ -779,22 +1209,18 @@ func (s *assetCategoryService) Delete(id uint, confirmName string) error {
 	// Vérifier si la catégorie a des actifs associés
 	assetCount, err := s.assetRepo.CountByCategory(id)
 	if err != nil {
-		fmt.Printf("DEBUG Delete: Erreur lors du comptage des actifs: %v\n", err)
 		return fmt.Errorf("erreur lors de la vérification des actifs associés: %v", err)
 	}
-	fmt.Printf("DEBUG Delete: Nombre d'actifs trouvés: %d\n", assetCount)
 	if assetCount > 0 {
 		return fmt.Errorf("impossible de supprimer cette catégorie car elle est utilisée par %d actif(s). Veuillez d'abord modifier ou supprimer les actifs associés", assetCount)
 	}
 
 	// Supprimer la catégorie
-	fmt.Printf("DEBUG Delete: Suppression de la catégorie ID=%d\n", id)
 	if err := s.categoryRepo.Delete(id); err != nil {
-		fmt.Printf("DEBUG Delete: Erreur lors de la suppression: %v\n", err)
 		return fmt.Errorf("erreur lors de la suppression de la catégorie: %v", err)
 	}
 
-	fmt.Printf("DEBUG Delete: Catégorie ID=%d supprimée avec succès\n", id)
+	slog.Debug("asset_category_deleted", "category_id", id)
 	return nil
 }
 
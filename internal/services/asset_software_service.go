@@ -8,6 +8,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // AssetSoftwareService interface pour les opérations sur les logiciels installés
@@ -46,7 +47,7 @@ func (s *assetSoftwareService) Create(req dto.CreateAssetSoftwareRequest) (*dto.
 	if req.AssetID != nil && *req.AssetID > 0 {
 		_, err := s.assetRepo.FindByID(*req.AssetID)
 		if err != nil {
-			return nil, errors.New("actif introuvable")
+			return nil, utils.ErrAssetNotFound
 		}
 	}
 
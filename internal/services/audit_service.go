@@ -1,13 +1,24 @@
 package services
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
 	"time"
 
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/audit"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/storage"
 )
 
 // AuditService interface pour les opérations sur les logs d'audit
@@ -18,17 +29,41 @@ type AuditService interface {
 	GetByAction(scope interface{}, action string) ([]dto.AuditLogDTO, error)
 	GetByEntity(scope interface{}, entityType string, entityID uint) ([]dto.AuditLogDTO, error)
 	GetTicketAuditTrail(scope interface{}, ticketID uint) ([]dto.AuditLogDTO, error)
+	VerifyChain() (*dto.AuditChainVerificationDTO, error)
+	Export(scope interface{}, filter dto.AuditLogExportFilter, format string, w io.Writer) error
+	Archive(ctx context.Context) (int64, error)
 }
 
+// Formats d'export du journal d'audit
+const (
+	AuditExportCSV   = "csv"
+	AuditExportJSONL = "jsonl"
+)
+
+// auditChainBatchSize nombre d'entrées lues par lot lors de la vérification du chaînage
+const auditChainBatchSize = 1000
+
+// auditExportBatchSize nombre d'entrées lues par lot lors d'un export (écrites au fil de l'eau)
+const auditExportBatchSize = 500
+
+// auditArchiveBatchSize nombre d'entrées par fichier d'archive
+const auditArchiveBatchSize = 5000
+
+// auditExportColumns colonnes de l'export CSV
+var auditExportColumns = []string{"id", "created_at", "user_id", "username", "on_behalf_of_id", "action", "entity_type", "entity_id", "ip_address", "user_agent", "description", "old_values", "new_values", "hash"}
+
 // auditService implémente AuditService
 type auditService struct {
-	auditLogRepo repositories.AuditLogRepository
+	auditLogRepo   repositories.AuditLogRepository
+	archiveStorage storage.Storage
 }
 
 // NewAuditService crée une nouvelle instance de AuditService
-func NewAuditService(auditLogRepo repositories.AuditLogRepository) AuditService {
+// archiveStorage reçoit les logs archivés avant leur purge (nil : pas d'archivage)
+func NewAuditService(auditLogRepo repositories.AuditLogRepository, archiveStorage storage.Storage) AuditService {
 	return &auditService{
-		auditLogRepo: auditLogRepo,
+		auditLogRepo:   auditLogRepo,
+		archiveStorage: archiveStorage,
 	}
 }
 
@@ -146,6 +181,200 @@ func (s *auditService) GetTicketAuditTrail(scopeParam interface{}, ticketID uint
 	return s.GetByEntity(scopeParam, "ticket", ticketID)
 }
 
+// VerifyChain recalcule les empreintes de toutes les entrées chaînées et contrôle leur enchaînement
+// La première entrée chaînée sert d'ancrage (les entrées plus anciennes peuvent avoir été purgées)
+func (s *auditService) VerifyChain() (*dto.AuditChainVerificationDTO, error) {
+	result := &dto.AuditChainVerificationDTO{Valid: true}
+	var afterID uint
+	previousHash := ""
+	for {
+		logs, err := s.auditLogRepo.FindChain(afterID, auditChainBatchSize)
+		if err != nil {
+			return nil, errors.New("erreur lors de la vérification du journal d'audit")
+		}
+		for i := range logs {
+			entry := &logs[i]
+			afterID = entry.ID
+			if entry.Hash == "" {
+				if result.FirstID == 0 {
+					result.Unchained++
+					continue
+				}
+				return invalidAuditChain(result, entry.ID, "entrée sans empreinte dans la chaîne"), nil
+			}
+			if result.FirstID == 0 {
+				result.FirstID = entry.ID
+			} else if entry.PrevHash != previousHash {
+				return invalidAuditChain(result, entry.ID, "chaînage rompu : entrée précédente supprimée ou modifiée"), nil
+			}
+			if audit.Hash(entry) != entry.Hash {
+				return invalidAuditChain(result, entry.ID, "contenu modifié après enregistrement"), nil
+			}
+			previousHash = entry.Hash
+			result.Checked++
+			result.LastID = entry.ID
+			result.LastHash = entry.Hash
+		}
+		if len(logs) < auditChainBatchSize {
+			break
+		}
+	}
+	result.VerifiedAt = time.Now()
+	return result, nil
+}
+
+// Export écrit les logs filtrés (dans le périmètre du scope) en CSV ou JSONL, lot par lot
+// Chaque lot est transmis au client dès qu'il est écrit si w le permet (http.Flusher)
+func (s *auditService) Export(scopeParam interface{}, filter dto.AuditLogExportFilter, format string, w io.Writer) error {
+	if format != AuditExportCSV && format != AuditExportJSONL {
+		return errors.New("format d'export non supporté (csv ou jsonl)")
+	}
+	repoFilter := repositories.AuditLogFilter{
+		From:       filter.From,
+		To:         filter.To,
+		UserID:     filter.UserID,
+		Action:     filter.Action,
+		EntityType: filter.EntityType,
+		EntityID:   filter.EntityID,
+	}
+
+	var csvWriter *csv.Writer
+	encoder := json.NewEncoder(w)
+	if format == AuditExportCSV {
+		csvWriter = csv.NewWriter(w)
+	}
+	flusher, _ := w.(interface{ Flush() })
+
+	var afterID uint
+	for {
+		logs, err := s.auditLogRepo.FindForExport(scopeParam, repoFilter, afterID, auditExportBatchSize)
+		if err != nil {
+			return errors.New("erreur lors de l'export des logs d'audit")
+		}
+		// En-tête CSV écrit après la première lecture : une erreur initiale peut encore être retournée au client
+		if csvWriter != nil && afterID == 0 {
+			if err := csvWriter.Write(auditExportColumns); err != nil {
+				return err
+			}
+		}
+		for i := range logs {
+			entry := &logs[i]
+			afterID = entry.ID
+			if csvWriter != nil {
+				err = csvWriter.Write(auditLogToCSV(entry))
+			} else {
+				err = encoder.Encode(s.auditLogToDTO(entry))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if len(logs) < auditExportBatchSize {
+			return nil
+		}
+	}
+}
+
+// Archive déplace les logs plus anciens que la durée de conservation (AUDIT_RETENTION_DAYS) vers le stockage
+// d'archives (fichiers JSONL compressés, empreintes comprises pour rester vérifiables), puis les purge de la base
+func (s *auditService) Archive(ctx context.Context) (int64, error) {
+	retentionDays := config.AppConfig.App.AuditRetentionDays
+	if retentionDays <= 0 || s.archiveStorage == nil {
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	var archived int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return archived, err
+		}
+		logs, err := s.auditLogRepo.FindOlderThan(cutoff, auditArchiveBatchSize)
+		if err != nil {
+			return archived, fmt.Errorf("lecture des logs à archiver: %w", err)
+		}
+		if len(logs) == 0 {
+			return archived, nil
+		}
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		encoder := json.NewEncoder(gz)
+		for i := range logs {
+			if err := encoder.Encode(&logs[i]); err != nil {
+				return archived, fmt.Errorf("sérialisation des logs à archiver: %w", err)
+			}
+		}
+		if err := gz.Close(); err != nil {
+			return archived, fmt.Errorf("compression des logs à archiver: %w", err)
+		}
+
+		first, last := logs[0], logs[len(logs)-1]
+		key := fmt.Sprintf("%s/audit_logs_%d-%d.jsonl.gz", first.CreatedAt.Format("2006/01"), first.ID, last.ID)
+		if err := s.archiveStorage.Put(ctx, key, &buf, int64(buf.Len()), "application/gzip"); err != nil {
+			return archived, fmt.Errorf("écriture de l'archive %s: %w", key, err)
+		}
+
+		// Purge seulement après l'écriture de l'archive
+		deleted, err := s.auditLogRepo.DeleteArchived(cutoff, last.ID)
+		if err != nil {
+			return archived, fmt.Errorf("purge des logs archivés: %w", err)
+		}
+		archived += deleted
+		log.Printf("Audit: %d logs archivés dans %s", deleted, key)
+	}
+}
+
+// auditLogToCSV convertit un log d'audit en ligne CSV (voir auditExportColumns)
+func auditLogToCSV(entry *models.AuditLog) []string {
+	username := ""
+	if entry.User != nil {
+		username = entry.User.Username
+	}
+	return []string{
+		strconv.FormatUint(uint64(entry.ID), 10),
+		entry.CreatedAt.Format(time.RFC3339),
+		formatOptionalID(entry.UserID),
+		username,
+		formatOptionalID(entry.OnBehalfOfID),
+		entry.Action,
+		entry.EntityType,
+		formatOptionalID(entry.EntityID),
+		entry.IPAddress,
+		entry.UserAgent,
+		entry.Description,
+		string(entry.OldValues),
+		string(entry.NewValues),
+		entry.Hash,
+	}
+}
+
+// formatOptionalID formate un ID optionnel (vide si absent)
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// invalidAuditChain complète le résultat de vérification avec la première anomalie détectée
+func invalidAuditChain(result *dto.AuditChainVerificationDTO, id uint, reason string) *dto.AuditChainVerificationDTO {
+	result.Valid = false
+	result.FirstInvalidID = &id
+	result.Reason = reason
+	result.VerifiedAt = time.Now()
+	return result
+}
+
 // auditLogToDTO convertit un modèle AuditLog en DTO
 func (s *auditService) auditLogToDTO(log *models.AuditLog) dto.AuditLogDTO {
 	logDTO := dto.AuditLogDTO{
@@ -157,6 +386,7 @@ func (s *auditService) auditLogToDTO(log *models.AuditLog) dto.AuditLogDTO {
 		UserAgent:   log.UserAgent,
 		Description: log.Description,
 		CreatedAt:   log.CreatedAt,
+		Hash:        log.Hash,
 	}
 
 	if log.UserID != nil {
@@ -177,6 +407,22 @@ func (s *auditService) auditLogToDTO(log *models.AuditLog) dto.AuditLogDTO {
 		}
 	}
 
+	if log.OnBehalfOfID != nil {
+		logDTO.OnBehalfOfID = log.OnBehalfOfID
+		if log.OnBehalfOf != nil {
+			logDTO.OnBehalfOf = &dto.UserDTO{
+				ID:        log.OnBehalfOf.ID,
+				Username:  log.OnBehalfOf.Username,
+				Email:     log.OnBehalfOf.Email,
+				FirstName: log.OnBehalfOf.FirstName,
+				LastName:  log.OnBehalfOf.LastName,
+				IsActive:  log.OnBehalfOf.IsActive,
+				CreatedAt: log.OnBehalfOf.CreatedAt,
+				UpdatedAt: log.OnBehalfOf.UpdatedAt,
+			}
+		}
+	}
+
 	// Convertir les valeurs JSON si présentes
 	if log.OldValues != nil && len(log.OldValues) > 0 {
 		var oldValues map[string]interface{}
@@ -1,15 +1,19 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/mcicare/itsm-backend/config"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
 	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
@@ -26,14 +30,16 @@ type authService struct {
 	userRepo    repositories.UserRepository
 	sessionRepo repositories.UserSessionRepository
 	roleRepo    repositories.RoleRepository
+	eventBus    *events.Bus
 }
 
 // NewAuthService crée une nouvelle instance de AuthService
-func NewAuthService(userRepo repositories.UserRepository, sessionRepo repositories.UserSessionRepository, roleRepo repositories.RoleRepository) AuthService {
+func NewAuthService(userRepo repositories.UserRepository, sessionRepo repositories.UserSessionRepository, roleRepo repositories.RoleRepository, eventBus *events.Bus) AuthService {
 	return &authService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
 		roleRepo:    roleRepo,
+		eventBus:    eventBus,
 	}
 }
 
@@ -211,6 +217,19 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
 		// On pourrait utiliser un logger ici
 	}
 
+	// Tracer la connexion (journal d'audit, résumé d'activité)
+	if s.eventBus != nil {
+		actorID := user.ID
+		s.eventBus.Publish(context.Background(), events.Event{
+			Type:       events.UserLoggedIn,
+			ActorID:    &actorID,
+			FilialeID:  user.FilialeID,
+			EntityType: "users",
+			EntityID:   user.ID,
+			Metadata:   map[string]any{"username": user.Username},
+		})
+	}
+
 	// Convertir l'utilisateur en DTO
 	userDTO := s.userToDTO(user)
 
@@ -233,7 +252,7 @@ func (s *authService) RefreshToken(refreshToken string) (string, error) {
 	// Trouver l'utilisateur
 	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
-		return "", errors.New("utilisateur introuvable")
+		return "", utils.ErrUserNotFound
 	}
 
 	// Vérifier si l'utilisateur est actif
@@ -309,6 +328,7 @@ func (s *authService) userToDTO(user *models.User) dto.UserDTO {
 		LastName:     user.LastName,
 		DepartmentID: user.DepartmentID,
 		FilialeID:    user.FilialeID,
+		ManagerID:    user.ManagerID,
 		Avatar:       user.Avatar,
 		Role:         user.Role.Name,                           // Nom du rôle brut (ex: "DSI")
 		Permissions:  s.getPermissionsForRole(user.Role.Name),   // Permissions dérivées du rôle
@@ -317,6 +337,8 @@ func (s *authService) userToDTO(user *models.User) dto.UserDTO {
 		CreatedAt:    user.CreatedAt,
 		UpdatedAt:    user.UpdatedAt,
 	}
+	userDTO.AvatarURL, userDTO.AvatarThumbnailURL = avatarURLs(user.Avatar)
+	userDTO.OutOfOffice = outOfOfficeToDTO(user)
 
 	// Inclure la filiale si présente
 	if user.Filiale != nil {
@@ -369,28 +391,13 @@ func (s *authService) userToDTO(user *models.User) dto.UserDTO {
 	return userDTO
 }
 
-// getPermissionsForRole retourne la liste des permissions associées à un rôle donné.
-// Les permissions sont récupérées depuis la base de données via la table role_permissions
-// Cette fonction doit être identique à celle dans scope.go pour la cohérence
+// getPermissionsForRole retourne la liste des permissions associées à un rôle donné (cache du package scope).
+// En cas d'indisponibilité, aucune permission n'est retournée plutôt qu'un ensemble par défaut
 func (s *authService) getPermissionsForRole(roleName string) []string {
-	// Récupérer le rôle par son nom
-	role, err := s.roleRepo.FindByName(roleName)
+	permissions, err := scope.PermissionsForRole(roleName)
 	if err != nil {
-		// Retourner des permissions minimales par défaut si le rôle n'existe pas
-		return []string{"tickets.view_own"}
-	}
-	
-	// Récupérer les permissions du rôle depuis la base de données
-	permissions, err := s.roleRepo.GetPermissionsByRoleID(role.ID)
-	if err != nil {
-		// Retourner des permissions minimales par défaut en cas d'erreur
-		return []string{"tickets.view_own"}
-	}
-	
-	// Si aucune permission n'est assignée, retourner des permissions minimales
-	if len(permissions) == 0 {
-		return []string{"tickets.view_own"}
+		slog.Warn("role_permissions_unavailable", "role", roleName, "error", err)
+		return []string{}
 	}
-	
 	return permissions
 }
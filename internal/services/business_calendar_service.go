@@ -0,0 +1,390 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/businesstime"
+	"github.com/mcicare/itsm-backend/internal/cache"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// businessCalendarsCacheKey clé du cache des calendriers actifs (invalidée à chaque modification)
+const businessCalendarsCacheKey = "business_calendars:active"
+
+// BusinessCalendarService interface pour la gestion des calendriers ouvrés utilisés par les SLA
+type BusinessCalendarService interface {
+	GetAll() ([]dto.BusinessCalendarDTO, error)
+	GetByID(id uint) (*dto.BusinessCalendarDTO, error)
+	Create(req dto.CreateBusinessCalendarRequest, createdByID uint) (*dto.BusinessCalendarDTO, error)
+	Update(id uint, req dto.UpdateBusinessCalendarRequest) (*dto.BusinessCalendarDTO, error)
+	Delete(id uint) error
+	AddHoliday(calendarID uint, req dto.CreateBusinessHolidayRequest) (*dto.BusinessCalendarDTO, error)
+	DeleteHoliday(calendarID, holidayID uint) error
+	CalendarFor(filialeID *uint) *businesstime.Calendar // Calendrier de la filiale, sinon par défaut ; nil = 24h/24
+}
+
+// businessCalendarService implémente BusinessCalendarService
+type businessCalendarService struct {
+	calendarRepo repositories.BusinessCalendarRepository
+	filialeRepo  repositories.FilialeRepository
+}
+
+// businessCalendarSet calendriers actifs prêts au calcul, par filiale
+type businessCalendarSet struct {
+	byFiliale map[uint]*businesstime.Calendar
+	fallback  *businesstime.Calendar
+}
+
+// NewBusinessCalendarService crée une nouvelle instance de BusinessCalendarService
+func NewBusinessCalendarService(
+	calendarRepo repositories.BusinessCalendarRepository,
+	filialeRepo repositories.FilialeRepository,
+) BusinessCalendarService {
+	return &businessCalendarService{
+		calendarRepo: calendarRepo,
+		filialeRepo:  filialeRepo,
+	}
+}
+
+// GetAll récupère tous les calendriers ouvrés
+func (s *businessCalendarService) GetAll() ([]dto.BusinessCalendarDTO, error) {
+	calendars, err := s.calendarRepo.FindAll()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des calendriers ouvrés")
+	}
+	calendarDTOs := make([]dto.BusinessCalendarDTO, 0, len(calendars))
+	for i := range calendars {
+		calendarDTOs = append(calendarDTOs, businessCalendarToDTO(&calendars[i]))
+	}
+	return calendarDTOs, nil
+}
+
+// GetByID récupère un calendrier ouvré par son ID
+func (s *businessCalendarService) GetByID(id uint) (*dto.BusinessCalendarDTO, error) {
+	calendar, err := s.calendarRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrCalendarNotFound
+	}
+	calendarDTO := businessCalendarToDTO(calendar)
+	return &calendarDTO, nil
+}
+
+// Create crée un calendrier ouvré ; une filiale (ou le calendrier par défaut) n'a qu'un seul calendrier
+func (s *businessCalendarService) Create(req dto.CreateBusinessCalendarRequest, createdByID uint) (*dto.BusinessCalendarDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("le nom du calendrier est obligatoire")
+	}
+	if req.FilialeID != nil {
+		if _, err := s.filialeRepo.FindByID(*req.FilialeID); err != nil {
+			return nil, utils.ErrFilialeNotFound
+		}
+	}
+	if err := s.checkFiliale(req.FilialeID, 0); err != nil {
+		return nil, err
+	}
+	if !timezone.Valid(req.Timezone) {
+		return nil, errors.New("fuseau horaire invalide")
+	}
+	workingHours, err := encodeWorkingHours(req.WorkingHours)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &models.BusinessCalendar{
+		Name:         name,
+		FilialeID:    req.FilialeID,
+		Timezone:     req.Timezone,
+		WorkingHours: workingHours,
+		IsActive:     req.IsActive == nil || *req.IsActive,
+		CreatedByID:  &createdByID,
+	}
+	for _, holidayReq := range req.Holidays {
+		holiday, err := newBusinessHoliday(holidayReq)
+		if err != nil {
+			return nil, err
+		}
+		calendar.Holidays = append(calendar.Holidays, *holiday)
+	}
+
+	if err := s.calendarRepo.Create(calendar); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création du calendrier ouvré")
+	}
+	if !calendar.IsActive {
+		// La valeur par défaut de la colonne s'applique à false à la création
+		if err := s.calendarRepo.Update(calendar); err != nil {
+			log.Printf("Erreur lors de la désactivation du calendrier ouvré %d: %v", calendar.ID, err)
+		}
+	}
+	invalidateBusinessCalendars()
+	return s.GetByID(calendar.ID)
+}
+
+// Update met à jour un calendrier ouvré (les créneaux fournis remplacent les existants)
+func (s *businessCalendarService) Update(id uint, req dto.UpdateBusinessCalendarRequest) (*dto.BusinessCalendarDTO, error) {
+	calendar, err := s.calendarRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrCalendarNotFound
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("le nom du calendrier est obligatoire")
+		}
+		calendar.Name = name
+	}
+	if req.Timezone != nil {
+		if !timezone.Valid(*req.Timezone) {
+			return nil, errors.New("fuseau horaire invalide")
+		}
+		calendar.Timezone = *req.Timezone
+	}
+	if req.WorkingHours != nil {
+		if calendar.WorkingHours, err = encodeWorkingHours(*req.WorkingHours); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		calendar.IsActive = *req.IsActive
+	}
+
+	if err := s.calendarRepo.Update(calendar); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour du calendrier ouvré")
+	}
+	invalidateBusinessCalendars()
+	return s.GetByID(id)
+}
+
+// Delete supprime un calendrier ouvré et ses jours fériés
+func (s *businessCalendarService) Delete(id uint) error {
+	if _, err := s.calendarRepo.FindByID(id); err != nil {
+		return utils.ErrCalendarNotFound
+	}
+	if err := s.calendarRepo.Delete(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression du calendrier ouvré")
+	}
+	invalidateBusinessCalendars()
+	return nil
+}
+
+// AddHoliday ajoute un jour férié à un calendrier
+func (s *businessCalendarService) AddHoliday(calendarID uint, req dto.CreateBusinessHolidayRequest) (*dto.BusinessCalendarDTO, error) {
+	if _, err := s.calendarRepo.FindByID(calendarID); err != nil {
+		return nil, utils.ErrCalendarNotFound
+	}
+	holiday, err := newBusinessHoliday(req)
+	if err != nil {
+		return nil, err
+	}
+	holiday.CalendarID = calendarID
+	if err := s.calendarRepo.CreateHoliday(holiday); err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'ajout du jour férié")
+	}
+	invalidateBusinessCalendars()
+	return s.GetByID(calendarID)
+}
+
+// DeleteHoliday supprime un jour férié d'un calendrier
+func (s *businessCalendarService) DeleteHoliday(calendarID, holidayID uint) error {
+	holiday, err := s.calendarRepo.FindHolidayByID(holidayID)
+	if err != nil || holiday.CalendarID != calendarID {
+		return utils.ErrHolidayNotFound
+	}
+	if err := s.calendarRepo.DeleteHoliday(holidayID); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression du jour férié")
+	}
+	invalidateBusinessCalendars()
+	return nil
+}
+
+// CalendarFor retourne le calendrier actif de la filiale, sinon le calendrier par défaut
+// Nil si aucun calendrier ne s'applique : les échéances se calculent alors en temps réel
+func (s *businessCalendarService) CalendarFor(filialeID *uint) *businesstime.Calendar {
+	set, err := cache.GetOrLoad(cache.Shared, businessCalendarsCacheKey, 0, s.loadCalendars)
+	if err != nil {
+		log.Printf("Erreur lors du chargement des calendriers ouvrés: %v", err)
+		return nil
+	}
+	if filialeID != nil {
+		if calendar, ok := set.byFiliale[*filialeID]; ok {
+			return calendar
+		}
+	}
+	return set.fallback
+}
+
+// loadCalendars construit les calendriers actifs (fuseau du calendrier, sinon de sa filiale, sinon de l'application)
+func (s *businessCalendarService) loadCalendars() (*businessCalendarSet, error) {
+	calendars, err := s.calendarRepo.FindActive()
+	if err != nil {
+		return nil, err
+	}
+	set := &businessCalendarSet{byFiliale: make(map[uint]*businesstime.Calendar, len(calendars))}
+	for i := range calendars {
+		calendar := &calendars[i]
+		filialeTimezone := ""
+		if calendar.Filiale != nil {
+			filialeTimezone = calendar.Filiale.Timezone
+		}
+		built := buildBusinessCalendar(calendar, timezone.Resolve(calendar.Timezone, filialeTimezone))
+		if calendar.FilialeID == nil {
+			set.fallback = built
+		} else {
+			set.byFiliale[*calendar.FilialeID] = built
+		}
+	}
+	return set, nil
+}
+
+// checkFiliale vérifie que la filiale (ou le défaut si nil) n'a pas déjà un calendrier
+func (s *businessCalendarService) checkFiliale(filialeID *uint, excludeID uint) error {
+	existing, err := s.calendarRepo.FindByFilialeID(filialeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return utils.NewInternalError("erreur lors de la vérification du calendrier de la filiale")
+	}
+	if existing.ID != excludeID {
+		return utils.ErrCalendarConflict
+	}
+	return nil
+}
+
+// invalidateBusinessCalendars vide le cache des calendriers actifs
+func invalidateBusinessCalendars() {
+	cache.Shared.Delete(businessCalendarsCacheKey)
+}
+
+// encodeWorkingHours valide les créneaux (jour 0-6, HH:MM, début < fin, sans chevauchement) et les sérialise
+func encodeWorkingHours(workingHours []dto.WorkingHoursDTO) ([]byte, error) {
+	intervals, err := parseWorkingHours(workingHours)
+	if err != nil {
+		return nil, err
+	}
+	normalized := make([]dto.WorkingHoursDTO, 0, len(workingHours))
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		for _, interval := range sortedIntervals(intervals[weekday]) {
+			normalized = append(normalized, dto.WorkingHoursDTO{
+				Weekday: int(weekday),
+				Start:   businesstime.FormatClock(interval.Start),
+				End:     businesstime.FormatClock(interval.End),
+			})
+		}
+	}
+	return json.Marshal(normalized)
+}
+
+// parseWorkingHours convertit les créneaux en intervalles par jour de la semaine
+func parseWorkingHours(workingHours []dto.WorkingHoursDTO) (map[time.Weekday][]businesstime.Interval, error) {
+	intervals := make(map[time.Weekday][]businesstime.Interval)
+	for _, slot := range workingHours {
+		if slot.Weekday < 0 || slot.Weekday > 6 {
+			return nil, fmt.Errorf("jour de la semaine invalide: %d (0 = dimanche, 6 = samedi)", slot.Weekday)
+		}
+		start, err := businesstime.ParseClock(slot.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := businesstime.ParseClock(slot.End)
+		if err != nil {
+			return nil, err
+		}
+		if start >= end {
+			return nil, fmt.Errorf("créneau invalide: %s-%s (le début doit précéder la fin)", slot.Start, slot.End)
+		}
+		weekday := time.Weekday(slot.Weekday)
+		for _, other := range intervals[weekday] {
+			if start < other.End && other.Start < end {
+				return nil, fmt.Errorf("créneaux qui se chevauchent le %s: %s-%s", weekdayLabels[weekday], slot.Start, slot.End)
+			}
+		}
+		intervals[weekday] = append(intervals[weekday], businesstime.Interval{Start: start, End: end})
+	}
+	return intervals, nil
+}
+
+// weekdayLabels noms des jours de la semaine dans les messages d'erreur
+var weekdayLabels = [...]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+
+// sortedIntervals trie les créneaux d'une journée par heure de début
+func sortedIntervals(intervals []businesstime.Interval) []businesstime.Interval {
+	sorted := slices.Clone(intervals)
+	slices.SortFunc(sorted, func(a, b businesstime.Interval) int { return a.Start - b.Start })
+	return sorted
+}
+
+// decodeWorkingHours décode les créneaux enregistrés d'un calendrier
+func decodeWorkingHours(calendar *models.BusinessCalendar) []dto.WorkingHoursDTO {
+	workingHours := []dto.WorkingHoursDTO{}
+	if len(calendar.WorkingHours) > 0 {
+		_ = json.Unmarshal(calendar.WorkingHours, &workingHours)
+	}
+	return workingHours
+}
+
+// newBusinessHoliday valide et construit un jour férié
+func newBusinessHoliday(req dto.CreateBusinessHolidayRequest) (*models.BusinessHoliday, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("le nom du jour férié est obligatoire")
+	}
+	date, err := timezone.ParseDate(strings.TrimSpace(req.Date))
+	if err != nil {
+		return nil, errors.New("date du jour férié invalide (format YYYY-MM-DD)")
+	}
+	return &models.BusinessHoliday{Date: date, Name: name, Recurring: req.Recurring}, nil
+}
+
+// buildBusinessCalendar construit le calendrier de calcul d'un calendrier enregistré
+func buildBusinessCalendar(calendar *models.BusinessCalendar, location *time.Location) *businesstime.Calendar {
+	intervals, err := parseWorkingHours(decodeWorkingHours(calendar))
+	if err != nil {
+		log.Printf("Calendrier ouvré %d ignoré: %v", calendar.ID, err)
+		return nil
+	}
+	holidays := make([]businesstime.Holiday, 0, len(calendar.Holidays))
+	for _, holiday := range calendar.Holidays {
+		holidays = append(holidays, businesstime.Holiday{Date: holiday.Date, Recurring: holiday.Recurring})
+	}
+	return businesstime.New(location, intervals, holidays)
+}
+
+// businessCalendarToDTO convertit un modèle BusinessCalendar en DTO
+func businessCalendarToDTO(calendar *models.BusinessCalendar) dto.BusinessCalendarDTO {
+	calendarDTO := dto.BusinessCalendarDTO{
+		ID:           calendar.ID,
+		Name:         calendar.Name,
+		FilialeID:    calendar.FilialeID,
+		Timezone:     calendar.Timezone,
+		WorkingHours: decodeWorkingHours(calendar),
+		Holidays:     make([]dto.BusinessHolidayDTO, 0, len(calendar.Holidays)),
+		IsActive:     calendar.IsActive,
+		CreatedAt:    calendar.CreatedAt,
+		UpdatedAt:    calendar.UpdatedAt,
+	}
+	if calendar.Filiale != nil && calendar.Filiale.ID != 0 {
+		calendarDTO.Filiale = &dto.FilialeDTO{ID: calendar.Filiale.ID, Code: calendar.Filiale.Code, Name: calendar.Filiale.Name}
+	}
+	for _, holiday := range calendar.Holidays {
+		calendarDTO.Holidays = append(calendarDTO.Holidays, dto.BusinessHolidayDTO{
+			ID:        holiday.ID,
+			Date:      holiday.Date.Format(timezone.DateLayout),
+			Name:      holiday.Name,
+			Recurring: holiday.Recurring,
+		})
+	}
+	return calendarDTO
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/ical"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// calendarFeedRefresh intervalle de rafraîchissement suggéré aux clients de calendrier
+const calendarFeedRefresh = time.Hour
+
+// calendarFeedHistory ancienneté maximale des changements et jalons publiés
+const calendarFeedHistory = 30 * 24 * time.Hour
+
+// calendarFeedMaxPerUser nombre maximal de flux par utilisateur
+const calendarFeedMaxPerUser = 10
+
+// CalendarFeedService interface pour les flux de calendrier ICS (échéances SLA, fenêtres de changement, jalons de projet)
+type CalendarFeedService interface {
+	Create(userID uint, req dto.CreateCalendarFeedRequest) (*dto.CalendarFeedDTO, error)
+	List(userID uint) ([]dto.CalendarFeedDTO, error)
+	Delete(id, userID uint) error
+	Render(token string) ([]byte, error)
+}
+
+// calendarFeedService implémente CalendarFeedService
+type calendarFeedService struct {
+	feedRepo       repositories.CalendarFeedRepository
+	userRepo       repositories.UserRepository
+	preferenceRepo repositories.UserPreferenceRepository
+}
+
+// NewCalendarFeedService crée une nouvelle instance de CalendarFeedService
+func NewCalendarFeedService(feedRepo repositories.CalendarFeedRepository, userRepo repositories.UserRepository, preferenceRepo repositories.UserPreferenceRepository) CalendarFeedService {
+	return &calendarFeedService{
+		feedRepo:       feedRepo,
+		userRepo:       userRepo,
+		preferenceRepo: preferenceRepo,
+	}
+}
+
+// Create crée un flux ; l'URL d'abonnement (contenant le token) n'est retournée qu'à cette occasion
+func (s *calendarFeedService) Create(userID uint, req dto.CreateCalendarFeedRequest) (*dto.CalendarFeedDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	scope := req.Scope
+	if scope == "" {
+		scope = models.CalendarFeedScopeUser
+	}
+	if scope == models.CalendarFeedScopeDepartment && user.DepartmentID == nil {
+		return nil, errors.New("aucun département associé à l'utilisateur")
+	}
+
+	feeds, err := s.feedRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des flux")
+	}
+	if len(feeds) >= calendarFeedMaxPerUser {
+		return nil, fmt.Errorf("nombre maximal de flux atteint (%d)", calendarFeedMaxPerUser)
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, errors.New("erreur lors de la génération du token")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "Kronos"
+		if scope == models.CalendarFeedScopeDepartment {
+			name = "Kronos - équipe"
+		}
+	}
+	feed := &models.CalendarFeed{
+		UserID:    userID,
+		Name:      name,
+		Scope:     scope,
+		TokenHash: utils.HashString(token),
+	}
+	if err := s.feedRepo.Create(feed); err != nil {
+		return nil, errors.New("erreur lors de la création du flux")
+	}
+
+	feedDTO := calendarFeedToDTO(feed)
+	feedDTO.URL = strings.TrimRight(config.AppConfig.AppURL, "/") + "/api/v1/calendar/feeds/" + token + ".ics"
+	return &feedDTO, nil
+}
+
+// List récupère les flux de l'utilisateur
+func (s *calendarFeedService) List(userID uint) ([]dto.CalendarFeedDTO, error) {
+	feeds, err := s.feedRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des flux")
+	}
+	feedDTOs := make([]dto.CalendarFeedDTO, len(feeds))
+	for i := range feeds {
+		feedDTOs[i] = calendarFeedToDTO(&feeds[i])
+	}
+	return feedDTOs, nil
+}
+
+// Delete révoque un flux de l'utilisateur
+func (s *calendarFeedService) Delete(id, userID uint) error {
+	feed, err := s.feedRepo.FindByID(id)
+	if err != nil || feed.UserID != userID {
+		return errors.New("flux introuvable")
+	}
+	if err := s.feedRepo.Delete(id); err != nil {
+		return errors.New("erreur lors de la suppression du flux")
+	}
+	return nil
+}
+
+// Render construit le calendrier d'un flux à partir des données courantes
+func (s *calendarFeedService) Render(token string) ([]byte, error) {
+	if token == "" {
+		return nil, errors.New("flux introuvable")
+	}
+	feed, err := s.feedRepo.FindByTokenHash(utils.HashString(token))
+	if err != nil || !feed.User.IsActive {
+		return nil, errors.New("flux introuvable")
+	}
+
+	userIDs := []uint{feed.UserID}
+	if feed.Scope == models.CalendarFeedScopeDepartment {
+		if feed.User.DepartmentID == nil {
+			return nil, errors.New("aucun département associé à l'utilisateur")
+		}
+		if userIDs, err = s.feedRepo.FindDepartmentUserIDs(*feed.User.DepartmentID); err != nil {
+			return nil, errors.New("erreur lors de la récupération de l'équipe")
+		}
+		if len(userIDs) == 0 {
+			userIDs = []uint{feed.UserID}
+		}
+	}
+
+	now := time.Now()
+	since := now.Add(-calendarFeedHistory)
+	calendar := &ical.Calendar{
+		Name:            feed.Name,
+		Description:     "Échéances SLA, fenêtres de changement et jalons de projet",
+		Timezone:        timezoneName(s.ownerLocation(&feed.User)),
+		RefreshInterval: calendarFeedRefresh,
+	}
+
+	slas, err := s.feedRepo.FindOpenTicketSLAs(userIDs)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des échéances SLA")
+	}
+	for _, sla := range slas {
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("ticket-sla-%d@kronos", sla.TicketID),
+			Summary:     fmt.Sprintf("Échéance SLA %s - %s", sla.Ticket.Code, sla.Ticket.Title),
+			Description: fmt.Sprintf("Statut SLA: %s\nPriorité: %s", sla.Status, sla.Ticket.Priority),
+			Categories:  []string{"SLA"},
+			Start:       sla.TargetTime,
+			Duration:    15 * time.Minute,
+			Updated:     sla.UpdatedAt,
+		})
+	}
+
+	changes, err := s.feedRepo.FindPlannedChanges(userIDs, since)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des changements")
+	}
+	for _, change := range changes {
+		event := ical.Event{
+			UID:         fmt.Sprintf("change-%d@kronos", change.ID),
+			Summary:     fmt.Sprintf("Changement %s - %s", change.Ticket.Code, change.Ticket.Title),
+			Description: fmt.Sprintf("Risque: %s", change.Risk),
+			Categories:  []string{"Changement"},
+			Start:       *change.PlannedStart,
+			Duration:    time.Hour,
+			Updated:     change.UpdatedAt,
+		}
+		if change.PlannedEnd != nil {
+			event.End = *change.PlannedEnd
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	phases, err := s.feedRepo.FindProjectPhases(userIDs, since)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des jalons")
+	}
+	for _, phase := range phases {
+		projectName := ""
+		if phase.Project != nil {
+			projectName = phase.Project.Name
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("project-phase-%d@kronos", phase.ID),
+			Summary:     fmt.Sprintf("Fin de phase %s - %s", phase.Name, projectName),
+			Description: phase.Description,
+			Categories:  []string{"Projet"},
+			Start:       *phase.EndDate,
+			AllDay:      true,
+			Updated:     phase.UpdatedAt,
+		})
+	}
+
+	projects, err := s.feedRepo.FindProjects(userIDs, since)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des jalons")
+	}
+	for _, project := range projects {
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:        fmt.Sprintf("project-%d@kronos", project.ID),
+			Summary:    fmt.Sprintf("Échéance du projet %s", project.Name),
+			Categories: []string{"Projet"},
+			Start:      *project.EndDate,
+			AllDay:     true,
+			Updated:    project.UpdatedAt,
+		})
+	}
+
+	_ = s.feedRepo.UpdateLastFetched(feed.ID, now)
+	return calendar.Bytes(), nil
+}
+
+// ownerLocation retourne le fuseau du propriétaire du flux (préférence, sinon filiale, sinon fuseau de l'application)
+func (s *calendarFeedService) ownerLocation(user *models.User) *time.Location {
+	preferredTimezone, filialeTimezone := "", ""
+	if preference, err := s.preferenceRepo.FindByUserIDCached(user.ID); err == nil && preference != nil {
+		preferredTimezone = preference.Timezone
+	}
+	if user.Filiale != nil {
+		filialeTimezone = user.Filiale.Timezone
+	}
+	return timezone.Resolve(preferredTimezone, filialeTimezone)
+}
+
+// timezoneName retourne le nom IANA d'un fuseau (vide pour le fuseau système, non nommé)
+func timezoneName(location *time.Location) string {
+	if location == time.Local {
+		return ""
+	}
+	return location.String()
+}
+
+// calendarFeedToDTO convertit un flux en DTO (sans URL)
+func calendarFeedToDTO(feed *models.CalendarFeed) dto.CalendarFeedDTO {
+	return dto.CalendarFeedDTO{
+		ID:            feed.ID,
+		Name:          feed.Name,
+		Scope:         feed.Scope,
+		LastFetchedAt: feed.LastFetchedAt,
+		CreatedAt:     feed.CreatedAt,
+	}
+}
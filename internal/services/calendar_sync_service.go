@@ -0,0 +1,496 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/calendarsync"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// calendarSyncStateTTL durée de validité d'une demande de connexion (paramètre state OAuth)
+const calendarSyncStateTTL = 15 * time.Minute
+
+// calendarSyncHistory ancienneté maximale des éléments publiés dans les agendas
+const calendarSyncHistory = 7 * 24 * time.Hour
+
+// Types d'éléments publiés dans les agendas
+const (
+	calendarSourceProjectTask = "project_task"
+	calendarSourceChange      = "change"
+)
+
+// CalendarSyncService interface pour la synchronisation des tâches de projet et des maintenances planifiées
+// vers les agendas Google et Microsoft des utilisateurs
+type CalendarSyncService interface {
+	Providers() []dto.CalendarProviderDTO
+	List(userID uint) ([]dto.CalendarConnectionDTO, error)
+	Authorize(userID uint, provider string) (*dto.CalendarAuthorizationDTO, error)
+	Callback(ctx context.Context, provider, code, state string) (*dto.CalendarConnectionDTO, error)
+	Disconnect(ctx context.Context, id, userID uint) error
+	RequestSync(ctx context.Context, id, userID uint) error
+	Sync(ctx context.Context, connectionID uint) error
+	EnqueueAll(ctx context.Context) error // Planifie la synchronisation de tous les agendas connectés
+	ReturnURL() string
+}
+
+// calendarSyncService implémente CalendarSyncService
+type calendarSyncService struct {
+	cfg            config.CalendarSyncConfig
+	providers      map[string]calendarsync.Provider // Fournisseurs configurés
+	connectionRepo repositories.CalendarConnectionRepository
+	feedRepo       repositories.CalendarFeedRepository
+	jobQueue       *jobs.Queue
+}
+
+// NewCalendarSyncService crée une nouvelle instance de CalendarSyncService
+func NewCalendarSyncService(
+	cfg config.CalendarSyncConfig,
+	connectionRepo repositories.CalendarConnectionRepository,
+	feedRepo repositories.CalendarFeedRepository,
+	jobQueue *jobs.Queue,
+) CalendarSyncService {
+	providers := map[string]calendarsync.Provider{}
+	if cfg.GoogleClientID != "" {
+		providers[calendarsync.ProviderGoogle] = calendarsync.NewGoogle(cfg.GoogleClientID, cfg.GoogleClientSecret)
+	}
+	if cfg.MicrosoftClientID != "" {
+		providers[calendarsync.ProviderMicrosoft] = calendarsync.NewMicrosoft(cfg.MicrosoftTenant, cfg.MicrosoftClientID, cfg.MicrosoftClientSecret)
+	}
+	return &calendarSyncService{
+		cfg:            cfg,
+		providers:      providers,
+		connectionRepo: connectionRepo,
+		feedRepo:       feedRepo,
+		jobQueue:       jobQueue,
+	}
+}
+
+// Providers retourne les fournisseurs et leur disponibilité
+func (s *calendarSyncService) Providers() []dto.CalendarProviderDTO {
+	names := []string{calendarsync.ProviderGoogle, calendarsync.ProviderMicrosoft}
+	providers := make([]dto.CalendarProviderDTO, len(names))
+	for i, name := range names {
+		_, enabled := s.providers[name]
+		providers[i] = dto.CalendarProviderDTO{Provider: name, Enabled: enabled}
+	}
+	return providers
+}
+
+// ReturnURL retourne la page du frontend affichée après une connexion (vide si non configurée)
+func (s *calendarSyncService) ReturnURL() string {
+	return s.cfg.ReturnURL
+}
+
+// List récupère les agendas connectés de l'utilisateur
+func (s *calendarSyncService) List(userID uint) ([]dto.CalendarConnectionDTO, error) {
+	connections, err := s.connectionRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des agendas connectés")
+	}
+	connectionDTOs := make([]dto.CalendarConnectionDTO, len(connections))
+	for i := range connections {
+		connectionDTOs[i] = calendarConnectionToDTO(&connections[i])
+	}
+	return connectionDTOs, nil
+}
+
+// Authorize retourne l'URL de consentement du fournisseur pour l'utilisateur
+func (s *calendarSyncService) Authorize(userID uint, provider string) (*dto.CalendarAuthorizationDTO, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, errors.New("synchronisation non configurée pour ce fournisseur d'agenda")
+	}
+	state := signCalendarState(userID, provider, time.Now().Add(calendarSyncStateTTL))
+	return &dto.CalendarAuthorizationDTO{
+		Provider:         provider,
+		AuthorizationURL: p.AuthURL(state, calendarRedirectURI(provider)),
+	}, nil
+}
+
+// Callback termine la connexion après le consentement de l'utilisateur et planifie une première synchronisation
+func (s *calendarSyncService) Callback(ctx context.Context, provider, code, state string) (*dto.CalendarConnectionDTO, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, errors.New("synchronisation non configurée pour ce fournisseur d'agenda")
+	}
+	userID, ok := verifyCalendarState(state, provider)
+	if !ok {
+		return nil, errors.New("demande de connexion invalide ou expirée")
+	}
+	if code == "" {
+		return nil, errors.New("autorisation refusée")
+	}
+
+	token, err := p.Exchange(ctx, code, calendarRedirectURI(provider))
+	if err != nil {
+		return nil, fmt.Errorf("échec de l'autorisation: %v", err)
+	}
+	email, err := p.AccountEmail(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("échec de l'autorisation: %v", err)
+	}
+
+	connection, err := s.connectionRepo.FindByUserAndProvider(userID, provider)
+	if err != nil {
+		connection = &models.CalendarConnection{UserID: userID, Provider: provider}
+	} else if connection.AccountEmail != email {
+		// Autre compte : les événements publiés dans l'ancien agenda n'y sont plus gérés
+		if err := s.forgetLinks(connection.ID); err != nil {
+			return nil, errors.New("erreur lors de la mise à jour de l'agenda connecté")
+		}
+	}
+	connection.AccountEmail = email
+	connection.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		connection.RefreshToken = token.RefreshToken
+	}
+	connection.TokenExpiry = token.Expiry
+	connection.Status = models.CalendarConnectionActive
+	connection.LastError = ""
+
+	if connection.ID == 0 {
+		err = s.connectionRepo.Create(connection)
+	} else {
+		err = s.connectionRepo.Update(connection)
+	}
+	if err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement de l'agenda connecté")
+	}
+
+	_ = s.jobQueue.Enqueue(ctx, jobs.TypeCalendarSync, jobs.CalendarSyncPayload{ConnectionID: connection.ID})
+	connectionDTO := calendarConnectionToDTO(connection)
+	return &connectionDTO, nil
+}
+
+// Disconnect retire les événements publiés (si l'autorisation le permet encore) puis supprime la connexion
+func (s *calendarSyncService) Disconnect(ctx context.Context, id, userID uint) error {
+	connection, err := s.connectionRepo.FindByID(id)
+	if err != nil || connection.UserID != userID {
+		return errors.New("agenda connecté introuvable")
+	}
+
+	if p, ok := s.providers[connection.Provider]; ok && connection.Status != models.CalendarConnectionRevoked {
+		if accessToken, err := s.accessToken(ctx, p, connection); err == nil {
+			links, _ := s.connectionRepo.FindLinks(connection.ID)
+			for _, link := range links {
+				if err := p.DeleteEvent(ctx, accessToken, link.EventID); err != nil {
+					break
+				}
+			}
+		}
+	}
+
+	if err := s.connectionRepo.Delete(connection.ID); err != nil {
+		return errors.New("erreur lors de la suppression de l'agenda connecté")
+	}
+	return nil
+}
+
+// RequestSync planifie la synchronisation immédiate d'un agenda de l'utilisateur
+func (s *calendarSyncService) RequestSync(ctx context.Context, id, userID uint) error {
+	connection, err := s.connectionRepo.FindByID(id)
+	if err != nil || connection.UserID != userID {
+		return errors.New("agenda connecté introuvable")
+	}
+	if connection.Status == models.CalendarConnectionRevoked {
+		return errors.New("autorisation révoquée : reconnectez l'agenda")
+	}
+	if err := s.jobQueue.Enqueue(ctx, jobs.TypeCalendarSync, jobs.CalendarSyncPayload{ConnectionID: connection.ID}); err != nil {
+		return errors.New("erreur lors de la planification de la synchronisation")
+	}
+	return nil
+}
+
+// EnqueueAll planifie la synchronisation de chaque agenda connecté
+func (s *calendarSyncService) EnqueueAll(ctx context.Context) error {
+	if len(s.providers) == 0 {
+		return nil
+	}
+	ids, err := s.connectionRepo.FindSyncableIDs()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.jobQueue.Enqueue(ctx, jobs.TypeCalendarSync, jobs.CalendarSyncPayload{ConnectionID: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync publie dans l'agenda les tâches de projet assignées et les maintenances planifiées de l'utilisateur :
+// seuls les événements nouveaux ou modifiés sont envoyés, ceux des éléments sortis du périmètre sont supprimés
+func (s *calendarSyncService) Sync(ctx context.Context, connectionID uint) error {
+	connection, err := s.connectionRepo.FindByID(connectionID)
+	if err != nil || connection.Status == models.CalendarConnectionRevoked {
+		return nil // Agenda déconnecté entre-temps
+	}
+	p, ok := s.providers[connection.Provider]
+	if !ok {
+		return nil // Fournisseur désactivé
+	}
+
+	accessToken, err := s.accessToken(ctx, p, connection)
+	if err != nil {
+		return s.recordSync(connection, err)
+	}
+	desired, err := s.desiredEvents(connection.UserID)
+	if err != nil {
+		return err
+	}
+	links, err := s.connectionRepo.FindLinks(connection.ID)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]*models.CalendarEventLink, len(links))
+	for i := range links {
+		existing[calendarSourceKey(links[i].SourceType, links[i].SourceID)] = &links[i]
+	}
+
+	var syncErr error
+	for key, item := range desired {
+		if syncErr = s.pushEvent(ctx, p, accessToken, connection.ID, existing[key], item); syncErr != nil {
+			break
+		}
+		delete(existing, key)
+	}
+	if syncErr == nil {
+		for _, link := range existing {
+			if syncErr = p.DeleteEvent(ctx, accessToken, link.EventID); syncErr != nil {
+				break
+			}
+			if syncErr = s.connectionRepo.DeleteLink(link.ID); syncErr != nil {
+				break
+			}
+		}
+	}
+	return s.recordSync(connection, syncErr)
+}
+
+// calendarSyncItem événement attendu dans l'agenda pour un élément Kronos
+type calendarSyncItem struct {
+	sourceType string
+	sourceID   uint
+	event      calendarsync.Event
+}
+
+// desiredEvents construit les événements attendus pour l'utilisateur, indexés par élément source
+func (s *calendarSyncService) desiredEvents(userID uint) (map[string]calendarSyncItem, error) {
+	since := time.Now().Add(-calendarSyncHistory)
+	items := map[string]calendarSyncItem{}
+
+	tasks, err := s.connectionRepo.FindAssignedProjectTasks(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		summary := fmt.Sprintf("%s - %s", task.Code, task.Title)
+		if task.Project != nil {
+			summary = fmt.Sprintf("%s (%s)", summary, task.Project.Name)
+		}
+		items[calendarSourceKey(calendarSourceProjectTask, task.ID)] = calendarSyncItem{
+			sourceType: calendarSourceProjectTask,
+			sourceID:   task.ID,
+			event: calendarsync.Event{
+				Summary:     "Échéance tâche " + summary,
+				Description: task.Description,
+				Start:       *task.DueDate,
+				AllDay:      true,
+			},
+		}
+	}
+
+	changes, err := s.feedRepo.FindPlannedChanges([]uint{userID}, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		end := change.PlannedStart.Add(time.Hour)
+		if change.PlannedEnd != nil {
+			end = *change.PlannedEnd
+		}
+		items[calendarSourceKey(calendarSourceChange, change.ID)] = calendarSyncItem{
+			sourceType: calendarSourceChange,
+			sourceID:   change.ID,
+			event: calendarsync.Event{
+				Summary:     fmt.Sprintf("Maintenance %s - %s", change.Ticket.Code, change.Ticket.Title),
+				Description: fmt.Sprintf("Risque: %s\n%s", change.Risk, change.RiskDescription),
+				Start:       *change.PlannedStart,
+				End:         end,
+			},
+		}
+	}
+	return items, nil
+}
+
+// pushEvent crée ou met à jour l'événement d'un élément si son contenu a changé
+func (s *calendarSyncService) pushEvent(ctx context.Context, p calendarsync.Provider, accessToken string, connectionID uint, link *models.CalendarEventLink, item calendarSyncItem) error {
+	e := item.event
+	hash := utils.HashString(strings.Join([]string{e.Summary, e.Description, e.Start.UTC().Format(time.RFC3339), e.End.UTC().Format(time.RFC3339), strconv.FormatBool(e.AllDay)}, "\x00"))
+	if link != nil && link.ContentHash == hash {
+		return nil
+	}
+
+	if link != nil {
+		err := p.UpdateEvent(ctx, accessToken, link.EventID, e)
+		if err == nil {
+			link.ContentHash = hash
+			return s.connectionRepo.SaveLink(link)
+		}
+		if !errors.Is(err, calendarsync.ErrNotFound) {
+			return err
+		}
+		// Événement supprimé dans l'agenda : il est recréé
+	} else {
+		link = &models.CalendarEventLink{ConnectionID: connectionID, SourceType: item.sourceType, SourceID: item.sourceID}
+	}
+
+	eventID, err := p.CreateEvent(ctx, accessToken, e)
+	if err != nil {
+		return err
+	}
+	link.EventID = eventID
+	link.ContentHash = hash
+	return s.connectionRepo.SaveLink(link)
+}
+
+// accessToken retourne un jeton d'accès valide, renouvelé si nécessaire
+func (s *calendarSyncService) accessToken(ctx context.Context, p calendarsync.Provider, connection *models.CalendarConnection) (string, error) {
+	if time.Now().Add(time.Minute).Before(connection.TokenExpiry) {
+		return connection.AccessToken, nil
+	}
+	if connection.RefreshToken == "" {
+		return "", calendarsync.ErrUnauthorized
+	}
+	token, err := p.Refresh(ctx, connection.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	connection.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		connection.RefreshToken = token.RefreshToken
+	}
+	connection.TokenExpiry = token.Expiry
+	if err := s.connectionRepo.Update(connection); err != nil {
+		return "", err
+	}
+	return connection.AccessToken, nil
+}
+
+// recordSync enregistre le résultat d'une synchronisation ; une autorisation révoquée n'est pas réessayée
+func (s *calendarSyncService) recordSync(connection *models.CalendarConnection, syncErr error) error {
+	now := time.Now()
+	switch {
+	case syncErr == nil:
+		connection.Status = models.CalendarConnectionActive
+		connection.LastError = ""
+		connection.LastSyncedAt = &now
+	case errors.Is(syncErr, calendarsync.ErrUnauthorized):
+		connection.Status = models.CalendarConnectionRevoked
+		connection.LastError = syncErr.Error()
+	default:
+		connection.Status = models.CalendarConnectionError
+		connection.LastError = truncateRunes(syncErr.Error(), 1000)
+	}
+	if err := s.connectionRepo.Update(connection); err != nil {
+		return err
+	}
+	if errors.Is(syncErr, calendarsync.ErrUnauthorized) {
+		return nil
+	}
+	return syncErr
+}
+
+// forgetLinks oublie les événements publiés dans un agenda sans les supprimer chez le fournisseur
+func (s *calendarSyncService) forgetLinks(connectionID uint) error {
+	links, err := s.connectionRepo.FindLinks(connectionID)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		if err := s.connectionRepo.DeleteLink(link.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// calendarSourceKey clé d'un élément publié
+func calendarSourceKey(sourceType string, sourceID uint) string {
+	return sourceType + ":" + strconv.FormatUint(uint64(sourceID), 10)
+}
+
+// calendarRedirectURI URL de retour OAuth déclarée auprès du fournisseur
+func calendarRedirectURI(provider string) string {
+	return strings.TrimRight(config.AppConfig.AppURL, "/") + "/api/v1/calendar/oauth/" + provider + "/callback"
+}
+
+// signCalendarState construit le paramètre state OAuth (utilisateur, fournisseur et expiration signés par HMAC)
+func signCalendarState(userID uint, provider string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d:%s:%d", userID, provider, expiresAt.Unix())
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyCalendarState vérifie le paramètre state OAuth et retourne l'utilisateur à l'origine de la demande
+func verifyCalendarState(state, provider string) (uint, bool) {
+	encoded, signature, ok := strings.Cut(state, ".")
+	if !ok {
+		return 0, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return 0, false
+	}
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return 0, false
+	}
+
+	parts := strings.Split(string(payload), ":")
+	if len(parts) != 3 || parts[1] != provider {
+		return 0, false
+	}
+	userID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+// calendarConnectionToDTO convertit une connexion en DTO
+func calendarConnectionToDTO(connection *models.CalendarConnection) dto.CalendarConnectionDTO {
+	return dto.CalendarConnectionDTO{
+		ID:           connection.ID,
+		Provider:     connection.Provider,
+		AccountEmail: connection.AccountEmail,
+		Status:       connection.Status,
+		LastError:    connection.LastError,
+		LastSyncedAt: connection.LastSyncedAt,
+		CreatedAt:    connection.CreatedAt,
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+)
+
+const (
+	capacityDefaultWeeks = 4
+	capacityMaxWeeks     = 26
+	// capacityOverAllocationThreshold charge (% de la capacité) au-delà de laquelle un utilisateur est signalé en surcharge
+	capacityOverAllocationThreshold = 100.0
+)
+
+// CapacityService interface pour le plan de charge des ressources
+type CapacityService interface {
+	// GetCapacity calcule, par utilisateur et par semaine ISO, la charge allouée (temps déclaré et reste à faire
+	// des tâches de projet et des tickets) rapportée à la capacité ; weeks <= 0 retient 4 semaines
+	GetCapacity(scope interface{}, filter repositories.CapacityFilter, from time.Time, weeks int) (*dto.CapacityReportDTO, error)
+}
+
+// capacityService implémente CapacityService
+type capacityService struct {
+	capacityRepo repositories.CapacityRepository
+}
+
+// NewCapacityService crée une nouvelle instance de CapacityService
+func NewCapacityService(capacityRepo repositories.CapacityRepository) CapacityService {
+	return &capacityService{
+		capacityRepo: capacityRepo,
+	}
+}
+
+// capacityWeek cumul d'une semaine pour un utilisateur
+type capacityWeek struct {
+	task     float64
+	ticket   float64
+	declared int
+}
+
+// isWorkingDay indique si le jour est ouvré (lundi à vendredi)
+func isWorkingDay(d time.Time) bool {
+	return d.Weekday() != time.Saturday && d.Weekday() != time.Sunday
+}
+
+// mondayOf retourne le lundi de la semaine du jour
+func mondayOf(d time.Time) time.Time {
+	offset := (int(d.Weekday()) + 6) % 7
+	return time.Date(d.Year(), d.Month(), d.Day()-offset, 0, 0, 0, 0, d.Location())
+}
+
+// capacityFullName nom affiché d'un utilisateur
+func capacityFullName(u *models.User) string {
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if name == "" {
+		return u.Username
+	}
+	return name
+}
+
+// GetCapacity calcule le plan de charge. Les semaines passées ne comptent que le temps déclaré ; à partir d'aujourd'hui,
+// le reste à faire des tâches est réparti sur les jours ouvrés jusqu'à leur échéance (en retard : semaine en cours)
+// et celui des tickets ouverts est affecté à la semaine en cours.
+func (s *capacityService) GetCapacity(scopeParam interface{}, filter repositories.CapacityFilter, from time.Time, weeks int) (*dto.CapacityReportDTO, error) {
+	if weeks <= 0 {
+		weeks = capacityDefaultWeeks
+	}
+	if weeks > capacityMaxWeeks {
+		return nil, fmt.Errorf("la période ne peut pas dépasser %d semaines", capacityMaxWeeks)
+	}
+	today := timezone.Today(timezone.Default())
+	start := mondayOf(from)
+	end := start.AddDate(0, 0, 7*weeks-1)
+
+	users, err := s.capacityRepo.FindUsers(scopeParam, filter)
+	if err != nil {
+		log.Printf("[GetCapacity] users: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du plan de charge")
+	}
+	userIDs := make([]uint, len(users))
+	for i := range users {
+		userIDs[i] = users[i].ID
+	}
+	taskLoads, err := s.capacityRepo.FindOpenTaskLoads(userIDs)
+	if err != nil {
+		log.Printf("[GetCapacity] task loads: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du plan de charge")
+	}
+	ticketLoads, err := s.capacityRepo.FindOpenTicketLoads(userIDs)
+	if err != nil {
+		log.Printf("[GetCapacity] ticket loads: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du plan de charge")
+	}
+	declared, err := s.capacityRepo.FindDeclaredTime(userIDs, start, end)
+	if err != nil {
+		log.Printf("[GetCapacity] declared time: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du plan de charge")
+	}
+
+	// Cumuls par utilisateur et par index de semaine (hors période : ignorés)
+	buckets := make(map[uint][]capacityWeek, len(users))
+	unscheduled := make(map[uint]float64)
+	for _, id := range userIDs {
+		buckets[id] = make([]capacityWeek, weeks)
+	}
+	weekIndex := func(d time.Time) int {
+		if d.Before(start) || d.After(end) {
+			return -1
+		}
+		return calendarDaysBetween(start, d) / 7
+	}
+	currentWeek := weekIndex(today)
+
+	for _, load := range taskLoads {
+		share := float64(load.Remaining) / float64(max(load.AssigneeCount, 1))
+		if load.DueDate == nil {
+			unscheduled[load.UserID] += share
+			continue
+		}
+		due := time.Date(load.DueDate.Year(), load.DueDate.Month(), load.DueDate.Day(), 0, 0, 0, 0, today.Location())
+		if due.Before(today) {
+			if currentWeek >= 0 {
+				buckets[load.UserID][currentWeek].task += share
+			}
+			continue
+		}
+		workingDays := workingMinutesBetween(today, due) / (8 * 60)
+		if workingDays == 0 {
+			if i := weekIndex(due); i >= 0 {
+				buckets[load.UserID][i].task += share
+			}
+			continue
+		}
+		perDay := share / float64(workingDays)
+		for d := today; !d.After(due) && !d.After(end); d = d.AddDate(0, 0, 1) {
+			if i := weekIndex(d); i >= 0 && isWorkingDay(d) {
+				buckets[load.UserID][i].task += perDay
+			}
+		}
+	}
+	if currentWeek >= 0 {
+		for _, load := range ticketLoads {
+			buckets[load.UserID][currentWeek].ticket += float64(load.Remaining) / float64(max(load.AssigneeCount, 1))
+		}
+	}
+	for _, entry := range declared {
+		date := time.Date(entry.Date.Year(), entry.Date.Month(), entry.Date.Day(), 0, 0, 0, 0, today.Location())
+		if i := weekIndex(date); i >= 0 {
+			buckets[entry.UserID][i].declared += entry.Minutes
+		}
+	}
+
+	report := &dto.CapacityReportDTO{
+		From:      start,
+		To:        end,
+		Weeks:     make([]string, weeks),
+		Threshold: capacityOverAllocationThreshold,
+		Users:     make([]dto.CapacityUserDTO, 0, len(users)),
+		Warnings:  []dto.CapacityWarningDTO{},
+	}
+	for w := 0; w < weeks; w++ {
+		year, week := start.AddDate(0, 0, 7*w).ISOWeek()
+		report.Weeks[w] = fmt.Sprintf("%d-W%02d", year, week)
+	}
+	for i := range users {
+		u := &users[i]
+		userDTO := dto.CapacityUserDTO{
+			UserID:             u.ID,
+			Username:           u.Username,
+			FullName:           capacityFullName(u),
+			DepartmentID:       u.DepartmentID,
+			FilialeID:          u.FilialeID,
+			UnscheduledMinutes: int(math.Round(unscheduled[u.ID])),
+			Weeks:              make([]dto.CapacityWeekDTO, weeks),
+		}
+		for w, bucket := range buckets[u.ID] {
+			weekStart := start.AddDate(0, 0, 7*w)
+			weekDTO := dto.CapacityWeekDTO{
+				Week:            report.Weeks[w],
+				StartDate:       weekStart,
+				CapacityMinutes: workingMinutesBetween(weekStart, weekStart.AddDate(0, 0, 6)),
+				TaskMinutes:     int(math.Round(bucket.task)),
+				TicketMinutes:   int(math.Round(bucket.ticket)),
+				DeclaredMinutes: bucket.declared,
+			}
+			weekDTO.AllocatedMinutes = weekDTO.DeclaredMinutes + weekDTO.TaskMinutes + weekDTO.TicketMinutes
+			if weekDTO.CapacityMinutes > 0 {
+				weekDTO.AllocationPercent = math.Round(float64(weekDTO.AllocatedMinutes)*1000/float64(weekDTO.CapacityMinutes)) / 10
+			}
+			if weekDTO.AllocationPercent > capacityOverAllocationThreshold {
+				weekDTO.OverAllocated = true
+				report.Warnings = append(report.Warnings, dto.CapacityWarningDTO{
+					UserID:            u.ID,
+					FullName:          userDTO.FullName,
+					Week:              weekDTO.Week,
+					AllocationPercent: weekDTO.AllocationPercent,
+					OverMinutes:       weekDTO.AllocatedMinutes - weekDTO.CapacityMinutes,
+				})
+			}
+			userDTO.Weeks[w] = weekDTO
+		}
+		report.Users = append(report.Users, userDTO)
+	}
+	return report, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // ChangeService interface pour les opérations sur les changements
@@ -49,7 +50,7 @@ func (s *changeService) Create(req dto.CreateChangeRequest, createdByID uint) (*
 	// Vérifier que le ticket existe et est de catégorie "changement"
 	ticket, err := s.ticketRepo.FindByID(req.TicketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	if ticket.Category != "changement" {
@@ -62,11 +63,17 @@ func (s *changeService) Create(req dto.CreateChangeRequest, createdByID uint) (*
 		return nil, errors.New("un changement existe déjà pour ce ticket")
 	}
 
+	if err := validateChangeWindow(req.PlannedStart, req.PlannedEnd); err != nil {
+		return nil, err
+	}
+
 	// Créer le changement
 	change := &models.Change{
 		TicketID:        req.TicketID,
 		Risk:            req.Risk,
 		RiskDescription: req.RiskDescription,
+		PlannedStart:    req.PlannedStart,
+		PlannedEnd:      req.PlannedEnd,
 	}
 
 	if err := s.changeRepo.Create(change); err != nil {
@@ -88,7 +95,7 @@ func (s *changeService) Create(req dto.CreateChangeRequest, createdByID uint) (*
 func (s *changeService) GetByID(id uint) (*dto.ChangeDTO, error) {
 	change, err := s.changeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("changement introuvable")
+		return nil, utils.ErrChangeNotFound
 	}
 
 	changeDTO := s.changeToDTO(change)
@@ -99,7 +106,7 @@ func (s *changeService) GetByID(id uint) (*dto.ChangeDTO, error) {
 func (s *changeService) GetByTicketID(ticketID uint) (*dto.ChangeDTO, error) {
 	change, err := s.changeRepo.FindByTicketID(ticketID)
 	if err != nil {
-		return nil, errors.New("changement introuvable")
+		return nil, utils.ErrChangeNotFound
 	}
 
 	changeDTO := s.changeToDTO(change)
@@ -158,7 +165,7 @@ func (s *changeService) GetByResponsible(scopeParam interface{}, responsibleID u
 func (s *changeService) Update(id uint, req dto.UpdateChangeRequest, updatedByID uint) (*dto.ChangeDTO, error) {
 	change, err := s.changeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("changement introuvable")
+		return nil, utils.ErrChangeNotFound
 	}
 
 	// Mettre à jour les champs fournis
@@ -168,6 +175,15 @@ func (s *changeService) Update(id uint, req dto.UpdateChangeRequest, updatedByID
 	if req.RiskDescription != "" {
 		change.RiskDescription = req.RiskDescription
 	}
+	if req.PlannedStart != nil {
+		change.PlannedStart = req.PlannedStart
+	}
+	if req.PlannedEnd != nil {
+		change.PlannedEnd = req.PlannedEnd
+	}
+	if err := validateChangeWindow(change.PlannedStart, change.PlannedEnd); err != nil {
+		return nil, err
+	}
 
 	if err := s.changeRepo.Update(change); err != nil {
 		return nil, errors.New("erreur lors de la mise à jour du changement")
@@ -187,7 +203,7 @@ func (s *changeService) Update(id uint, req dto.UpdateChangeRequest, updatedByID
 func (s *changeService) AssignResponsible(id uint, req dto.AssignResponsibleRequest, assignedByID uint) (*dto.ChangeDTO, error) {
 	change, err := s.changeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("changement introuvable")
+		return nil, utils.ErrChangeNotFound
 	}
 
 	// Vérifier que l'utilisateur responsable existe
@@ -216,7 +232,7 @@ func (s *changeService) AssignResponsible(id uint, req dto.AssignResponsibleRequ
 func (s *changeService) UpdateRisk(id uint, req dto.UpdateRiskRequest, updatedByID uint) (*dto.ChangeDTO, error) {
 	change, err := s.changeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("changement introuvable")
+		return nil, utils.ErrChangeNotFound
 	}
 
 	change.Risk = req.Risk
@@ -242,7 +258,7 @@ func (s *changeService) UpdateRisk(id uint, req dto.UpdateRiskRequest, updatedBy
 func (s *changeService) RecordResult(id uint, req dto.RecordChangeResultRequest, recordedByID uint) (*dto.ChangeDTO, error) {
 	change, err := s.changeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("changement introuvable")
+		return nil, utils.ErrChangeNotFound
 	}
 
 	now := time.Now()
@@ -268,7 +284,7 @@ func (s *changeService) RecordResult(id uint, req dto.RecordChangeResultRequest,
 func (s *changeService) Delete(id uint) error {
 	_, err := s.changeRepo.FindByID(id)
 	if err != nil {
-		return errors.New("changement introuvable")
+		return utils.ErrChangeNotFound
 	}
 
 	if err := s.changeRepo.Delete(id); err != nil {
@@ -278,6 +294,17 @@ func (s *changeService) Delete(id uint) error {
 	return nil
 }
 
+// validateChangeWindow vérifie la fenêtre planifiée d'un changement (fin seule interdite, fin après le début)
+func validateChangeWindow(start, end *time.Time) error {
+	if end != nil && start == nil {
+		return errors.New("le début de la fenêtre de changement est obligatoire avec sa fin")
+	}
+	if start != nil && end != nil && !end.After(*start) {
+		return errors.New("la fin de la fenêtre de changement doit être postérieure à son début")
+	}
+	return nil
+}
+
 // changeToDTO convertit un modèle Change en DTO
 func (s *changeService) changeToDTO(change *models.Change) dto.ChangeDTO {
 	changeDTO := dto.ChangeDTO{
@@ -288,6 +315,8 @@ func (s *changeService) changeToDTO(change *models.Change) dto.ChangeDTO {
 		Result:            change.Result,
 		ResultDescription: change.ResultDescription,
 		ResultDate:        change.ResultDate,
+		PlannedStart:      change.PlannedStart,
+		PlannedEnd:        change.PlannedEnd,
 		CreatedAt:         change.CreatedAt,
 		UpdatedAt:         change.UpdatedAt,
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // DailyDeclarationService interface pour les opérations sur les déclarations journalières
@@ -66,7 +67,7 @@ func (s *dailyDeclarationService) GetByID(id uint) (result *dto.DailyDeclaration
 func (s *dailyDeclarationService) GetByUserIDAndDate(userID uint, date time.Time) (*dto.DailyDeclarationDTO, error) {
 	declaration, err := s.declarationRepo.FindByUserIDAndDate(userID, date)
 	if err != nil {
-		return nil, errors.New("déclaration introuvable")
+		return nil, utils.ErrDeclarationNotFound
 	}
 
 	declarationDTO := s.declarationToDTO(declaration)
@@ -207,7 +208,7 @@ func (s *dailyDeclarationService) GetPendingValidation() ([]dto.DailyDeclaration
 func (s *dailyDeclarationService) Validate(id uint, validatedByID uint) (*dto.DailyDeclarationDTO, error) {
 	declaration, err := s.declarationRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("déclaration introuvable")
+		return nil, utils.ErrDeclarationNotFound
 	}
 
 	// Vérifier que le validateur existe
@@ -239,7 +240,7 @@ func (s *dailyDeclarationService) Validate(id uint, validatedByID uint) (*dto.Da
 func (s *dailyDeclarationService) Delete(id uint) error {
 	_, err := s.declarationRepo.FindByID(id)
 	if err != nil {
-		return errors.New("déclaration introuvable")
+		return utils.ErrDeclarationNotFound
 	}
 
 	if err := s.declarationRepo.Delete(id); err != nil {
@@ -1,13 +1,20 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/mcicare/itsm-backend/config"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // DelayService interface pour les opérations sur les retards
@@ -21,7 +28,10 @@ type DelayService interface {
 	Delete(id uint) error
 	CreateJustification(delayID uint, req dto.CreateDelayJustificationRequest, userID uint) (*dto.DelayJustificationDTO, error)
 	UpdateJustification(id uint, req dto.UpdateDelayJustificationRequest, userID uint) (*dto.DelayJustificationDTO, error)
-	ValidateJustification(id uint, req dto.ValidateDelayJustificationRequest, validatedByID uint) (*dto.DelayJustificationDTO, error)
+	// ValidateJustification enregistre la décision de l'approbateur attendu ; canOverride (validateur habilité) permet de
+	// décider hors circuit, le circuit d'approbation en cours étant alors annulé
+	ValidateJustification(id uint, req dto.ValidateDelayJustificationRequest, validatedByID uint, canOverride bool) (*dto.DelayJustificationDTO, error)
+	GetJustificationByID(id uint) (*dto.DelayJustificationDTO, error)
 	GetJustificationByDelayID(delayID uint) (*dto.DelayJustificationDTO, error)
 	DeleteJustification(delayID uint, userID uint) error
 	GetJustificationsByUserID(userID uint) ([]dto.DelayJustificationDTO, error)
@@ -29,7 +39,8 @@ type DelayService interface {
 	GetValidatedJustifications() ([]dto.DelayJustificationDTO, error)
 	GetRejectedJustifications() ([]dto.DelayJustificationDTO, error)
 	GetJustificationsHistory() ([]dto.DelayJustificationDTO, error)
-	RejectJustification(delayID uint, req dto.ValidateDelayJustificationRequest, rejectedByID uint) (*dto.DelayJustificationDTO, error)
+	RejectJustification(delayID uint, req dto.ValidateDelayJustificationRequest, rejectedByID uint, canOverride bool) (*dto.DelayJustificationDTO, error)
+	GetPendingMyApproval(userID uint) ([]dto.DelayJustificationDTO, error) // Justifications attendant la décision de l'utilisateur ou d'un délégant absent
 	GetStatusStats() (*dto.DelayStatusStatsDTO, error)
 }
 
@@ -51,6 +62,11 @@ type delayService struct {
 	delayJustificationRepo repositories.DelayJustificationRepository
 	userRepo               repositories.UserRepository
 	ticketRepo             repositories.TicketRepository
+	absenceRepo            repositories.AbsenceRepository
+	departmentRepo         repositories.DepartmentRepository
+	delegationRepo         repositories.AccessDelegationRepository
+	approvalService        ApprovalService
+	jobQueue               *jobs.Queue
 	syncMu                 sync.Mutex
 	lastSync               time.Time
 	syncing                bool
@@ -62,12 +78,22 @@ func NewDelayService(
 	delayJustificationRepo repositories.DelayJustificationRepository,
 	userRepo repositories.UserRepository,
 	ticketRepo repositories.TicketRepository,
+	absenceRepo repositories.AbsenceRepository,
+	departmentRepo repositories.DepartmentRepository,
+	delegationRepo repositories.AccessDelegationRepository,
+	approvalService ApprovalService,
+	jobQueue *jobs.Queue,
 ) DelayService {
 	return &delayService{
 		delayRepo:              delayRepo,
 		delayJustificationRepo: delayJustificationRepo,
 		userRepo:               userRepo,
 		ticketRepo:             ticketRepo,
+		absenceRepo:            absenceRepo,
+		departmentRepo:         departmentRepo,
+		delegationRepo:         delegationRepo,
+		approvalService:        approvalService,
+		jobQueue:               jobQueue,
 	}
 }
 
@@ -75,7 +101,7 @@ func NewDelayService(
 func (s *delayService) GetByID(id uint) (*dto.DelayDTO, error) {
 	delay, err := s.delayRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("retard introuvable")
+		return nil, utils.ErrDelayNotFound
 	}
 
 	delayDTO := s.delayToDTO(delay)
@@ -86,7 +112,7 @@ func (s *delayService) GetByID(id uint) (*dto.DelayDTO, error) {
 func (s *delayService) GetByTicketID(ticketID uint) (*dto.DelayDTO, error) {
 	delay, err := s.delayRepo.FindByTicketID(ticketID)
 	if err != nil {
-		return nil, errors.New("retard introuvable")
+		return nil, utils.ErrDelayNotFound
 	}
 
 	delayDTO := s.delayToDTO(delay)
@@ -146,6 +172,16 @@ func (s *delayService) triggerSyncDelaysFromTickets() {
 }
 
 func (s *delayService) syncDelaysFromTickets() {
+	// Aucun nouveau retard n'est imputé à un utilisateur en absence approuvée aujourd'hui :
+	// il sera détecté à son retour
+	absentToday := make(map[uint]bool)
+	today := time.Now()
+	if absences, err := s.absenceRepo.FindApproved(nil, today, today); err == nil {
+		for _, absence := range absences {
+			absentToday[absence.UserID] = true
+		}
+	}
+
 	page := 1
 	limit := 500
 	for {
@@ -180,6 +216,9 @@ func (s *delayService) syncDelaysFromTickets() {
 				ownerID = *ticket.AssignedToID
 			}
 			if err != nil || existing == nil {
+				if absentToday[ownerID] {
+					continue
+				}
 				tid := ticket.ID
 				delay := &models.Delay{
 					TicketID:        &tid,
@@ -249,7 +288,7 @@ func (s *delayService) GetUnjustified(scopeParam interface{}) ([]dto.DelayDTO, e
 func (s *delayService) Delete(id uint) error {
 	_, err := s.delayRepo.FindByID(id)
 	if err != nil {
-		return errors.New("retard introuvable")
+		return utils.ErrDelayNotFound
 	}
 
 	if err := s.delayRepo.Delete(id); err != nil {
@@ -264,7 +303,7 @@ func (s *delayService) CreateJustification(delayID uint, req dto.CreateDelayJust
 	// Vérifier que le retard existe
 	delay, err := s.delayRepo.FindByID(delayID)
 	if err != nil {
-		return nil, errors.New("retard introuvable")
+		return nil, utils.ErrDelayNotFound
 	}
 
 	// Vérifier que l'utilisateur est le technicien du retard
@@ -302,6 +341,12 @@ func (s *delayService) CreateJustification(delayID uint, req dto.CreateDelayJust
 		return nil, errors.New("erreur lors de la récupération de la justification créée")
 	}
 
+	// Circuit d'approbation configuré (les approbateurs sont notifiés à l'ouverture de leur étape) ;
+	// à défaut, escalade vers le responsable hiérarchique du technicien
+	if !s.startJustificationApproval(createdJustification) {
+		s.notifyManagerOfJustification(delay)
+	}
+
 	justificationDTO := s.justificationToDTO(createdJustification)
 	return &justificationDTO, nil
 }
@@ -339,8 +384,10 @@ func (s *delayService) UpdateJustification(id uint, req dto.UpdateDelayJustifica
 	return &justificationDTO, nil
 }
 
-// ValidateJustification valide ou rejette une justification
-func (s *delayService) ValidateJustification(id uint, req dto.ValidateDelayJustificationRequest, validatedByID uint) (*dto.DelayJustificationDTO, error) {
+// ValidateJustification valide ou rejette une justification : avec un circuit d'approbation, la décision est celle
+// de l'étape en cours (la justification est tranchée après la dernière étape ou au premier rejet) ; sans circuit,
+// elle revient au responsable hiérarchique du technicien ou à un validateur habilité
+func (s *delayService) ValidateJustification(id uint, req dto.ValidateDelayJustificationRequest, validatedByID uint, canOverride bool) (*dto.DelayJustificationDTO, error) {
 	justification, err := s.delayJustificationRepo.FindByID(id)
 	if err != nil {
 		return nil, errors.New("justification introuvable")
@@ -351,39 +398,104 @@ func (s *delayService) ValidateJustification(id uint, req dto.ValidateDelayJusti
 		return nil, errors.New("la justification a déjà été traitée")
 	}
 
+	approved := req.Validated != nil && *req.Validated
+	decided := false
+	if justification.ApprovalRequestID != nil {
+		if decided, err = s.decideJustificationApproval(justification, approved, validatedByID, req.Comment, canOverride); err != nil {
+			return nil, err
+		}
+	} else if !canOverride && (justification.User.ManagerID == nil || *justification.User.ManagerID != validatedByID) {
+		return nil, utils.ErrDelayDecisionForbidden
+	}
+	if !decided {
+		if err := applyDelayJustificationDecision(s.delayJustificationRepo, s.delayRepo, id, approved, validatedByID, req.Comment); err != nil {
+			return nil, err
+		}
+	}
+
+	// Récupérer la justification mise à jour
+	updatedJustification, err := s.delayJustificationRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de la justification mise à jour")
+	}
+
+	justificationDTO := s.justificationToDTO(updatedJustification)
+	return &justificationDTO, nil
+}
+
+// decideJustificationApproval transmet la décision au circuit d'approbation si l'utilisateur est un approbateur
+// de l'étape en cours (decided = true) ; un validateur habilité hors circuit l'annule pour décider directement
+func (s *delayService) decideJustificationApproval(justification *models.DelayJustification, approved bool, userID uint, comment string, canOverride bool) (bool, error) {
+	approval, err := s.approvalService.GetByID(*justification.ApprovalRequestID, userID, true)
+	if err != nil {
+		return false, err
+	}
+	if approval.Status == models.ApprovalStatusPending && slices.ContainsFunc(approval.Approvers, func(a dto.ApprovalApproverDTO) bool {
+		return a.UserID == userID && a.Status == models.ApproverStatusPending
+	}) {
+		if approved {
+			_, err = s.approvalService.Approve(approval.ID, userID, comment)
+		} else {
+			_, err = s.approvalService.Reject(approval.ID, userID, comment)
+		}
+		return true, err
+	}
+	if !canOverride {
+		return false, utils.ErrDelayDecisionForbidden
+	}
+	if approval.Status == models.ApprovalStatusPending {
+		if _, err := s.approvalService.Cancel(approval.ID, userID, true); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// applyDelayJustificationDecision tranche une justification en attente et met à jour le statut du retard
+func applyDelayJustificationDecision(justificationRepo repositories.DelayJustificationRepository, delayRepo repositories.DelayRepository, id uint, approved bool, decidedByID uint, comment string) error {
+	justification, err := justificationRepo.FindByID(id)
+	if err != nil {
+		return errors.New("justification introuvable")
+	}
+	if justification.Status != "pending" {
+		return errors.New("la justification a déjà été traitée")
+	}
+
 	now := time.Now()
-	justification.ValidatedByID = &validatedByID
+	justification.ValidatedByID = &decidedByID
 	justification.ValidatedAt = &now
-	justification.ValidationComment = req.Comment
-
-	if req.Validated != nil && *req.Validated {
+	justification.ValidationComment = comment
+	if approved {
 		justification.Status = "validated"
 	} else {
 		justification.Status = "rejected"
 	}
 
-	if err := s.delayJustificationRepo.Update(justification); err != nil {
-		return nil, errors.New("erreur lors de la validation de la justification")
+	if err := justificationRepo.Update(justification); err != nil {
+		return errors.New("erreur lors de la validation de la justification")
 	}
 
 	// Mettre à jour le statut du retard
-	delay, err := s.delayRepo.FindByID(justification.DelayID)
+	delay, err := delayRepo.FindByID(justification.DelayID)
 	if err == nil {
-		if req.Validated != nil && *req.Validated {
+		if approved {
 			delay.Status = "justified"
 		} else {
 			delay.Status = "unjustified"
 		}
-		s.delayRepo.Update(delay)
+		delayRepo.Update(delay)
 	}
+	return nil
+}
 
-	// Récupérer la justification mise à jour
-	updatedJustification, err := s.delayJustificationRepo.FindByID(id)
+// GetJustificationByID récupère une justification par son ID
+func (s *delayService) GetJustificationByID(id uint) (*dto.DelayJustificationDTO, error) {
+	justification, err := s.delayJustificationRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de la justification mise à jour")
+		return nil, errors.New("justification introuvable")
 	}
 
-	justificationDTO := s.justificationToDTO(updatedJustification)
+	justificationDTO := s.justificationToDTO(justification)
 	return &justificationDTO, nil
 }
 
@@ -418,6 +530,11 @@ func (s *delayService) DeleteJustification(delayID uint, userID uint) error {
 	if err := s.delayJustificationRepo.Delete(justification.ID); err != nil {
 		return errors.New("erreur lors de la suppression de la justification")
 	}
+	if justification.ApprovalRequestID != nil {
+		if _, err := s.approvalService.Cancel(*justification.ApprovalRequestID, userID, true); err != nil {
+			log.Printf("Erreur lors de l'annulation du circuit d'approbation %d de la justification %d: %v", *justification.ApprovalRequestID, justification.ID, err)
+		}
+	}
 
 	// Remettre le statut du retard à unjustified
 	delay, err := s.delayRepo.FindByID(delayID)
@@ -505,7 +622,7 @@ func (s *delayService) GetJustificationsHistory() ([]dto.DelayJustificationDTO,
 }
 
 // RejectJustification rejette une justification
-func (s *delayService) RejectJustification(delayID uint, req dto.ValidateDelayJustificationRequest, rejectedByID uint) (*dto.DelayJustificationDTO, error) {
+func (s *delayService) RejectJustification(delayID uint, req dto.ValidateDelayJustificationRequest, rejectedByID uint, canOverride bool) (*dto.DelayJustificationDTO, error) {
 	justification, err := s.delayJustificationRepo.FindByDelayID(delayID)
 	if err != nil {
 		return nil, errors.New("justification introuvable")
@@ -523,7 +640,197 @@ func (s *delayService) RejectJustification(delayID uint, req dto.ValidateDelayJu
 		Comment:   req.Comment,
 	}
 
-	return s.ValidateJustification(justification.ID, rejectReq, rejectedByID)
+	return s.ValidateJustification(justification.ID, rejectReq, rejectedByID, canOverride)
+}
+
+// startJustificationApproval soumet la justification au circuit d'approbation configuré ; retourne false si aucun
+// approbateur n'a pu être désigné (validation unique par le responsable ou un validateur habilité)
+func (s *delayService) startJustificationApproval(justification *models.DelayJustification) bool {
+	approvers := s.justificationApprovers(&justification.User)
+	if len(approvers) == 0 {
+		return false
+	}
+	approval, err := s.approvalService.Create(dto.CreateApprovalRequest{
+		EntityType:  models.ApprovalEntityDelayJustification,
+		EntityID:    justification.ID,
+		Description: truncateRunes(justification.Justification, 500),
+		Approvers:   approvers,
+	}, justification.UserID)
+	if err != nil {
+		log.Printf("Erreur lors de la création du circuit d'approbation de la justification %d: %v", justification.ID, err)
+		return false
+	}
+	justification.ApprovalRequestID = &approval.ID
+	if err := s.delayJustificationRepo.Update(justification); err != nil {
+		log.Printf("Erreur lors du rattachement du circuit d'approbation %d à la justification %d: %v", approval.ID, justification.ID, err)
+	}
+	return true
+}
+
+// justificationApprovers résout les étapes du circuit (DELAY_JUSTIFICATION_APPROVAL_CHAIN) pour le technicien :
+// un niveau sans titulaire, désactivé, déjà présent dans le circuit ou égal au technicien est ignoré ;
+// un approbateur absent aujourd'hui est remplacé par son suppléant ou par son délégataire pour la validation des temps
+func (s *delayService) justificationApprovers(user *models.User) []dto.ApproverRequest {
+	now := time.Now()
+	var approvers []dto.ApproverRequest
+	for _, level := range config.AppConfig.Delays.ApprovalChain {
+		var approverID *uint
+		switch level {
+		case config.DelayApprovalLevelManager:
+			approverID = user.ManagerID
+		case config.DelayApprovalLevelDepartmentHead:
+			if user.DepartmentID != nil {
+				if department, err := s.departmentRepo.FindByID(*user.DepartmentID); err == nil {
+					approverID = department.HeadID
+				}
+			}
+		}
+		if approverID == nil {
+			continue
+		}
+		approver, err := s.userRepo.FindByID(*approverID)
+		if err != nil || !approver.IsActive {
+			continue
+		}
+		if s.isAbsent(approver, now) {
+			if substitute := s.approverSubstitute(approver, now); substitute != nil {
+				approver = substitute
+			}
+		}
+		if approver.ID == user.ID || slices.ContainsFunc(approvers, func(a dto.ApproverRequest) bool { return a.UserID == approver.ID }) {
+			continue
+		}
+		approvers = append(approvers, dto.ApproverRequest{UserID: approver.ID, StepOrder: len(approvers) + 1})
+	}
+	return approvers
+}
+
+// isAbsent indique si l'utilisateur est absent à la date donnée (message d'absence ou absence approuvée)
+func (s *delayService) isAbsent(user *models.User, at time.Time) bool {
+	if user.IsOutOfOffice(at) {
+		return true
+	}
+	absences, err := s.absenceRepo.FindApproved([]uint{user.ID}, at, at)
+	return err == nil && len(absences) > 0
+}
+
+// approverSubstitute retourne le remplaçant actif d'un approbateur absent : son suppléant, sinon le délégataire
+// d'une délégation en cours couvrant la validation des temps
+func (s *delayService) approverSubstitute(approver *models.User, at time.Time) *models.User {
+	candidates := []uint{}
+	if approver.BackupUserID != nil {
+		candidates = append(candidates, *approver.BackupUserID)
+	}
+	if delegations, err := s.delegationRepo.FindByDelegator(approver.ID); err == nil {
+		for _, delegation := range delegations {
+			if delegation.IsActiveAt(at) && delegation.HasArea(models.DelegationAreaTimesheet) {
+				candidates = append(candidates, delegation.DelegateID)
+			}
+		}
+	}
+	for _, candidateID := range candidates {
+		if candidate, err := s.userRepo.FindByID(candidateID); err == nil && candidate.IsActive && !s.isAbsent(candidate, at) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// GetPendingMyApproval récupère les justifications en attente de la décision de l'utilisateur : étape en cours
+// d'un circuit d'approbation, validation unique des membres de son équipe, et décisions attendues d'un collègue
+// absent qui lui a délégué la validation des temps (on_behalf_of_id, à transmettre dans l'en-tête X-On-Behalf-Of)
+func (s *delayService) GetPendingMyApproval(userID uint) ([]dto.DelayJustificationDTO, error) {
+	approverIDs := []uint{userID}
+	delegations, err := s.delegationRepo.FindByDelegate(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des délégations")
+	}
+	now := time.Now()
+	for _, delegation := range delegations {
+		if delegation.IsActiveAt(now) && delegation.HasArea(models.DelegationAreaTimesheet) && !slices.Contains(approverIDs, delegation.DelegatorID) {
+			approverIDs = append(approverIDs, delegation.DelegatorID)
+		}
+	}
+
+	justificationDTOs := []dto.DelayJustificationDTO{}
+	seen := make(map[uint]bool)
+	add := func(justification *models.DelayJustification, approverID uint) {
+		if seen[justification.ID] || justification.Status != "pending" {
+			return
+		}
+		seen[justification.ID] = true
+		justificationDTO := s.justificationToDTO(justification)
+		if approverID != userID {
+			onBehalfOfID := approverID
+			justificationDTO.OnBehalfOfID = &onBehalfOfID
+		}
+		justificationDTOs = append(justificationDTOs, justificationDTO)
+	}
+
+	for _, approverID := range approverIDs {
+		approvals, err := s.approvalService.GetMyPending(approverID)
+		if err != nil {
+			return nil, err
+		}
+		for _, approval := range approvals {
+			if approval.EntityType != models.ApprovalEntityDelayJustification {
+				continue
+			}
+			if justification, err := s.delayJustificationRepo.FindByID(approval.EntityID); err == nil {
+				add(justification, approverID)
+			}
+		}
+	}
+
+	// Justifications sans circuit : validation par le responsable hiérarchique du technicien
+	pending, err := s.delayJustificationRepo.FindPending()
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des justifications en attente")
+	}
+	for i := range pending {
+		justification := &pending[i]
+		if justification.ApprovalRequestID != nil || justification.User.ManagerID == nil {
+			continue
+		}
+		if slices.Contains(approverIDs, *justification.User.ManagerID) {
+			add(justification, *justification.User.ManagerID)
+		}
+	}
+	return justificationDTOs, nil
+}
+
+// notifyManagerOfJustification informe le responsable hiérarchique du technicien (ou son suppléant s'il est absent)
+// qu'une justification attend sa validation
+func (s *delayService) notifyManagerOfJustification(delay *models.Delay) {
+	user, err := s.userRepo.FindByID(delay.UserID)
+	if err != nil || user.ManagerID == nil {
+		return
+	}
+
+	// Responsable absent : la validation revient à son suppléant
+	recipientID := *user.ManagerID
+	if manager, err := s.userRepo.FindByID(recipientID); err == nil && manager.IsOutOfOffice(time.Now()) && manager.BackupUserID != nil {
+		recipientID = *manager.BackupUserID
+	}
+
+	message := fmt.Sprintf("%s %s a justifié un retard", user.FirstName, user.LastName)
+	if delay.Ticket != nil && delay.Ticket.Code != "" {
+		message += " sur le ticket " + delay.Ticket.Code
+	}
+	err = s.jobQueue.Enqueue(context.Background(), jobs.TypeNotifyUsers, jobs.NotifyUsersPayload{
+		UserIDs: []uint{recipientID},
+		Type:    "delay_justification_pending",
+		Title:   "Justification de retard à valider",
+		Message: message,
+		LinkURL: fmt.Sprintf("/app/delays/%d", delay.ID),
+		Metadata: map[string]any{
+			"delay_id": delay.ID,
+			"user_id":  user.ID,
+		},
+	})
+	if err != nil {
+		log.Printf("Erreur lors de la notification du responsable %d pour le retard %d: %v", recipientID, delay.ID, err)
+	}
 }
 
 // GetStatusStats récupère les statistiques de retards par statut
@@ -589,9 +896,10 @@ func (s *delayService) justificationToDTO(justification *models.DelayJustificati
 		DelayID:       justification.DelayID,
 		UserID:        justification.UserID,
 		Justification: justification.Justification,
-		Status:        justification.Status,
-		CreatedAt:     justification.CreatedAt,
-		UpdatedAt:     justification.UpdatedAt,
+		Status:            justification.Status,
+		ApprovalRequestID: justification.ApprovalRequestID,
+		CreatedAt:         justification.CreatedAt,
+		UpdatedAt:         justification.UpdatedAt,
 	}
 
 	if justification.Delay.ID != 0 {
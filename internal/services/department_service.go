@@ -9,6 +9,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // DepartmentService interface pour les opérations sur les départements
@@ -28,6 +29,7 @@ type departmentService struct {
 	departmentRepo repositories.DepartmentRepository
 	officeRepo     repositories.OfficeRepository
 	filialeRepo    repositories.FilialeRepository
+	userRepo       repositories.UserRepository
 }
 
 // NewDepartmentService crée une nouvelle instance de DepartmentService
@@ -35,11 +37,13 @@ func NewDepartmentService(
 	departmentRepo repositories.DepartmentRepository,
 	officeRepo repositories.OfficeRepository,
 	filialeRepo repositories.FilialeRepository,
+	userRepo repositories.UserRepository,
 ) DepartmentService {
 	return &departmentService{
 		departmentRepo: departmentRepo,
 		officeRepo:     officeRepo,
 		filialeRepo:    filialeRepo,
+		userRepo:       userRepo,
 	}
 }
 
@@ -67,7 +71,7 @@ func (s *departmentService) Create(req dto.CreateDepartmentRequest) (*dto.Depart
 	}
 	filiale, err := s.filialeRepo.FindByID(*req.FilialeID)
 	if err != nil {
-		return nil, errors.New("filiale introuvable")
+		return nil, utils.ErrFilialeNotFound
 	}
 
 	// Préfixer automatiquement le code avec le code filiale
@@ -83,7 +87,14 @@ func (s *departmentService) Create(req dto.CreateDepartmentRequest) (*dto.Depart
 	if req.OfficeID != nil {
 		_, err := s.officeRepo.FindByID(*req.OfficeID)
 		if err != nil {
-			return nil, errors.New("siège introuvable")
+			return nil, utils.ErrOfficeNotFound
+		}
+	}
+
+	// Vérifier que le chef de département existe (si fourni)
+	if req.HeadID != nil {
+		if _, err := s.userRepo.FindByID(*req.HeadID); err != nil {
+			return nil, utils.ErrUserNotFound
 		}
 	}
 
@@ -115,6 +126,7 @@ func (s *departmentService) Create(req dto.CreateDepartmentRequest) (*dto.Depart
 		OfficeID:       req.OfficeID,
 		IsActive:       isActive,
 		IsITDepartment: isITDepartment,
+		HeadID:         req.HeadID,
 	}
 
 	if err := s.departmentRepo.Create(department); err != nil {
@@ -193,7 +205,7 @@ func (s *departmentService) GetAll(activeOnly bool) ([]dto.DepartmentDTO, error)
 func (s *departmentService) GetByID(id uint) (*dto.DepartmentDTO, error) {
 	department, err := s.departmentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("département introuvable")
+		return nil, utils.ErrDepartmentNotFound
 	}
 
 	return s.departmentToDTO(department), nil
@@ -203,7 +215,7 @@ func (s *departmentService) GetByID(id uint) (*dto.DepartmentDTO, error) {
 func (s *departmentService) GetByCode(code string) (*dto.DepartmentDTO, error) {
 	department, err := s.departmentRepo.FindByCode(code)
 	if err != nil {
-		return nil, errors.New("département introuvable")
+		return nil, utils.ErrDepartmentNotFound
 	}
 
 	return s.departmentToDTO(department), nil
@@ -213,7 +225,7 @@ func (s *departmentService) GetByCode(code string) (*dto.DepartmentDTO, error) {
 func (s *departmentService) Update(id uint, req dto.UpdateDepartmentRequest) (*dto.DepartmentDTO, error) {
 	department, err := s.departmentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("département introuvable")
+		return nil, utils.ErrDepartmentNotFound
 	}
 
 	// Si le code est modifié, le préfixer avec le code filiale (celle du département ou celle demandée)
@@ -227,7 +239,7 @@ func (s *departmentService) Update(id uint, req dto.UpdateDepartmentRequest) (*d
 		}
 		filiale, err := s.filialeRepo.FindByID(*targetFilialeID)
 		if err != nil {
-			return nil, errors.New("filiale introuvable")
+			return nil, utils.ErrFilialeNotFound
 		}
 		prefixed := prefixCodeWithFiliale(filiale.Code, *req.Code)
 		req.Code = &prefixed
@@ -245,7 +257,7 @@ func (s *departmentService) Update(id uint, req dto.UpdateDepartmentRequest) (*d
 	if req.OfficeID != nil {
 		_, err := s.officeRepo.FindByID(*req.OfficeID)
 		if err != nil {
-			return nil, errors.New("siège introuvable")
+			return nil, utils.ErrOfficeNotFound
 		}
 	}
 
@@ -256,12 +268,12 @@ func (s *departmentService) Update(id uint, req dto.UpdateDepartmentRequest) (*d
 		if req.FilialeID != nil {
 			filialeToCheck, err = s.filialeRepo.FindByID(*req.FilialeID)
 			if err != nil {
-				return nil, errors.New("filiale introuvable")
+				return nil, utils.ErrFilialeNotFound
 			}
 		} else if department.FilialeID != nil {
 			filialeToCheck, err = s.filialeRepo.FindByID(*department.FilialeID)
 			if err != nil {
-				return nil, errors.New("filiale introuvable")
+				return nil, utils.ErrFilialeNotFound
 			}
 		}
 
@@ -291,6 +303,16 @@ func (s *departmentService) Update(id uint, req dto.UpdateDepartmentRequest) (*d
 	if req.IsActive != nil {
 		department.IsActive = *req.IsActive
 	}
+	if req.HeadID != nil {
+		if *req.HeadID == 0 {
+			department.HeadID = nil
+		} else {
+			if _, err := s.userRepo.FindByID(*req.HeadID); err != nil {
+				return nil, utils.ErrUserNotFound
+			}
+			department.HeadID = req.HeadID
+		}
+	}
 
 	if err := s.departmentRepo.Update(department); err != nil {
 		return nil, errors.New("erreur lors de la mise à jour du département")
@@ -309,7 +331,7 @@ func (s *departmentService) Update(id uint, req dto.UpdateDepartmentRequest) (*d
 func (s *departmentService) Delete(id uint) error {
 	_, err := s.departmentRepo.FindByID(id)
 	if err != nil {
-		return errors.New("département introuvable")
+		return utils.ErrDepartmentNotFound
 	}
 
 	if err := s.departmentRepo.Delete(id); err != nil {
@@ -360,6 +382,7 @@ func (s *departmentService) departmentToDTO(department *models.Department) *dto.
 		OfficeID:       department.OfficeID,
 		IsActive:       department.IsActive,
 		IsITDepartment: department.IsITDepartment,
+		HeadID:         department.HeadID,
 		CreatedAt:      department.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:      department.UpdatedAt.Format(time.RFC3339),
 	}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/mail"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+)
+
+// emailNotificationTypes types de notification pouvant être envoyés par email (modèle dédié ou modèle par défaut)
+var emailNotificationTypes = []string{"ticket_created", "ticket_assigned", "ticket_status_changed", "ticket_escalated", "ticket_merged", "ticket_commented", "ticket_satisfaction", "sla_at_risk", "sla_violated", "major_incident", "major_incident_communication", "major_incident_update_due", "approval_requested", "approval_decided", "software_license_expiring", "asset_reservation_decided", "asset_reservation_due", "asset_reservation_overdue", "weekly_declaration_decided", "absence_requested", "absence_decided"}
+
+// ticketStatusLabels libellés des statuts de ticket affichés dans les emails
+var ticketStatusLabels = map[string]string{
+	"ouvert":     "Ouvert",
+	"en_cours":   "En cours",
+	"en_attente": "En attente",
+	"resolu":     "Résolu",
+	"cloture":    "Clôturé",
+	"fusionne":   "Fusionné",
+}
+
+// EmailService interface pour le canal email : réglages par type de notification et envoi par SMTP
+type EmailService interface {
+	NotificationChannel
+	Enabled() bool
+	GetPreferences(userID uint) (*dto.NotificationPreferencesDTO, error)
+	UpdatePreferences(userID uint, req dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesDTO, error)
+	Send(ctx context.Context, payload jobs.EmailSendPayload) error
+}
+
+// emailService implémente EmailService
+type emailService struct {
+	client                     *mail.Client // Nil si le canal n'est pas configuré
+	templates                  *mail.Templates
+	appName                    string
+	frontendURL                string
+	userRepo                   repositories.UserRepository
+	preferenceRepo             repositories.UserPreferenceRepository
+	notificationPreferenceRepo repositories.NotificationPreferenceRepository
+	jobQueue                   *jobs.Queue
+}
+
+// NewEmailService crée une nouvelle instance de EmailService ; des modèles illisibles désactivent le canal
+func NewEmailService(
+	cfg config.SMTPConfig,
+	appName string,
+	userRepo repositories.UserRepository,
+	preferenceRepo repositories.UserPreferenceRepository,
+	notificationPreferenceRepo repositories.NotificationPreferenceRepository,
+	jobQueue *jobs.Queue,
+) EmailService {
+	service := &emailService{
+		appName:                    appName,
+		frontendURL:                cfg.FrontendURL,
+		userRepo:                   userRepo,
+		preferenceRepo:             preferenceRepo,
+		notificationPreferenceRepo: notificationPreferenceRepo,
+		jobQueue:                   jobQueue,
+	}
+	if cfg.Enabled() {
+		templates, err := mail.LoadTemplates()
+		if err != nil {
+			log.Printf("⚠️  Notifications par email désactivées: %v", err)
+			return service
+		}
+		service.templates = templates
+		service.client = mail.New(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From, cfg.FromName, cfg.TLS)
+	}
+	return service
+}
+
+// Enabled indique si le canal est configuré
+func (s *emailService) Enabled() bool {
+	return s.client != nil
+}
+
+// GetPreferences retourne le réglage de chaque type de notification pouvant être envoyé par email (activé par défaut)
+func (s *emailService) GetPreferences(userID uint) (*dto.NotificationPreferencesDTO, error) {
+	preferences, err := s.notificationPreferenceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des préférences de notification")
+	}
+	emailByType := make(map[string]bool, len(preferences))
+	for _, preference := range preferences {
+		emailByType[preference.NotificationType] = preference.Email
+	}
+
+	preferencesDTO := &dto.NotificationPreferencesDTO{
+		EmailAvailable:     s.Enabled(),
+		EmailNotifications: s.emailNotificationsEnabled(userID),
+		Types:              make([]dto.NotificationTypePreferenceDTO, len(emailNotificationTypes)),
+	}
+	for i, notificationType := range emailNotificationTypes {
+		email, ok := emailByType[notificationType]
+		preferencesDTO.Types[i] = dto.NotificationTypePreferenceDTO{NotificationType: notificationType, Email: !ok || email}
+	}
+	return preferencesDTO, nil
+}
+
+// UpdatePreferences enregistre les réglages fournis (les types omis sont inchangés)
+func (s *emailService) UpdatePreferences(userID uint, req dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesDTO, error) {
+	preferences := make([]models.NotificationPreference, 0, len(req.Types))
+	for _, typePreference := range req.Types {
+		if !slices.Contains(emailNotificationTypes, typePreference.NotificationType) {
+			return nil, fmt.Errorf("type de notification non pris en charge: %s", typePreference.NotificationType)
+		}
+		preferences = append(preferences, models.NotificationPreference{
+			UserID:           userID,
+			NotificationType: typePreference.NotificationType,
+			Email:            *typePreference.Email,
+		})
+	}
+	if err := s.notificationPreferenceRepo.Upsert(preferences); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement des préférences de notification")
+	}
+	return s.GetPreferences(userID)
+}
+
+// Deliver planifie l'envoi de la notification par email selon les préférences du destinataire
+func (s *emailService) Deliver(notification *models.Notification) {
+	if !s.Enabled() || !slices.Contains(emailNotificationTypes, notification.Type) {
+		return
+	}
+	if !s.emailNotificationsEnabled(notification.UserID) {
+		return
+	}
+	if preference, err := s.notificationPreferenceRepo.FindByUserIDAndType(notification.UserID, notification.Type); err == nil && !preference.Email {
+		return
+	}
+
+	payload := jobs.EmailSendPayload{
+		UserID:           notification.UserID,
+		NotificationType: notification.Type,
+		Title:            notification.Title,
+		Message:          notification.Message,
+		LinkURL:          notification.LinkURL,
+	}
+	if len(notification.Metadata) > 0 {
+		_ = json.Unmarshal(notification.Metadata, &payload.Metadata)
+	}
+	if err := s.jobQueue.Enqueue(context.Background(), jobs.TypeEmailSend, payload); err != nil {
+		log.Printf("Erreur lors de la planification de la notification par email (user %d): %v", notification.UserID, err)
+	}
+}
+
+// Send envoie une notification par email avec le modèle de son type ; une adresse refusée n'est pas réessayée
+func (s *emailService) Send(ctx context.Context, payload jobs.EmailSendPayload) error {
+	if !s.Enabled() {
+		return nil
+	}
+	user, err := s.userRepo.FindByID(payload.UserID)
+	if err != nil || !user.IsActive || user.Email == "" {
+		return nil // Compte supprimé ou désactivé entre-temps
+	}
+
+	data := mail.TemplateData{
+		AppName:       s.appName,
+		RecipientName: strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Title:         payload.Title,
+		Message:       payload.Message,
+		TicketCode:    metadataString(payload.Metadata, "ticket_code"),
+		TicketTitle:   metadataString(payload.Metadata, "ticket_title"),
+		OldStatus:     ticketStatusLabel(metadataString(payload.Metadata, "old_status")),
+		NewStatus:     ticketStatusLabel(metadataString(payload.Metadata, "new_status")),
+		TargetTime:    metadataString(payload.Metadata, "target_time"),
+	}
+	if data.TicketTitle == "" {
+		data.TicketTitle = payload.Title
+	}
+	if s.frontendURL != "" && payload.LinkURL != "" {
+		data.LinkURL = s.frontendURL + payload.LinkURL
+	}
+	subject, text, html, err := s.templates.Render(payload.NotificationType, data)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.Send(ctx, mail.Message{
+		To:      user.Email,
+		ToName:  data.RecipientName,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+	})
+	if errors.Is(err, mail.ErrInvalidRecipient) {
+		log.Printf("Notification par email: adresse refusée pour l'utilisateur %d", user.ID)
+		return nil
+	}
+	return err
+}
+
+// emailNotificationsEnabled indique si l'utilisateur reçoit les notifications par email (interrupteur général, activé par défaut)
+func (s *emailService) emailNotificationsEnabled(userID uint) bool {
+	if s.preferenceRepo == nil {
+		return true
+	}
+	preference, err := s.preferenceRepo.FindByUserIDCached(userID)
+	if err != nil || preference == nil {
+		return true
+	}
+	return preference.EmailNotifications
+}
+
+// metadataString retourne une valeur des métadonnées d'une notification sous forme de texte
+func metadataString(metadata map[string]any, key string) string {
+	value, ok := metadata[key]
+	if !ok || value == nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprint(value)
+}
+
+// ticketStatusLabel retourne le libellé d'un statut de ticket (le code si inconnu)
+func ticketStatusLabel(status string) string {
+	if label, ok := ticketStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Déclencheurs des règles d'escalade
+const (
+	EscalationTriggerStatusDuration = "status_duration" // Ticket resté plus de N heures dans un statut
+	EscalationTriggerSLAAtRisk      = "sla_at_risk"     // Échéance SLA du ticket à risque
+)
+
+// ticketPriorities priorités des tickets, de la plus basse à la plus haute
+var ticketPriorities = []string{"low", "medium", "high", "critical"}
+
+// EscalationService interface pour les règles d'escalade automatique des tickets
+type EscalationService interface {
+	GetAll(filialeID *uint, category string) ([]dto.EscalationRuleDTO, error)
+	GetByID(id uint) (*dto.EscalationRuleDTO, error)
+	Create(req dto.CreateEscalationRuleRequest, createdByID uint) (*dto.EscalationRuleDTO, error)
+	Update(id uint, req dto.UpdateEscalationRuleRequest) (*dto.EscalationRuleDTO, error)
+	Delete(id uint) error
+	EvaluateStatusRules() (int, error)                         // Escalade les tickets restés trop longtemps dans un statut (exécuté périodiquement)
+	HandleEvent(ctx context.Context, event events.Event) error // Escalade les tickets dont le SLA passe à risque (abonné au bus d'événements)
+}
+
+// escalationService implémente EscalationService
+type escalationService struct {
+	ruleRepo            repositories.EscalationRuleRepository
+	userRepo            repositories.UserRepository
+	filialeRepo         repositories.FilialeRepository
+	ticketCategoryRepo  repositories.TicketCategoryRepository
+	ticketService       TicketService
+	notificationService NotificationService
+}
+
+// NewEscalationService crée une nouvelle instance de EscalationService
+func NewEscalationService(
+	ruleRepo repositories.EscalationRuleRepository,
+	userRepo repositories.UserRepository,
+	filialeRepo repositories.FilialeRepository,
+	ticketCategoryRepo repositories.TicketCategoryRepository,
+	ticketService TicketService,
+	notificationService NotificationService,
+) EscalationService {
+	return &escalationService{
+		ruleRepo:            ruleRepo,
+		userRepo:            userRepo,
+		filialeRepo:         filialeRepo,
+		ticketCategoryRepo:  ticketCategoryRepo,
+		ticketService:       ticketService,
+		notificationService: notificationService,
+	}
+}
+
+// GetAll récupère les règles d'escalade (celles de la filiale et les règles globales si filialeID est fourni)
+func (s *escalationService) GetAll(filialeID *uint, category string) ([]dto.EscalationRuleDTO, error) {
+	rules, err := s.ruleRepo.FindAll(filialeID, category)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des règles d'escalade")
+	}
+	ruleDTOs := make([]dto.EscalationRuleDTO, 0, len(rules))
+	for i := range rules {
+		ruleDTOs = append(ruleDTOs, escalationRuleToDTO(&rules[i]))
+	}
+	return ruleDTOs, nil
+}
+
+// GetByID récupère une règle d'escalade par son ID
+func (s *escalationService) GetByID(id uint) (*dto.EscalationRuleDTO, error) {
+	rule, err := s.ruleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrEscalationRuleNotFound
+	}
+	ruleDTO := escalationRuleToDTO(rule)
+	return &ruleDTO, nil
+}
+
+// Create crée une règle d'escalade
+func (s *escalationService) Create(req dto.CreateEscalationRuleRequest, createdByID uint) (*dto.EscalationRuleDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("le nom de la règle est obligatoire")
+	}
+	if req.FilialeID != nil {
+		if _, err := s.filialeRepo.FindByID(*req.FilialeID); err != nil {
+			return nil, utils.ErrFilialeNotFound
+		}
+	}
+
+	rule := &models.EscalationRule{
+		Name:           name,
+		Description:    strings.TrimSpace(req.Description),
+		FilialeID:      req.FilialeID,
+		Category:       req.Category,
+		Trigger:        req.Trigger,
+		Status:         req.Status,
+		ThresholdHours: req.ThresholdHours,
+		BumpPriority:   req.BumpPriority,
+		NotifyManagers: req.NotifyManagers,
+		IsActive:       req.IsActive == nil || *req.IsActive,
+		CreatedByID:    createdByID,
+	}
+	if err := s.applyUserLists(rule, &req.ReassignToIDs, &req.NotifyUserIDs); err != nil {
+		return nil, err
+	}
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la règle d'escalade")
+	}
+	if !rule.IsActive {
+		// La valeur par défaut de la colonne s'applique à false à la création
+		if err := s.ruleRepo.Update(rule); err != nil {
+			log.Printf("Erreur lors de la désactivation de la règle d'escalade %d: %v", rule.ID, err)
+		}
+	}
+	return s.GetByID(rule.ID)
+}
+
+// Update met à jour une règle d'escalade
+func (s *escalationService) Update(id uint, req dto.UpdateEscalationRuleRequest) (*dto.EscalationRuleDTO, error) {
+	rule, err := s.ruleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrEscalationRuleNotFound
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("le nom de la règle est obligatoire")
+		}
+		rule.Name = name
+	}
+	if req.Description != nil {
+		rule.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Category != nil {
+		rule.Category = *req.Category
+	}
+	if req.Status != nil {
+		rule.Status = *req.Status
+	}
+	if req.ThresholdHours != nil {
+		rule.ThresholdHours = *req.ThresholdHours
+	}
+	if req.BumpPriority != nil {
+		rule.BumpPriority = *req.BumpPriority
+	}
+	if req.NotifyManagers != nil {
+		rule.NotifyManagers = *req.NotifyManagers
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if err := s.applyUserLists(rule, req.ReassignToIDs, req.NotifyUserIDs); err != nil {
+		return nil, err
+	}
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la règle d'escalade")
+	}
+	return s.GetByID(id)
+}
+
+// Delete supprime une règle d'escalade
+func (s *escalationService) Delete(id uint) error {
+	if _, err := s.ruleRepo.FindByID(id); err != nil {
+		return utils.ErrEscalationRuleNotFound
+	}
+	if err := s.ruleRepo.Delete(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de la règle d'escalade")
+	}
+	return nil
+}
+
+// EvaluateStatusRules applique les règles status_duration aux tickets restés trop longtemps dans le statut surveillé
+func (s *escalationService) EvaluateStatusRules() (int, error) {
+	rules, err := s.ruleRepo.FindActiveByTrigger(EscalationTriggerStatusDuration)
+	if err != nil {
+		return 0, utils.NewInternalError("erreur lors de la récupération des règles d'escalade")
+	}
+
+	escalated := 0
+	now := time.Now()
+	for i := range rules {
+		rule := &rules[i]
+		ticketIDs, err := s.ruleRepo.FindStatusDurationCandidates(rule, now)
+		if err != nil {
+			log.Printf("Erreur lors de la recherche des tickets à escalader (règle %d): %v", rule.ID, err)
+			continue
+		}
+		for _, ticketID := range ticketIDs {
+			ticket, err := s.ticketService.GetByID(ticketID, false)
+			if err != nil {
+				continue
+			}
+			reason := fmt.Sprintf("au statut « %s » depuis plus de %d h", ticketStatusLabel(rule.Status), rule.ThresholdHours)
+			s.escalate(rule, ticket, reason)
+			escalated++
+		}
+	}
+	return escalated, nil
+}
+
+// HandleEvent applique les règles sla_at_risk au ticket de l'événement (une seule fois par règle et par ticket)
+func (s *escalationService) HandleEvent(ctx context.Context, event events.Event) error {
+	if event.Type != events.SLAAtRisk {
+		return nil
+	}
+	ticket, ok := event.Data.(dto.TicketDTO)
+	if !ok {
+		return nil
+	}
+	rules, err := s.ruleRepo.FindActiveByTrigger(EscalationTriggerSLAAtRisk)
+	if err != nil {
+		return fmt.Errorf("règles d'escalade: %w", err)
+	}
+	for i := range rules {
+		rule := &rules[i]
+		if !escalationRuleMatches(rule, &ticket) {
+			continue
+		}
+		done, err := s.ruleRepo.HasEscalation(rule.ID, ticket.ID)
+		if err != nil {
+			return fmt.Errorf("escalade du ticket %d (règle %d): %w", ticket.ID, rule.ID, err)
+		}
+		if !done {
+			s.escalate(rule, &ticket, "échéance SLA à risque")
+		}
+	}
+	return nil
+}
+
+// escalate applique les actions de la règle au ticket puis enregistre l'escalade ; une action en échec n'empêche pas les suivantes
+func (s *escalationService) escalate(rule *models.EscalationRule, ticket *dto.TicketDTO, reason string) {
+	var actions []string
+	previousAssigneeIDs := ticketAssigneeIDs(ticket)
+
+	if reassignTo := decodeUintList(rule.ReassignToIDs); len(reassignTo) > 0 {
+		if _, err := s.ticketService.Assign(ticket.ID, dto.AssignTicketRequest{UserIDs: reassignTo}, rule.CreatedByID); err != nil {
+			log.Printf("Escalade du ticket %d (règle %d): réassignation impossible: %v", ticket.ID, rule.ID, err)
+		} else {
+			actions = append(actions, "reassigned")
+		}
+	}
+	if rule.BumpPriority {
+		if priority := nextTicketPriority(ticket.Priority); priority != "" {
+			if _, err := s.ticketService.Update(ticket.ID, dto.UpdateTicketRequest{Priority: priority}, rule.CreatedByID); err != nil {
+				log.Printf("Escalade du ticket %d (règle %d): changement de priorité impossible: %v", ticket.ID, rule.ID, err)
+			} else {
+				actions = append(actions, "priority:"+priority)
+			}
+		}
+	}
+
+	recipients := s.escalationRecipients(rule, previousAssigneeIDs)
+	title := fmt.Sprintf("Ticket escaladé : %s", ticket.Title)
+	message := fmt.Sprintf("Le ticket %s a été escaladé par la règle « %s » (%s).", ticket.Code, rule.Name, reason)
+	linkURL := fmt.Sprintf("/app/tickets/%d", ticket.ID)
+	metadata := map[string]any{
+		"ticket_id":    ticket.ID,
+		"ticket_code":  ticket.Code,
+		"ticket_title": ticket.Title,
+		"rule_id":      rule.ID,
+		"rule_name":    rule.Name,
+		"reason":       reason,
+		"escalation":   true,
+	}
+	notified := 0
+	for _, userID := range recipients {
+		if err := s.notificationService.Create(userID, "ticket_escalated", title, message, linkURL, metadata); err != nil {
+			log.Printf("Escalade du ticket %d (règle %d): notification impossible (user %d): %v", ticket.ID, rule.ID, userID, err)
+			continue
+		}
+		notified++
+	}
+	if notified > 0 {
+		actions = append(actions, fmt.Sprintf("notified:%d", notified))
+	}
+
+	if err := s.ruleRepo.CreateEscalation(&models.TicketEscalation{
+		RuleID:   rule.ID,
+		TicketID: ticket.ID,
+		Trigger:  rule.Trigger,
+		Actions:  strings.Join(actions, ","),
+	}); err != nil {
+		log.Printf("Erreur lors de l'enregistrement de l'escalade du ticket %d (règle %d): %v", ticket.ID, rule.ID, err)
+	}
+}
+
+// escalationRecipients retourne la chaîne d'escalade de la règle, complétée des responsables (N+1) des assignés si demandé
+func (s *escalationService) escalationRecipients(rule *models.EscalationRule, assigneeIDs []uint) []uint {
+	recipients := decodeUintList(rule.NotifyUserIDs)
+	if !rule.NotifyManagers || len(assigneeIDs) == 0 {
+		return recipients
+	}
+	users, err := s.userRepo.FindByIDs(assigneeIDs)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des responsables des assignés: %v", err)
+		return recipients
+	}
+	for _, user := range users {
+		if user.ManagerID != nil && !slices.Contains(recipients, *user.ManagerID) {
+			recipients = append(recipients, *user.ManagerID)
+		}
+	}
+	return recipients
+}
+
+// applyUserLists valide et enregistre le groupe de repli et la chaîne d'escalade fournis (nil : inchangé)
+func (s *escalationService) applyUserLists(rule *models.EscalationRule, reassignTo, notify *[]uint) error {
+	var err error
+	if reassignTo != nil {
+		if rule.ReassignToIDs, err = s.encodeUserIDs(*reassignTo, "du groupe de repli"); err != nil {
+			return err
+		}
+	}
+	if notify != nil {
+		if rule.NotifyUserIDs, err = s.encodeUserIDs(*notify, "de la chaîne d'escalade"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeUserIDs vérifie que les utilisateurs existent et sérialise leurs IDs (sans doublons)
+func (s *escalationService) encodeUserIDs(userIDs []uint, label string) ([]byte, error) {
+	ids, _, _ := normalizeAssignees(userIDs, nil)
+	if len(ids) > 0 {
+		count, err := s.userRepo.CountByIDs(ids)
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors de la vérification des utilisateurs")
+		}
+		if int(count) != len(ids) {
+			return nil, fmt.Errorf("un ou plusieurs utilisateurs %s sont introuvables", label)
+		}
+	}
+	return json.Marshal(ids)
+}
+
+// validateRule vérifie la cohérence d'une règle (paramètres du déclencheur, catégorie, au moins une action)
+func (s *escalationService) validateRule(rule *models.EscalationRule) error {
+	if rule.Trigger == EscalationTriggerStatusDuration && (rule.Status == "" || rule.ThresholdHours < 1) {
+		return errors.New("le statut surveillé et la durée (en heures) sont obligatoires pour le déclencheur status_duration")
+	}
+	if rule.Trigger == EscalationTriggerSLAAtRisk {
+		rule.Status = ""
+		rule.ThresholdHours = 0
+	}
+	if rule.Category != "" {
+		if _, err := s.ticketCategoryRepo.FindBySlug(rule.Category); err != nil {
+			return utils.ErrCategoryNotFound
+		}
+	}
+	if len(decodeUintList(rule.ReassignToIDs)) == 0 && !rule.BumpPriority && len(decodeUintList(rule.NotifyUserIDs)) == 0 && !rule.NotifyManagers {
+		return errors.New("la règle doit comporter au moins une action (réassignation, priorité ou notification)")
+	}
+	return nil
+}
+
+// escalationRuleMatches indique si le ticket entre dans le périmètre de la règle (filiale et catégorie)
+func escalationRuleMatches(rule *models.EscalationRule, ticket *dto.TicketDTO) bool {
+	if rule.FilialeID != nil && (ticket.FilialeID == nil || *ticket.FilialeID != *rule.FilialeID) {
+		return false
+	}
+	return rule.Category == "" || rule.Category == ticket.Category
+}
+
+// ticketAssigneeIDs retourne les IDs des utilisateurs assignés au ticket (responsable compris)
+func ticketAssigneeIDs(ticket *dto.TicketDTO) []uint {
+	var ids []uint
+	if ticket.AssignedTo != nil && ticket.AssignedTo.ID != 0 {
+		ids = append(ids, ticket.AssignedTo.ID)
+	}
+	for _, assignee := range ticket.Assignees {
+		if assignee.User.ID != 0 && !slices.Contains(ids, assignee.User.ID) {
+			ids = append(ids, assignee.User.ID)
+		}
+	}
+	return ids
+}
+
+// nextTicketPriority retourne la priorité immédiatement supérieure (vide si déjà critique)
+func nextTicketPriority(priority string) string {
+	index := slices.Index(ticketPriorities, priority)
+	if index < 0 {
+		index = slices.Index(ticketPriorities, "medium")
+	}
+	if index+1 >= len(ticketPriorities) {
+		return ""
+	}
+	return ticketPriorities[index+1]
+}
+
+// decodeUintList décode une liste d'IDs enregistrée en JSON
+func decodeUintList(raw []byte) []uint {
+	ids := []uint{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &ids)
+	}
+	return ids
+}
+
+// escalationRuleToDTO convertit un modèle EscalationRule en DTO
+func escalationRuleToDTO(rule *models.EscalationRule) dto.EscalationRuleDTO {
+	ruleDTO := dto.EscalationRuleDTO{
+		ID:             rule.ID,
+		Name:           rule.Name,
+		Description:    rule.Description,
+		FilialeID:      rule.FilialeID,
+		Category:       rule.Category,
+		Trigger:        rule.Trigger,
+		Status:         rule.Status,
+		ThresholdHours: rule.ThresholdHours,
+		ReassignToIDs:  decodeUintList(rule.ReassignToIDs),
+		BumpPriority:   rule.BumpPriority,
+		NotifyUserIDs:  decodeUintList(rule.NotifyUserIDs),
+		NotifyManagers: rule.NotifyManagers,
+		IsActive:       rule.IsActive,
+		CreatedByID:    rule.CreatedByID,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+	if rule.Filiale != nil && rule.Filiale.ID != 0 {
+		ruleDTO.Filiale = &dto.FilialeDTO{ID: rule.Filiale.ID, Code: rule.Filiale.Code, Name: rule.Filiale.Name}
+	}
+	return ruleDTO
+}
@@ -0,0 +1,527 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/audit"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+)
+
+// RegisterEventSubscribers abonne les consommateurs internes au bus d'événements
+// Les services émetteurs (tickets, projets) publient sans connaître ces consommateurs
+func RegisterEventSubscribers(
+	bus *events.Bus,
+	notificationService NotificationService,
+	webhookService WebhookService,
+	searchService SearchService,
+	auditLogRepo repositories.AuditLogRepository,
+	jiraSyncService JiraSyncService,
+	userRepo repositories.UserRepository,
+	escalationService EscalationService,
+	ticketWatcherRepo repositories.TicketWatcherRepository,
+	satisfactionService TicketSatisfactionService,
+) {
+	// Webhooks sortants : tous les événements, le filtrage se fait par abonnement
+	if webhookService != nil {
+		bus.Subscribe("*", "webhooks", webhookService.HandleEvent)
+	}
+
+	// Notifications in-app / WebSocket
+	if notificationService != nil {
+		notifier := &eventNotifier{notificationService: notificationService, userRepo: userRepo, ticketWatcherRepo: ticketWatcherRepo}
+		bus.Subscribe(events.TicketAssigned, "notifications", notifier.onTicketAssigned)
+		bus.Subscribe(events.TicketStatusChanged, "notifications", notifier.onTicketStatusChanged)
+		bus.Subscribe(events.TicketCommented, "notifications", notifier.onTicketCommented)
+		bus.Subscribe(events.TicketMerged, "notifications", notifier.onTicketMerged)
+		bus.Subscribe(events.SLAAtRisk, "notifications", notifier.onSLAAtRisk)
+		bus.Subscribe(events.SLAViolated, "notifications", notifier.onSLAViolated)
+		bus.Subscribe(events.IncidentMajor, "notifications", notifier.onMajorIncident)
+		bus.Subscribe(events.MajorIncidentDeclared, "notifications", notifier.onMajorIncidentCommunication)
+		bus.Subscribe(events.MajorIncidentUpdated, "notifications", notifier.onMajorIncidentCommunication)
+		bus.Subscribe(events.MajorIncidentEnded, "notifications", notifier.onMajorIncidentCommunication)
+		bus.Subscribe(events.MajorIncidentUpdateDue, "notifications", notifier.onMajorIncidentUpdateDue)
+		bus.Subscribe(events.ApprovalRequested, "notifications", notifier.onApprovalRequested)
+		bus.Subscribe(events.ApprovalDecided, "notifications", notifier.onApprovalDecided)
+	}
+
+	// Journal d'audit métier : complète l'audit HTTP avec les transitions significatives
+	if auditLogRepo != nil {
+		auditor := &eventAuditor{auditLogRepo: auditLogRepo}
+		for _, eventType := range []string{events.TicketAssigned, events.TicketStatusChanged, events.TicketMerged, events.SLAViolated, events.IncidentMajor, events.TaskCompleted, events.ProjectDeleted, events.ProjectArchived, events.ProjectUnarchived, events.UserLoggedIn, events.ApprovalDecided, events.MajorIncidentDeclared, events.MajorIncidentEnded} {
+			bus.Subscribe(eventType, "audit", auditor.record)
+		}
+	}
+
+	// Synchronisation Jira : statut et commentaires publics des tickets liés
+	if jiraSyncService != nil && jiraSyncService.Enabled() {
+		for _, eventType := range []string{events.TicketCreated, events.TicketUpdated, events.TicketStatusChanged, events.TicketCommented} {
+			bus.Subscribe(eventType, "jira", jiraSyncService.HandleEvent)
+		}
+	}
+
+	// Règles d'escalade : réassignation, priorité et notification au passage à risque du SLA
+	if escalationService != nil {
+		bus.Subscribe(events.SLAAtRisk, "escalation", escalationService.HandleEvent)
+	}
+
+	// Enquêtes de satisfaction : envoyées au demandeur à la validation ou à la clôture du ticket
+	if satisfactionService != nil {
+		bus.Subscribe(events.TicketStatusChanged, "satisfaction", satisfactionService.HandleEvent)
+	}
+
+	// Index de recherche : les résultats en cache deviennent obsolètes dès qu'un ticket change
+	if searchService != nil {
+		bus.Subscribe("ticket.*", "search_index", func(ctx context.Context, event events.Event) error {
+			searchService.InvalidateCache()
+			return nil
+		})
+	}
+}
+
+// eventNotifier crée les notifications déclenchées par les événements du bus
+type eventNotifier struct {
+	notificationService NotificationService
+	userRepo            repositories.UserRepository
+	ticketWatcherRepo   repositories.TicketWatcherRepository
+}
+
+// onTicketAssigned notifie les utilisateurs assignés (sauf l'auteur de l'assignation)
+func (n *eventNotifier) onTicketAssigned(ctx context.Context, event events.Event) error {
+	ticket, ok := event.Data.(dto.TicketDTO)
+	if !ok {
+		return nil
+	}
+	assigneeIDs, _ := event.Metadata["assignee_ids"].([]uint)
+
+	title := fmt.Sprintf("Ticket assigné : %s", ticket.Title)
+	message := fmt.Sprintf("Le ticket %s vous a été assigné.", ticket.Code)
+	linkURL := fmt.Sprintf("/app/tickets/%d", ticket.ID)
+	metadata := map[string]any{"ticket_id": ticket.ID, "ticket_code": ticket.Code, "ticket_title": ticket.Title}
+	for _, userID := range assigneeIDs {
+		if event.ActorID != nil && *event.ActorID == userID {
+			continue
+		}
+		if err := n.notificationService.Create(userID, "ticket_assigned", title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("notification ticket assigné (user %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// onTicketStatusChanged notifie le demandeur du ticket (à défaut son créateur) et ses abonnés du nouveau statut, sauf l'auteur du changement
+// Le demandeur reçoit déjà une notification dédiée à la validation (ticket_validated) : seuls les abonnés sont alors notifiés de la résolution
+func (n *eventNotifier) onTicketStatusChanged(ctx context.Context, event events.Event) error {
+	ticket, ok := event.Data.(dto.TicketDTO)
+	if !ok {
+		return nil
+	}
+	oldStatus, _ := event.Metadata["old_status"].(string)
+	newStatus, _ := event.Metadata["new_status"].(string)
+	if newStatus == "" || newStatus == oldStatus {
+		return nil
+	}
+	requesterID := ticket.CreatedBy.ID
+	if ticket.RequesterID != nil {
+		requesterID = *ticket.RequesterID
+	}
+
+	notificationType := "ticket_status_changed"
+	title := fmt.Sprintf("Statut du ticket mis à jour : %s", ticket.Title)
+	message := fmt.Sprintf("Le ticket %s est passé au statut « %s ».", ticket.Code, ticketStatusLabel(newStatus))
+	if validated, _ := event.Metadata["validated"].(bool); validated {
+		notificationType = "ticket_validated"
+		title = fmt.Sprintf("Ticket validé : %s", ticket.Title)
+		message = fmt.Sprintf("Le ticket %s a été validé. Le problème est considéré comme résolu.", ticket.Code)
+		requesterID = 0
+	}
+	recipientIDs, err := n.ticketRecipients(ticket.ID, requesterID, event.ActorID)
+	if err != nil {
+		return err
+	}
+
+	linkURL := fmt.Sprintf("/app/tickets/%d", ticket.ID)
+	metadata := map[string]any{
+		"ticket_id":    ticket.ID,
+		"ticket_code":  ticket.Code,
+		"ticket_title": ticket.Title,
+		"old_status":   oldStatus,
+		"new_status":   newStatus,
+	}
+	for _, userID := range recipientIDs {
+		if err := n.notificationService.Create(userID, notificationType, title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("notification statut du ticket (user %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// onTicketCommented notifie le demandeur du ticket et ses abonnés d'un nouveau commentaire public, sauf son auteur
+func (n *eventNotifier) onTicketCommented(ctx context.Context, event events.Event) error {
+	comment, ok := event.Data.(dto.TicketCommentDTO)
+	if !ok || comment.IsInternal {
+		return nil
+	}
+	ticketCode, _ := event.Metadata["ticket_code"].(string)
+	ticketTitle, _ := event.Metadata["ticket_title"].(string)
+	requesterID, _ := event.Metadata["requester_id"].(uint)
+	recipientIDs, err := n.ticketRecipients(comment.TicketID, requesterID, event.ActorID)
+	if err != nil {
+		return err
+	}
+
+	author := strings.TrimSpace(comment.User.FirstName + " " + comment.User.LastName)
+	if author == "" {
+		author = comment.User.Username
+	}
+	title := fmt.Sprintf("Nouveau commentaire : %s", ticketTitle)
+	message := fmt.Sprintf("%s a commenté le ticket %s.", author, ticketCode)
+	linkURL := fmt.Sprintf("/app/tickets/%d", comment.TicketID)
+	metadata := map[string]any{
+		"ticket_id":    comment.TicketID,
+		"ticket_code":  ticketCode,
+		"ticket_title": ticketTitle,
+		"comment_id":   comment.ID,
+	}
+	for _, userID := range recipientIDs {
+		if err := n.notificationService.Create(userID, "ticket_commented", title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("notification commentaire du ticket (user %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// ticketRecipients retourne le demandeur (0 pour l'ignorer) puis les abonnés actifs du ticket, sans doublon et hors auteur de l'événement
+func (n *eventNotifier) ticketRecipients(ticketID, requesterID uint, actorID *uint) ([]uint, error) {
+	var recipientIDs []uint
+	if requesterID != 0 {
+		recipientIDs = append(recipientIDs, requesterID)
+	}
+	if n.ticketWatcherRepo != nil {
+		watcherIDs, err := n.ticketWatcherRepo.FindUserIDsByTicketID(ticketID)
+		if err != nil {
+			return nil, fmt.Errorf("abonnés du ticket %d: %w", ticketID, err)
+		}
+		for _, userID := range watcherIDs {
+			if !slices.Contains(recipientIDs, userID) {
+				recipientIDs = append(recipientIDs, userID)
+			}
+		}
+	}
+	if actorID != nil {
+		recipientIDs = slices.DeleteFunc(recipientIDs, func(userID uint) bool { return userID == *actorID })
+	}
+	return recipientIDs, nil
+}
+
+// onTicketMerged notifie les demandeurs des tickets fusionnés et du ticket principal (sauf l'auteur de la fusion)
+func (n *eventNotifier) onTicketMerged(ctx context.Context, event events.Event) error {
+	ticket, ok := event.Data.(dto.TicketDTO)
+	if !ok {
+		return nil
+	}
+	requesterTickets, _ := event.Metadata["requester_tickets"].(map[uint][]string)
+	mergedCodes, _ := event.Metadata["merged_ticket_codes"].([]string)
+
+	title := fmt.Sprintf("Tickets fusionnés : %s", ticket.Title)
+	linkURL := fmt.Sprintf("/app/tickets/%d", ticket.ID)
+	metadata := map[string]any{"ticket_id": ticket.ID, "ticket_code": ticket.Code, "ticket_title": ticket.Title, "merged_ticket_codes": mergedCodes}
+	for _, recipientID := range slices.Sorted(maps.Keys(requesterTickets)) {
+		if recipientID == 0 || (event.ActorID != nil && *event.ActorID == recipientID) {
+			continue
+		}
+		codes := slices.DeleteFunc(slices.Clone(requesterTickets[recipientID]), func(code string) bool { return code == ticket.Code })
+		message := fmt.Sprintf("Les tickets en double %s ont été fusionnés dans votre ticket %s.", strings.Join(mergedCodes, ", "), ticket.Code)
+		if len(codes) > 0 {
+			message = fmt.Sprintf("Votre demande %s a été fusionnée dans le ticket %s, qui poursuit son traitement.", strings.Join(codes, ", "), ticket.Code)
+		}
+		if err := n.notificationService.Create(recipientID, "ticket_merged", title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("notification tickets fusionnés (user %d): %w", recipientID, err)
+		}
+	}
+	return nil
+}
+
+// onSLAAtRisk notifie les assignés du ticket dont l'échéance SLA approche, ainsi que leurs responsables hiérarchiques
+func (n *eventNotifier) onSLAAtRisk(ctx context.Context, event events.Event) error {
+	ticket, ok := event.Data.(dto.TicketDTO)
+	if !ok {
+		return nil
+	}
+	targetTime, _ := event.Metadata["target_time"].(time.Time)
+	deadline := targetTime.Format("02/01/2006 15:04")
+
+	return n.notifySLAThreshold(ticket, "sla_at_risk",
+		fmt.Sprintf("SLA à risque : %s", ticket.Title),
+		fmt.Sprintf("L'échéance SLA du ticket %s approche (%s).", ticket.Code, deadline),
+		func(assignees string) string {
+			return fmt.Sprintf("L'échéance SLA du ticket %s, assigné à %s, approche (%s).", ticket.Code, assignees, deadline)
+		},
+		map[string]any{"target_time": deadline},
+	)
+}
+
+// onSLAViolated notifie les assignés du ticket dont le SLA est violé, ainsi que leurs responsables hiérarchiques
+func (n *eventNotifier) onSLAViolated(ctx context.Context, event events.Event) error {
+	ticket, ok := event.Data.(dto.TicketDTO)
+	if !ok {
+		return nil
+	}
+
+	return n.notifySLAThreshold(ticket, "sla_violated",
+		fmt.Sprintf("SLA violé : %s", ticket.Title),
+		fmt.Sprintf("Le délai SLA du ticket %s est dépassé.", ticket.Code),
+		func(assignees string) string {
+			return fmt.Sprintf("Le délai SLA du ticket %s, assigné à %s, est dépassé.", ticket.Code, assignees)
+		},
+		nil,
+	)
+}
+
+// notifySLAThreshold notifie le franchissement d'un seuil SLA aux assignés du ticket puis à leurs responsables (N+1)
+// managerMessage reçoit les noms des membres de l'équipe du responsable assignés au ticket
+func (n *eventNotifier) notifySLAThreshold(ticket dto.TicketDTO, notificationType, title, message string, managerMessage func(assignees string) string, extra map[string]any) error {
+	assigneeNames := map[uint]string{}
+	var assigneeIDs []uint
+	addAssignee := func(user dto.UserDTO) {
+		if user.ID == 0 || slices.Contains(assigneeIDs, user.ID) {
+			return
+		}
+		assigneeIDs = append(assigneeIDs, user.ID)
+		assigneeNames[user.ID] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		if assigneeNames[user.ID] == "" {
+			assigneeNames[user.ID] = user.Username
+		}
+	}
+	if ticket.AssignedTo != nil {
+		addAssignee(*ticket.AssignedTo)
+	}
+	for _, assignee := range ticket.Assignees {
+		addAssignee(assignee.User)
+	}
+	if len(assigneeIDs) == 0 {
+		return nil
+	}
+
+	linkURL := fmt.Sprintf("/app/tickets/%d", ticket.ID)
+	metadata := map[string]any{"ticket_id": ticket.ID, "ticket_code": ticket.Code, "ticket_title": ticket.Title}
+	for key, value := range extra {
+		metadata[key] = value
+	}
+	for _, userID := range assigneeIDs {
+		if err := n.notificationService.Create(userID, notificationType, title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("notification %s (user %d): %w", notificationType, userID, err)
+		}
+	}
+
+	if n.userRepo == nil {
+		return nil
+	}
+	users, err := n.userRepo.FindByIDs(assigneeIDs)
+	if err != nil {
+		return fmt.Errorf("responsables des assignés du ticket %d: %w", ticket.ID, err)
+	}
+	reportsByManager := map[uint][]string{}
+	var managerIDs []uint
+	for _, user := range users {
+		if user.ManagerID == nil || slices.Contains(assigneeIDs, *user.ManagerID) {
+			continue // Responsable déjà notifié en tant qu'assigné
+		}
+		if _, ok := reportsByManager[*user.ManagerID]; !ok {
+			managerIDs = append(managerIDs, *user.ManagerID)
+		}
+		reportsByManager[*user.ManagerID] = append(reportsByManager[*user.ManagerID], assigneeNames[user.ID])
+	}
+	for _, managerID := range managerIDs {
+		managerMetadata := maps.Clone(metadata)
+		managerMetadata["escalation"] = true
+		if err := n.notificationService.Create(managerID, notificationType, title, managerMessage(strings.Join(reportsByManager[managerID], ", ")), linkURL, managerMetadata); err != nil {
+			return fmt.Errorf("notification %s (responsable %d): %w", notificationType, managerID, err)
+		}
+	}
+	return nil
+}
+
+// onMajorIncident notifie le responsable du ticket et les destinataires des alertes d'incident majeur de sa filiale
+func (n *eventNotifier) onMajorIncident(ctx context.Context, event events.Event) error {
+	incident, ok := event.Data.(dto.IncidentDTO)
+	if !ok || incident.Ticket == nil {
+		return nil
+	}
+	ticket := incident.Ticket
+
+	var recipientIDs []uint
+	if ticket.AssignedTo != nil {
+		recipientIDs = append(recipientIDs, ticket.AssignedTo.ID)
+	}
+	if n.userRepo != nil {
+		users, err := n.userRepo.FindActiveByPermission("incidents.major_alerts", event.FilialeID)
+		if err != nil {
+			return fmt.Errorf("destinataires des alertes d'incident majeur: %w", err)
+		}
+		for _, user := range users {
+			if !slices.Contains(recipientIDs, user.ID) {
+				recipientIDs = append(recipientIDs, user.ID)
+			}
+		}
+	}
+
+	title := fmt.Sprintf("Incident majeur : %s", ticket.Title)
+	message := fmt.Sprintf("Le ticket %s est qualifié en incident majeur (impact critique, urgence %s).", ticket.Code, incident.Urgency)
+	linkURL := fmt.Sprintf("/app/tickets/%d", ticket.ID)
+	metadata := map[string]any{"ticket_id": ticket.ID, "ticket_code": ticket.Code, "incident_id": incident.ID}
+	for _, userID := range recipientIDs {
+		if event.ActorID != nil && *event.ActorID == userID {
+			continue
+		}
+		if err := n.notificationService.Create(userID, "major_incident", title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("notification incident majeur (user %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// onMajorIncidentCommunication diffuse la déclaration, les points de situation publics et le rétablissement d'un incident
+// majeur à tous les utilisateurs actifs de la filiale du ticket
+func (n *eventNotifier) onMajorIncidentCommunication(ctx context.Context, event events.Event) error {
+	if n.userRepo == nil {
+		return nil
+	}
+	publicTitle, _ := event.Metadata["public_title"].(string)
+	message, _ := event.Metadata["message"].(string)
+	status, _ := event.Metadata["status"].(string)
+
+	var title string
+	switch event.Type {
+	case events.MajorIncidentDeclared:
+		title = fmt.Sprintf("Incident majeur en cours : %s", publicTitle)
+	case events.MajorIncidentEnded:
+		title = fmt.Sprintf("Service rétabli : %s", publicTitle)
+	default:
+		title = fmt.Sprintf("Point de situation : %s", publicTitle)
+	}
+
+	userIDs, err := n.userRepo.FindActiveIDsByFiliale(event.FilialeID)
+	if err != nil {
+		return fmt.Errorf("destinataires de la communication d'incident majeur: %w", err)
+	}
+	linkURL := "/status"
+	metadata := map[string]any{"incident_id": event.EntityID, "status": status}
+	for _, userID := range userIDs {
+		if err := n.notificationService.Create(userID, "major_incident_communication", title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("communication d'incident majeur (user %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// onMajorIncidentUpdateDue rappelle au pilote et au déclarant qu'un point de situation est attendu
+func (n *eventNotifier) onMajorIncidentUpdateDue(ctx context.Context, event events.Event) error {
+	recipientIDs, _ := event.Metadata["recipient_ids"].([]uint)
+	publicTitle, _ := event.Metadata["public_title"].(string)
+	ticketCode, _ := event.Metadata["ticket_code"].(string)
+
+	title := fmt.Sprintf("Point de situation attendu : %s", publicTitle)
+	message := fmt.Sprintf("Aucun point de situation n'a été publié sur l'incident majeur %s depuis l'échéance prévue.", ticketCode)
+	linkURL := fmt.Sprintf("/app/incidents/%d", event.EntityID)
+	metadata := map[string]any{"incident_id": event.EntityID, "ticket_code": ticketCode}
+	for _, userID := range recipientIDs {
+		if err := n.notificationService.Create(userID, "major_incident_update_due", title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("rappel de point de situation (user %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// onApprovalRequested notifie les approbateurs de l'étape ouverte
+func (n *eventNotifier) onApprovalRequested(ctx context.Context, event events.Event) error {
+	approval, ok := event.Data.(dto.ApprovalRequestDTO)
+	if !ok {
+		return nil
+	}
+	approverIDs, _ := event.Metadata["approver_ids"].([]uint)
+
+	title := fmt.Sprintf("Approbation demandée : %s", approval.Title)
+	message := "Une demande d'approbation attend votre décision."
+	if approval.RequestedBy != nil {
+		message = fmt.Sprintf("%s demande votre approbation.", approval.RequestedBy.Username)
+	}
+	linkURL := fmt.Sprintf("/app/approvals/%d", approval.ID)
+	metadata := map[string]any{"approval_id": approval.ID, "entity_type": approval.EntityType, "entity_id": approval.EntityID, "step": approval.CurrentStep}
+	for _, userID := range approverIDs {
+		if err := n.notificationService.Create(userID, "approval_requested", title, message, linkURL, metadata); err != nil {
+			return fmt.Errorf("notification approbation demandée (user %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// onApprovalDecided notifie le demandeur de la décision finale
+func (n *eventNotifier) onApprovalDecided(ctx context.Context, event events.Event) error {
+	approval, ok := event.Data.(dto.ApprovalRequestDTO)
+	if !ok || approval.RequestedBy == nil {
+		return nil
+	}
+	if event.ActorID != nil && *event.ActorID == approval.RequestedBy.ID {
+		return nil
+	}
+
+	var title string
+	switch approval.Status {
+	case models.ApprovalStatusApproved:
+		title = fmt.Sprintf("Demande approuvée : %s", approval.Title)
+	case models.ApprovalStatusRejected:
+		title = fmt.Sprintf("Demande rejetée : %s", approval.Title)
+	default:
+		return nil
+	}
+	message := title
+	for _, approver := range approval.Approvers {
+		if approver.Status == models.ApproverStatusRejected && approver.Comment != "" {
+			message = fmt.Sprintf("Motif du rejet : %s", approver.Comment)
+		}
+	}
+	linkURL := fmt.Sprintf("/app/approvals/%d", approval.ID)
+	metadata := map[string]any{"approval_id": approval.ID, "entity_type": approval.EntityType, "entity_id": approval.EntityID, "status": approval.Status}
+	if err := n.notificationService.Create(approval.RequestedBy.ID, "approval_decided", title, message, linkURL, metadata); err != nil {
+		return fmt.Errorf("notification décision d'approbation (user %d): %w", approval.RequestedBy.ID, err)
+	}
+	return nil
+}
+
+// eventAuditor enregistre les événements métier dans le journal d'audit
+type eventAuditor struct {
+	auditLogRepo repositories.AuditLogRepository
+}
+
+// record crée une entrée d'audit dont l'action est le type d'événement
+func (a *eventAuditor) record(ctx context.Context, event events.Event) error {
+	var newValues []byte
+	if len(event.Metadata) > 0 {
+		newValues, _ = json.Marshal(event.Metadata)
+		newValues = audit.MaskJSON(newValues)
+	}
+	var entityID *uint
+	if event.EntityID != 0 {
+		id := event.EntityID
+		entityID = &id
+	}
+
+	return a.auditLogRepo.Create(&models.AuditLog{
+		UserID:      event.ActorID,
+		Action:      event.Type,
+		EntityType:  event.EntityType,
+		EntityID:    entityID,
+		NewValues:   newValues,
+		Description: fmt.Sprintf("Événement %s (%s)", event.Type, event.ID),
+		CreatedAt:   event.OccurredAt,
+	})
+}
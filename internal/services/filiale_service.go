@@ -6,6 +6,8 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // FilialeService interface pour les opérations sur les filiales
@@ -39,6 +41,9 @@ func (s *filialeService) Create(req dto.CreateFilialeRequest) (*dto.FilialeDTO,
 	if existing != nil {
 		return nil, errors.New("une filiale avec ce code existe déjà")
 	}
+	if !timezone.Valid(req.Timezone) {
+		return nil, errors.New("fuseau horaire inconnu (format IANA attendu, ex: Africa/Abidjan)")
+	}
 
 	filiale := &models.Filiale{
 		Code:        req.Code,
@@ -48,6 +53,7 @@ func (s *filialeService) Create(req dto.CreateFilialeRequest) (*dto.FilialeDTO,
 		Address:     req.Address,
 		Phone:       req.Phone,
 		Email:       req.Email,
+		Timezone:    req.Timezone,
 		IsActive:    true,
 		IsSoftwareProvider: req.IsSoftwareProvider,
 	}
@@ -69,7 +75,7 @@ func (s *filialeService) Create(req dto.CreateFilialeRequest) (*dto.FilialeDTO,
 func (s *filialeService) GetByID(id uint) (*dto.FilialeDTO, error) {
 	filiale, err := s.filialeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("filiale introuvable")
+		return nil, utils.ErrFilialeNotFound
 	}
 
 	return s.filialeToDTO(filiale), nil
@@ -79,7 +85,7 @@ func (s *filialeService) GetByID(id uint) (*dto.FilialeDTO, error) {
 func (s *filialeService) GetByCode(code string) (*dto.FilialeDTO, error) {
 	filiale, err := s.filialeRepo.FindByCode(code)
 	if err != nil {
-		return nil, errors.New("filiale introuvable")
+		return nil, utils.ErrFilialeNotFound
 	}
 
 	return s.filialeToDTO(filiale), nil
@@ -129,7 +135,7 @@ func (s *filialeService) GetSoftwareProvider() (*dto.FilialeDTO, error) {
 func (s *filialeService) Update(id uint, req dto.UpdateFilialeRequest) (*dto.FilialeDTO, error) {
 	filiale, err := s.filialeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("filiale introuvable")
+		return nil, utils.ErrFilialeNotFound
 	}
 
 	// Mettre à jour les champs fournis
@@ -151,6 +157,12 @@ func (s *filialeService) Update(id uint, req dto.UpdateFilialeRequest) (*dto.Fil
 	if req.Email != "" {
 		filiale.Email = req.Email
 	}
+	if req.Timezone != nil {
+		if !timezone.Valid(*req.Timezone) {
+			return nil, errors.New("fuseau horaire inconnu (format IANA attendu, ex: Africa/Abidjan)")
+		}
+		filiale.Timezone = *req.Timezone
+	}
 	if req.IsActive != nil {
 		filiale.IsActive = *req.IsActive
 	}
@@ -175,7 +187,7 @@ func (s *filialeService) Update(id uint, req dto.UpdateFilialeRequest) (*dto.Fil
 func (s *filialeService) Delete(id uint) error {
 	_, err := s.filialeRepo.FindByID(id)
 	if err != nil {
-		return errors.New("filiale introuvable")
+		return utils.ErrFilialeNotFound
 	}
 
 	if err := s.filialeRepo.Delete(id); err != nil {
@@ -196,6 +208,7 @@ func (s *filialeService) filialeToDTO(filiale *models.Filiale) *dto.FilialeDTO {
 		Address:     filiale.Address,
 		Phone:       filiale.Phone,
 		Email:       filiale.Email,
+		Timezone:    filiale.Timezone,
 		IsActive:    filiale.IsActive,
 		IsSoftwareProvider: filiale.IsSoftwareProvider,
 		CreatedAt:   filiale.CreatedAt,
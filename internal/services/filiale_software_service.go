@@ -4,14 +4,17 @@ import (
 	"errors"
 	"time"
 
+	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
 )
 
 // FilialeSoftwareService interface pour les opérations sur les déploiements de logiciels
 type FilialeSoftwareService interface {
-	Create(req dto.CreateFilialeSoftwareRequest) (*dto.FilialeSoftwareDTO, error)
+	Create(req dto.CreateFilialeSoftwareRequest, userID uint) (*dto.FilialeSoftwareDTO, error)
 	GetByID(id uint) (*dto.FilialeSoftwareDTO, error)
 	GetAll() ([]dto.FilialeSoftwareDTO, error)
 	GetByFilialeID(filialeID uint) ([]dto.FilialeSoftwareDTO, error)
@@ -20,8 +23,13 @@ type FilialeSoftwareService interface {
 	GetActive() ([]dto.FilialeSoftwareDTO, error)
 	GetActiveByFiliale(filialeID uint) ([]dto.FilialeSoftwareDTO, error)
 	GetActiveBySoftware(softwareID uint) ([]dto.FilialeSoftwareDTO, error)
-	Update(id uint, req dto.UpdateFilialeSoftwareRequest) (*dto.FilialeSoftwareDTO, error)
+	Update(id uint, req dto.UpdateFilialeSoftwareRequest, userID uint) (*dto.FilialeSoftwareDTO, error)
 	Delete(id uint) error
+	Plan(filialeID uint, req dto.PlanFilialeSoftwareRequest, userID uint) (*dto.FilialeSoftwareDTO, error)
+	Start(id uint, req dto.DeploymentTransitionRequest, userID uint) (*dto.FilialeSoftwareDTO, error)
+	Confirm(id uint, req dto.DeploymentTransitionRequest, userID uint) (*dto.FilialeSoftwareDTO, error)
+	Rollback(id uint, req dto.DeploymentTransitionRequest, userID uint) (*dto.FilialeSoftwareDTO, error)
+	GetHistory(id uint) ([]dto.FilialeSoftwareHistoryDTO, error)
 }
 
 // filialeSoftwareService implémente FilialeSoftwareService
@@ -44,8 +52,8 @@ func NewFilialeSoftwareService(
 	}
 }
 
-// Create crée un nouveau déploiement
-func (s *filialeSoftwareService) Create(req dto.CreateFilialeSoftwareRequest) (*dto.FilialeSoftwareDTO, error) {
+// Create enregistre un déploiement effectué : il devient la version en service de son environnement
+func (s *filialeSoftwareService) Create(req dto.CreateFilialeSoftwareRequest, userID uint) (*dto.FilialeSoftwareDTO, error) {
 	// Vérifier que FilialeID est fourni
 	if req.FilialeID == 0 {
 		return nil, errors.New("filiale_id est obligatoire")
@@ -54,13 +62,13 @@ func (s *filialeSoftwareService) Create(req dto.CreateFilialeSoftwareRequest) (*
 	// Vérifier que la filiale existe
 	_, err := s.filialeRepo.FindByID(req.FilialeID)
 	if err != nil {
-		return nil, errors.New("filiale introuvable")
+		return nil, utils.ErrFilialeNotFound
 	}
 
 	// Vérifier que le logiciel existe
-	_, err = s.softwareRepo.FindByID(req.SoftwareID)
+	software, err := s.softwareRepo.FindByID(req.SoftwareID)
 	if err != nil {
-		return nil, errors.New("logiciel introuvable")
+		return nil, utils.ErrSoftwareNotFound
 	}
 
 	deployedAt := req.DeployedAt
@@ -70,15 +78,30 @@ func (s *filialeSoftwareService) Create(req dto.CreateFilialeSoftwareRequest) (*
 	}
 
 	deployment := &models.FilialeSoftware{
-		FilialeID:  req.FilialeID,
-		SoftwareID: req.SoftwareID,
-		Version:    "", // Non utilisé - la version du logiciel est dans la table Software
-		DeployedAt: deployedAt,
-		IsActive:   true,
-		Notes:      req.Notes,
-	}
-
-	if err := s.deploymentRepo.Create(deployment); err != nil {
+		FilialeID:    req.FilialeID,
+		SoftwareID:   req.SoftwareID,
+		Version:      deploymentVersion(req.Version, software),
+		Environment:  deploymentEnvironment(req.Environment),
+		Status:       models.DeploymentStatusDone,
+		DeployedAt:   deployedAt,
+		DeployedByID: &userID,
+		IsActive:     true,
+		Notes:        req.Notes,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(deployment).Error; err != nil {
+			return err
+		}
+		if err := recordDeploymentHistory(tx, deployment, userID, "created", "", models.DeploymentStatusDone, ""); err != nil {
+			return err
+		}
+		if err := supersedeDeployments(tx, deployment, userID); err != nil {
+			return err
+		}
+		return syncEnvironmentVersion(tx, deployment)
+	})
+	if err != nil {
 		return nil, errors.New("erreur lors de la création du déploiement")
 	}
 
@@ -202,14 +225,23 @@ func (s *filialeSoftwareService) GetActiveBySoftware(softwareID uint) ([]dto.Fil
 }
 
 // Update met à jour un déploiement
-func (s *filialeSoftwareService) Update(id uint, req dto.UpdateFilialeSoftwareRequest) (*dto.FilialeSoftwareDTO, error) {
+// La version n'est modifiable qu'avant la confirmation ; le statut évolue via Start, Confirm et Rollback
+func (s *filialeSoftwareService) Update(id uint, req dto.UpdateFilialeSoftwareRequest, userID uint) (*dto.FilialeSoftwareDTO, error) {
 	deployment, err := s.deploymentRepo.FindByID(id)
 	if err != nil {
 		return nil, errors.New("déploiement introuvable")
 	}
 
 	// Mettre à jour les champs fournis
-	// Note: Version (numéro de déploiement) n'est pas modifiable car calculé automatiquement lors de la création
+	if req.Version != "" && req.Version != deployment.Version {
+		if deployment.Status != models.DeploymentStatusPlanned && deployment.Status != models.DeploymentStatusInProgress {
+			return nil, errors.New("la version d'un déploiement confirmé ou annulé n'est pas modifiable")
+		}
+		deployment.Version = req.Version
+	}
+	if req.PlannedAt != nil {
+		deployment.PlannedAt = req.PlannedAt
+	}
 	if req.DeployedAt != nil {
 		deployment.DeployedAt = req.DeployedAt
 	}
@@ -220,7 +252,13 @@ func (s *filialeSoftwareService) Update(id uint, req dto.UpdateFilialeSoftwareRe
 		deployment.Notes = req.Notes
 	}
 
-	if err := s.deploymentRepo.Update(deployment); err != nil {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Filiale", "Software").Save(deployment).Error; err != nil {
+			return err
+		}
+		return recordDeploymentHistory(tx, deployment, userID, "updated", "", "", "")
+	})
+	if err != nil {
 		return nil, errors.New("erreur lors de la mise à jour du déploiement")
 	}
 
@@ -247,18 +285,260 @@ func (s *filialeSoftwareService) Delete(id uint) error {
 	return nil
 }
 
+// Plan planifie le déploiement d'un logiciel chez une filiale (inactif jusqu'à sa confirmation)
+func (s *filialeSoftwareService) Plan(filialeID uint, req dto.PlanFilialeSoftwareRequest, userID uint) (*dto.FilialeSoftwareDTO, error) {
+	if _, err := s.filialeRepo.FindByID(filialeID); err != nil {
+		return nil, utils.ErrFilialeNotFound
+	}
+	software, err := s.softwareRepo.FindByID(req.SoftwareID)
+	if err != nil {
+		return nil, utils.ErrSoftwareNotFound
+	}
+
+	deployment := &models.FilialeSoftware{
+		FilialeID:   filialeID,
+		SoftwareID:  req.SoftwareID,
+		Version:     deploymentVersion(req.Version, software),
+		Environment: deploymentEnvironment(req.Environment),
+		Status:      models.DeploymentStatusPlanned,
+		PlannedAt:   req.PlannedAt,
+		Notes:       req.Notes,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(deployment).Error; err != nil {
+			return err
+		}
+		// is_active a une valeur par défaut : la valeur false est ignorée à l'insertion
+		if err := tx.Model(deployment).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return recordDeploymentHistory(tx, deployment, userID, "planned", "", models.DeploymentStatusPlanned, "")
+	})
+	if err != nil {
+		return nil, errors.New("erreur lors de la planification du déploiement")
+	}
+
+	created, err := s.deploymentRepo.FindByID(deployment.ID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération du déploiement créé")
+	}
+	return s.deploymentToDTO(created), nil
+}
+
+// Start passe un déploiement planifié en cours
+func (s *filialeSoftwareService) Start(id uint, req dto.DeploymentTransitionRequest, userID uint) (*dto.FilialeSoftwareDTO, error) {
+	return s.transition(id, userID, "started", models.DeploymentStatusInProgress, req.Notes,
+		[]string{models.DeploymentStatusPlanned},
+		func(tx *gorm.DB, deployment *models.FilialeSoftware) error {
+			if req.Version != "" {
+				deployment.Version = req.Version
+			}
+			return nil
+		})
+}
+
+// Confirm confirme un déploiement planifié ou en cours : il devient la version en service de son environnement
+// et remplace le déploiement précédemment actif
+func (s *filialeSoftwareService) Confirm(id uint, req dto.DeploymentTransitionRequest, userID uint) (*dto.FilialeSoftwareDTO, error) {
+	return s.transition(id, userID, "confirmed", models.DeploymentStatusDone, req.Notes,
+		[]string{models.DeploymentStatusPlanned, models.DeploymentStatusInProgress},
+		func(tx *gorm.DB, deployment *models.FilialeSoftware) error {
+			if req.Version != "" {
+				deployment.Version = req.Version
+			}
+			deployedAt := time.Now()
+			if req.DeployedAt != nil {
+				deployedAt = *req.DeployedAt
+			}
+			deployment.DeployedAt = &deployedAt
+			deployment.DeployedByID = &userID
+			deployment.IsActive = true
+			if err := supersedeDeployments(tx, deployment, userID); err != nil {
+				return err
+			}
+			return syncEnvironmentVersion(tx, deployment)
+		})
+}
+
+// Rollback annule un déploiement ; s'il était en service, la version qu'il avait remplacée est remise en service
+func (s *filialeSoftwareService) Rollback(id uint, req dto.DeploymentTransitionRequest, userID uint) (*dto.FilialeSoftwareDTO, error) {
+	return s.transition(id, userID, "rolled_back", models.DeploymentStatusRolledBack, req.Notes,
+		[]string{models.DeploymentStatusPlanned, models.DeploymentStatusInProgress, models.DeploymentStatusDone},
+		func(tx *gorm.DB, deployment *models.FilialeSoftware) error {
+			wasActive := deployment.Status == models.DeploymentStatusDone && deployment.IsActive
+			deployment.IsActive = false
+			if !wasActive {
+				return nil
+			}
+
+			// Remettre en service le déploiement effectué le plus récent parmi ceux remplacés
+			var previous models.FilialeSoftware
+			err := tx.Where("filiale_id = ? AND software_id = ? AND environment = ? AND status = ? AND id <> ?",
+				deployment.FilialeID, deployment.SoftwareID, deployment.Environment, models.DeploymentStatusDone, deployment.ID).
+				Order("deployed_at DESC, id DESC").
+				First(&previous).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&previous).Update("is_active", true).Error; err != nil {
+				return err
+			}
+			if err := syncEnvironmentVersion(tx, &previous); err != nil {
+				return err
+			}
+			return recordDeploymentHistory(tx, &previous, userID, "restored", "", "", "Remis en service après le retour arrière du déploiement de la version "+deployment.Version)
+		})
+}
+
+// GetHistory récupère l'historique d'un déploiement
+func (s *filialeSoftwareService) GetHistory(id uint) ([]dto.FilialeSoftwareHistoryDTO, error) {
+	if _, err := s.deploymentRepo.FindByID(id); err != nil {
+		return nil, errors.New("déploiement introuvable")
+	}
+	history, err := s.deploymentRepo.FindHistory(id)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'historique")
+	}
+
+	historyDTOs := make([]dto.FilialeSoftwareHistoryDTO, 0, len(history))
+	for _, entry := range history {
+		entryDTO := dto.FilialeSoftwareHistoryDTO{
+			ID:         entry.ID,
+			Action:     entry.Action,
+			FromStatus: entry.FromStatus,
+			ToStatus:   entry.ToStatus,
+			Version:    entry.Version,
+			Notes:      entry.Notes,
+			CreatedAt:  entry.CreatedAt,
+		}
+		if entry.User != nil {
+			entryDTO.User = shareUserToDTO(entry.User)
+		}
+		historyDTOs = append(historyDTOs, entryDTO)
+	}
+	return historyDTOs, nil
+}
+
+// transition fait passer un déploiement d'un des statuts from au statut to et consigne l'étape dans l'historique
+func (s *filialeSoftwareService) transition(id, userID uint, action, to, notes string, from []string, apply func(tx *gorm.DB, deployment *models.FilialeSoftware) error) (*dto.FilialeSoftwareDTO, error) {
+	deployment, err := s.deploymentRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("déploiement introuvable")
+	}
+	allowed := false
+	for _, status := range from {
+		if deployment.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, errors.New("transition impossible depuis le statut " + deployment.Status)
+	}
+
+	fromStatus := deployment.Status
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := apply(tx, deployment); err != nil {
+			return err
+		}
+		deployment.Status = to
+		if err := tx.Omit("Filiale", "Software").Save(deployment).Error; err != nil {
+			return err
+		}
+		return recordDeploymentHistory(tx, deployment, userID, action, fromStatus, to, notes)
+	})
+	if err != nil {
+		return nil, errors.New("erreur lors de la mise à jour du déploiement")
+	}
+
+	updated, err := s.deploymentRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération du déploiement mis à jour")
+	}
+	return s.deploymentToDTO(updated), nil
+}
+
+// supersedeDeployments désactive les autres déploiements en service du même logiciel, chez la même filiale et
+// dans le même environnement, et retient la version remplacée
+func supersedeDeployments(tx *gorm.DB, deployment *models.FilialeSoftware, userID uint) error {
+	var current []models.FilialeSoftware
+	err := tx.Where("filiale_id = ? AND software_id = ? AND environment = ? AND status = ? AND is_active = ? AND id <> ?",
+		deployment.FilialeID, deployment.SoftwareID, deployment.Environment, models.DeploymentStatusDone, true, deployment.ID).
+		Order("deployed_at DESC, id DESC").
+		Find(&current).Error
+	if err != nil {
+		return err
+	}
+	for i := range current {
+		if i == 0 && current[i].Version != deployment.Version {
+			deployment.PreviousVersion = current[i].Version
+		}
+		if err := tx.Model(&current[i]).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		if err := recordDeploymentHistory(tx, &current[i], userID, "superseded", "", "", "Remplacé par la version "+deployment.Version); err != nil {
+			return err
+		}
+	}
+	if deployment.PreviousVersion != "" {
+		return tx.Model(deployment).Update("previous_version", deployment.PreviousVersion).Error
+	}
+	return nil
+}
+
+// recordDeploymentHistory consigne une étape dans l'historique d'un déploiement
+func recordDeploymentHistory(tx *gorm.DB, deployment *models.FilialeSoftware, userID uint, action, from, to, notes string) error {
+	entry := &models.FilialeSoftwareHistory{
+		FilialeSoftwareID: deployment.ID,
+		Action:            action,
+		FromStatus:        from,
+		ToStatus:          to,
+		Version:           deployment.Version,
+		Notes:             notes,
+	}
+	if userID != 0 {
+		entry.UserID = &userID
+	}
+	return tx.Create(entry).Error
+}
+
+// deploymentVersion retourne la version demandée, ou la version courante du logiciel
+func deploymentVersion(version string, software *models.Software) string {
+	if version != "" {
+		return version
+	}
+	return software.Version
+}
+
+// deploymentEnvironment retourne l'environnement demandé (production par défaut)
+func deploymentEnvironment(environment string) string {
+	if environment == "" {
+		return models.DeploymentEnvironmentProduction
+	}
+	return environment
+}
+
 // deploymentToDTO convertit un modèle FilialeSoftware en DTO
 func (s *filialeSoftwareService) deploymentToDTO(deployment *models.FilialeSoftware) *dto.FilialeSoftwareDTO {
 	deploymentDTO := &dto.FilialeSoftwareDTO{
-		ID:         deployment.ID,
-		FilialeID:  deployment.FilialeID,
-		SoftwareID: deployment.SoftwareID,
-		Version:    deployment.Version,
-		DeployedAt: deployment.DeployedAt,
-		IsActive:   deployment.IsActive,
-		Notes:      deployment.Notes,
-		CreatedAt:  deployment.CreatedAt,
-		UpdatedAt:  deployment.UpdatedAt,
+		ID:              deployment.ID,
+		FilialeID:       deployment.FilialeID,
+		SoftwareID:      deployment.SoftwareID,
+		Version:         deployment.Version,
+		PreviousVersion: deployment.PreviousVersion,
+		Environment:     deployment.Environment,
+		Status:          deployment.Status,
+		PlannedAt:       deployment.PlannedAt,
+		DeployedAt:      deployment.DeployedAt,
+		DeployedByID:    deployment.DeployedByID,
+		IsActive:        deployment.IsActive,
+		Notes:           deployment.Notes,
+		CreatedAt:       deployment.CreatedAt,
+		UpdatedAt:       deployment.UpdatedAt,
 	}
 
 	// Inclure la filiale si présente
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"github.com/mcicare/itsm-backend/internal/vcs"
+)
+
+// GitIntegrationService interface pour le rattachement des commits et merge requests GitHub / GitLab aux tickets
+type GitIntegrationService interface {
+	Enabled() bool
+	VerifyWebhook(provider, token, signature string, body []byte) bool
+	HandleWebhook(provider, event string, body []byte) (*dto.GitWebhookResultDTO, error)
+	GetTicketLinks(ticketID uint) ([]dto.TicketGitLinkDTO, error)
+}
+
+// gitIntegrationService implémente GitIntegrationService
+type gitIntegrationService struct {
+	cfg         config.GitConfig
+	linkRepo    repositories.GitLinkRepository
+	ticketRepo  repositories.TicketRepository
+	historyRepo repositories.TicketHistoryRepository
+	userRepo    repositories.UserRepository
+	ticketSvc   TicketService
+	mu          sync.Mutex // Sérialise les webhooks : push et merge request d'un même commit arrivent souvent ensemble
+}
+
+// NewGitIntegrationService crée une nouvelle instance de GitIntegrationService
+func NewGitIntegrationService(
+	cfg config.GitConfig,
+	linkRepo repositories.GitLinkRepository,
+	ticketRepo repositories.TicketRepository,
+	historyRepo repositories.TicketHistoryRepository,
+	userRepo repositories.UserRepository,
+	ticketSvc TicketService,
+) GitIntegrationService {
+	return &gitIntegrationService{
+		cfg:         cfg,
+		linkRepo:    linkRepo,
+		ticketRepo:  ticketRepo,
+		historyRepo: historyRepo,
+		userRepo:    userRepo,
+		ticketSvc:   ticketSvc,
+	}
+}
+
+// Enabled indique si l'intégration Git est configurée
+func (s *gitIntegrationService) Enabled() bool {
+	return s.cfg.Enabled()
+}
+
+// VerifyWebhook authentifie un webhook : jeton X-Gitlab-Token pour GitLab,
+// signature X-Hub-Signature-256 (sha256=HMAC du corps) pour GitHub
+func (s *gitIntegrationService) VerifyWebhook(provider, token, signature string, body []byte) bool {
+	if s.cfg.WebhookSecret == "" {
+		return false
+	}
+	switch provider {
+	case vcs.ProviderGitLab:
+		return subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.WebhookSecret)) == 1
+	case vcs.ProviderGitHub:
+		sig, ok := strings.CutPrefix(signature, "sha256=")
+		if !ok {
+			return false
+		}
+		expected, err := hex.DecodeString(sig)
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(s.cfg.WebhookSecret))
+		mac.Write(body)
+		return hmac.Equal(expected, mac.Sum(nil))
+	}
+	return false
+}
+
+// HandleWebhook lie aux tickets cités les commits et merge requests d'un événement
+func (s *gitIntegrationService) HandleWebhook(provider, event string, body []byte) (*dto.GitWebhookResultDTO, error) {
+	if !s.Enabled() {
+		return nil, errors.New("intégration Git non configurée")
+	}
+	refs, err := vcs.Parse(provider, event, body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &dto.GitWebhookResultDTO{Received: len(refs)}
+	unknown := map[string]bool{}
+	for _, ref := range refs {
+		if ref.Repository == "" || ref.Ref == "" {
+			continue
+		}
+		for _, code := range ref.TicketCodes() {
+			ticket, err := s.linkRepo.FindTicketByCode(code)
+			if err != nil {
+				if !unknown[code] {
+					unknown[code] = true
+					result.Unknown = append(result.Unknown, code)
+				}
+				continue
+			}
+			linked, merged, err := s.applyReference(provider, ref, ticket)
+			if err != nil {
+				return nil, err
+			}
+			if linked {
+				result.Linked++
+			}
+			if merged {
+				result.Merged++
+			}
+		}
+	}
+	return result, nil
+}
+
+// applyReference crée ou met à jour le lien d'un ticket vers un commit ou une merge request
+// et l'inscrit dans l'historique du ticket ; retourne si le lien est nouveau et si la merge request vient d'être fusionnée
+func (s *gitIntegrationService) applyReference(provider string, ref vcs.Reference, ticket *models.Ticket) (bool, bool, error) {
+	actorID := s.gitActorID(ref, ticket)
+	title := truncateRunes(ref.Summary(), 500)
+
+	link, err := s.linkRepo.Find(ticket.ID, provider, ref.Repository, ref.Kind, ref.Ref)
+	if err != nil {
+		link = &models.TicketGitLink{
+			TicketID:   ticket.ID,
+			Provider:   provider,
+			Repository: ref.Repository,
+			Kind:       ref.Kind,
+			Ref:        ref.Ref,
+			Title:      title,
+			URL:        ref.URL,
+			AuthorName: ref.AuthorName,
+			State:      ref.State,
+		}
+		if err := s.linkRepo.Create(link); err != nil {
+			return false, false, errors.New("erreur lors de l'enregistrement du lien Git")
+		}
+
+		action, label := "git_commit_linked", "Commit"
+		if ref.Kind == vcs.KindMergeRequest {
+			action, label = "git_merge_request_linked", "Merge request"
+		}
+		s.addHistory(ticket.ID, actorID, action, ref.URL,
+			fmt.Sprintf("%s %s (%s) de %s : %s", label, ref.ShortRef(), ref.Repository, ref.AuthorName, title))
+
+		merged := ref.Kind == vcs.KindMergeRequest && ref.State == vcs.StateMerged
+		if merged {
+			s.onMerged(ref, ticket, actorID)
+		}
+		return true, merged, nil
+	}
+
+	if ref.Kind != vcs.KindMergeRequest || ref.State == link.State {
+		return false, false, nil
+	}
+	wasMerged := link.State == vcs.StateMerged
+	link.State = ref.State
+	link.Title = title
+	if ref.URL != "" {
+		link.URL = ref.URL
+	}
+	if err := s.linkRepo.Update(link); err != nil {
+		return false, false, errors.New("erreur lors de la mise à jour du lien Git")
+	}
+	if ref.State != vcs.StateMerged || wasMerged {
+		return false, false, nil
+	}
+	s.onMerged(ref, ticket, actorID)
+	return false, true, nil
+}
+
+// onMerged inscrit la fusion d'une merge request et, si configuré, passe le ticket en attente de validation
+func (s *gitIntegrationService) onMerged(ref vcs.Reference, ticket *models.Ticket, actorID uint) {
+	s.addHistory(ticket.ID, actorID, "git_merge_request_merged", ref.URL,
+		fmt.Sprintf("Merge request %s (%s) fusionnée", ref.ShortRef(), ref.Repository))
+
+	if !s.cfg.PendingOnMerge || (ticket.Status != "ouvert" && ticket.Status != "en_cours") {
+		return
+	}
+	if _, err := s.ticketSvc.ChangeStatus(ticket.ID, "en_attente", actorID); err != nil {
+		log.Printf("Git: impossible de passer le ticket %s en attente: %v", ticket.Code, err)
+		return
+	}
+	ticket.Status = "en_attente"
+}
+
+// addHistory ajoute une entrée à l'historique du ticket
+func (s *gitIntegrationService) addHistory(ticketID, userID uint, action, url, description string) {
+	if err := s.historyRepo.Create(&models.TicketHistory{
+		TicketID:    ticketID,
+		UserID:      userID,
+		Action:      action,
+		NewValue:    url,
+		Description: description,
+	}); err != nil {
+		log.Printf("Git: erreur lors de l'enregistrement de l'historique du ticket %d: %v", ticketID, err)
+	}
+}
+
+// gitActorID retrouve l'utilisateur correspondant à l'auteur (par e-mail),
+// à défaut le technicien assigné puis le créateur du ticket
+func (s *gitIntegrationService) gitActorID(ref vcs.Reference, ticket *models.Ticket) uint {
+	if ref.AuthorEmail != "" {
+		if user, err := s.userRepo.FindByEmail(ref.AuthorEmail); err == nil {
+			return user.ID
+		}
+	}
+	if ticket.AssignedToID != nil {
+		return *ticket.AssignedToID
+	}
+	return ticket.CreatedByID
+}
+
+// GetTicketLinks récupère les commits et merge requests liés à un ticket
+func (s *gitIntegrationService) GetTicketLinks(ticketID uint) ([]dto.TicketGitLinkDTO, error) {
+	if exists, err := s.ticketRepo.ExistsByID(ticketID); err != nil || !exists {
+		return nil, utils.ErrTicketNotFound
+	}
+	links, err := s.linkRepo.FindByTicketID(ticketID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des liens Git")
+	}
+	result := make([]dto.TicketGitLinkDTO, len(links))
+	for i, link := range links {
+		result[i] = dto.TicketGitLinkDTO{
+			ID:         link.ID,
+			Provider:   link.Provider,
+			Repository: link.Repository,
+			Kind:       link.Kind,
+			Ref:        link.Ref,
+			Title:      link.Title,
+			URL:        link.URL,
+			AuthorName: link.AuthorName,
+			State:      link.State,
+			CreatedAt:  link.CreatedAt,
+			UpdatedAt:  link.UpdatedAt,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,811 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/glpi"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Types d'éléments GLPI enregistrés dans la table de correspondance
+const (
+	glpiEntityUser     = "user"
+	glpiEntityCategory = "category"
+	glpiEntityAsset    = "asset" // ExternalID: "<type>:<id>" (ex: Computer:12)
+	glpiEntityTicket   = "ticket"
+	glpiEntityFollowup = "followup"
+	glpiEntityDocument = "document" // ExternalID: "<ticket>:<document>" (un document peut être lié à plusieurs tickets)
+)
+
+// glpiMaxDocumentSize taille maximale d'un document GLPI téléchargé
+const glpiMaxDocumentSize = 50 << 20
+
+// glpiAssetCategoryNames catégorie d'actif Kronos de chaque type de matériel GLPI
+var glpiAssetCategoryNames = map[string]string{
+	"Computer":         "Ordinateurs",
+	"Monitor":          "Écrans",
+	"Printer":          "Imprimantes",
+	"NetworkEquipment": "Équipements réseau",
+	"Peripheral":       "Périphériques",
+	"Phone":            "Téléphones",
+}
+
+// glpiTicketStatuses statut Kronos de chaque statut GLPI
+var glpiTicketStatuses = map[int]string{
+	1: "ouvert",
+	2: "en_cours",
+	3: "en_cours",
+	4: "en_attente",
+	5: "resolu",
+	6: "cloture",
+}
+
+// glpiTicketPriorities priorité Kronos de chaque priorité GLPI (1 très basse ... 6 majeure)
+var glpiTicketPriorities = map[int]string{
+	1: "low",
+	2: "low",
+	3: "medium",
+	4: "high",
+	5: "critical",
+	6: "critical",
+}
+
+// glpiImportSource importe utilisateurs, catégories, matériels et tickets depuis l'API REST de GLPI
+// Les tickets sont créés directement (sans notification, SLA ni événement) : il s'agit d'historique
+type glpiImportSource struct {
+	svc    *importService
+	client *glpi.Client
+	run    *models.ImportRun
+	opts   dto.GLPIImportOptions
+
+	// Caches valables le temps d'un lot
+	categorySlugs  map[uint]string
+	categoryBySlug map[string]*uint
+	usersByLogin   map[string]*uint
+}
+
+// newGLPIImportSource crée le lecteur GLPI d'un import
+func newGLPIImportSource(svc *importService, run *models.ImportRun) (importSource, error) {
+	if !config.AppConfig.GLPI.Enabled() {
+		return nil, errors.New("import GLPI non configuré (GLPI_URL)")
+	}
+	var opts dto.GLPIImportOptions
+	if err := json.Unmarshal(run.Options, &opts); err != nil {
+		return nil, fmt.Errorf("options d'import invalides: %w", err)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = importDefaultBatchSize
+	}
+	return &glpiImportSource{
+		svc:            svc,
+		client:         glpi.NewClient(config.AppConfig.GLPI),
+		run:            run,
+		opts:           opts,
+		categorySlugs:  map[uint]string{},
+		categoryBySlug: map[string]*uint{},
+		usersByLogin:   map[string]*uint{},
+	}, nil
+}
+
+// expandStages détaille l'étape des actifs par type de matériel
+func (g *glpiImportSource) expandStages(stages []string) []string {
+	expanded := make([]string, 0, len(stages)+len(glpi.AssetItemTypes))
+	for _, stage := range stages {
+		if stage != dto.ImportStageAssets {
+			expanded = append(expanded, stage)
+			continue
+		}
+		for _, itemType := range glpi.AssetItemTypes {
+			expanded = append(expanded, dto.ImportStageAssets+":"+itemType)
+		}
+	}
+	return expanded
+}
+
+// close ferme la session GLPI
+func (g *glpiImportSource) close(ctx context.Context) {
+	g.client.Close(ctx)
+}
+
+// importBatch importe un lot de l'étape demandée
+func (g *glpiImportSource) importBatch(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	name, itemType, _ := strings.Cut(stage, ":")
+	switch name {
+	case dto.ImportStageUsers:
+		return g.importUsers(ctx, progress, stage, offset)
+	case dto.ImportStageCategories:
+		return g.importCategories(ctx, progress, stage, offset)
+	case dto.ImportStageAssets:
+		return g.importAssets(ctx, progress, stage, itemType, offset)
+	case dto.ImportStageTickets:
+		return g.importTickets(ctx, progress, stage, offset)
+	default:
+		return 0, 0, false, fmt.Errorf("étape d'import inconnue: %s", stage)
+	}
+}
+
+// batchDone indique si la dernière page de l'étape a été lue
+func (g *glpiImportSource) batchDone(read, total, offset int) bool {
+	return read < g.opts.BatchSize || (total > 0 && offset+read >= total)
+}
+
+// importUsers importe un lot d'utilisateurs ; un compte Kronos de même e-mail ou identifiant est réutilisé
+// Les comptes créés reçoivent un mot de passe aléatoire non communiqué (à réinitialiser par un administrateur)
+func (g *glpiImportSource) importUsers(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	var users []glpi.User
+	total, err := g.client.List(ctx, "User", offset, g.opts.BatchSize, false, &users)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	mapped, err := g.mappedIDs(glpiEntityUser, len(users), func(i int) int { return users[i].ID })
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	for _, u := range users {
+		externalID := strconv.Itoa(u.ID)
+		stats := progress.stage(stage)
+		if _, ok := mapped[externalID]; ok || u.IsDeleted == 1 || u.Name == "" {
+			stats.Skipped++
+			continue
+		}
+
+		var emails []glpi.UserEmail
+		if err := g.client.SubItems(ctx, "User", u.ID, "UserEmail", &emails); err != nil {
+			return 0, 0, false, err
+		}
+		email := ""
+		for _, e := range emails {
+			if email == "" || e.IsDefault == 1 {
+				email = strings.ToLower(strings.TrimSpace(e.Email))
+			}
+		}
+		if email == "" {
+			progress.fail(stage, externalID, errors.New("adresse e-mail manquante"))
+			continue
+		}
+
+		// Compte existant (même e-mail ou même identifiant) : simple association
+		existing, err := g.svc.userRepo.FindByEmail(email)
+		if err != nil {
+			existing, err = g.svc.userRepo.FindByUsername(u.Name)
+		}
+		if err == nil {
+			if err := g.saveMapping(database.DB, glpiEntityUser, externalID, existing.ID); err != nil {
+				progress.fail(stage, externalID, err)
+				continue
+			}
+			stats.Matched++
+			continue
+		}
+
+		if err := g.createUser(u, email, externalID); err != nil {
+			progress.fail(stage, externalID, err)
+			continue
+		}
+		stats.Created++
+	}
+	return offset + len(users), total, g.batchDone(len(users), total, offset), nil
+}
+
+// createUser crée le compte Kronos d'un utilisateur GLPI
+func (g *glpiImportSource) createUser(u glpi.User, email, externalID string) error {
+	password, err := generateInitialPassword()
+	if err != nil {
+		return errors.New("erreur lors de la génération du mot de passe")
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return errors.New("erreur lors du hashage du mot de passe")
+	}
+	phone := u.Phone
+	if phone == "" {
+		phone = u.Mobile
+	}
+	createdByID := g.run.CreatedByID
+	user := &models.User{
+		Username:     u.Name,
+		Email:        email,
+		Phone:        truncateRunes(phone, 20),
+		PasswordHash: passwordHash,
+		FirstName:    truncateRunes(u.FirstName, 100),
+		LastName:     truncateRunes(u.RealName, 100),
+		RoleID:       g.opts.RoleID,
+		FilialeID:    &g.opts.FilialeID,
+		DepartmentID: g.opts.DepartmentID,
+		IsActive:     true,
+		CreatedByID:  &createdByID,
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return fmt.Errorf("création de l'utilisateur %s: %w", u.Name, err)
+		}
+		// is_active a une valeur par défaut : la désactivation est appliquée après la création
+		if u.IsActive != 1 {
+			if err := tx.Model(user).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return g.saveMapping(tx, glpiEntityUser, externalID, user.ID)
+	})
+}
+
+// importCategories importe un lot de catégories ITIL ; une catégorie Kronos de même nom est réutilisée
+// Les catégories créées sont inactives : elles classent l'historique sans apparaître à la création de tickets
+func (g *glpiImportSource) importCategories(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	var categories []glpi.ITILCategory
+	total, err := g.client.List(ctx, "ITILCategory", offset, g.opts.BatchSize, false, &categories)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	mapped, err := g.mappedIDs(glpiEntityCategory, len(categories), func(i int) int { return categories[i].ID })
+	if err != nil {
+		return 0, 0, false, err
+	}
+	existing, err := g.svc.ticketCategoryRepo.FindAll()
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("lecture des catégories de tickets: %w", err)
+	}
+	byName := make(map[string]uint, len(existing))
+	for _, c := range existing {
+		byName[strings.ToLower(c.Name)] = c.ID
+	}
+
+	for _, c := range categories {
+		externalID := strconv.Itoa(c.ID)
+		stats := progress.stage(stage)
+		if _, ok := mapped[externalID]; ok {
+			stats.Skipped++
+			continue
+		}
+		name := strings.TrimSpace(glpi.PlainText(c.CompleteName))
+		if name == "" {
+			name = strings.TrimSpace(glpi.PlainText(c.Name))
+		}
+		name = truncateRunes(name, 100)
+		if name == "" {
+			stats.Skipped++
+			continue
+		}
+
+		if id, ok := byName[strings.ToLower(name)]; ok {
+			if err := g.saveMapping(database.DB, glpiEntityCategory, externalID, id); err != nil {
+				progress.fail(stage, externalID, err)
+				continue
+			}
+			stats.Matched++
+			continue
+		}
+
+		category := &models.TicketCategory{
+			Name:        name,
+			Slug:        glpiCategorySlug(c.ID, name),
+			Description: glpi.PlainText(c.Comment),
+			IsActive:    true,
+		}
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(category).Error; err != nil {
+				return fmt.Errorf("création de la catégorie %s: %w", name, err)
+			}
+			if err := tx.Model(category).Update("is_active", false).Error; err != nil {
+				return err
+			}
+			return g.saveMapping(tx, glpiEntityCategory, externalID, category.ID)
+		})
+		if err != nil {
+			progress.fail(stage, externalID, err)
+			continue
+		}
+		byName[strings.ToLower(name)] = category.ID
+		stats.Created++
+	}
+	return offset + len(categories), total, g.batchDone(len(categories), total, offset), nil
+}
+
+// importAssets importe un lot de matériels d'un type (liste développée : fabricant, lieu et utilisateur en clair)
+func (g *glpiImportSource) importAssets(ctx context.Context, progress *importProgress, stage, itemType string, offset int) (int, int, bool, error) {
+	categoryName, ok := glpiAssetCategoryNames[itemType]
+	if !ok {
+		return 0, 0, false, fmt.Errorf("type de matériel GLPI non pris en charge: %s", itemType)
+	}
+	var assets []glpi.Asset
+	total, err := g.client.List(ctx, itemType, offset, g.opts.BatchSize, true, &assets)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	externalIDs := make([]string, 0, len(assets))
+	for _, a := range assets {
+		externalIDs = append(externalIDs, fmt.Sprintf("%s:%d", itemType, a.ID))
+	}
+	mapped, err := g.svc.importRepo.FindLocalIDs(ImportSourceGLPI, glpiEntityAsset, externalIDs)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("lecture des correspondances: %w", err)
+	}
+	if len(assets) == 0 {
+		return offset, total, true, nil
+	}
+	categoryID, err := g.assetCategoryID(categoryName)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	for i, a := range assets {
+		externalID := externalIDs[i]
+		stats := progress.stage(stage)
+		if _, ok := mapped[externalID]; ok || a.IsDeleted == 1 || a.IsTemplate == 1 {
+			stats.Skipped++
+			continue
+		}
+
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			name = fmt.Sprintf("%s #%d", categoryName, a.ID)
+		}
+		notes := glpi.PlainText(a.Comment)
+		if a.OtherSerial != "" {
+			notes = strings.TrimSpace(fmt.Sprintf("N° d'inventaire GLPI : %s\n%s", a.OtherSerial, notes))
+		}
+		createdByID := g.run.CreatedByID
+		asset := &models.Asset{
+			Name:         truncateRunes(name, 255),
+			SerialNumber: truncateRunes(a.Serial, 100),
+			Model:        truncateRunes(a.Model(), 255),
+			Manufacturer: truncateRunes(string(a.Manufacturer), 255),
+			CategoryID:   categoryID,
+			FilialeID:    &g.opts.FilialeID,
+			Status:       models.AssetStatusInStock,
+			Location:     truncateRunes(string(a.Location), 255),
+			Notes:        notes,
+			CreatedByID:  &createdByID,
+		}
+		if userID := g.userByLogin(string(a.User)); userID != nil {
+			asset.AssignedToID = userID
+			asset.Status = models.AssetStatusAssigned
+		}
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Omit("CreatedBy").Create(asset).Error; err != nil {
+				return fmt.Errorf("création de l'actif %s: %w", name, err)
+			}
+			event := &models.AssetLifecycleEvent{
+				AssetID:       asset.ID,
+				ToStatus:      asset.Status,
+				AssignedToID:  asset.AssignedToID,
+				Comment:       "Import GLPI",
+				PerformedByID: createdByID,
+			}
+			if err := tx.Omit(clause.Associations).Create(event).Error; err != nil {
+				return fmt.Errorf("historique de l'actif %s: %w", name, err)
+			}
+			return g.saveMapping(tx, glpiEntityAsset, externalID, asset.ID)
+		})
+		if err != nil {
+			progress.fail(stage, externalID, err)
+			continue
+		}
+		stats.Created++
+	}
+	return offset + len(assets), total, g.batchDone(len(assets), total, offset), nil
+}
+
+// importTickets importe un lot de tickets avec leurs acteurs, suivis et documents
+func (g *glpiImportSource) importTickets(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	var tickets []glpi.Ticket
+	total, err := g.client.List(ctx, "Ticket", offset, g.opts.BatchSize, false, &tickets)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	mapped, err := g.mappedIDs(glpiEntityTicket, len(tickets), func(i int) int { return tickets[i].ID })
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	for _, t := range tickets {
+		externalID := strconv.Itoa(t.ID)
+		stats := progress.stage(stage)
+		if _, ok := mapped[externalID]; ok || t.IsDeleted == 1 {
+			stats.Skipped++
+			continue
+		}
+
+		var actors []glpi.TicketUser
+		if err := g.client.SubItems(ctx, "Ticket", t.ID, "Ticket_User", &actors); err != nil {
+			return 0, 0, false, err
+		}
+		var followups []glpi.Followup
+		if err := g.client.SubItems(ctx, "Ticket", t.ID, "ITILFollowup", &followups); err != nil {
+			return 0, 0, false, err
+		}
+
+		ticketID, err := g.createTicket(t, actors, followups)
+		if err != nil {
+			progress.fail(stage, externalID, err)
+			continue
+		}
+		stats.Created++
+
+		if !g.opts.SkipAttachments {
+			g.importDocuments(ctx, progress, stage, t.ID, ticketID)
+		}
+	}
+	return offset + len(tickets), total, g.batchDone(len(tickets), total, offset), nil
+}
+
+// createTicket crée un ticket GLPI avec ses assignations et ses suivis (en une transaction)
+func (g *glpiImportSource) createTicket(t glpi.Ticket, actors []glpi.TicketUser, followups []glpi.Followup) (uint, error) {
+	userIDs := make([]int, 0, len(actors)+len(followups)+1)
+	userIDs = append(userIDs, t.RecipientID.ID())
+	for _, a := range actors {
+		userIDs = append(userIDs, a.UserID)
+	}
+	for _, f := range followups {
+		userIDs = append(userIDs, f.UserID)
+	}
+	users, err := g.mappedIDs(glpiEntityUser, len(userIDs), func(i int) int { return userIDs[i] })
+	if err != nil {
+		return 0, err
+	}
+	localUser := func(glpiID int) *uint {
+		if id, ok := users[strconv.Itoa(glpiID)]; ok {
+			return &id
+		}
+		return nil
+	}
+
+	var requesterID *uint
+	requesterName := ""
+	var assigneeIDs []uint
+	for _, a := range actors {
+		switch a.Type {
+		case glpi.TicketUserRequester:
+			if requesterID == nil {
+				requesterID = localUser(a.UserID)
+			}
+			if requesterID == nil && requesterName == "" {
+				requesterName = a.AlternativeEmail
+			}
+		case glpi.TicketUserAssigned:
+			if id := localUser(a.UserID); id != nil {
+				assigneeIDs = append(assigneeIDs, *id)
+			}
+		}
+	}
+
+	createdByID := g.run.CreatedByID
+	if id := localUser(t.RecipientID.ID()); id != nil {
+		createdByID = *id
+	} else if requesterID != nil {
+		createdByID = *requesterID
+	}
+
+	categorySlug, categoryID, err := g.ticketCategory(t)
+	if err != nil {
+		return 0, err
+	}
+	status := glpiTicketStatuses[t.Status]
+	if status == "" {
+		status = "ouvert"
+	}
+	priority := glpiTicketPriorities[t.Priority]
+	if priority == "" {
+		priority = "medium"
+	}
+	source := "direct"
+	switch t.RequestTypeID.ID() {
+	case 2:
+		source = "mail"
+	case 3:
+		source = "appel"
+	}
+	title := strings.TrimSpace(glpi.PlainText(t.Name))
+	if title == "" {
+		title = fmt.Sprintf("Ticket GLPI #%d", t.ID)
+	}
+
+	ticket := &models.Ticket{
+		Code:          fmt.Sprintf("GLPI-%d", t.ID),
+		Title:         truncateRunes(title, 255),
+		Description:   glpi.PlainText(t.Content),
+		Category:      categorySlug,
+		CategoryID:    categoryID,
+		Source:        source,
+		Status:        status,
+		Priority:      priority,
+		CreatedByID:   createdByID,
+		RequesterID:   requesterID,
+		RequesterName: truncateRunes(requesterName, 255),
+		FilialeID:     &g.opts.FilialeID,
+	}
+	if len(assigneeIDs) > 0 {
+		ticket.AssignedToID = &assigneeIDs[0]
+	}
+	if t.ActionTime > 0 {
+		minutes := t.ActionTime / 60
+		ticket.ActualTime = &minutes
+	}
+	if date := glpi.ParseDate(t.Date); date != nil {
+		ticket.CreatedAt = *date
+	}
+	if date := glpi.ParseDate(t.DateMod); date != nil {
+		ticket.UpdatedAt = *date
+	}
+	if status == "cloture" {
+		ticket.ClosedAt = glpi.ParseDate(t.CloseDate)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ticket).Error; err != nil {
+			return fmt.Errorf("création du ticket: %w", err)
+		}
+		for i, userID := range assigneeIDs {
+			assignee := &models.TicketAssignee{TicketID: ticket.ID, UserID: userID, IsLead: i == 0}
+			if err := tx.Omit("Ticket", "User").Create(assignee).Error; err != nil {
+				return fmt.Errorf("assignation du ticket: %w", err)
+			}
+		}
+
+		for _, f := range followups {
+			text := glpi.PlainText(f.Content)
+			if text == "" {
+				continue
+			}
+			authorID := createdByID
+			if id := localUser(f.UserID); id != nil {
+				authorID = *id
+			} else {
+				text = "[GLPI] " + text
+			}
+			comment := &models.TicketComment{
+				TicketID:   ticket.ID,
+				UserID:     authorID,
+				Comment:    text,
+				IsInternal: f.IsPrivate == 1,
+			}
+			if date := glpi.ParseDate(f.Date); date != nil {
+				comment.CreatedAt = *date
+				comment.UpdatedAt = *date
+			}
+			if err := tx.Omit("Ticket", "User").Create(comment).Error; err != nil {
+				return fmt.Errorf("création du suivi %d: %w", f.ID, err)
+			}
+			if err := g.saveMapping(tx, glpiEntityFollowup, strconv.Itoa(f.ID), comment.ID); err != nil {
+				return err
+			}
+		}
+
+		history := &models.TicketHistory{
+			TicketID:    ticket.ID,
+			UserID:      g.run.CreatedByID,
+			Action:      "created",
+			NewValue:    fmt.Sprintf("Importé depuis GLPI (ticket #%d)", t.ID),
+			Description: fmt.Sprintf("Import GLPI n°%d", g.run.ID),
+		}
+		if err := tx.Omit("Ticket", "User").Create(history).Error; err != nil {
+			return err
+		}
+		return g.saveMapping(tx, glpiEntityTicket, strconv.Itoa(t.ID), ticket.ID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ticket.ID, nil
+}
+
+// importDocuments télécharge les documents d'un ticket GLPI et les enregistre comme pièces jointes
+// Un document en échec est signalé dans les erreurs de l'import sans remettre en cause le ticket
+func (g *glpiImportSource) importDocuments(ctx context.Context, progress *importProgress, stage string, glpiTicketID int, ticketID uint) {
+	var items []glpi.DocumentItem
+	if err := g.client.SubItems(ctx, "Ticket", glpiTicketID, "Document_Item", &items); err != nil {
+		progress.warn(stage, strconv.Itoa(glpiTicketID), fmt.Errorf("documents: %w", err))
+		return
+	}
+
+	for _, item := range items {
+		externalID := fmt.Sprintf("%d:%d", glpiTicketID, item.DocumentID)
+		if _, found, err := g.svc.importRepo.FindLocalID(ImportSourceGLPI, glpiEntityDocument, externalID); err != nil || found {
+			continue
+		}
+		if err := g.importDocument(ctx, item.DocumentID, ticketID, externalID); err != nil {
+			progress.warn(stage, strconv.Itoa(glpiTicketID), fmt.Errorf("document %d: %w", item.DocumentID, err))
+		}
+	}
+}
+
+// importDocument télécharge un document et crée la pièce jointe correspondante
+func (g *glpiImportSource) importDocument(ctx context.Context, documentID int, ticketID uint, externalID string) error {
+	var document glpi.Document
+	if err := g.client.Get(ctx, "Document", documentID, &document); err != nil {
+		return err
+	}
+	body, err := g.client.DownloadDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	content, err := io.ReadAll(io.LimitReader(body, glpiMaxDocumentSize+1))
+	if err != nil {
+		return err
+	}
+	if len(content) > glpiMaxDocumentSize {
+		return errors.New("document trop volumineux")
+	}
+
+	fileName := document.Filename
+	if fileName == "" {
+		fileName = fmt.Sprintf("document_%d", documentID)
+	}
+	key := fmt.Sprintf("ticket_%d/glpi_%d_%s", ticketID, documentID, fileName)
+	if err := g.svc.attachmentStorage.Put(ctx, key, bytes.NewReader(content), int64(len(content)), document.Mime); err != nil {
+		return fmt.Errorf("enregistrement du fichier: %w", err)
+	}
+
+	size := len(content)
+	isImage := strings.HasPrefix(document.Mime, "image/")
+	attachment := &models.TicketAttachment{
+		TicketID: ticketID,
+		UserID:   g.run.CreatedByID,
+		FileName: truncateRunes(fileName, 255),
+		FilePath: key,
+		FileSize: &size,
+		MimeType: truncateRunes(document.Mime, 100),
+		IsImage:  isImage,
+	}
+	if isImage {
+		attachment.ThumbnailPath = key
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Ticket", "User").Create(attachment).Error; err != nil {
+			return err
+		}
+		return g.saveMapping(tx, glpiEntityDocument, externalID, attachment.ID)
+	})
+	if err != nil {
+		_ = g.svc.attachmentStorage.Delete(ctx, key)
+		return fmt.Errorf("création de la pièce jointe: %w", err)
+	}
+	return nil
+}
+
+// ticketCategory retourne la catégorie d'un ticket : catégorie ITIL importée, sinon incident ou demande selon le type
+func (g *glpiImportSource) ticketCategory(t glpi.Ticket) (string, *uint, error) {
+	if glpiCategoryID := t.CategoryID.ID(); glpiCategoryID != 0 {
+		localID, found, err := g.svc.importRepo.FindLocalID(ImportSourceGLPI, glpiEntityCategory, strconv.Itoa(glpiCategoryID))
+		if err != nil {
+			return "", nil, err
+		}
+		if found {
+			slug, ok := g.categorySlugs[localID]
+			if !ok {
+				category, err := g.svc.ticketCategoryRepo.FindByID(localID)
+				if err == nil {
+					slug = category.Slug
+				}
+				g.categorySlugs[localID] = slug
+			}
+			if slug != "" {
+				return slug, &localID, nil
+			}
+		}
+	}
+
+	slug := "incident"
+	if t.Type == 2 {
+		slug = "demande"
+	}
+	categoryID, ok := g.categoryBySlug[slug]
+	if !ok {
+		if category, err := g.svc.ticketCategoryRepo.FindBySlug(slug); err == nil {
+			categoryID = &category.ID
+		}
+		g.categoryBySlug[slug] = categoryID
+	}
+	return slug, categoryID, nil
+}
+
+// assetCategoryID retrouve (ou crée) la catégorie d'actif d'un type de matériel
+func (g *glpiImportSource) assetCategoryID(name string) (uint, error) {
+	categories, err := g.svc.assetCategoryRepo.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("lecture des catégories d'actifs: %w", err)
+	}
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, name) {
+			return c.ID, nil
+		}
+	}
+	category := &models.AssetCategory{Name: name, Description: "Catégorie créée par l'import GLPI"}
+	if err := g.svc.assetCategoryRepo.Create(category); err != nil {
+		return 0, fmt.Errorf("création de la catégorie d'actif %s: %w", name, err)
+	}
+	return category.ID, nil
+}
+
+// userByLogin retrouve un utilisateur Kronos par l'identifiant de connexion GLPI
+func (g *glpiImportSource) userByLogin(login string) *uint {
+	if login == "" {
+		return nil
+	}
+	if id, ok := g.usersByLogin[login]; ok {
+		return id
+	}
+	var id *uint
+	if user, err := g.svc.userRepo.FindByUsername(login); err == nil {
+		id = &user.ID
+	}
+	g.usersByLogin[login] = id
+	return id
+}
+
+// mappedIDs retourne les correspondances déjà enregistrées pour n identifiants GLPI
+func (g *glpiImportSource) mappedIDs(entityType string, n int, id func(i int) int) (map[string]uint, error) {
+	externalIDs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if v := id(i); v != 0 {
+			externalIDs = append(externalIDs, strconv.Itoa(v))
+		}
+	}
+	mapped, err := g.svc.importRepo.FindLocalIDs(ImportSourceGLPI, entityType, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("lecture des correspondances: %w", err)
+	}
+	return mapped, nil
+}
+
+// saveMapping enregistre la correspondance d'un élément GLPI (dans la transaction de création)
+func (g *glpiImportSource) saveMapping(tx *gorm.DB, entityType, externalID string, localID uint) error {
+	runID := g.run.ID
+	return tx.Create(&models.ImportMapping{
+		Source:      ImportSourceGLPI,
+		EntityType:  entityType,
+		ExternalID:  externalID,
+		LocalID:     localID,
+		ImportRunID: &runID,
+	}).Error
+}
+
+// glpiCategorySlug construit le slug unique d'une catégorie importée (50 caractères au plus)
+func glpiCategorySlug(id int, name string) string {
+	replacer := strings.NewReplacer(
+		"à", "a", "â", "a", "ä", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+		"î", "i", "ï", "i", "ô", "o", "ö", "o", "ù", "u", "û", "u", "ü", "u", "ÿ", "y",
+	)
+	var sb strings.Builder
+	dash := false
+	for _, r := range replacer.Replace(strings.ToLower(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := fmt.Sprintf("glpi-%d-%s", id, strings.TrimRight(sb.String(), "-"))
+	if len(slug) > 50 {
+		slug = strings.TrimRight(slug[:50], "-")
+	}
+	return strings.TrimRight(slug, "-")
+}
+
+// truncateRunes tronque une chaîne à max caractères
+func truncateRunes(value string, max int) string {
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+	return string([]rune(value)[:max])
+}
@@ -0,0 +1,551 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/helpdesk"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Types d'éléments d'un outil de support enregistrés dans la table de correspondance
+const (
+	helpdeskEntityContact = "contact"
+	helpdeskEntityTicket  = "ticket"
+	helpdeskEntityComment = "comment"
+	helpdeskEntityArticle = "article"
+)
+
+// helpdeskRequiredColumns colonnes obligatoires du fichier de chaque étape
+var helpdeskRequiredColumns = map[string][]string{
+	dto.ImportStageContacts: {"email"},
+	dto.ImportStageTickets:  {"subject"},
+	dto.ImportStageArticles: {"title", "body"},
+}
+
+// Valeurs Kronos autorisées dans les correspondances
+var (
+	helpdeskPriorities = []string{"low", "medium", "high", "critical"}
+	helpdeskStatuses   = []string{"ouvert", "en_cours", "en_attente", "resolu", "cloture"}
+)
+
+// Correspondances par défaut (valeurs Zendesk, Freshdesk et Kronos), complétées par celles de l'import
+var (
+	helpdeskDefaultPriorities = map[string]string{
+		"low": "low", "normal": "medium", "medium": "medium", "high": "high", "urgent": "critical", "critical": "critical",
+		"basse": "low", "moyenne": "medium", "haute": "high", "critique": "critical",
+	}
+	helpdeskDefaultStatuses = map[string]string{
+		"new": "ouvert", "open": "ouvert", "pending": "en_attente", "hold": "en_attente", "on-hold": "en_attente",
+		"solved": "resolu", "resolved": "resolu", "closed": "cloture",
+		"ouvert": "ouvert", "en_cours": "en_cours", "en_attente": "en_attente", "resolu": "resolu", "cloture": "cloture",
+	}
+	helpdeskChannels = map[string]string{
+		"email": "mail", "mail": "mail", "outbound_email": "mail",
+		"phone": "appel", "voice": "appel", "appel": "appel",
+	}
+)
+
+// helpdeskSourceLabels libellé de chaque source dans l'historique des tickets
+var helpdeskSourceLabels = map[string]string{
+	ImportSourceZendesk:   "Zendesk",
+	ImportSourceFreshdesk: "Freshdesk",
+	ImportSourceCSV:       "fichier",
+}
+
+// helpdeskImportSource importe contacts, articles et tickets depuis un outil de support (API ou fichiers)
+// Comme pour GLPI, les tickets sont créés directement (sans notification, SLA ni événement)
+type helpdeskImportSource struct {
+	svc      *importService
+	run      *models.ImportRun
+	opts     dto.HelpdeskRunOptions
+	provider helpdesk.Provider
+	// mappingSource espace des correspondances : par filiale pour une API (une instance par filiale),
+	// par import pour des fichiers (dont les identifiants peuvent être des numéros de ligne)
+	mappingSource string
+
+	// Caches valables le temps d'un lot
+	categoryIDs         map[string]*uint
+	knowledgeCategories map[string]uint
+}
+
+// newHelpdeskImportSource crée le lecteur d'un import depuis un outil de support
+func newHelpdeskImportSource(svc *importService, run *models.ImportRun) (importSource, error) {
+	var opts dto.HelpdeskRunOptions
+	if err := json.Unmarshal(run.Options, &opts); err != nil {
+		return nil, fmt.Errorf("options d'import invalides: %w", err)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = importDefaultBatchSize
+	}
+
+	source := &helpdeskImportSource{
+		svc:                 svc,
+		run:                 run,
+		opts:                opts,
+		mappingSource:       fmt.Sprintf("%s:%d", run.Source, opts.FilialeID),
+		categoryIDs:         map[string]*uint{},
+		knowledgeCategories: map[string]uint{},
+	}
+	switch run.Source {
+	case ImportSourceZendesk:
+		source.provider = helpdesk.NewZendesk(opts.URL, opts.Email, run.Secret, opts.BatchSize)
+	case ImportSourceFreshdesk:
+		source.provider = helpdesk.NewFreshdesk(opts.URL, run.Secret, opts.BatchSize)
+	case ImportSourceCSV:
+		files, err := svc.importRepo.FindFiles(run.ID)
+		if err != nil {
+			return nil, fmt.Errorf("lecture des fichiers de l'import: %w", err)
+		}
+		rows := make(map[string][][]string, len(files))
+		for _, file := range files {
+			var fileRows [][]string
+			if err := json.Unmarshal(file.Rows, &fileRows); err != nil {
+				return nil, fmt.Errorf("fichier %s illisible: %w", file.FileName, err)
+			}
+			rows[file.Stage] = fileRows
+		}
+		source.provider = helpdesk.NewCSV(rows, opts.BatchSize)
+		source.mappingSource = fmt.Sprintf("%s:run%d", run.Source, run.ID)
+	default:
+		return nil, fmt.Errorf("source d'import inconnue: %s", run.Source)
+	}
+	return source, nil
+}
+
+// expandStages les étapes d'un outil de support ne sont pas détaillées
+func (h *helpdeskImportSource) expandStages(stages []string) []string {
+	return stages
+}
+
+// close sans effet : les API sont appelées sans session
+func (h *helpdeskImportSource) close(ctx context.Context) {}
+
+// importBatch importe un lot de l'étape demandée
+func (h *helpdeskImportSource) importBatch(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	switch stage {
+	case dto.ImportStageContacts:
+		return h.importContacts(ctx, progress, stage, offset)
+	case dto.ImportStageArticles:
+		return h.importArticles(ctx, progress, stage, offset)
+	case dto.ImportStageTickets:
+		return h.importTickets(ctx, progress, stage, offset)
+	default:
+		return 0, 0, false, fmt.Errorf("étape d'import inconnue: %s", stage)
+	}
+}
+
+// importContacts importe un lot de contacts ; un compte Kronos de même e-mail est réutilisé
+// Les comptes créés reçoivent un mot de passe aléatoire non communiqué (à réinitialiser par un administrateur)
+func (h *helpdeskImportSource) importContacts(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	page, err := h.provider.Contacts(ctx, offset)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	externalIDs := make([]string, 0, len(page.Items))
+	for _, c := range page.Items {
+		externalIDs = append(externalIDs, c.ExternalID)
+	}
+	mapped, err := h.svc.importRepo.FindLocalIDs(h.mappingSource, helpdeskEntityContact, externalIDs)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("lecture des correspondances: %w", err)
+	}
+
+	for _, c := range page.Items {
+		stats := progress.stage(stage)
+		if _, ok := mapped[c.ExternalID]; ok {
+			stats.Skipped++
+			continue
+		}
+		email := strings.ToLower(strings.TrimSpace(c.Email))
+		if email == "" {
+			progress.fail(stage, c.ExternalID, errors.New("adresse e-mail manquante"))
+			continue
+		}
+
+		if existing, err := h.svc.userRepo.FindByEmail(email); err == nil {
+			if err := h.saveMapping(database.DB, helpdeskEntityContact, c.ExternalID, existing.ID); err != nil {
+				progress.fail(stage, c.ExternalID, err)
+				continue
+			}
+			stats.Matched++
+			continue
+		}
+
+		if err := h.createContact(c, email); err != nil {
+			progress.fail(stage, c.ExternalID, err)
+			continue
+		}
+		stats.Created++
+	}
+	return page.Next, page.Total, page.Done, nil
+}
+
+// createContact crée le compte Kronos d'un contact ; l'identifiant est la partie locale de l'e-mail
+func (h *helpdeskImportSource) createContact(c helpdesk.Contact, email string) error {
+	username, _, _ := strings.Cut(email, "@")
+	if _, err := h.svc.userRepo.FindByUsername(username); err == nil {
+		username = email
+	}
+	firstName, lastName := c.FirstName, c.LastName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(strings.TrimSpace(c.Name), " ")
+	}
+
+	password, err := generateInitialPassword()
+	if err != nil {
+		return errors.New("erreur lors de la génération du mot de passe")
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return errors.New("erreur lors du hashage du mot de passe")
+	}
+	createdByID := h.run.CreatedByID
+	user := &models.User{
+		Username:     truncateRunes(username, 100),
+		Email:        email,
+		Phone:        truncateRunes(c.Phone, 20),
+		PasswordHash: passwordHash,
+		FirstName:    truncateRunes(firstName, 100),
+		LastName:     truncateRunes(strings.TrimSpace(lastName), 100),
+		RoleID:       h.opts.RoleID,
+		FilialeID:    &h.opts.FilialeID,
+		DepartmentID: h.opts.DepartmentID,
+		IsActive:     true,
+		CreatedByID:  &createdByID,
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return fmt.Errorf("création de l'utilisateur %s: %w", email, err)
+		}
+		// is_active a une valeur par défaut : la désactivation est appliquée après la création
+		if !c.Active {
+			if err := tx.Model(user).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return h.saveMapping(tx, helpdeskEntityContact, c.ExternalID, user.ID)
+	})
+}
+
+// importArticles importe un lot d'articles de base de connaissances
+// Sans catégorie imposée, chaque rubrique source devient une catégorie de la filiale (retrouvée par son nom)
+func (h *helpdeskImportSource) importArticles(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	page, err := h.provider.Articles(ctx, offset)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	externalIDs := make([]string, 0, len(page.Items))
+	for _, a := range page.Items {
+		externalIDs = append(externalIDs, a.ExternalID)
+	}
+	mapped, err := h.svc.importRepo.FindLocalIDs(h.mappingSource, helpdeskEntityArticle, externalIDs)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("lecture des correspondances: %w", err)
+	}
+
+	for _, a := range page.Items {
+		stats := progress.stage(stage)
+		if _, ok := mapped[a.ExternalID]; ok {
+			stats.Skipped++
+			continue
+		}
+		title := strings.TrimSpace(a.Title)
+		if title == "" || strings.TrimSpace(a.Body) == "" {
+			progress.fail(stage, a.ExternalID, errors.New("titre ou contenu manquant"))
+			continue
+		}
+		categoryID, err := h.knowledgeCategoryID(a.Section)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		article := &models.KnowledgeArticle{
+			Title:         truncateRunes(title, 255),
+			Content:       richtext.Sanitize(a.Body),
+			ContentFormat: richtext.FormatHTML, // Corps des articles exportés en HTML
+			CategoryID:    categoryID,
+			FilialeID:     &h.opts.FilialeID,
+			AuthorID:      h.run.CreatedByID,
+			IsPublished:   a.Published,
+		}
+		if a.CreatedAt != nil {
+			article.CreatedAt = *a.CreatedAt
+		}
+		if a.UpdatedAt != nil {
+			article.UpdatedAt = *a.UpdatedAt
+		}
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Omit("Category", "Filiale", "Author").Create(article).Error; err != nil {
+				return fmt.Errorf("création de l'article: %w", err)
+			}
+			return h.saveMapping(tx, helpdeskEntityArticle, a.ExternalID, article.ID)
+		})
+		if err != nil {
+			progress.fail(stage, a.ExternalID, err)
+			continue
+		}
+		stats.Created++
+	}
+	return page.Next, page.Total, page.Done, nil
+}
+
+// importTickets importe un lot de tickets avec leurs commentaires
+func (h *helpdeskImportSource) importTickets(ctx context.Context, progress *importProgress, stage string, offset int) (int, int, bool, error) {
+	page, err := h.provider.Tickets(ctx, offset)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	externalIDs := make([]string, 0, len(page.Items))
+	for _, t := range page.Items {
+		externalIDs = append(externalIDs, t.ExternalID)
+	}
+	mapped, err := h.svc.importRepo.FindLocalIDs(h.mappingSource, helpdeskEntityTicket, externalIDs)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("lecture des correspondances: %w", err)
+	}
+
+	for _, t := range page.Items {
+		stats := progress.stage(stage)
+		if _, ok := mapped[t.ExternalID]; ok {
+			stats.Skipped++
+			continue
+		}
+		comments, err := h.provider.Comments(ctx, t.ExternalID)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if err := h.createTicket(t, comments); err != nil {
+			progress.fail(stage, t.ExternalID, err)
+			continue
+		}
+		stats.Created++
+	}
+	return page.Next, page.Total, page.Done, nil
+}
+
+// createTicket crée un ticket avec ses commentaires (en une transaction) en appliquant les correspondances de l'import
+func (h *helpdeskImportSource) createTicket(t helpdesk.Ticket, comments []helpdesk.Comment) error {
+	contactIDs := []string{t.RequesterID, t.AssigneeID}
+	for _, c := range comments {
+		contactIDs = append(contactIDs, c.AuthorID)
+	}
+	contacts, err := h.svc.importRepo.FindLocalIDs(h.mappingSource, helpdeskEntityContact, contactIDs)
+	if err != nil {
+		return fmt.Errorf("lecture des correspondances: %w", err)
+	}
+	localUser := func(externalID, email string) *uint {
+		if id, ok := contacts[externalID]; ok && externalID != "" {
+			return &id
+		}
+		if email != "" {
+			if user, err := h.svc.userRepo.FindByEmail(strings.ToLower(email)); err == nil {
+				return &user.ID
+			}
+		}
+		return nil
+	}
+
+	categorySlug := h.ticketCategorySlug(t)
+	categoryID, err := h.ticketCategoryID(categorySlug)
+	if err != nil {
+		return err
+	}
+	status := h.mapValue(t.Status, h.opts.Mapping.Statuses, helpdeskDefaultStatuses, "ouvert")
+	source := helpdeskChannels[strings.ToLower(t.Channel)]
+	if source == "" {
+		source = "direct"
+	}
+	label := helpdeskSourceLabels[h.run.Source]
+	title := strings.TrimSpace(t.Subject)
+	if title == "" {
+		title = fmt.Sprintf("Ticket %s #%s", label, t.ExternalID)
+	}
+
+	ticket := &models.Ticket{
+		Code:        truncateRunes(h.ticketCode(t.ExternalID), 50),
+		Title:       truncateRunes(title, 255),
+		Description: t.Description,
+		Category:    categorySlug,
+		CategoryID:  categoryID,
+		Source:      source,
+		Status:      status,
+		Priority:    h.mapValue(t.Priority, h.opts.Mapping.Priorities, helpdeskDefaultPriorities, "medium"),
+		CreatedByID: h.run.CreatedByID,
+		FilialeID:   &h.opts.FilialeID,
+	}
+	if requesterID := localUser(t.RequesterID, t.RequesterEmail); requesterID != nil {
+		ticket.RequesterID = requesterID
+		ticket.CreatedByID = *requesterID
+	} else {
+		ticket.RequesterName = truncateRunes(t.RequesterEmail, 255)
+	}
+	assigneeID := localUser(t.AssigneeID, t.AssigneeEmail)
+	ticket.AssignedToID = assigneeID
+	if t.CreatedAt != nil {
+		ticket.CreatedAt = *t.CreatedAt
+	}
+	if t.UpdatedAt != nil {
+		ticket.UpdatedAt = *t.UpdatedAt
+		if status == "cloture" {
+			ticket.ClosedAt = t.UpdatedAt
+		}
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ticket).Error; err != nil {
+			return fmt.Errorf("création du ticket: %w", err)
+		}
+		if assigneeID != nil {
+			assignee := &models.TicketAssignee{TicketID: ticket.ID, UserID: *assigneeID, IsLead: true}
+			if err := tx.Omit("Ticket", "User").Create(assignee).Error; err != nil {
+				return fmt.Errorf("assignation du ticket: %w", err)
+			}
+		}
+
+		for _, c := range comments {
+			text := strings.TrimSpace(c.Body)
+			if text == "" {
+				continue
+			}
+			authorID := h.run.CreatedByID
+			if id, ok := contacts[c.AuthorID]; ok && c.AuthorID != "" {
+				authorID = id
+			} else {
+				text = fmt.Sprintf("[%s] %s", label, text)
+			}
+			comment := &models.TicketComment{
+				TicketID:   ticket.ID,
+				UserID:     authorID,
+				Comment:    text,
+				IsInternal: !c.Public,
+			}
+			if c.CreatedAt != nil {
+				comment.CreatedAt = *c.CreatedAt
+				comment.UpdatedAt = *c.CreatedAt
+			}
+			if err := tx.Omit("Ticket", "User").Create(comment).Error; err != nil {
+				return fmt.Errorf("création du commentaire %s: %w", c.ExternalID, err)
+			}
+			if err := h.saveMapping(tx, helpdeskEntityComment, c.ExternalID, comment.ID); err != nil {
+				return err
+			}
+		}
+
+		history := &models.TicketHistory{
+			TicketID:    ticket.ID,
+			UserID:      h.run.CreatedByID,
+			Action:      "created",
+			NewValue:    fmt.Sprintf("Importé depuis %s (ticket #%s)", label, t.ExternalID),
+			Description: fmt.Sprintf("Import %s n°%d", h.run.Source, h.run.ID),
+		}
+		if err := tx.Omit("Ticket", "User").Create(history).Error; err != nil {
+			return err
+		}
+		return h.saveMapping(tx, helpdeskEntityTicket, t.ExternalID, ticket.ID)
+	})
+}
+
+// ticketCode construit le code d'un ticket importé (ZD-<filiale>-<id>, FD-<filiale>-<id>, IMP-<import>-<id>)
+func (h *helpdeskImportSource) ticketCode(externalID string) string {
+	switch h.run.Source {
+	case ImportSourceZendesk:
+		return fmt.Sprintf("ZD-%d-%s", h.opts.FilialeID, externalID)
+	case ImportSourceFreshdesk:
+		return fmt.Sprintf("FD-%d-%s", h.opts.FilialeID, externalID)
+	default:
+		return fmt.Sprintf("IMP-%d-%s", h.run.ID, externalID)
+	}
+}
+
+// ticketCategorySlug retourne la catégorie d'un ticket : correspondance de son type, puis de ses étiquettes, sinon la catégorie par défaut
+func (h *helpdeskImportSource) ticketCategorySlug(t helpdesk.Ticket) string {
+	mapping := h.opts.Mapping
+	if slug, ok := mapping.Categories[strings.ToLower(strings.TrimSpace(t.Type))]; ok && t.Type != "" {
+		return slug
+	}
+	for _, tag := range t.Tags {
+		if slug, ok := mapping.Categories[strings.ToLower(tag)]; ok {
+			return slug
+		}
+	}
+	return mapping.DefaultCategory
+}
+
+// ticketCategoryID retrouve l'identifiant d'une catégorie de ticket par son slug
+func (h *helpdeskImportSource) ticketCategoryID(slug string) (*uint, error) {
+	if id, ok := h.categoryIDs[slug]; ok {
+		return id, nil
+	}
+	category, err := h.svc.ticketCategoryRepo.FindBySlug(slug)
+	if err != nil {
+		return nil, fmt.Errorf("catégorie de ticket introuvable: %s", slug)
+	}
+	h.categoryIDs[slug] = &category.ID
+	return &category.ID, nil
+}
+
+// mapValue applique la correspondance de l'import, puis celle par défaut, à une valeur source
+func (h *helpdeskImportSource) mapValue(value string, mapping, defaults map[string]string, fallback string) string {
+	key := strings.ToLower(strings.TrimSpace(value))
+	if mapped, ok := mapping[key]; ok {
+		return mapped
+	}
+	if mapped, ok := defaults[key]; ok {
+		return mapped
+	}
+	return fallback
+}
+
+// knowledgeCategoryID retourne la catégorie d'un article : catégorie imposée, sinon celle de sa rubrique (créée au besoin)
+func (h *helpdeskImportSource) knowledgeCategoryID(section string) (uint, error) {
+	if h.opts.Mapping.KnowledgeCategoryID != nil {
+		return *h.opts.Mapping.KnowledgeCategoryID, nil
+	}
+	name := truncateRunes(strings.TrimSpace(section), 255)
+	if name == "" {
+		name = "Importé depuis " + helpdeskSourceLabels[h.run.Source]
+	}
+	key := strings.ToLower(name)
+	if id, ok := h.knowledgeCategories[key]; ok {
+		return id, nil
+	}
+
+	categories, err := h.svc.knowledgeRepo.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("lecture des catégories de la base de connaissances: %w", err)
+	}
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, name) && (c.FilialeID == nil || *c.FilialeID == h.opts.FilialeID) {
+			h.knowledgeCategories[key] = c.ID
+			return c.ID, nil
+		}
+	}
+	category := &models.KnowledgeCategory{Name: name, FilialeID: &h.opts.FilialeID, IsActive: true}
+	if err := h.svc.knowledgeRepo.Create(category); err != nil {
+		return 0, fmt.Errorf("création de la catégorie %s: %w", name, err)
+	}
+	h.knowledgeCategories[key] = category.ID
+	return category.ID, nil
+}
+
+// saveMapping enregistre la correspondance d'un élément (dans la transaction de création)
+func (h *helpdeskImportSource) saveMapping(tx *gorm.DB, entityType, externalID string, localID uint) error {
+	runID := h.run.ID
+	return tx.Create(&models.ImportMapping{
+		Source:      h.mappingSource,
+		EntityType:  entityType,
+		ExternalID:  externalID,
+		LocalID:     localID,
+		ImportRunID: &runID,
+	}).Error
+}
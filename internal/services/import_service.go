@@ -0,0 +1,590 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/helpdesk"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/storage"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Sources d'import prises en charge
+const (
+	ImportSourceGLPI      = "glpi"
+	ImportSourceZendesk   = "zendesk"
+	ImportSourceFreshdesk = "freshdesk"
+	ImportSourceCSV       = "csv" // Fichiers CSV ou XLSX (export de n'importe quel outil de support)
+)
+
+// Paramètres des lots d'import
+const (
+	importDefaultBatchSize = 50
+	importMaxErrors        = 100 // Erreurs par élément conservées avec l'import (les plus récentes)
+)
+
+// Ordre de traitement des étapes de chaque type d'import (les tickets référencent utilisateurs, contacts et catégories)
+var (
+	glpiStageOrder     = []string{dto.ImportStageUsers, dto.ImportStageCategories, dto.ImportStageAssets, dto.ImportStageTickets}
+	helpdeskStageOrder = []string{dto.ImportStageContacts, dto.ImportStageArticles, dto.ImportStageTickets}
+)
+
+// importSource lit et importe les éléments d'un outil externe, lot par lot
+type importSource interface {
+	// expandStages détaille les étapes demandées en étapes de traitement (ex: assets -> assets:Computer, assets:Monitor, ...)
+	expandStages(stages []string) []string
+	// importBatch importe le lot de l'étape à partir de la position offset ; retourne la position du lot suivant
+	// (rang d'élément, numéro de page ou horodatage selon la source), le total de l'étape (0 si inconnu) et si l'étape est terminée
+	importBatch(ctx context.Context, progress *importProgress, stage string, offset int) (next int, total int, done bool, err error)
+	close(ctx context.Context)
+}
+
+// ImportService interface pour les imports de données depuis d'autres outils
+type ImportService interface {
+	StartGLPI(req dto.StartGLPIImportRequest, userID uint) (*dto.ImportRunDTO, error)
+	StartHelpdesk(req dto.StartHelpdeskImportRequest, userID uint) (*dto.ImportRunDTO, error)
+	StartHelpdeskFiles(opts dto.HelpdeskImportOptions, files []dto.HelpdeskImportFile, userID uint) (*dto.ImportRunDTO, error)
+	GetAll(source string, page, limit int) (*dto.ImportRunListResponse, error)
+	GetByID(id uint) (*dto.ImportRunDTO, error)
+	Resume(id uint) (*dto.ImportRunDTO, error)
+	Cancel(id uint) (*dto.ImportRunDTO, error)
+	// ProcessBatch traite un lot et retourne la position du lot suivant (nil si l'import est terminé ou arrêté)
+	ProcessBatch(ctx context.Context, batch jobs.ImportBatchPayload) (*jobs.ImportBatchPayload, error)
+}
+
+// importService implémente ImportService
+type importService struct {
+	importRepo         repositories.ImportRepository
+	userRepo           repositories.UserRepository
+	roleRepo           repositories.RoleRepository
+	departmentRepo     repositories.DepartmentRepository
+	filialeRepo        repositories.FilialeRepository
+	ticketCategoryRepo repositories.TicketCategoryRepository
+	assetCategoryRepo  repositories.AssetCategoryRepository
+	knowledgeRepo      repositories.KnowledgeCategoryRepository
+	attachmentStorage  storage.Storage
+	jobQueue           *jobs.Queue // Nil pour la commande glpi-import : les lots sont alors traités par l'appelant
+}
+
+// NewImportService crée une nouvelle instance de ImportService
+func NewImportService(
+	importRepo repositories.ImportRepository,
+	userRepo repositories.UserRepository,
+	roleRepo repositories.RoleRepository,
+	departmentRepo repositories.DepartmentRepository,
+	filialeRepo repositories.FilialeRepository,
+	ticketCategoryRepo repositories.TicketCategoryRepository,
+	assetCategoryRepo repositories.AssetCategoryRepository,
+	knowledgeRepo repositories.KnowledgeCategoryRepository,
+	attachmentStorage storage.Storage,
+	jobQueue *jobs.Queue,
+) ImportService {
+	return &importService{
+		importRepo:         importRepo,
+		userRepo:           userRepo,
+		roleRepo:           roleRepo,
+		departmentRepo:     departmentRepo,
+		filialeRepo:        filialeRepo,
+		ticketCategoryRepo: ticketCategoryRepo,
+		assetCategoryRepo:  assetCategoryRepo,
+		knowledgeRepo:      knowledgeRepo,
+		attachmentStorage:  attachmentStorage,
+		jobQueue:           jobQueue,
+	}
+}
+
+// StartGLPI crée un import GLPI et planifie son premier lot
+func (s *importService) StartGLPI(req dto.StartGLPIImportRequest, userID uint) (*dto.ImportRunDTO, error) {
+	if !config.AppConfig.GLPI.Enabled() {
+		return nil, errors.New("import GLPI non configuré (GLPI_URL)")
+	}
+	if err := s.validateTarget(req.FilialeID, req.RoleID, req.DepartmentID); err != nil {
+		return nil, err
+	}
+	if err := s.checkNoActiveRun(ImportSourceGLPI); err != nil {
+		return nil, err
+	}
+
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = importDefaultBatchSize
+	}
+	options, _ := json.Marshal(dto.GLPIImportOptions{
+		FilialeID:       req.FilialeID,
+		RoleID:          req.RoleID,
+		DepartmentID:    req.DepartmentID,
+		SkipAttachments: req.SkipAttachments,
+		BatchSize:       batchSize,
+	})
+	source := &glpiImportSource{}
+	stages := source.expandStages(orderedImportStages(glpiStageOrder, req.Stages))
+	stagesJSON, _ := json.Marshal(stages)
+
+	run := &models.ImportRun{
+		Source:      ImportSourceGLPI,
+		Status:      models.ImportStatusPending,
+		Options:     options,
+		Stages:      stagesJSON,
+		Stage:       stages[0],
+		CreatedByID: userID,
+	}
+	if err := s.importRepo.CreateRun(run); err != nil {
+		return nil, errors.New("erreur lors de la création de l'import")
+	}
+	if err := s.enqueue(run); err != nil {
+		return nil, err
+	}
+	return s.GetByID(run.ID)
+}
+
+// StartHelpdesk crée un import depuis l'API Zendesk ou Freshdesk et planifie son premier lot
+func (s *importService) StartHelpdesk(req dto.StartHelpdeskImportRequest, userID uint) (*dto.ImportRunDTO, error) {
+	if req.Source == ImportSourceZendesk && req.Email == "" {
+		return nil, errors.New("l'adresse e-mail de l'agent est obligatoire pour Zendesk")
+	}
+	if err := s.validateHelpdeskOptions(&req.HelpdeskImportOptions); err != nil {
+		return nil, err
+	}
+	if err := s.checkNoActiveRun(req.Source); err != nil {
+		return nil, err
+	}
+
+	options, _ := json.Marshal(dto.HelpdeskRunOptions{
+		HelpdeskImportOptions: req.HelpdeskImportOptions,
+		URL:                   strings.TrimRight(req.URL, "/"),
+		Email:                 req.Email,
+	})
+	stages := orderedImportStages(helpdeskStageOrder, req.Stages)
+	stagesJSON, _ := json.Marshal(stages)
+
+	run := &models.ImportRun{
+		Source:      req.Source,
+		Status:      models.ImportStatusPending,
+		Options:     options,
+		Stages:      stagesJSON,
+		Stage:       stages[0],
+		Secret:      req.APIToken,
+		CreatedByID: userID,
+	}
+	if err := s.importRepo.CreateRun(run); err != nil {
+		return nil, errors.New("erreur lors de la création de l'import")
+	}
+	if err := s.enqueue(run); err != nil {
+		return nil, err
+	}
+	return s.GetByID(run.ID)
+}
+
+// StartHelpdeskFiles crée un import depuis des fichiers (un par étape) et planifie son premier lot
+// Les étapes importées sont celles dont le fichier est fourni (restreintes à opts.Stages si renseigné)
+func (s *importService) StartHelpdeskFiles(opts dto.HelpdeskImportOptions, files []dto.HelpdeskImportFile, userID uint) (*dto.ImportRunDTO, error) {
+	if err := s.validateHelpdeskOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	requested := orderedImportStages(helpdeskStageOrder, opts.Stages)
+	byStage := make(map[string]dto.HelpdeskImportFile, len(files))
+	for _, file := range files {
+		if !slices.Contains(requested, file.Stage) {
+			continue
+		}
+		if len(file.Rows) < 2 {
+			return nil, fmt.Errorf("le fichier %s ne contient aucune ligne de données", file.FileName)
+		}
+		if missing := helpdesk.MissingColumns(file.Stage, file.Rows[0], helpdeskRequiredColumns[file.Stage]...); len(missing) > 0 {
+			return nil, fmt.Errorf("colonnes obligatoires manquantes dans %s: %s", file.FileName, strings.Join(missing, ", "))
+		}
+		byStage[file.Stage] = file
+	}
+	var stages []string
+	var importFiles []models.ImportFile
+	for _, stage := range requested {
+		file, ok := byStage[stage]
+		if !ok {
+			continue
+		}
+		rows, _ := json.Marshal(file.Rows)
+		stages = append(stages, stage)
+		importFiles = append(importFiles, models.ImportFile{Stage: stage, FileName: file.FileName, Rows: rows})
+	}
+	if len(stages) == 0 {
+		return nil, errors.New("aucun fichier à importer (contacts, articles ou tickets)")
+	}
+	if err := s.checkNoActiveRun(ImportSourceCSV); err != nil {
+		return nil, err
+	}
+
+	opts.Stages = stages
+	options, _ := json.Marshal(dto.HelpdeskRunOptions{HelpdeskImportOptions: opts})
+	stagesJSON, _ := json.Marshal(stages)
+	run := &models.ImportRun{
+		Source:      ImportSourceCSV,
+		Status:      models.ImportStatusPending,
+		Options:     options,
+		Stages:      stagesJSON,
+		Stage:       stages[0],
+		CreatedByID: userID,
+	}
+	if err := s.importRepo.CreateRunWithFiles(run, importFiles); err != nil {
+		return nil, errors.New("erreur lors de la création de l'import")
+	}
+	if err := s.enqueue(run); err != nil {
+		return nil, err
+	}
+	return s.GetByID(run.ID)
+}
+
+// validateTarget vérifie la filiale, le rôle et le département attribués aux éléments importés
+func (s *importService) validateTarget(filialeID, roleID uint, departmentID *uint) error {
+	if _, err := s.filialeRepo.FindByID(filialeID); err != nil {
+		return utils.ErrFilialeNotFound
+	}
+	if _, err := s.roleRepo.FindByID(roleID); err != nil {
+		return utils.ErrRoleNotFound
+	}
+	if departmentID != nil {
+		department, err := s.departmentRepo.FindByID(*departmentID)
+		if err != nil {
+			return utils.ErrDepartmentNotFound
+		}
+		if department.FilialeID == nil || *department.FilialeID != filialeID {
+			return errors.New("le département n'appartient pas à la filiale de l'import")
+		}
+	}
+	return nil
+}
+
+// validateHelpdeskOptions vérifie la cible et les correspondances d'un import depuis un outil de support
+// Les clés des correspondances sont normalisées en minuscules
+func (s *importService) validateHelpdeskOptions(opts *dto.HelpdeskImportOptions) error {
+	if err := s.validateTarget(opts.FilialeID, opts.RoleID, opts.DepartmentID); err != nil {
+		return err
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = importDefaultBatchSize
+	}
+
+	mapping := &opts.Mapping
+	if mapping.DefaultCategory == "" {
+		mapping.DefaultCategory = "incident"
+	}
+	slugs := []string{mapping.DefaultCategory}
+	for _, slug := range mapping.Categories {
+		slugs = append(slugs, slug)
+	}
+	for _, slug := range slugs {
+		if _, err := s.ticketCategoryRepo.FindBySlug(slug); err != nil {
+			return fmt.Errorf("catégorie de ticket introuvable: %s", slug)
+		}
+	}
+	for key, priority := range mapping.Priorities {
+		if !slices.Contains(helpdeskPriorities, priority) {
+			return fmt.Errorf("priorité invalide pour %s: %s (low, medium, high, critical)", key, priority)
+		}
+	}
+	for key, status := range mapping.Statuses {
+		if !slices.Contains(helpdeskStatuses, status) {
+			return fmt.Errorf("statut invalide pour %s: %s (ouvert, en_cours, en_attente, resolu, cloture)", key, status)
+		}
+	}
+	if mapping.KnowledgeCategoryID != nil {
+		if _, err := s.knowledgeRepo.FindByID(*mapping.KnowledgeCategoryID); err != nil {
+			return errors.New("catégorie de la base de connaissances introuvable")
+		}
+	}
+	mapping.Categories = lowerKeys(mapping.Categories)
+	mapping.Priorities = lowerKeys(mapping.Priorities)
+	mapping.Statuses = lowerKeys(mapping.Statuses)
+	return nil
+}
+
+// checkNoActiveRun refuse un nouvel import tant qu'un import de la même source est en cours
+func (s *importService) checkNoActiveRun(source string) error {
+	active, err := s.importRepo.HasActiveRun(source)
+	if err != nil {
+		return errors.New("erreur lors de la vérification des imports en cours")
+	}
+	if active {
+		return fmt.Errorf("un import %s est déjà en cours", source)
+	}
+	return nil
+}
+
+// GetAll récupère les imports
+func (s *importService) GetAll(source string, page, limit int) (*dto.ImportRunListResponse, error) {
+	runs, total, err := s.importRepo.FindRuns(source, page, limit)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des imports")
+	}
+	runDTOs := make([]dto.ImportRunDTO, 0, len(runs))
+	for i := range runs {
+		runDTOs = append(runDTOs, importRunToDTO(&runs[i]))
+	}
+	return &dto.ImportRunListResponse{
+		Runs: runDTOs,
+		Pagination: dto.PaginationDTO{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	}, nil
+}
+
+// GetByID récupère un import
+func (s *importService) GetByID(id uint) (*dto.ImportRunDTO, error) {
+	run, err := s.importRepo.FindRunByID(id)
+	if err != nil {
+		return nil, errors.New("import introuvable")
+	}
+	runDTO := importRunToDTO(run)
+	return &runDTO, nil
+}
+
+// Resume reprend un import interrompu (échec ou arrêt) à partir de sa position enregistrée
+func (s *importService) Resume(id uint) (*dto.ImportRunDTO, error) {
+	run, err := s.importRepo.FindRunByID(id)
+	if err != nil {
+		return nil, errors.New("import introuvable")
+	}
+	if run.Status != models.ImportStatusFailed && run.Status != models.ImportStatusCancelled {
+		return nil, errors.New("seul un import en échec ou arrêté peut être repris")
+	}
+	active, err := s.importRepo.HasActiveRun(run.Source)
+	if err != nil {
+		return nil, errors.New("erreur lors de la vérification des imports en cours")
+	}
+	if active {
+		return nil, errors.New("un autre import de cette source est déjà en cours")
+	}
+
+	run.Status = models.ImportStatusPending
+	run.LastError = ""
+	run.FinishedAt = nil
+	if err := s.importRepo.UpdateRun(run); err != nil {
+		return nil, errors.New("erreur lors de la reprise de l'import")
+	}
+	if err := s.enqueue(run); err != nil {
+		return nil, err
+	}
+	return s.GetByID(run.ID)
+}
+
+// Cancel arrête un import : le lot en cours se termine, aucun lot suivant n'est traité
+func (s *importService) Cancel(id uint) (*dto.ImportRunDTO, error) {
+	run, err := s.importRepo.FindRunByID(id)
+	if err != nil {
+		return nil, errors.New("import introuvable")
+	}
+	if run.Status != models.ImportStatusPending && run.Status != models.ImportStatusRunning {
+		return nil, errors.New("seul un import en attente ou en cours peut être arrêté")
+	}
+	run.Status = models.ImportStatusCancelled
+	if err := s.importRepo.UpdateRun(run); err != nil {
+		return nil, errors.New("erreur lors de l'arrêt de l'import")
+	}
+	return s.GetByID(run.ID)
+}
+
+// ProcessBatch traite le lot d'un import à la position indiquée
+// Une erreur de la source interrompt l'import (statut failed, reprise possible) ; seules les erreurs
+// d'enregistrement de la progression sont retournées (la tâche est alors réessayée)
+func (s *importService) ProcessBatch(ctx context.Context, batch jobs.ImportBatchPayload) (*jobs.ImportBatchPayload, error) {
+	run, err := s.importRepo.FindRunByID(batch.RunID)
+	if err != nil {
+		return nil, nil
+	}
+	// Import arrêté ou lot obsolète (déjà traité par une reprise)
+	if (run.Status != models.ImportStatusPending && run.Status != models.ImportStatusRunning) ||
+		run.Stage != batch.Stage || run.Offset != batch.Offset {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if run.StartedAt == nil {
+		run.StartedAt = &now
+	}
+	run.Status = models.ImportStatusRunning
+	progress := newImportProgress(run)
+
+	source, err := s.newSource(run)
+	if err == nil {
+		defer source.close(ctx)
+		var next, total int
+		var done bool
+		next, total, done, err = source.importBatch(ctx, progress, run.Stage, run.Offset)
+		if err == nil {
+			run.Offset = next
+			run.Total = total
+			if done {
+				s.advanceStage(run)
+			}
+		}
+	}
+	if err != nil {
+		run.Status = models.ImportStatusFailed
+		run.LastError = err.Error()
+	}
+	progress.save()
+
+	// Un arrêt demandé pendant le traitement du lot est conservé
+	if current, findErr := s.importRepo.FindRunByID(run.ID); findErr == nil && current.Status == models.ImportStatusCancelled {
+		run.Status = models.ImportStatusCancelled
+	}
+	if err := s.importRepo.UpdateRun(run); err != nil {
+		return nil, fmt.Errorf("enregistrement de la progression de l'import %d: %w", run.ID, err)
+	}
+
+	if run.Status != models.ImportStatusRunning {
+		return nil, nil
+	}
+	return &jobs.ImportBatchPayload{RunID: run.ID, Stage: run.Stage, Offset: run.Offset}, nil
+}
+
+// advanceStage passe à l'étape suivante, ou termine l'import après la dernière étape
+func (s *importService) advanceStage(run *models.ImportRun) {
+	var stages []string
+	_ = json.Unmarshal(run.Stages, &stages)
+	for i, stage := range stages {
+		if stage == run.Stage && i+1 < len(stages) {
+			run.Stage = stages[i+1]
+			run.Offset = 0
+			run.Total = 0
+			return
+		}
+	}
+	now := time.Now()
+	run.Status = models.ImportStatusCompleted
+	run.FinishedAt = &now
+}
+
+// enqueue planifie le lot correspondant à la position de l'import
+func (s *importService) enqueue(run *models.ImportRun) error {
+	if s.jobQueue == nil {
+		return nil
+	}
+	batch := jobs.ImportBatchPayload{RunID: run.ID, Stage: run.Stage, Offset: run.Offset}
+	if err := s.jobQueue.Enqueue(context.Background(), jobs.TypeImportBatch, batch); err != nil {
+		return errors.New("erreur lors de la planification de l'import")
+	}
+	return nil
+}
+
+// newSource crée le lecteur de la source d'un import
+func (s *importService) newSource(run *models.ImportRun) (importSource, error) {
+	switch run.Source {
+	case ImportSourceGLPI:
+		return newGLPIImportSource(s, run)
+	case ImportSourceZendesk, ImportSourceFreshdesk, ImportSourceCSV:
+		return newHelpdeskImportSource(s, run)
+	default:
+		return nil, fmt.Errorf("source d'import inconnue: %s", run.Source)
+	}
+}
+
+// orderedImportStages retourne les étapes demandées dans l'ordre de traitement (toutes si aucune)
+func orderedImportStages(order, requested []string) []string {
+	if len(requested) == 0 {
+		return order
+	}
+	stages := make([]string, 0, len(order))
+	for _, stage := range order {
+		if slices.Contains(requested, stage) {
+			stages = append(stages, stage)
+		}
+	}
+	return stages
+}
+
+// lowerKeys retourne une copie d'une correspondance avec des clés en minuscules
+func lowerKeys(values map[string]string) map[string]string {
+	lowered := make(map[string]string, len(values))
+	for key, value := range values {
+		lowered[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return lowered
+}
+
+// importProgress tient les compteurs et erreurs d'un import pendant le traitement d'un lot
+type importProgress struct {
+	run    *models.ImportRun
+	stats  map[string]*dto.ImportStageStatsDTO
+	errors []string
+}
+
+// newImportProgress charge les compteurs et erreurs enregistrés avec l'import
+func newImportProgress(run *models.ImportRun) *importProgress {
+	p := &importProgress{run: run, stats: map[string]*dto.ImportStageStatsDTO{}}
+	_ = json.Unmarshal(run.Stats, &p.stats)
+	_ = json.Unmarshal(run.Errors, &p.errors)
+	return p
+}
+
+// stage retourne les compteurs d'une étape (les étapes détaillées sont regroupées : assets:Computer -> assets)
+func (p *importProgress) stage(stage string) *dto.ImportStageStatsDTO {
+	name, _, _ := strings.Cut(stage, ":")
+	stats, ok := p.stats[name]
+	if !ok {
+		stats = &dto.ImportStageStatsDTO{}
+		p.stats[name] = stats
+	}
+	return stats
+}
+
+// fail comptabilise un élément en erreur et conserve le motif
+func (p *importProgress) fail(stage, externalID string, err error) {
+	p.stage(stage).Failed++
+	p.warn(stage, externalID, err)
+}
+
+// warn conserve une erreur sans compter l'élément en échec (ex: document d'un ticket importé)
+func (p *importProgress) warn(stage, externalID string, err error) {
+	p.errors = append(p.errors, fmt.Sprintf("%s %s: %v", stage, externalID, err))
+	if len(p.errors) > importMaxErrors {
+		p.errors = p.errors[len(p.errors)-importMaxErrors:]
+	}
+}
+
+// save enregistre les compteurs et erreurs dans l'import
+func (p *importProgress) save() {
+	p.run.Stats, _ = json.Marshal(p.stats)
+	p.run.Errors, _ = json.Marshal(p.errors)
+}
+
+// importRunToDTO convertit un import en DTO
+func importRunToDTO(run *models.ImportRun) dto.ImportRunDTO {
+	runDTO := dto.ImportRunDTO{
+		ID:          run.ID,
+		Source:      run.Source,
+		Status:      run.Status,
+		Options:     json.RawMessage(run.Options),
+		Stage:       run.Stage,
+		Offset:      run.Offset,
+		Total:       run.Total,
+		Stats:       map[string]dto.ImportStageStatsDTO{},
+		LastError:   run.LastError,
+		CreatedByID: run.CreatedByID,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		CreatedAt:   run.CreatedAt,
+		UpdatedAt:   run.UpdatedAt,
+	}
+	_ = json.Unmarshal(run.Stages, &runDTO.Stages)
+	_ = json.Unmarshal(run.Stats, &runDTO.Stats)
+	_ = json.Unmarshal(run.Errors, &runDTO.Errors)
+	if run.Status == models.ImportStatusCompleted {
+		runDTO.Stage = ""
+	}
+	return runDTO
+}
@@ -1,12 +1,15 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // IncidentService interface pour les opérations sur les incidents
@@ -31,6 +34,7 @@ type incidentService struct {
 	ticketRepo      repositories.TicketRepository
 	ticketAssetRepo repositories.TicketAssetRepository
 	assetRepo       repositories.AssetRepository
+	eventBus        *events.Bus // Publication des incidents majeurs (notifications)
 }
 
 // NewIncidentService crée une nouvelle instance de IncidentService
@@ -39,21 +43,46 @@ func NewIncidentService(
 	ticketRepo repositories.TicketRepository,
 	ticketAssetRepo repositories.TicketAssetRepository,
 	assetRepo repositories.AssetRepository,
+	eventBus *events.Bus,
 ) IncidentService {
 	return &incidentService{
 		incidentRepo:    incidentRepo,
 		ticketRepo:      ticketRepo,
 		ticketAssetRepo: ticketAssetRepo,
 		assetRepo:       assetRepo,
+		eventBus:        eventBus,
 	}
 }
 
+// majorIncidentImpact impact à partir duquel un incident est majeur
+const majorIncidentImpact = "critical"
+
+// publishIfMajor publie l'événement incident majeur lorsque l'incident le devient (previousImpact : impact avant la modification)
+func (s *incidentService) publishIfMajor(incident *dto.IncidentDTO, previousImpact string, actorID uint) {
+	if s.eventBus == nil || incident.Impact != majorIncidentImpact || previousImpact == majorIncidentImpact {
+		return
+	}
+	var filialeID *uint
+	if incident.Ticket != nil {
+		filialeID = incident.Ticket.FilialeID
+	}
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       events.IncidentMajor,
+		ActorID:    &actorID,
+		FilialeID:  filialeID,
+		EntityType: "incidents",
+		EntityID:   incident.ID,
+		Data:       *incident,
+		Metadata:   map[string]any{"ticket_id": incident.TicketID, "urgency": incident.Urgency},
+	})
+}
+
 // Create crée un nouvel incident à partir d'un ticket
 func (s *incidentService) Create(req dto.CreateIncidentRequest, createdByID uint) (*dto.IncidentDTO, error) {
 	// Vérifier que le ticket existe et est de catégorie "incident"
 	ticket, err := s.ticketRepo.FindByID(req.TicketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	if ticket.Category != "incident" {
@@ -111,6 +140,7 @@ func (s *incidentService) Create(req dto.CreateIncidentRequest, createdByID uint
 
 	// Convertir en DTO
 	incidentDTO := s.incidentToDTO(createdIncident)
+	s.publishIfMajor(&incidentDTO, "", createdByID)
 	return &incidentDTO, nil
 }
 
@@ -118,7 +148,7 @@ func (s *incidentService) Create(req dto.CreateIncidentRequest, createdByID uint
 func (s *incidentService) GetByID(id uint) (*dto.IncidentDTO, error) {
 	incident, err := s.incidentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("incident introuvable")
+		return nil, utils.ErrIncidentNotFound
 	}
 
 	incidentDTO := s.incidentToDTO(incident)
@@ -129,7 +159,7 @@ func (s *incidentService) GetByID(id uint) (*dto.IncidentDTO, error) {
 func (s *incidentService) GetByTicketID(ticketID uint) (*dto.IncidentDTO, error) {
 	incident, err := s.incidentRepo.FindByTicketID(ticketID)
 	if err != nil {
-		return nil, errors.New("incident introuvable")
+		return nil, utils.ErrIncidentNotFound
 	}
 
 	incidentDTO := s.incidentToDTO(incident)
@@ -189,9 +219,11 @@ func (s *incidentService) Update(id uint, req dto.UpdateIncidentRequest, updated
 	// Récupérer l'incident existant
 	incident, err := s.incidentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("incident introuvable")
+		return nil, utils.ErrIncidentNotFound
 	}
 
+	previousImpact := incident.Impact
+
 	// Mettre à jour les champs fournis
 	if req.Impact != "" {
 		// Valider l'impact
@@ -237,6 +269,7 @@ func (s *incidentService) Update(id uint, req dto.UpdateIncidentRequest, updated
 	}
 
 	incidentDTO := s.incidentToDTO(updatedIncident)
+	s.publishIfMajor(&incidentDTO, previousImpact, updatedByID)
 	return &incidentDTO, nil
 }
 
@@ -245,7 +278,7 @@ func (s *incidentService) Qualify(id uint, req dto.QualifyIncidentRequest, quali
 	// Récupérer l'incident
 	incident, err := s.incidentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("incident introuvable")
+		return nil, utils.ErrIncidentNotFound
 	}
 
 	// Valider l'impact et l'urgence
@@ -275,6 +308,7 @@ func (s *incidentService) Qualify(id uint, req dto.QualifyIncidentRequest, quali
 	}
 
 	// Mettre à jour
+	previousImpact := incident.Impact
 	incident.Impact = req.Impact
 	incident.Urgency = req.Urgency
 
@@ -289,6 +323,7 @@ func (s *incidentService) Qualify(id uint, req dto.QualifyIncidentRequest, quali
 	}
 
 	incidentDTO := s.incidentToDTO(updatedIncident)
+	s.publishIfMajor(&incidentDTO, previousImpact, qualifiedByID)
 	return &incidentDTO, nil
 }
 
@@ -297,13 +332,13 @@ func (s *incidentService) LinkAsset(incidentID uint, assetID uint, linkedByID ui
 	// Vérifier que l'incident existe
 	incident, err := s.incidentRepo.FindByID(incidentID)
 	if err != nil {
-		return errors.New("incident introuvable")
+		return utils.ErrIncidentNotFound
 	}
 
 	// Vérifier que l'actif existe
 	_, err = s.assetRepo.FindByID(assetID)
 	if err != nil {
-		return errors.New("actif introuvable")
+		return utils.ErrAssetNotFound
 	}
 
 	// Créer la liaison via ticket_assets (car incident est lié à un ticket)
@@ -324,7 +359,7 @@ func (s *incidentService) UnlinkAsset(incidentID uint, assetID uint) error {
 	// Vérifier que l'incident existe
 	incident, err := s.incidentRepo.FindByID(incidentID)
 	if err != nil {
-		return errors.New("incident introuvable")
+		return utils.ErrIncidentNotFound
 	}
 
 	// Supprimer la liaison directement
@@ -340,13 +375,13 @@ func (s *incidentService) Resolve(id uint, resolvedByID uint) (*dto.IncidentDTO,
 	// Récupérer l'incident
 	incident, err := s.incidentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("incident introuvable")
+		return nil, utils.ErrIncidentNotFound
 	}
 
 	// Calculer le temps de résolution si le ticket est clôturé
 	ticket, err := s.ticketRepo.FindByID(incident.TicketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	if ticket.ClosedAt != nil && ticket.CreatedAt.Before(*ticket.ClosedAt) {
@@ -377,7 +412,7 @@ func (s *incidentService) Delete(id uint) error {
 	// Vérifier que l'incident existe
 	_, err := s.incidentRepo.FindByID(id)
 	if err != nil {
-		return errors.New("incident introuvable")
+		return utils.ErrIncidentNotFound
 	}
 
 	// Supprimer
@@ -404,18 +439,30 @@ func (s *incidentService) incidentToDTO(incident *models.Incident) dto.IncidentD
 		}
 	}
 
-	return dto.IncidentDTO{
-		ID:             incident.ID,
-		TicketID:       incident.TicketID,
-		Ticket:         &ticketDTO,
-		Impact:         incident.Impact,
-		Urgency:        incident.Urgency,
-		ResolutionTime: incident.ResolutionTime,
-		ResolvedAt:     incident.ResolvedAt,
-		LinkedAssets:   assetDTOs,
-		CreatedAt:      incident.CreatedAt,
-		UpdatedAt:      incident.UpdatedAt,
-	}
+	incidentDTO := dto.IncidentDTO{
+		ID:                    incident.ID,
+		TicketID:              incident.TicketID,
+		Ticket:                &ticketDTO,
+		Impact:                incident.Impact,
+		Urgency:               incident.Urgency,
+		ResolutionTime:        incident.ResolutionTime,
+		ResolvedAt:            incident.ResolvedAt,
+		LinkedAssets:          assetDTOs,
+		IsMajor:               incident.IsMajor,
+		MajorDeclaredAt:       incident.MajorDeclaredAt,
+		MajorEndedAt:          incident.MajorEndedAt,
+		PublicTitle:           incident.PublicTitle,
+		UpdateIntervalMinutes: incident.UpdateIntervalMinutes,
+		LastStatusUpdateAt:    incident.LastStatusUpdateAt,
+		NextStatusUpdateDue:   nextMajorIncidentUpdateDue(incident),
+		CreatedAt:             incident.CreatedAt,
+		UpdatedAt:             incident.UpdatedAt,
+	}
+	if incident.MajorDeclaredBy != nil {
+		declaredBy := s.userToDTO(incident.MajorDeclaredBy)
+		incidentDTO.MajorDeclaredBy = &declaredBy
+	}
+	return incidentDTO
 }
 
 // ticketToDTO convertit un modèle Ticket en DTO TicketDTO (méthode utilitaire)
@@ -430,6 +477,7 @@ func (s *incidentService) ticketToDTO(ticket *models.Ticket) dto.TicketDTO {
 
 	return dto.TicketDTO{
 		ID:            ticket.ID,
+		Code:          ticket.Code,
 		Title:         ticket.Title,
 		Description:   ticket.Description,
 		Category:      ticket.Category,
@@ -443,6 +491,7 @@ func (s *incidentService) ticketToDTO(ticket *models.Ticket) dto.TicketDTO {
 		CreatedAt:     ticket.CreatedAt,
 		UpdatedAt:     ticket.UpdatedAt,
 		ClosedAt:      ticket.ClosedAt,
+		FilialeID:     ticket.FilialeID,
 	}
 }
 
@@ -0,0 +1,511 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/jira"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// jiraAutoCreateCategory catégorie de ticket pour laquelle un ticket Jira est créé automatiquement
+const jiraAutoCreateCategory = "developpement"
+
+// jiraCategoryByStatus catégorie de statut Jira correspondant à chaque statut de ticket
+var jiraCategoryByStatus = map[string]string{
+	"ouvert":     jira.StatusCategoryNew,
+	"en_cours":   jira.StatusCategoryInProgress,
+	"en_attente": jira.StatusCategoryInProgress,
+	"resolu":     jira.StatusCategoryDone,
+	"cloture":    jira.StatusCategoryDone,
+}
+
+// ticketStatusByJiraCategory statut de ticket appliqué lorsqu'un ticket Jira change de catégorie de statut
+var ticketStatusByJiraCategory = map[string]string{
+	jira.StatusCategoryNew:        "ouvert",
+	jira.StatusCategoryInProgress: "en_cours",
+	jira.StatusCategoryDone:       "resolu",
+}
+
+// JiraSyncService interface pour la synchronisation bidirectionnelle des tickets avec Jira
+type JiraSyncService interface {
+	Enabled() bool
+	LinkTicket(ticketID uint, userID uint) (*dto.JiraLinkDTO, error) // Crée immédiatement le ticket Jira lié
+	UnlinkTicket(ticketID uint) error                                // Supprime le lien (le ticket Jira est conservé)
+	HandleEvent(ctx context.Context, event events.Event) error       // Abonné du bus : planifie les synchronisations sortantes
+	CreateIssue(ctx context.Context, ticketID uint, userID *uint) error
+	PushStatus(ctx context.Context, ticketID uint) error
+	PushComment(ctx context.Context, commentID uint) error
+	VerifyWebhook(secret, signature string, body []byte) bool
+	HandleWebhook(body []byte) error // Applique un événement Jira (statut, commentaire) au ticket lié
+}
+
+// jiraSyncService implémente JiraSyncService
+type jiraSyncService struct {
+	client      *jira.Client // Nil si l'intégration n'est pas configurée
+	cfg         config.JiraConfig
+	linkRepo    repositories.JiraLinkRepository
+	ticketRepo  repositories.TicketRepository
+	commentRepo repositories.TicketCommentRepository
+	historyRepo repositories.TicketHistoryRepository
+	userRepo    repositories.UserRepository
+	ticketSvc   TicketService
+	jobQueue    *jobs.Queue
+}
+
+// NewJiraSyncService crée une nouvelle instance de JiraSyncService
+func NewJiraSyncService(
+	cfg config.JiraConfig,
+	linkRepo repositories.JiraLinkRepository,
+	ticketRepo repositories.TicketRepository,
+	commentRepo repositories.TicketCommentRepository,
+	historyRepo repositories.TicketHistoryRepository,
+	userRepo repositories.UserRepository,
+	ticketSvc TicketService,
+	jobQueue *jobs.Queue,
+) JiraSyncService {
+	var client *jira.Client
+	if cfg.Enabled() {
+		client = jira.NewClient(cfg)
+	}
+	return &jiraSyncService{
+		client:      client,
+		cfg:         cfg,
+		linkRepo:    linkRepo,
+		ticketRepo:  ticketRepo,
+		commentRepo: commentRepo,
+		historyRepo: historyRepo,
+		userRepo:    userRepo,
+		ticketSvc:   ticketSvc,
+		jobQueue:    jobQueue,
+	}
+}
+
+// Enabled indique si l'intégration Jira est configurée
+func (s *jiraSyncService) Enabled() bool {
+	return s.client != nil
+}
+
+// LinkTicket crée le ticket Jira d'un ticket à la demande d'un utilisateur
+func (s *jiraSyncService) LinkTicket(ticketID uint, userID uint) (*dto.JiraLinkDTO, error) {
+	if !s.Enabled() {
+		return nil, errors.New("intégration Jira non configurée")
+	}
+	if _, err := s.ticketRepo.FindByIDForUpdate(ticketID); err != nil {
+		return nil, utils.ErrTicketNotFound
+	}
+	if _, err := s.linkRepo.FindByTicketID(ticketID); err == nil {
+		return nil, errors.New("ce ticket est déjà lié à un ticket Jira")
+	}
+
+	link, err := s.createIssue(context.Background(), ticketID, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la création du ticket Jira: %w", err)
+	}
+	return jiraLinkToDTO(link), nil
+}
+
+// UnlinkTicket supprime le lien entre un ticket et son ticket Jira
+func (s *jiraSyncService) UnlinkTicket(ticketID uint) error {
+	if _, err := s.linkRepo.FindByTicketID(ticketID); err != nil {
+		return errors.New("lien Jira introuvable")
+	}
+	if err := s.linkRepo.DeleteByTicketID(ticketID); err != nil {
+		return errors.New("erreur lors de la suppression du lien Jira")
+	}
+	return nil
+}
+
+// HandleEvent planifie les synchronisations sortantes déclenchées par les événements ticket
+func (s *jiraSyncService) HandleEvent(ctx context.Context, event events.Event) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	switch event.Type {
+	case events.TicketCreated:
+		ticket, ok := event.Data.(dto.TicketDTO)
+		if !ok || !s.cfg.AutoCreate || ticket.Category != jiraAutoCreateCategory {
+			return nil
+		}
+		return s.jobQueue.Enqueue(ctx, jobs.TypeJiraCreate, jobs.JiraCreatePayload{TicketID: ticket.ID})
+
+	case events.TicketUpdated, events.TicketStatusChanged:
+		ticket, ok := event.Data.(dto.TicketDTO)
+		if !ok {
+			return nil
+		}
+		link, err := s.linkRepo.FindByTicketID(ticket.ID)
+		if err != nil || link.SyncedStatus == ticket.Status {
+			return nil
+		}
+		return s.jobQueue.Enqueue(ctx, jobs.TypeJiraStatus, jobs.JiraStatusPayload{TicketID: ticket.ID})
+
+	case events.TicketCommented:
+		comment, ok := event.Data.(dto.TicketCommentDTO)
+		if !ok || comment.IsInternal {
+			return nil
+		}
+		if _, err := s.linkRepo.FindByTicketID(comment.TicketID); err != nil {
+			return nil
+		}
+		return s.jobQueue.Enqueue(ctx, jobs.TypeJiraComment, jobs.JiraCommentPayload{CommentID: comment.ID})
+	}
+	return nil
+}
+
+// CreateIssue crée le ticket Jira d'un ticket (tâche en arrière-plan, sans effet si le lien existe déjà)
+func (s *jiraSyncService) CreateIssue(ctx context.Context, ticketID uint, userID *uint) error {
+	if !s.Enabled() {
+		return nil
+	}
+	if _, err := s.linkRepo.FindByTicketID(ticketID); err == nil {
+		return nil
+	}
+	_, err := s.createIssue(ctx, ticketID, userID)
+	return err
+}
+
+// createIssue crée le ticket Jira et enregistre le lien
+func (s *jiraSyncService) createIssue(ctx context.Context, ticketID uint, userID *uint) (*models.TicketJiraLink, error) {
+	ticket, err := s.ticketRepo.FindByIDForUpdate(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("[%s] %s", ticket.Code, ticket.Title)
+	description := fmt.Sprintf("%s\n\n----\nTicket Kronos %s (catégorie : %s, priorité : %s)", ticket.Description, ticket.Code, ticket.Category, ticket.Priority)
+	issue, err := s.client.CreateIssue(ctx, summary, description, []string{"kronos"})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	link := &models.TicketJiraLink{
+		TicketID:           ticket.ID,
+		IssueKey:           issue.Key,
+		IssueID:            issue.ID,
+		IssueURL:           s.client.IssueURL(issue.Key),
+		JiraStatusCategory: jira.StatusCategoryNew,
+		SyncedStatus:       ticket.Status,
+		LastSyncedAt:       &now,
+		CreatedByID:        userID,
+	}
+	if err := s.linkRepo.Create(link); err != nil {
+		return nil, fmt.Errorf("enregistrement du lien %s: %w", issue.Key, err)
+	}
+
+	// Le ticket a déjà avancé : aligner le statut Jira
+	if jiraCategoryByStatus[ticket.Status] != jira.StatusCategoryNew {
+		if err := s.jobQueue.Enqueue(ctx, jobs.TypeJiraStatus, jobs.JiraStatusPayload{TicketID: ticket.ID}); err != nil {
+			return nil, err
+		}
+	}
+	return link, nil
+}
+
+// PushStatus reporte le statut du ticket sur le ticket Jira lié
+func (s *jiraSyncService) PushStatus(ctx context.Context, ticketID uint) error {
+	if !s.Enabled() {
+		return nil
+	}
+	link, err := s.linkRepo.FindByTicketID(ticketID)
+	if err != nil {
+		return nil
+	}
+	ticket, err := s.ticketRepo.FindByIDForUpdate(ticketID)
+	if err != nil {
+		return nil
+	}
+
+	target := jiraCategoryByStatus[ticket.Status]
+	if target == "" || target == link.JiraStatusCategory {
+		link.SyncedStatus = ticket.Status
+		return s.linkRepo.Update(link)
+	}
+
+	status, err := s.client.TransitionToCategory(ctx, link.IssueKey, target)
+	if err != nil {
+		link.SyncError = err.Error()
+		if updateErr := s.linkRepo.Update(link); updateErr != nil {
+			return updateErr
+		}
+		// Workflow sans transition adaptée : inutile de réessayer
+		if errors.Is(err, jira.ErrNoTransition) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	link.JiraStatus = status.Name
+	link.JiraStatusCategory = status.CategoryKey
+	link.SyncedStatus = ticket.Status
+	link.LastSyncedAt = &now
+	link.SyncError = ""
+	return s.linkRepo.Update(link)
+}
+
+// PushComment reporte un commentaire public sur le ticket Jira lié
+func (s *jiraSyncService) PushComment(ctx context.Context, commentID uint) error {
+	if !s.Enabled() {
+		return nil
+	}
+	comment, err := s.commentRepo.FindByIDWithUser(commentID)
+	if err != nil || comment.IsInternal {
+		return nil
+	}
+	if linked, err := s.linkRepo.CommentLinkedByTicketComment(commentID); err != nil || linked {
+		return err
+	}
+	link, err := s.linkRepo.FindByTicketID(comment.TicketID)
+	if err != nil {
+		return nil
+	}
+
+	author := strings.TrimSpace(comment.User.FirstName + " " + comment.User.LastName)
+	if author == "" {
+		author = comment.User.Username
+	}
+	jiraCommentID, err := s.client.AddComment(ctx, link.IssueKey, fmt.Sprintf("%s (Kronos) :\n%s", author, comment.Comment))
+	if err != nil {
+		link.SyncError = err.Error()
+		if updateErr := s.linkRepo.Update(link); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+
+	return s.linkRepo.CreateCommentLink(&models.JiraCommentLink{
+		TicketCommentID: comment.ID,
+		IssueKey:        link.IssueKey,
+		JiraCommentID:   jiraCommentID,
+	})
+}
+
+// VerifyWebhook authentifie un webhook entrant par secret partagé (?secret=) ou signature HMAC-SHA256 du corps
+func (s *jiraSyncService) VerifyWebhook(secret, signature string, body []byte) bool {
+	if s.cfg.WebhookSecret == "" {
+		return false
+	}
+	if secret != "" {
+		return subtle.ConstantTimeCompare([]byte(secret), []byte(s.cfg.WebhookSecret)) == 1
+	}
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.cfg.WebhookSecret))
+	mac.Write(body)
+	return hmac.Equal(expected, mac.Sum(nil))
+}
+
+// jiraWebhookUser auteur d'un événement Jira
+type jiraWebhookUser struct {
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
+
+// jiraWebhookPayload champs utilisés des webhooks Jira (jira:issue_updated, comment_created)
+type jiraWebhookPayload struct {
+	WebhookEvent       string           `json:"webhookEvent"`
+	IssueEventTypeName string           `json:"issue_event_type_name"`
+	User               *jiraWebhookUser `json:"user"`
+	Issue              struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Status *struct {
+				Name           string `json:"name"`
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	} `json:"issue"`
+	Changelog *struct {
+		Items []struct {
+			Field string `json:"field"`
+		} `json:"items"`
+	} `json:"changelog"`
+	Comment *struct {
+		ID     string          `json:"id"`
+		Body   string          `json:"body"`
+		Author jiraWebhookUser `json:"author"`
+	} `json:"comment"`
+}
+
+// HandleWebhook applique un événement Jira au ticket lié (les tickets Jira non liés sont ignorés)
+func (s *jiraSyncService) HandleWebhook(body []byte) error {
+	var payload jiraWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return errors.New("contenu du webhook Jira invalide")
+	}
+	if payload.Issue.Key == "" {
+		return nil
+	}
+	link, err := s.linkRepo.FindByIssueKey(payload.Issue.Key)
+	if err != nil {
+		return nil
+	}
+
+	switch payload.WebhookEvent {
+	case "comment_created":
+		return s.applyComment(link, &payload)
+	case "jira:issue_updated":
+		if payload.IssueEventTypeName == "issue_commented" {
+			return s.applyComment(link, &payload)
+		}
+		if payload.Changelog != nil {
+			for _, item := range payload.Changelog.Items {
+				if item.Field == "status" {
+					return s.applyStatus(link, &payload)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// applyStatus reporte le changement de statut Jira sur le ticket lié
+func (s *jiraSyncService) applyStatus(link *models.TicketJiraLink, payload *jiraWebhookPayload) error {
+	status := payload.Issue.Fields.Status
+	if status == nil {
+		return nil
+	}
+	ticket, err := s.ticketRepo.FindByIDForUpdate(link.TicketID)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	link.JiraStatus = status.Name
+	link.JiraStatusCategory = status.StatusCategory.Key
+	link.LastSyncedAt = &now
+	link.SyncError = ""
+
+	// Le ticket est déjà dans un statut équivalent : seul le statut Jira connu est mis à jour
+	newStatus, ok := ticketStatusByJiraCategory[status.StatusCategory.Key]
+	if !ok || jiraCategoryByStatus[ticket.Status] == status.StatusCategory.Key {
+		if err := s.linkRepo.Update(link); err != nil {
+			return errors.New("erreur lors de la mise à jour du lien Jira")
+		}
+		return nil
+	}
+
+	// Enregistré avant le changement de statut pour que l'événement émis ne soit pas renvoyé vers Jira
+	link.SyncedStatus = newStatus
+	if err := s.linkRepo.Update(link); err != nil {
+		return errors.New("erreur lors de la mise à jour du lien Jira")
+	}
+	if _, err := s.ticketSvc.ChangeStatus(ticket.ID, newStatus, s.jiraActorID(payload.User, link, ticket)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// applyComment ajoute au ticket lié un commentaire créé dans Jira
+func (s *jiraSyncService) applyComment(link *models.TicketJiraLink, payload *jiraWebhookPayload) error {
+	comment := payload.Comment
+	if comment == nil || comment.ID == "" || strings.TrimSpace(comment.Body) == "" {
+		return nil
+	}
+	// Commentaire publié par la synchronisation elle-même
+	if strings.EqualFold(comment.Author.EmailAddress, s.cfg.Email) {
+		return nil
+	}
+	if linked, err := s.linkRepo.CommentLinkedByJiraComment(link.IssueKey, comment.ID); err != nil || linked {
+		return err
+	}
+	ticket, err := s.ticketRepo.FindByIDForUpdate(link.TicketID)
+	if err != nil {
+		return nil
+	}
+
+	// Auteur sans compte : le commentaire est attribué par défaut et signé du nom Jira
+	userID := s.jiraActorID(&comment.Author, link, ticket)
+	text := fmt.Sprintf("[Jira] %s : %s", comment.Author.DisplayName, comment.Body)
+	if s.jiraUser(&comment.Author) != nil {
+		text = "[Jira] " + comment.Body
+	}
+
+	ticketComment := &models.TicketComment{
+		TicketID: ticket.ID,
+		UserID:   userID,
+		Comment:  text,
+	}
+	if err := s.commentRepo.Create(ticketComment); err != nil {
+		return errors.New("erreur lors de la création du commentaire")
+	}
+	if err := s.linkRepo.CreateCommentLink(&models.JiraCommentLink{
+		TicketCommentID: ticketComment.ID,
+		IssueKey:        link.IssueKey,
+		JiraCommentID:   comment.ID,
+	}); err != nil {
+		return errors.New("erreur lors de l'enregistrement du lien de commentaire Jira")
+	}
+
+	_ = s.historyRepo.Create(&models.TicketHistory{
+		TicketID:    ticket.ID,
+		UserID:      userID,
+		Action:      "comment_added",
+		NewValue:    "Commentaire ajouté depuis Jira",
+		Description: fmt.Sprintf("Commentaire Jira %s", link.IssueKey),
+	})
+	return nil
+}
+
+// jiraActorID retrouve l'utilisateur correspondant à l'auteur Jira (par e-mail),
+// à défaut l'auteur du lien puis le créateur du ticket
+func (s *jiraSyncService) jiraActorID(user *jiraWebhookUser, link *models.TicketJiraLink, ticket *models.Ticket) uint {
+	if u := s.jiraUser(user); u != nil {
+		return u.ID
+	}
+	if link.CreatedByID != nil {
+		return *link.CreatedByID
+	}
+	return ticket.CreatedByID
+}
+
+// jiraUser retrouve le compte correspondant à un auteur Jira par son e-mail (nil si inconnu)
+func (s *jiraSyncService) jiraUser(user *jiraWebhookUser) *models.User {
+	if user == nil || user.EmailAddress == "" {
+		return nil
+	}
+	u, err := s.userRepo.FindByEmail(user.EmailAddress)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// jiraLinkToDTO convertit le lien Jira d'un ticket en DTO
+func jiraLinkToDTO(link *models.TicketJiraLink) *dto.JiraLinkDTO {
+	if link == nil {
+		return nil
+	}
+	return &dto.JiraLinkDTO{
+		IssueKey:     link.IssueKey,
+		IssueURL:     link.IssueURL,
+		JiraStatus:   link.JiraStatus,
+		LastSyncedAt: link.LastSyncedAt,
+		SyncError:    link.SyncError,
+	}
+}
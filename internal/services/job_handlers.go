@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+)
+
+// RegisterJobHandlers enregistre les handlers des tâches en arrière-plan (à appeler avant jobs.Queue.Start)
+// Un handler qui retourne une erreur est réessayé par la file, puis placé en dead-letter
+func RegisterJobHandlers(
+	queue *jobs.Queue,
+	notificationService NotificationService,
+	ticketService TicketService,
+	historyRepo repositories.TicketHistoryRepository,
+	jiraSyncService JiraSyncService,
+	importService ImportService,
+	calendarSyncService CalendarSyncService,
+	telegramService TelegramService,
+	whatsAppService WhatsAppService,
+	pushService PushService,
+	emailService EmailService,
+) {
+	queue.Register(jobs.TypeNotifyUsers, func(ctx context.Context, payload []byte) error {
+		var p jobs.NotifyUsersPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		var failedIDs []uint
+		var lastErr error
+		for _, userID := range p.UserIDs {
+			if err := notificationService.Create(userID, p.Type, p.Title, p.Message, p.LinkURL, p.Metadata); err != nil {
+				failedIDs = append(failedIDs, userID)
+				lastErr = err
+			}
+		}
+		if len(failedIDs) == 0 {
+			return nil
+		}
+		// Aucun envoi réussi : la tâche complète est réessayée (puis dead-letter)
+		if len(failedIDs) == len(p.UserIDs) {
+			return fmt.Errorf("notification de %d utilisateur(s): %w", len(failedIDs), lastErr)
+		}
+		// Envoi partiel : seuls les utilisateurs en échec sont replanifiés, pour ne pas notifier deux fois les autres
+		p.UserIDs = failedIDs
+		return queue.Enqueue(ctx, jobs.TypeNotifyUsers, p)
+	})
+
+	queue.Register(jobs.TypeTicketHistory, func(ctx context.Context, payload []byte) error {
+		var p jobs.TicketHistoryPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return historyRepo.Create(ticketHistoryFromPayload(p))
+	})
+
+	queue.Register(jobs.TypeApplySLA, func(ctx context.Context, payload []byte) error {
+		var p jobs.ApplySLAPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return ticketService.ApplySLA(p.TicketID)
+	})
+
+	queue.Register(jobs.TypeJiraCreate, func(ctx context.Context, payload []byte) error {
+		var p jobs.JiraCreatePayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return jiraSyncService.CreateIssue(ctx, p.TicketID, p.UserID)
+	})
+
+	queue.Register(jobs.TypeJiraStatus, func(ctx context.Context, payload []byte) error {
+		var p jobs.JiraStatusPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return jiraSyncService.PushStatus(ctx, p.TicketID)
+	})
+
+	queue.Register(jobs.TypeJiraComment, func(ctx context.Context, payload []byte) error {
+		var p jobs.JiraCommentPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return jiraSyncService.PushComment(ctx, p.CommentID)
+	})
+
+	// Import par lots : chaque lot planifie le suivant, la progression est enregistrée entre deux lots
+	queue.Register(jobs.TypeImportBatch, func(ctx context.Context, payload []byte) error {
+		var p jobs.ImportBatchPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		next, err := importService.ProcessBatch(ctx, p)
+		if err != nil || next == nil {
+			return err
+		}
+		return queue.Enqueue(ctx, jobs.TypeImportBatch, *next)
+	})
+
+	queue.Register(jobs.TypeCalendarSync, func(ctx context.Context, payload []byte) error {
+		var p jobs.CalendarSyncPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return calendarSyncService.Sync(ctx, p.ConnectionID)
+	})
+
+	queue.Register(jobs.TypeTelegramSend, func(ctx context.Context, payload []byte) error {
+		var p jobs.TelegramSendPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return telegramService.Send(ctx, p)
+	})
+
+	queue.Register(jobs.TypeWhatsAppSend, func(ctx context.Context, payload []byte) error {
+		var p jobs.WhatsAppSendPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return whatsAppService.Send(ctx, p)
+	})
+
+	queue.Register(jobs.TypePushSend, func(ctx context.Context, payload []byte) error {
+		var p jobs.PushSendPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return pushService.Send(ctx, p)
+	})
+
+	queue.Register(jobs.TypeEmailSend, func(ctx context.Context, payload []byte) error {
+		var p jobs.EmailSendPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("charge utile invalide: %w", err)
+		}
+		return emailService.Send(ctx, p)
+	})
+}
+
+// ticketHistoryFromPayload convertit la charge utile d'une tâche d'historique en modèle
+func ticketHistoryFromPayload(p jobs.TicketHistoryPayload) *models.TicketHistory {
+	return &models.TicketHistory{
+		TicketID:  p.TicketID,
+		UserID:    p.UserID,
+		Action:    p.Action,
+		FieldName: p.FieldName,
+		OldValue:  p.OldValue,
+		NewValue:  p.NewValue,
+		CreatedAt: p.CreatedAt,
+	}
+}
@@ -1,11 +1,25 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mcicare/itsm-backend/internal/antivirus"
+	"github.com/mcicare/itsm-backend/internal/kbsearch"
 
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/storage"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // KnowledgeArticleService interface pour les opérations sur les articles de la base de connaissances
@@ -15,12 +29,23 @@ type KnowledgeArticleService interface {
 	GetAll(scope interface{}) ([]dto.KnowledgeArticleDTO, error) // scope peut être *scope.QueryScope ou nil
 	GetPublished(scope interface{}) ([]dto.KnowledgeArticleDTO, error)
 	GetByCategory(scope interface{}, categoryID uint) ([]dto.KnowledgeArticleDTO, error)
-	GetByAuthor(scope interface{}, authorID uint) ([]dto.KnowledgeArticleDTO, error) // scope peut être *scope.QueryScope ou nil
-	Search(scope interface{}, query string) ([]dto.KnowledgeArticleSearchResultDTO, error)
+	GetByAuthor(scope interface{}, authorID uint) ([]dto.KnowledgeArticleDTO, error)                // scope peut être *scope.QueryScope ou nil
+	Search(scope interface{}, query string) ([]dto.KnowledgeArticleSearchResultDTO, error)          // Classés par pertinence, extraits surlignés
+	Suggest(scope interface{}, query string, limit int) (*dto.KnowledgeSearchSuggestionsDTO, error) // Titres d'articles et correction des fautes de frappe
+	ReindexSearch() (int, error)                                                                    // Renvoie tous les articles au moteur de recherche (exécuté périodiquement)
 	Update(id uint, req dto.UpdateKnowledgeArticleRequest, updatedByID uint) (*dto.KnowledgeArticleDTO, error)
 	Publish(id uint, published bool, updatedByID uint) (*dto.KnowledgeArticleDTO, error)
 	Delete(id uint) error
 	IncrementViewCount(id uint) error
+	GetRevisions(id uint) ([]dto.KnowledgeArticleRevisionDTO, error)
+	GetRevision(id uint, version int) (*dto.KnowledgeArticleRevisionDTO, error)
+	Diff(id uint, fromVersion, toVersion int) (*dto.KnowledgeArticleDiffDTO, error)    // 0 = version courante (to) ou précédente (from)
+	Restore(id uint, version int, restoredByID uint) (*dto.KnowledgeArticleDTO, error) // Crée une nouvelle version à partir d'une version antérieure
+	SubmitForReview(id uint, req dto.SubmitKnowledgeReviewRequest, submittedByID uint) (*dto.KnowledgeArticleDTO, error)
+	Review(id uint, req dto.KnowledgeReviewDecisionRequest, reviewerID uint, canPublish bool) (*dto.KnowledgeArticleDTO, error)
+	Archive(id uint, archivedByID uint) (*dto.KnowledgeArticleDTO, error)
+	GetReviewQueue(userID uint, all bool) (*dto.KnowledgeReviewQueueDTO, error) // all = toutes les relectures, sinon celles de l'utilisateur
+	SendReviewAlerts() (int, error)                                             // Alerte des relectures en retard et des articles à réviser (exécuté périodiquement)
 }
 
 // KnowledgeCategoryService interface pour les opérations sur les catégories de la base de connaissances
@@ -34,11 +59,28 @@ type KnowledgeCategoryService interface {
 	Delete(id uint) error
 }
 
+// Échéances par défaut du cycle de relecture
+const (
+	knowledgeReviewDelay    = 7 * 24 * time.Hour   // Délai de relecture d'un article soumis
+	knowledgeReviewInterval = 365 * 24 * time.Hour // Périodicité de révision d'un article publié
+)
+
+// knowledgeStatusTransitions changements de statut autorisés depuis chaque statut d'un article
+var knowledgeStatusTransitions = map[string][]string{
+	models.KnowledgeStatusDraft:     {models.KnowledgeStatusInReview, models.KnowledgeStatusPublished, models.KnowledgeStatusArchived},
+	models.KnowledgeStatusInReview:  {models.KnowledgeStatusDraft, models.KnowledgeStatusPublished},
+	models.KnowledgeStatusPublished: {models.KnowledgeStatusDraft, models.KnowledgeStatusInReview, models.KnowledgeStatusArchived},
+	models.KnowledgeStatusArchived:  {models.KnowledgeStatusDraft},
+}
+
 // knowledgeArticleService implémente KnowledgeArticleService
 type knowledgeArticleService struct {
-	articleRepo  repositories.KnowledgeArticleRepository
-	categoryRepo repositories.KnowledgeCategoryRepository
-	userRepo     repositories.UserRepository
+	articleRepo         repositories.KnowledgeArticleRepository
+	categoryRepo        repositories.KnowledgeCategoryRepository
+	userRepo            repositories.UserRepository
+	notificationService NotificationService
+	attachments         *knowledgeAttachments // Images intégrées aux articles (espace "knowledge")
+	search              *knowledgeSearch      // Recherche plein texte (KB_SEARCH_DRIVER)
 }
 
 // NewKnowledgeArticleService crée une nouvelle instance de KnowledgeArticleService
@@ -46,11 +88,24 @@ func NewKnowledgeArticleService(
 	articleRepo repositories.KnowledgeArticleRepository,
 	categoryRepo repositories.KnowledgeCategoryRepository,
 	userRepo repositories.UserRepository,
+	notificationService NotificationService,
+	attachmentRepo repositories.KnowledgeAttachmentRepository,
+	fileStorage storage.Storage,
+	scanner antivirus.Scanner,
+	searchEngine kbsearch.Engine,
 ) KnowledgeArticleService {
 	return &knowledgeArticleService{
-		articleRepo:  articleRepo,
-		categoryRepo: categoryRepo,
-		userRepo:     userRepo,
+		articleRepo:         articleRepo,
+		categoryRepo:        categoryRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		attachments: &knowledgeAttachments{
+			articleRepo:    articleRepo,
+			attachmentRepo: attachmentRepo,
+			fileStorage:    fileStorage,
+			scanner:        scanner,
+		},
+		search: &knowledgeSearch{engine: searchEngine, articleRepo: articleRepo},
 	}
 }
 
@@ -59,27 +114,49 @@ func (s *knowledgeArticleService) Create(req dto.CreateKnowledgeArticleRequest,
 	// Vérifier que la catégorie existe
 	_, err := s.categoryRepo.FindByID(req.CategoryID)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	// Créer l'article
+	contentFormat := richtext.NormalizeFormat(req.ContentFormat)
 	article := &models.KnowledgeArticle{
-		Title:       req.Title,
-		Content:     req.Content,
-		CategoryID:  req.CategoryID,
-		AuthorID:    authorID,
-		IsPublished: req.IsPublished,
-		ViewCount:   0,
+		Title:         req.Title,
+		Content:       richtext.Prepare(req.Content, contentFormat),
+		ContentFormat: contentFormat,
+		CategoryID:    req.CategoryID,
+		AuthorID:      authorID,
+		IsPublic:      req.IsPublic,
+		ViewCount:     0,
+	}
+	status := models.KnowledgeStatusDraft
+	if req.IsPublished {
+		status = models.KnowledgeStatusPublished
 	}
+	setKnowledgeStatus(article, status, time.Now())
 
 	if err := s.articleRepo.Create(article); err != nil {
-		return nil, errors.New("erreur lors de la création de l'article")
+		return nil, utils.NewInternalError("erreur lors de la création de l'article")
+	}
+
+	// Images intégrées : enregistrées une fois l'article créé (l'article est supprimé si l'une est refusée)
+	content, err := s.extractEmbeddedImages(article, article.Content, contentFormat, authorID)
+	if err != nil {
+		_ = s.articleRepo.Delete(article.ID)
+		return nil, err
+	}
+	if content != article.Content {
+		article.Content = content
+		if err := s.articleRepo.Update(article); err != nil {
+			return nil, utils.NewInternalError("erreur lors de la création de l'article")
+		}
 	}
+	s.createRevision(article, authorID, "Création de l'article")
+	s.search.index(article)
 
 	// Récupérer l'article créé avec ses relations
 	createdArticle, err := s.articleRepo.FindByID(article.ID)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de l'article créé")
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'article créé")
 	}
 
 	articleDTO := s.articleToDTO(createdArticle)
@@ -90,7 +167,7 @@ func (s *knowledgeArticleService) Create(req dto.CreateKnowledgeArticleRequest,
 func (s *knowledgeArticleService) GetByID(id uint) (*dto.KnowledgeArticleDTO, error) {
 	article, err := s.articleRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("article introuvable")
+		return nil, utils.ErrArticleNotFound
 	}
 
 	// Incrémenter le compteur de vues si l'article est publié
@@ -106,7 +183,7 @@ func (s *knowledgeArticleService) GetByID(id uint) (*dto.KnowledgeArticleDTO, er
 func (s *knowledgeArticleService) GetAll(scopeParam interface{}) ([]dto.KnowledgeArticleDTO, error) {
 	articles, err := s.articleRepo.FindAll(scopeParam)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des articles")
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles")
 	}
 
 	var articleDTOs []dto.KnowledgeArticleDTO
@@ -121,7 +198,7 @@ func (s *knowledgeArticleService) GetAll(scopeParam interface{}) ([]dto.Knowledg
 func (s *knowledgeArticleService) GetPublished(scopeParam interface{}) ([]dto.KnowledgeArticleDTO, error) {
 	articles, err := s.articleRepo.FindPublished(scopeParam)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des articles")
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles")
 	}
 
 	var articleDTOs []dto.KnowledgeArticleDTO
@@ -136,7 +213,7 @@ func (s *knowledgeArticleService) GetPublished(scopeParam interface{}) ([]dto.Kn
 func (s *knowledgeArticleService) GetByCategory(scopeParam interface{}, categoryID uint) ([]dto.KnowledgeArticleDTO, error) {
 	articles, err := s.articleRepo.FindByCategory(scopeParam, categoryID)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des articles")
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles")
 	}
 
 	var articleDTOs []dto.KnowledgeArticleDTO
@@ -151,7 +228,7 @@ func (s *knowledgeArticleService) GetByCategory(scopeParam interface{}, category
 func (s *knowledgeArticleService) GetByAuthor(scopeParam interface{}, authorID uint) ([]dto.KnowledgeArticleDTO, error) {
 	articles, err := s.articleRepo.FindByAuthor(scopeParam, authorID)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des articles")
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles")
 	}
 
 	var articleDTOs []dto.KnowledgeArticleDTO
@@ -162,26 +239,60 @@ func (s *knowledgeArticleService) GetByAuthor(scopeParam interface{}, authorID u
 	return articleDTOs, nil
 }
 
-// Search recherche des articles
+// Search recherche des articles en plein texte, par pertinence décroissante
+// Sans résultat, la requête corrigée des fautes de frappe est utilisée
 func (s *knowledgeArticleService) Search(scopeParam interface{}, searchQuery string) ([]dto.KnowledgeArticleSearchResultDTO, error) {
-	articles, err := s.articleRepo.Search(scopeParam, searchQuery)
+	results, _, err := s.search.search(scopeParam, searchQuery, 0)
 	if err != nil {
-		return nil, errors.New("erreur lors de la recherche des articles")
+		return nil, utils.NewInternalError("erreur lors de la recherche des articles")
 	}
 
 	var resultDTOs []dto.KnowledgeArticleSearchResultDTO
-	for _, article := range articles {
-		resultDTOs = append(resultDTOs, s.articleToSearchResultDTO(&article))
+	for _, result := range results {
+		resultDTOs = append(resultDTOs, s.articleToSearchResultDTO(&result.article, result.hit))
 	}
 
 	return resultDTOs, nil
 }
 
+// Suggest propose des titres d'articles pour une saisie en cours et, si un terme est inconnu, la requête corrigée
+func (s *knowledgeArticleService) Suggest(scopeParam interface{}, query string, limit int) (*dto.KnowledgeSearchSuggestionsDTO, error) {
+	suggestions := &dto.KnowledgeSearchSuggestionsDTO{
+		Query:    query,
+		Articles: []dto.KnowledgeArticleSuggestionDTO{},
+	}
+	if corrected, ok := s.search.vocabulary().Correct(query); ok {
+		suggestions.DidYouMean = corrected
+	}
+
+	results, _, err := s.search.search(scopeParam, query, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la recherche des articles")
+	}
+	for _, result := range results {
+		suggestions.Articles = append(suggestions.Articles, dto.KnowledgeArticleSuggestionDTO{
+			ID:             result.article.ID,
+			Title:          result.article.Title,
+			TitleHighlight: result.hit.TitleHighlight,
+		})
+	}
+	return suggestions, nil
+}
+
+// ReindexSearch renvoie tous les articles au moteur de recherche (rattrape les articles créés hors de ce service)
+func (s *knowledgeArticleService) ReindexSearch() (int, error) {
+	count, err := s.search.reindex()
+	if err != nil {
+		return count, fmt.Errorf("erreur lors de l'indexation des articles: %w", err)
+	}
+	return count, nil
+}
+
 // Update met à jour un article
 func (s *knowledgeArticleService) Update(id uint, req dto.UpdateKnowledgeArticleRequest, updatedByID uint) (*dto.KnowledgeArticleDTO, error) {
 	article, err := s.articleRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("article introuvable")
+		return nil, utils.ErrArticleNotFound
 	}
 
 	// Vérifier que l'utilisateur est l'auteur ou a les droits
@@ -189,33 +300,68 @@ func (s *knowledgeArticleService) Update(id uint, req dto.UpdateKnowledgeArticle
 		// TODO: Vérifier les permissions (admin, etc.)
 	}
 
+	// Version courante, enregistrée si l'article n'en a encore aucune (articles antérieurs au versionnement)
+	previous := articleRevision(article, article.AuthorID, "Version initiale")
+
 	// Mettre à jour les champs fournis
 	if req.Title != "" {
 		article.Title = req.Title
 	}
+	if req.ContentFormat != "" {
+		article.ContentFormat = req.ContentFormat
+	}
 	if req.Content != "" {
-		article.Content = req.Content
+		content, err := s.extractEmbeddedImages(article, richtext.Prepare(req.Content, article.ContentFormat), article.ContentFormat, updatedByID)
+		if err != nil {
+			return nil, err
+		}
+		article.Content = content
 	}
 	if req.CategoryID != nil {
 		// Vérifier que la catégorie existe
 		_, err = s.categoryRepo.FindByID(*req.CategoryID)
 		if err != nil {
-			return nil, errors.New("catégorie introuvable")
+			return nil, utils.ErrCategoryNotFound
 		}
 		article.CategoryID = *req.CategoryID
 	}
 	if req.IsPublished != nil {
-		article.IsPublished = *req.IsPublished
+		if err := s.changeStatus(article, publicationStatus(article, *req.IsPublished)); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsPublic != nil {
+		article.IsPublic = *req.IsPublic
+	}
+	if req.ReviewerID != nil {
+		if err := s.assignReviewer(article, *req.ReviewerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.ReviewDueAt != nil {
+		dueAt := *req.ReviewDueAt
+		article.ReviewDueAt = &dueAt
+		article.ReviewAlertedAt = nil
+	}
+
+	changed := revisionChanged(previous, article)
+	if changed {
+		s.ensureBaseline(previous)
+		article.Version++
 	}
 
 	if err := s.articleRepo.Update(article); err != nil {
-		return nil, errors.New("erreur lors de la mise à jour de l'article")
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'article")
+	}
+	if changed {
+		s.createRevision(article, updatedByID, req.ChangeSummary)
+		s.search.index(article)
 	}
 
 	// Récupérer l'article mis à jour
 	updatedArticle, err := s.articleRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de l'article mis à jour")
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'article mis à jour")
 	}
 
 	articleDTO := s.articleToDTO(updatedArticle)
@@ -226,19 +372,21 @@ func (s *knowledgeArticleService) Update(id uint, req dto.UpdateKnowledgeArticle
 func (s *knowledgeArticleService) Publish(id uint, published bool, updatedByID uint) (*dto.KnowledgeArticleDTO, error) {
 	article, err := s.articleRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("article introuvable")
+		return nil, utils.ErrArticleNotFound
 	}
 
-	article.IsPublished = published
+	if err := s.changeStatus(article, publicationStatus(article, published)); err != nil {
+		return nil, err
+	}
 
 	if err := s.articleRepo.Update(article); err != nil {
-		return nil, errors.New("erreur lors de la mise à jour de l'article")
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'article")
 	}
 
 	// Récupérer l'article mis à jour
 	updatedArticle, err := s.articleRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de l'article mis à jour")
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'article mis à jour")
 	}
 
 	articleDTO := s.articleToDTO(updatedArticle)
@@ -249,12 +397,13 @@ func (s *knowledgeArticleService) Publish(id uint, published bool, updatedByID u
 func (s *knowledgeArticleService) Delete(id uint) error {
 	_, err := s.articleRepo.FindByID(id)
 	if err != nil {
-		return errors.New("article introuvable")
+		return utils.ErrArticleNotFound
 	}
 
 	if err := s.articleRepo.Delete(id); err != nil {
-		return errors.New("erreur lors de la suppression de l'article")
+		return utils.NewInternalError("erreur lors de la suppression de l'article")
 	}
+	s.search.remove(id)
 
 	return nil
 }
@@ -264,18 +413,586 @@ func (s *knowledgeArticleService) IncrementViewCount(id uint) error {
 	return s.articleRepo.IncrementViewCount(id)
 }
 
+// GetRevisions récupère l'historique des versions d'un article (sans leur contenu)
+func (s *knowledgeArticleService) GetRevisions(id uint) ([]dto.KnowledgeArticleRevisionDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	revisions, err := s.articleRepo.FindRevisions(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des versions de l'article")
+	}
+	if len(revisions) == 0 {
+		// Article antérieur au versionnement : seule la version courante existe
+		revisions = []models.KnowledgeArticleRevision{*articleRevision(article, article.AuthorID, "Version initiale")}
+		revisions[0].Editor = article.Author
+	}
+
+	revisionDTOs := make([]dto.KnowledgeArticleRevisionDTO, 0, len(revisions))
+	for i := range revisions {
+		revisionDTO := s.revisionToDTO(&revisions[i], article.Version)
+		revisionDTO.Content = ""
+		revisionDTOs = append(revisionDTOs, revisionDTO)
+	}
+	return revisionDTOs, nil
+}
+
+// GetRevision récupère une version d'un article avec son contenu
+func (s *knowledgeArticleService) GetRevision(id uint, version int) (*dto.KnowledgeArticleRevisionDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	revision, err := s.findVersion(article, version)
+	if err != nil {
+		return nil, err
+	}
+	revisionDTO := s.revisionToDTO(revision, article.Version)
+	return &revisionDTO, nil
+}
+
+// Diff compare deux versions d'un article (par défaut la version courante et la précédente)
+func (s *knowledgeArticleService) Diff(id uint, fromVersion, toVersion int) (*dto.KnowledgeArticleDiffDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	if toVersion == 0 {
+		toVersion = article.Version
+	}
+	if fromVersion == 0 {
+		fromVersion = toVersion - 1
+	}
+	if fromVersion < 1 {
+		return nil, errors.New("aucune version antérieure à comparer")
+	}
+
+	from, err := s.findVersion(article, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findVersion(article, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &dto.KnowledgeArticleDiffDTO{
+		ArticleID:     id,
+		FromVersion:   fromVersion,
+		ToVersion:     toVersion,
+		ChangedFields: []string{},
+		TitleFrom:     from.Title,
+		TitleTo:       to.Title,
+		Lines:         diffLines(from.Content, to.Content),
+	}
+	if from.Title != to.Title {
+		diff.ChangedFields = append(diff.ChangedFields, "title")
+	}
+	if from.Content != to.Content {
+		diff.ChangedFields = append(diff.ChangedFields, "content")
+	}
+	if richtext.NormalizeFormat(from.ContentFormat) != richtext.NormalizeFormat(to.ContentFormat) {
+		diff.ChangedFields = append(diff.ChangedFields, "content_format")
+	}
+	if from.CategoryID != to.CategoryID {
+		diff.ChangedFields = append(diff.ChangedFields, "category_id")
+	}
+	for _, line := range diff.Lines {
+		switch line.Op {
+		case "added":
+			diff.Added++
+		case "removed":
+			diff.Removed++
+		}
+	}
+	return diff, nil
+}
+
+// Restore rétablit le contenu d'une version antérieure, enregistré comme nouvelle version
+func (s *knowledgeArticleService) Restore(id uint, version int, restoredByID uint) (*dto.KnowledgeArticleDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	if version == article.Version {
+		return nil, errors.New("cette version est déjà la version courante de l'article")
+	}
+	revision, err := s.articleRepo.FindRevision(id, version)
+	if err != nil {
+		return nil, utils.ErrArticleVersionNotFound
+	}
+
+	previous := articleRevision(article, article.AuthorID, "Version initiale")
+	article.Title = revision.Title
+	article.Content = revision.Content
+	article.ContentFormat = revision.ContentFormat
+	// La catégorie d'origine peut avoir été supprimée entre-temps : l'article reste alors dans sa catégorie actuelle
+	if _, err := s.categoryRepo.FindByID(revision.CategoryID); err == nil {
+		article.CategoryID = revision.CategoryID
+	}
+	if !revisionChanged(previous, article) {
+		return nil, errors.New("cette version est identique à la version courante de l'article")
+	}
+	s.ensureBaseline(previous)
+	article.Version++
+
+	if err := s.articleRepo.Update(article); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la restauration de la version")
+	}
+	s.createRevision(article, restoredByID, fmt.Sprintf("Restauration de la version %d", version))
+	s.search.index(article)
+	return s.reload(id)
+}
+
+// SubmitForReview soumet un article au relecteur désigné
+func (s *knowledgeArticleService) SubmitForReview(id uint, req dto.SubmitKnowledgeReviewRequest, submittedByID uint) (*dto.KnowledgeArticleDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	if err := s.changeStatus(article, models.KnowledgeStatusInReview); err != nil {
+		return nil, err
+	}
+	if req.ReviewerID != nil {
+		if err := s.assignReviewer(article, *req.ReviewerID); err != nil {
+			return nil, err
+		}
+	}
+	if article.ReviewerID == nil {
+		return nil, errors.New("un relecteur doit être désigné")
+	}
+	dueAt := time.Now().Add(knowledgeReviewDelay)
+	if req.ReviewDueAt != nil {
+		dueAt = *req.ReviewDueAt
+	}
+	article.ReviewDueAt = &dueAt
+	article.ReviewAlertedAt = nil
+
+	if err := s.articleRepo.Update(article); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'article")
+	}
+
+	message := "Relecture attendue avant le " + dueAt.Format("02/01/2006")
+	if comment := strings.TrimSpace(req.Comment); comment != "" {
+		message += " - " + comment
+	}
+	if *article.ReviewerID != submittedByID {
+		s.notify(*article.ReviewerID, article, "knowledge_review_requested", "Article à relire : "+article.Title, message)
+	}
+	return s.reload(id)
+}
+
+// Review enregistre la décision du relecteur : publication ou retour en brouillon
+func (s *knowledgeArticleService) Review(id uint, req dto.KnowledgeReviewDecisionRequest, reviewerID uint, canPublish bool) (*dto.KnowledgeArticleDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	if article.Status != models.KnowledgeStatusInReview {
+		return nil, errors.New("l'article n'est pas en relecture")
+	}
+	if !canPublish && (article.ReviewerID == nil || *article.ReviewerID != reviewerID) {
+		return nil, utils.ErrArticleReviewer
+	}
+
+	now := time.Now()
+	comment := strings.TrimSpace(req.Comment)
+	var notificationType, title string
+	if req.Decision == "approve" {
+		setKnowledgeStatus(article, models.KnowledgeStatusPublished, now)
+		nextReview := now.Add(knowledgeReviewInterval)
+		if req.NextReviewAt != nil {
+			nextReview = *req.NextReviewAt
+		}
+		article.ReviewDueAt = &nextReview
+		notificationType, title = "knowledge_review_approved", "Article publié : "+article.Title
+	} else {
+		if comment == "" {
+			return nil, errors.New("un commentaire est obligatoire pour renvoyer l'article en brouillon")
+		}
+		setKnowledgeStatus(article, models.KnowledgeStatusDraft, now)
+		article.ReviewDueAt = nil
+		notificationType, title = "knowledge_review_rejected", "Article à reprendre : "+article.Title
+	}
+	article.ReviewAlertedAt = nil
+	article.LastReviewedAt = &now
+	article.ReviewComment = comment
+
+	if err := s.articleRepo.Update(article); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'article")
+	}
+	if article.AuthorID != reviewerID {
+		message := comment
+		if message == "" {
+			message = "L'article a été approuvé par le relecteur"
+		}
+		s.notify(article.AuthorID, article, notificationType, title, message)
+	}
+	return s.reload(id)
+}
+
+// Archive retire un article de la publication sans le supprimer
+func (s *knowledgeArticleService) Archive(id uint, archivedByID uint) (*dto.KnowledgeArticleDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	if err := s.changeStatus(article, models.KnowledgeStatusArchived); err != nil {
+		return nil, err
+	}
+	if err := s.articleRepo.Update(article); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'article")
+	}
+	return s.reload(id)
+}
+
+// GetReviewQueue récupère les articles en relecture et ceux dont la révision périodique est échue
+func (s *knowledgeArticleService) GetReviewQueue(userID uint, all bool) (*dto.KnowledgeReviewQueueDTO, error) {
+	var filter *uint
+	if !all {
+		filter = &userID
+	}
+	inReview, err := s.articleRepo.FindInReview(filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles en relecture")
+	}
+	reviewDue, err := s.articleRepo.FindReviewDue(time.Now(), filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles à réviser")
+	}
+
+	queue := &dto.KnowledgeReviewQueueDTO{
+		InReview:  make([]dto.KnowledgeArticleDTO, 0, len(inReview)),
+		ReviewDue: make([]dto.KnowledgeArticleDTO, 0, len(reviewDue)),
+	}
+	for i := range inReview {
+		queue.InReview = append(queue.InReview, s.articleToDTO(&inReview[i]))
+	}
+	for i := range reviewDue {
+		queue.ReviewDue = append(queue.ReviewDue, s.articleToDTO(&reviewDue[i]))
+	}
+	return queue, nil
+}
+
+// SendReviewAlerts alerte le relecteur (à défaut l'auteur) des relectures en retard et des articles à réviser
+// Chaque échéance n'est signalée qu'une fois
+func (s *knowledgeArticleService) SendReviewAlerts() (int, error) {
+	now := time.Now()
+	articles, err := s.articleRepo.FindReviewAlerts(now)
+	if err != nil {
+		return 0, fmt.Errorf("articles à relire: %w", err)
+	}
+
+	sent := 0
+	for i := range articles {
+		article := &articles[i]
+		recipientID := article.AuthorID
+		if article.ReviewerID != nil {
+			recipientID = *article.ReviewerID
+		}
+		dueAt := article.ReviewDueAt.Format("02/01/2006")
+		if article.Status == models.KnowledgeStatusInReview {
+			s.notify(recipientID, article, "knowledge_review_overdue", "Relecture en retard : "+article.Title, "Relecture attendue le "+dueAt)
+		} else {
+			s.notify(recipientID, article, "knowledge_review_due", "Article à réviser : "+article.Title, "Révision périodique prévue le "+dueAt)
+		}
+		if err := s.articleRepo.MarkReviewAlerted(article.ID, now); err != nil {
+			log.Printf("Erreur lors de l'enregistrement de l'alerte de relecture de l'article %d: %v", article.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// changeStatus contrôle et applique un changement de statut (sans effet si l'article a déjà ce statut)
+func (s *knowledgeArticleService) changeStatus(article *models.KnowledgeArticle, to string) error {
+	from := article.Status
+	if from == to {
+		return nil
+	}
+	if !slices.Contains(knowledgeStatusTransitions[from], to) {
+		return utils.ErrArticleTransition.WithDetails(map[string]any{
+			"from":    from,
+			"to":      to,
+			"allowed": knowledgeStatusTransitions[from],
+		})
+	}
+	setKnowledgeStatus(article, to, time.Now())
+	if to != models.KnowledgeStatusPublished && to != models.KnowledgeStatusInReview {
+		article.ReviewDueAt = nil
+		article.ReviewAlertedAt = nil
+	}
+	return nil
+}
+
+// assignReviewer désigne le relecteur de l'article (0 = aucun)
+func (s *knowledgeArticleService) assignReviewer(article *models.KnowledgeArticle, reviewerID uint) error {
+	if reviewerID == 0 {
+		article.ReviewerID = nil
+		return nil
+	}
+	reviewer, err := s.userRepo.FindByID(reviewerID)
+	if err != nil {
+		return utils.ErrUserNotFound
+	}
+	if !reviewer.IsActive {
+		return errors.New("le relecteur doit être un utilisateur actif")
+	}
+	article.ReviewerID = &reviewer.ID
+	return nil
+}
+
+// findVersion récupère une version d'un article ; la version courante est reconstituée si elle n'a pas été enregistrée
+func (s *knowledgeArticleService) findVersion(article *models.KnowledgeArticle, version int) (*models.KnowledgeArticleRevision, error) {
+	revision, err := s.articleRepo.FindRevision(article.ID, version)
+	if err == nil {
+		return revision, nil
+	}
+	if version != article.Version {
+		return nil, utils.ErrArticleVersionNotFound
+	}
+	revision = articleRevision(article, article.AuthorID, "")
+	revision.Editor = article.Author
+	return revision, nil
+}
+
+// ensureBaseline enregistre la version courante d'un article qui n'a encore aucune version (articles antérieurs au versionnement)
+func (s *knowledgeArticleService) ensureBaseline(current *models.KnowledgeArticleRevision) {
+	count, err := s.articleRepo.CountRevisions(current.ArticleID)
+	if err != nil || count > 0 {
+		return
+	}
+	if err := s.articleRepo.CreateRevision(current); err != nil {
+		log.Printf("Erreur lors de l'enregistrement de la version initiale de l'article %d: %v", current.ArticleID, err)
+	}
+}
+
+// createRevision enregistre la version courante d'un article
+func (s *knowledgeArticleService) createRevision(article *models.KnowledgeArticle, editorID uint, summary string) {
+	revision := articleRevision(article, editorID, strings.TrimSpace(summary))
+	if err := s.articleRepo.CreateRevision(revision); err != nil {
+		log.Printf("Erreur lors de l'enregistrement de la version %d de l'article %d: %v", article.Version, article.ID, err)
+	}
+}
+
+// reload récupère l'article à jour avec ses relations
+func (s *knowledgeArticleService) reload(id uint) (*dto.KnowledgeArticleDTO, error) {
+	article, err := s.articleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'article mis à jour")
+	}
+	articleDTO := s.articleToDTO(article)
+	return &articleDTO, nil
+}
+
+// notify envoie une notification de relecture, en journalisant l'échec éventuel
+func (s *knowledgeArticleService) notify(userID uint, article *models.KnowledgeArticle, notificationType, title, message string) {
+	metadata := map[string]any{
+		"article_id": article.ID,
+		"status":     article.Status,
+	}
+	linkURL := fmt.Sprintf("/knowledge-base/articles/%d", article.ID)
+	if err := s.notificationService.Create(userID, notificationType, title, message, linkURL, metadata); err != nil {
+		log.Printf("Erreur lors de la notification %s de l'article %d à l'utilisateur %d: %v", notificationType, article.ID, userID, err)
+	}
+}
+
+// revisionToDTO convertit une version d'article en DTO
+func (s *knowledgeArticleService) revisionToDTO(revision *models.KnowledgeArticleRevision, currentVersion int) dto.KnowledgeArticleRevisionDTO {
+	revisionDTO := dto.KnowledgeArticleRevisionDTO{
+		Version:       revision.Version,
+		Title:         revision.Title,
+		Content:       revision.Content,
+		ContentFormat: richtext.NormalizeFormat(revision.ContentFormat),
+		CategoryID:    revision.CategoryID,
+		EditorID:      revision.EditorID,
+		Summary:       revision.Summary,
+		Current:       revision.Version == currentVersion,
+		CreatedAt:     revision.CreatedAt,
+	}
+	if revision.Editor.ID != 0 {
+		editor := s.userToDTO(&revision.Editor)
+		revisionDTO.Editor = &editor
+	}
+	return revisionDTO
+}
+
+// setKnowledgeStatus applique un statut et tient IsPublished à jour
+// Un article publié sans échéance de révision reçoit l'échéance périodique par défaut
+func setKnowledgeStatus(article *models.KnowledgeArticle, status string, now time.Time) {
+	article.Status = status
+	article.IsPublished = status == models.KnowledgeStatusPublished
+	if article.IsPublished && article.ReviewDueAt == nil {
+		dueAt := now.Add(knowledgeReviewInterval)
+		article.ReviewDueAt = &dueAt
+		article.ReviewAlertedAt = nil
+	}
+}
+
+// publicationStatus traduit la publication / dépublication directe en statut
+func publicationStatus(article *models.KnowledgeArticle, published bool) string {
+	if published {
+		return models.KnowledgeStatusPublished
+	}
+	if article.Status == models.KnowledgeStatusPublished {
+		return models.KnowledgeStatusDraft
+	}
+	return article.Status
+}
+
+// articleRevision construit la version correspondant à l'état courant d'un article
+func articleRevision(article *models.KnowledgeArticle, editorID uint, summary string) *models.KnowledgeArticleRevision {
+	return &models.KnowledgeArticleRevision{
+		ArticleID:     article.ID,
+		Version:       article.Version,
+		Title:         article.Title,
+		Content:       article.Content,
+		ContentFormat: article.ContentFormat,
+		CategoryID:    article.CategoryID,
+		EditorID:      editorID,
+		Summary:       summary,
+		CreatedAt:     article.UpdatedAt,
+	}
+}
+
+// revisionChanged indique si le titre, le contenu, le format ou la catégorie de l'article diffèrent de la version
+func revisionChanged(revision *models.KnowledgeArticleRevision, article *models.KnowledgeArticle) bool {
+	return revision.Title != article.Title || revision.Content != article.Content ||
+		revision.ContentFormat != article.ContentFormat || revision.CategoryID != article.CategoryID
+}
+
+// maxDiffCells taille maximale de la table de comparaison ; au-delà, les lignes divergentes sont présentées comme remplacées en bloc
+const maxDiffCells = 4_000_000
+
+// diffLines compare deux contenus ligne à ligne (plus longue sous-séquence commune)
+func diffLines(from, to string) []dto.KnowledgeDiffLineDTO {
+	a, b := splitLines(from), splitLines(to)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]dto.KnowledgeDiffLineDTO, 0, len(a)+len(b)-prefix-suffix)
+	oldLine, newLine := 1, 1
+	emit := func(op, text string) {
+		line := dto.KnowledgeDiffLineDTO{Op: op, Text: text}
+		switch op {
+		case "equal":
+			line.OldLine, line.NewLine = oldLine, newLine
+			oldLine++
+			newLine++
+		case "removed":
+			line.OldLine = oldLine
+			oldLine++
+		case "added":
+			line.NewLine = newLine
+			newLine++
+		}
+		lines = append(lines, line)
+	}
+
+	for _, text := range a[:prefix] {
+		emit("equal", text)
+	}
+	oldMid, newMid := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(oldMid), len(newMid)
+	i, j := 0, 0
+	if n*m <= maxDiffCells {
+		lcs := make([][]int32, n+1)
+		for k := range lcs {
+			lcs[k] = make([]int32, m+1)
+		}
+		for x := n - 1; x >= 0; x-- {
+			for y := m - 1; y >= 0; y-- {
+				if oldMid[x] == newMid[y] {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else {
+					lcs[x][y] = max(lcs[x+1][y], lcs[x][y+1])
+				}
+			}
+		}
+		for i < n && j < m {
+			switch {
+			case oldMid[i] == newMid[j]:
+				emit("equal", oldMid[i])
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				emit("removed", oldMid[i])
+				i++
+			default:
+				emit("added", newMid[j])
+				j++
+			}
+		}
+	}
+	for ; i < n; i++ {
+		emit("removed", oldMid[i])
+	}
+	for ; j < m; j++ {
+		emit("added", newMid[j])
+	}
+	for _, text := range a[len(a)-suffix:] {
+		emit("equal", text)
+	}
+	return lines
+}
+
+// splitLines découpe un contenu en lignes (aucune ligne pour un contenu vide)
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+}
+
+// extractEmbeddedImages enregistre les images intégrées (data URI) du contenu et les remplace par leur lien de téléchargement
+// Chaque image est vérifiée (type réel, taille, antivirus, quota de la filiale) ; une image déjà jointe à l'article est réutilisée
+func (s *knowledgeArticleService) extractEmbeddedImages(article *models.KnowledgeArticle, content, format string, userID uint) (string, error) {
+	ctx := context.Background()
+	return richtext.ExtractImages(content, format, func(image richtext.Image) (string, error) {
+		attachment, _, err := s.attachments.store(ctx, article, image.Name, bytes.NewReader(image.Data), true, &userID)
+		if err != nil {
+			return "", err
+		}
+		return knowledgeAttachmentURL(article.ID, attachment.ID), nil
+	})
+}
+
 // articleToDTO convertit un modèle KnowledgeArticle en DTO
 func (s *knowledgeArticleService) articleToDTO(article *models.KnowledgeArticle) dto.KnowledgeArticleDTO {
 	articleDTO := dto.KnowledgeArticleDTO{
-		ID:          article.ID,
-		Title:       article.Title,
-		Content:     article.Content,
-		CategoryID:  article.CategoryID,
-		AuthorID:    article.AuthorID,
-		IsPublished: article.IsPublished,
-		ViewCount:   article.ViewCount,
-		CreatedAt:   article.CreatedAt,
-		UpdatedAt:   article.UpdatedAt,
+		ID:              article.ID,
+		Title:           article.Title,
+		Content:         article.Content,
+		ContentFormat:   richtext.NormalizeFormat(article.ContentFormat),
+		ContentHTML:     richtext.Render(article.Content, article.ContentFormat),
+		CategoryID:      article.CategoryID,
+		AuthorID:        article.AuthorID,
+		SourceTicketID:  article.SourceTicketID,
+		IsPublic:        article.IsPublic,
+		IsPublished:     article.IsPublished,
+		Status:          article.Status,
+		Version:         article.Version,
+		ViewCount:       article.ViewCount,
+		HelpfulCount:    article.HelpfulCount,
+		NotHelpfulCount: article.NotHelpfulCount,
+		HelpfulRate:     article.HelpfulRate,
+		ReviewerID:      article.ReviewerID,
+		ReviewDueAt:     article.ReviewDueAt,
+		LastReviewedAt:  article.LastReviewedAt,
+		ReviewComment:   article.ReviewComment,
+		CreatedAt:       article.CreatedAt,
+		UpdatedAt:       article.UpdatedAt,
+	}
+	if article.ReviewDueAt != nil && article.ReviewDueAt.Before(time.Now()) {
+		articleDTO.ReviewOverdue = article.Status == models.KnowledgeStatusInReview || article.Status == models.KnowledgeStatusPublished
 	}
 
 	// Convertir la catégorie si présente
@@ -294,21 +1011,24 @@ func (s *knowledgeArticleService) articleToDTO(article *models.KnowledgeArticle)
 }
 
 // articleToSearchResultDTO convertit un modèle KnowledgeArticle en DTO de recherche
-func (s *knowledgeArticleService) articleToSearchResultDTO(article *models.KnowledgeArticle) dto.KnowledgeArticleSearchResultDTO {
+func (s *knowledgeArticleService) articleToSearchResultDTO(article *models.KnowledgeArticle, hit kbsearch.Hit) dto.KnowledgeArticleSearchResultDTO {
 	// Créer un extrait du contenu (premiers 200 caractères)
-	snippet := article.Content
-	if len(snippet) > 200 {
-		snippet = snippet[:200] + "..."
+	snippet := richtext.PlainText(article.Content, article.ContentFormat)
+	if utf8.RuneCountInString(snippet) > 200 {
+		snippet = truncateRunes(snippet, 200) + "..."
 	}
 
 	resultDTO := dto.KnowledgeArticleSearchResultDTO{
-		ID:         article.ID,
-		Title:      article.Title,
-		Snippet:    snippet,
-		CategoryID: article.CategoryID,
-		AuthorID:   article.AuthorID,
-		ViewCount:  article.ViewCount,
-		CreatedAt:  article.CreatedAt,
+		ID:             article.ID,
+		Title:          article.Title,
+		Snippet:        snippet,
+		TitleHighlight: hit.TitleHighlight,
+		Highlight:      hit.Highlight,
+		Score:          hit.Score,
+		CategoryID:     article.CategoryID,
+		AuthorID:       article.AuthorID,
+		ViewCount:      article.ViewCount,
+		CreatedAt:      article.CreatedAt,
 	}
 
 	// Convertir la catégorie si présente
@@ -387,12 +1107,12 @@ func (s *knowledgeCategoryService) Create(req dto.CreateKnowledgeCategoryRequest
 	}
 
 	if err := s.categoryRepo.Create(category); err != nil {
-		return nil, errors.New("erreur lors de la création de la catégorie")
+		return nil, utils.NewInternalError("erreur lors de la création de la catégorie")
 	}
 
 	createdCategory, err := s.categoryRepo.FindByID(category.ID)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de la catégorie créée")
+		return nil, utils.NewInternalError("erreur lors de la récupération de la catégorie créée")
 	}
 
 	categoryDTO := s.categoryToDTO(createdCategory)
@@ -403,7 +1123,7 @@ func (s *knowledgeCategoryService) Create(req dto.CreateKnowledgeCategoryRequest
 func (s *knowledgeCategoryService) GetByID(id uint) (*dto.KnowledgeCategoryDTO, error) {
 	category, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	categoryDTO := s.categoryToDTO(category)
@@ -414,7 +1134,7 @@ func (s *knowledgeCategoryService) GetByID(id uint) (*dto.KnowledgeCategoryDTO,
 func (s *knowledgeCategoryService) GetAll() ([]dto.KnowledgeCategoryDTO, error) {
 	categories, err := s.categoryRepo.FindAll()
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des catégories")
+		return nil, utils.NewInternalError("erreur lors de la récupération des catégories")
 	}
 
 	var categoryDTOs []dto.KnowledgeCategoryDTO
@@ -429,7 +1149,7 @@ func (s *knowledgeCategoryService) GetAll() ([]dto.KnowledgeCategoryDTO, error)
 func (s *knowledgeCategoryService) GetByParentID(parentID uint) ([]dto.KnowledgeCategoryDTO, error) {
 	categories, err := s.categoryRepo.FindByParentID(parentID)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des catégories")
+		return nil, utils.NewInternalError("erreur lors de la récupération des catégories")
 	}
 
 	var categoryDTOs []dto.KnowledgeCategoryDTO
@@ -444,7 +1164,7 @@ func (s *knowledgeCategoryService) GetByParentID(parentID uint) ([]dto.Knowledge
 func (s *knowledgeCategoryService) GetActive() ([]dto.KnowledgeCategoryDTO, error) {
 	categories, err := s.categoryRepo.FindActive()
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des catégories")
+		return nil, utils.NewInternalError("erreur lors de la récupération des catégories")
 	}
 
 	var categoryDTOs []dto.KnowledgeCategoryDTO
@@ -459,7 +1179,7 @@ func (s *knowledgeCategoryService) GetActive() ([]dto.KnowledgeCategoryDTO, erro
 func (s *knowledgeCategoryService) Update(id uint, req dto.UpdateKnowledgeCategoryRequest, updatedByID uint) (*dto.KnowledgeCategoryDTO, error) {
 	category, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	if req.Name != "" {
@@ -473,12 +1193,12 @@ func (s *knowledgeCategoryService) Update(id uint, req dto.UpdateKnowledgeCatego
 	}
 
 	if err := s.categoryRepo.Update(category); err != nil {
-		return nil, errors.New("erreur lors de la mise à jour de la catégorie")
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la catégorie")
 	}
 
 	updatedCategory, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de la catégorie mise à jour")
+		return nil, utils.NewInternalError("erreur lors de la récupération de la catégorie mise à jour")
 	}
 
 	categoryDTO := s.categoryToDTO(updatedCategory)
@@ -489,11 +1209,11 @@ func (s *knowledgeCategoryService) Update(id uint, req dto.UpdateKnowledgeCatego
 func (s *knowledgeCategoryService) Delete(id uint) error {
 	_, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return errors.New("catégorie introuvable")
+		return utils.ErrCategoryNotFound
 	}
 
 	if err := s.categoryRepo.Delete(id); err != nil {
-		return errors.New("erreur lors de la suppression de la catégorie")
+		return utils.NewInternalError("erreur lors de la suppression de la catégorie")
 	}
 
 	return nil
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/antivirus"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/storage"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// knowledgeInlineImageTypes types des images pouvant être intégrées au contenu d'un article
+var knowledgeInlineImageTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// KnowledgeAttachmentService interface pour les pièces jointes des articles et les quotas de stockage
+type KnowledgeAttachmentService interface {
+	// Upload vérifie le fichier (extension, contenu, taille, antivirus, quota de la filiale) avant de le joindre à l'article
+	Upload(scope interface{}, articleID uint, fileName string, content io.Reader, inline bool, userID uint) (*dto.KnowledgeArticleAttachmentDTO, error)
+	GetByArticle(scope interface{}, articleID uint) ([]dto.KnowledgeArticleAttachmentDTO, error)
+	Open(scope interface{}, articleID, attachmentID uint) (*storage.Object, *dto.KnowledgeArticleAttachmentDTO, error)
+	Delete(scope interface{}, articleID, attachmentID uint) error
+	GetStorageUsage() ([]dto.KnowledgeStorageUsageDTO, error)
+	SetQuota(filialeID uint, maxBytes int64, updatedByID uint) (*dto.KnowledgeStorageUsageDTO, error)
+	ResetQuota(filialeID uint) (*dto.KnowledgeStorageUsageDTO, error) // Rétablit le quota par défaut
+}
+
+// knowledgeAttachmentService implémente KnowledgeAttachmentService
+type knowledgeAttachmentService struct {
+	attachmentRepo repositories.KnowledgeAttachmentRepository
+	articleRepo    repositories.KnowledgeArticleRepository
+	filialeRepo    repositories.FilialeRepository
+	attachments    *knowledgeAttachments
+}
+
+// NewKnowledgeAttachmentService crée une nouvelle instance de KnowledgeAttachmentService
+func NewKnowledgeAttachmentService(
+	attachmentRepo repositories.KnowledgeAttachmentRepository,
+	articleRepo repositories.KnowledgeArticleRepository,
+	filialeRepo repositories.FilialeRepository,
+	fileStorage storage.Storage,
+	scanner antivirus.Scanner,
+) KnowledgeAttachmentService {
+	return &knowledgeAttachmentService{
+		attachmentRepo: attachmentRepo,
+		articleRepo:    articleRepo,
+		filialeRepo:    filialeRepo,
+		attachments: &knowledgeAttachments{
+			articleRepo:    articleRepo,
+			attachmentRepo: attachmentRepo,
+			fileStorage:    fileStorage,
+			scanner:        scanner,
+		},
+	}
+}
+
+// Upload joint un fichier à un article ; inline le destine au contenu (images uniquement)
+func (s *knowledgeAttachmentService) Upload(scopeParam interface{}, articleID uint, fileName string, content io.Reader, inline bool, userID uint) (*dto.KnowledgeArticleAttachmentDTO, error) {
+	article, err := s.visibleArticle(scopeParam, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status == models.KnowledgeStatusArchived {
+		return nil, errors.New("impossible de joindre un fichier à un article archivé")
+	}
+
+	attachment, deduplicated, err := s.attachments.store(context.Background(), article, fileName, content, inline, &userID)
+	if err != nil {
+		return nil, err
+	}
+	attachmentDTO := knowledgeAttachmentToDTO(attachment, article.ContentFormat)
+	attachmentDTO.Deduplicated = deduplicated
+	return &attachmentDTO, nil
+}
+
+// GetByArticle liste les pièces jointes d'un article visible
+func (s *knowledgeAttachmentService) GetByArticle(scopeParam interface{}, articleID uint) ([]dto.KnowledgeArticleAttachmentDTO, error) {
+	article, err := s.visibleArticle(scopeParam, articleID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.FindByArticle(articleID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des pièces jointes")
+	}
+	attachmentDTOs := make([]dto.KnowledgeArticleAttachmentDTO, len(attachments))
+	for i := range attachments {
+		attachmentDTOs[i] = knowledgeAttachmentToDTO(&attachments[i], article.ContentFormat)
+	}
+	return attachmentDTOs, nil
+}
+
+// Open ouvre le fichier d'une pièce jointe d'un article visible
+func (s *knowledgeAttachmentService) Open(scopeParam interface{}, articleID, attachmentID uint) (*storage.Object, *dto.KnowledgeArticleAttachmentDTO, error) {
+	article, err := s.visibleArticle(scopeParam, articleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachment, err := s.articleRepo.FindAttachment(articleID, attachmentID)
+	if err != nil {
+		return nil, nil, utils.ErrAttachmentNotFound
+	}
+	object, err := s.attachments.fileStorage.Get(context.Background(), attachment.FilePath)
+	if err != nil {
+		return nil, nil, utils.ErrAttachmentFileMissing
+	}
+	if object.ContentType == "" {
+		object.ContentType = attachment.MimeType
+	}
+	attachmentDTO := knowledgeAttachmentToDTO(attachment, article.ContentFormat)
+	return object, &attachmentDTO, nil
+}
+
+// Delete supprime une pièce jointe qui n'est plus citée dans le contenu de l'article ; le fichier stocké n'est
+// supprimé que s'il n'est plus partagé avec une autre pièce jointe
+func (s *knowledgeAttachmentService) Delete(scopeParam interface{}, articleID, attachmentID uint) error {
+	article, err := s.visibleArticle(scopeParam, articleID)
+	if err != nil {
+		return err
+	}
+	attachment, err := s.articleRepo.FindAttachment(articleID, attachmentID)
+	if err != nil {
+		return utils.ErrAttachmentNotFound
+	}
+	if strings.Contains(article.Content, knowledgeAttachmentURL(articleID, attachmentID)) {
+		return errors.New("la pièce jointe est utilisée dans le contenu de l'article : retirez-la du contenu avant de la supprimer")
+	}
+	return s.attachments.remove(context.Background(), attachment)
+}
+
+// GetStorageUsage retourne l'espace occupé et le quota de chaque filiale ayant des pièces jointes ou un quota propre
+func (s *knowledgeAttachmentService) GetStorageUsage() ([]dto.KnowledgeStorageUsageDTO, error) {
+	usages, err := s.attachmentRepo.UsageByFiliale()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors du calcul de l'espace occupé")
+	}
+	quotas, err := s.attachmentRepo.FindQuotas()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des quotas de stockage")
+	}
+	filiales, err := s.filialeRepo.FindAll()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des filiales")
+	}
+	names := make(map[uint]string, len(filiales))
+	for _, filiale := range filiales {
+		names[filiale.ID] = filiale.Name
+	}
+
+	report := make([]dto.KnowledgeStorageUsageDTO, 0, len(usages)+len(quotas))
+	for i := range usages {
+		quota, isDefault, err := s.attachments.quota(usages[i].FilialeID)
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors de la récupération des quotas de stockage")
+		}
+		report = append(report, storageUsageToDTO(&usages[i], quota, isDefault, names))
+	}
+	for _, quota := range quotas {
+		filialeID := quota.FilialeID
+		if !slices.ContainsFunc(usages, func(u repositories.KnowledgeStorageUsage) bool { return sameUintPtr(u.FilialeID, &filialeID) }) {
+			report = append(report, storageUsageToDTO(&repositories.KnowledgeStorageUsage{FilialeID: &filialeID}, quota.MaxBytes, false, names))
+		}
+	}
+	return report, nil
+}
+
+// SetQuota définit le quota de stockage propre à une filiale
+func (s *knowledgeAttachmentService) SetQuota(filialeID uint, maxBytes int64, updatedByID uint) (*dto.KnowledgeStorageUsageDTO, error) {
+	filiale, err := s.filialeRepo.FindByID(filialeID)
+	if err != nil {
+		return nil, utils.ErrFilialeNotFound
+	}
+	quota, err := s.attachmentRepo.FindQuota(filialeID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewInternalError("erreur lors de la récupération du quota de stockage")
+		}
+		quota = &models.KnowledgeStorageQuota{FilialeID: filialeID}
+	}
+	quota.MaxBytes = maxBytes
+	quota.UpdatedByID = &updatedByID
+	if err := s.attachmentRepo.SaveQuota(quota); err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'enregistrement du quota de stockage")
+	}
+	return s.filialeUsage(filiale)
+}
+
+// ResetQuota supprime le quota propre à une filiale : le quota par défaut s'applique de nouveau
+func (s *knowledgeAttachmentService) ResetQuota(filialeID uint) (*dto.KnowledgeStorageUsageDTO, error) {
+	filiale, err := s.filialeRepo.FindByID(filialeID)
+	if err != nil {
+		return nil, utils.ErrFilialeNotFound
+	}
+	if err := s.attachmentRepo.DeleteQuota(filialeID); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la suppression du quota de stockage")
+	}
+	return s.filialeUsage(filiale)
+}
+
+// filialeUsage retourne l'espace occupé et le quota d'une filiale
+func (s *knowledgeAttachmentService) filialeUsage(filiale *models.Filiale) (*dto.KnowledgeStorageUsageDTO, error) {
+	usage, err := s.attachmentRepo.Usage(&filiale.ID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors du calcul de l'espace occupé")
+	}
+	quota, isDefault, err := s.attachments.quota(&filiale.ID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du quota de stockage")
+	}
+	usageDTO := storageUsageToDTO(usage, quota, isDefault, map[uint]string{filiale.ID: filiale.Name})
+	return &usageDTO, nil
+}
+
+// visibleArticle récupère un article visible dans le périmètre de l'utilisateur
+func (s *knowledgeAttachmentService) visibleArticle(scopeParam interface{}, articleID uint) (*models.KnowledgeArticle, error) {
+	articles, err := s.articleRepo.FindVisibleByIDs(scopeParam, []uint{articleID})
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'article")
+	}
+	if len(articles) == 0 {
+		return nil, utils.ErrArticleNotFound
+	}
+	return &articles[0], nil
+}
+
+// knowledgeAttachments enregistrement des pièces jointes des articles, commun aux téléversements et aux images
+// intégrées au contenu : empreinte du contenu, quota de la filiale et réutilisation des fichiers déjà stockés
+type knowledgeAttachments struct {
+	articleRepo    repositories.KnowledgeArticleRepository
+	attachmentRepo repositories.KnowledgeAttachmentRepository
+	fileStorage    storage.Storage   // Espace "knowledge"
+	scanner        antivirus.Scanner // Analyse antivirus avant stockage
+}
+
+// store vérifie et joint un fichier à un article. Un contenu déjà joint à l'article est retourné tel quel et un contenu
+// déjà stocké pour un autre article réutilise le fichier existant (deduplicated) ; le quota de la filiale de l'article
+// n'est vérifié que pour un fichier qui n'y est pas encore compté
+func (k *knowledgeAttachments) store(ctx context.Context, article *models.KnowledgeArticle, fileName string, content io.Reader, inline bool, uploadedByID *uint) (*models.KnowledgeArticleAttachment, bool, error) {
+	upload, err := prepareAttachmentUpload(ctx, k.scanner, fileName, content)
+	if err != nil {
+		return nil, false, err
+	}
+	defer upload.cleanup()
+	if inline && !slices.Contains(knowledgeInlineImageTypes, upload.mimeType) {
+		return nil, false, utils.ErrAttachmentType.WithDetails(knowledgeInlineImageTypes)
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, upload.file); err != nil {
+		return nil, false, utils.NewInternalError("erreur lors de la lecture du fichier")
+	}
+	if _, err := upload.file.Seek(0, io.SeekStart); err != nil {
+		return nil, false, utils.NewInternalError("erreur lors de la lecture du fichier")
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	existing, err := k.attachmentRepo.FindByHash(hash)
+	if err != nil {
+		return nil, false, utils.NewInternalError("erreur lors de la vérification de la pièce jointe")
+	}
+	for i := range existing {
+		if existing[i].ArticleID == article.ID && existing[i].IsInline == inline {
+			return &existing[i], true, nil
+		}
+	}
+	counted := slices.ContainsFunc(existing, func(a models.KnowledgeArticleAttachment) bool {
+		return a.Article.ID != 0 && sameUintPtr(a.Article.FilialeID, article.FilialeID)
+	})
+	if !counted {
+		if err := k.checkQuota(article.FilialeID, upload.size); err != nil {
+			return nil, false, err
+		}
+	}
+
+	stored := len(existing) == 0
+	key := fmt.Sprintf("sha256/%s/%s", hash[:2], hash)
+	if stored {
+		if err := k.fileStorage.Put(ctx, key, upload.file, upload.size, upload.mimeType); err != nil {
+			return nil, false, utils.NewInternalError("erreur lors de la sauvegarde du fichier")
+		}
+	} else {
+		key = existing[0].FilePath
+	}
+
+	attachment := &models.KnowledgeArticleAttachment{
+		ArticleID:    article.ID,
+		FileName:     fileName,
+		FilePath:     key,
+		FileSize:     int(upload.size),
+		MimeType:     upload.mimeType,
+		ContentHash:  hash,
+		IsInline:     inline,
+		UploadedByID: uploadedByID,
+	}
+	if err := k.articleRepo.CreateAttachment(attachment); err != nil {
+		if stored {
+			_ = k.fileStorage.Delete(ctx, key)
+		}
+		return nil, false, utils.NewInternalError("erreur lors de la création de la pièce jointe")
+	}
+	return attachment, !stored, nil
+}
+
+// remove supprime une pièce jointe puis son fichier s'il n'est plus référencé
+func (k *knowledgeAttachments) remove(ctx context.Context, attachment *models.KnowledgeArticleAttachment) error {
+	if err := k.attachmentRepo.Delete(attachment.ID); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de la pièce jointe")
+	}
+	references, err := k.attachmentRepo.CountByPath(attachment.FilePath)
+	if err != nil || references > 0 {
+		return nil
+	}
+	if err := k.fileStorage.Delete(ctx, attachment.FilePath); err != nil {
+		log.Printf("Erreur lors de la suppression du fichier %s: %v", attachment.FilePath, err)
+	}
+	return nil
+}
+
+// checkQuota vérifie que le fichier tient dans le quota restant de la filiale
+func (k *knowledgeAttachments) checkQuota(filialeID *uint, size int64) error {
+	quota, _, err := k.quota(filialeID)
+	if err != nil {
+		return utils.NewInternalError("erreur lors de la récupération du quota de stockage")
+	}
+	if quota == 0 {
+		return nil
+	}
+	usage, err := k.attachmentRepo.Usage(filialeID)
+	if err != nil {
+		return utils.NewInternalError("erreur lors du calcul de l'espace occupé")
+	}
+	if usage.Bytes+size > quota {
+		return utils.ErrStorageQuotaExceeded.WithDetails(map[string]int64{
+			"quota_bytes": quota,
+			"used_bytes":  usage.Bytes,
+			"file_size":   size,
+		})
+	}
+	return nil
+}
+
+// quota retourne le quota applicable à une filiale (0 = illimité) et indique s'il s'agit du quota par défaut
+// (articles globaux ou filiale sans quota propre)
+func (k *knowledgeAttachments) quota(filialeID *uint) (int64, bool, error) {
+	if filialeID != nil {
+		quota, err := k.attachmentRepo.FindQuota(*filialeID)
+		if err == nil {
+			return quota.MaxBytes, false, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, false, err
+		}
+	}
+	return config.AppConfig.Storage.KnowledgeQuota, true, nil
+}
+
+// knowledgeAttachmentURL retourne le lien de téléchargement d'une pièce jointe, tel qu'il figure dans le contenu
+func knowledgeAttachmentURL(articleID, attachmentID uint) string {
+	return fmt.Sprintf("/api/v1/knowledge-base/articles/%d/attachments/%d", articleID, attachmentID)
+}
+
+// knowledgeAttachmentSnippet retourne le code qui insère la pièce jointe dans un contenu du format donné
+func knowledgeAttachmentSnippet(attachment *models.KnowledgeArticleAttachment, format string) string {
+	url := knowledgeAttachmentURL(attachment.ArticleID, attachment.ID)
+	switch richtext.NormalizeFormat(format) {
+	case richtext.FormatMarkdown:
+		label := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(attachment.FileName)
+		if attachment.IsInline {
+			return fmt.Sprintf("![%s](%s)", label, url)
+		}
+		return fmt.Sprintf("[%s](%s)", label, url)
+	case richtext.FormatHTML:
+		name := html.EscapeString(attachment.FileName)
+		if attachment.IsInline {
+			return fmt.Sprintf(`<img src="%s" alt="%s">`, url, name)
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, url, name)
+	}
+	return url
+}
+
+// knowledgeAttachmentToDTO convertit une pièce jointe d'article en DTO ; format est celui du contenu de l'article
+func knowledgeAttachmentToDTO(attachment *models.KnowledgeArticleAttachment, format string) dto.KnowledgeArticleAttachmentDTO {
+	return dto.KnowledgeArticleAttachmentDTO{
+		ID:           attachment.ID,
+		ArticleID:    attachment.ArticleID,
+		FileName:     attachment.FileName,
+		FileSize:     attachment.FileSize,
+		MimeType:     attachment.MimeType,
+		IsInline:     attachment.IsInline,
+		URL:          knowledgeAttachmentURL(attachment.ArticleID, attachment.ID),
+		Snippet:      knowledgeAttachmentSnippet(attachment, format),
+		UploadedByID: attachment.UploadedByID,
+		CreatedAt:    attachment.CreatedAt,
+	}
+}
+
+// storageUsageToDTO convertit l'espace occupé par une filiale et son quota en DTO
+func storageUsageToDTO(usage *repositories.KnowledgeStorageUsage, quota int64, isDefault bool, names map[uint]string) dto.KnowledgeStorageUsageDTO {
+	usageDTO := dto.KnowledgeStorageUsageDTO{
+		FilialeID:    usage.FilialeID,
+		Files:        usage.Files,
+		UsedBytes:    usage.Bytes,
+		QuotaBytes:   quota,
+		DefaultQuota: isDefault,
+	}
+	if usage.FilialeID != nil {
+		usageDTO.FilialeName = names[*usage.FilialeID]
+	}
+	if quota > 0 {
+		percent := math.Round(float64(usage.Bytes)/float64(quota)*1000) / 10
+		usageDTO.UsedPercent = &percent
+	}
+	return usageDTO
+}
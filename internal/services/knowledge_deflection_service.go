@@ -0,0 +1,250 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/kbsearch"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/scope"
+)
+
+const (
+	knowledgeSuggestTextLength  = 2000 // Caractères de la description pris en compte
+	knowledgeDeflectionTopLimit = 10   // Articles du classement des statistiques
+)
+
+// KnowledgeDeflectionService interface pour la suggestion d'articles pendant la saisie d'un ticket et le suivi de la déviation
+type KnowledgeDeflectionService interface {
+	// Suggest retourne les articles publiés proches du ticket en cours de saisie et enregistre leur affichage
+	Suggest(scope interface{}, userID uint, title, description, sessionID string, limit int) (*dto.KnowledgeTicketSuggestionsDTO, error)
+	Record(scope interface{}, userID uint, req dto.RecordKnowledgeDeflectionRequest) error
+	GetStats(filter repositories.KnowledgeDeflectionFilter) (*dto.KnowledgeDeflectionStatsDTO, error)
+}
+
+// knowledgeDeflectionService implémente KnowledgeDeflectionService
+type knowledgeDeflectionService struct {
+	deflectionRepo repositories.KnowledgeDeflectionRepository
+	search         *knowledgeSearch
+}
+
+// NewKnowledgeDeflectionService crée une nouvelle instance de KnowledgeDeflectionService
+func NewKnowledgeDeflectionService(
+	deflectionRepo repositories.KnowledgeDeflectionRepository,
+	articleRepo repositories.KnowledgeArticleRepository,
+	searchEngine kbsearch.Engine,
+) KnowledgeDeflectionService {
+	return &knowledgeDeflectionService{
+		deflectionRepo: deflectionRepo,
+		search:         &knowledgeSearch{engine: searchEngine, articleRepo: articleRepo},
+	}
+}
+
+// Suggest classe les articles par proximité avec le titre et la description. Les appels successifs d'une même saisie
+// (session_id) n'enregistrent l'affichage d'un article qu'une fois
+func (s *knowledgeDeflectionService) Suggest(scopeParam interface{}, userID uint, title, description, sessionID string, limit int) (*dto.KnowledgeTicketSuggestionsDTO, error) {
+	title = strings.TrimSpace(title)
+	description = strings.TrimSpace(description)
+	if title == "" && description == "" {
+		return nil, errors.New("le titre ou la description du ticket est requis")
+	}
+
+	var events []models.KnowledgeDeflectionEvent
+	if sessionID == "" {
+		sessionID = newDeflectionSessionID()
+	} else {
+		var err error
+		if events, err = s.sessionEvents(sessionID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Le titre vient en tête : certains moteurs ne retiennent que les premiers mots
+	text := strings.TrimSpace(title + "\n" + truncateRunes(description, knowledgeSuggestTextLength))
+	results, err := s.search.related(scopeParam, text, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la recherche des articles")
+	}
+
+	suggestions := &dto.KnowledgeTicketSuggestionsDTO{
+		SessionID: sessionID,
+		Articles:  []dto.KnowledgeArticleSearchResultDTO{},
+	}
+	var shown []models.KnowledgeDeflectionEvent
+	filialeID := deflectionFilialeID(scopeParam)
+	for _, result := range results {
+		suggestions.Articles = append(suggestions.Articles, suggestionToDTO(&result))
+		if hasDeflectionEvent(events, models.KnowledgeDeflectionShown, &result.article.ID) {
+			continue
+		}
+		articleID := result.article.ID
+		shown = append(shown, models.KnowledgeDeflectionEvent{
+			SessionID: sessionID,
+			Event:     models.KnowledgeDeflectionShown,
+			ArticleID: &articleID,
+			UserID:    userID,
+			FilialeID: filialeID,
+			Score:     result.hit.Score,
+			Query:     truncateRunes(title, 255),
+		})
+	}
+	if err := s.deflectionRepo.Create(shown); err != nil {
+		log.Printf("Erreur lors de l'enregistrement des suggestions de la session %s: %v", sessionID, err)
+	}
+
+	return suggestions, nil
+}
+
+// Record enregistre la consultation d'un article suggéré, l'abandon de la saisie ou la création du ticket.
+// L'abandon et la création ne sont enregistrés qu'une fois par session
+func (s *knowledgeDeflectionService) Record(scopeParam interface{}, userID uint, req dto.RecordKnowledgeDeflectionRequest) error {
+	events, err := s.sessionEvents(req.SessionID, userID)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return utils.ErrDeflectionSessionNotFound
+	}
+
+	event := models.KnowledgeDeflectionEvent{
+		SessionID: req.SessionID,
+		Event:     req.Event,
+		UserID:    userID,
+		FilialeID: deflectionFilialeID(scopeParam),
+	}
+	switch req.Event {
+	case models.KnowledgeDeflectionClicked:
+		if req.ArticleID == nil || !hasDeflectionEvent(events, models.KnowledgeDeflectionShown, req.ArticleID) {
+			return errors.New("cet article n'a pas été suggéré dans cette session")
+		}
+		event.ArticleID = req.ArticleID
+	case models.KnowledgeDeflectionAbandoned:
+		if hasDeflectionEvent(events, models.KnowledgeDeflectionAbandoned, nil) {
+			return nil
+		}
+	case models.KnowledgeDeflectionTicketCreated:
+		if hasDeflectionEvent(events, models.KnowledgeDeflectionTicketCreated, nil) {
+			return nil
+		}
+		event.TicketID = req.TicketID
+	default:
+		return errors.New("événement invalide")
+	}
+
+	if err := s.deflectionRepo.Create([]models.KnowledgeDeflectionEvent{event}); err != nil {
+		return utils.NewInternalError("erreur lors de l'enregistrement de l'événement")
+	}
+	return nil
+}
+
+// GetStats calcule le taux de déviation de la période et les articles les plus efficaces
+func (s *knowledgeDeflectionService) GetStats(filter repositories.KnowledgeDeflectionFilter) (*dto.KnowledgeDeflectionStatsDTO, error) {
+	if !filter.To.After(filter.From) {
+		return nil, errors.New("la fin de la période doit être postérieure à son début")
+	}
+	totals, err := s.deflectionRepo.Totals(filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors du calcul des statistiques de déviation")
+	}
+	top, err := s.deflectionRepo.TopArticles(filter, knowledgeDeflectionTopLimit)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors du calcul des statistiques de déviation")
+	}
+
+	stats := &dto.KnowledgeDeflectionStatsDTO{
+		From:            filter.From,
+		To:              filter.To,
+		Sessions:        totals.Sessions,
+		ClickedSessions: totals.ClickedSessions,
+		Deflected:       totals.Deflected,
+		TicketsCreated:  totals.TicketsCreated,
+		TopArticles:     make([]dto.KnowledgeDeflectionArticleStatDTO, len(top)),
+	}
+	if totals.Sessions > 0 {
+		stats.DeflectionRate = math.Round(float64(totals.Deflected)/float64(totals.Sessions)*1000) / 10
+	}
+	for i, article := range top {
+		stats.TopArticles[i] = dto.KnowledgeDeflectionArticleStatDTO{
+			ArticleID: article.ArticleID,
+			Title:     article.Title,
+			Shown:     article.Shown,
+			Clicked:   article.Clicked,
+			Deflected: article.Deflected,
+		}
+	}
+	return stats, nil
+}
+
+// sessionEvents récupère les événements d'une session, qui doit appartenir à l'utilisateur
+func (s *knowledgeDeflectionService) sessionEvents(sessionID string, userID uint) ([]models.KnowledgeDeflectionEvent, error) {
+	events, err := s.deflectionRepo.FindSessionEvents(sessionID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de la session de suggestion")
+	}
+	if slices.ContainsFunc(events, func(e models.KnowledgeDeflectionEvent) bool { return e.UserID != userID }) {
+		return nil, utils.ErrDeflectionSessionNotFound
+	}
+	return events, nil
+}
+
+// hasDeflectionEvent indique si la session comporte l'événement (pour l'article donné si non nil)
+func hasDeflectionEvent(events []models.KnowledgeDeflectionEvent, event string, articleID *uint) bool {
+	return slices.ContainsFunc(events, func(e models.KnowledgeDeflectionEvent) bool {
+		return e.Event == event && (articleID == nil || sameUintPtr(e.ArticleID, articleID))
+	})
+}
+
+// deflectionFilialeID retourne la filiale de l'utilisateur courant
+func deflectionFilialeID(scopeParam interface{}) *uint {
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		return queryScope.FilialeID
+	}
+	return nil
+}
+
+// newDeflectionSessionID génère l'identifiant d'une session de saisie de ticket
+func newDeflectionSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// suggestionToDTO convertit un article suggéré en DTO de résultat de recherche
+func suggestionToDTO(result *knowledgeSearchResult) dto.KnowledgeArticleSearchResultDTO {
+	article := &result.article
+	snippet := richtext.PlainText(article.Content, article.ContentFormat)
+	if utf8.RuneCountInString(snippet) > 200 {
+		snippet = truncateRunes(snippet, 200) + "..."
+	}
+	resultDTO := dto.KnowledgeArticleSearchResultDTO{
+		ID:             article.ID,
+		Title:          article.Title,
+		Snippet:        snippet,
+		TitleHighlight: result.hit.TitleHighlight,
+		Highlight:      result.hit.Highlight,
+		Score:          result.hit.Score,
+		CategoryID:     article.CategoryID,
+		AuthorID:       article.AuthorID,
+		ViewCount:      article.ViewCount,
+		CreatedAt:      article.CreatedAt,
+	}
+	if article.Category.ID != 0 {
+		resultDTO.Category = &dto.KnowledgeCategoryDTO{
+			ID:          article.Category.ID,
+			Name:        article.Category.Name,
+			Description: article.Category.Description,
+			ParentID:    article.Category.ParentID,
+		}
+	}
+	return resultDTO
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// knowledgeLowRatedComments nombre de commentaires « pas utile » joints à chaque article du rapport
+const knowledgeLowRatedComments = 3
+
+// KnowledgeFeedbackService interface pour les évaluations des articles de la base de connaissances
+type KnowledgeFeedbackService interface {
+	// Rate enregistre ou remplace l'évaluation de l'article par l'utilisateur
+	Rate(scope interface{}, articleID, userID uint, req dto.RateKnowledgeArticleRequest) (*dto.KnowledgeArticleRatingDTO, error)
+	RemoveRating(scope interface{}, articleID, userID uint) (*dto.KnowledgeArticleRatingDTO, error)
+	// GetRating retourne les agrégats et l'évaluation de l'utilisateur ; withDetails ajoute toutes les évaluations
+	GetRating(scope interface{}, articleID, userID uint, withDetails bool) (*dto.KnowledgeArticleRatingDTO, error)
+	GetLowRated(scope interface{}, filter repositories.KnowledgeLowRatedFilter) ([]dto.LowRatedKnowledgeArticleDTO, error)
+}
+
+// knowledgeFeedbackService implémente KnowledgeFeedbackService
+type knowledgeFeedbackService struct {
+	feedbackRepo repositories.KnowledgeFeedbackRepository
+	articleRepo  repositories.KnowledgeArticleRepository
+}
+
+// NewKnowledgeFeedbackService crée une nouvelle instance de KnowledgeFeedbackService
+func NewKnowledgeFeedbackService(
+	feedbackRepo repositories.KnowledgeFeedbackRepository,
+	articleRepo repositories.KnowledgeArticleRepository,
+) KnowledgeFeedbackService {
+	return &knowledgeFeedbackService{
+		feedbackRepo: feedbackRepo,
+		articleRepo:  articleRepo,
+	}
+}
+
+// Rate enregistre l'évaluation d'un article publié ; une nouvelle évaluation remplace la précédente
+func (s *knowledgeFeedbackService) Rate(scopeParam interface{}, articleID, userID uint, req dto.RateKnowledgeArticleRequest) (*dto.KnowledgeArticleRatingDTO, error) {
+	article, err := s.visibleArticle(scopeParam, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !article.IsPublished {
+		return nil, errors.New("seuls les articles publiés peuvent être évalués")
+	}
+
+	feedback, err := s.feedbackRepo.FindByArticleAndUser(articleID, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewInternalError("erreur lors de la récupération de l'évaluation")
+		}
+		feedback = &models.KnowledgeArticleFeedback{ArticleID: articleID, UserID: userID}
+	}
+	feedback.Helpful = *req.Helpful
+	feedback.Comment = strings.TrimSpace(req.Comment)
+	feedback.ArticleVersion = article.Version
+	if err := s.feedbackRepo.Save(feedback); err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'enregistrement de l'évaluation")
+	}
+
+	scores, err := s.feedbackRepo.RefreshArticleScore(articleID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors du calcul des évaluations de l'article")
+	}
+	rating := ratingToDTO(scores)
+	myFeedback := feedbackToDTO(feedback, article.Version)
+	rating.MyFeedback = &myFeedback
+	return rating, nil
+}
+
+// RemoveRating retire l'évaluation de l'utilisateur (sans effet s'il n'a pas évalué l'article)
+func (s *knowledgeFeedbackService) RemoveRating(scopeParam interface{}, articleID, userID uint) (*dto.KnowledgeArticleRatingDTO, error) {
+	if _, err := s.visibleArticle(scopeParam, articleID); err != nil {
+		return nil, err
+	}
+	if err := s.feedbackRepo.Delete(articleID, userID); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la suppression de l'évaluation")
+	}
+	scores, err := s.feedbackRepo.RefreshArticleScore(articleID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors du calcul des évaluations de l'article")
+	}
+	return ratingToDTO(scores), nil
+}
+
+// GetRating récupère les évaluations agrégées d'un article
+func (s *knowledgeFeedbackService) GetRating(scopeParam interface{}, articleID, userID uint, withDetails bool) (*dto.KnowledgeArticleRatingDTO, error) {
+	article, err := s.visibleArticle(scopeParam, articleID)
+	if err != nil {
+		return nil, err
+	}
+	rating := ratingToDTO(article)
+
+	feedback, err := s.feedbackRepo.FindByArticleAndUser(articleID, userID)
+	if err == nil {
+		myFeedback := feedbackToDTO(feedback, article.Version)
+		rating.MyFeedback = &myFeedback
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'évaluation")
+	}
+
+	if withDetails {
+		feedbacks, err := s.feedbackRepo.FindByArticle(articleID, repositories.KnowledgeFeedbackFilter{})
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors de la récupération des évaluations")
+		}
+		rating.Feedbacks = make([]dto.KnowledgeArticleFeedbackDTO, len(feedbacks))
+		for i := range feedbacks {
+			rating.Feedbacks[i] = feedbackToDTO(&feedbacks[i], article.Version)
+		}
+	}
+	return rating, nil
+}
+
+// GetLowRated liste les articles visibles les moins utiles, avec leurs derniers commentaires négatifs
+func (s *knowledgeFeedbackService) GetLowRated(scopeParam interface{}, filter repositories.KnowledgeLowRatedFilter) ([]dto.LowRatedKnowledgeArticleDTO, error) {
+	articles, err := s.feedbackRepo.FindLowRated(scopeParam, filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles mal notés")
+	}
+
+	notHelpful := false
+	report := make([]dto.LowRatedKnowledgeArticleDTO, 0, len(articles))
+	for i := range articles {
+		article := &articles[i]
+		comments, err := s.feedbackRepo.FindByArticle(article.ID, repositories.KnowledgeFeedbackFilter{
+			Helpful:     &notHelpful,
+			WithComment: true,
+			Limit:       knowledgeLowRatedComments,
+		})
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors de la récupération des évaluations")
+		}
+
+		item := dto.LowRatedKnowledgeArticleDTO{
+			ID:              article.ID,
+			Title:           article.Title,
+			Status:          article.Status,
+			Version:         article.Version,
+			CategoryID:      article.CategoryID,
+			AuthorID:        article.AuthorID,
+			ViewCount:       article.ViewCount,
+			HelpfulCount:    article.HelpfulCount,
+			NotHelpfulCount: article.NotHelpfulCount,
+			RecentComments:  make([]dto.KnowledgeArticleFeedbackDTO, len(comments)),
+			UpdatedAt:       article.UpdatedAt,
+		}
+		if article.HelpfulRate != nil {
+			item.HelpfulRate = *article.HelpfulRate
+		}
+		if article.Category.ID != 0 {
+			item.Category = &dto.KnowledgeCategoryDTO{
+				ID:          article.Category.ID,
+				Name:        article.Category.Name,
+				Description: article.Category.Description,
+				ParentID:    article.Category.ParentID,
+			}
+		}
+		if article.Author.ID != 0 {
+			item.Author = ptrUserDTO(&article.Author)
+		}
+		for j := range comments {
+			item.RecentComments[j] = feedbackToDTO(&comments[j], article.Version)
+		}
+		report = append(report, item)
+	}
+	return report, nil
+}
+
+// visibleArticle récupère un article visible dans le périmètre de l'utilisateur
+func (s *knowledgeFeedbackService) visibleArticle(scopeParam interface{}, articleID uint) (*models.KnowledgeArticle, error) {
+	articles, err := s.articleRepo.FindVisibleByIDs(scopeParam, []uint{articleID})
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'article")
+	}
+	if len(articles) == 0 {
+		return nil, utils.ErrArticleNotFound
+	}
+	return &articles[0], nil
+}
+
+// ratingToDTO convertit les agrégats des évaluations d'un article en DTO
+func ratingToDTO(article *models.KnowledgeArticle) *dto.KnowledgeArticleRatingDTO {
+	return &dto.KnowledgeArticleRatingDTO{
+		ArticleID:       article.ID,
+		HelpfulCount:    article.HelpfulCount,
+		NotHelpfulCount: article.NotHelpfulCount,
+		HelpfulRate:     article.HelpfulRate,
+	}
+}
+
+// feedbackToDTO convertit une évaluation en DTO ; currentVersion signale les évaluations antérieures à la dernière modification
+func feedbackToDTO(feedback *models.KnowledgeArticleFeedback, currentVersion int) dto.KnowledgeArticleFeedbackDTO {
+	feedbackDTO := dto.KnowledgeArticleFeedbackDTO{
+		ID:             feedback.ID,
+		ArticleID:      feedback.ArticleID,
+		UserID:         feedback.UserID,
+		Helpful:        feedback.Helpful,
+		Comment:        feedback.Comment,
+		ArticleVersion: feedback.ArticleVersion,
+		Outdated:       feedback.ArticleVersion < currentVersion,
+		CreatedAt:      feedback.CreatedAt,
+		UpdatedAt:      feedback.UpdatedAt,
+	}
+	if feedback.User != nil {
+		feedbackDTO.User = ptrUserDTO(feedback.User)
+	}
+	return feedbackDTO
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/kbsearch"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/storage"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// knowledgePortalKeyPrefix préfixe des clés d'API du portail public de la base de connaissances
+const knowledgePortalKeyPrefix = "kbp_"
+
+// KnowledgePortalService interface pour le portail public (lecture seule) de la base de connaissances et ses clés d'API
+// Le portail n'expose que les articles publiés marqués publics ; filialeID restreint aux articles globaux et à ceux de la filiale
+type KnowledgePortalService interface {
+	Authenticate(key string) (*models.KnowledgePortalKey, error)
+	GetArticles(filter repositories.KnowledgePortalFilter) (*dto.PublicKnowledgeArticleListDTO, error) // Par pertinence si filter.Query est renseigné
+	GetArticle(id uint, filialeID *uint) (*dto.PublicKnowledgeArticleDTO, error)
+	GetCategories(filialeID *uint) ([]dto.PublicKnowledgeCategoryDTO, error)
+	OpenAttachment(articleID, attachmentID uint, filialeID *uint) (*storage.Object, *dto.KnowledgeArticleAttachmentDTO, error)
+	GetKeys() ([]dto.KnowledgePortalKeyDTO, error)
+	CreateKey(req dto.CreateKnowledgePortalKeyRequest, createdByID uint) (*dto.KnowledgePortalKeyDTO, error)
+	UpdateKey(id uint, req dto.UpdateKnowledgePortalKeyRequest) (*dto.KnowledgePortalKeyDTO, error)
+	RotateKey(id uint) (*dto.KnowledgePortalKeyDTO, error)
+	DeleteKey(id uint) error
+}
+
+// knowledgePortalService implémente KnowledgePortalService
+type knowledgePortalService struct {
+	portalRepo   repositories.KnowledgePortalRepository
+	articleRepo  repositories.KnowledgeArticleRepository
+	searchEngine kbsearch.Engine
+	fileStorage  storage.Storage
+}
+
+// NewKnowledgePortalService crée une nouvelle instance de KnowledgePortalService
+func NewKnowledgePortalService(
+	portalRepo repositories.KnowledgePortalRepository,
+	articleRepo repositories.KnowledgeArticleRepository,
+	searchEngine kbsearch.Engine,
+	fileStorage storage.Storage,
+) KnowledgePortalService {
+	return &knowledgePortalService{
+		portalRepo:   portalRepo,
+		articleRepo:  articleRepo,
+		searchEngine: searchEngine,
+		fileStorage:  fileStorage,
+	}
+}
+
+// Authenticate retrouve la clé active correspondant à une clé d'API du portail
+func (s *knowledgePortalService) Authenticate(key string) (*models.KnowledgePortalKey, error) {
+	hash, err := apiKeyLookupHash(key, knowledgePortalKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	portalKey, err := s.portalRepo.FindKeyByHash(hash)
+	if err != nil || !portalKey.IsActive {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+	if markAPIKeyUsed(&portalKey.LastUsedAt) {
+		if err := s.portalRepo.UpdateKey(portalKey); err != nil {
+			log.Printf("Portail KB: erreur lors de la mise à jour de la clé %d: %v", portalKey.ID, err)
+		}
+	}
+	return portalKey, nil
+}
+
+// GetArticles récupère une page d'articles publics ; une recherche est classée par pertinence avec le moteur plein texte,
+// ou par date de modification si le moteur est indisponible ou la requête trop courte
+func (s *knowledgePortalService) GetArticles(filter repositories.KnowledgePortalFilter) (*dto.PublicKnowledgeArticleListDTO, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query != "" {
+		hits, err := s.searchEngine.Search(context.Background(), filter.Query, knowledgeSearchCandidates)
+		if err == nil {
+			return s.rankedArticles(hits, filter)
+		}
+		if !errors.Is(err, kbsearch.ErrQueryTooShort) {
+			log.Printf("Portail KB: recherche plein texte indisponible, recherche simple utilisée: %v", err)
+		}
+	}
+
+	articles, total, err := s.portalRepo.FindArticles(filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles")
+	}
+	terms := kbsearch.Terms(filter.Query)
+	summaries := make([]dto.PublicKnowledgeArticleSummaryDTO, len(articles))
+	for i := range articles {
+		summaries[i] = publicArticleSummaryToDTO(&articles[i], highlightHit(kbsearch.Hit{ID: articles[i].ID}, &articles[i], terms))
+	}
+	return publicArticleList(summaries, filter, total), nil
+}
+
+// rankedArticles conserve, dans l'ordre du moteur, les articles publics du filtre puis en extrait la page demandée
+func (s *knowledgePortalService) rankedArticles(hits []kbsearch.Hit, filter repositories.KnowledgePortalFilter) (*dto.PublicKnowledgeArticleListDTO, error) {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	idFilter := filter
+	idFilter.Query = ""
+	articles, err := s.portalRepo.FindArticlesByIDs(ids, idFilter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des articles")
+	}
+	public := make(map[uint]*models.KnowledgeArticle, len(articles))
+	for i := range articles {
+		public[articles[i].ID] = &articles[i]
+	}
+
+	terms := kbsearch.Terms(filter.Query)
+	summaries := []dto.PublicKnowledgeArticleSummaryDTO{}
+	for _, hit := range hits {
+		if article, ok := public[hit.ID]; ok {
+			summaries = append(summaries, publicArticleSummaryToDTO(article, highlightHit(hit, article, terms)))
+		}
+	}
+	total := int64(len(summaries))
+	start := (filter.Page - 1) * filter.Limit
+	if start > len(summaries) {
+		start = len(summaries)
+	}
+	end := start + filter.Limit
+	if end > len(summaries) {
+		end = len(summaries)
+	}
+	return publicArticleList(summaries[start:end], filter, total), nil
+}
+
+// GetArticle récupère un article public et incrémente son compteur de vues
+func (s *knowledgePortalService) GetArticle(id uint, filialeID *uint) (*dto.PublicKnowledgeArticleDTO, error) {
+	article, err := s.portalRepo.FindArticle(id, filialeID)
+	if err != nil {
+		return nil, utils.ErrArticleNotFound
+	}
+	if err := s.articleRepo.IncrementViewCount(id); err != nil {
+		log.Printf("Portail KB: erreur lors de l'incrément des vues de l'article %d: %v", id, err)
+	}
+
+	articleDTO := dto.PublicKnowledgeArticleDTO{
+		ID:          article.ID,
+		Title:       article.Title,
+		ContentHTML: publicAttachmentLinks(article.ID, richtext.Render(article.Content, article.ContentFormat)),
+		Category:    publicCategoryToDTO(&article.Category),
+		ViewCount:   article.ViewCount + 1,
+		HelpfulRate: article.HelpfulRate,
+		UpdatedAt:   article.UpdatedAt,
+	}
+	return &articleDTO, nil
+}
+
+// GetCategories récupère les catégories actives contenant des articles publics
+func (s *knowledgePortalService) GetCategories(filialeID *uint) ([]dto.PublicKnowledgeCategoryDTO, error) {
+	categories, err := s.portalRepo.FindCategories(filialeID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des catégories")
+	}
+	categoryDTOs := make([]dto.PublicKnowledgeCategoryDTO, len(categories))
+	for i := range categories {
+		categoryDTOs[i] = *publicCategoryToDTO(&categories[i])
+	}
+	return categoryDTOs, nil
+}
+
+// OpenAttachment ouvre le fichier d'une pièce jointe d'un article public
+func (s *knowledgePortalService) OpenAttachment(articleID, attachmentID uint, filialeID *uint) (*storage.Object, *dto.KnowledgeArticleAttachmentDTO, error) {
+	article, err := s.portalRepo.FindArticle(articleID, filialeID)
+	if err != nil {
+		return nil, nil, utils.ErrArticleNotFound
+	}
+	attachment, err := s.articleRepo.FindAttachment(articleID, attachmentID)
+	if err != nil {
+		return nil, nil, utils.ErrAttachmentNotFound
+	}
+	object, err := s.fileStorage.Get(context.Background(), attachment.FilePath)
+	if err != nil {
+		return nil, nil, utils.ErrAttachmentFileMissing
+	}
+	if object.ContentType == "" {
+		object.ContentType = attachment.MimeType
+	}
+	attachmentDTO := knowledgeAttachmentToDTO(attachment, article.ContentFormat)
+	attachmentDTO.URL = publicKnowledgeAttachmentURL(articleID, attachmentID)
+	attachmentDTO.Snippet = ""
+	return object, &attachmentDTO, nil
+}
+
+// GetKeys récupère les clés d'API du portail
+func (s *knowledgePortalService) GetKeys() ([]dto.KnowledgePortalKeyDTO, error) {
+	keys, err := s.portalRepo.FindKeys()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des clés d'API")
+	}
+	result := make([]dto.KnowledgePortalKeyDTO, len(keys))
+	for i := range keys {
+		result[i] = knowledgePortalKeyToDTO(&keys[i])
+	}
+	return result, nil
+}
+
+// CreateKey crée une clé d'API du portail ; sans filiale, la clé voit les articles publics de toutes les filiales
+func (s *knowledgePortalService) CreateKey(req dto.CreateKnowledgePortalKeyRequest, createdByID uint) (*dto.KnowledgePortalKeyDTO, error) {
+	key, err := generateAPIKey(knowledgePortalKeyPrefix)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la génération de la clé d'API")
+	}
+	portalKey := &models.KnowledgePortalKey{
+		Name:        strings.TrimSpace(req.Name),
+		KeyHash:     key.Hash,
+		KeyPrefix:   key.Prefix,
+		FilialeID:   req.FilialeID,
+		IsActive:    true,
+		CreatedByID: createdByID,
+	}
+	if err := s.portalRepo.CreateKey(portalKey); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la clé d'API")
+	}
+
+	created, err := s.portalRepo.FindKeyByID(portalKey.ID)
+	if err != nil {
+		created = portalKey
+	}
+	keyDTO := knowledgePortalKeyToDTO(created)
+	keyDTO.Key = key.Key
+	return &keyDTO, nil
+}
+
+// UpdateKey met à jour le nom ou l'activation d'une clé
+func (s *knowledgePortalService) UpdateKey(id uint, req dto.UpdateKnowledgePortalKeyRequest) (*dto.KnowledgePortalKeyDTO, error) {
+	portalKey, err := s.portalRepo.FindKeyByID(id)
+	if err != nil {
+		return nil, utils.ErrAPIKeyNotFound
+	}
+	if req.Name != nil {
+		portalKey.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.IsActive != nil {
+		portalKey.IsActive = *req.IsActive
+	}
+	if err := s.portalRepo.UpdateKey(portalKey); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la clé d'API")
+	}
+	keyDTO := knowledgePortalKeyToDTO(portalKey)
+	return &keyDTO, nil
+}
+
+// RotateKey renouvelle une clé ; l'ancienne est immédiatement refusée
+func (s *knowledgePortalService) RotateKey(id uint) (*dto.KnowledgePortalKeyDTO, error) {
+	portalKey, err := s.portalRepo.FindKeyByID(id)
+	if err != nil {
+		return nil, utils.ErrAPIKeyNotFound
+	}
+	key, err := generateAPIKey(knowledgePortalKeyPrefix)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la génération de la clé d'API")
+	}
+	portalKey.KeyHash = key.Hash
+	portalKey.KeyPrefix = key.Prefix
+	if err := s.portalRepo.UpdateKey(portalKey); err != nil {
+		return nil, utils.NewInternalError("erreur lors du renouvellement de la clé d'API")
+	}
+	keyDTO := knowledgePortalKeyToDTO(portalKey)
+	keyDTO.Key = key.Key
+	return &keyDTO, nil
+}
+
+// DeleteKey supprime une clé
+func (s *knowledgePortalService) DeleteKey(id uint) error {
+	if _, err := s.portalRepo.FindKeyByID(id); err != nil {
+		return utils.ErrAPIKeyNotFound
+	}
+	if err := s.portalRepo.DeleteKey(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de la clé d'API")
+	}
+	return nil
+}
+
+// publicKnowledgeAttachmentURL retourne le lien de téléchargement d'une pièce jointe depuis le portail public
+func publicKnowledgeAttachmentURL(articleID, attachmentID uint) string {
+	return fmt.Sprintf("/public/v1/knowledge/articles/%d/attachments/%d", articleID, attachmentID)
+}
+
+// publicAttachmentLinks réécrit vers le portail les liens des pièces jointes de l'article présents dans son contenu rendu
+func publicAttachmentLinks(articleID uint, contentHTML string) string {
+	return strings.ReplaceAll(contentHTML,
+		fmt.Sprintf("/api/v1/knowledge-base/articles/%d/attachments/", articleID),
+		fmt.Sprintf("/public/v1/knowledge/articles/%d/attachments/", articleID))
+}
+
+// publicArticleList construit une page d'articles du portail
+func publicArticleList(summaries []dto.PublicKnowledgeArticleSummaryDTO, filter repositories.KnowledgePortalFilter, total int64) *dto.PublicKnowledgeArticleListDTO {
+	totalPages := int(total) / filter.Limit
+	if int(total)%filter.Limit > 0 {
+		totalPages++
+	}
+	return &dto.PublicKnowledgeArticleListDTO{
+		Articles: summaries,
+		Pagination: dto.PaginationDTO{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}
+}
+
+// publicArticleSummaryToDTO convertit un article public et ses surlignages en DTO de liste
+func publicArticleSummaryToDTO(article *models.KnowledgeArticle, hit kbsearch.Hit) dto.PublicKnowledgeArticleSummaryDTO {
+	summary := dto.PublicKnowledgeArticleSummaryDTO{
+		ID:        article.ID,
+		Title:     article.Title,
+		Snippet:   hit.Highlight,
+		Category:  publicCategoryToDTO(&article.Category),
+		UpdatedAt: article.UpdatedAt,
+	}
+	if hit.TitleHighlight != "" && strings.Contains(hit.TitleHighlight, "<mark>") {
+		summary.TitleHighlight = hit.TitleHighlight
+	}
+	return summary
+}
+
+// publicCategoryToDTO convertit une catégorie en DTO du portail (nil si la catégorie n'est pas chargée)
+func publicCategoryToDTO(category *models.KnowledgeCategory) *dto.PublicKnowledgeCategoryDTO {
+	if category.ID == 0 {
+		return nil
+	}
+	return &dto.PublicKnowledgeCategoryDTO{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		ParentID:    category.ParentID,
+	}
+}
+
+// knowledgePortalKeyToDTO convertit une clé d'API du portail en DTO
+func knowledgePortalKeyToDTO(key *models.KnowledgePortalKey) dto.KnowledgePortalKeyDTO {
+	keyDTO := dto.KnowledgePortalKeyDTO{
+		ID:         key.ID,
+		Name:       key.Name,
+		KeyPrefix:  key.KeyPrefix,
+		FilialeID:  key.FilialeID,
+		IsActive:   key.IsActive,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+	if key.Filiale != nil {
+		keyDTO.FilialeName = key.Filiale.Name
+	}
+	return keyDTO
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/cache"
+	"github.com/mcicare/itsm-backend/internal/kbsearch"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+)
+
+const (
+	knowledgeSearchCandidates   = 200 // Résultats demandés au moteur avant filtrage par périmètre
+	knowledgeSnippetLength      = 200 // Longueur des extraits surlignés (caractères)
+	knowledgeIndexBatchSize     = 200
+	knowledgeVocabularyCacheKey = "kb:search:vocabulary"
+	knowledgeVocabularyTTL      = 10 * time.Minute
+)
+
+// knowledgeSearch recherche plein texte des articles, commune à la base de connaissances et à la recherche globale
+type knowledgeSearch struct {
+	engine      kbsearch.Engine
+	articleRepo repositories.KnowledgeArticleRepository
+}
+
+// knowledgeSearchResult article visible trouvé, avec son score et ses surlignages
+type knowledgeSearchResult struct {
+	article models.KnowledgeArticle
+	hit     kbsearch.Hit
+}
+
+// search retourne les articles visibles classés par pertinence. Les requêtes sans terme indexable (mots trop courts)
+// et les pannes du moteur se rabattent sur la recherche LIKE ; si rien n'est trouvé, la requête corrigée des fautes
+// de frappe est essayée et retournée dans corrected
+func (k *knowledgeSearch) search(scopeParam interface{}, query string, limit int) (results []knowledgeSearchResult, corrected string, err error) {
+	ctx := context.Background()
+	hits, err := k.engine.Search(ctx, query, knowledgeSearchCandidates)
+	if err != nil {
+		if !errors.Is(err, kbsearch.ErrQueryTooShort) {
+			log.Printf("Recherche plein texte indisponible, recherche simple utilisée: %v", err)
+		}
+		return k.searchLike(scopeParam, query, limit)
+	}
+	if len(hits) == 0 {
+		if alternative, ok := k.vocabulary().Correct(query); ok {
+			if hits, err = k.engine.Search(ctx, alternative, knowledgeSearchCandidates); err == nil && len(hits) > 0 {
+				query, corrected = alternative, alternative
+			}
+		}
+	}
+
+	results, err = k.visible(scopeParam, hits, kbsearch.Terms(query), limit, false)
+	return results, corrected, err
+}
+
+// related retourne les articles publiés visibles proches d'un texte libre (ticket en cours de saisie), par pertinence
+func (k *knowledgeSearch) related(scopeParam interface{}, text string, limit int) ([]knowledgeSearchResult, error) {
+	hits, err := k.engine.Related(context.Background(), text, knowledgeSearchCandidates)
+	if errors.Is(err, kbsearch.ErrQueryTooShort) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return k.visible(scopeParam, hits, kbsearch.Terms(text), limit, true)
+}
+
+// visible conserve, dans l'ordre du moteur, les articles visibles dans le périmètre (publiés uniquement si demandé)
+func (k *knowledgeSearch) visible(scopeParam interface{}, hits []kbsearch.Hit, terms []string, limit int, publishedOnly bool) ([]knowledgeSearchResult, error) {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	articles, err := k.articleRepo.FindVisibleByIDs(scopeParam, ids)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[uint]models.KnowledgeArticle, len(articles))
+	for _, article := range articles {
+		if !publishedOnly || article.IsPublished {
+			visible[article.ID] = article
+		}
+	}
+
+	var results []knowledgeSearchResult
+	for _, hit := range hits {
+		article, ok := visible[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, knowledgeSearchResult{article: article, hit: highlightHit(hit, &article, terms)})
+		if limit > 0 && len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// searchLike recherche par sous-chaîne, du plus récent au plus ancien
+func (k *knowledgeSearch) searchLike(scopeParam interface{}, query string, limit int) ([]knowledgeSearchResult, string, error) {
+	articles, err := k.articleRepo.Search(scopeParam, query)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit > 0 && len(articles) > limit {
+		articles = articles[:limit]
+	}
+	terms := kbsearch.Terms(query)
+	results := make([]knowledgeSearchResult, len(articles))
+	for i := range articles {
+		results[i] = knowledgeSearchResult{article: articles[i], hit: highlightHit(kbsearch.Hit{ID: articles[i].ID}, &articles[i], terms)}
+	}
+	return results, "", nil
+}
+
+// highlightHit complète les surlignages que le moteur n'a pas produits (MySQL, recherche simple)
+func highlightHit(hit kbsearch.Hit, article *models.KnowledgeArticle, terms []string) kbsearch.Hit {
+	if hit.TitleHighlight == "" {
+		hit.TitleHighlight = kbsearch.Highlight(article.Title, terms, 0)
+	}
+	if hit.Highlight == "" {
+		hit.Highlight = kbsearch.Highlight(richtext.PlainText(article.Content, article.ContentFormat), terms, knowledgeSnippetLength)
+	}
+	return hit
+}
+
+// vocabulary retourne les mots des articles publiés (mis en cache), référence de la correction des fautes de frappe
+func (k *knowledgeSearch) vocabulary() *kbsearch.Vocabulary {
+	vocabulary, err := cache.GetOrLoad(cache.Shared, knowledgeVocabularyCacheKey, knowledgeVocabularyTTL, func() (*kbsearch.Vocabulary, error) {
+		vocabulary := kbsearch.NewVocabulary()
+		err := k.eachArticle(func(article *models.KnowledgeArticle) error {
+			if article.IsPublished {
+				vocabulary.Add(article.Title)
+				vocabulary.Add(richtext.PlainText(article.Content, article.ContentFormat))
+			}
+			return nil
+		})
+		return vocabulary, err
+	})
+	if err != nil {
+		log.Printf("Erreur lors du chargement du vocabulaire de la base de connaissances: %v", err)
+		return kbsearch.NewVocabulary()
+	}
+	return vocabulary
+}
+
+// index met à jour un article dans le moteur de recherche, en journalisant l'échec éventuel
+func (k *knowledgeSearch) index(article *models.KnowledgeArticle) {
+	if err := k.engine.Index(context.Background(), []kbsearch.Document{knowledgeDocument(article)}); err != nil {
+		log.Printf("Erreur lors de l'indexation de l'article %d: %v", article.ID, err)
+	}
+	cache.Shared.Delete(knowledgeVocabularyCacheKey)
+}
+
+// remove retire un article du moteur de recherche, en journalisant l'échec éventuel
+func (k *knowledgeSearch) remove(id uint) {
+	if err := k.engine.Delete(context.Background(), id); err != nil {
+		log.Printf("Erreur lors du retrait de l'article %d de l'index de recherche: %v", id, err)
+	}
+	cache.Shared.Delete(knowledgeVocabularyCacheKey)
+}
+
+// reindex renvoie tous les articles au moteur par lots et retourne le nombre d'articles indexés
+func (k *knowledgeSearch) reindex() (int, error) {
+	var batch []kbsearch.Document
+	count := 0
+	flush := func() error {
+		if err := k.engine.Index(context.Background(), batch); err != nil {
+			return err
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	err := k.eachArticle(func(article *models.KnowledgeArticle) error {
+		batch = append(batch, knowledgeDocument(article))
+		if len(batch) == knowledgeIndexBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	cache.Shared.Delete(knowledgeVocabularyCacheKey)
+	return count, err
+}
+
+// eachArticle parcourt tous les articles par lots
+func (k *knowledgeSearch) eachArticle(fn func(article *models.KnowledgeArticle) error) error {
+	var afterID uint
+	for {
+		articles, err := k.articleRepo.FindForIndex(afterID, knowledgeIndexBatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range articles {
+			if err := fn(&articles[i]); err != nil {
+				return err
+			}
+		}
+		if len(articles) < knowledgeIndexBatchSize {
+			return nil
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+}
+
+// knowledgeDocument convertit un article en document indexé (contenu en texte brut)
+func knowledgeDocument(article *models.KnowledgeArticle) kbsearch.Document {
+	return kbsearch.Document{
+		ID:      article.ID,
+		Title:   article.Title,
+		Content: richtext.PlainText(article.Content, article.ContentFormat),
+	}
+}
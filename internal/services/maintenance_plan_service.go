@@ -0,0 +1,643 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/recurrence"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Valeurs par défaut des tickets de maintenance
+const (
+	maintenanceTicketCategory = "maintenance"
+	maintenanceDepartment     = "Maintenance"
+	maintenanceRequesterName  = "Maintenance préventive"
+)
+
+// maintenanceHistoryLimit nombre maximal d'opérations retournées dans l'historique d'un actif
+const maintenanceHistoryLimit = 100
+
+// maintenancePlanRecentRecords nombre d'opérations récentes retournées avec le détail d'un plan
+const maintenancePlanRecentRecords = 20
+
+// MaintenancePlanService interface pour les plans de maintenance préventive des actifs
+type MaintenancePlanService interface {
+	GetAll(filter repositories.MaintenancePlanFilter) ([]dto.MaintenancePlanDTO, error)
+	GetByID(id uint) (*dto.MaintenancePlanDTO, error)
+	Create(req dto.CreateMaintenancePlanRequest, createdByID uint) (*dto.MaintenancePlanDTO, error)
+	Update(id uint, req dto.UpdateMaintenancePlanRequest) (*dto.MaintenancePlanDTO, error)
+	Delete(id uint) error
+	RunNow(id uint) ([]dto.MaintenanceRecordDTO, error)                 // Génère immédiatement les tickets, sans décaler la prochaine échéance
+	RunDue(ctx context.Context) (int, error)                            // Génère les tickets des plans arrivés à échéance (exécuté périodiquement)
+	GetAssetMaintenance(assetID uint) (*dto.AssetMaintenanceDTO, error) // Plans applicables et historique de maintenance d'un actif
+}
+
+// maintenancePlanService implémente MaintenancePlanService
+type maintenancePlanService struct {
+	planRepo           repositories.MaintenancePlanRepository
+	assetRepo          repositories.AssetRepository
+	assetCategoryRepo  repositories.AssetCategoryRepository
+	userRepo           repositories.UserRepository
+	ticketCategoryRepo repositories.TicketCategoryRepository
+	ticketAssetRepo    repositories.TicketAssetRepository
+	checklistRepo      repositories.TicketChecklistRepository
+	ticketService      TicketService
+}
+
+// NewMaintenancePlanService crée une nouvelle instance de MaintenancePlanService
+func NewMaintenancePlanService(
+	planRepo repositories.MaintenancePlanRepository,
+	assetRepo repositories.AssetRepository,
+	assetCategoryRepo repositories.AssetCategoryRepository,
+	userRepo repositories.UserRepository,
+	ticketCategoryRepo repositories.TicketCategoryRepository,
+	ticketAssetRepo repositories.TicketAssetRepository,
+	checklistRepo repositories.TicketChecklistRepository,
+	ticketService TicketService,
+) MaintenancePlanService {
+	return &maintenancePlanService{
+		planRepo:           planRepo,
+		assetRepo:          assetRepo,
+		assetCategoryRepo:  assetCategoryRepo,
+		userRepo:           userRepo,
+		ticketCategoryRepo: ticketCategoryRepo,
+		ticketAssetRepo:    ticketAssetRepo,
+		checklistRepo:      checklistRepo,
+		ticketService:      ticketService,
+	}
+}
+
+// GetAll récupère les plans de maintenance selon les filtres
+func (s *maintenancePlanService) GetAll(filter repositories.MaintenancePlanFilter) ([]dto.MaintenancePlanDTO, error) {
+	plans, err := s.planRepo.FindAll(filter)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des plans de maintenance")
+	}
+	planDTOs := make([]dto.MaintenancePlanDTO, 0, len(plans))
+	for i := range plans {
+		planDTOs = append(planDTOs, maintenancePlanToDTO(&plans[i]))
+	}
+	return planDTOs, nil
+}
+
+// GetByID récupère un plan de maintenance et ses dernières opérations
+func (s *maintenancePlanService) GetByID(id uint) (*dto.MaintenancePlanDTO, error) {
+	plan, err := s.planRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrMaintenancePlanNotFound
+	}
+	planDTO := maintenancePlanToDTO(plan)
+	records, err := s.planRepo.FindRecordsByPlan(id, maintenancePlanRecentRecords)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'historique du plan de maintenance")
+	}
+	for i := range records {
+		planDTO.RecentRecords = append(planDTO.RecentRecords, maintenanceRecordToDTO(&records[i]))
+	}
+	return &planDTO, nil
+}
+
+// Create crée un plan de maintenance et calcule sa première échéance
+func (s *maintenancePlanService) Create(req dto.CreateMaintenancePlanRequest, createdByID uint) (*dto.MaintenancePlanDTO, error) {
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	plan := &models.MaintenancePlan{
+		Name:                strings.TrimSpace(req.Name),
+		Description:         strings.TrimSpace(req.Description),
+		AssetID:             optionalID(req.AssetID),
+		CategoryID:          optionalID(req.CategoryID),
+		Schedule:            strings.TrimSpace(req.Schedule),
+		Timezone:            strings.TrimSpace(req.Timezone),
+		StartsAt:            startsAt.Truncate(time.Second),
+		TicketCategory:      strings.TrimSpace(req.TicketCategory),
+		Priority:            req.Priority,
+		EstimatedTime:       req.EstimatedTime,
+		RequesterDepartment: strings.TrimSpace(req.RequesterDepartment),
+		IsActive:            req.IsActive == nil || *req.IsActive,
+		CreatedByID:         createdByID,
+	}
+	if plan.TicketCategory == "" {
+		plan.TicketCategory = maintenanceTicketCategory
+	}
+	if plan.Priority == "" {
+		plan.Priority = "medium"
+	}
+	if plan.RequesterDepartment == "" {
+		plan.RequesterDepartment = maintenanceDepartment
+	}
+	if err := applyMaintenanceChecklist(plan, req.Checklist); err != nil {
+		return nil, err
+	}
+	if err := s.applyAssignees(plan, req.AssigneeIDs, req.LeadID); err != nil {
+		return nil, err
+	}
+	if err := s.validate(plan); err != nil {
+		return nil, err
+	}
+	if err := scheduleMaintenancePlan(plan, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.planRepo.Create(plan); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création du plan de maintenance")
+	}
+	if !plan.IsActive {
+		// La valeur par défaut de la colonne s'applique à false à la création
+		if err := s.planRepo.Update(plan); err != nil {
+			log.Printf("Erreur lors de la désactivation du plan de maintenance %d: %v", plan.ID, err)
+		}
+	}
+	return s.GetByID(plan.ID)
+}
+
+// Update met à jour un plan de maintenance ; la prochaine échéance est recalculée à partir de maintenant
+func (s *maintenancePlanService) Update(id uint, req dto.UpdateMaintenancePlanRequest) (*dto.MaintenancePlanDTO, error) {
+	plan, err := s.planRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrMaintenancePlanNotFound
+	}
+
+	if req.Name != nil {
+		plan.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		plan.Description = strings.TrimSpace(*req.Description)
+	}
+	// Changement de périmètre : l'actif et la catégorie sont exclusifs
+	if req.AssetID != nil && *req.AssetID != 0 {
+		plan.AssetID, plan.CategoryID = req.AssetID, nil
+	} else if req.CategoryID != nil && *req.CategoryID != 0 {
+		plan.AssetID, plan.CategoryID = nil, req.CategoryID
+	}
+	if req.Schedule != nil {
+		plan.Schedule = strings.TrimSpace(*req.Schedule)
+	}
+	if req.Timezone != nil {
+		plan.Timezone = strings.TrimSpace(*req.Timezone)
+	}
+	if req.StartsAt != nil {
+		plan.StartsAt = req.StartsAt.Truncate(time.Second)
+	}
+	if req.Checklist != nil {
+		if err := applyMaintenanceChecklist(plan, *req.Checklist); err != nil {
+			return nil, err
+		}
+	}
+	if req.TicketCategory != nil {
+		plan.TicketCategory = strings.TrimSpace(*req.TicketCategory)
+	}
+	if req.Priority != nil {
+		plan.Priority = *req.Priority
+	}
+	if req.EstimatedTime != nil {
+		plan.EstimatedTime = req.EstimatedTime
+	}
+	if req.RequesterDepartment != nil {
+		plan.RequesterDepartment = strings.TrimSpace(*req.RequesterDepartment)
+	}
+	if req.IsActive != nil {
+		plan.IsActive = *req.IsActive
+	}
+	if req.AssigneeIDs != nil || req.LeadID != nil {
+		assigneeIDs := decodeUintList(plan.AssigneeIDs)
+		if req.AssigneeIDs != nil {
+			assigneeIDs = *req.AssigneeIDs
+		}
+		leadID := plan.LeadID
+		if req.LeadID != nil {
+			leadID = optionalID(req.LeadID)
+		}
+		if err := s.applyAssignees(plan, assigneeIDs, leadID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validate(plan); err != nil {
+		return nil, err
+	}
+	if err := scheduleMaintenancePlan(plan, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.planRepo.Update(plan); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour du plan de maintenance")
+	}
+	return s.GetByID(id)
+}
+
+// Delete supprime un plan de maintenance (l'historique des actifs et les tickets déjà créés sont conservés)
+func (s *maintenancePlanService) Delete(id uint) error {
+	if _, err := s.planRepo.FindByID(id); err != nil {
+		return utils.ErrMaintenancePlanNotFound
+	}
+	if err := s.planRepo.Delete(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression du plan de maintenance")
+	}
+	return nil
+}
+
+// RunNow génère immédiatement les tickets du plan (ex: pour vérifier le modèle)
+func (s *maintenancePlanService) RunNow(id uint) ([]dto.MaintenanceRecordDTO, error) {
+	plan, err := s.planRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrMaintenancePlanNotFound
+	}
+	now := time.Now()
+	records, err := s.generate(plan, now, now, false)
+	if err != nil {
+		return nil, err
+	}
+	recordDTOs := make([]dto.MaintenanceRecordDTO, 0, len(records))
+	for i := range records {
+		recordDTOs = append(recordDTOs, maintenanceRecordToDTO(&records[i]))
+	}
+	return recordDTOs, nil
+}
+
+// RunDue génère les tickets de chaque plan arrivé à échéance
+// Comme pour les tickets récurrents, les échéances manquées ne sont pas rattrapées
+func (s *maintenancePlanService) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	plans, err := s.planRepo.FindDue(now)
+	if err != nil {
+		return 0, utils.NewInternalError("erreur lors de la récupération des plans de maintenance à échéance")
+	}
+
+	created := 0
+	for i := range plans {
+		if ctx.Err() != nil {
+			return created, ctx.Err()
+		}
+		plan := &plans[i]
+		dueAt := *plan.NextRunAt
+		// Compte l'exécution en cours pour borner COUNT
+		plan.RunCount++
+		if err := scheduleMaintenancePlan(plan, now); err != nil {
+			log.Printf("Plan de maintenance %d: %v", plan.ID, err)
+			plan.NextRunAt = nil
+		}
+		claimed, err := s.planRepo.Claim(plan.ID, dueAt, plan.NextRunAt)
+		if err != nil {
+			log.Printf("Erreur lors de la réservation du plan de maintenance %d: %v", plan.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // Déjà traité par une autre exécution
+		}
+		records, err := s.generate(plan, dueAt, now, true)
+		if err != nil {
+			log.Printf("Plan de maintenance %d: %v", plan.ID, err)
+			continue
+		}
+		for _, record := range records {
+			if record.TicketID != nil {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
+// generate crée un ticket de maintenance pour chaque actif en service du périmètre et enregistre l'exécution du plan
+// En exécution planifiée (skipOpen), un actif dont le ticket précédent du plan n'est pas clôturé n'en reçoit pas de nouveau
+func (s *maintenancePlanService) generate(plan *models.MaintenancePlan, dueAt, runAt time.Time, skipOpen bool) ([]models.MaintenanceRecord, error) {
+	assets, err := s.scopeAssets(plan)
+	if err != nil {
+		s.recordRun(plan.ID, runAt, err.Error())
+		return nil, err
+	}
+
+	var records []models.MaintenanceRecord
+	failed, skipped := 0, 0
+	for i := range assets {
+		asset := &assets[i]
+		if skipOpen {
+			open, err := s.planRepo.HasOpenTicket(plan.ID, asset.ID, closedTicketStatuses)
+			if err != nil {
+				log.Printf("Plan de maintenance %d: vérification des tickets ouverts de l'actif %d impossible: %v", plan.ID, asset.ID, err)
+			} else if open {
+				skipped++
+				continue
+			}
+		}
+
+		record := models.MaintenanceRecord{PlanID: &plan.ID, AssetID: asset.ID, DueAt: dueAt}
+		ticket, err := s.createTicket(plan, asset, dueAt)
+		if err != nil {
+			failed++
+			record.Error = err.Error()
+		} else {
+			record.TicketID = &ticket.ID
+		}
+		if err := s.planRepo.CreateRecord(&record); err != nil {
+			log.Printf("Plan de maintenance %d: enregistrement de l'historique de l'actif %d impossible: %v", plan.ID, asset.ID, err)
+		}
+		if ticket != nil {
+			record.Ticket = &models.Ticket{ID: ticket.ID, Code: ticket.Code, Title: ticket.Title, Status: ticket.Status}
+		}
+		record.Plan = plan
+		records = append(records, record)
+	}
+
+	runError := ""
+	switch {
+	case len(assets) == 0:
+		runError = "aucun actif en service dans le périmètre du plan"
+	case failed > 0:
+		runError = fmt.Sprintf("%d ticket(s) de maintenance non créé(s)", failed)
+	}
+	if skipped > 0 {
+		log.Printf("Plan de maintenance %d: %d actif(s) ignoré(s), ticket précédent non clôturé", plan.ID, skipped)
+	}
+	s.recordRun(plan.ID, runAt, runError)
+	return records, nil
+}
+
+// scopeAssets retourne les actifs en service du périmètre du plan (hors actifs retirés ou mis au rebut)
+func (s *maintenancePlanService) scopeAssets(plan *models.MaintenancePlan) ([]models.Asset, error) {
+	var assets []models.Asset
+	if plan.AssetID != nil {
+		asset, err := s.assetRepo.FindByID(*plan.AssetID)
+		if err != nil {
+			return nil, utils.ErrAssetNotFound
+		}
+		assets = []models.Asset{*asset}
+	} else if plan.CategoryID != nil {
+		var err error
+		assets, err = s.assetRepo.FindByCategory(nil, *plan.CategoryID)
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors de la récupération des actifs de la catégorie")
+		}
+	}
+	return slices.DeleteFunc(assets, func(asset models.Asset) bool {
+		status := models.NormalizeAssetStatus(asset.Status)
+		return status == models.AssetStatusRetired || status == models.AssetStatusDisposed
+	}), nil
+}
+
+// createTicket crée le ticket de maintenance d'un actif au nom du créateur du plan, le rattache à l'actif et copie la checklist
+func (s *maintenancePlanService) createTicket(plan *models.MaintenancePlan, asset *models.Asset, dueAt time.Time) (*dto.TicketDTO, error) {
+	assetLabel := asset.Name
+	if asset.Code != nil && *asset.Code != "" {
+		assetLabel += " (" + *asset.Code + ")"
+	}
+
+	var description strings.Builder
+	if plan.Description != "" {
+		description.WriteString(plan.Description + "\n\n")
+	}
+	description.WriteString("Plan de maintenance : " + plan.Name + "\n")
+	description.WriteString("Actif : " + assetLabel + "\n")
+	if asset.SerialNumber != "" {
+		description.WriteString("Numéro de série : " + asset.SerialNumber + "\n")
+	}
+	if asset.Location != "" {
+		description.WriteString("Localisation : " + asset.Location + "\n")
+	}
+	description.WriteString("Échéance : " + dueAt.Format("02/01/2006 15:04"))
+
+	ticket, err := s.ticketService.Create(dto.CreateTicketRequest{
+		Title:               truncateRunes("[Maintenance] "+plan.Name+" - "+assetLabel, 255),
+		Description:         description.String(),
+		Category:            plan.TicketCategory,
+		Source:              "kronos",
+		Priority:            plan.Priority,
+		EstimatedTime:       plan.EstimatedTime,
+		RequesterName:       maintenanceRequesterName,
+		RequesterDepartment: plan.RequesterDepartment,
+		FilialeID:           asset.FilialeID,
+		AssigneeIDs:         decodeUintList(plan.AssigneeIDs),
+		LeadID:              plan.LeadID,
+	}, plan.CreatedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ticketAssetRepo.Create(&models.TicketAsset{TicketID: ticket.ID, AssetID: asset.ID}); err != nil {
+		log.Printf("Plan de maintenance %d: rattachement du ticket %d à l'actif %d impossible: %v", plan.ID, ticket.ID, asset.ID, err)
+	}
+	for i, label := range decodeStringList(plan.Checklist) {
+		item := &models.TicketChecklistItem{TicketID: ticket.ID, Label: label, DisplayOrder: i, CreatedByID: plan.CreatedByID}
+		if err := s.checklistRepo.Create(item); err != nil {
+			log.Printf("Plan de maintenance %d: création de la checklist du ticket %d impossible: %v", plan.ID, ticket.ID, err)
+			break
+		}
+	}
+	return ticket, nil
+}
+
+// recordRun enregistre le résultat d'une exécution du plan
+func (s *maintenancePlanService) recordRun(planID uint, runAt time.Time, runError string) {
+	if err := s.planRepo.RecordRun(planID, runAt, runError); err != nil {
+		log.Printf("Erreur lors de l'enregistrement de l'exécution du plan de maintenance %d: %v", planID, err)
+	}
+}
+
+// GetAssetMaintenance retourne les plans qui s'appliquent à l'actif et son historique de maintenance
+func (s *maintenancePlanService) GetAssetMaintenance(assetID uint) (*dto.AssetMaintenanceDTO, error) {
+	asset, err := s.assetRepo.FindByID(assetID)
+	if err != nil {
+		return nil, utils.ErrAssetNotFound
+	}
+	plans, err := s.planRepo.FindForAsset(asset.ID, asset.CategoryID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des plans de maintenance")
+	}
+	records, err := s.planRepo.FindRecordsByAsset(asset.ID, maintenanceHistoryLimit)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'historique de maintenance")
+	}
+
+	view := &dto.AssetMaintenanceDTO{
+		AssetID: asset.ID,
+		Plans:   make([]dto.MaintenancePlanDTO, 0, len(plans)),
+		History: make([]dto.MaintenanceRecordDTO, 0, len(records)),
+	}
+	for i := range plans {
+		view.Plans = append(view.Plans, maintenancePlanToDTO(&plans[i]))
+		if next := plans[i].NextRunAt; plans[i].IsActive && next != nil && (view.NextDueAt == nil || next.Before(*view.NextDueAt)) {
+			view.NextDueAt = next
+		}
+	}
+	for i := range records {
+		view.History = append(view.History, maintenanceRecordToDTO(&records[i]))
+		ticket := records[i].Ticket
+		if ticket == nil {
+			continue
+		}
+		if !slices.Contains(closedTicketStatuses, ticket.Status) {
+			view.OpenTickets++
+		} else if ticket.ClosedAt != nil && (view.LastDoneAt == nil || ticket.ClosedAt.After(*view.LastDoneAt)) {
+			view.LastDoneAt = ticket.ClosedAt
+		}
+	}
+	return view, nil
+}
+
+// applyAssignees valide et enregistre l'équipe assignée et le responsable des tickets
+func (s *maintenancePlanService) applyAssignees(plan *models.MaintenancePlan, assigneeIDs []uint, leadID *uint) error {
+	ids, lead, err := normalizeAssignees(assigneeIDs, leadID)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		count, err := s.userRepo.CountByIDs(ids)
+		if err != nil {
+			return utils.NewInternalError("erreur lors de la vérification des utilisateurs")
+		}
+		if int(count) != len(ids) {
+			return errors.New("un ou plusieurs assignés sont introuvables")
+		}
+	}
+	if plan.AssigneeIDs, err = json.Marshal(ids); err != nil {
+		return err
+	}
+	plan.LeadID = lead
+	return nil
+}
+
+// validate vérifie le plan (nom, périmètre, catégorie de ticket)
+func (s *maintenancePlanService) validate(plan *models.MaintenancePlan) error {
+	if plan.Name == "" {
+		return errors.New("le nom du plan de maintenance est obligatoire")
+	}
+	if (plan.AssetID == nil) == (plan.CategoryID == nil) {
+		return errors.New("le plan doit porter sur un actif ou sur une catégorie d'actifs")
+	}
+	if plan.AssetID != nil {
+		if _, err := s.assetRepo.FindByID(*plan.AssetID); err != nil {
+			return utils.ErrAssetNotFound
+		}
+	}
+	if plan.CategoryID != nil {
+		if _, err := s.assetCategoryRepo.FindByID(*plan.CategoryID); err != nil {
+			return utils.ErrCategoryNotFound
+		}
+	}
+	if _, err := s.ticketCategoryRepo.FindBySlug(plan.TicketCategory); err != nil {
+		return utils.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// scheduleMaintenancePlan calcule la prochaine échéance après l'instant donné (nil si inactif ou récurrence terminée)
+func scheduleMaintenancePlan(plan *models.MaintenancePlan, after time.Time) error {
+	if !timezone.Valid(plan.Timezone) {
+		return errors.New("fuseau horaire invalide")
+	}
+	schedule, err := recurrence.Parse(plan.Schedule, timezone.Resolve(plan.Timezone), plan.StartsAt)
+	if err != nil {
+		return err
+	}
+	plan.NextRunAt = nil
+	if !plan.IsActive {
+		return nil
+	}
+	if count := schedule.Count(); count > 0 && plan.RunCount >= count {
+		return nil
+	}
+	if start := plan.StartsAt.Add(-time.Second); after.Before(start) {
+		after = start
+	}
+	if next := schedule.Next(after); !next.IsZero() {
+		plan.NextRunAt = &next
+	}
+	return nil
+}
+
+// applyMaintenanceChecklist enregistre les étapes non vides de la checklist
+func applyMaintenanceChecklist(plan *models.MaintenancePlan, steps []string) error {
+	checklist := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if step = strings.TrimSpace(step); step != "" {
+			checklist = append(checklist, step)
+		}
+	}
+	raw, err := json.Marshal(checklist)
+	if err != nil {
+		return err
+	}
+	plan.Checklist = raw
+	return nil
+}
+
+// decodeStringList décode une liste de chaînes stockée en JSON
+func decodeStringList(raw []byte) []string {
+	values := []string{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &values)
+	}
+	return values
+}
+
+// maintenancePlanToDTO convertit un modèle MaintenancePlan en DTO
+func maintenancePlanToDTO(plan *models.MaintenancePlan) dto.MaintenancePlanDTO {
+	planDTO := dto.MaintenancePlanDTO{
+		ID:                  plan.ID,
+		Name:                plan.Name,
+		Description:         plan.Description,
+		AssetID:             plan.AssetID,
+		CategoryID:          plan.CategoryID,
+		Schedule:            plan.Schedule,
+		Timezone:            plan.Timezone,
+		StartsAt:            plan.StartsAt,
+		Checklist:           decodeStringList(plan.Checklist),
+		TicketCategory:      plan.TicketCategory,
+		Priority:            plan.Priority,
+		EstimatedTime:       plan.EstimatedTime,
+		RequesterDepartment: plan.RequesterDepartment,
+		AssigneeIDs:         decodeUintList(plan.AssigneeIDs),
+		LeadID:              plan.LeadID,
+		IsActive:            plan.IsActive,
+		NextRunAt:           plan.NextRunAt,
+		LastRunAt:           plan.LastRunAt,
+		RunCount:            plan.RunCount,
+		LastError:           plan.LastError,
+		CreatedByID:         plan.CreatedByID,
+		CreatedAt:           plan.CreatedAt,
+		UpdatedAt:           plan.UpdatedAt,
+	}
+	if plan.Asset != nil && plan.Asset.ID != 0 {
+		node := assetToNodeDTO(plan.Asset)
+		planDTO.Asset = &node
+	}
+	if plan.Category != nil && plan.Category.ID != 0 {
+		planDTO.Category = &dto.AssetCategoryDTO{ID: plan.Category.ID, Name: plan.Category.Name, Description: plan.Category.Description, ParentID: plan.Category.ParentID}
+	}
+	return planDTO
+}
+
+// maintenanceRecordToDTO convertit une opération de maintenance en DTO
+func maintenanceRecordToDTO(record *models.MaintenanceRecord) dto.MaintenanceRecordDTO {
+	recordDTO := dto.MaintenanceRecordDTO{
+		ID:        record.ID,
+		PlanID:    record.PlanID,
+		AssetID:   record.AssetID,
+		TicketID:  record.TicketID,
+		DueAt:     record.DueAt,
+		Error:     record.Error,
+		CreatedAt: record.CreatedAt,
+	}
+	if record.Plan != nil {
+		recordDTO.PlanName = record.Plan.Name
+	}
+	if record.Ticket != nil {
+		recordDTO.TicketCode = record.Ticket.Code
+		recordDTO.TicketTitle = record.Ticket.Title
+		recordDTO.TicketStatus = record.Ticket.Status
+		recordDTO.ClosedAt = record.Ticket.ClosedAt
+	}
+	return recordDTO
+}
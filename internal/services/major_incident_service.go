@@ -0,0 +1,553 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// defaultMajorIncidentUpdateInterval fréquence par défaut des points de situation (minutes)
+const defaultMajorIncidentUpdateInterval = 30
+
+// statusPageRetention durée d'affichage d'un incident majeur terminé sur la page de statut
+const statusPageRetention = 24 * time.Hour
+
+// statusPageMaxUpdates nombre maximal de points de situation affichés par incident sur la page de statut
+const statusPageMaxUpdates = 20
+
+// majorIncidentStatusLabels libellés des statuts des points de situation (chronologie de la revue post-incident)
+var majorIncidentStatusLabels = map[string]string{
+	models.MajorIncidentStatusInvestigating: "Analyse en cours",
+	models.MajorIncidentStatusIdentified:    "Cause identifiée",
+	models.MajorIncidentStatusMonitoring:    "Surveillance",
+	models.MajorIncidentStatusResolved:      "Service rétabli",
+}
+
+// MajorIncidentService interface pour le mode incident majeur : diffusion aux utilisateurs, points de situation,
+// page de statut publique et revue post-incident
+type MajorIncidentService interface {
+	Declare(incidentID uint, req dto.DeclareMajorIncidentRequest, declaredByID uint) (*dto.IncidentDTO, error)
+	PostUpdate(incidentID uint, req dto.PostMajorIncidentUpdateRequest, postedByID uint) (*dto.MajorIncidentUpdateDTO, error)
+	End(incidentID uint, req dto.EndMajorIncidentRequest, endedByID uint) (*dto.IncidentDTO, error)
+	GetUpdates(incidentID uint) ([]dto.MajorIncidentUpdateDTO, error)
+	GetActive() ([]dto.IncidentDTO, error)
+	GetPublicStatus() (*dto.PublicStatusDTO, error)
+	GetReview(incidentID uint) (*dto.PostIncidentReviewDTO, error)
+	CreateReview(incidentID uint, createdByID uint) (*dto.PostIncidentReviewDTO, error)
+	UpdateReview(incidentID uint, req dto.UpdatePostIncidentReviewRequest, updatedByID uint) (*dto.PostIncidentReviewDTO, error)
+	RemindOverdueUpdates() (int, error) // Tâche planifiée : rappel des points de situation en retard
+}
+
+// majorIncidentService implémente MajorIncidentService
+type majorIncidentService struct {
+	incidentRepo      repositories.IncidentRepository
+	majorIncidentRepo repositories.MajorIncidentRepository
+	incidentService   IncidentService
+	eventBus          *events.Bus // Diffusion des communications et rappels (notifications)
+}
+
+// NewMajorIncidentService crée une nouvelle instance de MajorIncidentService
+func NewMajorIncidentService(
+	incidentRepo repositories.IncidentRepository,
+	majorIncidentRepo repositories.MajorIncidentRepository,
+	incidentService IncidentService,
+	eventBus *events.Bus,
+) MajorIncidentService {
+	return &majorIncidentService{
+		incidentRepo:      incidentRepo,
+		majorIncidentRepo: majorIncidentRepo,
+		incidentService:   incidentService,
+		eventBus:          eventBus,
+	}
+}
+
+// Declare déclare un incident majeur : le premier message est diffusé à tous les utilisateurs de la filiale du ticket
+func (s *majorIncidentService) Declare(incidentID uint, req dto.DeclareMajorIncidentRequest, declaredByID uint) (*dto.IncidentDTO, error) {
+	incident, err := s.incidentRepo.FindByID(incidentID)
+	if err != nil {
+		return nil, utils.ErrIncidentNotFound
+	}
+	if isActiveMajorIncident(incident) {
+		return nil, errors.New("l'incident est déjà déclaré majeur")
+	}
+
+	publicTitle := strings.TrimSpace(req.PublicTitle)
+	if publicTitle == "" {
+		publicTitle = incident.Ticket.Title
+	}
+	interval := req.UpdateIntervalMinutes
+	if interval == 0 {
+		interval = defaultMajorIncidentUpdateInterval
+	}
+	now := time.Now()
+	err = s.majorIncidentRepo.UpdateMajorFields(incidentID, map[string]any{
+		"is_major":                true,
+		"major_declared_at":       now,
+		"major_declared_by_id":    declaredByID,
+		"major_ended_at":          nil,
+		"public_title":            truncateRunes(publicTitle, 255),
+		"update_interval_minutes": interval,
+		"update_reminder_at":      nil,
+	})
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la déclaration de l'incident majeur")
+	}
+
+	update := &models.MajorIncidentUpdate{
+		IncidentID: incidentID,
+		Status:     models.MajorIncidentStatusInvestigating,
+		Message:    strings.TrimSpace(req.Message),
+		IsPublic:   true,
+		PostedByID: declaredByID,
+		CreatedAt:  now,
+	}
+	if err := s.majorIncidentRepo.CreateUpdate(update); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la publication du point de situation")
+	}
+
+	incidentDTO, err := s.incidentService.GetByID(incidentID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(events.MajorIncidentDeclared, incidentDTO, update, declaredByID)
+	return incidentDTO, nil
+}
+
+// PostUpdate publie un point de situation ; un point public est diffusé aux utilisateurs de la filiale
+func (s *majorIncidentService) PostUpdate(incidentID uint, req dto.PostMajorIncidentUpdateRequest, postedByID uint) (*dto.MajorIncidentUpdateDTO, error) {
+	incident, err := s.incidentRepo.FindByID(incidentID)
+	if err != nil {
+		return nil, utils.ErrIncidentNotFound
+	}
+	if !isActiveMajorIncident(incident) {
+		return nil, errors.New("l'incident n'est pas un incident majeur en cours")
+	}
+
+	update := &models.MajorIncidentUpdate{
+		IncidentID: incidentID,
+		Status:     req.Status,
+		Message:    strings.TrimSpace(req.Message),
+		IsPublic:   !req.Internal,
+		PostedByID: postedByID,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.majorIncidentRepo.CreateUpdate(update); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la publication du point de situation")
+	}
+
+	if update.IsPublic {
+		if incidentDTO, err := s.incidentService.GetByID(incidentID); err == nil {
+			s.publish(events.MajorIncidentUpdated, incidentDTO, update, postedByID)
+		}
+	}
+	updateDTO := majorIncidentUpdateToDTO(update)
+	return &updateDTO, nil
+}
+
+// End termine l'incident majeur : le message de rétablissement est diffusé et la revue post-incident préremplie
+func (s *majorIncidentService) End(incidentID uint, req dto.EndMajorIncidentRequest, endedByID uint) (*dto.IncidentDTO, error) {
+	incident, err := s.incidentRepo.FindByID(incidentID)
+	if err != nil {
+		return nil, utils.ErrIncidentNotFound
+	}
+	if !isActiveMajorIncident(incident) {
+		return nil, errors.New("l'incident n'est pas un incident majeur en cours")
+	}
+
+	now := time.Now()
+	update := &models.MajorIncidentUpdate{
+		IncidentID: incidentID,
+		Status:     models.MajorIncidentStatusResolved,
+		Message:    strings.TrimSpace(req.Message),
+		IsPublic:   true,
+		PostedByID: endedByID,
+		CreatedAt:  now,
+	}
+	if err := s.majorIncidentRepo.CreateUpdate(update); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la publication du point de situation")
+	}
+	if err := s.majorIncidentRepo.UpdateMajorFields(incidentID, map[string]any{"major_ended_at": now}); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la clôture de l'incident majeur")
+	}
+
+	// La revue d'un incident majeur déjà déclaré une première fois est conservée
+	if _, err := s.majorIncidentRepo.FindReview(incidentID); err != nil {
+		if _, err := s.CreateReview(incidentID, endedByID); err != nil {
+			log.Printf("Incident %d: revue post-incident non créée: %v", incidentID, err)
+		}
+	}
+
+	incidentDTO, err := s.incidentService.GetByID(incidentID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(events.MajorIncidentEnded, incidentDTO, update, endedByID)
+	return incidentDTO, nil
+}
+
+// GetUpdates récupère les points de situation d'un incident (notes internes comprises), les plus récents d'abord
+func (s *majorIncidentService) GetUpdates(incidentID uint) ([]dto.MajorIncidentUpdateDTO, error) {
+	if _, err := s.incidentRepo.FindByID(incidentID); err != nil {
+		return nil, utils.ErrIncidentNotFound
+	}
+	updates, err := s.majorIncidentRepo.FindUpdates(incidentID, false)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des points de situation")
+	}
+	updateDTOs := make([]dto.MajorIncidentUpdateDTO, len(updates))
+	for i := range updates {
+		updateDTOs[i] = majorIncidentUpdateToDTO(&updates[i])
+	}
+	return updateDTOs, nil
+}
+
+// GetActive récupère les incidents majeurs en cours
+func (s *majorIncidentService) GetActive() ([]dto.IncidentDTO, error) {
+	incidents, err := s.majorIncidentRepo.FindActive()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des incidents majeurs")
+	}
+	incidentDTOs := make([]dto.IncidentDTO, 0, len(incidents))
+	for _, incident := range incidents {
+		incidentDTO, err := s.incidentService.GetByID(incident.ID)
+		if err != nil {
+			continue // Supprimé entre-temps
+		}
+		incidentDTOs = append(incidentDTOs, *incidentDTO)
+	}
+	return incidentDTOs, nil
+}
+
+// GetPublicStatus construit la page de statut : incidents majeurs en cours puis terminés depuis moins de 24 h,
+// avec leurs seuls points de situation publics
+func (s *majorIncidentService) GetPublicStatus() (*dto.PublicStatusDTO, error) {
+	now := time.Now()
+	incidents, err := s.majorIncidentRepo.FindForStatusPage(now.Add(-statusPageRetention))
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des incidents majeurs")
+	}
+
+	status := &dto.PublicStatusDTO{Operational: true, Incidents: make([]dto.PublicMajorIncidentDTO, 0, len(incidents)), GeneratedAt: now}
+	for _, incident := range incidents {
+		updates, err := s.majorIncidentRepo.FindUpdates(incident.ID, true)
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors de la récupération des points de situation")
+		}
+		if len(updates) > statusPageMaxUpdates {
+			updates = updates[:statusPageMaxUpdates]
+		}
+
+		publicIncident := dto.PublicMajorIncidentDTO{
+			ID:      incident.ID,
+			Title:   incident.PublicTitle,
+			Status:  models.MajorIncidentStatusInvestigating,
+			EndedAt: incident.MajorEndedAt,
+			Updates: make([]dto.PublicStatusUpdateDTO, len(updates)),
+		}
+		if incident.MajorDeclaredAt != nil {
+			publicIncident.StartedAt = *incident.MajorDeclaredAt
+		}
+		if incident.Ticket.Filiale != nil {
+			publicIncident.Filiale = incident.Ticket.Filiale.Name
+		}
+		if len(updates) > 0 {
+			publicIncident.Status = updates[0].Status
+		}
+		for i, update := range updates {
+			publicIncident.Updates[i] = dto.PublicStatusUpdateDTO{Status: update.Status, Message: update.Message, CreatedAt: update.CreatedAt}
+		}
+		if incident.MajorEndedAt == nil {
+			status.Operational = false
+		}
+		status.Incidents = append(status.Incidents, publicIncident)
+	}
+	return status, nil
+}
+
+// GetReview récupère la revue post-incident
+func (s *majorIncidentService) GetReview(incidentID uint) (*dto.PostIncidentReviewDTO, error) {
+	review, err := s.majorIncidentRepo.FindReview(incidentID)
+	if err != nil {
+		return nil, utils.ErrPostIncidentReviewNotFound
+	}
+	reviewDTO := postIncidentReviewToDTO(review)
+	return &reviewDTO, nil
+}
+
+// CreateReview crée la revue post-incident à partir du modèle : résumé, impact et chronologie des points de situation
+func (s *majorIncidentService) CreateReview(incidentID uint, createdByID uint) (*dto.PostIncidentReviewDTO, error) {
+	incident, err := s.incidentRepo.FindByID(incidentID)
+	if err != nil {
+		return nil, utils.ErrIncidentNotFound
+	}
+	if !incident.IsMajor {
+		return nil, errors.New("la revue post-incident est réservée aux incidents majeurs")
+	}
+	if _, err := s.majorIncidentRepo.FindReview(incidentID); err == nil {
+		return nil, errors.New("une revue post-incident existe déjà pour cet incident")
+	}
+	updates, err := s.majorIncidentRepo.FindUpdates(incidentID, false)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des points de situation")
+	}
+
+	review := newPostIncidentReview(incident, updates)
+	review.CreatedByID = createdByID
+	if err := s.majorIncidentRepo.CreateReview(review); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la revue post-incident")
+	}
+	return s.GetReview(incidentID)
+}
+
+// UpdateReview met à jour la revue post-incident ; complete la clôt (cause racine et actions correctives requises)
+func (s *majorIncidentService) UpdateReview(incidentID uint, req dto.UpdatePostIncidentReviewRequest, updatedByID uint) (*dto.PostIncidentReviewDTO, error) {
+	review, err := s.majorIncidentRepo.FindReview(incidentID)
+	if err != nil {
+		return nil, utils.ErrPostIncidentReviewNotFound
+	}
+	if review.Status == models.PostIncidentReviewCompleted {
+		return nil, errors.New("la revue post-incident est clôturée")
+	}
+
+	if req.Summary != nil {
+		review.Summary = strings.TrimSpace(*req.Summary)
+	}
+	if req.Timeline != nil {
+		review.Timeline = strings.TrimSpace(*req.Timeline)
+	}
+	if req.Impact != nil {
+		review.Impact = strings.TrimSpace(*req.Impact)
+	}
+	if req.RootCause != nil {
+		review.RootCause = strings.TrimSpace(*req.RootCause)
+	}
+	if req.Resolution != nil {
+		review.Resolution = strings.TrimSpace(*req.Resolution)
+	}
+	if req.LessonsLearned != nil {
+		review.LessonsLearned = strings.TrimSpace(*req.LessonsLearned)
+	}
+	if req.ActionItems != nil {
+		review.ActionItems = strings.TrimSpace(*req.ActionItems)
+	}
+	if req.Complete {
+		if review.RootCause == "" || review.ActionItems == "" {
+			return nil, errors.New("la cause racine et les actions correctives sont requises pour clôturer la revue")
+		}
+		now := time.Now()
+		review.Status = models.PostIncidentReviewCompleted
+		review.CompletedByID = &updatedByID
+		review.CompletedAt = &now
+	}
+
+	if err := s.majorIncidentRepo.UpdateReview(review); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la revue post-incident")
+	}
+	return s.GetReview(incidentID)
+}
+
+// RemindOverdueUpdates rappelle au pilote et au déclarant les points de situation en retard, au plus une fois par intervalle
+func (s *majorIncidentService) RemindOverdueUpdates() (int, error) {
+	incidents, err := s.majorIncidentRepo.FindActive()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	reminded := 0
+	for i := range incidents {
+		incident := &incidents[i]
+		due := nextMajorIncidentUpdateDue(incident)
+		if due == nil || now.Before(*due) {
+			continue
+		}
+		interval := time.Duration(incident.UpdateIntervalMinutes) * time.Minute
+		if incident.UpdateReminderAt != nil && now.Sub(*incident.UpdateReminderAt) < interval {
+			continue
+		}
+
+		var recipientIDs []uint
+		if incident.Ticket.AssignedToID != nil {
+			recipientIDs = append(recipientIDs, *incident.Ticket.AssignedToID)
+		}
+		if incident.MajorDeclaredByID != nil && (len(recipientIDs) == 0 || recipientIDs[0] != *incident.MajorDeclaredByID) {
+			recipientIDs = append(recipientIDs, *incident.MajorDeclaredByID)
+		}
+		s.eventBus.Publish(context.Background(), events.Event{
+			Type:       events.MajorIncidentUpdateDue,
+			FilialeID:  incident.Ticket.FilialeID,
+			EntityType: "incidents",
+			EntityID:   incident.ID,
+			Metadata: map[string]any{
+				"recipient_ids": recipientIDs,
+				"public_title":  incident.PublicTitle,
+				"ticket_code":   incident.Ticket.Code,
+				"overdue_since": *due,
+			},
+		})
+		if err := s.majorIncidentRepo.UpdateMajorFields(incident.ID, map[string]any{"update_reminder_at": now}); err != nil {
+			return reminded, err
+		}
+		reminded++
+	}
+	return reminded, nil
+}
+
+// publish diffuse une communication d'incident majeur (déclaration, point de situation, rétablissement)
+func (s *majorIncidentService) publish(eventType string, incident *dto.IncidentDTO, update *models.MajorIncidentUpdate, actorID uint) {
+	var filialeID *uint
+	if incident.Ticket != nil {
+		filialeID = incident.Ticket.FilialeID
+	}
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       eventType,
+		ActorID:    &actorID,
+		FilialeID:  filialeID,
+		EntityType: "incidents",
+		EntityID:   incident.ID,
+		Data:       *incident,
+		Metadata: map[string]any{
+			"public_title": incident.PublicTitle,
+			"status":       update.Status,
+			"message":      update.Message,
+			"update_id":    update.ID,
+		},
+	})
+}
+
+// isActiveMajorIncident indique si l'incident est un incident majeur en cours
+func isActiveMajorIncident(incident *models.Incident) bool {
+	return incident.IsMajor && incident.MajorEndedAt == nil
+}
+
+// nextMajorIncidentUpdateDue échéance du prochain point de situation d'un incident majeur en cours
+func nextMajorIncidentUpdateDue(incident *models.Incident) *time.Time {
+	if !isActiveMajorIncident(incident) || incident.UpdateIntervalMinutes <= 0 {
+		return nil
+	}
+	last := incident.LastStatusUpdateAt
+	if last == nil {
+		last = incident.MajorDeclaredAt
+	}
+	if last == nil {
+		return nil
+	}
+	due := last.Add(time.Duration(incident.UpdateIntervalMinutes) * time.Minute)
+	return &due
+}
+
+// newPostIncidentReview prépare la revue post-incident à partir du modèle
+func newPostIncidentReview(incident *models.Incident, updates []models.MajorIncidentUpdate) *models.PostIncidentReview {
+	loc := timezone.Default()
+	ticket := incident.Ticket
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%s - %s", ticket.Code, incident.PublicTitle)
+	var duration time.Duration
+	if incident.MajorDeclaredAt != nil {
+		fmt.Fprintf(&summary, "\nIncident majeur déclaré le %s", incident.MajorDeclaredAt.In(loc).Format("02/01/2006 à 15:04"))
+		if incident.MajorEndedAt != nil {
+			duration = incident.MajorEndedAt.Sub(*incident.MajorDeclaredAt)
+			fmt.Fprintf(&summary, ", service rétabli le %s", incident.MajorEndedAt.In(loc).Format("02/01/2006 à 15:04"))
+		}
+		summary.WriteString(".")
+	}
+
+	impact := fmt.Sprintf("Impact : %s, urgence : %s", incident.Impact, incident.Urgency)
+	if duration > 0 {
+		impact += fmt.Sprintf("\nDurée de l'interruption : %s", formatOutage(duration))
+	}
+	impact += "\nUtilisateurs et services affectés : "
+
+	// Chronologie dans l'ordre des événements (les points de situation sont lus du plus récent au plus ancien)
+	var timeline strings.Builder
+	resolution := ""
+	for i := len(updates) - 1; i >= 0; i-- {
+		update := updates[i]
+		label := majorIncidentStatusLabels[update.Status]
+		if !update.IsPublic {
+			label += " (note interne)"
+		}
+		fmt.Fprintf(&timeline, "- **%s** · %s · %s", update.CreatedAt.In(loc).Format("02/01/2006 15:04"), label, update.Message)
+		if update.PostedBy.ID != 0 {
+			fmt.Fprintf(&timeline, " (%s)", update.PostedBy.Username)
+		}
+		timeline.WriteString("\n")
+		if update.Status == models.MajorIncidentStatusResolved {
+			resolution = update.Message
+		}
+	}
+
+	return &models.PostIncidentReview{
+		IncidentID:     incident.ID,
+		Status:         models.PostIncidentReviewDraft,
+		Summary:        summary.String(),
+		Timeline:       strings.TrimRight(timeline.String(), "\n"),
+		Impact:         impact,
+		Resolution:     resolution,
+		LessonsLearned: "Ce qui a bien fonctionné :\n- \n\nCe qui peut être amélioré :\n- ",
+		ActionItems:    "| Action | Responsable | Échéance |\n|---|---|---|\n|  |  |  |",
+	}
+}
+
+// formatOutage formate une durée d'interruption (ex: 2 h 05 min)
+func formatOutage(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%d h %02d min", minutes/60, minutes%60)
+}
+
+// majorIncidentUpdateToDTO convertit un point de situation en DTO
+func majorIncidentUpdateToDTO(update *models.MajorIncidentUpdate) dto.MajorIncidentUpdateDTO {
+	updateDTO := dto.MajorIncidentUpdateDTO{
+		ID:        update.ID,
+		Status:    update.Status,
+		Message:   update.Message,
+		IsPublic:  update.IsPublic,
+		CreatedAt: update.CreatedAt,
+	}
+	if update.PostedBy.ID != 0 {
+		postedBy := userToDTO(&update.PostedBy)
+		updateDTO.PostedBy = &postedBy
+	}
+	return updateDTO
+}
+
+// postIncidentReviewToDTO convertit une revue post-incident en DTO
+func postIncidentReviewToDTO(review *models.PostIncidentReview) dto.PostIncidentReviewDTO {
+	reviewDTO := dto.PostIncidentReviewDTO{
+		ID:             review.ID,
+		IncidentID:     review.IncidentID,
+		Status:         review.Status,
+		Summary:        review.Summary,
+		Timeline:       review.Timeline,
+		Impact:         review.Impact,
+		RootCause:      review.RootCause,
+		Resolution:     review.Resolution,
+		LessonsLearned: review.LessonsLearned,
+		ActionItems:    review.ActionItems,
+		CompletedAt:    review.CompletedAt,
+		CreatedAt:      review.CreatedAt,
+		UpdatedAt:      review.UpdatedAt,
+	}
+	if review.CreatedBy.ID != 0 {
+		createdBy := userToDTO(&review.CreatedBy)
+		reviewDTO.CreatedBy = &createdBy
+	}
+	if review.CompletedBy != nil {
+		completedBy := userToDTO(review.CompletedBy)
+		reviewDTO.CompletedBy = &completedBy
+	}
+	return reviewDTO
+}
@@ -0,0 +1,519 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/alerting"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// alertSourceKeyPrefix préfixe des clés d'API des outils de supervision
+const alertSourceKeyPrefix = "kal_"
+
+// alertSeverityMapping priorité du ticket, impact et urgence de l'incident pour chaque sévérité
+var alertSeverityMapping = map[string][3]string{
+	alerting.SeverityCritical: {"critical", "high", "critical"},
+	alerting.SeverityHigh:     {"high", "medium", "high"},
+	alerting.SeverityWarning:  {"medium", "medium", "medium"},
+	alerting.SeverityInfo:     {"low", "low", "low"},
+}
+
+// MonitoringAlertService interface pour l'ingestion des alertes de supervision (Alertmanager, Zabbix)
+type MonitoringAlertService interface {
+	GetSources() ([]dto.AlertSourceDTO, error)
+	CreateSource(req dto.CreateAlertSourceRequest, createdByID uint) (*dto.AlertSourceDTO, error)
+	UpdateSource(id uint, req dto.UpdateAlertSourceRequest) (*dto.AlertSourceDTO, error)
+	RotateKey(id uint) (*dto.AlertSourceDTO, error)
+	DeleteSource(id uint) error
+	GetAlerts(sourceID *uint, status string, page, limit int) (*dto.MonitoringAlertListResponse, error)
+	Ingest(key string, body []byte) (*dto.AlertIngestResultDTO, error)
+}
+
+// monitoringAlertService implémente MonitoringAlertService
+type monitoringAlertService struct {
+	alertRepo       repositories.MonitoringAlertRepository
+	assetRepo       repositories.AssetRepository
+	ticketService   TicketService
+	incidentService IncidentService
+	mu              sync.Mutex // Sérialise les notifications : les alertes d'une même empreinte ne créent qu'un ticket
+}
+
+// NewMonitoringAlertService crée une nouvelle instance de MonitoringAlertService
+func NewMonitoringAlertService(
+	alertRepo repositories.MonitoringAlertRepository,
+	assetRepo repositories.AssetRepository,
+	ticketService TicketService,
+	incidentService IncidentService,
+) MonitoringAlertService {
+	return &monitoringAlertService{
+		alertRepo:       alertRepo,
+		assetRepo:       assetRepo,
+		ticketService:   ticketService,
+		incidentService: incidentService,
+	}
+}
+
+// GetSources récupère les outils de supervision déclarés
+func (s *monitoringAlertService) GetSources() ([]dto.AlertSourceDTO, error) {
+	sources, err := s.alertRepo.FindSources()
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des sources d'alertes")
+	}
+	sourceDTOs := make([]dto.AlertSourceDTO, len(sources))
+	for i := range sources {
+		sourceDTOs[i] = alertSourceToDTO(&sources[i])
+	}
+	return sourceDTOs, nil
+}
+
+// CreateSource déclare un outil de supervision et retourne sa clé d'API
+func (s *monitoringAlertService) CreateSource(req dto.CreateAlertSourceRequest, createdByID uint) (*dto.AlertSourceDTO, error) {
+	key, err := generateAPIKey(alertSourceKeyPrefix)
+	if err != nil {
+		return nil, errors.New("erreur lors de la génération de la clé d'API")
+	}
+	source := &models.AlertSource{
+		Name:        strings.TrimSpace(req.Name),
+		Kind:        req.Kind,
+		KeyHash:     key.Hash,
+		KeyPrefix:   key.Prefix,
+		FilialeID:   req.FilialeID,
+		AutoResolve: true,
+		IsActive:    true,
+		CreatedByID: createdByID,
+	}
+	if req.AutoResolve != nil {
+		source.AutoResolve = *req.AutoResolve
+	}
+	if err := s.alertRepo.CreateSource(source); err != nil {
+		return nil, errors.New("erreur lors de la création de la source d'alertes")
+	}
+
+	created, err := s.alertRepo.FindSourceByID(source.ID)
+	if err != nil {
+		created = source
+	}
+	sourceDTO := alertSourceToDTO(created)
+	sourceDTO.Key = key.Key
+	return &sourceDTO, nil
+}
+
+// UpdateSource met à jour un outil de supervision
+func (s *monitoringAlertService) UpdateSource(id uint, req dto.UpdateAlertSourceRequest) (*dto.AlertSourceDTO, error) {
+	source, err := s.alertRepo.FindSourceByID(id)
+	if err != nil {
+		return nil, errors.New("source d'alertes introuvable")
+	}
+	if req.Name != nil {
+		source.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.FilialeID != nil {
+		source.FilialeID = req.FilialeID
+		if *req.FilialeID == 0 {
+			source.FilialeID = nil
+		}
+	}
+	if req.AutoResolve != nil {
+		source.AutoResolve = *req.AutoResolve
+	}
+	if req.IsActive != nil {
+		source.IsActive = *req.IsActive
+	}
+	if err := s.alertRepo.UpdateSource(source); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour de la source d'alertes")
+	}
+
+	updated, err := s.alertRepo.FindSourceByID(id)
+	if err != nil {
+		updated = source
+	}
+	sourceDTO := alertSourceToDTO(updated)
+	return &sourceDTO, nil
+}
+
+// RotateKey remplace la clé d'API d'un outil de supervision ; l'ancienne clé est immédiatement refusée
+func (s *monitoringAlertService) RotateKey(id uint) (*dto.AlertSourceDTO, error) {
+	source, err := s.alertRepo.FindSourceByID(id)
+	if err != nil {
+		return nil, errors.New("source d'alertes introuvable")
+	}
+	key, err := generateAPIKey(alertSourceKeyPrefix)
+	if err != nil {
+		return nil, errors.New("erreur lors de la génération de la clé d'API")
+	}
+	source.KeyHash = key.Hash
+	source.KeyPrefix = key.Prefix
+	if err := s.alertRepo.UpdateSource(source); err != nil {
+		return nil, errors.New("erreur lors du renouvellement de la clé d'API")
+	}
+	sourceDTO := alertSourceToDTO(source)
+	sourceDTO.Key = key.Key
+	return &sourceDTO, nil
+}
+
+// DeleteSource supprime un outil de supervision et son historique d'alertes (les tickets sont conservés)
+func (s *monitoringAlertService) DeleteSource(id uint) error {
+	if _, err := s.alertRepo.FindSourceByID(id); err != nil {
+		return errors.New("source d'alertes introuvable")
+	}
+	if err := s.alertRepo.DeleteSource(id); err != nil {
+		return errors.New("erreur lors de la suppression de la source d'alertes")
+	}
+	return nil
+}
+
+// GetAlerts récupère les alertes reçues
+func (s *monitoringAlertService) GetAlerts(sourceID *uint, status string, page, limit int) (*dto.MonitoringAlertListResponse, error) {
+	alerts, total, err := s.alertRepo.FindAll(sourceID, status, page, limit)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des alertes")
+	}
+	alertDTOs := make([]dto.MonitoringAlertDTO, len(alerts))
+	for i := range alerts {
+		alertDTOs[i] = monitoringAlertToDTO(&alerts[i])
+	}
+	return &dto.MonitoringAlertListResponse{
+		Alerts: alertDTOs,
+		Pagination: dto.PaginationDTO{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	}, nil
+}
+
+// Ingest traite une notification de l'outil authentifié par key : création des incidents, déduplication par empreinte et résolution
+// Une alerte en erreur n'empêche pas le traitement des autres ; ses erreurs sont retournées dans le résultat
+func (s *monitoringAlertService) Ingest(key string, body []byte) (*dto.AlertIngestResultDTO, error) {
+	hash, err := apiKeyLookupHash(key, alertSourceKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.alertRepo.FindSourceByKeyHash(hash)
+	if err != nil || !source.IsActive {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+	alerts, err := alerting.Parse(source.Kind, body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &dto.AlertIngestResultDTO{Received: len(alerts)}
+	for _, alert := range alerts {
+		if err := s.ingestAlert(source, alert, result); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", alert.Name, err))
+		}
+	}
+
+	now := time.Now()
+	source.LastReceivedAt = &now
+	_ = s.alertRepo.UpdateSource(source)
+	return result, nil
+}
+
+// ingestAlert applique une alerte : nouvel incident, nouveau déclenchement, répétition ou résolution
+func (s *monitoringAlertService) ingestAlert(source *models.AlertSource, alert alerting.Alert, result *dto.AlertIngestResultDTO) error {
+	now := time.Now()
+	fingerprintHash := utils.HashString(alert.Fingerprint)
+	existing, err := s.alertRepo.FindByFingerprint(source.ID, fingerprintHash)
+	if err != nil {
+		// Première notification de cette alerte
+		record := &models.MonitoringAlert{
+			SourceID:        source.ID,
+			FingerprintHash: fingerprintHash,
+			Fingerprint:     truncateRunes(alert.Fingerprint, 255),
+			Status:          alert.Status,
+			StartsAt:        alert.StartsAt,
+			LastReceivedAt:  now,
+			Occurrences:     1,
+		}
+		applyAlertDetails(record, alert)
+		if alert.Status == alerting.StatusResolved {
+			// Alerte levée avant d'avoir été reçue : conservée pour l'historique, sans ticket
+			record.ResolvedAt = resolvedAt(alert, now)
+			result.Resolved++
+			return s.alertRepo.Create(record)
+		}
+		if err := s.alertRepo.Create(record); err != nil {
+			return fmt.Errorf("enregistrement de l'alerte: %w", err)
+		}
+		result.Created++
+		return s.openIncident(source, record, alert)
+	}
+
+	applyAlertDetails(existing, alert)
+	existing.LastReceivedAt = now
+	switch {
+	case alert.Status == alerting.StatusFiring && existing.Status == models.MonitoringAlertResolved:
+		// Nouveau déclenchement : le ticket est rouvert, ou un nouveau ticket est créé s'il est clôturé
+		existing.Status = models.MonitoringAlertFiring
+		existing.ResolvedAt = nil
+		existing.StartsAt = alert.StartsAt
+		existing.Occurrences++
+		result.Updated++
+		if err := s.alertRepo.Update(existing); err != nil {
+			return fmt.Errorf("mise à jour de l'alerte: %w", err)
+		}
+		return s.reopenIncident(source, existing, alert)
+
+	case alert.Status == alerting.StatusResolved && existing.Status == models.MonitoringAlertFiring:
+		existing.Status = models.MonitoringAlertResolved
+		existing.ResolvedAt = resolvedAt(alert, now)
+		result.Resolved++
+		if err := s.alertRepo.Update(existing); err != nil {
+			return fmt.Errorf("mise à jour de l'alerte: %w", err)
+		}
+		return s.resolveIncident(source, existing)
+	}
+
+	// Répétition d'une notification (même état) ; le ticket est recréé si sa création avait échoué
+	result.Updated++
+	if err := s.alertRepo.Update(existing); err != nil {
+		return fmt.Errorf("mise à jour de l'alerte: %w", err)
+	}
+	if existing.Status == models.MonitoringAlertFiring && existing.TicketID == nil {
+		return s.openIncident(source, existing, alert)
+	}
+	return nil
+}
+
+// openIncident crée le ticket d'incident d'une alerte, rattaché à l'actif et au logiciel reconnus
+func (s *monitoringAlertService) openIncident(source *models.AlertSource, record *models.MonitoringAlert, alert alerting.Alert) error {
+	s.matchAsset(source, record, alert)
+	if value := alert.Label("software", "application", "app", "service"); value != "" {
+		if software, err := s.alertRepo.FindSoftwareByCodeOrName(value); err == nil {
+			record.SoftwareID = &software.ID
+		}
+	}
+
+	mapping, ok := alertSeverityMapping[alert.Severity]
+	if !ok {
+		mapping = alertSeverityMapping[alerting.SeverityInfo]
+	}
+	impact, urgency := mapping[1], mapping[2]
+	if value := strings.ToLower(alert.Label("impact")); isIncidentLevel(value) {
+		impact = value
+	}
+	if value := strings.ToLower(alert.Label("urgency")); isIncidentLevel(value) {
+		urgency = value
+	}
+
+	title := "[Supervision] " + alert.Name
+	if host := alert.Host(); host != "" {
+		title += " sur " + host
+	}
+	ticket, err := s.ticketService.Create(dto.CreateTicketRequest{
+		Title:               truncateRunes(title, 255),
+		Description:         alertDescription(source, alert),
+		Category:            "incident",
+		Source:              "direct",
+		Priority:            mapping[0],
+		RequesterName:       source.Name,
+		RequesterDepartment: "Supervision",
+		FilialeID:           source.FilialeID,
+		SoftwareID:          record.SoftwareID,
+	}, source.CreatedByID)
+	if err != nil {
+		return fmt.Errorf("création du ticket: %w", err)
+	}
+	record.TicketID = &ticket.ID
+	if err := s.alertRepo.Update(record); err != nil {
+		return fmt.Errorf("mise à jour de l'alerte: %w", err)
+	}
+
+	incident, err := s.incidentService.Create(dto.CreateIncidentRequest{TicketID: ticket.ID, Impact: impact, Urgency: urgency}, source.CreatedByID)
+	if err != nil {
+		return fmt.Errorf("création de l'incident: %w", err)
+	}
+	if record.AssetID != nil {
+		if err := s.incidentService.LinkAsset(incident.ID, *record.AssetID, source.CreatedByID); err != nil {
+			return fmt.Errorf("rattachement de l'actif: %w", err)
+		}
+	}
+	return nil
+}
+
+// reopenIncident rouvre le ticket d'une alerte déclenchée à nouveau
+func (s *monitoringAlertService) reopenIncident(source *models.AlertSource, record *models.MonitoringAlert, alert alerting.Alert) error {
+	if record.TicketID == nil {
+		return s.openIncident(source, record, alert)
+	}
+	ticket, err := s.ticketService.GetByID(*record.TicketID, false)
+	if err != nil || ticket.Status == "cloture" {
+		record.TicketID = nil
+		return s.openIncident(source, record, alert)
+	}
+
+	comment := fmt.Sprintf("Alerte de nouveau active (%d déclenchements) : %s", record.Occurrences, alertSummary(alert))
+	if _, err := s.ticketService.AddComment(ticket.ID, dto.CreateTicketCommentRequest{Comment: comment, IsInternal: true}, source.CreatedByID); err != nil {
+		return fmt.Errorf("commentaire du ticket: %w", err)
+	}
+	if ticket.Status == "resolu" {
+		if _, err := s.ticketService.ChangeStatus(ticket.ID, "en_cours", source.CreatedByID); err != nil {
+			return fmt.Errorf("réouverture du ticket: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolveIncident signale la levée de l'alerte sur son ticket et le passe en résolu si la source le prévoit
+func (s *monitoringAlertService) resolveIncident(source *models.AlertSource, record *models.MonitoringAlert) error {
+	if record.TicketID == nil {
+		return nil
+	}
+	ticket, err := s.ticketService.GetByID(*record.TicketID, false)
+	if err != nil || ticket.Status == "resolu" || ticket.Status == "cloture" {
+		return nil
+	}
+
+	comment := "Alerte levée par " + source.Name
+	if record.ResolvedAt != nil {
+		comment += " le " + record.ResolvedAt.Format("02/01/2006 à 15:04")
+	}
+	if _, err := s.ticketService.AddComment(ticket.ID, dto.CreateTicketCommentRequest{Comment: comment, IsInternal: true}, source.CreatedByID); err != nil {
+		return fmt.Errorf("commentaire du ticket: %w", err)
+	}
+	if source.AutoResolve {
+		if _, err := s.ticketService.ChangeStatus(ticket.ID, "resolu", source.CreatedByID); err != nil {
+			return fmt.Errorf("résolution du ticket: %w", err)
+		}
+	}
+	return nil
+}
+
+// matchAsset reconnaît l'actif concerné : étiquette asset_id, numéro de série, puis nom de l'hôte
+func (s *monitoringAlertService) matchAsset(source *models.AlertSource, record *models.MonitoringAlert, alert alerting.Alert) {
+	if value := alert.Label("asset_id"); value != "" {
+		if id, err := strconv.ParseUint(value, 10, 32); err == nil {
+			if asset, err := s.assetRepo.FindByID(uint(id)); err == nil {
+				record.AssetID = &asset.ID
+				return
+			}
+		}
+	}
+	if value := alert.Label("serial", "serial_number", "asset_serial"); value != "" {
+		if asset, err := s.assetRepo.FindBySerialNumber(value); err == nil {
+			record.AssetID = &asset.ID
+			return
+		}
+	}
+	if host := alert.Host(); host != "" {
+		if asset, err := s.alertRepo.FindAssetByName(host, source.FilialeID); err == nil {
+			record.AssetID = &asset.ID
+		}
+	}
+}
+
+// applyAlertDetails recopie les informations descriptives de la dernière notification
+func applyAlertDetails(record *models.MonitoringAlert, alert alerting.Alert) {
+	record.Name = truncateRunes(alert.Name, 255)
+	record.Severity = alert.Severity
+	record.Summary = alertSummary(alert)
+	record.Labels, _ = json.Marshal(alert.Labels)
+	record.URL = truncateRunes(alert.URL, 1000)
+}
+
+// resolvedAt retourne la date de fin fournie par l'outil, sinon la date de réception
+func resolvedAt(alert alerting.Alert, now time.Time) *time.Time {
+	if alert.EndsAt != nil {
+		return alert.EndsAt
+	}
+	return &now
+}
+
+// alertSummary retourne le résumé d'une alerte (à défaut son nom)
+func alertSummary(alert alerting.Alert) string {
+	if alert.Summary != "" {
+		return alert.Summary
+	}
+	return alert.Name
+}
+
+// alertDescription construit la description du ticket d'incident
+func alertDescription(source *models.AlertSource, alert alerting.Alert) string {
+	var b strings.Builder
+	b.WriteString(alertSummary(alert))
+	if alert.Description != "" && alert.Description != alert.Summary {
+		b.WriteString("\n\n" + alert.Description)
+	}
+	b.WriteString("\n\nAlerte reçue de " + source.Name + " (" + source.Kind + "), sévérité " + alert.Severity)
+	if alert.StartsAt != nil {
+		b.WriteString(", active depuis le " + alert.StartsAt.Local().Format("02/01/2006 à 15:04"))
+	}
+	if alert.URL != "" {
+		b.WriteString("\n" + alert.URL)
+	}
+	if labels := alert.LabelsText(); labels != "" {
+		b.WriteString("\n\nÉtiquettes :\n" + labels)
+	}
+	return b.String()
+}
+
+// isIncidentLevel indique si value est un niveau d'impact ou d'urgence valide
+func isIncidentLevel(value string) bool {
+	switch value {
+	case "low", "medium", "high", "critical":
+		return true
+	}
+	return false
+}
+
+// alertSourceToDTO convertit une source d'alertes en DTO
+func alertSourceToDTO(source *models.AlertSource) dto.AlertSourceDTO {
+	sourceDTO := dto.AlertSourceDTO{
+		ID:             source.ID,
+		Name:           source.Name,
+		Kind:           source.Kind,
+		KeyPrefix:      source.KeyPrefix,
+		FilialeID:      source.FilialeID,
+		AutoResolve:    source.AutoResolve,
+		IsActive:       source.IsActive,
+		LastReceivedAt: source.LastReceivedAt,
+		CreatedAt:      source.CreatedAt,
+	}
+	if source.Filiale != nil {
+		sourceDTO.FilialeName = source.Filiale.Name
+	}
+	return sourceDTO
+}
+
+// monitoringAlertToDTO convertit une alerte en DTO
+func monitoringAlertToDTO(alert *models.MonitoringAlert) dto.MonitoringAlertDTO {
+	alertDTO := dto.MonitoringAlertDTO{
+		ID:             alert.ID,
+		SourceID:       alert.SourceID,
+		SourceName:     alert.Source.Name,
+		Fingerprint:    alert.Fingerprint,
+		Name:           alert.Name,
+		Status:         alert.Status,
+		Severity:       alert.Severity,
+		Summary:        alert.Summary,
+		URL:            alert.URL,
+		TicketID:       alert.TicketID,
+		AssetID:        alert.AssetID,
+		SoftwareID:     alert.SoftwareID,
+		Occurrences:    alert.Occurrences,
+		StartsAt:       alert.StartsAt,
+		ResolvedAt:     alert.ResolvedAt,
+		LastReceivedAt: alert.LastReceivedAt,
+	}
+	_ = json.Unmarshal(alert.Labels, &alertDTO.Labels)
+	if alert.Ticket != nil {
+		alertDTO.TicketCode = alert.Ticket.Code
+	}
+	return alertDTO
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,8 +27,15 @@ type NotificationListOpts struct {
 	FilterFilialeID *uint  // admin: filtrer par filiale
 }
 
+// NotificationChannel canal externe recevant une copie des notifications créées (Telegram, ...)
+// Deliver ne doit pas bloquer : l'envoi est planifié dans la file de tâches
+type NotificationChannel interface {
+	Deliver(notification *models.Notification)
+}
+
 // NotificationService interface pour les opérations sur les notifications
 type NotificationService interface {
+	AddChannel(channel NotificationChannel)
 	Create(userID uint, notificationType string, title string, message string, linkURL string, metadata map[string]any) error
 	GetByID(id uint) (*dto.NotificationDTO, error)
 	GetByUserID(userID uint) ([]dto.NotificationDTO, error)
@@ -44,22 +52,31 @@ type NotificationService interface {
 type notificationService struct {
 	notificationRepo repositories.NotificationRepository
 	userRepo         repositories.UserRepository
+	preferenceRepo   repositories.UserPreferenceRepository
 	hub              *websocket.Hub // Hub WebSocket pour les notifications en temps réel
+	channels         []NotificationChannel
 }
 
 // NewNotificationService crée une nouvelle instance de NotificationService
 func NewNotificationService(
 	notificationRepo repositories.NotificationRepository,
 	userRepo repositories.UserRepository,
+	preferenceRepo repositories.UserPreferenceRepository,
 	hub *websocket.Hub,
 ) NotificationService {
 	return &notificationService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
+		preferenceRepo:   preferenceRepo,
 		hub:              hub,
 	}
 }
 
+// AddChannel ajoute un canal externe de diffusion (à appeler au démarrage, avant le traitement des notifications)
+func (s *notificationService) AddChannel(channel NotificationChannel) {
+	s.channels = append(s.channels, channel)
+}
+
 // Create crée une nouvelle notification
 func (s *notificationService) Create(userID uint, notificationType string, title string, message string, linkURL string, metadata map[string]any) error {
 	// Vérifier que l'utilisateur existe
@@ -68,6 +85,11 @@ func (s *notificationService) Create(userID uint, notificationType string, title
 		return errors.New("utilisateur destinataire introuvable")
 	}
 
+	// Type de notification désactivé par l'utilisateur (préférences)
+	if s.isMuted(userID, notificationType) {
+		return nil
+	}
+
 	// Convertir metadata en JSON si fourni
 	var metadataJSON []byte
 	if metadata != nil {
@@ -119,9 +141,25 @@ func (s *notificationService) Create(userID uint, notificationType string, title
 		log.Printf("📤 Notification WebSocket envoyée à l'utilisateur %d: %s", userID, notification.Title)
 	}
 
+	for _, channel := range s.channels {
+		channel.Deliver(notification)
+	}
+
 	return nil
 }
 
+// isMuted indique si l'utilisateur a désactivé ce type de notification
+func (s *notificationService) isMuted(userID uint, notificationType string) bool {
+	if s.preferenceRepo == nil {
+		return false
+	}
+	preference, err := s.preferenceRepo.FindByUserIDCached(userID)
+	if err != nil || preference == nil {
+		return false
+	}
+	return slices.Contains(mutedNotificationTypes(preference), notificationType)
+}
+
 // GetByID récupère une notification par son ID
 func (s *notificationService) GetByID(id uint) (*dto.NotificationDTO, error) {
 	notification, err := s.notificationRepo.FindByID(id)
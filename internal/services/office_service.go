@@ -10,6 +10,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // OfficeService interface pour les opérations sur les sièges
@@ -166,7 +167,7 @@ func (s *officeService) Create(req dto.CreateOfficeRequest) (*dto.OfficeDTO, err
 	}
 	filiale, err := s.filialeRepo.FindByID(*req.FilialeID)
 	if err != nil {
-		return nil, errors.New("filiale introuvable")
+		return nil, utils.ErrFilialeNotFound
 	}
 
 	// Générer le code automatiquement si non fourni ou vide
@@ -259,7 +260,7 @@ func (s *officeService) GetByFilialeID(filialeID uint) ([]dto.OfficeDTO, error)
 func (s *officeService) GetByID(id uint) (*dto.OfficeDTO, error) {
 	office, err := s.officeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("siège introuvable")
+		return nil, utils.ErrOfficeNotFound
 	}
 
 	return s.officeToDTO(office), nil
@@ -269,7 +270,7 @@ func (s *officeService) GetByID(id uint) (*dto.OfficeDTO, error) {
 func (s *officeService) Update(id uint, req dto.UpdateOfficeRequest) (*dto.OfficeDTO, error) {
 	office, err := s.officeRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("siège introuvable")
+		return nil, utils.ErrOfficeNotFound
 	}
 
 	// Mettre à jour les champs fournis
@@ -302,7 +303,7 @@ func (s *officeService) Update(id uint, req dto.UpdateOfficeRequest) (*dto.Offic
 		}
 		filiale, err := s.filialeRepo.FindByID(*targetFilialeID)
 		if err != nil {
-			return nil, errors.New("filiale introuvable")
+			return nil, utils.ErrFilialeNotFound
 		}
 		prefixed := prefixCodeWithFiliale(filiale.Code, *req.Code)
 		office.Code = &prefixed
@@ -334,7 +335,7 @@ func (s *officeService) Update(id uint, req dto.UpdateOfficeRequest) (*dto.Offic
 func (s *officeService) Delete(id uint) error {
 	_, err := s.officeRepo.FindByID(id)
 	if err != nil {
-		return errors.New("siège introuvable")
+		return utils.ErrOfficeNotFound
 	}
 
 	if err := s.officeRepo.Delete(id); err != nil {
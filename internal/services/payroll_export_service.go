@@ -0,0 +1,230 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/spreadsheet"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Formats de l'export de paie
+const (
+	PayrollExportFormatCSV   = "csv"   // Une ligne d'en-tête puis une ligne par utilisateur et centre de coût
+	PayrollExportFormatFixed = "fixed" // Enregistrements à largeur fixe (compatibles SAP), selon PAYROLL_EXPORT_LAYOUT
+)
+
+// payrollExportMaxDays durée maximale de la période exportée
+const payrollExportMaxDays = 366
+
+// PayrollExportService interface pour l'export des heures validées vers la paie
+type PayrollExportService interface {
+	// Validate vérifie la période et le format demandés, avant tout envoi de la réponse
+	Validate(filter repositories.PayrollExportFilter, format string) error
+	// Export écrit les heures validées de la période, par utilisateur et centre de coût (département ou projet) ;
+	// rien n'est écrit en cas d'erreur
+	Export(filter repositories.PayrollExportFilter, format string, w io.Writer) error
+}
+
+// payrollExportRecord ligne exportée et période couverte
+type payrollExportRecord struct {
+	row    *repositories.PayrollExportRow
+	filter *repositories.PayrollExportFilter
+}
+
+// payrollExportField champ exportable : valeur et alignement (numérique = aligné à droite, complété par des zéros)
+type payrollExportField struct {
+	numeric bool
+	value   func(record payrollExportRecord) string
+}
+
+// payrollExportFields champs utilisables dans PAYROLL_EXPORT_LAYOUT et colonnes du CSV
+var payrollExportFields = map[string]payrollExportField{
+	"personnel_number": {true, func(r payrollExportRecord) string { return strconv.FormatUint(uint64(r.row.UserID), 10) }},
+	"username":         {false, func(r payrollExportRecord) string { return r.row.Username }},
+	"last_name":        {false, func(r payrollExportRecord) string { return r.row.LastName }},
+	"first_name":       {false, func(r payrollExportRecord) string { return r.row.FirstName }},
+	"period_start":     {true, func(r payrollExportRecord) string { return r.filter.From.Format("20060102") }},
+	"period_end":       {true, func(r payrollExportRecord) string { return r.filter.To.Format("20060102") }},
+	"wage_type":        {false, func(payrollExportRecord) string { return config.AppConfig.Payroll.WageType }},
+	"cost_center_type": {false, func(r payrollExportRecord) string { return r.row.CostCenterType }},
+	"cost_center":      {false, func(r payrollExportRecord) string { return r.row.CostCenterCode }},
+	"cost_center_name": {false, func(r payrollExportRecord) string { return r.row.CostCenterName }},
+	"minutes":          {true, func(r payrollExportRecord) string { return strconv.Itoa(r.row.Minutes) }},
+	// Heures en centièmes, virgule implicite (7h30 = 750)
+	"hours":  {true, func(r payrollExportRecord) string { return strconv.Itoa(payrollHundredthsOfHour(r.row.Minutes)) }},
+	"filler": {false, func(payrollExportRecord) string { return "" }},
+}
+
+// payrollExportCSVColumns colonnes du format CSV, dans l'ordre (dates au format AAAA-MM-JJ, heures décimales)
+var payrollExportCSVColumns = []string{
+	"personnel_number", "username", "last_name", "first_name", "period_start", "period_end",
+	"cost_center_type", "cost_center", "cost_center_name", "wage_type", "minutes", "hours",
+}
+
+// payrollLayoutField champ d'un enregistrement à largeur fixe
+type payrollLayoutField struct {
+	name  string
+	width int
+	payrollExportField
+}
+
+// payrollExportService implémente PayrollExportService
+type payrollExportService struct {
+	payrollRepo repositories.PayrollExportRepository
+}
+
+// NewPayrollExportService crée une nouvelle instance de PayrollExportService
+func NewPayrollExportService(payrollRepo repositories.PayrollExportRepository) PayrollExportService {
+	return &payrollExportService{
+		payrollRepo: payrollRepo,
+	}
+}
+
+// Validate vérifie que la période est renseignée, ordonnée et limitée à payrollExportMaxDays, et que le format est supporté
+func (s *payrollExportService) Validate(filter repositories.PayrollExportFilter, format string) error {
+	if filter.From.IsZero() || filter.To.IsZero() {
+		return errors.New("la période est requise (from et to)")
+	}
+	if filter.To.Before(filter.From) {
+		return errors.New("la date de fin doit être postérieure à la date de début")
+	}
+	if days := int(filter.To.Sub(filter.From).Hours()/24) + 1; days > payrollExportMaxDays {
+		return fmt.Errorf("la période ne peut pas dépasser %d jours", payrollExportMaxDays)
+	}
+	if format != PayrollExportFormatCSV && format != PayrollExportFormatFixed {
+		return errors.New("format d'export non supporté (csv ou fixed)")
+	}
+	return nil
+}
+
+// Export agrège les heures validées puis les écrit au format demandé
+func (s *payrollExportService) Export(filter repositories.PayrollExportFilter, format string, w io.Writer) error {
+	if err := s.Validate(filter, format); err != nil {
+		return err
+	}
+
+	var layout []payrollLayoutField
+	if format == PayrollExportFormatFixed {
+		var err error
+		if layout, err = parsePayrollLayout(config.AppConfig.Payroll.Layout); err != nil {
+			log.Printf("[PayrollExport] %v", err)
+			return utils.NewInternalError("erreur lors de la lecture de PAYROLL_EXPORT_LAYOUT")
+		}
+	}
+
+	rows, err := s.payrollRepo.SumValidatedTime(filter)
+	if err != nil {
+		log.Printf("[PayrollExport] agrégation des heures: %v", err)
+		return utils.NewInternalError("erreur lors de l'agrégation des heures validées")
+	}
+
+	if format == PayrollExportFormatFixed {
+		err = writePayrollFixedWidth(rows, &filter, layout, w)
+	} else {
+		err = writePayrollCSV(rows, &filter, w)
+	}
+	if err != nil {
+		log.Printf("[PayrollExport] écriture: %v", err)
+		return utils.NewInternalError("erreur lors de l'export de paie")
+	}
+	return nil
+}
+
+// parsePayrollLayout lit la disposition des enregistrements à largeur fixe (ex: "personnel_number:8,hours:7")
+func parsePayrollLayout(raw string) ([]payrollLayoutField, error) {
+	var layout []payrollLayoutField
+	for _, item := range strings.Split(raw, ",") {
+		name, rawWidth, _ := strings.Cut(strings.TrimSpace(item), ":")
+		field, ok := payrollExportFields[name]
+		if !ok {
+			return nil, fmt.Errorf("PAYROLL_EXPORT_LAYOUT: champ inconnu %q", name)
+		}
+		width, err := strconv.Atoi(rawWidth)
+		if err != nil || width <= 0 {
+			return nil, fmt.Errorf("PAYROLL_EXPORT_LAYOUT: largeur invalide pour %s", name)
+		}
+		layout = append(layout, payrollLayoutField{name: name, width: width, payrollExportField: field})
+	}
+	return layout, nil
+}
+
+// writePayrollFixedWidth écrit un enregistrement par ligne : textes alignés à gauche, complétés par des espaces et tronqués,
+// nombres alignés à droite et complétés par des zéros. Les lignes sont préparées avant l'écriture : un nombre trop long
+// pour son champ est une erreur, rien n'est alors écrit
+func writePayrollFixedWidth(rows []repositories.PayrollExportRow, filter *repositories.PayrollExportFilter, layout []payrollLayoutField, w io.Writer) error {
+	lineEnd := "\n"
+	if config.AppConfig.Payroll.CRLF {
+		lineEnd = "\r\n"
+	}
+	var out strings.Builder
+	for i := range rows {
+		record := payrollExportRecord{row: &rows[i], filter: filter}
+		for _, field := range layout {
+			value := field.value(record)
+			length := utf8.RuneCountInString(value)
+			switch {
+			case field.numeric && length > field.width:
+				return fmt.Errorf("valeur %s trop longue pour le champ %s (largeur %d dans PAYROLL_EXPORT_LAYOUT)",
+					value, field.name, field.width)
+			case field.numeric:
+				out.WriteString(strings.Repeat("0", field.width-length))
+				out.WriteString(value)
+			case length > field.width:
+				out.WriteString(string([]rune(value)[:field.width]))
+			default:
+				out.WriteString(value)
+				out.WriteString(strings.Repeat(" ", field.width-length))
+			}
+		}
+		out.WriteString(lineEnd)
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// writePayrollCSV écrit les heures au format CSV, avec dates lisibles et heures décimales (7.50)
+func writePayrollCSV(rows []repositories.PayrollExportRow, filter *repositories.PayrollExportFilter, w io.Writer) error {
+	writer, err := spreadsheet.NewWriter(w, spreadsheet.FormatCSV)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRow(payrollExportCSVColumns); err != nil {
+		return err
+	}
+	periodStart := filter.From.Format("2006-01-02")
+	periodEnd := filter.To.Format("2006-01-02")
+	row := make([]string, len(payrollExportCSVColumns))
+	for i := range rows {
+		record := payrollExportRecord{row: &rows[i], filter: filter}
+		for j, name := range payrollExportCSVColumns {
+			switch name {
+			case "period_start":
+				row[j] = periodStart
+			case "period_end":
+				row[j] = periodEnd
+			case "hours":
+				hundredths := payrollHundredthsOfHour(rows[i].Minutes)
+				row[j] = fmt.Sprintf("%d.%02d", hundredths/100, hundredths%100)
+			default:
+				row[j] = payrollExportFields[name].value(record)
+			}
+		}
+		if err := writer.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// payrollHundredthsOfHour convertit des minutes en centièmes d'heure, arrondis au plus proche
+func payrollHundredthsOfHour(minutes int) int {
+	return (minutes*100 + 30) / 60
+}
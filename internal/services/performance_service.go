@@ -2,10 +2,16 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // PerformanceService interface pour les opérations sur les performances
@@ -17,14 +23,32 @@ type PerformanceService interface {
 	GetWorkloadByUserID(userID uint) (*dto.WorkloadDTO, error)
 	GetPerformanceRanking(limit int) ([]dto.PerformanceRankingDTO, error)
 	GetTeamComparison() (*dto.TeamComparisonDTO, error)
+	// GetUtilization calcule, par utilisateur, équipe (département) et période (week ou month), la répartition du temps
+	// déclaré par catégorie de travail et facturable / non facturable, et le taux d'utilisation (facturable / capacité)
+	GetUtilization(scope interface{}, filter repositories.CapacityFilter, from, to time.Time, granularity string) (*dto.UtilizationReportDTO, error)
+	GetUserUtilization(scope interface{}, userID uint, from, to time.Time, granularity string) (*dto.UserUtilizationDTO, error)
+	// GetUtilizationTargets liste les objectifs d'utilisation des rôles (filialeID non nil : rôles de la filiale et globaux)
+	GetUtilizationTargets(filialeID *uint) ([]dto.UtilizationTargetDTO, error)
+	// SetUtilizationTarget définit l'objectif d'un rôle (filialeID non nil : rôles de la filiale uniquement)
+	SetUtilizationTarget(roleID uint, req dto.SetUtilizationTargetRequest, filialeID *uint) (*dto.UtilizationTargetDTO, error)
 }
 
+const (
+	UtilizationGranularityWeek  = "week"
+	UtilizationGranularityMonth = "month"
+	// utilizationMaxDays durée maximale d'un rapport d'utilisation
+	utilizationMaxDays = 366
+)
+
 // performanceService implémente PerformanceService
 type performanceService struct {
 	ticketRepo    repositories.TicketRepository
 	timeEntryRepo repositories.TimeEntryRepository
 	delayRepo     repositories.DelayRepository
 	userRepo      repositories.UserRepository
+	capacityRepo  repositories.CapacityRepository
+	absenceRepo   repositories.AbsenceRepository
+	roleRepo      repositories.RoleRepository
 }
 
 // NewPerformanceService crée une nouvelle instance de PerformanceService
@@ -33,12 +57,18 @@ func NewPerformanceService(
 	timeEntryRepo repositories.TimeEntryRepository,
 	delayRepo repositories.DelayRepository,
 	userRepo repositories.UserRepository,
+	capacityRepo repositories.CapacityRepository,
+	absenceRepo repositories.AbsenceRepository,
+	roleRepo repositories.RoleRepository,
 ) PerformanceService {
 	return &performanceService{
 		ticketRepo:    ticketRepo,
 		timeEntryRepo: timeEntryRepo,
 		delayRepo:     delayRepo,
 		userRepo:      userRepo,
+		capacityRepo:  capacityRepo,
+		absenceRepo:   absenceRepo,
+		roleRepo:      roleRepo,
 	}
 }
 
@@ -47,7 +77,7 @@ func (s *performanceService) GetPerformanceByUserID(userID uint) (*dto.Performan
 	// Vérifier que l'utilisateur existe
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	// TODO: Implémenter les calculs de performance
@@ -63,7 +93,7 @@ func (s *performanceService) GetPerformanceByUserID(userID uint) (*dto.Performan
 func (s *performanceService) GetEfficiencyByUserID(userID uint) (*dto.EfficiencyDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	userDTO := s.userToDTO(user)
@@ -77,7 +107,7 @@ func (s *performanceService) GetEfficiencyByUserID(userID uint) (*dto.Efficiency
 func (s *performanceService) GetProductivityByUserID(userID uint) (*dto.ProductivityDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	userDTO := s.userToDTO(user)
@@ -91,7 +121,7 @@ func (s *performanceService) GetProductivityByUserID(userID uint) (*dto.Producti
 func (s *performanceService) GetBudgetComplianceByUserID(userID uint) (*dto.BudgetComplianceDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	userDTO := s.userToDTO(user)
@@ -105,7 +135,7 @@ func (s *performanceService) GetBudgetComplianceByUserID(userID uint) (*dto.Budg
 func (s *performanceService) GetWorkloadByUserID(userID uint) (*dto.WorkloadDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	userDTO := s.userToDTO(user)
@@ -127,6 +157,291 @@ func (s *performanceService) GetTeamComparison() (*dto.TeamComparisonDTO, error)
 	return &dto.TeamComparisonDTO{}, nil
 }
 
+// utilizationPeriod borne une période du rapport d'utilisation
+type utilizationPeriod struct {
+	label      string
+	start, end time.Time
+}
+
+// utilizationPeriods découpe [from, to] en semaines ISO ou en mois, bornés à la plage
+func utilizationPeriods(from, to time.Time, granularity string) []utilizationPeriod {
+	var periods []utilizationPeriod
+	for start := from; !start.After(to); {
+		var end time.Time
+		var label string
+		if granularity == UtilizationGranularityMonth {
+			end = time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+			label = start.Format("2006-01")
+		} else {
+			end = mondayOf(start).AddDate(0, 0, 6)
+			year, week := start.ISOWeek()
+			label = fmt.Sprintf("%d-W%02d", year, week)
+		}
+		if end.After(to) {
+			end = to
+		}
+		periods = append(periods, utilizationPeriod{label: label, start: start, end: end})
+		start = end.AddDate(0, 0, 1)
+	}
+	return periods
+}
+
+// addUtilization cumule a dans total (temps et capacité)
+func addUtilization(total *dto.UtilizationFiguresDTO, a dto.UtilizationFiguresDTO) {
+	total.TotalMinutes += a.TotalMinutes
+	total.BillableMinutes += a.BillableMinutes
+	total.NonBillableMinutes += a.NonBillableMinutes
+	total.SupportMinutes += a.SupportMinutes
+	total.ProjectMinutes += a.ProjectMinutes
+	total.InternalMinutes += a.InternalMinutes
+	total.CapacityMinutes += a.CapacityMinutes
+}
+
+// computeUtilizationRates calcule la part facturable et le taux d'utilisation (en %, au dixième)
+func computeUtilizationRates(f *dto.UtilizationFiguresDTO) {
+	f.BillablePercent, f.UtilizationPercent = 0, 0
+	if f.TotalMinutes > 0 {
+		f.BillablePercent = math.Round(float64(f.BillableMinutes)*1000/float64(f.TotalMinutes)) / 10
+	}
+	if f.CapacityMinutes > 0 {
+		f.UtilizationPercent = math.Round(float64(f.BillableMinutes)*1000/float64(f.CapacityMinutes)) / 10
+	}
+}
+
+// newUtilizationPeriods initialise les périodes du rapport (sans temps)
+func newUtilizationPeriods(periods []utilizationPeriod) []dto.UtilizationPeriodDTO {
+	result := make([]dto.UtilizationPeriodDTO, len(periods))
+	for i, p := range periods {
+		result[i] = dto.UtilizationPeriodDTO{Period: p.label, StartDate: p.start, EndDate: p.end}
+	}
+	return result
+}
+
+// GetUtilization calcule le rapport d'utilisation. La capacité compte 8 h par jour ouvré hors absences approuvées ;
+// le taux d'utilisation rapporte le temps facturable à cette capacité et l'objectif est celui du rôle de l'utilisateur
+func (s *performanceService) GetUtilization(scopeParam interface{}, filter repositories.CapacityFilter, from, to time.Time, granularity string) (*dto.UtilizationReportDTO, error) {
+	if granularity == "" {
+		granularity = UtilizationGranularityWeek
+	}
+	if granularity != UtilizationGranularityWeek && granularity != UtilizationGranularityMonth {
+		return nil, errors.New("granularité invalide (week ou month)")
+	}
+	from, to = calendarDay(from), calendarDay(to)
+	if to.Before(from) {
+		return nil, errors.New("la date de fin doit être postérieure à la date de début")
+	}
+	if calendarDaysBetween(from, to) >= utilizationMaxDays {
+		return nil, fmt.Errorf("la période ne peut pas dépasser %d jours", utilizationMaxDays)
+	}
+
+	users, err := s.capacityRepo.FindUsers(scopeParam, filter)
+	if err != nil {
+		log.Printf("[GetUtilization] users: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul de l'utilisation")
+	}
+	userIDs := make([]uint, len(users))
+	for i := range users {
+		userIDs[i] = users[i].ID
+	}
+	totals, err := s.timeEntryRepo.SumByCategory(userIDs, from, to)
+	if err != nil {
+		log.Printf("[GetUtilization] time entries: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul de l'utilisation")
+	}
+	absences, err := s.absenceRepo.FindApproved(userIDs, from, to)
+	if err != nil {
+		log.Printf("[GetUtilization] absences: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul de l'utilisation")
+	}
+	absencesByUser := make(map[uint][]models.Absence)
+	for _, absence := range absences {
+		absencesByUser[absence.UserID] = append(absencesByUser[absence.UserID], absence)
+	}
+
+	periods := utilizationPeriods(from, to, granularity)
+	periodIndex := func(d time.Time) int {
+		for i, p := range periods {
+			if !d.Before(p.start) && !d.After(p.end) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// Temps déclaré par utilisateur et par période
+	declared := make(map[uint][]dto.UtilizationFiguresDTO, len(users))
+	for _, id := range userIDs {
+		declared[id] = make([]dto.UtilizationFiguresDTO, len(periods))
+	}
+	for _, total := range totals {
+		i := periodIndex(calendarDay(total.Date))
+		if i < 0 || declared[total.UserID] == nil {
+			continue
+		}
+		f := &declared[total.UserID][i]
+		f.TotalMinutes += total.Minutes
+		if total.Billable {
+			f.BillableMinutes += total.Minutes
+		} else {
+			f.NonBillableMinutes += total.Minutes
+		}
+		switch total.WorkCategory {
+		case models.WorkCategoryProject:
+			f.ProjectMinutes += total.Minutes
+		case models.WorkCategoryInternal:
+			f.InternalMinutes += total.Minutes
+		default:
+			f.SupportMinutes += total.Minutes
+		}
+	}
+
+	report := &dto.UtilizationReportDTO{
+		From:        from,
+		To:          to,
+		Granularity: granularity,
+		Users:       make([]dto.UserUtilizationDTO, 0, len(users)),
+		Teams:       []dto.TeamUtilizationDTO{},
+		Periods:     newUtilizationPeriods(periods),
+	}
+	type teamTotals struct {
+		team           *dto.TeamUtilizationDTO
+		targetCapacity float64 // Capacité des membres ayant un objectif
+		targetWeighted float64 // Somme objectif x capacité
+	}
+	teams := make(map[uint]*teamTotals)
+	for i := range users {
+		u := &users[i]
+		absent := absentDays(absencesByUser[u.ID], from, to)
+		userDTO := dto.UserUtilizationDTO{
+			UserID:       u.ID,
+			Username:     u.Username,
+			FullName:     capacityFullName(u),
+			DepartmentID: u.DepartmentID,
+			FilialeID:    u.FilialeID,
+			Periods:      newUtilizationPeriods(periods),
+		}
+		if u.Role.ID != 0 {
+			userDTO.Role = u.Role.Name
+			userDTO.TargetPercent = u.Role.UtilizationTarget
+		}
+		for p, period := range periods {
+			figures := declared[u.ID][p]
+			figures.CapacityMinutes, _ = expectedWorkload(period.start, period.end, absent)
+			computeUtilizationRates(&figures)
+			userDTO.Periods[p].UtilizationFiguresDTO = figures
+			addUtilization(&userDTO.UtilizationFiguresDTO, figures)
+			addUtilization(&report.Periods[p].UtilizationFiguresDTO, figures)
+		}
+		computeUtilizationRates(&userDTO.UtilizationFiguresDTO)
+		if userDTO.TargetPercent != nil {
+			met := userDTO.UtilizationPercent >= *userDTO.TargetPercent
+			userDTO.TargetMet = &met
+		}
+		addUtilization(&report.Total, userDTO.UtilizationFiguresDTO)
+
+		// Équipe : département de l'utilisateur (0 : sans département)
+		var teamKey uint
+		if u.DepartmentID != nil {
+			teamKey = *u.DepartmentID
+		}
+		t := teams[teamKey]
+		if t == nil {
+			t = &teamTotals{team: &dto.TeamUtilizationDTO{DepartmentID: u.DepartmentID, DepartmentName: "Sans département", Periods: newUtilizationPeriods(periods)}}
+			if u.Department != nil && u.Department.ID != 0 {
+				t.team.DepartmentName = u.Department.Name
+			}
+			teams[teamKey] = t
+		}
+		t.team.UserCount++
+		for p := range periods {
+			addUtilization(&t.team.Periods[p].UtilizationFiguresDTO, userDTO.Periods[p].UtilizationFiguresDTO)
+		}
+		addUtilization(&t.team.UtilizationFiguresDTO, userDTO.UtilizationFiguresDTO)
+		if userDTO.TargetPercent != nil {
+			t.targetCapacity += float64(userDTO.CapacityMinutes)
+			t.targetWeighted += *userDTO.TargetPercent * float64(userDTO.CapacityMinutes)
+		}
+
+		report.Users = append(report.Users, userDTO)
+	}
+
+	for _, t := range teams {
+		for p := range t.team.Periods {
+			computeUtilizationRates(&t.team.Periods[p].UtilizationFiguresDTO)
+		}
+		computeUtilizationRates(&t.team.UtilizationFiguresDTO)
+		if t.targetCapacity > 0 {
+			target := math.Round(t.targetWeighted*10/t.targetCapacity) / 10
+			t.team.TargetPercent = &target
+		}
+		report.Teams = append(report.Teams, *t.team)
+	}
+	sort.Slice(report.Teams, func(i, j int) bool { return report.Teams[i].DepartmentName < report.Teams[j].DepartmentName })
+	for p := range report.Periods {
+		computeUtilizationRates(&report.Periods[p].UtilizationFiguresDTO)
+	}
+	computeUtilizationRates(&report.Total)
+	return report, nil
+}
+
+// GetUserUtilization calcule l'utilisation d'un utilisateur visible dans le périmètre
+func (s *performanceService) GetUserUtilization(scopeParam interface{}, userID uint, from, to time.Time, granularity string) (*dto.UserUtilizationDTO, error) {
+	report, err := s.GetUtilization(scopeParam, repositories.CapacityFilter{UserID: &userID}, from, to, granularity)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Users) == 0 {
+		return nil, utils.ErrUserNotFound
+	}
+	return &report.Users[0], nil
+}
+
+// GetUtilizationTargets liste les objectifs d'utilisation des rôles
+func (s *performanceService) GetUtilizationTargets(filialeID *uint) ([]dto.UtilizationTargetDTO, error) {
+	var roles []models.Role
+	var err error
+	if filialeID != nil {
+		roles, err = s.roleRepo.FindByFilialeOrGlobal(filialeID)
+	} else {
+		roles, err = s.roleRepo.FindAll()
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des rôles")
+	}
+	targets := make([]dto.UtilizationTargetDTO, 0, len(roles))
+	for i := range roles {
+		targets = append(targets, utilizationTargetToDTO(&roles[i]))
+	}
+	return targets, nil
+}
+
+// SetUtilizationTarget définit ou retire (null) l'objectif d'utilisation d'un rôle
+func (s *performanceService) SetUtilizationTarget(roleID uint, req dto.SetUtilizationTargetRequest, filialeID *uint) (*dto.UtilizationTargetDTO, error) {
+	role, err := s.roleRepo.FindByID(roleID)
+	if err != nil {
+		return nil, utils.ErrRoleNotFound
+	}
+	if filialeID != nil && (role.FilialeID == nil || *role.FilialeID != *filialeID) {
+		return nil, errors.New("vous ne pouvez définir l'objectif que des rôles de votre filiale")
+	}
+	if err := s.roleRepo.UpdateUtilizationTarget(roleID, req.TargetPercent); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'objectif d'utilisation")
+	}
+	role.UtilizationTarget = req.TargetPercent
+	target := utilizationTargetToDTO(role)
+	return &target, nil
+}
+
+// utilizationTargetToDTO convertit l'objectif d'utilisation d'un rôle en DTO
+func utilizationTargetToDTO(role *models.Role) dto.UtilizationTargetDTO {
+	return dto.UtilizationTargetDTO{
+		RoleID:        role.ID,
+		RoleName:      role.Name,
+		FilialeID:     role.FilialeID,
+		TargetPercent: role.UtilizationTarget,
+	}
+}
+
 // userToDTO convertit un modèle User en DTO (méthode helper)
 func (s *performanceService) userToDTO(user *models.User) dto.UserDTO {
 	userDTO := dto.UserDTO{
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+)
+
+const (
+	portfolioDefaultMonths = 6
+	portfolioMaxMonths     = 24
+	// portfolioTopProjects nombre de projets listés dans les classements (budget, retards)
+	portfolioTopProjects = 10
+)
+
+// PortfolioService interface pour le tableau de bord du portefeuille de projets (PMO)
+type PortfolioService interface {
+	// GetPortfolio agrège les projets visibles : répartition par statut, consommation du budget temps, tâches en retard,
+	// comparaison par filiale et tendance sur les derniers mois (mois en cours inclus) ; months <= 0 retient 6 mois
+	GetPortfolio(scope interface{}, filter repositories.PortfolioFilter, months int) (*dto.PortfolioReportDTO, error)
+}
+
+// portfolioService implémente PortfolioService
+type portfolioService struct {
+	portfolioRepo repositories.PortfolioRepository
+}
+
+// NewPortfolioService crée une nouvelle instance de PortfolioService
+func NewPortfolioService(portfolioRepo repositories.PortfolioRepository) PortfolioService {
+	return &portfolioService{
+		portfolioRepo: portfolioRepo,
+	}
+}
+
+// portfolioPercent retourne part / total en pourcentage, arrondi au dixième (0 si total nul)
+func portfolioPercent(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// GetPortfolio calcule le tableau de bord du portefeuille
+func (s *portfolioService) GetPortfolio(scopeParam interface{}, filter repositories.PortfolioFilter, months int) (*dto.PortfolioReportDTO, error) {
+	if months <= 0 {
+		months = portfolioDefaultMonths
+	}
+	if months > portfolioMaxMonths {
+		return nil, fmt.Errorf("la période ne peut pas dépasser %d mois", portfolioMaxMonths)
+	}
+
+	projects, err := s.portfolioRepo.FindProjects(scopeParam, filter)
+	if err != nil {
+		log.Printf("[GetPortfolio] projects: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du portefeuille de projets")
+	}
+	truncated := len(projects) > repositories.MaxPortfolioProjects
+	if truncated {
+		projects = projects[:repositories.MaxPortfolioProjects]
+	}
+	projectIDs := make([]uint, len(projects))
+	for i := range projects {
+		projectIDs[i] = projects[i].ID
+	}
+
+	now := time.Now()
+	today := timezone.Today(timezone.Default())
+	firstMonth := time.Date(today.Year(), today.Month()-time.Month(months-1), 1, 0, 0, 0, 0, today.Location())
+
+	taskCounts, err := s.portfolioRepo.CountTasks(projectIDs, today)
+	if err != nil {
+		log.Printf("[GetPortfolio] task counts: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du portefeuille de projets")
+	}
+	created, err := s.portfolioRepo.CountTasksCreatedByMonth(projectIDs, firstMonth)
+	if err != nil {
+		log.Printf("[GetPortfolio] tasks created: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du portefeuille de projets")
+	}
+	closed, err := s.portfolioRepo.CountTasksClosedByMonth(projectIDs, firstMonth)
+	if err != nil {
+		log.Printf("[GetPortfolio] tasks closed: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du portefeuille de projets")
+	}
+	timeSpent, err := s.portfolioRepo.SumTimeSpentByMonth(projectIDs, firstMonth)
+	if err != nil {
+		log.Printf("[GetPortfolio] time spent: %v", err)
+		return nil, utils.NewInternalError("erreur lors du calcul du portefeuille de projets")
+	}
+
+	report := &dto.PortfolioReportDTO{
+		GeneratedAt:     now,
+		Months:          months,
+		IncludeArchived: filter.IncludeArchived,
+		TotalProjects:   len(projects),
+		Truncated:       truncated,
+		ByStatus:        []dto.PortfolioStatusCountDTO{},
+		Budget:          &dto.PortfolioBudgetDTO{TopConsumers: []dto.PortfolioProjectBudgetDTO{}},
+		Overdue:         dto.PortfolioOverdueDTO{TopProjects: []dto.PortfolioProjectOverdueDTO{}},
+		ByFiliale:       []dto.PortfolioFilialeDTO{},
+		Trend:           make([]dto.PortfolioMonthDTO, months),
+	}
+
+	countsByProject := make(map[uint]repositories.PortfolioTaskCount, len(taskCounts))
+	for _, c := range taskCounts {
+		countsByProject[c.ProjectID] = c
+	}
+
+	// Tendance : un point par mois, du plus ancien au mois en cours
+	monthIndex := make(map[string]int, months)
+	for i := range report.Trend {
+		month := firstMonth.AddDate(0, i, 0).Format("2006-01")
+		report.Trend[i].Month = month
+		monthIndex[month] = i
+	}
+	for _, v := range created {
+		if i, ok := monthIndex[v.Period]; ok {
+			report.Trend[i].TasksCreated = v.Value
+		}
+	}
+	for _, v := range closed {
+		if i, ok := monthIndex[v.Period]; ok {
+			report.Trend[i].TasksClosed = v.Value
+		}
+	}
+	for _, v := range timeSpent {
+		if i, ok := monthIndex[v.Period]; ok {
+			report.Trend[i].TimeSpentMinutes = v.Value
+		}
+	}
+
+	statusCounts := make(map[string]int)
+	filialeIndex := make(map[uint]int)
+	noFiliale := -1
+	for _, p := range projects {
+		statusCounts[p.Status]++
+		if i, ok := monthIndex[p.CreatedAt.In(today.Location()).Format("2006-01")]; ok {
+			report.Trend[i].ProjectsCreated++
+		}
+
+		counts := countsByProject[p.ID]
+		report.Overdue.OpenTasks += counts.OpenCount
+		report.Overdue.OverdueTasks += counts.OverdueCount
+		if counts.OverdueCount > 0 {
+			report.Overdue.ProjectsOverdue++
+			report.Overdue.TopProjects = append(report.Overdue.TopProjects, dto.PortfolioProjectOverdueDTO{
+				ProjectID:    p.ID,
+				Name:         p.Name,
+				OpenTasks:    counts.OpenCount,
+				OverdueTasks: counts.OverdueCount,
+			})
+		}
+
+		budget := 0
+		if p.TotalBudgetTime != nil && *p.TotalBudgetTime > 0 {
+			budget = *p.TotalBudgetTime
+			report.Budget.BudgetedProjects++
+			report.Budget.BudgetMinutes += budget
+			report.Budget.ConsumedMinutes += p.ConsumedTime
+			if p.ConsumedTime > budget {
+				report.Budget.OverBudgetProjects++
+			}
+			report.Budget.TopConsumers = append(report.Budget.TopConsumers, dto.PortfolioProjectBudgetDTO{
+				ProjectID:          p.ID,
+				Name:               p.Name,
+				Status:             p.Status,
+				BudgetMinutes:      budget,
+				ConsumedMinutes:    p.ConsumedTime,
+				ConsumptionPercent: portfolioPercent(p.ConsumedTime, budget),
+			})
+		}
+
+		// Comparaison par filiale (projets sans filiale regroupés)
+		var i int
+		if p.FilialeID == nil {
+			if noFiliale < 0 {
+				noFiliale = len(report.ByFiliale)
+				report.ByFiliale = append(report.ByFiliale, dto.PortfolioFilialeDTO{})
+			}
+			i = noFiliale
+		} else {
+			var ok bool
+			if i, ok = filialeIndex[*p.FilialeID]; !ok {
+				i = len(report.ByFiliale)
+				filialeIndex[*p.FilialeID] = i
+				report.ByFiliale = append(report.ByFiliale, dto.PortfolioFilialeDTO{FilialeID: p.FilialeID, FilialeName: p.FilialeName})
+			}
+		}
+		f := &report.ByFiliale[i]
+		f.Projects++
+		if p.Status == "active" {
+			f.ActiveProjects++
+		}
+		if budget > 0 {
+			f.BudgetMinutes += budget
+			f.ConsumedMinutes += p.ConsumedTime
+		}
+		f.OpenTasks += counts.OpenCount
+		f.OverdueTasks += counts.OverdueCount
+	}
+
+	for status, count := range statusCounts {
+		report.ByStatus = append(report.ByStatus, dto.PortfolioStatusCountDTO{Status: status, Count: count})
+	}
+	sort.Slice(report.ByStatus, func(i, j int) bool {
+		if report.ByStatus[i].Count != report.ByStatus[j].Count {
+			return report.ByStatus[i].Count > report.ByStatus[j].Count
+		}
+		return report.ByStatus[i].Status < report.ByStatus[j].Status
+	})
+
+	report.Budget.ConsumptionPercent = portfolioPercent(report.Budget.ConsumedMinutes, report.Budget.BudgetMinutes)
+	sort.SliceStable(report.Budget.TopConsumers, func(i, j int) bool {
+		return report.Budget.TopConsumers[i].ConsumptionPercent > report.Budget.TopConsumers[j].ConsumptionPercent
+	})
+	if len(report.Budget.TopConsumers) > portfolioTopProjects {
+		report.Budget.TopConsumers = report.Budget.TopConsumers[:portfolioTopProjects]
+	}
+
+	sort.SliceStable(report.Overdue.TopProjects, func(i, j int) bool {
+		return report.Overdue.TopProjects[i].OverdueTasks > report.Overdue.TopProjects[j].OverdueTasks
+	})
+	if len(report.Overdue.TopProjects) > portfolioTopProjects {
+		report.Overdue.TopProjects = report.Overdue.TopProjects[:portfolioTopProjects]
+	}
+
+	for i := range report.ByFiliale {
+		f := &report.ByFiliale[i]
+		f.ConsumptionPercent = portfolioPercent(f.ConsumedMinutes, f.BudgetMinutes)
+	}
+	sort.SliceStable(report.ByFiliale, func(i, j int) bool {
+		return report.ByFiliale[i].Projects > report.ByFiliale[j].Projects
+	})
+
+	return report, nil
+}
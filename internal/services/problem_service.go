@@ -0,0 +1,498 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// problemTicketCategory catégorie des tickets portant un problème
+const problemTicketCategory = "probleme"
+
+// ProblemService interface pour les opérations sur les problèmes et erreurs connues
+type ProblemService interface {
+	Create(req dto.CreateProblemRequest, createdByID uint) (*dto.ProblemDTO, error)
+	GetByID(id uint) (*dto.ProblemDTO, error)
+	GetByTicketID(ticketID uint) (*dto.ProblemDTO, error)
+	GetAll(scope interface{}, knownError *bool) ([]dto.ProblemDTO, error)             // scope peut être *scope.QueryScope ou nil
+	GetByIncidentTicketID(scope interface{}, ticketID uint) ([]dto.ProblemDTO, error) // Problèmes auxquels un ticket incident est lié
+	Update(id uint, req dto.UpdateProblemRequest, updatedByID uint) (*dto.ProblemDTO, error)
+	MarkKnownError(id uint, req dto.MarkKnownErrorRequest, declaredByID uint) (*dto.ProblemDTO, error)
+	Resolve(id uint, resolvedByID uint) (*dto.ProblemDTO, error)
+	Delete(id uint) error
+	LinkIncident(id uint, ticketID uint, linkedByID uint) (*dto.ProblemIncidentDTO, error)
+	UnlinkIncident(id uint, ticketID uint) error
+	GetIncidents(id uint) ([]dto.ProblemIncidentDTO, error)
+	PublishWorkaround(id uint, req dto.PublishProblemWorkaroundRequest, publishedByID uint) (*dto.KnowledgeArticleDTO, error)
+}
+
+// problemService implémente ProblemService
+type problemService struct {
+	problemRepo    repositories.ProblemRepository
+	ticketRepo     repositories.TicketRepository
+	articleService KnowledgeArticleService // Publication des contournements dans la base de connaissances
+}
+
+// NewProblemService crée une nouvelle instance de ProblemService
+func NewProblemService(
+	problemRepo repositories.ProblemRepository,
+	ticketRepo repositories.TicketRepository,
+	articleService KnowledgeArticleService,
+) ProblemService {
+	return &problemService{
+		problemRepo:    problemRepo,
+		ticketRepo:     ticketRepo,
+		articleService: articleService,
+	}
+}
+
+// Create crée un nouveau problème à partir d'un ticket et lie éventuellement des tickets incidents
+func (s *problemService) Create(req dto.CreateProblemRequest, createdByID uint) (*dto.ProblemDTO, error) {
+	// Vérifier que le ticket existe et est de catégorie "probleme"
+	ticket, err := s.ticketRepo.FindByID(req.TicketID)
+	if err != nil {
+		return nil, utils.ErrTicketNotFound
+	}
+	if ticket.Category != problemTicketCategory {
+		return nil, errors.New("le ticket doit être de catégorie 'probleme'")
+	}
+
+	// Vérifier qu'un problème n'existe pas déjà pour ce ticket
+	existingProblem, _ := s.problemRepo.FindByTicketID(req.TicketID)
+	if existingProblem != nil {
+		return nil, errors.New("un problème existe déjà pour ce ticket")
+	}
+
+	// Vérifier les tickets incidents avant toute création
+	incidentIDs := make([]uint, 0, len(req.IncidentTicketIDs))
+	seen := map[uint]bool{}
+	for _, incidentID := range req.IncidentTicketIDs {
+		if seen[incidentID] {
+			continue
+		}
+		seen[incidentID] = true
+		if _, err := s.findIncidentTicket(incidentID); err != nil {
+			return nil, err
+		}
+		incidentIDs = append(incidentIDs, incidentID)
+	}
+
+	problem := &models.Problem{
+		TicketID:          req.TicketID,
+		Impact:            req.Impact,
+		RootCause:         strings.TrimSpace(req.RootCause),
+		RootCauseCategory: req.RootCauseCategory,
+		Workaround:        strings.TrimSpace(req.Workaround),
+	}
+	if problem.RootCause != "" {
+		now := time.Now()
+		problem.RootCauseIdentifiedAt = &now
+	}
+
+	if err := s.problemRepo.Create(problem); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création du problème")
+	}
+
+	for _, incidentID := range incidentIDs {
+		link := &models.ProblemIncident{ProblemID: problem.ID, TicketID: incidentID, LinkedByID: &createdByID}
+		if err := s.problemRepo.AddIncident(link); err != nil {
+			return nil, utils.NewInternalError("erreur lors de la liaison des incidents au problème")
+		}
+	}
+
+	return s.GetByID(problem.ID)
+}
+
+// GetByID récupère un problème par son ID, avec ses tickets incidents liés
+func (s *problemService) GetByID(id uint) (*dto.ProblemDTO, error) {
+	problem, err := s.problemRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+	return s.problemDetail(problem)
+}
+
+// GetByTicketID récupère un problème par l'ID de son ticket
+func (s *problemService) GetByTicketID(ticketID uint) (*dto.ProblemDTO, error) {
+	problem, err := s.problemRepo.FindByTicketID(ticketID)
+	if err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+	return s.problemDetail(problem)
+}
+
+// GetAll récupère les problèmes (knownError : filtre optionnel sur les erreurs connues)
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (s *problemService) GetAll(scopeParam interface{}, knownError *bool) ([]dto.ProblemDTO, error) {
+	problems, err := s.problemRepo.FindAll(scopeParam, knownError)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des problèmes")
+	}
+	return s.problemsToDTOs(problems)
+}
+
+// GetByIncidentTicketID récupère les problèmes auxquels un ticket incident est lié
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (s *problemService) GetByIncidentTicketID(scopeParam interface{}, ticketID uint) ([]dto.ProblemDTO, error) {
+	problems, err := s.problemRepo.FindByIncidentTicketID(scopeParam, ticketID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des problèmes")
+	}
+	return s.problemsToDTOs(problems)
+}
+
+// Update met à jour un problème (impact, cause racine, contournement)
+func (s *problemService) Update(id uint, req dto.UpdateProblemRequest, updatedByID uint) (*dto.ProblemDTO, error) {
+	problem, err := s.problemRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+
+	if req.Impact != "" {
+		problem.Impact = req.Impact
+	}
+	if req.RootCause != nil {
+		problem.RootCause = strings.TrimSpace(*req.RootCause)
+	}
+	if req.RootCauseCategory != nil {
+		problem.RootCauseCategory = *req.RootCauseCategory
+	}
+	if req.Workaround != nil {
+		problem.Workaround = strings.TrimSpace(*req.Workaround)
+	}
+
+	// Une erreur connue reste documentée
+	if problem.IsKnownError && (problem.RootCause == "" || problem.Workaround == "") {
+		return nil, errors.New("une erreur connue doit conserver sa cause racine et son contournement")
+	}
+	if problem.RootCause == "" {
+		problem.RootCauseIdentifiedAt = nil
+	} else if problem.RootCauseIdentifiedAt == nil {
+		now := time.Now()
+		problem.RootCauseIdentifiedAt = &now
+	}
+
+	if err := s.problemRepo.Update(problem); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour du problème")
+	}
+
+	return s.GetByID(id)
+}
+
+// MarkKnownError déclare le problème comme erreur connue : la cause racine et le contournement doivent être documentés
+func (s *problemService) MarkKnownError(id uint, req dto.MarkKnownErrorRequest, declaredByID uint) (*dto.ProblemDTO, error) {
+	problem, err := s.problemRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+	if problem.IsKnownError {
+		return nil, errors.New("ce problème est déjà une erreur connue")
+	}
+
+	now := time.Now()
+	if rootCause := strings.TrimSpace(req.RootCause); rootCause != "" {
+		problem.RootCause = rootCause
+	}
+	if workaround := strings.TrimSpace(req.Workaround); workaround != "" {
+		problem.Workaround = workaround
+	}
+	if problem.RootCause == "" || problem.Workaround == "" {
+		return nil, errors.New("une erreur connue doit documenter la cause racine et le contournement")
+	}
+	if problem.RootCauseIdentifiedAt == nil {
+		problem.RootCauseIdentifiedAt = &now
+	}
+	problem.IsKnownError = true
+	problem.KnownErrorAt = &now
+	problem.KnownErrorByID = &declaredByID
+
+	if err := s.problemRepo.Update(problem); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la déclaration de l'erreur connue")
+	}
+
+	return s.GetByID(id)
+}
+
+// Resolve marque le problème comme résolu (correctif définitif appliqué)
+func (s *problemService) Resolve(id uint, resolvedByID uint) (*dto.ProblemDTO, error) {
+	problem, err := s.problemRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+	if problem.ResolvedAt != nil {
+		return nil, errors.New("ce problème est déjà résolu")
+	}
+
+	now := time.Now()
+	problem.ResolvedAt = &now
+	if err := s.problemRepo.Update(problem); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la résolution du problème")
+	}
+
+	return s.GetByID(id)
+}
+
+// Delete supprime un problème (le ticket et les incidents liés sont conservés)
+func (s *problemService) Delete(id uint) error {
+	if _, err := s.problemRepo.FindByID(id); err != nil {
+		return utils.ErrProblemNotFound
+	}
+	if err := s.problemRepo.Delete(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression du problème")
+	}
+	return nil
+}
+
+// LinkIncident lie un ticket incident au problème
+func (s *problemService) LinkIncident(id uint, ticketID uint, linkedByID uint) (*dto.ProblemIncidentDTO, error) {
+	if _, err := s.problemRepo.FindByID(id); err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+	if _, err := s.findIncidentTicket(ticketID); err != nil {
+		return nil, err
+	}
+	if existing, _ := s.problemRepo.FindIncident(id, ticketID); existing != nil {
+		return nil, errors.New("ce ticket incident est déjà lié à ce problème")
+	}
+
+	link := &models.ProblemIncident{ProblemID: id, TicketID: ticketID, LinkedByID: &linkedByID}
+	if err := s.problemRepo.AddIncident(link); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la liaison de l'incident au problème")
+	}
+
+	created, err := s.problemRepo.FindIncident(id, ticketID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de la liaison créée")
+	}
+	linkDTO := problemIncidentToDTO(created)
+	return &linkDTO, nil
+}
+
+// UnlinkIncident supprime la liaison entre le problème et un ticket incident
+func (s *problemService) UnlinkIncident(id uint, ticketID uint) error {
+	if _, err := s.problemRepo.FindByID(id); err != nil {
+		return utils.ErrProblemNotFound
+	}
+	if _, err := s.problemRepo.FindIncident(id, ticketID); err != nil {
+		return utils.ErrProblemIncidentLinkNotFound
+	}
+	if err := s.problemRepo.RemoveIncident(id, ticketID); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de la liaison")
+	}
+	return nil
+}
+
+// GetIncidents récupère les tickets incidents liés au problème
+func (s *problemService) GetIncidents(id uint) ([]dto.ProblemIncidentDTO, error) {
+	if _, err := s.problemRepo.FindByID(id); err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+	links, err := s.problemRepo.FindIncidents(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des incidents liés")
+	}
+	linkDTOs := make([]dto.ProblemIncidentDTO, len(links))
+	for i := range links {
+		linkDTOs[i] = problemIncidentToDTO(&links[i])
+	}
+	return linkDTOs, nil
+}
+
+// PublishWorkaround publie le contournement dans la base de connaissances
+// Une nouvelle publication met à jour l'article existant plutôt que d'en créer un second
+func (s *problemService) PublishWorkaround(id uint, req dto.PublishProblemWorkaroundRequest, publishedByID uint) (*dto.KnowledgeArticleDTO, error) {
+	problem, err := s.problemRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProblemNotFound
+	}
+	if problem.Workaround == "" {
+		return nil, errors.New("aucun contournement à publier pour ce problème")
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = truncateRunes("Erreur connue : "+problem.Ticket.Title, 255)
+	}
+	content := s.workaroundArticleContent(problem)
+
+	if problem.WorkaroundArticleID != nil {
+		published := true
+		categoryID := req.CategoryID
+		article, err := s.articleService.Update(*problem.WorkaroundArticleID, dto.UpdateKnowledgeArticleRequest{
+			Title:         title,
+			Content:       content,
+			ContentFormat: richtext.FormatMarkdown,
+			CategoryID:    &categoryID,
+			IsPublished:   &published,
+		}, publishedByID)
+		if !errors.Is(err, utils.ErrArticleNotFound) {
+			return article, err
+		}
+		// Article supprimé entre-temps : le republier
+	}
+
+	article, err := s.articleService.Create(dto.CreateKnowledgeArticleRequest{
+		Title:         title,
+		Content:       content,
+		ContentFormat: richtext.FormatMarkdown,
+		CategoryID:    req.CategoryID,
+		IsPublished:   true,
+	}, publishedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	problem.WorkaroundArticleID = &article.ID
+	if err := s.problemRepo.Update(problem); err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'enregistrement de l'article du contournement")
+	}
+	return article, nil
+}
+
+// workaroundArticleContent rédige l'article Markdown d'un contournement (symptômes, cause racine, contournement)
+func (s *problemService) workaroundArticleContent(problem *models.Problem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Problème %s : %s\n\n", problem.Ticket.Code, problem.Ticket.Title)
+	if symptoms := richtext.PlainText(problem.Ticket.Description, problem.Ticket.DescriptionFormat); symptoms != "" {
+		b.WriteString("## Symptômes\n\n" + symptoms + "\n\n")
+	}
+	if problem.RootCause != "" {
+		b.WriteString("## Cause racine\n\n" + problem.RootCause + "\n\n")
+	}
+	b.WriteString("## Contournement\n\n" + problem.Workaround + "\n")
+	return b.String()
+}
+
+// findIncidentTicket vérifie qu'un ticket existe et est de catégorie "incident"
+func (s *problemService) findIncidentTicket(ticketID uint) (*models.Ticket, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return nil, utils.ErrTicketNotFound.WithDetails(map[string]any{"ticket_id": ticketID})
+	}
+	if ticket.Category != "incident" {
+		return nil, fmt.Errorf("le ticket %s n'est pas de catégorie 'incident'", ticket.Code)
+	}
+	return ticket, nil
+}
+
+// problemDetail convertit un problème en DTO avec ses tickets incidents liés
+func (s *problemService) problemDetail(problem *models.Problem) (*dto.ProblemDTO, error) {
+	links, err := s.problemRepo.FindIncidents(problem.ID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des incidents liés")
+	}
+	problemDTO := s.problemToDTO(problem)
+	problemDTO.IncidentCount = len(links)
+	problemDTO.Incidents = make([]dto.ProblemIncidentDTO, len(links))
+	for i := range links {
+		problemDTO.Incidents[i] = problemIncidentToDTO(&links[i])
+	}
+	return &problemDTO, nil
+}
+
+// problemsToDTOs convertit une liste de problèmes en DTO avec leur nombre d'incidents liés
+func (s *problemService) problemsToDTOs(problems []models.Problem) ([]dto.ProblemDTO, error) {
+	ids := make([]uint, len(problems))
+	for i, problem := range problems {
+		ids[i] = problem.ID
+	}
+	counts, err := s.problemRepo.CountIncidents(ids)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des problèmes")
+	}
+	problemDTOs := make([]dto.ProblemDTO, len(problems))
+	for i := range problems {
+		problemDTOs[i] = s.problemToDTO(&problems[i])
+		problemDTOs[i].IncidentCount = counts[problems[i].ID]
+	}
+	return problemDTOs, nil
+}
+
+// problemToDTO convertit un modèle Problem en DTO ProblemDTO
+func (s *problemService) problemToDTO(problem *models.Problem) dto.ProblemDTO {
+	ticketDTO := s.ticketToDTO(&problem.Ticket)
+
+	problemDTO := dto.ProblemDTO{
+		ID:                    problem.ID,
+		TicketID:              problem.TicketID,
+		Ticket:                &ticketDTO,
+		Impact:                problem.Impact,
+		RootCause:             problem.RootCause,
+		RootCauseCategory:     problem.RootCauseCategory,
+		RootCauseIdentifiedAt: problem.RootCauseIdentifiedAt,
+		Workaround:            problem.Workaround,
+		IsKnownError:          problem.IsKnownError,
+		KnownErrorAt:          problem.KnownErrorAt,
+		WorkaroundArticleID:   problem.WorkaroundArticleID,
+		ResolvedAt:            problem.ResolvedAt,
+		CreatedAt:             problem.CreatedAt,
+		UpdatedAt:             problem.UpdatedAt,
+	}
+	if problem.Workaround != "" {
+		problemDTO.WorkaroundHTML = richtext.Render(problem.Workaround, richtext.FormatMarkdown)
+	}
+	if problem.KnownErrorBy != nil {
+		knownErrorBy := userToDTO(problem.KnownErrorBy)
+		problemDTO.KnownErrorBy = &knownErrorBy
+	}
+	if problem.WorkaroundArticle != nil {
+		problemDTO.WorkaroundArticle = problem.WorkaroundArticle.Title
+	}
+	return problemDTO
+}
+
+// ticketToDTO convertit le ticket d'un problème en DTO TicketDTO (méthode utilitaire)
+func (s *problemService) ticketToDTO(ticket *models.Ticket) dto.TicketDTO {
+	var assignedToDTO *dto.UserDTO
+	if ticket.AssignedTo != nil {
+		assignedDTO := userToDTO(ticket.AssignedTo)
+		assignedToDTO = &assignedDTO
+	}
+
+	return dto.TicketDTO{
+		ID:                ticket.ID,
+		Code:              ticket.Code,
+		Title:             ticket.Title,
+		Description:       ticket.Description,
+		DescriptionFormat: richtext.NormalizeFormat(ticket.DescriptionFormat),
+		DescriptionHTML:   richtext.Render(ticket.Description, ticket.DescriptionFormat),
+		Category:          ticket.Category,
+		Source:            ticket.Source,
+		Status:            ticket.Status,
+		Priority:          ticket.Priority,
+		AssignedTo:        assignedToDTO,
+		CreatedBy:         userToDTO(&ticket.CreatedBy),
+		EstimatedTime:     ticket.EstimatedTime,
+		ActualTime:        ticket.ActualTime,
+		CreatedAt:         ticket.CreatedAt,
+		UpdatedAt:         ticket.UpdatedAt,
+		ClosedAt:          ticket.ClosedAt,
+		FilialeID:         ticket.FilialeID,
+	}
+}
+
+// problemIncidentToDTO convertit une liaison problème / incident en DTO
+func problemIncidentToDTO(link *models.ProblemIncident) dto.ProblemIncidentDTO {
+	linkDTO := dto.ProblemIncidentDTO{
+		Ticket: dto.RelatedTicketDTO{
+			ID:       link.Ticket.ID,
+			Code:     link.Ticket.Code,
+			Title:    link.Ticket.Title,
+			Status:   link.Ticket.Status,
+			Priority: link.Ticket.Priority,
+		},
+		LinkedAt: link.CreatedAt,
+	}
+	if link.LinkedBy != nil {
+		linkedBy := userToDTO(link.LinkedBy)
+		linkDTO.LinkedBy = &linkedBy
+	}
+	return linkDTO
+}
@@ -1,14 +1,20 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/mcicare/itsm-backend/database"
+	"github.com/mcicare/itsm-backend/internal/boardrank"
+	"github.com/mcicare/itsm-backend/internal/events"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -16,10 +22,15 @@ import (
 type ProjectService interface {
 	Create(name, description string, totalBudgetTime *int, startDate, endDate *string, createdByID uint) (*models.Project, error)
 	GetByID(id uint) (*models.Project, error)
-	GetAll(scope interface{}) ([]models.Project, error)
+	GetAll(scope interface{}, includeArchived bool) ([]models.Project, error) // Projets archivés exclus sauf includeArchived
 	GetByStatus(scope interface{}, status string) ([]models.Project, error)
 	Update(id uint, name, description string, totalBudgetTime *int, status string, startDate, endDate *string, updatedByID uint) (*models.Project, error)
 	Delete(id uint) error
+	// Archivage : un projet archivé et ses éléments sont en lecture seule, sauf via AllowArchivedWrites (administrateurs)
+	Archive(id, archivedByID uint) (*models.Project, error)
+	Unarchive(id, unarchivedByID uint) (*models.Project, error)
+	AllowArchivedWrites() ProjectService // Copie du service autorisée à modifier les projets archivés
+	CheckWritable(projectID uint) error  // utils.ErrProjectArchived si le projet est archivé et non modifiable
 	UpdateConsumedTime(projectID uint, consumedTime int) error
 	AddBudgetExtension(projectID uint, additionalMinutes int, justification string, startDate, endDate *string, createdByID uint) (*models.ProjectBudgetExtension, error)
 	GetBudgetExtensions(projectID uint) ([]models.ProjectBudgetExtension, error)
@@ -54,6 +65,18 @@ type ProjectService interface {
 	CreateTask(projectID, phaseID, createdByID uint, title, description, status, priority string, assigneeIDs []uint, estimatedTime *int, dueDate *string) (*models.ProjectTask, error)
 	UpdateTask(taskID uint, title, description, status, priority string, assigneeIDs *[]uint, estimatedTime *int, actualTime *int, dueDate *string, projectPhaseID *uint) (*models.ProjectTask, error)
 	DeleteTask(taskID uint) error
+	MoveTask(projectID, taskID uint, status string, position int) (*models.ProjectTask, error) // Tableau : change de colonne (statut) et place la tâche à la position donnée
+	// Dépendances entre tâches : une tâche dont un prérequis n'est pas clôturé est marquée bloquée
+	GetTaskDependencies(projectID, taskID uint) ([]models.ProjectTaskDependency, error)
+	AddTaskDependency(projectID, taskID, dependsOnID, createdByID uint) (*models.ProjectTask, error)
+	RemoveTaskDependency(projectID, taskID, dependsOnID uint) (*models.ProjectTask, error)
+	// Jalons : un jalon planifié dont l'échéance est dépassée est en retard, le report de son échéance est notifié
+	GetMilestones(projectID uint) ([]models.ProjectMilestone, error)
+	CreateMilestone(projectID, createdByID uint, name, description, completionCriteria, dueDate string, projectPhaseID *uint) (*models.ProjectMilestone, error)
+	UpdateMilestone(projectID, milestoneID, updatedByID uint, name, description, completionCriteria, dueDate, status *string, projectPhaseID *uint) (*models.ProjectMilestone, error)
+	DeleteMilestone(projectID, milestoneID uint) error
+	GetMilestoneDashboard(scope interface{}, days int) (*ProjectMilestoneDashboard, error)
+	SendMilestoneLateAlerts() (int, error) // Alerte les responsables des jalons passés en retard, une fois par échéance
 }
 
 // projectService implémente ProjectService
@@ -66,7 +89,10 @@ type projectService struct {
 	memberRepo         repositories.ProjectMemberRepository
 	phaseMemberRepo    repositories.ProjectPhaseMemberRepository
 	taskRepo           repositories.ProjectTaskRepository
+	milestoneRepo      repositories.ProjectMilestoneRepository
 	notificationService NotificationService
+	eventBus           *events.Bus // Publication des événements métier (notifications, webhooks, audit, ...)
+	archivedWritable   bool        // Modification des projets archivés autorisée (administrateurs)
 }
 
 // NewProjectService crée une nouvelle instance de ProjectService
@@ -79,7 +105,9 @@ func NewProjectService(
 	memberRepo repositories.ProjectMemberRepository,
 	phaseMemberRepo repositories.ProjectPhaseMemberRepository,
 	taskRepo repositories.ProjectTaskRepository,
+	milestoneRepo repositories.ProjectMilestoneRepository,
 	notificationService NotificationService,
+	eventBus *events.Bus,
 ) ProjectService {
 	return &projectService{
 		projectRepo:        projectRepo,
@@ -90,7 +118,9 @@ func NewProjectService(
 		memberRepo:         memberRepo,
 		phaseMemberRepo:    phaseMemberRepo,
 		taskRepo:           taskRepo,
+		milestoneRepo:      milestoneRepo,
 		notificationService: notificationService,
+		eventBus:           eventBus,
 	}
 }
 
@@ -189,6 +219,7 @@ func (s *projectService) Create(name, description string, totalBudgetTime *int,
 		log.Printf("[Create] project %d: création Lead: %v", createdProject.ID, err)
 	}
 
+	s.publishEvent(events.ProjectCreated, "projects", createdProject.ID, createdByID, createdProject, nil)
 	return createdProject, nil
 }
 
@@ -196,7 +227,7 @@ func (s *projectService) Create(name, description string, totalBudgetTime *int,
 func (s *projectService) GetByID(id uint) (*models.Project, error) {
 	project, err := s.projectRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("projet introuvable")
+		return nil, utils.ErrProjectNotFound
 	}
 
 	return project, nil
@@ -204,8 +235,8 @@ func (s *projectService) GetByID(id uint) (*models.Project, error) {
 
 // GetAll récupère tous les projets
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
-func (s *projectService) GetAll(scopeParam interface{}) ([]models.Project, error) {
-	projects, err := s.projectRepo.FindAll(scopeParam)
+func (s *projectService) GetAll(scopeParam interface{}, includeArchived bool) ([]models.Project, error) {
+	projects, err := s.projectRepo.FindAll(scopeParam, includeArchived)
 	if err != nil {
 		return nil, errors.New("erreur lors de la récupération des projets")
 	}
@@ -228,7 +259,13 @@ func (s *projectService) GetByStatus(scopeParam interface{}, status string) ([]m
 func (s *projectService) Update(id uint, name, description string, totalBudgetTime *int, status string, startDate, endDate *string, updatedByID uint) (*models.Project, error) {
 	project, err := s.projectRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("projet introuvable")
+		return nil, utils.ErrProjectNotFound
+	}
+	if err := s.checkWritable(project); err != nil {
+		return nil, err
+	}
+	if status != "" && (status == models.ProjectStatusArchived) != (project.Status == models.ProjectStatusArchived) {
+		return nil, errors.New("l'archivage d'un projet se fait uniquement par l'archivage et le désarchivage")
 	}
 
 	// Mettre à jour les champs fournis
@@ -286,17 +323,21 @@ func (s *projectService) Update(id uint, name, description string, totalBudgetTi
 		return nil, errors.New("erreur lors de la récupération du projet mis à jour")
 	}
 
+	s.publishEvent(events.ProjectUpdated, "projects", updatedProject.ID, updatedByID, updatedProject, nil)
 	return updatedProject, nil
 }
 
 // Delete supprime un projet et toutes les données liées (cascade manuelle car FK ON DELETE RESTRICT).
 func (s *projectService) Delete(id uint) error {
-	_, err := s.projectRepo.FindByID(id)
+	project, err := s.projectRepo.FindByID(id)
 	if err != nil {
-		return errors.New("projet introuvable")
+		return utils.ErrProjectNotFound
+	}
+	if err := s.checkWritable(project); err != nil {
+		return err
 	}
 
-	return database.DB.Transaction(func(tx *gorm.DB) error {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
 		// 1. Tâches du projet : libérer time_entries puis supprimer commentaires, pièces jointes, historique, assignees, tâches
 		var taskIDs []uint
 		if err := tx.Model(&models.ProjectTask{}).Where("project_id = ?", id).Pluck("id", &taskIDs).Error; err != nil {
@@ -309,10 +350,24 @@ func (s *projectService) Delete(id uint) error {
 			_ = tx.Where("project_task_id IN ?", taskIDs).Delete(&models.ProjectTaskAttachment{}).Error
 			_ = tx.Where("project_task_id IN ?", taskIDs).Delete(&models.ProjectTaskHistory{}).Error
 			_ = tx.Where("project_task_id IN ?", taskIDs).Delete(&models.ProjectTaskAssignee{}).Error
+			_ = tx.Where("project_task_id IN ?", taskIDs).Delete(&models.ProjectTaskTimer{}).Error
 			if err := tx.Where("project_id = ?", id).Delete(&models.ProjectTask{}).Error; err != nil {
 				log.Printf("Delete project: delete tasks error: %v", err)
 				return errors.New("erreur lors de la suppression du projet")
 			}
+			if err := repositories.RecordTombstones(tx, models.SyncEntityTasks, taskIDs, nil); err != nil {
+				log.Printf("Delete project: record task tombstones error: %v", err)
+				return errors.New("erreur lors de la suppression du projet")
+			}
+		}
+		// 1 bis. Jalons et règles de récurrence des tâches (avant les phases auxquelles ils peuvent être liés)
+		if err := tx.Where("project_id = ?", id).Delete(&models.ProjectMilestone{}).Error; err != nil {
+			log.Printf("Delete project: delete milestones error: %v", err)
+			return errors.New("erreur lors de la suppression du projet")
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.ProjectTaskRecurrence{}).Error; err != nil {
+			log.Printf("Delete project: delete task recurrences error: %v", err)
+			return errors.New("erreur lors de la suppression du projet")
 		}
 		// 2. Membres des phases puis phases
 		var phaseIDs []uint
@@ -350,11 +405,15 @@ func (s *projectService) Delete(id uint) error {
 			log.Printf("Delete project: delete budget extensions error: %v", err)
 			return errors.New("erreur lors de la suppression du projet")
 		}
-		// 6. Liaison tickets-projets
+		// 6. Liaisons tickets-projets et tickets-tâches
 		if err := tx.Where("project_id = ?", id).Delete(&models.TicketProject{}).Error; err != nil {
 			log.Printf("Delete project: delete ticket_projects error: %v", err)
 			return errors.New("erreur lors de la suppression du projet")
 		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.TicketProjectTask{}).Error; err != nil {
+			log.Printf("Delete project: delete ticket_project_tasks error: %v", err)
+			return errors.New("erreur lors de la suppression du projet")
+		}
 		// 7. Projet
 		if err := tx.Delete(&models.Project{}, id).Error; err != nil {
 			log.Printf("Delete project: delete project error: %v", err)
@@ -362,6 +421,115 @@ func (s *projectService) Delete(id uint) error {
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.publishEvent(events.ProjectDeleted, "projects", project.ID, 0, project, nil)
+	return nil
+}
+
+// Archive archive un projet : il passe en lecture seule et n'apparaît plus dans la liste par défaut ;
+// son statut courant est conservé pour le désarchivage
+func (s *projectService) Archive(id, archivedByID uint) (*models.Project, error) {
+	project, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProjectNotFound
+	}
+	if project.Status == models.ProjectStatusArchived {
+		return nil, utils.ErrProjectAlreadyArchived
+	}
+	now := time.Now()
+	previousStatus := project.Status
+	project.StatusBeforeArchive = previousStatus
+	project.Status = models.ProjectStatusArchived
+	project.ArchivedAt = &now
+	project.ArchivedByID = &archivedByID
+	if err := s.projectRepo.UpdateArchiveState(project); err != nil {
+		log.Printf("[Archive] project=%d: %v", id, err)
+		return nil, utils.NewInternalError("erreur lors de l'archivage du projet")
+	}
+	archived, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du projet archivé")
+	}
+	s.publishEvent(events.ProjectArchived, "projects", id, archivedByID, archived, map[string]any{
+		"project_name":    archived.Name,
+		"previous_status": previousStatus,
+	})
+	return archived, nil
+}
+
+// Unarchive désarchive un projet et lui rend le statut qu'il avait à l'archivage
+func (s *projectService) Unarchive(id, unarchivedByID uint) (*models.Project, error) {
+	project, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProjectNotFound
+	}
+	if project.Status != models.ProjectStatusArchived {
+		return nil, utils.ErrProjectNotArchived
+	}
+	status := project.StatusBeforeArchive
+	if status == "" {
+		status = "active"
+	}
+	project.Status = status
+	project.StatusBeforeArchive = ""
+	project.ArchivedAt = nil
+	project.ArchivedByID = nil
+	if err := s.projectRepo.UpdateArchiveState(project); err != nil {
+		log.Printf("[Unarchive] project=%d: %v", id, err)
+		return nil, utils.NewInternalError("erreur lors du désarchivage du projet")
+	}
+	restored, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du projet désarchivé")
+	}
+	s.publishEvent(events.ProjectUnarchived, "projects", id, unarchivedByID, restored, map[string]any{
+		"project_name": restored.Name,
+		"status":       status,
+	})
+	return restored, nil
+}
+
+// AllowArchivedWrites retourne une copie du service autorisée à modifier les projets archivés
+func (s *projectService) AllowArchivedWrites() ProjectService {
+	writable := *s
+	writable.archivedWritable = true
+	return &writable
+}
+
+// CheckWritable vérifie que le projet peut être modifié
+func (s *projectService) CheckWritable(projectID uint) error {
+	project, err := s.projectRepo.FindByID(projectID)
+	if err != nil {
+		return utils.ErrProjectNotFound
+	}
+	return s.checkWritable(project)
+}
+
+// checkWritable refuse la modification d'un projet archivé, sauf si le service y est autorisé
+func (s *projectService) checkWritable(project *models.Project) error {
+	if project.Status == models.ProjectStatusArchived && !s.archivedWritable {
+		return utils.ErrProjectArchived
+	}
+	return nil
+}
+
+// publishEvent publie un événement projet sur le bus (les projets ne sont pas rattachés à une filiale)
+func (s *projectService) publishEvent(eventType, entityType string, entityID, actorID uint, data any, metadata map[string]any) {
+	var actor *uint
+	if actorID != 0 {
+		actor = &actorID
+	}
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       eventType,
+		ActorID:    actor,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Data:       data,
+		Metadata:   metadata,
+	})
 }
 
 // UpdateConsumedTime met à jour le temps consommé d'un projet
@@ -374,7 +542,10 @@ func (s *projectService) UpdateConsumedTime(projectID uint, consumedTime int) er
 func (s *projectService) AddBudgetExtension(projectID uint, additionalMinutes int, justification string, startDate, endDate *string, createdByID uint) (*models.ProjectBudgetExtension, error) {
 	p, err := s.projectRepo.FindByID(projectID)
 	if err != nil {
-		return nil, errors.New("projet introuvable")
+		return nil, utils.ErrProjectNotFound
+	}
+	if err := s.checkWritable(p); err != nil {
+		return nil, err
 	}
 	if p.Status != "completed" {
 		return nil, errors.New("l'extension de budget n'est possible que pour un projet clôturé")
@@ -451,6 +622,9 @@ func (s *projectService) UpdateBudgetExtension(projectID, extID uint, additional
 	if ext.ProjectID != projectID {
 		return nil, errors.New("cette extension n'appartient pas à ce projet")
 	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
 	if additionalMinutes <= 0 {
 		return nil, errors.New("le temps ajouté doit être strictement positif")
 	}
@@ -517,6 +691,9 @@ func (s *projectService) DeleteBudgetExtension(projectID, extID uint) error {
 	if ext.ProjectID != projectID {
 		return errors.New("cette extension n'appartient pas à ce projet")
 	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return err
+	}
 	if err := s.projectRepo.IncrementTotalBudgetTime(projectID, -ext.AdditionalMinutes); err != nil {
 		return errors.New("erreur lors de la mise à jour du budget")
 	}
@@ -529,14 +706,14 @@ func (s *projectService) DeleteBudgetExtension(projectID, extID uint) error {
 // --- Phases ---
 func (s *projectService) GetPhases(projectID uint) ([]models.ProjectPhase, error) {
 	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+		return nil, utils.ErrProjectNotFound
 	}
 	return s.phaseRepo.FindByProjectID(projectID)
 }
 
 func (s *projectService) CreatePhase(projectID uint, name, description string, displayOrder int, status string) (*models.ProjectPhase, error) {
-	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
 	}
 	if name == "" {
 		return nil, errors.New("le nom de l'étape est requis")
@@ -556,6 +733,9 @@ func (s *projectService) UpdatePhase(phaseID uint, name, description string, dis
 	if err != nil {
 		return nil, errors.New("étape introuvable")
 	}
+	if err := s.CheckWritable(p.ProjectID); err != nil {
+		return nil, err
+	}
 	if name != "" {
 		p.Name = name
 	}
@@ -575,15 +755,15 @@ func (s *projectService) UpdatePhase(phaseID uint, name, description string, dis
 }
 
 func (s *projectService) DeletePhase(phaseID uint) error {
-	if _, err := s.phaseRepo.FindByID(phaseID); err != nil {
-		return errors.New("étape introuvable")
+	if err := s.checkPhaseWritable(phaseID); err != nil {
+		return err
 	}
 	return s.phaseRepo.Delete(phaseID)
 }
 
 func (s *projectService) ReorderPhases(projectID uint, order []uint) error {
-	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return errors.New("projet introuvable")
+	if err := s.CheckWritable(projectID); err != nil {
+		return err
 	}
 	return s.phaseRepo.Reorder(projectID, order)
 }
@@ -591,14 +771,14 @@ func (s *projectService) ReorderPhases(projectID uint, order []uint) error {
 // --- Functions ---
 func (s *projectService) GetFunctions(projectID uint) ([]models.ProjectFunction, error) {
 	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+		return nil, utils.ErrProjectNotFound
 	}
 	return s.functionRepo.FindByProjectID(projectID)
 }
 
 func (s *projectService) CreateFunction(projectID uint, name, functionType string, displayOrder int) (*models.ProjectFunction, error) {
-	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
 	}
 	if name == "" {
 		return nil, errors.New("le nom de la fonction est requis")
@@ -619,6 +799,11 @@ func (s *projectService) UpdateFunction(functionID uint, name string, functionTy
 	if err != nil {
 		return nil, errors.New("fonction introuvable")
 	}
+	if f.ProjectID != nil {
+		if err := s.CheckWritable(*f.ProjectID); err != nil {
+			return nil, err
+		}
+	}
 	if name != "" {
 		f.Name = name
 	}
@@ -635,26 +820,32 @@ func (s *projectService) UpdateFunction(functionID uint, name string, functionTy
 }
 
 func (s *projectService) DeleteFunction(functionID uint) error {
-	if _, err := s.functionRepo.FindByID(functionID); err != nil {
+	f, err := s.functionRepo.FindByID(functionID)
+	if err != nil {
 		return errors.New("fonction introuvable")
 	}
+	if f.ProjectID != nil {
+		if err := s.CheckWritable(*f.ProjectID); err != nil {
+			return err
+		}
+	}
 	return s.functionRepo.Delete(functionID)
 }
 
 // --- Members ---
 func (s *projectService) GetMembers(projectID uint) ([]models.ProjectMember, error) {
 	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+		return nil, utils.ErrProjectNotFound
 	}
 	return s.memberRepo.FindByProjectID(projectID)
 }
 
 func (s *projectService) AddMember(projectID, userID uint, functionIDs []uint) (*models.ProjectMember, error) {
-	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
 	}
 	if _, err := s.userRepo.FindByID(userID); err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 	existing, _ := s.memberRepo.FindByProjectIDAndUserID(projectID, userID)
 	if existing != nil {
@@ -711,6 +902,9 @@ func (s *projectService) RemoveMember(projectID, userID uint) error {
 	if err != nil || m == nil {
 		return errors.New("membre introuvable")
 	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return err
+	}
 	// Retirer ce membre des tâches où il est assigné
 	tasks, _ := s.taskRepo.FindByProjectID(projectID)
 	for _, t := range tasks {
@@ -736,6 +930,9 @@ func (s *projectService) SetMemberFunctions(projectID, userID uint, functionIDs
 	if err != nil || m == nil {
 		return errors.New("membre introuvable")
 	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return err
+	}
 	for _, fid := range functionIDs {
 		fn, err := s.functionRepo.FindByID(fid)
 		if err != nil || fn == nil {
@@ -749,8 +946,8 @@ func (s *projectService) SetMemberFunctions(projectID, userID uint, functionIDs
 }
 
 func (s *projectService) SetProjectManager(projectID, userID uint) error {
-	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return errors.New("projet introuvable")
+	if err := s.CheckWritable(projectID); err != nil {
+		return err
 	}
 	if userID != 0 {
 		m, _ := s.memberRepo.FindByProjectIDAndUserID(projectID, userID)
@@ -762,8 +959,8 @@ func (s *projectService) SetProjectManager(projectID, userID uint) error {
 }
 
 func (s *projectService) SetLead(projectID, userID uint) error {
-	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return errors.New("projet introuvable")
+	if err := s.CheckWritable(projectID); err != nil {
+		return err
 	}
 	if userID != 0 {
 		m, _ := s.memberRepo.FindByProjectIDAndUserID(projectID, userID)
@@ -775,6 +972,16 @@ func (s *projectService) SetLead(projectID, userID uint) error {
 }
 
 // --- Phase members ---
+
+// checkPhaseWritable vérifie que le projet de l'étape peut être modifié
+func (s *projectService) checkPhaseWritable(phaseID uint) error {
+	ph, err := s.phaseRepo.FindByID(phaseID)
+	if err != nil {
+		return errors.New("étape introuvable")
+	}
+	return s.CheckWritable(ph.ProjectID)
+}
+
 func (s *projectService) GetPhaseMembers(phaseID uint) ([]models.ProjectPhaseMember, error) {
 	if _, err := s.phaseRepo.FindByID(phaseID); err != nil {
 		return nil, errors.New("étape introuvable")
@@ -783,11 +990,11 @@ func (s *projectService) GetPhaseMembers(phaseID uint) ([]models.ProjectPhaseMem
 }
 
 func (s *projectService) AddPhaseMember(phaseID, userID uint, projectFunctionID *uint) (*models.ProjectPhaseMember, error) {
-	if _, err := s.phaseRepo.FindByID(phaseID); err != nil {
-		return nil, errors.New("étape introuvable")
+	if err := s.checkPhaseWritable(phaseID); err != nil {
+		return nil, err
 	}
 	if _, err := s.userRepo.FindByID(userID); err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 	existing, _ := s.phaseMemberRepo.FindByPhaseIDAndUserID(phaseID, userID)
 	if existing != nil {
@@ -834,6 +1041,9 @@ func (s *projectService) RemovePhaseMember(phaseID, userID uint) error {
 	if err != nil || m == nil {
 		return errors.New("membre d'étape introuvable")
 	}
+	if err := s.checkPhaseWritable(phaseID); err != nil {
+		return err
+	}
 	return s.phaseMemberRepo.Delete(m.ID)
 }
 
@@ -842,6 +1052,9 @@ func (s *projectService) SetPhaseMemberFunction(phaseID, userID uint, projectFun
 	if err != nil || m == nil {
 		return errors.New("membre d'étape introuvable")
 	}
+	if err := s.checkPhaseWritable(phaseID); err != nil {
+		return err
+	}
 	m.ProjectFunctionID = projectFunctionID
 	return s.phaseMemberRepo.Update(m)
 }
@@ -849,7 +1062,7 @@ func (s *projectService) SetPhaseMemberFunction(phaseID, userID uint, projectFun
 // --- Tasks ---
 func (s *projectService) GetTasks(projectID uint) ([]models.ProjectTask, error) {
 	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+		return nil, utils.ErrProjectNotFound
 	}
 	return s.taskRepo.FindByProjectID(projectID)
 }
@@ -886,7 +1099,7 @@ func (s *projectService) ensureAssigneesAsMembers(projectID uint, assigneeIDs []
 	}
 }
 
-// recalcAndUpdateProjectConsumedTime recalcule le temps consommé du projet (somme des actual_time des tâches) et met à jour la colonne consumed_time.
+// recalcAndUpdateProjectConsumedTime recalcule le temps consommé du projet (somme des actual_time et ticket_time des tâches) et met à jour la colonne consumed_time.
 func (s *projectService) recalcAndUpdateProjectConsumedTime(projectID uint) error {
 	tasks, err := s.taskRepo.FindByProjectID(projectID)
 	if err != nil {
@@ -894,14 +1107,14 @@ func (s *projectService) recalcAndUpdateProjectConsumedTime(projectID uint) erro
 	}
 	sum := 0
 	for _, t := range tasks {
-		sum += t.ActualTime
+		sum += t.ActualTime + t.TicketTime
 	}
 	return s.projectRepo.UpdateConsumedTime(projectID, sum)
 }
 
 func (s *projectService) CreateTask(projectID, phaseID, createdByID uint, title, description, status, priority string, assigneeIDs []uint, estimatedTime *int, dueDate *string) (*models.ProjectTask, error) {
-	if _, err := s.projectRepo.FindByID(projectID); err != nil {
-		return nil, errors.New("projet introuvable")
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
 	}
 	ph, err := s.phaseRepo.FindByID(phaseID)
 	if err != nil || ph == nil || ph.ProjectID != projectID {
@@ -934,6 +1147,7 @@ func (s *projectService) CreateTask(projectID, phaseID, createdByID uint, title,
 		CreatedByID:    createdByID,
 		EstimatedTime:  estimatedTime,
 		DueDate:        parseDate(dueDate),
+		BoardRank:      s.endOfBoardColumn(projectID, status, 0),
 	}
 	if err := s.taskRepo.Create(t); err != nil {
 		return nil, err
@@ -969,13 +1183,24 @@ func (s *projectService) UpdateTask(taskID uint, title, description, status, pri
 	if err != nil {
 		return nil, errors.New("tâche introuvable")
 	}
+	if err := s.CheckWritable(t.ProjectID); err != nil {
+		return nil, err
+	}
 	if title != "" {
 		t.Title = title
 	}
 	if description != "" {
 		t.Description = description
 	}
+	completed := false
+	reopened := false
 	if status != "" {
+		completed = status == "cloture" && t.Status != "cloture"
+		reopened = status != "cloture" && t.Status == "cloture"
+		if status != t.Status {
+			// Changement de colonne sur le tableau : la tâche passe en fin de colonne
+			t.BoardRank = s.endOfBoardColumn(t.ProjectID, status, t.ID)
+		}
 		t.Status = status
 		if status == "cloture" {
 			now := time.Now()
@@ -1024,7 +1249,20 @@ func (s *projectService) UpdateTask(taskID uint, title, description, status, pri
 		}
 		s.ensureAssigneesAsMembers(t.ProjectID, *assigneeIDs)
 	}
-	return s.taskRepo.FindByID(taskID)
+	updated, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		s.publishEvent(events.TaskCompleted, "project_tasks", taskID, 0, updated, map[string]any{
+			"project_id": updated.ProjectID,
+			"task_code":  updated.Code,
+		})
+	}
+	if completed || reopened {
+		s.refreshDependents(updated)
+	}
+	return updated, nil
 }
 
 func (s *projectService) DeleteTask(taskID uint) error {
@@ -1032,10 +1270,579 @@ func (s *projectService) DeleteTask(taskID uint) error {
 	if err != nil {
 		return errors.New("tâche introuvable")
 	}
+	if err := s.CheckWritable(t.ProjectID); err != nil {
+		return err
+	}
 	projectID := t.ProjectID
+	dependents, _ := s.taskRepo.FindDependents(taskID)
 	if err := s.taskRepo.Delete(taskID); err != nil {
 		return err
 	}
 	_ = s.recalcAndUpdateProjectConsumedTime(projectID)
+	// Les tâches qui n'attendaient plus que celle-ci sont débloquées
+	for i := range dependents {
+		if s.refreshBlocked(&dependents[i]) {
+			s.notifyTaskUnblocked(&dependents[i], t)
+		}
+	}
 	return nil
 }
+
+// --- Task dependencies ---
+
+// boardColumns statuts des tâches, une colonne chacun sur le tableau
+var boardColumns = map[string]bool{"ouvert": true, "en_cours": true, "en_attente": true, "cloture": true}
+
+// endOfBoardColumn retourne une clé d'ordre plaçant une tâche en fin de colonne
+func (s *projectService) endOfBoardColumn(projectID uint, status string, excludeID uint) string {
+	column, err := s.taskRepo.FindBoardColumn(projectID, status, excludeID)
+	if err != nil || len(column) == 0 {
+		rank, _ := boardrank.Between("", "")
+		return rank
+	}
+	rank, err := boardrank.Between(column[len(column)-1].BoardRank, "")
+	if err != nil || len(rank) > boardrank.MaxLength {
+		// Clé finale invalide ou trop longue : la tâche reste sans clé jusqu'au prochain déplacement dans la colonne
+		return ""
+	}
+	return rank
+}
+
+// MoveTask déplace une tâche sur le tableau : changement de statut éventuel (mêmes effets que UpdateTask) puis
+// insertion à la position donnée (0 = haut de colonne) avec une seule nouvelle clé d'ordre ; la colonne n'est
+// renumérotée que si ses clés sont absentes, en double ou trop longues.
+func (s *projectService) MoveTask(projectID, taskID uint, status string, position int) (*models.ProjectTask, error) {
+	t, err := s.findProjectTask(projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = t.Status
+	}
+	if !boardColumns[status] {
+		return nil, errors.New("colonne invalide (ouvert, en_cours, en_attente, cloture)")
+	}
+	if position < 0 {
+		return nil, errors.New("la position doit être positive ou nulle")
+	}
+	if status != t.Status {
+		if _, err := s.UpdateTask(taskID, "", "", status, "", nil, nil, nil, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	column, err := s.taskRepo.FindBoardColumn(projectID, status, taskID)
+	if err != nil {
+		log.Printf("[MoveTask] task=%d: %v", taskID, err)
+		return nil, utils.NewInternalError("erreur lors du déplacement de la tâche")
+	}
+	if position > len(column) {
+		position = len(column)
+	}
+	before, after := "", ""
+	if position > 0 {
+		before = column[position-1].BoardRank
+	}
+	if position < len(column) {
+		after = column[position].BoardRank
+	}
+	unranked := (position > 0 && before == "") || (position < len(column) && after == "")
+	rank, err := boardrank.Between(before, after)
+	if err == nil && len(rank) <= boardrank.MaxLength && !unranked {
+		err = s.taskRepo.SetBoardRanks(map[uint]string{taskID: rank})
+	} else {
+		// Colonne sans clés (tâches antérieures au tableau), clés en double ou trop longues : renumérotation
+		ids := make([]uint, 0, len(column)+1)
+		for i, c := range column {
+			if i == position {
+				ids = append(ids, taskID)
+			}
+			ids = append(ids, c.ID)
+		}
+		if position == len(column) {
+			ids = append(ids, taskID)
+		}
+		keys := boardrank.Spread(len(ids))
+		ranks := make(map[uint]string, len(ids))
+		for i, id := range ids {
+			ranks[id] = keys[i]
+		}
+		err = s.taskRepo.SetBoardRanks(ranks)
+	}
+	if err != nil {
+		log.Printf("[MoveTask] task=%d: %v", taskID, err)
+		return nil, utils.NewInternalError("erreur lors du déplacement de la tâche")
+	}
+	return s.taskRepo.FindByID(taskID)
+}
+
+// findProjectTask charge une tâche en vérifiant qu'elle appartient au projet
+func (s *projectService) findProjectTask(projectID, taskID uint) (*models.ProjectTask, error) {
+	t, err := s.taskRepo.FindByID(taskID)
+	if err != nil || t.ProjectID != projectID {
+		return nil, utils.ErrProjectTaskNotFound
+	}
+	return t, nil
+}
+
+func (s *projectService) GetTaskDependencies(projectID, taskID uint) ([]models.ProjectTaskDependency, error) {
+	if _, err := s.findProjectTask(projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindDependencies(taskID)
+}
+
+// AddTaskDependency rend une tâche dépendante d'une autre tâche du même projet ; les cycles sont refusés
+func (s *projectService) AddTaskDependency(projectID, taskID, dependsOnID, createdByID uint) (*models.ProjectTask, error) {
+	t, err := s.findProjectTask(projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	if taskID == dependsOnID {
+		return nil, errors.New("une tâche ne peut pas dépendre d'elle-même")
+	}
+	if _, err := s.findProjectTask(projectID, dependsOnID); err != nil {
+		return nil, utils.ErrTaskPrerequisiteNotFound.WithDetails(map[string]any{"depends_on_id": dependsOnID})
+	}
+	links, err := s.taskRepo.FindDependenciesByProject(projectID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des dépendances")
+	}
+	prerequisites := make(map[uint][]uint)
+	for _, link := range links {
+		if link.TaskID == taskID && link.DependsOnID == dependsOnID {
+			return nil, errors.New("cette dépendance existe déjà")
+		}
+		prerequisites[link.TaskID] = append(prerequisites[link.TaskID], link.DependsOnID)
+	}
+	// Cycle si la tâche est déjà (directement ou non) un prérequis de la tâche prérequise
+	visited := map[uint]bool{}
+	pending := []uint{dependsOnID}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if id == taskID {
+			return nil, errors.New("cette dépendance créerait un cycle entre les tâches")
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		pending = append(pending, prerequisites[id]...)
+	}
+
+	d := &models.ProjectTaskDependency{TaskID: taskID, DependsOnID: dependsOnID, CreatedByID: createdByID}
+	if err := s.taskRepo.CreateDependency(d); err != nil {
+		return nil, errors.New("erreur lors de l'ajout de la dépendance")
+	}
+	s.refreshBlocked(t)
+	return s.taskRepo.FindByID(taskID)
+}
+
+// RemoveTaskDependency supprime un prérequis ; les assignés sont notifiés si la tâche est débloquée
+func (s *projectService) RemoveTaskDependency(projectID, taskID, dependsOnID uint) (*models.ProjectTask, error) {
+	t, err := s.findProjectTask(projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	var prerequisite *models.ProjectTask
+	for _, d := range t.Dependencies {
+		if d.DependsOnID == dependsOnID {
+			prerequisite = d.DependsOn
+		}
+	}
+	if prerequisite == nil {
+		return nil, utils.ErrTaskDependencyNotFound
+	}
+	if err := s.taskRepo.DeleteDependency(taskID, dependsOnID); err != nil {
+		return nil, errors.New("erreur lors de la suppression de la dépendance")
+	}
+	if s.refreshBlocked(t) {
+		s.notifyTaskUnblocked(t, prerequisite)
+	}
+	return s.taskRepo.FindByID(taskID)
+}
+
+// refreshBlocked recalcule l'indicateur de blocage d'une tâche ; retourne true si la tâche vient d'être débloquée
+func (s *projectService) refreshBlocked(t *models.ProjectTask) bool {
+	open, err := s.taskRepo.CountOpenPrerequisites(t.ID)
+	if err != nil {
+		log.Printf("[refreshBlocked] task=%d: %v", t.ID, err)
+		return false
+	}
+	blocked := open > 0
+	if blocked == t.IsBlocked {
+		return false
+	}
+	if err := s.taskRepo.SetBlocked(t.ID, blocked); err != nil {
+		log.Printf("[refreshBlocked] task=%d: %v", t.ID, err)
+		return false
+	}
+	wasBlocked := t.IsBlocked
+	t.IsBlocked = blocked
+	return wasBlocked && !blocked
+}
+
+// refreshDependents recalcule le blocage des tâches dépendantes après la clôture ou la réouverture d'une tâche,
+// et notifie les assignés des tâches débloquées
+func (s *projectService) refreshDependents(prerequisite *models.ProjectTask) {
+	dependents, err := s.taskRepo.FindDependents(prerequisite.ID)
+	if err != nil {
+		log.Printf("[refreshDependents] task=%d: %v", prerequisite.ID, err)
+		return
+	}
+	for i := range dependents {
+		if s.refreshBlocked(&dependents[i]) {
+			s.notifyTaskUnblocked(&dependents[i], prerequisite)
+		}
+	}
+}
+
+// notifyTaskUnblocked prévient les assignés d'une tâche que son dernier prérequis est levé
+func (s *projectService) notifyTaskUnblocked(t *models.ProjectTask, prerequisite *models.ProjectTask) {
+	if s.notificationService == nil || t.Status == "cloture" {
+		return
+	}
+	recipients := make(map[uint]bool)
+	for _, a := range t.Assignees {
+		recipients[a.UserID] = true
+	}
+	if t.AssignedToID != nil {
+		recipients[*t.AssignedToID] = true
+	}
+	linkURL := fmt.Sprintf("/app/projects/%d", t.ProjectID)
+	title := "Tâche débloquée"
+	message := fmt.Sprintf("La tâche %s - %s n'est plus bloquée : le prérequis %s - %s est levé.", t.Code, t.Title, prerequisite.Code, prerequisite.Title)
+	metadata := map[string]any{"project_id": t.ProjectID, "task_id": t.ID, "task_code": t.Code, "prerequisite_id": prerequisite.ID}
+	for userID := range recipients {
+		if err := s.notificationService.Create(userID, "project_task_unblocked", title, message, linkURL, metadata); err != nil {
+			log.Printf("Erreur notification tâche projet débloquée (user %d): %v", userID, err)
+		}
+	}
+}
+
+// --- Milestones ---
+
+// ProjectMilestoneDashboard synthèse des jalons à venir et en retard sur le périmètre projets de l'utilisateur
+type ProjectMilestoneDashboard struct {
+	Days          int                       `json:"days"` // Fenêtre des jalons à venir, en jours à partir d'aujourd'hui
+	UpcomingCount int                       `json:"upcoming_count"`
+	LateCount     int                       `json:"late_count"`
+	Upcoming      []models.ProjectMilestone `json:"upcoming"`
+	Late          []models.ProjectMilestone `json:"late"`
+}
+
+// milestoneStatuses statuts acceptés pour un jalon
+var milestoneStatuses = map[string]bool{
+	models.MilestoneStatusPlanned:   true,
+	models.MilestoneStatusAchieved:  true,
+	models.MilestoneStatusCancelled: true,
+}
+
+// calendarDaysBetween nombre de jours calendaires entre deux dates (indépendant des changements d'heure)
+func calendarDaysBetween(from, to time.Time) int {
+	fromUTC := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toUTC := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toUTC.Sub(fromUTC).Hours() / 24)
+}
+
+// decorateMilestones renseigne les champs calculés (retard, glissement) des jalons
+func decorateMilestones(list []models.ProjectMilestone, today time.Time) {
+	for i := range list {
+		m := &list[i]
+		m.IsLate = m.Status == models.MilestoneStatusPlanned && calendarDaysBetween(today, m.DueDate) < 0
+		m.SlipDays = calendarDaysBetween(m.BaselineDueDate, m.DueDate)
+	}
+}
+
+// findProjectMilestone récupère un jalon en vérifiant son appartenance au projet
+func (s *projectService) findProjectMilestone(projectID, milestoneID uint) (*models.ProjectMilestone, error) {
+	m, err := s.milestoneRepo.FindByID(milestoneID)
+	if err != nil || m.ProjectID != projectID {
+		return nil, utils.ErrMilestoneNotFound
+	}
+	return m, nil
+}
+
+// checkMilestonePhase vérifie que l'étape liée appartient au projet
+func (s *projectService) checkMilestonePhase(projectID uint, phaseID *uint) error {
+	if phaseID == nil {
+		return nil
+	}
+	phase, err := s.phaseRepo.FindByID(*phaseID)
+	if err != nil {
+		return utils.ErrPhaseNotFound
+	}
+	if phase.ProjectID != projectID {
+		return errors.New("l'étape n'appartient pas à ce projet")
+	}
+	return nil
+}
+
+func (s *projectService) GetMilestones(projectID uint) ([]models.ProjectMilestone, error) {
+	if _, err := s.projectRepo.FindByID(projectID); err != nil {
+		return nil, utils.ErrProjectNotFound
+	}
+	list, err := s.milestoneRepo.FindByProjectID(projectID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des jalons")
+	}
+	decorateMilestones(list, timezone.Today(timezone.Default()))
+	return list, nil
+}
+
+func (s *projectService) CreateMilestone(projectID, createdByID uint, name, description, completionCriteria, dueDate string, projectPhaseID *uint) (*models.ProjectMilestone, error) {
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("le nom du jalon est requis")
+	}
+	due, err := timezone.ParseDate(dueDate)
+	if err != nil {
+		return nil, errors.New("date d'échéance invalide (format YYYY-MM-DD)")
+	}
+	if projectPhaseID != nil && *projectPhaseID == 0 {
+		projectPhaseID = nil
+	}
+	if err := s.checkMilestonePhase(projectID, projectPhaseID); err != nil {
+		return nil, err
+	}
+	m := &models.ProjectMilestone{
+		ProjectID:          projectID,
+		ProjectPhaseID:     projectPhaseID,
+		Name:               name,
+		Description:        description,
+		CompletionCriteria: completionCriteria,
+		DueDate:            due,
+		BaselineDueDate:    due,
+		Status:             models.MilestoneStatusPlanned,
+		CreatedByID:        createdByID,
+	}
+	if err := s.milestoneRepo.Create(m); err != nil {
+		log.Printf("[CreateMilestone] project=%d: %v", projectID, err)
+		return nil, errors.New("erreur lors de la création du jalon")
+	}
+	return s.getMilestone(m.ID)
+}
+
+// UpdateMilestone met à jour un jalon ; project_phase_id à 0 retire le lien avec l'étape.
+// Le report de l'échéance d'un jalon planifié est notifié et réarme l'alerte de retard.
+func (s *projectService) UpdateMilestone(projectID, milestoneID, updatedByID uint, name, description, completionCriteria, dueDate, status *string, projectPhaseID *uint) (*models.ProjectMilestone, error) {
+	m, err := s.findProjectMilestone(projectID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	previousDue := m.DueDate
+	if name != nil {
+		trimmed := strings.TrimSpace(*name)
+		if trimmed == "" {
+			return nil, errors.New("le nom du jalon est requis")
+		}
+		m.Name = trimmed
+	}
+	if description != nil {
+		m.Description = *description
+	}
+	if completionCriteria != nil {
+		m.CompletionCriteria = *completionCriteria
+	}
+	if projectPhaseID != nil {
+		if *projectPhaseID == 0 {
+			m.ProjectPhaseID = nil
+		} else {
+			if err := s.checkMilestonePhase(projectID, projectPhaseID); err != nil {
+				return nil, err
+			}
+			m.ProjectPhaseID = projectPhaseID
+		}
+	}
+	if dueDate != nil {
+		due, err := timezone.ParseDate(*dueDate)
+		if err != nil {
+			return nil, errors.New("date d'échéance invalide (format YYYY-MM-DD)")
+		}
+		m.DueDate = due
+	}
+	if status != nil && *status != m.Status {
+		if !milestoneStatuses[*status] {
+			return nil, errors.New("statut de jalon invalide (planned, achieved, cancelled)")
+		}
+		m.Status = *status
+		if m.Status == models.MilestoneStatusAchieved {
+			now := time.Now()
+			m.AchievedAt = &now
+		} else {
+			m.AchievedAt = nil
+		}
+	}
+	slipped := m.Status == models.MilestoneStatusPlanned && calendarDaysBetween(previousDue, m.DueDate) > 0
+	if calendarDaysBetween(previousDue, m.DueDate) != 0 {
+		m.LateAlertedAt = nil
+	}
+	m.Project, m.ProjectPhase = nil, nil
+	if err := s.milestoneRepo.Update(m); err != nil {
+		log.Printf("[UpdateMilestone] milestone=%d: %v", m.ID, err)
+		return nil, errors.New("erreur lors de la mise à jour du jalon")
+	}
+	updated, err := s.getMilestone(m.ID)
+	if err != nil {
+		return nil, err
+	}
+	if slipped {
+		s.notifyMilestoneSlipped(updated, previousDue, updatedByID)
+	}
+	return updated, nil
+}
+
+func (s *projectService) DeleteMilestone(projectID, milestoneID uint) error {
+	if _, err := s.findProjectMilestone(projectID, milestoneID); err != nil {
+		return err
+	}
+	if err := s.CheckWritable(projectID); err != nil {
+		return err
+	}
+	if err := s.milestoneRepo.Delete(milestoneID); err != nil {
+		log.Printf("[DeleteMilestone] milestone=%d: %v", milestoneID, err)
+		return errors.New("erreur lors de la suppression du jalon")
+	}
+	return nil
+}
+
+// getMilestone relit un jalon avec ses relations et ses champs calculés
+func (s *projectService) getMilestone(id uint) (*models.ProjectMilestone, error) {
+	m, err := s.milestoneRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrMilestoneNotFound
+	}
+	list := []models.ProjectMilestone{*m}
+	decorateMilestones(list, timezone.Today(timezone.Default()))
+	return &list[0], nil
+}
+
+// GetMilestoneDashboard retourne les jalons planifiés à échéance dans les days prochains jours et ceux en retard
+func (s *projectService) GetMilestoneDashboard(scopeParam interface{}, days int) (*ProjectMilestoneDashboard, error) {
+	if days <= 0 {
+		days = 30
+	}
+	if days > 365 {
+		days = 365
+	}
+	today := timezone.Today(timezone.Default())
+	upcoming, err := s.milestoneRepo.FindUpcoming(scopeParam, today, today.AddDate(0, 0, days))
+	if err != nil {
+		log.Printf("[GetMilestoneDashboard] upcoming: %v", err)
+		return nil, errors.New("erreur lors de la récupération des jalons")
+	}
+	late, err := s.milestoneRepo.FindLate(scopeParam, today)
+	if err != nil {
+		log.Printf("[GetMilestoneDashboard] late: %v", err)
+		return nil, errors.New("erreur lors de la récupération des jalons")
+	}
+	decorateMilestones(upcoming, today)
+	decorateMilestones(late, today)
+	return &ProjectMilestoneDashboard{
+		Days:          days,
+		UpcomingCount: len(upcoming),
+		LateCount:     len(late),
+		Upcoming:      upcoming,
+		Late:          late,
+	}, nil
+}
+
+// SendMilestoneLateAlerts notifie le chef de projet, le lead et le créateur des jalons passés en retard
+func (s *projectService) SendMilestoneLateAlerts() (int, error) {
+	today := timezone.Today(timezone.Default())
+	list, err := s.milestoneRepo.FindLateToAlert(today)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range list {
+		m := &list[i]
+		if s.notificationService != nil {
+			linkURL := fmt.Sprintf("/app/projects/%d", m.ProjectID)
+			title := "Jalon en retard"
+			message := fmt.Sprintf("Le jalon « %s » du projet « %s » n'est pas atteint alors que son échéance était le %s.", m.Name, milestoneProjectName(m), m.DueDate.Format("02/01/2006"))
+			metadata := map[string]any{"project_id": m.ProjectID, "milestone_id": m.ID, "due_date": m.DueDate.Format(timezone.DateLayout)}
+			for _, userID := range milestoneRecipients(m, 0) {
+				if err := s.notificationService.Create(userID, "project_milestone_late", title, message, linkURL, metadata); err != nil {
+					log.Printf("Erreur notification jalon en retard (user %d): %v", userID, err)
+				}
+			}
+		}
+		if err := s.milestoneRepo.MarkLateAlerted(m.ID, time.Now()); err != nil {
+			log.Printf("[SendMilestoneLateAlerts] milestone=%d: %v", m.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// notifyMilestoneSlipped notifie le report de l'échéance d'un jalon (hors auteur de la modification)
+func (s *projectService) notifyMilestoneSlipped(m *models.ProjectMilestone, previousDue time.Time, actorID uint) {
+	if s.notificationService == nil {
+		return
+	}
+	linkURL := fmt.Sprintf("/app/projects/%d", m.ProjectID)
+	title := "Jalon reporté"
+	message := fmt.Sprintf("L'échéance du jalon « %s » du projet « %s » passe du %s au %s (%d jour(s) de glissement depuis l'échéance initiale).",
+		m.Name, milestoneProjectName(m), previousDue.Format("02/01/2006"), m.DueDate.Format("02/01/2006"), m.SlipDays)
+	metadata := map[string]any{
+		"project_id":        m.ProjectID,
+		"milestone_id":      m.ID,
+		"previous_due_date": previousDue.Format(timezone.DateLayout),
+		"due_date":          m.DueDate.Format(timezone.DateLayout),
+		"slip_days":         m.SlipDays,
+	}
+	for _, userID := range milestoneRecipients(m, actorID) {
+		if err := s.notificationService.Create(userID, "project_milestone_slipped", title, message, linkURL, metadata); err != nil {
+			log.Printf("Erreur notification jalon reporté (user %d): %v", userID, err)
+		}
+	}
+}
+
+// milestoneProjectName nom du projet d'un jalon (projet préchargé)
+func milestoneProjectName(m *models.ProjectMilestone) string {
+	if m.Project != nil {
+		return m.Project.Name
+	}
+	return fmt.Sprintf("#%d", m.ProjectID)
+}
+
+// milestoneRecipients destinataires des alertes d'un jalon : chef de projet, lead et créateur, sans doublon ni excludeID
+func milestoneRecipients(m *models.ProjectMilestone, excludeID uint) []uint {
+	candidates := []uint{m.CreatedByID}
+	if m.Project != nil {
+		if m.Project.ProjectManagerID != nil {
+			candidates = append(candidates, *m.Project.ProjectManagerID)
+		}
+		if m.Project.LeadID != nil {
+			candidates = append(candidates, *m.Project.LeadID)
+		}
+	}
+	seen := make(map[uint]bool)
+	var recipients []uint
+	for _, id := range candidates {
+		if id == 0 || id == excludeID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		recipients = append(recipients, id)
+	}
+	return recipients
+}
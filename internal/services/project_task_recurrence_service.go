@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/recurrence"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+)
+
+const (
+	taskRecurrenceDefaultLeadDays = 7
+	taskRecurrenceMaxLeadDays     = 60
+	// taskRecurrenceMaxPerRun nombre maximal d'occurrences traitées par règle et par passage
+	taskRecurrenceMaxPerRun = 20
+)
+
+// ProjectTaskRecurrenceService interface pour les tâches de projet récurrentes
+type ProjectTaskRecurrenceService interface {
+	GetByProject(projectID uint) ([]models.ProjectTaskRecurrence, error)
+	Create(projectID, taskID, createdByID uint, req dto.CreateProjectTaskRecurrenceRequest) (*models.ProjectTaskRecurrence, error) // La tâche sert de modèle aux occurrences
+	Update(projectID, recurrenceID uint, req dto.UpdateProjectTaskRecurrenceRequest) (*models.ProjectTaskRecurrence, error)
+	Delete(projectID, recurrenceID uint) error
+	// RunDue crée les occurrences à venir (LeadDays jours avant leur date) et clôture les occurrences expirées
+	// (projets archivés exclus)
+	RunDue(ctx context.Context) (int, error)
+	AllowArchivedWrites() ProjectTaskRecurrenceService // Copie du service autorisée à modifier les projets archivés
+}
+
+// projectTaskRecurrenceService implémente ProjectTaskRecurrenceService
+type projectTaskRecurrenceService struct {
+	recurrenceRepo repositories.ProjectTaskRecurrenceRepository
+	taskRepo       repositories.ProjectTaskRepository
+	phaseRepo      repositories.ProjectPhaseRepository
+	userRepo       repositories.UserRepository
+	projectService ProjectService
+}
+
+// NewProjectTaskRecurrenceService crée une nouvelle instance de ProjectTaskRecurrenceService
+func NewProjectTaskRecurrenceService(
+	recurrenceRepo repositories.ProjectTaskRecurrenceRepository,
+	taskRepo repositories.ProjectTaskRepository,
+	phaseRepo repositories.ProjectPhaseRepository,
+	userRepo repositories.UserRepository,
+	projectService ProjectService,
+) ProjectTaskRecurrenceService {
+	return &projectTaskRecurrenceService{
+		recurrenceRepo: recurrenceRepo,
+		taskRepo:       taskRepo,
+		phaseRepo:      phaseRepo,
+		userRepo:       userRepo,
+		projectService: projectService,
+	}
+}
+
+// GetByProject récupère les règles de récurrence d'un projet
+func (s *projectTaskRecurrenceService) GetByProject(projectID uint) ([]models.ProjectTaskRecurrence, error) {
+	if _, err := s.projectService.GetByID(projectID); err != nil {
+		return nil, err
+	}
+	recurrences, err := s.recurrenceRepo.FindByProjectID(projectID)
+	if err != nil {
+		log.Printf("[GetByProject] task recurrences: %v", err)
+		return nil, utils.NewInternalError("erreur lors de la récupération des récurrences")
+	}
+	return recurrences, nil
+}
+
+// Create crée une règle de récurrence à partir d'une tâche du projet ; la tâche est rattachée à la règle
+func (s *projectTaskRecurrenceService) Create(projectID, taskID, createdByID uint, req dto.CreateProjectTaskRecurrenceRequest) (*models.ProjectTaskRecurrence, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil || task.ProjectID != projectID {
+		return nil, utils.ErrProjectTaskNotFound
+	}
+	if err := s.projectService.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	if task.RecurrenceID != nil {
+		return nil, errors.New("cette tâche a déjà une règle de récurrence")
+	}
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	leadDays := taskRecurrenceDefaultLeadDays
+	if req.LeadDays != nil {
+		leadDays = *req.LeadDays
+	}
+	assigneeIDs := make([]uint, 0, len(task.Assignees))
+	for _, a := range task.Assignees {
+		assigneeIDs = append(assigneeIDs, a.UserID)
+	}
+
+	rule := &models.ProjectTaskRecurrence{
+		ProjectID:      projectID,
+		Schedule:       strings.TrimSpace(req.Schedule),
+		Timezone:       strings.TrimSpace(req.Timezone),
+		ProjectPhaseID: task.ProjectPhaseID,
+		Title:          task.Title,
+		Description:    task.Description,
+		Priority:       task.Priority,
+		EstimatedTime:  task.EstimatedTime,
+		LeadDays:       leadDays,
+		CloseExpired:   req.CloseExpired == nil || *req.CloseExpired,
+		IsActive:       true,
+		StartsAt:       startsAt.Truncate(time.Second),
+		CreatedByID:    createdByID,
+	}
+	if rule.AssigneeIDs, err = json.Marshal(assigneeIDs); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleNext(rule, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.recurrenceRepo.Create(rule); err != nil {
+		log.Printf("[Create] task recurrence (task %d): %v", taskID, err)
+		return nil, utils.NewInternalError("erreur lors de la création de la récurrence")
+	}
+	if err := s.recurrenceRepo.TagTask(taskID, rule.ID, nil, nil); err != nil {
+		log.Printf("[Create] task recurrence %d: tag task %d: %v", rule.ID, taskID, err)
+	}
+	return s.recurrenceRepo.FindByID(rule.ID)
+}
+
+// Update met à jour une règle ; la prochaine occurrence est recalculée après maintenant et après la dernière occurrence traitée
+func (s *projectTaskRecurrenceService) Update(projectID, recurrenceID uint, req dto.UpdateProjectTaskRecurrenceRequest) (*models.ProjectTaskRecurrence, error) {
+	rule, err := s.findRecurrence(projectID, recurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projectService.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	if req.Schedule != nil {
+		rule.Schedule = strings.TrimSpace(*req.Schedule)
+	}
+	if req.Timezone != nil {
+		rule.Timezone = strings.TrimSpace(*req.Timezone)
+	}
+	if req.StartsAt != nil {
+		rule.StartsAt = req.StartsAt.Truncate(time.Second)
+	}
+	if req.LeadDays != nil {
+		rule.LeadDays = *req.LeadDays
+	}
+	if req.CloseExpired != nil {
+		rule.CloseExpired = *req.CloseExpired
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if req.ProjectPhaseID != nil {
+		phase, err := s.phaseRepo.FindByID(*req.ProjectPhaseID)
+		if err != nil || phase.ProjectID != projectID {
+			return nil, errors.New("étape introuvable ou n'appartient pas au projet")
+		}
+		rule.ProjectPhaseID = phase.ID
+	}
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return nil, errors.New("le titre de la tâche est requis")
+		}
+		rule.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		rule.Description = *req.Description
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.EstimatedTime != nil {
+		rule.EstimatedTime = req.EstimatedTime
+	}
+	if req.AssigneeIDs != nil {
+		ids, _, _ := normalizeAssignees(*req.AssigneeIDs, nil)
+		if len(ids) > 0 {
+			count, err := s.userRepo.CountByIDs(ids)
+			if err != nil {
+				return nil, utils.NewInternalError("erreur lors de la vérification des utilisateurs")
+			}
+			if int(count) != len(ids) {
+				return nil, errors.New("un ou plusieurs assignés sont introuvables")
+			}
+		}
+		if rule.AssigneeIDs, err = json.Marshal(ids); err != nil {
+			return nil, err
+		}
+	}
+
+	after := time.Now()
+	if rule.LastOccurrenceAt != nil && rule.LastOccurrenceAt.After(after) {
+		after = *rule.LastOccurrenceAt // Occurrences déjà créées : pas de doublon
+	}
+	if err := s.scheduleNext(rule, after); err != nil {
+		return nil, err
+	}
+	if err := s.recurrenceRepo.Update(rule); err != nil {
+		log.Printf("[Update] task recurrence %d: %v", recurrenceID, err)
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la récurrence")
+	}
+	return s.recurrenceRepo.FindByID(recurrenceID)
+}
+
+// Delete supprime une règle ; les tâches déjà créées sont conservées
+func (s *projectTaskRecurrenceService) Delete(projectID, recurrenceID uint) error {
+	if _, err := s.findRecurrence(projectID, recurrenceID); err != nil {
+		return err
+	}
+	if err := s.projectService.CheckWritable(projectID); err != nil {
+		return err
+	}
+	if err := s.recurrenceRepo.Delete(recurrenceID); err != nil {
+		log.Printf("[Delete] task recurrence %d: %v", recurrenceID, err)
+		return utils.NewInternalError("erreur lors de la suppression de la récurrence")
+	}
+	return nil
+}
+
+// RunDue crée les occurrences dont la date est dans moins de LeadDays jours puis clôture les occurrences expirées.
+// Une occurrence déjà expirée lors de son traitement (serveur arrêté) n'est pas créée.
+func (s *projectTaskRecurrenceService) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	rules, err := s.recurrenceRepo.FindDue(now.AddDate(0, 0, taskRecurrenceMaxLeadDays))
+	if err != nil {
+		return 0, utils.NewInternalError("erreur lors de la récupération des récurrences à échéance")
+	}
+
+	created := 0
+	for i := range rules {
+		rule := &rules[i]
+		horizon := now.AddDate(0, 0, rule.LeadDays)
+		for n := 0; n < taskRecurrenceMaxPerRun && rule.NextOccurrenceAt != nil && !rule.NextOccurrenceAt.After(horizon); n++ {
+			if ctx.Err() != nil {
+				return created, ctx.Err()
+			}
+			occurrenceAt := *rule.NextOccurrenceAt
+			// Compte l'occurrence en cours pour borner COUNT
+			rule.OccurrenceCount++
+			if err := s.scheduleNext(rule, occurrenceAt); err != nil {
+				// Planification devenue invalide (ex: fuseau supprimé) : terminée en conservant l'erreur
+				log.Printf("Récurrence de tâche %d: %v", rule.ID, err)
+				rule.NextOccurrenceAt = nil
+			}
+			claimed, err := s.recurrenceRepo.Claim(rule.ID, occurrenceAt, rule.NextOccurrenceAt, rule.OccurrenceCount)
+			if err != nil {
+				log.Printf("Erreur lors de la réservation de la récurrence de tâche %d: %v", rule.ID, err)
+				break
+			}
+			if !claimed {
+				break // Déjà traitée par une autre exécution
+			}
+			if rule.NextOccurrenceAt != nil && !rule.NextOccurrenceAt.After(now) {
+				continue
+			}
+			runError := ""
+			if err := s.createOccurrence(rule, occurrenceAt); err != nil {
+				log.Printf("Récurrence de tâche %d: création de l'occurrence du %s impossible: %v", rule.ID, occurrenceAt.Format(time.RFC3339), err)
+				runError = err.Error()
+			} else {
+				created++
+			}
+			if runError != rule.LastError {
+				if err := s.recurrenceRepo.RecordError(rule.ID, runError); err != nil {
+					log.Printf("Erreur lors de l'enregistrement du passage de la récurrence de tâche %d: %v", rule.ID, err)
+				}
+				rule.LastError = runError
+			}
+		}
+	}
+
+	closed := s.closeExpired(ctx, now)
+	if created > 0 || closed > 0 {
+		log.Printf("Tâches récurrentes: %d occurrence(s) créée(s), %d occurrence(s) expirée(s) clôturée(s)", created, closed)
+	}
+	return created, nil
+}
+
+// AllowArchivedWrites retourne une copie du service autorisée à modifier les règles des projets archivés
+func (s *projectTaskRecurrenceService) AllowArchivedWrites() ProjectTaskRecurrenceService {
+	writable := *s
+	writable.projectService = s.projectService.AllowArchivedWrites()
+	return &writable
+}
+
+// createOccurrence crée la tâche d'une occurrence au nom du créateur de la règle, échue à la date de l'occurrence
+// et expirant à l'occurrence suivante
+func (s *projectTaskRecurrenceService) createOccurrence(rule *models.ProjectTaskRecurrence, occurrenceAt time.Time) error {
+	dueDate := occurrenceAt.In(timezone.Resolve(rule.Timezone)).Format(timezone.DateLayout)
+	task, err := s.projectService.CreateTask(rule.ProjectID, rule.ProjectPhaseID, rule.CreatedByID, rule.Title, rule.Description,
+		"", rule.Priority, decodeUintList(rule.AssigneeIDs), rule.EstimatedTime, &dueDate)
+	if err != nil {
+		return err
+	}
+	return s.recurrenceRepo.TagTask(task.ID, rule.ID, &occurrenceAt, rule.NextOccurrenceAt)
+}
+
+// closeExpired clôture les occurrences non terminées dont l'occurrence suivante est arrivée
+func (s *projectTaskRecurrenceService) closeExpired(ctx context.Context, now time.Time) int {
+	tasks, err := s.recurrenceRepo.FindExpiredOccurrences(now)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des occurrences expirées: %v", err)
+		return 0
+	}
+	closed := 0
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := s.projectService.UpdateTask(t.ID, "", "", "cloture", "", nil, nil, nil, nil, nil); err != nil {
+			log.Printf("Récurrence de tâche %d: clôture de l'occurrence %s impossible: %v", *t.RecurrenceID, t.Code, err)
+			continue
+		}
+		closed++
+	}
+	return closed
+}
+
+// findRecurrence récupère une règle du projet
+func (s *projectTaskRecurrenceService) findRecurrence(projectID, recurrenceID uint) (*models.ProjectTaskRecurrence, error) {
+	rule, err := s.recurrenceRepo.FindByID(recurrenceID)
+	if err != nil || rule.ProjectID != projectID {
+		return nil, utils.ErrTaskRecurrenceNotFound
+	}
+	return rule, nil
+}
+
+// scheduleNext calcule la prochaine occurrence après l'instant donné (nil si inactive ou récurrence terminée)
+func (s *projectTaskRecurrenceService) scheduleNext(rule *models.ProjectTaskRecurrence, after time.Time) error {
+	if !timezone.Valid(rule.Timezone) {
+		return errors.New("fuseau horaire invalide")
+	}
+	schedule, err := recurrence.Parse(rule.Schedule, timezone.Resolve(rule.Timezone), rule.StartsAt)
+	if err != nil {
+		return err
+	}
+	rule.NextOccurrenceAt = nil
+	if !rule.IsActive {
+		return nil
+	}
+	if count := schedule.Count(); count > 0 && rule.OccurrenceCount >= count {
+		return nil
+	}
+	if start := rule.StartsAt.Add(-time.Second); after.Before(start) {
+		after = start
+	}
+	if next := schedule.Next(after); !next.IsZero() {
+		rule.NextOccurrenceAt = &next
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ProjectTaskTimerService interface pour la saisie du temps des tâches de projet par chronomètre
+type ProjectTaskTimerService interface {
+	// Start démarre un chronomètre sur une tâche ; un seul chronomètre en cours par utilisateur
+	Start(projectID, taskID, userID uint, req dto.StartProjectTaskTimerRequest) (*dto.ProjectTaskTimerDTO, error)
+	// Stop arrête le chronomètre de la tâche et crée l'entrée de temps correspondante
+	Stop(projectID, taskID, userID uint, req dto.StopProjectTaskTimerRequest) (*dto.StoppedProjectTaskTimerDTO, error)
+	// GetCurrent retourne le chronomètre en cours de l'utilisateur (nil s'il n'y en a pas)
+	GetCurrent(userID uint) (*dto.ProjectTaskTimerDTO, error)
+	// Discard abandonne le chronomètre en cours sans saisir de temps (sans effet s'il n'y en a pas)
+	Discard(userID uint) error
+	AllowArchivedWrites() ProjectTaskTimerService // Copie du service autorisée à modifier les projets archivés
+}
+
+// projectTaskTimerService implémente ProjectTaskTimerService
+type projectTaskTimerService struct {
+	timerRepo       repositories.ProjectTaskTimerRepository
+	taskRepo        repositories.ProjectTaskRepository
+	projectService  ProjectService
+	declarationRepo repositories.WeeklyDeclarationRepository
+}
+
+// NewProjectTaskTimerService crée une nouvelle instance de ProjectTaskTimerService
+func NewProjectTaskTimerService(
+	timerRepo repositories.ProjectTaskTimerRepository,
+	taskRepo repositories.ProjectTaskRepository,
+	projectService ProjectService,
+	declarationRepo repositories.WeeklyDeclarationRepository,
+) ProjectTaskTimerService {
+	return &projectTaskTimerService{
+		timerRepo:       timerRepo,
+		taskRepo:        taskRepo,
+		projectService:  projectService,
+		declarationRepo: declarationRepo,
+	}
+}
+
+// Start démarre un chronomètre sur une tâche non clôturée du projet
+func (s *projectTaskTimerService) Start(projectID, taskID, userID uint, req dto.StartProjectTaskTimerRequest) (*dto.ProjectTaskTimerDTO, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil || task.ProjectID != projectID {
+		return nil, utils.ErrProjectTaskNotFound
+	}
+	if err := s.projectService.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	if task.Status == "cloture" {
+		return nil, errors.New("impossible de démarrer un chronomètre sur une tâche clôturée")
+	}
+	if err := checkWeekUnlocked(s.declarationRepo, userID, timezone.Date(time.Now(), timezone.Default())); err != nil {
+		return nil, err
+	}
+
+	running, err := s.timerRepo.FindByUserID(userID)
+	if err != nil {
+		log.Printf("[StartTaskTimer] user=%d: %v", userID, err)
+		return nil, utils.NewInternalError("erreur lors du démarrage du chronomètre")
+	}
+	if running != nil {
+		return nil, utils.ErrTaskTimerRunning
+	}
+
+	timer := &models.ProjectTaskTimer{
+		UserID:        userID,
+		ProjectID:     projectID,
+		ProjectTaskID: taskID,
+		StartedAt:     time.Now(),
+		Description:   strings.TrimSpace(req.Description),
+	}
+	if err := s.timerRepo.Create(timer); err != nil {
+		// Démarrage concurrent : l'index unique sur user_id garantit un seul chronomètre
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return nil, utils.ErrTaskTimerRunning
+		}
+		log.Printf("[StartTaskTimer] user=%d task=%d: %v", userID, taskID, err)
+		return nil, utils.NewInternalError("erreur lors du démarrage du chronomètre")
+	}
+	timer.ProjectTask = task
+	return projectTaskTimerToDTO(timer, time.Now()), nil
+}
+
+// Stop arrête le chronomètre en cours sur la tâche : le temps écoulé, arrondi à la minute (au moins 1), est saisi à la date
+// de démarrage et ajouté au temps passé de la tâche et au temps consommé du projet
+func (s *projectTaskTimerService) Stop(projectID, taskID, userID uint, req dto.StopProjectTaskTimerRequest) (*dto.StoppedProjectTaskTimerDTO, error) {
+	timer, err := s.timerRepo.FindByUserID(userID)
+	if err != nil {
+		log.Printf("[StopTaskTimer] user=%d: %v", userID, err)
+		return nil, utils.NewInternalError("erreur lors de l'arrêt du chronomètre")
+	}
+	if timer == nil || timer.ProjectID != projectID || timer.ProjectTaskID != taskID {
+		return nil, utils.ErrTaskTimerNotRunning
+	}
+	if err := s.projectService.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	stoppedAt := time.Now()
+	minutes := int(math.Round(stoppedAt.Sub(timer.StartedAt).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	description := timer.Description
+	if req.Description != nil {
+		description = strings.TrimSpace(*req.Description)
+	}
+	date := timezone.Date(timer.StartedAt, timezone.Default())
+	if err := checkWeekUnlocked(s.declarationRepo, userID, date); err != nil {
+		return nil, err
+	}
+	projectTaskID := timer.ProjectTaskID
+	entry := &models.TimeEntry{
+		ProjectTaskID: &projectTaskID,
+		UserID:        userID,
+		TimeSpent:     minutes,
+		Date:          date,
+		Description:   description,
+		WorkCategory:  models.WorkCategoryProject,
+		Billable:      models.DefaultBillable(models.WorkCategoryProject),
+	}
+	stopped, err := s.timerRepo.Stop(timer, entry)
+	if err != nil {
+		log.Printf("[StopTaskTimer] timer=%d: %v", timer.ID, err)
+		return nil, utils.NewInternalError("erreur lors de l'arrêt du chronomètre")
+	}
+	if !stopped {
+		// Arrêté entre-temps par une autre requête
+		return nil, utils.ErrTaskTimerNotRunning
+	}
+
+	result := &dto.StoppedProjectTaskTimerDTO{
+		TimeEntryID:   entry.ID,
+		ProjectID:     timer.ProjectID,
+		ProjectTaskID: timer.ProjectTaskID,
+		StartedAt:     timer.StartedAt,
+		StoppedAt:     stoppedAt,
+		TimeSpent:     entry.TimeSpent,
+		Date:          entry.Date,
+		Description:   entry.Description,
+	}
+	if task, err := s.taskRepo.FindByID(timer.ProjectTaskID); err == nil {
+		result.TaskActualTime = task.ActualTime
+	}
+	return result, nil
+}
+
+// GetCurrent retourne le chronomètre en cours de l'utilisateur
+func (s *projectTaskTimerService) GetCurrent(userID uint) (*dto.ProjectTaskTimerDTO, error) {
+	timer, err := s.timerRepo.FindByUserID(userID)
+	if err != nil {
+		log.Printf("[GetCurrentTaskTimer] user=%d: %v", userID, err)
+		return nil, utils.NewInternalError("erreur lors de la récupération du chronomètre")
+	}
+	if timer == nil {
+		return nil, nil
+	}
+	return projectTaskTimerToDTO(timer, time.Now()), nil
+}
+
+// Discard abandonne le chronomètre en cours (tâche supprimée, projet archivé, oubli...)
+func (s *projectTaskTimerService) Discard(userID uint) error {
+	timer, err := s.timerRepo.FindByUserID(userID)
+	if err != nil {
+		log.Printf("[DiscardTaskTimer] user=%d: %v", userID, err)
+		return utils.NewInternalError("erreur lors de l'abandon du chronomètre")
+	}
+	if timer == nil {
+		return nil
+	}
+	if _, err := s.timerRepo.Delete(timer.ID); err != nil {
+		log.Printf("[DiscardTaskTimer] timer=%d: %v", timer.ID, err)
+		return utils.NewInternalError("erreur lors de l'abandon du chronomètre")
+	}
+	return nil
+}
+
+// AllowArchivedWrites retourne une copie du service autorisée à saisir du temps sur les projets archivés
+func (s *projectTaskTimerService) AllowArchivedWrites() ProjectTaskTimerService {
+	writable := *s
+	writable.projectService = s.projectService.AllowArchivedWrites()
+	return &writable
+}
+
+// projectTaskTimerToDTO convertit un chronomètre en DTO, avec le temps écoulé à l'instant now
+func projectTaskTimerToDTO(timer *models.ProjectTaskTimer, now time.Time) *dto.ProjectTaskTimerDTO {
+	timerDTO := &dto.ProjectTaskTimerDTO{
+		ID:             timer.ID,
+		ProjectID:      timer.ProjectID,
+		ProjectTaskID:  timer.ProjectTaskID,
+		StartedAt:      timer.StartedAt,
+		ElapsedSeconds: int64(now.Sub(timer.StartedAt).Seconds()),
+		Description:    timer.Description,
+	}
+	if timer.Project != nil {
+		timerDTO.ProjectName = timer.Project.Name
+	}
+	if timer.ProjectTask != nil {
+		timerDTO.TaskCode = timer.ProjectTask.Code
+		timerDTO.TaskTitle = timer.ProjectTask.Title
+	}
+	return timerDTO
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+)
+
+// ProjectTemplateService interface pour les modèles de projet
+type ProjectTemplateService interface {
+	GetAll(activeOnly bool) ([]models.ProjectTemplate, error)
+	GetByID(id uint) (*models.ProjectTemplate, error)
+	Create(req dto.SaveProjectTemplateRequest, createdByID uint) (*models.ProjectTemplate, error)
+	Update(id uint, req dto.SaveProjectTemplateRequest) (*models.ProjectTemplate, error) // Remplace le contenu du modèle
+	Delete(id uint) error
+	// Instantiate crée un projet depuis un modèle actif : étapes datées depuis la date de début, fonctions,
+	// membres par défaut (chef de projet, lead) et tâches assignées aux membres de leur fonction
+	Instantiate(templateID, createdByID uint, req dto.CreateProjectFromTemplateRequest) (*models.Project, error)
+}
+
+// projectTemplateService implémente ProjectTemplateService
+type projectTemplateService struct {
+	templateRepo   repositories.ProjectTemplateRepository
+	phaseRepo      repositories.ProjectPhaseRepository
+	userRepo       repositories.UserRepository
+	projectService ProjectService
+}
+
+// NewProjectTemplateService crée une nouvelle instance de ProjectTemplateService
+func NewProjectTemplateService(
+	templateRepo repositories.ProjectTemplateRepository,
+	phaseRepo repositories.ProjectPhaseRepository,
+	userRepo repositories.UserRepository,
+	projectService ProjectService,
+) ProjectTemplateService {
+	return &projectTemplateService{
+		templateRepo:   templateRepo,
+		phaseRepo:      phaseRepo,
+		userRepo:       userRepo,
+		projectService: projectService,
+	}
+}
+
+// GetAll récupère les modèles de projet
+func (s *projectTemplateService) GetAll(activeOnly bool) ([]models.ProjectTemplate, error) {
+	templates, err := s.templateRepo.FindAll(activeOnly)
+	if err != nil {
+		log.Printf("[GetAll] project templates: %v", err)
+		return nil, utils.NewInternalError("erreur lors de la récupération des modèles de projet")
+	}
+	return templates, nil
+}
+
+// GetByID récupère un modèle de projet
+func (s *projectTemplateService) GetByID(id uint) (*models.ProjectTemplate, error) {
+	template, err := s.templateRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProjectTemplateNotFound
+	}
+	return template, nil
+}
+
+// Create crée un modèle de projet
+func (s *projectTemplateService) Create(req dto.SaveProjectTemplateRequest, createdByID uint) (*models.ProjectTemplate, error) {
+	template := &models.ProjectTemplate{IsActive: true, CreatedByID: createdByID}
+	if err := s.apply(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Create(template); err != nil {
+		log.Printf("[Create] project template: %v", err)
+		return nil, utils.NewInternalError("erreur lors de la création du modèle de projet")
+	}
+	return s.GetByID(template.ID)
+}
+
+// Update met à jour un modèle de projet et remplace son contenu
+func (s *projectTemplateService) Update(id uint, req dto.SaveProjectTemplateRequest) (*models.ProjectTemplate, error) {
+	template, err := s.templateRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrProjectTemplateNotFound
+	}
+	if err := s.apply(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Update(template); err != nil {
+		log.Printf("[Update] project template %d: %v", id, err)
+		return nil, utils.NewInternalError("erreur lors de la mise à jour du modèle de projet")
+	}
+	return s.GetByID(id)
+}
+
+// Delete supprime un modèle de projet
+func (s *projectTemplateService) Delete(id uint) error {
+	if _, err := s.templateRepo.FindByID(id); err != nil {
+		return utils.ErrProjectTemplateNotFound
+	}
+	if err := s.templateRepo.Delete(id); err != nil {
+		log.Printf("[Delete] project template %d: %v", id, err)
+		return utils.NewInternalError("erreur lors de la suppression du modèle de projet")
+	}
+	return nil
+}
+
+// apply valide la requête et la reporte sur le modèle (le contenu fourni remplace le contenu existant)
+func (s *projectTemplateService) apply(template *models.ProjectTemplate, req dto.SaveProjectTemplateRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("le nom du modèle est requis")
+	}
+	if existing, err := s.templateRepo.FindByName(name); err == nil && existing.ID != template.ID {
+		return utils.ErrProjectTemplateConflict
+	}
+	validIndex := func(index *int, count int) bool {
+		return index == nil || (*index >= 0 && *index < count)
+	}
+
+	template.Name = name
+	template.Description = req.Description
+	template.DurationDays = req.DurationDays
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+
+	template.Phases = make([]models.ProjectTemplatePhase, len(req.Phases))
+	for i, p := range req.Phases {
+		template.Phases[i] = models.ProjectTemplatePhase{
+			Name:            p.Name,
+			Description:     p.Description,
+			StartOffsetDays: p.StartOffsetDays,
+			DurationDays:    p.DurationDays,
+		}
+	}
+
+	template.Functions = make([]models.ProjectTemplateFunction, len(req.Functions))
+	for i, f := range req.Functions {
+		functionType := f.Type
+		if functionType == "" {
+			functionType = "execution"
+		}
+		template.Functions[i] = models.ProjectTemplateFunction{Name: strings.TrimSpace(f.Name), Type: functionType}
+	}
+
+	template.Members = make([]models.ProjectTemplateMember, len(req.Members))
+	var managers, leads int
+	for i, m := range req.Members {
+		if !validIndex(m.FunctionIndex, len(req.Functions)) {
+			return fmt.Errorf("membre %d : fonction inexistante dans le modèle", i+1)
+		}
+		if m.UserID != nil {
+			if _, err := s.userRepo.FindByID(*m.UserID); err != nil {
+				return fmt.Errorf("membre %d : utilisateur inexistant", i+1)
+			}
+		}
+		if m.IsProjectManager {
+			managers++
+		}
+		if m.IsLead {
+			leads++
+		}
+		template.Members[i] = models.ProjectTemplateMember{
+			UserID:           m.UserID,
+			FunctionIndex:    m.FunctionIndex,
+			IsProjectManager: m.IsProjectManager,
+			IsLead:           m.IsLead,
+		}
+	}
+	if managers > 1 || leads > 1 {
+		return errors.New("un modèle ne peut désigner qu'un chef de projet et qu'un lead")
+	}
+
+	template.Tasks = make([]models.ProjectTemplateTask, len(req.Tasks))
+	for i, t := range req.Tasks {
+		if t.PhaseIndex < 0 || t.PhaseIndex >= len(req.Phases) {
+			return fmt.Errorf("tâche %d : étape inexistante dans le modèle", i+1)
+		}
+		if !validIndex(t.FunctionIndex, len(req.Functions)) {
+			return fmt.Errorf("tâche %d : fonction inexistante dans le modèle", i+1)
+		}
+		priority := t.Priority
+		if priority == "" {
+			priority = "medium"
+		}
+		template.Tasks[i] = models.ProjectTemplateTask{
+			PhaseIndex:    t.PhaseIndex,
+			FunctionIndex: t.FunctionIndex,
+			Title:         t.Title,
+			Description:   t.Description,
+			Priority:      priority,
+			EstimatedTime: t.EstimatedTime,
+			DueOffsetDays: t.DueOffsetDays,
+		}
+	}
+	return nil
+}
+
+// Instantiate crée un projet depuis un modèle. En cas d'échec après la création du projet, le projet est supprimé.
+func (s *projectTemplateService) Instantiate(templateID, createdByID uint, req dto.CreateProjectFromTemplateRequest) (*models.Project, error) {
+	template, err := s.templateRepo.FindByID(templateID)
+	if err != nil {
+		return nil, utils.ErrProjectTemplateNotFound
+	}
+	if !template.IsActive {
+		return nil, errors.New("ce modèle de projet est désactivé")
+	}
+
+	start := timezone.Today(timezone.Default())
+	if req.StartDate != nil && *req.StartDate != "" {
+		if start, err = timezone.ParseDate(*req.StartDate); err != nil {
+			return nil, errors.New("start_date invalide (attendu: AAAA-MM-JJ)")
+		}
+	}
+	startDate := start.Format(timezone.DateLayout)
+	var endDate *string
+	if template.DurationDays != nil {
+		end := start.AddDate(0, 0, *template.DurationDays-1).Format(timezone.DateLayout)
+		endDate = &end
+	}
+	description := req.Description
+	if description == "" {
+		description = template.Description
+	}
+
+	project, err := s.projectService.Create(req.Name, description, req.TotalBudgetTime, &startDate, endDate, createdByID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.populate(project.ID, createdByID, template, start); err != nil {
+		log.Printf("[Instantiate] template %d, project %d: %v", templateID, project.ID, err)
+		if delErr := s.projectService.Delete(project.ID); delErr != nil {
+			log.Printf("[Instantiate] rollback project %d: %v", project.ID, delErr)
+		}
+		return nil, utils.NewInternalError("erreur lors de la création du projet depuis le modèle")
+	}
+	return s.projectService.GetByID(project.ID)
+}
+
+// populate crée les fonctions, étapes, membres et tâches du modèle dans le projet
+func (s *projectTemplateService) populate(projectID, createdByID uint, template *models.ProjectTemplate, start time.Time) error {
+	// Fonctions : reprise de la fonction du projet (Chef de projet, Lead) ou du catalogue de même nom, sinon création
+	existing, err := s.projectService.GetFunctions(projectID)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]uint, len(existing))
+	for _, f := range existing {
+		key := strings.ToLower(f.Name)
+		if _, ok := byName[key]; !ok || (f.ProjectID != nil && *f.ProjectID == projectID) {
+			byName[key] = f.ID
+		}
+	}
+	functionIDs := make(map[uint]uint, len(template.Functions)) // fonction du modèle → fonction du projet
+	for _, f := range template.Functions {
+		if id, ok := byName[strings.ToLower(f.Name)]; ok {
+			functionIDs[f.ID] = id
+			continue
+		}
+		created, err := s.projectService.CreateFunction(projectID, f.Name, f.Type, len(existing)+f.DisplayOrder)
+		if err != nil {
+			return err
+		}
+		functionIDs[f.ID] = created.ID
+		byName[strings.ToLower(f.Name)] = created.ID
+	}
+
+	// Étapes datées relativement au début du projet
+	phaseIDs := make(map[uint]uint, len(template.Phases))
+	for _, p := range template.Phases {
+		phase, err := s.projectService.CreatePhase(projectID, p.Name, p.Description, p.DisplayOrder, "")
+		if err != nil {
+			return err
+		}
+		phaseStart := start.AddDate(0, 0, p.StartOffsetDays)
+		phase.StartDate = &phaseStart
+		if p.DurationDays != nil {
+			phaseEnd := phaseStart.AddDate(0, 0, *p.DurationDays-1)
+			phase.EndDate = &phaseEnd
+		}
+		if err := s.phaseRepo.Update(phase); err != nil {
+			return err
+		}
+		phaseIDs[p.ID] = phase.ID
+	}
+
+	// Membres : un utilisateur cité plusieurs fois cumule ses fonctions ; un utilisateur supprimé depuis est ignoré
+	var userOrder []uint
+	userFunctions := make(map[uint][]uint)
+	holders := make(map[uint][]uint) // fonction du modèle → utilisateurs
+	var managerID, leadID uint
+	for _, m := range template.Members {
+		userID := createdByID
+		if m.UserID != nil {
+			userID = *m.UserID
+		}
+		if _, err := s.userRepo.FindByID(userID); err != nil {
+			log.Printf("[Instantiate] project %d: member %d ignored (user not found)", projectID, userID)
+			continue
+		}
+		if _, ok := userFunctions[userID]; !ok {
+			userOrder = append(userOrder, userID)
+			userFunctions[userID] = []uint{}
+		}
+		if m.FunctionID != nil {
+			if fid := functionIDs[*m.FunctionID]; !slices.Contains(userFunctions[userID], fid) {
+				userFunctions[userID] = append(userFunctions[userID], fid)
+			}
+			if !slices.Contains(holders[*m.FunctionID], userID) {
+				holders[*m.FunctionID] = append(holders[*m.FunctionID], userID)
+			}
+		}
+		if m.IsProjectManager {
+			managerID = userID
+		}
+		if m.IsLead {
+			leadID = userID
+		}
+	}
+	for _, userID := range userOrder {
+		if _, err := s.projectService.AddMember(projectID, userID, userFunctions[userID]); err != nil {
+			return err
+		}
+	}
+	if managerID != 0 {
+		if err := s.projectService.SetProjectManager(projectID, managerID); err != nil {
+			return err
+		}
+	}
+	if leadID != 0 {
+		if err := s.projectService.SetLead(projectID, leadID); err != nil {
+			return err
+		}
+	}
+
+	// Tâches : échéance relative au début du projet, assignées aux membres ayant la fonction de la tâche
+	for _, t := range template.Tasks {
+		var assigneeIDs []uint
+		if t.FunctionID != nil {
+			assigneeIDs = holders[*t.FunctionID]
+		}
+		var dueDate *string
+		if t.DueOffsetDays != nil {
+			due := start.AddDate(0, 0, *t.DueOffsetDays).Format(timezone.DateLayout)
+			dueDate = &due
+		}
+		if _, err := s.projectService.CreateTask(projectID, phaseIDs[t.PhaseID], createdByID, t.Title, t.Description, "", t.Priority, assigneeIDs, t.EstimatedTime, dueDate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/push"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+)
+
+// pushMessageMaxLength longueur maximale du texte d'une notification push (la charge utile est limitée à 4 Ko)
+const pushMessageMaxLength = 500
+
+// pushSender fournisseur de notifications push (FCM ou APNs)
+type pushSender interface {
+	Send(ctx context.Context, token string, message push.Message) error
+}
+
+// PushService interface pour les notifications push mobiles : enregistrement des appareils et envoi
+type PushService interface {
+	NotificationChannel
+	Enabled() bool
+	GetDevices(userID uint, sessionTokenHash string) ([]dto.PushDeviceDTO, error)
+	RegisterDevice(userID uint, sessionTokenHash string, req dto.RegisterPushDeviceRequest) (*dto.PushDeviceDTO, error)
+	DeleteDevice(id uint, userID uint) error
+	Send(ctx context.Context, payload jobs.PushSendPayload) error
+}
+
+// pushService implémente PushService
+type pushService struct {
+	senders     map[string]pushSender // Fournisseurs configurés, par nom (fcm, apns)
+	pushRepo    repositories.PushRepository
+	sessionRepo repositories.UserSessionRepository
+	jobQueue    *jobs.Queue
+}
+
+// NewPushService crée une nouvelle instance de PushService ; un fournisseur dont les identifiants sont illisibles reste désactivé
+func NewPushService(cfg config.PushConfig, pushRepo repositories.PushRepository, sessionRepo repositories.UserSessionRepository, jobQueue *jobs.Queue) PushService {
+	senders := map[string]pushSender{}
+	if cfg.FCMEnabled() {
+		if client, err := push.NewFCM(cfg.FCMCredentialsFile, cfg.FCMProjectID); err != nil {
+			log.Printf("⚠️  Notifications push FCM désactivées: %v", err)
+		} else {
+			senders[models.PushProviderFCM] = client
+		}
+	}
+	if cfg.APNsEnabled() {
+		if client, err := push.NewAPNs(cfg.APNsKeyFile, cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsBundleID, cfg.APNsSandbox); err != nil {
+			log.Printf("⚠️  Notifications push APNs désactivées: %v", err)
+		} else {
+			senders[models.PushProviderAPNs] = client
+		}
+	}
+	return &pushService{
+		senders:     senders,
+		pushRepo:    pushRepo,
+		sessionRepo: sessionRepo,
+		jobQueue:    jobQueue,
+	}
+}
+
+// Enabled indique si au moins un fournisseur est configuré
+func (s *pushService) Enabled() bool {
+	return len(s.senders) > 0
+}
+
+// GetDevices récupère les appareils de l'utilisateur ; celui de la session courante est signalé
+func (s *pushService) GetDevices(userID uint, sessionTokenHash string) ([]dto.PushDeviceDTO, error) {
+	devices, err := s.pushRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des appareils")
+	}
+	sessionID := s.sessionID(userID, sessionTokenHash)
+	deviceDTOs := make([]dto.PushDeviceDTO, len(devices))
+	for i := range devices {
+		deviceDTOs[i] = pushDeviceToDTO(&devices[i], sessionID)
+	}
+	return deviceDTOs, nil
+}
+
+// RegisterDevice enregistre le jeton d'un appareil pour l'utilisateur et la session courante
+// Un jeton déjà connu (renouvellement, changement d'utilisateur sur l'appareil) est rattaché à l'utilisateur et à la session
+func (s *pushService) RegisterDevice(userID uint, sessionTokenHash string, req dto.RegisterPushDeviceRequest) (*dto.PushDeviceDTO, error) {
+	if _, ok := s.senders[req.Provider]; !ok {
+		return nil, errors.New("fournisseur de notifications push non configuré")
+	}
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		return nil, errors.New("jeton d'appareil requis")
+	}
+
+	device, err := s.pushRepo.FindByToken(token)
+	if err != nil {
+		device = &models.PushDevice{Token: token}
+	}
+	device.UserID = userID
+	device.SessionID = s.sessionID(userID, sessionTokenHash)
+	device.Provider = req.Provider
+	device.Platform = req.Platform
+	if device.Platform == "" {
+		device.Platform = "android"
+		if req.Provider == models.PushProviderAPNs {
+			device.Platform = "ios"
+		}
+	}
+	device.DeviceName = strings.TrimSpace(req.DeviceName)
+	device.AppVersion = strings.TrimSpace(req.AppVersion)
+	device.LastSeenAt = time.Now()
+	if err := s.pushRepo.Save(device); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement de l'appareil")
+	}
+	deviceDTO := pushDeviceToDTO(device, device.SessionID)
+	return &deviceDTO, nil
+}
+
+// DeleteDevice supprime un appareil de l'utilisateur : il ne reçoit plus de notifications push
+func (s *pushService) DeleteDevice(id uint, userID uint) error {
+	device, err := s.pushRepo.FindByID(id)
+	if err != nil || device.UserID != userID {
+		return errors.New("appareil introuvable")
+	}
+	if err := s.pushRepo.Delete(id); err != nil {
+		return errors.New("erreur lors de la suppression de l'appareil")
+	}
+	return nil
+}
+
+// Deliver planifie l'envoi de la notification sur chaque appareil du destinataire (une tâche par appareil)
+func (s *pushService) Deliver(notification *models.Notification) {
+	if !s.Enabled() {
+		return
+	}
+	devices, err := s.pushRepo.FindByUserID(notification.UserID)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des appareils push (user %d): %v", notification.UserID, err)
+		return
+	}
+	for _, device := range devices {
+		if _, ok := s.senders[device.Provider]; !ok {
+			continue
+		}
+		payload := jobs.PushSendPayload{
+			DeviceID:       device.ID,
+			NotificationID: notification.ID,
+			Type:           notification.Type,
+			Title:          notification.Title,
+			Message:        notification.Message,
+			LinkURL:        notification.LinkURL,
+		}
+		if err := s.jobQueue.Enqueue(context.Background(), jobs.TypePushSend, payload); err != nil {
+			log.Printf("Erreur lors de la planification de la notification push (appareil %d): %v", device.ID, err)
+		}
+	}
+}
+
+// Send envoie une notification à un appareil ; un jeton refusé par le fournisseur est supprimé
+func (s *pushService) Send(ctx context.Context, payload jobs.PushSendPayload) error {
+	device, err := s.pushRepo.FindByID(payload.DeviceID)
+	if err != nil {
+		return nil // Appareil supprimé entre-temps (déconnexion, désinscription)
+	}
+	sender, ok := s.senders[device.Provider]
+	if !ok {
+		return nil
+	}
+
+	message := push.Message{
+		Title: truncateRunes(payload.Title, pushMessageMaxLength),
+		Body:  truncateRunes(payload.Message, pushMessageMaxLength),
+		Data: map[string]string{
+			"notification_id": strconv.FormatUint(uint64(payload.NotificationID), 10),
+			"type":            payload.Type,
+		},
+	}
+	if payload.LinkURL != "" {
+		message.Data["link_url"] = payload.LinkURL
+	}
+	err = sender.Send(ctx, device.Token, message)
+	if errors.Is(err, push.ErrInvalidToken) {
+		log.Printf("Notification push: jeton invalide, appareil %d supprimé (user %d)", device.ID, device.UserID)
+		return s.pushRepo.Delete(device.ID)
+	}
+	return err
+}
+
+// sessionID retourne l'ID de la session de l'utilisateur correspondant au hash du jeton (nil si introuvable)
+func (s *pushService) sessionID(userID uint, sessionTokenHash string) *uint {
+	if sessionTokenHash == "" {
+		return nil
+	}
+	session, err := s.sessionRepo.FindByTokenHash(sessionTokenHash)
+	if err != nil || session.UserID != userID {
+		return nil
+	}
+	return &session.ID
+}
+
+// pushDeviceToDTO convertit un appareil en DTO ; sessionID désigne la session courante
+func pushDeviceToDTO(device *models.PushDevice, sessionID *uint) dto.PushDeviceDTO {
+	return dto.PushDeviceDTO{
+		ID:         device.ID,
+		Provider:   device.Provider,
+		Platform:   device.Platform,
+		DeviceName: device.DeviceName,
+		AppVersion: device.AppVersion,
+		Current:    sessionID != nil && device.SessionID != nil && *device.SessionID == *sessionID,
+		LastSeenAt: device.LastSeenAt,
+		CreatedAt:  device.CreatedAt,
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// RecordShareService interface pour la gestion des partages de tickets et de projets
+type RecordShareService interface {
+	GetShares(resourceType string, resourceID uint, queryScope *scope.QueryScope) ([]dto.RecordShareDTO, error)
+	Share(resourceType string, resourceID uint, req dto.CreateRecordShareRequest, queryScope *scope.QueryScope, sharedByID uint) (*dto.RecordShareDTO, error)
+	UpdateShare(resourceType string, resourceID, shareID uint, req dto.UpdateRecordShareRequest, queryScope *scope.QueryScope) (*dto.RecordShareDTO, error)
+	RevokeShare(resourceType string, resourceID, shareID uint, queryScope *scope.QueryScope) error
+	GetSharedWithMe(userID uint, departmentID *uint) ([]dto.RecordShareDTO, error)
+}
+
+// recordShareService implémente RecordShareService
+type recordShareService struct {
+	shareRepo      repositories.RecordShareRepository
+	userRepo       repositories.UserRepository
+	departmentRepo repositories.DepartmentRepository
+}
+
+// NewRecordShareService crée une nouvelle instance de RecordShareService
+func NewRecordShareService(shareRepo repositories.RecordShareRepository, userRepo repositories.UserRepository, departmentRepo repositories.DepartmentRepository) RecordShareService {
+	return &recordShareService{
+		shareRepo:      shareRepo,
+		userRepo:       userRepo,
+		departmentRepo: departmentRepo,
+	}
+}
+
+// GetShares récupère les partages d'une ressource visible par l'utilisateur
+func (s *recordShareService) GetShares(resourceType string, resourceID uint, queryScope *scope.QueryScope) ([]dto.RecordShareDTO, error) {
+	if err := s.ensureManageable(resourceType, resourceID, queryScope); err != nil {
+		return nil, err
+	}
+	shares, err := s.shareRepo.FindByResource(resourceType, resourceID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des partages")
+	}
+	shareDTOs := make([]dto.RecordShareDTO, len(shares))
+	for i := range shares {
+		shareDTOs[i] = s.shareToDTO(&shares[i])
+	}
+	return shareDTOs, nil
+}
+
+// Share partage une ressource avec un utilisateur ou un département
+// Un partage existant pour le même bénéficiaire est mis à jour
+func (s *recordShareService) Share(resourceType string, resourceID uint, req dto.CreateRecordShareRequest, queryScope *scope.QueryScope, sharedByID uint) (*dto.RecordShareDTO, error) {
+	if (req.UserID == nil) == (req.DepartmentID == nil) {
+		return nil, errors.New("indiquez soit un utilisateur, soit un département")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("la date d'expiration doit être dans le futur")
+	}
+	if err := s.ensureManageable(resourceType, resourceID, queryScope); err != nil {
+		return nil, err
+	}
+	if req.UserID != nil {
+		if *req.UserID == sharedByID {
+			return nil, errors.New("impossible de partager avec soi-même")
+		}
+		if _, err := s.userRepo.FindByID(*req.UserID); err != nil {
+			return nil, utils.ErrUserNotFound
+		}
+	}
+	if req.DepartmentID != nil {
+		if _, err := s.departmentRepo.FindByID(*req.DepartmentID); err != nil {
+			return nil, utils.ErrDepartmentNotFound
+		}
+	}
+
+	share, err := s.shareRepo.FindForRecipient(resourceType, resourceID, req.UserID, req.DepartmentID)
+	if err == nil {
+		share.AccessLevel = req.AccessLevel
+		share.ExpiresAt = req.ExpiresAt
+		if err := s.shareRepo.Update(share); err != nil {
+			return nil, errors.New("erreur lors de la mise à jour du partage")
+		}
+	} else {
+		share = &models.RecordShare{
+			ResourceType:           resourceType,
+			ResourceID:             resourceID,
+			SharedWithUserID:       req.UserID,
+			SharedWithDepartmentID: req.DepartmentID,
+			AccessLevel:            req.AccessLevel,
+			ExpiresAt:              req.ExpiresAt,
+			SharedByID:             sharedByID,
+		}
+		if err := s.shareRepo.Create(share); err != nil {
+			return nil, errors.New("erreur lors de la création du partage")
+		}
+	}
+
+	created, err := s.shareRepo.FindByID(share.ID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération du partage")
+	}
+	shareDTO := s.shareToDTO(created)
+	return &shareDTO, nil
+}
+
+// UpdateShare modifie le niveau d'accès ou l'expiration d'un partage
+func (s *recordShareService) UpdateShare(resourceType string, resourceID, shareID uint, req dto.UpdateRecordShareRequest, queryScope *scope.QueryScope) (*dto.RecordShareDTO, error) {
+	share, err := s.findShare(resourceType, resourceID, shareID, queryScope)
+	if err != nil {
+		return nil, err
+	}
+	if req.AccessLevel != "" {
+		share.AccessLevel = req.AccessLevel
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, errors.New("la date d'expiration doit être dans le futur")
+		}
+		share.ExpiresAt = req.ExpiresAt
+	}
+	if req.RemoveExpiry {
+		share.ExpiresAt = nil
+	}
+	if err := s.shareRepo.Update(share); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour du partage")
+	}
+
+	updated, err := s.shareRepo.FindByID(share.ID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération du partage")
+	}
+	shareDTO := s.shareToDTO(updated)
+	return &shareDTO, nil
+}
+
+// RevokeShare supprime un partage
+func (s *recordShareService) RevokeShare(resourceType string, resourceID, shareID uint, queryScope *scope.QueryScope) error {
+	share, err := s.findShare(resourceType, resourceID, shareID, queryScope)
+	if err != nil {
+		return err
+	}
+	if err := s.shareRepo.Delete(share.ID); err != nil {
+		return errors.New("erreur lors de la suppression du partage")
+	}
+	return nil
+}
+
+// GetSharedWithMe récupère les partages actifs dont bénéficie l'utilisateur
+func (s *recordShareService) GetSharedWithMe(userID uint, departmentID *uint) ([]dto.RecordShareDTO, error) {
+	shares, err := s.shareRepo.FindActiveForUserWithDetails(userID, departmentID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des partages")
+	}
+	shareDTOs := make([]dto.RecordShareDTO, len(shares))
+	for i := range shares {
+		shareDTOs[i] = s.shareToDTO(&shares[i])
+	}
+	return shareDTOs, nil
+}
+
+// ensureManageable vérifie le type de ressource et que l'utilisateur la voit via ses permissions (pas via un partage)
+func (s *recordShareService) ensureManageable(resourceType string, resourceID uint, queryScope *scope.QueryScope) error {
+	if resourceType != models.ShareResourceTicket && resourceType != models.ShareResourceProject {
+		return errors.New("type de ressource non partageable")
+	}
+	if queryScope == nil {
+		return errors.New("ressource introuvable")
+	}
+	visible, err := s.shareRepo.IsVisible(resourceType, resourceID, queryScope.WithoutShares())
+	if err != nil {
+		return errors.New("erreur lors de la vérification des droits d'accès")
+	}
+	if !visible {
+		return errors.New("ressource introuvable")
+	}
+	return nil
+}
+
+// findShare récupère un partage appartenant à la ressource donnée
+func (s *recordShareService) findShare(resourceType string, resourceID, shareID uint, queryScope *scope.QueryScope) (*models.RecordShare, error) {
+	if err := s.ensureManageable(resourceType, resourceID, queryScope); err != nil {
+		return nil, err
+	}
+	share, err := s.shareRepo.FindByID(shareID)
+	if err != nil || share.ResourceType != resourceType || share.ResourceID != resourceID {
+		return nil, errors.New("partage introuvable")
+	}
+	return share, nil
+}
+
+// shareToDTO convertit un modèle RecordShare en DTO
+func (s *recordShareService) shareToDTO(share *models.RecordShare) dto.RecordShareDTO {
+	shareDTO := dto.RecordShareDTO{
+		ID:                     share.ID,
+		ResourceType:           share.ResourceType,
+		ResourceID:             share.ResourceID,
+		SharedWithUserID:       share.SharedWithUserID,
+		SharedWithDepartmentID: share.SharedWithDepartmentID,
+		AccessLevel:            share.AccessLevel,
+		ExpiresAt:              share.ExpiresAt,
+		IsExpired:              share.ExpiresAt != nil && !share.ExpiresAt.After(time.Now()),
+		SharedByID:             share.SharedByID,
+		CreatedAt:              share.CreatedAt,
+		UpdatedAt:              share.UpdatedAt,
+	}
+	if share.SharedWithUser != nil {
+		shareDTO.SharedWithUser = shareUserToDTO(share.SharedWithUser)
+	}
+	if share.SharedWithDepartment != nil {
+		shareDTO.SharedWithDepartment = &dto.DepartmentDTO{
+			ID:        share.SharedWithDepartment.ID,
+			Name:      share.SharedWithDepartment.Name,
+			Code:      share.SharedWithDepartment.Code,
+			FilialeID: share.SharedWithDepartment.FilialeID,
+			IsActive:  share.SharedWithDepartment.IsActive,
+		}
+	}
+	if share.SharedBy.ID != 0 {
+		shareDTO.SharedBy = shareUserToDTO(&share.SharedBy)
+	}
+	return shareDTO
+}
+
+// shareUserToDTO convertit l'utilisateur d'un partage en DTO (informations essentielles)
+func shareUserToDTO(user *models.User) *dto.UserDTO {
+	return &dto.UserDTO{
+		ID:           user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		DepartmentID: user.DepartmentID,
+		Avatar:       user.Avatar,
+		IsActive:     user.IsActive,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+	}
+}
@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/recurrence"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// RecurringTicketService interface pour les planifications de création automatique de tickets
+type RecurringTicketService interface {
+	GetAll(filialeID *uint) ([]dto.RecurringTicketDTO, error)
+	GetByID(id uint) (*dto.RecurringTicketDTO, error)
+	Create(req dto.CreateRecurringTicketRequest, createdByID uint) (*dto.RecurringTicketDTO, error)
+	Update(id uint, req dto.UpdateRecurringTicketRequest) (*dto.RecurringTicketDTO, error)
+	Delete(id uint) error
+	RunNow(id uint) (*dto.TicketDTO, error)  // Crée immédiatement un ticket, sans décaler la prochaine échéance
+	RunDue(ctx context.Context) (int, error) // Crée les tickets des planifications arrivées à échéance (exécuté périodiquement)
+}
+
+// recurringTicketService implémente RecurringTicketService
+type recurringTicketService struct {
+	recurringRepo      repositories.RecurringTicketRepository
+	userRepo           repositories.UserRepository
+	filialeRepo        repositories.FilialeRepository
+	projectRepo        repositories.ProjectRepository
+	ticketCategoryRepo repositories.TicketCategoryRepository
+	ticketService      TicketService
+}
+
+// NewRecurringTicketService crée une nouvelle instance de RecurringTicketService
+func NewRecurringTicketService(
+	recurringRepo repositories.RecurringTicketRepository,
+	userRepo repositories.UserRepository,
+	filialeRepo repositories.FilialeRepository,
+	projectRepo repositories.ProjectRepository,
+	ticketCategoryRepo repositories.TicketCategoryRepository,
+	ticketService TicketService,
+) RecurringTicketService {
+	return &recurringTicketService{
+		recurringRepo:      recurringRepo,
+		userRepo:           userRepo,
+		filialeRepo:        filialeRepo,
+		projectRepo:        projectRepo,
+		ticketCategoryRepo: ticketCategoryRepo,
+		ticketService:      ticketService,
+	}
+}
+
+// GetAll récupère les planifications (toutes, ou celles d'une filiale)
+func (s *recurringTicketService) GetAll(filialeID *uint) ([]dto.RecurringTicketDTO, error) {
+	recurrings, err := s.recurringRepo.FindAll(filialeID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des tickets récurrents")
+	}
+	recurringDTOs := make([]dto.RecurringTicketDTO, 0, len(recurrings))
+	for i := range recurrings {
+		recurringDTOs = append(recurringDTOs, recurringTicketToDTO(&recurrings[i]))
+	}
+	return recurringDTOs, nil
+}
+
+// GetByID récupère une planification par son ID
+func (s *recurringTicketService) GetByID(id uint) (*dto.RecurringTicketDTO, error) {
+	recurring, err := s.recurringRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrRecurringTicketNotFound
+	}
+	recurringDTO := recurringTicketToDTO(recurring)
+	return &recurringDTO, nil
+}
+
+// Create crée une planification et calcule sa première échéance
+func (s *recurringTicketService) Create(req dto.CreateRecurringTicketRequest, createdByID uint) (*dto.RecurringTicketDTO, error) {
+	if req.FilialeID != nil {
+		if _, err := s.filialeRepo.FindByID(*req.FilialeID); err != nil {
+			return nil, utils.ErrFilialeNotFound
+		}
+	}
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	priority := req.Priority
+	if priority == "" {
+		priority = "medium"
+	}
+
+	recurring := &models.RecurringTicket{
+		Name:                strings.TrimSpace(req.Name),
+		Schedule:            strings.TrimSpace(req.Schedule),
+		Timezone:            strings.TrimSpace(req.Timezone),
+		Title:               strings.TrimSpace(req.Title),
+		Description:         strings.TrimSpace(req.Description),
+		Category:            req.Category,
+		Priority:            priority,
+		EstimatedTime:       req.EstimatedTime,
+		RequesterID:         optionalID(req.RequesterID),
+		RequesterName:       strings.TrimSpace(req.RequesterName),
+		RequesterDepartment: strings.TrimSpace(req.RequesterDepartment),
+		FilialeID:           req.FilialeID,
+		SoftwareID:          optionalID(req.SoftwareID),
+		ProjectID:           optionalID(req.ProjectID),
+		IsActive:            req.IsActive == nil || *req.IsActive,
+		StartsAt:            startsAt.Truncate(time.Second),
+		CreatedByID:         createdByID,
+	}
+	if err := s.applyAssignees(recurring, req.AssigneeIDs, req.LeadID); err != nil {
+		return nil, err
+	}
+	if err := s.validate(recurring); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleNext(recurring, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.recurringRepo.Create(recurring); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création du ticket récurrent")
+	}
+	if !recurring.IsActive {
+		// La valeur par défaut de la colonne s'applique à false à la création
+		if err := s.recurringRepo.Update(recurring); err != nil {
+			log.Printf("Erreur lors de la désactivation du ticket récurrent %d: %v", recurring.ID, err)
+		}
+	}
+	return s.GetByID(recurring.ID)
+}
+
+// Update met à jour une planification ; la prochaine échéance est recalculée à partir de maintenant
+func (s *recurringTicketService) Update(id uint, req dto.UpdateRecurringTicketRequest) (*dto.RecurringTicketDTO, error) {
+	recurring, err := s.recurringRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrRecurringTicketNotFound
+	}
+
+	if req.Name != nil {
+		recurring.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Schedule != nil {
+		recurring.Schedule = strings.TrimSpace(*req.Schedule)
+	}
+	if req.Timezone != nil {
+		recurring.Timezone = strings.TrimSpace(*req.Timezone)
+	}
+	if req.StartsAt != nil {
+		recurring.StartsAt = req.StartsAt.Truncate(time.Second)
+	}
+	if req.Title != nil {
+		recurring.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		recurring.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Category != nil {
+		recurring.Category = *req.Category
+	}
+	if req.Priority != nil {
+		recurring.Priority = *req.Priority
+	}
+	if req.EstimatedTime != nil {
+		recurring.EstimatedTime = req.EstimatedTime
+	}
+	if req.RequesterID != nil {
+		recurring.RequesterID = optionalID(req.RequesterID)
+	}
+	if req.RequesterName != nil {
+		recurring.RequesterName = strings.TrimSpace(*req.RequesterName)
+	}
+	if req.RequesterDepartment != nil {
+		recurring.RequesterDepartment = strings.TrimSpace(*req.RequesterDepartment)
+	}
+	if req.SoftwareID != nil {
+		recurring.SoftwareID = optionalID(req.SoftwareID)
+	}
+	if req.ProjectID != nil {
+		recurring.ProjectID = optionalID(req.ProjectID)
+	}
+	if req.IsActive != nil {
+		recurring.IsActive = *req.IsActive
+	}
+	if req.AssigneeIDs != nil || req.LeadID != nil {
+		assigneeIDs := decodeUintList(recurring.AssigneeIDs)
+		if req.AssigneeIDs != nil {
+			assigneeIDs = *req.AssigneeIDs
+		}
+		leadID := recurring.LeadID
+		if req.LeadID != nil {
+			leadID = optionalID(req.LeadID)
+		}
+		if err := s.applyAssignees(recurring, assigneeIDs, leadID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validate(recurring); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleNext(recurring, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.recurringRepo.Update(recurring); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour du ticket récurrent")
+	}
+	return s.GetByID(id)
+}
+
+// Delete supprime une planification (les tickets déjà créés sont conservés)
+func (s *recurringTicketService) Delete(id uint) error {
+	if _, err := s.recurringRepo.FindByID(id); err != nil {
+		return utils.ErrRecurringTicketNotFound
+	}
+	if err := s.recurringRepo.Delete(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression du ticket récurrent")
+	}
+	return nil
+}
+
+// RunNow crée immédiatement un ticket à partir de la planification (ex: pour vérifier le modèle)
+func (s *recurringTicketService) RunNow(id uint) (*dto.TicketDTO, error) {
+	recurring, err := s.recurringRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrRecurringTicketNotFound
+	}
+	return s.createTicket(recurring, time.Now())
+}
+
+// RunDue crée un ticket pour chaque planification arrivée à échéance
+// Les échéances manquées (serveur arrêté) ne sont pas rattrapées : un seul ticket est créé et l'échéance suivante part de maintenant
+func (s *recurringTicketService) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	recurrings, err := s.recurringRepo.FindDue(now)
+	if err != nil {
+		return 0, utils.NewInternalError("erreur lors de la récupération des tickets récurrents à échéance")
+	}
+
+	created := 0
+	for i := range recurrings {
+		if ctx.Err() != nil {
+			return created, ctx.Err()
+		}
+		recurring := &recurrings[i]
+		dueAt := *recurring.NextRunAt
+		// Compte l'occurrence en cours pour borner COUNT
+		recurring.RunCount++
+		if err := s.scheduleNext(recurring, now); err != nil {
+			// Planification devenue invalide (ex: fuseau supprimé) : désactivée en conservant l'erreur
+			log.Printf("Ticket récurrent %d: %v", recurring.ID, err)
+			recurring.NextRunAt = nil
+		}
+		claimed, err := s.recurringRepo.Claim(recurring.ID, dueAt, recurring.NextRunAt)
+		if err != nil {
+			log.Printf("Erreur lors de la réservation du ticket récurrent %d: %v", recurring.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // Déjà traité par une autre exécution
+		}
+		if _, err := s.createTicket(recurring, now); err != nil {
+			log.Printf("Ticket récurrent %d: création du ticket impossible: %v", recurring.ID, err)
+			continue
+		}
+		created++
+	}
+	return created, nil
+}
+
+// createTicket crée le ticket du modèle au nom du créateur de la planification, le rattache au projet et enregistre l'exécution
+func (s *recurringTicketService) createTicket(recurring *models.RecurringTicket, runAt time.Time) (*dto.TicketDTO, error) {
+	ticket, err := s.ticketService.Create(dto.CreateTicketRequest{
+		Title:               recurring.Title,
+		Description:         recurring.Description,
+		Category:            recurring.Category,
+		Source:              "kronos",
+		Priority:            recurring.Priority,
+		EstimatedTime:       recurring.EstimatedTime,
+		RequesterID:         recurring.RequesterID,
+		RequesterName:       recurring.RequesterName,
+		RequesterDepartment: recurring.RequesterDepartment,
+		FilialeID:           recurring.FilialeID,
+		SoftwareID:          recurring.SoftwareID,
+		AssigneeIDs:         decodeUintList(recurring.AssigneeIDs),
+		LeadID:              recurring.LeadID,
+	}, recurring.CreatedByID)
+	if err != nil {
+		if recordErr := s.recurringRepo.RecordRun(recurring.ID, runAt, nil, err.Error()); recordErr != nil {
+			log.Printf("Erreur lors de l'enregistrement de l'exécution du ticket récurrent %d: %v", recurring.ID, recordErr)
+		}
+		return nil, err
+	}
+
+	runError := ""
+	if recurring.ProjectID != nil {
+		if err := s.recurringRepo.LinkProject(ticket.ID, *recurring.ProjectID); err != nil {
+			log.Printf("Ticket récurrent %d: rattachement du ticket %d au projet %d impossible: %v", recurring.ID, ticket.ID, *recurring.ProjectID, err)
+			runError = "rattachement au projet impossible"
+		}
+	}
+	if err := s.recurringRepo.RecordRun(recurring.ID, runAt, &ticket.ID, runError); err != nil {
+		log.Printf("Erreur lors de l'enregistrement de l'exécution du ticket récurrent %d: %v", recurring.ID, err)
+	}
+	return ticket, nil
+}
+
+// scheduleNext calcule la prochaine échéance après l'instant donné (nil si inactive ou récurrence terminée)
+func (s *recurringTicketService) scheduleNext(recurring *models.RecurringTicket, after time.Time) error {
+	if !timezone.Valid(recurring.Timezone) {
+		return errors.New("fuseau horaire invalide")
+	}
+	schedule, err := recurrence.Parse(recurring.Schedule, timezone.Resolve(recurring.Timezone), recurring.StartsAt)
+	if err != nil {
+		return err
+	}
+	recurring.NextRunAt = nil
+	if !recurring.IsActive {
+		return nil
+	}
+	if count := schedule.Count(); count > 0 && recurring.RunCount >= count {
+		return nil
+	}
+	if start := recurring.StartsAt.Add(-time.Second); after.Before(start) {
+		after = start
+	}
+	if next := schedule.Next(after); !next.IsZero() {
+		recurring.NextRunAt = &next
+	}
+	return nil
+}
+
+// applyAssignees valide et enregistre les assignés et le responsable des tickets créés
+func (s *recurringTicketService) applyAssignees(recurring *models.RecurringTicket, assigneeIDs []uint, leadID *uint) error {
+	ids, lead, err := normalizeAssignees(assigneeIDs, leadID)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		count, err := s.userRepo.CountByIDs(ids)
+		if err != nil {
+			return utils.NewInternalError("erreur lors de la vérification des utilisateurs")
+		}
+		if int(count) != len(ids) {
+			return errors.New("un ou plusieurs assignés sont introuvables")
+		}
+	}
+	if recurring.AssigneeIDs, err = json.Marshal(ids); err != nil {
+		return err
+	}
+	recurring.LeadID = lead
+	return nil
+}
+
+// validate vérifie le modèle de ticket (champs obligatoires, catégorie, demandeur, projet)
+func (s *recurringTicketService) validate(recurring *models.RecurringTicket) error {
+	if recurring.Name == "" {
+		return errors.New("le nom du ticket récurrent est obligatoire")
+	}
+	if recurring.Title == "" {
+		return errors.New("le titre des tickets est obligatoire")
+	}
+	if recurring.RequesterDepartment == "" {
+		return errors.New("le département du demandeur est obligatoire")
+	}
+	if _, err := s.ticketCategoryRepo.FindBySlug(recurring.Category); err != nil {
+		return utils.ErrCategoryNotFound
+	}
+	if recurring.RequesterID != nil {
+		if _, err := s.userRepo.FindByID(*recurring.RequesterID); err != nil {
+			return utils.ErrUserNotFound
+		}
+	}
+	if recurring.ProjectID != nil {
+		if _, err := s.projectRepo.FindByID(*recurring.ProjectID); err != nil {
+			return utils.ErrProjectNotFound
+		}
+	}
+	return nil
+}
+
+// optionalID convertit un ID optionnel où 0 signifie « aucun »
+func optionalID(id *uint) *uint {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return id
+}
+
+// recurringTicketToDTO convertit un modèle RecurringTicket en DTO
+func recurringTicketToDTO(recurring *models.RecurringTicket) dto.RecurringTicketDTO {
+	recurringDTO := dto.RecurringTicketDTO{
+		ID:                  recurring.ID,
+		Name:                recurring.Name,
+		Schedule:            recurring.Schedule,
+		Timezone:            recurring.Timezone,
+		Title:               recurring.Title,
+		Description:         recurring.Description,
+		Category:            recurring.Category,
+		Priority:            recurring.Priority,
+		EstimatedTime:       recurring.EstimatedTime,
+		RequesterID:         recurring.RequesterID,
+		RequesterName:       recurring.RequesterName,
+		RequesterDepartment: recurring.RequesterDepartment,
+		FilialeID:           recurring.FilialeID,
+		SoftwareID:          recurring.SoftwareID,
+		AssigneeIDs:         decodeUintList(recurring.AssigneeIDs),
+		LeadID:              recurring.LeadID,
+		ProjectID:           recurring.ProjectID,
+		IsActive:            recurring.IsActive,
+		StartsAt:            recurring.StartsAt,
+		NextRunAt:           recurring.NextRunAt,
+		LastRunAt:           recurring.LastRunAt,
+		LastTicketID:        recurring.LastTicketID,
+		RunCount:            recurring.RunCount,
+		LastError:           recurring.LastError,
+		CreatedByID:         recurring.CreatedByID,
+		CreatedAt:           recurring.CreatedAt,
+		UpdatedAt:           recurring.UpdatedAt,
+	}
+	if recurring.Filiale != nil && recurring.Filiale.ID != 0 {
+		recurringDTO.Filiale = &dto.FilialeDTO{ID: recurring.Filiale.ID, Code: recurring.Filiale.Code, Name: recurring.Filiale.Name}
+	}
+	if recurring.Project != nil {
+		recurringDTO.ProjectName = recurring.Project.Name
+	}
+	return recurringDTO
+}
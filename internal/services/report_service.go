@@ -12,6 +12,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
 	"github.com/mcicare/itsm-backend/internal/scope"
+	"gorm.io/gorm"
 )
 
 // ReportService interface pour les opérations sur les rapports
@@ -26,6 +27,7 @@ type ReportService interface {
 	GetIndividualPerformanceReport(userID uint, period string) (*dto.IndividualPerformanceReportDTO, error)
 	GetAssetSummary(scope interface{}, period string) (*dto.AssetReportDTO, error)
 	GetKnowledgeSummary(scope interface{}, period string) (*dto.KnowledgeReportDTO, error)
+	GetSatisfactionReport(scope interface{}, period string) (*dto.SatisfactionReportDTO, error)
 	ExportReport(reportType, format, period string) (any, error)
 	GenerateCustomReport(req dto.CustomReportRequest) (any, error)
 }
@@ -75,10 +77,17 @@ func periodStart(period string, now time.Time) time.Time {
 	}
 }
 
+// reportNow retourne l'instant courant dans le fuseau de l'utilisateur, pour que les périodes
+// (jour, semaine, mois...) commencent à minuit heure de l'utilisateur
+func reportNow(scopeParam interface{}) time.Time {
+	queryScope, _ := scopeParam.(*scope.QueryScope)
+	return time.Now().In(queryScope.Loc())
+}
+
 // GetDashboard récupère le tableau de bord
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *reportService) GetDashboard(scopeParam interface{}, period string) (*dto.DashboardDTO, error) {
-	now := time.Now()
+	now := reportNow(scopeParam)
 	start := periodStart(period, now)
 
 	// Tableau de bord département demandé mais l'utilisateur n'a pas de département associé
@@ -238,7 +247,7 @@ func (s *reportService) GetDashboard(scopeParam interface{}, period string) (*dt
 // GetTicketCountReport récupère le rapport de nombre de tickets
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *reportService) GetTicketCountReport(scopeParam interface{}, period string) (*dto.TicketCountReportDTO, error) {
-	now := time.Now()
+	now := reportNow(scopeParam)
 	start := periodStart(period, now)
 	// S'assurer que la date de début est au début de la journée (00:00:00)
 	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
@@ -507,7 +516,7 @@ type workloadAcc struct {
 // GetWorkloadByAgent récupère la charge de travail par agent (tickets normaux + tickets internes)
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *reportService) GetWorkloadByAgent(scopeParam interface{}, period string) ([]dto.WorkloadByAgentDTO, error) {
-	now := time.Now()
+	now := reportNow(scopeParam)
 	start := periodStart(period, now)
 
 	type workloadRow struct {
@@ -689,7 +698,7 @@ func (s *reportService) GetWorkloadByAgent(scopeParam interface{}, period string
 // GetSLAComplianceReport récupère le rapport de conformité SLA
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *reportService) GetSLAComplianceReport(scopeParam interface{}, period string) (*dto.SLAComplianceReportDTO, error) {
-	now := time.Now()
+	now := reportNow(scopeParam)
 	start := periodStart(period, now)
 
 	type statusRow struct {
@@ -786,7 +795,7 @@ func (s *reportService) GetSLAComplianceReport(scopeParam interface{}, period st
 // GetDelayedTicketsReport récupère le rapport des tickets en retard
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *reportService) GetDelayedTicketsReport(scopeParam interface{}, period string) ([]dto.DelayedTicketDTO, error) {
-	now := time.Now()
+	now := reportNow(scopeParam)
 	start := periodStart(period, now)
 
 	// Construire la requête de base
@@ -834,7 +843,7 @@ func (s *reportService) GetIndividualPerformanceReport(userID uint, period strin
 
 // GetAssetSummary récupère un résumé des actifs (filtré par scope si fourni)
 func (s *reportService) GetAssetSummary(scopeParam interface{}, period string) (*dto.AssetReportDTO, error) {
-	now := time.Now()
+	now := reportNow(scopeParam)
 	start := periodStart(period, now)
 
 	baseQuery := database.DB.Model(&models.Asset{}).Where("created_at >= ?", start)
@@ -902,7 +911,7 @@ func (s *reportService) GetAssetSummary(scopeParam interface{}, period string) (
 
 // GetKnowledgeSummary récupère un résumé de la base de connaissances (filtré par scope si fourni)
 func (s *reportService) GetKnowledgeSummary(scopeParam interface{}, period string) (*dto.KnowledgeReportDTO, error) {
-	now := time.Now()
+	now := reportNow(scopeParam)
 	start := periodStart(period, now)
 
 	baseQuery := database.DB.Model(&models.KnowledgeArticle{}).Where("created_at >= ?", start)
@@ -970,6 +979,130 @@ func (s *reportService) GetKnowledgeSummary(scopeParam interface{}, period strin
 	}, nil
 }
 
+// GetSatisfactionReport récupère la satisfaction des demandeurs (CSAT) sur les enquêtes envoyées pendant la période,
+// globalement, par agent (responsable du ticket à la clôture) et par filiale
+// Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
+func (s *reportService) GetSatisfactionReport(scopeParam interface{}, period string) (*dto.SatisfactionReportDTO, error) {
+	now := reportNow(scopeParam)
+	start := periodStart(period, now)
+
+	baseQuery := func() *gorm.DB {
+		query := database.DB.Table("ticket_satisfactions").
+			Joins("INNER JOIN tickets ON tickets.id = ticket_satisfactions.ticket_id AND tickets.deleted_at IS NULL").
+			Where("ticket_satisfactions.created_at >= ?", start)
+		if scopeParam != nil {
+			if queryScope, ok := scopeParam.(*scope.QueryScope); ok {
+				query = scope.ApplyTicketScopeToTable(query, queryScope)
+			}
+		}
+		return query
+	}
+	// Réponses, somme des notes et réponses satisfaites (note 4 ou 5)
+	const satisfactionColumns = "COUNT(ticket_satisfactions.answered_at) as responses, " +
+		"COALESCE(SUM(ticket_satisfactions.rating), 0) as rating_sum, " +
+		"SUM(CASE WHEN ticket_satisfactions.rating >= 4 THEN 1 ELSE 0 END) as satisfied"
+	type satisfactionRow struct {
+		GroupID   *uint `gorm:"column:group_id"`
+		Sent      int   `gorm:"column:sent"`
+		Responses int   `gorm:"column:responses"`
+		RatingSum int   `gorm:"column:rating_sum"`
+		Satisfied int   `gorm:"column:satisfied"`
+	}
+	averages := func(row satisfactionRow) (float64, float64) {
+		if row.Responses == 0 {
+			return 0, 0
+		}
+		return float64(row.RatingSum) / float64(row.Responses), (float64(row.Satisfied) / float64(row.Responses)) * 100
+	}
+
+	var total satisfactionRow
+	if err := baseQuery().Select("COUNT(*) as sent, " + satisfactionColumns).Scan(&total).Error; err != nil {
+		return nil, err
+	}
+	report := &dto.SatisfactionReportDTO{
+		Period:      normalizePeriod(period),
+		SurveysSent: total.Sent,
+		Responses:   total.Responses,
+		ByAgent:     []dto.SatisfactionByAgentDTO{},
+		ByFiliale:   []dto.SatisfactionByFilialeDTO{},
+		GeneratedAt: time.Now(),
+	}
+	if total.Sent > 0 {
+		report.ResponseRate = (float64(total.Responses) / float64(total.Sent)) * 100
+	}
+	report.AverageRating, report.CSAT = averages(total)
+
+	var agentRows []satisfactionRow
+	if err := baseQuery().
+		Select("ticket_satisfactions.agent_id as group_id, " + satisfactionColumns).
+		Where("ticket_satisfactions.agent_id IS NOT NULL AND ticket_satisfactions.answered_at IS NOT NULL").
+		Group("ticket_satisfactions.agent_id").
+		Order("responses DESC").
+		Scan(&agentRows).Error; err != nil {
+		return nil, err
+	}
+	agentIDs := make([]uint, 0, len(agentRows))
+	for _, row := range agentRows {
+		agentIDs = append(agentIDs, *row.GroupID)
+	}
+	agents := map[uint]*models.User{}
+	if len(agentIDs) > 0 {
+		users, err := s.userRepo.FindByIDs(agentIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range users {
+			agents[users[i].ID] = &users[i]
+		}
+	}
+	for _, row := range agentRows {
+		averageRating, csat := averages(row)
+		agentDTO := dto.SatisfactionByAgentDTO{
+			UserID:        *row.GroupID,
+			Responses:     row.Responses,
+			AverageRating: averageRating,
+			CSAT:          csat,
+		}
+		if user, ok := agents[*row.GroupID]; ok {
+			userDTO := userToDTO(user)
+			agentDTO.User = &userDTO
+		}
+		report.ByAgent = append(report.ByAgent, agentDTO)
+	}
+
+	var filialeRows []satisfactionRow
+	if err := baseQuery().
+		Select("ticket_satisfactions.filiale_id as group_id, " + satisfactionColumns).
+		Where("ticket_satisfactions.filiale_id IS NOT NULL AND ticket_satisfactions.answered_at IS NOT NULL").
+		Group("ticket_satisfactions.filiale_id").
+		Order("responses DESC").
+		Scan(&filialeRows).Error; err != nil {
+		return nil, err
+	}
+	filialeNames := map[uint]string{}
+	if len(filialeRows) > 0 {
+		var filiales []models.Filiale
+		if err := database.DB.Select("id", "name").Find(&filiales).Error; err != nil {
+			return nil, err
+		}
+		for _, filiale := range filiales {
+			filialeNames[filiale.ID] = filiale.Name
+		}
+	}
+	for _, row := range filialeRows {
+		averageRating, csat := averages(row)
+		report.ByFiliale = append(report.ByFiliale, dto.SatisfactionByFilialeDTO{
+			FilialeID:     *row.GroupID,
+			FilialeName:   filialeNames[*row.GroupID],
+			Responses:     row.Responses,
+			AverageRating: averageRating,
+			CSAT:          csat,
+		})
+	}
+
+	return report, nil
+}
+
 // getDepartmentUserIDs retourne les IDs des utilisateurs actifs du département quand scope = tableau de bord département
 func getDepartmentUserIDs(scopeParam interface{}) ([]uint, bool) {
 	if scopeParam == nil {
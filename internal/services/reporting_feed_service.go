@@ -0,0 +1,262 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/odata"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// reportingKeyPrefix préfixe des clés d'API du flux OData
+const reportingKeyPrefix = "krp_"
+
+// reportingPageSize nombre maximal d'entités par page du flux OData (pages suivantes via @odata.nextLink)
+const reportingPageSize = 1000
+
+// ReportingFeedService interface pour le flux OData de reporting et ses clés d'API
+type ReportingFeedService interface {
+	EntitySets() []odata.EntitySet
+	Authenticate(key string) (*models.ReportingKey, error)
+	Query(key *models.ReportingKey, setName string, values url.Values) (*dto.ODataPage, error)
+	GetKeys(filialeID *uint) ([]dto.ReportingKeyDTO, error)
+	CreateKey(req dto.CreateReportingKeyRequest, createdByID uint, filialeID *uint, includeBudget bool) (*dto.ReportingKeyDTO, error)
+	UpdateKey(id uint, req dto.UpdateReportingKeyRequest, filialeID *uint) (*dto.ReportingKeyDTO, error)
+	RotateKey(id uint, filialeID *uint) (*dto.ReportingKeyDTO, error)
+	DeleteKey(id uint, filialeID *uint) error
+}
+
+// reportingFeedService implémente ReportingFeedService
+type reportingFeedService struct {
+	reportingRepo repositories.ReportingRepository
+}
+
+// NewReportingFeedService crée une nouvelle instance de ReportingFeedService
+func NewReportingFeedService(reportingRepo repositories.ReportingRepository) ReportingFeedService {
+	return &reportingFeedService{
+		reportingRepo: reportingRepo,
+	}
+}
+
+// EntitySets retourne le schéma de reporting exposé
+func (s *reportingFeedService) EntitySets() []odata.EntitySet {
+	return s.reportingRepo.EntitySets()
+}
+
+// Authenticate retrouve la clé active correspondant à une clé d'API
+func (s *reportingFeedService) Authenticate(key string) (*models.ReportingKey, error) {
+	hash, err := apiKeyLookupHash(key, reportingKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	reportingKey, err := s.reportingRepo.FindKeyByHash(hash)
+	if err != nil || !reportingKey.IsActive {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	if markAPIKeyUsed(&reportingKey.LastUsedAt) {
+		if err := s.reportingRepo.UpdateKey(reportingKey); err != nil {
+			log.Printf("OData: erreur lors de la mise à jour de la clé %d: %v", reportingKey.ID, err)
+		}
+	}
+	return reportingKey, nil
+}
+
+// Query lit une page d'un ensemble d'entités dans le périmètre de la clé
+func (s *reportingFeedService) Query(key *models.ReportingKey, setName string, values url.Values) (*dto.ODataPage, error) {
+	var set *odata.EntitySet
+	sets := s.reportingRepo.EntitySets()
+	for i := range sets {
+		if sets[i].Name == setName {
+			set = &sets[i]
+			break
+		}
+	}
+	if set == nil {
+		return nil, errors.New("ensemble d'entités introuvable")
+	}
+
+	query, err := odata.ParseQuery(set, values)
+	if err != nil {
+		return nil, err
+	}
+	if !key.IncludeBudget {
+		for _, condition := range query.Filter {
+			if condition.Property.Restricted {
+				return nil, fmt.Errorf("propriété non accessible avec cette clé: %s", condition.Property.Name)
+			}
+		}
+		for _, order := range query.OrderBy {
+			if order.Property.Restricted {
+				return nil, fmt.Errorf("propriété non accessible avec cette clé: %s", order.Property.Name)
+			}
+		}
+	}
+
+	limit := query.Limit(reportingPageSize)
+	rows, total, err := s.reportingRepo.Query(set, query, key.FilialeID, key.IncludeBudget, limit)
+	if err != nil {
+		log.Printf("OData: erreur lors de la lecture de %s: %v", set.Name, err)
+		return nil, errors.New("erreur lors de la lecture des données")
+	}
+
+	page := &dto.ODataPage{Count: total, NextTop: -1}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		if query.Top < 0 || query.Top > limit {
+			page.HasNext = true
+			page.NextSkip = query.Skip + limit
+			if query.Top >= 0 {
+				page.NextTop = query.Top - limit
+			}
+		}
+	}
+
+	properties := query.Select
+	if len(properties) == 0 {
+		for i := range set.Properties {
+			properties = append(properties, &set.Properties[i])
+		}
+	}
+	for _, row := range rows {
+		for _, prop := range properties {
+			row[prop.Name] = odata.FormatValue(prop, row[prop.Name])
+		}
+	}
+	page.Rows = rows
+	return page, nil
+}
+
+// GetKeys récupère les clés d'API, limitées à une filiale si filialeID est renseigné
+func (s *reportingFeedService) GetKeys(filialeID *uint) ([]dto.ReportingKeyDTO, error) {
+	keys, err := s.reportingRepo.FindKeys(filialeID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des clés d'API")
+	}
+	result := make([]dto.ReportingKeyDTO, len(keys))
+	for i := range keys {
+		result[i] = reportingKeyToDTO(&keys[i])
+	}
+	return result, nil
+}
+
+// CreateKey crée une clé d'API ; filialeID nil donne accès aux données de toutes les filiales
+func (s *reportingFeedService) CreateKey(req dto.CreateReportingKeyRequest, createdByID uint, filialeID *uint, includeBudget bool) (*dto.ReportingKeyDTO, error) {
+	key, err := generateAPIKey(reportingKeyPrefix)
+	if err != nil {
+		return nil, errors.New("erreur lors de la génération de la clé d'API")
+	}
+	reportingKey := &models.ReportingKey{
+		Name:          strings.TrimSpace(req.Name),
+		KeyHash:       key.Hash,
+		KeyPrefix:     key.Prefix,
+		FilialeID:     filialeID,
+		IncludeBudget: includeBudget,
+		IsActive:      true,
+		CreatedByID:   createdByID,
+	}
+	if err := s.reportingRepo.CreateKey(reportingKey); err != nil {
+		return nil, errors.New("erreur lors de la création de la clé d'API")
+	}
+
+	created, err := s.reportingRepo.FindKeyByID(reportingKey.ID)
+	if err != nil {
+		created = reportingKey
+	}
+	keyDTO := reportingKeyToDTO(created)
+	keyDTO.Key = key.Key
+	return &keyDTO, nil
+}
+
+// UpdateKey met à jour le nom ou l'activation d'une clé
+func (s *reportingFeedService) UpdateKey(id uint, req dto.UpdateReportingKeyRequest, filialeID *uint) (*dto.ReportingKeyDTO, error) {
+	reportingKey, err := s.findKey(id, filialeID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		reportingKey.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.IsActive != nil {
+		reportingKey.IsActive = *req.IsActive
+	}
+	if err := s.reportingRepo.UpdateKey(reportingKey); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour de la clé d'API")
+	}
+	keyDTO := reportingKeyToDTO(reportingKey)
+	return &keyDTO, nil
+}
+
+// RotateKey renouvelle une clé ; l'ancienne est immédiatement refusée
+func (s *reportingFeedService) RotateKey(id uint, filialeID *uint) (*dto.ReportingKeyDTO, error) {
+	reportingKey, err := s.findKey(id, filialeID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := generateAPIKey(reportingKeyPrefix)
+	if err != nil {
+		return nil, errors.New("erreur lors de la génération de la clé d'API")
+	}
+	reportingKey.KeyHash = key.Hash
+	reportingKey.KeyPrefix = key.Prefix
+	if err := s.reportingRepo.UpdateKey(reportingKey); err != nil {
+		return nil, errors.New("erreur lors du renouvellement de la clé d'API")
+	}
+	keyDTO := reportingKeyToDTO(reportingKey)
+	keyDTO.Key = key.Key
+	return &keyDTO, nil
+}
+
+// DeleteKey supprime une clé
+func (s *reportingFeedService) DeleteKey(id uint, filialeID *uint) error {
+	if _, err := s.findKey(id, filialeID); err != nil {
+		return err
+	}
+	if err := s.reportingRepo.DeleteKey(id); err != nil {
+		return errors.New("erreur lors de la suppression de la clé d'API")
+	}
+	return nil
+}
+
+// findKey charge une clé en vérifiant qu'elle appartient à la filiale du gestionnaire (filialeID nil = toutes)
+func (s *reportingFeedService) findKey(id uint, filialeID *uint) (*models.ReportingKey, error) {
+	reportingKey, err := s.reportingRepo.FindKeyByID(id)
+	if err != nil {
+		return nil, errors.New("clé d'API introuvable")
+	}
+	if filialeID != nil && (reportingKey.FilialeID == nil || *reportingKey.FilialeID != *filialeID) {
+		return nil, errors.New("clé d'API introuvable")
+	}
+	return reportingKey, nil
+}
+
+// ReportingFeedURL retourne l'URL racine du flux OData
+func ReportingFeedURL() string {
+	return strings.TrimRight(config.AppConfig.AppURL, "/") + "/api/v1/odata"
+}
+
+// reportingKeyToDTO convertit une clé d'API en DTO
+func reportingKeyToDTO(key *models.ReportingKey) dto.ReportingKeyDTO {
+	keyDTO := dto.ReportingKeyDTO{
+		ID:            key.ID,
+		Name:          key.Name,
+		KeyPrefix:     key.KeyPrefix,
+		FeedURL:       ReportingFeedURL(),
+		FilialeID:     key.FilialeID,
+		IncludeBudget: key.IncludeBudget,
+		IsActive:      key.IsActive,
+		LastUsedAt:    key.LastUsedAt,
+		CreatedAt:     key.CreatedAt,
+	}
+	if key.Filiale != nil {
+		keyDTO.FilialeName = key.Filiale.Name
+	}
+	return keyDTO
+}
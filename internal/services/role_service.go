@@ -7,6 +7,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // RoleService interface pour les opérations sur les rôles
@@ -22,6 +23,9 @@ type RoleService interface {
 	GetAssignablePermissions(userID uint) ([]string, error)                                                      // Récupère les permissions que l'utilisateur peut déléguer
 	GetMyDelegations(userID uint) ([]dto.RoleDTO, error)                                                         // Rôles créés par l'utilisateur (délégation)
 	GetForDelegationPage(userID uint, filialeID *uint) ([]dto.RoleDTO, error)                                    // Rôles créés par l'utilisateur + rôles utilisés par au moins un user de la filiale
+	GetTemplates() []dto.RoleTemplateDTO                                                                         // Modèles de rôles prédéfinis
+	CreateFromTemplate(req dto.CreateRoleFromTemplateRequest, createdByID uint) (*dto.RoleDTO, error)            // Crée un rôle à partir d'un modèle
+	Clone(id uint, req dto.CloneRoleRequest, createdByID uint) (*dto.RoleDTO, error)                             // Duplique un rôle avec ses permissions
 }
 
 // roleService implémente RoleService
@@ -66,7 +70,7 @@ func (s *roleService) Create(req dto.CreateRoleRequest, createdByID uint) (*dto.
 	if filialeID != nil {
 		filiale, err := s.filialeRepo.FindByID(*filialeID)
 		if err != nil || filiale == nil {
-			return nil, errors.New("filiale introuvable")
+			return nil, utils.ErrFilialeNotFound
 		}
 		code := strings.TrimSpace(filiale.Code)
 		if code != "" {
@@ -131,11 +135,76 @@ func (s *roleService) Create(req dto.CreateRoleRequest, createdByID uint) (*dto.
 	return &roleDTO, nil
 }
 
+// GetTemplates retourne les modèles de rôles prédéfinis
+func (s *roleService) GetTemplates() []dto.RoleTemplateDTO {
+	templates := make([]dto.RoleTemplateDTO, 0, len(roleTemplates))
+	for _, template := range roleTemplates {
+		templates = append(templates, dto.RoleTemplateDTO{
+			Key:         template.Key,
+			Name:        template.Name,
+			Description: template.Description,
+			Permissions: append([]string(nil), template.Permissions...),
+		})
+	}
+	return templates
+}
+
+// CreateFromTemplate crée un rôle avec le jeu de permissions d'un modèle
+// Les mêmes règles que Create s'appliquent (le créateur doit posséder toutes les permissions du modèle)
+func (s *roleService) CreateFromTemplate(req dto.CreateRoleFromTemplateRequest, createdByID uint) (*dto.RoleDTO, error) {
+	template := findRoleTemplate(req.Template)
+	if template == nil {
+		return nil, errors.New("modèle de rôle introuvable")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = template.Name
+	}
+	description := req.Description
+	if description == "" {
+		description = template.Description
+	}
+
+	return s.Create(dto.CreateRoleRequest{
+		Name:        name,
+		Description: description,
+		Permissions: append([]string(nil), template.Permissions...),
+		FilialeID:   req.FilialeID,
+	}, createdByID)
+}
+
+// Clone crée un nouveau rôle avec les permissions d'un rôle existant
+// Les mêmes règles que Create s'appliquent (le créateur doit posséder toutes les permissions du rôle source)
+func (s *roleService) Clone(id uint, req dto.CloneRoleRequest, createdByID uint) (*dto.RoleDTO, error) {
+	source, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrRoleNotFound
+	}
+
+	permissions, err := s.roleRepo.GetPermissionsByRoleID(source.ID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des permissions")
+	}
+
+	description := req.Description
+	if description == "" {
+		description = source.Description
+	}
+
+	return s.Create(dto.CreateRoleRequest{
+		Name:        req.Name,
+		Description: description,
+		Permissions: permissions,
+		FilialeID:   req.FilialeID,
+	}, createdByID)
+}
+
 // GetByID récupère un rôle par son ID
 func (s *roleService) GetByID(id uint) (*dto.RoleDTO, error) {
 	role, err := s.roleRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("rôle introuvable")
+		return nil, utils.ErrRoleNotFound
 	}
 
 	roleDTO := s.roleToDTO(role)
@@ -232,7 +301,7 @@ func (s *roleService) GetForDelegationPage(userID uint, filialeID *uint) ([]dto.
 func (s *roleService) Update(id uint, req dto.UpdateRoleRequest, updatedByID uint, canManageAllRoles bool) (*dto.RoleDTO, error) {
 	role, err := s.roleRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("rôle introuvable")
+		return nil, utils.ErrRoleNotFound
 	}
 
 	// Vérifier que ce n'est pas un rôle système (ne peut pas être modifié)
@@ -287,7 +356,7 @@ func (s *roleService) Update(id uint, req dto.UpdateRoleRequest, updatedByID uin
 func (s *roleService) Delete(id uint, deletedByID uint, canManageAllRoles bool) error {
 	role, err := s.roleRepo.FindByID(id)
 	if err != nil {
-		return errors.New("rôle introuvable")
+		return utils.ErrRoleNotFound
 	}
 
 	// Vérifier que ce n'est pas un rôle système
@@ -319,14 +388,15 @@ func (s *roleService) Delete(id uint, deletedByID uint, canManageAllRoles bool)
 // roleToDTO convertit un modèle Role en DTO
 func (s *roleService) roleToDTO(role *models.Role) dto.RoleDTO {
 	return dto.RoleDTO{
-		ID:          role.ID,
-		Name:        role.Name,
-		Description: role.Description,
-		IsSystem:    role.IsSystem,
-		CreatedByID: role.CreatedByID,
-		FilialeID:   role.FilialeID,
-		CreatedAt:   role.CreatedAt,
-		UpdatedAt:   role.UpdatedAt,
+		ID:                role.ID,
+		Name:              role.Name,
+		Description:       role.Description,
+		IsSystem:          role.IsSystem,
+		CreatedByID:       role.CreatedByID,
+		FilialeID:         role.FilialeID,
+		UtilizationTarget: role.UtilizationTarget,
+		CreatedAt:         role.CreatedAt,
+		UpdatedAt:         role.UpdatedAt,
 	}
 }
 
@@ -335,7 +405,7 @@ func (s *roleService) GetRolePermissions(roleID uint) ([]string, error) {
 	// Vérifier que le rôle existe
 	_, err := s.roleRepo.FindByID(roleID)
 	if err != nil {
-		return nil, errors.New("rôle introuvable")
+		return nil, utils.ErrRoleNotFound
 	}
 
 	// Récupérer les permissions
@@ -352,7 +422,7 @@ func (s *roleService) UpdateRolePermissions(roleID uint, permissionCodes []strin
 	// Vérifier que le rôle existe
 	role, err := s.roleRepo.FindByID(roleID)
 	if err != nil {
-		return errors.New("rôle introuvable")
+		return utils.ErrRoleNotFound
 	}
 
 	// Vérifier que ce n'est pas un rôle système (ne peut pas être modifié)
@@ -408,7 +478,7 @@ func (s *roleService) GetAssignablePermissions(userID uint) ([]string, error) {
 	// Récupérer l'utilisateur
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	// Récupérer les permissions du rôle de l'utilisateur
@@ -0,0 +1,104 @@
+package services
+
+// roleTemplate décrit un modèle de rôle prédéfini (jeu de permissions prêt à l'emploi pour l'ouverture d'une filiale)
+type roleTemplate struct {
+	Key         string
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// roleTemplates liste les modèles de rôles proposés par POST /roles/from-template
+var roleTemplates = []roleTemplate{
+	{
+		Key:         "dsi_filiale",
+		Name:        "DSI",
+		Description: "DSI de filiale : pilotage des tickets, utilisateurs, projets et rapports de sa filiale",
+		Permissions: []string{
+			"tickets.view_filiale", "tickets.create", "tickets.update", "tickets.assign", "tickets.reassign",
+			"tickets.close", "tickets.resolve_own_filiale", "tickets.validate", "tickets.share", "tickets.merge", "tickets.comments.view_internal",
+			"tickets_internes.view_filiale", "tickets_internes.create", "tickets_internes.update", "tickets_internes.assign",
+			"tickets_internes.validate", "tickets_internes.close",
+			"incidents.view_all", "incidents.create", "incidents.update",
+			"service_requests.view_all", "service_requests.create", "service_requests.update",
+			"changes.view_all", "changes.create", "changes.update",
+			"users.view_filiale", "users.create", "users.update", "users.view_phone",
+			"roles.view_filiale", "roles.delegate_permissions",
+			"departments.view_filiale", "offices.view_filiale", "filiales.view",
+			"reports.view_filiale", "reports.view_departments", "reports.view_employees",
+			"assets.view_team", "assets.create", "assets.update", "asset_reservations.manage", "asset_maintenance.manage",
+			"knowledge.view_all", "knowledge.create", "knowledge.update", "knowledge.publish",
+			"sla.view", "sla.view_all", "audit.view_team", "support_contracts.view", "software_licenses.view",
+			"delays.view_all", "delays.validate",
+			"timesheet.view_all", "timesheet.validate", "timesheet.validate_justification", "timesheet.view_budget",
+			"timesheet.view_utilization", "timesheet.manage_utilization_targets",
+			"projects.view", "projects.create", "projects.update", "projects.budget.view", "projects.dashboard.view", "projects.capacity.view", "projects.templates.manage", "projects.archive", "projects.portfolio.view",
+			"software.view", "ticket_categories.view", "asset_categories.view", "knowledge_categories.view",
+		},
+	},
+	{
+		Key:         "resolveur_it",
+		Name:        "RESOLVEUR_IT",
+		Description: "Technicien IT : traitement des tickets de son équipe, saisie des temps et base de connaissances",
+		Permissions: []string{
+			"tickets.view_team", "tickets.create", "tickets.update", "tickets.close", "tickets.resolve_own_filiale",
+			"tickets.comments.view_internal",
+			"tickets_internes.view_department", "tickets_internes.create", "tickets_internes.update", "tickets_internes.close",
+			"incidents.view_team", "incidents.create", "incidents.update",
+			"service_requests.view_team", "service_requests.update", "changes.view_team",
+			"users.view_team", "users.view_phone",
+			"assets.view_team", "assets.update", "asset_reservations.manage", "asset_maintenance.manage",
+			"knowledge.view_published", "knowledge.create", "knowledge.update",
+			"timesheet.create_entry", "timesheet.view_own", "timesheet.justify_delay", "timesheet.create_daily", "timesheet.create_weekly",
+			"delays.view_own", "sla.view_team",
+			"software.view", "ticket_categories.view", "asset_categories.view", "knowledge_categories.view",
+		},
+	},
+	{
+		Key:         "chef_projet",
+		Name:        "CHEF_PROJET",
+		Description: "Chef de projet : gestion complète des projets (phases, membres, tâches, budget temps)",
+		Permissions: []string{
+			"projects.view", "projects.create", "projects.update", "projects.set_project_manager", "projects.set_lead", "projects.share",
+			"projects.phases.view", "projects.phases.create", "projects.phases.update", "projects.phases.delete", "projects.phases.reorder",
+			"projects.functions.view",
+			"projects.members.view", "projects.members.add", "projects.members.remove", "projects.members.assign_function",
+			"projects.phase_members.view", "projects.phase_members.add", "projects.phase_members.remove", "projects.phase_members.assign_function",
+			"projects.tasks.view", "projects.tasks.view_project", "projects.tasks.create", "projects.tasks.update", "projects.tasks.delete",
+			"projects.tasks.assign", "projects.tasks.close",
+			"projects.tasks.comments.view", "projects.tasks.comments.create", "projects.tasks.comments.update",
+			"projects.tasks.attachments.view", "projects.tasks.attachments.create",
+			"projects.tasks.time.view", "projects.tasks.time.create",
+			"projects.budget.view", "projects.budget.manage", "projects.dashboard.view", "projects.capacity.view", "projects.templates.manage", "projects.archive", "projects.portfolio.view",
+			"tickets.view_own", "tickets.create",
+			"users.view_filiale", "users.view_phone",
+			"timesheet.create_entry", "timesheet.view_team", "timesheet.view_own", "timesheet.view_utilization",
+			"reports.view_team", "knowledge.view_published",
+		},
+	},
+	{
+		Key:         "rh",
+		Name:        "RH",
+		Description: "Ressources humaines : gestion des utilisateurs, départements et suivi des temps de la filiale",
+		Permissions: []string{
+			"users.view_filiale", "users.create", "users.update", "users.view_phone",
+			"roles.view_filiale",
+			"departments.view_filiale", "offices.view_filiale",
+			"reports.view_employees", "reports.view_departments",
+			"timesheet.view_all", "timesheet.validate_justification", "timesheet.view_utilization",
+			"delays.view_all",
+			"tickets.view_own", "tickets.create",
+			"knowledge.view_published",
+		},
+	},
+}
+
+// findRoleTemplate retourne le modèle de rôle correspondant à la clé, ou nil
+func findRoleTemplate(key string) *roleTemplate {
+	for i := range roleTemplates {
+		if roleTemplates[i].Key == key {
+			return &roleTemplates[i]
+		}
+	}
+	return nil
+}
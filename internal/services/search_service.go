@@ -2,12 +2,17 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/mcicare/itsm-backend/internal/cache"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/kbsearch"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/scope"
 )
 
 // SearchService interface pour les opérations de recherche
@@ -18,8 +23,15 @@ type SearchService interface {
 	SearchKnowledgeBase(scope interface{}, query string, category string, limit int) ([]dto.KnowledgeArticleSearchResultDTO, error) // scope peut être *scope.QueryScope ou nil
 	SearchUsers(scope interface{}, query string, limit int) ([]dto.UserSearchResultDTO, error) // scope peut être *scope.QueryScope ou nil
 	SearchTimeEntries(scope interface{}, query string, limit int) ([]dto.TimeEntrySearchResultDTO, error) // scope peut être *scope.QueryScope ou nil
+	InvalidateCache() // Vide le cache des résultats (abonné aux événements ticket.* du bus)
 }
 
+// searchCachePrefix préfixe des résultats de recherche globale en cache (courte durée, par utilisateur)
+const searchCachePrefix = "search:"
+
+// searchCacheTTL durée de conservation d'un résultat de recherche globale
+const searchCacheTTL = 30 * time.Second
+
 // searchService implémente SearchService
 type searchService struct {
 	ticketRepo  repositories.TicketRepository
@@ -27,6 +39,7 @@ type searchService struct {
 	articleRepo repositories.KnowledgeArticleRepository
 	userRepo    repositories.UserRepository
 	timeEntryRepo repositories.TimeEntryRepository
+	knowledgeSearch *knowledgeSearch // Recherche plein texte des articles
 }
 
 // NewSearchService crée une nouvelle instance de SearchService
@@ -36,6 +49,7 @@ func NewSearchService(
 	articleRepo repositories.KnowledgeArticleRepository,
 	userRepo repositories.UserRepository,
 	timeEntryRepo repositories.TimeEntryRepository,
+	knowledgeEngine kbsearch.Engine,
 ) SearchService {
 	return &searchService{
 		ticketRepo:  ticketRepo,
@@ -43,6 +57,7 @@ func NewSearchService(
 		articleRepo: articleRepo,
 		userRepo:    userRepo,
 		timeEntryRepo: timeEntryRepo,
+		knowledgeSearch: &knowledgeSearch{engine: knowledgeEngine, articleRepo: articleRepo},
 	}
 }
 
@@ -57,6 +72,23 @@ func (s *searchService) GlobalSearch(scopeParam interface{}, query string, types
 		limit = 20
 	}
 
+	// Résultats mis en cache par utilisateur : la recherche globale interroge cinq tables en LIKE
+	if queryScope, ok := scopeParam.(*scope.QueryScope); ok && queryScope != nil {
+		key := fmt.Sprintf("%s%d:%s:%d:%s", searchCachePrefix, queryScope.UserID, strings.Join(types, ","), limit, strings.ToLower(query))
+		return cache.GetOrLoad(cache.Shared, key, searchCacheTTL, func() (*dto.GlobalSearchResultDTO, error) {
+			return s.globalSearch(scopeParam, query, types, limit)
+		})
+	}
+	return s.globalSearch(scopeParam, query, types, limit)
+}
+
+// InvalidateCache vide le cache des résultats de recherche globale
+func (s *searchService) InvalidateCache() {
+	cache.Shared.DeletePrefix(searchCachePrefix)
+}
+
+// globalSearch exécute la recherche globale sans cache
+func (s *searchService) globalSearch(scopeParam interface{}, query string, types []string, limit int) (*dto.GlobalSearchResultDTO, error) {
 	result := &dto.GlobalSearchResultDTO{
 		Query: query,
 		Types: types,
@@ -167,30 +199,33 @@ func (s *searchService) searchAssetsInternal(scopeParam interface{}, query strin
 	return resultDTOs, nil
 }
 
-// searchKnowledgeBaseInternal recherche interne dans la base de connaissances
+// searchKnowledgeBaseInternal recherche interne dans la base de connaissances (plein texte, par pertinence)
 func (s *searchService) searchKnowledgeBaseInternal(scopeParam interface{}, query string, category string, limit int) ([]dto.KnowledgeArticleSearchResultDTO, error) {
-	articles, err := s.articleRepo.Search(scopeParam, query)
+	results, _, err := s.knowledgeSearch.search(scopeParam, query, 0)
 	if err != nil {
 		return nil, errors.New("erreur lors de la recherche dans la base de connaissances")
 	}
 
 	// Filtrer par catégorie si spécifiée
-	filteredArticles := []models.KnowledgeArticle{}
-	for _, article := range articles {
-		if category == "" || (article.Category.ID != 0 && strings.EqualFold(article.Category.Name, category)) {
-			filteredArticles = append(filteredArticles, article)
+	filteredResults := []knowledgeSearchResult{}
+	for _, result := range results {
+		if category == "" || (result.article.Category.ID != 0 && strings.EqualFold(result.article.Category.Name, category)) {
+			filteredResults = append(filteredResults, result)
 		}
 	}
 
 	// Limiter les résultats
-	if limit > 0 && len(filteredArticles) > limit {
-		filteredArticles = filteredArticles[:limit]
+	if limit > 0 && len(filteredResults) > limit {
+		filteredResults = filteredResults[:limit]
 	}
 
 	// Convertir en DTOs
-	resultDTOs := make([]dto.KnowledgeArticleSearchResultDTO, len(filteredArticles))
-	for i, article := range filteredArticles {
-		resultDTOs[i] = s.articleToSearchResultDTO(&article, query)
+	resultDTOs := make([]dto.KnowledgeArticleSearchResultDTO, len(filteredResults))
+	for i, result := range filteredResults {
+		resultDTOs[i] = s.articleToSearchResultDTO(&result.article, query)
+		resultDTOs[i].TitleHighlight = result.hit.TitleHighlight
+		resultDTOs[i].Highlight = result.hit.Highlight
+		resultDTOs[i].Score = result.hit.Score
 	}
 
 	return resultDTOs, nil
@@ -228,7 +263,7 @@ func (s *searchService) searchTimeEntriesInternal(scopeParam interface{}, query
 
 // ticketToSearchResultDTO convertit un ticket en DTO de recherche
 func (s *searchService) ticketToSearchResultDTO(ticket *models.Ticket, query string) dto.TicketSearchResultDTO {
-	snippet := extractSnippet(ticket.Description, query, 150)
+	snippet := extractSnippet(richtext.PlainText(ticket.Description, ticket.DescriptionFormat), query, 150)
 	
 	result := dto.TicketSearchResultDTO{
 		ID:        ticket.ID,
@@ -304,7 +339,7 @@ func (s *searchService) assetToSearchResultDTO(asset *models.Asset, query string
 
 // articleToSearchResultDTO convertit un article en DTO de recherche
 func (s *searchService) articleToSearchResultDTO(article *models.KnowledgeArticle, query string) dto.KnowledgeArticleSearchResultDTO {
-	snippet := extractSnippet(article.Content, query, 200)
+	snippet := extractSnippet(richtext.PlainText(article.Content, article.ContentFormat), query, 200)
 	
 	result := dto.KnowledgeArticleSearchResultDTO{
 		ID:        article.ID,
@@ -7,6 +7,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // ServiceRequestService interface pour les opérations sur les demandes de service
@@ -78,7 +79,7 @@ func (s *serviceRequestService) Create(req dto.CreateServiceRequestRequest, crea
 	// Vérifier que le ticket existe et est de catégorie "demande"
 	ticket, err := s.ticketRepo.FindByID(req.TicketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	if ticket.Category != "demande" {
@@ -139,7 +140,7 @@ func (s *serviceRequestService) Create(req dto.CreateServiceRequestRequest, crea
 func (s *serviceRequestService) GetByID(id uint) (*dto.ServiceRequestDTO, error) {
 	serviceRequest, err := s.serviceRequestRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("demande de service introuvable")
+		return nil, utils.ErrServiceRequestNotFound
 	}
 
 	requestDTO := s.serviceRequestToDTO(serviceRequest)
@@ -150,7 +151,7 @@ func (s *serviceRequestService) GetByID(id uint) (*dto.ServiceRequestDTO, error)
 func (s *serviceRequestService) GetByTicketID(ticketID uint) (*dto.ServiceRequestDTO, error) {
 	serviceRequest, err := s.serviceRequestRepo.FindByTicketID(ticketID)
 	if err != nil {
-		return nil, errors.New("demande de service introuvable")
+		return nil, utils.ErrServiceRequestNotFound
 	}
 
 	requestDTO := s.serviceRequestToDTO(serviceRequest)
@@ -222,7 +223,7 @@ func (s *serviceRequestService) Update(id uint, req dto.UpdateServiceRequestRequ
 	// Récupérer la demande existante
 	serviceRequest, err := s.serviceRequestRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("demande de service introuvable")
+		return nil, utils.ErrServiceRequestNotFound
 	}
 
 	// Mettre à jour les champs fournis
@@ -262,7 +263,7 @@ func (s *serviceRequestService) Validate(id uint, req dto.ValidateServiceRequest
 	// Récupérer la demande
 	serviceRequest, err := s.serviceRequestRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("demande de service introuvable")
+		return nil, utils.ErrServiceRequestNotFound
 	}
 
 	// Vérifier que la demande n'est pas déjà validée
@@ -297,7 +298,7 @@ func (s *serviceRequestService) Delete(id uint) error {
 	// Vérifier que la demande existe
 	_, err := s.serviceRequestRepo.FindByID(id)
 	if err != nil {
-		return errors.New("demande de service introuvable")
+		return utils.ErrServiceRequestNotFound
 	}
 
 	// Supprimer
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/i18n"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// maxSkillSearchResults nombre maximal d'utilisateurs retournés par une recherche par compétences
+const maxSkillSearchResults = 100
+
+// SkillSearchOptions critères d'une recherche d'utilisateurs par compétences
+type SkillSearchOptions struct {
+	SkillIDs      []uint
+	MinLevel      int  // Niveau minimal (1 par défaut)
+	MatchAll      bool // Toutes les compétences requises (sinon au moins une)
+	AvailableOnly bool // Exclure les utilisateurs absents
+	Limit         int
+}
+
+// SkillService interface pour les opérations sur les compétences et certifications
+// La recherche (Search) sert au routage des tickets par compétence et aux suggestions d'affectation des projets
+type SkillService interface {
+	GetCatalog(kind, category string, includeInactive bool) ([]dto.SkillDTO, error)
+	Create(req dto.CreateSkillRequest) (*dto.SkillDTO, error)
+	Update(id uint, req dto.UpdateSkillRequest) (*dto.SkillDTO, error)
+	Delete(id uint) error
+	GetUserSkills(userID uint) ([]dto.UserSkillDTO, error)
+	SetUserSkill(userID, skillID uint, req dto.SetUserSkillRequest) ([]dto.UserSkillDTO, error)
+	RemoveUserSkill(userID, skillID uint) error
+	Search(scope interface{}, opts SkillSearchOptions) ([]dto.SkillSearchResultDTO, error) // scope peut être *scope.QueryScope ou nil
+}
+
+// skillService implémente SkillService
+type skillService struct {
+	skillRepo     repositories.SkillRepository
+	userSkillRepo repositories.UserSkillRepository
+	userRepo      repositories.UserRepository
+}
+
+// NewSkillService crée une nouvelle instance de SkillService
+func NewSkillService(skillRepo repositories.SkillRepository, userSkillRepo repositories.UserSkillRepository, userRepo repositories.UserRepository) SkillService {
+	return &skillService{
+		skillRepo:     skillRepo,
+		userSkillRepo: userSkillRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// GetCatalog récupère le catalogue des compétences
+func (s *skillService) GetCatalog(kind, category string, includeInactive bool) ([]dto.SkillDTO, error) {
+	skills, err := s.skillRepo.FindAll(kind, category, !includeInactive)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des compétences")
+	}
+	skillDTOs := make([]dto.SkillDTO, 0, len(skills))
+	for i := range skills {
+		skillDTOs = append(skillDTOs, skillToDTO(&skills[i]))
+	}
+	return skillDTOs, nil
+}
+
+// Create ajoute une compétence au catalogue
+func (s *skillService) Create(req dto.CreateSkillRequest) (*dto.SkillDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("le nom de la compétence est requis")
+	}
+	if _, err := s.skillRepo.FindByName(name); err == nil {
+		return nil, errors.New("une compétence porte déjà ce nom")
+	}
+
+	skill := &models.Skill{
+		Name:        name,
+		Kind:        req.Kind,
+		Category:    strings.TrimSpace(req.Category),
+		Description: req.Description,
+		IsActive:    true,
+	}
+	if skill.Kind == "" {
+		skill.Kind = models.SkillKindSkill
+	}
+	if err := s.skillRepo.Create(skill); err != nil {
+		return nil, errors.New("erreur lors de la création de la compétence")
+	}
+
+	skillDTO := skillToDTO(skill)
+	return &skillDTO, nil
+}
+
+// Update met à jour une compétence du catalogue
+func (s *skillService) Update(id uint, req dto.UpdateSkillRequest) (*dto.SkillDTO, error) {
+	skill, err := s.skillRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("compétence introuvable")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("le nom de la compétence est requis")
+		}
+		if existing, err := s.skillRepo.FindByName(name); err == nil && existing.ID != id {
+			return nil, errors.New("une compétence porte déjà ce nom")
+		}
+		skill.Name = name
+	}
+	if req.Kind != nil {
+		skill.Kind = *req.Kind
+	}
+	if req.Category != nil {
+		skill.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.Description != nil {
+		skill.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		skill.IsActive = *req.IsActive
+	}
+
+	if err := s.skillRepo.Update(skill); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour de la compétence")
+	}
+
+	skillDTO := skillToDTO(skill)
+	return &skillDTO, nil
+}
+
+// Delete supprime une compétence qui n'est détenue par personne
+func (s *skillService) Delete(id uint) error {
+	if _, err := s.skillRepo.FindByID(id); err != nil {
+		return errors.New("compétence introuvable")
+	}
+	holders, err := s.skillRepo.CountHolders(id)
+	if err != nil {
+		return errors.New("erreur lors de la suppression de la compétence")
+	}
+	if holders > 0 {
+		return fmt.Errorf("compétence détenue par %d utilisateur(s) : désactivez-la plutôt", holders)
+	}
+	if err := s.skillRepo.Delete(id); err != nil {
+		return errors.New("erreur lors de la suppression de la compétence")
+	}
+	return nil
+}
+
+// GetUserSkills récupère le profil de compétences d'un utilisateur
+func (s *skillService) GetUserSkills(userID uint) ([]dto.UserSkillDTO, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	userSkills, err := s.userSkillRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des compétences")
+	}
+	return userSkillsToDTOs(userSkills), nil
+}
+
+// SetUserSkill ajoute ou met à jour une compétence dans le profil d'un utilisateur
+func (s *skillService) SetUserSkill(userID, skillID uint, req dto.SetUserSkillRequest) ([]dto.UserSkillDTO, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	skill, err := s.skillRepo.FindByID(skillID)
+	if err != nil {
+		return nil, errors.New("compétence introuvable")
+	}
+	if !skill.IsActive {
+		return nil, errors.New("cette compétence n'est plus proposée")
+	}
+	if req.ObtainedAt != nil && req.ExpiresAt != nil && !req.ExpiresAt.After(*req.ObtainedAt) {
+		return nil, errors.New("la date d'expiration doit être postérieure à la date d'obtention")
+	}
+
+	userSkill := &models.UserSkill{
+		UserID:     userID,
+		SkillID:    skillID,
+		Level:      req.Level,
+		ObtainedAt: req.ObtainedAt,
+		ExpiresAt:  req.ExpiresAt,
+		Reference:  strings.TrimSpace(req.Reference),
+	}
+	if err := s.userSkillRepo.Save(userSkill); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement de la compétence")
+	}
+	return s.GetUserSkills(userID)
+}
+
+// RemoveUserSkill retire une compétence du profil d'un utilisateur
+func (s *skillService) RemoveUserSkill(userID, skillID uint) error {
+	removed, err := s.userSkillRepo.Delete(userID, skillID)
+	if err != nil {
+		return errors.New("erreur lors de la suppression de la compétence")
+	}
+	if !removed {
+		return errors.New("compétence absente du profil")
+	}
+	return nil
+}
+
+// Search recherche les utilisateurs détenant des compétences, triés par pertinence
+// (nombre de compétences détenues au niveau requis, puis niveau cumulé)
+func (s *skillService) Search(scopeParam interface{}, opts SkillSearchOptions) ([]dto.SkillSearchResultDTO, error) {
+	if len(opts.SkillIDs) == 0 {
+		return nil, errors.New("au moins une compétence est requise")
+	}
+	if opts.MinLevel < models.SkillLevelBeginner || opts.MinLevel > models.SkillLevelExpert {
+		opts.MinLevel = models.SkillLevelBeginner
+	}
+	if opts.Limit <= 0 || opts.Limit > maxSkillSearchResults {
+		opts.Limit = maxSkillSearchResults
+	}
+
+	// Les absents sont filtrés après coup : on en demande davantage pour conserver la limite
+	limit := opts.Limit
+	if opts.AvailableOnly {
+		limit = opts.Limit * 2
+	}
+	matches, err := s.userSkillRepo.SearchUsers(scopeParam, opts.SkillIDs, opts.MinLevel, opts.MatchAll, limit)
+	if err != nil {
+		return nil, errors.New("erreur lors de la recherche par compétences")
+	}
+
+	userIDs := make([]uint, 0, len(matches))
+	for _, match := range matches {
+		userIDs = append(userIDs, match.UserID)
+	}
+	users, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return nil, errors.New("erreur lors de la recherche par compétences")
+	}
+	usersByID := make(map[uint]*models.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+	userSkills, err := s.userSkillRepo.FindByUserIDs(userIDs, opts.SkillIDs)
+	if err != nil {
+		return nil, errors.New("erreur lors de la recherche par compétences")
+	}
+	skillsByUser := make(map[uint][]models.UserSkill)
+	for _, userSkill := range userSkills {
+		skillsByUser[userSkill.UserID] = append(skillsByUser[userSkill.UserID], userSkill)
+	}
+
+	now := time.Now()
+	results := make([]dto.SkillSearchResultDTO, 0, len(matches))
+	for _, match := range matches {
+		user, ok := usersByID[match.UserID]
+		if !ok {
+			continue
+		}
+		available := !user.IsOutOfOffice(now)
+		if opts.AvailableOnly && !available {
+			continue
+		}
+		results = append(results, dto.SkillSearchResultDTO{
+			User:         *orgChartUserToDTO(user),
+			MatchedCount: match.MatchedCount,
+			LevelTotal:   match.LevelTotal,
+			Available:    available,
+			Skills:       userSkillsToDTOs(skillsByUser[user.ID]),
+		})
+		if len(results) == opts.Limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// skillToDTO convertit un modèle Skill en DTO
+func skillToDTO(skill *models.Skill) dto.SkillDTO {
+	return dto.SkillDTO{
+		ID:          skill.ID,
+		Name:        skill.Name,
+		Kind:        skill.Kind,
+		Category:    skill.Category,
+		Description: skill.Description,
+		IsActive:    skill.IsActive,
+		CreatedAt:   skill.CreatedAt,
+		UpdatedAt:   skill.UpdatedAt,
+	}
+}
+
+// userSkillsToDTOs convertit les compétences d'un utilisateur en DTOs
+func userSkillsToDTOs(userSkills []models.UserSkill) []dto.UserSkillDTO {
+	now := time.Now()
+	userSkillDTOs := make([]dto.UserSkillDTO, 0, len(userSkills))
+	for i := range userSkills {
+		userSkill := &userSkills[i]
+		userSkillDTO := dto.UserSkillDTO{
+			Level:      userSkill.Level,
+			LevelLabel: i18n.Label(i18n.Default, "skill_level", strconv.Itoa(userSkill.Level)),
+			ObtainedAt: userSkill.ObtainedAt,
+			ExpiresAt:  userSkill.ExpiresAt,
+			Expired:    userSkill.IsExpired(now),
+			Reference:  userSkill.Reference,
+			UpdatedAt:  userSkill.UpdatedAt,
+		}
+		if userSkill.Skill != nil {
+			userSkillDTO.Skill = skillToDTO(userSkill.Skill)
+		}
+		userSkillDTOs = append(userSkillDTOs, userSkillDTO)
+	}
+	return userSkillDTOs
+}
@@ -1,12 +1,17 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"log"
 	"time"
 
+	"github.com/mcicare/itsm-backend/internal/businesstime"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // SLAService interface pour les opérations sur les SLA
@@ -28,10 +33,13 @@ type SLAService interface {
 
 // slaService implémente SLAService
 type slaService struct {
-	slaRepo            repositories.SLARepository
-	ticketSLARepo      repositories.TicketSLARepository
-	ticketRepo         repositories.TicketRepository
-	ticketCategoryRepo repositories.TicketCategoryRepository
+	slaRepo                 repositories.SLARepository
+	ticketSLARepo           repositories.TicketSLARepository
+	ticketRepo              repositories.TicketRepository
+	ticketCategoryRepo      repositories.TicketCategoryRepository
+	ticketService           TicketService
+	eventBus                *events.Bus
+	businessCalendarService BusinessCalendarService // Calendriers ouvrés (échéances en temps ouvré)
 }
 
 // NewSLAService crée une nouvelle instance de SLAService
@@ -40,12 +48,18 @@ func NewSLAService(
 	ticketSLARepo repositories.TicketSLARepository,
 	ticketRepo repositories.TicketRepository,
 	ticketCategoryRepo repositories.TicketCategoryRepository,
+	ticketService TicketService,
+	eventBus *events.Bus,
+	businessCalendarService BusinessCalendarService,
 ) SLAService {
 	return &slaService{
-		slaRepo:            slaRepo,
-		ticketSLARepo:      ticketSLARepo,
-		ticketRepo:         ticketRepo,
-		ticketCategoryRepo: ticketCategoryRepo,
+		slaRepo:                 slaRepo,
+		ticketSLARepo:           ticketSLARepo,
+		ticketRepo:              ticketRepo,
+		ticketCategoryRepo:      ticketCategoryRepo,
+		ticketService:           ticketService,
+		eventBus:                eventBus,
+		businessCalendarService: businessCalendarService,
 	}
 }
 
@@ -76,12 +90,17 @@ func (s *slaService) Create(req dto.CreateSLARequest, createdByID uint) (*dto.SL
 		Description:    req.Description,
 		TicketCategory: req.TicketCategory,
 		Priority:       req.Priority,
+		SupportLevel:   req.SupportLevel,
 		TargetTime:     req.TargetTime,
 		Unit:           unit,
 		IsActive:       isActive,
 		CreatedByID:    createdByIDPtr,
 	}
 
+	if err := s.checkRuleConflict(sla); err != nil {
+		return nil, err
+	}
+
 	if err := s.slaRepo.Create(sla); err != nil {
 		return nil, errors.New("erreur lors de la création du SLA")
 	}
@@ -100,7 +119,7 @@ func (s *slaService) Create(req dto.CreateSLARequest, createdByID uint) (*dto.SL
 func (s *slaService) GetByID(id uint) (*dto.SLADTO, error) {
 	sla, err := s.slaRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("SLA introuvable")
+		return nil, utils.ErrSLANotFound
 	}
 
 	slaDTO := s.slaToDTO(sla)
@@ -156,7 +175,7 @@ func (s *slaService) GetByCategory(category string) ([]dto.SLADTO, error) {
 func (s *slaService) Update(id uint, req dto.UpdateSLARequest, updatedByID uint) (*dto.SLADTO, error) {
 	sla, err := s.slaRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("SLA introuvable")
+		return nil, utils.ErrSLANotFound
 	}
 
 	// Mettre à jour les champs fournis
@@ -175,6 +194,17 @@ func (s *slaService) Update(id uint, req dto.UpdateSLARequest, updatedByID uint)
 	if req.IsActive != nil {
 		sla.IsActive = *req.IsActive
 	}
+	if req.SupportLevel != nil {
+		if *req.SupportLevel == "none" {
+			sla.SupportLevel = nil
+		} else {
+			sla.SupportLevel = req.SupportLevel
+		}
+	}
+
+	if err := s.checkRuleConflict(sla); err != nil {
+		return nil, err
+	}
 
 	if err := s.slaRepo.Update(sla); err != nil {
 		return nil, errors.New("erreur lors de la mise à jour du SLA")
@@ -190,11 +220,31 @@ func (s *slaService) Update(id uint, req dto.UpdateSLARequest, updatedByID uint)
 	return &slaDTO, nil
 }
 
+// checkRuleConflict refuse un SLA actif couvrant la même catégorie, priorité et niveau de support qu'un autre SLA actif
+// (un seul SLA serait appliqué aux tickets concernés)
+func (s *slaService) checkRuleConflict(sla *models.SLA) error {
+	if !sla.IsActive {
+		return nil
+	}
+	priority, supportLevel := "", ""
+	if sla.Priority != nil {
+		priority = *sla.Priority
+	}
+	if sla.SupportLevel != nil {
+		supportLevel = *sla.SupportLevel
+	}
+	existing, err := s.slaRepo.FindByCategoryAndPriority(sla.TicketCategory, priority, supportLevel)
+	if err == nil && existing.ID != sla.ID {
+		return utils.ErrSLARuleConflict.WithDetails(map[string]any{"conflicting_sla_id": existing.ID, "conflicting_sla_name": existing.Name})
+	}
+	return nil
+}
+
 // Delete supprime un SLA
 func (s *slaService) Delete(id uint) error {
 	_, err := s.slaRepo.FindByID(id)
 	if err != nil {
-		return errors.New("SLA introuvable")
+		return utils.ErrSLANotFound
 	}
 
 	if err := s.slaRepo.Delete(id); err != nil {
@@ -209,7 +259,7 @@ func (s *slaService) GetTicketSLAStatus(ticketID uint) (*dto.TicketSLAStatusDTO,
 	// Récupérer le ticket
 	ticket, err := s.ticketRepo.FindByID(ticketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	// Récupérer l'association ticket-SLA
@@ -218,14 +268,16 @@ func (s *slaService) GetTicketSLAStatus(ticketID uint) (*dto.TicketSLAStatusDTO,
 		return nil, errors.New("aucun SLA associé à ce ticket")
 	}
 
-	// Calculer le temps écoulé
-	elapsedTime := int(time.Since(ticket.CreatedAt).Minutes())
-	remaining := int(time.Until(ticketSLA.TargetTime).Minutes())
+	// Calculer le temps écoulé et restant (en minutes ouvrées si un calendrier s'applique)
+	calendar := s.businessCalendarService.CalendarFor(ticket.FilialeID)
+	now := time.Now()
+	elapsedTime := int(calendar.Duration(ticket.CreatedAt, now).Minutes())
+	remaining := int(calendar.Duration(now, ticketSLA.TargetTime).Minutes())
 
 	// Déterminer le statut
 	status := "on_time"
 	var violatedAt *time.Time
-	if remaining < 0 {
+	if now.After(ticketSLA.TargetTime) {
 		status = "violated"
 		violatedAt = &ticketSLA.TargetTime
 	} else if remaining < (elapsedTime / 4) {
@@ -249,7 +301,7 @@ func (s *slaService) GetTicketSLAStatus(ticketID uint) (*dto.TicketSLAStatusDTO,
 func (s *slaService) GetCompliance(slaID uint) (*dto.SLAComplianceDTO, error) {
 	sla, err := s.slaRepo.FindByID(slaID)
 	if err != nil {
-		return nil, errors.New("SLA introuvable")
+		return nil, utils.ErrSLANotFound
 	}
 
 	// Récupérer tous les tickets SLA associés à ce SLA
@@ -444,11 +496,10 @@ func (s *slaService) GetComplianceReport(period, format string) (*dto.SLAComplia
 	}, nil
 }
 
-// RecalculateSLAStatuses recalcule les statuts SLA pour tous les tickets ouverts
+// RecalculateSLAStatuses recalcule les statuts SLA des tickets ouverts (exécuté périodiquement par le planificateur)
+// Chaque franchissement de seuil (à risque, violé) est publié sur le bus d'événements
 func (s *slaService) RecalculateSLAStatuses() (int, error) {
-	// Récupérer tous les tickets SLA
-	// Note: RecalculateSLAStatuses est utilisé en interne, donc on passe nil pour le scope
-	allTicketSLAs, err := s.ticketSLARepo.FindAll(nil)
+	ticketSLAs, err := s.ticketSLARepo.FindPending()
 	if err != nil {
 		return 0, errors.New("erreur lors de la récupération des tickets SLA")
 	}
@@ -456,52 +507,87 @@ func (s *slaService) RecalculateSLAStatuses() (int, error) {
 	updatedCount := 0
 	now := time.Now()
 
-	for _, ticketSLA := range allTicketSLAs {
-		// Récupérer le ticket associé
-		ticket, err := s.ticketRepo.FindByID(ticketSLA.TicketID)
-		if err != nil || ticket == nil {
-			continue
-		}
-
-		// Ne recalculer que pour les tickets non clôturés
-		if ticket.Status == "cloture" {
+	for i := range ticketSLAs {
+		ticketSLA := &ticketSLAs[i]
+		oldStatus := ticketSLA.Status
+		calendar := s.businessCalendarService.CalendarFor(ticketSLA.Ticket.FilialeID)
+		newStatus := ticketSLAStatusAt(calendar, ticketSLA, ticketSLA.Ticket.CreatedAt, now)
+		if newStatus == oldStatus {
 			continue
 		}
 
-		// Recalculer le statut
-		oldStatus := ticketSLA.Status
-		newStatus := "on_time"
-
-		if now.After(ticketSLA.TargetTime) {
-			newStatus = "violated"
-			// Calculer le temps de violation en minutes
-			violationMinutes := int(now.Sub(ticketSLA.TargetTime).Minutes())
+		ticketSLA.Status = newStatus
+		if newStatus == "violated" {
+			violationMinutes := int(calendar.Duration(ticketSLA.TargetTime, now).Minutes())
 			ticketSLA.ViolationTime = &violationMinutes
-		} else {
-			// Vérifier si on est à risque (moins de 25% du temps restant)
-			totalDuration := ticketSLA.TargetTime.Sub(ticket.CreatedAt)
-			if totalDuration > 0 {
-				remainingPercent := float64(ticketSLA.TargetTime.Sub(now)) / float64(totalDuration)
-				if remainingPercent < 0.25 {
-					newStatus = "at_risk"
-				}
-			}
-			ticketSLA.ViolationTime = nil
 		}
+		if err := s.ticketSLARepo.Update(ticketSLA); err != nil {
+			log.Printf("Erreur lors de la mise à jour du SLA du ticket %d: %v", ticketSLA.TicketID, err)
+			continue
+		}
+		updatedCount++
 
-		// Mettre à jour seulement si le statut a changé
-		if newStatus != oldStatus {
-			ticketSLA.Status = newStatus
-			if err := s.ticketSLARepo.Update(&ticketSLA); err != nil {
-				continue // Ignorer les erreurs individuelles
-			}
-			updatedCount++
+		switch newStatus {
+		case "at_risk":
+			s.publishThreshold(events.SLAAtRisk, ticketSLA, nil)
+		case "violated":
+			s.publishThreshold(events.SLAViolated, ticketSLA, map[string]any{"violation_minutes": ticketSLA.ViolationTime})
 		}
 	}
 
 	return updatedCount, nil
 }
 
+// slaTargetTime calcule l'échéance d'un SLA à partir de start, en temps ouvré selon le calendrier (nil = temps réel)
+func slaTargetTime(calendar *businesstime.Calendar, start time.Time, targetTime int, unit string) time.Time {
+	switch unit {
+	case "hours":
+		return calendar.Add(start, time.Duration(targetTime)*time.Hour)
+	case "days":
+		return calendar.AddDays(start, targetTime)
+	default:
+		// Par défaut, traiter comme minutes
+		return calendar.Add(start, time.Duration(targetTime)*time.Minute)
+	}
+}
+
+// ticketSLAStatusAt calcule le statut SLA à l'instant now : violé après l'échéance, à risque quand il reste moins de 25% du délai ouvré
+func ticketSLAStatusAt(calendar *businesstime.Calendar, ticketSLA *models.TicketSLA, createdAt time.Time, now time.Time) string {
+	if now.After(ticketSLA.TargetTime) {
+		return "violated"
+	}
+	totalDuration := calendar.Duration(createdAt, ticketSLA.TargetTime)
+	if totalDuration > 0 && float64(calendar.Duration(now, ticketSLA.TargetTime))/float64(totalDuration) < 0.25 {
+		return "at_risk"
+	}
+	return "on_time"
+}
+
+// publishThreshold publie le franchissement d'un seuil SLA (à risque, violé) d'un ticket
+func (s *slaService) publishThreshold(eventType string, ticketSLA *models.TicketSLA, metadata map[string]any) {
+	if s.eventBus == nil || s.ticketService == nil {
+		return
+	}
+	ticketDTO, err := s.ticketService.GetByID(ticketSLA.TicketID, false)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération du ticket %d (%s): %v", ticketSLA.TicketID, eventType, err)
+		return
+	}
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["sla_id"] = ticketSLA.SLAID
+	metadata["target_time"] = ticketSLA.TargetTime
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       eventType,
+		FilialeID:  ticketSLA.Ticket.FilialeID,
+		EntityType: "tickets",
+		EntityID:   ticketSLA.TicketID,
+		Data:       *ticketDTO,
+		Metadata:   metadata,
+	})
+}
+
 // slaToDTO convertit un modèle SLA en DTO
 func (s *slaService) slaToDTO(sla *models.SLA) dto.SLADTO {
 	return dto.SLADTO{
@@ -510,6 +596,7 @@ func (s *slaService) slaToDTO(sla *models.SLA) dto.SLADTO {
 		Description:    sla.Description,
 		TicketCategory: sla.TicketCategory,
 		Priority:       sla.Priority,
+		SupportLevel:   sla.SupportLevel,
 		TargetTime:     sla.TargetTime,
 		Unit:           sla.Unit,
 		IsActive:       sla.IsActive,
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// SoftwareEnvironmentService interface pour la gestion des environnements des logiciels par filiale
+type SoftwareEnvironmentService interface {
+	GetByFiliale(filialeID uint, softwareID *uint, activeOnly bool) ([]dto.SoftwareEnvironmentDTO, error)
+	GetByID(id uint) (*dto.SoftwareEnvironmentDTO, error)
+	Create(filialeID uint, req dto.CreateSoftwareEnvironmentRequest) (*dto.SoftwareEnvironmentDTO, error)
+	Update(id uint, req dto.UpdateSoftwareEnvironmentRequest) (*dto.SoftwareEnvironmentDTO, error)
+	Delete(id uint) error
+}
+
+// softwareEnvironmentService implémente SoftwareEnvironmentService
+type softwareEnvironmentService struct {
+	environmentRepo repositories.SoftwareEnvironmentRepository
+	filialeRepo     repositories.FilialeRepository
+	softwareRepo    repositories.SoftwareRepository
+	deploymentRepo  repositories.FilialeSoftwareRepository
+}
+
+// NewSoftwareEnvironmentService crée une nouvelle instance de SoftwareEnvironmentService
+func NewSoftwareEnvironmentService(
+	environmentRepo repositories.SoftwareEnvironmentRepository,
+	filialeRepo repositories.FilialeRepository,
+	softwareRepo repositories.SoftwareRepository,
+	deploymentRepo repositories.FilialeSoftwareRepository,
+) SoftwareEnvironmentService {
+	return &softwareEnvironmentService{
+		environmentRepo: environmentRepo,
+		filialeRepo:     filialeRepo,
+		softwareRepo:    softwareRepo,
+		deploymentRepo:  deploymentRepo,
+	}
+}
+
+// GetByFiliale récupère les environnements d'une filiale (éventuellement d'un seul logiciel)
+func (s *softwareEnvironmentService) GetByFiliale(filialeID uint, softwareID *uint, activeOnly bool) ([]dto.SoftwareEnvironmentDTO, error) {
+	if _, err := s.filialeRepo.FindByID(filialeID); err != nil {
+		return nil, utils.ErrFilialeNotFound
+	}
+	environments, err := s.environmentRepo.FindByFiliale(filialeID, softwareID, activeOnly)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des environnements")
+	}
+
+	environmentDTOs := make([]dto.SoftwareEnvironmentDTO, 0, len(environments))
+	for i := range environments {
+		environmentDTOs = append(environmentDTOs, softwareEnvironmentToDTO(&environments[i]))
+	}
+	return environmentDTOs, nil
+}
+
+// GetByID récupère un environnement par son ID
+func (s *softwareEnvironmentService) GetByID(id uint) (*dto.SoftwareEnvironmentDTO, error) {
+	environment, err := s.environmentRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("environnement introuvable")
+	}
+	environmentDTO := softwareEnvironmentToDTO(environment)
+	return &environmentDTO, nil
+}
+
+// Create déclare un environnement d'un logiciel chez une filiale
+// Sans version fournie, la version du déploiement en service dans cet environnement est reprise
+func (s *softwareEnvironmentService) Create(filialeID uint, req dto.CreateSoftwareEnvironmentRequest) (*dto.SoftwareEnvironmentDTO, error) {
+	if _, err := s.filialeRepo.FindByID(filialeID); err != nil {
+		return nil, utils.ErrFilialeNotFound
+	}
+	if _, err := s.softwareRepo.FindByID(req.SoftwareID); err != nil {
+		return nil, utils.ErrSoftwareNotFound
+	}
+	if _, err := s.environmentRepo.FindByFilialeSoftwareAndName(filialeID, req.SoftwareID, req.Name); err == nil {
+		return nil, errors.New("cet environnement existe déjà pour ce logiciel")
+	}
+
+	version := req.Version
+	if version == "" {
+		deployments, err := s.deploymentRepo.FindActiveByFiliale(filialeID)
+		if err != nil {
+			return nil, errors.New("erreur lors de la récupération des déploiements")
+		}
+		for _, deployment := range deployments {
+			if deployment.SoftwareID == req.SoftwareID && deployment.Environment == req.Name && deployment.Status == models.DeploymentStatusDone {
+				version = deployment.Version
+				break
+			}
+		}
+	}
+
+	environment := &models.SoftwareEnvironment{
+		FilialeID:  filialeID,
+		SoftwareID: req.SoftwareID,
+		Name:       req.Name,
+		Label:      req.Label,
+		URL:        req.URL,
+		Version:    version,
+		Notes:      req.Notes,
+		IsActive:   true,
+	}
+	if err := s.environmentRepo.Create(environment); err != nil {
+		return nil, errors.New("erreur lors de la création de l'environnement")
+	}
+	return s.GetByID(environment.ID)
+}
+
+// Update met à jour un environnement
+func (s *softwareEnvironmentService) Update(id uint, req dto.UpdateSoftwareEnvironmentRequest) (*dto.SoftwareEnvironmentDTO, error) {
+	environment, err := s.environmentRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("environnement introuvable")
+	}
+
+	if req.Label != nil {
+		environment.Label = *req.Label
+	}
+	if req.URL != nil {
+		environment.URL = *req.URL
+	}
+	if req.Version != nil {
+		environment.Version = *req.Version
+	}
+	if req.Notes != nil {
+		environment.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		environment.IsActive = *req.IsActive
+	}
+
+	if err := s.environmentRepo.Update(environment); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour de l'environnement")
+	}
+	return s.GetByID(id)
+}
+
+// Delete supprime un environnement ; les tickets qui le référençaient n'ont plus d'environnement
+func (s *softwareEnvironmentService) Delete(id uint) error {
+	if _, err := s.environmentRepo.FindByID(id); err != nil {
+		return errors.New("environnement introuvable")
+	}
+	if err := s.environmentRepo.Delete(id); err != nil {
+		return errors.New("erreur lors de la suppression de l'environnement")
+	}
+	return nil
+}
+
+// syncEnvironmentVersion reporte la version d'un déploiement en service sur l'environnement correspondant
+func syncEnvironmentVersion(tx *gorm.DB, deployment *models.FilialeSoftware) error {
+	return tx.Model(&models.SoftwareEnvironment{}).
+		Where("filiale_id = ? AND software_id = ? AND name = ?", deployment.FilialeID, deployment.SoftwareID, deployment.Environment).
+		Update("version", deployment.Version).Error
+}
+
+// softwareEnvironmentToDTO convertit un modèle SoftwareEnvironment en DTO
+func softwareEnvironmentToDTO(environment *models.SoftwareEnvironment) dto.SoftwareEnvironmentDTO {
+	environmentDTO := dto.SoftwareEnvironmentDTO{
+		ID:         environment.ID,
+		FilialeID:  environment.FilialeID,
+		SoftwareID: environment.SoftwareID,
+		Name:       environment.Name,
+		Label:      environment.Label,
+		URL:        environment.URL,
+		Version:    environment.Version,
+		Notes:      environment.Notes,
+		IsActive:   environment.IsActive,
+		CreatedAt:  environment.CreatedAt,
+		UpdatedAt:  environment.UpdatedAt,
+	}
+	if environment.Software != nil && environment.Software.ID != 0 {
+		environmentDTO.Software = &dto.SoftwareDTO{
+			ID:      environment.Software.ID,
+			Code:    environment.Software.Code,
+			Name:    environment.Software.Name,
+			Version: environment.Software.Version,
+		}
+	}
+	return environmentDTO
+}
+
+// environmentSummaryToDTO convertit l'environnement d'un ticket en résumé
+func environmentSummaryToDTO(environment *models.SoftwareEnvironment) *dto.EnvironmentSummaryDTO {
+	if environment == nil || environment.ID == 0 {
+		return nil
+	}
+	return &dto.EnvironmentSummaryDTO{
+		ID:      environment.ID,
+		Name:    environment.Name,
+		Label:   environment.Label,
+		URL:     environment.URL,
+		Version: environment.Version,
+	}
+}
@@ -0,0 +1,465 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// Statuts de conformité d'un logiciel dans une filiale
+const (
+	LicenseCompliant    = "compliant"
+	LicenseOverDeployed = "over_deployed"
+	LicenseUnlicensed   = "unlicensed"
+)
+
+// SoftwareLicenseService interface pour la gestion des licences logicielles et de leur conformité
+type SoftwareLicenseService interface {
+	GetAll(filialeID, softwareID *uint, activeOnly bool, expiryWithinDays *int) ([]dto.SoftwareLicenseDTO, error)
+	GetByID(id uint) (*dto.SoftwareLicenseDTO, error)
+	Create(req dto.CreateSoftwareLicenseRequest, userID uint) (*dto.SoftwareLicenseDTO, error)
+	Update(id uint, req dto.UpdateSoftwareLicenseRequest) (*dto.SoftwareLicenseDTO, error)
+	Delete(id uint) error
+	GetComplianceReport(filialeID *uint, expiryWithinDays int) (*dto.LicenseComplianceReportDTO, error)
+	NotifyExpiring(withinDays int) (int, error) // Alerte les gestionnaires de licences de la filiale ; retourne le nombre de licences signalées
+}
+
+// softwareLicenseService implémente SoftwareLicenseService
+type softwareLicenseService struct {
+	licenseRepo         repositories.SoftwareLicenseRepository
+	softwareRepo        repositories.SoftwareRepository
+	filialeRepo         repositories.FilialeRepository
+	userRepo            repositories.UserRepository
+	notificationService NotificationService
+}
+
+// NewSoftwareLicenseService crée une nouvelle instance de SoftwareLicenseService
+func NewSoftwareLicenseService(
+	licenseRepo repositories.SoftwareLicenseRepository,
+	softwareRepo repositories.SoftwareRepository,
+	filialeRepo repositories.FilialeRepository,
+	userRepo repositories.UserRepository,
+	notificationService NotificationService,
+) SoftwareLicenseService {
+	return &softwareLicenseService{
+		licenseRepo:         licenseRepo,
+		softwareRepo:        softwareRepo,
+		filialeRepo:         filialeRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// GetAll récupère les licences ; expiryWithinDays limite aux licences actives expirant dans ce nombre de jours (ou expirées)
+func (s *softwareLicenseService) GetAll(filialeID, softwareID *uint, activeOnly bool, expiryWithinDays *int) ([]dto.SoftwareLicenseDTO, error) {
+	var expiryBefore *time.Time
+	if expiryWithinDays != nil {
+		limit := time.Now().AddDate(0, 0, *expiryWithinDays)
+		expiryBefore = &limit
+	}
+	licenses, err := s.licenseRepo.FindAll(filialeID, softwareID, activeOnly || expiryWithinDays != nil, expiryBefore)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des licences")
+	}
+
+	now := time.Now()
+	licenseDTOs := make([]dto.SoftwareLicenseDTO, 0, len(licenses))
+	for i := range licenses {
+		licenseDTOs = append(licenseDTOs, softwareLicenseToDTO(&licenses[i], now))
+	}
+	return licenseDTOs, nil
+}
+
+// GetByID récupère une licence par son ID
+func (s *softwareLicenseService) GetByID(id uint) (*dto.SoftwareLicenseDTO, error) {
+	license, err := s.licenseRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrLicenseNotFound
+	}
+	licenseDTO := softwareLicenseToDTO(license, time.Now())
+	return &licenseDTO, nil
+}
+
+// Create crée une licence logicielle
+func (s *softwareLicenseService) Create(req dto.CreateSoftwareLicenseRequest, userID uint) (*dto.SoftwareLicenseDTO, error) {
+	if _, err := s.softwareRepo.FindByID(req.SoftwareID); err != nil {
+		return nil, utils.ErrSoftwareNotFound
+	}
+	if _, err := s.filialeRepo.FindByID(req.FilialeID); err != nil {
+		return nil, utils.ErrFilialeNotFound
+	}
+
+	license := &models.SoftwareLicense{
+		SoftwareID:  req.SoftwareID,
+		FilialeID:   req.FilialeID,
+		Reference:   strings.TrimSpace(req.Reference),
+		LicenseKey:  strings.TrimSpace(req.LicenseKey),
+		Seats:       req.Seats,
+		Notes:       req.Notes,
+		IsActive:    true,
+		CreatedByID: userID,
+	}
+	var err error
+	if license.StartDate, err = parseContractDate(req.StartDate, "début"); err != nil {
+		return nil, err
+	}
+	if license.ExpiryDate, err = parseContractDate(req.ExpiryDate, "expiration"); err != nil {
+		return nil, err
+	}
+	if err := validateLicensePeriod(license); err != nil {
+		return nil, err
+	}
+
+	if err := s.licenseRepo.Create(license); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la licence")
+	}
+	return s.GetByID(license.ID)
+}
+
+// Update met à jour une licence logicielle
+func (s *softwareLicenseService) Update(id uint, req dto.UpdateSoftwareLicenseRequest) (*dto.SoftwareLicenseDTO, error) {
+	license, err := s.licenseRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrLicenseNotFound
+	}
+
+	if req.Reference != nil {
+		license.Reference = strings.TrimSpace(*req.Reference)
+	}
+	if req.LicenseKey != nil {
+		license.LicenseKey = strings.TrimSpace(*req.LicenseKey)
+	}
+	if req.UnlimitedSeats {
+		license.Seats = nil
+	} else if req.Seats != nil {
+		license.Seats = req.Seats
+	}
+	if req.StartDate != nil {
+		if license.StartDate, err = parseContractDate(*req.StartDate, "début"); err != nil {
+			return nil, err
+		}
+	}
+	if req.ExpiryDate != nil {
+		previous := license.ExpiryDate
+		if license.ExpiryDate, err = parseContractDate(*req.ExpiryDate, "expiration"); err != nil {
+			return nil, err
+		}
+		// Nouvelle échéance (renouvellement) : l'alerte d'expiration sera de nouveau envoyée
+		if !sameDay(previous, license.ExpiryDate) {
+			license.ExpiryNotifiedAt = nil
+		}
+	}
+	if err := validateLicensePeriod(license); err != nil {
+		return nil, err
+	}
+	if req.Notes != nil {
+		license.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		license.IsActive = *req.IsActive
+	}
+
+	if err := s.licenseRepo.Update(license); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la licence")
+	}
+	return s.GetByID(id)
+}
+
+// Delete supprime une licence logicielle
+func (s *softwareLicenseService) Delete(id uint) error {
+	if _, err := s.licenseRepo.FindByID(id); err != nil {
+		return utils.ErrLicenseNotFound
+	}
+	if err := s.licenseRepo.Delete(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de la licence")
+	}
+	return nil
+}
+
+// GetComplianceReport compare, par filiale et logiciel du catalogue, les postes couverts par les licences en vigueur
+// aux installations inventoriées sur les actifs en service ; filialeID limite le rapport à une filiale
+func (s *softwareLicenseService) GetComplianceReport(filialeID *uint, expiryWithinDays int) (*dto.LicenseComplianceReportDTO, error) {
+	if expiryWithinDays < 0 || expiryWithinDays > 365 {
+		expiryWithinDays = 30
+	}
+	catalog, err := s.softwareRepo.FindAll()
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des logiciels")
+	}
+	licenses, err := s.licenseRepo.FindAll(filialeID, nil, true, nil)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des licences")
+	}
+	installations, err := s.licenseRepo.CountInstallations(filialeID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors du comptage des installations")
+	}
+
+	now := time.Now()
+	report := &dto.LicenseComplianceReportDTO{
+		GeneratedAt:      now,
+		ExpiryWithinDays: expiryWithinDays,
+		Entries:          []dto.LicenseComplianceEntryDTO{},
+		Expiring:         []dto.SoftwareLicenseDTO{},
+	}
+
+	type entryKey struct {
+		filialeID  uint // 0 : actifs sans filiale
+		softwareID uint
+	}
+	entries := make(map[entryKey]*dto.LicenseComplianceEntryDTO)
+	filialeNames := make(map[uint]string)
+	softwareByID := make(map[uint]*models.Software, len(catalog))
+	for i := range catalog {
+		softwareByID[catalog[i].ID] = &catalog[i]
+	}
+	entryFor := func(filiale *uint, software *models.Software) *dto.LicenseComplianceEntryDTO {
+		key := entryKey{softwareID: software.ID}
+		if filiale != nil {
+			key.filialeID = *filiale
+		}
+		if entry, ok := entries[key]; ok {
+			return entry
+		}
+		entry := &dto.LicenseComplianceEntryDTO{
+			FilialeID:       filiale,
+			SoftwareID:      software.ID,
+			SoftwareCode:    software.Code,
+			SoftwareName:    software.Name,
+			SoftwareVersion: software.Version,
+		}
+		entries[key] = entry
+		return entry
+	}
+
+	// Droits : licences en vigueur
+	expiryLimit := now.AddDate(0, 0, expiryWithinDays)
+	for i := range licenses {
+		license := &licenses[i]
+		if license.Filiale != nil && license.Filiale.ID != 0 {
+			filialeNames[license.FilialeID] = license.Filiale.Name
+		}
+		if license.ExpiryDate != nil && !license.ExpiryDate.After(expiryLimit) {
+			report.Expiring = append(report.Expiring, softwareLicenseToDTO(license, now))
+		}
+		software, ok := softwareByID[license.SoftwareID]
+		if !ok || !license.InEffect(now) {
+			continue
+		}
+		filiale := license.FilialeID
+		entry := entryFor(&filiale, software)
+		entry.Licenses++
+		if license.Seats == nil {
+			entry.Unlimited = true
+		} else {
+			seats := *license.Seats
+			if entry.EntitledSeats != nil {
+				seats += *entry.EntitledSeats
+			}
+			entry.EntitledSeats = &seats
+		}
+	}
+
+	// Installations inventoriées rapprochées du catalogue (nom ou code, version si renseignée)
+	for _, installation := range installations {
+		for i := range catalog {
+			if softwareMatchesInstallation(&catalog[i], installation.SoftwareName, installation.Version) {
+				entryFor(installation.FilialeID, &catalog[i]).Installations += int(installation.Installations)
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.Licenses == 0 && entry.Installations == 0 {
+			continue
+		}
+		if entry.Unlimited {
+			entry.EntitledSeats = nil
+		} else if entry.EntitledSeats != nil {
+			available := *entry.EntitledSeats - entry.Installations
+			entry.AvailableSeats = &available
+		}
+		switch {
+		case entry.Licenses == 0:
+			entry.Status = LicenseUnlicensed
+			report.Summary.Unlicensed++
+		case entry.AvailableSeats != nil && *entry.AvailableSeats < 0:
+			entry.Status = LicenseOverDeployed
+			report.Summary.OverDeployed++
+		default:
+			entry.Status = LicenseCompliant
+			report.Summary.Compliant++
+		}
+		if entry.FilialeID != nil {
+			entry.FilialeName = s.filialeName(*entry.FilialeID, filialeNames)
+		}
+		report.Entries = append(report.Entries, *entry)
+	}
+	report.Summary.Expiring = len(report.Expiring)
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.FilialeName != b.FilialeName {
+			return a.FilialeName < b.FilialeName
+		}
+		if a.SoftwareName != b.SoftwareName {
+			return a.SoftwareName < b.SoftwareName
+		}
+		return a.SoftwareVersion < b.SoftwareVersion
+	})
+	return report, nil
+}
+
+// NotifyExpiring alerte les gestionnaires de licences (software_licenses.manage) de la filiale titulaire
+// des licences expirant dans les withinDays prochains jours ou déjà expirées ; chaque échéance n'est signalée qu'une fois
+func (s *softwareLicenseService) NotifyExpiring(withinDays int) (int, error) {
+	now := time.Now()
+	licenses, err := s.licenseRepo.FindExpiringUnnotified(now.AddDate(0, 0, withinDays))
+	if err != nil {
+		return 0, fmt.Errorf("licences arrivant à échéance: %w", err)
+	}
+
+	notified := 0
+	for i := range licenses {
+		license := &licenses[i]
+		filialeID := license.FilialeID
+		recipients, err := s.userRepo.FindActiveByPermission("software_licenses.manage", &filialeID)
+		if err != nil {
+			log.Printf("Erreur lors de la recherche des gestionnaires de la licence %d: %v", license.ID, err)
+			continue
+		}
+
+		title, message := licenseExpiryMessage(license, now)
+		linkURL := fmt.Sprintf("/software-licenses/%d", license.ID)
+		metadata := map[string]any{
+			"license_id":  license.ID,
+			"software_id": license.SoftwareID,
+			"filiale_id":  license.FilialeID,
+			"expiry_date": license.ExpiryDate.Format("2006-01-02"),
+		}
+		for _, recipient := range recipients {
+			if err := s.notificationService.Create(recipient.ID, "software_license_expiring", title, message, linkURL, metadata); err != nil {
+				log.Printf("Erreur lors de la notification d'expiration de la licence %d à l'utilisateur %d: %v", license.ID, recipient.ID, err)
+			}
+		}
+
+		if err := s.licenseRepo.MarkExpiryNotified(license.ID, now); err != nil {
+			log.Printf("Erreur lors de l'enregistrement de l'alerte d'expiration de la licence %d: %v", license.ID, err)
+			continue
+		}
+		notified++
+	}
+	return notified, nil
+}
+
+// filialeName retourne le nom d'une filiale (mis en cache dans names)
+func (s *softwareLicenseService) filialeName(filialeID uint, names map[uint]string) string {
+	if name, ok := names[filialeID]; ok {
+		return name
+	}
+	name := ""
+	if filiale, err := s.filialeRepo.FindByID(filialeID); err == nil {
+		name = filiale.Name
+	}
+	names[filialeID] = name
+	return name
+}
+
+// licenseExpiryMessage construit le titre et le message de l'alerte d'expiration d'une licence
+func licenseExpiryMessage(license *models.SoftwareLicense, now time.Time) (string, string) {
+	software := fmt.Sprintf("#%d", license.SoftwareID)
+	if license.Software != nil && license.Software.ID != 0 {
+		software = strings.TrimSpace(license.Software.Name + " " + license.Software.Version)
+	}
+	filiale := ""
+	if license.Filiale != nil && license.Filiale.ID != 0 {
+		filiale = fmt.Sprintf(" (%s)", license.Filiale.Name)
+	}
+	reference := ""
+	if license.Reference != "" {
+		reference = " " + license.Reference
+	}
+	expiry := license.ExpiryDate.Format("02/01/2006")
+	if license.ExpiryDate.Format("2006-01-02") < now.Format("2006-01-02") {
+		return "Licence logicielle expirée",
+			fmt.Sprintf("La licence%s du logiciel %s%s a expiré le %s.", reference, software, filiale, expiry)
+	}
+	return "Licence logicielle bientôt expirée",
+		fmt.Sprintf("La licence%s du logiciel %s%s expire le %s (dans %d jour(s)).", reference, software, filiale, expiry, daysUntil(*license.ExpiryDate, now))
+}
+
+// softwareMatchesInstallation indique si une installation inventoriée correspond à un logiciel du catalogue :
+// nom ou code identique (sans tenir compte de la casse), version comparée par préfixe lorsque les deux sont renseignées
+func softwareMatchesInstallation(software *models.Software, name, version string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name != strings.ToLower(strings.TrimSpace(software.Name)) && name != strings.ToLower(strings.TrimSpace(software.Code)) {
+		return false
+	}
+	if software.Version == "" || version == "" {
+		return true
+	}
+	return strings.HasPrefix(strings.ToLower(version), strings.ToLower(software.Version))
+}
+
+// validateLicensePeriod vérifie la cohérence des dates d'une licence
+func validateLicensePeriod(license *models.SoftwareLicense) error {
+	if license.StartDate != nil && license.ExpiryDate != nil && license.ExpiryDate.Before(*license.StartDate) {
+		return errors.New("la date d'expiration doit être postérieure à la date de début")
+	}
+	return nil
+}
+
+// sameDay indique si deux dates optionnelles désignent le même jour
+func sameDay(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
+
+// daysUntil nombre de jours calendaires entre now et date (négatif si la date est passée)
+func daysUntil(date, now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(math.Round(day.Sub(today).Hours() / 24))
+}
+
+// softwareLicenseToDTO convertit une licence en DTO
+func softwareLicenseToDTO(license *models.SoftwareLicense, now time.Time) dto.SoftwareLicenseDTO {
+	licenseDTO := dto.SoftwareLicenseDTO{
+		ID:          license.ID,
+		SoftwareID:  license.SoftwareID,
+		FilialeID:   license.FilialeID,
+		Reference:   license.Reference,
+		LicenseKey:  license.LicenseKey,
+		Seats:       license.Seats,
+		StartDate:   license.StartDate,
+		ExpiryDate:  license.ExpiryDate,
+		Notes:       license.Notes,
+		IsActive:    license.IsActive,
+		InEffect:    license.InEffect(now),
+		CreatedByID: license.CreatedByID,
+		CreatedAt:   license.CreatedAt,
+		UpdatedAt:   license.UpdatedAt,
+	}
+	if license.ExpiryDate != nil {
+		days := daysUntil(*license.ExpiryDate, now)
+		licenseDTO.DaysToExpiry = &days
+	}
+	if license.Filiale != nil && license.Filiale.ID != 0 {
+		licenseDTO.Filiale = &dto.FilialeDTO{ID: license.Filiale.ID, Code: license.Filiale.Code, Name: license.Filiale.Name}
+	}
+	if license.Software != nil && license.Software.ID != 0 {
+		licenseDTO.Software = &dto.SoftwareDTO{ID: license.Software.ID, Code: license.Software.Code, Name: license.Software.Name, Version: license.Software.Version}
+	}
+	return licenseDTO
+}
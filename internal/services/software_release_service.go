@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SoftwareReleaseService interface pour la gestion des versions publiées des logiciels
+type SoftwareReleaseService interface {
+	GetBySoftwareID(softwareID uint) ([]dto.SoftwareReleaseDTO, error)
+	GetByID(id uint) (*dto.SoftwareReleaseDTO, error)
+	Create(softwareID uint, req dto.CreateSoftwareReleaseRequest, userID uint) (*dto.SoftwareReleaseDTO, error)
+	Update(id uint, req dto.UpdateSoftwareReleaseRequest) (*dto.SoftwareReleaseDTO, error)
+	Delete(id uint) error
+	Publish(id uint, userID uint) (*dto.SoftwareReleaseDTO, int, error)
+	GetFixedTickets(id uint) ([]dto.TicketDTO, error)
+	LinkTickets(id uint, req dto.LinkReleaseTicketsRequest) (*dto.ReleaseTicketsResultDTO, error)
+	UnlinkTicket(id, ticketID uint) error
+}
+
+// softwareReleaseService implémente SoftwareReleaseService
+type softwareReleaseService struct {
+	releaseRepo    repositories.SoftwareReleaseRepository
+	softwareRepo   repositories.SoftwareRepository
+	deploymentRepo repositories.FilialeSoftwareRepository
+	jobQueue       *jobs.Queue
+}
+
+// NewSoftwareReleaseService crée une nouvelle instance de SoftwareReleaseService
+func NewSoftwareReleaseService(
+	releaseRepo repositories.SoftwareReleaseRepository,
+	softwareRepo repositories.SoftwareRepository,
+	deploymentRepo repositories.FilialeSoftwareRepository,
+	jobQueue *jobs.Queue,
+) SoftwareReleaseService {
+	return &softwareReleaseService{
+		releaseRepo:    releaseRepo,
+		softwareRepo:   softwareRepo,
+		deploymentRepo: deploymentRepo,
+		jobQueue:       jobQueue,
+	}
+}
+
+// GetBySoftwareID récupère les versions d'un logiciel
+func (s *softwareReleaseService) GetBySoftwareID(softwareID uint) ([]dto.SoftwareReleaseDTO, error) {
+	if _, err := s.softwareRepo.FindByID(softwareID); err != nil {
+		return nil, utils.ErrSoftwareNotFound
+	}
+	releases, err := s.releaseRepo.FindBySoftwareID(softwareID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des versions")
+	}
+
+	ids := make([]uint, len(releases))
+	for i := range releases {
+		ids[i] = releases[i].ID
+	}
+	counts, err := s.releaseRepo.CountFixedTickets(ids)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des versions")
+	}
+
+	releaseDTOs := make([]dto.SoftwareReleaseDTO, 0, len(releases))
+	for i := range releases {
+		releaseDTOs = append(releaseDTOs, softwareReleaseToDTO(&releases[i], counts[releases[i].ID]))
+	}
+	return releaseDTOs, nil
+}
+
+// GetByID récupère une version
+func (s *softwareReleaseService) GetByID(id uint) (*dto.SoftwareReleaseDTO, error) {
+	release, err := s.releaseRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("version introuvable")
+	}
+	return s.releaseDTO(release)
+}
+
+// Create crée une version en préparation
+func (s *softwareReleaseService) Create(softwareID uint, req dto.CreateSoftwareReleaseRequest, userID uint) (*dto.SoftwareReleaseDTO, error) {
+	if _, err := s.softwareRepo.FindByID(softwareID); err != nil {
+		return nil, utils.ErrSoftwareNotFound
+	}
+	version := strings.TrimSpace(req.Version)
+	if version == "" {
+		return nil, errors.New("le numéro de version est requis")
+	}
+	if existing, _ := s.releaseRepo.FindBySoftwareAndVersion(softwareID, version); existing != nil {
+		return nil, errors.New("cette version existe déjà pour ce logiciel")
+	}
+
+	release := &models.SoftwareRelease{
+		SoftwareID:  softwareID,
+		Version:     version,
+		Title:       strings.TrimSpace(req.Title),
+		Changelog:   req.Changelog,
+		KnownIssues: req.KnownIssues,
+		ReleaseDate: req.ReleaseDate,
+		Status:      models.ReleaseStatusDraft,
+		CreatedByID: userID,
+	}
+	if err := s.releaseRepo.Create(release); err != nil {
+		return nil, errors.New("erreur lors de la création de la version")
+	}
+	releaseDTO := softwareReleaseToDTO(release, 0)
+	return &releaseDTO, nil
+}
+
+// Update met à jour une version ; le numéro n'est modifiable qu'avant la publication
+func (s *softwareReleaseService) Update(id uint, req dto.UpdateSoftwareReleaseRequest) (*dto.SoftwareReleaseDTO, error) {
+	release, err := s.releaseRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("version introuvable")
+	}
+
+	if req.Version != nil {
+		version := strings.TrimSpace(*req.Version)
+		if version != release.Version {
+			if release.Status == models.ReleaseStatusReleased {
+				return nil, errors.New("le numéro d'une version publiée n'est pas modifiable")
+			}
+			if existing, _ := s.releaseRepo.FindBySoftwareAndVersion(release.SoftwareID, version); existing != nil {
+				return nil, errors.New("cette version existe déjà pour ce logiciel")
+			}
+			release.Version = version
+		}
+	}
+	if req.Title != nil {
+		release.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Changelog != nil {
+		release.Changelog = *req.Changelog
+	}
+	if req.KnownIssues != nil {
+		release.KnownIssues = *req.KnownIssues
+	}
+	if req.ReleaseDate != nil {
+		release.ReleaseDate = req.ReleaseDate
+	}
+
+	if err := s.releaseRepo.Update(release); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour de la version")
+	}
+	return s.releaseDTO(release)
+}
+
+// Delete supprime une version en préparation (une version publiée est conservée pour l'historique des tickets)
+func (s *softwareReleaseService) Delete(id uint) error {
+	release, err := s.releaseRepo.FindByID(id)
+	if err != nil {
+		return errors.New("version introuvable")
+	}
+	if release.Status == models.ReleaseStatusReleased {
+		return errors.New("une version publiée ne peut pas être supprimée")
+	}
+	if err := s.releaseRepo.Delete(id); err != nil {
+		return errors.New("erreur lors de la suppression de la version")
+	}
+	return nil
+}
+
+// Publish publie une version et notifie les filiales concernées par les tickets ouverts qu'elle corrige
+// Retourne aussi le nombre d'utilisateurs notifiés
+func (s *softwareReleaseService) Publish(id uint, userID uint) (*dto.SoftwareReleaseDTO, int, error) {
+	release, err := s.releaseRepo.FindByID(id)
+	if err != nil {
+		return nil, 0, errors.New("version introuvable")
+	}
+	if release.Status == models.ReleaseStatusReleased {
+		return nil, 0, errors.New("cette version est déjà publiée")
+	}
+
+	now := time.Now()
+	release.Status = models.ReleaseStatusReleased
+	release.PublishedAt = &now
+	release.PublishedByID = &userID
+	if release.ReleaseDate == nil {
+		release.ReleaseDate = &now
+	}
+	if err := s.releaseRepo.Update(release); err != nil {
+		return nil, 0, errors.New("erreur lors de la publication de la version")
+	}
+
+	tickets, err := s.releaseRepo.FindFixedTickets(release.ID)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des tickets corrigés par la version %d: %v", release.ID, err)
+	}
+	notified := s.notifyAffectedFiliales(release, tickets)
+
+	releaseDTO, err := s.releaseDTO(release)
+	if err != nil {
+		return nil, 0, err
+	}
+	return releaseDTO, notified, nil
+}
+
+// GetFixedTickets récupère les tickets corrigés par une version
+func (s *softwareReleaseService) GetFixedTickets(id uint) ([]dto.TicketDTO, error) {
+	if _, err := s.releaseRepo.FindByID(id); err != nil {
+		return nil, errors.New("version introuvable")
+	}
+	tickets, err := s.releaseRepo.FindFixedTickets(id)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des tickets")
+	}
+
+	ticketDTOs := make([]dto.TicketDTO, 0, len(tickets))
+	for _, ticket := range tickets {
+		ticketDTO := dto.TicketDTO{
+			ID:               ticket.ID,
+			Code:             ticket.Code,
+			Title:            ticket.Title,
+			Category:         ticket.Category,
+			Status:           ticket.Status,
+			Priority:         ticket.Priority,
+			FilialeID:        ticket.FilialeID,
+			SoftwareID:       ticket.SoftwareID,
+			FixedInReleaseID: ticket.FixedInReleaseID,
+			CreatedAt:        ticket.CreatedAt,
+			UpdatedAt:        ticket.UpdatedAt,
+			ClosedAt:         ticket.ClosedAt,
+		}
+		if ticket.Filiale != nil {
+			ticketDTO.Filiale = &dto.FilialeDTO{ID: ticket.Filiale.ID, Code: ticket.Filiale.Code, Name: ticket.Filiale.Name}
+		}
+		ticketDTOs = append(ticketDTOs, ticketDTO)
+	}
+	return ticketDTOs, nil
+}
+
+// LinkTickets rattache à une version les tickets qu'elle corrige (tickets du même logiciel uniquement)
+// Si la version est déjà publiée, les filiales concernées sont notifiées immédiatement
+func (s *softwareReleaseService) LinkTickets(id uint, req dto.LinkReleaseTicketsRequest) (*dto.ReleaseTicketsResultDTO, error) {
+	release, err := s.releaseRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("version introuvable")
+	}
+
+	linked, err := s.releaseRepo.LinkTickets(release.ID, release.SoftwareID, req.TicketIDs)
+	if err != nil {
+		return nil, errors.New("erreur lors du rattachement des tickets")
+	}
+	result := &dto.ReleaseTicketsResultDTO{Linked: linked}
+	isLinked := make(map[uint]bool, len(linked))
+	for _, ticketID := range linked {
+		isLinked[ticketID] = true
+	}
+	for _, ticketID := range req.TicketIDs {
+		if !isLinked[ticketID] {
+			result.Rejected = append(result.Rejected, ticketID)
+		}
+	}
+
+	if release.Status == models.ReleaseStatusReleased && len(linked) > 0 {
+		tickets, err := s.releaseRepo.FindFixedTickets(release.ID)
+		if err != nil {
+			log.Printf("Erreur lors de la récupération des tickets corrigés par la version %d: %v", release.ID, err)
+		}
+		newlyLinked := make([]models.Ticket, 0, len(linked))
+		for _, ticket := range tickets {
+			if isLinked[ticket.ID] {
+				newlyLinked = append(newlyLinked, ticket)
+			}
+		}
+		result.Notified = s.notifyAffectedFiliales(release, newlyLinked)
+	}
+	return result, nil
+}
+
+// UnlinkTicket détache un ticket d'une version
+func (s *softwareReleaseService) UnlinkTicket(id, ticketID uint) error {
+	if _, err := s.releaseRepo.FindByID(id); err != nil {
+		return errors.New("version introuvable")
+	}
+	unlinked, err := s.releaseRepo.UnlinkTicket(id, ticketID)
+	if err != nil {
+		return errors.New("erreur lors du détachement du ticket")
+	}
+	if !unlinked {
+		return errors.New("ticket non rattaché à cette version")
+	}
+	return nil
+}
+
+// notifyAffectedFiliales notifie le demandeur (à défaut le créateur) de chaque ticket ouvert corrigé par la version,
+// sauf si sa filiale utilise déjà cette version ; retourne le nombre d'utilisateurs notifiés
+func (s *softwareReleaseService) notifyAffectedFiliales(release *models.SoftwareRelease, tickets []models.Ticket) int {
+	deployedVersions := make(map[uint]string) // filiale -> version en service
+	recipients := make(map[uint][]string)     // utilisateur -> codes des tickets corrigés
+	for _, ticket := range tickets {
+		if ticket.Status == "resolu" || ticket.Status == "cloture" || ticket.FilialeID == nil {
+			continue
+		}
+		filialeID := *ticket.FilialeID
+		version, known := deployedVersions[filialeID]
+		if !known {
+			if deployment, err := s.deploymentRepo.FindByFilialeAndSoftware(filialeID, release.SoftwareID); err == nil && deployment.IsActive {
+				version = deployment.Version
+			}
+			deployedVersions[filialeID] = version
+		}
+		if version == release.Version {
+			continue
+		}
+
+		recipientID := ticket.CreatedByID
+		if ticket.RequesterID != nil {
+			recipientID = *ticket.RequesterID
+		}
+		recipients[recipientID] = append(recipients[recipientID], ticket.Code)
+	}
+
+	for userID, codes := range recipients {
+		err := s.jobQueue.Enqueue(context.Background(), jobs.TypeNotifyUsers, jobs.NotifyUsersPayload{
+			UserIDs: []uint{userID},
+			Type:    "software_release_fix",
+			Title:   "Correctif disponible",
+			Message: fmt.Sprintf("La version %s corrige vos tickets : %s", release.Version, strings.Join(codes, ", ")),
+			LinkURL: fmt.Sprintf("/app/software/%d/releases/%d", release.SoftwareID, release.ID),
+			Metadata: map[string]any{
+				"release_id":  release.ID,
+				"software_id": release.SoftwareID,
+				"version":     release.Version,
+				"tickets":     codes,
+			},
+		})
+		if err != nil {
+			log.Printf("Erreur lors de la notification de l'utilisateur %d pour la version %d: %v", userID, release.ID, err)
+		}
+	}
+	return len(recipients)
+}
+
+// releaseDTO convertit une version en DTO avec le nombre de tickets corrigés
+func (s *softwareReleaseService) releaseDTO(release *models.SoftwareRelease) (*dto.SoftwareReleaseDTO, error) {
+	counts, err := s.releaseRepo.CountFixedTickets([]uint{release.ID})
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de la version")
+	}
+	releaseDTO := softwareReleaseToDTO(release, counts[release.ID])
+	return &releaseDTO, nil
+}
+
+// softwareReleaseToDTO convertit un modèle SoftwareRelease en DTO
+func softwareReleaseToDTO(release *models.SoftwareRelease, fixedTickets int) dto.SoftwareReleaseDTO {
+	return dto.SoftwareReleaseDTO{
+		ID:            release.ID,
+		SoftwareID:    release.SoftwareID,
+		Version:       release.Version,
+		Title:         release.Title,
+		Changelog:     release.Changelog,
+		KnownIssues:   release.KnownIssues,
+		ReleaseDate:   release.ReleaseDate,
+		Status:        release.Status,
+		PublishedAt:   release.PublishedAt,
+		PublishedByID: release.PublishedByID,
+		CreatedByID:   release.CreatedByID,
+		FixedTickets:  fixedTickets,
+		CreatedAt:     release.CreatedAt,
+		UpdatedAt:     release.UpdatedAt,
+	}
+}
+
+// releaseSummaryToDTO convertit la version corrective d'un ticket en DTO résumé (nil si absente)
+func releaseSummaryToDTO(release *models.SoftwareRelease) *dto.ReleaseSummaryDTO {
+	if release == nil || release.ID == 0 {
+		return nil
+	}
+	return &dto.ReleaseSummaryDTO{
+		ID:          release.ID,
+		Version:     release.Version,
+		Status:      release.Status,
+		ReleaseDate: release.ReleaseDate,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // SoftwareService interface pour les opérations sur les logiciels
@@ -66,7 +67,7 @@ func (s *softwareService) Create(req dto.CreateSoftwareRequest) (*dto.SoftwareDT
 func (s *softwareService) GetByID(id uint) (*dto.SoftwareDTO, error) {
 	software, err := s.softwareRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("logiciel introuvable")
+		return nil, utils.ErrSoftwareNotFound
 	}
 
 	return s.softwareToDTO(software), nil
@@ -76,7 +77,7 @@ func (s *softwareService) GetByID(id uint) (*dto.SoftwareDTO, error) {
 func (s *softwareService) GetByCode(code string) (*dto.SoftwareDTO, error) {
 	software, err := s.softwareRepo.FindByCode(code)
 	if err != nil {
-		return nil, errors.New("logiciel introuvable")
+		return nil, utils.ErrSoftwareNotFound
 	}
 
 	return s.softwareToDTO(software), nil
@@ -116,7 +117,7 @@ func (s *softwareService) GetActive() ([]dto.SoftwareDTO, error) {
 func (s *softwareService) Update(id uint, req dto.UpdateSoftwareRequest) (*dto.SoftwareDTO, error) {
 	software, err := s.softwareRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("logiciel introuvable")
+		return nil, utils.ErrSoftwareNotFound
 	}
 
 	// Mettre à jour les champs fournis
@@ -150,7 +151,7 @@ func (s *softwareService) Update(id uint, req dto.UpdateSoftwareRequest) (*dto.S
 func (s *softwareService) Delete(id uint) error {
 	_, err := s.softwareRepo.FindByID(id)
 	if err != nil {
-		return errors.New("logiciel introuvable")
+		return utils.ErrSoftwareNotFound
 	}
 
 	if err := s.softwareRepo.Delete(id); err != nil {
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// SupportContractService interface pour la gestion des contrats de support logiciel
+type SupportContractService interface {
+	GetAll(filialeID, softwareID *uint, activeOnly bool, renewalWithinDays *int) ([]dto.SupportContractDTO, error)
+	GetByID(id uint) (*dto.SupportContractDTO, error)
+	Create(req dto.CreateSupportContractRequest, userID uint) (*dto.SupportContractDTO, error)
+	Update(id uint, req dto.UpdateSupportContractRequest) (*dto.SupportContractDTO, error)
+	Delete(id uint) error
+}
+
+// supportContractService implémente SupportContractService
+type supportContractService struct {
+	contractRepo repositories.SupportContractRepository
+	filialeRepo  repositories.FilialeRepository
+	softwareRepo repositories.SoftwareRepository
+}
+
+// NewSupportContractService crée une nouvelle instance de SupportContractService
+func NewSupportContractService(
+	contractRepo repositories.SupportContractRepository,
+	filialeRepo repositories.FilialeRepository,
+	softwareRepo repositories.SoftwareRepository,
+) SupportContractService {
+	return &supportContractService{
+		contractRepo: contractRepo,
+		filialeRepo:  filialeRepo,
+		softwareRepo: softwareRepo,
+	}
+}
+
+// GetAll récupère les contrats ; renewalWithinDays limite aux contrats à renouveler dans ce nombre de jours
+func (s *supportContractService) GetAll(filialeID, softwareID *uint, activeOnly bool, renewalWithinDays *int) ([]dto.SupportContractDTO, error) {
+	var renewalBefore *time.Time
+	if renewalWithinDays != nil {
+		limit := time.Now().AddDate(0, 0, *renewalWithinDays)
+		renewalBefore = &limit
+	}
+	contracts, err := s.contractRepo.FindAll(filialeID, softwareID, activeOnly || renewalWithinDays != nil, renewalBefore)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des contrats")
+	}
+
+	contractDTOs := make([]dto.SupportContractDTO, 0, len(contracts))
+	for i := range contracts {
+		contractDTOs = append(contractDTOs, supportContractToDTO(&contracts[i]))
+	}
+	return contractDTOs, nil
+}
+
+// GetByID récupère un contrat par son ID
+func (s *supportContractService) GetByID(id uint) (*dto.SupportContractDTO, error) {
+	contract, err := s.contractRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("contrat introuvable")
+	}
+	contractDTO := supportContractToDTO(contract)
+	return &contractDTO, nil
+}
+
+// Create crée un contrat de support
+func (s *supportContractService) Create(req dto.CreateSupportContractRequest, userID uint) (*dto.SupportContractDTO, error) {
+	reference := strings.TrimSpace(req.Reference)
+	if reference == "" {
+		return nil, errors.New("la référence du contrat est obligatoire")
+	}
+	if err := s.checkReference(reference, 0); err != nil {
+		return nil, err
+	}
+
+	var providerID uint
+	if req.ProviderFilialeID != nil {
+		provider, err := s.filialeRepo.FindByID(*req.ProviderFilialeID)
+		if err != nil {
+			return nil, errors.New("filiale fournisseur introuvable")
+		}
+		providerID = provider.ID
+	} else {
+		provider, err := s.filialeRepo.FindSoftwareProvider()
+		if err != nil {
+			return nil, errors.New("aucune filiale fournisseur de logiciels n'est définie")
+		}
+		providerID = provider.ID
+	}
+	if _, err := s.filialeRepo.FindByID(req.FilialeID); err != nil {
+		return nil, utils.ErrFilialeNotFound
+	}
+	if req.FilialeID == providerID {
+		return nil, errors.New("la filiale cliente doit être différente de la filiale fournisseur")
+	}
+	if req.SoftwareID != nil {
+		if _, err := s.softwareRepo.FindByID(*req.SoftwareID); err != nil {
+			return nil, utils.ErrSoftwareNotFound
+		}
+	}
+
+	startDate, err := parseContractDate(req.StartDate, "début")
+	if err != nil {
+		return nil, err
+	}
+	contract := &models.SupportContract{
+		Reference:         reference,
+		ProviderFilialeID: providerID,
+		FilialeID:         req.FilialeID,
+		SoftwareID:        req.SoftwareID,
+		SupportLevel:      req.SupportLevel,
+		StartDate:         *startDate,
+		Notes:             req.Notes,
+		IsActive:          true,
+		CreatedByID:       userID,
+	}
+	if contract.EndDate, err = parseContractDate(req.EndDate, "fin"); err != nil {
+		return nil, err
+	}
+	if contract.RenewalDate, err = parseContractDate(req.RenewalDate, "renouvellement"); err != nil {
+		return nil, err
+	}
+	if err := validateContractPeriod(contract); err != nil {
+		return nil, err
+	}
+	if contract.Contacts, err = json.Marshal(normalizeContractContacts(req.Contacts)); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement des interlocuteurs")
+	}
+	if contract.CoveredModules, err = json.Marshal(normalizeContractModules(req.CoveredModules)); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement des modules couverts")
+	}
+
+	if err := s.contractRepo.Create(contract); err != nil {
+		return nil, errors.New("erreur lors de la création du contrat")
+	}
+	return s.GetByID(contract.ID)
+}
+
+// Update met à jour un contrat de support
+func (s *supportContractService) Update(id uint, req dto.UpdateSupportContractRequest) (*dto.SupportContractDTO, error) {
+	contract, err := s.contractRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("contrat introuvable")
+	}
+
+	if req.Reference != nil {
+		reference := strings.TrimSpace(*req.Reference)
+		if reference == "" {
+			return nil, errors.New("la référence du contrat est obligatoire")
+		}
+		if err := s.checkReference(reference, contract.ID); err != nil {
+			return nil, err
+		}
+		contract.Reference = reference
+	}
+	if req.SupportLevel != nil {
+		contract.SupportLevel = *req.SupportLevel
+	}
+	if req.StartDate != nil {
+		startDate, err := parseContractDate(*req.StartDate, "début")
+		if err != nil {
+			return nil, err
+		}
+		if startDate == nil {
+			return nil, errors.New("la date de début est obligatoire")
+		}
+		contract.StartDate = *startDate
+	}
+	if req.EndDate != nil {
+		if contract.EndDate, err = parseContractDate(*req.EndDate, "fin"); err != nil {
+			return nil, err
+		}
+	}
+	if req.RenewalDate != nil {
+		if contract.RenewalDate, err = parseContractDate(*req.RenewalDate, "renouvellement"); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateContractPeriod(contract); err != nil {
+		return nil, err
+	}
+	if req.Contacts != nil {
+		if contract.Contacts, err = json.Marshal(normalizeContractContacts(*req.Contacts)); err != nil {
+			return nil, errors.New("erreur lors de l'enregistrement des interlocuteurs")
+		}
+	}
+	if req.CoveredModules != nil {
+		if contract.CoveredModules, err = json.Marshal(normalizeContractModules(*req.CoveredModules)); err != nil {
+			return nil, errors.New("erreur lors de l'enregistrement des modules couverts")
+		}
+	}
+	if req.Notes != nil {
+		contract.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		contract.IsActive = *req.IsActive
+	}
+
+	if err := s.contractRepo.Update(contract); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour du contrat")
+	}
+	return s.GetByID(id)
+}
+
+// Delete supprime un contrat de support
+func (s *supportContractService) Delete(id uint) error {
+	if _, err := s.contractRepo.FindByID(id); err != nil {
+		return errors.New("contrat introuvable")
+	}
+	if err := s.contractRepo.Delete(id); err != nil {
+		return errors.New("erreur lors de la suppression du contrat")
+	}
+	return nil
+}
+
+// checkReference vérifie que la référence n'est pas déjà utilisée par un autre contrat
+func (s *supportContractService) checkReference(reference string, excludeID uint) error {
+	exists, err := s.contractRepo.ReferenceExists(reference, excludeID)
+	if err != nil {
+		return errors.New("erreur lors de la vérification de la référence")
+	}
+	if exists {
+		return errors.New("cette référence de contrat existe déjà")
+	}
+	return nil
+}
+
+// parseContractDate lit une date au format YYYY-MM-DD (chaîne vide : pas de date)
+func parseContractDate(value, label string) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		return nil, errors.New("date de " + label + " invalide (format YYYY-MM-DD)")
+	}
+	return &parsed, nil
+}
+
+// validateContractPeriod vérifie la cohérence des dates d'un contrat
+func validateContractPeriod(contract *models.SupportContract) error {
+	if contract.EndDate != nil && contract.EndDate.Before(contract.StartDate) {
+		return errors.New("la date de fin doit être postérieure à la date de début")
+	}
+	if contract.RenewalDate != nil && contract.RenewalDate.Before(contract.StartDate) {
+		return errors.New("la date de renouvellement doit être postérieure à la date de début")
+	}
+	return nil
+}
+
+// normalizeContractContacts retire les espaces superflus des interlocuteurs
+func normalizeContractContacts(contacts []dto.SupportContractContactDTO) []dto.SupportContractContactDTO {
+	normalized := make([]dto.SupportContractContactDTO, 0, len(contacts))
+	for _, contact := range contacts {
+		contact.Name = strings.TrimSpace(contact.Name)
+		if contact.Name == "" {
+			continue
+		}
+		contact.Role = strings.TrimSpace(contact.Role)
+		contact.Email = strings.TrimSpace(contact.Email)
+		contact.Phone = strings.TrimSpace(contact.Phone)
+		normalized = append(normalized, contact)
+	}
+	return normalized
+}
+
+// normalizeContractModules retire les modules vides et les doublons
+func normalizeContractModules(modules []string) []string {
+	seen := make(map[string]bool, len(modules))
+	normalized := make([]string, 0, len(modules))
+	for _, module := range modules {
+		module = strings.TrimSpace(module)
+		key := strings.ToLower(module)
+		if module == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, module)
+	}
+	return normalized
+}
+
+// contractInEffect indique si un contrat est actif et couvre la date at
+func contractInEffect(contract *models.SupportContract, at time.Time) bool {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(contract.StartDate.Year(), contract.StartDate.Month(), contract.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	if !contract.IsActive || day.Before(start) {
+		return false
+	}
+	if contract.EndDate != nil {
+		end := time.Date(contract.EndDate.Year(), contract.EndDate.Month(), contract.EndDate.Day(), 0, 0, 0, 0, time.UTC)
+		return !day.After(end)
+	}
+	return true
+}
+
+// decodeContractLists décode les interlocuteurs et modules couverts d'un contrat
+func decodeContractLists(contract *models.SupportContract) ([]dto.SupportContractContactDTO, []string) {
+	contacts := []dto.SupportContractContactDTO{}
+	modules := []string{}
+	if len(contract.Contacts) > 0 {
+		_ = json.Unmarshal(contract.Contacts, &contacts)
+	}
+	if len(contract.CoveredModules) > 0 {
+		_ = json.Unmarshal(contract.CoveredModules, &modules)
+	}
+	return contacts, modules
+}
+
+// supportContractToDTO convertit un modèle SupportContract en DTO
+func supportContractToDTO(contract *models.SupportContract) dto.SupportContractDTO {
+	contacts, modules := decodeContractLists(contract)
+	contractDTO := dto.SupportContractDTO{
+		ID:                contract.ID,
+		Reference:         contract.Reference,
+		ProviderFilialeID: contract.ProviderFilialeID,
+		FilialeID:         contract.FilialeID,
+		SoftwareID:        contract.SoftwareID,
+		SupportLevel:      contract.SupportLevel,
+		StartDate:         contract.StartDate,
+		EndDate:           contract.EndDate,
+		RenewalDate:       contract.RenewalDate,
+		Contacts:          contacts,
+		CoveredModules:    modules,
+		Notes:             contract.Notes,
+		IsActive:          contract.IsActive,
+		InEffect:          contractInEffect(contract, time.Now()),
+		CreatedByID:       contract.CreatedByID,
+		CreatedAt:         contract.CreatedAt,
+		UpdatedAt:         contract.UpdatedAt,
+	}
+	if contract.ProviderFiliale != nil && contract.ProviderFiliale.ID != 0 {
+		contractDTO.ProviderFiliale = &dto.FilialeDTO{ID: contract.ProviderFiliale.ID, Code: contract.ProviderFiliale.Code, Name: contract.ProviderFiliale.Name}
+	}
+	if contract.Filiale != nil && contract.Filiale.ID != 0 {
+		contractDTO.Filiale = &dto.FilialeDTO{ID: contract.Filiale.ID, Code: contract.Filiale.Code, Name: contract.Filiale.Name}
+	}
+	if contract.Software != nil && contract.Software.ID != 0 {
+		contractDTO.Software = &dto.SoftwareDTO{ID: contract.Software.ID, Code: contract.Software.Code, Name: contract.Software.Name, Version: contract.Software.Version}
+	}
+	return contractDTO
+}
+
+// supportContractSummaryToDTO convertit le contrat couvrant un ticket en résumé
+func supportContractSummaryToDTO(contract *models.SupportContract) *dto.SupportContractSummaryDTO {
+	if contract == nil {
+		return nil
+	}
+	contacts, modules := decodeContractLists(contract)
+	return &dto.SupportContractSummaryDTO{
+		ID:             contract.ID,
+		Reference:      contract.Reference,
+		SupportLevel:   contract.SupportLevel,
+		EndDate:        contract.EndDate,
+		RenewalDate:    contract.RenewalDate,
+		Contacts:       contacts,
+		CoveredModules: modules,
+	}
+}
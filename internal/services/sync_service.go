@@ -0,0 +1,170 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+)
+
+// syncMaxChanges nombre maximal d'éléments retournés par entité et par appel
+const syncMaxChanges = 1000
+
+// syncCursorOverlap recouvrement du curseur : couvre les écritures en cours de validation pendant la lecture
+// (un élément peut être renvoyé deux fois, jamais omis)
+const syncCursorOverlap = 5 * time.Second
+
+// SyncTombstoneRetention durée de conservation des traces de suppression ; un curseur plus ancien impose une resynchronisation complète
+const SyncTombstoneRetention = 90 * 24 * time.Hour
+
+// syncCursorPrefix version du format de curseur
+const syncCursorPrefix = "v1:"
+
+// syncEntities entités synchronisées, dans l'ordre de la réponse
+var syncEntities = []string{models.SyncEntityTickets, models.SyncEntityComments, models.SyncEntityNotifications, models.SyncEntityTasks}
+
+// syncTombstoneEntities entités sans soft delete, dont les suppressions sont tracées dans sync_tombstones
+var syncTombstoneEntities = map[string]bool{models.SyncEntityNotifications: true, models.SyncEntityTasks: true}
+
+// SyncService interface pour la synchronisation incrémentale de l'application mobile
+type SyncService interface {
+	GetChanges(queryScope *scope.QueryScope, since string, entities []string) (*dto.SyncChangesDTO, error)
+	PurgeTombstones() (int64, error)
+}
+
+// syncService implémente SyncService
+type syncService struct {
+	syncRepo repositories.SyncRepository
+}
+
+// NewSyncService crée une nouvelle instance de SyncService
+func NewSyncService(syncRepo repositories.SyncRepository) SyncService {
+	return &syncService{
+		syncRepo: syncRepo,
+	}
+}
+
+// GetChanges retourne les IDs créés, modifiés et supprimés depuis since (horodatage RFC 3339 ou curseur
+// d'une réponse précédente) dans le périmètre de l'utilisateur ; sans since, tous les éléments visibles
+func (s *syncService) GetChanges(queryScope *scope.QueryScope, since string, entities []string) (*dto.SyncChangesDTO, error) {
+	if queryScope == nil {
+		return nil, errors.New("contexte utilisateur manquant")
+	}
+	if len(entities) == 0 {
+		entities = syncEntities
+	}
+	for _, entity := range entities {
+		if !isSyncEntity(entity) {
+			return nil, fmt.Errorf("entité de synchronisation inconnue: %s", entity)
+		}
+	}
+
+	now := time.Now()
+	sinceTime, err := parseSyncSince(since)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.SyncChangesDTO{
+		ServerTime: now,
+		Changes:    make(map[string]dto.SyncEntityChangesDTO, len(entities)),
+	}
+	// Traces de suppression purgées au-delà de la rétention : le client repart d'une liste complète
+	if sinceTime == nil || sinceTime.Before(now.Add(-SyncTombstoneRetention)) {
+		sinceTime = nil
+		result.Reset = true
+	}
+
+	cursor := now.Add(-syncCursorOverlap)
+	for _, entity := range entities {
+		changes, err := s.syncRepo.FindChanges(entity, queryScope, sinceTime, syncMaxChanges+1)
+		if err != nil {
+			return nil, errors.New("erreur lors de la récupération des modifications")
+		}
+		if len(changes) > syncMaxChanges {
+			changes = changes[:syncMaxChanges]
+			result.HasMore = true
+			if boundary := changes[len(changes)-1].ChangedAt; boundary.Before(cursor) {
+				cursor = boundary
+			}
+		}
+
+		entityChanges := dto.SyncEntityChangesDTO{Created: []uint{}, Updated: []uint{}, Deleted: []uint{}}
+		for _, change := range changes {
+			switch {
+			case change.Deleted:
+				entityChanges.Deleted = append(entityChanges.Deleted, change.ID)
+			case sinceTime == nil || !change.CreatedAt.Before(*sinceTime):
+				entityChanges.Created = append(entityChanges.Created, change.ID)
+			default:
+				entityChanges.Updated = append(entityChanges.Updated, change.ID)
+			}
+		}
+
+		if sinceTime != nil && syncTombstoneEntities[entity] {
+			tombstones, err := s.syncRepo.FindTombstones(entity, queryScope.UserID, *sinceTime, syncMaxChanges+1)
+			if err != nil {
+				return nil, errors.New("erreur lors de la récupération des modifications")
+			}
+			if len(tombstones) > syncMaxChanges {
+				tombstones = tombstones[:syncMaxChanges]
+				result.HasMore = true
+				if boundary := tombstones[len(tombstones)-1].DeletedAt; boundary.Before(cursor) {
+					cursor = boundary
+				}
+			}
+			for _, tombstone := range tombstones {
+				entityChanges.Deleted = append(entityChanges.Deleted, tombstone.EntityID)
+			}
+		}
+		result.Changes[entity] = entityChanges
+	}
+
+	result.Cursor = encodeSyncCursor(cursor)
+	return result, nil
+}
+
+// PurgeTombstones supprime les traces de suppression au-delà de la rétention
+func (s *syncService) PurgeTombstones() (int64, error) {
+	return s.syncRepo.PurgeTombstones(time.Now().Add(-SyncTombstoneRetention))
+}
+
+// isSyncEntity indique si l'entité est synchronisable
+func isSyncEntity(entity string) bool {
+	for _, e := range syncEntities {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeSyncCursor encode un instant en curseur opaque
+func encodeSyncCursor(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncCursorPrefix + strconv.FormatInt(t.UnixNano(), 10)))
+}
+
+// parseSyncSince lit le paramètre since : curseur d'une réponse précédente ou horodatage RFC 3339 (vide = nil)
+func parseSyncSince(since string) (*time.Time, error) {
+	if since == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+		return &t, nil
+	}
+	if decoded, err := base64.RawURLEncoding.DecodeString(since); err == nil {
+		if value, ok := strings.CutPrefix(string(decoded), syncCursorPrefix); ok {
+			if nanos, err := strconv.ParseInt(value, 10, 64); err == nil {
+				t := time.Unix(0, nanos)
+				return &t, nil
+			}
+		}
+	}
+	return nil, errors.New("paramètre since invalide (curseur ou date RFC 3339 attendu)")
+}
@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/telegram"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// telegramLinkTTL durée de validité d'un lien de liaison
+const telegramLinkTTL = 15 * time.Minute
+
+// Actions rapides proposées sous les notifications de ticket (données des boutons : "<action>:<id du ticket>")
+const (
+	telegramActionAcknowledge = "ack"
+	telegramActionAssignToMe  = "assign"
+	telegramActionClose       = "close"
+)
+
+// TelegramService interface pour le bot Telegram : liaison des comptes, notifications et actions rapides sur les tickets
+type TelegramService interface {
+	NotificationChannel
+	Enabled() bool
+	Status(userID uint) (*dto.TelegramLinkDTO, error)
+	CreateLink(userID uint) (*dto.TelegramLinkDTO, error)
+	Unlink(userID uint) error
+	Send(ctx context.Context, payload jobs.TelegramSendPayload) error
+	RegisterWebhook(ctx context.Context) error
+	VerifyWebhook(secret string) bool
+	HandleUpdate(ctx context.Context, body []byte) error
+}
+
+// telegramService implémente TelegramService
+type telegramService struct {
+	client    *telegram.Client // Nil si le bot n'est pas configuré
+	cfg       config.TelegramConfig
+	linkRepo  repositories.TelegramLinkRepository
+	userRepo  repositories.UserRepository
+	shareRepo repositories.RecordShareRepository
+	ticketSvc TicketService
+	jobQueue  *jobs.Queue
+}
+
+// NewTelegramService crée une nouvelle instance de TelegramService
+func NewTelegramService(
+	cfg config.TelegramConfig,
+	linkRepo repositories.TelegramLinkRepository,
+	userRepo repositories.UserRepository,
+	shareRepo repositories.RecordShareRepository,
+	ticketSvc TicketService,
+	jobQueue *jobs.Queue,
+) TelegramService {
+	var client *telegram.Client
+	if cfg.Enabled() {
+		client = telegram.New(cfg.BotToken)
+	}
+	return &telegramService{
+		client:    client,
+		cfg:       cfg,
+		linkRepo:  linkRepo,
+		userRepo:  userRepo,
+		shareRepo: shareRepo,
+		ticketSvc: ticketSvc,
+		jobQueue:  jobQueue,
+	}
+}
+
+// Enabled indique si le bot est configuré
+func (s *telegramService) Enabled() bool {
+	return s.client != nil
+}
+
+// Status retourne l'état de la liaison du compte
+func (s *telegramService) Status(userID uint) (*dto.TelegramLinkDTO, error) {
+	status := &dto.TelegramLinkDTO{Enabled: s.Enabled()}
+	if link, err := s.linkRepo.FindByUserID(userID); err == nil && link.ChatID != nil {
+		status.Linked = true
+		status.TelegramUsername = link.TelegramUsername
+		status.LinkedAt = link.LinkedAt
+	}
+	return status, nil
+}
+
+// CreateLink génère un lien de liaison à usage unique ; une liaison existante est remplacée à son utilisation
+func (s *telegramService) CreateLink(userID uint) (*dto.TelegramLinkDTO, error) {
+	if !s.Enabled() {
+		return nil, errors.New("bot Telegram non configuré")
+	}
+	code, err := generateInvitationToken()
+	if err != nil {
+		return nil, errors.New("erreur lors de la génération du lien")
+	}
+
+	link, err := s.linkRepo.FindByUserID(userID)
+	if err != nil {
+		link = &models.TelegramLink{UserID: userID}
+	}
+	expiresAt := time.Now().Add(telegramLinkTTL)
+	link.LinkCodeHash = utils.HashString(code)
+	link.LinkCodeExpiresAt = &expiresAt
+	if err := s.linkRepo.Save(link); err != nil {
+		return nil, errors.New("erreur lors de la création du lien")
+	}
+
+	status, _ := s.Status(userID)
+	status.LinkURL = "https://t.me/" + s.cfg.BotUsername + "?start=" + code
+	status.LinkExpiresAt = &expiresAt
+	return status, nil
+}
+
+// Unlink supprime la liaison du compte
+func (s *telegramService) Unlink(userID uint) error {
+	if _, err := s.linkRepo.FindByUserID(userID); err != nil {
+		return errors.New("liaison Telegram introuvable")
+	}
+	if err := s.linkRepo.DeleteByUserID(userID); err != nil {
+		return errors.New("erreur lors de la suppression de la liaison")
+	}
+	return nil
+}
+
+// Deliver planifie l'envoi d'une notification aux utilisateurs ayant lié leur compte
+func (s *telegramService) Deliver(notification *models.Notification) {
+	if !s.Enabled() {
+		return
+	}
+	if link, err := s.linkRepo.FindByUserID(notification.UserID); err != nil || link.ChatID == nil {
+		return
+	}
+	payload := jobs.TelegramSendPayload{UserID: notification.UserID, Title: notification.Title, Message: notification.Message}
+	var metadata struct {
+		TicketID uint `json:"ticket_id"`
+	}
+	if len(notification.Metadata) > 0 && json.Unmarshal(notification.Metadata, &metadata) == nil {
+		payload.TicketID = metadata.TicketID
+	}
+	if err := s.jobQueue.Enqueue(context.Background(), jobs.TypeTelegramSend, payload); err != nil {
+		log.Printf("Erreur lors de la planification de la notification Telegram (user %d): %v", notification.UserID, err)
+	}
+}
+
+// Send envoie une notification ; les notifications de ticket proposent les actions rapides
+func (s *telegramService) Send(ctx context.Context, payload jobs.TelegramSendPayload) error {
+	if !s.Enabled() {
+		return nil
+	}
+	link, err := s.linkRepo.FindByUserID(payload.UserID)
+	if err != nil || link.ChatID == nil {
+		return nil // Compte délié entre-temps
+	}
+
+	text := "<b>" + html.EscapeString(payload.Title) + "</b>\n" + html.EscapeString(payload.Message)
+	if payload.TicketID == 0 {
+		return s.client.SendMessage(ctx, *link.ChatID, text)
+	}
+	id := strconv.FormatUint(uint64(payload.TicketID), 10)
+	return s.client.SendMessage(ctx, *link.ChatID, text, []telegram.Button{
+		{Text: "✅ Prendre en charge", Data: telegramActionAcknowledge + ":" + id},
+		{Text: "🙋 M'assigner", Data: telegramActionAssignToMe + ":" + id},
+		{Text: "🔒 Clôturer", Data: telegramActionClose + ":" + id},
+	})
+}
+
+// RegisterWebhook déclare auprès de Telegram l'URL du webhook de cette instance
+func (s *telegramService) RegisterWebhook(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+	url := strings.TrimRight(config.AppConfig.AppURL, "/") + "/api/v1/integrations/telegram/webhook"
+	return s.client.SetWebhook(ctx, url, s.cfg.WebhookSecret)
+}
+
+// VerifyWebhook vérifie le secret d'une mise à jour reçue
+func (s *telegramService) VerifyWebhook(secret string) bool {
+	if !s.Enabled() || s.cfg.WebhookSecret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(s.cfg.WebhookSecret)) == 1
+}
+
+// HandleUpdate traite une mise à jour : commandes /start (liaison) et /stop, clics sur les boutons d'action
+// Seule une mise à jour illisible est une erreur : Telegram réémettrait sinon la mise à jour
+func (s *telegramService) HandleUpdate(ctx context.Context, body []byte) error {
+	var update telegram.Update
+	if err := json.Unmarshal(body, &update); err != nil {
+		return errors.New("mise à jour Telegram invalide")
+	}
+
+	switch {
+	case update.CallbackQuery != nil:
+		query := update.CallbackQuery
+		chatID := query.From.ID
+		if query.Message != nil {
+			chatID = query.Message.Chat.ID
+		}
+		reply := s.runAction(chatID, query.Data)
+		if err := s.client.AnswerCallbackQuery(ctx, query.ID, reply); err != nil {
+			log.Printf("Erreur lors de la réponse à une action Telegram: %v", err)
+		}
+	case update.Message != nil && update.Message.Chat.Type == "private":
+		if reply := s.handleCommand(update.Message); reply != "" {
+			if err := s.client.SendMessage(ctx, update.Message.Chat.ID, reply); err != nil {
+				log.Printf("Erreur lors de la réponse à une commande Telegram: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// handleCommand traite une commande reçue en conversation privée et retourne la réponse
+func (s *telegramService) handleCommand(message *telegram.Message) string {
+	command, argument, _ := strings.Cut(strings.TrimSpace(message.Text), " ")
+	switch command {
+	case "/start":
+		if argument == "" {
+			if _, err := s.linkRepo.FindByChatID(message.Chat.ID); err == nil {
+				return "Votre compte Kronos est lié : vous recevez ici vos notifications. Envoyez /stop pour les désactiver."
+			}
+			return "Pour recevoir vos notifications, ouvrez le lien de liaison depuis votre profil Kronos."
+		}
+		return s.link(message, strings.TrimSpace(argument))
+	case "/stop":
+		if err := s.linkRepo.DeleteByChatID(message.Chat.ID); err != nil {
+			return "Erreur lors de la suppression de la liaison, réessayez plus tard."
+		}
+		return "Liaison supprimée : vous ne recevrez plus de notifications Kronos ici."
+	}
+	return ""
+}
+
+// link lie la conversation au compte ayant généré le code
+func (s *telegramService) link(message *telegram.Message, code string) string {
+	link, err := s.linkRepo.FindByLinkCodeHash(utils.HashString(code))
+	if err != nil || link.LinkCodeExpiresAt == nil || time.Now().After(*link.LinkCodeExpiresAt) {
+		return "Lien de liaison invalide ou expiré. Générez un nouveau lien depuis votre profil Kronos."
+	}
+	user, err := s.userRepo.FindByID(link.UserID)
+	if err != nil || !user.IsActive {
+		return "Compte Kronos introuvable ou désactivé."
+	}
+
+	// Une conversation ne peut être liée qu'à un seul compte
+	if existing, err := s.linkRepo.FindByChatID(message.Chat.ID); err == nil && existing.UserID != link.UserID {
+		if err := s.linkRepo.DeleteByChatID(message.Chat.ID); err != nil {
+			return "Erreur lors de la liaison, réessayez plus tard."
+		}
+	}
+
+	now := time.Now()
+	chatID := message.Chat.ID
+	link.ChatID = &chatID
+	link.LinkedAt = &now
+	link.LinkCodeHash = ""
+	link.LinkCodeExpiresAt = nil
+	if message.From != nil {
+		link.TelegramUsername = message.From.Username
+	}
+	if err := s.linkRepo.Save(link); err != nil {
+		return "Erreur lors de la liaison, réessayez plus tard."
+	}
+	return fmt.Sprintf("Compte Kronos de %s %s lié : vous recevrez ici vos notifications. Envoyez /stop pour les désactiver.", user.FirstName, user.LastName)
+}
+
+// runAction exécute une action rapide au nom de l'utilisateur lié, dans la limite de ses permissions
+func (s *telegramService) runAction(chatID int64, data string) string {
+	action, idParam, _ := strings.Cut(data, ":")
+	ticketID, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return "Action inconnue"
+	}
+	link, err := s.linkRepo.FindByChatID(chatID)
+	if err != nil {
+		return "Compte non lié : ouvrez le lien de liaison depuis votre profil Kronos"
+	}
+	user, err := s.userRepo.FindByID(link.UserID)
+	if err != nil || !user.IsActive {
+		return "Compte Kronos introuvable ou désactivé"
+	}
+	queryScope, err := scope.NewQueryScopeFromUser(user)
+	if err != nil {
+		return "Permissions indisponibles, réessayez plus tard"
+	}
+
+	// Mêmes règles que l'API : ticket dans le périmètre de l'utilisateur et non partagé en lecture seule
+	id := uint(ticketID)
+	visible, err := s.shareRepo.IsVisible(models.ShareResourceTicket, id, queryScope)
+	if err != nil {
+		return "Erreur lors de la vérification des droits d'accès"
+	}
+	if !visible {
+		return "Ticket introuvable"
+	}
+	if queryScope.SharedAccess(models.ShareResourceTicket, id) == models.ShareAccessRead {
+		if owned, err := s.shareRepo.IsVisible(models.ShareResourceTicket, id, queryScope.WithoutShares()); err != nil || !owned {
+			return "Accès en lecture seule à ce ticket"
+		}
+	}
+
+	ticket, err := s.ticketSvc.GetByID(id, false)
+	if err != nil {
+		return "Ticket introuvable"
+	}
+	assigned := slices.ContainsFunc(ticket.Assignees, func(a dto.TicketAssigneeDTO) bool { return a.User.ID == user.ID }) ||
+		(ticket.AssignedTo != nil && ticket.AssignedTo.ID == user.ID)
+
+	switch action {
+	case telegramActionAcknowledge:
+		if !assigned && !queryScope.HasPermission("tickets.update") {
+			return "Permission insuffisante: tickets.update"
+		}
+		if ticket.Status != "ouvert" {
+			return fmt.Sprintf("Ticket %s déjà pris en charge", ticket.Code)
+		}
+		if _, err := s.ticketSvc.ChangeStatus(id, "en_cours", user.ID); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("Ticket %s pris en charge", ticket.Code)
+
+	case telegramActionAssignToMe:
+		if !queryScope.HasPermission("tickets.assign") {
+			return "Permission insuffisante: tickets.assign"
+		}
+		if ticket.AssignedTo != nil && ticket.AssignedTo.ID == user.ID {
+			return fmt.Sprintf("Ticket %s déjà assigné à vous", ticket.Code)
+		}
+		// Les assignés actuels sont conservés, l'utilisateur devient responsable
+		assigneeIDs := []uint{user.ID}
+		for _, assignee := range ticket.Assignees {
+			if assignee.User.ID != user.ID {
+				assigneeIDs = append(assigneeIDs, assignee.User.ID)
+			}
+		}
+		if _, err := s.ticketSvc.Assign(id, dto.AssignTicketRequest{UserIDs: assigneeIDs, LeadID: &user.ID}, user.ID); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("Ticket %s assigné à vous", ticket.Code)
+
+	case telegramActionClose:
+		if !queryScope.HasPermission("tickets.close") {
+			return "Permission insuffisante: tickets.close"
+		}
+		if ticket.Status == "cloture" {
+			return fmt.Sprintf("Ticket %s déjà clôturé", ticket.Code)
+		}
+		if _, err := s.ticketSvc.Close(id, user.ID); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("Ticket %s clôturé", ticket.Code)
+	}
+	return "Action inconnue"
+}
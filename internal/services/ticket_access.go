@@ -0,0 +1,38 @@
+package services
+
+import (
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// checkTicketVisible vérifie que le ticket fait partie du périmètre de l'utilisateur (partages inclus) ;
+// un ticket hors périmètre est signalé introuvable
+func checkTicketVisible(shareRepo repositories.RecordShareRepository, ticketID uint, queryScope *scope.QueryScope) error {
+	if queryScope == nil {
+		return utils.ErrTicketNotFound
+	}
+	visible, err := shareRepo.IsVisible(models.ShareResourceTicket, ticketID, queryScope)
+	if err != nil {
+		return utils.NewInternalError("erreur lors de la vérification des droits d'accès")
+	}
+	if !visible {
+		return utils.ErrTicketNotFound
+	}
+	return nil
+}
+
+// checkTicketWritable vérifie que le ticket est visible et ne lui est pas partagé en lecture seule
+// (un partage en lecture ne restreint pas un ticket déjà dans le périmètre propre de l'utilisateur)
+func checkTicketWritable(shareRepo repositories.RecordShareRepository, ticketID uint, queryScope *scope.QueryScope) error {
+	if err := checkTicketVisible(shareRepo, ticketID, queryScope); err != nil {
+		return err
+	}
+	if queryScope.SharedAccess(models.ShareResourceTicket, ticketID) == models.ShareAccessRead {
+		if owned, err := shareRepo.IsVisible(models.ShareResourceTicket, ticketID, queryScope.WithoutShares()); err != nil || !owned {
+			return utils.ErrTicketReadOnly
+		}
+	}
+	return nil
+}
@@ -1,26 +1,39 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/antivirus"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/storage"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // TicketAttachmentService interface pour les opérations sur les pièces jointes de tickets
 type TicketAttachmentService interface {
-	UploadAttachment(ticketID uint, fileName, filePath, thumbnailPath string, fileSize int, mimeType string, isImage bool, description string, displayOrder int, userID uint) (*dto.TicketAttachmentDTO, error)
+	// UploadAttachment vérifie le fichier (extension, contenu, taille, antivirus) avant de l'enregistrer ; le type MIME est déduit du contenu
+	UploadAttachment(ticketID uint, fileName string, content io.Reader, description string, displayOrder int, userID uint) (*dto.TicketAttachmentDTO, error)
 	GetByTicketID(ticketID uint, imagesOnly bool) ([]dto.TicketAttachmentDTO, error)
 	GetByID(id uint) (*dto.TicketAttachmentDTO, error)
 	GetImagesByTicketID(ticketID uint) ([]dto.TicketAttachmentDTO, error)
-	GetFilePath(id uint) (string, error)
-	GetThumbnailPath(id uint) (string, error)
-	GetFilePathForTicket(ticketID uint, attachmentID uint) (string, error)
-	GetThumbnailPathForTicket(ticketID uint, attachmentID uint) (string, error)
+	OpenFileForTicket(ticketID uint, attachmentID uint) (*storage.Object, string, error)
+	OpenThumbnailForTicket(ticketID uint, attachmentID uint) (*storage.Object, string, error)
+	SignedURLForTicket(ticketID uint, attachmentID uint, ttl time.Duration) (string, error)
 	Update(id uint, req dto.UpdateTicketAttachmentRequest, updatedByID uint) (*dto.TicketAttachmentDTO, error)
 	SetPrimary(ticketID, attachmentID uint, updatedByID uint) (*dto.TicketAttachmentDTO, error)
 	Delete(id uint) error
@@ -32,36 +45,91 @@ type ticketAttachmentService struct {
 	attachmentRepo repositories.TicketAttachmentRepository
 	ticketRepo     repositories.TicketRepository
 	userRepo       repositories.UserRepository
+	fileStorage    storage.Storage
+	scanner        antivirus.Scanner
 }
 
+// attachmentContentType type MIME enregistré pour une extension et type détecté attendu dans le contenu
+type attachmentContentType struct {
+	mimeType string
+	detected string
+}
+
+// attachmentContentTypes extensions connues ; une extension autorisée absente de cette table est acceptée
+// si son contenu n'est pas détecté comme exécutable ou HTML
+var attachmentContentTypes = map[string]attachmentContentType{
+	".jpg":  {"image/jpeg", "image/jpeg"},
+	".jpeg": {"image/jpeg", "image/jpeg"},
+	".png":  {"image/png", "image/png"},
+	".gif":  {"image/gif", "image/gif"},
+	".webp": {"image/webp", "image/webp"},
+	".pdf":  {"application/pdf", "application/pdf"},
+	".doc":  {"application/msword", "application/x-ole-storage"},
+	".xls":  {"application/vnd.ms-excel", "application/x-ole-storage"},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+	".xlsx": {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/zip"},
+	".txt":  {"text/plain; charset=utf-8", "text/plain"},
+	".zip":  {"application/zip", "application/zip"},
+}
+
+// forbiddenContentTypes contenus refusés quelle que soit l'extension annoncée
+var forbiddenContentTypes = []string{"application/x-msdownload", "application/x-executable", "text/html"}
+
 // NewTicketAttachmentService crée une nouvelle instance de TicketAttachmentService
 func NewTicketAttachmentService(
 	attachmentRepo repositories.TicketAttachmentRepository,
 	ticketRepo repositories.TicketRepository,
 	userRepo repositories.UserRepository,
+	fileStorage storage.Storage,
+	scanner antivirus.Scanner,
 ) TicketAttachmentService {
 	return &ticketAttachmentService{
 		attachmentRepo: attachmentRepo,
 		ticketRepo:     ticketRepo,
 		userRepo:       userRepo,
+		fileStorage:    fileStorage,
+		scanner:        scanner,
 	}
 }
 
-// UploadAttachment upload une pièce jointe pour un ticket
-func (s *ticketAttachmentService) UploadAttachment(ticketID uint, fileName, filePath, thumbnailPath string, fileSize int, mimeType string, isImage bool, description string, displayOrder int, userID uint) (*dto.TicketAttachmentDTO, error) {
+// UploadAttachment enregistre le fichier dans le stockage puis crée la pièce jointe du ticket
+func (s *ticketAttachmentService) UploadAttachment(ticketID uint, fileName string, content io.Reader, description string, displayOrder int, userID uint) (*dto.TicketAttachmentDTO, error) {
 	// Vérifier que le ticket existe
 	exists, err := s.ticketRepo.ExistsByID(ticketID)
 	if err != nil {
 		return nil, errors.New("erreur lors de la vérification du ticket")
 	}
 	if !exists {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	// Vérifier que l'utilisateur existe
 	_, err = s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
+	}
+
+	// Copie temporaire : taille réelle, type détecté et analyse antivirus avant l'envoi au stockage
+	ctx := context.Background()
+	upload, err := prepareAttachmentUpload(ctx, s.scanner, fileName, content)
+	if err != nil {
+		return nil, err
+	}
+	defer upload.cleanup()
+	fileSize := int(upload.size)
+	mimeType := upload.mimeType
+	isImage := strings.HasPrefix(mimeType, "image/")
+
+	// Enregistrer le fichier sous un nom unique (clé relative à l'espace "tickets")
+	key := fmt.Sprintf("ticket_%d/%d_%s", ticketID, time.Now().Unix(), fileName)
+	if err := s.fileStorage.Put(ctx, key, upload.file, upload.size, mimeType); err != nil {
+		return nil, errors.New("erreur lors de la sauvegarde du fichier")
+	}
+
+	// Miniature : le fichier original est utilisé pour l'instant (TODO: générer une miniature)
+	thumbnailPath := ""
+	if isImage {
+		thumbnailPath = key
 	}
 
 	// Créer l'attachment
@@ -69,7 +137,7 @@ func (s *ticketAttachmentService) UploadAttachment(ticketID uint, fileName, file
 		TicketID:      ticketID,
 		UserID:        userID,
 		FileName:      fileName,
-		FilePath:      filePath,
+		FilePath:      key,
 		ThumbnailPath: thumbnailPath,
 		FileSize:      &fileSize,
 		MimeType:      mimeType,
@@ -79,6 +147,7 @@ func (s *ticketAttachmentService) UploadAttachment(ticketID uint, fileName, file
 	}
 
 	if err := s.attachmentRepo.Create(attachment); err != nil {
+		_ = s.fileStorage.Delete(ctx, key)
 		return nil, errors.New("erreur lors de la création de la pièce jointe")
 	}
 
@@ -100,7 +169,7 @@ func (s *ticketAttachmentService) GetByTicketID(ticketID uint, imagesOnly bool)
 		return nil, errors.New("erreur lors de la vérification du ticket")
 	}
 	if !exists {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	var attachments []models.TicketAttachment
@@ -126,7 +195,7 @@ func (s *ticketAttachmentService) GetByTicketID(ticketID uint, imagesOnly bool)
 func (s *ticketAttachmentService) GetByID(id uint) (*dto.TicketAttachmentDTO, error) {
 	attachment, err := s.attachmentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("pièce jointe introuvable")
+		return nil, utils.ErrAttachmentNotFound
 	}
 
 	attachmentDTO := s.attachmentToDTO(attachment)
@@ -138,75 +207,169 @@ func (s *ticketAttachmentService) GetImagesByTicketID(ticketID uint) ([]dto.Tick
 	return s.GetByTicketID(ticketID, true)
 }
 
-// GetFilePath récupère le chemin complet d'une pièce jointe
-func (s *ticketAttachmentService) GetFilePath(id uint) (string, error) {
-	attachment, err := s.attachmentRepo.FindByIDBasic(id)
+// OpenFileForTicket ouvre le fichier d'une pièce jointe appartenant au ticket ; retourne aussi le nom d'origine
+func (s *ticketAttachmentService) OpenFileForTicket(ticketID uint, attachmentID uint) (*storage.Object, string, error) {
+	attachment, err := s.findForTicket(ticketID, attachmentID)
 	if err != nil {
-		return "", errors.New("pièce jointe introuvable")
+		return nil, "", err
 	}
 
-	fullPath := filepath.Join(config.AppConfig.TicketAttachmentsDir, attachment.FilePath)
-	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return "", errors.New("fichier introuvable")
+	object, err := s.fileStorage.Get(context.Background(), attachment.FilePath)
+	if err != nil {
+		return nil, "", errors.New("fichier introuvable")
 	}
-
-	return fullPath, nil
+	return object, attachment.FileName, nil
 }
 
-// GetThumbnailPath récupère le chemin de la miniature
-func (s *ticketAttachmentService) GetThumbnailPath(id uint) (string, error) {
-	attachment, err := s.attachmentRepo.FindByIDBasic(id)
+// OpenThumbnailForTicket ouvre la miniature d'une image (ou l'image originale à défaut de miniature)
+func (s *ticketAttachmentService) OpenThumbnailForTicket(ticketID uint, attachmentID uint) (*storage.Object, string, error) {
+	attachment, err := s.findForTicket(ticketID, attachmentID)
 	if err != nil {
-		return "", errors.New("pièce jointe introuvable")
+		return nil, "", err
 	}
-
 	if !attachment.IsImage {
-		return "", errors.New("cette pièce jointe n'est pas une image")
+		return nil, "", errors.New("cette pièce jointe n'est pas une image")
+	}
+
+	if attachment.ThumbnailPath != "" {
+		if object, err := s.fileStorage.Get(context.Background(), attachment.ThumbnailPath); err == nil {
+			return object, attachment.FileName, nil
+		}
 	}
+	return s.OpenFileForTicket(ticketID, attachmentID)
+}
 
-	if attachment.ThumbnailPath == "" {
-		// Si pas de miniature, retourner l'image originale
-		return s.GetFilePath(id)
+// SignedURLForTicket retourne une URL temporaire de téléchargement direct de la pièce jointe
+func (s *ticketAttachmentService) SignedURLForTicket(ticketID uint, attachmentID uint, ttl time.Duration) (string, error) {
+	attachment, err := s.findForTicket(ticketID, attachmentID)
+	if err != nil {
+		return "", err
 	}
 
-	fullPath := filepath.Join(config.AppConfig.TicketAttachmentsDir, attachment.ThumbnailPath)
-	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		// Si la miniature n'existe pas, retourner l'image originale
-		return s.GetFilePath(id)
+	url, err := s.fileStorage.SignedURL(context.Background(), attachment.FilePath, ttl)
+	if err != nil {
+		return "", errors.New("erreur lors de la génération de l'URL de téléchargement")
 	}
+	return url, nil
+}
 
-	return fullPath, nil
+// preparedUpload fichier reçu, copié et vérifié, prêt à être envoyé au stockage
+type preparedUpload struct {
+	file     *os.File
+	size     int64
+	mimeType string
 }
 
-// GetFilePathForTicket retourne le chemin du fichier uniquement si la pièce jointe appartient au ticket
-func (s *ticketAttachmentService) GetFilePathForTicket(ticketID uint, attachmentID uint) (string, error) {
-	attachment, err := s.attachmentRepo.FindByIDBasic(attachmentID)
+func (u *preparedUpload) cleanup() {
+	u.file.Close()
+	os.Remove(u.file.Name())
+}
+
+// prepareAttachmentUpload copie le fichier reçu dans un fichier temporaire (dans la limite de MAX_UPLOAD_SIZE), vérifie
+// que son contenu correspond à son extension autorisée puis le fait analyser par l'antivirus
+func prepareAttachmentUpload(ctx context.Context, scanner antivirus.Scanner, fileName string, content io.Reader) (*preparedUpload, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if ext == "" || !slices.Contains(config.AppConfig.Storage.AttachmentExtensions, ext) {
+		return nil, utils.ErrAttachmentType.WithDetails(config.AppConfig.Storage.AttachmentExtensions)
+	}
+
+	tmp, err := os.CreateTemp("", "attachment-*")
 	if err != nil {
-		return "", errors.New("pièce jointe introuvable")
+		return nil, errors.New("erreur lors de la sauvegarde du fichier")
 	}
-	if attachment.TicketID != ticketID {
-		return "", errors.New("pièce jointe introuvable")
+	upload := &preparedUpload{file: tmp}
+	maxSize := config.AppConfig.MaxUploadSize
+	upload.size, err = io.Copy(tmp, io.LimitReader(content, maxSize+1))
+	if err != nil {
+		upload.cleanup()
+		return nil, errors.New("erreur lors de la lecture du fichier")
+	}
+	if upload.size > maxSize {
+		upload.cleanup()
+		return nil, utils.ErrAttachmentTooLarge.WithDetails(map[string]int64{"max_size": maxSize})
+	}
+
+	head := make([]byte, 512)
+	n, _ := tmp.ReadAt(head, 0)
+	detected := detectAttachmentContentType(head[:n])
+	expected, known := attachmentContentTypes[ext]
+	if slices.Contains(forbiddenContentTypes, detected) || (known && detected != expected.detected) {
+		upload.cleanup()
+		return nil, utils.ErrAttachmentMismatch
+	}
+	upload.mimeType = expected.mimeType
+	if !known {
+		if upload.mimeType = mime.TypeByExtension(ext); upload.mimeType == "" {
+			upload.mimeType = "application/octet-stream"
+		}
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		upload.cleanup()
+		return nil, errors.New("erreur lors de la lecture du fichier")
 	}
-	return s.GetFilePath(attachmentID)
+	if err := scanAttachment(ctx, scanner, tmp); err != nil {
+		upload.cleanup()
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		upload.cleanup()
+		return nil, errors.New("erreur lors de la lecture du fichier")
+	}
+	return upload, nil
 }
 
-// GetThumbnailPathForTicket retourne le chemin de la miniature uniquement si la pièce jointe appartient au ticket
-func (s *ticketAttachmentService) GetThumbnailPathForTicket(ticketID uint, attachmentID uint) (string, error) {
-	attachment, err := s.attachmentRepo.FindByIDBasic(attachmentID)
-	if err != nil {
-		return "", errors.New("pièce jointe introuvable")
+// scanAttachment soumet un contenu à l'antivirus ; s'il est indisponible, le contenu est refusé sauf avec ANTIVIRUS_FAIL_OPEN
+func scanAttachment(ctx context.Context, scanner antivirus.Scanner, content io.Reader) error {
+	if scanner == nil {
+		return nil
+	}
+	err := scanner.Scan(ctx, content)
+	var infected *antivirus.InfectedError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &infected):
+		log.Printf("Pièce jointe refusée par l'antivirus: %s", infected.Signature)
+		return utils.ErrAttachmentInfected.WithDetails(map[string]string{"signature": infected.Signature})
+	case config.AppConfig.Antivirus.FailOpen:
+		log.Printf("Analyse antivirus impossible, pièce jointe acceptée (ANTIVIRUS_FAIL_OPEN): %v", err)
+		return nil
+	default:
+		log.Printf("Analyse antivirus impossible: %v", err)
+		return utils.ErrAntivirusUnavailable
 	}
-	if attachment.TicketID != ticketID {
-		return "", errors.New("pièce jointe introuvable")
+}
+
+// detectAttachmentContentType détecte le type d'un contenu à partir de ses premiers octets (sans paramètres) ;
+// complète http.DetectContentType pour les documents Office 97-2003 (OLE) et les exécutables
+func detectAttachmentContentType(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}):
+		return "application/x-ole-storage"
+	case bytes.HasPrefix(head, []byte("MZ")):
+		return "application/x-msdownload"
+	case bytes.HasPrefix(head, []byte("\x7fELF")):
+		return "application/x-executable"
+	}
+	detected, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return strings.TrimSpace(detected)
+}
+
+// findForTicket récupère une pièce jointe uniquement si elle appartient au ticket
+func (s *ticketAttachmentService) findForTicket(ticketID uint, attachmentID uint) (*models.TicketAttachment, error) {
+	attachment, err := s.attachmentRepo.FindByIDBasic(attachmentID)
+	if err != nil || attachment.TicketID != ticketID {
+		return nil, utils.ErrAttachmentNotFound
 	}
-	return s.GetThumbnailPath(attachmentID)
+	return attachment, nil
 }
 
 // Update met à jour une pièce jointe
 func (s *ticketAttachmentService) Update(id uint, req dto.UpdateTicketAttachmentRequest, updatedByID uint) (*dto.TicketAttachmentDTO, error) {
 	attachment, err := s.attachmentRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("pièce jointe introuvable")
+		return nil, utils.ErrAttachmentNotFound
 	}
 
 	if req.Description != "" {
@@ -238,13 +401,13 @@ func (s *ticketAttachmentService) SetPrimary(ticketID, attachmentID uint, update
 		return nil, errors.New("erreur lors de la vérification du ticket")
 	}
 	if !exists {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	// Vérifier que l'attachment existe et appartient au ticket
 	attachment, err := s.attachmentRepo.FindByID(attachmentID)
 	if err != nil {
-		return nil, errors.New("pièce jointe introuvable")
+		return nil, utils.ErrAttachmentNotFound
 	}
 
 	if attachment.TicketID != ticketID {
@@ -284,21 +447,14 @@ func (s *ticketAttachmentService) SetPrimary(ticketID, attachmentID uint, update
 func (s *ticketAttachmentService) Delete(id uint) error {
 	attachment, err := s.attachmentRepo.FindByID(id)
 	if err != nil {
-		return errors.New("pièce jointe introuvable")
-	}
-
-	// Supprimer le fichier
-	filePath := filepath.Join(config.AppConfig.TicketAttachmentsDir, attachment.FilePath)
-	if _, err := os.Stat(filePath); err == nil {
-		os.Remove(filePath)
+		return utils.ErrAttachmentNotFound
 	}
 
-	// Supprimer la miniature si elle existe
-	if attachment.ThumbnailPath != "" {
-		thumbnailPath := filepath.Join(config.AppConfig.TicketAttachmentsDir, attachment.ThumbnailPath)
-		if _, err := os.Stat(thumbnailPath); err == nil {
-			os.Remove(thumbnailPath)
-		}
+	// Supprimer le fichier et la miniature si elle est distincte
+	ctx := context.Background()
+	_ = s.fileStorage.Delete(ctx, attachment.FilePath)
+	if attachment.ThumbnailPath != "" && attachment.ThumbnailPath != attachment.FilePath {
+		_ = s.fileStorage.Delete(ctx, attachment.ThumbnailPath)
 	}
 
 	// Supprimer de la base de données
@@ -317,7 +473,7 @@ func (s *ticketAttachmentService) Reorder(ticketID uint, attachmentIDs []uint, u
 		return errors.New("erreur lors de la vérification du ticket")
 	}
 	if !exists {
-		return errors.New("ticket introuvable")
+		return utils.ErrTicketNotFound
 	}
 
 	// Mettre à jour l'ordre d'affichage pour chaque attachment
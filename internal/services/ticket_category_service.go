@@ -6,6 +6,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // TicketCategoryService interface pour les opérations sur les catégories de tickets
@@ -42,13 +43,14 @@ func (s *ticketCategoryService) Create(req dto.CreateTicketCategoryRequest) (*dt
 	}
 
 	category := &models.TicketCategory{
-		Name:         req.Name,
-		Slug:         req.Slug,
-		Description:  req.Description,
-		Icon:         req.Icon,
-		Color:        req.Color,
-		IsActive:     req.IsActive,
-		DisplayOrder: req.DisplayOrder,
+		Name:                req.Name,
+		Slug:                req.Slug,
+		Description:         req.Description,
+		Icon:                req.Icon,
+		Color:               req.Color,
+		IsActive:            req.IsActive,
+		DisplayOrder:        req.DisplayOrder,
+		KnowledgeCategoryID: req.KnowledgeCategoryID,
 	}
 
 	// Valeurs par défaut
@@ -73,7 +75,7 @@ func (s *ticketCategoryService) Create(req dto.CreateTicketCategoryRequest) (*dt
 func (s *ticketCategoryService) GetByID(id uint) (*dto.TicketCategoryDTO, error) {
 	category, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	categoryDTO := s.categoryToDTO(category)
@@ -84,7 +86,7 @@ func (s *ticketCategoryService) GetByID(id uint) (*dto.TicketCategoryDTO, error)
 func (s *ticketCategoryService) GetBySlug(slug string) (*dto.TicketCategoryDTO, error) {
 	category, err := s.categoryRepo.FindBySlug(slug)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	categoryDTO := s.categoryToDTO(category)
@@ -125,7 +127,7 @@ func (s *ticketCategoryService) GetActive() ([]dto.TicketCategoryDTO, error) {
 func (s *ticketCategoryService) Update(id uint, req dto.UpdateTicketCategoryRequest) (*dto.TicketCategoryDTO, error) {
 	category, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("catégorie introuvable")
+		return nil, utils.ErrCategoryNotFound
 	}
 
 	// Vérifier que le slug n'existe pas déjà (si modifié)
@@ -155,6 +157,12 @@ func (s *ticketCategoryService) Update(id uint, req dto.UpdateTicketCategoryRequ
 	if req.DisplayOrder != nil {
 		category.DisplayOrder = *req.DisplayOrder
 	}
+	if req.KnowledgeCategoryID != nil {
+		category.KnowledgeCategoryID = req.KnowledgeCategoryID
+		if *req.KnowledgeCategoryID == 0 {
+			category.KnowledgeCategoryID = nil
+		}
+	}
 
 	if err := s.categoryRepo.Update(category); err != nil {
 		return nil, errors.New("erreur lors de la mise à jour de la catégorie")
@@ -174,7 +182,7 @@ func (s *ticketCategoryService) Delete(id uint) error {
 	// Vérifier que la catégorie existe
 	_, err := s.categoryRepo.FindByID(id)
 	if err != nil {
-		return errors.New("catégorie introuvable")
+		return utils.ErrCategoryNotFound
 	}
 
 	// TODO: Vérifier qu'aucun ticket n'utilise cette catégorie avant de supprimer
@@ -189,13 +197,14 @@ func (s *ticketCategoryService) Delete(id uint) error {
 // categoryToDTO convertit un modèle TicketCategory en DTO TicketCategoryDTO
 func (s *ticketCategoryService) categoryToDTO(category *models.TicketCategory) dto.TicketCategoryDTO {
 	return dto.TicketCategoryDTO{
-		ID:           category.ID,
-		Name:         category.Name,
-		Slug:         category.Slug,
-		Description:  category.Description,
-		Icon:         category.Icon,
-		Color:        category.Color,
-		IsActive:     category.IsActive,
-		DisplayOrder: category.DisplayOrder,
+		ID:                  category.ID,
+		Name:                category.Name,
+		Slug:                category.Slug,
+		Description:         category.Description,
+		Icon:                category.Icon,
+		Color:               category.Color,
+		IsActive:            category.IsActive,
+		DisplayOrder:        category.DisplayOrder,
+		KnowledgeCategoryID: category.KnowledgeCategoryID,
 	}
 }
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketChecklistService interface pour la checklist des tickets (étapes de résolution sans sous-ticket)
+type TicketChecklistService interface {
+	GetChecklist(ticketID uint, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error)
+	AddItem(ticketID uint, req dto.CreateTicketChecklistItemRequest, queryScope *scope.QueryScope, createdByID uint) (*dto.TicketChecklistDTO, error)
+	UpdateItem(ticketID, itemID uint, req dto.UpdateTicketChecklistItemRequest, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error)
+	ToggleItem(ticketID, itemID uint, queryScope *scope.QueryScope, userID uint) (*dto.TicketChecklistDTO, error)
+	Reorder(ticketID uint, itemIDs []uint, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error)
+	DeleteItem(ticketID, itemID uint, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error)
+}
+
+// ticketChecklistService implémente TicketChecklistService
+type ticketChecklistService struct {
+	checklistRepo repositories.TicketChecklistRepository
+	ticketRepo    repositories.TicketRepository
+	userRepo      repositories.UserRepository
+	shareRepo     repositories.RecordShareRepository
+}
+
+// NewTicketChecklistService crée une nouvelle instance de TicketChecklistService
+func NewTicketChecklistService(
+	checklistRepo repositories.TicketChecklistRepository,
+	ticketRepo repositories.TicketRepository,
+	userRepo repositories.UserRepository,
+	shareRepo repositories.RecordShareRepository,
+) TicketChecklistService {
+	return &ticketChecklistService{
+		checklistRepo: checklistRepo,
+		ticketRepo:    ticketRepo,
+		userRepo:      userRepo,
+		shareRepo:     shareRepo,
+	}
+}
+
+// GetChecklist récupère la checklist d'un ticket visible par l'utilisateur
+func (s *ticketChecklistService) GetChecklist(ticketID uint, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error) {
+	if err := checkTicketVisible(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	return s.checklist(ticketID)
+}
+
+// AddItem ajoute une étape en fin de checklist
+func (s *ticketChecklistService) AddItem(ticketID uint, req dto.CreateTicketChecklistItemRequest, queryScope *scope.QueryScope, createdByID uint) (*dto.TicketChecklistDTO, error) {
+	if err := s.checkWritable(ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return nil, errors.New("le libellé de l'étape est obligatoire")
+	}
+	if req.AssigneeID != nil {
+		if err := s.checkAssignee(*req.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+	displayOrder, err := s.checklistRepo.NextDisplayOrder(ticketID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'ajout de l'étape")
+	}
+
+	item := &models.TicketChecklistItem{
+		TicketID:     ticketID,
+		Label:        label,
+		AssigneeID:   req.AssigneeID,
+		DisplayOrder: displayOrder,
+		CreatedByID:  createdByID,
+	}
+	if err := s.checklistRepo.Create(item); err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'ajout de l'étape")
+	}
+	return s.checklist(ticketID)
+}
+
+// UpdateItem modifie le libellé ou l'utilisateur chargé d'une étape
+func (s *ticketChecklistService) UpdateItem(ticketID, itemID uint, req dto.UpdateTicketChecklistItemRequest, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error) {
+	item, err := s.findItem(ticketID, itemID, queryScope)
+	if err != nil {
+		return nil, err
+	}
+	if req.Label != nil {
+		label := strings.TrimSpace(*req.Label)
+		if label == "" {
+			return nil, errors.New("le libellé de l'étape est obligatoire")
+		}
+		item.Label = label
+	}
+	if req.ClearAssignee {
+		item.AssigneeID = nil
+	} else if req.AssigneeID != nil {
+		if err := s.checkAssignee(*req.AssigneeID); err != nil {
+			return nil, err
+		}
+		item.AssigneeID = req.AssigneeID
+	}
+
+	if err := s.checklistRepo.Update(item); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'étape")
+	}
+	return s.checklist(ticketID)
+}
+
+// ToggleItem coche ou décoche une étape
+func (s *ticketChecklistService) ToggleItem(ticketID, itemID uint, queryScope *scope.QueryScope, userID uint) (*dto.TicketChecklistDTO, error) {
+	item, err := s.findItem(ticketID, itemID, queryScope)
+	if err != nil {
+		return nil, err
+	}
+	item.Done = !item.Done
+	if item.Done {
+		now := time.Now()
+		item.DoneAt = &now
+		item.DoneByID = &userID
+	} else {
+		item.DoneAt = nil
+		item.DoneByID = nil
+	}
+
+	if err := s.checklistRepo.Update(item); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de l'étape")
+	}
+	return s.checklist(ticketID)
+}
+
+// Reorder réorganise la checklist dans l'ordre des IDs fournis
+func (s *ticketChecklistService) Reorder(ticketID uint, itemIDs []uint, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error) {
+	if err := s.checkWritable(ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	if err := s.checklistRepo.Reorder(ticketID, itemIDs); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la réorganisation de la checklist")
+	}
+	return s.checklist(ticketID)
+}
+
+// DeleteItem supprime une étape de la checklist
+func (s *ticketChecklistService) DeleteItem(ticketID, itemID uint, queryScope *scope.QueryScope) (*dto.TicketChecklistDTO, error) {
+	if _, err := s.findItem(ticketID, itemID, queryScope); err != nil {
+		return nil, err
+	}
+	if err := s.checklistRepo.Delete(itemID); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la suppression de l'étape")
+	}
+	return s.checklist(ticketID)
+}
+
+// findItem récupère une étape du ticket après avoir vérifié que le ticket est modifiable
+func (s *ticketChecklistService) findItem(ticketID, itemID uint, queryScope *scope.QueryScope) (*models.TicketChecklistItem, error) {
+	if err := s.checkWritable(ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	item, err := s.checklistRepo.FindByID(itemID)
+	if err != nil || item.TicketID != ticketID {
+		return nil, utils.ErrChecklistItemNotFound
+	}
+	return item, nil
+}
+
+// checkAssignee vérifie que l'utilisateur chargé d'une étape existe et est actif
+func (s *ticketChecklistService) checkAssignee(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.IsActive {
+		return utils.ErrUserNotFound
+	}
+	return nil
+}
+
+// checklist retourne la checklist d'un ticket et son avancement
+func (s *ticketChecklistService) checklist(ticketID uint) (*dto.TicketChecklistDTO, error) {
+	items, err := s.checklistRepo.FindByTicketID(ticketID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de la checklist")
+	}
+	checklist := &dto.TicketChecklistDTO{
+		Items: make([]dto.TicketChecklistItemDTO, 0, len(items)),
+		Total: len(items),
+	}
+	for i := range items {
+		if items[i].Done {
+			checklist.Done++
+		}
+		checklist.Items = append(checklist.Items, ticketChecklistItemToDTO(&items[i]))
+	}
+	if checklist.Total > 0 {
+		checklist.Progress = checklist.Done * 100 / checklist.Total
+	}
+	return checklist, nil
+}
+
+// checkWritable vérifie que le ticket est modifiable : visible, hors partage en lecture seule et non fusionné
+func (s *ticketChecklistService) checkWritable(ticketID uint, queryScope *scope.QueryScope) error {
+	if err := checkTicketWritable(s.shareRepo, ticketID, queryScope); err != nil {
+		return err
+	}
+	ticket, err := s.ticketRepo.FindByIDForUpdate(ticketID)
+	if err != nil {
+		return utils.ErrTicketNotFound
+	}
+	if ticket.Status == ticketStatusMerged {
+		return errors.New("un ticket fusionné ne peut plus être modifié")
+	}
+	return nil
+}
+
+// ticketChecklistItemToDTO convertit une étape de checklist en DTO
+func ticketChecklistItemToDTO(item *models.TicketChecklistItem) dto.TicketChecklistItemDTO {
+	itemDTO := dto.TicketChecklistItemDTO{
+		ID:           item.ID,
+		TicketID:     item.TicketID,
+		Label:        item.Label,
+		Done:         item.Done,
+		AssigneeID:   item.AssigneeID,
+		DisplayOrder: item.DisplayOrder,
+		DoneAt:       item.DoneAt,
+		CreatedByID:  item.CreatedByID,
+		CreatedAt:    item.CreatedAt,
+		UpdatedAt:    item.UpdatedAt,
+	}
+	if item.Assignee != nil {
+		assigneeDTO := userToDTO(item.Assignee)
+		itemDTO.Assignee = &assigneeDTO
+	}
+	if item.DoneBy != nil {
+		doneByDTO := userToDTO(item.DoneBy)
+		itemDTO.DoneBy = &doneByDTO
+	}
+	return itemDTO
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/spreadsheet"
+)
+
+// TicketExportService interface pour l'export des tickets (CSV ou XLSX) écrit en flux
+type TicketExportService interface {
+	ParseColumns(raw string) ([]string, error) // Colonnes demandées (séparées par des virgules) ou colonnes par défaut
+	Export(queryScope *scope.QueryScope, filter repositories.TicketFilter, format string, columns []string, w io.Writer) error
+}
+
+// ticketExportBatchSize nombre de tickets lus par lot lors d'un export (écrits au fil de l'eau)
+const ticketExportBatchSize = 500
+
+// ticketExportDateLayout format des dates exportées (fuseau de l'utilisateur)
+const ticketExportDateLayout = "2006-01-02 15:04:05"
+
+// ticketExportColumn colonne exportable : clé (en-tête) et extraction de la valeur
+type ticketExportColumn struct {
+	key   string
+	value func(ticket *models.Ticket, loc *time.Location) string
+}
+
+// ticketExportColumns colonnes exportables, dans l'ordre par défaut
+var ticketExportColumns = []ticketExportColumn{
+	{"id", func(t *models.Ticket, _ *time.Location) string { return strconv.FormatUint(uint64(t.ID), 10) }},
+	{"code", func(t *models.Ticket, _ *time.Location) string { return t.Code }},
+	{"title", func(t *models.Ticket, _ *time.Location) string { return t.Title }},
+	{"description", func(t *models.Ticket, _ *time.Location) string { return t.Description }},
+	{"category", func(t *models.Ticket, _ *time.Location) string { return t.Category }},
+	{"source", func(t *models.Ticket, _ *time.Location) string { return t.Source }},
+	{"status", func(t *models.Ticket, _ *time.Location) string { return t.Status }},
+	{"priority", func(t *models.Ticket, _ *time.Location) string { return t.Priority }},
+	{"filiale", func(t *models.Ticket, _ *time.Location) string {
+		if t.Filiale == nil {
+			return ""
+		}
+		return t.Filiale.Name
+	}},
+	{"software", func(t *models.Ticket, _ *time.Location) string {
+		if t.Software == nil {
+			return ""
+		}
+		return t.Software.Name
+	}},
+	{"requester", func(t *models.Ticket, _ *time.Location) string {
+		if t.Requester != nil {
+			return exportUserName(t.Requester)
+		}
+		return t.RequesterName
+	}},
+	{"requester_department", func(t *models.Ticket, _ *time.Location) string { return t.RequesterDepartment }},
+	{"assigned_to", func(t *models.Ticket, _ *time.Location) string {
+		if t.AssignedTo == nil {
+			return ""
+		}
+		return exportUserName(t.AssignedTo)
+	}},
+	{"created_by", func(t *models.Ticket, _ *time.Location) string { return exportUserName(&t.CreatedBy) }},
+	{"estimated_time", func(t *models.Ticket, _ *time.Location) string { return formatOptionalInt(t.EstimatedTime) }},
+	{"actual_time", func(t *models.Ticket, _ *time.Location) string { return formatOptionalInt(t.ActualTime) }},
+	{"parent_id", func(t *models.Ticket, _ *time.Location) string { return formatOptionalID(t.ParentID) }},
+	{"created_at", func(t *models.Ticket, loc *time.Location) string {
+		return t.CreatedAt.In(loc).Format(ticketExportDateLayout)
+	}},
+	{"updated_at", func(t *models.Ticket, loc *time.Location) string {
+		return t.UpdatedAt.In(loc).Format(ticketExportDateLayout)
+	}},
+	{"closed_at", func(t *models.Ticket, loc *time.Location) string {
+		if t.ClosedAt == nil {
+			return ""
+		}
+		return t.ClosedAt.In(loc).Format(ticketExportDateLayout)
+	}},
+}
+
+// ticketExportDefaultColumns colonnes exportées lorsqu'aucune n'est demandée (sans la description, souvent longue)
+var ticketExportDefaultColumns = []string{"code", "title", "category", "status", "priority", "filiale", "requester", "assigned_to", "created_at", "closed_at"}
+
+// ticketExportService implémente TicketExportService
+type ticketExportService struct {
+	ticketRepo repositories.TicketRepository
+}
+
+// NewTicketExportService crée une nouvelle instance de TicketExportService
+func NewTicketExportService(ticketRepo repositories.TicketRepository) TicketExportService {
+	return &ticketExportService{
+		ticketRepo: ticketRepo,
+	}
+}
+
+// ParseColumns valide la liste des colonnes demandées (ex: "code,title,status")
+func (s *ticketExportService) ParseColumns(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return ticketExportDefaultColumns, nil
+	}
+	var columns []string
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if findTicketExportColumn(key) == nil {
+			return nil, fmt.Errorf("colonne d'export inconnue: %s", key)
+		}
+		columns = append(columns, key)
+	}
+	if len(columns) == 0 {
+		return ticketExportDefaultColumns, nil
+	}
+	return columns, nil
+}
+
+// Export écrit les tickets filtrés (dans le périmètre du scope) en CSV ou XLSX, lot par lot
+func (s *ticketExportService) Export(queryScope *scope.QueryScope, filter repositories.TicketFilter, format string, columns []string, w io.Writer) error {
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		return errors.New("format d'export non supporté (csv ou xlsx)")
+	}
+	selected := make([]*ticketExportColumn, 0, len(columns))
+	for _, key := range columns {
+		column := findTicketExportColumn(key)
+		if column == nil {
+			return fmt.Errorf("colonne d'export inconnue: %s", key)
+		}
+		selected = append(selected, column)
+	}
+	loc := queryScope.Loc()
+	flusher, _ := w.(interface{ Flush() })
+
+	var writer spreadsheet.Writer
+	var afterID uint
+	for {
+		tickets, err := s.ticketRepo.FindForExport(queryScope, filter, afterID, ticketExportBatchSize)
+		if err != nil {
+			return errors.New("erreur lors de l'export des tickets")
+		}
+		// En-tête écrit après la première lecture : une erreur initiale peut encore être retournée au client
+		if writer == nil {
+			if writer, err = spreadsheet.NewWriter(w, format); err != nil {
+				return err
+			}
+			if err := writer.WriteRow(columns); err != nil {
+				return err
+			}
+		}
+		row := make([]string, len(selected))
+		for i := range tickets {
+			ticket := &tickets[i]
+			afterID = ticket.ID
+			for j, column := range selected {
+				row[j] = column.value(ticket, loc)
+			}
+			if err := writer.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		if len(tickets) < ticketExportBatchSize {
+			return writer.Close()
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// findTicketExportColumn retrouve une colonne exportable par sa clé
+func findTicketExportColumn(key string) *ticketExportColumn {
+	for i := range ticketExportColumns {
+		if ticketExportColumns[i].key == key {
+			return &ticketExportColumns[i]
+		}
+	}
+	return nil
+}
+
+// exportUserName nom affiché d'un utilisateur dans un export
+func exportUserName(user *models.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		return user.Username
+	}
+	return name
+}
+
+// formatOptionalInt formate un entier optionnel (vide si absent)
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
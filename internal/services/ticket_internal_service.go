@@ -9,6 +9,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // TicketInternalService interface pour les opérations sur les tickets internes
@@ -68,7 +69,7 @@ func (s *ticketInternalService) Create(req dto.CreateTicketInternalRequest, crea
 	}
 	dept, err := s.departmentRepo.FindByID(req.DepartmentID)
 	if err != nil || dept == nil {
-		return nil, errors.New("département introuvable")
+		return nil, utils.ErrDepartmentNotFound
 	}
 	if dept.IsITDepartment {
 		return nil, errors.New("les tickets internes ne concernent que les départements non-IT")
@@ -199,7 +200,7 @@ func (s *ticketInternalService) Assign(id uint, req dto.AssignTicketInternalRequ
 	if req.AssignedToID != nil && *req.AssignedToID != 0 && !allowAssignAny {
 		assigner, errAssigner := s.userRepo.FindByID(assignedByID)
 		if errAssigner != nil {
-			return nil, errors.New("utilisateur introuvable")
+			return nil, utils.ErrUserNotFound
 		}
 		if assigner.DepartmentID == nil {
 			return nil, errors.New("vous ne pouvez assigner qu'à un membre de votre département")
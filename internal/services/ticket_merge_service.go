@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// ticketStatusMerged statut d'un ticket fusionné dans un ticket principal (statut final)
+const ticketStatusMerged = "fusionne"
+
+// Paramètres de la suggestion de doublons
+const (
+	duplicateLookbackDays    = 90  // Seuls les tickets créés sur cette période sont comparés
+	duplicateCandidateLimit  = 500 // Nombre maximal de tickets comparés
+	duplicateMinSimilarity   = 0.5 // Similarité minimale des titres (indice de Jaccard des mots significatifs)
+	duplicateSuggestionLimit = 10
+)
+
+// titleStopWords mots ignorés dans la comparaison des titres
+var titleStopWords = map[string]bool{
+	"les": true, "des": true, "une": true, "pour": true, "dans": true, "sur": true, "avec": true, "pas": true,
+	"est": true, "que": true, "qui": true, "par": true, "aux": true,
+	"the": true, "and": true, "for": true, "not": true, "with": true,
+}
+
+// accentFolder supprime les accents des titres mis en minuscules
+var accentFolder = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "é", "e", "è", "e", "ê", "e", "ë", "e", "î", "i", "ï", "i",
+	"ô", "o", "ö", "o", "ù", "u", "û", "u", "ü", "u", "ç", "c", "ÿ", "y", "œ", "oe", "æ", "ae",
+)
+
+// TicketMergeService interface pour la fusion des tickets en double et la suggestion de doublons
+type TicketMergeService interface {
+	Merge(primaryID uint, req dto.MergeTicketsRequest, queryScope *scope.QueryScope, mergedByID uint) (*dto.TicketMergeResultDTO, error)
+	SuggestDuplicates(ticketID uint, queryScope *scope.QueryScope, limit int) ([]dto.TicketDuplicateDTO, error)
+}
+
+// ticketMergeService implémente TicketMergeService
+type ticketMergeService struct {
+	ticketRepo    repositories.TicketRepository
+	shareRepo     repositories.RecordShareRepository
+	ticketService TicketService
+	eventBus      *events.Bus
+}
+
+// NewTicketMergeService crée une nouvelle instance de TicketMergeService
+func NewTicketMergeService(
+	ticketRepo repositories.TicketRepository,
+	shareRepo repositories.RecordShareRepository,
+	ticketService TicketService,
+	eventBus *events.Bus,
+) TicketMergeService {
+	return &ticketMergeService{
+		ticketRepo:    ticketRepo,
+		shareRepo:     shareRepo,
+		ticketService: ticketService,
+		eventBus:      eventBus,
+	}
+}
+
+// Merge fusionne les doublons dans le ticket principal : commentaires, pièces jointes, entrées de temps et SLA sont rattachés au principal,
+// les doublons passent au statut fusionne avec un renvoi vers le principal, puis les demandeurs de tous les tickets sont notifiés
+// Tous les tickets doivent être modifiables par l'utilisateur (dans son périmètre, hors partage en lecture seule)
+func (s *ticketMergeService) Merge(primaryID uint, req dto.MergeTicketsRequest, queryScope *scope.QueryScope, mergedByID uint) (*dto.TicketMergeResultDTO, error) {
+	primary, err := s.ticketRepo.FindByIDLean(primaryID)
+	if err != nil {
+		return nil, utils.ErrTicketNotFound
+	}
+	if err := checkTicketWritable(s.shareRepo, primaryID, queryScope); err != nil {
+		return nil, err
+	}
+	if primary.Status == ticketStatusMerged {
+		return nil, errors.New("le ticket principal a déjà été fusionné dans un autre ticket")
+	}
+
+	duplicateIDs := make([]uint, 0, len(req.DuplicateIDs))
+	for _, id := range req.DuplicateIDs {
+		if id == primaryID {
+			return nil, errors.New("le ticket principal ne peut pas être fusionné avec lui-même")
+		}
+		if !slices.Contains(duplicateIDs, id) {
+			duplicateIDs = append(duplicateIDs, id)
+		}
+	}
+
+	now := time.Now()
+	duplicates := make([]*models.Ticket, 0, len(duplicateIDs))
+	histories := make([]models.TicketHistory, 0, len(duplicateIDs)+1)
+	codes := make([]string, 0, len(duplicateIDs))
+	for _, id := range duplicateIDs {
+		duplicate, err := s.ticketRepo.FindByIDLean(id)
+		if err != nil {
+			return nil, utils.ErrTicketNotFound.WithDetails(map[string]any{"ticket_id": id})
+		}
+		if err := checkTicketWritable(s.shareRepo, id, queryScope); err != nil {
+			return nil, utils.ErrTicketNotFound.WithDetails(map[string]any{"ticket_id": id})
+		}
+		if duplicate.Status == ticketStatusMerged {
+			return nil, fmt.Errorf("le ticket %s a déjà été fusionné", duplicate.Code)
+		}
+		duplicates = append(duplicates, duplicate)
+		codes = append(codes, duplicate.Code)
+		histories = append(histories, models.TicketHistory{
+			TicketID:    id,
+			UserID:      mergedByID,
+			Action:      "merged",
+			FieldName:   "status",
+			OldValue:    duplicate.Status,
+			NewValue:    ticketStatusMerged,
+			Description: fmt.Sprintf("Fusionné dans le ticket %s", primary.Code),
+			CreatedAt:   now,
+		})
+	}
+	histories = append(histories, models.TicketHistory{
+		TicketID:    primaryID,
+		UserID:      mergedByID,
+		Action:      "merged",
+		NewValue:    strings.Join(codes, ", "),
+		Description: fmt.Sprintf("Tickets fusionnés dans ce ticket : %s", strings.Join(codes, ", ")),
+		CreatedAt:   now,
+	})
+
+	result, err := s.ticketRepo.Merge(primaryID, duplicateIDs, histories, now)
+	if err != nil {
+		log.Printf("Erreur lors de la fusion des tickets %v dans le ticket %d: %v", duplicateIDs, primaryID, err)
+		return nil, utils.NewInternalError("erreur lors de la fusion des tickets")
+	}
+
+	ticketDTO, err := s.ticketService.GetByID(primaryID, false)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du ticket")
+	}
+
+	// Demandeur de chaque ticket (à défaut son créateur) et codes de ses tickets concernés par la fusion
+	requesterTickets := map[uint][]string{}
+	for _, ticket := range append([]*models.Ticket{primary}, duplicates...) {
+		requesterID := ticket.CreatedByID
+		if ticket.RequesterID != nil {
+			requesterID = *ticket.RequesterID
+		}
+		requesterTickets[requesterID] = append(requesterTickets[requesterID], ticket.Code)
+	}
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       events.TicketMerged,
+		ActorID:    &mergedByID,
+		FilialeID:  primary.FilialeID,
+		EntityType: "tickets",
+		EntityID:   primaryID,
+		Data:       *ticketDTO,
+		Metadata: map[string]any{
+			"merged_ticket_ids":   duplicateIDs,
+			"merged_ticket_codes": codes,
+			"requester_tickets":   requesterTickets,
+		},
+	})
+
+	return &dto.TicketMergeResultDTO{
+		Ticket:           *ticketDTO,
+		MergedTicketIDs:  duplicateIDs,
+		MovedComments:    result.Comments,
+		MovedAttachments: result.Attachments,
+		MovedTimeEntries: result.TimeEntries,
+		SLAMoved:         result.SLAMoved,
+	}, nil
+}
+
+// SuggestDuplicates retourne les tickets non clôturés de la même filiale dont le titre est proche, du plus similaire au moins similaire
+func (s *ticketMergeService) SuggestDuplicates(ticketID uint, queryScope *scope.QueryScope, limit int) ([]dto.TicketDuplicateDTO, error) {
+	ticket, err := s.ticketRepo.FindByIDLean(ticketID)
+	if err != nil || queryScope == nil {
+		return nil, utils.ErrTicketNotFound
+	}
+	if visible, err := s.shareRepo.IsVisible(models.ShareResourceTicket, ticketID, queryScope); err != nil || !visible {
+		return nil, utils.ErrTicketNotFound
+	}
+	if limit < 1 || limit > duplicateSuggestionLimit {
+		limit = duplicateSuggestionLimit
+	}
+
+	tokens := titleTokens(ticket.Title)
+	suggestions := []dto.TicketDuplicateDTO{}
+	if len(tokens) == 0 {
+		return suggestions, nil
+	}
+	since := time.Now().AddDate(0, 0, -duplicateLookbackDays)
+	candidates, err := s.ticketRepo.FindMergeCandidates(queryScope, ticketID, ticket.FilialeID, since, duplicateCandidateLimit)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la recherche des doublons")
+	}
+	for _, candidate := range candidates {
+		similarity := titleSimilarity(tokens, titleTokens(candidate.Title))
+		if similarity < duplicateMinSimilarity {
+			continue
+		}
+		suggestions = append(suggestions, dto.TicketDuplicateDTO{
+			ID:         candidate.ID,
+			Code:       candidate.Code,
+			Title:      candidate.Title,
+			Status:     candidate.Status,
+			Priority:   candidate.Priority,
+			Similarity: float64(int(similarity*100+0.5)) / 100,
+			CreatedAt:  candidate.CreatedAt,
+		})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].Similarity > suggestions[j].Similarity })
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// titleTokens retourne les mots significatifs d'un titre (minuscules, sans accents, au moins 3 caractères hors mots vides)
+func titleTokens(title string) map[string]bool {
+	normalized := accentFolder.Replace(strings.ToLower(title))
+	words := strings.FieldsFunc(normalized, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make(map[string]bool, len(words))
+	for _, word := range words {
+		if len([]rune(word)) >= 3 && !titleStopWords[word] {
+			tokens[word] = true
+		}
+	}
+	return tokens
+}
+
+// titleSimilarity calcule l'indice de Jaccard de deux ensembles de mots
+func titleSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range a {
+		if b[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
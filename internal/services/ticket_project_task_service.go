@@ -0,0 +1,268 @@
+package services
+
+import (
+	"errors"
+	"log"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketProjectTaskService interface pour les liens entre tickets et tâches de projet
+// Le temps saisi sur un ticket lié est reporté dans le ticket_time de la tâche et dans le temps consommé du projet.
+type TicketProjectTaskService interface {
+	// Côté tâche (tickets limités à ceux visibles par l'utilisateur)
+	GetTicketsByTask(projectID, taskID uint, queryScope *scope.QueryScope) ([]dto.ProjectTaskTicketDTO, error)
+	LinkTicket(projectID, taskID, ticketID uint, queryScope *scope.QueryScope, createdByID uint) ([]dto.ProjectTaskTicketDTO, error)
+	UnlinkTicket(projectID, taskID, ticketID uint, queryScope *scope.QueryScope) ([]dto.ProjectTaskTicketDTO, error)
+
+	// Côté ticket (tâches limitées aux projets du périmètre)
+	GetTasksByTicket(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketProjectTaskDTO, error)
+	LinkTask(ticketID, taskID uint, queryScope *scope.QueryScope, createdByID uint) ([]dto.TicketProjectTaskDTO, error)
+	UnlinkTask(ticketID, taskID uint, queryScope *scope.QueryScope) ([]dto.TicketProjectTaskDTO, error)
+
+	AllowArchivedWrites() TicketProjectTaskService // Copie du service autorisée à modifier les projets archivés
+}
+
+// ticketProjectTaskService implémente TicketProjectTaskService
+type ticketProjectTaskService struct {
+	linkRepo       repositories.TicketProjectTaskRepository
+	taskRepo       repositories.ProjectTaskRepository
+	ticketRepo     repositories.TicketRepository
+	shareRepo      repositories.RecordShareRepository
+	projectService ProjectService
+}
+
+// NewTicketProjectTaskService crée une nouvelle instance de TicketProjectTaskService
+func NewTicketProjectTaskService(
+	linkRepo repositories.TicketProjectTaskRepository,
+	taskRepo repositories.ProjectTaskRepository,
+	ticketRepo repositories.TicketRepository,
+	shareRepo repositories.RecordShareRepository,
+	projectService ProjectService,
+) TicketProjectTaskService {
+	return &ticketProjectTaskService{
+		linkRepo:       linkRepo,
+		taskRepo:       taskRepo,
+		ticketRepo:     ticketRepo,
+		shareRepo:      shareRepo,
+		projectService: projectService,
+	}
+}
+
+// GetTicketsByTask récupère les tickets liés à une tâche du projet
+func (s *ticketProjectTaskService) GetTicketsByTask(projectID, taskID uint, queryScope *scope.QueryScope) ([]dto.ProjectTaskTicketDTO, error) {
+	if _, err := s.findTask(projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.ticketsOf(taskID, queryScope)
+}
+
+// LinkTicket lie un ticket visible à une tâche du projet
+func (s *ticketProjectTaskService) LinkTicket(projectID, taskID, ticketID uint, queryScope *scope.QueryScope, createdByID uint) ([]dto.ProjectTaskTicketDTO, error) {
+	task, err := s.findTask(projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projectService.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	if exists, err := s.ticketRepo.ExistsByID(ticketID); err != nil || !exists {
+		return nil, utils.ErrTicketNotFound
+	}
+	if err := checkTicketVisible(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	if err := s.link(task, ticketID, createdByID); err != nil {
+		return nil, err
+	}
+	return s.ticketsOf(taskID, queryScope)
+}
+
+// UnlinkTicket supprime le lien entre un ticket et une tâche du projet
+func (s *ticketProjectTaskService) UnlinkTicket(projectID, taskID, ticketID uint, queryScope *scope.QueryScope) ([]dto.ProjectTaskTicketDTO, error) {
+	if _, err := s.findTask(projectID, taskID); err != nil {
+		return nil, err
+	}
+	if err := s.projectService.CheckWritable(projectID); err != nil {
+		return nil, err
+	}
+	if err := s.unlink(ticketID, taskID); err != nil {
+		return nil, err
+	}
+	return s.ticketsOf(taskID, queryScope)
+}
+
+// GetTasksByTicket récupère les tâches liées à un ticket visible
+func (s *ticketProjectTaskService) GetTasksByTicket(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketProjectTaskDTO, error) {
+	if err := checkTicketVisible(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	return s.tasksOf(ticketID, queryScope)
+}
+
+// LinkTask lie le ticket à une tâche de projet
+func (s *ticketProjectTaskService) LinkTask(ticketID, taskID uint, queryScope *scope.QueryScope, createdByID uint) ([]dto.TicketProjectTaskDTO, error) {
+	if err := checkTicketWritable(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, utils.ErrProjectTaskNotFound
+	}
+	if err := s.projectService.CheckWritable(task.ProjectID); err != nil {
+		return nil, err
+	}
+	if err := s.link(task, ticketID, createdByID); err != nil {
+		return nil, err
+	}
+	return s.tasksOf(ticketID, queryScope)
+}
+
+// UnlinkTask supprime le lien entre le ticket et une tâche de projet
+func (s *ticketProjectTaskService) UnlinkTask(ticketID, taskID uint, queryScope *scope.QueryScope) ([]dto.TicketProjectTaskDTO, error) {
+	if err := checkTicketWritable(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, utils.ErrTicketTaskLinkNotFound
+	}
+	if err := s.projectService.CheckWritable(task.ProjectID); err != nil {
+		return nil, err
+	}
+	if err := s.unlink(ticketID, taskID); err != nil {
+		return nil, err
+	}
+	return s.tasksOf(ticketID, queryScope)
+}
+
+// AllowArchivedWrites retourne une copie du service autorisée à modifier les liens des tâches des projets archivés
+func (s *ticketProjectTaskService) AllowArchivedWrites() TicketProjectTaskService {
+	writable := *s
+	writable.projectService = s.projectService.AllowArchivedWrites()
+	return &writable
+}
+
+// findTask récupère une tâche en vérifiant qu'elle appartient au projet
+func (s *ticketProjectTaskService) findTask(projectID, taskID uint) (*models.ProjectTask, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil || task.ProjectID != projectID {
+		return nil, utils.ErrProjectTaskNotFound
+	}
+	return task, nil
+}
+
+// link crée le lien (un ticket est lié à au plus une tâche par projet) et reporte le temps du ticket sur la tâche
+func (s *ticketProjectTaskService) link(task *models.ProjectTask, ticketID, createdByID uint) error {
+	existing, err := s.linkRepo.FindByTicketAndProject(ticketID, task.ProjectID)
+	if err != nil {
+		log.Printf("[LinkTicketProjectTask] ticket=%d task=%d: %v", ticketID, task.ID, err)
+		return utils.NewInternalError("erreur lors de la liaison du ticket à la tâche")
+	}
+	if existing != nil {
+		if existing.ProjectTaskID == task.ID {
+			return errors.New("ce ticket est déjà lié à cette tâche")
+		}
+		return errors.New("ce ticket est déjà lié à une autre tâche de ce projet")
+	}
+	link := &models.TicketProjectTask{
+		TicketID:      ticketID,
+		ProjectID:     task.ProjectID,
+		ProjectTaskID: task.ID,
+		CreatedByID:   createdByID,
+	}
+	if err := s.linkRepo.Create(link); err != nil {
+		log.Printf("[LinkTicketProjectTask] ticket=%d task=%d: %v", ticketID, task.ID, err)
+		return utils.NewInternalError("erreur lors de la liaison du ticket à la tâche")
+	}
+	if err := s.linkRepo.RefreshTicketTime([]uint{task.ID}); err != nil {
+		log.Printf("[LinkTicketProjectTask] refresh task=%d: %v", task.ID, err)
+	}
+	return nil
+}
+
+// unlink supprime le lien et retire le temps du ticket de la tâche
+func (s *ticketProjectTaskService) unlink(ticketID, taskID uint) error {
+	deleted, err := s.linkRepo.Delete(ticketID, taskID)
+	if err != nil {
+		log.Printf("[UnlinkTicketProjectTask] ticket=%d task=%d: %v", ticketID, taskID, err)
+		return utils.NewInternalError("erreur lors de la suppression du lien")
+	}
+	if !deleted {
+		return utils.ErrTicketTaskLinkNotFound
+	}
+	if err := s.linkRepo.RefreshTicketTime([]uint{taskID}); err != nil {
+		log.Printf("[UnlinkTicketProjectTask] refresh task=%d: %v", taskID, err)
+	}
+	return nil
+}
+
+// ticketsOf retourne les tickets liés à la tâche et visibles par l'utilisateur
+func (s *ticketProjectTaskService) ticketsOf(taskID uint, queryScope *scope.QueryScope) ([]dto.ProjectTaskTicketDTO, error) {
+	links, err := s.linkRepo.FindByTaskID(taskID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des tickets liés")
+	}
+	tickets := make([]dto.ProjectTaskTicketDTO, 0, len(links))
+	if queryScope == nil {
+		return tickets, nil
+	}
+	for i := range links {
+		ticket := links[i].Ticket
+		if ticket == nil {
+			continue // Ticket supprimé
+		}
+		if visible, err := s.shareRepo.IsVisible(models.ShareResourceTicket, ticket.ID, queryScope); err != nil || !visible {
+			continue
+		}
+		ticketDTO := dto.ProjectTaskTicketDTO{
+			ID: links[i].ID,
+			Ticket: dto.RelatedTicketDTO{
+				ID:       ticket.ID,
+				Code:     ticket.Code,
+				Title:    ticket.Title,
+				Status:   ticket.Status,
+				Priority: ticket.Priority,
+			},
+			LinkedAt: links[i].CreatedAt,
+		}
+		if ticket.ActualTime != nil {
+			ticketDTO.TimeSpent = *ticket.ActualTime
+		}
+		tickets = append(tickets, ticketDTO)
+	}
+	return tickets, nil
+}
+
+// tasksOf retourne les tâches liées au ticket dans les projets du périmètre
+func (s *ticketProjectTaskService) tasksOf(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketProjectTaskDTO, error) {
+	links, err := s.linkRepo.FindByTicketID(ticketID, queryScope)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des tâches liées")
+	}
+	tasks := make([]dto.TicketProjectTaskDTO, 0, len(links))
+	for i := range links {
+		link := &links[i]
+		if link.ProjectTask == nil {
+			continue
+		}
+		taskDTO := dto.TicketProjectTaskDTO{
+			ID:         link.ID,
+			ProjectID:  link.ProjectID,
+			TaskID:     link.ProjectTaskID,
+			TaskCode:   link.ProjectTask.Code,
+			TaskTitle:  link.ProjectTask.Title,
+			TaskStatus: link.ProjectTask.Status,
+			LinkedAt:   link.CreatedAt,
+		}
+		if link.Project != nil {
+			taskDTO.ProjectName = link.Project.Name
+		}
+		tasks = append(tasks, taskDTO)
+	}
+	return tasks, nil
+}
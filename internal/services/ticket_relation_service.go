@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketRelationService interface pour les relations entre tickets (liens incident / problème / changement, blocages, doublons)
+type TicketRelationService interface {
+	GetRelations(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketRelationDTO, error) // Limitées aux tickets liés visibles
+	Link(ticketID uint, req dto.CreateTicketRelationRequest, queryScope *scope.QueryScope, createdByID uint) ([]dto.TicketRelationDTO, error)
+	Unlink(ticketID, relationID uint, queryScope *scope.QueryScope) ([]dto.TicketRelationDTO, error)
+}
+
+// ticketRelationService implémente TicketRelationService
+type ticketRelationService struct {
+	relationRepo repositories.TicketRelationRepository
+	ticketRepo   repositories.TicketRepository
+	shareRepo    repositories.RecordShareRepository
+}
+
+// NewTicketRelationService crée une nouvelle instance de TicketRelationService
+func NewTicketRelationService(
+	relationRepo repositories.TicketRelationRepository,
+	ticketRepo repositories.TicketRepository,
+	shareRepo repositories.RecordShareRepository,
+) TicketRelationService {
+	return &ticketRelationService{
+		relationRepo: relationRepo,
+		ticketRepo:   ticketRepo,
+		shareRepo:    shareRepo,
+	}
+}
+
+// GetRelations récupère les relations d'un ticket visible, du point de vue de ce ticket
+func (s *ticketRelationService) GetRelations(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketRelationDTO, error) {
+	if err := checkTicketVisible(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	return s.relations(ticketID, queryScope)
+}
+
+// Link lie le ticket à un autre ticket visible ; « blocked_by » est enregistré comme « blocks » dans l'autre sens
+func (s *ticketRelationService) Link(ticketID uint, req dto.CreateTicketRelationRequest, queryScope *scope.QueryScope, createdByID uint) ([]dto.TicketRelationDTO, error) {
+	if err := checkTicketWritable(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	if req.TicketID == ticketID {
+		return nil, errors.New("un ticket ne peut pas être lié à lui-même")
+	}
+	if exists, err := s.ticketRepo.ExistsByID(req.TicketID); err != nil || !exists {
+		return nil, utils.ErrTicketNotFound.WithDetails(map[string]any{"ticket_id": req.TicketID})
+	}
+	if err := checkTicketVisible(s.shareRepo, req.TicketID, queryScope); err != nil {
+		return nil, utils.ErrTicketNotFound.WithDetails(map[string]any{"ticket_id": req.TicketID})
+	}
+	linked, err := s.relationRepo.ExistsBetween(ticketID, req.TicketID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la vérification des relations du ticket")
+	}
+	if linked {
+		return nil, errors.New("ces tickets sont déjà liés")
+	}
+
+	relation := &models.TicketRelation{
+		SourceTicketID: ticketID,
+		TargetTicketID: req.TicketID,
+		Type:           req.Type,
+		CreatedByID:    createdByID,
+	}
+	if req.Type == models.TicketRelationBlockedBy {
+		relation.SourceTicketID, relation.TargetTicketID = req.TicketID, ticketID
+		relation.Type = models.TicketRelationBlocks
+	}
+	if err := s.relationRepo.Create(relation); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la liaison des tickets")
+	}
+	return s.relations(ticketID, queryScope)
+}
+
+// Unlink supprime une relation du ticket
+func (s *ticketRelationService) Unlink(ticketID, relationID uint, queryScope *scope.QueryScope) ([]dto.TicketRelationDTO, error) {
+	if err := checkTicketWritable(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	relation, err := s.relationRepo.FindByID(relationID)
+	if err != nil || (relation.SourceTicketID != ticketID && relation.TargetTicketID != ticketID) {
+		return nil, utils.ErrTicketRelationNotFound
+	}
+	if err := s.relationRepo.Delete(relationID); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la suppression de la relation")
+	}
+	return s.relations(ticketID, queryScope)
+}
+
+// relations retourne les relations du ticket vers des tickets visibles par l'utilisateur
+func (s *ticketRelationService) relations(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketRelationDTO, error) {
+	relations, err := s.relationRepo.FindByTicketID(ticketID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des relations du ticket")
+	}
+	relationDTOs := make([]dto.TicketRelationDTO, 0, len(relations))
+	for i := range relations {
+		relation := &relations[i]
+		relationType, other := relation.Type, relation.TargetTicket
+		if relation.TargetTicketID == ticketID {
+			relationType, other = models.TicketRelationInverse(relation.Type), relation.SourceTicket
+		}
+		if other == nil {
+			continue // Ticket lié supprimé
+		}
+		if visible, err := s.shareRepo.IsVisible(models.ShareResourceTicket, other.ID, queryScope); err != nil || !visible {
+			continue
+		}
+		relationDTOs = append(relationDTOs, ticketRelationToDTO(relation, relationType, other))
+	}
+	return relationDTOs, nil
+}
+
+// ticketRelationToDTO convertit une relation en DTO, avec son type vu depuis le ticket consulté et l'autre ticket
+func ticketRelationToDTO(relation *models.TicketRelation, relationType string, other *models.Ticket) dto.TicketRelationDTO {
+	return dto.TicketRelationDTO{
+		ID:   relation.ID,
+		Type: relationType,
+		Ticket: dto.RelatedTicketDTO{
+			ID:       other.ID,
+			Code:     other.Code,
+			Title:    other.Title,
+			Status:   other.Status,
+			Priority: other.Priority,
+		},
+		CreatedAt: relation.CreatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketSatisfactionService interface pour les enquêtes de satisfaction (CSAT) envoyées à la clôture des tickets
+type TicketSatisfactionService interface {
+	HandleEvent(ctx context.Context, event events.Event) error // Abonné au bus : crée l'enquête à la validation ou à la clôture
+	GetSurvey(ticketID uint, token string) (*dto.TicketSatisfactionSurveyDTO, error)
+	Submit(ticketID uint, req dto.SubmitTicketSatisfactionRequest) (*dto.TicketSatisfactionSurveyDTO, error)
+}
+
+// ticketSatisfactionService implémente TicketSatisfactionService
+type ticketSatisfactionService struct {
+	satisfactionRepo    repositories.TicketSatisfactionRepository
+	notificationService NotificationService
+}
+
+// NewTicketSatisfactionService crée une nouvelle instance de TicketSatisfactionService
+func NewTicketSatisfactionService(satisfactionRepo repositories.TicketSatisfactionRepository, notificationService NotificationService) TicketSatisfactionService {
+	return &ticketSatisfactionService{
+		satisfactionRepo:    satisfactionRepo,
+		notificationService: notificationService,
+	}
+}
+
+// HandleEvent crée l'enquête de satisfaction du ticket validé ou clôturé et l'envoie au demandeur (à défaut au créateur)
+// Un ticket n'a qu'une enquête : une nouvelle clôture après réouverture n'en génère pas d'autre
+func (s *ticketSatisfactionService) HandleEvent(ctx context.Context, event events.Event) error {
+	if event.Type != events.TicketStatusChanged {
+		return nil
+	}
+	ticket, ok := event.Data.(dto.TicketDTO)
+	if !ok {
+		return nil
+	}
+	validated, _ := event.Metadata["validated"].(bool)
+	newStatus, _ := event.Metadata["new_status"].(string)
+	if !validated && newStatus != "cloture" {
+		return nil
+	}
+	requesterID := ticket.CreatedBy.ID
+	if ticket.RequesterID != nil {
+		requesterID = *ticket.RequesterID
+	}
+	if requesterID == 0 {
+		return nil
+	}
+
+	var agentID *uint
+	if ticket.Lead != nil {
+		agentID = &ticket.Lead.ID
+	} else if ticket.AssignedTo != nil {
+		agentID = &ticket.AssignedTo.ID
+	}
+	token, err := generateInvitationToken()
+	if err != nil {
+		return fmt.Errorf("token de l'enquête de satisfaction du ticket %d: %w", ticket.ID, err)
+	}
+	survey := &models.TicketSatisfaction{
+		TicketID:    ticket.ID,
+		RequesterID: requesterID,
+		AgentID:     agentID,
+		FilialeID:   ticket.FilialeID,
+		TokenHash:   utils.HashString(token),
+		ExpiresAt:   time.Now().Add(config.AppConfig.App.SatisfactionSurveyTTL),
+	}
+	created, err := s.satisfactionRepo.Create(survey)
+	if err != nil {
+		return fmt.Errorf("enquête de satisfaction du ticket %d: %w", ticket.ID, err)
+	}
+	if !created || s.notificationService == nil {
+		return nil
+	}
+
+	title := fmt.Sprintf("Votre avis sur le ticket %s", ticket.Code)
+	message := fmt.Sprintf("Le ticket %s « %s » est résolu. Merci de nous indiquer en un clic si sa prise en charge vous a satisfait.", ticket.Code, ticket.Title)
+	linkURL := fmt.Sprintf("/satisfaction/%d?token=%s", ticket.ID, url.QueryEscape(token))
+	metadata := map[string]any{"ticket_id": ticket.ID, "ticket_code": ticket.Code, "ticket_title": ticket.Title}
+	return s.notificationService.Create(requesterID, "ticket_satisfaction", title, message, linkURL, metadata)
+}
+
+// GetSurvey récupère l'enquête désignée par le token du lien (pour afficher le formulaire ou la réponse déjà donnée)
+func (s *ticketSatisfactionService) GetSurvey(ticketID uint, token string) (*dto.TicketSatisfactionSurveyDTO, error) {
+	survey, err := s.findSurvey(ticketID, token)
+	if err != nil {
+		return nil, err
+	}
+	return ticketSatisfactionToDTO(survey), nil
+}
+
+// Submit enregistre la note et le commentaire du demandeur (une seule réponse par enquête)
+func (s *ticketSatisfactionService) Submit(ticketID uint, req dto.SubmitTicketSatisfactionRequest) (*dto.TicketSatisfactionSurveyDTO, error) {
+	survey, err := s.findSurvey(ticketID, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if survey.AnsweredAt != nil {
+		return nil, utils.ErrSurveyAnswered
+	}
+	if time.Now().After(survey.ExpiresAt) {
+		return nil, utils.ErrSurveyExpired
+	}
+
+	now := time.Now()
+	comment := strings.TrimSpace(req.Comment)
+	answered, err := s.satisfactionRepo.Answer(survey.ID, req.Rating, comment, now)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'enregistrement de la réponse")
+	}
+	if !answered {
+		return nil, utils.ErrSurveyAnswered
+	}
+	survey.Rating = &req.Rating
+	survey.Comment = comment
+	survey.AnsweredAt = &now
+	return ticketSatisfactionToDTO(survey), nil
+}
+
+// findSurvey retrouve l'enquête par son token et vérifie qu'elle concerne bien le ticket de l'URL
+func (s *ticketSatisfactionService) findSurvey(ticketID uint, token string) (*models.TicketSatisfaction, error) {
+	if token == "" {
+		return nil, utils.ErrSurveyNotFound
+	}
+	survey, err := s.satisfactionRepo.FindByTokenHash(utils.HashString(token))
+	if err != nil || survey.TicketID != ticketID {
+		return nil, utils.ErrSurveyNotFound
+	}
+	return survey, nil
+}
+
+// ticketSatisfactionToDTO convertit une enquête de satisfaction en DTO
+func ticketSatisfactionToDTO(survey *models.TicketSatisfaction) *dto.TicketSatisfactionSurveyDTO {
+	return &dto.TicketSatisfactionSurveyDTO{
+		TicketID:    survey.TicketID,
+		TicketCode:  survey.Ticket.Code,
+		TicketTitle: survey.Ticket.Title,
+		Answered:    survey.AnsweredAt != nil,
+		Rating:      survey.Rating,
+		Comment:     survey.Comment,
+		AnsweredAt:  survey.AnsweredAt,
+		ExpiresAt:   survey.ExpiresAt,
+	}
+}
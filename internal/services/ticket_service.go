@@ -1,18 +1,25 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"math"
 	"strings"
 	"time"
 
 	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/jobs"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // TicketService interface pour les opérations sur les tickets
@@ -21,6 +28,7 @@ type TicketService interface {
 	GetByID(id uint, includeDepartment bool) (*dto.TicketDTO, error)
 	GetAll(scope interface{}, page, limit int) (*dto.TicketListResponse, error) // scope peut être *scope.QueryScope ou nil
 	GetAllWithFilters(scope interface{}, page, limit int, status string, filialeID *uint, assigneeUserID *uint) (*dto.TicketListResponse, error)
+	GetByFilter(scope interface{}, filter repositories.TicketFilter, page, limit int) (*dto.TicketListResponse, error) // Vues enregistrées
 	GetByStatus(scope interface{}, status string, page, limit int) (*dto.TicketListResponse, error)
 	GetByCategory(scope interface{}, category string, page, limit int, status, priority string) (*dto.TicketListResponse, error)
 	GetBySource(scope interface{}, source string, page, limit int) (*dto.TicketListResponse, error)
@@ -40,22 +48,29 @@ type TicketService interface {
 	GetComments(ticketID uint, canViewInternalComments bool) ([]dto.TicketCommentDTO, error)
 	UpdateComment(ticketID uint, commentID uint, req dto.UpdateTicketCommentRequest, userID uint) (*dto.TicketCommentDTO, error)
 	DeleteComment(ticketID uint, commentID uint, userID uint) error
+	ApplySLA(ticketID uint) error // Applique le SLA correspondant (exécuté par la file de tâches)
 }
 
 // ticketService implémente TicketService
 type ticketService struct {
-	ticketRepo          repositories.TicketRepository
-	userRepo            repositories.UserRepository
-	commentRepo         repositories.TicketCommentRepository
-	historyRepo         repositories.TicketHistoryRepository
-	slaRepo             repositories.SLARepository
-	ticketSLARepo       repositories.TicketSLARepository
-	ticketCategoryRepo  repositories.TicketCategoryRepository
-	notificationRepo    repositories.NotificationRepository
-	notificationService NotificationService // Service de notifications pour WebSocket
-	departmentRepo      repositories.DepartmentRepository
-	filialeRepo         repositories.FilialeRepository
-	timeEntryRepo       repositories.TimeEntryRepository // pour valider les entrées de temps quand le ticket est validé
+	ticketRepo              repositories.TicketRepository
+	userRepo                repositories.UserRepository
+	commentRepo             repositories.TicketCommentRepository
+	historyRepo             repositories.TicketHistoryRepository
+	slaRepo                 repositories.SLARepository
+	ticketSLARepo           repositories.TicketSLARepository
+	ticketCategoryRepo      repositories.TicketCategoryRepository
+	notificationRepo        repositories.NotificationRepository
+	notificationService     NotificationService // Service de notifications pour WebSocket
+	departmentRepo          repositories.DepartmentRepository
+	filialeRepo             repositories.FilialeRepository
+	timeEntryRepo           repositories.TimeEntryRepository           // pour valider les entrées de temps quand le ticket est validé
+	environmentRepo         repositories.SoftwareEnvironmentRepository // Environnements des logiciels (environnement concerné par le ticket)
+	contractRepo            repositories.SupportContractRepository     // Contrats de support (niveau de SLA, affichage sur le ticket)
+	eventBus                *events.Bus                                // Publication des événements métier (notifications, webhooks, audit, ...)
+	jobQueue                *jobs.Queue                                // File de tâches durable (historique, SLA, notifications de masse)
+	businessCalendarService BusinessCalendarService                    // Calendriers ouvrés (échéances SLA en temps ouvré)
+	attachmentService       TicketAttachmentService                    // Pièces jointes (images intégrées aux descriptions et commentaires)
 }
 
 // NewTicketService crée une nouvelle instance de TicketService
@@ -72,20 +87,32 @@ func NewTicketService(
 	departmentRepo repositories.DepartmentRepository,
 	filialeRepo repositories.FilialeRepository,
 	timeEntryRepo repositories.TimeEntryRepository,
+	environmentRepo repositories.SoftwareEnvironmentRepository,
+	contractRepo repositories.SupportContractRepository,
+	eventBus *events.Bus,
+	jobQueue *jobs.Queue,
+	businessCalendarService BusinessCalendarService,
+	attachmentService TicketAttachmentService,
 ) TicketService {
 	return &ticketService{
-		ticketRepo:          ticketRepo,
-		userRepo:            userRepo,
-		commentRepo:         commentRepo,
-		historyRepo:         historyRepo,
-		slaRepo:             slaRepo,
-		ticketSLARepo:       ticketSLARepo,
-		ticketCategoryRepo:  ticketCategoryRepo,
-		notificationRepo:    notificationRepo,
-		notificationService: notificationService,
-		departmentRepo:      departmentRepo,
-		filialeRepo:         filialeRepo,
-		timeEntryRepo:       timeEntryRepo,
+		ticketRepo:              ticketRepo,
+		userRepo:                userRepo,
+		commentRepo:             commentRepo,
+		historyRepo:             historyRepo,
+		slaRepo:                 slaRepo,
+		ticketSLARepo:           ticketSLARepo,
+		ticketCategoryRepo:      ticketCategoryRepo,
+		notificationRepo:        notificationRepo,
+		notificationService:     notificationService,
+		departmentRepo:          departmentRepo,
+		filialeRepo:             filialeRepo,
+		timeEntryRepo:           timeEntryRepo,
+		environmentRepo:         environmentRepo,
+		contractRepo:            contractRepo,
+		eventBus:                eventBus,
+		jobQueue:                jobQueue,
+		businessCalendarService: businessCalendarService,
+		attachmentService:       attachmentService,
 	}
 }
 
@@ -115,6 +142,20 @@ func (s *ticketService) Create(req dto.CreateTicketRequest, createdByID uint) (*
 		// Pour l'instant, on accepte la valeur
 	}
 
+	// Valider l'environnement concerné : il doit appartenir au logiciel du ticket chez sa filiale
+	softwareID := req.SoftwareID
+	environmentID := req.EnvironmentID
+	if environmentID != nil && *environmentID == 0 {
+		environmentID = nil
+	}
+	if environmentID != nil {
+		environment, err := s.validateEnvironment(*environmentID, filialeID, softwareID)
+		if err != nil {
+			return nil, err
+		}
+		softwareID = &environment.SoftwareID
+	}
+
 	if err := s.validateCategorySlug(req.Category); err != nil {
 		return nil, err
 	}
@@ -199,10 +240,12 @@ func (s *ticketService) Create(req dto.CreateTicketRequest, createdByID uint) (*
 		priority = "medium" // Valeur par défaut
 	}
 
+	descriptionFormat := richtext.NormalizeFormat(req.DescriptionFormat)
 	ticket := &models.Ticket{
 		Code:                code,
 		Title:               req.Title,
-		Description:         req.Description,
+		Description:         richtext.Prepare(req.Description, descriptionFormat),
+		DescriptionFormat:   descriptionFormat,
 		Category:            req.Category,
 		Source:              source,
 		Status:              "ouvert", // Statut par défaut
@@ -212,7 +255,8 @@ func (s *ticketService) Create(req dto.CreateTicketRequest, createdByID uint) (*
 		RequesterName:       req.RequesterName,
 		RequesterDepartment: req.RequesterDepartment,
 		FilialeID:           filialeID,      // Filiale de l'utilisateur créateur
-		SoftwareID:          req.SoftwareID, // Logiciel concerné (optionnel)
+		SoftwareID:          softwareID,     // Logiciel concerné (optionnel)
+		EnvironmentID:       environmentID,  // Environnement concerné (optionnel)
 		EstimatedTime:       req.EstimatedTime,
 		ParentID:            req.ParentID,
 	}
@@ -231,6 +275,19 @@ func (s *ticketService) Create(req dto.CreateTicketRequest, createdByID uint) (*
 	// Créer une entrée d'historique
 	s.createHistory(ticket.ID, createdByID, "created", "", "", "Ticket créé")
 
+	// Images intégrées : enregistrées en pièces jointes une fois le ticket créé ; en cas d'échec elles sont retirées
+	var warnings []string
+	if description, err := s.extractEmbeddedImages(ticket.ID, ticket.Description, descriptionFormat, createdByID); err != nil {
+		slog.Warn("ticket_embedded_images_failed", "ticket_id", ticket.ID, "error", err)
+		warnings = append(warnings, "Images intégrées non enregistrées : "+err.Error())
+		description, _ = richtext.ExtractImages(ticket.Description, descriptionFormat, func(richtext.Image) (string, error) { return "", nil })
+		_ = s.ticketRepo.UpdateFields(ticket.ID, map[string]interface{}{"description": description})
+	} else if description != ticket.Description {
+		if err := s.ticketRepo.UpdateFields(ticket.ID, map[string]interface{}{"description": description}); err != nil {
+			return nil, errors.New("erreur lors de la mise à jour de la description du ticket")
+		}
+	}
+
 	if len(assigneeIDs) > 0 {
 		if err := s.replaceAssignees(ticket.ID, assigneeIDs, leadID); err != nil {
 			return nil, err
@@ -243,8 +300,11 @@ func (s *ticketService) Create(req dto.CreateTicketRequest, createdByID uint) (*
 		return nil, errors.New("erreur lors de la récupération du ticket créé")
 	}
 
-	// Appliquer automatiquement un SLA si une règle correspondante existe
-	s.applySLAIfApplicable(createdTicket)
+	// Appliquer automatiquement un SLA si une règle correspondante existe (tâche en arrière-plan)
+	if err := s.jobQueue.Enqueue(context.Background(), jobs.TypeApplySLA, jobs.ApplySLAPayload{TicketID: createdTicket.ID}); err != nil {
+		slog.Warn("ticket_apply_sla_enqueue_failed", "ticket_id", createdTicket.ID, "error", err)
+		s.applySLAIfApplicable(createdTicket)
+	}
 
 	// Notification : Envoyer une notification à la DSI de MCI CARE CI lors de la création d'un ticket
 	// Récupérer les informations du créateur et de la filiale pour le message
@@ -270,6 +330,7 @@ func (s *ticketService) Create(req dto.CreateTicketRequest, createdByID uint) (*
 	metadata := map[string]any{
 		"ticket_id":     createdTicket.ID,
 		"ticket_code":   createdTicket.Code,
+		"ticket_title":  createdTicket.Title,
 		"filiale_id":    createdTicket.FilialeID,
 		"created_by_id": createdByID,
 	}
@@ -277,6 +338,8 @@ func (s *ticketService) Create(req dto.CreateTicketRequest, createdByID uint) (*
 
 	// Convertir en DTO
 	ticketDTO := s.ticketToDTO(createdTicket)
+	s.publishEvent(events.TicketCreated, createdTicket.ID, createdTicket.FilialeID, createdByID, ticketDTO, nil)
+	ticketDTO.Warnings = append(warnings, s.outOfOfficeWarnings(assigneeIDs)...)
 	return &ticketDTO, nil
 }
 
@@ -292,10 +355,11 @@ func (s *ticketService) GetByID(id uint, includeDepartment bool) (*dto.TicketDTO
 		ticket, err = s.ticketRepo.FindByIDLean(id)
 	}
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	ticketDTO := s.ticketToDTOWithSubTickets(ticket, true)
+	ticketDTO.SupportContract = supportContractSummaryToDTO(s.findSupportContract(ticket, time.Now()))
 	return &ticketDTO, nil
 }
 
@@ -307,10 +371,7 @@ func (s *ticketService) GetAll(scopeParam interface{}, page, limit int) (*dto.Ti
 		return nil, errors.New("erreur lors de la récupération des tickets")
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -329,10 +390,7 @@ func (s *ticketService) GetAllWithFilters(scopeParam interface{}, page, limit in
 	if err != nil {
 		return nil, errors.New("erreur lors de la récupération des tickets")
 	}
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
 		Pagination: dto.PaginationDTO{
@@ -344,6 +402,23 @@ func (s *ticketService) GetAllWithFilters(scopeParam interface{}, page, limit in
 	}, nil
 }
 
+// GetByFilter récupère les tickets répondant aux critères d'une vue enregistrée
+func (s *ticketService) GetByFilter(scopeParam interface{}, filter repositories.TicketFilter, page, limit int) (*dto.TicketListResponse, error) {
+	tickets, total, err := s.ticketRepo.FindByFilter(scopeParam, filter, page, limit)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des tickets")
+	}
+	return &dto.TicketListResponse{
+		Tickets: s.ticketsToDTOs(tickets),
+		Pagination: dto.PaginationDTO{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, limit),
+		},
+	}, nil
+}
+
 // GetByStatus récupère les tickets par statut
 // Le scope est utilisé pour filtrer automatiquement selon les permissions de l'utilisateur
 func (s *ticketService) GetByStatus(scopeParam interface{}, status string, page, limit int) (*dto.TicketListResponse, error) {
@@ -352,10 +427,7 @@ func (s *ticketService) GetByStatus(scopeParam interface{}, status string, page,
 		return nil, errors.New("erreur lors de la récupération des tickets")
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -376,10 +448,7 @@ func (s *ticketService) GetByCategory(scopeParam interface{}, category string, p
 		return nil, errors.New("erreur lors de la récupération des tickets")
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -400,10 +469,7 @@ func (s *ticketService) GetByAssignedTo(userID uint, page, limit int) (*dto.Tick
 		return nil, fmt.Errorf("erreur lors de la récupération des tickets: %w", err)
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -422,10 +488,7 @@ func (s *ticketService) GetPanier(userID uint, page, limit int) (*dto.TicketList
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la récupération du panier: %w", err)
 	}
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
 		Pagination: dto.PaginationDTO{
@@ -445,10 +508,7 @@ func (s *ticketService) GetByCreatedBy(userID uint, page, limit int) (*dto.Ticke
 		return nil, fmt.Errorf("erreur lors de la récupération des tickets: %w", err)
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -469,10 +529,7 @@ func (s *ticketService) GetByUser(userID uint, page, limit int, status string) (
 		return nil, fmt.Errorf("erreur lors de la récupération des tickets: %w", err)
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -493,10 +550,7 @@ func (s *ticketService) GetBySource(scopeParam interface{}, source string, page,
 		return nil, errors.New("erreur lors de la récupération des tickets")
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -516,10 +570,7 @@ func (s *ticketService) GetByDepartment(departmentID uint, page, limit int) (*dt
 		return nil, fmt.Errorf("erreur lors de la récupération des tickets par département: %w", err)
 	}
 
-	ticketDTOs := make([]dto.TicketDTO, len(tickets))
-	for i, ticket := range tickets {
-		ticketDTOs[i] = s.ticketToDTO(&ticket)
-	}
+	ticketDTOs := s.ticketsToDTOs(tickets)
 
 	return &dto.TicketListResponse{
 		Tickets: ticketDTOs,
@@ -564,7 +615,7 @@ func (s *ticketService) Update(id uint, req dto.UpdateTicketRequest, updatedByID
 	start := time.Now()
 	ticket, err := s.ticketRepo.FindByIDForUpdate(id)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	updates := map[string]interface{}{}
@@ -576,10 +627,27 @@ func (s *ticketService) Update(id uint, req dto.UpdateTicketRequest, updatedByID
 		updates["title"] = req.Title
 	}
 
+	// Format de la description : celui fourni, sinon le format actuel
+	currentFormat := richtext.NormalizeFormat(ticket.DescriptionFormat)
+	descriptionFormat := currentFormat
+	if req.DescriptionFormat != "" {
+		descriptionFormat = req.DescriptionFormat
+	}
+
 	if req.Description != "" {
-		s.createHistory(id, updatedByID, "updated", "description", ticket.Description, req.Description)
-		ticket.Description = req.Description
-		updates["description"] = req.Description
+		description, err := s.prepareRichText(id, req.Description, descriptionFormat, updatedByID)
+		if err != nil {
+			return nil, err
+		}
+		s.createHistory(id, updatedByID, "updated", "description", ticket.Description, description)
+		ticket.Description = description
+		updates["description"] = description
+	}
+
+	if descriptionFormat != currentFormat {
+		s.createHistory(id, updatedByID, "updated", "description_format", currentFormat, descriptionFormat)
+		ticket.DescriptionFormat = descriptionFormat
+		updates["description_format"] = descriptionFormat
 	}
 
 	// Mettre à jour la catégorie si elle est fournie
@@ -676,6 +744,41 @@ func (s *ticketService) Update(id uint, req dto.UpdateTicketRequest, updatedByID
 		}
 	}
 
+	// Gérer EnvironmentID (0 pour retirer l'environnement ; retiré aussi si le logiciel change)
+	newEnvironmentID := ticket.EnvironmentID
+	if req.EnvironmentID != nil {
+		newEnvironmentID = req.EnvironmentID
+		if *req.EnvironmentID == 0 {
+			newEnvironmentID = nil
+		}
+	}
+	if newEnvironmentID != nil {
+		environment, err := s.validateEnvironment(*newEnvironmentID, ticket.FilialeID, ticket.SoftwareID)
+		if err != nil {
+			if req.EnvironmentID != nil {
+				return nil, err
+			}
+			newEnvironmentID = nil
+		} else if ticket.SoftwareID == nil || *ticket.SoftwareID == 0 {
+			// Logiciel déduit de l'environnement
+			s.createHistory(id, updatedByID, "updated", "software_id", "", fmt.Sprintf("%d", environment.SoftwareID))
+			ticket.SoftwareID = &environment.SoftwareID
+			updates["software_id"] = ticket.SoftwareID
+		}
+	}
+	if !sameUintPtr(ticket.EnvironmentID, newEnvironmentID) {
+		oldValue, newValue := "", ""
+		if ticket.EnvironmentID != nil {
+			oldValue = fmt.Sprintf("%d", *ticket.EnvironmentID)
+		}
+		if newEnvironmentID != nil {
+			newValue = fmt.Sprintf("%d", *newEnvironmentID)
+		}
+		s.createHistory(id, updatedByID, "updated", "environment_id", oldValue, newValue)
+		ticket.EnvironmentID = newEnvironmentID
+		updates["environment_id"] = newEnvironmentID
+	}
+
 	if req.ParentID != nil {
 		if *req.ParentID == 0 {
 			return nil, errors.New("ticket parent invalide")
@@ -691,11 +794,13 @@ func (s *ticketService) Update(id uint, req dto.UpdateTicketRequest, updatedByID
 	}
 
 	assigneesStart := time.Now()
+	var warnings []string
 	if len(req.AssigneeIDs) > 0 || req.LeadID != nil {
 		assigneeIDs, leadID, err := normalizeAssignees(req.AssigneeIDs, req.LeadID)
 		if err != nil {
 			return nil, err
 		}
+		warnings = s.outOfOfficeWarnings(assigneeIDs)
 		// Valider que les utilisateurs assignés appartiennent au même département IT si l'assigneur est IT
 		if err := s.validateAssigneesForITUser(assigneeIDs, updatedByID); err != nil {
 			return nil, err
@@ -726,17 +831,17 @@ func (s *ticketService) Update(id uint, req dto.UpdateTicketRequest, updatedByID
 	}
 
 	// Sauvegarder
-	fmt.Printf("DEBUG: Avant sauvegarde - Catégorie du ticket: '%s'\n", ticket.Category)
 	updateStart := time.Now()
 	if err := s.ticketRepo.UpdateFields(ticket.ID, updates); err != nil {
-		fmt.Printf("DEBUG: Erreur lors de la sauvegarde: %v\n", err)
+		slog.Error("ticket_update_failed", "ticket_id", ticket.ID, "error", err)
 		return nil, errors.New("erreur lors de la mise à jour du ticket")
 	}
-	fmt.Printf("DEBUG: Sauvegarde réussie\n")
 	updateDur := time.Since(updateStart)
 
 	ticketDTO := s.ticketToDTO(ticket)
-	log.Printf("PERF Update ticket=%d assignees=%s update=%s total=%s", id, assigneesDur, updateDur, time.Since(start))
+	s.publishEvent(events.TicketUpdated, ticket.ID, ticket.FilialeID, updatedByID, ticketDTO, nil)
+	ticketDTO.Warnings = warnings
+	slog.Debug("perf_ticket_update", "ticket_id", id, "assignees_ms", assigneesDur.Milliseconds(), "update_ms", updateDur.Milliseconds(), "total_ms", time.Since(start).Milliseconds())
 	return &ticketDTO, nil
 }
 
@@ -746,7 +851,7 @@ func (s *ticketService) Assign(id uint, req dto.AssignTicketRequest, assignedByI
 	// Récupérer le ticket (léger)
 	ticket, err := s.ticketRepo.FindByIDForAssign(id)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	assigneeIDs := req.UserIDs
@@ -816,17 +921,55 @@ func (s *ticketService) Assign(id uint, req dto.AssignTicketRequest, assignedByI
 	fetchDur := time.Since(fetchStart)
 
 	ticketDTO := s.ticketToDTO(updatedTicket)
-	fmt.Printf("PERF AssignService ticket=%d users=%d validate=%s update=%s replace=%s fetch=%s total=%s\n",
-		id, len(assigneeIDs), validateDur, updateDur, replaceDur, fetchDur, time.Since(start))
+	s.publishEvent(events.TicketAssigned, id, updatedTicket.FilialeID, assignedByID, ticketDTO, map[string]any{
+		"assignee_ids":     assigneeIDs,
+		"previous_lead_id": oldAssignedID,
+		"lead_id":          newAssignedID,
+	})
+	slog.Debug("perf_ticket_assign", "ticket_id", id, "users", len(assigneeIDs),
+		"validate_ms", validateDur.Milliseconds(), "update_ms", updateDur.Milliseconds(), "replace_ms", replaceDur.Milliseconds(),
+		"fetch_ms", fetchDur.Milliseconds(), "total_ms", time.Since(start).Milliseconds())
+	ticketDTO.Warnings = s.outOfOfficeWarnings(assigneeIDs)
 	return &ticketDTO, nil
 }
 
+// outOfOfficeWarnings signale les assignés actuellement absents (l'assignation reste effectuée)
+func (s *ticketService) outOfOfficeWarnings(assigneeIDs []uint) []string {
+	if len(assigneeIDs) == 0 {
+		return nil
+	}
+	users, err := s.userRepo.FindByIDs(assigneeIDs)
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	var warnings []string
+	for i := range users {
+		user := &users[i]
+		if !user.IsOutOfOffice(now) {
+			continue
+		}
+		warning := fmt.Sprintf("%s %s est absent(e) jusqu'au %s", user.FirstName, user.LastName, user.OutOfOfficeUntil.Format("02/01/2006 15:04"))
+		if user.BackupUserID != nil {
+			if backup, err := s.userRepo.FindByID(*user.BackupUserID); err == nil {
+				warning += fmt.Sprintf(" (suppléant : %s %s)", backup.FirstName, backup.LastName)
+			}
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
 // ChangeStatus change le statut d'un ticket
 func (s *ticketService) ChangeStatus(id uint, status string, changedByID uint) (*dto.TicketDTO, error) {
 	// Récupérer le ticket
 	ticket, err := s.ticketRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
+	}
+
+	if ticket.Status == ticketStatusMerged {
+		return nil, errors.New("un ticket fusionné ne peut plus changer de statut")
 	}
 
 	// Valider le statut (ajouter "resolu" pour le workflow multi-filiales)
@@ -933,6 +1076,11 @@ func (s *ticketService) ChangeStatus(id uint, status string, changedByID uint) (
 	}
 
 	ticketDTO := s.ticketToDTO(updatedTicket)
+	statusMetadata := map[string]any{"old_status": oldStatus, "new_status": status}
+	s.publishEvent(events.TicketStatusChanged, id, updatedTicket.FilialeID, changedByID, ticketDTO, statusMetadata)
+	if status == "cloture" {
+		s.publishEvent(events.TicketClosed, id, updatedTicket.FilialeID, changedByID, ticketDTO, statusMetadata)
+	}
 	return &ticketDTO, nil
 }
 
@@ -943,7 +1091,7 @@ func (s *ticketService) ValidateTicket(id uint, validatedByID uint) (*dto.Ticket
 	// Récupérer le ticket
 	ticket, err := s.ticketRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	// Vérifier que le ticket est en statut "en_attente" (soumis pour validation)
@@ -1029,6 +1177,11 @@ func (s *ticketService) ValidateTicket(id uint, validatedByID uint) (*dto.Ticket
 	}
 
 	ticketDTO := s.ticketToDTO(updatedTicket)
+	s.publishEvent(events.TicketStatusChanged, id, updatedTicket.FilialeID, validatedByID, ticketDTO, map[string]any{
+		"old_status": oldStatus,
+		"new_status": updatedTicket.Status,
+		"validated":  true,
+	})
 	return &ticketDTO, nil
 }
 
@@ -1050,7 +1203,7 @@ func (s *ticketService) Delete(id uint) error {
 	// Vérifier que le ticket existe
 	_, err := s.ticketRepo.FindByID(id)
 	if err != nil {
-		return errors.New("ticket introuvable")
+		return utils.ErrTicketNotFound
 	}
 
 	// Supprimer (soft delete)
@@ -1069,14 +1222,22 @@ func (s *ticketService) AddComment(ticketID uint, req dto.CreateTicketCommentReq
 		return nil, errors.New("erreur lors de la vérification du ticket")
 	}
 	if !exists {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
+	}
+
+	// Assainir le contenu et enregistrer les images intégrées en pièces jointes du ticket
+	format := richtext.NormalizeFormat(req.Format)
+	content, err := s.prepareRichText(ticketID, req.Comment, format, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Créer le commentaire
 	comment := &models.TicketComment{
 		TicketID:   ticketID,
 		UserID:     userID,
-		Comment:    req.Comment,
+		Comment:    content,
+		Format:     format,
 		IsInternal: req.IsInternal,
 	}
 
@@ -1095,6 +1256,23 @@ func (s *ticketService) AddComment(ticketID uint, req dto.CreateTicketCommentReq
 
 	// Convertir en DTO
 	commentDTO := s.commentToDTO(createdComment)
+
+	var filialeID *uint
+	metadata := map[string]any{
+		"comment_id":  comment.ID,
+		"is_internal": comment.IsInternal,
+	}
+	if ticket, err := s.ticketRepo.FindByIDForUpdate(ticketID); err == nil {
+		filialeID = ticket.FilialeID
+		requesterID := ticket.CreatedByID
+		if ticket.RequesterID != nil {
+			requesterID = *ticket.RequesterID
+		}
+		metadata["ticket_code"] = ticket.Code
+		metadata["ticket_title"] = ticket.Title
+		metadata["requester_id"] = requesterID
+	}
+	s.publishEvent(events.TicketCommented, ticketID, filialeID, userID, commentDTO, metadata)
 	return &commentDTO, nil
 }
 
@@ -1130,7 +1308,14 @@ func (s *ticketService) UpdateComment(ticketID uint, commentID uint, req dto.Upd
 	if comment.UserID != userID {
 		return nil, errors.New("seul l'auteur du commentaire peut le modifier")
 	}
-	comment.Comment = strings.TrimSpace(req.Comment)
+	if req.Format != "" {
+		comment.Format = req.Format
+	}
+	content, err := s.prepareRichText(ticketID, strings.TrimSpace(req.Comment), comment.Format, userID)
+	if err != nil {
+		return nil, err
+	}
+	comment.Comment = strings.TrimSpace(content)
 	if comment.Comment == "" {
 		return nil, errors.New("le commentaire ne peut pas être vide")
 	}
@@ -1255,6 +1440,41 @@ func (s *ticketService) validateAssigneesForITUser(assigneeIDs []uint, assignedB
 	return nil
 }
 
+// findSupportContract retourne le contrat de support en vigueur à la date at pour la filiale et le logiciel du ticket (nil si aucun)
+func (s *ticketService) findSupportContract(ticket *models.Ticket, at time.Time) *models.SupportContract {
+	if ticket.FilialeID == nil {
+		return nil
+	}
+	contract, err := s.contractRepo.FindEffective(*ticket.FilialeID, ticket.SoftwareID, at)
+	if err != nil {
+		return nil
+	}
+	return contract
+}
+
+// validateEnvironment vérifie qu'un environnement existe et correspond à la filiale et au logiciel du ticket (s'ils sont connus)
+func (s *ticketService) validateEnvironment(environmentID uint, filialeID, softwareID *uint) (*models.SoftwareEnvironment, error) {
+	environment, err := s.environmentRepo.FindByID(environmentID)
+	if err != nil {
+		return nil, errors.New("environnement introuvable")
+	}
+	if filialeID != nil && environment.FilialeID != *filialeID {
+		return nil, errors.New("l'environnement n'appartient pas à la filiale du ticket")
+	}
+	if softwareID != nil && *softwareID != 0 && environment.SoftwareID != *softwareID {
+		return nil, errors.New("l'environnement ne correspond pas au logiciel du ticket")
+	}
+	return environment, nil
+}
+
+// sameUintPtr indique si deux identifiants optionnels sont égaux
+func sameUintPtr(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // validateCategorySlug vérifie que le slug existe dans ticket_categories et que la catégorie est active.
 func (s *ticketService) validateCategorySlug(slug string) error {
 	if slug == "" {
@@ -1294,22 +1514,36 @@ func (s *ticketService) replaceAssignees(ticketID uint, assigneeIDs []uint, lead
 	return nil
 }
 
-// createHistory crée une entrée d'historique pour un ticket
+// createHistory crée une entrée d'historique pour un ticket (écriture différée via la file de tâches, avec réessais)
 func (s *ticketService) createHistory(ticketID, userID uint, action, fieldName, oldValue, newValue string) {
-	history := &models.TicketHistory{
-		TicketID:    ticketID,
-		UserID:      userID,
-		Action:      action,
-		FieldName:   fieldName,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		Description: "",
-	}
-	go func(h *models.TicketHistory) {
-		if err := s.historyRepo.Create(h); err != nil {
+	payload := jobs.TicketHistoryPayload{
+		TicketID:  ticketID,
+		UserID:    userID,
+		Action:    action,
+		FieldName: fieldName,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		CreatedAt: time.Now(),
+	}
+	if err := s.jobQueue.Enqueue(context.Background(), jobs.TypeTicketHistory, payload); err != nil {
+		log.Printf("WARN history enqueue ticket=%d action=%s err=%v", ticketID, action, err)
+		if err := s.historyRepo.Create(ticketHistoryFromPayload(payload)); err != nil {
 			log.Printf("WARN history create ticket=%d action=%s err=%v", ticketID, action, err)
 		}
-	}(history)
+	}
+}
+
+// ApplySLA applique le SLA correspondant à un ticket (tâche jobs.TypeApplySLA)
+func (s *ticketService) ApplySLA(ticketID uint) error {
+	if existing, err := s.ticketSLARepo.FindByTicketID(ticketID); err == nil && existing != nil {
+		return nil // Déjà appliqué (tâche rejouée)
+	}
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return fmt.Errorf("ticket %d introuvable: %w", ticketID, err)
+	}
+	s.applySLAIfApplicable(ticket)
+	return nil
 }
 
 // ticketToDTO convertit un modèle Ticket en DTO TicketDTO
@@ -1317,7 +1551,70 @@ func (s *ticketService) ticketToDTO(ticket *models.Ticket) dto.TicketDTO {
 	return s.ticketToDTOWithSubTickets(ticket, false)
 }
 
+// ticketToDTOWithSubTickets convertit un ticket (et, si demandé, ses sous-tickets) en DTO
 func (s *ticketService) ticketToDTOWithSubTickets(ticket *models.Ticket, includeSubTickets bool) dto.TicketDTO {
+	tickets := []*models.Ticket{ticket}
+	if includeSubTickets {
+		for i := range ticket.SubTickets {
+			tickets = append(tickets, &ticket.SubTickets[i])
+		}
+	}
+	return s.assembleTicketDTO(ticket, includeSubTickets, s.loadTicketUsers(tickets...))
+}
+
+// ticketsToDTOs convertit une page de tickets en DTOs ; les utilisateurs non préchargés sont lus en une seule requête
+func (s *ticketService) ticketsToDTOs(tickets []models.Ticket) []dto.TicketDTO {
+	pointers := make([]*models.Ticket, len(tickets))
+	for i := range tickets {
+		pointers[i] = &tickets[i]
+	}
+	users := s.loadTicketUsers(pointers...)
+	ticketDTOs := make([]dto.TicketDTO, len(tickets))
+	for i := range tickets {
+		ticketDTOs[i] = s.assembleTicketDTO(&tickets[i], false, users)
+	}
+	return ticketDTOs
+}
+
+// loadTicketUsers charge en une requête (IN) les créateurs, demandeurs et valideurs dont la relation n'a pas été préchargée
+func (s *ticketService) loadTicketUsers(tickets ...*models.Ticket) map[uint]*models.User {
+	seen := map[uint]bool{}
+	var ids []uint
+	add := func(id uint) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, ticket := range tickets {
+		if ticket.CreatedBy.ID == 0 {
+			add(ticket.CreatedByID)
+		}
+		if (ticket.Requester == nil || ticket.Requester.ID == 0) && ticket.RequesterID != nil {
+			add(*ticket.RequesterID)
+		}
+		if (ticket.ValidatedBy == nil || ticket.ValidatedBy.ID == 0) && ticket.ValidatedByUserID != nil {
+			add(*ticket.ValidatedByUserID)
+		}
+	}
+
+	users := make(map[uint]*models.User, len(ids))
+	if len(ids) == 0 {
+		return users
+	}
+	found, err := s.userRepo.FindByIDs(ids)
+	if err != nil {
+		log.Printf("Erreur lors du chargement des utilisateurs des tickets: %v", err)
+		return users
+	}
+	for i := range found {
+		users[found[i].ID] = &found[i]
+	}
+	return users
+}
+
+// assembleTicketDTO construit le DTO d'un ticket ; users fournit les utilisateurs dont la relation n'est pas préchargée (voir loadTicketUsers)
+func (s *ticketService) assembleTicketDTO(ticket *models.Ticket, includeSubTickets bool, users map[uint]*models.User) dto.TicketDTO {
 	// Convertir les utilisateurs en DTOs
 	var assignedToDTO *dto.UserDTO
 	if ticket.AssignedTo != nil {
@@ -1339,13 +1636,41 @@ func (s *ticketService) ticketToDTOWithSubTickets(ticket *models.Ticket, include
 		}
 	}
 
-	// CreatedBy : utiliser le Preload si chargé, sinon charger manuellement pour éviter "Utilisateur inconnu"
+	var watchersDTO []dto.UserDTO
+	for i := range ticket.Watchers {
+		watchersDTO = append(watchersDTO, s.userToDTO(&ticket.Watchers[i].User))
+	}
+
+	var relationsDTO []dto.TicketRelationDTO
+	for _, relation := range ticket.OutgoingRelations {
+		if relation.TargetTicket != nil {
+			relationsDTO = append(relationsDTO, ticketRelationToDTO(&relation, relation.Type, relation.TargetTicket))
+		}
+	}
+	for _, relation := range ticket.IncomingRelations {
+		if relation.SourceTicket != nil {
+			relationsDTO = append(relationsDTO, ticketRelationToDTO(&relation, models.TicketRelationInverse(relation.Type), relation.SourceTicket))
+		}
+	}
+
+	var checklistProgress *int
+	if len(ticket.Checklist) > 0 {
+		done := 0
+		for _, item := range ticket.Checklist {
+			if item.Done {
+				done++
+			}
+		}
+		progress := done * 100 / len(ticket.Checklist)
+		checklistProgress = &progress
+	}
+
+	// CreatedBy : utiliser le Preload si chargé, sinon l'utilisateur chargé par lot pour éviter "Utilisateur inconnu"
 	var createdByDTO dto.UserDTO
 	if ticket.CreatedBy.ID != 0 {
 		createdByDTO = s.userToDTO(&ticket.CreatedBy)
 	} else if ticket.CreatedByID != 0 {
-		createdByUser, err := s.userRepo.FindByID(ticket.CreatedByID)
-		if err == nil && createdByUser != nil {
+		if createdByUser, ok := users[ticket.CreatedByID]; ok {
 			createdByDTO = s.userToDTO(createdByUser)
 		} else {
 			createdByDTO = dto.UserDTO{ID: ticket.CreatedByID, Username: "Utilisateur inconnu"}
@@ -1367,9 +1692,8 @@ func (s *ticketService) ticketToDTOWithSubTickets(ticket *models.Ticket, include
 			requesterName = ticket.Requester.Username
 		}
 	} else if ticket.RequesterID != nil && *ticket.RequesterID != 0 {
-		// Si RequesterID est défini mais la relation n'est pas chargée, charger l'utilisateur
-		requesterUser, err := s.userRepo.FindByID(*ticket.RequesterID)
-		if err == nil && requesterUser != nil {
+		// Si RequesterID est défini mais la relation n'est pas chargée, utiliser l'utilisateur chargé par lot
+		if requesterUser, ok := users[*ticket.RequesterID]; ok {
 			reqDTO := s.userToDTO(requesterUser)
 			requesterDTO = &reqDTO
 			requesterName = fmt.Sprintf("%s %s", requesterUser.FirstName, requesterUser.LastName)
@@ -1389,7 +1713,7 @@ func (s *ticketService) ticketToDTOWithSubTickets(ticket *models.Ticket, include
 	if includeSubTickets && len(ticket.SubTickets) > 0 {
 		subTickets = make([]dto.TicketDTO, 0, len(ticket.SubTickets))
 		for _, sub := range ticket.SubTickets {
-			subTickets = append(subTickets, s.ticketToDTOWithSubTickets(&sub, false))
+			subTickets = append(subTickets, s.assembleTicketDTO(&sub, false, users))
 		}
 	}
 
@@ -1399,8 +1723,7 @@ func (s *ticketService) ticketToDTOWithSubTickets(ticket *models.Ticket, include
 		validDTO := s.userToDTO(ticket.ValidatedBy)
 		validatedByDTO = &validDTO
 	} else if ticket.ValidatedByUserID != nil && *ticket.ValidatedByUserID != 0 {
-		validatedUser, err := s.userRepo.FindByID(*ticket.ValidatedByUserID)
-		if err == nil && validatedUser != nil {
+		if validatedUser, ok := users[*ticket.ValidatedByUserID]; ok {
 			validDTO := s.userToDTO(validatedUser)
 			validatedByDTO = &validDTO
 		}
@@ -1445,12 +1768,15 @@ func (s *ticketService) ticketToDTOWithSubTickets(ticket *models.Ticket, include
 		Code:                ticket.Code,
 		Title:               ticket.Title,
 		Description:         ticket.Description,
+		DescriptionFormat:   richtext.NormalizeFormat(ticket.DescriptionFormat),
+		DescriptionHTML:     richtext.Render(ticket.Description, ticket.DescriptionFormat),
 		Category:            ticket.Category,
 		Source:              ticket.Source,
 		Status:              ticket.Status,
 		Priority:            ticket.Priority,
 		AssignedTo:          assignedToDTO,
 		Assignees:           assigneesDTO,
+		Watchers:            watchersDTO,
 		Lead:                leadDTO,
 		CreatedBy:           createdByDTO,
 		RequesterID:         ticket.RequesterID,
@@ -1461,12 +1787,20 @@ func (s *ticketService) ticketToDTOWithSubTickets(ticket *models.Ticket, include
 		Filiale:             filialeDTO,
 		SoftwareID:          ticket.SoftwareID,
 		Software:            softwareDTO,
+		FixedInReleaseID:    ticket.FixedInReleaseID,
+		FixedInRelease:      releaseSummaryToDTO(ticket.FixedInRelease),
+		EnvironmentID:       ticket.EnvironmentID,
+		Environment:         environmentSummaryToDTO(ticket.Environment),
+		JiraIssue:           jiraLinkToDTO(ticket.JiraLink),
 		ValidatedByUserID:   ticket.ValidatedByUserID,
 		ValidatedBy:         validatedByDTO,
 		ValidatedAt:         ticket.ValidatedAt,
 		EstimatedTime:       ticket.EstimatedTime,
 		ActualTime:          ticket.ActualTime,
 		ParentID:            ticket.ParentID,
+		MergedIntoID:        ticket.MergedIntoID,
+		ChecklistProgress:   checklistProgress,
+		Relations:           relationsDTO,
 		SubTickets:          subTickets,
 		CreatedAt:           ticket.CreatedAt,
 		UpdatedAt:           ticket.UpdatedAt,
@@ -1481,13 +1815,33 @@ func (s *ticketService) commentToDTO(comment *models.TicketComment) dto.TicketCo
 		ID:         comment.ID,
 		TicketID:   comment.TicketID,
 		User:       userDTO,
-		Comment:    comment.Comment,
-		IsInternal: comment.IsInternal,
-		CreatedAt:  comment.CreatedAt,
-		UpdatedAt:  comment.UpdatedAt,
+		Comment:     comment.Comment,
+		Format:      richtext.NormalizeFormat(comment.Format),
+		CommentHTML: richtext.Render(comment.Comment, comment.Format),
+		IsInternal:  comment.IsInternal,
+		CreatedAt:   comment.CreatedAt,
+		UpdatedAt:   comment.UpdatedAt,
 	}
 }
 
+// prepareRichText assainit un contenu (description, commentaire) et enregistre ses images intégrées en pièces jointes du ticket
+func (s *ticketService) prepareRichText(ticketID uint, content, format string, userID uint) (string, error) {
+	return s.extractEmbeddedImages(ticketID, richtext.Prepare(content, format), format, userID)
+}
+
+// extractEmbeddedImages remplace les images intégrées (data URI) par le lien de téléchargement de leur pièce jointe
+// Les images passent par les mêmes contrôles que les pièces jointes (type, taille, antivirus)
+func (s *ticketService) extractEmbeddedImages(ticketID uint, content, format string, userID uint) (string, error) {
+	return richtext.ExtractImages(content, format, func(image richtext.Image) (string, error) {
+		fileName := fmt.Sprintf("%d_%s", time.Now().UnixNano(), image.Name)
+		attachment, err := s.attachmentService.UploadAttachment(ticketID, fileName, bytes.NewReader(image.Data), "Image intégrée", 0, userID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("/api/v1/tickets/%d/attachments/%d/download", ticketID, attachment.ID), nil
+	})
+}
+
 // applySLAIfApplicable applique automatiquement un SLA au ticket s'il existe une règle correspondante
 func (s *ticketService) applySLAIfApplicable(ticket *models.Ticket) {
 	// Vérifier si un SLA existe déjà pour ce ticket
@@ -1498,23 +1852,30 @@ func (s *ticketService) applySLAIfApplicable(ticket *models.Ticket) {
 	}
 
 	// Chercher un SLA actif correspondant à la catégorie et priorité du ticket
+	// Les SLA du niveau de support du contrat couvrant le ticket priment sur les SLA hors contrat
 	var sla *models.SLA
 	var errSLA error
 
-	// D'abord, chercher un SLA spécifique à la priorité (si le ticket a une priorité)
-	if ticket.Priority != "" {
-		sla, errSLA = s.slaRepo.FindByCategoryAndPriority(ticket.Category, ticket.Priority)
-		if errSLA == nil && sla != nil {
-			// SLA spécifique trouvé, l'utiliser
-			log.Printf("SLA spécifique trouvé pour ticket %d: catégorie=%s, priorité=%s", ticket.ID, ticket.Category, ticket.Priority)
+	levels := []string{""}
+	if contract := s.findSupportContract(ticket, ticket.CreatedAt); contract != nil {
+		levels = []string{contract.SupportLevel, ""}
+	}
+	for _, level := range levels {
+		// D'abord, chercher un SLA spécifique à la priorité (si le ticket a une priorité)
+		if ticket.Priority != "" {
+			sla, errSLA = s.slaRepo.FindByCategoryAndPriority(ticket.Category, ticket.Priority, level)
+			if errSLA == nil && sla != nil {
+				// SLA spécifique trouvé, l'utiliser
+				log.Printf("SLA spécifique trouvé pour ticket %d: catégorie=%s, priorité=%s, contrat=%s", ticket.ID, ticket.Category, ticket.Priority, level)
+				break
+			}
 		}
-	}
 
-	// Si aucun SLA spécifique n'est trouvé, chercher un SLA général (sans priorité = NULL)
-	if sla == nil || errSLA != nil {
-		sla, errSLA = s.slaRepo.FindByCategoryAndPriority(ticket.Category, "")
+		// Si aucun SLA spécifique n'est trouvé, chercher un SLA général (sans priorité = NULL)
+		sla, errSLA = s.slaRepo.FindByCategoryAndPriority(ticket.Category, "", level)
 		if errSLA == nil && sla != nil {
-			log.Printf("SLA général trouvé pour ticket %d: catégorie=%s (sans priorité spécifique)", ticket.ID, ticket.Category)
+			log.Printf("SLA général trouvé pour ticket %d: catégorie=%s (sans priorité spécifique), contrat=%s", ticket.ID, ticket.Category, level)
+			break
 		}
 	}
 
@@ -1524,47 +1885,22 @@ func (s *ticketService) applySLAIfApplicable(ticket *models.Ticket) {
 
 	// Si un SLA est trouvé, créer l'association ticket-SLA
 	if sla != nil && errSLA == nil {
-		// Calculer la date cible : created_at + target_time
-		var targetTime time.Time
-		switch sla.Unit {
-		case "minutes":
-			targetTime = ticket.CreatedAt.Add(time.Duration(sla.TargetTime) * time.Minute)
-		case "hours":
-			targetTime = ticket.CreatedAt.Add(time.Duration(sla.TargetTime) * time.Hour)
-		case "days":
-			targetTime = ticket.CreatedAt.AddDate(0, 0, sla.TargetTime)
-		default:
-			// Par défaut, traiter comme minutes
-			targetTime = ticket.CreatedAt.Add(time.Duration(sla.TargetTime) * time.Minute)
-		}
-
-		// Déterminer le statut initial
-		status := "on_time"
-		now := time.Now()
-		if now.After(targetTime) {
-			status = "violated"
-		} else {
-			// Vérifier si on est à risque (moins de 25% du temps restant)
-			totalDuration := targetTime.Sub(ticket.CreatedAt)
-			if totalDuration > 0 {
-				remainingPercent := float64(targetTime.Sub(now)) / float64(totalDuration)
-				if remainingPercent < 0.25 {
-					status = "at_risk"
-				}
-			}
-		}
+		// Calculer la date cible : created_at + target_time, en temps ouvré selon le calendrier de la filiale
+		calendar := s.businessCalendarService.CalendarFor(ticket.FilialeID)
+		targetTime := slaTargetTime(calendar, ticket.CreatedAt, sla.TargetTime, sla.Unit)
 
 		ticketSLA := &models.TicketSLA{
 			TicketID:   ticket.ID,
 			SLAID:      sla.ID,
 			TargetTime: targetTime,
-			Status:     status,
 		}
+		// Déterminer le statut initial
+		ticketSLA.Status = ticketSLAStatusAt(calendar, ticketSLA, ticket.CreatedAt, time.Now())
 
 		if err := s.ticketSLARepo.Create(ticketSLA); err != nil {
 			log.Printf("Erreur lors de l'application du SLA au ticket %d: %v", ticket.ID, err)
 		} else {
-			log.Printf("SLA appliqué au ticket %d: %s (cible: %v, statut: %s)", ticket.ID, sla.Name, targetTime, status)
+			log.Printf("SLA appliqué au ticket %d: %s (cible: %v, statut: %s)", ticket.ID, sla.Name, targetTime, ticketSLA.Status)
 		}
 	}
 }
@@ -1585,12 +1921,13 @@ func (s *ticketService) updateSLAOnClose(ticketID uint) {
 	// Mettre à jour ActualTime et recalculer le statut
 	now := time.Now()
 	ticketSLA.ActualTime = &now
+	alreadyViolated := ticketSLA.Status == "violated" // Violation déjà publiée par la surveillance périodique
 
 	// Recalculer le statut
 	if now.After(ticketSLA.TargetTime) {
 		ticketSLA.Status = "violated"
-		// Calculer le temps de violation en minutes
-		violationMinutes := int(now.Sub(ticketSLA.TargetTime).Minutes())
+		// Calculer le temps de violation en minutes (ouvrées si un calendrier s'applique)
+		violationMinutes := int(s.businessCalendarService.CalendarFor(ticket.FilialeID).Duration(ticketSLA.TargetTime, now).Minutes())
 		ticketSLA.ViolationTime = &violationMinutes
 	} else {
 		ticketSLA.Status = "on_time"
@@ -1601,6 +1938,13 @@ func (s *ticketService) updateSLAOnClose(ticketID uint) {
 		log.Printf("Erreur lors de la mise à jour du SLA pour le ticket %d: %v", ticketID, err)
 	} else {
 		log.Printf("SLA mis à jour pour le ticket %d: statut=%s", ticketID, ticketSLA.Status)
+		if ticketSLA.Status == "violated" && !alreadyViolated {
+			s.publishEvent(events.SLAViolated, ticketID, ticket.FilialeID, 0, s.ticketToDTO(ticket), map[string]any{
+				"sla_id":            ticketSLA.SLAID,
+				"target_time":       ticketSLA.TargetTime,
+				"violation_minutes": ticketSLA.ViolationTime,
+			})
+		}
 	}
 }
 
@@ -1710,6 +2054,23 @@ func (s *ticketService) createNotification(userID uint, notificationType string,
 	}
 }
 
+// publishEvent publie un événement ticket sur le bus (les abonnés sont exécutés de manière asynchrone)
+func (s *ticketService) publishEvent(eventType string, ticketID uint, filialeID *uint, actorID uint, data any, metadata map[string]any) {
+	var actor *uint
+	if actorID != 0 {
+		actor = &actorID
+	}
+	s.eventBus.Publish(context.Background(), events.Event{
+		Type:       eventType,
+		ActorID:    actor,
+		FilialeID:  filialeID,
+		EntityType: "tickets",
+		EntityID:   ticketID,
+		Data:       data,
+		Metadata:   metadata,
+	})
+}
+
 // notifyITDepartmentOfSoftwareProvider envoie une notification à tous les utilisateurs IT de la filiale fournisseur de logiciels
 func (s *ticketService) notifyITDepartmentOfSoftwareProvider(notificationType string, title string, message string, linkURL string, metadata map[string]any) {
 	itUserIDs, err := s.getITUsersOfSoftwareProvider()
@@ -1726,8 +2087,19 @@ func (s *ticketService) notifyITDepartmentOfSoftwareProvider(notificationType st
 	}
 
 	log.Printf("✅ Envoi de notification '%s' à %d utilisateur(s) IT de la filiale fournisseur", notificationType, len(itUserIDs))
-	for _, userID := range itUserIDs {
-		s.createNotification(userID, notificationType, title, message, linkURL, metadata)
+	err = s.jobQueue.Enqueue(context.Background(), jobs.TypeNotifyUsers, jobs.NotifyUsersPayload{
+		UserIDs:  itUserIDs,
+		Type:     notificationType,
+		Title:    title,
+		Message:  message,
+		LinkURL:  linkURL,
+		Metadata: metadata,
+	})
+	if err != nil {
+		log.Printf("Erreur lors de la planification des notifications '%s': %v", notificationType, err)
+		for _, userID := range itUserIDs {
+			s.createNotification(userID, notificationType, title, message, linkURL, metadata)
+		}
 	}
 }
 
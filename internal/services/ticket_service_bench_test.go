@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+)
+
+// benchQueryLatency aller-retour simulé d'une requête SQL
+const benchQueryLatency = 100 * time.Microsecond
+
+// benchUserRepository compte les requêtes sur les utilisateurs ; les autres méthodes ne sont pas utilisées
+type benchUserRepository struct {
+	repositories.UserRepository
+	queries int
+}
+
+func (r *benchUserRepository) FindByID(id uint) (*models.User, error) {
+	r.queries++
+	time.Sleep(benchQueryLatency)
+	return &models.User{ID: id, Username: "user"}, nil
+}
+
+func (r *benchUserRepository) FindByIDs(ids []uint) ([]models.User, error) {
+	r.queries++
+	time.Sleep(benchQueryLatency)
+	users := make([]models.User, len(ids))
+	for i, id := range ids {
+		users[i] = models.User{ID: id, Username: "user"}
+	}
+	return users, nil
+}
+
+// benchTickets liste de tickets sans relations préchargées (créateur, demandeur, valideur parmi 20 utilisateurs)
+func benchTickets(n int) []models.Ticket {
+	tickets := make([]models.Ticket, n)
+	for i := range tickets {
+		requesterID := uint(i%20 + 1)
+		validatedByID := uint((i+7)%20 + 1)
+		tickets[i] = models.Ticket{
+			ID:                uint(i + 1),
+			Title:             "Ticket",
+			CreatedByID:       uint((i+3)%20 + 1),
+			RequesterID:       &requesterID,
+			ValidatedByUserID: &validatedByID,
+		}
+	}
+	return tickets
+}
+
+// BenchmarkTicketsToDTOsBatched assemble une page de tickets avec le chargement groupé des utilisateurs (une requête IN)
+func BenchmarkTicketsToDTOsBatched(b *testing.B) {
+	repo := &benchUserRepository{}
+	s := &ticketService{userRepo: repo}
+	tickets := benchTickets(50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.ticketsToDTOs(tickets)
+	}
+	b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+}
+
+// BenchmarkTicketsToDTOsPerRow assemble la même page avec une recherche par ticket et par utilisateur (N+1)
+func BenchmarkTicketsToDTOsPerRow(b *testing.B) {
+	repo := &benchUserRepository{}
+	s := &ticketService{userRepo: repo}
+	tickets := benchTickets(50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range tickets {
+			ticket := &tickets[j]
+			users := map[uint]*models.User{}
+			for _, id := range []*uint{&ticket.CreatedByID, ticket.RequesterID, ticket.ValidatedByUserID} {
+				if id != nil {
+					users[*id], _ = repo.FindByID(*id)
+				}
+			}
+			s.assembleTicketDTO(ticket, false, users)
+		}
+	}
+	b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+}
@@ -1,11 +1,21 @@
 package services
 
 import (
+	"encoding/base64"
 	"errors"
-
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mcicare/itsm-backend/config"
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // TicketSolutionService interface pour les opérations sur les solutions de tickets
@@ -16,6 +26,8 @@ type TicketSolutionService interface {
 	Update(id uint, req dto.UpdateTicketSolutionRequest, updatedByID uint) (*dto.TicketSolutionDTO, error)
 	Delete(id uint) error
 	PublishToKB(solutionID uint, req dto.PublishSolutionToKBRequest, publishedByID uint) (*dto.KnowledgeArticleDTO, error)
+	// PublishAsArticle rédige un brouillon d'article à partir de la solution (symptômes, résolution, pièces jointes) et le lie au ticket
+	PublishAsArticle(ticketID, solutionID uint, req dto.PublishSolutionAsArticleRequest, authorID uint) (*dto.KnowledgeArticleDTO, error)
 }
 
 // ticketSolutionService implémente TicketSolutionService
@@ -26,6 +38,11 @@ type ticketSolutionService struct {
 	roleRepo       repositories.RoleRepository
 	kbArticleRepo  repositories.KnowledgeArticleRepository
 	kbCategoryRepo repositories.KnowledgeCategoryRepository
+
+	ticketCategoryRepo repositories.TicketCategoryRepository
+	historyRepo        repositories.TicketHistoryRepository
+	articleService     KnowledgeArticleService
+	attachmentService  TicketAttachmentService
 }
 
 // NewTicketSolutionService crée une nouvelle instance de TicketSolutionService
@@ -36,14 +53,22 @@ func NewTicketSolutionService(
 	roleRepo repositories.RoleRepository,
 	kbArticleRepo repositories.KnowledgeArticleRepository,
 	kbCategoryRepo repositories.KnowledgeCategoryRepository,
+	ticketCategoryRepo repositories.TicketCategoryRepository,
+	historyRepo repositories.TicketHistoryRepository,
+	articleService KnowledgeArticleService,
+	attachmentService TicketAttachmentService,
 ) TicketSolutionService {
 	return &ticketSolutionService{
-		solutionRepo:   solutionRepo,
-		ticketRepo:     ticketRepo,
-		userRepo:       userRepo,
-		roleRepo:       roleRepo,
-		kbArticleRepo:  kbArticleRepo,
-		kbCategoryRepo: kbCategoryRepo,
+		solutionRepo:       solutionRepo,
+		ticketRepo:         ticketRepo,
+		userRepo:           userRepo,
+		roleRepo:           roleRepo,
+		kbArticleRepo:      kbArticleRepo,
+		kbCategoryRepo:     kbCategoryRepo,
+		ticketCategoryRepo: ticketCategoryRepo,
+		historyRepo:        historyRepo,
+		articleService:     articleService,
+		attachmentService:  attachmentService,
 	}
 }
 
@@ -52,7 +77,7 @@ func (s *ticketSolutionService) Create(ticketID uint, req dto.CreateTicketSoluti
 	// Vérifier que le ticket existe et est résolu ou clôturé (résolveurs/assignés peuvent documenter dès que le ticket est résolu)
 	ticket, err := s.ticketRepo.FindByID(ticketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	if ticket.Status != "resolu" && ticket.Status != "cloture" {
@@ -62,7 +87,7 @@ func (s *ticketSolutionService) Create(ticketID uint, req dto.CreateTicketSoluti
 	// Vérifier que l'utilisateur est assigné ou admin
 	user, err := s.userRepo.FindByID(createdByID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	// Vérifier si l'utilisateur est assigné au ticket ou est admin
@@ -117,7 +142,7 @@ func (s *ticketSolutionService) Create(ticketID uint, req dto.CreateTicketSoluti
 func (s *ticketSolutionService) GetByID(id uint) (*dto.TicketSolutionDTO, error) {
 	solution, err := s.solutionRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("solution introuvable")
+		return nil, utils.ErrSolutionNotFound
 	}
 
 	solutionDTO := s.solutionToDTO(solution)
@@ -143,13 +168,13 @@ func (s *ticketSolutionService) GetByTicketID(ticketID uint) ([]dto.TicketSoluti
 func (s *ticketSolutionService) Update(id uint, req dto.UpdateTicketSolutionRequest, updatedByID uint) (*dto.TicketSolutionDTO, error) {
 	solution, err := s.solutionRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("solution introuvable")
+		return nil, utils.ErrSolutionNotFound
 	}
 
 	// Vérifier que l'utilisateur est le créateur, assigné au ticket ou admin
 	user, err := s.userRepo.FindByID(updatedByID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	isCreator := solution.CreatedByID == updatedByID
@@ -165,7 +190,7 @@ func (s *ticketSolutionService) Update(id uint, req dto.UpdateTicketSolutionRequ
 	// Vérifier si l'utilisateur est assigné au ticket
 	ticket, err := s.ticketRepo.FindByID(solution.TicketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	isAssigned := false
@@ -204,7 +229,7 @@ func (s *ticketSolutionService) Update(id uint, req dto.UpdateTicketSolutionRequ
 func (s *ticketSolutionService) Delete(id uint) error {
 	_, err := s.solutionRepo.FindByID(id)
 	if err != nil {
-		return errors.New("solution introuvable")
+		return utils.ErrSolutionNotFound
 	}
 
 	if err := s.solutionRepo.Delete(id); err != nil {
@@ -219,7 +244,7 @@ func (s *ticketSolutionService) PublishToKB(solutionID uint, req dto.PublishSolu
 	// Récupérer la solution
 	solution, err := s.solutionRepo.FindByID(solutionID)
 	if err != nil {
-		return nil, errors.New("solution introuvable")
+		return nil, utils.ErrSolutionNotFound
 	}
 
 	// Vérifier que la catégorie KB existe
@@ -230,12 +255,13 @@ func (s *ticketSolutionService) PublishToKB(solutionID uint, req dto.PublishSolu
 
 	// Créer l'article de base de connaissances
 	article := &models.KnowledgeArticle{
-		Title:       req.Title,
-		Content:     solution.Solution,
-		CategoryID:  req.CategoryID,
-		AuthorID:    publishedByID,
-		IsPublished: true, // Publication directe
-		ViewCount:   0,
+		Title:         req.Title,
+		Content:       solution.Solution,
+		ContentFormat: richtext.FormatMarkdown, // Solutions documentées en Markdown
+		CategoryID:    req.CategoryID,
+		AuthorID:      publishedByID,
+		IsPublished:   true, // Publication directe
+		ViewCount:     0,
 	}
 
 	if err := s.kbArticleRepo.Create(article); err != nil {
@@ -253,14 +279,168 @@ func (s *ticketSolutionService) PublishToKB(solutionID uint, req dto.PublishSolu
 	return &articleDTO, nil
 }
 
+// PublishAsArticle rédige un brouillon d'article à partir d'une solution, à relire avant publication
+// L'article reprend le titre et la description du ticket (symptômes), la solution (résolution) et ses pièces jointes ;
+// il est lié au ticket et à la solution, qui ne peut être convertie qu'une fois
+func (s *ticketSolutionService) PublishAsArticle(ticketID, solutionID uint, req dto.PublishSolutionAsArticleRequest, authorID uint) (*dto.KnowledgeArticleDTO, error) {
+	solution, err := s.solutionRepo.FindByID(solutionID)
+	if err != nil || solution.TicketID != ticketID {
+		return nil, utils.ErrSolutionNotFound
+	}
+	if solution.ArticleID != nil {
+		if _, err := s.kbArticleRepo.FindByID(*solution.ArticleID); err == nil {
+			return nil, fmt.Errorf("cette solution a déjà été rédigée en article (article %d)", *solution.ArticleID)
+		}
+		// Article supprimé entre-temps : en rédiger un nouveau
+	}
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return nil, utils.ErrTicketNotFound
+	}
+
+	categoryID, err := s.solutionArticleCategory(ticket, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = truncateRunes(ticket.Title, 255)
+	}
+	symptoms := strings.TrimSpace(req.Symptoms)
+	if symptoms == "" {
+		symptoms = richtext.PlainText(ticket.Description, ticket.DescriptionFormat)
+	}
+	images, files, err := s.solutionAttachments(ticketID, req.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "Ticket %s : %s\n\n", ticket.Code, ticket.Title)
+	if symptoms != "" {
+		content.WriteString("## Symptômes\n\n" + symptoms + "\n\n")
+	}
+	content.WriteString("## Résolution\n\n" + solution.Solution + "\n")
+	if len(images) > 0 {
+		content.WriteString("\n## Captures\n\n" + strings.Join(images, "\n\n") + "\n")
+	}
+	if len(files) > 0 {
+		content.WriteString("\n## Pièces jointes du ticket\n\n- " + strings.Join(files, "\n- ") + "\n")
+	}
+
+	article, err := s.articleService.Create(dto.CreateKnowledgeArticleRequest{
+		Title:         title,
+		Content:       content.String(),
+		ContentFormat: richtext.FormatMarkdown,
+		CategoryID:    categoryID,
+	}, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Liens entre l'article, la solution et le ticket
+	if err := s.kbArticleRepo.SetSourceTicket(article.ID, ticketID); err != nil {
+		log.Printf("Erreur lors de la liaison de l'article %d au ticket %d: %v", article.ID, ticketID, err)
+	}
+	article.SourceTicketID = &ticketID
+	solution.ArticleID = &article.ID
+	if err := s.solutionRepo.Update(solution); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement de l'article de la solution")
+	}
+	if err := s.historyRepo.Create(&models.TicketHistory{
+		TicketID:    ticketID,
+		UserID:      authorID,
+		Action:      "knowledge_article_drafted",
+		NewValue:    strconv.FormatUint(uint64(article.ID), 10),
+		Description: "Article de la base de connaissances rédigé à partir de la solution : " + article.Title,
+	}); err != nil {
+		log.Printf("Erreur lors de l'enregistrement de l'historique du ticket %d: %v", ticketID, err)
+	}
+	return article, nil
+}
+
+// solutionArticleCategory détermine la catégorie de l'article : celle demandée, à défaut celle associée à la catégorie du ticket
+func (s *ticketSolutionService) solutionArticleCategory(ticket *models.Ticket, requested *uint) (uint, error) {
+	if requested != nil && *requested != 0 {
+		return *requested, nil
+	}
+	var ticketCategory *models.TicketCategory
+	if ticket.CategoryID != nil {
+		ticketCategory, _ = s.ticketCategoryRepo.FindByID(*ticket.CategoryID)
+	} else if ticket.Category != "" {
+		ticketCategory, _ = s.ticketCategoryRepo.FindBySlug(ticket.Category)
+	}
+	if ticketCategory == nil || ticketCategory.KnowledgeCategoryID == nil {
+		return 0, errors.New("aucune catégorie de la base de connaissances n'est associée à la catégorie du ticket : category_id est requis")
+	}
+	return *ticketCategory.KnowledgeCategoryID, nil
+}
+
+// solutionAttachments reprend les pièces jointes du ticket (toutes, ou celles demandées) : les images sont intégrées
+// au contenu (data URI, enregistrées en pièces jointes de l'article à sa création), les autres fichiers sont cités par leur nom
+func (s *ticketSolutionService) solutionAttachments(ticketID uint, ids []uint) (images, files []string, err error) {
+	attachments, err := s.attachmentService.GetByTicketID(ticketID, false)
+	if err != nil {
+		return nil, nil, errors.New("erreur lors de la récupération des pièces jointes du ticket")
+	}
+	if len(ids) > 0 {
+		selected := make([]dto.TicketAttachmentDTO, 0, len(ids))
+		for _, id := range ids {
+			i := slices.IndexFunc(attachments, func(a dto.TicketAttachmentDTO) bool { return a.ID == id })
+			if i < 0 {
+				return nil, nil, utils.ErrAttachmentNotFound.WithDetails(map[string]any{"attachment_id": id})
+			}
+			selected = append(selected, attachments[i])
+		}
+		attachments = selected
+	}
+
+	for _, attachment := range attachments {
+		if attachment.IsImage && len(images) < richtext.MaxEmbeddedImages {
+			if image, ok := s.embeddedImage(ticketID, &attachment); ok {
+				images = append(images, image)
+				continue
+			}
+		}
+		files = append(files, attachment.FileName)
+	}
+	return images, files, nil
+}
+
+// embeddedImage lit une image du ticket et la retourne en image Markdown intégrée ; false si son format
+// n'est pas accepté par la base de connaissances, si elle dépasse la taille maximale ou si elle est illisible
+func (s *ticketSolutionService) embeddedImage(ticketID uint, attachment *dto.TicketAttachmentDTO) (string, bool) {
+	switch attachment.MimeType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+	default:
+		return "", false
+	}
+	object, _, err := s.attachmentService.OpenFileForTicket(ticketID, attachment.ID)
+	if err != nil {
+		log.Printf("Erreur lors de la lecture de la pièce jointe %d du ticket %d: %v", attachment.ID, ticketID, err)
+		return "", false
+	}
+	defer object.Body.Close()
+
+	maxSize := config.AppConfig.MaxUploadSize
+	data, err := io.ReadAll(io.LimitReader(object.Body, maxSize+1))
+	if err != nil || int64(len(data)) > maxSize {
+		return "", false
+	}
+	alt := strings.NewReplacer("[", "", "]", "", "\n", " ").Replace(attachment.FileName)
+	return fmt.Sprintf("![%s](data:%s;base64,%s)", alt, attachment.MimeType, base64.StdEncoding.EncodeToString(data)), true
+}
+
 // solutionToDTO convertit un modèle TicketSolution en DTO
 func (s *ticketSolutionService) solutionToDTO(solution *models.TicketSolution) dto.TicketSolutionDTO {
 	solutionDTO := dto.TicketSolutionDTO{
-		ID:        solution.ID,
-		TicketID:  solution.TicketID,
-		Solution:  solution.Solution,
-		CreatedAt: solution.CreatedAt,
-		UpdatedAt: solution.UpdatedAt,
+		ID:           solution.ID,
+		TicketID:     solution.TicketID,
+		Solution:     solution.Solution,
+		SolutionHTML: richtext.Render(solution.Solution, richtext.FormatMarkdown),
+		ArticleID:    solution.ArticleID,
+		CreatedAt:    solution.CreatedAt,
+		UpdatedAt:    solution.UpdatedAt,
 	}
 
 	// Convertir CreatedBy
@@ -300,15 +480,24 @@ func (s *ticketSolutionService) userToDTO(user *models.User) dto.UserDTO {
 // articleToDTO convertit un modèle KnowledgeArticle en DTO
 func (s *ticketSolutionService) articleToDTO(article *models.KnowledgeArticle) dto.KnowledgeArticleDTO {
 	articleDTO := dto.KnowledgeArticleDTO{
-		ID:          article.ID,
-		Title:       article.Title,
-		Content:     article.Content,
-		CategoryID:  article.CategoryID,
-		AuthorID:    article.AuthorID,
-		IsPublished: article.IsPublished,
-		ViewCount:   article.ViewCount,
-		CreatedAt:   article.CreatedAt,
-		UpdatedAt:   article.UpdatedAt,
+		ID:              article.ID,
+		Title:           article.Title,
+		Content:         article.Content,
+		ContentFormat:   richtext.NormalizeFormat(article.ContentFormat),
+		ContentHTML:     richtext.Render(article.Content, article.ContentFormat),
+		CategoryID:      article.CategoryID,
+		AuthorID:        article.AuthorID,
+		SourceTicketID:  article.SourceTicketID,
+		IsPublic:        article.IsPublic,
+		IsPublished:     article.IsPublished,
+		Status:          article.Status,
+		Version:         article.Version,
+		ViewCount:       article.ViewCount,
+		HelpfulCount:    article.HelpfulCount,
+		NotHelpfulCount: article.NotHelpfulCount,
+		HelpfulRate:     article.HelpfulRate,
+		CreatedAt:       article.CreatedAt,
+		UpdatedAt:       article.UpdatedAt,
 	}
 
 	// Convertir Category
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/richtext"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketTimelineService interface pour le fil d'activité d'un ticket (historique, commentaires, pièces jointes, temps passés et SLA)
+type TicketTimelineService interface {
+	GetTimeline(ticketID uint, queryScope *scope.QueryScope, cursor string, limit int) (*dto.TicketTimelineDTO, error)
+}
+
+// timelineCursorPrefix version du format de curseur du fil d'activité
+const timelineCursorPrefix = "t1:"
+
+// timelineEntry élément du fil avec sa clé de tri
+type timelineEntry struct {
+	source string
+	dto    dto.TicketTimelineEntryDTO
+}
+
+// ticketTimelineService implémente TicketTimelineService
+type ticketTimelineService struct {
+	timelineRepo            repositories.TicketTimelineRepository
+	ticketSLARepo           repositories.TicketSLARepository
+	businessCalendarService BusinessCalendarService
+	shareRepo               repositories.RecordShareRepository
+}
+
+// NewTicketTimelineService crée une nouvelle instance de TicketTimelineService
+func NewTicketTimelineService(
+	timelineRepo repositories.TicketTimelineRepository,
+	ticketSLARepo repositories.TicketSLARepository,
+	businessCalendarService BusinessCalendarService,
+	shareRepo repositories.RecordShareRepository,
+) TicketTimelineService {
+	return &ticketTimelineService{
+		timelineRepo:            timelineRepo,
+		ticketSLARepo:           ticketSLARepo,
+		businessCalendarService: businessCalendarService,
+		shareRepo:               shareRepo,
+	}
+}
+
+// GetTimeline retourne une page du fil d'activité, du plus ancien au plus récent, après le curseur fourni
+// Chaque source est lue dans la limite de la page, puis les éléments sont fusionnés par date
+func (s *ticketTimelineService) GetTimeline(ticketID uint, queryScope *scope.QueryScope, cursor string, limit int) (*dto.TicketTimelineDTO, error) {
+	after, err := decodeTimelineCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if queryScope == nil {
+		return nil, utils.ErrTicketNotFound
+	}
+	visible, err := s.shareRepo.IsVisible(models.ShareResourceTicket, ticketID, queryScope)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la vérification des droits d'accès")
+	}
+	if !visible {
+		return nil, utils.ErrTicketNotFound
+	}
+	// Commentaires internes : départements IT, ou rôles disposant de tickets.comments.view_internal
+	includeInternal := queryScope.DepartmentIsIT || queryScope.HasPermission("tickets.comments.view_internal")
+
+	// Une ligne de plus que la page pour savoir s'il reste des éléments
+	fetch := limit + 1
+	var entries []timelineEntry
+
+	histories, err := s.timelineRepo.FindHistory(ticketID, after, fetch)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du fil d'activité")
+	}
+	for i := range histories {
+		entries = append(entries, timelineHistoryEntry(&histories[i]))
+	}
+	comments, err := s.timelineRepo.FindComments(ticketID, includeInternal, after, fetch)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du fil d'activité")
+	}
+	for i := range comments {
+		comment := &comments[i]
+		user := userToDTO(&comment.User)
+		entries = append(entries, timelineEntry{source: repositories.TimelineSourceComment, dto: dto.TicketTimelineEntryDTO{
+			Type:        "comment",
+			ID:          comment.ID,
+			At:          comment.CreatedAt,
+			User:        &user,
+			Comment:     comment.Comment,
+			CommentHTML: richtext.Render(comment.Comment, comment.Format),
+			IsInternal:  comment.IsInternal,
+		}})
+	}
+	attachments, err := s.timelineRepo.FindAttachments(ticketID, after, fetch)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du fil d'activité")
+	}
+	for i := range attachments {
+		attachment := &attachments[i]
+		user := userToDTO(&attachment.User)
+		entries = append(entries, timelineEntry{source: repositories.TimelineSourceAttachment, dto: dto.TicketTimelineEntryDTO{
+			Type:        "attachment",
+			ID:          attachment.ID,
+			At:          attachment.CreatedAt,
+			User:        &user,
+			FileName:    attachment.FileName,
+			MimeType:    attachment.MimeType,
+			FileSize:    attachment.FileSize,
+			IsImage:     attachment.IsImage,
+			Description: attachment.Description,
+		}})
+	}
+	timeEntries, err := s.timelineRepo.FindTimeEntries(ticketID, after, fetch)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération du fil d'activité")
+	}
+	for i := range timeEntries {
+		timeEntry := &timeEntries[i]
+		user := userToDTO(&timeEntry.User)
+		entries = append(entries, timelineEntry{source: repositories.TimelineSourceTimeEntry, dto: dto.TicketTimelineEntryDTO{
+			Type:        "time_entry",
+			ID:          timeEntry.ID,
+			At:          timeEntry.CreatedAt,
+			User:        &user,
+			TimeSpent:   &timeEntry.TimeSpent,
+			Date:        &timeEntry.Date,
+			Description: timeEntry.Description,
+		}})
+	}
+	for _, entry := range s.slaEntries(ticketID) {
+		if after.After(entry.dto.At, entry.source, entry.dto.ID) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.dto.At.Equal(b.dto.At) {
+			return a.dto.At.Before(b.dto.At)
+		}
+		if a.source != b.source {
+			return a.source < b.source
+		}
+		return a.dto.ID < b.dto.ID
+	})
+
+	timeline := &dto.TicketTimelineDTO{Items: make([]dto.TicketTimelineEntryDTO, 0, limit)}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		timeline.HasMore = true
+		last := entries[len(entries)-1]
+		timeline.NextCursor = encodeTimelineCursor(last.dto.At, last.source, last.dto.ID)
+	}
+	for _, entry := range entries {
+		timeline.Items = append(timeline.Items, entry.dto)
+	}
+	return timeline, nil
+}
+
+// slaEntries reconstitue les transitions SLA du ticket : démarrage, passage à risque, violation, respect
+// Le passage à risque est daté au moment où il reste 25% du délai ouvré (même règle que le recalcul périodique)
+func (s *ticketTimelineService) slaEntries(ticketID uint) []timelineEntry {
+	ticketSLA, err := s.ticketSLARepo.FindByTicketID(ticketID)
+	if err != nil || ticketSLA == nil {
+		return nil
+	}
+	target := ticketSLA.TargetTime
+	newEntry := func(rank uint, action string, at time.Time, description string) timelineEntry {
+		return timelineEntry{source: repositories.TimelineSourceSLA, dto: dto.TicketTimelineEntryDTO{
+			Type:        "sla",
+			ID:          rank,
+			At:          at,
+			Action:      action,
+			TargetTime:  &target,
+			Description: description,
+		}}
+	}
+
+	entries := []timelineEntry{newEntry(1, "sla_started", ticketSLA.CreatedAt, fmt.Sprintf("SLA « %s » appliqué", ticketSLA.SLA.Name))}
+
+	// Fin de la période observée : résolution, sinon maintenant
+	end := time.Now()
+	if ticketSLA.ActualTime != nil {
+		end = *ticketSLA.ActualTime
+	}
+	calendar := s.businessCalendarService.CalendarFor(ticketSLA.Ticket.FilialeID)
+	start := ticketSLA.Ticket.CreatedAt
+	if total := calendar.Duration(start, target); total > 0 {
+		atRisk := calendar.Add(start, total-total/4)
+		if atRisk.Before(target) && !end.Before(atRisk) {
+			entries = append(entries, newEntry(2, "sla_at_risk", atRisk, "Échéance SLA à risque"))
+		}
+	}
+	if end.After(target) {
+		entries = append(entries, newEntry(3, "sla_violated", target, "Échéance SLA dépassée"))
+	} else if ticketSLA.ActualTime != nil {
+		entries = append(entries, newEntry(4, "sla_met", *ticketSLA.ActualTime, "SLA respecté"))
+	}
+	return entries
+}
+
+// timelineHistoryEntry convertit une entrée d'historique en élément du fil (les assignations ont leur propre type)
+func timelineHistoryEntry(history *models.TicketHistory) timelineEntry {
+	entryType := "history"
+	if history.Action == "assigned" {
+		entryType = "assignment"
+	}
+	user := userToDTO(&history.User)
+	return timelineEntry{source: repositories.TimelineSourceHistory, dto: dto.TicketTimelineEntryDTO{
+		Type:        entryType,
+		ID:          history.ID,
+		At:          history.CreatedAt,
+		User:        &user,
+		Action:      history.Action,
+		FieldName:   history.FieldName,
+		OldValue:    history.OldValue,
+		NewValue:    history.NewValue,
+		Description: history.Description,
+	}}
+}
+
+// encodeTimelineCursor encode la position du dernier élément d'une page en curseur opaque
+func encodeTimelineCursor(at time.Time, source string, id uint) string {
+	raw := timelineCursorPrefix + strconv.FormatInt(at.UnixNano(), 10) + ":" + source + ":" + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeTimelineCursor lit un curseur retourné par une page précédente (vide = début du fil)
+func decodeTimelineCursor(cursor string) (*repositories.TicketTimelineCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("curseur de pagination invalide")
+	}
+	value, ok := strings.CutPrefix(string(decoded), timelineCursorPrefix)
+	if !ok {
+		return nil, errors.New("curseur de pagination invalide")
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return nil, errors.New("curseur de pagination invalide")
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, errors.New("curseur de pagination invalide")
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, errors.New("curseur de pagination invalide")
+	}
+	return &repositories.TicketTimelineCursor{At: time.Unix(0, nanos), Source: parts[1], ID: uint(id)}, nil
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketViewService interface pour les vues de tickets enregistrées (filtres nommés, personnels ou partagés)
+type TicketViewService interface {
+	GetAll(userID uint) ([]dto.TicketViewDTO, error) // Vues de l'utilisateur puis vues partagées
+	GetByID(id, userID uint) (*dto.TicketViewDTO, error)
+	Create(req dto.CreateTicketViewRequest, userID uint) (*dto.TicketViewDTO, error)
+	Update(id uint, req dto.UpdateTicketViewRequest, userID uint) (*dto.TicketViewDTO, error)
+	Delete(id, userID uint) error
+	GetResults(id uint, scope interface{}, userID uint, page, limit int) (*dto.TicketListResponse, error) // Exécute le filtre de la vue dans le périmètre de l'utilisateur
+}
+
+// ticketViewService implémente TicketViewService
+type ticketViewService struct {
+	viewRepo      repositories.TicketViewRepository
+	ticketService TicketService
+}
+
+// NewTicketViewService crée une nouvelle instance de TicketViewService
+func NewTicketViewService(viewRepo repositories.TicketViewRepository, ticketService TicketService) TicketViewService {
+	return &ticketViewService{
+		viewRepo:      viewRepo,
+		ticketService: ticketService,
+	}
+}
+
+// GetAll récupère les vues visibles par l'utilisateur
+func (s *ticketViewService) GetAll(userID uint) ([]dto.TicketViewDTO, error) {
+	views, err := s.viewRepo.FindVisible(userID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des vues de tickets")
+	}
+	viewDTOs := make([]dto.TicketViewDTO, 0, len(views))
+	for i := range views {
+		viewDTOs = append(viewDTOs, ticketViewToDTO(&views[i], userID))
+	}
+	return viewDTOs, nil
+}
+
+// GetByID récupère une vue ; une vue privée d'un autre utilisateur est introuvable
+func (s *ticketViewService) GetByID(id, userID uint) (*dto.TicketViewDTO, error) {
+	view, err := s.findVisible(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	viewDTO := ticketViewToDTO(view, userID)
+	return &viewDTO, nil
+}
+
+// Create crée une vue appartenant à l'utilisateur
+func (s *ticketViewService) Create(req dto.CreateTicketViewRequest, userID uint) (*dto.TicketViewDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("le nom de la vue est obligatoire")
+	}
+	filters, err := encodeTicketViewFilters(req.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.TicketView{
+		Name:        name,
+		Description: req.Description,
+		UserID:      userID,
+		IsShared:    req.IsShared,
+		Filters:     filters,
+	}
+	if err := s.viewRepo.Create(view); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la création de la vue de tickets")
+	}
+	return s.GetByID(view.ID, userID)
+}
+
+// Update met à jour une vue (réservé à son propriétaire)
+func (s *ticketViewService) Update(id uint, req dto.UpdateTicketViewRequest, userID uint) (*dto.TicketViewDTO, error) {
+	view, err := s.findOwned(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("le nom de la vue est obligatoire")
+		}
+		view.Name = name
+	}
+	if req.Description != nil {
+		view.Description = *req.Description
+	}
+	if req.IsShared != nil {
+		view.IsShared = *req.IsShared
+	}
+	if req.Filters != nil {
+		filters, err := encodeTicketViewFilters(*req.Filters)
+		if err != nil {
+			return nil, err
+		}
+		view.Filters = filters
+	}
+
+	if err := s.viewRepo.Update(view); err != nil {
+		return nil, utils.NewInternalError("erreur lors de la mise à jour de la vue de tickets")
+	}
+	return s.GetByID(view.ID, userID)
+}
+
+// Delete supprime une vue (réservé à son propriétaire)
+func (s *ticketViewService) Delete(id, userID uint) error {
+	if _, err := s.findOwned(id, userID); err != nil {
+		return err
+	}
+	if err := s.viewRepo.Delete(id); err != nil {
+		return utils.NewInternalError("erreur lors de la suppression de la vue de tickets")
+	}
+	return nil
+}
+
+// GetResults exécute le filtre de la vue ; le scope restreint les résultats aux tickets visibles par l'utilisateur
+// Les dates sont interprétées dans le fuseau de l'utilisateur qui consulte la vue
+func (s *ticketViewService) GetResults(id uint, scopeParam interface{}, userID uint, page, limit int) (*dto.TicketListResponse, error) {
+	view, err := s.findVisible(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	filters := decodeTicketViewFilters(view.Filters)
+	queryScope, _ := scopeParam.(*scope.QueryScope)
+
+	filter := repositories.TicketFilter{
+		Statuses:   filters.Statuses,
+		Priorities: filters.Priorities,
+		FilialeID:  filters.FilialeID,
+		AssigneeID: filters.AssigneeID,
+		Text:       strings.TrimSpace(filters.Text),
+	}
+	if filters.AssignedToMe {
+		filter.AssigneeID = &userID
+	}
+	if filters.CreatedFrom != "" {
+		if date, err := timezone.ParseDate(filters.CreatedFrom); err == nil {
+			from, _ := timezone.DayBounds(date, queryScope.Loc())
+			filter.CreatedFrom = &from
+		}
+	}
+	if filters.CreatedTo != "" {
+		if date, err := timezone.ParseDate(filters.CreatedTo); err == nil {
+			_, to := timezone.DayBounds(date, queryScope.Loc())
+			filter.CreatedTo = &to
+		}
+	}
+	return s.ticketService.GetByFilter(scopeParam, filter, page, limit)
+}
+
+// findVisible récupère une vue de l'utilisateur ou une vue partagée
+func (s *ticketViewService) findVisible(id, userID uint) (*models.TicketView, error) {
+	view, err := s.viewRepo.FindByID(id)
+	if err != nil || (view.UserID != userID && !view.IsShared) {
+		return nil, utils.ErrTicketViewNotFound
+	}
+	return view, nil
+}
+
+// findOwned récupère une vue modifiable par l'utilisateur (son propriétaire)
+func (s *ticketViewService) findOwned(id, userID uint) (*models.TicketView, error) {
+	view, err := s.findVisible(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if view.UserID != userID {
+		return nil, utils.ErrTicketViewForbidden
+	}
+	return view, nil
+}
+
+// encodeTicketViewFilters valide la définition du filtre et la sérialise
+func encodeTicketViewFilters(filters dto.TicketViewFiltersDTO) ([]byte, error) {
+	var from, to time.Time
+	var err error
+	if filters.CreatedFrom != "" {
+		if from, err = timezone.ParseDate(filters.CreatedFrom); err != nil {
+			return nil, errors.New("date de début invalide (format YYYY-MM-DD attendu)")
+		}
+	}
+	if filters.CreatedTo != "" {
+		if to, err = timezone.ParseDate(filters.CreatedTo); err != nil {
+			return nil, errors.New("date de fin invalide (format YYYY-MM-DD attendu)")
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, errors.New("la date de fin doit être postérieure à la date de début")
+	}
+	filters.Text = strings.TrimSpace(filters.Text)
+	return json.Marshal(filters)
+}
+
+// decodeTicketViewFilters lit la définition du filtre enregistrée (vide si illisible)
+func decodeTicketViewFilters(raw []byte) dto.TicketViewFiltersDTO {
+	var filters dto.TicketViewFiltersDTO
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &filters)
+	}
+	return filters
+}
+
+// ticketViewToDTO convertit une vue en DTO du point de vue de l'utilisateur courant
+func ticketViewToDTO(view *models.TicketView, userID uint) dto.TicketViewDTO {
+	viewDTO := dto.TicketViewDTO{
+		ID:          view.ID,
+		Name:        view.Name,
+		Description: view.Description,
+		UserID:      view.UserID,
+		IsShared:    view.IsShared,
+		IsOwner:     view.UserID == userID,
+		Filters:     decodeTicketViewFilters(view.Filters),
+		CreatedAt:   view.CreatedAt,
+		UpdatedAt:   view.UpdatedAt,
+	}
+	if view.User != nil {
+		userDTO := userToDTO(view.User)
+		viewDTO.User = &userDTO
+	}
+	return viewDTO
+}
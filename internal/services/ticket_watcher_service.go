@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// TicketWatcherService interface pour les abonnés aux tickets (notifiés comme le demandeur des changements de statut et des commentaires)
+type TicketWatcherService interface {
+	GetWatchers(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketWatcherDTO, error)
+	Watch(ticketID, userID, addedByID uint, queryScope *scope.QueryScope) ([]dto.TicketWatcherDTO, error)
+	Unwatch(ticketID, userID uint, queryScope *scope.QueryScope) ([]dto.TicketWatcherDTO, error)
+}
+
+// ticketWatcherService implémente TicketWatcherService
+type ticketWatcherService struct {
+	watcherRepo repositories.TicketWatcherRepository
+	userRepo    repositories.UserRepository
+	shareRepo   repositories.RecordShareRepository
+}
+
+// NewTicketWatcherService crée une nouvelle instance de TicketWatcherService
+func NewTicketWatcherService(
+	watcherRepo repositories.TicketWatcherRepository,
+	userRepo repositories.UserRepository,
+	shareRepo repositories.RecordShareRepository,
+) TicketWatcherService {
+	return &ticketWatcherService{
+		watcherRepo: watcherRepo,
+		userRepo:    userRepo,
+		shareRepo:   shareRepo,
+	}
+}
+
+// GetWatchers récupère les abonnés d'un ticket visible par l'utilisateur
+func (s *ticketWatcherService) GetWatchers(ticketID uint, queryScope *scope.QueryScope) ([]dto.TicketWatcherDTO, error) {
+	if err := checkTicketVisible(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	return s.watchers(ticketID)
+}
+
+// Watch abonne un utilisateur à un ticket ; l'abonné doit lui-même avoir accès au ticket (les notifications en reprennent le contenu)
+func (s *ticketWatcherService) Watch(ticketID, userID, addedByID uint, queryScope *scope.QueryScope) ([]dto.TicketWatcherDTO, error) {
+	if err := checkTicketVisible(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	if userID != addedByID {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil || !user.IsActive {
+			return nil, utils.ErrUserNotFound
+		}
+		userScope, err := scope.NewQueryScopeFromUser(user)
+		if err != nil {
+			return nil, utils.NewInternalError("erreur lors de la vérification des droits d'accès")
+		}
+		if visible, err := s.shareRepo.IsVisible(models.ShareResourceTicket, ticketID, userScope); err != nil || !visible {
+			return nil, errors.New("l'utilisateur n'a pas accès à ce ticket")
+		}
+	}
+
+	watcher := &models.TicketWatcher{
+		TicketID:  ticketID,
+		UserID:    userID,
+		AddedByID: addedByID,
+	}
+	if err := s.watcherRepo.Create(watcher); err != nil {
+		return nil, utils.NewInternalError("erreur lors de l'abonnement au ticket")
+	}
+	return s.watchers(ticketID)
+}
+
+// Unwatch désabonne un utilisateur d'un ticket (sans effet s'il ne le suivait pas)
+func (s *ticketWatcherService) Unwatch(ticketID, userID uint, queryScope *scope.QueryScope) ([]dto.TicketWatcherDTO, error) {
+	if err := checkTicketVisible(s.shareRepo, ticketID, queryScope); err != nil {
+		return nil, err
+	}
+	if err := s.watcherRepo.Delete(ticketID, userID); err != nil {
+		return nil, utils.NewInternalError("erreur lors du désabonnement du ticket")
+	}
+	return s.watchers(ticketID)
+}
+
+// watchers retourne la liste des abonnés d'un ticket
+func (s *ticketWatcherService) watchers(ticketID uint) ([]dto.TicketWatcherDTO, error) {
+	watchers, err := s.watcherRepo.FindByTicketID(ticketID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération des abonnés du ticket")
+	}
+	watcherDTOs := make([]dto.TicketWatcherDTO, 0, len(watchers))
+	for i := range watchers {
+		watcherDTOs = append(watcherDTOs, dto.TicketWatcherDTO{
+			User:      userToDTO(&watchers[i].User),
+			AddedByID: watchers[i].AddedByID,
+			CreatedAt: watchers[i].CreatedAt,
+		})
+	}
+	return watcherDTOs, nil
+}
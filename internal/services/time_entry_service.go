@@ -2,11 +2,14 @@ package services
 
 import (
 	"errors"
+	"log"
 	"time"
 
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // TimeEntryService interface pour les opérations sur les entrées de temps
@@ -28,10 +31,12 @@ type TimeEntryService interface {
 
 // timeEntryService implémente TimeEntryService
 type timeEntryService struct {
-	timeEntryRepo repositories.TimeEntryRepository
-	ticketRepo    repositories.TicketRepository
-	userRepo      repositories.UserRepository
-	delayRepo     repositories.DelayRepository
+	timeEntryRepo   repositories.TimeEntryRepository
+	ticketRepo      repositories.TicketRepository
+	userRepo        repositories.UserRepository
+	delayRepo       repositories.DelayRepository
+	ticketTaskRepo  repositories.TicketProjectTaskRepository
+	declarationRepo repositories.WeeklyDeclarationRepository
 }
 
 // NewTimeEntryService crée une nouvelle instance de TimeEntryService
@@ -40,12 +45,16 @@ func NewTimeEntryService(
 	ticketRepo repositories.TicketRepository,
 	userRepo repositories.UserRepository,
 	delayRepo repositories.DelayRepository,
+	ticketTaskRepo repositories.TicketProjectTaskRepository,
+	declarationRepo repositories.WeeklyDeclarationRepository,
 ) TimeEntryService {
 	return &timeEntryService{
-		timeEntryRepo: timeEntryRepo,
-		ticketRepo:    ticketRepo,
-		userRepo:      userRepo,
-		delayRepo:     delayRepo,
+		timeEntryRepo:   timeEntryRepo,
+		ticketRepo:      ticketRepo,
+		userRepo:        userRepo,
+		delayRepo:       delayRepo,
+		ticketTaskRepo:  ticketTaskRepo,
+		declarationRepo: declarationRepo,
 	}
 }
 
@@ -54,23 +63,43 @@ func (s *timeEntryService) Create(req dto.CreateTimeEntryRequest, userID uint) (
 	// Vérifier que le ticket existe
 	_, err := s.ticketRepo.FindByID(req.TicketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 
 	// Parser la date
-	date, err := time.Parse("2006-01-02", req.Date)
+	date, err := timezone.ParseDate(req.Date)
 	if err != nil {
 		return nil, errors.New("format de date invalide, attendu: YYYY-MM-DD")
 	}
 
+	// Refuser la saisie sur une semaine dont la déclaration est approuvée
+	if err := checkWeekUnlocked(s.declarationRepo, userID, date); err != nil {
+		return nil, err
+	}
+
+	// Catégorie : projet si le ticket est lié à une tâche de projet, sinon support ; facturable selon la catégorie
+	workCategory := req.WorkCategory
+	if workCategory == "" {
+		workCategory = models.WorkCategorySupport
+		if links, err := s.ticketTaskRepo.FindByTicketID(req.TicketID, nil); err == nil && len(links) > 0 {
+			workCategory = models.WorkCategoryProject
+		}
+	}
+	billable := models.DefaultBillable(workCategory)
+	if req.Billable != nil {
+		billable = *req.Billable
+	}
+
 	ticketID := req.TicketID
 	timeEntry := &models.TimeEntry{
-		TicketID:    &ticketID,
-		UserID:      userID,
-		TimeSpent:   req.TimeSpent,
-		Date:        date,
-		Description: req.Description,
-		Validated:   false,
+		TicketID:     &ticketID,
+		UserID:       userID,
+		TimeSpent:    req.TimeSpent,
+		Date:         date,
+		Description:  req.Description,
+		WorkCategory: workCategory,
+		Billable:     billable,
+		Validated:    false,
 	}
 
 	if err := s.timeEntryRepo.Create(timeEntry); err != nil {
@@ -95,7 +124,7 @@ func (s *timeEntryService) Create(req dto.CreateTimeEntryRequest, userID uint) (
 func (s *timeEntryService) GetByID(id uint) (*dto.TimeEntryDTO, error) {
 	timeEntry, err := s.timeEntryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("entrée de temps introuvable")
+		return nil, utils.ErrTimeEntryNotFound
 	}
 
 	entryDTO := s.timeEntryToDTO(timeEntry)
@@ -199,13 +228,16 @@ func (s *timeEntryService) GetPendingValidation(scopeParam interface{}) ([]dto.T
 func (s *timeEntryService) Update(id uint, req dto.UpdateTimeEntryRequest, updatedByID uint) (*dto.TimeEntryDTO, error) {
 	timeEntry, err := s.timeEntryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("entrée de temps introuvable")
+		return nil, utils.ErrTimeEntryNotFound
 	}
 
 	// Vérifier que l'entrée n'est pas validée (on ne peut pas modifier une entrée validée)
 	if timeEntry.Validated {
 		return nil, errors.New("impossible de modifier une entrée de temps validée")
 	}
+	if err := checkWeekUnlocked(s.declarationRepo, timeEntry.UserID, timeEntry.Date); err != nil {
+		return nil, err
+	}
 
 	// Mettre à jour les champs fournis
 	if req.TimeSpent > 0 {
@@ -214,6 +246,19 @@ func (s *timeEntryService) Update(id uint, req dto.UpdateTimeEntryRequest, updat
 	if req.Description != "" {
 		timeEntry.Description = req.Description
 	}
+	// La catégorie des entrées de tâche de projet et de ticket interne découle de leur rattachement
+	if req.WorkCategory != "" && req.WorkCategory != timeEntry.WorkCategory {
+		if timeEntry.TicketID == nil {
+			return nil, errors.New("la catégorie de travail n'est modifiable que pour les entrées sur ticket")
+		}
+		timeEntry.WorkCategory = req.WorkCategory
+		if req.Billable == nil {
+			timeEntry.Billable = models.DefaultBillable(req.WorkCategory)
+		}
+	}
+	if req.Billable != nil {
+		timeEntry.Billable = *req.Billable
+	}
 
 	if err := s.timeEntryRepo.Update(timeEntry); err != nil {
 		return nil, errors.New("erreur lors de la mise à jour de l'entrée de temps")
@@ -238,7 +283,7 @@ func (s *timeEntryService) Update(id uint, req dto.UpdateTimeEntryRequest, updat
 func (s *timeEntryService) Validate(id uint, req dto.ValidateTimeEntryRequest, validatedByID uint) (*dto.TimeEntryDTO, error) {
 	timeEntry, err := s.timeEntryRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("entrée de temps introuvable")
+		return nil, utils.ErrTimeEntryNotFound
 	}
 
 	// Vérifier que le validateur existe
@@ -246,6 +291,9 @@ func (s *timeEntryService) Validate(id uint, req dto.ValidateTimeEntryRequest, v
 	if err != nil {
 		return nil, errors.New("validateur introuvable")
 	}
+	if err := checkWeekUnlocked(s.declarationRepo, timeEntry.UserID, timeEntry.Date); err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	timeEntry.Validated = *req.Validated
@@ -270,13 +318,16 @@ func (s *timeEntryService) Validate(id uint, req dto.ValidateTimeEntryRequest, v
 func (s *timeEntryService) Delete(id uint) error {
 	timeEntry, err := s.timeEntryRepo.FindByID(id)
 	if err != nil {
-		return errors.New("entrée de temps introuvable")
+		return utils.ErrTimeEntryNotFound
 	}
 
 	// Vérifier que l'entrée n'est pas validée
 	if timeEntry.Validated {
 		return errors.New("impossible de supprimer une entrée de temps validée")
 	}
+	if err := checkWeekUnlocked(s.declarationRepo, timeEntry.UserID, timeEntry.Date); err != nil {
+		return err
+	}
 
 	if err := s.timeEntryRepo.Delete(id); err != nil {
 		return errors.New("erreur lors de la suppression de l'entrée de temps")
@@ -314,6 +365,13 @@ func (s *timeEntryService) updateTicketActualTime(ticketID uint, userID uint) {
 	ticket.ActualTime = &total
 	s.ticketRepo.Update(ticket)
 
+	// Report du temps sur les tâches de projet liées au ticket
+	if s.ticketTaskRepo != nil {
+		if err := s.ticketTaskRepo.RefreshTicketTimeByTicketID(ticketID); err != nil {
+			log.Printf("[updateTicketActualTime] ticket=%d: %v", ticketID, err)
+		}
+	}
+
 	// Détection du retard
 	if ticket.EstimatedTime == nil || *ticket.EstimatedTime <= 0 {
 		return
@@ -387,6 +445,8 @@ func (s *timeEntryService) timeEntryToDTO(timeEntry *models.TimeEntry) dto.TimeE
 		TimeSpent:     timeEntry.TimeSpent,
 		Date:          timeEntry.Date,
 		Description:   timeEntry.Description,
+		WorkCategory:  timeEntry.WorkCategory,
+		Billable:      timeEntry.Billable,
 		Validated:     timeEntry.Validated,
 		CreatedAt:     timeEntry.CreatedAt,
 		UpdatedAt:     timeEntry.UpdatedAt,
@@ -429,6 +489,8 @@ func (s *timeEntryService) timeEntryToDTOSafe(timeEntry *models.TimeEntry) dto.T
 		TimeSpent:     timeEntry.TimeSpent,
 		Date:          timeEntry.Date,
 		Description:   timeEntry.Description,
+		WorkCategory:  timeEntry.WorkCategory,
+		Billable:      timeEntry.Billable,
 		Validated:     timeEntry.Validated,
 		CreatedAt:     timeEntry.CreatedAt,
 		UpdatedAt:     timeEntry.UpdatedAt,
@@ -3,6 +3,8 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 	"github.com/mcicare/itsm-backend/database"
 )
 
@@ -41,6 +44,7 @@ type TimesheetService interface {
 	GetWeeklyTasks(week string, userID uint) ([]dto.WeeklyTaskDTO, error)
 	GetWeeklySummary(week string, userID uint) (*dto.WeeklySummaryDTO, error)
 	GetWeeklyDailyBreakdown(week string, userID uint) ([]dto.DailyBreakdownDTO, error)
+	SubmitWeeklyDeclaration(week string, userID uint, comment string) (*dto.WeeklyDeclarationDTO, error)
 	ValidateWeeklyDeclaration(week string, userID uint, validatedByID uint) (*dto.WeeklyDeclarationDTO, error)
 	GetWeeklyValidationStatus(week string, userID uint) (*dto.ValidationStatusDTO, error)
 
@@ -84,6 +88,8 @@ type timesheetService struct {
 	delayRepo                repositories.DelayRepository
 	delayJustificationRepo   repositories.DelayJustificationRepository
 	userRepo                 repositories.UserRepository
+	absenceRepo              repositories.AbsenceRepository
+	declarationRepo          repositories.WeeklyDeclarationRepository
 }
 
 // NewTimesheetService crée une nouvelle instance de TimesheetService
@@ -96,6 +102,8 @@ func NewTimesheetService(
 	delayRepo repositories.DelayRepository,
 	delayJustificationRepo repositories.DelayJustificationRepository,
 	userRepo repositories.UserRepository,
+	absenceRepo repositories.AbsenceRepository,
+	declarationRepo repositories.WeeklyDeclarationRepository,
 ) TimesheetService {
 	return &timesheetService{
 		timeEntryService:         timeEntryService,
@@ -106,6 +114,8 @@ func NewTimesheetService(
 		delayRepo:                delayRepo,
 		delayJustificationRepo:   delayJustificationRepo,
 		userRepo:                 userRepo,
+		absenceRepo:              absenceRepo,
+		declarationRepo:          declarationRepo,
 	}
 }
 
@@ -160,6 +170,11 @@ func (s *timesheetService) CreateOrUpdateDailyDeclaration(date time.Time, userID
 	// Normaliser la date (garder seulement la date, sans l'heure)
 	dateOnly := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 
+	// Refuser la saisie sur une semaine dont la déclaration est approuvée
+	if err := checkWeekUnlocked(s.declarationRepo, userID, dateOnly); err != nil {
+		return nil, err
+	}
+
 	// Vérifier si une déclaration existe déjà pour cette date et cet utilisateur
 	existingDeclaration, err := s.dailyDeclarationService.GetByUserIDAndDate(userID, dateOnly)
 	
@@ -362,6 +377,9 @@ func (s *timesheetService) GetDailyTasks(date time.Time, userID uint) ([]dto.Dai
 
 // CreateDailyTask crée une tâche dans une déclaration journalière
 func (s *timesheetService) CreateDailyTask(date time.Time, userID uint, task dto.DailyTaskRequest) (*dto.DailyTaskDTO, error) {
+	if err := checkWeekUnlocked(s.declarationRepo, userID, date); err != nil {
+		return nil, err
+	}
 	existingTasks, _ := s.GetDailyTasks(date, userID)
 	tasks := make([]dto.DailyTaskRequest, 0, len(existingTasks)+1)
 	for _, existing := range existingTasks {
@@ -392,6 +410,9 @@ func (s *timesheetService) CreateDailyTask(date time.Time, userID uint, task dto
 
 // DeleteDailyTask supprime une tâche d'une déclaration journalière
 func (s *timesheetService) DeleteDailyTask(date time.Time, userID uint, taskID uint) error {
+	if err := checkWeekUnlocked(s.declarationRepo, userID, date); err != nil {
+		return err
+	}
 	existingTasks, _ := s.GetDailyTasks(date, userID)
 	tasks := make([]dto.DailyTaskRequest, 0, len(existingTasks))
 	for _, existing := range existingTasks {
@@ -416,7 +437,7 @@ func (s *timesheetService) DeleteDailyTask(date time.Time, userID uint, taskID u
 func (s *timesheetService) GetDailySummary(date time.Time, userID uint) (*dto.DailySummaryDTO, error) {
 	declaration, err := s.dailyDeclarationService.GetByUserIDAndDate(userID, date)
 	if err != nil || declaration == nil {
-		return nil, errors.New("déclaration introuvable")
+		return nil, utils.ErrDeclarationNotFound
 	}
 
 	summary := &dto.DailySummaryDTO{
@@ -425,6 +446,10 @@ func (s *timesheetService) GetDailySummary(date time.Time, userID uint) (*dto.Da
 		TotalTime: declaration.TotalTime,
 		Validated: declaration.Validated,
 	}
+	absent := s.approvedAbsentDays(userID, date, date)
+	summary.Absence = absent[calendarDay(date).Format("2006-01-02")]
+	summary.ExpectedTime, _ = expectedWorkload(date, date, absent)
+	summary.UnderDeclared = summary.TotalTime < summary.ExpectedTime
 	return summary, nil
 }
 
@@ -435,19 +460,47 @@ func (s *timesheetService) GetDailyCalendar(userID uint, startDate, endDate time
 		return nil, err
 	}
 
-	calendar := make([]dto.DailyCalendarDTO, 0, len(declarations))
+	// Les jours d'absence approuvée figurent au calendrier même sans déclaration
+	absent := s.approvedAbsentDays(userID, startDate, endDate)
+	declaredDays := make(map[string]bool, len(declarations))
+	calendar := make([]dto.DailyCalendarDTO, 0, len(declarations)+len(absent))
 	for _, declaration := range declarations {
+		day := calendarDay(declaration.Date)
+		declaredDays[day.Format("2006-01-02")] = true
+		expectedTime, _ := expectedWorkload(day, day, absent)
 		calendar = append(calendar, dto.DailyCalendarDTO{
-			Date:      declaration.Date,
-			HasEntry:  true,
-			TotalTime: declaration.TotalTime,
-			Validated: declaration.Validated,
+			Date:          declaration.Date,
+			HasEntry:      true,
+			TotalTime:     declaration.TotalTime,
+			Validated:     declaration.Validated,
+			ExpectedTime:  expectedTime,
+			Absence:       absent[day.Format("2006-01-02")],
+			UnderDeclared: declaration.TotalTime < expectedTime,
 		})
 	}
+	for day, absenceType := range absent {
+		if declaredDays[day] {
+			continue
+		}
+		date, _ := time.Parse("2006-01-02", day)
+		calendar = append(calendar, dto.DailyCalendarDTO{Date: date, Absence: absenceType})
+	}
+	sort.Slice(calendar, func(i, j int) bool { return calendar[i].Date.Before(calendar[j].Date) })
 
 	return calendar, nil
 }
 
+// approvedAbsentDays indexe les jours ouvrés d'absence approuvée de l'utilisateur sur la période ;
+// en cas d'erreur, le temps attendu est calculé sans les absences
+func (s *timesheetService) approvedAbsentDays(userID uint, from, to time.Time) map[string]string {
+	absences, err := s.absenceRepo.FindApproved([]uint{userID}, from, to)
+	if err != nil {
+		log.Printf("[Timesheet] absences de l'utilisateur %d: %v", userID, err)
+		return map[string]string{}
+	}
+	return absentDays(absences, from, to)
+}
+
 // GetDailyRange récupère les déclarations journalières dans une plage de dates
 func (s *timesheetService) GetDailyRange(userID uint, startDate, endDate time.Time) ([]dto.DailyDeclarationDTO, error) {
 	return s.dailyDeclarationService.GetByDateRange(userID, startDate, endDate)
@@ -551,6 +604,15 @@ func (s *timesheetService) CreateOrUpdateWeeklyDeclaration(week string, userID u
 
 	// Vérifier si une déclaration existe déjà pour cette semaine et cet utilisateur
 	existingDeclaration, err := s.weeklyDeclarationService.GetByUserIDAndWeek(userID, week)
+	if err == nil && existingDeclaration != nil {
+		// Une déclaration soumise ou approuvée n'est plus modifiable (rejet ou déverrouillage préalable)
+		switch existingDeclaration.Status {
+		case models.WeeklyDeclarationStatusApproved:
+			return nil, utils.ErrDeclarationLocked
+		case models.WeeklyDeclarationStatusSubmitted:
+			return nil, utils.ErrDeclarationSubmitted
+		}
+	}
 	
 	var declaration *models.WeeklyDeclaration
 	totalTime := 0
@@ -630,19 +692,28 @@ func (s *timesheetService) CreateOrUpdateWeeklyDeclaration(week string, userID u
 // GetWeeklyTasks récupère les tâches d'une déclaration hebdomadaire
 func (s *timesheetService) GetWeeklyTasks(week string, userID uint) ([]dto.WeeklyTaskDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
 // GetWeeklySummary récupère le résumé d'une déclaration hebdomadaire
 func (s *timesheetService) GetWeeklySummary(week string, userID uint) (*dto.WeeklySummaryDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
 // GetWeeklyDailyBreakdown récupère la répartition quotidienne d'une déclaration hebdomadaire
 func (s *timesheetService) GetWeeklyDailyBreakdown(week string, userID uint) ([]dto.DailyBreakdownDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
+}
+
+// SubmitWeeklyDeclaration soumet la déclaration hebdomadaire de l'utilisateur à validation
+func (s *timesheetService) SubmitWeeklyDeclaration(week string, userID uint, comment string) (*dto.WeeklyDeclarationDTO, error) {
+	declaration, err := s.weeklyDeclarationService.GetByUserIDAndWeek(userID, week)
+	if err != nil {
+		return nil, err
+	}
+	return s.weeklyDeclarationService.Submit(declaration.ID, userID, comment)
 }
 
 // ValidateWeeklyDeclaration valide une déclaration hebdomadaire
@@ -661,9 +732,14 @@ func (s *timesheetService) GetWeeklyValidationStatus(week string, userID uint) (
 		return nil, err
 	}
 	return &dto.ValidationStatusDTO{
-		Validated:   declaration.Validated,
-		ValidatedBy: declaration.ValidatedBy,
-		ValidatedAt: declaration.ValidatedAt,
+		Status:        declaration.Status,
+		Validated:     declaration.Validated,
+		ValidatedBy:   declaration.ValidatedBy,
+		ValidatedAt:   declaration.ValidatedAt,
+		DeclaredTime:  declaration.TotalTime,
+		ExpectedTime:  declaration.ExpectedTime,
+		AbsenceDays:   declaration.AbsenceDays,
+		UnderDeclared: declaration.UnderDeclared,
 	}, nil
 }
 
@@ -672,7 +748,7 @@ func (s *timesheetService) GetWeeklyValidationStatus(week string, userID uint) (
 func (s *timesheetService) SetTicketEstimatedTime(ticketID uint, estimatedTime int, userID uint) error {
 	ticket, err := s.ticketRepo.FindByID(ticketID)
 	if err != nil {
-		return errors.New("ticket introuvable")
+		return utils.ErrTicketNotFound
 	}
 	ticket.EstimatedTime = &estimatedTime
 	if ticket.Status == "ouvert" {
@@ -685,7 +761,7 @@ func (s *timesheetService) SetTicketEstimatedTime(ticketID uint, estimatedTime i
 func (s *timesheetService) GetTicketEstimatedTime(ticketID uint) (*dto.EstimatedTimeDTO, error) {
 	ticket, err := s.ticketRepo.FindByID(ticketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 	
 	// Vérifier que le ticket a un temps estimé défini (0 est une valeur valide)
@@ -708,7 +784,7 @@ func (s *timesheetService) UpdateTicketEstimatedTime(ticketID uint, estimatedTim
 func (s *timesheetService) GetTicketTimeComparison(ticketID uint) (*dto.TimeComparisonDTO, error) {
 	ticket, err := s.ticketRepo.FindByID(ticketID)
 	if err != nil {
-		return nil, errors.New("ticket introuvable")
+		return nil, utils.ErrTicketNotFound
 	}
 	estimatedTime := 0
 	if ticket.EstimatedTime != nil {
@@ -731,13 +807,13 @@ func (s *timesheetService) GetTicketTimeComparison(ticketID uint) (*dto.TimeComp
 // GetProjectTimeBudget récupère le budget temps d'un projet
 func (s *timesheetService) GetProjectTimeBudget(projectID uint) (*dto.ProjectTimeBudgetDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
 // SetProjectTimeBudget définit le budget temps d'un projet
 func (s *timesheetService) SetProjectTimeBudget(projectID uint, budget dto.SetProjectTimeBudgetRequest, userID uint) error {
 	// TODO: Implémenter
-	return errors.New("non implémenté")
+	return utils.ErrNotImplemented
 }
 
 // GetBudgetAlerts récupère les alertes de budget
@@ -821,7 +897,7 @@ func (s *timesheetService) GetPendingValidationEntries(scope interface{}) ([]dto
 // GetValidationHistory récupère l'historique de validation
 func (s *timesheetService) GetValidationHistory() ([]dto.ValidationHistoryDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
 // GetDelayAlerts récupère les alertes de retard
@@ -856,19 +932,71 @@ func (s *timesheetService) GetBudgetAlertsForTimesheet() ([]dto.BudgetAlertDTO,
 // GetOverloadAlerts récupère les alertes de surcharge
 func (s *timesheetService) GetOverloadAlerts() ([]dto.OverloadAlertDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
-// GetUnderloadAlerts récupère les alertes de sous-charge
+// underloadAlertDays nombre de jours (jusqu'à la veille) examinés pour les alertes de sous-charge
+const underloadAlertDays = 7
+
+// GetUnderloadAlerts récupère les alertes de sous-charge : jours ouvrés des 7 derniers jours où un utilisateur
+// qui déclare son temps a déclaré moins que le temps attendu ; les jours d'absence approuvée ne sont pas signalés
 func (s *timesheetService) GetUnderloadAlerts() ([]dto.UnderloadAlertDTO, error) {
-	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	to := calendarDay(time.Now()).AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -(underloadAlertDays - 1))
+
+	declarations, err := s.dailyDeclarationService.GetAllByDateRange(from, to)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des déclarations journalières")
+	}
+	declaredTime := make(map[uint]map[string]int)
+	var userIDs []uint
+	for _, declaration := range declarations {
+		if declaredTime[declaration.UserID] == nil {
+			declaredTime[declaration.UserID] = make(map[string]int)
+			userIDs = append(userIDs, declaration.UserID)
+		}
+		declaredTime[declaration.UserID][calendarDay(declaration.Date).Format("2006-01-02")] += declaration.TotalTime
+	}
+	if len(userIDs) == 0 {
+		return []dto.UnderloadAlertDTO{}, nil
+	}
+
+	absences, err := s.absenceRepo.FindApproved(userIDs, from, to)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des absences")
+	}
+	absencesByUser := make(map[uint][]models.Absence)
+	for _, absence := range absences {
+		absencesByUser[absence.UserID] = append(absencesByUser[absence.UserID], absence)
+	}
+
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	alerts := []dto.UnderloadAlertDTO{}
+	for _, userID := range userIDs {
+		absent := absentDays(absencesByUser[userID], from, to)
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			expectedTime, _ := expectedWorkload(day, day, absent)
+			actualTime := declaredTime[userID][day.Format("2006-01-02")]
+			if actualTime >= expectedTime {
+				continue
+			}
+			alerts = append(alerts, dto.UnderloadAlertDTO{
+				UserID:     userID,
+				Date:       day,
+				ActualTime: actualTime,
+				MinTime:    expectedTime,
+				Message: fmt.Sprintf("Temps déclaré le %s : %dh%02d sur %dh%02d attendues",
+					day.Format("02/01/2006"), actualTime/60, actualTime%60, expectedTime/60, expectedTime%60),
+			})
+		}
+	}
+	return alerts, nil
 }
 
 // SendReminderAlerts envoie des rappels
 func (s *timesheetService) SendReminderAlerts(userIDs []uint) error {
 	// TODO: Implémenter
-	return errors.New("non implémenté")
+	return utils.ErrNotImplemented
 }
 
 // GetPendingJustificationAlerts récupère les alertes de justifications en attente
@@ -892,24 +1020,24 @@ func (s *timesheetService) GetPendingJustificationAlerts() ([]dto.PendingJustifi
 // GetTimesheetHistory récupère l'historique du timesheet
 func (s *timesheetService) GetTimesheetHistory(userID uint, startDate, endDate time.Time) ([]dto.TimesheetHistoryDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
 // GetTimesheetHistoryEntry récupère une entrée de l'historique
 func (s *timesheetService) GetTimesheetHistoryEntry(entryID uint) (*dto.TimesheetHistoryEntryDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
 // GetTimesheetAuditTrail récupère la piste d'audit du timesheet
 func (s *timesheetService) GetTimesheetAuditTrail(userID uint, startDate, endDate time.Time) ([]dto.AuditTrailDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
 // GetTimesheetModifications récupère les modifications du timesheet
 func (s *timesheetService) GetTimesheetModifications(userID uint, startDate, endDate time.Time) ([]dto.ModificationDTO, error) {
 	// TODO: Implémenter
-	return nil, errors.New("non implémenté")
+	return nil, utils.ErrNotImplemented
 }
 
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// maxRecentTouchedTickets nombre de tickets détaillés dans le résumé d'activité
+const maxRecentTouchedTickets = 20
+
+// UserActivityService interface pour le résumé d'activité des utilisateurs
+type UserActivityService interface {
+	GetActivity(queryScope *scope.QueryScope, userID uint, from, to time.Time) (*dto.UserActivityDTO, error)
+}
+
+// userActivityService implémente UserActivityService
+type userActivityService struct {
+	activityRepo repositories.UserActivityRepository
+	userRepo     repositories.UserRepository
+}
+
+// NewUserActivityService crée une nouvelle instance de UserActivityService
+func NewUserActivityService(activityRepo repositories.UserActivityRepository, userRepo repositories.UserRepository) UserActivityService {
+	return &userActivityService{
+		activityRepo: activityRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// GetActivity agrège l'activité d'un utilisateur du jour from au jour to inclus
+// Accessible pour soi-même, ses collaborateurs et les utilisateurs de son périmètre de visibilité
+func (s *userActivityService) GetActivity(queryScope *scope.QueryScope, userID uint, from, to time.Time) (*dto.UserActivityDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if userID != queryScope.UserID && !queryScope.IsManagerOf(userID) {
+		visible, err := s.activityRepo.IsUserVisible(queryScope, userID)
+		if err != nil {
+			return nil, errors.New("erreur lors de la récupération de l'activité")
+		}
+		if !visible {
+			// Même réponse qu'un utilisateur inexistant : ne pas révéler les comptes hors périmètre
+			return nil, utils.ErrUserNotFound
+		}
+	}
+
+	end := to.AddDate(0, 0, 1) // Borne exclusive : lendemain du dernier jour
+	// Bornes des journées dans le fuseau de l'utilisateur connecté, pour les colonnes horodatées
+	startAt, _ := timezone.DayBounds(from, queryScope.Loc())
+	_, endAt := timezone.DayBounds(to, queryScope.Loc())
+	activity := &dto.UserActivityDTO{
+		User:      orgChartUserToDTO(user),
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		LastLogin: user.LastLogin,
+	}
+
+	touched, err := s.activityRepo.FindTouchedTickets(userID, startAt, endAt)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'activité")
+	}
+	activity.Tickets.Touched = len(touched)
+	activity.Tickets.Recent = make([]dto.UserActivityTicketDTO, 0, min(len(touched), maxRecentTouchedTickets))
+	for i := range touched {
+		if i == maxRecentTouchedTickets {
+			break
+		}
+		t := touched[i]
+		activity.Tickets.Recent = append(activity.Tickets.Recent, dto.UserActivityTicketDTO{
+			ID:           t.TicketID,
+			Code:         t.Code,
+			Title:        t.Title,
+			Status:       t.Status,
+			Actions:      t.Actions,
+			LastActionAt: t.LastActionAt,
+		})
+	}
+
+	created, err := s.activityRepo.CountTicketsCreated(userID, startAt, endAt)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'activité")
+	}
+	resolved, err := s.activityRepo.CountTicketsResolved(userID, startAt, endAt)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'activité")
+	}
+	activity.Tickets.Created = int(created)
+	activity.Tickets.Resolved = int(resolved)
+
+	ticketComments, taskComments, err := s.activityRepo.CountComments(userID, startAt, endAt)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'activité")
+	}
+	activity.Comments = dto.UserActivityCommentsDTO{
+		Tickets:      int(ticketComments),
+		ProjectTasks: int(taskComments),
+		Total:        int(ticketComments + taskComments),
+	}
+
+	timeLogged, err := s.activityRepo.SumTimeLogged(userID, from, end)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'activité")
+	}
+	activity.Time = dto.UserActivityTimeDTO{
+		Entries:   timeLogged.Entries,
+		Total:     timeLogged.Total,
+		Validated: timeLogged.Validated,
+	}
+
+	tasks, err := s.activityRepo.CountTasksCompleted(userID, startAt, endAt)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'activité")
+	}
+	activity.TasksCompleted = int(tasks)
+
+	logins, err := s.activityRepo.CountLogins(userID, startAt, endAt)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'activité")
+	}
+	activity.Logins = int(logins)
+
+	return activity, nil
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// UserImportMaxRows nombre maximal de lignes (hors en-tête) par import
+const UserImportMaxRows = 2000
+
+// userImportDefaultRole rôle attribué aux lignes sans rôle
+const userImportDefaultRole = "USER"
+
+// userImportFields liste les champs importables et les en-têtes reconnus par défaut (comparaison insensible à la casse)
+var userImportFields = []struct {
+	Field    string
+	Required bool
+	Headers  []string
+}{
+	{"username", true, []string{"username", "login", "identifiant", "nom d'utilisateur"}},
+	{"email", true, []string{"email", "e-mail", "mail", "courriel"}},
+	{"first_name", false, []string{"first_name", "firstname", "prénom", "prenom"}},
+	{"last_name", false, []string{"last_name", "lastname", "nom"}},
+	{"phone", false, []string{"phone", "téléphone", "telephone", "tel"}},
+	{"role", false, []string{"role", "rôle"}},
+	{"department", false, []string{"department", "department_code", "département", "departement"}},
+	{"filiale", false, []string{"filiale", "filiale_code"}},
+}
+
+// UserImportService interface pour l'import en masse d'utilisateurs et l'activation des comptes invités
+type UserImportService interface {
+	Import(rows [][]string, opts dto.UserImportOptions, importerID uint, anyFiliale bool) (*dto.UserImportReportDTO, error)
+	AcceptInvitation(req dto.AcceptInvitationRequest) error
+}
+
+// userImportService implémente UserImportService
+type userImportService struct {
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	departmentRepo repositories.DepartmentRepository
+	filialeRepo    repositories.FilialeRepository
+	invitationRepo repositories.UserInvitationRepository
+}
+
+// NewUserImportService crée une nouvelle instance de UserImportService
+func NewUserImportService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, departmentRepo repositories.DepartmentRepository, filialeRepo repositories.FilialeRepository, invitationRepo repositories.UserInvitationRepository) UserImportService {
+	return &userImportService{
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		departmentRepo: departmentRepo,
+		filialeRepo:    filialeRepo,
+		invitationRepo: invitationRepo,
+	}
+}
+
+// userImportLookups met en cache les références résolues par code pendant un import
+type userImportLookups struct {
+	roles       map[string]*models.Role
+	departments map[string]*models.Department
+	filiales    map[string]*models.Filiale
+}
+
+// Import valide chaque ligne du fichier (en-tête en première ligne) et crée les comptes valides
+// En simulation (dry_run), aucun compte n'est créé ; le rapport indique le résultat ligne par ligne
+// Sans anyFiliale, les comptes sont limités à la filiale de l'importateur
+func (s *userImportService) Import(rows [][]string, opts dto.UserImportOptions, importerID uint, anyFiliale bool) (*dto.UserImportReportDTO, error) {
+	if len(rows) < 2 {
+		return nil, errors.New("le fichier ne contient aucune ligne à importer")
+	}
+	if len(rows)-1 > UserImportMaxRows {
+		return nil, fmt.Errorf("le fichier dépasse %d lignes", UserImportMaxRows)
+	}
+
+	passwordMode := opts.PasswordMode
+	if passwordMode == "" {
+		passwordMode = dto.UserImportPasswordGenerate
+	}
+	if passwordMode != dto.UserImportPasswordGenerate && passwordMode != dto.UserImportPasswordInvite {
+		return nil, errors.New("mode de mot de passe invalide (generate ou invite)")
+	}
+
+	columns, headers, err := resolveUserImportColumns(rows[0], opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	importer, err := s.userRepo.FindByID(importerID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	report := &dto.UserImportReportDTO{
+		DryRun:       opts.DryRun,
+		PasswordMode: passwordMode,
+		Columns:      headers,
+		Total:        len(rows) - 1,
+		Rows:         make([]dto.UserImportRowDTO, 0, len(rows)-1),
+	}
+	lookups := &userImportLookups{
+		roles:       map[string]*models.Role{},
+		departments: map[string]*models.Department{},
+		filiales:    map[string]*models.Filiale{},
+	}
+	seenUsernames := map[string]int{}
+	seenEmails := map[string]int{}
+
+	for i, row := range rows[1:] {
+		cell := func(field string) string {
+			idx, ok := columns[field]
+			if !ok || idx >= len(row) {
+				return ""
+			}
+			return row[idx]
+		}
+
+		line := i + 2
+		result := dto.UserImportRowDTO{Row: line, Username: cell("username"), Email: strings.ToLower(cell("email"))}
+		user, errs := s.buildImportedUser(cell, result.Username, result.Email, importer, anyFiliale, lookups)
+
+		// Doublons à l'intérieur du fichier
+		if result.Username != "" {
+			if first, ok := seenUsernames[strings.ToLower(result.Username)]; ok {
+				errs = append(errs, fmt.Sprintf("nom d'utilisateur en double (ligne %d)", first))
+			} else {
+				seenUsernames[strings.ToLower(result.Username)] = line
+			}
+		}
+		if result.Email != "" {
+			if first, ok := seenEmails[result.Email]; ok {
+				errs = append(errs, fmt.Sprintf("email en double (ligne %d)", first))
+			} else {
+				seenEmails[result.Email] = line
+			}
+		}
+
+		switch {
+		case len(errs) > 0:
+			result.Status = dto.UserImportRowError
+			result.Errors = errs
+		case opts.DryRun:
+			result.Status = dto.UserImportRowValid
+		default:
+			if err := s.createImportedUser(user, passwordMode, importerID, &result); err != nil {
+				result.Status = dto.UserImportRowError
+				result.Errors = []string{err.Error()}
+			} else {
+				result.Status = dto.UserImportRowCreated
+				report.Created++
+			}
+		}
+
+		if result.Status == dto.UserImportRowError {
+			report.Failed++
+		} else {
+			report.Valid++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// buildImportedUser valide une ligne et construit l'utilisateur correspondant (non enregistré)
+func (s *userImportService) buildImportedUser(cell func(string) string, username, email string, importer *models.User, anyFiliale bool, lookups *userImportLookups) (*models.User, []string) {
+	var errs []string
+	user := &models.User{
+		Username:  username,
+		Email:     email,
+		FirstName: cell("first_name"),
+		LastName:  cell("last_name"),
+		Phone:     cell("phone"),
+		IsActive:  true,
+	}
+
+	if username == "" {
+		errs = append(errs, "nom d'utilisateur manquant")
+	} else if existing, _ := s.userRepo.FindByUsername(username); existing != nil {
+		errs = append(errs, "ce nom d'utilisateur est déjà utilisé")
+	}
+	if email == "" {
+		errs = append(errs, "email manquant")
+	} else if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		errs = append(errs, "email invalide")
+	} else if existing, _ := s.userRepo.FindByEmail(email); existing != nil {
+		errs = append(errs, "cet email est déjà utilisé")
+	}
+
+	// Rôle (par son nom, ex: TECHNICIEN_IT), USER par défaut
+	roleName := strings.ToUpper(cell("role"))
+	if roleName == "" {
+		roleName = userImportDefaultRole
+	}
+	if role := s.lookupRole(roleName, lookups); role != nil {
+		user.RoleID = role.ID
+	} else {
+		errs = append(errs, fmt.Sprintf("rôle introuvable: %s", roleName))
+	}
+
+	// Filiale (par code), celle de l'importateur par défaut
+	user.FilialeID = importer.FilialeID
+	if code := cell("filiale"); code != "" {
+		if filiale := s.lookupFiliale(code, lookups); filiale != nil {
+			user.FilialeID = &filiale.ID
+		} else {
+			errs = append(errs, fmt.Sprintf("filiale introuvable: %s", code))
+		}
+	}
+	if !anyFiliale && importer.FilialeID != nil && (user.FilialeID == nil || *user.FilialeID != *importer.FilialeID) {
+		errs = append(errs, "vous ne pouvez importer des utilisateurs que dans votre propre filiale")
+	}
+
+	// Département (par code), rattaché à la filiale de l'utilisateur
+	if code := cell("department"); code != "" {
+		if dept := s.lookupDepartment(code, lookups); dept == nil {
+			errs = append(errs, fmt.Sprintf("département introuvable: %s", code))
+		} else if user.FilialeID != nil && dept.FilialeID != nil && *dept.FilialeID != *user.FilialeID {
+			errs = append(errs, fmt.Sprintf("le département %s n'appartient pas à la filiale de l'utilisateur", code))
+		} else {
+			user.DepartmentID = &dept.ID
+		}
+	}
+
+	return user, errs
+}
+
+// createImportedUser enregistre un utilisateur validé et prépare son mot de passe initial ou son invitation
+func (s *userImportService) createImportedUser(user *models.User, passwordMode string, importerID uint, result *dto.UserImportRowDTO) error {
+	// En mode invitation, le mot de passe aléatoire n'est jamais communiqué : le compte s'active via le lien
+	password, err := generateInitialPassword()
+	if err != nil {
+		return errors.New("erreur lors de la génération du mot de passe")
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return errors.New("erreur lors du hashage du mot de passe")
+	}
+	user.PasswordHash = passwordHash
+	user.CreatedByID = &importerID
+
+	if err := s.userRepo.Create(user); err != nil {
+		return fmt.Errorf("erreur lors de la création de l'utilisateur: %w", err)
+	}
+	result.UserID = &user.ID
+
+	if passwordMode == dto.UserImportPasswordGenerate {
+		result.InitialPassword = password
+		return nil
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return errors.New("erreur lors de la génération de l'invitation")
+	}
+	invitation := &models.UserInvitation{
+		UserID:      user.ID,
+		TokenHash:   utils.HashString(token),
+		ExpiresAt:   time.Now().Add(config.AppConfig.App.InvitationTTL),
+		CreatedByID: &importerID,
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return errors.New("utilisateur créé mais erreur lors de la création de l'invitation")
+	}
+	result.InvitationURL = invitationURL(token)
+	return nil
+}
+
+// AcceptInvitation active un compte invité en définissant son mot de passe (lien à usage unique)
+func (s *userImportService) AcceptInvitation(req dto.AcceptInvitationRequest) error {
+	invitation, err := s.invitationRepo.FindByTokenHash(utils.HashString(req.Token))
+	if err != nil {
+		return errors.New("invitation invalide")
+	}
+	if invitation.AcceptedAt != nil {
+		return errors.New("invitation déjà utilisée")
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return errors.New("invitation expirée")
+	}
+	if !invitation.User.IsActive {
+		return errors.New("compte désactivé")
+	}
+
+	passwordHash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return errors.New("erreur lors du hashage du mot de passe")
+	}
+	if err := s.userRepo.UpdatePassword(invitation.UserID, passwordHash); err != nil {
+		return errors.New("erreur lors de l'activation du compte")
+	}
+
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return errors.New("erreur lors de l'activation du compte")
+	}
+	return nil
+}
+
+// lookupRole retrouve un rôle par son nom (mis en cache pour l'import)
+func (s *userImportService) lookupRole(name string, lookups *userImportLookups) *models.Role {
+	if role, ok := lookups.roles[name]; ok {
+		return role
+	}
+	role, err := s.roleRepo.FindByName(name)
+	if err != nil {
+		role = nil
+	}
+	lookups.roles[name] = role
+	return role
+}
+
+// lookupDepartment retrouve un département par son code (mis en cache pour l'import)
+func (s *userImportService) lookupDepartment(code string, lookups *userImportLookups) *models.Department {
+	if dept, ok := lookups.departments[code]; ok {
+		return dept
+	}
+	dept, err := s.departmentRepo.FindByCode(code)
+	if err != nil {
+		dept = nil
+	}
+	lookups.departments[code] = dept
+	return dept
+}
+
+// lookupFiliale retrouve une filiale par son code (mise en cache pour l'import)
+func (s *userImportService) lookupFiliale(code string, lookups *userImportLookups) *models.Filiale {
+	if filiale, ok := lookups.filiales[code]; ok {
+		return filiale
+	}
+	filiale, err := s.filialeRepo.FindByCode(code)
+	if err != nil {
+		filiale = nil
+	}
+	lookups.filiales[code] = filiale
+	return filiale
+}
+
+// resolveUserImportColumns associe chaque champ à l'index de sa colonne
+// La correspondance explicite (champ -> en-tête) prime sur les en-têtes reconnus par défaut
+func resolveUserImportColumns(header []string, mapping map[string]string) (map[string]int, map[string]string, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(h))
+		if _, ok := index[key]; !ok && key != "" {
+			index[key] = i
+		}
+	}
+
+	known := make(map[string]bool, len(userImportFields))
+	for _, f := range userImportFields {
+		known[f.Field] = true
+	}
+	for field := range mapping {
+		if !known[field] {
+			return nil, nil, fmt.Errorf("champ inconnu dans la correspondance: %s", field)
+		}
+	}
+
+	columns := map[string]int{}
+	headers := map[string]string{}
+	for _, f := range userImportFields {
+		if h, ok := mapping[f.Field]; ok {
+			idx, found := index[strings.ToLower(strings.TrimSpace(h))]
+			if !found {
+				return nil, nil, fmt.Errorf("colonne \"%s\" introuvable pour le champ %s", h, f.Field)
+			}
+			columns[f.Field], headers[f.Field] = idx, header[idx]
+			continue
+		}
+		for _, candidate := range f.Headers {
+			if idx, found := index[candidate]; found {
+				columns[f.Field], headers[f.Field] = idx, header[idx]
+				break
+			}
+		}
+		if _, ok := columns[f.Field]; !ok && f.Required {
+			return nil, nil, fmt.Errorf("colonne obligatoire manquante: %s", f.Field)
+		}
+	}
+	return columns, headers, nil
+}
+
+// generateInitialPassword génère un mot de passe initial aléatoire de 12 caractères (sans caractères ambigus)
+func generateInitialPassword() (string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	b := make([]byte, 12)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// generateInvitationToken génère le token aléatoire d'un lien d'invitation
+func generateInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// invitationURL construit le lien d'activation d'un compte invité
+func invitationURL(token string) string {
+	base := config.AppConfig.App.InvitationURL
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + "token=" + url.QueryEscape(token)
+}
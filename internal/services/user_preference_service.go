@@ -0,0 +1,152 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// DefaultDateFormat format de date utilisé sans préférence
+const DefaultDateFormat = "DD/MM/YYYY"
+
+// DefaultDashboardOwn tableau de bord personnel (« Mon tableau de bord »)
+const DefaultDashboardOwn = "own"
+
+// UserPreferenceService interface pour les opérations sur les préférences utilisateur
+type UserPreferenceService interface {
+	Get(userID uint) (*dto.UserPreferencesDTO, error)
+	Update(userID uint, req dto.UpdateUserPreferencesRequest) (*dto.UserPreferencesDTO, error)
+}
+
+// userPreferenceService implémente UserPreferenceService
+type userPreferenceService struct {
+	preferenceRepo repositories.UserPreferenceRepository
+}
+
+// NewUserPreferenceService crée une nouvelle instance de UserPreferenceService
+func NewUserPreferenceService(preferenceRepo repositories.UserPreferenceRepository) UserPreferenceService {
+	return &userPreferenceService{
+		preferenceRepo: preferenceRepo,
+	}
+}
+
+// Get récupère les préférences d'un utilisateur (valeurs par défaut si aucune n'est enregistrée)
+func (s *userPreferenceService) Get(userID uint) (*dto.UserPreferencesDTO, error) {
+	preference, err := s.preferenceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des préférences")
+	}
+	if preference == nil {
+		preference = defaultUserPreference(userID)
+	}
+	preferenceDTO := userPreferenceToDTO(preference)
+	return &preferenceDTO, nil
+}
+
+// Update met à jour les préférences d'un utilisateur (seuls les champs fournis sont modifiés)
+func (s *userPreferenceService) Update(userID uint, req dto.UpdateUserPreferencesRequest) (*dto.UserPreferencesDTO, error) {
+	preference, err := s.preferenceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des préférences")
+	}
+	if preference == nil {
+		preference = defaultUserPreference(userID)
+	}
+
+	if req.Language != nil {
+		if *req.Language != "" && *req.Language != utils.LangFR && *req.Language != utils.LangEN {
+			return nil, errors.New("langue non supportée (fr ou en)")
+		}
+		preference.Language = *req.Language
+	}
+	if req.Timezone != nil {
+		if *req.Timezone != "" {
+			if _, err := time.LoadLocation(*req.Timezone); err != nil {
+				return nil, errors.New("fuseau horaire inconnu (format IANA attendu, ex: Africa/Abidjan)")
+			}
+		}
+		preference.Timezone = *req.Timezone
+	}
+	if req.DateFormat != nil {
+		preference.DateFormat = *req.DateFormat
+	}
+	if req.DefaultDashboard != nil {
+		switch *req.DefaultDashboard {
+		case "", DefaultDashboardOwn, scope.DashboardScopeDepartment, scope.DashboardScopeFiliale, scope.DashboardScopeGlobal:
+			preference.DefaultDashboard = *req.DefaultDashboard
+		default:
+			return nil, errors.New("tableau de bord par défaut invalide (own, department, filiale ou global)")
+		}
+	}
+	if req.EmailNotifications != nil {
+		preference.EmailNotifications = *req.EmailNotifications
+	}
+	if req.DigestFrequency != nil {
+		preference.DigestFrequency = *req.DigestFrequency
+	}
+	if req.MutedNotificationTypes != nil {
+		muted := slices.Compact(slices.Sorted(slices.Values(req.MutedNotificationTypes)))
+		mutedJSON, err := json.Marshal(muted)
+		if err != nil {
+			return nil, errors.New("erreur lors de la sérialisation des préférences")
+		}
+		preference.MutedNotificationTypes = mutedJSON
+	}
+
+	if err := s.preferenceRepo.Save(preference); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement des préférences")
+	}
+
+	preferenceDTO := userPreferenceToDTO(preference)
+	return &preferenceDTO, nil
+}
+
+// defaultUserPreference retourne les préférences par défaut d'un utilisateur
+func defaultUserPreference(userID uint) *models.UserPreference {
+	return &models.UserPreference{
+		UserID:             userID,
+		DateFormat:         DefaultDateFormat,
+		EmailNotifications: true,
+		DigestFrequency:    models.DigestFrequencyNone,
+	}
+}
+
+// mutedNotificationTypes décode la liste des types de notifications désactivés
+func mutedNotificationTypes(preference *models.UserPreference) []string {
+	muted := []string{}
+	if len(preference.MutedNotificationTypes) > 0 {
+		_ = json.Unmarshal(preference.MutedNotificationTypes, &muted)
+	}
+	return muted
+}
+
+// userPreferenceToDTO convertit un modèle UserPreference en DTO
+func userPreferenceToDTO(preference *models.UserPreference) dto.UserPreferencesDTO {
+	preferenceDTO := dto.UserPreferencesDTO{
+		Language:               preference.Language,
+		Timezone:               preference.Timezone,
+		DateFormat:             preference.DateFormat,
+		DefaultDashboard:       preference.DefaultDashboard,
+		EmailNotifications:     preference.EmailNotifications,
+		DigestFrequency:        preference.DigestFrequency,
+		MutedNotificationTypes: mutedNotificationTypes(preference),
+	}
+	if preferenceDTO.DateFormat == "" {
+		preferenceDTO.DateFormat = DefaultDateFormat
+	}
+	if preferenceDTO.DigestFrequency == "" {
+		preferenceDTO.DigestFrequency = models.DigestFrequencyNone
+	}
+	if preference.ID != 0 {
+		updatedAt := preference.UpdatedAt
+		preferenceDTO.UpdatedAt = &updatedAt
+	}
+	return preferenceDTO
+}
@@ -1,19 +1,40 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
+	"log/slog"
+	"path"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/database"
 	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/imaging"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/scope"
+	"github.com/mcicare/itsm-backend/internal/storage"
 	"github.com/mcicare/itsm-backend/internal/utils"
+	"gorm.io/gorm"
 )
 
+// Dimensions standard des avatars (carrés, recadrés au centre)
+const (
+	AvatarSize          = 256
+	AvatarThumbnailSize = 100
+)
+
+// avatarKeyPattern valide les clés d'avatar servies publiquement (user_<id>_<version>[_thumb].<ext>)
+var avatarKeyPattern = regexp.MustCompile(`^user_[0-9]+_[0-9a-f]+(_thumb)?\.(jpg|jpeg|png|gif|webp)$`)
+
 // UserService interface pour les opérations sur les utilisateurs
 type UserService interface {
 	Create(req dto.CreateUserRequest, createdByID uint) (*dto.UserDTO, error)
@@ -27,11 +48,17 @@ type UserService interface {
 	ResetPassword(userID uint, newPassword string) error
 	Activate(id uint) error
 	Deactivate(id uint) error
+	Offboard(id uint, req dto.OffboardUserRequest, offboardedByID uint) (*dto.UserOffboardingReportDTO, error)
+	SetOutOfOffice(userID uint, req dto.SetOutOfOfficeRequest) (*dto.UserDTO, error)
+	ClearOutOfOffice(userID uint) (*dto.UserDTO, error)
 	GetPermissions(userID uint) (*dto.UserPermissionsDTO, error)
 	UpdatePermissions(userID uint, req dto.UpdateUserPermissionsRequest, updatedByID uint) (*dto.UserPermissionsDTO, error)
-	UploadAvatar(userID uint, filePath string, updatedByID uint) (*dto.UserDTO, error)
-	GetAvatarPath(userID uint) (string, error)
-	GetAvatarThumbnailPath(userID uint) (string, error)
+	UploadAvatar(userID uint, content io.Reader, updatedByID uint) (*dto.UserDTO, error)
+	OpenAvatar(userID uint) (*storage.Object, error)
+	OpenAvatarThumbnail(userID uint) (*storage.Object, error)
+	OpenAvatarFile(key string) (*storage.Object, error)
+	GetOrgChart(userID uint, depth int) (*dto.OrgChartDTO, error)
+	GetTeam(managerID uint, directOnly bool) ([]dto.UserDTO, error)
 	DeleteAvatar(userID uint, updatedByID uint) (*dto.UserDTO, error)
 }
 
@@ -41,15 +68,17 @@ type userService struct {
 	roleRepo       repositories.RoleRepository
 	departmentRepo repositories.DepartmentRepository
 	ticketRepo     repositories.TicketRepository
+	avatarStorage  storage.Storage
 }
 
 // NewUserService crée une nouvelle instance de UserService
-func NewUserService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, departmentRepo repositories.DepartmentRepository, ticketRepo repositories.TicketRepository) UserService {
+func NewUserService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, departmentRepo repositories.DepartmentRepository, ticketRepo repositories.TicketRepository, avatarStorage storage.Storage) UserService {
 	return &userService{
 		userRepo:       userRepo,
 		roleRepo:       roleRepo,
 		departmentRepo: departmentRepo,
 		ticketRepo:     ticketRepo,
+		avatarStorage:  avatarStorage,
 	}
 }
 
@@ -67,7 +96,7 @@ func (s *userService) Create(req dto.CreateUserRequest, createdByID uint) (*dto.
 	}
 	_, err = s.roleRepo.FindByID(req.RoleID)
 	if err != nil {
-		return nil, errors.New("rôle introuvable")
+		return nil, utils.ErrRoleNotFound
 	}
 
 	// Vérifier que le département existe si fourni
@@ -75,7 +104,7 @@ func (s *userService) Create(req dto.CreateUserRequest, createdByID uint) (*dto.
 	if req.DepartmentID != nil && *req.DepartmentID != 0 {
 		dept, err := s.departmentRepo.FindByID(*req.DepartmentID)
 		if err != nil {
-			return nil, errors.New("département introuvable")
+			return nil, utils.ErrDepartmentNotFound
 		}
 
 		// Déterminer la filiale cible pour la validation
@@ -123,6 +152,15 @@ func (s *userService) Create(req dto.CreateUserRequest, createdByID uint) (*dto.
 		return nil, errors.New("cet email est déjà utilisé")
 	}
 
+	// Vérifier le responsable hiérarchique si fourni
+	if req.ManagerID != nil && *req.ManagerID != 0 {
+		if err := s.validateManager(0, *req.ManagerID); err != nil {
+			return nil, err
+		}
+	} else {
+		req.ManagerID = nil
+	}
+
 	// Hasher le mot de passe
 	passwordHash, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -139,6 +177,7 @@ func (s *userService) Create(req dto.CreateUserRequest, createdByID uint) (*dto.
 		Phone:        req.Phone,
 		DepartmentID: req.DepartmentID,
 		FilialeID:    req.FilialeID,
+		ManagerID:    req.ManagerID,
 		RoleID:       req.RoleID,
 		IsActive:     true, // Par défaut actif
 		CreatedByID:  &createdByID,
@@ -164,7 +203,7 @@ func (s *userService) Create(req dto.CreateUserRequest, createdByID uint) (*dto.
 func (s *userService) GetByID(id uint) (*dto.UserDTO, error) {
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	userDTO := s.userToDTO(user)
@@ -225,11 +264,10 @@ func (s *userService) Update(id uint, req dto.UpdateUserRequest, updatedByID uin
 	// Récupérer l'utilisateur existant
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
-	// Log pour déboguer
-	fmt.Printf("Update user %d - RoleID reçu: %d, Rôle actuel: %d\n", id, req.RoleID, user.RoleID)
+	slog.Debug("user_update", "user_id", id, "requested_role_id", req.RoleID, "current_role_id", user.RoleID)
 
 	// Récupérer l'utilisateur qui effectue la mise à jour pour vérifier ses permissions
 	updater, err := s.userRepo.FindByID(updatedByID)
@@ -241,12 +279,10 @@ func (s *userService) Update(id uint, req dto.UpdateUserRequest, updatedByID uin
 	if req.RoleID != 0 {
 		_, err := s.roleRepo.FindByID(req.RoleID)
 		if err != nil {
-			return nil, errors.New("rôle introuvable")
+			return nil, utils.ErrRoleNotFound
 		}
-		fmt.Printf("Rôle trouvé, mise à jour du rôle de %d vers %d\n", user.RoleID, req.RoleID)
+		slog.Debug("user_update_role", "user_id", id, "from_role_id", user.RoleID, "to_role_id", req.RoleID)
 		user.RoleID = req.RoleID
-	} else {
-		fmt.Printf("RoleID est 0 ou non fourni, pas de mise à jour du rôle\n")
 	}
 
 	// Gérer la mise à jour du département
@@ -261,7 +297,7 @@ func (s *userService) Update(id uint, req dto.UpdateUserRequest, updatedByID uin
 			// DepartmentID pointe vers une valeur valide, vérifier qu'il existe
 			dept, err := s.departmentRepo.FindByID(*req.DepartmentID)
 			if err != nil {
-				return nil, errors.New("département introuvable")
+				return nil, utils.ErrDepartmentNotFound
 			}
 
 			// Déterminer la filiale cible (celle modifiée, celle de l'utilisateur modifié, ou celle du modificateur)
@@ -303,6 +339,18 @@ func (s *userService) Update(id uint, req dto.UpdateUserRequest, updatedByID uin
 		// on ne fait rien (pas de changement)
 	}
 
+	// Gérer le responsable hiérarchique : nil = inchangé, 0 = retirer le responsable
+	if req.ManagerID != nil {
+		if *req.ManagerID == 0 {
+			user.ManagerID = nil
+		} else {
+			if err := s.validateManager(id, *req.ManagerID); err != nil {
+				return nil, err
+			}
+			user.ManagerID = req.ManagerID
+		}
+	}
+
 	// Mettre à jour les champs fournis
 	// Username peut être modifié si fourni
 	if req.Username != "" {
@@ -367,9 +415,7 @@ func (s *userService) Update(id uint, req dto.UpdateUserRequest, updatedByID uin
 		return nil, errors.New("erreur lors de la récupération de l'utilisateur mis à jour")
 	}
 
-	// Log pour vérifier que le rôle a bien été mis à jour
-	fmt.Printf("Après mise à jour - User ID: %d, RoleID dans la DB: %d, Role Name: %s\n",
-		updatedUser.ID, updatedUser.RoleID, updatedUser.Role.Name)
+	slog.Debug("user_updated", "user_id", updatedUser.ID, "role_id", updatedUser.RoleID, "role", updatedUser.Role.Name)
 
 	// Mettre à jour le nom du demandeur dans tous les tickets créés par cet utilisateur
 	// si le nom ou prénom a changé
@@ -385,18 +431,18 @@ func (s *userService) Update(id uint, req dto.UpdateUserRequest, updatedByID uin
 		// 1. Mettre à jour les tickets où requester_id correspond à cet utilisateur
 		if err := s.ticketRepo.UpdateRequesterNameByRequesterID(id, newRequesterName); err != nil {
 			// Log l'erreur mais ne bloque pas la mise à jour de l'utilisateur
-			fmt.Printf("Erreur lors de la mise à jour du nom du demandeur dans les tickets (par requester_id): %v\n", err)
+			slog.Warn("ticket_requester_name_update_failed", "user_id", id, "by", "requester_id", "error", err)
 		}
 		// 2. Mettre à jour les tickets créés par cet utilisateur
 		if err := s.ticketRepo.UpdateRequesterNameByCreatedBy(id, newRequesterName); err != nil {
 			// Log l'erreur mais ne bloque pas la mise à jour de l'utilisateur
-			fmt.Printf("Erreur lors de la mise à jour du nom du demandeur dans les tickets (par created_by_id): %v\n", err)
+			slog.Warn("ticket_requester_name_update_failed", "user_id", id, "by", "created_by_id", "error", err)
 		}
 		// 3. Mettre à jour aussi les tickets où le requester_name correspond à l'ancien nom
 		// (au cas où le ticket a été créé par quelqu'un d'autre pour cet utilisateur)
 		if err := s.ticketRepo.UpdateRequesterNameByName(oldRequesterName, newRequesterName); err != nil {
 			// Log l'erreur mais ne bloque pas la mise à jour de l'utilisateur
-			fmt.Printf("Erreur lors de la mise à jour du nom du demandeur dans les tickets (par requester_name): %v\n", err)
+			slog.Warn("ticket_requester_name_update_failed", "user_id", id, "by", "requester_name", "error", err)
 		}
 	}
 
@@ -409,7 +455,7 @@ func (s *userService) Delete(id uint, deletedByID uint) error {
 	// Vérifier que l'utilisateur existe et récupérer ses informations
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
-		return errors.New("utilisateur introuvable")
+		return utils.ErrUserNotFound
 	}
 
 	// Empêcher la suppression du compte admin par défaut (point d'entrée de l'application)
@@ -451,7 +497,7 @@ func (s *userService) ChangePassword(userID uint, oldPassword, newPassword strin
 	// Récupérer l'utilisateur
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return errors.New("utilisateur introuvable")
+		return utils.ErrUserNotFound
 	}
 
 	// Vérifier l'ancien mot de passe
@@ -479,7 +525,7 @@ func (s *userService) ResetPassword(userID uint, newPassword string) error {
 	// Récupérer l'utilisateur
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return errors.New("utilisateur introuvable")
+		return utils.ErrUserNotFound
 	}
 
 	// Hasher le nouveau mot de passe
@@ -501,7 +547,7 @@ func (s *userService) ResetPassword(userID uint, newPassword string) error {
 func (s *userService) Activate(id uint) error {
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
-		return errors.New("utilisateur introuvable")
+		return utils.ErrUserNotFound
 	}
 
 	user.IsActive = true
@@ -516,7 +562,7 @@ func (s *userService) Activate(id uint) error {
 func (s *userService) Deactivate(id uint) error {
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
-		return errors.New("utilisateur introuvable")
+		return utils.ErrUserNotFound
 	}
 
 	user.IsActive = false
@@ -527,11 +573,274 @@ func (s *userService) Deactivate(id uint) error {
 	return nil
 }
 
+// SetOutOfOffice déclare l'absence d'un utilisateur ; pendant l'absence, ses validations en attente
+// sont accessibles à son suppléant et les assignations qui le visent sont signalées
+func (s *userService) SetOutOfOffice(userID uint, req dto.SetOutOfOfficeRequest) (*dto.UserDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if !req.Until.After(req.From) {
+		return nil, errors.New("la fin de l'absence doit être postérieure à son début")
+	}
+	if !req.Until.After(time.Now()) {
+		return nil, errors.New("la fin de l'absence est déjà passée")
+	}
+
+	if req.BackupUserID != nil {
+		if *req.BackupUserID == userID {
+			return nil, errors.New("le suppléant doit être un autre utilisateur")
+		}
+		backup, err := s.userRepo.FindByID(*req.BackupUserID)
+		if err != nil {
+			return nil, errors.New("suppléant introuvable")
+		}
+		if !backup.IsActive {
+			return nil, errors.New("le suppléant doit être un utilisateur actif")
+		}
+		if backup.IsOutOfOffice(req.From) {
+			return nil, errors.New("le suppléant est lui-même absent au début de la période")
+		}
+	}
+
+	if err := s.userRepo.UpdateOutOfOffice(user.ID, &req.From, &req.Until, req.BackupUserID, strings.TrimSpace(req.Message)); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement de l'absence")
+	}
+	return s.GetByID(user.ID)
+}
+
+// ClearOutOfOffice met fin à l'absence déclarée d'un utilisateur
+func (s *userService) ClearOutOfOffice(userID uint) (*dto.UserDTO, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if err := s.userRepo.UpdateOutOfOffice(userID, nil, nil, nil, ""); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement de l'absence")
+	}
+	return s.GetByID(userID)
+}
+
+// outOfOfficeToDTO retourne l'absence en cours ou à venir d'un utilisateur (nil si aucune)
+func outOfOfficeToDTO(user *models.User) *dto.OutOfOfficeDTO {
+	now := time.Now()
+	if user.OutOfOfficeFrom == nil || user.OutOfOfficeUntil == nil || !user.OutOfOfficeUntil.After(now) {
+		return nil
+	}
+	return &dto.OutOfOfficeDTO{
+		From:         *user.OutOfOfficeFrom,
+		Until:        *user.OutOfOfficeUntil,
+		BackupUserID: user.BackupUserID,
+		Message:      user.OutOfOfficeMessage,
+		Active:       user.IsOutOfOffice(now),
+	}
+}
+
+// closedTicketStatuses statuts des tickets qui ne sont plus en cours de traitement
+var closedTicketStatuses = []string{"resolu", "cloture", "fusionne"}
+
+// Offboard désactive un utilisateur et transfère en une seule transaction son travail en cours au successeur :
+// tickets et tickets internes ouverts, tâches de projet ouvertes et collaborateurs (donc leurs validations en attente)
+// Sans successeur, le travail est désassigné et les collaborateurs n'ont plus de responsable
+func (s *userService) Offboard(id uint, req dto.OffboardUserRequest, offboardedByID uint) (*dto.UserOffboardingReportDTO, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if id == offboardedByID {
+		return nil, errors.New("vous ne pouvez pas organiser votre propre départ")
+	}
+	if !user.IsActive {
+		return nil, errors.New("l'utilisateur est déjà désactivé")
+	}
+
+	var successor *models.User
+	if req.SuccessorID != nil {
+		if *req.SuccessorID == id {
+			return nil, errors.New("le successeur doit être un autre utilisateur")
+		}
+		successor, err = s.userRepo.FindByID(*req.SuccessorID)
+		if err != nil {
+			return nil, errors.New("successeur introuvable")
+		}
+		if !successor.IsActive {
+			return nil, errors.New("le successeur doit être un utilisateur actif")
+		}
+	}
+
+	var successorID *uint
+	newValue := ""
+	if successor != nil {
+		successorID = &successor.ID
+		newValue = fmt.Sprintf("user#%d", successor.ID)
+	}
+	description := fmt.Sprintf("Départ de %s %s", user.FirstName, user.LastName)
+	if req.Reason != "" {
+		description += " : " + req.Reason
+	}
+
+	report := &dto.UserOffboardingReportDTO{
+		TicketIDs:         []uint{},
+		InternalTicketIDs: []uint{},
+		ProjectTaskIDs:    []uint{},
+		ReportIDs:         []uint{},
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 1. Tickets ouverts (assigné principal ou co-assigné)
+		if err := tx.Model(&models.Ticket{}).
+			Where("status NOT IN ?", closedTicketStatuses).
+			Where("assigned_to_id = ? OR id IN (?)", id,
+				tx.Model(&models.TicketAssignee{}).Select("ticket_id").Where("user_id = ?", id)).
+			Pluck("id", &report.TicketIDs).Error; err != nil {
+			return err
+		}
+		if len(report.TicketIDs) > 0 {
+			if err := tx.Model(&models.Ticket{}).
+				Where("id IN ? AND assigned_to_id = ?", report.TicketIDs, id).
+				Update("assigned_to_id", successorID).Error; err != nil {
+				return err
+			}
+			if err := reassignRows(tx, &models.TicketAssignee{}, "ticket_id", report.TicketIDs, id, successorID); err != nil {
+				return err
+			}
+			history := make([]models.TicketHistory, 0, len(report.TicketIDs))
+			for _, ticketID := range report.TicketIDs {
+				history = append(history, models.TicketHistory{
+					TicketID:    ticketID,
+					UserID:      offboardedByID,
+					Action:      "assigned",
+					FieldName:   "assigned_to",
+					OldValue:    fmt.Sprintf("user#%d", id),
+					NewValue:    newValue,
+					Description: description,
+				})
+			}
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. Tickets internes ouverts
+		if err := tx.Model(&models.TicketInternal{}).
+			Where("assigned_to_id = ? AND status NOT IN ?", id, closedTicketStatuses).
+			Pluck("id", &report.InternalTicketIDs).Error; err != nil {
+			return err
+		}
+		if len(report.InternalTicketIDs) > 0 {
+			if err := tx.Model(&models.TicketInternal{}).
+				Where("id IN ?", report.InternalTicketIDs).
+				Update("assigned_to_id", successorID).Error; err != nil {
+				return err
+			}
+		}
+
+		// 3. Tâches de projet ouvertes (assigné principal ou co-assigné)
+		if err := tx.Model(&models.ProjectTask{}).
+			Where("status <> ?", "cloture").
+			Where("assigned_to_id = ? OR id IN (?)", id,
+				tx.Model(&models.ProjectTaskAssignee{}).Select("project_task_id").Where("user_id = ?", id)).
+			Pluck("id", &report.ProjectTaskIDs).Error; err != nil {
+			return err
+		}
+		if len(report.ProjectTaskIDs) > 0 {
+			if err := tx.Model(&models.ProjectTask{}).
+				Where("id IN ? AND assigned_to_id = ?", report.ProjectTaskIDs, id).
+				Update("assigned_to_id", successorID).Error; err != nil {
+				return err
+			}
+			if err := reassignRows(tx, &models.ProjectTaskAssignee{}, "project_task_id", report.ProjectTaskIDs, id, successorID); err != nil {
+				return err
+			}
+		}
+
+		// 4. Collaborateurs directs : leurs validations en attente remontent désormais au successeur
+		if err := tx.Model(&models.User{}).Where("manager_id = ?", id).Pluck("id", &report.ReportIDs).Error; err != nil {
+			return err
+		}
+		if len(report.ReportIDs) > 0 {
+			if err := tx.Model(&models.TimeEntry{}).
+				Where("user_id IN ? AND validated = ?", report.ReportIDs, false).
+				Count(&report.PendingTimeEntries).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.DelayJustification{}).
+				Where("user_id IN ? AND status = ?", report.ReportIDs, "pending").
+				Count(&report.PendingJustifications).Error; err != nil {
+				return err
+			}
+			// Un successeur issu de l'équipe reprend le responsable de l'utilisateur (pas d'auto-rattachement)
+			if successorID != nil {
+				if err := tx.Model(&models.User{}).
+					Where("id = ? AND manager_id = ?", *successorID, id).
+					Update("manager_id", user.ManagerID).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Model(&models.User{}).
+				Where("manager_id = ?", id).
+				Update("manager_id", successorID).Error; err != nil {
+				return err
+			}
+		}
+
+		// 5. Désactivation du compte et révocation des sessions
+		if err := tx.Model(&models.User{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"is_active": false, "updated_by_id": offboardedByID}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("user_id = ?", id).Delete(&models.UserSession{})
+		if result.Error != nil {
+			return result.Error
+		}
+		report.SessionsRevoked = result.RowsAffected
+		return tx.Where("user_id = ?", id).Delete(&models.PushDevice{}).Error
+	})
+	if err != nil {
+		slog.Error("user_offboard_failed", "user_id", id, "error", err)
+		return nil, errors.New("erreur lors du départ de l'utilisateur")
+	}
+
+	cacheIDs := append([]uint{id}, report.ReportIDs...)
+	if successorID != nil {
+		cacheIDs = append(cacheIDs, *successorID)
+	}
+	s.userRepo.InvalidateCache(cacheIDs...)
+
+	offboarded, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'utilisateur")
+	}
+	report.User = s.userToDTO(offboarded)
+	if successor != nil {
+		successorDTO := s.userToDTO(successor)
+		report.Successor = &successorDTO
+	}
+	return report, nil
+}
+
+// reassignRows transfère les lignes d'assignation multiple (ticket_assignees, project_task_assignees) d'un utilisateur
+// Les lignes sont supprimées sans successeur, ou lorsque le successeur est déjà co-assigné (index unique)
+func reassignRows(tx *gorm.DB, model interface{}, parentColumn string, parentIDs []uint, fromID uint, toID *uint) error {
+	if toID != nil {
+		var alreadyAssigned []uint
+		if err := tx.Model(model).Where(parentColumn+" IN ? AND user_id = ?", parentIDs, *toID).
+			Pluck(parentColumn, &alreadyAssigned).Error; err != nil {
+			return err
+		}
+		if len(alreadyAssigned) > 0 {
+			if err := tx.Where(parentColumn+" IN ? AND user_id = ?", alreadyAssigned, fromID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(model).Where(parentColumn+" IN ? AND user_id = ?", parentIDs, fromID).Update("user_id", *toID).Error
+	}
+	return tx.Where(parentColumn+" IN ? AND user_id = ?", parentIDs, fromID).Delete(model).Error
+}
+
 // GetPermissions récupère les permissions d'un utilisateur
 func (s *userService) GetPermissions(userID uint) (*dto.UserPermissionsDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	userDTO := s.userToDTO(user)
@@ -553,7 +862,7 @@ func (s *userService) GetPermissions(userID uint) (*dto.UserPermissionsDTO, erro
 func (s *userService) UpdatePermissions(userID uint, req dto.UpdateUserPermissionsRequest, updatedByID uint) (*dto.UserPermissionsDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	// TODO: Implémenter la sauvegarde des permissions personnalisées
@@ -569,34 +878,66 @@ func (s *userService) UpdatePermissions(userID uint, req dto.UpdateUserPermissio
 	return permissionsDTO, nil
 }
 
-// UploadAvatar upload un avatar pour un utilisateur
-func (s *userService) UploadAvatar(userID uint, fileName string, updatedByID uint) (*dto.UserDTO, error) {
+// UploadAvatar valide et redimensionne une image puis l'enregistre comme avatar (et miniature) en remplaçant l'ancien
+// La clé du fichier dérive de son contenu : une URL d'avatar ne change jamais de contenu et peut être mise en cache
+func (s *userService) UploadAvatar(userID uint, content io.Reader, updatedByID uint) (*dto.UserDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
-	// Supprimer l'ancien avatar s'il existe
-	if user.Avatar != "" {
-		oldPath := filepath.Join(config.AppConfig.AvatarDir, user.Avatar)
-		if _, err := os.Stat(oldPath); err == nil {
-			os.Remove(oldPath)
-		}
-		// Supprimer aussi la miniature
-		thumbnailPath := strings.Replace(oldPath, filepath.Ext(oldPath), "_thumb"+filepath.Ext(oldPath), 1)
-		if _, err := os.Stat(thumbnailPath); err == nil {
-			os.Remove(thumbnailPath)
+	img, _, err := imaging.Decode(content)
+	if err != nil {
+		if errors.Is(err, imaging.ErrUnsupportedFormat) || errors.Is(err, imaging.ErrTooLarge) {
+			return nil, err
 		}
+		return nil, errors.New("erreur lors de la lecture du fichier")
 	}
 
-	// Mettre à jour l'avatar dans la base de données (on stocke juste le nom du fichier)
+	avatar := imaging.Square(img, AvatarSize)
+	thumbnail := imaging.Square(img, AvatarThumbnailSize)
+	opaque := avatar.Opaque()
+	avatarData, contentType, ext, err := imaging.Encode(avatar, opaque)
+	if err != nil {
+		return nil, errors.New("erreur lors du traitement de l'image")
+	}
+	thumbnailData, _, _, err := imaging.Encode(thumbnail, opaque)
+	if err != nil {
+		return nil, errors.New("erreur lors du traitement de l'image")
+	}
+
+	// Enregistrer l'avatar et sa miniature (on stocke juste le nom du fichier)
+	ctx := context.Background()
+	sum := sha256.Sum256(avatarData)
+	fileName := fmt.Sprintf("user_%d_%s%s", userID, hex.EncodeToString(sum[:8]), ext)
+	if err := s.avatarStorage.Put(ctx, fileName, bytes.NewReader(avatarData), int64(len(avatarData)), contentType); err != nil {
+		return nil, errors.New("erreur lors de la sauvegarde du fichier")
+	}
+	if err := s.avatarStorage.Put(ctx, avatarThumbnailKey(fileName), bytes.NewReader(thumbnailData), int64(len(thumbnailData)), contentType); err != nil {
+		s.deleteAvatarFiles(ctx, fileName)
+		return nil, errors.New("erreur lors de la sauvegarde du fichier")
+	}
+
+	if user.Avatar == fileName {
+		// Même image que l'avatar actuel : rien d'autre à faire
+		userDTO := s.userToDTO(user)
+		return &userDTO, nil
+	}
+
+	oldAvatar := user.Avatar
 	user.Avatar = fileName
 	user.UpdatedByID = &updatedByID
 
 	if err := s.userRepo.Update(user); err != nil {
+		s.deleteAvatarFiles(ctx, fileName)
 		return nil, errors.New("erreur lors de la mise à jour de l'avatar")
 	}
 
+	// Supprimer l'ancien avatar et sa miniature
+	if oldAvatar != "" {
+		s.deleteAvatarFiles(ctx, oldAvatar)
+	}
+
 	updatedUser, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return nil, errors.New("erreur lors de la récupération de l'utilisateur mis à jour")
@@ -606,75 +947,153 @@ func (s *userService) UploadAvatar(userID uint, fileName string, updatedByID uin
 	return &userDTO, nil
 }
 
-// GetAvatarPath récupère le chemin de l'avatar d'un utilisateur
-func (s *userService) GetAvatarPath(userID uint) (string, error) {
+// OpenAvatar ouvre l'avatar d'un utilisateur
+func (s *userService) OpenAvatar(userID uint) (*storage.Object, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return "", errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	if user.Avatar == "" {
-		return "", errors.New("aucun avatar trouvé")
+		return nil, errors.New("aucun avatar trouvé")
 	}
 
-	fullPath := filepath.Join(config.AppConfig.AvatarDir, user.Avatar)
-	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return "", errors.New("fichier avatar introuvable")
+	object, err := s.avatarStorage.Get(context.Background(), user.Avatar)
+	if err != nil {
+		return nil, errors.New("fichier avatar introuvable")
 	}
-
-	return fullPath, nil
+	return object, nil
 }
 
-// GetAvatarThumbnailPath récupère le chemin de la miniature de l'avatar
-func (s *userService) GetAvatarThumbnailPath(userID uint) (string, error) {
+// OpenAvatarThumbnail ouvre la miniature de l'avatar (ou l'avatar original à défaut de miniature)
+func (s *userService) OpenAvatarThumbnail(userID uint) (*storage.Object, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return "", errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	if user.Avatar == "" {
-		return "", errors.New("aucun avatar trouvé")
+		return nil, errors.New("aucun avatar trouvé")
 	}
 
-	// Générer le chemin de la miniature
-	avatarPath := filepath.Join(config.AppConfig.AvatarDir, user.Avatar)
-	ext := filepath.Ext(avatarPath)
-	thumbnailPath := strings.Replace(avatarPath, ext, "_thumb"+ext, 1)
+	if object, err := s.avatarStorage.Get(context.Background(), avatarThumbnailKey(user.Avatar)); err == nil {
+		return object, nil
+	}
+	return s.OpenAvatar(userID)
+}
+
+// GetOrgChart construit l'organigramme d'un utilisateur : sa chaîne hiérarchique et ses collaborateurs sur depth niveaux
+func (s *userService) GetOrgChart(userID uint, depth int) (*dto.OrgChartDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
 
-	if _, err := os.Stat(thumbnailPath); os.IsNotExist(err) {
-		// Si la miniature n'existe pas, retourner l'avatar original
-		if _, err := os.Stat(avatarPath); os.IsNotExist(err) {
-			return "", errors.New("fichier avatar introuvable")
+	chart := &dto.OrgChartDTO{Managers: []dto.UserDTO{}}
+
+	// Remonter la chaîne des responsables (bornée pour ne pas boucler sur un cycle en base)
+	visited := map[uint]bool{user.ID: true}
+	managerID := user.ManagerID
+	for managerID != nil && !visited[*managerID] && len(chart.Managers) < maxOrgChartDepth {
+		manager, err := s.userRepo.FindByID(*managerID)
+		if err != nil {
+			break
+		}
+		visited[manager.ID] = true
+		chart.Managers = append(chart.Managers, *orgChartUserToDTO(manager))
+		managerID = manager.ManagerID
+	}
+
+	// Descendre niveau par niveau (une requête par niveau)
+	if depth <= 0 {
+		depth = 1
+	}
+	if depth > maxOrgChartDepth {
+		depth = maxOrgChartDepth
+	}
+	children := map[uint][]*models.User{}
+	counts := map[uint]int{}
+	level := []uint{user.ID}
+	for d := 0; d <= depth && len(level) > 0; d++ {
+		reports, err := s.userRepo.FindByManagerIDs(level)
+		if err != nil {
+			return nil, errors.New("erreur lors de la récupération de l'organigramme")
+		}
+		level = nil
+		for i := range reports {
+			report := &reports[i]
+			counts[*report.ManagerID]++
+			if d == depth || visited[report.ID] {
+				continue // Compté mais non détaillé au-delà de la profondeur demandée
+			}
+			visited[report.ID] = true
+			children[*report.ManagerID] = append(children[*report.ManagerID], report)
+			level = append(level, report.ID)
 		}
-		return avatarPath, nil
 	}
 
-	return thumbnailPath, nil
+	var build func(u *models.User) dto.OrgChartNodeDTO
+	build = func(u *models.User) dto.OrgChartNodeDTO {
+		node := dto.OrgChartNodeDTO{User: *orgChartUserToDTO(u), ReportsCount: counts[u.ID], Reports: make([]dto.OrgChartNodeDTO, 0, len(children[u.ID]))}
+		for _, child := range children[u.ID] {
+			node.Reports = append(node.Reports, build(child))
+		}
+		return node
+	}
+	chart.Root = build(user)
+
+	return chart, nil
+}
+
+// GetTeam récupère les collaborateurs d'un responsable (directs seulement ou toute la hiérarchie)
+func (s *userService) GetTeam(managerID uint, directOnly bool) ([]dto.UserDTO, error) {
+	var users []models.User
+	var err error
+	if directOnly {
+		users, err = s.userRepo.FindByManagerIDs([]uint{managerID})
+	} else {
+		var ids []uint
+		ids, err = s.userRepo.FindTeamMemberIDs(managerID)
+		if err == nil {
+			users, err = s.userRepo.FindByIDs(ids)
+		}
+	}
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération de l'équipe")
+	}
+
+	team := make([]dto.UserDTO, 0, len(users))
+	for i := range users {
+		team = append(team, *orgChartUserToDTO(&users[i]))
+	}
+	return team, nil
+}
+
+// OpenAvatarFile ouvre un fichier d'avatar par sa clé (URL publique et cacheable des avatars)
+func (s *userService) OpenAvatarFile(key string) (*storage.Object, error) {
+	if !avatarKeyPattern.MatchString(key) {
+		return nil, errors.New("fichier avatar introuvable")
+	}
+	object, err := s.avatarStorage.Get(context.Background(), key)
+	if err != nil {
+		return nil, errors.New("fichier avatar introuvable")
+	}
+	return object, nil
 }
 
 // DeleteAvatar supprime l'avatar d'un utilisateur
 func (s *userService) DeleteAvatar(userID uint, updatedByID uint) (*dto.UserDTO, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("utilisateur introuvable")
+		return nil, utils.ErrUserNotFound
 	}
 
 	if user.Avatar == "" {
 		return nil, errors.New("aucun avatar à supprimer")
 	}
 
-	// Supprimer le fichier
-	avatarPath := filepath.Join(config.AppConfig.AvatarDir, user.Avatar)
-	if _, err := os.Stat(avatarPath); err == nil {
-		os.Remove(avatarPath)
-	}
-
-	// Supprimer la miniature
-	ext := filepath.Ext(avatarPath)
-	thumbnailPath := strings.Replace(avatarPath, ext, "_thumb"+ext, 1)
-	if _, err := os.Stat(thumbnailPath); err == nil {
-		os.Remove(thumbnailPath)
-	}
+	// Supprimer le fichier et sa miniature
+	s.deleteAvatarFiles(context.Background(), user.Avatar)
 
 	// Mettre à jour dans la base de données
 	user.Avatar = ""
@@ -704,6 +1123,7 @@ func (s *userService) userToDTO(user *models.User) dto.UserDTO {
 		Phone:        user.Phone,
 		DepartmentID: user.DepartmentID,
 		FilialeID:    user.FilialeID,
+		ManagerID:    user.ManagerID,
 		Avatar:       user.Avatar,
 		Role:         user.Role.Name,
 		Permissions:  s.getPermissionsForRole(user.Role.Name),
@@ -712,6 +1132,8 @@ func (s *userService) userToDTO(user *models.User) dto.UserDTO {
 		CreatedAt:    user.CreatedAt,
 		UpdatedAt:    user.UpdatedAt,
 	}
+	userDTO.AvatarURL, userDTO.AvatarThumbnailURL = avatarURLs(user.Avatar)
+	userDTO.OutOfOffice = outOfOfficeToDTO(user)
 
 	// Inclure la filiale si présente
 	if user.Filiale != nil {
@@ -765,27 +1187,81 @@ func (s *userService) userToDTO(user *models.User) dto.UserDTO {
 	return userDTO
 }
 
-// getPermissionsForRole retourne la liste des permissions associées à un rôle donné.
-// Les permissions sont récupérées depuis la base de données via la table role_permissions
+// getPermissionsForRole retourne la liste des permissions associées à un rôle donné (cache du package scope).
+// En cas d'indisponibilité, aucune permission n'est retournée plutôt qu'un ensemble par défaut
 func (s *userService) getPermissionsForRole(roleName string) []string {
-	// Récupérer le rôle par son nom
-	role, err := s.roleRepo.FindByName(roleName)
+	permissions, err := scope.PermissionsForRole(roleName)
+	if err != nil {
+		slog.Warn("role_permissions_unavailable", "role", roleName, "error", err)
+		return []string{}
+	}
+	return permissions
+}
+
+// deleteAvatarFiles supprime un avatar et sa miniature du stockage
+func (s *userService) deleteAvatarFiles(ctx context.Context, avatar string) {
+	_ = s.avatarStorage.Delete(ctx, avatar)
+	_ = s.avatarStorage.Delete(ctx, avatarThumbnailKey(avatar))
+}
+
+// maxOrgChartDepth profondeur maximale de l'organigramme (dans les deux sens)
+const maxOrgChartDepth = 10
+
+// validateManager vérifie qu'un responsable peut être assigné à l'utilisateur userID (0 pour un nouvel utilisateur)
+func (s *userService) validateManager(userID uint, managerID uint) error {
+	if managerID == userID {
+		return errors.New("un utilisateur ne peut pas être son propre responsable")
+	}
+	manager, err := s.userRepo.FindByID(managerID)
 	if err != nil {
-		// Retourner des permissions minimales par défaut si le rôle n'existe pas
-		return []string{"tickets.view_own"}
+		return errors.New("responsable introuvable")
+	}
+	if !manager.IsActive {
+		return errors.New("le responsable est désactivé")
+	}
+	if userID == 0 {
+		return nil
 	}
 
-	// Récupérer les permissions du rôle depuis la base de données
-	permissions, err := s.roleRepo.GetPermissionsByRoleID(role.ID)
+	// Le responsable ne doit pas faire partie de l'équipe de l'utilisateur (cycle)
+	team, err := s.userRepo.FindTeamMemberIDs(userID)
 	if err != nil {
-		// Retourner des permissions minimales par défaut en cas d'erreur
-		return []string{"tickets.view_own"}
+		return errors.New("erreur lors de la vérification de la hiérarchie")
 	}
+	for _, id := range team {
+		if id == managerID {
+			return errors.New("ce responsable fait partie de l'équipe de l'utilisateur (hiérarchie circulaire)")
+		}
+	}
+	return nil
+}
+
+// orgChartUserToDTO convertit un utilisateur en DTO allégé pour l'organigramme (sans permissions)
+func orgChartUserToDTO(user *models.User) *dto.UserDTO {
+	userDTO := shareUserToDTO(user)
+	userDTO.Phone = user.Phone
+	userDTO.FilialeID = user.FilialeID
+	userDTO.ManagerID = user.ManagerID
+	userDTO.OutOfOffice = outOfOfficeToDTO(user)
+	userDTO.Role = user.Role.Name
+	if user.Department != nil {
+		userDTO.Department = &dto.DepartmentDTO{ID: user.Department.ID, Name: user.Department.Name, Code: user.Department.Code}
+	}
+	userDTO.AvatarURL, userDTO.AvatarThumbnailURL = avatarURLs(user.Avatar)
+	return userDTO
+}
 
-	// Si aucune permission n'est assignée, retourner des permissions minimales
-	if len(permissions) == 0 {
-		return []string{"tickets.view_own"}
+// avatarURLs retourne les URL publiques d'un avatar et de sa miniature (vides sans avatar)
+func avatarURLs(avatar string) (string, string) {
+	if avatar == "" || config.AppConfig == nil {
+		return "", ""
 	}
+	base := strings.TrimRight(config.AppConfig.AppURL, "/") + "/api/v1/avatars/"
+	return base + avatar, base + avatarThumbnailKey(avatar)
+}
 
-	return permissions
+// avatarThumbnailKey retourne la clé de la miniature d'un avatar (user_1_123.png -> user_1_123_thumb.png)
+func avatarThumbnailKey(avatar string) string {
+	ext := path.Ext(avatar)
+	return strings.TrimSuffix(avatar, ext) + "_thumb" + ext
 }
@@ -0,0 +1,622 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/events"
+	"github.com/mcicare/itsm-backend/internal/logger"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
+)
+
+// WebhookEventPing est l'événement de test envoyé par POST /webhooks/:id/test
+const WebhookEventPing = "webhook.ping"
+
+// webhookEventTypes décrit les événements du bus proposés à l'abonnement
+var webhookEventTypes = []dto.WebhookEventTypeDTO{
+	{Type: events.TicketCreated, Description: "Un ticket a été créé"},
+	{Type: events.TicketUpdated, Description: "Un ticket a été modifié"},
+	{Type: events.TicketAssigned, Description: "Un ticket a été assigné"},
+	{Type: events.TicketStatusChanged, Description: "Le statut d'un ticket a changé"},
+	{Type: events.TicketClosed, Description: "Un ticket a été clôturé"},
+	{Type: events.TicketCommented, Description: "Un commentaire a été ajouté à un ticket"},
+	{Type: events.TicketMerged, Description: "Des tickets en double ont été fusionnés dans un ticket"},
+	{Type: events.SLAAtRisk, Description: "L'échéance SLA d'un ticket approche"},
+	{Type: events.SLAViolated, Description: "Le SLA d'un ticket a été violé"},
+	{Type: events.ProjectCreated, Description: "Un projet a été créé"},
+	{Type: events.ProjectUpdated, Description: "Un projet a été modifié"},
+	{Type: events.ProjectDeleted, Description: "Un projet a été supprimé"},
+	{Type: events.ProjectArchived, Description: "Un projet a été archivé"},
+	{Type: events.ProjectUnarchived, Description: "Un projet a été désarchivé"},
+	{Type: events.TaskCompleted, Description: "Une tâche de projet a été terminée"},
+}
+
+// Paramètres de livraison
+const (
+	webhookMaxAttempts     = 6
+	webhookBaseBackoff     = 30 * time.Second
+	webhookRequestTimeout  = 10 * time.Second
+	webhookQueueSize       = 500
+	webhookWorkers         = 4
+	webhookRetryInterval   = 30 * time.Second
+	webhookResponseMaxSize = 2048
+)
+
+// WebhookPayload est le corps JSON envoyé aux abonnés
+type WebhookPayload struct {
+	ID         string    `json:"id"`          // Identifiant unique de l'événement
+	Event      string    `json:"event"`       // Type d'événement
+	OccurredAt time.Time `json:"occurred_at"` // Date de l'événement
+	FilialeID  *uint     `json:"filiale_id,omitempty"`
+	Data       any       `json:"data"` // Ressource concernée (DTO)
+}
+
+// WebhookService interface pour la gestion des abonnements webhook et la diffusion des événements
+type WebhookService interface {
+	GetAll() ([]dto.WebhookSubscriptionDTO, error)
+	GetByID(id uint) (*dto.WebhookSubscriptionDTO, error)
+	Create(req dto.CreateWebhookSubscriptionRequest, createdByID uint) (*dto.WebhookSubscriptionDTO, error)
+	Update(id uint, req dto.UpdateWebhookSubscriptionRequest) (*dto.WebhookSubscriptionDTO, error)
+	Delete(id uint) error
+	GetEventTypes() []dto.WebhookEventTypeDTO
+	GetDeliveries(subscriptionID uint, status string, page, limit int) (*dto.WebhookDeliveryListResponse, error)
+	Redeliver(subscriptionID, deliveryID uint) (*dto.WebhookDeliveryDTO, error)
+	Ping(subscriptionID uint) (*dto.WebhookDeliveryDTO, error)
+
+	// HandleEvent enregistre l'événement du bus pour chaque abonnement concerné et planifie l'envoi asynchrone
+	HandleEvent(ctx context.Context, event events.Event) error
+	// Start démarre les workers d'envoi et la boucle de réessai
+	Start(ctx context.Context)
+}
+
+// webhookService implémente WebhookService
+type webhookService struct {
+	webhookRepo repositories.WebhookRepository
+	client      *http.Client
+	queue       chan uint
+	startOnce   sync.Once
+}
+
+// NewWebhookService crée une nouvelle instance de WebhookService
+func NewWebhookService(webhookRepo repositories.WebhookRepository) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: webhookRequestTimeout},
+		queue:       make(chan uint, webhookQueueSize),
+	}
+}
+
+// GetAll récupère tous les abonnements
+func (s *webhookService) GetAll() ([]dto.WebhookSubscriptionDTO, error) {
+	subscriptions, err := s.webhookRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des webhooks")
+	}
+
+	subscriptionDTOs := make([]dto.WebhookSubscriptionDTO, len(subscriptions))
+	for i := range subscriptions {
+		subscriptionDTOs[i] = s.subscriptionToDTO(&subscriptions[i])
+	}
+	return subscriptionDTOs, nil
+}
+
+// GetByID récupère un abonnement par son ID
+func (s *webhookService) GetByID(id uint) (*dto.WebhookSubscriptionDTO, error) {
+	subscription, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrWebhookNotFound
+	}
+	subscriptionDTO := s.subscriptionToDTO(subscription)
+	return &subscriptionDTO, nil
+}
+
+// Create crée un abonnement ; le secret est retourné une seule fois dans la réponse
+func (s *webhookService) Create(req dto.CreateWebhookSubscriptionRequest, createdByID uint) (*dto.WebhookSubscriptionDTO, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret = generateWebhookSecret()
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	subscription := &models.WebhookSubscription{
+		Name:        req.Name,
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		FilialeID:   req.FilialeID,
+		IsActive:    isActive,
+		CreatedByID: &createdByID,
+	}
+	if err := s.webhookRepo.Create(subscription); err != nil {
+		return nil, errors.New("erreur lors de la création du webhook")
+	}
+
+	created, err := s.webhookRepo.FindByID(subscription.ID)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération du webhook créé")
+	}
+	subscriptionDTO := s.subscriptionToDTO(created)
+	subscriptionDTO.Secret = secret
+	return &subscriptionDTO, nil
+}
+
+// Update met à jour un abonnement
+func (s *webhookService) Update(id uint, req dto.UpdateWebhookSubscriptionRequest) (*dto.WebhookSubscriptionDTO, error) {
+	subscription, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrWebhookNotFound
+	}
+
+	if req.Name != "" {
+		subscription.Name = req.Name
+	}
+	if req.URL != "" {
+		if err := validateWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+		subscription.URL = req.URL
+	}
+	if len(req.EventTypes) > 0 {
+		eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		subscription.EventTypes = eventTypes
+	}
+	if req.ClearFiliale {
+		subscription.FilialeID = nil
+	} else if req.FilialeID != nil {
+		subscription.FilialeID = req.FilialeID
+	}
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+	newSecret := ""
+	if req.RegenerateSecret {
+		newSecret = generateWebhookSecret()
+		subscription.Secret = newSecret
+	}
+	subscription.Filiale = nil
+
+	if err := s.webhookRepo.Update(subscription); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour du webhook")
+	}
+
+	updated, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération du webhook mis à jour")
+	}
+	subscriptionDTO := s.subscriptionToDTO(updated)
+	subscriptionDTO.Secret = newSecret
+	return &subscriptionDTO, nil
+}
+
+// Delete supprime un abonnement et son journal de livraison
+func (s *webhookService) Delete(id uint) error {
+	if _, err := s.webhookRepo.FindByID(id); err != nil {
+		return utils.ErrWebhookNotFound
+	}
+	if err := s.webhookRepo.Delete(id); err != nil {
+		return errors.New("erreur lors de la suppression du webhook")
+	}
+	return nil
+}
+
+// GetEventTypes retourne la liste des événements disponibles
+func (s *webhookService) GetEventTypes() []dto.WebhookEventTypeDTO {
+	return webhookEventTypes
+}
+
+// GetDeliveries récupère le journal de livraison d'un abonnement
+func (s *webhookService) GetDeliveries(subscriptionID uint, status string, page, limit int) (*dto.WebhookDeliveryListResponse, error) {
+	if _, err := s.webhookRepo.FindByID(subscriptionID); err != nil {
+		return nil, utils.ErrWebhookNotFound
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	deliveries, total, err := s.webhookRepo.FindDeliveriesBySubscription(subscriptionID, status, page, limit)
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération du journal de livraison")
+	}
+
+	deliveryDTOs := make([]dto.WebhookDeliveryDTO, len(deliveries))
+	for i := range deliveries {
+		deliveryDTOs[i] = deliveryToDTO(&deliveries[i])
+	}
+	return &dto.WebhookDeliveryListResponse{
+		Deliveries: deliveryDTOs,
+		Pagination: dto.PaginationDTO{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, limit),
+		},
+	}, nil
+}
+
+// Redeliver replanifie immédiatement une livraison (échouée ou non)
+func (s *webhookService) Redeliver(subscriptionID, deliveryID uint) (*dto.WebhookDeliveryDTO, error) {
+	delivery, err := s.webhookRepo.FindDeliveryByID(deliveryID)
+	if err != nil || delivery.SubscriptionID != subscriptionID {
+		return nil, errors.New("livraison introuvable")
+	}
+	if delivery.Status == "sending" {
+		return nil, errors.New("la livraison est en cours d'envoi")
+	}
+
+	now := time.Now()
+	delivery.Status = "pending"
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.LastError = ""
+	if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+		return nil, errors.New("erreur lors de la replanification de la livraison")
+	}
+	s.enqueue(delivery.ID)
+
+	deliveryDTO := deliveryToDTO(delivery)
+	return &deliveryDTO, nil
+}
+
+// Ping envoie un événement de test à l'abonnement (même s'il ne l'a pas souscrit)
+func (s *webhookService) Ping(subscriptionID uint) (*dto.WebhookDeliveryDTO, error) {
+	subscription, err := s.webhookRepo.FindByID(subscriptionID)
+	if err != nil {
+		return nil, utils.ErrWebhookNotFound
+	}
+
+	delivery, err := s.createDelivery(subscription, newWebhookEventID(), WebhookEventPing, time.Now(), subscription.FilialeID, map[string]any{
+		"subscription_id": subscription.ID,
+		"message":         "Test de livraison du webhook",
+	})
+	if err != nil {
+		return nil, errors.New("erreur lors de la création de la livraison de test")
+	}
+	s.enqueue(delivery.ID)
+
+	deliveryDTO := deliveryToDTO(delivery)
+	return &deliveryDTO, nil
+}
+
+// HandleEvent enregistre l'événement pour chaque abonnement actif concerné (abonné "*" du bus)
+// Les abonnements filtrés par filiale ne reçoivent que les événements de leur filiale
+func (s *webhookService) HandleEvent(ctx context.Context, event events.Event) error {
+	subscriptions, err := s.webhookRepo.FindActive()
+	if err != nil {
+		return fmt.Errorf("lecture des abonnements webhook: %w", err)
+	}
+
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		if !subscriptionMatches(subscription, event.Type, event.FilialeID) {
+			continue
+		}
+		delivery, err := s.createDelivery(subscription, event.ID, event.Type, event.OccurredAt, event.FilialeID, event.Data)
+		if err != nil {
+			logger.FromContext(ctx).Error("webhook_delivery_create_failed", "subscription_id", subscription.ID, "event", event.Type, "error", err)
+			continue
+		}
+		s.enqueue(delivery.ID)
+	}
+	return nil
+}
+
+// Start démarre les workers d'envoi et la boucle de réessai (une seule fois)
+func (s *webhookService) Start(ctx context.Context) {
+	s.startOnce.Do(func() {
+		for i := 0; i < webhookWorkers; i++ {
+			go s.worker(ctx)
+		}
+		go s.retryLoop(ctx)
+	})
+}
+
+// createDelivery enregistre la livraison avec le corps JSON figé au moment de l'événement
+func (s *webhookService) createDelivery(subscription *models.WebhookSubscription, eventID, eventType string, occurredAt time.Time, filialeID *uint, data any) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(WebhookPayload{
+		ID:         eventID,
+		Event:      eventType,
+		OccurredAt: occurredAt,
+		FilialeID:  filialeID,
+		Data:       data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        string(payload),
+		Status:         "pending",
+		NextAttemptAt:  &now,
+	}
+	if err := s.webhookRepo.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// enqueue place la livraison dans la file d'envoi ; si la file est pleine, la boucle de réessai la reprendra
+func (s *webhookService) enqueue(deliveryID uint) {
+	select {
+	case s.queue <- deliveryID:
+	default:
+		slog.Warn("webhook_queue_full", "delivery_id", deliveryID)
+	}
+}
+
+// worker traite les livraisons de la file
+func (s *webhookService) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case deliveryID := <-s.queue:
+			s.deliver(deliveryID)
+		}
+	}
+}
+
+// retryLoop reprend périodiquement les livraisons échues (réessais, file pleine, redémarrage du serveur)
+func (s *webhookService) retryLoop(ctx context.Context) {
+	ticker := time.NewTicker(webhookRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deliveries, err := s.webhookRepo.FindDueDeliveries(time.Now(), webhookQueueSize/2)
+			if err != nil {
+				slog.Error("webhook_retry_scan_failed", "error", err)
+				continue
+			}
+			for _, delivery := range deliveries {
+				s.enqueue(delivery.ID)
+			}
+		}
+	}
+}
+
+// deliver envoie une livraison et enregistre le résultat (succès, réessai avec backoff exponentiel ou échec définitif)
+func (s *webhookService) deliver(deliveryID uint) {
+	claimed, err := s.webhookRepo.ClaimDelivery(deliveryID)
+	if err != nil || !claimed {
+		return
+	}
+	delivery, err := s.webhookRepo.FindDeliveryByID(deliveryID)
+	if err != nil {
+		return
+	}
+	subscription, err := s.webhookRepo.FindByID(delivery.SubscriptionID)
+	if err != nil {
+		delivery.Status = "failed"
+		delivery.LastError = "abonnement introuvable"
+		_ = s.webhookRepo.UpdateDelivery(delivery)
+		return
+	}
+
+	delivery.Attempts++
+	statusCode, body, sendErr := s.send(subscription, delivery)
+	delivery.ResponseStatus = statusCode
+	delivery.ResponseBody = body
+
+	now := time.Now()
+	if sendErr == nil {
+		delivery.Status = "success"
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = sendErr.Error()
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = "failed"
+			delivery.NextAttemptAt = nil
+			slog.Warn("webhook_delivery_failed", "delivery_id", delivery.ID, "subscription_id", subscription.ID, "event", delivery.EventType, "attempts", delivery.Attempts, "error", sendErr)
+		} else {
+			next := now.Add(webhookBackoff(delivery.Attempts))
+			delivery.Status = "retrying"
+			delivery.NextAttemptAt = &next
+		}
+	}
+
+	if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+		slog.Error("webhook_delivery_update_failed", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send effectue la requête HTTP signée
+// Signature : X-Webhook-Signature = "sha256=" + HMAC-SHA256(secret, timestamp + "." + corps)
+func (s *webhookService) send(subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, string, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ITSM-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Event-ID", delivery.EventID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(subscription.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseMaxSize))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), fmt.Errorf("réponse HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// SignWebhookPayload calcule la signature HMAC-SHA256 (hexadécimal) d'un corps de webhook
+// Les destinataires recalculent cette valeur avec leur secret pour authentifier l'appel
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff retourne le délai avant la tentative suivante (30s, 2min, 8min, 32min, ~2h)
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 4
+	}
+	return delay
+}
+
+// subscriptionMatches vérifie si un abonnement doit recevoir l'événement
+func subscriptionMatches(subscription *models.WebhookSubscription, eventType string, filialeID *uint) bool {
+	if subscription.FilialeID != nil && (filialeID == nil || *filialeID != *subscription.FilialeID) {
+		return false
+	}
+	var eventTypes []string
+	if err := json.Unmarshal(subscription.EventTypes, &eventTypes); err != nil {
+		return false
+	}
+	for _, t := range eventTypes {
+		// Type exact, "*" ou joker par domaine ("ticket.*")
+		if events.Matches(t, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeWebhookEventTypes valide les types d'événements et les encode en JSON
+func normalizeWebhookEventTypes(eventTypes []string) ([]byte, error) {
+	known := make(map[string]bool, len(webhookEventTypes))
+	for _, t := range webhookEventTypes {
+		known[t.Type] = true
+	}
+
+	normalized := make([]string, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if t != "*" && !strings.HasSuffix(t, ".*") && !known[t] {
+			return nil, fmt.Errorf("type d'événement inconnu: %s", t)
+		}
+		normalized = append(normalized, t)
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("au moins un type d'événement est requis")
+	}
+	return json.Marshal(normalized)
+}
+
+// validateWebhookURL vérifie que l'URL de destination est une URL HTTP(S) absolue
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return errors.New("l'URL du webhook doit être une URL http(s) valide")
+	}
+	return nil
+}
+
+// generateWebhookSecret génère un secret aléatoire de 32 octets (hexadécimal)
+func generateWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newWebhookEventID génère un identifiant d'événement aléatoire
+func newWebhookEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
+
+// subscriptionToDTO convertit un modèle WebhookSubscription en DTO (sans le secret)
+func (s *webhookService) subscriptionToDTO(subscription *models.WebhookSubscription) dto.WebhookSubscriptionDTO {
+	var eventTypes []string
+	_ = json.Unmarshal(subscription.EventTypes, &eventTypes)
+
+	subscriptionDTO := dto.WebhookSubscriptionDTO{
+		ID:          subscription.ID,
+		Name:        subscription.Name,
+		URL:         subscription.URL,
+		EventTypes:  eventTypes,
+		FilialeID:   subscription.FilialeID,
+		IsActive:    subscription.IsActive,
+		CreatedByID: subscription.CreatedByID,
+		CreatedAt:   subscription.CreatedAt,
+		UpdatedAt:   subscription.UpdatedAt,
+	}
+	if subscription.Filiale != nil {
+		subscriptionDTO.Filiale = &dto.FilialeDTO{
+			ID:       subscription.Filiale.ID,
+			Code:     subscription.Filiale.Code,
+			Name:     subscription.Filiale.Name,
+			IsActive: subscription.Filiale.IsActive,
+		}
+	}
+	return subscriptionDTO
+}
+
+// deliveryToDTO convertit un modèle WebhookDelivery en DTO
+func deliveryToDTO(delivery *models.WebhookDelivery) dto.WebhookDeliveryDTO {
+	return dto.WebhookDeliveryDTO{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
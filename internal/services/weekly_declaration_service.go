@@ -2,11 +2,16 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/mcicare/itsm-backend/internal/dto"
 	"github.com/mcicare/itsm-backend/internal/models"
 	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/utils"
 )
 
 // WeeklyDeclarationService interface pour les opérations sur les déclarations hebdomadaires
@@ -16,24 +21,44 @@ type WeeklyDeclarationService interface {
 	GetByUserID(userID uint) ([]dto.WeeklyDeclarationDTO, error)
 	GetValidated() ([]dto.WeeklyDeclarationDTO, error)
 	GetPendingValidation() ([]dto.WeeklyDeclarationDTO, error)
-	Validate(id uint, validatedByID uint) (*dto.WeeklyDeclarationDTO, error)
+	Validate(id uint, validatedByID uint) (*dto.WeeklyDeclarationDTO, error) // Équivaut à Approve sans commentaire
+	Submit(id uint, submittedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error)
+	Approve(id uint, approvedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error)
+	Reject(id uint, rejectedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error)
+	Unlock(id uint, unlockedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error)
+	GetHistory(id uint) ([]dto.WeeklyDeclarationEventDTO, error)
 	Delete(id uint) error
 }
 
+// weeklyDeclarationTransitions liste les changements de statut autorisés : soumission, approbation (verrouillage
+// de la semaine) ou rejet, puis déverrouillage d'une déclaration approuvée (retour en brouillon)
+var weeklyDeclarationTransitions = map[string][]string{
+	models.WeeklyDeclarationStatusDraft:     {models.WeeklyDeclarationStatusSubmitted},
+	models.WeeklyDeclarationStatusSubmitted: {models.WeeklyDeclarationStatusApproved, models.WeeklyDeclarationStatusRejected},
+	models.WeeklyDeclarationStatusRejected:  {models.WeeklyDeclarationStatusSubmitted},
+	models.WeeklyDeclarationStatusApproved:  {models.WeeklyDeclarationStatusDraft},
+}
+
 // weeklyDeclarationService implémente WeeklyDeclarationService
 type weeklyDeclarationService struct {
-	declarationRepo repositories.WeeklyDeclarationRepository
-	userRepo        repositories.UserRepository
+	declarationRepo     repositories.WeeklyDeclarationRepository
+	userRepo            repositories.UserRepository
+	absenceRepo         repositories.AbsenceRepository
+	notificationService NotificationService
 }
 
 // NewWeeklyDeclarationService crée une nouvelle instance de WeeklyDeclarationService
 func NewWeeklyDeclarationService(
 	declarationRepo repositories.WeeklyDeclarationRepository,
 	userRepo repositories.UserRepository,
+	absenceRepo repositories.AbsenceRepository,
+	notificationService NotificationService,
 ) WeeklyDeclarationService {
 	return &weeklyDeclarationService{
-		declarationRepo: declarationRepo,
-		userRepo:        userRepo,
+		declarationRepo:     declarationRepo,
+		userRepo:            userRepo,
+		absenceRepo:         absenceRepo,
+		notificationService: notificationService,
 	}
 }
 
@@ -41,35 +66,38 @@ func NewWeeklyDeclarationService(
 func (s *weeklyDeclarationService) GetByID(id uint) (*dto.WeeklyDeclarationDTO, error) {
 	declaration, err := s.declarationRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("déclaration introuvable")
+		return nil, utils.ErrDeclarationNotFound
 	}
 
-	declarationDTO := s.declarationToDTO(declaration)
-	return &declarationDTO, nil
+	declarationDTOs := []dto.WeeklyDeclarationDTO{s.declarationToDTO(declaration)}
+	s.applyWorkload(declarationDTOs)
+	return &declarationDTOs[0], nil
 }
 
 // GetByUserIDAndWeek récupère une déclaration par utilisateur et semaine
 func (s *weeklyDeclarationService) GetByUserIDAndWeek(userID uint, week string) (*dto.WeeklyDeclarationDTO, error) {
 	declaration, err := s.declarationRepo.FindByUserIDAndWeek(userID, week)
 	if err != nil {
-		return nil, errors.New("déclaration introuvable")
+		return nil, utils.ErrDeclarationNotFound
 	}
 
-	declarationDTO := s.declarationToDTO(declaration)
-	return &declarationDTO, nil
+	declarationDTOs := []dto.WeeklyDeclarationDTO{s.declarationToDTO(declaration)}
+	s.applyWorkload(declarationDTOs)
+	return &declarationDTOs[0], nil
 }
 
 // GetByUserID récupère les déclarations d'un utilisateur
 func (s *weeklyDeclarationService) GetByUserID(userID uint) ([]dto.WeeklyDeclarationDTO, error) {
 	declarations, err := s.declarationRepo.FindByUserID(userID)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des déclarations")
+		return nil, utils.NewInternalError("erreur lors de la récupération des déclarations")
 	}
 
 	var declarationDTOs []dto.WeeklyDeclarationDTO
 	for _, declaration := range declarations {
 		declarationDTOs = append(declarationDTOs, s.declarationToDTO(&declaration))
 	}
+	s.applyWorkload(declarationDTOs)
 
 	return declarationDTOs, nil
 }
@@ -78,13 +106,14 @@ func (s *weeklyDeclarationService) GetByUserID(userID uint) ([]dto.WeeklyDeclara
 func (s *weeklyDeclarationService) GetValidated() ([]dto.WeeklyDeclarationDTO, error) {
 	declarations, err := s.declarationRepo.FindValidated()
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des déclarations")
+		return nil, utils.NewInternalError("erreur lors de la récupération des déclarations")
 	}
 
 	var declarationDTOs []dto.WeeklyDeclarationDTO
 	for _, declaration := range declarations {
 		declarationDTOs = append(declarationDTOs, s.declarationToDTO(&declaration))
 	}
+	s.applyWorkload(declarationDTOs)
 
 	return declarationDTOs, nil
 }
@@ -93,76 +122,280 @@ func (s *weeklyDeclarationService) GetValidated() ([]dto.WeeklyDeclarationDTO, e
 func (s *weeklyDeclarationService) GetPendingValidation() ([]dto.WeeklyDeclarationDTO, error) {
 	declarations, err := s.declarationRepo.FindPendingValidation()
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération des déclarations")
+		return nil, utils.NewInternalError("erreur lors de la récupération des déclarations")
 	}
 
 	var declarationDTOs []dto.WeeklyDeclarationDTO
 	for _, declaration := range declarations {
 		declarationDTOs = append(declarationDTOs, s.declarationToDTO(&declaration))
 	}
+	s.applyWorkload(declarationDTOs)
 
 	return declarationDTOs, nil
 }
 
-// Validate valide une déclaration
+// Validate valide une déclaration (approbation sans commentaire)
 func (s *weeklyDeclarationService) Validate(id uint, validatedByID uint) (*dto.WeeklyDeclarationDTO, error) {
+	return s.Approve(id, validatedByID, "")
+}
+
+// Submit soumet la déclaration à validation ; seul son auteur peut la soumettre
+func (s *weeklyDeclarationService) Submit(id uint, submittedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error) {
 	declaration, err := s.declarationRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("déclaration introuvable")
+		return nil, utils.ErrDeclarationNotFound
+	}
+	if declaration.UserID != submittedByID {
+		return nil, utils.ErrDeclarationAuthor
 	}
+	return s.transition(declaration, models.WeeklyDeclarationStatusSubmitted, submittedByID, comment)
+}
 
-	// Vérifier que le validateur existe
-	_, err = s.userRepo.FindByID(validatedByID)
+// Approve approuve une déclaration soumise : les entrées de temps de la semaine deviennent non modifiables
+func (s *weeklyDeclarationService) Approve(id uint, approvedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error) {
+	declaration, err := s.declarationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrDeclarationNotFound
+	}
+	if _, err := s.userRepo.FindByID(approvedByID); err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	updated, err := s.transition(declaration, models.WeeklyDeclarationStatusApproved, approvedByID, comment)
 	if err != nil {
-		return nil, errors.New("validateur introuvable")
+		return nil, err
 	}
+	s.notifyDecision(declaration, approvedByID, "Déclaration "+declaration.Week+" approuvée",
+		"Votre déclaration est approuvée, la saisie de temps de la semaine est verrouillée", comment)
+	return updated, nil
+}
 
-	now := time.Now()
-	declaration.Validated = true
-	declaration.ValidatedByID = &validatedByID
-	declaration.ValidatedAt = &now
+// Reject rejette une déclaration soumise ; l'auteur peut la corriger puis la soumettre à nouveau
+func (s *weeklyDeclarationService) Reject(id uint, rejectedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, errors.New("un motif est requis pour rejeter la déclaration")
+	}
+	declaration, err := s.declarationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrDeclarationNotFound
+	}
+	updated, err := s.transition(declaration, models.WeeklyDeclarationStatusRejected, rejectedByID, comment)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyDecision(declaration, rejectedByID, "Déclaration "+declaration.Week+" rejetée",
+		"Votre déclaration est rejetée, corrigez-la avant de la soumettre à nouveau", comment)
+	return updated, nil
+}
 
-	if err := s.declarationRepo.Update(declaration); err != nil {
-		return nil, errors.New("erreur lors de la validation de la déclaration")
+// Unlock déverrouille une déclaration approuvée : elle repasse en brouillon et la saisie de temps de la semaine
+// redevient possible
+func (s *weeklyDeclarationService) Unlock(id uint, unlockedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, errors.New("un motif est requis pour déverrouiller la déclaration")
 	}
+	declaration, err := s.declarationRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrDeclarationNotFound
+	}
+	updated, err := s.transition(declaration, models.WeeklyDeclarationStatusDraft, unlockedByID, comment)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyDecision(declaration, unlockedByID, "Déclaration "+declaration.Week+" déverrouillée",
+		"Votre déclaration est repassée en brouillon, la saisie de temps de la semaine est rouverte", comment)
+	return updated, nil
+}
 
-	// Récupérer la déclaration mise à jour
-	updatedDeclaration, err := s.declarationRepo.FindByID(id)
+// GetHistory récupère l'historique des changements de statut d'une déclaration
+func (s *weeklyDeclarationService) GetHistory(id uint) ([]dto.WeeklyDeclarationEventDTO, error) {
+	if _, err := s.declarationRepo.FindByID(id); err != nil {
+		return nil, utils.ErrDeclarationNotFound
+	}
+	events, err := s.declarationRepo.FindEvents(id)
 	if err != nil {
-		return nil, errors.New("erreur lors de la récupération de la déclaration mise à jour")
+		return nil, utils.NewInternalError("erreur lors de la récupération de l'historique de la déclaration")
 	}
 
-	declarationDTO := s.declarationToDTO(updatedDeclaration)
-	return &declarationDTO, nil
+	eventDTOs := make([]dto.WeeklyDeclarationEventDTO, 0, len(events))
+	for _, event := range events {
+		eventDTO := dto.WeeklyDeclarationEventDTO{
+			ID:         event.ID,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Comment:    event.Comment,
+			CreatedAt:  event.CreatedAt,
+		}
+		if event.PerformedBy.ID != 0 {
+			performedBy := s.userToDTO(&event.PerformedBy)
+			eventDTO.PerformedBy = &performedBy
+		}
+		eventDTOs = append(eventDTOs, eventDTO)
+	}
+	return eventDTOs, nil
 }
 
-// Delete supprime une déclaration
+// Delete supprime une déclaration ; une déclaration approuvée doit d'abord être déverrouillée
 func (s *weeklyDeclarationService) Delete(id uint) error {
-	_, err := s.declarationRepo.FindByID(id)
+	declaration, err := s.declarationRepo.FindByID(id)
 	if err != nil {
-		return errors.New("déclaration introuvable")
+		return utils.ErrDeclarationNotFound
+	}
+	if declaration.Status == models.WeeklyDeclarationStatusApproved {
+		return utils.ErrDeclarationLocked
 	}
 
 	if err := s.declarationRepo.Delete(id); err != nil {
-		return errors.New("erreur lors de la suppression de la déclaration")
+		return utils.NewInternalError("erreur lors de la suppression de la déclaration")
+	}
+
+	return nil
+}
+
+// transition contrôle et applique un changement de statut, et l'enregistre dans l'historique de la déclaration
+func (s *weeklyDeclarationService) transition(declaration *models.WeeklyDeclaration, to string, performedByID uint, comment string) (*dto.WeeklyDeclarationDTO, error) {
+	from := declaration.Status
+	if !slices.Contains(weeklyDeclarationTransitions[from], to) {
+		return nil, utils.ErrDeclarationTransition.WithDetails(map[string]any{
+			"from":    from,
+			"to":      to,
+			"allowed": weeklyDeclarationTransitions[from],
+		})
+	}
+
+	comment = strings.TrimSpace(comment)
+	now := time.Now()
+	declaration.Status = to
+	switch to {
+	case models.WeeklyDeclarationStatusSubmitted:
+		declaration.SubmittedAt = &now
+		declaration.Validated = false
+		declaration.ValidatedByID = nil
+		declaration.ValidatedAt = nil
+		declaration.ValidationComment = ""
+	case models.WeeklyDeclarationStatusApproved, models.WeeklyDeclarationStatusRejected:
+		declaration.Validated = to == models.WeeklyDeclarationStatusApproved
+		declaration.ValidatedByID = &performedByID
+		declaration.ValidatedAt = &now
+		declaration.ValidationComment = comment
+	case models.WeeklyDeclarationStatusDraft:
+		declaration.Validated = false
+		declaration.ValidatedByID = nil
+		declaration.ValidatedAt = nil
+		declaration.ValidationComment = ""
+	}
+
+	event := &models.WeeklyDeclarationEvent{
+		DeclarationID: declaration.ID,
+		FromStatus:    from,
+		ToStatus:      to,
+		Comment:       comment,
+		PerformedByID: performedByID,
+	}
+	applied, err := s.declarationRepo.Transition(declaration, from, event)
+	if err != nil {
+		log.Printf("[WeeklyDeclarationTransition] declaration=%d %s->%s: %v", declaration.ID, from, to, err)
+		return nil, utils.NewInternalError("erreur lors de la mise à jour du statut de la déclaration")
+	}
+	if !applied {
+		// La déclaration a changé de statut depuis sa lecture
+		return nil, utils.ErrDeclarationTransition
 	}
 
+	updatedDeclaration, err := s.declarationRepo.FindByID(declaration.ID)
+	if err != nil {
+		return nil, utils.NewInternalError("erreur lors de la récupération de la déclaration mise à jour")
+	}
+	declarationDTOs := []dto.WeeklyDeclarationDTO{s.declarationToDTO(updatedDeclaration)}
+	s.applyWorkload(declarationDTOs)
+	return &declarationDTOs[0], nil
+}
+
+// notifyDecision notifie l'auteur de la déclaration d'une décision prise par un tiers
+func (s *weeklyDeclarationService) notifyDecision(declaration *models.WeeklyDeclaration, decidedByID uint, title, message, comment string) {
+	if s.notificationService == nil || declaration.UserID == decidedByID {
+		return
+	}
+	if comment = strings.TrimSpace(comment); comment != "" {
+		message += " - " + comment
+	}
+	metadata := map[string]any{
+		"declaration_id": declaration.ID,
+		"week":           declaration.Week,
+		"status":         declaration.Status,
+	}
+	linkURL := fmt.Sprintf("/timesheet/weekly/%s", declaration.Week)
+	if err := s.notificationService.Create(declaration.UserID, "weekly_declaration_decided", title, message, linkURL, metadata); err != nil {
+		log.Printf("Erreur lors de la notification de la déclaration %d à l'utilisateur %d: %v", declaration.ID, declaration.UserID, err)
+	}
+}
+
+// checkWeekUnlocked refuse la modification du temps d'un utilisateur sur une semaine dont la déclaration est approuvée
+func checkWeekUnlocked(declarationRepo repositories.WeeklyDeclarationRepository, userID uint, date time.Time) error {
+	locked, err := declarationRepo.IsLocked(userID, date)
+	if err != nil {
+		log.Printf("[CheckWeekUnlocked] user=%d date=%s: %v", userID, date.Format("2006-01-02"), err)
+		return utils.NewInternalError("erreur lors de la vérification du verrouillage de la semaine")
+	}
+	if locked {
+		return utils.ErrDeclarationLocked.WithDetails(map[string]any{"date": date.Format("2006-01-02")})
+	}
 	return nil
 }
 
+// applyWorkload renseigne le temps attendu des déclarations (jours ouvrés hors absences approuvées) et signale
+// celles dont le temps déclaré est inférieur ; les absences sont chargées en une requête pour toute la liste
+func (s *weeklyDeclarationService) applyWorkload(declarationDTOs []dto.WeeklyDeclarationDTO) {
+	if len(declarationDTOs) == 0 {
+		return
+	}
+	userIDs := make([]uint, 0, len(declarationDTOs))
+	from, to := declarationDTOs[0].StartDate, declarationDTOs[0].EndDate
+	for _, declarationDTO := range declarationDTOs {
+		if !slices.Contains(userIDs, declarationDTO.UserID) {
+			userIDs = append(userIDs, declarationDTO.UserID)
+		}
+		if declarationDTO.StartDate.Before(from) {
+			from = declarationDTO.StartDate
+		}
+		if declarationDTO.EndDate.After(to) {
+			to = declarationDTO.EndDate
+		}
+	}
+
+	absences, err := s.absenceRepo.FindApproved(userIDs, from, to)
+	if err != nil {
+		// Le temps attendu est alors calculé sans les absences
+		log.Printf("[WeeklyDeclaration] absences des utilisateurs %v: %v", userIDs, err)
+	}
+	absencesByUser := make(map[uint][]models.Absence)
+	for _, absence := range absences {
+		absencesByUser[absence.UserID] = append(absencesByUser[absence.UserID], absence)
+	}
+
+	for i := range declarationDTOs {
+		declarationDTO := &declarationDTOs[i]
+		absent := absentDays(absencesByUser[declarationDTO.UserID], declarationDTO.StartDate, declarationDTO.EndDate)
+		declarationDTO.ExpectedTime, declarationDTO.AbsenceDays = expectedWorkload(declarationDTO.StartDate, declarationDTO.EndDate, absent)
+		declarationDTO.UnderDeclared = declarationDTO.TotalTime < declarationDTO.ExpectedTime
+	}
+}
+
 // declarationToDTO convertit un modèle WeeklyDeclaration en DTO
 func (s *weeklyDeclarationService) declarationToDTO(declaration *models.WeeklyDeclaration) dto.WeeklyDeclarationDTO {
 	declarationDTO := dto.WeeklyDeclarationDTO{
-		ID:        declaration.ID,
-		UserID:    declaration.UserID,
-		Week:      declaration.Week,
-		StartDate: declaration.StartDate,
-		EndDate:   declaration.EndDate,
-		TaskCount: declaration.TaskCount,
-		TotalTime: declaration.TotalTime,
-		Validated: declaration.Validated,
-		CreatedAt: declaration.CreatedAt,
-		UpdatedAt: declaration.UpdatedAt,
+		ID:                declaration.ID,
+		UserID:            declaration.UserID,
+		Week:              declaration.Week,
+		StartDate:         declaration.StartDate,
+		EndDate:           declaration.EndDate,
+		TaskCount:         declaration.TaskCount,
+		TotalTime:         declaration.TotalTime,
+		Status:            declaration.Status,
+		SubmittedAt:       declaration.SubmittedAt,
+		Validated:         declaration.Validated,
+		ValidationComment: declaration.ValidationComment,
+		CreatedAt:         declaration.CreatedAt,
+		UpdatedAt:         declaration.UpdatedAt,
 	}
 
 	if declaration.ValidatedByID != nil {
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/mcicare/itsm-backend/internal/dto"
+	"github.com/mcicare/itsm-backend/internal/jobs"
+	"github.com/mcicare/itsm-backend/internal/models"
+	"github.com/mcicare/itsm-backend/internal/repositories"
+	"github.com/mcicare/itsm-backend/internal/whatsapp"
+)
+
+// whatsAppNotificationTypes types de notification critiques pouvant être envoyés sur WhatsApp
+var whatsAppNotificationTypes = []string{"sla_violated", "major_incident"}
+
+// whatsAppPhonePattern numéro au format E.164
+var whatsAppPhonePattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// whatsAppParameterMaxLength longueur maximale d'une variable de modèle
+const whatsAppParameterMaxLength = 1024
+
+// WhatsAppService interface pour le canal WhatsApp Business : modèles de messages, consentements et envoi des notifications critiques
+type WhatsAppService interface {
+	NotificationChannel
+	Enabled() bool
+	GetTemplates() ([]dto.WhatsAppTemplateDTO, error)
+	CreateTemplate(req dto.CreateWhatsAppTemplateRequest, createdByID uint) (*dto.WhatsAppTemplateDTO, error)
+	UpdateTemplate(id uint, req dto.UpdateWhatsAppTemplateRequest) (*dto.WhatsAppTemplateDTO, error)
+	DeleteTemplate(id uint) error
+	GetOptIn(userID uint) (*dto.WhatsAppOptInDTO, error)
+	OptIn(userID uint, req dto.UpdateWhatsAppOptInRequest) (*dto.WhatsAppOptInDTO, error)
+	OptOut(userID uint) error
+	Send(ctx context.Context, payload jobs.WhatsAppSendPayload) error
+}
+
+// whatsAppService implémente WhatsAppService
+type whatsAppService struct {
+	client       *whatsapp.Client // Nil si le canal n'est pas configuré
+	whatsAppRepo repositories.WhatsAppRepository
+	jobQueue     *jobs.Queue
+}
+
+// NewWhatsAppService crée une nouvelle instance de WhatsAppService
+func NewWhatsAppService(cfg config.WhatsAppConfig, whatsAppRepo repositories.WhatsAppRepository, jobQueue *jobs.Queue) WhatsAppService {
+	var client *whatsapp.Client
+	if cfg.Enabled() {
+		client = whatsapp.New(cfg.PhoneNumberID, cfg.AccessToken, cfg.APIVersion)
+	}
+	return &whatsAppService{
+		client:       client,
+		whatsAppRepo: whatsAppRepo,
+		jobQueue:     jobQueue,
+	}
+}
+
+// Enabled indique si le canal est configuré
+func (s *whatsAppService) Enabled() bool {
+	return s.client != nil
+}
+
+// GetTemplates récupère les modèles de messages
+func (s *whatsAppService) GetTemplates() ([]dto.WhatsAppTemplateDTO, error) {
+	templates, err := s.whatsAppRepo.FindTemplates()
+	if err != nil {
+		return nil, errors.New("erreur lors de la récupération des modèles")
+	}
+	templateDTOs := make([]dto.WhatsAppTemplateDTO, len(templates))
+	for i := range templates {
+		templateDTOs[i] = whatsAppTemplateToDTO(&templates[i])
+	}
+	return templateDTOs, nil
+}
+
+// CreateTemplate associe un modèle de message à un type de notification critique
+func (s *whatsAppService) CreateTemplate(req dto.CreateWhatsAppTemplateRequest, createdByID uint) (*dto.WhatsAppTemplateDTO, error) {
+	if !slices.Contains(whatsAppNotificationTypes, req.NotificationType) {
+		return nil, errors.New("type de notification non pris en charge")
+	}
+	if _, err := s.whatsAppRepo.FindTemplateByType(req.NotificationType); err == nil {
+		return nil, errors.New("un modèle existe déjà pour ce type de notification")
+	}
+
+	template := &models.WhatsAppTemplate{
+		NotificationType: req.NotificationType,
+		Name:             strings.TrimSpace(req.Name),
+		Language:         "fr",
+		IsActive:         true,
+		CreatedByID:      &createdByID,
+	}
+	if req.Language != "" {
+		template.Language = req.Language
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	template.Parameters, _ = json.Marshal(append([]string{}, req.Parameters...))
+
+	if err := s.whatsAppRepo.CreateTemplate(template); err != nil {
+		return nil, errors.New("erreur lors de la création du modèle")
+	}
+	templateDTO := whatsAppTemplateToDTO(template)
+	return &templateDTO, nil
+}
+
+// UpdateTemplate met à jour un modèle de message
+func (s *whatsAppService) UpdateTemplate(id uint, req dto.UpdateWhatsAppTemplateRequest) (*dto.WhatsAppTemplateDTO, error) {
+	template, err := s.whatsAppRepo.FindTemplateByID(id)
+	if err != nil {
+		return nil, errors.New("modèle introuvable")
+	}
+	if req.Name != nil {
+		template.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Language != nil {
+		template.Language = *req.Language
+	}
+	if req.Parameters != nil {
+		template.Parameters, _ = json.Marshal(req.Parameters)
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+
+	if err := s.whatsAppRepo.UpdateTemplate(template); err != nil {
+		return nil, errors.New("erreur lors de la mise à jour du modèle")
+	}
+	templateDTO := whatsAppTemplateToDTO(template)
+	return &templateDTO, nil
+}
+
+// DeleteTemplate supprime un modèle : son type de notification n'est plus envoyé sur WhatsApp
+func (s *whatsAppService) DeleteTemplate(id uint) error {
+	if _, err := s.whatsAppRepo.FindTemplateByID(id); err != nil {
+		return errors.New("modèle introuvable")
+	}
+	if err := s.whatsAppRepo.DeleteTemplate(id); err != nil {
+		return errors.New("erreur lors de la suppression du modèle")
+	}
+	return nil
+}
+
+// GetOptIn retourne l'état du consentement de l'utilisateur
+func (s *whatsAppService) GetOptIn(userID uint) (*dto.WhatsAppOptInDTO, error) {
+	status := &dto.WhatsAppOptInDTO{Enabled: s.Enabled(), NotificationTypes: []string{}}
+	if optIn, err := s.whatsAppRepo.FindOptInByUserID(userID); err == nil {
+		status.OptedIn = true
+		status.PhoneNumber = optIn.PhoneNumber
+		status.OptedInAt = &optIn.OptedInAt
+	}
+	for _, notificationType := range whatsAppNotificationTypes {
+		if template, err := s.whatsAppRepo.FindTemplateByType(notificationType); err == nil && template.IsActive {
+			status.NotificationTypes = append(status.NotificationTypes, notificationType)
+		}
+	}
+	return status, nil
+}
+
+// OptIn enregistre le consentement de l'utilisateur et son numéro ; un changement de numéro renouvelle le consentement
+func (s *whatsAppService) OptIn(userID uint, req dto.UpdateWhatsAppOptInRequest) (*dto.WhatsAppOptInDTO, error) {
+	if !s.Enabled() {
+		return nil, errors.New("canal WhatsApp non configuré")
+	}
+	if !req.Consent {
+		return nil, errors.New("le consentement explicite est requis")
+	}
+	phone := normalizeWhatsAppPhone(req.PhoneNumber)
+	if !whatsAppPhonePattern.MatchString(phone) {
+		return nil, errors.New("numéro de téléphone invalide (format international attendu, ex: +2250700000000)")
+	}
+
+	optIn, err := s.whatsAppRepo.FindOptInByUserID(userID)
+	if err != nil {
+		optIn = &models.WhatsAppOptIn{UserID: userID}
+	}
+	if optIn.PhoneNumber != phone {
+		optIn.PhoneNumber = phone
+		optIn.OptedInAt = time.Now()
+	}
+	if err := s.whatsAppRepo.SaveOptIn(optIn); err != nil {
+		return nil, errors.New("erreur lors de l'enregistrement du consentement")
+	}
+	return s.GetOptIn(userID)
+}
+
+// OptOut retire le consentement de l'utilisateur
+func (s *whatsAppService) OptOut(userID uint) error {
+	if _, err := s.whatsAppRepo.FindOptInByUserID(userID); err != nil {
+		return errors.New("consentement WhatsApp introuvable")
+	}
+	if err := s.whatsAppRepo.DeleteOptInByUserID(userID); err != nil {
+		return errors.New("erreur lors de la suppression du consentement")
+	}
+	return nil
+}
+
+// Deliver planifie l'envoi des notifications critiques aux utilisateurs ayant consenti
+func (s *whatsAppService) Deliver(notification *models.Notification) {
+	if !s.Enabled() || !slices.Contains(whatsAppNotificationTypes, notification.Type) {
+		return
+	}
+	if _, err := s.whatsAppRepo.FindOptInByUserID(notification.UserID); err != nil {
+		return
+	}
+	if template, err := s.whatsAppRepo.FindTemplateByType(notification.Type); err != nil || !template.IsActive {
+		return
+	}
+
+	payload := jobs.WhatsAppSendPayload{
+		UserID:           notification.UserID,
+		NotificationType: notification.Type,
+		Title:            notification.Title,
+		Message:          notification.Message,
+	}
+	var metadata struct {
+		TicketCode string `json:"ticket_code"`
+	}
+	if len(notification.Metadata) > 0 && json.Unmarshal(notification.Metadata, &metadata) == nil {
+		payload.TicketCode = metadata.TicketCode
+	}
+	if err := s.jobQueue.Enqueue(context.Background(), jobs.TypeWhatsAppSend, payload); err != nil {
+		log.Printf("Erreur lors de la planification de la notification WhatsApp (user %d): %v", notification.UserID, err)
+	}
+}
+
+// Send envoie une notification avec le modèle de son type
+func (s *whatsAppService) Send(ctx context.Context, payload jobs.WhatsAppSendPayload) error {
+	if !s.Enabled() {
+		return nil
+	}
+	optIn, err := s.whatsAppRepo.FindOptInByUserID(payload.UserID)
+	if err != nil {
+		return nil // Consentement retiré entre-temps
+	}
+	template, err := s.whatsAppRepo.FindTemplateByType(payload.NotificationType)
+	if err != nil || !template.IsActive {
+		return nil // Modèle supprimé ou désactivé entre-temps
+	}
+
+	var fields []string
+	_ = json.Unmarshal(template.Parameters, &fields)
+	parameters := make([]string, len(fields))
+	for i, field := range fields {
+		var value string
+		switch field {
+		case models.WhatsAppFieldTitle:
+			value = payload.Title
+		case models.WhatsAppFieldMessage:
+			value = payload.Message
+		case models.WhatsAppFieldTicketCode:
+			value = payload.TicketCode
+		}
+		parameters[i] = whatsAppParameter(value)
+	}
+	return s.client.SendTemplate(ctx, strings.TrimPrefix(optIn.PhoneNumber, "+"), template.Name, template.Language, parameters)
+}
+
+// normalizeWhatsAppPhone supprime les séparateurs d'un numéro et remplace le préfixe international 00 par +
+func normalizeWhatsAppPhone(phone string) string {
+	phone = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" .-()/", r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + phone[2:]
+	}
+	return phone
+}
+
+// whatsAppParameter adapte une valeur aux contraintes des variables de modèle (ni retour à la ligne ni valeur vide)
+func whatsAppParameter(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return "-"
+	}
+	return truncateRunes(value, whatsAppParameterMaxLength)
+}
+
+// whatsAppTemplateToDTO convertit un modèle de message en DTO
+func whatsAppTemplateToDTO(template *models.WhatsAppTemplate) dto.WhatsAppTemplateDTO {
+	parameters := []string{}
+	_ = json.Unmarshal(template.Parameters, &parameters)
+	return dto.WhatsAppTemplateDTO{
+		ID:               template.ID,
+		NotificationType: template.NotificationType,
+		Name:             template.Name,
+		Language:         template.Language,
+		Parameters:       parameters,
+		IsActive:         template.IsActive,
+		CreatedAt:        template.CreatedAt,
+		UpdatedAt:        template.UpdatedAt,
+	}
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// localStorage stocke les fichiers sur le disque local
+type localStorage struct {
+	root      string
+	namespace string
+	baseURL   string // URL publique des fichiers signés (ex: https://api.exemple.ci/api/v1/files)
+	secret    string
+}
+
+// NewLocal crée un stockage sur disque enraciné dans root
+func NewLocal(root, namespace, baseURL, secret string) (Storage, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("création du dossier %s: %w", root, err)
+	}
+	return &localStorage{root: root, namespace: namespace, baseURL: baseURL, secret: secret}, nil
+}
+
+// Put écrit le fichier (écriture atomique via un fichier temporaire)
+func (s *localStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+// Get ouvre le fichier
+func (s *localStorage) Get(ctx context.Context, key string) (*Object, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, ErrNotFound
+	}
+	return &Object{
+		Body:        f,
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(fullPath)),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// Delete supprime le fichier (sans erreur s'il n'existe pas)
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Exists indique si le fichier existe
+func (s *localStorage) Exists(ctx context.Context, key string) (bool, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// SignedURL retourne une URL temporaire servie par l'API (GET /files/{namespace}/{key})
+func (s *localStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", signature(s.secret, s.namespace, cleaned, expires))
+	return fmt.Sprintf("%s/%s/%s?%s", s.baseURL, s.namespace, (&url.URL{Path: cleaned}).EscapedPath(), query.Encode()), nil
+}
+
+// Walk parcourt les fichiers de l'espace
+func (s *localStorage) Walk(ctx context.Context, fn func(key string) error) error {
+	return filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || (len(d.Name()) > 0 && d.Name()[0] == '.') {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel))
+	})
+}
+
+// Backend retourne "local"
+func (s *localStorage) Backend() string {
+	return "local"
+}
+
+// path retourne le chemin disque d'une clé
+func (s *localStorage) path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
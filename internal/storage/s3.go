@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Storage stocke les fichiers dans un bucket S3 (AWS S3 ou compatible : MinIO, Scaleway, ...)
+type s3Storage struct {
+	client *minio.Client
+	bucket string
+	prefix string // <S3_PREFIX>/<namespace>/
+}
+
+// NewS3 crée un stockage S3 pour un espace
+func NewS3(cfg config.StorageConfig, namespace string) (Storage, error) {
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("client S3: %w", err)
+	}
+	prefix := namespace + "/"
+	if p := strings.Trim(cfg.S3Prefix, "/"); p != "" {
+		prefix = p + "/" + prefix
+	}
+	return &s3Storage{client: client, bucket: cfg.S3Bucket, prefix: prefix}, nil
+}
+
+// Put envoie le fichier dans le bucket (size = -1 si inconnue)
+func (s *s3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, objectKey, body, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Get lit le fichier depuis le bucket
+func (s *s3Storage) Get(ctx context.Context, key string) (*Object, error) {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s.translate(err)
+	}
+	// GetObject est paresseux : Stat déclenche la requête et détecte l'absence du fichier
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, s.translate(err)
+	}
+	return &Object{Body: obj, Size: info.Size, ContentType: info.ContentType, ModTime: info.LastModified}, nil
+}
+
+// Delete supprime le fichier (sans erreur s'il n'existe pas)
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, objectKey, minio.RemoveObjectOptions{})
+}
+
+// Exists indique si le fichier existe
+func (s *s3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := s.client.StatObject(ctx, s.bucket, objectKey, minio.StatObjectOptions{}); err != nil {
+		if s.translate(err) == ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SignedURL retourne une URL présignée de téléchargement direct depuis le bucket
+func (s *s3Storage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return "", err
+	}
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, objectKey, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// Walk parcourt les objets de l'espace
+func (s *s3Storage) Walk(ctx context.Context, fn func(key string) error) error {
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return obj.Err
+		}
+		if err := fn(strings.TrimPrefix(obj.Key, s.prefix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backend retourne "s3"
+func (s *s3Storage) Backend() string {
+	return "s3"
+}
+
+// objectKey retourne la clé complète de l'objet dans le bucket
+func (s *s3Storage) objectKey(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return s.prefix + cleaned, nil
+}
+
+// translate convertit les erreurs "objet absent" en ErrNotFound
+func (s *s3Storage) translate(err error) error {
+	if resp := minio.ToErrorResponse(err); resp.Code == "NoSuchKey" || resp.StatusCode == 404 {
+		return ErrNotFound
+	}
+	return err
+}
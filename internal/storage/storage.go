@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// Espaces de stockage (un dossier local ou un préfixe S3 par espace)
+const (
+	NamespaceTickets   = "tickets"   // Pièces jointes des tickets
+	NamespaceUsers     = "users"     // Avatars des utilisateurs
+	NamespaceAudit     = "audit"     // Archives du journal d'audit
+	NamespaceKnowledge = "knowledge" // Images intégrées aux articles de la base de connaissances
+)
+
+// Erreurs retournées par les implémentations
+var (
+	ErrNotFound   = errors.New("fichier introuvable")
+	ErrInvalidKey = errors.New("clé de fichier invalide")
+)
+
+// Object représente un fichier lu depuis le stockage ; Body doit être fermé par l'appelant
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage abstrait le stockage des fichiers (disque local, S3, ...)
+// Les clés sont des chemins relatifs à l'espace, séparés par "/" (ex: ticket_12/1700000000_rapport.pdf)
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// Walk appelle fn pour chaque clé de l'espace (migration entre backends)
+	Walk(ctx context.Context, fn func(key string) error) error
+	// Backend retourne le nom du backend (local, s3)
+	Backend() string
+}
+
+// New crée le stockage d'un espace selon STORAGE_DRIVER :
+// dossier localDir en local, préfixe <S3_PREFIX>/<namespace>/ dans le bucket en S3
+func New(cfg *config.Config, namespace, localDir string) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "", "local":
+		return NewLocal(localDir, namespace, strings.TrimRight(cfg.App.URL, "/")+"/api/v1/files", cfg.Storage.SigningSecret)
+	case "s3":
+		return NewS3(cfg.Storage, namespace)
+	}
+	return nil, fmt.Errorf("backend de stockage inconnu: %s", cfg.Storage.Driver)
+}
+
+// cleanKey normalise une clé ; le nettoyage d'un chemin enraciné empêche de sortir de l'espace (../)
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))[1:]
+	if cleaned == "" {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// signature calcule la signature HMAC d'une URL locale
+func signature(secret, namespace, key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(namespace + "/" + key + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature vérifie une URL signée du stockage local (signature valide et non expirée)
+func VerifySignature(secret, namespace, key, expires, sig string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+	expected := signature(secret, namespace, key, expiresAt)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
@@ -0,0 +1,130 @@
+// Package telegram appelle l'API Bot de Telegram (envoi de messages, boutons d'action, webhook)
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// apiURL point d'entrée de l'API Bot
+const apiURL = "https://api.telegram.org/bot"
+
+// requestTimeout borne la durée d'un appel à l'API
+const requestTimeout = 15 * time.Second
+
+// Update mise à jour reçue par le webhook (message ou clic sur un bouton)
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+// Message message d'une conversation
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+// Chat conversation (privée pour un utilisateur du bot)
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// User compte Telegram
+type User struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+}
+
+// CallbackQuery clic sur un bouton d'un message du bot
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+// Button bouton d'action affiché sous un message ; Data est renvoyé au webhook lors du clic (64 octets maximum)
+type Button struct {
+	Text string `json:"text"`
+	Data string `json:"callback_data"`
+}
+
+// Client client de l'API Bot pour un bot donné
+type Client struct {
+	token string
+	http  *http.Client
+}
+
+// New crée un client pour le jeton du bot
+func New(token string) *Client {
+	return &Client{token: token, http: &http.Client{Timeout: requestTimeout}}
+}
+
+// SendMessage envoie un message HTML, avec une rangée de boutons par élément de keyboard
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string, keyboard ...[]Button) error {
+	params := map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+	if len(keyboard) > 0 {
+		params["reply_markup"] = map[string]any{"inline_keyboard": keyboard}
+	}
+	return c.call(ctx, "sendMessage", params)
+}
+
+// AnswerCallbackQuery acquitte un clic sur un bouton en affichant un court message
+func (c *Client) AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error {
+	return c.call(ctx, "answerCallbackQuery", map[string]any{"callback_query_id": callbackQueryID, "text": text})
+}
+
+// SetWebhook déclare l'URL du webhook ; secret est renvoyé dans l'en-tête X-Telegram-Bot-Api-Secret-Token
+func (c *Client) SetWebhook(ctx context.Context, url, secret string) error {
+	return c.call(ctx, "setWebhook", map[string]any{
+		"url":             url,
+		"secret_token":    secret,
+		"allowed_updates": []string{"message", "callback_query"},
+	})
+}
+
+// call appelle une méthode de l'API
+func (c *Client) call(ctx context.Context, method string, params map[string]any) error {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL+c.token+"/"+method, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// L'URL contient le jeton du bot : l'erreur n'est pas propagée telle quelle
+		return fmt.Errorf("API Telegram %s: requête impossible", method)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return fmt.Errorf("API Telegram %s: réponse HTTP %d illisible", method, resp.StatusCode)
+	}
+	if !body.OK {
+		return fmt.Errorf("API Telegram %s: réponse HTTP %d: %s", method, resp.StatusCode, body.Description)
+	}
+	return nil
+}
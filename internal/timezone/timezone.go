@@ -0,0 +1,84 @@
+// Package timezone résout le fuseau horaire applicable à un utilisateur (préférence, sinon filiale,
+// sinon fuseau par défaut de l'application) et calcule les bornes de journée dans ce fuseau
+package timezone
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mcicare/itsm-backend/config"
+)
+
+// DateLayout format des dates calendaires (YYYY-MM-DD)
+const DateLayout = "2006-01-02"
+
+// locations fuseaux déjà chargés, par nom IANA
+var locations sync.Map
+
+// Load charge un fuseau IANA (ex: Africa/Abidjan) en le gardant en cache
+func Load(name string) (*time.Location, error) {
+	if cached, ok := locations.Load(name); ok {
+		return cached.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, location)
+	return location, nil
+}
+
+// Valid indique si le nom est un fuseau IANA connu (vide accepté : fuseau par défaut)
+func Valid(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, err := Load(name)
+	return err == nil
+}
+
+// Default retourne le fuseau par défaut de l'application (APP_TIMEZONE, sinon fuseau du serveur)
+func Default() *time.Location {
+	if config.AppConfig != nil && config.AppConfig.App.Timezone != "" {
+		if location, err := Load(config.AppConfig.App.Timezone); err == nil {
+			return location
+		}
+	}
+	return time.Local
+}
+
+// Resolve retourne le premier fuseau valide parmi les noms fournis (par ordre de priorité), sinon le fuseau par défaut
+func Resolve(names ...string) *time.Location {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if location, err := Load(name); err == nil {
+			return location
+		}
+	}
+	return Default()
+}
+
+// Date retourne la date calendaire de l'instant t vue dans le fuseau loc, à minuit heure du serveur
+// (représentation attendue par les colonnes DATE)
+func Date(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// Today retourne la date du jour dans le fuseau loc (voir Date)
+func Today(loc *time.Location) time.Time {
+	return Date(time.Now(), loc)
+}
+
+// ParseDate lit une date calendaire YYYY-MM-DD (minuit heure du serveur, voir Date)
+func ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, value, time.Local)
+}
+
+// DayBounds retourne les instants de début (inclus) et de fin (exclue) d'une date calendaire dans le fuseau loc
+func DayBounds(date time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
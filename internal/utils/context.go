@@ -5,17 +5,6 @@ import (
 	"github.com/mcicare/itsm-backend/internal/scope"
 )
 
-// ApplyDashboardScopeHint applique le paramètre query scope=department|filiale|global sur le QueryScope (pour le tableau de bord)
-func ApplyDashboardScopeHint(c *gin.Context, queryScope *scope.QueryScope) {
-	if queryScope == nil {
-		return
-	}
-	switch c.Query("scope") {
-	case "department", "filiale", "global":
-		queryScope.DashboardScopeHint = c.Query("scope")
-	}
-}
-
 // GetScopeFromContext extrait le QueryScope du contexte Gin
 // Retourne nil si le scope n'est pas trouvé (ne devrait jamais arriver si AuthMiddleware est utilisé)
 func GetScopeFromContext(c *gin.Context) *scope.QueryScope {
@@ -32,6 +21,17 @@ func GetScopeFromContext(c *gin.Context) *scope.QueryScope {
 	return queryScope
 }
 
+// RequireScope extrait le QueryScope du contexte et répond 500 s'il est absent
+// Le scope est construit une fois par requête par AuthMiddleware (permissions, délégation, périmètre ?scope=)
+func RequireScope(c *gin.Context) (*scope.QueryScope, bool) {
+	queryScope := GetScopeFromContext(c)
+	if queryScope == nil {
+		InternalServerErrorResponse(c, "Contexte utilisateur introuvable")
+		return nil, false
+	}
+	return queryScope, true
+}
+
 // GetUserIDFromContext extrait l'ID utilisateur du contexte Gin
 func GetUserIDFromContext(c *gin.Context) (uint, bool) {
 	userIDValue, exists := c.Get("user_id")
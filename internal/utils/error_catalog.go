@@ -0,0 +1,191 @@
+package utils
+
+import "net/http"
+
+// Codes d'erreur métier : stables, les clients peuvent s'y fier (les messages, eux, sont traduits et peuvent évoluer)
+const (
+	ErrCodeNotImplemented              = "not_implemented"
+	ErrCodeAPIKeyInvalid               = "api_key_invalid"
+	ErrCodeAPIKeyNotFound              = "api_key_not_found"
+	ErrCodeUserNotFound                = "user_not_found"
+	ErrCodeRoleNotFound                = "role_not_found"
+	ErrCodeFilialeNotFound             = "filiale_not_found"
+	ErrCodeDepartmentNotFound          = "department_not_found"
+	ErrCodeOfficeNotFound              = "office_not_found"
+	ErrCodeTicketNotFound              = "ticket_not_found"
+	ErrCodeTicketReadOnly              = "ticket_read_only_share"
+	ErrCodeChecklistItemNotFound       = "ticket_checklist_item_not_found"
+	ErrCodeTicketRelationNotFound      = "ticket_relation_not_found"
+	ErrCodeSolutionNotFound            = "ticket_solution_not_found"
+	ErrCodeRecurringTicketNotFound     = "recurring_ticket_not_found"
+	ErrCodeTicketViewNotFound          = "ticket_view_not_found"
+	ErrCodeTicketViewForbidden         = "ticket_view_forbidden"
+	ErrCodeSurveyNotFound              = "satisfaction_survey_not_found"
+	ErrCodeSurveyExpired               = "satisfaction_survey_expired"
+	ErrCodeSurveyAnswered              = "satisfaction_survey_answered"
+	ErrCodeCategoryNotFound            = "category_not_found"
+	ErrCodeAttachmentNotFound          = "attachment_not_found"
+	ErrCodeAttachmentFileMissing       = "attachment_file_missing"
+	ErrCodeAttachmentTooLarge          = "attachment_too_large"
+	ErrCodeAttachmentType              = "attachment_type_not_allowed"
+	ErrCodeAttachmentMismatch          = "attachment_content_mismatch"
+	ErrCodeAttachmentInfected          = "attachment_infected"
+	ErrCodeStorageQuotaExceeded        = "storage_quota_exceeded"
+	ErrCodeAntivirusUnavailable        = "antivirus_unavailable"
+	ErrCodeIncidentNotFound            = "incident_not_found"
+	ErrCodePostIncidentReviewNotFound  = "post_incident_review_not_found"
+	ErrCodeServiceRequestNotFound      = "service_request_not_found"
+	ErrCodeChangeNotFound              = "change_not_found"
+	ErrCodeProblemNotFound             = "problem_not_found"
+	ErrCodeProblemIncidentLinkNotFound = "problem_incident_link_not_found"
+	ErrCodeSLANotFound                 = "sla_not_found"
+	ErrCodeSLARuleConflict             = "sla_rule_conflict"
+	ErrCodeEscalationRuleNotFound      = "escalation_rule_not_found"
+	ErrCodeCalendarNotFound            = "business_calendar_not_found"
+	ErrCodeCalendarConflict            = "business_calendar_conflict"
+	ErrCodeHolidayNotFound             = "business_calendar_holiday_not_found"
+	ErrCodeProjectNotFound             = "project_not_found"
+	ErrCodeProjectArchived             = "project_archived"
+	ErrCodeProjectAlreadyArchived      = "project_already_archived"
+	ErrCodeProjectNotArchived          = "project_not_archived"
+	ErrCodeBudgetExtensionNotFound     = "project_budget_extension_not_found"
+	ErrCodeProjectTemplateNotFound     = "project_template_not_found"
+	ErrCodeProjectTemplateConflict     = "project_template_conflict"
+	ErrCodeProjectTaskNotFound         = "project_task_not_found"
+	ErrCodeTaskPrerequisiteNotFound    = "project_task_prerequisite_not_found"
+	ErrCodeTaskDependencyNotFound      = "project_task_dependency_not_found"
+	ErrCodeMilestoneNotFound           = "project_milestone_not_found"
+	ErrCodePhaseNotFound               = "project_phase_not_found"
+	ErrCodeTaskRecurrenceNotFound      = "project_task_recurrence_not_found"
+	ErrCodeTicketTaskLinkNotFound      = "ticket_project_task_link_not_found"
+	ErrCodeTaskTimerRunning            = "project_task_timer_running"
+	ErrCodeTaskTimerNotRunning         = "project_task_timer_not_running"
+	ErrCodeAssetNotFound               = "asset_not_found"
+	ErrCodeAssetTransition             = "asset_invalid_transition"
+	ErrCodeAssetRelationNotFound       = "asset_relation_not_found"
+	ErrCodeMaintenancePlanNotFound     = "maintenance_plan_not_found"
+	ErrCodeAssetCategoryNotFound       = "asset_category_not_found"
+	ErrCodeDiscoveryAgentNotFound      = "discovery_agent_not_found"
+	ErrCodeDiscrepancyNotFound         = "discovery_discrepancy_not_found"
+	ErrCodeDiscrepancyProcessed        = "discovery_discrepancy_processed"
+	ErrCodeReservationNotFound         = "asset_reservation_not_found"
+	ErrCodeReservationConflict         = "asset_reservation_conflict"
+	ErrCodeSoftwareNotFound            = "software_not_found"
+	ErrCodeLicenseNotFound             = "software_license_not_found"
+	ErrCodeArticleNotFound             = "article_not_found"
+	ErrCodeArticleTransition           = "article_invalid_transition"
+	ErrCodeArticleReviewer             = "article_review_forbidden"
+	ErrCodeArticleVersionNotFound      = "article_version_not_found"
+	ErrCodeDeflectionSessionNotFound   = "deflection_session_not_found"
+	ErrCodeTimeEntryNotFound           = "time_entry_not_found"
+	ErrCodeDeclarationNotFound         = "declaration_not_found"
+	ErrCodeDeclarationTransition       = "declaration_invalid_transition"
+	ErrCodeDeclarationLocked           = "declaration_week_locked"
+	ErrCodeDeclarationSubmitted        = "declaration_submitted"
+	ErrCodeDeclarationAuthor           = "declaration_author_only"
+	ErrCodeAbsenceNotFound             = "absence_not_found"
+	ErrCodeAbsenceConflict             = "absence_conflict"
+	ErrCodeDelayNotFound               = "delay_not_found"
+	ErrCodeJustificationNotFound       = "delay_justification_not_found"
+	ErrCodeDelayDecisionForbidden      = "delay_justification_decision_forbidden"
+	ErrCodeWebhookNotFound             = "webhook_not_found"
+	ErrCodeApprovalNotFound            = "approval_not_found"
+	ErrCodeApprovalForbidden           = "approval_forbidden"
+	ErrCodeApprovalPending             = "approval_already_pending"
+	ErrCodeApprovalClosed              = "approval_closed"
+	ErrCodeApprovalNoTask              = "approval_no_pending_task"
+)
+
+// Erreurs du catalogue retournées par les services
+var (
+	ErrNotImplemented              = NewAppError(http.StatusNotImplemented, ErrCodeNotImplemented, "non implémenté")
+	ErrAPIKeyInvalid               = NewAppError(http.StatusUnauthorized, ErrCodeAPIKeyInvalid, "clé d'API invalide")
+	ErrAPIKeyNotFound              = NewAppError(http.StatusNotFound, ErrCodeAPIKeyNotFound, "clé d'API introuvable")
+	ErrUserNotFound                = NewAppError(http.StatusNotFound, ErrCodeUserNotFound, "utilisateur introuvable")
+	ErrRoleNotFound                = NewAppError(http.StatusNotFound, ErrCodeRoleNotFound, "rôle introuvable")
+	ErrFilialeNotFound             = NewAppError(http.StatusNotFound, ErrCodeFilialeNotFound, "filiale introuvable")
+	ErrDepartmentNotFound          = NewAppError(http.StatusNotFound, ErrCodeDepartmentNotFound, "département introuvable")
+	ErrOfficeNotFound              = NewAppError(http.StatusNotFound, ErrCodeOfficeNotFound, "siège introuvable")
+	ErrTicketNotFound              = NewAppError(http.StatusNotFound, ErrCodeTicketNotFound, "ticket introuvable")
+	ErrTicketReadOnly              = NewAppError(http.StatusForbidden, ErrCodeTicketReadOnly, "accès en lecture seule à ce ticket")
+	ErrChecklistItemNotFound       = NewAppError(http.StatusNotFound, ErrCodeChecklistItemNotFound, "étape de checklist introuvable")
+	ErrTicketRelationNotFound      = NewAppError(http.StatusNotFound, ErrCodeTicketRelationNotFound, "relation introuvable")
+	ErrSolutionNotFound            = NewAppError(http.StatusNotFound, ErrCodeSolutionNotFound, "solution introuvable")
+	ErrRecurringTicketNotFound     = NewAppError(http.StatusNotFound, ErrCodeRecurringTicketNotFound, "ticket récurrent introuvable")
+	ErrTicketViewNotFound          = NewAppError(http.StatusNotFound, ErrCodeTicketViewNotFound, "vue de tickets introuvable")
+	ErrTicketViewForbidden         = NewAppError(http.StatusForbidden, ErrCodeTicketViewForbidden, "seul le propriétaire peut modifier cette vue")
+	ErrSurveyNotFound              = NewAppError(http.StatusNotFound, ErrCodeSurveyNotFound, "enquête de satisfaction introuvable")
+	ErrSurveyExpired               = NewAppError(http.StatusGone, ErrCodeSurveyExpired, "enquête de satisfaction expirée")
+	ErrSurveyAnswered              = NewAppError(http.StatusConflict, ErrCodeSurveyAnswered, "cette enquête de satisfaction a déjà reçu une réponse")
+	ErrCategoryNotFound            = NewAppError(http.StatusNotFound, ErrCodeCategoryNotFound, "catégorie introuvable")
+	ErrAttachmentNotFound          = NewAppError(http.StatusNotFound, ErrCodeAttachmentNotFound, "pièce jointe introuvable")
+	ErrAttachmentFileMissing       = NewAppError(http.StatusNotFound, ErrCodeAttachmentFileMissing, "fichier introuvable")
+	ErrAttachmentTooLarge          = NewAppError(http.StatusRequestEntityTooLarge, ErrCodeAttachmentTooLarge, "fichier trop volumineux")
+	ErrAttachmentType              = NewAppError(http.StatusUnsupportedMediaType, ErrCodeAttachmentType, "type de fichier non autorisé")
+	ErrAttachmentMismatch          = NewAppError(http.StatusUnsupportedMediaType, ErrCodeAttachmentMismatch, "le contenu du fichier ne correspond pas à son extension")
+	ErrAttachmentInfected          = NewAppError(http.StatusUnprocessableEntity, ErrCodeAttachmentInfected, "fichier rejeté par l'antivirus")
+	ErrStorageQuotaExceeded        = NewAppError(http.StatusRequestEntityTooLarge, ErrCodeStorageQuotaExceeded, "quota de stockage de la filiale atteint")
+	ErrAntivirusUnavailable        = NewAppError(http.StatusServiceUnavailable, ErrCodeAntivirusUnavailable, "analyse antivirus indisponible, réessayez plus tard")
+	ErrIncidentNotFound            = NewAppError(http.StatusNotFound, ErrCodeIncidentNotFound, "incident introuvable")
+	ErrPostIncidentReviewNotFound  = NewAppError(http.StatusNotFound, ErrCodePostIncidentReviewNotFound, "revue post-incident introuvable")
+	ErrServiceRequestNotFound      = NewAppError(http.StatusNotFound, ErrCodeServiceRequestNotFound, "demande de service introuvable")
+	ErrChangeNotFound              = NewAppError(http.StatusNotFound, ErrCodeChangeNotFound, "changement introuvable")
+	ErrProblemNotFound             = NewAppError(http.StatusNotFound, ErrCodeProblemNotFound, "problème introuvable")
+	ErrProblemIncidentLinkNotFound = NewAppError(http.StatusNotFound, ErrCodeProblemIncidentLinkNotFound, "liaison entre le problème et l'incident introuvable")
+	ErrSLANotFound                 = NewAppError(http.StatusNotFound, ErrCodeSLANotFound, "SLA introuvable")
+	ErrSLARuleConflict             = NewAppError(http.StatusConflict, ErrCodeSLARuleConflict, "un SLA actif existe déjà pour cette catégorie, cette priorité et ce niveau de support")
+	ErrEscalationRuleNotFound      = NewAppError(http.StatusNotFound, ErrCodeEscalationRuleNotFound, "règle d'escalade introuvable")
+	ErrCalendarNotFound            = NewAppError(http.StatusNotFound, ErrCodeCalendarNotFound, "calendrier ouvré introuvable")
+	ErrCalendarConflict            = NewAppError(http.StatusConflict, ErrCodeCalendarConflict, "un calendrier ouvré existe déjà pour cette filiale")
+	ErrHolidayNotFound             = NewAppError(http.StatusNotFound, ErrCodeHolidayNotFound, "jour férié introuvable")
+	ErrProjectNotFound             = NewAppError(http.StatusNotFound, ErrCodeProjectNotFound, "projet introuvable")
+	ErrProjectArchived             = NewAppError(http.StatusConflict, ErrCodeProjectArchived, "projet archivé : lecture seule")
+	ErrProjectAlreadyArchived      = NewAppError(http.StatusConflict, ErrCodeProjectAlreadyArchived, "ce projet est déjà archivé")
+	ErrProjectNotArchived          = NewAppError(http.StatusConflict, ErrCodeProjectNotArchived, "ce projet n'est pas archivé")
+	ErrBudgetExtensionNotFound     = NewAppError(http.StatusNotFound, ErrCodeBudgetExtensionNotFound, "extension de budget introuvable")
+	ErrProjectTemplateNotFound     = NewAppError(http.StatusNotFound, ErrCodeProjectTemplateNotFound, "modèle de projet introuvable")
+	ErrProjectTemplateConflict     = NewAppError(http.StatusConflict, ErrCodeProjectTemplateConflict, "un modèle de projet porte déjà ce nom")
+	ErrProjectTaskNotFound         = NewAppError(http.StatusNotFound, ErrCodeProjectTaskNotFound, "tâche introuvable")
+	ErrTaskPrerequisiteNotFound    = NewAppError(http.StatusNotFound, ErrCodeTaskPrerequisiteNotFound, "tâche prérequise introuvable dans le projet")
+	ErrTaskDependencyNotFound      = NewAppError(http.StatusNotFound, ErrCodeTaskDependencyNotFound, "dépendance introuvable")
+	ErrMilestoneNotFound           = NewAppError(http.StatusNotFound, ErrCodeMilestoneNotFound, "jalon introuvable")
+	ErrPhaseNotFound               = NewAppError(http.StatusNotFound, ErrCodePhaseNotFound, "étape introuvable")
+	ErrTaskRecurrenceNotFound      = NewAppError(http.StatusNotFound, ErrCodeTaskRecurrenceNotFound, "récurrence introuvable")
+	ErrTicketTaskLinkNotFound      = NewAppError(http.StatusNotFound, ErrCodeTicketTaskLinkNotFound, "lien introuvable")
+	ErrTaskTimerRunning            = NewAppError(http.StatusConflict, ErrCodeTaskTimerRunning, "un chronomètre est déjà en cours : arrêtez-le avant d'en démarrer un autre")
+	ErrTaskTimerNotRunning         = NewAppError(http.StatusConflict, ErrCodeTaskTimerNotRunning, "aucun chronomètre en cours sur cette tâche")
+	ErrAssetNotFound               = NewAppError(http.StatusNotFound, ErrCodeAssetNotFound, "actif introuvable")
+	ErrAssetTransition             = NewAppError(http.StatusConflict, ErrCodeAssetTransition, "transition de cycle de vie non autorisée pour cet actif")
+	ErrAssetRelationNotFound       = NewAppError(http.StatusNotFound, ErrCodeAssetRelationNotFound, "relation d'actif introuvable")
+	ErrMaintenancePlanNotFound     = NewAppError(http.StatusNotFound, ErrCodeMaintenancePlanNotFound, "plan de maintenance introuvable")
+	ErrAssetCategoryNotFound       = NewAppError(http.StatusNotFound, ErrCodeAssetCategoryNotFound, "catégorie d'actif introuvable")
+	ErrDiscoveryAgentNotFound      = NewAppError(http.StatusNotFound, ErrCodeDiscoveryAgentNotFound, "agent d'inventaire introuvable")
+	ErrDiscrepancyNotFound         = NewAppError(http.StatusNotFound, ErrCodeDiscrepancyNotFound, "écart de découverte introuvable")
+	ErrDiscrepancyProcessed        = NewAppError(http.StatusConflict, ErrCodeDiscrepancyProcessed, "cet écart a déjà été traité")
+	ErrReservationNotFound         = NewAppError(http.StatusNotFound, ErrCodeReservationNotFound, "réservation introuvable")
+	ErrReservationConflict         = NewAppError(http.StatusConflict, ErrCodeReservationConflict, "l'équipement est déjà réservé sur ce créneau")
+	ErrSoftwareNotFound            = NewAppError(http.StatusNotFound, ErrCodeSoftwareNotFound, "logiciel introuvable")
+	ErrLicenseNotFound             = NewAppError(http.StatusNotFound, ErrCodeLicenseNotFound, "licence introuvable")
+	ErrArticleNotFound             = NewAppError(http.StatusNotFound, ErrCodeArticleNotFound, "article introuvable")
+	ErrArticleTransition           = NewAppError(http.StatusConflict, ErrCodeArticleTransition, "changement de statut non autorisé pour cet article")
+	ErrArticleReviewer             = NewAppError(http.StatusForbidden, ErrCodeArticleReviewer, "seul le relecteur désigné peut se prononcer sur cet article")
+	ErrArticleVersionNotFound      = NewAppError(http.StatusNotFound, ErrCodeArticleVersionNotFound, "version de l'article introuvable")
+	ErrDeflectionSessionNotFound   = NewAppError(http.StatusNotFound, ErrCodeDeflectionSessionNotFound, "session de suggestion introuvable")
+	ErrTimeEntryNotFound           = NewAppError(http.StatusNotFound, ErrCodeTimeEntryNotFound, "entrée de temps introuvable")
+	ErrDeclarationNotFound         = NewAppError(http.StatusNotFound, ErrCodeDeclarationNotFound, "déclaration introuvable")
+	ErrDeclarationTransition       = NewAppError(http.StatusConflict, ErrCodeDeclarationTransition, "changement de statut non autorisé pour cette déclaration")
+	ErrDeclarationLocked           = NewAppError(http.StatusConflict, ErrCodeDeclarationLocked, "semaine verrouillée : sa déclaration hebdomadaire est approuvée, demandez son déverrouillage à un validateur")
+	ErrDeclarationSubmitted        = NewAppError(http.StatusConflict, ErrCodeDeclarationSubmitted, "déclaration soumise : elle ne peut plus être modifiée avant la décision du validateur")
+	ErrDeclarationAuthor           = NewAppError(http.StatusForbidden, ErrCodeDeclarationAuthor, "seul l'auteur de la déclaration peut la soumettre")
+	ErrAbsenceNotFound             = NewAppError(http.StatusNotFound, ErrCodeAbsenceNotFound, "absence introuvable")
+	ErrAbsenceConflict             = NewAppError(http.StatusConflict, ErrCodeAbsenceConflict, "une absence en attente ou approuvée couvre déjà une partie de cette période")
+	ErrDelayNotFound               = NewAppError(http.StatusNotFound, ErrCodeDelayNotFound, "retard introuvable")
+	ErrJustificationNotFound       = NewAppError(http.StatusNotFound, ErrCodeJustificationNotFound, "justification introuvable")
+	ErrDelayDecisionForbidden      = NewAppError(http.StatusForbidden, ErrCodeDelayDecisionForbidden, "aucune décision n'est attendue de votre part sur cette justification")
+	ErrWebhookNotFound             = NewAppError(http.StatusNotFound, ErrCodeWebhookNotFound, "webhook introuvable")
+	ErrApprovalNotFound            = NewAppError(http.StatusNotFound, ErrCodeApprovalNotFound, "demande d'approbation introuvable")
+	ErrApprovalForbidden           = NewAppError(http.StatusForbidden, ErrCodeApprovalForbidden, "vous ne participez pas à cette demande d'approbation")
+	ErrApprovalPending             = NewAppError(http.StatusConflict, ErrCodeApprovalPending, "une demande d'approbation est déjà en attente pour cet enregistrement")
+	ErrApprovalClosed              = NewAppError(http.StatusConflict, ErrCodeApprovalClosed, "cette demande d'approbation n'est plus en attente")
+	ErrApprovalNoTask              = NewAppError(http.StatusConflict, ErrCodeApprovalNoTask, "aucune décision n'est attendue de votre part sur cette demande")
+)
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Codes d'erreur lisibles par machine, exposés dans le champ code des réponses v1 et dans les problem details
+// RFC 7807 de l'API v2. Codes génériques, déduits du statut HTTP ; les codes métier sont dans error_catalog.go
+const (
+	ErrCodeValidation         = "validation_error"
+	ErrCodeUnauthenticated    = "unauthenticated"
+	ErrCodeForbidden          = "forbidden"
+	ErrCodePermissionDenied   = "permission_denied"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeConflict           = "conflict"
+	ErrCodePayloadTooLarge    = "payload_too_large"
+	ErrCodeUnprocessable      = "unprocessable_entity"
+	ErrCodeRateLimited        = "rate_limited"
+	ErrCodeInternal           = "internal_error"
+	ErrCodeServiceUnavailable = "service_unavailable"
+)
+
+// errorCodeContextKey clé du contexte Gin portant le code d'erreur explicite d'une réponse
+const errorCodeContextKey = "error_code"
+
+// AppError erreur métier du catalogue : code machine stable, statut HTTP et détails optionnels
+// Le message est le message source en français, traduit à l'envoi de la réponse
+type AppError struct {
+	Code    string
+	Status  int
+	Message string
+	Details any // Exposé dans le champ error (v1) / errors (v2), ex: identifiant de la ressource en conflit
+}
+
+// Error retourne le message source
+func (e *AppError) Error() string {
+	return e.Message
+}
+
+// Is rend une erreur enrichie de détails équivalente à l'erreur du catalogue dont elle provient (errors.Is)
+func (e *AppError) Is(target error) bool {
+	other, ok := target.(*AppError)
+	return ok && other.Code == e.Code && other.Message == e.Message
+}
+
+// WithDetails retourne une copie de l'erreur portant des détails
+func (e *AppError) WithDetails(details any) *AppError {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// messageCodes codes des erreurs du catalogue, par message source (voir messageKey) : une réponse construite
+// à partir du message (ex: NotFoundResponse(c, err.Error()) ou NotFoundResponse(c, "Ticket introuvable"))
+// reçoit ainsi le code de l'erreur
+var messageCodes = map[string]string{}
+
+// NewAppError déclare une erreur du catalogue (à appeler à l'initialisation du package, jamais par requête)
+func NewAppError(status int, code, message string) *AppError {
+	messageCodes[messageKey(message)] = code
+	return &AppError{Code: code, Status: status, Message: message}
+}
+
+// NewInternalError erreur technique (500) retournée par un service : le message source décrit l'opération en échec,
+// le code est le code générique internal_error (contrairement à NewAppError, utilisable à chaque requête)
+func NewInternalError(message string) *AppError {
+	return &AppError{Code: ErrCodeInternal, Status: http.StatusInternalServerError, Message: message}
+}
+
+// messageKey clé d'un message dans messageCodes : première lettre en minuscule (les handlers capitalisent les messages des services)
+func messageKey(message string) string {
+	first, size := utf8.DecodeRuneInString(message)
+	return string(unicode.ToLower(first)) + message[size:]
+}
+
+// ServiceErrorResponse envoie la réponse d'une erreur retournée par un service : statut, code et détails
+// d'une erreur du catalogue, sinon 400 avec le message de l'erreur
+func ServiceErrorResponse(c *gin.Context, err error) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		ErrorResponseWithCode(c, appErr.Status, appErr.Code, err.Error(), appErr.Details)
+		return
+	}
+	ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+}
+
+// AppErrorResponse envoie la réponse d'une erreur du catalogue et retourne true ; false si l'erreur n'en provient pas
+// (utilisé en tête des fonctions de traduction d'erreurs propres aux handlers)
+func AppErrorResponse(c *gin.Context, err error) bool {
+	var appErr *AppError
+	if !errors.As(err, &appErr) {
+		return false
+	}
+	ErrorResponseWithCode(c, appErr.Status, appErr.Code, err.Error(), appErr.Details)
+	return true
+}
+
+// ErrorResponseWithCode envoie une réponse d'erreur en précisant un code machine explicite
+// En v1 la réponse est identique à ErrorResponse ; en v2 le code est exposé dans les problem details
+func ErrorResponseWithCode(c *gin.Context, statusCode int, code, message string, err any) {
+	c.Set(errorCodeContextKey, code)
+	ErrorResponse(c, statusCode, message, err)
+}
+
+// ErrorCodeFromContext retourne le code d'erreur de la réponse : explicite s'il a été défini,
+// sinon celui de l'erreur du catalogue portant ce message, sinon déduit du statut HTTP et du message
+func ErrorCodeFromContext(c *gin.Context, statusCode int, message string) string {
+	if code := c.GetString(errorCodeContextKey); code != "" {
+		return code
+	}
+	if code, ok := messageCodes[messageKey(message)]; ok {
+		return code
+	}
+	switch statusCode {
+	case http.StatusBadRequest:
+		return ErrCodeValidation
+	case http.StatusUnauthorized:
+		return ErrCodeUnauthenticated
+	case http.StatusForbidden:
+		if strings.HasPrefix(message, "Permission insuffisante") {
+			return ErrCodePermissionDenied
+		}
+		return ErrCodeForbidden
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrCodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return ErrCodeUnprocessable
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrCodeServiceUnavailable
+	}
+	if statusCode >= 500 {
+		return ErrCodeInternal
+	}
+	return ErrCodeValidation
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldTree représente la sélection de champs demandée (notation pointée : "requester.name")
+type fieldTree map[string]fieldTree
+
+// parseFields transforme "id,title,requester.name" en arbre de sélection
+func parseFields(raw string) fieldTree {
+	tree := fieldTree{}
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		node := tree
+		for _, part := range strings.Split(path, ".") {
+			child, ok := node[part]
+			if !ok || child == nil {
+				child = fieldTree{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// SelectFields applique la sélection de champs (?fields=id,title,requester.name) aux données d'une réponse
+// Sans paramètre fields, les données sont retournées telles quelles
+// Pour une liste encapsulée (ex: {"tickets": [...], "pagination": {...}}), la sélection s'applique aux éléments
+// de la liste et les autres clés (pagination) sont conservées
+func SelectFields(c *gin.Context, data any) any {
+	raw := c.Query("fields")
+	if raw == "" || data == nil {
+		return data
+	}
+	tree := parseFields(raw)
+	if len(tree) == 0 {
+		return data
+	}
+
+	// Masquer les champs sensibles avant la conversion générique (les tags mask sont perdus ensuite)
+	encoded, err := json.Marshal(MaskFields(c, data))
+	if err != nil {
+		return data
+	}
+	var generic any
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return data
+	}
+
+	switch value := generic.(type) {
+	case []any:
+		return filterValue(value, tree)
+	case map[string]any:
+		// Liste encapsulée : filtrer les éléments des tableaux d'objets, conserver le reste
+		wrapped := false
+		for key, inner := range value {
+			if items, ok := inner.([]any); ok && len(items) > 0 {
+				if _, isObject := items[0].(map[string]any); isObject {
+					value[key] = filterValue(items, tree)
+					wrapped = true
+				}
+			}
+		}
+		if wrapped {
+			return value
+		}
+		return filterValue(value, tree)
+	default:
+		return data
+	}
+}
+
+// filterValue conserve uniquement les champs sélectionnés (récursivement pour les objets et tableaux)
+func filterValue(value any, tree fieldTree) any {
+	if len(tree) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case []any:
+		for i, item := range v {
+			v[i] = filterValue(item, tree)
+		}
+		return v
+	case map[string]any:
+		result := make(map[string]any, len(tree))
+		for key, child := range tree {
+			if inner, ok := v[key]; ok {
+				result[key] = filterValue(inner, child)
+			}
+		}
+		return result
+	default:
+		return value
+	}
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/scope"
+)
+
+// maskTag est le tag de struct indiquant la permission requise pour voir un champ sensible
+// Syntaxe : `mask:"projects.budget.view"` ou `mask:"users.view_phone,owner=ID"`
+// L'option owner=<Champ> laisse le champ visible quand la valeur de <Champ> (même struct) est l'ID de l'utilisateur connecté
+const maskTag = "mask"
+
+// maxMaskDepth limite la profondeur de parcours (protection contre les structures cycliques)
+const maxMaskDepth = 32
+
+// maskedTypes met en cache, par type, la présence d'au moins un champ masquable (reflect.Type -> bool)
+var maskedTypes sync.Map
+
+// MaskFields masque les champs sensibles des données d'une réponse selon les permissions de l'utilisateur connecté
+// Les champs dont la permission manque sont remis à leur valeur zéro (omis en JSON avec omitempty)
+// Les données d'origine ne sont pas modifiées (copie des seules parties concernées)
+// Sans scope dans le contexte (routes publiques), les données sont retournées telles quelles
+func MaskFields(c *gin.Context, data any) any {
+	if data == nil {
+		return nil
+	}
+	queryScope := GetScopeFromContext(c)
+	if queryScope == nil {
+		return data
+	}
+	return MaskFieldsForScope(data, queryScope)
+}
+
+// MaskFieldsForScope masque les champs sensibles selon les permissions d'un QueryScope
+func MaskFieldsForScope(data any, queryScope *scope.QueryScope) any {
+	if data == nil || queryScope == nil {
+		return data
+	}
+	value := reflect.ValueOf(data)
+	if !typeHasMask(value.Type()) {
+		return data
+	}
+	return maskValue(value, queryScope, 0).Interface()
+}
+
+// typeHasMask indique si un type contient (directement ou non) un champ masquable
+// Les interfaces sont considérées comme potentiellement masquables (type dynamique)
+func typeHasMask(t reflect.Type) bool {
+	if cached, ok := maskedTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	result := computeHasMask(t, map[reflect.Type]bool{})
+	maskedTypes.Store(t, result)
+	return result
+}
+
+func computeHasMask(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return computeHasMask(t.Elem(), visiting)
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		if visiting[t] {
+			return false
+		}
+		visiting[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if _, ok := field.Tag.Lookup(maskTag); ok {
+				return true
+			}
+			if computeHasMask(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maskValue retourne une copie de la valeur avec les champs non autorisés remis à zéro
+func maskValue(value reflect.Value, queryScope *scope.QueryScope, depth int) reflect.Value {
+	if !value.IsValid() || depth > maxMaskDepth {
+		return value
+	}
+	if value.Kind() != reflect.Interface && !typeHasMask(value.Type()) {
+		return value
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type().Elem())
+		copied.Elem().Set(maskValue(value.Elem(), queryScope, depth+1))
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(maskValue(value.Elem(), queryScope, depth+1))
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(maskValue(value.Index(i), queryScope, depth+1))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(maskValue(value.Index(i), queryScope, depth+1))
+		}
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), maskValue(iter.Value(), queryScope, depth+1))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		t := value.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if tag, ok := field.Tag.Lookup(maskTag); ok && !canViewField(value, tag, queryScope) {
+				copied.Field(i).Set(reflect.Zero(field.Type))
+				continue
+			}
+			copied.Field(i).Set(maskValue(value.Field(i), queryScope, depth+1))
+		}
+		return copied
+	}
+	return value
+}
+
+// canViewField évalue le tag mask d'un champ : permission requise, puis exception propriétaire (owner=Champ)
+func canViewField(owner reflect.Value, tag string, queryScope *scope.QueryScope) bool {
+	parts := strings.Split(tag, ",")
+	permission := strings.TrimSpace(parts[0])
+	if permission == "" || queryScope.HasPermission(permission) {
+		return true
+	}
+	for _, option := range parts[1:] {
+		name, ok := strings.CutPrefix(strings.TrimSpace(option), "owner=")
+		if !ok {
+			continue
+		}
+		ownerID := owner.FieldByName(name)
+		if ownerID.Kind() == reflect.Pointer {
+			if ownerID.IsNil() {
+				continue
+			}
+			ownerID = ownerID.Elem()
+		}
+		if ownerID.IsValid() && ownerID.CanUint() && ownerID.Uint() == uint64(queryScope.UserID) {
+			return true
+		}
+	}
+	return false
+}
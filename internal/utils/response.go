@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mcicare/itsm-backend/internal/i18n"
 )
 
 // Response est la structure standard pour toutes les réponses JSON
@@ -11,6 +12,7 @@ type Response struct {
 	Success bool   `json:"success"`           // Indique si l'opération a réussi
 	Message string `json:"message,omitempty"` // Message optionnel
 	Data    any    `json:"data,omitempty"`    // Données de la réponse
+	Code    string `json:"code,omitempty"`    // Code d'erreur machine si échec (voir ErrCode*)
 	Error   any    `json:"error,omitempty"`   // Erreur si échec
 }
 
@@ -30,11 +32,12 @@ type Pagination struct {
 }
 
 // SuccessResponse envoie une réponse de succès (200 OK)
+// Les champs sensibles (tag mask) sont masqués selon les permissions de l'utilisateur (voir MaskFields)
 func SuccessResponse(c *gin.Context, data any, message string) {
 	c.JSON(http.StatusOK, Response{
 		Success: true,
-		Message: message,
-		Data:    data,
+		Message: localize(c, message),
+		Data:    MaskFields(c, data),
 	})
 }
 
@@ -42,16 +45,30 @@ func SuccessResponse(c *gin.Context, data any, message string) {
 func CreatedResponse(c *gin.Context, data any, message string) {
 	c.JSON(http.StatusCreated, Response{
 		Success: true,
-		Message: message,
-		Data:    data,
+		Message: localize(c, message),
+		Data:    MaskFields(c, data),
+	})
+}
+
+// AcceptedResponse envoie une réponse de prise en compte asynchrone (202 Accepted)
+func AcceptedResponse(c *gin.Context, data any, message string) {
+	c.JSON(http.StatusAccepted, Response{
+		Success: true,
+		Message: localize(c, message),
+		Data:    MaskFields(c, data),
 	})
 }
 
 // ErrorResponse envoie une réponse d'erreur avec un code HTTP personnalisé
+// Le message est traduit dans la langue de la requête (voir RequestLanguage)
 func ErrorResponse(c *gin.Context, statusCode int, message string, err any) {
+	// Le code est déduit du message source, avant traduction, et conservé pour l'enveloppe v2
+	code := ErrorCodeFromContext(c, statusCode, message)
+	c.Set(errorCodeContextKey, code)
 	c.JSON(statusCode, Response{
 		Success: false,
-		Message: message,
+		Message: localize(c, message),
+		Code:    code,
 		Error:   err,
 	})
 }
@@ -60,11 +77,18 @@ func ErrorResponse(c *gin.Context, statusCode int, message string, err any) {
 func PaginatedSuccessResponse(c *gin.Context, data any, pagination Pagination) {
 	c.JSON(http.StatusOK, PaginatedResponse{
 		Success:    true,
-		Data:       data,
+		Data:       MaskFields(c, data),
 		Pagination: pagination,
 	})
 }
 
+// localize traduit un message dans la langue de la requête et annonce cette langue au client
+func localize(c *gin.Context, message string) string {
+	lang := RequestLanguage(c)
+	c.Header("Content-Language", lang)
+	return i18n.T(lang, message)
+}
+
 // Fonctions helper pour les erreurs courantes
 
 // BadRequestResponse envoie une erreur 400 Bad Request
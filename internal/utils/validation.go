@@ -0,0 +1,268 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/mcicare/itsm-backend/internal/i18n"
+	"github.com/mcicare/itsm-backend/internal/timezone"
+)
+
+// Langues supportées pour les messages de l'API
+const (
+	LangFR = i18n.FR
+	LangEN = i18n.EN
+)
+
+// Codes stables des erreurs de validation par champ (indépendants de la langue)
+const (
+	ValidationCodeRequired      = "required"
+	ValidationCodeInvalidEmail  = "invalid_email"
+	ValidationCodeInvalidURL    = "invalid_url"
+	ValidationCodeInvalidChoice = "invalid_choice"
+	ValidationCodeInvalidFormat = "invalid_format"
+	ValidationCodeInvalidType   = "invalid_type"
+	ValidationCodeInvalidLength = "invalid_length"
+	ValidationCodeTooShort      = "too_short"
+	ValidationCodeTooLong       = "too_long"
+	ValidationCodeTooFewItems   = "too_few_items"
+	ValidationCodeTooManyItems  = "too_many_items"
+	ValidationCodeTooSmall      = "too_small"
+	ValidationCodeTooLarge      = "too_large"
+	ValidationCodeMalformedBody = "malformed_body"
+	ValidationCodeInvalid       = "invalid"
+)
+
+// FieldError représente une erreur de validation portant sur un champ de la requête
+type FieldError struct {
+	Field   string `json:"field,omitempty"` // Chemin JSON du champ (ex: title, items[0].quantity) ; vide = corps entier
+	Code    string `json:"code"`            // Code stable (ValidationCode*)
+	Message string `json:"message"`         // Message localisé
+	Param   string `json:"param,omitempty"` // Paramètre de la règle (longueur, valeurs autorisées, ...)
+}
+
+// validationMessages messages par code et par langue ; %s est remplacé par le paramètre de la règle
+var validationMessages = map[string]map[string]string{
+	ValidationCodeRequired:      {LangFR: "Ce champ est obligatoire", LangEN: "This field is required"},
+	ValidationCodeInvalidEmail:  {LangFR: "Adresse email invalide", LangEN: "Invalid email address"},
+	ValidationCodeInvalidURL:    {LangFR: "URL invalide", LangEN: "Invalid URL"},
+	ValidationCodeInvalidChoice: {LangFR: "Valeur non autorisée (valeurs possibles : %s)", LangEN: "Value not allowed (allowed values: %s)"},
+	ValidationCodeInvalidFormat: {LangFR: "Format invalide", LangEN: "Invalid format"},
+	ValidationCodeInvalidType:   {LangFR: "Type de valeur invalide (attendu : %s)", LangEN: "Invalid value type (expected: %s)"},
+	ValidationCodeInvalidLength: {LangFR: "La longueur doit être exactement %s", LangEN: "Length must be exactly %s"},
+	ValidationCodeTooShort:      {LangFR: "Doit contenir au moins %s caractères", LangEN: "Must be at least %s characters long"},
+	ValidationCodeTooLong:       {LangFR: "Ne doit pas dépasser %s caractères", LangEN: "Must be at most %s characters long"},
+	ValidationCodeTooFewItems:   {LangFR: "Doit contenir au moins %s élément(s)", LangEN: "Must contain at least %s item(s)"},
+	ValidationCodeTooManyItems:  {LangFR: "Ne doit pas contenir plus de %s élément(s)", LangEN: "Must contain at most %s item(s)"},
+	ValidationCodeTooSmall:      {LangFR: "Doit être supérieur ou égal à %s", LangEN: "Must be greater than or equal to %s"},
+	ValidationCodeTooLarge:      {LangFR: "Doit être inférieur ou égal à %s", LangEN: "Must be less than or equal to %s"},
+	ValidationCodeMalformedBody: {LangFR: "Corps de requête JSON invalide ou absent", LangEN: "Missing or malformed JSON request body"},
+	ValidationCodeInvalid:       {LangFR: "Valeur invalide", LangEN: "Invalid value"},
+}
+
+// validationTitles message global de la réponse par langue
+var validationTitles = map[string]string{LangFR: "Données invalides", LangEN: "Invalid data"}
+
+func init() {
+	// Les erreurs du validateur utilisent le nom JSON des champs plutôt que le nom Go
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form", "uri"} {
+				name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	}
+}
+
+// LanguageContextKey clé du contexte Gin portant la langue des préférences de l'utilisateur connecté
+const LanguageContextKey = "language"
+
+// LocationContextKey clé du contexte Gin portant le fuseau horaire (*time.Location) de l'utilisateur connecté
+const LocationContextKey = "location"
+
+// RequestLocation retourne le fuseau horaire de l'utilisateur connecté, sinon le fuseau par défaut de l'application
+func RequestLocation(c *gin.Context) *time.Location {
+	if value, ok := c.Get(LocationContextKey); ok {
+		if location, ok := value.(*time.Location); ok && location != nil {
+			return location
+		}
+	}
+	return timezone.Default()
+}
+
+// RequestLanguage retourne la langue de la requête : préférence de l'utilisateur connecté,
+// sinon langue supportée la mieux pondérée de l'en-tête Accept-Language, français par défaut
+func RequestLanguage(c *gin.Context) string {
+	if lang := c.GetString(LanguageContextKey); i18n.Supported(lang) {
+		return lang
+	}
+	best, bestWeight := i18n.Default, 0.0
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !i18n.Supported(lang) {
+			continue
+		}
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = parsed
+			}
+		}
+		if weight > bestWeight {
+			best, bestWeight = lang, weight
+		}
+	}
+	return best
+}
+
+// ValidationErrors convertit une erreur de binding (validateur, JSON mal formé, type incorrect)
+// en erreurs par champ avec codes stables et messages localisés
+func ValidationErrors(err error, lang string) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrors := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			code, param := validationCode(fe)
+			fieldErrors = append(fieldErrors, newFieldError(fieldPath(fe), code, param, lang))
+		}
+		return fieldErrors
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{newFieldError(typeErr.Field, ValidationCodeInvalidType, jsonTypeName(typeErr.Type), lang)}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []FieldError{newFieldError("", ValidationCodeMalformedBody, "", lang)}
+	}
+
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return []FieldError{newFieldError("", ValidationCodeInvalidType, "number", lang)}
+	}
+
+	return []FieldError{{Code: ValidationCodeInvalid, Message: err.Error()}}
+}
+
+// ValidationErrorResponse envoie une réponse 400 avec les erreurs de validation par champ
+func ValidationErrorResponse(c *gin.Context, err error) {
+	lang := RequestLanguage(c)
+	ErrorResponseWithCode(c, http.StatusBadRequest, ErrCodeValidation, validationTitles[lang], ValidationErrors(err, lang))
+}
+
+// ValidationErrorResponseWithMessage envoie une réponse 400 avec un message global spécifique
+func ValidationErrorResponseWithMessage(c *gin.Context, message string, err error) {
+	ErrorResponseWithCode(c, http.StatusBadRequest, ErrCodeValidation, message, ValidationErrors(err, RequestLanguage(c)))
+}
+
+// newFieldError construit une erreur de champ avec son message localisé
+func newFieldError(field, code, param, lang string) FieldError {
+	messages, ok := validationMessages[code]
+	if !ok {
+		messages = validationMessages[ValidationCodeInvalid]
+	}
+	message, ok := messages[lang]
+	if !ok {
+		message = messages[LangFR]
+	}
+	if strings.Contains(message, "%s") {
+		message = fmt.Sprintf(message, param)
+	}
+	return FieldError{Field: field, Code: code, Message: message, Param: param}
+}
+
+// validationCode associe une règle du validateur à un code stable
+func validationCode(fe validator.FieldError) (string, string) {
+	kind := fe.Kind()
+	isCollection := kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+	isString := kind == reflect.String
+
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return ValidationCodeRequired, ""
+	case "email":
+		return ValidationCodeInvalidEmail, ""
+	case "url", "uri", "http_url":
+		return ValidationCodeInvalidURL, ""
+	case "oneof":
+		return ValidationCodeInvalidChoice, strings.Join(strings.Fields(fe.Param()), ", ")
+	case "len":
+		return ValidationCodeInvalidLength, fe.Param()
+	case "min", "gte":
+		switch {
+		case isString:
+			return ValidationCodeTooShort, fe.Param()
+		case isCollection:
+			return ValidationCodeTooFewItems, fe.Param()
+		}
+		return ValidationCodeTooSmall, fe.Param()
+	case "max", "lte":
+		switch {
+		case isString:
+			return ValidationCodeTooLong, fe.Param()
+		case isCollection:
+			return ValidationCodeTooManyItems, fe.Param()
+		}
+		return ValidationCodeTooLarge, fe.Param()
+	case "gt":
+		return ValidationCodeTooSmall, fe.Param()
+	case "lt":
+		return ValidationCodeTooLarge, fe.Param()
+	case "datetime", "numeric", "number", "alphanum", "alpha", "hexcolor", "uuid", "uuid4", "e164":
+		return ValidationCodeInvalidFormat, ""
+	}
+	return ValidationCodeInvalid, fe.Tag()
+}
+
+// fieldPath retourne le chemin JSON du champ sans le nom de la structure racine
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if idx := strings.Index(namespace, "."); idx >= 0 {
+		return namespace[idx+1:]
+	}
+	return fe.Field()
+}
+
+// jsonTypeName retourne le nom JSON du type Go attendu
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	}
+	return t.String()
+}
@@ -0,0 +1,277 @@
+// Package vcs lit les webhooks des forges Git (GitHub, GitLab) et en extrait les commits
+// et merge requests, ainsi que les codes de tickets (TKT-YYYY-NNNN) qu'ils citent
+package vcs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Forges prises en charge
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Types de références
+const (
+	KindCommit       = "commit"
+	KindMergeRequest = "merge_request"
+)
+
+// États d'une merge request
+const (
+	StateOpened = "opened"
+	StateMerged = "merged"
+	StateClosed = "closed"
+)
+
+// ticketCodePattern code de ticket cité dans un message de commit ou un titre de merge request
+var ticketCodePattern = regexp.MustCompile(`(?i)\bTKT-\d{4}-\d{4,}\b`)
+
+// Reference commit ou merge request reçu d'une forge
+type Reference struct {
+	Provider    string // github, gitlab
+	Kind        string // commit, merge_request
+	Repository  string // Chemin du dépôt (ex: groupe/projet)
+	Ref         string // SHA du commit ou numéro de la merge request
+	Title       string // Message du commit ou titre de la merge request
+	URL         string
+	AuthorName  string
+	AuthorEmail string // Vide si la forge ne le fournit pas
+	State       string // opened, merged, closed (merge requests uniquement)
+}
+
+// ShortRef retourne la référence abrégée (7 caractères du SHA, #numéro sur GitHub, !numéro sur GitLab)
+func (r Reference) ShortRef() string {
+	if r.Kind == KindMergeRequest && r.Provider == ProviderGitHub {
+		return "#" + r.Ref
+	}
+	if r.Kind == KindMergeRequest {
+		return "!" + r.Ref
+	}
+	if len(r.Ref) > 7 {
+		return r.Ref[:7]
+	}
+	return r.Ref
+}
+
+// Summary retourne la première ligne du message ou du titre
+func (r Reference) Summary() string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(r.Title), "\n")
+	return strings.TrimSpace(summary)
+}
+
+// TicketCodes retourne les codes de tickets cités par la référence, en majuscules et sans doublon
+func (r Reference) TicketCodes() []string {
+	var codes []string
+	seen := map[string]bool{}
+	for _, match := range ticketCodePattern.FindAllString(r.Title, -1) {
+		code := strings.ToUpper(match)
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Parse lit un webhook d'une forge ; les événements autres que push et merge request sont ignorés
+func Parse(provider, event string, body []byte) ([]Reference, error) {
+	refs, err := parse(provider, event, body)
+	for i := range refs {
+		refs[i].Provider = provider
+	}
+	return refs, err
+}
+
+// parse aiguille l'événement vers le lecteur de la forge
+func parse(provider, event string, body []byte) ([]Reference, error) {
+	switch provider {
+	case ProviderGitHub:
+		switch event {
+		case "push":
+			return parseGitHubPush(body)
+		case "pull_request":
+			return parseGitHubPullRequest(body)
+		}
+		return nil, nil
+	case ProviderGitLab:
+		switch event {
+		case "Push Hook":
+			return parseGitLabPush(body)
+		case "Merge Request Hook":
+			return parseGitLabMergeRequest(body)
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("forge non prise en charge: %s", provider)
+	}
+}
+
+// gitHubPush champs utilisés de l'événement push GitHub
+type gitHubPush struct {
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Commits []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		URL     string `json:"url"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"commits"`
+}
+
+// parseGitHubPush lit les commits d'un push GitHub
+func parseGitHubPush(body []byte) ([]Reference, error) {
+	var payload gitHubPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("webhook GitHub invalide")
+	}
+	refs := make([]Reference, 0, len(payload.Commits))
+	for _, commit := range payload.Commits {
+		refs = append(refs, Reference{
+			Kind:        KindCommit,
+			Repository:  payload.Repository.FullName,
+			Ref:         commit.ID,
+			Title:       commit.Message,
+			URL:         commit.URL,
+			AuthorName:  commit.Author.Name,
+			AuthorEmail: commit.Author.Email,
+		})
+	}
+	return refs, nil
+}
+
+// gitHubPullRequest champs utilisés de l'événement pull_request GitHub
+type gitHubPullRequest struct {
+	PullRequest struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		State   string `json:"state"` // open, closed
+		Merged  bool   `json:"merged"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// parseGitHubPullRequest lit une pull request GitHub
+func parseGitHubPullRequest(body []byte) ([]Reference, error) {
+	var payload gitHubPullRequest
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("webhook GitHub invalide")
+	}
+	pr := payload.PullRequest
+	state := StateOpened
+	switch {
+	case pr.Merged:
+		state = StateMerged
+	case pr.State == "closed":
+		state = StateClosed
+	}
+	return []Reference{{
+		Kind:       KindMergeRequest,
+		Repository: payload.Repository.FullName,
+		Ref:        strconv.Itoa(pr.Number),
+		Title:      pr.Title,
+		URL:        pr.HTMLURL,
+		AuthorName: pr.User.Login,
+		State:      state,
+	}}, nil
+}
+
+// gitLabPush champs utilisés de l'événement Push Hook GitLab
+type gitLabPush struct {
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	Commits []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		URL     string `json:"url"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"commits"`
+}
+
+// parseGitLabPush lit les commits d'un push GitLab
+func parseGitLabPush(body []byte) ([]Reference, error) {
+	var payload gitLabPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("webhook GitLab invalide")
+	}
+	refs := make([]Reference, 0, len(payload.Commits))
+	for _, commit := range payload.Commits {
+		refs = append(refs, Reference{
+			Kind:        KindCommit,
+			Repository:  payload.Project.PathWithNamespace,
+			Ref:         commit.ID,
+			Title:       commit.Message,
+			URL:         commit.URL,
+			AuthorName:  commit.Author.Name,
+			AuthorEmail: commit.Author.Email,
+		})
+	}
+	return refs, nil
+}
+
+// gitLabMergeRequest champs utilisés de l'événement Merge Request Hook GitLab
+type gitLabMergeRequest struct {
+	User struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID   int    `json:"iid"`
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		State string `json:"state"` // opened, closed, merged, locked
+	} `json:"object_attributes"`
+}
+
+// parseGitLabMergeRequest lit une merge request GitLab
+func parseGitLabMergeRequest(body []byte) ([]Reference, error) {
+	var payload gitLabMergeRequest
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("webhook GitLab invalide")
+	}
+	mr := payload.ObjectAttributes
+	state := StateOpened
+	switch mr.State {
+	case "merged":
+		state = StateMerged
+	case "closed":
+		state = StateClosed
+	}
+	email := payload.User.Email
+	if strings.Contains(email, "[REDACTED]") {
+		email = ""
+	}
+	return []Reference{{
+		Kind:        KindMergeRequest,
+		Repository:  payload.Project.PathWithNamespace,
+		Ref:         strconv.Itoa(mr.IID),
+		Title:       mr.Title,
+		URL:         mr.URL,
+		AuthorName:  payload.User.Name,
+		AuthorEmail: email,
+		State:       state,
+	}}, nil
+}
@@ -0,0 +1,89 @@
+// Package whatsapp appelle l'API WhatsApp Business (Cloud API) pour envoyer des messages modèles
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// apiURL point d'entrée de l'API Graph
+const apiURL = "https://graph.facebook.com/"
+
+// requestTimeout borne la durée d'un appel à l'API
+const requestTimeout = 15 * time.Second
+
+// Client client de l'API pour un numéro expéditeur
+// Hors d'une conversation ouverte par l'utilisateur, WhatsApp n'accepte que des modèles approuvés par Meta
+type Client struct {
+	phoneNumberID string
+	accessToken   string
+	version       string
+	http          *http.Client
+}
+
+// New crée un client pour le numéro expéditeur
+func New(phoneNumberID, accessToken, version string) *Client {
+	return &Client{
+		phoneNumberID: phoneNumberID,
+		accessToken:   accessToken,
+		version:       version,
+		http:          &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// SendTemplate envoie le modèle name (langue language) au numéro to (format E.164) ; parameters remplit les variables {{1}}, {{2}}, ... du corps
+func (c *Client) SendTemplate(ctx context.Context, to, name, language string, parameters []string) error {
+	template := map[string]any{
+		"name":     name,
+		"language": map[string]string{"code": language},
+	}
+	if len(parameters) > 0 {
+		values := make([]map[string]string, len(parameters))
+		for i, parameter := range parameters {
+			values[i] = map[string]string{"type": "text", "text": parameter}
+		}
+		template["components"] = []map[string]any{{"type": "body", "parameters": values}}
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              "template",
+		"template":          template,
+	})
+	if err != nil {
+		return err
+	}
+	url := apiURL + c.version + "/" + c.phoneNumberID + "/messages"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("API WhatsApp: requête impossible: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil || body.Error.Message == "" {
+		return fmt.Errorf("API WhatsApp: réponse HTTP %d", resp.StatusCode)
+	}
+	return fmt.Errorf("API WhatsApp: réponse HTTP %d: %s (code %d)", resp.StatusCode, body.Error.Message, body.Error.Code)
+}